@@ -0,0 +1,276 @@
+package cooklang
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Adjustment is a change AdjustForConvection or AdjustForAltitude made to a recipe.
+type Adjustment struct {
+	What     string         `json:"what"`              // The ingredient name, "temperature", "timer", or a metadata key such as "oven_temp"
+	Before   string         `json:"before"`            // e.g. "200°C" or "1 tsp"
+	After    string         `json:"after"`             // e.g. "185°C" or "7/8 tsp"
+	Reason   string         `json:"reason"`            // Why it changed
+	Position SourcePosition `json:"position,omitzero"` // Where the component is in the recipe source; zero for metadata
+}
+
+// String returns the adjustment as a line of a change log, e.g.
+// "temperature: 200°C → 185°C (convection ovens cook hotter)".
+func (a Adjustment) String() string {
+	return fmt.Sprintf("%s: %s → %s (%s)", a.What, a.Before, a.After, a.Reason)
+}
+
+// AdjustedForKey is the metadata key AdjustForConvection and AdjustForAltitude add
+// to the copies they return, e.g. "adjusted_for: convection oven".
+const AdjustedForKey = "adjusted_for"
+
+// ovenWords mark a step that happens in the oven.
+var ovenWords = []string{"oven", "bake", "bakes", "baked", "baking", "roast", "roasting", "preheat"}
+
+// altitudeBand holds the high-altitude baking corrections from a given altitude up.
+type altitudeBand struct {
+	meters    float64 // Lowest altitude of the band
+	leavening float64 // Factor for baking powder, baking soda and yeast
+	liquids   float64 // Factor for water, milk and other liquids
+}
+
+// altitudeBands are the usual corrections for 3,000, 5,000 and 7,000 feet: 1/8 to
+// 1/4 tsp less leavening per teaspoon and 2 to 4 tbsp more liquid per cup.
+var altitudeBands = []altitudeBand{
+	{meters: 2100, leavening: 0.75, liquids: 1.25},
+	{meters: 1500, leavening: 0.8125, liquids: 1.1875},
+	{meters: 900, leavening: 0.875, liquids: 1.125},
+}
+
+// leaveningNames and liquidNames are matched against ingredient names as words.
+var (
+	leaveningNames = []string{"baking powder", "baking soda", "bicarbonate of soda", "yeast"}
+	liquidNames    = []string{"water", "milk", "buttermilk", "cream", "beer", "juice", "stock", "broth", "coffee"}
+)
+
+// AdjustForConvection returns a copy of the recipe for a convection (fan) oven: oven
+// temperatures are lowered by 15°C (25°F), and timers of baking and roasting steps
+// are shortened by 20%. Oven temperatures are those in the TemperatureMetadataKeys
+// metadata and those of 100°C (212°F) and up in steps that mention the oven, baking,
+// roasting or preheating. The copy's AdjustedForKey metadata says it was adjusted.
+//
+// Returns:
+//   - *Recipe: The adjusted copy
+//   - []Adjustment: The changes, in recipe order
+//
+// Example:
+//
+//	recipe, _ := cooklang.ParseString("Bake at 200°C for ~{25%minutes}.")
+//	convection, changes := recipe.AdjustForConvection()
+//	fmt.Println(convection.Render()) // ... Bake at 185°C for ~{20%minutes}.
+//	for _, change := range changes {
+//	    fmt.Println(change) // temperature: 200°C → 185°C (convection ovens cook hotter)
+//	}
+func (r *Recipe) AdjustForConvection() (*Recipe, []Adjustment) {
+	const reason = "convection ovens cook hotter"
+	adjusted := r.Clone()
+	changes := adjustOvenTemperatures(adjusted, -15, -25, reason)
+	for step := adjusted.FirstStep; step != nil; step = step.NextStep {
+		if !isOvenStep(step) {
+			continue
+		}
+		for component := step.FirstComponent; component != nil; component = component.GetNext() {
+			if timer, ok := component.(*Timer); ok {
+				if change, ok := scaleTimer(timer, 0.8, "convection ovens bake about 20% faster"); ok {
+					changes = append(changes, change)
+				}
+			}
+		}
+	}
+	annotateAdjustment(adjusted, "convection oven")
+	return adjusted, changes
+}
+
+// AdjustForAltitude returns a copy of the recipe with the usual high-altitude baking
+// corrections, from 900 m (3,000 ft) up: less baking powder, baking soda and yeast
+// (1/8 less at 900 m, 3/16 at 1,500 m, 1/4 from 2,100 m), and, for recipes baked in
+// the oven, more water, milk and other liquids (1/8, 3/16 and 1/4 more) and oven
+// temperatures raised by 15°C (25°F). Oven temperatures are found as by
+// AdjustForConvection. Below 900 m the copy is unchanged. The copy's AdjustedForKey
+// metadata says it was adjusted.
+//
+// Parameters:
+//   - meters: The altitude in meters
+//
+// Returns:
+//   - *Recipe: The adjusted copy
+//   - []Adjustment: The changes, in recipe order
+//
+// Example:
+//
+//	cake, _ := cooklang.ParseFile("cake.cook")
+//	adjusted, changes := cake.AdjustForAltitude(1600)
+//	for _, change := range changes {
+//	    fmt.Println(change) // baking powder: 2 tsp → 1.625 tsp (leavening rises more at 1600 m)
+//	}
+func (r *Recipe) AdjustForAltitude(meters float64) (*Recipe, []Adjustment) {
+	adjusted := r.Clone()
+	var band *altitudeBand
+	for i := range altitudeBands {
+		if meters >= altitudeBands[i].meters {
+			band = &altitudeBands[i]
+			break
+		}
+	}
+	if band == nil {
+		return adjusted, nil
+	}
+
+	altitude := strconv.FormatFloat(meters, 'f', -1, 64) + " m"
+	baked := false
+	for step := adjusted.FirstStep; step != nil && !baked; step = step.NextStep {
+		baked = isOvenStep(step)
+	}
+	_, hasOven := adjusted.OvenTemperature()
+
+	var changes []Adjustment
+	if baked || hasOven {
+		changes = adjustOvenTemperatures(adjusted, 15, 25, "a hotter oven sets batters before they over-rise at "+altitude)
+	}
+	for step := adjusted.FirstStep; step != nil; step = step.NextStep {
+		for component := step.FirstComponent; component != nil; component = component.GetNext() {
+			ingredient, ok := component.(*Ingredient)
+			if !ok || !ingredient.Quantity.HasAmount() || ingredient.IsReference() {
+				continue
+			}
+			switch {
+			case nameHasWord(ingredient.Name, leaveningNames):
+				changes = append(changes, scaleIngredient(ingredient, band.leavening, "leavening rises more at "+altitude))
+			case (baked || hasOven) && nameHasWord(ingredient.Name, liquidNames):
+				changes = append(changes, scaleIngredient(ingredient, band.liquids, "liquids evaporate faster at "+altitude))
+			}
+		}
+	}
+	annotateAdjustment(adjusted, "altitude "+altitude)
+	return adjusted, changes
+}
+
+// adjustOvenTemperatures raises or lowers the oven temperatures of the recipe, in
+// its metadata and oven steps, by the given degrees of each scale.
+func adjustOvenTemperatures(recipe *Recipe, celsius, fahrenheit float64, reason string) []Adjustment {
+	var changes []Adjustment
+	for _, key := range TemperatureMetadataKeys {
+		value, ok := recipe.Metadata[key]
+		if !ok {
+			continue
+		}
+		if t, err := ParseTemperature(value); err == nil {
+			shiftTemperature(t, celsius, fahrenheit)
+			recipe.Metadata[key] = t.RenderDisplay()
+			changes = append(changes, Adjustment{What: key, Before: value, After: recipe.Metadata[key], Reason: reason})
+		}
+	}
+	for step := recipe.FirstStep; step != nil; step = step.NextStep {
+		if !isOvenStep(step) {
+			continue
+		}
+		for component := step.FirstComponent; component != nil; component = component.GetNext() {
+			t, ok := component.(*Temperature)
+			if !ok || t.ConvertTo(Celsius).Value < 100 {
+				continue
+			}
+			before := t.RenderDisplay()
+			shiftTemperature(t, celsius, fahrenheit)
+			changes = append(changes, Adjustment{What: "temperature", Before: before, After: t.RenderDisplay(), Reason: reason, Position: t.Position})
+		}
+	}
+	return changes
+}
+
+// shiftTemperature adds degrees to a temperature in its own scale.
+func shiftTemperature(t *Temperature, celsius, fahrenheit float64) {
+	shift := celsius
+	if t.Scale == Fahrenheit {
+		shift = fahrenheit
+	}
+	t.Value += shift
+	if t.ValueMax > 0 {
+		t.ValueMax += shift
+	}
+	t.Text = "" // Render the new value
+}
+
+// scaleTimer multiplies a timer's duration, rounding to whole units from 5 up and
+// to tenths below. Durations that are not numbers, such as "overnight", are kept.
+func scaleTimer(timer *Timer, factor float64, reason string) (Adjustment, bool) {
+	q, err := ParseQuantity(timer.Duration)
+	if err != nil || q.Min() <= 0 {
+		return Adjustment{}, false
+	}
+	before := timer.RenderDisplay()
+	duration := formatTimerValue(q.Min() * factor)
+	if q.IsRange() {
+		duration += "-" + formatTimerValue(q.Max()*factor)
+	}
+	timer.Duration = duration
+	return Adjustment{What: "timer", Before: before, After: timer.RenderDisplay(), Reason: reason, Position: timer.Position}, true
+}
+
+// formatTimerValue rounds a scaled timer duration the way recipes write it.
+func formatTimerValue(value float64) string {
+	if value >= 5 {
+		value = math.Round(value)
+	} else {
+		value = math.Round(value*10) / 10
+	}
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// scaleIngredient multiplies an ingredient's quantity.
+func scaleIngredient(ingredient *Ingredient, factor float64, reason string) Adjustment {
+	before := strings.TrimSpace(ingredient.Quantity.String() + " " + ingredient.Unit)
+	ingredient.Quantity = ingredient.Quantity.Scale(factor)
+	after := strings.TrimSpace(ingredient.Quantity.String() + " " + ingredient.Unit)
+	return Adjustment{What: ingredient.Name, Before: before, After: after, Reason: reason, Position: ingredient.Position}
+}
+
+// isOvenStep reports whether a step mentions the oven, baking, roasting or
+// preheating, in its text or cookware.
+func isOvenStep(step *Step) bool {
+	for component := step.FirstComponent; component != nil; component = component.GetNext() {
+		switch c := component.(type) {
+		case *Instruction:
+			if nameHasWord(c.Text, ovenWords) {
+				return true
+			}
+		case *Cookware:
+			if nameHasWord(c.Name, ovenWords) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// nameHasWord reports whether text contains one of the names as whole words,
+// ignoring case and punctuation, so "Bake it." mentions "bake" and "Baker" does not.
+func nameHasWord(text string, names []string) bool {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !('a' <= r && r <= 'z')
+	})
+	padded := " " + strings.Join(words, " ") + " "
+	for _, name := range names {
+		if strings.Contains(padded, " "+name+" ") || strings.Contains(padded, " "+name+"s ") {
+			return true
+		}
+	}
+	return false
+}
+
+// annotateAdjustment records an adjustment in the recipe's AdjustedForKey metadata.
+func annotateAdjustment(recipe *Recipe, adjustment string) {
+	if recipe.Metadata == nil {
+		recipe.Metadata = make(Metadata)
+	}
+	if previous := recipe.Metadata[AdjustedForKey]; previous != "" {
+		adjustment = previous + ", " + adjustment
+	}
+	recipe.Metadata[AdjustedForKey] = adjustment
+}
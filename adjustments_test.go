@@ -0,0 +1,120 @@
+package cooklang
+
+import (
+	"strings"
+	"testing"
+)
+
+const adjustmentsRecipe = `---
+oven_temp: 350°F
+---
+Preheat the oven to 200°C.
+
+Mix @baking powder{2%tsp}, @milk{1%cup}, @flour{2%cup} and @eggs{2}.
+
+Bake for ~{25-30%minutes} at 200°C.
+
+Heat the @oil{1%l} to 175°C and fry the @&flour{} crumbs for ~{3%minutes}.
+`
+
+func TestAdjustForConvection(t *testing.T) {
+	recipe, err := ParseString(adjustmentsRecipe)
+	if err != nil {
+		t.Fatalf("Failed to parse recipe: %v", err)
+	}
+
+	adjusted, changes := recipe.AdjustForConvection()
+	expected := []string{
+		"oven_temp: 350°F → 325°F (convection ovens cook hotter)",
+		"temperature: 200°C → 185°C (convection ovens cook hotter)",
+		"temperature: 200°C → 185°C (convection ovens cook hotter)",
+		"timer: 25-30 minutes → 20-24 minutes (convection ovens bake about 20% faster)",
+	}
+	if len(changes) != len(expected) {
+		t.Fatalf("expected %d changes, got %v", len(expected), changes)
+	}
+	for i, want := range expected {
+		if got := changes[i].String(); got != want {
+			t.Errorf("change %d: got %q, want %q", i, got, want)
+		}
+	}
+	if changes[1].Position.Line != 4 {
+		t.Errorf("expected the first step temperature on line 4, got %v", changes[1].Position)
+	}
+
+	temperatures := adjusted.GetTemperatures()
+	if temperatures[0].RenderDisplay() != "185°C" || temperatures[2].RenderDisplay() != "175°C" {
+		t.Errorf("expected oven temperatures lowered and the frying oil kept, got %v, %v", temperatures[0], temperatures[2])
+	}
+	if adjusted.Metadata[AdjustedForKey] != "convection oven" {
+		t.Errorf("expected the copy to be annotated, got %q", adjusted.Metadata[AdjustedForKey])
+	}
+	if recipe.GetTemperatures()[0].Value != 200 || recipe.Metadata["oven_temp"] != "350°F" || recipe.Metadata[AdjustedForKey] != "" {
+		t.Error("AdjustForConvection changed the original recipe")
+	}
+}
+
+func TestAdjustForAltitude(t *testing.T) {
+	recipe, err := ParseString(adjustmentsRecipe)
+	if err != nil {
+		t.Fatalf("Failed to parse recipe: %v", err)
+	}
+
+	adjusted, changes := recipe.AdjustForAltitude(2400)
+	expected := []string{
+		"oven_temp: 350°F → 375°F (a hotter oven sets batters before they over-rise at 2400 m)",
+		"temperature: 200°C → 215°C (a hotter oven sets batters before they over-rise at 2400 m)",
+		"temperature: 200°C → 215°C (a hotter oven sets batters before they over-rise at 2400 m)",
+		"baking powder: 2 tsp → 1.5 tsp (leavening rises more at 2400 m)",
+		"milk: 1 cup → 1.25 cup (liquids evaporate faster at 2400 m)",
+	}
+	if len(changes) != len(expected) {
+		t.Fatalf("expected %d changes, got %v", len(expected), changes)
+	}
+	for i, want := range expected {
+		if got := changes[i].String(); got != want {
+			t.Errorf("change %d: got %q, want %q", i, got, want)
+		}
+	}
+	if adjusted.Metadata[AdjustedForKey] != "altitude 2400 m" {
+		t.Errorf("expected the copy to be annotated, got %q", adjusted.Metadata[AdjustedForKey])
+	}
+
+	// Lower altitudes need smaller corrections, and none below 900 m
+	_, changes = recipe.AdjustForAltitude(1000)
+	if len(changes) != 5 || !strings.HasPrefix(changes[3].String(), "baking powder: 2 tsp → 1.75 tsp") {
+		t.Errorf("expected 1/8 less leavening at 1000 m, got %v", changes)
+	}
+	unchanged, changes := recipe.AdjustForAltitude(500)
+	if len(changes) != 0 || unchanged.Metadata[AdjustedForKey] != "" {
+		t.Errorf("expected no changes at 500 m, got %v", changes)
+	}
+
+	// Liquids of recipes that are not baked are kept
+	soup, _ := ParseString("Simmer @water{1%l} with @yeast{1%tsp} flakes.")
+	_, changes = soup.AdjustForAltitude(2400)
+	if len(changes) != 1 || changes[0].What != "yeast" {
+		t.Errorf("expected only the yeast adjusted, got %v", changes)
+	}
+}
+
+func TestAdjustmentTransforms(t *testing.T) {
+	recipe, _ := ParseString("Bake at 400°F for ~{30%minutes}.")
+	pipeline, err := ParseTransformPipeline("convection,altitude=1500m")
+	if err != nil {
+		t.Fatalf("ParseTransformPipeline failed: %v", err)
+	}
+	adjusted, err := pipeline.Apply(recipe)
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if got := adjusted.GetTemperatures()[0].RenderDisplay(); got != "400°F" {
+		t.Errorf("expected 400°F - 25°F + 25°F, got %s", got)
+	}
+	if got := adjusted.GetTimers()[0].RenderDisplay(); got != "24 minutes" {
+		t.Errorf("expected 24 minutes, got %s", got)
+	}
+	if got := adjusted.Metadata[AdjustedForKey]; got != "convection oven, altitude 1500 m" {
+		t.Errorf("expected both adjustments noted, got %q", got)
+	}
+}
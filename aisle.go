@@ -0,0 +1,122 @@
+package cooklang
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// AisleConfig assigns ingredients to store aisles, as read from a Cooklang
+// aisle.conf file. Each "[aisle]" section lists ingredient names, one per line,
+// with synonyms separated by "|":
+//
+//	[produce]
+//	potatoes
+//	tomatoes|tomato
+//
+//	[dairy]
+//	milk
+//	butter
+type AisleConfig struct {
+	Aisles []string // Aisle names in file order, which is the store walking order
+
+	ingredients map[string]string // Lower-case ingredient name or synonym -> aisle
+}
+
+// ParseAisleConfig parses the content of an aisle.conf file. Blank lines and lines
+// starting with "#" or "//" are ignored.
+//
+// Parameters:
+//   - content: The aisle.conf content
+//
+// Returns:
+//   - *AisleConfig: The parsed configuration
+//   - error: An error naming the line if an ingredient appears before the first aisle
+//     or a section header is malformed
+//
+// Example:
+//
+//	aisles, err := cooklang.ParseAisleConfig("[produce]\npotatoes\ntomatoes|tomato\n")
+//	aisles.Aisle("tomato") // "produce"
+func ParseAisleConfig(content string) (*AisleConfig, error) {
+	config := &AisleConfig{ingredients: make(map[string]string)}
+	aisle := ""
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "//") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			name, ok := strings.CutSuffix(line[1:], "]")
+			if !ok || strings.TrimSpace(name) == "" {
+				return nil, fmt.Errorf("line %d: invalid aisle header %q", lineNumber, line)
+			}
+			aisle = strings.TrimSpace(name)
+			config.Aisles = append(config.Aisles, aisle)
+			continue
+		}
+		if aisle == "" {
+			return nil, fmt.Errorf("line %d: ingredient %q is not in an aisle", lineNumber, line)
+		}
+		for _, name := range strings.Split(line, "|") {
+			if name = strings.ToLower(strings.TrimSpace(name)); name != "" {
+				config.ingredients[name] = aisle
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// LoadAisleConfig reads and parses an aisle.conf file.
+//
+// Parameters:
+//   - path: The path of the aisle.conf file
+//
+// Returns:
+//   - *AisleConfig: The parsed configuration
+//   - error: An error if the file cannot be read or parsed
+//
+// Example:
+//
+//	aisles, err := cooklang.LoadAisleConfig("config/aisle.conf")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	list.Aisles = aisles
+func LoadAisleConfig(path string) (*AisleConfig, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	config, err := ParseAisleConfig(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return config, nil
+}
+
+// Aisle returns the aisle of an ingredient. Names are matched case-insensitively;
+// ingredients that are not listed get IngredientAisle's guess.
+//
+// Parameters:
+//   - name: The ingredient name
+//
+// Returns:
+//   - string: The aisle name
+//
+// Example:
+//
+//	aisles.Aisle("Potatoes") // "produce"
+func (c *AisleConfig) Aisle(name string) string {
+	if c != nil {
+		if aisle, ok := c.ingredients[strings.ToLower(strings.TrimSpace(name))]; ok {
+			return aisle
+		}
+	}
+	return IngredientAisle(name)
+}
@@ -0,0 +1,60 @@
+package cooklang
+
+import "testing"
+
+func TestParseAisleConfig(t *testing.T) {
+	aisles, err := ParseAisleConfig("# My store\n[produce]\npotatoes\ntomatoes | Tomato\n\n[dairy]\nmilk\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(aisles.Aisles) != 2 || aisles.Aisles[0] != "produce" || aisles.Aisles[1] != "dairy" {
+		t.Errorf("unexpected aisles: %v", aisles.Aisles)
+	}
+	tests := map[string]string{
+		"potatoes": "produce",
+		"tomato":   "produce",
+		"Milk":     "dairy",
+		"flour":    "Pantry", // Not listed: guessed by IngredientAisle
+	}
+	for name, expected := range tests {
+		if got := aisles.Aisle(name); got != expected {
+			t.Errorf("Aisle(%q) = %q, want %q", name, got, expected)
+		}
+	}
+
+	for _, content := range []string{"milk\n", "[produce\npotatoes\n", "[]\n"} {
+		if _, err := ParseAisleConfig(content); err == nil {
+			t.Errorf("expected an error for %q", content)
+		}
+	}
+}
+
+func TestShoppingList_SortedItemsWithAisles(t *testing.T) {
+	recipe, err := ParseString("Mix @flour{200%g}, @milk{300%ml}, @potatoes{2} and @saffron{}.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	list, err := CreateShoppingList(recipe)
+	if err != nil {
+		t.Fatal(err)
+	}
+	list.Aisles, err = ParseAisleConfig("[dairy]\nmilk\n[produce]\npotatoes\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, item := range list.Scale(2).SortedItems(OrderByAisle) {
+		got = append(got, item.Aisle+":"+item.Name)
+	}
+	expected := []string{"dairy:milk", "produce:potatoes", "Pantry:flour", "Other:saffron"}
+	if len(got) != len(expected) {
+		t.Fatalf("got %v, want %v", got, expected)
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Errorf("got %v, want %v", got, expected)
+			break
+		}
+	}
+}
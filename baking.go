@@ -0,0 +1,223 @@
+package cooklang
+
+import (
+	"errors"
+	"slices"
+	"strings"
+)
+
+// ErrNoFlour is returned by Recipe.BakersPercentages for recipes without flour
+// whose mass is known.
+var ErrNoFlour = errors.New("recipe has no flour with a known mass")
+
+// BakersPercentages is a recipe as a baker's formula: each ingredient's mass as a
+// percentage of the total flour mass, which is 100%.
+type BakersPercentages struct {
+	Flour       float64            `json:"flour"`                 // Total flour mass in grams, including the flour of sourdough starters
+	Hydration   float64            `json:"hydration"`             // Water, including the water in milk, eggs, butter and starters, as a percentage of the flour
+	Ingredients []BakersIngredient `json:"ingredients"`           // Flours first, then the other ingredients from heaviest to lightest
+	Unconverted []string           `json:"unconverted,omitempty"` // Ingredients without a known mass, e.g. "some" salt or a pinch of nutmeg
+}
+
+// BakersIngredient is an ingredient of a baker's formula. Uses of the same
+// ingredient in several steps are added up.
+type BakersIngredient struct {
+	Name    string  `json:"name"`
+	Grams   float64 `json:"grams"`
+	Percent float64 `json:"percent"`         // Mass as a percentage of the total flour mass
+	Flour   bool    `json:"flour,omitempty"` // A flour, see IsFlour
+}
+
+// bakingIngredient describes an ingredient whose volume or count can be weighed:
+// its density in g/ml, its mass per piece, and the parts of it that are water and
+// flour.
+type bakingIngredient struct {
+	name    string
+	density float64 // g/ml, 0 if unknown
+	each    float64 // g per piece, 0 if not counted
+	water   float64 // Part that is water
+	flour   float64 // Part that is flour, for ingredients that are not flours themselves
+}
+
+// bakingIngredients are matched against ingredient names in order, so more specific
+// names come before the names they contain ("egg white" before "egg").
+var bakingIngredients = []bakingIngredient{
+	{name: "whole wheat flour", density: 0.55},
+	{name: "wholemeal", density: 0.55},
+	{name: "flour", density: 0.53},
+	{name: "semolina", density: 0.7},
+	{name: "starter", density: 1, water: 0.5, flour: 0.5}, // At 100% hydration
+	{name: "levain", density: 1, water: 0.5, flour: 0.5},
+	{name: "water", density: 1, water: 1},
+	{name: "buttermilk", density: 1.03, water: 0.9},
+	{name: "milk", density: 1.03, water: 0.87},
+	{name: "cream", density: 1, water: 0.6},
+	{name: "yogurt", density: 1.03, water: 0.85},
+	{name: "yoghurt", density: 1.03, water: 0.85},
+	{name: "beer", density: 1.01, water: 0.92},
+	{name: "egg white", density: 1.03, each: 30, water: 0.88},
+	{name: "egg yolk", density: 1.03, each: 18, water: 0.5},
+	{name: "egg", density: 1.03, each: 50, water: 0.75},
+	{name: "butter", density: 0.96, water: 0.16},
+	{name: "oil", density: 0.92},
+	{name: "honey", density: 1.42, water: 0.17},
+	{name: "brown sugar", density: 0.93},
+	{name: "powdered sugar", density: 0.51},
+	{name: "icing sugar", density: 0.51},
+	{name: "sugar", density: 0.85},
+	{name: "salt", density: 1.2},
+	{name: "yeast", density: 0.6},
+	{name: "baking powder", density: 0.9},
+	{name: "baking soda", density: 0.97},
+	{name: "oats", density: 0.38},
+}
+
+// IsFlour reports whether an ingredient name is a flour for baker's percentages:
+// a name ending in "flour" or "meal" ("bread flour", "wholemeal", "cornmeal"), or
+// semolina.
+//
+// Parameters:
+//   - name: The ingredient name
+//
+// Returns:
+//   - bool: true for flours
+//
+// Example:
+//
+//	cooklang.IsFlour("Bread flour") // true
+//	cooklang.IsFlour("flour tortillas") // false
+func IsFlour(name string) bool {
+	words := strings.Fields(strings.ToLower(name))
+	if len(words) == 0 {
+		return false
+	}
+	last := words[len(words)-1]
+	return last == "flour" || last == "flours" || strings.HasSuffix(last, "meal") || slices.Contains(words, "semolina")
+}
+
+// lookupBakingIngredient finds the description of an ingredient by name, matching
+// whole words and plurals, so "large eggs" is an egg and "eggplant" is not.
+func lookupBakingIngredient(name string) (bakingIngredient, bool) {
+	padded := " " + strings.Join(strings.Fields(strings.ToLower(name)), " ") + " "
+	for _, known := range bakingIngredients {
+		for _, form := range []string{known.name, known.name + "s", known.name + "es"} {
+			if strings.Contains(padded, " "+form+" ") {
+				return known, true
+			}
+		}
+	}
+	return bakingIngredient{}, false
+}
+
+// Grams returns the mass of the ingredient in grams. Masses are converted; volumes
+// and counted items (@eggs{2}) are weighed for common baking ingredients such as
+// flour, sugar, butter, milk and eggs. Ranges count as their middle.
+//
+// Returns:
+//   - float64: The mass in grams
+//   - bool: false if the ingredient has no amount or its mass is not known
+//
+// Example:
+//
+//	milk := cooklang.NewIngredient("milk", 1, "cup")
+//	grams, _ := milk.Grams() // 243.7
+func (i *Ingredient) Grams() (float64, bool) {
+	if !i.Quantity.HasAmount() {
+		return 0, false
+	}
+	amount := (i.Quantity.Min() + i.Quantity.Max()) / 2
+	known, found := lookupBakingIngredient(i.Name)
+	switch {
+	case i.GetUnitType() == "mass":
+		if converted, err := i.ConvertTo("g"); err == nil {
+			return (converted.Quantity.Min() + converted.Quantity.Max()) / 2, true
+		}
+	case i.GetUnitType() == "volume" && found && known.density > 0:
+		if converted, err := i.ConvertTo("ml"); err == nil {
+			return (converted.Quantity.Min() + converted.Quantity.Max()) / 2 * known.density, true
+		}
+	case i.IsCount() && found && known.each > 0:
+		return amount * known.each, true
+	}
+	return 0, false
+}
+
+// BakersPercentages returns the recipe as a baker's formula: the mass of each
+// ingredient as a percentage of the total flour mass (see IsFlour), and the
+// hydration, the water as a percentage of the flour. Sourdough starters and levains
+// count as half flour and half water; milk, eggs, butter and honey add the water
+// they contain. Ingredients are weighed with Ingredient.Grams, and those whose mass
+// is not known are listed in Unconverted. References to ingredients added earlier
+// (@&flour{}) are left out.
+//
+// Returns:
+//   - *BakersPercentages: The formula
+//   - error: ErrNoFlour if no flour has a known mass
+//
+// Example:
+//
+//	recipe, _ := cooklang.ParseString("Mix @bread flour{500%g}, @water{350%g}, @salt{10%g} and @yeast{7%g}.")
+//	formula, _ := recipe.BakersPercentages()
+//	fmt.Printf("%.0f%% hydration\n", formula.Hydration) // 70% hydration
+//	for _, ingredient := range formula.Ingredients {
+//	    fmt.Printf("%s: %.1f%%\n", ingredient.Name, ingredient.Percent) // bread flour: 100.0%, water: 70.0%, ...
+//	}
+func (r *Recipe) BakersPercentages() (*BakersPercentages, error) {
+	formula := &BakersPercentages{}
+	byName := make(map[string]int) // Lower-case name -> index in Ingredients
+	var water float64
+	for _, ingredient := range r.GetIngredients().Ingredients {
+		if ingredient.IsReference() {
+			continue
+		}
+		grams, ok := ingredient.Grams()
+		if !ok {
+			if !slices.Contains(formula.Unconverted, ingredient.Name) {
+				formula.Unconverted = append(formula.Unconverted, ingredient.Name)
+			}
+			continue
+		}
+
+		flour := IsFlour(ingredient.Name)
+		if flour {
+			formula.Flour += grams
+		} else {
+			known, _ := lookupBakingIngredient(ingredient.Name)
+			formula.Flour += grams * known.flour
+			water += grams * known.water
+		}
+
+		key := libraryKey(ingredient.Name)
+		if index, seen := byName[key]; seen {
+			formula.Ingredients[index].Grams += grams
+			continue
+		}
+		byName[key] = len(formula.Ingredients)
+		formula.Ingredients = append(formula.Ingredients, BakersIngredient{Name: ingredient.Name, Grams: grams, Flour: flour})
+	}
+	if formula.Flour <= 0 {
+		return nil, ErrNoFlour
+	}
+
+	formula.Hydration = water / formula.Flour * 100
+	for i := range formula.Ingredients {
+		formula.Ingredients[i].Percent = formula.Ingredients[i].Grams / formula.Flour * 100
+	}
+	slices.SortStableFunc(formula.Ingredients, func(a, b BakersIngredient) int {
+		switch {
+		case a.Flour != b.Flour:
+			if a.Flour {
+				return -1
+			}
+			return 1
+		case a.Flour:
+			return 0 // Flours in recipe order
+		case a.Grams > b.Grams:
+			return -1
+		case a.Grams < b.Grams:
+			return 1
+		}
+		return 0
+	})
+	return formula, nil
+}
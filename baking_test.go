@@ -0,0 +1,102 @@
+package cooklang
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestIsFlour(t *testing.T) {
+	tests := map[string]bool{
+		"flour":             true,
+		"Bread flour":       true,
+		"whole wheat flour": true,
+		"wholemeal":         true,
+		"cornmeal":          true,
+		"semolina":          true,
+		"flour tortillas":   false,
+		"water":             false,
+		"":                  false,
+	}
+	for name, expected := range tests {
+		if got := IsFlour(name); got != expected {
+			t.Errorf("IsFlour(%q) = %v, want %v", name, got, expected)
+		}
+	}
+}
+
+func TestIngredientGrams(t *testing.T) {
+	tests := []struct {
+		ingredient *Ingredient
+		grams      float64
+		ok         bool
+	}{
+		{NewIngredient("flour", 1, "kg"), 1000, true},
+		{NewIngredient("butter", 8, "oz"), 226.8, true},
+		{NewIngredient("milk", 1, "cup"), 243.7, true},
+		{NewIngredient("flour", 1, "cup"), 125.4, true},
+		{NewIngredient("large eggs", 2, ""), 100, true},
+		{NewIngredient("eggplant", 2, ""), 0, false},
+		{NewIngredient("vanilla", 1, "tsp"), 0, false},
+		{&Ingredient{Name: "salt", Quantity: SomeQuantity()}, 0, false},
+	}
+	for _, tt := range tests {
+		grams, ok := tt.ingredient.Grams()
+		if ok != tt.ok || math.Abs(grams-tt.grams) > 0.1 {
+			t.Errorf("%v %s %s: got %.1f, %v; want %.1f, %v", tt.ingredient.Quantity, tt.ingredient.Unit, tt.ingredient.Name, grams, ok, tt.grams, tt.ok)
+		}
+	}
+}
+
+func TestRecipeBakersPercentages(t *testing.T) {
+	recipe, err := ParseString(`Mix @bread flour{400%g}, @whole wheat flour{100%g}, @sourdough starter{100%g} and @water{300%g}.
+
+Add @salt{10%g}, @olive oil{2%tbsp} and a pinch of @nutmeg{}.
+
+Dust with @bread flour{20%g} and fold in the @&water{}.
+`)
+	if err != nil {
+		t.Fatalf("Failed to parse recipe: %v", err)
+	}
+
+	formula, err := recipe.BakersPercentages()
+	if err != nil {
+		t.Fatalf("BakersPercentages failed: %v", err)
+	}
+	if formula.Flour != 570 {
+		t.Errorf("expected 570 g of flour, got %v", formula.Flour)
+	}
+	if hydration := math.Round(formula.Hydration*10) / 10; hydration != 61.4 {
+		t.Errorf("expected 61.4%% hydration, got %v", formula.Hydration)
+	}
+
+	expected := []struct {
+		name    string
+		percent float64
+		flour   bool
+	}{
+		{"bread flour", 73.7, true},
+		{"whole wheat flour", 17.5, true},
+		{"water", 52.6, false},
+		{"sourdough starter", 17.5, false},
+		{"olive oil", 4.8, false},
+		{"salt", 1.8, false},
+	}
+	if len(formula.Ingredients) != len(expected) {
+		t.Fatalf("expected %d ingredients, got %+v", len(expected), formula.Ingredients)
+	}
+	for i, want := range expected {
+		got := formula.Ingredients[i]
+		if got.Name != want.name || math.Round(got.Percent*10)/10 != want.percent || got.Flour != want.flour {
+			t.Errorf("ingredient %d: got %s %.1f%% (flour: %v), want %s %.1f%% (flour: %v)", i, got.Name, got.Percent, got.Flour, want.name, want.percent, want.flour)
+		}
+	}
+	if len(formula.Unconverted) != 1 || formula.Unconverted[0] != "nutmeg" {
+		t.Errorf("expected nutmeg to be unconverted, got %v", formula.Unconverted)
+	}
+
+	recipe, _ = ParseString("Shake @gin{50%ml} with @ice{}.")
+	if _, err := recipe.BakersPercentages(); !errors.Is(err, ErrNoFlour) {
+		t.Errorf("expected ErrNoFlour, got %v", err)
+	}
+}
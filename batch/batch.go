@@ -0,0 +1,299 @@
+// Package batch renders a directory of recipes at once, for publishing a collection
+// as a static site: every recipe is rendered concurrently by a pool of workers,
+// written under a slugified file name ("Main Courses/Beef Stew.cook" becomes
+// "main-courses/beef-stew.html"), its images are copied next to it, and an
+// index.html links to all of them.
+//
+// Example:
+//
+//	result, err := batch.Render("recipes", renderers.HTMLRenderer{}, batch.Options{
+//	    Output: "public",
+//	    Index:  true,
+//	    Progress: func(p batch.Progress) {
+//	        fmt.Printf("[%d/%d] %s\n", p.Done, p.Total, p.Entry.Source)
+//	    },
+//	})
+package batch
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/hilli/cooklang"
+	"github.com/hilli/cooklang/naming"
+	"github.com/hilli/cooklang/renderers"
+)
+
+// IndexFile is the name of the index page Render writes with Options.Index.
+const IndexFile = "index.html"
+
+// Options controls Render.
+type Options struct {
+	Output          string                    // Directory to write to (required)
+	Extension       string                    // Extension of the rendered files (default ".html")
+	Workers         int                       // Recipes rendered at once (default: the number of CPUs)
+	RendererOptions renderers.RendererOptions // Options for every recipe; Images is set per recipe
+	Images          cooklang.ImageMode        // How rendered files refer to recipe and step images; "" leaves the sources as written
+	Parse           []cooklang.ParseOption    // Options for parsing the recipes
+	Index           bool                      // Write an index.html linking to every rendered recipe
+	IndexTitle      string                    // Heading of the index page (default "Recipes")
+	Progress        func(Progress)            // Called after each recipe, one call at a time (optional)
+
+	// Prepare changes each recipe before it is rendered, e.g. with a transform
+	// pipeline (optional).
+	Prepare func(recipe *cooklang.Recipe) (*cooklang.Recipe, error)
+	// Document turns a rendering into the file's content, e.g. a complete HTML page
+	// around an HTML fragment (optional).
+	Document func(rendered string, recipe *cooklang.Recipe) string
+}
+
+// Entry is a recipe of a batch.
+type Entry struct {
+	Source string `json:"source"`          // Recipe file, relative to the rendered directory
+	Output string `json:"output"`          // Written file, relative to Options.Output
+	Title  string `json:"title,omitempty"` // Recipe title; empty if the recipe could not be read
+	Err    error  `json:"-"`               // Why the recipe could not be rendered, or nil
+}
+
+// Progress reports a finished recipe to Options.Progress.
+type Progress struct {
+	Done  int   // Recipes finished so far, including this one
+	Total int   // Recipes in the batch
+	Entry Entry // The finished recipe; Entry.Err is set if it failed
+}
+
+// Result lists the recipes of a batch.
+type Result struct {
+	Rendered []Entry `json:"rendered"`         // Recipes written, sorted by source
+	Failed   []Entry `json:"failed,omitempty"` // Recipes that could not be rendered, sorted by source
+	Index    string  `json:"index,omitempty"`  // The index page, relative to Options.Output, if written
+}
+
+// Render renders every .cook file in dir and its subdirectories with the renderer
+// and writes them to opts.Output, keeping the folder structure with slugified
+// names (see OutputPath; names that collide get "-2", "-3", ...). Recipes that
+// fail do not stop the others: they are listed in Result.Failed.
+//
+// Parameters:
+//   - dir: The directory of recipes
+//   - renderer: The renderer for every recipe
+//   - opts: The output directory and other options
+//
+// Returns:
+//   - *Result: The rendered and failed recipes
+//   - error: An error if dir cannot be read, the output directory or index cannot be
+//     written, or opts has no Output
+func Render(dir string, renderer renderers.Renderer, opts Options) (*Result, error) {
+	if opts.Output == "" {
+		return nil, errors.New("batch: no output directory")
+	}
+	if opts.Extension == "" {
+		opts.Extension = ".html"
+	}
+	if opts.Workers <= 0 {
+		opts.Workers = runtime.NumCPU()
+	}
+
+	entries, err := findRecipes(dir, opts.Extension)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(opts.Output, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	jobs := make(chan int)
+	done := make(chan int)
+	var wg sync.WaitGroup
+	for range min(opts.Workers, max(len(entries), 1)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				entries[i].Title, entries[i].Err = renderEntry(dir, entries[i], renderer, opts)
+				done <- i
+			}
+		}()
+	}
+	go func() {
+		for i := range entries {
+			jobs <- i
+		}
+		close(jobs)
+		wg.Wait()
+		close(done)
+	}()
+
+	finished := 0
+	for i := range done {
+		finished++
+		if opts.Progress != nil {
+			opts.Progress(Progress{Done: finished, Total: len(entries), Entry: entries[i]})
+		}
+	}
+
+	result := &Result{Rendered: []Entry{}}
+	for _, entry := range entries {
+		if entry.Err != nil {
+			result.Failed = append(result.Failed, entry)
+		} else {
+			result.Rendered = append(result.Rendered, entry)
+		}
+	}
+	if opts.Index {
+		if err := writeIndex(result.Rendered, opts); err != nil {
+			return result, err
+		}
+		result.Index = IndexFile
+	}
+	return result, nil
+}
+
+// findRecipes lists the recipes in dir with their output paths, sorted by source.
+func findRecipes(dir, extension string) ([]Entry, error) {
+	var entries []Entry
+	taken := map[string]bool{}
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || filepath.Ext(p) != ".cook" {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		output := OutputPath(filepath.ToSlash(rel), extension)
+		base := strings.TrimSuffix(output, extension)
+		for n := 2; taken[output] || output == IndexFile; n++ {
+			output = base + "-" + strconv.Itoa(n) + extension
+		}
+		taken[output] = true
+		entries = append(entries, Entry{Source: filepath.ToSlash(rel), Output: output})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", dir, err)
+	}
+	return entries, nil
+}
+
+// renderEntry renders one recipe and writes it, returning its title.
+func renderEntry(dir string, entry Entry, renderer renderers.Renderer, opts Options) (string, error) {
+	source := filepath.Join(dir, filepath.FromSlash(entry.Source))
+	recipe, err := cooklang.ParseFile(source, opts.Parse...)
+	if err != nil {
+		return "", err
+	}
+	if opts.Prepare != nil {
+		prepared, err := opts.Prepare(recipe)
+		if err != nil {
+			return recipe.Title, err
+		}
+		recipe = prepared
+	}
+
+	output := filepath.Join(opts.Output, filepath.FromSlash(entry.Output))
+	options := opts.RendererOptions
+	if opts.Images != "" {
+		options.Images, err = cooklang.ImageSources(recipe, filepath.Dir(source), output, opts.Images)
+		if err != nil {
+			return recipe.Title, err
+		}
+		if err := cooklang.SetStepImageSources(recipe, filepath.Dir(source), output, opts.Images); err != nil {
+			return recipe.Title, err
+		}
+	}
+	rendered, err := renderer.Render(recipe, options)
+	if err != nil {
+		return recipe.Title, err
+	}
+	if opts.Document != nil {
+		rendered = opts.Document(rendered, recipe)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(output), 0o755); err != nil {
+		return recipe.Title, fmt.Errorf("failed to create output directory: %w", err)
+	}
+	if err := os.WriteFile(output, []byte(rendered), 0o644); err != nil {
+		return recipe.Title, fmt.Errorf("failed to write output file: %w", err)
+	}
+	return recipe.Title, nil
+}
+
+// OutputPath returns the slugified output path of a recipe: each part of its
+// slash-separated path is slugified with naming.Slugify, and ".cook" is replaced by
+// extension.
+//
+// Parameters:
+//   - source: The recipe's path, relative to the rendered directory
+//   - extension: The extension of the output, e.g. ".html"
+//
+// Returns:
+//   - string: The output path, e.g. "main-courses/beef-stew.html"
+func OutputPath(source, extension string) string {
+	parts := strings.Split(strings.TrimSuffix(source, ".cook"), "/")
+	for i, part := range parts {
+		if slug := naming.Slugify(part); slug != "" {
+			parts[i] = slug
+		} else {
+			parts[i] = "recipe"
+		}
+	}
+	return path.Join(parts...) + extension
+}
+
+// writeIndex writes the index page linking to the rendered recipes, sorted by title.
+func writeIndex(entries []Entry, opts Options) error {
+	sorted := append([]Entry(nil), entries...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return strings.ToLower(indexTitle(sorted[i])) < strings.ToLower(indexTitle(sorted[j]))
+	})
+	title := opts.IndexTitle
+	if title == "" {
+		title = "Recipes"
+	}
+
+	var page strings.Builder
+	page.WriteString("<!DOCTYPE html>\n")
+	fmt.Fprintf(&page, "<html lang=\"%s\">\n", html.EscapeString(opts.RendererOptions.Language()))
+	page.WriteString("<head>\n")
+	page.WriteString("  <meta charset=\"UTF-8\">\n")
+	page.WriteString("  <meta name=\"viewport\" content=\"width=device-width, initial-scale=1.0\">\n")
+	fmt.Fprintf(&page, "  <title>%s</title>\n", html.EscapeString(title))
+	page.WriteString("</head>\n")
+	page.WriteString("<body>\n")
+	fmt.Fprintf(&page, "<h1>%s</h1>\n", html.EscapeString(title))
+	page.WriteString("<ul class=\"recipe-index\">\n")
+	for _, entry := range sorted {
+		fmt.Fprintf(&page, "  <li><a href=\"%s\">%s</a></li>\n", html.EscapeString(entry.Output), html.EscapeString(indexTitle(entry)))
+	}
+	page.WriteString("</ul>\n")
+	page.WriteString("</body>\n")
+	page.WriteString("</html>\n")
+
+	if err := os.WriteFile(filepath.Join(opts.Output, IndexFile), []byte(page.String()), 0o644); err != nil {
+		return fmt.Errorf("failed to write index: %w", err)
+	}
+	return nil
+}
+
+// indexTitle returns the name of a recipe on the index page: its title, or its
+// file name.
+func indexTitle(entry Entry) string {
+	if entry.Title != "" {
+		return entry.Title
+	}
+	return strings.TrimSuffix(path.Base(entry.Source), ".cook")
+}
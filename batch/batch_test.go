@@ -0,0 +1,111 @@
+package batch
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hilli/cooklang"
+	"github.com/hilli/cooklang/renderers"
+)
+
+func TestOutputPath(t *testing.T) {
+	tests := []struct {
+		source   string
+		expected string
+	}{
+		{"Pancakes.cook", "pancakes.html"},
+		{"Main Courses/Beef Stew.cook", "main-courses/beef-stew.html"},
+		{"Gin & Tonic (v2).cook", "gin-tonic-v2.html"},
+		{"Crème Brûlée.cook", "creme-brulee.html"},
+		{"!!!.cook", "recipe.html"},
+	}
+	for _, tt := range tests {
+		if got := OutputPath(tt.source, ".html"); got != tt.expected {
+			t.Errorf("OutputPath(%q) = %q, want %q", tt.source, got, tt.expected)
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(t.TempDir(), "public")
+	files := map[string]string{
+		"Soup.cook":             "---\ntitle: Tomato Soup\n---\nBoil @tomatoes{4}.\n",
+		"Soup.jpg":              "jpeg",
+		"soup.cook":             "Heat @stock{1%l}.\n",
+		"Drinks/Negroni.cook":   "Stir @gin{30%ml}.\n",
+		"Drinks/Bad Mood.cook":  "Stir @vodka{30%ml}.\n",
+		"Drinks/notes/todo.txt": "not a recipe",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var progress []Progress
+	result, err := Render(dir, renderers.HTMLRenderer{}, Options{
+		Output:  out,
+		Workers: 3,
+		Images:  cooklang.ImagesCopied,
+		Index:   true,
+		Prepare: func(recipe *cooklang.Recipe) (*cooklang.Recipe, error) {
+			if recipe.Title == "Bad Mood" {
+				return nil, errors.New("no vodka")
+			}
+			return recipe.Scale(2), nil
+		},
+		Progress: func(p Progress) { progress = append(progress, p) },
+	})
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	var rendered []string
+	for _, entry := range result.Rendered {
+		rendered = append(rendered, entry.Source+"="+entry.Output)
+	}
+	if got := strings.Join(rendered, ","); got != "Drinks/Negroni.cook=drinks/negroni.html,Soup.cook=soup.html,soup.cook=soup-2.html" {
+		t.Errorf("rendered = %s", got)
+	}
+	if len(result.Failed) != 1 || result.Failed[0].Source != "Drinks/Bad Mood.cook" || result.Failed[0].Err == nil {
+		t.Errorf("failed = %+v, want Bad Mood with its error", result.Failed)
+	}
+	if len(progress) != 4 || progress[3].Done != 4 || progress[3].Total != 4 {
+		t.Errorf("progress = %+v, want 4 calls counting to 4", progress)
+	}
+
+	soup, err := os.ReadFile(filepath.Join(out, "soup.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(soup), `<span class="quantity">8</span>`) || !strings.Contains(string(soup), `src="soup.jpg"`) {
+		t.Errorf("expected the scaled soup with its copied image, got:\n%s", soup)
+	}
+	if _, err := os.Stat(filepath.Join(out, "soup.jpg")); err != nil {
+		t.Errorf("expected the image to be copied: %v", err)
+	}
+
+	index, err := os.ReadFile(filepath.Join(out, IndexFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(index), `<li><a href="drinks/negroni.html">Negroni</a></li>
+  <li><a href="soup-2.html">soup</a></li>
+  <li><a href="soup.html">Tomato Soup</a></li>`) {
+		t.Errorf("unexpected index:\n%s", index)
+	}
+}
+
+func TestRenderNeedsOutput(t *testing.T) {
+	if _, err := Render(t.TempDir(), renderers.HTMLRenderer{}, Options{}); err == nil {
+		t.Error("expected an error without an output directory")
+	}
+}
@@ -0,0 +1,219 @@
+package cooklang
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// MetadataOperation changes the metadata of one recipe. Operations are applied by a
+// BulkEditor, but can be called on any FrontmatterEditor.
+type MetadataOperation func(editor *FrontmatterEditor) error
+
+// SetMetadataOp returns an operation that sets key to value.
+//
+// Parameters:
+//   - key: The metadata key
+//   - value: The new value (comma-separated for tags and images)
+//
+// Returns:
+//   - MetadataOperation: The operation
+func SetMetadataOp(key, value string) MetadataOperation {
+	return func(editor *FrontmatterEditor) error {
+		return editor.SetMetadata(key, value)
+	}
+}
+
+// DeleteMetadataOp returns an operation that removes key.
+//
+// Parameters:
+//   - key: The metadata key
+//
+// Returns:
+//   - MetadataOperation: The operation
+func DeleteMetadataOp(key string) MetadataOperation {
+	return func(editor *FrontmatterEditor) error {
+		return editor.DeleteMetadata(key)
+	}
+}
+
+// AddTagOp returns an operation that adds a tag to recipes that do not have it yet.
+// Tags are compared case-insensitively.
+//
+// Parameters:
+//   - tag: The tag to add
+//
+// Returns:
+//   - MetadataOperation: The operation
+func AddTagOp(tag string) MetadataOperation {
+	return func(editor *FrontmatterEditor) error {
+		for _, existing := range editor.recipe.Tags {
+			if strings.EqualFold(existing, tag) {
+				return nil
+			}
+		}
+		return editor.AppendToArray("tags", tag)
+	}
+}
+
+// RemoveTagOp returns an operation that removes a tag.
+//
+// Parameters:
+//   - tag: The tag to remove
+//
+// Returns:
+//   - MetadataOperation: The operation
+func RemoveTagOp(tag string) MetadataOperation {
+	return func(editor *FrontmatterEditor) error {
+		return editor.RemoveFromArray("tags", tag)
+	}
+}
+
+// NormalizeDatesOp returns an operation that rewrites every date the editor's schema
+// can read (e.g. "January 5, 2024") in YYYY-MM-DD form. Dates that cannot be read are
+// left unchanged.
+//
+// Returns:
+//   - MetadataOperation: The operation
+func NormalizeDatesOp() MetadataOperation {
+	return func(editor *FrontmatterEditor) error {
+		for key, value := range editor.recipe.Metadata {
+			if editor.schema.Type(key) != MetadataDate || strings.TrimSpace(value) == "" {
+				continue
+			}
+			date, err := editor.schema.ParseDate(value)
+			if err != nil {
+				continue
+			}
+			if normalized := date.Format("2006-01-02"); normalized != value {
+				if err := editor.SetMetadata(key, normalized); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+}
+
+// BulkEditor applies metadata operations to every recipe in a set of files and
+// directories.
+//
+// Example:
+//
+//	editor := cooklang.BulkEditor{DryRun: true}
+//	report, err := editor.Apply([]string{"recipes"}, cooklang.SetMetadataOp("author", "Jane"))
+//	for _, file := range report.Changed() {
+//	    fmt.Println(file.Path, len(file.Changes))
+//	}
+type BulkEditor struct {
+	DryRun bool            // Report the changes without writing any file
+	Atomic bool            // Save files atomically (see FrontmatterEditor.SetAtomicSave)
+	Schema *MetadataSchema // Schema for validating values (default DefaultMetadataSchema)
+}
+
+// BulkReport is the result of a bulk edit.
+type BulkReport struct {
+	Files  []BulkFileReport `json:"files"`   // Every recipe that was processed, in the order found
+	DryRun bool             `json:"dry_run"` // True if no files were written
+}
+
+// BulkFileReport describes what a bulk edit did to one recipe.
+type BulkFileReport struct {
+	Path    string           `json:"path"`
+	Changes []MetadataChange `json:"changes,omitempty"`
+	Error   string           `json:"error,omitempty"` // Why the recipe could not be edited
+}
+
+// Changed returns the reports of the recipes that were (or, in a dry run, would be)
+// changed.
+//
+// Returns:
+//   - []BulkFileReport: The changed recipes
+func (r *BulkReport) Changed() []BulkFileReport {
+	var changed []BulkFileReport
+	for _, file := range r.Files {
+		if len(file.Changes) > 0 {
+			changed = append(changed, file)
+		}
+	}
+	return changed
+}
+
+// Apply runs the operations, in order, on every .cook file in paths. Directories are
+// searched recursively, skipping hidden directories. Recipes are only written if an
+// operation changed their metadata, and never in a dry run. A recipe that cannot be
+// read, edited or written does not stop the others.
+//
+// Parameters:
+//   - paths: .cook files and directories to edit
+//   - ops: The operations to apply to each recipe
+//
+// Returns:
+//   - *BulkReport: What changed in each recipe
+//   - error: All failures joined, or nil
+//
+// Example:
+//
+//	report, err := cooklang.BulkEditor{}.Apply([]string{"recipes/salads"}, cooklang.AddTagOp("vegan"))
+//	if err != nil {
+//	    log.Print(err)
+//	}
+//	fmt.Printf("%d recipes tagged\n", len(report.Changed()))
+func (b BulkEditor) Apply(paths []string, ops ...MetadataOperation) (*BulkReport, error) {
+	report := &BulkReport{DryRun: b.DryRun}
+	var errs []error
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if !info.IsDir() {
+			file, err := b.applyFile(path, ops)
+			report.Files = append(report.Files, file)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", path, err))
+			}
+			continue
+		}
+		walkErrs := walkCookFiles(path, func(file, _ string) error {
+			result, err := b.applyFile(file, ops)
+			report.Files = append(report.Files, result)
+			return err
+		})
+		errs = append(errs, walkErrs...)
+	}
+	return report, errors.Join(errs...)
+}
+
+// applyFile runs the operations on one recipe and saves it if anything changed.
+func (b BulkEditor) applyFile(path string, ops []MetadataOperation) (BulkFileReport, error) {
+	result := BulkFileReport{Path: path}
+	fail := func(err error) (BulkFileReport, error) {
+		result.Error = err.Error()
+		return result, err
+	}
+
+	editor, err := NewFrontmatterEditor(path)
+	if err != nil {
+		return fail(err)
+	}
+	if b.Schema != nil {
+		editor.SetSchema(b.Schema)
+	}
+	editor.SetAtomicSave(b.Atomic)
+	for _, op := range ops {
+		if err := op(editor); err != nil {
+			return fail(err)
+		}
+	}
+
+	result.Changes = editor.Changes()
+	if len(result.Changes) > 0 && !b.DryRun {
+		if err := editor.Save(); err != nil {
+			return fail(err)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,103 @@
+package cooklang
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeBulkRecipes(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	files := map[string]string{
+		"Toast.cook":         "---\ntitle: Toast\nauthor: Sam\ndate: January 5, 2024\n---\nToast @bread{2%slices}.\n",
+		"salads/Caesar.cook": "---\ntitle: Caesar\ntags: [salad, Vegan]\n---\nToss @lettuce{1}.\n",
+		"salads/Greek.cook":  "Toss @tomatoes{3} and @feta{200%g}.\n",
+		".drafts/Draft.cook": "---\ntitle: Draft\n---\nTBD.\n",
+		"salads/notes.txt":   "not a recipe",
+		"salads/Broken.cook": "---\ntitle: Broken\nservings: many\n---\nMix.\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestBulkEditorDryRun(t *testing.T) {
+	dir := writeBulkRecipes(t)
+	toast := filepath.Join(dir, "Toast.cook")
+	before, _ := os.ReadFile(toast)
+
+	report, err := BulkEditor{DryRun: true}.Apply([]string{dir, filepath.Join(dir, "Missing.cook")}, SetMetadataOp("author", "Jane"))
+	if err == nil || !strings.Contains(err.Error(), "Missing.cook") {
+		t.Errorf("expected an error for Missing.cook, got %v", err)
+	}
+	if !report.DryRun {
+		t.Error("expected a dry-run report")
+	}
+
+	changed := report.Changed()
+	var paths []string
+	for _, file := range changed {
+		rel, _ := filepath.Rel(dir, file.Path)
+		paths = append(paths, filepath.ToSlash(rel))
+	}
+	expected := "Toast.cook salads/Broken.cook salads/Caesar.cook salads/Greek.cook"
+	if strings.Join(paths, " ") != expected {
+		t.Errorf("expected changes in %s, got %v", expected, paths)
+	}
+	if c := changed[0].Changes; len(c) != 1 || c[0].Type != ChangeModified || c[0].Old != "Sam" || c[0].New != "Jane" {
+		t.Errorf("unexpected changes for Toast.cook: %+v", c)
+	}
+
+	if after, _ := os.ReadFile(toast); string(after) != string(before) {
+		t.Errorf("dry run modified Toast.cook:\n%s", after)
+	}
+}
+
+func TestBulkEditorApply(t *testing.T) {
+	dir := writeBulkRecipes(t)
+	salads := filepath.Join(dir, "salads")
+
+	report, err := BulkEditor{}.Apply([]string{salads, filepath.Join(dir, "Toast.cook")}, AddTagOp("vegan"), NormalizeDatesOp())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Changed()) != 3 {
+		t.Errorf("expected 3 changed recipes, got %+v", report.Changed())
+	}
+
+	caesar, _ := os.ReadFile(filepath.Join(salads, "Caesar.cook"))
+	if !strings.Contains(string(caesar), "tags: [salad, Vegan]") {
+		t.Errorf("Caesar already has the tag and should be unchanged:\n%s", caesar)
+	}
+	greek, _ := os.ReadFile(filepath.Join(salads, "Greek.cook"))
+	if !strings.HasPrefix(string(greek), "---\ntags:\n  - vegan\n---\n") {
+		t.Errorf("expected Greek to get frontmatter with the tag:\n%s", greek)
+	}
+	toast, _ := os.ReadFile(filepath.Join(dir, "Toast.cook"))
+	if !strings.Contains(string(toast), "date: 2024-01-05\n") || !strings.Contains(string(toast), "  - vegan\n") {
+		t.Errorf("expected Toast to be tagged with a normalized date:\n%s", toast)
+	}
+	draft, _ := os.ReadFile(filepath.Join(dir, ".drafts", "Draft.cook"))
+	if strings.Contains(string(draft), "vegan") {
+		t.Error("hidden directories should be skipped")
+	}
+
+	report, err = BulkEditor{}.Apply([]string{salads}, SetMetadataOp("servings", "plenty"))
+	if err == nil || len(report.Changed()) != 0 {
+		t.Errorf("expected invalid servings to be rejected, got %v", err)
+	}
+	for _, file := range report.Files {
+		if file.Error == "" {
+			t.Errorf("expected an error for %s in the report", file.Path)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package cooklang
+
+import (
+	"reflect"
+	"time"
+)
+
+// Clone returns a deep copy of the recipe. Every step and component is copied, so the
+// copy's linked lists share no nodes with the original and either can be modified
+// without affecting the other. A renderer set with SetRenderer renders the copy, not
+// the original; render functions assigned directly to the recipe or its steps are
+// not copied.
+//
+// Returns:
+//   - *Recipe: The copy, or nil if the recipe is nil
+//
+// Example:
+//
+//	draft := recipe.Clone()
+//	draft.Title = "Pancakes (gluten free)"
+//	// recipe.Title is unchanged
+func (r *Recipe) Clone() *Recipe {
+	if r == nil {
+		return nil
+	}
+	clone := r.copyRecipeFields()
+
+	var lastStep *Step
+	for step := r.FirstStep; step != nil; step = step.NextStep {
+		newStep := &Step{Position: step.Position}
+		if step.Images != nil {
+			newStep.Images = append([]string(nil), step.Images...)
+		}
+
+		var lastComponent StepComponent
+		for component := step.FirstComponent; component != nil; component = component.GetNext() {
+			newComponent := copyComponent(component)
+			if newComponent == nil {
+				continue
+			}
+			if lastComponent == nil {
+				newStep.FirstComponent = newComponent
+			} else {
+				lastComponent.SetNext(newComponent)
+			}
+			lastComponent = newComponent
+		}
+
+		if lastStep == nil {
+			clone.FirstStep = newStep
+		} else {
+			lastStep.NextStep = newStep
+		}
+		lastStep = newStep
+	}
+
+	// Point the copied ingredients at the copy's custom units
+	if len(clone.CustomUnits) > 0 {
+		clone.SetCustomUnits(nil)
+	}
+	return clone
+}
+
+// Equal reports whether two recipes have the same content: the same metadata and the
+// same steps with the same components. Source positions and custom render functions
+// are ignored, so a recipe built in code can equal a parsed one.
+//
+// Parameters:
+//   - other: The recipe to compare with
+//
+// Returns:
+//   - bool: true if the recipes are equal (two nil recipes are equal)
+//
+// Example:
+//
+//	if !got.Equal(want) {
+//	    t.Errorf("unexpected recipe: %s", got.Render())
+//	}
+func (r *Recipe) Equal(other *Recipe) bool {
+	if r == nil || other == nil {
+		return r == other
+	}
+	if !r.Date.Equal(other.Date) {
+		return false
+	}
+	a, b := comparableFields(r), comparableFields(other)
+	if !reflect.DeepEqual(a, b) {
+		return false
+	}
+
+	stepA, stepB := r.FirstStep, other.FirstStep
+	for stepA != nil && stepB != nil {
+		if !stepA.Equal(stepB) {
+			return false
+		}
+		stepA, stepB = stepA.NextStep, stepB.NextStep
+	}
+	return stepA == nil && stepB == nil
+}
+
+// Equal reports whether two steps have the same images and components, ignoring
+// source positions and custom render functions. The steps that follow are not
+// compared.
+//
+// Parameters:
+//   - other: The step to compare with
+//
+// Returns:
+//   - bool: true if the steps are equal (two nil steps are equal)
+func (s *Step) Equal(other *Step) bool {
+	if s == nil || other == nil {
+		return s == other
+	}
+	if len(s.Images) != len(other.Images) {
+		return false
+	}
+	for i := range s.Images {
+		if s.Images[i] != other.Images[i] {
+			return false
+		}
+	}
+
+	a, b := s.FirstComponent, other.FirstComponent
+	for a != nil && b != nil {
+		if !reflect.DeepEqual(comparableComponent(a), comparableComponent(b)) {
+			return false
+		}
+		a, b = a.GetNext(), b.GetNext()
+	}
+	return a == nil && b == nil
+}
+
+// comparableFields returns a copy of the recipe's top-level fields without steps,
+// date or render function, for comparison with reflect.DeepEqual.
+func comparableFields(r *Recipe) *Recipe {
+	fields := r.copyRecipeFields()
+	fields.Date = time.Time{} // Compared with time.Time.Equal
+	fields.CooklangRenderable, fields.renderer = CooklangRenderable{}, nil
+	if len(fields.Images) == 0 {
+		fields.Images = nil
+	}
+	if len(fields.Tags) == 0 {
+		fields.Tags = nil
+	}
+	if len(fields.CustomUnits) == 0 {
+		fields.CustomUnits = nil
+	}
+	return fields
+}
+
+// comparableComponent returns an unlinked copy of a component without source
+// position, render function or custom units, for comparison with reflect.DeepEqual.
+func comparableComponent(component StepComponent) StepComponent {
+	switch comp := copyComponent(component).(type) {
+	case *Ingredient:
+		comp.Position, comp.CooklangRenderable, comp.customUnits = SourcePosition{}, CooklangRenderable{}, nil
+		return comp
+	case *Timer:
+		comp.Position, comp.CooklangRenderable = SourcePosition{}, CooklangRenderable{}
+		return comp
+	case *Cookware:
+		comp.Position, comp.CooklangRenderable = SourcePosition{}, CooklangRenderable{}
+		return comp
+	case *Instruction:
+		comp.Position, comp.CooklangRenderable = SourcePosition{}, CooklangRenderable{}
+		return comp
+	case *Section:
+		comp.Position, comp.CooklangRenderable = SourcePosition{}, CooklangRenderable{}
+		return comp
+	case *Comment:
+		comp.Position, comp.CooklangRenderable = SourcePosition{}, CooklangRenderable{}
+		return comp
+	case *Note:
+		comp.Position, comp.CooklangRenderable = SourcePosition{}, CooklangRenderable{}
+		return comp
+	case *Temperature:
+		comp.Position, comp.CooklangRenderable = SourcePosition{}, CooklangRenderable{}
+		return comp
+	case *RecipeReference:
+		comp.Position, comp.CooklangRenderable = SourcePosition{}, CooklangRenderable{}
+		return comp
+	case *Extension:
+		comp.Position, comp.CooklangRenderable = SourcePosition{}, CooklangRenderable{}
+		return comp
+	}
+	return component
+}
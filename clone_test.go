@@ -0,0 +1,166 @@
+package cooklang
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestRecipeClone(t *testing.T) {
+	recipe, err := ParseString("---\ntitle: Pancakes\nservings: 2\nunits: {scoop: 30 g}\n---\nWhisk @flour{2%scoop} and @eggs{2} in a #bowl{}.\n\nFry for ~{2%minutes} at 180°C.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	recipe.FirstStep.Images = []string{"Pancakes.0.jpg"}
+
+	clone := recipe.Clone()
+	if !clone.Equal(recipe) {
+		t.Fatal("clone should equal the original")
+	}
+
+	// Modify every level of the clone
+	clone.Metadata["author"] = "Sam"
+	clone.Tags = append(clone.Tags, "sweet")
+	clone.FirstStep.Images[0] = "changed.jpg"
+	for step := clone.FirstStep; step != nil; step = step.NextStep {
+		for component := step.FirstComponent; component != nil; component = component.GetNext() {
+			if ingredient, ok := component.(*Ingredient); ok {
+				ingredient.Quantity = ingredient.Quantity.Scale(10)
+			}
+		}
+	}
+
+	if recipe.Metadata["author"] != "" || len(recipe.Tags) != 0 || recipe.FirstStep.Images[0] != "Pancakes.0.jpg" {
+		t.Error("modifying the clone's fields changed the original")
+	}
+	flour := recipe.FirstStep.FirstComponent.GetNext().(*Ingredient)
+	if flour.Quantity.Min() != 2 {
+		t.Errorf("modifying the clone's ingredients changed the original: %v", flour.Quantity)
+	}
+	if clone.Equal(recipe) {
+		t.Error("expected the modified clone to differ")
+	}
+
+	// The clone's ingredients use the clone's custom units
+	cloneFlour := clone.FirstStep.FirstComponent.GetNext().(*Ingredient)
+	clone.CustomUnits["scoop"] = CustomUnit{Amount: 50, Unit: "g"}
+	if converted, err := cloneFlour.ConvertTo("g"); err != nil || converted.Quantity.Min() != 1000 {
+		t.Errorf("expected 1000 g using the clone's scoop, got %v, %v", converted, err)
+	}
+
+	if (*Recipe)(nil).Clone() != nil {
+		t.Error("cloning nil should return nil")
+	}
+}
+
+func TestCloneRebindsRenderer(t *testing.T) {
+	recipe, err := ParseString("---\nservings: 2\n---\nAdd @flour{100%g}.")
+	if err != nil {
+		t.Fatalf("failed to parse recipe: %v", err)
+	}
+	recipe.SetRenderer(RendererFunc(func(r *Recipe) string {
+		ing := r.GetIngredients().Ingredients[0]
+		return fmt.Sprintf("%g %s %s", ing.Quantity.Min(), ing.Unit, ing.Name)
+	}))
+
+	if got := recipe.Scale(2).Render(); got != "200 g flour" {
+		t.Errorf("scaled recipe rendered %q, want %q", got, "200 g flour")
+	}
+	if got := recipe.ScaleToServings(1).Render(); got != "50 g flour" {
+		t.Errorf("recipe scaled to servings rendered %q, want %q", got, "50 g flour")
+	}
+	if got := recipe.Substitute("flour", "rye flour").Render(); got != "100 g rye flour" {
+		t.Errorf("substituted recipe rendered %q", got)
+	}
+	clone := recipe.Clone()
+	ingredients := clone.GetIngredients()
+	ingredients.Ingredients[0].Quantity = NewQuantity(5, 1)
+	if err := ingredients.ApplyTo(clone); err != nil {
+		t.Fatalf("ApplyTo failed: %v", err)
+	}
+	if got := clone.Render(); got != "5 g flour" {
+		t.Errorf("clone rendered %q, want %q", got, "5 g flour")
+	}
+	if got := recipe.Render(); got != "100 g flour" {
+		t.Errorf("original rendered %q, want %q", got, "100 g flour")
+	}
+	if !recipe.Equal(recipe.Clone()) {
+		t.Error("renderers should be ignored by Equal")
+	}
+
+	// RenderWith renders once, with a Renderer, and keeps the recipe's renderer
+	if got, err := recipe.RenderWith(RendererFunc(func(r *Recipe) string { return r.Title })); err != nil || got != "" {
+		t.Errorf("RenderWith returned %q, %v", got, err)
+	}
+	if got := recipe.Render(); got != "100 g flour" {
+		t.Errorf("RenderWith replaced the renderer: %q", got)
+	}
+
+	// A render function assigned directly cannot be re-bound, so copies drop it
+	recipe.RenderFunc = func() string { return "custom" }
+	recipe.renderer = nil
+	if got := recipe.Clone().RenderFunc; got != nil {
+		t.Error("expected the clone to drop a directly assigned RenderFunc")
+	}
+}
+
+func TestRecipeEqual(t *testing.T) {
+	a, _ := ParseString("Boil @water{1%L}.\n\nAdd @salt{}.")
+	b, _ := ParseString("\n\nBoil @water{1%L}.\n\nAdd @salt{}.")
+	if !a.Equal(b) {
+		t.Error("source positions should be ignored")
+	}
+
+	b.FirstStep.FirstComponent.GetNext().(*Ingredient).RenderFunc = func() string { return "water" }
+	if !a.Equal(b) {
+		t.Error("render functions should be ignored")
+	}
+
+	for name, modify := range map[string]func(*Recipe){
+		"title":     func(r *Recipe) { r.Title = "Soup" },
+		"date":      func(r *Recipe) { r.Date = time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC) },
+		"quantity":  func(r *Recipe) { r.FirstStep.FirstComponent.GetNext().(*Ingredient).Quantity = NewQuantity(2, 1) },
+		"last step": func(r *Recipe) { r.FirstStep.NextStep = nil },
+		"images":    func(r *Recipe) { r.FirstStep.Images = []string{"Soup.0.jpg"} },
+		"yield":     func(r *Recipe) { r.Yield = Yield{Quantity: 2, Unit: "loaves"} },
+	} {
+		c := a.Clone()
+		modify(c)
+		if a.Equal(c) || c.Equal(a) {
+			t.Errorf("changing the %s should make the recipes differ", name)
+		}
+	}
+
+	var nilRecipe *Recipe
+	if !nilRecipe.Equal(nil) || a.Equal(nil) || nilRecipe.Equal(a) {
+		t.Error("unexpected result comparing nil recipes")
+	}
+}
+
+func TestCloneKeepsYield(t *testing.T) {
+	recipe, _ := ParseString("---\nyield: 24 cookies\n---\nMix @flour{250%g}.")
+	clone := recipe.Clone()
+	if clone.Yield != recipe.Yield || clone.Yield.String() != "24 cookies" {
+		t.Errorf("clone yield = %v, want %v", clone.Yield, recipe.Yield)
+	}
+	if !recipe.Equal(clone) {
+		t.Error("expected the clone to equal the recipe")
+	}
+
+	clone.Yield.Quantity = 48
+	if recipe.Equal(clone) {
+		t.Error("expected recipes with different yields to differ")
+	}
+}
+
+func TestScaleDoesNotAlias(t *testing.T) {
+	recipe, _ := ParseString("Add @sugar{100%g}.")
+	scaled := recipe.Scale(2)
+	scaled.FirstStep.FirstComponent.GetNext().(*Ingredient).Name = "honey"
+	if name := recipe.FirstStep.FirstComponent.GetNext().(*Ingredient).Name; name != "sugar" {
+		t.Errorf("scaled recipe shares components with the original: %q", name)
+	}
+	if scaled.FirstStep.Position != recipe.FirstStep.Position {
+		t.Error("scaling should keep step positions")
+	}
+}
@@ -0,0 +1,445 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/hilli/cooklang"
+)
+
+// The JSON API served by 'cook serve' under /api. The types below are the
+// documented wire format; fields are only ever added, never renamed or removed.
+//
+//	GET  /api/recipes                              list recipes (?q=search query, ?tag=tag)
+//	GET  /api/recipes/{slug}                       one recipe (?scale=, ?servings=, ?transform=)
+//	GET  /api/recipes/{slug}/shopping-list         shopping list for one recipe (same parameters)
+//	POST /api/shopping-list                        shopping list for several recipes
+//
+// A slug is the recipe's path relative to the collection without ".cook",
+// e.g. "sauces/Hollandaise".
+
+// apiRecipeSummary is a recipe in the GET /api/recipes listing.
+type apiRecipeSummary struct {
+	Slug        string   `json:"slug"`
+	Title       string   `json:"title"`
+	Description string   `json:"description,omitempty"`
+	Cuisine     string   `json:"cuisine,omitempty"`
+	Tags        []string `json:"tags"`
+	Servings    float32  `json:"servings,omitempty"`
+	TotalTime   string   `json:"total_time,omitempty"`
+	Images      []string `json:"images"` // Image URLs
+	URL         string   `json:"url"`    // URL of the full recipe in the API
+}
+
+// apiRecipe is a full recipe, as returned by GET /api/recipes/{slug}.
+type apiRecipe struct {
+	Slug        string            `json:"slug"`
+	Title       string            `json:"title"`
+	Description string            `json:"description,omitempty"`
+	Cuisine     string            `json:"cuisine,omitempty"`
+	Difficulty  string            `json:"difficulty,omitempty"`
+	Author      string            `json:"author,omitempty"`
+	PrepTime    string            `json:"prep_time,omitempty"`
+	TotalTime   string            `json:"total_time,omitempty"`
+	Servings    float32           `json:"servings,omitempty"`
+	Tags        []string          `json:"tags"`
+	Images      []string          `json:"images"`   // Image URLs
+	Metadata    map[string]string `json:"metadata"` // All frontmatter values
+	Ingredients []apiIngredient   `json:"ingredients"`
+	Cookware    []string          `json:"cookware"`
+	Steps       []apiStep         `json:"steps"`
+	Notes       []string          `json:"notes"`
+}
+
+// apiStep is a numbered recipe step.
+type apiStep struct {
+	Number     int            `json:"number"` // 1-based, across the whole recipe
+	Section    string         `json:"section,omitempty"`
+	Text       string         `json:"text"` // Plain text of the step
+	Components []apiComponent `json:"components"`
+}
+
+// apiComponent is a part of a step. Type is one of "text", "ingredient",
+// "cookware", "timer" or "recipe"; the other fields are set as they apply.
+type apiComponent struct {
+	Type        string   `json:"type"`
+	Text        string   `json:"text"` // Display text (e.g., "200 g flour", "10 minutes")
+	Name        string   `json:"name,omitempty"`
+	Quantity    *float64 `json:"quantity,omitempty"`     // Omitted for unspecified amounts; lower bound for ranges
+	QuantityMax *float64 `json:"quantity_max,omitempty"` // Upper bound for ranges
+	Unit        string   `json:"unit,omitempty"`
+	Optional    bool     `json:"optional,omitempty"`
+	Reference   bool     `json:"reference,omitempty"` // Refers to an ingredient added in an earlier step
+	Fixed       bool     `json:"fixed,omitempty"`
+	Approximate bool     `json:"approximate,omitempty"`
+	Note        string   `json:"note,omitempty"`    // Annotation, e.g. "finely chopped"
+	Seconds     float64  `json:"seconds,omitempty"` // Timer duration (lower bound for ranges)
+	Path        string   `json:"path,omitempty"`    // Referenced recipe
+}
+
+// apiIngredient is an ingredient in a recipe's ingredient list or a shopping list.
+type apiIngredient struct {
+	Name        string   `json:"name"`
+	Quantity    *float64 `json:"quantity,omitempty"`     // Omitted for unspecified amounts; lower bound for ranges
+	QuantityMax *float64 `json:"quantity_max,omitempty"` // Upper bound for ranges
+	Unit        string   `json:"unit,omitempty"`
+	Display     string   `json:"display"` // Formatted amount and unit (e.g., "1/2 cup"); empty if unspecified
+	Optional    bool     `json:"optional,omitempty"`
+	Fixed       bool     `json:"fixed,omitempty"`
+	Approximate bool     `json:"approximate,omitempty"`
+}
+
+// apiShoppingList is a consolidated shopping list.
+type apiShoppingList struct {
+	Recipes []string          `json:"recipes"` // Titles of the included recipes
+	Items   []apiShoppingItem `json:"items"`   // Sorted by name
+}
+
+// apiShoppingItem is an ingredient in a shopping list, with where it comes from.
+type apiShoppingItem struct {
+	apiIngredient
+	Notes   []string `json:"notes,omitempty"`   // Annotations from the recipes
+	Recipes []string `json:"recipes,omitempty"` // Titles of the recipes that use it
+	Aisle   string   `json:"aisle"`
+}
+
+// apiShoppingListRequest is the body of POST /api/shopping-list.
+type apiShoppingListRequest struct {
+	Recipes []struct {
+		Slug     string  `json:"slug"`
+		Scale    float64 `json:"scale,omitempty"`
+		Servings float64 `json:"servings,omitempty"`
+	} `json:"recipes"`
+	Units string `json:"units,omitempty"` // metric, imperial or us
+}
+
+// registerAPI adds the JSON API routes.
+func (s *recipeServer) registerAPI() {
+	s.mux.HandleFunc("GET /api/recipes", s.handleAPIRecipes)
+	s.mux.HandleFunc("GET /api/recipes/{path...}", s.handleAPIRecipe)
+	s.mux.HandleFunc("POST /api/shopping-list", s.handleAPIShoppingList)
+}
+
+func (s *recipeServer) handleAPIRecipes(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	library, index := s.library, s.index
+	s.mu.RUnlock()
+
+	entries := library.Entries
+	query, tag := strings.TrimSpace(r.URL.Query().Get("q")), strings.TrimSpace(r.URL.Query().Get("tag"))
+	if query != "" || tag != "" {
+		q, err := index.ParseQuery(query)
+		if err != nil {
+			writeAPIError(w, http.StatusBadRequest, err)
+			return
+		}
+		if tag != "" {
+			q.Tag(tag)
+		}
+		entries = nil
+		for _, result := range q.Results() {
+			entries = append(entries, result.Entry)
+		}
+	}
+
+	summaries := []apiRecipeSummary{}
+	for _, entry := range entries {
+		recipe := entry.Recipe
+		slug := recipeSlug(entry.Path)
+		summaries = append(summaries, apiRecipeSummary{
+			Slug:        slug,
+			Title:       entry.Name(),
+			Description: recipe.Description,
+			Cuisine:     recipe.Cuisine,
+			Tags:        nonNil(recipe.Tags),
+			Servings:    recipe.Servings,
+			TotalTime:   recipe.TotalTime,
+			Images:      apiImages(entry),
+			URL:         "/api/recipes/" + escapePath(slug),
+		})
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"count": len(summaries), "recipes": summaries})
+}
+
+func (s *recipeServer) handleAPIRecipe(w http.ResponseWriter, r *http.Request) {
+	slug, shoppingList := strings.CutSuffix(r.PathValue("path"), "/shopping-list")
+	s.mu.RLock()
+	entry, ok := s.library.Get(slug)
+	s.mu.RUnlock()
+	if !ok || entry.Recipe == nil {
+		writeAPIError(w, http.StatusNotFound, fmt.Errorf("recipe %q not found", slug))
+		return
+	}
+
+	recipe, err := applyRecipeParams(entry.Recipe, r.URL.Query())
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err)
+		return
+	}
+	if !shoppingList {
+		writeJSON(w, http.StatusOK, newAPIRecipe(entry, recipe))
+		return
+	}
+
+	list, err := cooklang.CreateShoppingList(recipe)
+	if err != nil {
+		writeAPIError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, newAPIShoppingList(list))
+}
+
+func (s *recipeServer) handleAPIShoppingList(w http.ResponseWriter, r *http.Request) {
+	var request apiShoppingListRequest
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&request); err != nil {
+		writeAPIError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return
+	}
+	if len(request.Recipes) == 0 {
+		writeAPIError(w, http.StatusBadRequest, fmt.Errorf("no recipes given"))
+		return
+	}
+
+	s.mu.RLock()
+	library := s.library
+	s.mu.RUnlock()
+
+	var recipes []*cooklang.Recipe
+	for _, item := range request.Recipes {
+		entry, ok := library.Get(item.Slug)
+		if !ok || entry.Recipe == nil {
+			writeAPIError(w, http.StatusNotFound, fmt.Errorf("recipe %q not found", item.Slug))
+			return
+		}
+		params := url.Values{}
+		if item.Scale != 0 {
+			params.Set("scale", fmt.Sprint(item.Scale))
+		}
+		if item.Servings != 0 {
+			params.Set("servings", fmt.Sprint(item.Servings))
+		}
+		recipe, err := applyRecipeParams(entry.Recipe, params)
+		if err != nil {
+			writeAPIError(w, http.StatusBadRequest, fmt.Errorf("%s: %w", item.Slug, err))
+			return
+		}
+		recipes = append(recipes, recipe)
+	}
+
+	list, err := cooklang.CreateShoppingList(recipes...)
+	if err != nil {
+		writeAPIError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+	if request.Units != "" {
+		system, err := cooklang.ParseUnitSystem(request.Units)
+		if err != nil {
+			writeAPIError(w, http.StatusBadRequest, err)
+			return
+		}
+		list.Ingredients = list.Ingredients.ConvertToSystem(system)
+	}
+	writeJSON(w, http.StatusOK, newAPIShoppingList(list))
+}
+
+// applyRecipeParams applies the transform pipeline in the "transform" parameter
+// (e.g., "servings=4,units=metric"), followed by "servings" and "scale".
+func applyRecipeParams(recipe *cooklang.Recipe, params url.Values) (*cooklang.Recipe, error) {
+	pipeline, err := cooklang.ParseTransformPipeline(params.Get("transform"))
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range []string{"servings", "scale"} {
+		if value := params.Get(name); value != "" {
+			t, err := cooklang.NewTransform(name, value)
+			if err != nil {
+				return nil, err
+			}
+			pipeline = append(pipeline, t)
+		}
+	}
+	return pipeline.Apply(recipe)
+}
+
+// newAPIRecipe converts a (possibly transformed) recipe of a library entry to its API form.
+func newAPIRecipe(entry *cooklang.LibraryEntry, recipe *cooklang.Recipe) apiRecipe {
+	result := apiRecipe{
+		Slug:        recipeSlug(entry.Path),
+		Title:       entry.Name(),
+		Description: recipe.Description,
+		Cuisine:     recipe.Cuisine,
+		Difficulty:  recipe.Difficulty,
+		Author:      recipe.Author,
+		PrepTime:    recipe.PrepTime,
+		TotalTime:   recipe.TotalTime,
+		Servings:    recipe.Servings,
+		Tags:        nonNil(recipe.Tags),
+		Images:      apiImages(entry),
+		Metadata:    map[string]string{},
+		Ingredients: []apiIngredient{},
+		Cookware:    []string{},
+		Steps:       []apiStep{},
+		Notes:       []string{},
+	}
+	for key, value := range recipe.Metadata {
+		result.Metadata[key] = value
+	}
+	for _, ingredient := range recipe.GetIngredients().Ingredients {
+		result.Ingredients = append(result.Ingredients, newAPIIngredient(ingredient))
+	}
+	for _, cookware := range recipe.GetCookware() {
+		result.Cookware = append(result.Cookware, cookware.Name)
+	}
+
+	numbers := recipe.StepNumbers()
+	section := ""
+	for step := recipe.FirstStep; step != nil; step = step.NextStep {
+		apiStep := apiStep{Number: numbers[step], Components: []apiComponent{}}
+		var text strings.Builder
+		for component := step.FirstComponent; component != nil; component = component.GetNext() {
+			switch comp := component.(type) {
+			case *cooklang.Section:
+				section = comp.Name
+			case *cooklang.Note:
+				result.Notes = append(result.Notes, comp.Text)
+			case *cooklang.Instruction:
+				text.WriteString(comp.Text)
+				apiStep.Components = append(apiStep.Components, apiComponent{Type: "text", Text: comp.Text})
+			case *cooklang.Ingredient:
+				text.WriteString(comp.Name)
+				ingredient := newAPIIngredient(comp)
+				apiStep.Components = append(apiStep.Components, apiComponent{
+					Type: "ingredient", Text: comp.RenderDisplay(), Name: comp.Name,
+					Quantity: ingredient.Quantity, QuantityMax: ingredient.QuantityMax, Unit: comp.Unit,
+					Optional: comp.Optional, Reference: comp.Reference, Fixed: comp.Fixed, Approximate: comp.Approximate, Note: comp.Annotation,
+				})
+			case *cooklang.Cookware:
+				text.WriteString(comp.Name)
+				quantity := float64(comp.Quantity)
+				apiStep.Components = append(apiStep.Components, apiComponent{
+					Type: "cookware", Text: comp.RenderDisplay(), Name: comp.Name, Quantity: &quantity, Note: comp.Annotation,
+				})
+			case *cooklang.Timer:
+				display := comp.RenderDisplay()
+				text.WriteString(display)
+				timer := apiComponent{Type: "timer", Text: display, Name: comp.Name, Unit: comp.Unit, Note: comp.Annotation}
+				if d, err := comp.ParsedDuration(); err == nil {
+					timer.Seconds = d.Seconds()
+				}
+				apiStep.Components = append(apiStep.Components, timer)
+			case *cooklang.Temperature:
+				display := comp.RenderDisplay()
+				text.WriteString(display)
+				value := comp.Value
+				temperature := apiComponent{Type: "temperature", Text: display, Quantity: &value, Unit: "°" + string(comp.Scale)}
+				if comp.ValueMax > comp.Value {
+					valueMax := comp.ValueMax
+					temperature.QuantityMax = &valueMax
+				}
+				apiStep.Components = append(apiStep.Components, temperature)
+			case *cooklang.RecipeReference:
+				text.WriteString(comp.Path)
+				reference := apiComponent{Type: "recipe", Text: comp.Path, Path: comp.Path, Unit: comp.Unit}
+				if comp.Quantity > 0 {
+					quantity := float64(comp.Quantity)
+					reference.Quantity = &quantity
+				}
+				apiStep.Components = append(apiStep.Components, reference)
+			}
+		}
+		if apiStep.Number == 0 {
+			continue
+		}
+		apiStep.Section = section
+		apiStep.Text = strings.Join(strings.Fields(text.String()), " ")
+		result.Steps = append(result.Steps, apiStep)
+	}
+	return result
+}
+
+// newAPIIngredient converts an ingredient to its API form.
+func newAPIIngredient(ingredient *cooklang.Ingredient) apiIngredient {
+	result := apiIngredient{
+		Name:        ingredient.Name,
+		Unit:        ingredient.Unit,
+		Optional:    ingredient.Optional,
+		Fixed:       ingredient.Fixed,
+		Approximate: ingredient.Approximate,
+	}
+	if ingredient.Quantity.HasAmount() {
+		amount := ingredient.Quantity
+		low := amount.Min()
+		result.Quantity = &low
+		if amount.IsRange() {
+			high := amount.Max()
+			result.QuantityMax = &high
+		}
+		result.Display = strings.TrimSpace(amount.String() + " " + ingredient.Unit)
+		if ingredient.Approximate {
+			result.Display = cooklang.ApproximatePrefix + result.Display
+		}
+	}
+	return result
+}
+
+// newAPIShoppingList converts a shopping list to its API form, sorted by ingredient name.
+func newAPIShoppingList(list *cooklang.ShoppingList) apiShoppingList {
+	result := apiShoppingList{Recipes: nonNil(list.Recipes), Items: []apiShoppingItem{}}
+	for _, item := range list.Items() {
+		ingredient := &cooklang.Ingredient{Name: item.Name, Quantity: cooklang.SomeQuantity(), Unit: item.Unit, Approximate: item.Approximate}
+		if item.Quantity > 0 {
+			ingredient.Quantity = cooklang.QuantityFromFloat(float64(item.Quantity))
+			if item.QuantityMax > item.Quantity {
+				ingredient.Quantity = cooklang.NewRangeQuantity(ingredient.Quantity, cooklang.QuantityFromFloat(float64(item.QuantityMax)))
+			}
+		}
+		result.Items = append(result.Items, apiShoppingItem{
+			apiIngredient: newAPIIngredient(ingredient),
+			Notes:         item.Notes,
+			Recipes:       item.SourceRecipes,
+			Aisle:         item.Aisle,
+		})
+	}
+	return result
+}
+
+// apiImages returns the URLs of a recipe's images.
+func apiImages(entry *cooklang.LibraryEntry) []string {
+	images := []string{}
+	for _, img := range entry.Recipe.Images {
+		images = append(images, recipeImageURL(entry.Path, img))
+	}
+	return images
+}
+
+// recipeSlug returns the API identifier of a recipe: its path without ".cook".
+func recipeSlug(entryPath string) string {
+	return strings.TrimSuffix(entryPath, ".cook")
+}
+
+// nonNil returns the slice, or an empty slice instead of nil so it encodes as [].
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
+// writeJSON writes a JSON response.
+func writeJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	_ = encoder.Encode(data)
+}
+
+// writeAPIError writes an error as {"error": "..."}.
+func writeAPIError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
@@ -1,38 +1,155 @@
 package main
 
 import (
+	"fmt"
+	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 
 	"github.com/spf13/cobra"
 )
 
-// completeCookFiles provides shell completion for .cook files
-func completeCookFiles(_ *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-	// Look for .cook files matching the partial input
-	pattern := toComplete + "*.cook"
-	matches, err := filepath.Glob(pattern)
+var completionCmd = &cobra.Command{
+	Use:   "completion bash|zsh|fish|powershell",
+	Short: "Generate a shell completion script",
+	Long: `Generate a completion script for your shell. Commands, flags, recipe
+files (*.cook) and flag values such as --format and --unit are completed.
+
+Bash (requires the bash-completion package):
+  source <(cook completion bash)
+  # Permanently, on Linux:
+  cook completion bash > /etc/bash_completion.d/cook
+  # Permanently, on macOS with Homebrew:
+  cook completion bash > $(brew --prefix)/etc/bash_completion.d/cook
+
+Zsh:
+  # Enable completion once, if it is not already enabled:
+  echo "autoload -U compinit; compinit" >> ~/.zshrc
+  cook completion zsh > "${fpath[1]}/_cook"
+
+Fish:
+  cook completion fish > ~/.config/fish/completions/cook.fish
+
+PowerShell:
+  cook completion powershell | Out-String | Invoke-Expression
+
+Start a new shell for the completion to take effect.`,
+	Args:                  cobra.ExactArgs(1),
+	ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
+	DisableFlagsInUseLine: true,
+	RunE:                  runCompletion,
+}
+
+func init() {
+	rootCmd.AddCommand(completionCmd)
+}
+
+func runCompletion(cmd *cobra.Command, args []string) error {
+	switch args[0] {
+	case "bash":
+		return rootCmd.GenBashCompletionV2(os.Stdout, true)
+	case "zsh":
+		return rootCmd.GenZshCompletion(os.Stdout)
+	case "fish":
+		return rootCmd.GenFishCompletion(os.Stdout, true)
+	case "powershell":
+		return rootCmd.GenPowerShellCompletionWithDesc(os.Stdout)
+	}
+	return fmt.Errorf("unsupported shell %q (supported: bash, zsh, fish, powershell)", args[0])
+}
+
+// completeCookFiles provides shell completion for any number of .cook file arguments
+func completeCookFiles(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return cookFileCompletions(args, toComplete)
+}
+
+// completeCookFile provides shell completion for commands taking a single .cook file
+func completeCookFile(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return completeCookFilesUpTo(1)(cmd, args, toComplete)
+}
+
+// completeCookFilesUpTo provides shell completion for at most n .cook file arguments
+func completeCookFilesUpTo(n int) cobra.CompletionFunc {
+	return func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) >= n {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return cookFileCompletions(args, toComplete)
+	}
+}
+
+// completeCookFilesAfter provides shell completion for commands whose first n
+// arguments are values such as a metadata key, followed by .cook files
+func completeCookFilesAfter(n int) cobra.CompletionFunc {
+	return func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) < n {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return cookFileCompletions(args, toComplete)
+	}
+}
+
+// completeDirectory provides shell completion for a single directory argument
+func completeDirectory(_ *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return nil, cobra.ShellCompDirectiveFilterDirs
+}
+
+// completeMenuFile provides shell completion for a single .menu file argument
+func completeMenuFile(_ *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return []string{"menu"}, cobra.ShellCompDirectiveFilterFileExt
+}
+
+// cookFileCompletions lists the .cook files matching the partial input, skipping
+// files already on the command line, and the directories next to them that contain
+// recipes, so nested recipes can be completed one level at a time.
+func cookFileCompletions(args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	dir, _ := filepath.Split(toComplete)
+	entries, err := os.ReadDir(filepath.Join(".", dir))
 	if err != nil {
-		return nil, cobra.ShellCompDirectiveError
+		return nil, cobra.ShellCompDirectiveNoFileComp
 	}
 
-	// Also try directory completion
-	if toComplete != "" {
-		dirPattern := toComplete + "*"
-		dirMatches, _ := filepath.Glob(dirPattern)
-		for _, m := range dirMatches {
-			// Check if it's a directory
-			if info, err := filepath.Glob(m + "/*.cook"); err == nil && len(info) > 0 {
-				matches = append(matches, m+"/")
+	var files, dirs []string
+	for _, entry := range entries {
+		path := dir + entry.Name()
+		if strings.HasPrefix(entry.Name(), ".") || !strings.HasPrefix(path, toComplete) {
+			continue
+		}
+		if entry.IsDir() {
+			if recipes, _ := filepath.Glob(filepath.Join(path, "*.cook")); len(recipes) > 0 || hasSubdirectories(path) {
+				dirs = append(dirs, path+"/")
 			}
+		} else if strings.HasSuffix(entry.Name(), ".cook") && !slices.Contains(args, path) {
+			files = append(files, path)
 		}
 	}
 
-	// If no specific prefix, show all .cook files in current directory
-	if len(matches) == 0 && toComplete == "" {
-		matches, _ = filepath.Glob("*.cook")
+	if len(dirs) > 0 {
+		// Keep the cursor after a completed directory so its recipes can be completed next
+		return append(files, dirs...), cobra.ShellCompDirectiveNoSpace | cobra.ShellCompDirectiveNoFileComp
 	}
+	return files, cobra.ShellCompDirectiveNoFileComp
+}
 
-	return matches, cobra.ShellCompDirectiveNoSpace
+// hasSubdirectories reports whether dir contains visible subdirectories.
+func hasSubdirectories(dir string) bool {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return false
+	}
+	for _, entry := range entries {
+		if entry.IsDir() && !strings.HasPrefix(entry.Name(), ".") {
+			return true
+		}
+	}
+	return false
 }
 
 // completeUnitFlag provides shell completion for the --unit flag (unit systems only)
@@ -45,16 +162,47 @@ func completeUnitFlag(_ *cobra.Command, _ []string, _ string) ([]string, cobra.S
 	return units, cobra.ShellCompDirectiveNoFileComp
 }
 
-// completeFormatFlag provides shell completion for format flags
-func completeFormatFlag(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
-	formats := []string{
-		"cooklang\tOriginal Cooklang format",
-		"markdown\tMarkdown format",
-		"html\tHTML format",
-		"print\tPrint-optimized HTML",
-		"json\tJSON format",
+// completeProfileFlag offers the conversion profiles for --profile
+func completeProfileFlag(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+	profiles := []string{
+		"auto\tBartender for cocktail recipes, precise otherwise",
+		"precise\tExact conversions (29.57 ml per fl oz)",
+		"bartender\tBar measures: 30 ml per oz, dashes, barspoons",
+		"baking\tMasses in grams or ounces, spoons and cups in fractions",
+	}
+	return profiles, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeCountRoundingFlag offers the roundings for --round-counts
+func completeCountRoundingFlag(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+	roundings := []string{
+		"keep	Keep fractions: 1.5 eggs",
+		"up	Round up to whole items: 2 eggs",
+		"half	Round to the nearest half item: 1.5 lemons",
+	}
+	return roundings, cobra.ShellCompDirectiveNoFileComp
+}
+
+// formatDescriptions describes the output formats offered by completeFormats
+var formatDescriptions = map[string]string{
+	"cooklang": "Original Cooklang format",
+	"markdown": "Markdown format",
+	"html":     "HTML format",
+	"print":    "Print-optimized HTML",
+	"voice":    "JSON for voice assistants",
+	"ssml":     "SSML narration for text-to-speech",
+	"mermaid":  "Mermaid flowchart of the steps",
+	"docx":     "Word document",
+	"json":     "JSON format",
+}
+
+// completeFormats provides shell completion for a --format flag accepting the given formats
+func completeFormats(formats ...string) cobra.CompletionFunc {
+	completions := make([]string, len(formats))
+	for i, format := range formats {
+		completions[i] = format + "\t" + formatDescriptions[format]
 	}
-	return formats, cobra.ShellCompDirectiveNoFileComp
+	return cobra.FixedCompletions(completions, cobra.ShellCompDirectiveNoFileComp)
 }
 
 // completeServingsFlag provides shell completion for servings flags
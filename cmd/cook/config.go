@@ -0,0 +1,237 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/hilli/cooklang"
+	"github.com/hilli/cooklang/renderers"
+	"github.com/spf13/cobra"
+)
+
+var configPath string // --config flag
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Show or change cook's settings",
+	Long: `Show or change the settings in cook's config file,
+~/.config/cook/config.yaml (or $XDG_CONFIG_HOME/cook/config.yaml). Use the
+global --config flag or COOK_CONFIG to use another file.
+
+Settings:
+  units            Unit system for --unit: metric, imperial or us      (COOK_UNITS)
+  format           Default --format of cook render                      (COOK_FORMAT)
+  aisle            aisle.conf file for grouping cook shopping-list      (COOK_AISLE)
+  pantry           pantry.conf file for cook suggest                    (COOK_PANTRY)
+  preferred_units  YAML file of preferred units, like --preferred-units (COOK_PREFERRED_UNITS)
+  locale           Default --locale of cook render                      (COOK_LOCALE)
+  canonical        Parse in canonical mode, like --canonical: true/false (COOK_CANONICAL)
+
+Environment variables override the config file, and flags override both.
+
+Examples:
+  cook config set units metric
+  cook config set aisle ~/recipes/config/aisle.conf
+  cook config get units
+  cook config unset format
+  cook config list`,
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print a setting, including environment overrides",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		config, err := loadUserConfig()
+		if err != nil {
+			return err
+		}
+		value, err := config.Get(args[0])
+		if err != nil {
+			return err
+		}
+		fmt.Println(value)
+		return nil
+	},
+	ValidArgsFunction: completeConfigKey,
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Change a setting in the config file",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return updateConfigFile(args[0], args[1])
+	},
+	ValidArgsFunction: completeConfigKey,
+}
+
+var configUnsetCmd = &cobra.Command{
+	Use:   "unset <key>",
+	Short: "Remove a setting from the config file",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return updateConfigFile(args[0], "")
+	},
+	ValidArgsFunction: completeConfigKey,
+}
+
+var configListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Print all settings, including environment overrides",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		config, err := loadUserConfig()
+		if err != nil {
+			return err
+		}
+		for _, key := range cooklang.ConfigKeys {
+			value, _ := config.Get(key)
+			line := fmt.Sprintf("%s: %s", key, value)
+			if env := cooklang.ConfigEnvVar(key); os.Getenv(env) != "" {
+				line += fmt.Sprintf("  # from %s", env)
+			}
+			fmt.Println(strings.TrimRight(line, " "))
+		}
+		return nil
+	},
+}
+
+var configPathCmd = &cobra.Command{
+	Use:   "path",
+	Short: "Print the path of the config file",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Println(userConfigPath())
+	},
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&configPath, "config", "", "Config file (default: ~/.config/cook/config.yaml)")
+	configCmd.AddCommand(configGetCmd, configSetCmd, configUnsetCmd, configListCmd, configPathCmd)
+	rootCmd.AddCommand(configCmd)
+}
+
+// userConfigPath returns the config file to use: --config, COOK_CONFIG, or the
+// default path.
+func userConfigPath() string {
+	if configPath != "" {
+		return configPath
+	}
+	if path := os.Getenv("COOK_CONFIG"); path != "" {
+		return path
+	}
+	return cooklang.DefaultConfigPath()
+}
+
+// loadUserConfig reads the config file and applies the environment overrides.
+func loadUserConfig() (*cooklang.Config, error) {
+	config, err := cooklang.LoadConfig(userConfigPath())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	if err := config.ApplyEnv(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// updateConfigFile sets a key in the config file, without environment overrides.
+func updateConfigFile(key, value string) error {
+	path := userConfigPath()
+	config, err := cooklang.LoadConfig(path)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if err := config.Set(key, value); err != nil {
+		return err
+	}
+	if err := config.Save(path); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	if value == "" {
+		printSuccess("Removed %s from %s", key, path)
+	} else {
+		printSuccess("Set %s to %s in %s", key, value, path)
+	}
+	if env := cooklang.ConfigEnvVar(key); os.Getenv(env) != "" {
+		printWarning("%s is set and overrides the config file", env)
+	}
+	return nil
+}
+
+// applyUserConfig loads the config and uses its values as defaults for the flags
+// of cmd that were not given on the command line. The config commands themselves
+// skip this, so a broken config file can still be fixed.
+func applyUserConfig(cmd *cobra.Command, _ []string) error {
+	if cmd == configCmd || cmd.Parent() == configCmd {
+		return nil
+	}
+	config, err := loadUserConfig()
+	if err != nil {
+		return err
+	}
+
+	defaults := map[string]string{
+		"unit":            config.Units,
+		"locale":          config.Locale,
+		"aisle":           expandHome(config.Aisle),
+		"pantry":          expandHome(config.Pantry),
+		"preferred-units": expandHome(config.PreferredUnits),
+	}
+	if config.Canonical {
+		defaults["canonical"] = strconv.FormatBool(config.Canonical)
+	}
+	if cmd == renderCmd {
+		defaults["format"] = config.Format
+	}
+	for name, value := range defaults {
+		flag := cmd.Flags().Lookup(name)
+		if flag == nil || flag.Changed || value == "" {
+			continue
+		}
+		if err := flag.Value.Set(value); err != nil {
+			return fmt.Errorf("invalid config value for %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// expandHome replaces a leading "~/" in a path with the home directory.
+func expandHome(path string) string {
+	rest, ok := strings.CutPrefix(path, "~/")
+	if !ok {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, rest)
+}
+
+// completeConfigKey provides shell completion for config keys and their values
+func completeConfigKey(cmd *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 0 {
+		return cooklang.ConfigKeys, cobra.ShellCompDirectiveNoFileComp
+	}
+	if cmd.Name() != "set" || len(args) > 1 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	switch args[0] {
+	case "units":
+		return completeUnitFlag(cmd, args, "")
+	case "format":
+		return completeFormats("cooklang", "markdown", "html", "print", "voice", "ssml", "mermaid", "docx")(cmd, args, "")
+	case "locale":
+		return renderers.Locales(), cobra.ShellCompDirectiveNoFileComp
+	case "canonical":
+		return []string{"true", "false"}, cobra.ShellCompDirectiveNoFileComp
+	case "aisle", "pantry", "preferred_units":
+		return nil, cobra.ShellCompDirectiveDefault
+	}
+	return nil, cobra.ShellCompDirectiveNoFileComp
+}
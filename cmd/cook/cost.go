@@ -0,0 +1,137 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/hilli/cooklang"
+	"github.com/spf13/cobra"
+)
+
+var (
+	costPrices   string
+	costCurrency string
+	costJSON     bool
+)
+
+var costCmd = &cobra.Command{
+	Use:   "cost <recipe-file> [recipe-files...]",
+	Short: "Estimate what recipes cost from a price list",
+	Long: `Estimate what one or more recipes cost, ingredient by ingredient, from a
+price list, with the most expensive ingredients first. Ingredients of several recipes are added up as on a shopping list.
+
+The price list is YAML, with prices per unit or per item:
+
+  currency: EUR
+  prices:
+    flour: 1.20/kg
+    butter: 2.50/250 g
+    eggs: 0.30
+
+or CSV with the columns name, qty, unit, price, like a till receipt.
+Quantities are converted to the price's unit. Ingredients without a price,
+without an amount or in a unit that does not convert are listed as unpriced.
+
+Examples:
+  cook cost recipe.cook --prices prices.yaml
+  cook cost pancakes.cook waffles.cook --prices receipt.csv --currency USD
+  cook cost recipe.cook --prices prices.yaml --json`,
+	Args:              cobra.MinimumNArgs(1),
+	RunE:              runCost,
+	ValidArgsFunction: completeCookFiles,
+}
+
+func init() {
+	costCmd.Flags().StringVarP(&costPrices, "prices", "p", "", "Price list file, YAML or CSV (required)")
+	costCmd.Flags().StringVar(&costCurrency, "currency", "", "Currency of the prices, e.g. EUR (default: the price list's)")
+	costCmd.Flags().BoolVarP(&costJSON, "json", "j", false, "Output as JSON")
+	_ = costCmd.MarkFlagRequired("prices")
+	rootCmd.AddCommand(costCmd)
+
+	_ = costCmd.RegisterFlagCompletionFunc("prices", cobra.FixedCompletions([]string{"yaml", "yml", "csv"}, cobra.ShellCompDirectiveFilterFileExt))
+}
+
+func runCost(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+	prices, err := cooklang.LoadPriceList(costPrices)
+	if err != nil {
+		return fmt.Errorf("failed to load price list: %w", err)
+	}
+	if costCurrency != "" {
+		prices.Currency = costCurrency
+	}
+
+	// Recipes that fail are reported; the others are costed
+	recipes, _, readErr := readRecipeFiles(args)
+	if len(recipes) == 0 {
+		return readErr
+	}
+	list, err := cooklang.CreateShoppingList(recipes...)
+	if err != nil {
+		return fmt.Errorf("failed to combine ingredients: %w", err)
+	}
+	estimate := list.EstimateCost(prices)
+
+	if costJSON {
+		if err := outputJSON(estimate); err != nil {
+			return err
+		}
+		return readErr
+	}
+	printCostEstimate(estimate, list.Recipes)
+	return readErr
+}
+
+// printCostEstimate writes the estimate as a table of ingredients, amounts and costs,
+// the most expensive first
+func printCostEstimate(estimate *cooklang.CostEstimate, recipes []string) {
+	if len(recipes) > 0 {
+		fmt.Printf("Cost of %s\n\n", strings.Join(recipes, ", "))
+	}
+
+	items := slices.Clone(estimate.Items)
+	slices.SortFunc(items, func(a, b cooklang.CostItem) int {
+		return cmp.Or(cmp.Compare(b.Cost, a.Cost), strings.Compare(a.Ingredient.Name, b.Ingredient.Name))
+	})
+	rows := make([][3]string, 0, len(items)+1)
+	for _, item := range items {
+		amount := strings.TrimSpace(item.Ingredient.Quantity.String() + " " + item.Ingredient.Unit)
+		rows = append(rows, [3]string{item.Ingredient.Name, amount, estimate.Format(item.Cost)})
+	}
+	rows = append(rows, [3]string{"Total", "", estimate.Format(estimate.Total)})
+
+	var widths [3]int
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], utf8.RuneCountInString(cell))
+		}
+	}
+	for i, row := range rows {
+		if i == len(rows)-1 {
+			fmt.Printf("  %s\n", strings.Repeat("─", widths[0]+widths[1]+widths[2]+4))
+		}
+		fmt.Printf("  %s  %s  %s\n", padRight(row[0], widths[0]), padRight(row[1], widths[1]), padLeft(row[2], widths[2]))
+	}
+
+	if len(estimate.Unpriced) > 0 {
+		names := make([]string, 0, len(estimate.Unpriced))
+		for _, ingredient := range estimate.Unpriced {
+			names = append(names, ingredient.Name)
+		}
+		fmt.Println()
+		printWarning("Not priced: %s", strings.Join(names, ", "))
+	}
+}
+
+// padRight fills s with spaces on the right to width runes
+func padRight(s string, width int) string {
+	return s + strings.Repeat(" ", max(0, width-utf8.RuneCountInString(s)))
+}
+
+// padLeft fills s with spaces on the left to width runes
+func padLeft(s string, width int) string {
+	return strings.Repeat(" ", max(0, width-utf8.RuneCountInString(s))) + s
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hilli/cooklang"
+	"github.com/spf13/cobra"
+)
+
+var (
+	dedupeReport         bool
+	dedupeThreshold      float64
+	dedupeMinIngredients int
+	dedupeJSON           bool
+)
+
+var dedupeCmd = &cobra.Command{
+	Use:   "dedupe [directory]",
+	Short: "Find duplicate recipes in a collection",
+	Long: `Find recipes that look like the same recipe in a directory (default: current
+directory), such as recipes imported twice under different names. Two recipes
+are duplicates when their ingredient sets are at least --threshold alike
+(shared ingredients divided by all ingredients of both), or when their steps
+read the same ignoring case, punctuation and quantities.
+
+Nothing is changed; use --report to see how each pair differs.
+
+Examples:
+  cook dedupe ./recipes
+  cook dedupe ./recipes --report
+  cook dedupe ./recipes --threshold 0.9 --json`,
+	Args:              cobra.MaximumNArgs(1),
+	RunE:              runDedupe,
+	ValidArgsFunction: completeDirectory,
+}
+
+func init() {
+	defaults := cooklang.DefaultDuplicateOptions()
+	dedupeCmd.Flags().BoolVar(&dedupeReport, "report", false, "Show titles and the ingredients only one recipe of each pair uses")
+	dedupeCmd.Flags().Float64Var(&dedupeThreshold, "threshold", defaults.IngredientSimilarity, "Ingredient similarity from which recipes are duplicates, 0 to 1")
+	dedupeCmd.Flags().IntVar(&dedupeMinIngredients, "min-ingredients", defaults.MinIngredients, "Ingredients recipes need for their ingredients to be compared")
+	dedupeCmd.Flags().BoolVarP(&dedupeJSON, "json", "j", false, "Output as JSON")
+	rootCmd.AddCommand(dedupeCmd)
+}
+
+// dedupeItem is a pair of duplicates in the JSON output
+type dedupeItem struct {
+	A                    string   `json:"a"`
+	B                    string   `json:"b"`
+	IngredientSimilarity float64  `json:"ingredient_similarity"`
+	SameSteps            bool     `json:"same_steps"`
+	OnlyInA              []string `json:"only_in_a,omitempty"`
+	OnlyInB              []string `json:"only_in_b,omitempty"`
+}
+
+func runDedupe(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+	if dedupeThreshold <= 0 || dedupeThreshold > 1 {
+		return fmt.Errorf("--threshold must be above 0 and at most 1, got %g", dedupeThreshold)
+	}
+	dir := "."
+	if len(args) == 1 {
+		dir = args[0]
+	}
+
+	library, err := cooklang.LoadLibrary(dir)
+	if err != nil {
+		if library.Len() == 0 {
+			return fmt.Errorf("failed to load recipes from %s: %w", dir, err)
+		}
+		printWarning("Some recipes could not be loaded: %v", err)
+	}
+	printVerbose("Loaded %d recipes from %s", library.Len(), dir)
+
+	duplicates := library.FindDuplicatesWithOptions(cooklang.DuplicateOptions{
+		IngredientSimilarity: dedupeThreshold,
+		MinIngredients:       dedupeMinIngredients,
+	})
+
+	if dedupeJSON {
+		items := make([]dedupeItem, 0, len(duplicates))
+		for _, d := range duplicates {
+			onlyA, onlyB := ingredientDifference(d.A.Recipe, d.B.Recipe)
+			items = append(items, dedupeItem{
+				A:                    d.A.Path,
+				B:                    d.B.Path,
+				IngredientSimilarity: d.IngredientSimilarity,
+				SameSteps:            d.SameSteps,
+				OnlyInA:              onlyA,
+				OnlyInB:              onlyB,
+			})
+		}
+		return outputJSON(items)
+	}
+
+	for _, d := range duplicates {
+		reason := fmt.Sprintf("%.0f%% same ingredients", d.IngredientSimilarity*100)
+		if d.SameSteps {
+			reason += ", same steps"
+		}
+		fmt.Printf("%s ~ %s (%s)\n", d.A.Path, d.B.Path, reason)
+		if !dedupeReport {
+			continue
+		}
+		fmt.Printf("  %s: %s\n", d.A.Path, d.A.Name())
+		fmt.Printf("  %s: %s\n", d.B.Path, d.B.Name())
+		onlyA, onlyB := ingredientDifference(d.A.Recipe, d.B.Recipe)
+		if len(onlyA) > 0 {
+			fmt.Printf("  Only in %s: %s\n", d.A.Path, strings.Join(onlyA, ", "))
+		}
+		if len(onlyB) > 0 {
+			fmt.Printf("  Only in %s: %s\n", d.B.Path, strings.Join(onlyB, ", "))
+		}
+		fmt.Println()
+	}
+	if len(duplicates) == 0 {
+		printSuccess("No duplicates in %d recipes", library.Len())
+	} else {
+		printInfo("%d pairs of duplicates in %d recipes", len(duplicates), library.Len())
+	}
+	return nil
+}
+
+// ingredientDifference returns the lowercased ingredient names only a uses and only
+// b uses, sorted
+func ingredientDifference(a, b *cooklang.Recipe) ([]string, []string) {
+	namesA, namesB := ingredientNames(a), ingredientNames(b)
+	var onlyA, onlyB []string
+	for name := range namesA {
+		if !namesB[name] {
+			onlyA = append(onlyA, name)
+		}
+	}
+	for name := range namesB {
+		if !namesA[name] {
+			onlyB = append(onlyB, name)
+		}
+	}
+	sort.Strings(onlyA)
+	sort.Strings(onlyB)
+	return onlyA, onlyB
+}
+
+// ingredientNames returns the set of a recipe's lowercased ingredient names
+func ingredientNames(recipe *cooklang.Recipe) map[string]bool {
+	names := map[string]bool{}
+	for _, ingredient := range recipe.GetIngredients().Ingredients {
+		names[strings.ToLower(strings.TrimSpace(ingredient.Name))] = true
+	}
+	return names
+}
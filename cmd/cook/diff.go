@@ -0,0 +1,131 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hilli/cooklang"
+	"github.com/spf13/cobra"
+)
+
+var (
+	diffJSON     bool
+	diffExitCode bool
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff <old-recipe> <new-recipe>",
+	Short: "Show semantic differences between two recipes",
+	Long: `Compare two versions of a recipe and show what changed.
+
+Unlike a text diff, the output is semantic:
+  • Ingredients that were added, removed, or changed (with quantity deltas)
+  • Steps that were added, removed, or rewritten
+  • Metadata keys that were added, removed, or changed
+
+With --exit-code the command exits with status 1 when the recipes differ and 0
+when they match, like diff(1), so scripts can test the result.
+
+Examples:
+  cook diff old.cook new.cook
+  cook diff old.cook new.cook --json
+  cook diff old.cook new.cook --exit-code > /dev/null`,
+	Args:              cobra.ExactArgs(2),
+	RunE:              runDiff,
+	ValidArgsFunction: completeCookFilesUpTo(2),
+}
+
+func init() {
+	diffCmd.Flags().BoolVarP(&diffJSON, "json", "j", false, "Output as JSON")
+	diffCmd.Flags().BoolVar(&diffExitCode, "exit-code", false, "Exit with status 1 when the recipes differ")
+	rootCmd.AddCommand(diffCmd)
+}
+
+func runDiff(cmd *cobra.Command, args []string) error {
+	recipes, err := readMultipleRecipes(args)
+	if err != nil {
+		return err
+	}
+
+	diff := cooklang.Diff(recipes[0], recipes[1])
+	if err := printDiff(diff, args); err != nil {
+		return err
+	}
+	if diffExitCode && diff.HasChanges() {
+		cmd.SilenceUsage = true // Differences are not usage errors
+		return errExitStatus
+	}
+	return nil
+}
+
+// printDiff writes the diff to stdout as JSON or as text
+func printDiff(diff *cooklang.RecipeDiff, args []string) error {
+	if diffJSON {
+		return outputJSON(diff)
+	}
+
+	if !diff.HasChanges() {
+		fmt.Printf("No differences between %s and %s\n", args[0], args[1])
+		return nil
+	}
+
+	if len(diff.Ingredients) > 0 {
+		fmt.Println("Ingredients:")
+		for _, change := range diff.Ingredients {
+			switch change.Type {
+			case cooklang.ChangeAdded:
+				fmt.Printf("  + %s\n", change.New.RenderDisplay())
+			case cooklang.ChangeRemoved:
+				fmt.Printf("  - %s\n", change.Old.RenderDisplay())
+			case cooklang.ChangeModified:
+				line := fmt.Sprintf("  ~ %s: %s → %s", change.Name, formatDiffQuantity(change.Old), formatDiffQuantity(change.New))
+				if change.HasDelta {
+					line += fmt.Sprintf(" (%+.3g %s)", change.QuantityDelta, change.New.Unit)
+				}
+				fmt.Println(line)
+			}
+		}
+		fmt.Println()
+	}
+
+	if len(diff.Steps) > 0 {
+		fmt.Println("Steps:")
+		for _, change := range diff.Steps {
+			switch change.Type {
+			case cooklang.ChangeAdded:
+				fmt.Printf("  + Step %d: %s\n", change.NewIndex, change.New)
+			case cooklang.ChangeRemoved:
+				fmt.Printf("  - Step %d: %s\n", change.OldIndex, change.Old)
+			case cooklang.ChangeModified:
+				fmt.Printf("  ~ Step %d:\n", change.NewIndex)
+				fmt.Printf("      - %s\n", change.Old)
+				fmt.Printf("      + %s\n", change.New)
+			}
+		}
+		fmt.Println()
+	}
+
+	if len(diff.Metadata) > 0 {
+		fmt.Println("Metadata:")
+		for _, change := range diff.Metadata {
+			switch change.Type {
+			case cooklang.ChangeAdded:
+				fmt.Printf("  + %s: %s\n", change.Key, change.New)
+			case cooklang.ChangeRemoved:
+				fmt.Printf("  - %s: %s\n", change.Key, change.Old)
+			case cooklang.ChangeModified:
+				fmt.Printf("  ~ %s: %s → %s\n", change.Key, change.Old, change.New)
+			}
+		}
+	}
+
+	return nil
+}
+
+// formatDiffQuantity formats an ingredient quantity and unit for diff output
+func formatDiffQuantity(ing *cooklang.Ingredient) string {
+	if !ing.Quantity.HasAmount() {
+		return "some"
+	}
+	return strings.TrimSpace(ing.Quantity.Format(cooklang.QuantityStyleFraction) + " " + ing.Unit)
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hilli/cooklang/renderers"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportSiteOutput  string
+	exportSiteFlavor  string
+	exportSiteLayout  string
+	exportSiteBaseURL string
+	exportSiteLocale  string
+)
+
+var exportSiteCmd = &cobra.Command{
+	Use:   "export-site <directory>",
+	Short: "Export a recipe collection as Markdown pages for Hugo or Jekyll",
+	Long: `Convert every .cook file in a directory and its subdirectories into a
+Markdown page for a static site generator, keeping the folder structure.
+
+Each page starts with YAML frontmatter carrying the recipe's metadata, plus
+generated fields: the ingredient and cookware lists, ISO 8601 durations
+(prep_time_iso, ...) and the Schema.org JSON-LD under "jsonld", for a
+partial to put in the page's <head>. Ingredients in the body are written
+as "ingredient" shortcodes (Hugo) or includes (Jekyll) with name, quantity,
+unit, note and optional parameters, so the site's theme decides how they
+look.
+
+Examples:
+  cook export-site ./recipes --out content/recipes
+  cook export-site ./recipes --out _recipes --flavor jekyll --layout recipe
+  cook export-site ./recipes --out content/recipes --base-url https://example.com/recipes`,
+	Args:              cobra.ExactArgs(1),
+	RunE:              runExportSite,
+	ValidArgsFunction: completeDirectory,
+}
+
+func init() {
+	exportSiteCmd.Flags().StringVarP(&exportSiteOutput, "out", "o", "", "Output directory for the Markdown pages (required)")
+	exportSiteCmd.Flags().StringVar(&exportSiteFlavor, "flavor", "hugo", "Site generator to write for: hugo or jekyll")
+	exportSiteCmd.Flags().StringVar(&exportSiteLayout, "layout", "", "Frontmatter layout for every page, e.g. recipe")
+	exportSiteCmd.Flags().StringVar(&exportSiteBaseURL, "base-url", "", "URL of the exported directory, for the JSON-LD page URLs")
+	exportSiteCmd.Flags().StringVarP(&exportSiteLocale, "locale", "l", "", "Language of headings and labels (e.g., de, fr); default English")
+	_ = exportSiteCmd.MarkFlagRequired("out")
+	rootCmd.AddCommand(exportSiteCmd)
+
+	_ = exportSiteCmd.RegisterFlagCompletionFunc("flavor", cobra.FixedCompletions([]string{"hugo", "jekyll"}, cobra.ShellCompDirectiveNoFileComp))
+	_ = exportSiteCmd.RegisterFlagCompletionFunc("locale", cobra.FixedCompletions(renderers.Locales(), cobra.ShellCompDirectiveNoFileComp))
+}
+
+func runExportSite(cmd *cobra.Command, args []string) error {
+	source := args[0]
+	info, err := os.Stat(source)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", source, err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", source)
+	}
+	flavor := renderers.SiteFlavor(strings.ToLower(exportSiteFlavor))
+	if flavor != renderers.SiteHugo && flavor != renderers.SiteJekyll {
+		return fmt.Errorf("unknown site flavor: %s (use hugo or jekyll)", exportSiteFlavor)
+	}
+	cmd.SilenceUsage = true
+
+	files, err := collectCookFiles([]string{source})
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no .cook files found in %s", source)
+	}
+
+	var failed int
+	for _, filename := range files {
+		if err := exportSitePage(source, filename, flavor); err != nil {
+			printWarning("%s: %v", filename, err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d recipes could not be exported", failed, len(files))
+	}
+	printSuccess("Exported %d recipes to %s", len(files), exportSiteOutput)
+	return nil
+}
+
+// exportSitePage renders one recipe of the source directory as a site page in the
+// --out directory.
+func exportSitePage(source, filename string, flavor renderers.SiteFlavor) error {
+	recipe, err := readRecipeFile(filename)
+	if err != nil {
+		return err
+	}
+	rel, err := filepath.Rel(source, filename)
+	if err != nil {
+		return err
+	}
+	page := strings.TrimSuffix(filepath.ToSlash(rel), ".cook")
+
+	renderer := renderers.SiteMarkdownRenderer{Flavor: flavor, Layout: exportSiteLayout}
+	if exportSiteBaseURL != "" {
+		renderer.Page = &renderers.JSONLDOptions{URL: strings.TrimSuffix(exportSiteBaseURL, "/") + "/" + page + "/"}
+	}
+	rendered, err := renderer.Render(recipe, renderers.RendererOptions{Locale: exportSiteLocale})
+	if err != nil {
+		return err
+	}
+
+	output := filepath.Join(exportSiteOutput, filepath.FromSlash(page)+".md")
+	if err := os.MkdirAll(filepath.Dir(output), 0o755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	if err := os.WriteFile(output, []byte(rendered), 0o644); err != nil {
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+	printVerbose("Exported %s to %s", filename, output)
+	return nil
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"github.com/hilli/cooklang"
+	"github.com/spf13/cobra"
+)
+
+var (
+	fmtWrite      bool
+	fmtList       bool
+	fmtQuantities string
+)
+
+var fmtCmd = &cobra.Command{
+	Use:   "fmt [file|dir...]",
+	Short: "Format recipes in a consistent style",
+	Long: `Format recipes in a consistent style: frontmatter keys in a fixed order,
+one step per paragraph, normalized whitespace and quantities.
+
+Formatting does not change what a recipe means. Directories are searched
+recursively for .cook files; without arguments the current directory is used.
+By default the formatted recipes are printed; use -w to rewrite the files.
+
+Examples:
+  cook fmt Pancakes.cook
+  cook fmt -w ./recipes
+  cook fmt -l ./recipes
+  cook fmt -w --quantities fraction Pancakes.cook`,
+	RunE:              runFmt,
+	ValidArgsFunction: completeCookFiles,
+}
+
+func init() {
+	fmtCmd.Flags().BoolVarP(&fmtWrite, "write", "w", false, "Write the result to the files instead of stdout")
+	fmtCmd.Flags().BoolVarP(&fmtList, "list", "l", false, "List files whose formatting differs")
+	fmtCmd.Flags().StringVar(&fmtQuantities, "quantities", "auto", "Quantity style: auto, decimal, or fraction")
+	rootCmd.AddCommand(fmtCmd)
+
+	_ = fmtCmd.RegisterFlagCompletionFunc("quantities", cobra.FixedCompletions([]string{"auto", "decimal", "fraction"}, cobra.ShellCompDirectiveNoFileComp))
+}
+
+func runFmt(cmd *cobra.Command, args []string) error {
+	var opts cooklang.FormatOptions
+	switch fmtQuantities {
+	case "auto":
+		opts.QuantityStyle = cooklang.QuantityStyleAuto
+	case "decimal":
+		opts.QuantityStyle = cooklang.QuantityStyleDecimal
+	case "fraction":
+		opts.QuantityStyle = cooklang.QuantityStyleFraction
+	default:
+		return fmt.Errorf("unknown quantity style: %s (use auto, decimal, or fraction)", fmtQuantities)
+	}
+
+	if len(args) == 0 {
+		args = []string{"."}
+	}
+	files, err := collectCookFiles(args)
+	if err != nil {
+		return err
+	}
+
+	changed := 0
+	err = forEachFile(files, func(file string) error {
+		src, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("failed to read file: %w", err)
+		}
+		formatted, err := cooklang.Format(src, opts)
+		if err != nil {
+			return fmt.Errorf("failed to format: %w", err)
+		}
+
+		if bytes.Equal(src, formatted) {
+			printVerbose("%s is already formatted", file)
+		} else {
+			changed++
+			if fmtList {
+				fmt.Println(file)
+			}
+			if fmtWrite {
+				info, err := os.Stat(file)
+				if err != nil {
+					return err
+				}
+				if err := os.WriteFile(file, formatted, info.Mode().Perm()); err != nil {
+					return fmt.Errorf("failed to write file: %w", err)
+				}
+				printVerbose("Formatted %s", file)
+			}
+		}
+		if !fmtWrite && !fmtList {
+			fmt.Print(string(formatted))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if fmtWrite {
+		printSuccess("Formatted %d of %d files", changed, len(files))
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var hashCmd = &cobra.Command{
+	Use:   "hash <recipe-file> [recipe-files...]",
+	Short: "Print a content hash of recipes",
+	Long: `Print a stable content hash of each recipe, for build scripts that cache
+rendered output and rebuild it only when a recipe changes.
+
+The hash covers the recipe's metadata, steps and components. Reformatting a
+recipe does not change it, and neither does bookkeeping metadata such as
+last_cooked, times_cooked or updated. With one file only the hash is printed;
+with several, each line is the hash and the file name, like sha256sum.
+
+Examples:
+  cook hash pancakes.cook
+  cook hash *.cook
+  [ "$(cook hash pancakes.cook)" = "$(cat .cache/pancakes.hash)" ] || cook render pancakes.cook`,
+	Args:              cobra.MinimumNArgs(1),
+	RunE:              runHash,
+	ValidArgsFunction: completeCookFiles,
+}
+
+func init() {
+	rootCmd.AddCommand(hashCmd)
+}
+
+func runHash(_ *cobra.Command, args []string) error {
+	return forEachFile(args, func(filename string) error {
+		recipe, err := readRecipeFile(filename)
+		if err != nil {
+			return err
+		}
+		if len(args) == 1 {
+			fmt.Println(recipe.Hash())
+		} else {
+			fmt.Printf("%s  %s\n", recipe.Hash(), filename)
+		}
+		return nil
+	})
+}
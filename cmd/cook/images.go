@@ -0,0 +1,184 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/hilli/cooklang"
+	"github.com/spf13/cobra"
+)
+
+var (
+	imagesMaxSize       int
+	imagesMaxKB         int64
+	imagesDryRun        bool
+	imagesDeleteOrphans bool
+	imagesYes           bool
+	imagesForce         bool
+	imagesJSON          bool
+)
+
+var imagesCmd = &cobra.Command{
+	Use:   "images",
+	Short: "Manage the images of a recipe collection",
+	Long: `Manage the images of a recipe collection.
+
+Images belong to a recipe when they follow the auto-detection naming
+convention (Recipe.jpg, Recipe-1.png, ...) or are listed in the recipe's
+images frontmatter.`,
+}
+
+var imagesOptimizeCmd = &cobra.Command{
+	Use:   "optimize [directory]",
+	Short: "Generate web-sized images and find oversized and orphaned ones",
+	Long: `Scan a recipe collection (default: current directory) for images and:
+
+  • generate missing web-sized variants (Recipe.web.jpg) of large JPEG and
+    PNG images, fitting their longest edge within --max-size pixels
+  • report originals larger than --max-kb on disk
+  • list orphaned images that no recipe links to
+
+Orphans are only deleted with --delete-orphans, and only when --yes is
+also given; without --yes the files that would be deleted are listed.
+If any recipe fails to parse, its images look orphaned, so deletion is
+refused unless --force is also given.
+
+Examples:
+  cook images optimize ~/recipes
+  cook images optimize --dry-run --max-size 1200
+  cook images optimize ~/recipes --delete-orphans
+  cook images optimize ~/recipes --delete-orphans --yes
+  cook images optimize ~/recipes --json`,
+	Args:              cobra.MaximumNArgs(1),
+	RunE:              runImagesOptimize,
+	ValidArgsFunction: completeDirectory,
+}
+
+func init() {
+	imagesOptimizeCmd.Flags().IntVar(&imagesMaxSize, "max-size", cooklang.DefaultWebImageSize, "Longest edge in pixels of web-sized variants")
+	imagesOptimizeCmd.Flags().Int64Var(&imagesMaxKB, "max-kb", 1024, "Report originals larger than this many kilobytes")
+	imagesOptimizeCmd.Flags().BoolVarP(&imagesDryRun, "dry-run", "n", false, "Report only; do not write variants or delete files")
+	imagesOptimizeCmd.Flags().BoolVar(&imagesDeleteOrphans, "delete-orphans", false, "Delete orphaned images (requires --yes)")
+	imagesOptimizeCmd.Flags().BoolVarP(&imagesYes, "yes", "y", false, "Confirm deletion of orphaned images")
+	imagesOptimizeCmd.Flags().BoolVar(&imagesForce, "force", false, "Delete orphaned images even if some recipes could not be loaded")
+	imagesOptimizeCmd.Flags().BoolVarP(&imagesJSON, "json", "j", false, "Output the report as JSON")
+	imagesCmd.AddCommand(imagesOptimizeCmd)
+	rootCmd.AddCommand(imagesCmd)
+}
+
+// imagesReport is the result of cook images optimize.
+type imagesReport struct {
+	Generated []string                    `json:"generated"`
+	Failed    map[string]string           `json:"failed,omitempty"`
+	Oversized []*cooklang.CollectionImage `json:"oversized"`
+	Orphans   []*cooklang.CollectionImage `json:"orphans"`
+	Deleted   []string                    `json:"deleted"`
+}
+
+func runImagesOptimize(cmd *cobra.Command, args []string) error {
+	dir := "."
+	if len(args) == 1 {
+		dir = args[0]
+	}
+	if imagesMaxSize <= 0 {
+		return fmt.Errorf("--max-size must be positive, got %d", imagesMaxSize)
+	}
+
+	library, err := cooklang.LoadLibrary(dir)
+	if err != nil {
+		printWarning("Some recipes could not be loaded: %v", err)
+		// The images of recipes that failed to parse would look orphaned
+		if imagesDeleteOrphans && imagesYes && !imagesDryRun && !imagesForce {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("refusing to delete orphaned images because some recipes could not be loaded; fix them or re-run with --force")
+		}
+	}
+	audit, err := cooklang.AuditImages(library)
+	if err != nil {
+		return err
+	}
+	printVerbose("Found %d linked and %d orphaned images in %d recipes", len(audit.Images), len(audit.Orphans), library.Len())
+
+	report := imagesReport{
+		Generated: []string{},
+		Oversized: audit.Oversized(imagesMaxKB * 1024),
+		Orphans:   audit.Orphans,
+		Deleted:   []string{},
+	}
+
+	for _, img := range audit.MissingVariants(imagesMaxSize) {
+		variant := cooklang.WebVariantPath(img.Path)
+		if imagesDryRun {
+			printInfo("Would generate %s", variant)
+			continue
+		}
+		if _, err := cooklang.WriteWebVariant(filepath.Join(dir, filepath.FromSlash(img.Path)), imagesMaxSize); err != nil {
+			if report.Failed == nil {
+				report.Failed = make(map[string]string)
+			}
+			report.Failed[img.Path] = err.Error()
+			printWarning("%v", err)
+			continue
+		}
+		report.Generated = append(report.Generated, variant)
+	}
+
+	if imagesDeleteOrphans && len(audit.Orphans) > 0 {
+		switch {
+		case imagesDryRun:
+			printInfo("Dry run: not deleting %d orphaned images", len(audit.Orphans))
+		case !imagesYes:
+			printWarning("Not deleting %d orphaned images; re-run with --yes to confirm", len(audit.Orphans))
+		default:
+			for _, orphan := range audit.Orphans {
+				if err := os.Remove(filepath.Join(dir, filepath.FromSlash(orphan.Path))); err != nil {
+					printWarning("Failed to delete %s: %v", orphan.Path, err)
+					continue
+				}
+				report.Deleted = append(report.Deleted, orphan.Path)
+			}
+		}
+	}
+
+	if imagesJSON {
+		return outputJSON(report)
+	}
+
+	if len(report.Generated) > 0 {
+		fmt.Println("Generated web-sized variants:")
+		for _, variant := range report.Generated {
+			fmt.Printf("  %s\n", variant)
+		}
+	}
+	if len(report.Oversized) > 0 {
+		fmt.Printf("Oversized originals (over %d KB):\n", imagesMaxKB)
+		for _, img := range report.Oversized {
+			fmt.Printf("  %s (%s)\n", img.Path, formatBytes(img.Size))
+		}
+	}
+	if len(report.Orphans) > 0 {
+		fmt.Println("Orphaned images:")
+		for _, img := range report.Orphans {
+			fmt.Printf("  %s (%s)\n", img.Path, formatBytes(img.Size))
+		}
+	}
+	if len(report.Deleted) > 0 {
+		printSuccess("Deleted %d orphaned images", len(report.Deleted))
+	}
+	printInfo("%d images, %d variants generated, %d oversized, %d orphaned",
+		len(audit.Images), len(report.Generated), len(report.Oversized), len(report.Orphans))
+	return nil
+}
+
+// formatBytes formats a file size for display (e.g., "1.5 MB").
+func formatBytes(size int64) string {
+	switch {
+	case size >= 1024*1024:
+		return fmt.Sprintf("%.1f MB", float64(size)/(1024*1024))
+	case size >= 1024:
+		return fmt.Sprintf("%.1f KB", float64(size)/1024)
+	default:
+		return fmt.Sprintf("%d B", size)
+	}
+}
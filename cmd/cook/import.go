@@ -0,0 +1,229 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/hilli/cooklang"
+	"github.com/hilli/cooklang/importers"
+	"github.com/spf13/cobra"
+)
+
+// maxImportSize limits how much of a page 'cook import' downloads.
+const maxImportSize = 10 << 20
+
+var (
+	importOutput string
+	importOut    string
+	importFrom   string
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import <url|file>",
+	Short: "Import recipes from web pages or other recipe apps",
+	Long: `Import a recipe from a web page (or a saved HTML or JSON-LD file) that
+describes it with Schema.org Recipe markup, as most recipe sites do, or migrate
+a collection from another app with --from:
+
+  paprika    Paprika export (.paprikarecipes or .paprikarecipe)
+  mealie     Mealie recipe JSON (one recipe, a list, or an API page)
+  nextcloud  Nextcloud Cookbook recipe.json, or a folder of recipe folders
+
+Ingredients are linked into the first step that mentions them, durations
+become timers, and the recipe's name, times, yield, tags and images become
+metadata. The source URL is kept as "source" metadata. Review the result:
+ingredients no step mentions are listed in an opening "Gather" step.
+
+With --out, each recipe is saved as "<title>.cook" in that directory, along
+with its photo if the export has one. Existing files are not overwritten.
+
+Examples:
+  # Print the recipe as Cooklang
+  cook import https://example.com/recipes/pancakes
+
+  # Save it in a recipe directory, named after the recipe title
+  cook import https://example.com/recipes/pancakes --out ~/recipes
+
+  # Convert a saved page
+  cook import pancakes.html -o Pancakes.cook
+
+  # Migrate a Paprika collection
+  cook import --from paprika backup.paprikarecipes --out ./recipes`,
+	Args: cobra.ExactArgs(1),
+	RunE: runImport,
+}
+
+func init() {
+	importCmd.Flags().StringVarP(&importOutput, "output", "o", "", "Output file (default: stdout)")
+	importCmd.Flags().StringVarP(&importOut, "out", "d", "", "Save recipes as <title>.cook in this directory")
+	importCmd.Flags().StringVar(&importFrom, "from", "web", "Source format: web, paprika, mealie, nextcloud")
+	importCmd.MarkFlagsMutuallyExclusive("output", "out")
+	_ = importCmd.MarkFlagDirname("out")
+	_ = importCmd.RegisterFlagCompletionFunc("from", cobra.FixedCompletions([]string{"web", "paprika", "mealie", "nextcloud"}, cobra.ShellCompDirectiveNoFileComp))
+	rootCmd.AddCommand(importCmd)
+}
+
+func runImport(cmd *cobra.Command, args []string) error {
+	source := args[0]
+	if importFrom == "web" && strings.HasSuffix(strings.ToLower(source), ".paprikarecipes") {
+		importFrom = string(importers.FormatPaprika)
+	}
+
+	var recipes []importers.Recipe
+	var importErr error
+	if importFrom == "web" {
+		recipe, err := importWebRecipe(source)
+		if err != nil {
+			return err
+		}
+		recipes = []importers.Recipe{recipe}
+	} else {
+		format, err := importers.ParseFormat(importFrom)
+		if err != nil {
+			return err
+		}
+		recipes, importErr = importers.ImportFile(format, source)
+		if len(recipes) == 0 {
+			if importErr == nil {
+				importErr = fmt.Errorf("no recipes found in %s", source)
+			}
+			return importErr
+		}
+	}
+
+	switch {
+	case importOut != "":
+		if err := saveImportedRecipes(recipes, importOut); err != nil {
+			return err
+		}
+	case importOutput != "":
+		if len(recipes) > 1 {
+			return fmt.Errorf("%s holds %d recipes; use --out to save them to a directory", source, len(recipes))
+		}
+		if err := os.WriteFile(importOutput, []byte(recipes[0].Cooklang), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", importOutput, err)
+		}
+		printSuccess("Imported %s to %s", recipes[0].Name, importOutput)
+	default:
+		if len(recipes) > 1 {
+			return fmt.Errorf("%s holds %d recipes; use --out to save them to a directory", source, len(recipes))
+		}
+		fmt.Print(recipes[0].Cooklang)
+	}
+
+	if importErr != nil {
+		printWarning("Some recipes could not be imported:\n%v", importErr)
+		return fmt.Errorf("imported %d recipes with errors", len(recipes))
+	}
+	return nil
+}
+
+// importWebRecipe converts a web page or saved HTML/JSON-LD file with Schema.org markup.
+func importWebRecipe(source string) (importers.Recipe, error) {
+	data, err := readImportSource(source)
+	if err != nil {
+		return importers.Recipe{}, err
+	}
+	sourceURL := ""
+	if isURL(source) {
+		sourceURL = source
+	}
+	text, err := cooklang.ImportSchemaOrg(data, sourceURL)
+	if err != nil {
+		return importers.Recipe{}, fmt.Errorf("%s: %w", source, err)
+	}
+	recipe, err := cooklang.ParseString(text)
+	if err != nil {
+		return importers.Recipe{}, fmt.Errorf("imported recipe does not parse: %w", err)
+	}
+	printVerbose("Imported %d ingredients", len(recipe.GetIngredients().Ingredients))
+	return importers.Recipe{Name: recipe.Title, Cooklang: text}, nil
+}
+
+// saveImportedRecipes writes each recipe, and its photo, to dir. Recipes whose file
+// already exists are skipped.
+func saveImportedRecipes(recipes []importers.Recipe, dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	saved := 0
+	errs := &multiError{Total: len(recipes)}
+	for i, recipe := range recipes {
+		printProgress(i+1, len(recipes), recipe.Name)
+		name := recipe.FileName()
+		if name == "" {
+			printWarning("Skipping a recipe without a title")
+			continue
+		}
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			printWarning("Skipping %s: %s already exists", recipe.Name, path)
+			continue
+		}
+		if err := os.WriteFile(path, []byte(recipe.Cooklang), 0644); err != nil {
+			errs.add(path, fmt.Errorf("failed to write: %w", err))
+			continue
+		}
+		saved++
+		printVerbose("Saved %s", path)
+		if len(recipe.Image) > 0 {
+			image := strings.TrimSuffix(path, ".cook") + recipe.ImageExt
+			if err := os.WriteFile(image, recipe.Image, 0644); err != nil {
+				errs.add(image, fmt.Errorf("failed to write: %w", err))
+			}
+		}
+	}
+	if err := errs.err(); err != nil {
+		printInfo("Imported %d of %d recipes to %s", saved, len(recipes), dir)
+		return err
+	}
+	if saved == 0 && len(recipes) > 0 {
+		return fmt.Errorf("no recipes were saved to %s", dir)
+	}
+	printSuccess("Imported %d of %d recipes to %s", saved, len(recipes), dir)
+	return nil
+}
+
+// readImportSource downloads a URL or reads a local file.
+func readImportSource(source string) ([]byte, error) {
+	if !isURL(source) {
+		data, err := os.ReadFile(source)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", source, err)
+		}
+		return data, nil
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	req, err := http.NewRequest(http.MethodGet, source, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL %s: %w", source, err)
+	}
+	req.Header.Set("User-Agent", "cook/"+version+" (+https://github.com/hilli/cooklang)")
+	req.Header.Set("Accept", "text/html,application/ld+json,application/json;q=0.9,*/*;q=0.8")
+
+	printVerbose("Fetching %s", source)
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", source, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch %s: %s", source, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxImportSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", source, err)
+	}
+	return data, nil
+}
+
+// isURL reports whether an import source is an http(s) URL rather than a file.
+func isURL(source string) bool {
+	return strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://")
+}
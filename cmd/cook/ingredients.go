@@ -12,6 +12,8 @@ var (
 	ingredientsJSON        bool
 	ingredientsConsolidate bool
 	ingredientsTargetUnit  string
+	ingredientsProfile     string
+	ingredientsPreferred   string
 )
 
 var ingredientsCmd = &cobra.Command{
@@ -28,6 +30,8 @@ Examples:
   cook ingredients recipe1.cook recipe2.cook --consolidate
   cook ingredients *.cook --consolidate --unit metric
   cook ingredients recipe.cook --unit imperial
+  cook ingredients Negroni.cook --unit us --profile bartender
+  cook ingredients recipe.cook --unit us --preferred-units units.yaml
   cook ingredients recipe.cook --json`,
 	Args:              cobra.MinimumNArgs(1),
 	RunE:              runIngredients,
@@ -38,10 +42,14 @@ func init() {
 	ingredientsCmd.Flags().BoolVarP(&ingredientsJSON, "json", "j", false, "Output as JSON")
 	ingredientsCmd.Flags().BoolVarP(&ingredientsConsolidate, "consolidate", "c", false, "Consolidate ingredients with the same name")
 	ingredientsCmd.Flags().StringVarP(&ingredientsTargetUnit, "unit", "u", "", "Convert to unit system (metric, imperial, us)")
+	ingredientsCmd.Flags().StringVar(&ingredientsProfile, "profile", "auto", "How --unit rounds and picks units: precise, bartender, baking, auto (bartender for cocktails)")
+	ingredientsCmd.Flags().StringVar(&ingredientsPreferred, "preferred-units", "", "YAML file of preferred units per ingredient (butter: g, eggs: count)")
 	rootCmd.AddCommand(ingredientsCmd)
 
 	// Register completion for the --unit flag
 	_ = ingredientsCmd.RegisterFlagCompletionFunc("unit", completeUnitFlag)
+	_ = ingredientsCmd.RegisterFlagCompletionFunc("profile", completeProfileFlag)
+	_ = ingredientsCmd.MarkFlagFilename("preferred-units", "yaml", "yml")
 }
 
 func runIngredients(cmd *cobra.Command, args []string) error {
@@ -73,8 +81,21 @@ func getUnitSystem(unit string) (cooklang.UnitSystem, bool) {
 	}
 }
 
+// conversionProfile parses a --profile flag, choosing a profile for the recipes
+// for auto
+func conversionProfile(name string, recipes ...*cooklang.Recipe) (cooklang.ConversionProfile, error) {
+	profile, err := cooklang.ParseConversionProfile(name)
+	if err != nil {
+		return "", err
+	}
+	if profile == cooklang.ProfileAuto {
+		profile = cooklang.ProfileForRecipes(recipes...)
+	}
+	return profile, nil
+}
+
 // convertIngredientList converts all ingredients to the target unit system
-func convertIngredientList(ingredients *cooklang.IngredientList, targetUnit string) (*cooklang.IngredientList, error) {
+func convertIngredientList(ingredients *cooklang.IngredientList, targetUnit string, profile cooklang.ConversionProfile) (*cooklang.IngredientList, error) {
 	if targetUnit == "" {
 		return ingredients, nil
 	}
@@ -84,7 +105,20 @@ func convertIngredientList(ingredients *cooklang.IngredientList, targetUnit stri
 		return nil, fmt.Errorf("invalid unit system: %s (use metric, imperial, or us)", targetUnit)
 	}
 
-	return ingredients.ConvertToSystem(system), nil
+	return ingredients.ConvertToSystemWithProfile(system, profile), nil
+}
+
+// applyPreferredUnits converts ingredients to the units of a --preferred-units file;
+// without a file the list is returned as it is
+func applyPreferredUnits(ingredients *cooklang.IngredientList, path string) (*cooklang.IngredientList, error) {
+	if path == "" {
+		return ingredients, nil
+	}
+	prefs, err := cooklang.LoadPreferredUnits(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load preferred units: %w", err)
+	}
+	return ingredients.ApplyPreferredUnits(prefs), nil
 }
 
 func displaySingleRecipeIngredients(recipe *cooklang.Recipe, filename string) error {
@@ -92,11 +126,18 @@ func displaySingleRecipeIngredients(recipe *cooklang.Recipe, filename string) er
 
 	// Apply unit conversion if requested
 	if ingredientsTargetUnit != "" {
-		var err error
-		ingredients, err = convertIngredientList(ingredients, ingredientsTargetUnit)
+		profile, err := conversionProfile(ingredientsProfile, recipe)
 		if err != nil {
 			return err
 		}
+		ingredients, err = convertIngredientList(ingredients, ingredientsTargetUnit, profile)
+		if err != nil {
+			return err
+		}
+	}
+	ingredients, err := applyPreferredUnits(ingredients, ingredientsPreferred)
+	if err != nil {
+		return err
 	}
 
 	if ingredientsJSON {
@@ -126,6 +167,10 @@ func displayConsolidatedIngredients(recipes []*cooklang.Recipe, filenames []stri
 			return fmt.Errorf("invalid unit system: %s (use metric, imperial, or us)", ingredientsTargetUnit)
 		}
 	}
+	profile, err := conversionProfile(ingredientsProfile, recipes...)
+	if err != nil {
+		return err
+	}
 
 	// Collect all ingredients
 	allIngredients := cooklang.NewIngredientList()
@@ -138,12 +183,11 @@ func displayConsolidatedIngredients(recipes []*cooklang.Recipe, filenames []stri
 
 	// Consolidate if requested
 	var finalList *cooklang.IngredientList
-	var err error
 
 	if ingredientsConsolidate {
 		// Convert to unit system first if requested, then consolidate
 		if system, isSystem := getUnitSystem(ingredientsTargetUnit); isSystem {
-			converted := allIngredients.ConvertToSystem(system)
+			converted := allIngredients.ConvertToSystemWithProfile(system, profile)
 			finalList, err = converted.ConsolidateByName("")
 		} else {
 			// No unit conversion, just consolidate
@@ -158,12 +202,15 @@ func displayConsolidatedIngredients(recipes []*cooklang.Recipe, filenames []stri
 		// Apply unit conversion if requested (without consolidation)
 		if ingredientsTargetUnit != "" {
 			var convErr error
-			finalList, convErr = convertIngredientList(finalList, ingredientsTargetUnit)
+			finalList, convErr = convertIngredientList(finalList, ingredientsTargetUnit, profile)
 			if convErr != nil {
 				return convErr
 			}
 		}
 	}
+	if finalList, err = applyPreferredUnits(finalList, ingredientsPreferred); err != nil {
+		return err
+	}
 
 	if ingredientsJSON {
 		return outputJSON(finalList)
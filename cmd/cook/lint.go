@@ -0,0 +1,160 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hilli/cooklang/lint"
+	"github.com/hilli/cooklang/parser"
+	"github.com/spf13/cobra"
+)
+
+var (
+	lintFailOn  string
+	lintDisable []string
+	lintJSON    bool
+)
+
+var lintCmd = &cobra.Command{
+	Use:   "lint [file|dir...]",
+	Short: "Check recipes for common mistakes",
+	Long: `Check recipes for mistakes that parse fine but make a recipe less useful.
+
+Rules:
+  unmarked-ingredient   An ingredient is mentioned before it is marked with @ (warning)
+  timer-unit            A timer has no unit, so it is read as minutes (warning)
+  missing-servings      No servings metadata, so the recipe cannot be scaled to servings (warning)
+  unparseable-quantity  A quantity is not a number, fraction or range (error)
+  duplicate-metadata    A metadata key is set more than once (error)
+  unknown-unit          A unit cannot be converted (info)
+
+Directories are searched recursively for .cook files; without arguments the
+current directory is checked. The command fails when an issue is at least as
+severe as --fail-on, so it can run in CI for a recipe repository.
+
+Examples:
+  cook lint
+  cook lint ./recipes --fail-on warning
+  cook lint Pancakes.cook --disable unknown-unit,missing-servings
+  cook lint ./recipes --json`,
+	RunE:              runLint,
+	ValidArgsFunction: completeCookFiles,
+}
+
+func init() {
+	lintCmd.Flags().StringVar(&lintFailOn, "fail-on", "error", "Fail on issues of this severity or worse: info, warning, error, or none")
+	lintCmd.Flags().StringSliceVar(&lintDisable, "disable", nil, "Rules to skip (comma-separated)")
+	lintCmd.Flags().BoolVar(&lintJSON, "json", false, "Output issues as JSON")
+	rootCmd.AddCommand(lintCmd)
+
+	_ = lintCmd.RegisterFlagCompletionFunc("fail-on", cobra.FixedCompletions([]string{"info", "warning", "error", "none"}, cobra.ShellCompDirectiveNoFileComp))
+	ruleNames := make([]string, len(lint.DefaultRules))
+	for i, rule := range lint.DefaultRules {
+		ruleNames[i] = rule.Name
+	}
+	_ = lintCmd.RegisterFlagCompletionFunc("disable", cobra.FixedCompletions(ruleNames, cobra.ShellCompDirectiveNoFileComp))
+}
+
+// lintResult is the issues found in one file, for --json.
+type lintResult struct {
+	File   string       `json:"file"`
+	Issues []lint.Issue `json:"issues"`
+}
+
+func runLint(cmd *cobra.Command, args []string) error {
+	failOn := lint.Severity(-1)
+	if !strings.EqualFold(lintFailOn, "none") {
+		severity, err := lint.ParseSeverity(lintFailOn)
+		if err != nil {
+			return err
+		}
+		failOn = severity
+	}
+
+	if len(args) == 0 {
+		args = []string{"."}
+	}
+	files, err := collectCookFiles(args)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no .cook files found in %s", strings.Join(args, ", "))
+	}
+
+	// Lint findings are not usage errors
+	cmd.SilenceUsage = true
+
+	p := parser.New()
+	p.ExtendedMode = !canonicalMode
+	linter := lint.Linter{Disabled: lintDisable, Parser: p}
+
+	results := make([]lintResult, 0, len(files))
+	failing, total := 0, 0
+	for _, file := range files {
+		issues, err := linter.LintFile(file)
+		if err != nil {
+			return fmt.Errorf("failed to lint %s: %w", file, err)
+		}
+		if issues == nil {
+			issues = []lint.Issue{}
+		}
+		results = append(results, lintResult{File: file, Issues: issues})
+		for _, issue := range issues {
+			total++
+			if failOn >= 0 && issue.Severity >= failOn {
+				failing++
+			}
+			if !lintJSON {
+				fmt.Printf("%s: %s\n", file, issue)
+			}
+		}
+	}
+
+	if lintJSON {
+		if err := outputJSON(results); err != nil {
+			return err
+		}
+	}
+	if failing > 0 {
+		return fmt.Errorf("%d of %d issues in %d files are %s or worse", failing, total, len(files), failOn)
+	}
+	if total == 0 {
+		printSuccess("No issues in %d files", len(files))
+	} else {
+		printInfo("%d issues in %d files", total, len(files))
+	}
+	return nil
+}
+
+// collectCookFiles expands directories to the .cook files they contain, recursively.
+// Files given directly are used whatever their extension.
+func collectCookFiles(paths []string) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+		err = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() && filepath.Ext(p) == ".cook" {
+				files = append(files, p)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
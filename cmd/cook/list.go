@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hilli/cooklang"
+	"github.com/spf13/cobra"
+)
+
+var (
+	listTags        []string
+	listIngredients []string
+	listCuisine     string
+	listTitle       string
+	listSearch      string
+	listJSON        bool
+)
+
+var listCmd = &cobra.Command{
+	Use:   "list [directory]",
+	Short: "List recipes in a collection",
+	Long: `List the recipes found recursively in a directory (default: current directory).
+
+Filters can be combined; a recipe must match all of them. Repeat --tag or
+--ingredient (or separate values with commas) to require several.
+
+Examples:
+  cook list ~/recipes
+  cook list --tag italian --ingredient chicken
+  cook list ~/recipes --cuisine mexican
+  cook list --search lime
+  cook list ~/recipes --tag cocktail --json`,
+	Args:              cobra.MaximumNArgs(1),
+	RunE:              runList,
+	ValidArgsFunction: completeDirectory,
+}
+
+func init() {
+	listCmd.Flags().StringSliceVar(&listTags, "tag", nil, "Only recipes with this tag (repeatable)")
+	listCmd.Flags().StringSliceVar(&listIngredients, "ingredient", nil, "Only recipes using this ingredient (repeatable)")
+	listCmd.Flags().StringVar(&listCuisine, "cuisine", "", "Only recipes of this cuisine")
+	listCmd.Flags().StringVar(&listTitle, "title", "", "Only recipes whose title contains this text")
+	listCmd.Flags().StringVar(&listSearch, "search", "", "Only recipes mentioning this text in title, tags, cuisine, ingredients or description")
+	listCmd.Flags().BoolVarP(&listJSON, "json", "j", false, "Output as JSON")
+	rootCmd.AddCommand(listCmd)
+}
+
+func runList(cmd *cobra.Command, args []string) error {
+	dir := "."
+	if len(args) == 1 {
+		dir = args[0]
+	}
+
+	library, err := cooklang.LoadLibrary(dir)
+	if err != nil {
+		if library.Len() == 0 {
+			return fmt.Errorf("failed to load recipes from %s: %w", dir, err)
+		}
+		printWarning("Some recipes could not be loaded: %v", err)
+	}
+	printVerbose("Loaded %d recipes from %s", library.Len(), dir)
+
+	entries := library.Filter(cooklang.LibraryFilter{
+		Title:       listTitle,
+		Tags:        listTags,
+		Cuisine:     listCuisine,
+		Ingredients: listIngredients,
+	})
+	if listSearch != "" {
+		matches := library.Search(listSearch)
+		entries = intersectEntries(entries, matches)
+	}
+
+	if listJSON {
+		type listItem struct {
+			Title   string   `json:"title"`
+			Path    string   `json:"path"`
+			Cuisine string   `json:"cuisine,omitempty"`
+			Tags    []string `json:"tags,omitempty"`
+		}
+		items := make([]listItem, 0, len(entries))
+		for _, entry := range entries {
+			items = append(items, listItem{
+				Title:   entry.Name(),
+				Path:    entry.Path,
+				Cuisine: entry.Recipe.Cuisine,
+				Tags:    entry.Recipe.Tags,
+			})
+		}
+		return outputJSON(items)
+	}
+
+	for _, entry := range entries {
+		line := fmt.Sprintf("%s (%s)", entry.Name(), entry.Path)
+		if len(entry.Recipe.Tags) > 0 {
+			line += " [" + strings.Join(entry.Recipe.Tags, ", ") + "]"
+		}
+		fmt.Println(line)
+	}
+	printInfo("%d of %d recipes", len(entries), library.Len())
+	return nil
+}
+
+// intersectEntries returns the entries of a that are also in b, keeping a's order.
+func intersectEntries(a, b []*cooklang.LibraryEntry) []*cooklang.LibraryEntry {
+	inB := make(map[*cooklang.LibraryEntry]bool, len(b))
+	for _, entry := range b {
+		inB[entry] = true
+	}
+	var result []*cooklang.LibraryEntry
+	for _, entry := range a {
+		if inB[entry] {
+			result = append(result, entry)
+		}
+	}
+	return result
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
@@ -14,6 +15,10 @@ var (
 
 	// Global flags
 	canonicalMode bool // When true, use canonical spec mode (no extended features)
+	quietMode     bool // When true, suppress info and success messages
+	verboseMode   bool // When true, print additional diagnostic messages
+	progressMode  bool // When true, report the progress of bulk operations file by file
+	noColor       bool // When true, never color diagnostic output
 )
 
 var rootCmd = &cobra.Command{
@@ -37,8 +42,18 @@ By default, the parser uses extended mode which supports:
 
 Use --canonical to disable extended features and parse in strict canonical mode.
 
+Defaults such as the unit system and the render format can be set in
+~/.config/cook/config.yaml; see cook config.
+
+Command output is written to stdout; status messages, warnings, and errors are
+written to stderr so output can be piped safely. Use --quiet to hide status
+messages, --verbose for more detail, --progress to follow bulk operations file
+by file, and --no-color (or NO_COLOR) to disable colors. Bulk operations go on
+when a file fails and report all failures at the end.
+
 Visit https://cooklang.org for more information about the Cooklang format.`,
-	Version: version,
+	Version:           version,
+	PersistentPreRunE: applyUserConfig,
 }
 
 func init() {
@@ -46,11 +61,27 @@ func init() {
 
 	// Add global flags
 	rootCmd.PersistentFlags().BoolVar(&canonicalMode, "canonical", false, "Use canonical spec mode (disable extended features)")
+	rootCmd.PersistentFlags().BoolVarP(&quietMode, "quiet", "q", false, "Suppress informational messages")
+	rootCmd.PersistentFlags().BoolVarP(&verboseMode, "verbose", "v", false, "Print additional diagnostic messages")
+	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "Disable colored output")
+	rootCmd.PersistentFlags().BoolVar(&progressMode, "progress", false, "Report the progress of bulk operations file by file")
+	rootCmd.MarkFlagsMutuallyExclusive("quiet", "verbose")
+	rootCmd.MarkFlagsMutuallyExclusive("quiet", "progress")
+
+	// Errors are reported by main on stderr
+	rootCmd.SilenceErrors = true
 }
 
+// errExitStatus is returned by commands that report their result through the exit
+// status only, such as cook diff --exit-code; main exits with status 1 without
+// printing it.
+var errExitStatus = errors.New("exit status 1")
+
 func main() {
 	if err := rootCmd.Execute(); err != nil {
-		fmt.Fprintln(os.Stderr, err)
+		if !errors.Is(err, errExitStatus) {
+			printError(err)
+		}
 		os.Exit(1)
 	}
 }
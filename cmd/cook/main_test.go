@@ -1,12 +1,22 @@
 package main
 
 import (
+	"archive/zip"
 	"bytes"
+	"encoding/json"
+	"image"
+	"image/png"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/hilli/cooklang"
 )
 
 // TestMain builds the CLI binary before running tests
@@ -94,6 +104,12 @@ func TestCLI_Parse(t *testing.T) {
 			t.Errorf("parse output missing %q", expected)
 		}
 	}
+	if !strings.Contains(stdout, "│ Negroni") || !strings.Contains(stdout, "  1. Pour gin (50 ml)") {
+		t.Errorf("expected the terminal rendering, got:\n%s", stdout)
+	}
+	if strings.Contains(stdout, "\033[") {
+		t.Error("output that is not a terminal should not be colored")
+	}
 }
 
 func TestCLI_Parse_JSON(t *testing.T) {
@@ -180,151 +196,2274 @@ func TestCLI_Render_Markdown(t *testing.T) {
 	}
 }
 
-func TestCLI_Render_HTML(t *testing.T) {
-	recipePath := getExampleRecipePath("Negroni.cook")
+func TestCLI_List(t *testing.T) {
+	dir := filepath.Join("..", "..", "example_recipes")
 
-	stdout, stderr, err := runCLI("render", recipePath, "--format", "html")
+	stdout, stderr, err := runCLI("list", dir, "--tag", "gin", "--ingredient", "campari")
 	if err != nil {
-		t.Fatalf("render html command failed: %v\nstderr: %s", err, stderr)
+		t.Fatalf("list command failed: %v\nstderr: %s", err, stderr)
+	}
+	if strings.TrimSpace(stdout) != "Negroni (Negroni.cook) [classic, bitter, aperitif, gin, vermouth, campari]" {
+		t.Errorf("unexpected list output: %q", stdout)
+	}
+	if !strings.Contains(stderr, "1 of 3 recipes") {
+		t.Errorf("expected recipe count on stderr, got: %q", stderr)
 	}
 
-	// Check for complete HTML document structure
-	expectedStrings := []string{
-		"<!DOCTYPE html>",
-		"<html lang=\"en\">",
-		"<meta charset=\"UTF-8\">",
-		"<title>Negroni</title>",
-		"</head>",
-		"<body>",
-		"<div class=\"recipe\">",
-		"</body>",
-		"</html>",
+	stdout, stderr, err = runCLI("list", dir, "--cuisine", "british", "--json")
+	if err != nil {
+		t.Fatalf("list --json command failed: %v\nstderr: %s", err, stderr)
 	}
-	for _, expected := range expectedStrings {
-		if !strings.Contains(stdout, expected) {
-			t.Errorf("HTML output missing %q", expected)
+	var items []map[string]interface{}
+	if err := json.Unmarshal([]byte(stdout), &items); err != nil {
+		t.Fatalf("list output is not valid JSON: %v\n%s", err, stdout)
+	}
+	if len(items) != 1 || items[0]["title"] != "Gin and Tonic" {
+		t.Errorf("unexpected JSON list: %v", items)
+	}
+}
+
+func TestCLI_Lint(t *testing.T) {
+	stdout, stderr, err := runCLI("lint", filepath.Join("..", "..", "example_recipes"), "--fail-on", "warning")
+	if err != nil {
+		t.Fatalf("lint of the example recipes failed: %v\n%s%s", err, stdout, stderr)
+	}
+	if !strings.Contains(stdout, `Negroni.cook: info: line 22: unit "cube"`) || !strings.Contains(stderr, "2 issues in 3 files") {
+		t.Errorf("unexpected lint output:\n%s%s", stdout, stderr)
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "Toast.cook"), []byte("---\nservings: 1\n---\nToast @bread{2%slices} for ~{3}.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	stdout, _, err = runCLI("lint", dir)
+	if err != nil {
+		t.Fatalf("warnings should not fail with the default --fail-on error: %v", err)
+	}
+	if !strings.Contains(stdout, "Toast.cook: warning: line 4: timer \"3\" has no unit") || !strings.Contains(stdout, "[unknown-unit]") {
+		t.Errorf("unexpected lint output:\n%s", stdout)
+	}
+
+	stdout, _, err = runCLI("lint", dir, "--fail-on", "warning", "--disable", "unknown-unit")
+	if err == nil {
+		t.Error("expected --fail-on warning to fail")
+	}
+	if strings.Contains(stdout, "unknown-unit") {
+		t.Errorf("expected the disabled rule to be skipped, got:\n%s", stdout)
+	}
+
+	stdout, _, err = runCLI("lint", dir, "--json")
+	if err != nil {
+		t.Fatalf("lint --json failed: %v", err)
+	}
+	var results []struct {
+		File   string `json:"file"`
+		Issues []struct {
+			Rule     string `json:"rule"`
+			Severity string `json:"severity"`
+		} `json:"issues"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &results); err != nil {
+		t.Fatalf("lint output is not valid JSON: %v\n%s", err, stdout)
+	}
+	if len(results) != 1 || len(results[0].Issues) != 2 || results[0].Issues[0].Severity != "warning" {
+		t.Errorf("unexpected JSON: %+v", results)
+	}
+}
+
+func TestCLI_Fmt(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "Toast.cook")
+	src := "---\nservings:  1\ntitle: Toast\n---\nToast   @bread{ 2 % slices }\nfor ~{3%minutes}.\n\n\nServe with @butter{0.5%tbsp}.\n"
+	if err := os.WriteFile(path, []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+	expected := "---\ntitle: Toast\nservings: 1\n---\n\nToast @bread{2%slices} for ~{3%minutes}.\n\nServe with @butter{1/2%tbsp}.\n"
+
+	stdout, _, err := runCLI("fmt", path, "--quantities", "fraction")
+	if err != nil {
+		t.Fatalf("fmt failed: %v", err)
+	}
+	if stdout != expected {
+		t.Errorf("unexpected output:\n%s", stdout)
+	}
+
+	stdout, _, err = runCLI("fmt", "-l", dir)
+	if err != nil {
+		t.Fatalf("fmt -l failed: %v", err)
+	}
+	if strings.TrimSpace(stdout) != path {
+		t.Errorf("expected %s to be listed, got: %q", path, stdout)
+	}
+
+	if _, _, err := runCLI("fmt", "-w", "--quantities", "fraction", dir); err != nil {
+		t.Fatalf("fmt -w failed: %v", err)
+	}
+	written, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(written) != expected {
+		t.Errorf("unexpected file content:\n%s", written)
+	}
+
+	if _, _, err := runCLI("fmt", path, "--quantities", "roman"); err == nil {
+		t.Error("expected an error for an unknown quantity style")
+	}
+}
+
+func TestCLI_ImagesOptimize(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "Toast.cook"), []byte("Toast @bread{2%slices}."), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for name, width := range map[string]int{"Toast.png": 64, "Unused.png": 8} {
+		file, err := os.Create(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := png.Encode(file, image.NewGray(image.Rect(0, 0, width, width/2))); err != nil {
+			t.Fatal(err)
 		}
+		_ = file.Close()
+	}
+
+	stdout, stderr, err := runCLI("images", "optimize", dir, "--max-size", "16", "--delete-orphans")
+	if err != nil {
+		t.Fatalf("images optimize failed: %v\nstderr: %s", err, stderr)
+	}
+	if !strings.Contains(stdout, "Toast.web.png") || !strings.Contains(stdout, "Orphaned images:\n  Unused.png") {
+		t.Errorf("unexpected images output: %q", stdout)
+	}
+	if !strings.Contains(stderr, "--yes") {
+		t.Errorf("expected a confirmation warning, got: %q", stderr)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "Toast.web.png")); err != nil {
+		t.Errorf("web variant was not written: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "Unused.png")); err != nil {
+		t.Errorf("orphan was deleted without --yes: %v", err)
+	}
+
+	stdout, stderr, err = runCLI("images", "optimize", dir, "--max-size", "16", "--delete-orphans", "--yes", "--json")
+	if err != nil {
+		t.Fatalf("images optimize --yes failed: %v\nstderr: %s", err, stderr)
+	}
+	var report struct {
+		Generated []string `json:"generated"`
+		Deleted   []string `json:"deleted"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &report); err != nil {
+		t.Fatalf("output is not valid JSON: %v\n%s", err, stdout)
+	}
+	if len(report.Generated) != 0 || len(report.Deleted) != 1 || report.Deleted[0] != "Unused.png" {
+		t.Errorf("unexpected report: %+v", report)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "Unused.png")); !os.IsNotExist(err) {
+		t.Errorf("orphan was not deleted: %v", err)
 	}
 }
 
-func TestCLI_Render_Cooklang(t *testing.T) {
-	recipePath := getExampleRecipePath("Negroni.cook")
+func TestCLI_ImagesOptimizeKeepsOrphansOnLoadErrors(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"Toast.cook":  "Toast @bread{2%slices}.",
+		"Broken.cook": "Mix @flour{",
+		"Broken.png":  "not really a png",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
 
-	stdout, stderr, err := runCLI("render", recipePath, "--format", "cooklang")
+	_, stderr, err := runCLI("images", "optimize", dir, "--delete-orphans", "--yes")
+	if err == nil {
+		t.Fatal("expected images optimize to refuse deleting orphans")
+	}
+	if !strings.Contains(stderr, "--force") {
+		t.Errorf("expected a hint about --force, got: %q", stderr)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "Broken.png")); err != nil {
+		t.Errorf("image of the unparseable recipe was deleted: %v", err)
+	}
+
+	if _, stderr, err := runCLI("images", "optimize", dir, "--delete-orphans", "--yes", "--force"); err != nil {
+		t.Fatalf("images optimize --force failed: %v\nstderr: %s", err, stderr)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "Broken.png")); !os.IsNotExist(err) {
+		t.Errorf("orphan was not deleted with --force: %v", err)
+	}
+}
+
+func TestCLI_ExportSite(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "breakfast"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "breakfast", "Toast.cook"), []byte("---\nprep_time: 5 minutes\n---\nToast @bread{2%slices}."), 0644); err != nil {
+		t.Fatal(err)
+	}
+	out := filepath.Join(t.TempDir(), "content", "recipes")
+
+	if _, stderr, err := runCLI("export-site", dir, "--out", out, "--base-url", "https://example.com/recipes/"); err != nil {
+		t.Fatalf("export-site failed: %v\nstderr: %s", err, stderr)
+	}
+	page, err := os.ReadFile(filepath.Join(out, "breakfast", "Toast.md"))
 	if err != nil {
-		t.Fatalf("render cooklang command failed: %v\nstderr: %s", err, stderr)
+		t.Fatalf("expected the page to be written: %v", err)
+	}
+	for _, want := range []string{"title: Toast", "prep_time_iso: PT5M", `"url": "https://example.com/recipes/breakfast/Toast/"`, `{{< ingredient name="bread" quantity="2" unit="slices" >}}`} {
+		if !strings.Contains(string(page), want) {
+			t.Errorf("expected page to contain %q, got:\n%s", want, page)
+		}
 	}
 
-	// Check for cooklang syntax elements
-	if !strings.Contains(stdout, "@") {
-		t.Errorf("cooklang output missing ingredient markers (@)")
+	if _, _, err := runCLI("export-site", dir, "--out", out, "--flavor", "gatsby"); err == nil {
+		t.Error("expected an error for an unknown flavor")
 	}
 }
 
-func TestCLI_Scale(t *testing.T) {
+func TestCLI_Timeline(t *testing.T) {
 	recipePath := getExampleRecipePath("Negroni.cook")
 
-	stdout, stderr, err := runCLI("scale", recipePath, "--servings", "2")
+	stdout, stderr, err := runCLI("timeline", recipePath, "--serve-at", "2025-12-24 19:00")
 	if err != nil {
-		t.Fatalf("scale command failed: %v\nstderr: %s", err, stderr)
+		t.Fatalf("timeline command failed: %v\nstderr: %s", err, stderr)
+	}
+	if !strings.HasPrefix(stdout, "18:49  Step 1: ") || !strings.Contains(stdout, "18:54  Step 2: Stir for 10-15 seconds until well chilled. [15s]") || !strings.HasSuffix(stdout, "19:00  Serve\n") {
+		t.Errorf("unexpected timeline output:\n%s", stdout)
 	}
 
-	// Scaled recipe should have doubled quantities (50ml -> 100ml)
-	if !strings.Contains(stdout, "100") {
-		t.Errorf("scaled output doesn't show doubled quantities")
+	icsFile := filepath.Join(t.TempDir(), "dinner.ics")
+	if _, stderr, err := runCLI("timeline", recipePath, "--serve-at", "2025-12-24T19:00:00Z", "--ics", icsFile); err != nil {
+		t.Fatalf("timeline --ics failed: %v\nstderr: %s", err, stderr)
+	}
+	content, err := os.ReadFile(icsFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(content), "BEGIN:VCALENDAR\r\n") || !strings.Contains(string(content), "DTSTART:20251224T185445Z\r\n") {
+		t.Errorf("unexpected ICS output:\n%s", content)
+	}
+
+	if _, stderr, err := runCLI("timeline", recipePath, "--serve-at", "dinner time"); err == nil || !strings.Contains(stderr, "invalid --serve-at") {
+		t.Errorf("expected an invalid --serve-at error, got err=%v stderr=%q", err, stderr)
 	}
 }
 
-func TestCLI_Scale_HTML(t *testing.T) {
-	recipePath := getExampleRecipePath("Negroni.cook")
+func TestServe(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"Negroni.cook", "Gin_and_Tonic.cook"} {
+		content, err := os.ReadFile(getExampleRecipePath(name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), content, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	file, err := os.Create(filepath.Join(dir, "Negroni.png"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := png.Encode(file, image.NewGray(image.Rect(0, 0, 4, 4))); err != nil {
+		t.Fatal(err)
+	}
+	_ = file.Close()
 
-	stdout, stderr, err := runCLI("scale", recipePath, "--servings", "2", "--format", "html")
+	server, err := newRecipeServer(dir, true)
 	if err != nil {
-		t.Fatalf("scale html command failed: %v\nstderr: %s", err, stderr)
+		t.Fatalf("newRecipeServer failed: %v", err)
 	}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+	defer server.close()
 
-	// Check for complete HTML document structure
-	expectedStrings := []string{
-		"<!DOCTYPE html>",
-		"<html lang=\"en\">",
-		"<meta charset=\"UTF-8\">",
-		"<title>Negroni</title>",
-		"</body>",
-		"</html>",
+	get := func(path string) (int, string, string) {
+		t.Helper()
+		resp, err := http.Get(ts.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer func() { _ = resp.Body.Close() }()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.StatusCode, resp.Header.Get("Content-Type"), string(body)
 	}
-	for _, expected := range expectedStrings {
-		if !strings.Contains(stdout, expected) {
-			t.Errorf("scale HTML output missing %q", expected)
+
+	_, _, body := get("/")
+	if !strings.Contains(body, `<a href="/recipe/Negroni">Negroni</a>`) || !strings.Contains(body, `<a href="/recipe/Gin_and_Tonic">Gin and Tonic</a>`) {
+		t.Errorf("index does not list both recipes:\n%s", body)
+	}
+	if !strings.Contains(body, `new EventSource("/events")`) {
+		t.Error("index page is missing the live reload script")
+	}
+
+	_, _, body = get("/?q=i:campari")
+	if !strings.Contains(body, "/recipe/Negroni") || strings.Contains(body, "/recipe/Gin_and_Tonic") {
+		t.Errorf("search did not filter recipes:\n%s", body)
+	}
+	_, _, body = get("/?tag=highball")
+	if strings.Contains(body, "/recipe/Negroni\"") || !strings.Contains(body, "/recipe/Gin_and_Tonic") || !strings.Contains(body, `class="active">highball</a>`) {
+		t.Errorf("tag filter did not filter recipes:\n%s", body)
+	}
+
+	status, _, body := get("/recipe/Negroni")
+	if status != http.StatusOK || !strings.Contains(body, `<h1 class="recipe-title">Negroni</h1>`) {
+		t.Errorf("recipe page not rendered (status %d):\n%s", status, body)
+	}
+	if !strings.Contains(body, `<script type="application/ld+json">`) || !strings.Contains(body, `<img class="recipe-image" src="/images/Negroni.png"`) {
+		t.Errorf("recipe page is missing JSON-LD or image:\n%s", body)
+	}
+
+	if status, contentType, _ := get("/images/Negroni.png"); status != http.StatusOK || contentType != "image/png" {
+		t.Errorf("image: status %d, content type %q", status, contentType)
+	}
+	for _, path := range []string{"/images/Negroni.cook", "/images/../Negroni.png", "/recipe/Missing"} {
+		if status, _, _ := get(path); status != http.StatusNotFound {
+			t.Errorf("%s: expected 404, got %d", path, status)
 		}
 	}
 
-	// Check that scaling worked (100ml instead of 50ml)
-	if !strings.Contains(stdout, "100") {
-		t.Errorf("scaled HTML output doesn't show doubled quantities")
+	// Live reload: a new recipe is picked up and listeners are notified
+	server.mu.RLock()
+	changed := server.changed
+	server.mu.RUnlock()
+	if err := os.WriteFile(filepath.Join(dir, "Martini.cook"), []byte("Stir @gin{60%ml} with @vermouth{10%ml}."), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if reloaded, err := server.refresh(); err != nil || !reloaded {
+		t.Fatalf("refresh() = %v, %v; want reload", reloaded, err)
+	}
+	select {
+	case <-changed:
+	default:
+		t.Error("live reload listeners were not notified")
+	}
+	if _, _, body := get("/"); !strings.Contains(body, `<a href="/recipe/Martini">Martini</a>`) {
+		t.Errorf("new recipe not listed after reload:\n%s", body)
+	}
+	if reloaded, err := server.refresh(); err != nil || reloaded {
+		t.Errorf("refresh() without changes = %v, %v; want no reload", reloaded, err)
 	}
 }
 
-func TestCLI_ShoppingList(t *testing.T) {
-	negroniPath := getExampleRecipePath("Negroni.cook")
-	alaskaPath := getExampleRecipePath("Alaska.cook")
+func TestServeHostileRecipe(t *testing.T) {
+	dir := t.TempDir()
+	hostile := `---
+title: </title><script>alert("title")</script>
+description: </script><script>alert("description")</script>
+tags: <img src=x onerror=alert(1)>
+image: javascript:alert(1)
+---
+Mix @<b>flour</b>{1%<i>cup</i>}(</li><script>alert(1)</script>) in a #bowl{}.
+`
+	if err := os.WriteFile(filepath.Join(dir, "Hostile.cook"), []byte(hostile), 0644); err != nil {
+		t.Fatal(err)
+	}
+	server, err := newRecipeServer(dir, false)
+	if err != nil {
+		t.Fatalf("newRecipeServer failed: %v", err)
+	}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+	defer server.close()
 
-	stdout, stderr, err := runCLI("shopping-list", negroniPath, alaskaPath)
+	for _, path := range []string{"/", "/recipe/Hostile"} {
+		resp, err := http.Get(ts.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, err := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		page := string(body)
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("%s: status %d", path, resp.StatusCode)
+		}
+		// The JSON-LD script is the only script, and recipe text cannot close it early
+		if strings.Count(page, "<script") > strings.Count(page, `<script type="application/ld+json">`) ||
+			strings.Count(page, "</script>") > 1 {
+			t.Errorf("%s: recipe content injected a script:\n%s", path, page)
+		}
+		for _, injected := range []string{"<img src=x", "<b>", "<i>", "</title><", "src=\"javascript:"} {
+			if strings.Contains(page, injected) {
+				t.Errorf("%s: page contains unescaped %q:\n%s", path, injected, page)
+			}
+		}
+	}
+}
+
+func TestServeAPI(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"Negroni.cook", "Gin_and_Tonic.cook"} {
+		content, err := os.ReadFile(getExampleRecipePath(name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), content, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	server, err := newRecipeServer(dir, false)
 	if err != nil {
-		t.Fatalf("shopping-list command failed: %v\nstderr: %s", err, stderr)
+		t.Fatalf("newRecipeServer failed: %v", err)
 	}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+	defer server.close()
 
-	// Shopping list should contain ingredients from both recipes
-	if !strings.Contains(stdout, "gin") {
-		t.Errorf("shopping list missing gin")
+	request := func(method, path, body string, want int, result interface{}) {
+		t.Helper()
+		req, err := http.NewRequest(method, ts.URL+path, strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer func() { _ = resp.Body.Close() }()
+		data, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != want {
+			t.Fatalf("%s %s: expected status %d, got %d: %s", method, path, want, resp.StatusCode, data)
+		}
+		if contentType := resp.Header.Get("Content-Type"); contentType != "application/json" {
+			t.Errorf("%s %s: content type %q", method, path, contentType)
+		}
+		if err := json.Unmarshal(data, result); err != nil {
+			t.Fatalf("%s %s: invalid JSON: %v\n%s", method, path, err, data)
+		}
+	}
+
+	var list struct {
+		Count   int                `json:"count"`
+		Recipes []apiRecipeSummary `json:"recipes"`
+	}
+	request("GET", "/api/recipes", "", http.StatusOK, &list)
+	if list.Count != 2 || len(list.Recipes) != 2 {
+		t.Fatalf("expected 2 recipes, got %+v", list)
+	}
+	request("GET", "/api/recipes?tag=highball", "", http.StatusOK, &list)
+	if list.Count != 1 || list.Recipes[0].Slug != "Gin_and_Tonic" || list.Recipes[0].URL != "/api/recipes/Gin_and_Tonic" {
+		t.Errorf("tag filter: unexpected result %+v", list)
+	}
+
+	var recipe apiRecipe
+	request("GET", "/api/recipes/Negroni?scale=2", "", http.StatusOK, &recipe)
+	if recipe.Title != "Negroni" || recipe.Cuisine != "Italian" || recipe.Servings != 2 {
+		t.Errorf("unexpected recipe: %+v", recipe)
+	}
+	if len(recipe.Steps) != 3 || recipe.Steps[0].Number != 1 || len(recipe.Notes) != 1 {
+		t.Fatalf("expected 3 steps and 1 note, got %+v", recipe)
+	}
+	gin := recipe.Ingredients[0]
+	if gin.Name != "gin" || gin.Quantity == nil || *gin.Quantity != 100 || gin.Unit != "ml" || gin.Display != "100 ml" {
+		t.Errorf("unexpected scaled ingredient: %+v", gin)
+	}
+	timer := recipe.Steps[1].Components[1]
+	if timer.Type != "timer" || timer.Seconds != 10 {
+		t.Errorf("unexpected timer component: %+v", timer)
+	}
+
+	var shopping apiShoppingList
+	request("GET", "/api/recipes/Negroni/shopping-list?servings=3", "", http.StatusOK, &shopping)
+	if len(shopping.Items) == 0 || shopping.Items[0].Name != "Campari" || *shopping.Items[0].Quantity != 150 {
+		t.Errorf("unexpected shopping list: %+v", shopping)
+	}
+
+	request("POST", "/api/shopping-list", `{"recipes": [{"slug": "Negroni"}, {"slug": "Gin_and_Tonic", "scale": 2}]}`, http.StatusOK, &shopping)
+	if len(shopping.Recipes) != 2 {
+		t.Errorf("expected 2 recipes in shopping list, got %v", shopping.Recipes)
+	}
+	for _, item := range shopping.Items {
+		if item.Name == "gin" && (item.Quantity == nil || *item.Quantity != 150 || len(item.Recipes) != 2) {
+			t.Errorf("expected 150 ml gin from both recipes, got %+v", item)
+		}
+	}
+
+	var apiErr struct {
+		Error string `json:"error"`
+	}
+	request("GET", "/api/recipes/Missing", "", http.StatusNotFound, &apiErr)
+	request("GET", "/api/recipes/Negroni?scale=abc", "", http.StatusBadRequest, &apiErr)
+	request("POST", "/api/shopping-list", `{"recipes": []}`, http.StatusBadRequest, &apiErr)
+	if apiErr.Error == "" {
+		t.Error("expected an error message")
 	}
 }
 
-func TestCLI_ShoppingListServings(t *testing.T) {
-	negroniPath := getExampleRecipePath("Negroni.cook")
+func TestCLI_Import(t *testing.T) {
+	page := `<html><head><script type="application/ld+json">
+{"@context": "https://schema.org", "@type": "Recipe", "name": "Quick Lemonade",
+ "recipeYield": "2", "recipeIngredient": ["60 ml lemon juice", "2 tbsp sugar", "400 ml water"],
+ "recipeInstructions": [{"@type": "HowToStep", "text": "Stir the lemon juice, sugar and water until dissolved."}]}
+</script></head><body></body></html>`
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/lemonade" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = io.WriteString(w, page)
+	}))
+	defer ts.Close()
 
-	// Test --servings flag
-	stdout, stderr, err := runCLI("shopping-list", negroniPath, "--servings", "4")
+	stdout, stderr, err := runCLI("import", ts.URL+"/lemonade")
 	if err != nil {
-		t.Fatalf("shopping-list --servings failed: %v\nstderr: %s", err, stderr)
+		t.Fatalf("import command failed: %v\nstderr: %s", err, stderr)
+	}
+	for _, want := range []string{"title: Quick Lemonade", "servings: 2", "source: " + ts.URL + "/lemonade",
+		"Stir the @lemon juice{60%ml}, @sugar{2%tbsp} and @water{400%ml} until dissolved."} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("import output is missing %q:\n%s", want, stdout)
+		}
 	}
 
-	// Output should mention scaling to servings
-	if !strings.Contains(stdout, "4 servings") {
-		t.Errorf("expected '4 servings' in output, got: %s", stdout)
+	dir := t.TempDir()
+	file := filepath.Join(dir, "lemonade.html")
+	if err := os.WriteFile(file, []byte(page), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, stderr, err := runCLI("import", file, "--out", dir); err != nil {
+		t.Fatalf("import --out failed: %v\nstderr: %s", err, stderr)
+	}
+	recipe, err := cooklang.ParseFile(filepath.Join(dir, "Quick Lemonade.cook"))
+	if err != nil {
+		t.Fatalf("imported file does not parse: %v", err)
+	}
+	if got := len(recipe.GetIngredients().Ingredients); got != 3 {
+		t.Errorf("expected 3 ingredients, got %d", got)
+	}
+	if _, stderr, err := runCLI("import", file, "--out", dir); err == nil || !strings.Contains(stderr, "already exists") {
+		t.Errorf("expected an error when the file exists, got err=%v stderr=%q", err, stderr)
+	}
+
+	if _, stderr, err := runCLI("import", ts.URL+"/missing"); err == nil || !strings.Contains(stderr, "404") {
+		t.Errorf("expected a 404 error, got err=%v stderr=%q", err, stderr)
+	}
+
+	// Collections from other apps
+	mealie := filepath.Join(dir, "mealie.json")
+	if err := os.WriteFile(mealie, []byte(`[{"name": "Toast", "recipeIngredient": [{"note": "1 slice bread"}], "recipeInstructions": [{"text": "Toast the bread."}]},
+		{"name": "Tea", "recipeIngredient": [{"quantity": 1, "food": {"name": "tea bag"}}], "recipeInstructions": [{"text": "Steep the tea bag for 3 minutes."}]}]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, stderr, err := runCLI("import", "--from", "mealie", mealie); err == nil || !strings.Contains(stderr, "use --out") {
+		t.Errorf("expected an error asking for --out, got err=%v stderr=%q", err, stderr)
+	}
+	out := filepath.Join(dir, "mealie")
+	if _, stderr, err := runCLI("import", "--from", "mealie", mealie, "--out", out); err != nil {
+		t.Fatalf("import --from mealie failed: %v\nstderr: %s", err, stderr)
+	}
+	for _, name := range []string{"Toast.cook", "Tea.cook"} {
+		if _, err := os.Stat(filepath.Join(out, name)); err != nil {
+			t.Errorf("expected %s to be imported: %v", name, err)
+		}
+	}
+	if _, stderr, err := runCLI("import", "--from", "evernote", mealie); err == nil || !strings.Contains(stderr, "unknown import format") {
+		t.Errorf("expected an unknown format error, got err=%v stderr=%q", err, stderr)
 	}
 }
 
-func TestCLI_ShoppingListServingsScaleMutualExclusion(t *testing.T) {
-	negroniPath := getExampleRecipePath("Negroni.cook")
+func TestCLI_Search(t *testing.T) {
+	dir := filepath.Join("..", "..", "example_recipes")
+	indexFile := filepath.Join(t.TempDir(), "index.json")
 
-	// Test that --servings and --scale together produce an error
-	_, _, err := runCLI("shopping-list", negroniPath, "--servings", "4", "--scale", "2.0")
-	if err == nil {
-		t.Error("expected error when using both --servings and --scale, got none")
+	for i := 0; i < 2; i++ {
+		stdout, stderr, err := runCLI("search", "i:campary", "tag:gin", "--dir", dir, "--index", indexFile)
+		if err != nil {
+			t.Fatalf("search command failed: %v\nstderr: %s", err, stderr)
+		}
+		if strings.TrimSpace(stdout) != "Negroni (Negroni.cook)" {
+			t.Errorf("run %d: unexpected search output: %q", i+1, stdout)
+		}
+	}
+	if _, err := os.Stat(indexFile); err != nil {
+		t.Errorf("expected search index to be saved: %v", err)
+	}
+
+	_, stderr, err := runCLI("search", "colour:red", "--dir", dir)
+	if err == nil || !strings.Contains(stderr, "unknown search qualifier") {
+		t.Errorf("expected unknown qualifier error, got err=%v stderr=%q", err, stderr)
 	}
 }
 
-func TestCLI_CanonicalMode(t *testing.T) {
+func TestCLI_Render_Voice(t *testing.T) {
 	recipePath := getExampleRecipePath("Negroni.cook")
 
-	// Test that --canonical flag is accepted
-	_, stderr, err := runCLI("parse", recipePath, "--canonical")
+	stdout, stderr, err := runCLI("render", recipePath, "--format", "voice")
 	if err != nil {
-		t.Fatalf("parse with --canonical failed: %v\nstderr: %s", err, stderr)
+		t.Fatalf("render voice command failed: %v\nstderr: %s", err, stderr)
 	}
-}
 
-func TestCLI_InvalidFile(t *testing.T) {
-	_, _, err := runCLI("parse", "nonexistent.cook")
-	if err == nil {
-		t.Error("expected error for nonexistent file, got none")
+	var voice map[string]interface{}
+	if err := json.Unmarshal([]byte(stdout), &voice); err != nil {
+		t.Fatalf("voice output is not valid JSON: %v\n%s", err, stdout)
+	}
+	if voice["name"] != "Negroni" {
+		t.Errorf("expected name Negroni, got %v", voice["name"])
+	}
+	if voice["total_steps"] != float64(3) {
+		t.Errorf("expected 3 steps, got %v", voice["total_steps"])
 	}
-}
 
-func TestCLI_NoArgs(t *testing.T) {
+	stdout, stderr, err = runCLI("render", recipePath, "--format", "ssml")
+	if err != nil {
+		t.Fatalf("render ssml command failed: %v\nstderr: %s", err, stderr)
+	}
+	if !strings.HasPrefix(stdout, "<speak>\n<p>Let&apos;s make Negroni.") || !strings.Contains(stdout, "<p>Step 3. ") {
+		t.Errorf("unexpected SSML:\n%s", stdout)
+	}
+}
+
+func TestCLI_Render_Mermaid(t *testing.T) {
+	recipePath := getExampleRecipePath("Negroni.cook")
+
+	stdout, stderr, err := runCLI("render", recipePath, "--format", "mermaid")
+	if err != nil {
+		t.Fatalf("render mermaid command failed: %v\nstderr: %s", err, stderr)
+	}
+	if !strings.HasPrefix(stdout, "```mermaid\nflowchart TD\n") || !strings.HasSuffix(strings.TrimSpace(stdout), "```") {
+		t.Errorf("expected a mermaid code block, got:\n%s", stdout)
+	}
+	for _, expected := range []string{`ingredient1(["50 ml gin"]) --> step1`, `cookware1{{"rocks glass"}} -.- step1`, "step2 --> step3"} {
+		if !strings.Contains(stdout, expected) {
+			t.Errorf("expected %q in:\n%s", expected, stdout)
+		}
+	}
+}
+
+func TestCLI_Render_Docx(t *testing.T) {
+	recipePath := getExampleRecipePath("Negroni.cook")
+	output := filepath.Join(t.TempDir(), "negroni.docx")
+
+	_, stderr, err := runCLI("render", recipePath, "--format", "docx", "-o", output)
+	if err != nil {
+		t.Fatalf("render docx command failed: %v\nstderr: %s", err, stderr)
+	}
+	archive, err := zip.OpenReader(output)
+	if err != nil {
+		t.Fatalf("expected a .docx (ZIP) file: %v", err)
+	}
+	defer archive.Close()
+	r, err := archive.Open("word/document.xml")
+	if err != nil {
+		t.Fatalf("expected word/document.xml: %v", err)
+	}
+	defer r.Close()
+	document, _ := io.ReadAll(r)
+	for _, expected := range []string{">Negroni</w:t>", ">gin</w:t>", `<w:numId w:val="2"/>`} {
+		if !strings.Contains(string(document), expected) {
+			t.Errorf("expected %q in:\n%s", expected, document)
+		}
+	}
+}
+
+func TestCLI_Render_Flavor(t *testing.T) {
+	recipePath := getExampleRecipePath("Negroni.cook")
+
+	stdout, stderr, err := runCLI("render", recipePath, "--format", "markdown", "--flavor", "confluence")
+	if err != nil {
+		t.Fatalf("render confluence command failed: %v\nstderr: %s", err, stderr)
+	}
+	if !strings.HasPrefix(stdout, "h1. Negroni\n") || !strings.Contains(stdout, "h2. Ingredients") {
+		t.Errorf("expected Confluence wiki markup, got:\n%s", stdout)
+	}
+
+	stdout, stderr, err = runCLI("render", recipePath, "--flavor", "notion")
+	if err != nil {
+		t.Fatalf("render notion command failed: %v\nstderr: %s", err, stderr)
+	}
+	if !strings.Contains(stdout, "# Negroni") || !strings.Contains(stdout, "|---|") {
+		t.Errorf("expected the recipe information as a table, got:\n%s", stdout)
+	}
+
+	if _, stderr, err := runCLI("render", recipePath, "--format", "html", "--flavor", "notion"); err == nil || !strings.Contains(stderr, "only applies to the markdown format") {
+		t.Errorf("expected --flavor to be rejected for html, got err=%v stderr=%s", err, stderr)
+	}
+	if _, stderr, err := runCLI("render", recipePath, "--flavor", "wiki"); err == nil || !strings.Contains(stderr, "unknown Markdown flavor") {
+		t.Errorf("expected an unknown flavor error, got err=%v stderr=%s", err, stderr)
+	}
+}
+
+func TestCLI_Render_Email(t *testing.T) {
+	recipePath := getExampleRecipePath("Negroni.cook")
+
+	stdout, stderr, err := runCLI("render", recipePath, "--email", "--image-base-url", "https://example.com/recipes/")
+	if err != nil {
+		t.Fatalf("render email command failed: %v\nstderr: %s", err, stderr)
+	}
+	if !strings.Contains(stdout, "max-width: 600px") || !strings.Contains(stdout, ">Negroni</h1>") {
+		t.Errorf("expected an email layout, got:\n%s", stdout)
+	}
+	if strings.Contains(stdout, "<style") || strings.Count(stdout, "<html") != 1 {
+		t.Errorf("expected one document without style blocks, got:\n%s", stdout)
+	}
+
+	if _, stderr, err := runCLI("render", recipePath, "--email", "--format", "print"); err == nil || !strings.Contains(stderr, "only applies to the html format") {
+		t.Errorf("expected --email to be rejected for print, got err=%v stderr=%s", err, stderr)
+	}
+	if _, stderr, err := runCLI("render", recipePath, "--email", "--image-base-url", "images/"); err == nil || !strings.Contains(stderr, "absolute URL") {
+		t.Errorf("expected a relative base URL to be rejected, got err=%v stderr=%s", err, stderr)
+	}
+}
+
+func TestCLI_Render_HTML(t *testing.T) {
+	recipePath := getExampleRecipePath("Negroni.cook")
+
+	stdout, stderr, err := runCLI("render", recipePath, "--format", "html")
+	if err != nil {
+		t.Fatalf("render html command failed: %v\nstderr: %s", err, stderr)
+	}
+
+	// Check for complete HTML document structure
+	expectedStrings := []string{
+		"<!DOCTYPE html>",
+		"<html lang=\"en\">",
+		"<meta charset=\"UTF-8\">",
+		"<title>Negroni</title>",
+		"</head>",
+		"<body>",
+		"<div class=\"recipe\">",
+		"</body>",
+		"</html>",
+	}
+	for _, expected := range expectedStrings {
+		if !strings.Contains(stdout, expected) {
+			t.Errorf("HTML output missing %q", expected)
+		}
+	}
+}
+
+func TestCLI_Render_Images(t *testing.T) {
+	dir := t.TempDir()
+	recipePath := filepath.Join(dir, "recipes", "Toast.cook")
+	if err := os.MkdirAll(filepath.Dir(recipePath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(recipePath, []byte("Toast @bread{2%slices}."), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "recipes", "Toast.jpg"), []byte("\xff\xd8\xff\xe0"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	output := filepath.Join(dir, "out", "toast.html")
+
+	for mode, expected := range map[string]string{
+		"link":  `src="../recipes/Toast.jpg"`,
+		"embed": `src="data:image/jpeg;base64,/9j/4A=="`,
+		"copy":  `src="toast.jpg"`,
+	} {
+		if _, stderr, err := runCLI("render", recipePath, "-f", "print", "-o", output, "--images", mode); err != nil {
+			t.Fatalf("render --images %s failed: %v\nstderr: %s", mode, err, stderr)
+		}
+		content, _ := os.ReadFile(output)
+		if !strings.Contains(string(content), expected) {
+			t.Errorf("--images %s: expected %s in output", mode, expected)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "out", "toast.jpg")); err != nil {
+		t.Errorf("expected the image to be copied: %v", err)
+	}
+
+	if _, _, err := runCLI("render", recipePath, "-f", "html", "--images", "copy"); err == nil {
+		t.Error("expected an error when copying images without --output")
+	}
+}
+
+func TestCLI_Render_Locale(t *testing.T) {
+	recipePath := getExampleRecipePath("Negroni.cook")
+
+	stdout, stderr, err := runCLI("render", recipePath, "--format", "html", "--locale", "de")
+	if err != nil {
+		t.Fatalf("render --locale failed: %v\nstderr: %s", err, stderr)
+	}
+	for _, expected := range []string{"<html lang=\"de\">", "<h2>Zutaten</h2>", "<h2>Zubereitung</h2>"} {
+		if !strings.Contains(stdout, expected) {
+			t.Errorf("localized HTML output missing %q", expected)
+		}
+	}
+
+	stdout, stderr, err = runCLI("render", recipePath, "--locale", "tlh")
+	if err != nil {
+		t.Fatalf("render with an unknown locale failed: %v\nstderr: %s", err, stderr)
+	}
+	if !strings.Contains(stdout, "## Ingredients") || !strings.Contains(stderr, "No translation") {
+		t.Errorf("expected English labels and a warning for an unknown locale, got stderr %q", stderr)
+	}
+}
+
+func TestCLI_Render_Temperature(t *testing.T) {
+	dir := t.TempDir()
+	recipePath := filepath.Join(dir, "Bread.cook")
+	if err := os.WriteFile(recipePath, []byte("Bake the @dough{} at 220°C.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	stdout, stderr, err := runCLI("render", recipePath, "--temperature", "fahrenheit")
+	if err != nil {
+		t.Fatalf("render --temperature failed: %v\nstderr: %s", err, stderr)
+	}
+	if !strings.Contains(stdout, "at 430°F.") {
+		t.Errorf("expected the temperature in Fahrenheit, got:\n%s", stdout)
+	}
+
+	stdout, _, err = runCLI("render", recipePath, "--transform", "units=us", "--format", "cooklang")
+	if err != nil || !strings.Contains(stdout, "at 430°F.") {
+		t.Errorf("expected units=us to convert temperatures, got:\n%s (%v)", stdout, err)
+	}
+
+	stdout, _, err = runCLI("render", recipePath, "--temperature", "both")
+	if err != nil || !strings.Contains(stdout, "at 220°C / 430°F.") {
+		t.Errorf("expected both temperature scales, got:\n%s (%v)", stdout, err)
+	}
+
+	if _, _, err := runCLI("render", recipePath, "--temperature", "kelvin"); err == nil {
+		t.Error("expected an error for an unknown temperature scale")
+	}
+}
+
+func TestCLI_Render_Cooklang(t *testing.T) {
+	recipePath := getExampleRecipePath("Negroni.cook")
+
+	stdout, stderr, err := runCLI("render", recipePath, "--format", "cooklang")
+	if err != nil {
+		t.Fatalf("render cooklang command failed: %v\nstderr: %s", err, stderr)
+	}
+
+	// Check for cooklang syntax elements
+	if !strings.Contains(stdout, "@") {
+		t.Errorf("cooklang output missing ingredient markers (@)")
+	}
+}
+
+func TestCLI_Scale(t *testing.T) {
+	recipePath := getExampleRecipePath("Negroni.cook")
+
+	stdout, stderr, err := runCLI("scale", recipePath, "--servings", "2")
+	if err != nil {
+		t.Fatalf("scale command failed: %v\nstderr: %s", err, stderr)
+	}
+
+	// Scaled recipe should have doubled quantities (50ml -> 100ml)
+	if !strings.Contains(stdout, "100") {
+		t.Errorf("scaled output doesn't show doubled quantities")
+	}
+}
+
+func TestCLI_Scale_HTML(t *testing.T) {
+	recipePath := getExampleRecipePath("Negroni.cook")
+
+	stdout, stderr, err := runCLI("scale", recipePath, "--servings", "2", "--format", "html")
+	if err != nil {
+		t.Fatalf("scale html command failed: %v\nstderr: %s", err, stderr)
+	}
+
+	// Check for complete HTML document structure
+	expectedStrings := []string{
+		"<!DOCTYPE html>",
+		"<html lang=\"en\">",
+		"<meta charset=\"UTF-8\">",
+		"<title>Negroni</title>",
+		"</body>",
+		"</html>",
+	}
+	for _, expected := range expectedStrings {
+		if !strings.Contains(stdout, expected) {
+			t.Errorf("scale HTML output missing %q", expected)
+		}
+	}
+
+	// Check that scaling worked (100ml instead of 50ml)
+	if !strings.Contains(stdout, "100") {
+		t.Errorf("scaled HTML output doesn't show doubled quantities")
+	}
+}
+
+func TestCLI_ShoppingList(t *testing.T) {
+	negroniPath := getExampleRecipePath("Negroni.cook")
+	alaskaPath := getExampleRecipePath("Alaska.cook")
+
+	stdout, stderr, err := runCLI("shopping-list", negroniPath, alaskaPath)
+	if err != nil {
+		t.Fatalf("shopping-list command failed: %v\nstderr: %s", err, stderr)
+	}
+
+	// Shopping list should contain ingredients from both recipes
+	if !strings.Contains(stdout, "gin") {
+		t.Errorf("shopping list missing gin")
+	}
+}
+
+func TestCLI_ShoppingList_Sort(t *testing.T) {
+	negroniPath := getExampleRecipePath("Negroni.cook")
+	alaskaPath := getExampleRecipePath("Alaska.cook")
+
+	stdout, stderr, err := runCLI("shopping-list", negroniPath, alaskaPath, "--sort", "recipe")
+	if err != nil {
+		t.Fatalf("shopping-list --sort recipe failed: %v\nstderr: %s", err, stderr)
+	}
+	negroni, alaska := strings.Index(stdout, "Negroni:"), strings.Index(stdout, "Alaska:")
+	if negroni < 0 || alaska < negroni || strings.Index(stdout, "Campari") > alaska || strings.Index(stdout, "Yellow Chartreuse") < alaska {
+		t.Errorf("expected items grouped by recipe:\n%s", stdout)
+	}
+
+	// The ordered form is stable across runs
+	first, _, err := runCLI("shopping-list", negroniPath, alaskaPath, "--json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		if again, _, _ := runCLI("shopping-list", negroniPath, alaskaPath, "--json"); again != first {
+			t.Fatalf("JSON output changed between runs:\n%s\n%s", first, again)
+		}
+	}
+	var list struct {
+		Recipes []string                    `json:"recipes"`
+		Items   []cooklang.ShoppingListItem `json:"items"`
+	}
+	if err := json.Unmarshal([]byte(first), &list); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, first)
+	}
+	if len(list.Recipes) != 2 || len(list.Items) == 0 || list.Items[0].Name != "amontillado sherry" {
+		t.Errorf("unexpected JSON shopping list: %+v", list)
+	}
+
+	if _, _, err := runCLI("shopping-list", negroniPath, "--sort", "price"); err == nil {
+		t.Error("expected an error for an unknown sort order")
+	}
+}
+
+func TestCLI_ShoppingList_Export(t *testing.T) {
+	negroniPath := getExampleRecipePath("Negroni.cook")
+
+	stdout, stderr, err := runCLI("shopping-list", negroniPath, "--export", "markdown")
+	if err != nil {
+		t.Fatalf("shopping-list --export markdown failed: %v\nstderr: %s", err, stderr)
+	}
+	if !strings.HasPrefix(stdout, "# Shopping List\n\n") || !strings.Contains(stdout, "- [ ] Campari (") {
+		t.Errorf("unexpected Markdown checklist:\n%s", stdout)
+	}
+
+	csvPath := filepath.Join(t.TempDir(), "shopping.csv")
+	if _, stderr, err := runCLI("shopping-list", negroniPath, "--export", "csv", "-o", csvPath); err != nil {
+		t.Fatalf("shopping-list --export csv failed: %v\nstderr: %s", err, stderr)
+	}
+	data, err := os.ReadFile(csvPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(data), "name,quantity,unit\n") || !strings.Contains(string(data), "Campari,") {
+		t.Errorf("unexpected CSV:\n%s", data)
+	}
+
+	var body, auth string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		body, auth = string(data), r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+	if _, stderr, err := runCLI("shopping-list", negroniPath, "--export", "webhook", "--webhook-url", ts.URL,
+		"--webhook-header", "Authorization: Bearer secret"); err != nil {
+		t.Fatalf("shopping-list --export webhook failed: %v\nstderr: %s", err, stderr)
+	}
+	if auth != "Bearer secret" || !strings.Contains(body, `"name":"Campari"`) {
+		t.Errorf("unexpected webhook request: auth=%q body=%s", auth, body)
+	}
+
+	if _, _, err := runCLI("shopping-list", negroniPath, "--export", "webhook"); err == nil {
+		t.Error("expected an error for --export webhook without --webhook-url")
+	}
+	if _, _, err := runCLI("shopping-list", negroniPath, "--export", "pdf"); err == nil {
+		t.Error("expected an error for an unknown export format")
+	}
+}
+
+func TestCLI_ShoppingList_Format(t *testing.T) {
+	negroniPath := getExampleRecipePath("Negroni.cook")
+	alaskaPath := getExampleRecipePath("Alaska.cook")
+
+	stdout, stderr, err := runCLI("shopping-list", negroniPath, alaskaPath, "--format", "json")
+	if err != nil {
+		t.Fatalf("shopping-list --format json failed: %v\nstderr: %s", err, stderr)
+	}
+	var list struct {
+		Recipes []string `json:"recipes"`
+		Items   []struct {
+			Name     string `json:"name"`
+			Quantity *struct {
+				Value float64 `json:"value"`
+			} `json:"quantity"`
+			Unit     string   `json:"unit"`
+			Category string   `json:"category"`
+			Recipes  []string `json:"recipes"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &list); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, stdout)
+	}
+	if len(list.Recipes) != 2 || len(list.Items) == 0 || list.Items[0].Quantity == nil || list.Items[0].Category == "" || len(list.Items[0].Recipes) == 0 {
+		t.Errorf("unexpected JSON shopping list:\n%s", stdout)
+	}
+
+	stdout, stderr, err = runCLI("shopping-list", negroniPath, "--format", "csv")
+	if err != nil {
+		t.Fatalf("shopping-list --format csv failed: %v\nstderr: %s", err, stderr)
+	}
+	if !strings.HasPrefix(stdout, "name,quantity,unit,category,notes,recipes\n") || !strings.Contains(stdout, "Campari,") {
+		t.Errorf("unexpected CSV:\n%s", stdout)
+	}
+
+	output := filepath.Join(t.TempDir(), "shopping.txt")
+	if _, stderr, err := runCLI("shopping-list", negroniPath, "--format", "cooklang", "-o", output); err != nil {
+		t.Fatalf("shopping-list --format cooklang failed: %v\nstderr: %s", err, stderr)
+	}
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(data), "[") || !strings.Contains(string(data), "\nCampari: ") {
+		t.Errorf("unexpected Cooklang shopping list:\n%s", data)
+	}
+
+	for _, args := range [][]string{
+		{"--format", "xml"},
+		{"--format", "csv", "--export", "csv"},
+		{"--format", "json", "--json"},
+		{"--format", "json", "--sort", "recipe"},
+	} {
+		if _, _, err := runCLI(append([]string{"shopping-list", negroniPath}, args...)...); err == nil {
+			t.Errorf("expected an error for %v", args)
+		}
+	}
+}
+
+func TestCLI_ShoppingListServings(t *testing.T) {
+	negroniPath := getExampleRecipePath("Negroni.cook")
+
+	// Test --servings flag
+	stdout, stderr, err := runCLI("shopping-list", negroniPath, "--servings", "4")
+	if err != nil {
+		t.Fatalf("shopping-list --servings failed: %v\nstderr: %s", err, stderr)
+	}
+
+	// Output should mention scaling to servings
+	if !strings.Contains(stdout, "4 servings") {
+		t.Errorf("expected '4 servings' in output, got: %s", stdout)
+	}
+}
+
+func TestCLI_ShoppingListServingsScaleMutualExclusion(t *testing.T) {
+	negroniPath := getExampleRecipePath("Negroni.cook")
+
+	// Test that --servings and --scale together produce an error
+	_, _, err := runCLI("shopping-list", negroniPath, "--servings", "4", "--scale", "2.0")
+	if err == nil {
+		t.Error("expected error when using both --servings and --scale, got none")
+	}
+}
+
+func TestCLI_CanonicalMode(t *testing.T) {
+	recipePath := getExampleRecipePath("Negroni.cook")
+
+	// Test that --canonical flag is accepted
+	_, stderr, err := runCLI("parse", recipePath, "--canonical")
+	if err != nil {
+		t.Fatalf("parse with --canonical failed: %v\nstderr: %s", err, stderr)
+	}
+}
+
+func TestCLI_InvalidFile(t *testing.T) {
+	_, _, err := runCLI("parse", "nonexistent.cook")
+	if err == nil {
+		t.Error("expected error for nonexistent file, got none")
+	}
+}
+
+func TestCLI_NoArgs(t *testing.T) {
 	// Running without subcommand should show help
 	stdout, _, _ := runCLI()
 	if !strings.Contains(stdout, "cook") {
 		t.Errorf("expected help output when no args provided")
 	}
 }
+
+func TestCLI_Render_Transform(t *testing.T) {
+	recipePath := getExampleRecipePath("Negroni.cook")
+
+	stdout, stderr, err := runCLI("render", recipePath, "--format", "cooklang", "--transform", "scale=2")
+	if err != nil {
+		t.Fatalf("render --transform failed: %v\nstderr: %s", err, stderr)
+	}
+	if !strings.Contains(stdout, "@gin{100%ml}") {
+		t.Errorf("expected scaled gin quantity in output, got: %s", stdout)
+	}
+
+	_, _, err = runCLI("render", recipePath, "--transform", "bogus=1")
+	if err == nil {
+		t.Error("expected error for unknown transform, got none")
+	}
+}
+
+func TestCLI_Cost(t *testing.T) {
+	dir := t.TempDir()
+	recipePath := filepath.Join(dir, "Pancakes.cook")
+	if err := os.WriteFile(recipePath, []byte("Mix @flour{500%g}, @eggs{2} and @salt{}.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	pricesPath := filepath.Join(dir, "prices.yaml")
+	if err := os.WriteFile(pricesPath, []byte("currency: EUR\nprices:\n  flour: 1.20/kg\n  eggs: 0.30\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	stdout, stderr, err := runCLI("cost", recipePath, "--prices", pricesPath)
+	if err != nil {
+		t.Fatalf("cost failed: %v\nstderr: %s", err, stderr)
+	}
+	for _, want := range []string{"flour  500 g  €0.60", "eggs   2      €0.60", "Total         €1.20"} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("expected %q in output, got:\n%s", want, stdout)
+		}
+	}
+	if !strings.Contains(stderr, "Not priced: salt") {
+		t.Errorf("expected salt to be reported as unpriced, got stderr: %s", stderr)
+	}
+
+	stdout, stderr, err = runCLI("cost", recipePath, "--prices", pricesPath, "--currency", "DKK", "--json")
+	if err != nil {
+		t.Fatalf("cost --json failed: %v\nstderr: %s", err, stderr)
+	}
+	if !strings.Contains(stdout, `"currency": "DKK"`) || !strings.Contains(stdout, `"total": 1.2`) {
+		t.Errorf("expected a JSON estimate, got:\n%s", stdout)
+	}
+}
+
+func TestCLI_Diff(t *testing.T) {
+	negroniPath := getExampleRecipePath("Negroni.cook")
+	alaskaPath := getExampleRecipePath("Alaska.cook")
+
+	stdout, stderr, err := runCLI("diff", negroniPath, alaskaPath)
+	if err != nil {
+		t.Fatalf("diff command failed: %v\nstderr: %s", err, stderr)
+	}
+	if !strings.Contains(stdout, "Ingredients:") || !strings.Contains(stdout, "- ") {
+		t.Errorf("expected ingredient changes in diff output, got: %s", stdout)
+	}
+
+	stdout, _, err = runCLI("diff", "--quiet", negroniPath, negroniPath)
+	if err != nil {
+		t.Fatalf("diff of identical recipes failed: %v", err)
+	}
+	if !strings.Contains(stdout, "No differences") {
+		t.Errorf("expected no differences on stdout even with --quiet, got: %s", stdout)
+	}
+
+	if _, _, err = runCLI("diff", "--exit-code", negroniPath, negroniPath); err != nil {
+		t.Errorf("--exit-code should succeed for identical recipes: %v", err)
+	}
+	_, stderr, err = runCLI("diff", "--exit-code", negroniPath, alaskaPath)
+	if err == nil {
+		t.Error("--exit-code should fail when the recipes differ")
+	}
+	if stderr != "" {
+		t.Errorf("--exit-code should not print an error, got: %s", stderr)
+	}
+}
+
+func TestCLI_Hash(t *testing.T) {
+	dir := t.TempDir()
+	paths := map[string]string{
+		"a.cook":           "---\ntitle: Toast\n---\nToast @bread{2%slices}.\n",
+		"reformatted.cook": "---\ntitle: Toast\nlast_cooked: 2026-10-01\n---\n\n\nToast @bread{2%slices}.\n\n",
+		"changed.cook":     "---\ntitle: Toast\n---\nToast @bread{3%slices}.\n",
+	}
+	for name, content := range paths {
+		paths[name] = filepath.Join(dir, name)
+		if err := os.WriteFile(paths[name], []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	hash := func(path string) string {
+		t.Helper()
+		stdout, stderr, err := runCLI("hash", path)
+		if err != nil {
+			t.Fatalf("hash command failed: %v\nstderr: %s", err, stderr)
+		}
+		return strings.TrimSpace(stdout)
+	}
+	want := hash(paths["a.cook"])
+	if len(want) != 64 {
+		t.Fatalf("expected a 64-digit hash, got %q", want)
+	}
+	if got := hash(paths["reformatted.cook"]); got != want {
+		t.Errorf("reformatted recipe hash = %s, want %s", got, want)
+	}
+	if got := hash(paths["changed.cook"]); got == want {
+		t.Error("changed recipe has the same hash")
+	}
+
+	stdout, _, err := runCLI("hash", paths["a.cook"], paths["changed.cook"])
+	if err != nil {
+		t.Fatalf("hash of two files failed: %v", err)
+	}
+	if !strings.HasPrefix(stdout, want+"  "+paths["a.cook"]+"\n") || strings.Count(stdout, "\n") != 2 {
+		t.Errorf("expected a hash and file name per line, got:\n%s", stdout)
+	}
+}
+
+func TestCLI_DiffJSONHasNoLinkedComponents(t *testing.T) {
+	dir := t.TempDir()
+	oldPath := filepath.Join(dir, "old.cook")
+	newPath := filepath.Join(dir, "new.cook")
+	if err := os.WriteFile(oldPath, []byte("Mix @flour{200%g} with water in a #bowl{}.\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(newPath, []byte("Mix @flour{300%g} with water in a #bowl{}.\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	stdout, stderr, err := runCLI("diff", "--json", oldPath, newPath)
+	if err != nil {
+		t.Fatalf("diff --json failed: %v\nstderr: %s", err, stderr)
+	}
+	if strings.Contains(stdout, "next_component") {
+		t.Errorf("diff JSON should not include the rest of the step, got: %s", stdout)
+	}
+}
+
+func TestCLI_DiagnosticsOnStderr(t *testing.T) {
+	recipePath := getExampleRecipePath("Negroni.cook")
+
+	stdout, stderr, err := runCLI("scale", recipePath, "--factor", "2", "--json")
+	if err != nil {
+		t.Fatalf("scale --json failed: %v\nstderr: %s", err, stderr)
+	}
+	var data map[string]interface{}
+	if err := json.Unmarshal([]byte(stdout), &data); err != nil {
+		t.Errorf("stdout is not pure JSON: %v\n%s", err, stdout)
+	}
+	if !strings.Contains(stderr, "Scaling by factor") {
+		t.Errorf("expected scaling info on stderr, got: %s", stderr)
+	}
+	if strings.Contains(stderr, "\033[") {
+		t.Errorf("expected no color codes when stderr is not a terminal, got: %q", stderr)
+	}
+
+	_, stderr, err = runCLI("scale", recipePath, "--factor", "2", "--quiet")
+	if err != nil {
+		t.Fatalf("scale --quiet failed: %v", err)
+	}
+	if stderr != "" {
+		t.Errorf("expected no diagnostics with --quiet, got: %s", stderr)
+	}
+
+	_, stderr, err = runCLI("scale", recipePath, "--factor", "2", "--verbose")
+	if err != nil {
+		t.Fatalf("scale --verbose failed: %v", err)
+	}
+	if !strings.Contains(stderr, "Parsed") {
+		t.Errorf("expected verbose parse message, got: %s", stderr)
+	}
+
+	_, stderr, err = runCLI("parse", "does-not-exist.cook")
+	if err == nil {
+		t.Fatal("expected error for missing file")
+	}
+	if !strings.Contains(stderr, "failed to read file") {
+		t.Errorf("expected error on stderr, got: %s", stderr)
+	}
+}
+
+func TestCLI_Meta(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "Toast.cook")
+	src := "---\ntitle: Toast\nauthor: Sam\n---\nToast @bread{2%slices}.\n"
+	if err := os.WriteFile(path, []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	stdout, _, err := runCLI("meta", "set", "author", "Jane", dir, "--dry-run")
+	if err != nil {
+		t.Fatalf("meta set --dry-run failed: %v", err)
+	}
+	if !strings.Contains(stdout, "author: Sam → Jane") {
+		t.Errorf("expected the change to be reported, got: %s", stdout)
+	}
+	if content, _ := os.ReadFile(path); string(content) != src {
+		t.Errorf("dry run modified the recipe:\n%s", content)
+	}
+
+	if _, stderr, err := runCLI("meta", "add-tag", "vegan", dir); err != nil {
+		t.Fatalf("meta add-tag failed: %v\nstderr: %s", err, stderr)
+	}
+	content, _ := os.ReadFile(path)
+	if !strings.Contains(string(content), "tags:\n  - vegan\n") {
+		t.Errorf("expected the vegan tag, got:\n%s", content)
+	}
+
+	if _, _, err := runCLI("meta", "set", "servings", "lots", path); err == nil {
+		t.Error("expected an error for invalid servings")
+	}
+}
+
+func TestCLI_Start(t *testing.T) {
+	cmd := exec.Command("./cook_test", "start", getExampleRecipePath("Negroni.cook"))
+	cmd.Stdin = strings.NewReader("jxn3\rq")
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("start command failed: %v\nstderr: %s", err, stderr.String())
+	}
+	output := stdout.String()
+	for _, want := range []string{"Mise en place", "> [x] 50 ml vermouth", "Step 1 of 3", "    • 50 ml Campari", "Step 3 of 3\n\n  Add orange zest for garnish."} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in output:\n%s", want, output)
+		}
+	}
+}
+
+func TestCookingSession_Timers(t *testing.T) {
+	recipe, err := cooklang.ParseString("Boil @eggs{2} for ~{6%minutes}.\n\nServe.\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	session := newCookingSession(recipe, false)
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	session.handleKey("t", start) // No timers on the checklist
+	for _, key := range []string{"n", "t", "t"} {
+		session.handleKey(key, start)
+	}
+	if len(session.timers) != 1 {
+		t.Fatalf("expected one running timer, got %d", len(session.timers))
+	}
+	if view := session.view(start.Add(90 * time.Second)); !strings.Contains(view, "Step 1: 6 minutes  4:30") {
+		t.Errorf("expected a countdown, got:\n%s", view)
+	}
+	if session.tick(start.Add(5 * time.Minute)) {
+		t.Error("timer rang early")
+	}
+	if !session.tick(start.Add(6*time.Minute)) || session.tick(start.Add(7*time.Minute)) {
+		t.Error("expected the timer to ring exactly once")
+	}
+	session.handleKey("n", start)
+	session.handleKey("n", start)
+	if session.page != 2 {
+		t.Errorf("expected to stay on the last step, got page %d", session.page)
+	}
+}
+
+func TestCLI_Completion(t *testing.T) {
+	for _, shell := range []string{"bash", "zsh", "fish", "powershell"} {
+		stdout, stderr, err := runCLI("completion", shell)
+		if err != nil || !strings.Contains(stdout, "cook") {
+			t.Errorf("completion %s failed: %v\nstderr: %s", shell, err, stderr)
+		}
+	}
+
+	binary, err := filepath.Abs("cook_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	for _, name := range []string{"Pasta.cook", "Pizza.cook", "notes.txt", "desserts/Cake.cook"} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("Mix @flour{100%g}.\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	complete := func(args ...string) []string {
+		cmd := exec.Command(binary, append([]string{"__complete"}, args...)...)
+		cmd.Dir = dir
+		out, err := cmd.Output()
+		if err != nil {
+			t.Fatalf("completing %v failed: %v", args, err)
+		}
+		lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+		return lines[:len(lines)-1] // Drop the directive
+	}
+
+	tests := []struct {
+		args []string
+		want []string
+	}{
+		{[]string{"parse", ""}, []string{"Pasta.cook", "Pizza.cook", "desserts/"}},
+		{[]string{"parse", "Pi"}, []string{"Pizza.cook"}},
+		{[]string{"parse", "desserts/"}, []string{"desserts/Cake.cook"}},
+		{[]string{"parse", "Pasta.cook", ""}, nil},
+		{[]string{"diff", "Pasta.cook", ""}, []string{"Pizza.cook", "desserts/"}},
+		{[]string{"meta", "add-tag", ""}, nil},
+		{[]string{"scale", "--format", ""}, []string{"cooklang\tOriginal Cooklang format", "markdown\tMarkdown format", "html\tHTML format", "json\tJSON format"}},
+		{[]string{"import", "--from", ""}, []string{"web", "paprika", "mealie", "nextcloud"}},
+	}
+	for _, tt := range tests {
+		got := complete(tt.args...)
+		if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+			t.Errorf("completing %q: got %q, want %q", tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestCLI_New(t *testing.T) {
+	binary, err := filepath.Abs("cook_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	templates := filepath.Join(dir, "config", "cook", "templates")
+	if err := os.MkdirAll(templates, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(templates, "weeknight.cook"), []byte("-- Quick dinner\nCook {{.Title}} in a #wok{}.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	run := func(args ...string) (string, string, error) {
+		cmd := exec.Command(binary, append([]string{"new"}, args...)...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "XDG_CONFIG_HOME="+filepath.Join(dir, "config"))
+		var stdout, stderr bytes.Buffer
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+		err := cmd.Run()
+		return stdout.String(), stderr.String(), err
+	}
+
+	if _, stderr, err := run("Onion Soup", "--servings", "4", "--tags", "soup,french", "--meta", "cuisine=French"); err != nil {
+		t.Fatalf("new failed: %v\nstderr: %s", err, stderr)
+	}
+	recipe, err := cooklang.ParseFile(filepath.Join(dir, "Onion Soup.cook"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if recipe.Title != "Onion Soup" || recipe.Servings != 4 || recipe.Cuisine != "French" || len(recipe.Tags) != 2 {
+		t.Errorf("unexpected recipe metadata: %+v", recipe)
+	}
+	if _, stderr, err := run("Onion Soup"); err == nil || !strings.Contains(stderr, "already exists") {
+		t.Errorf("expected an error for an existing file, got err=%v stderr=%q", err, stderr)
+	}
+
+	stdout, stderr, err := run("Stir Fry", "--template", "weeknight", "--output", "-")
+	if err != nil {
+		t.Fatalf("new with a user template failed: %v\nstderr: %s", err, stderr)
+	}
+	if stdout != "---\ntitle: Stir Fry\n---\nCook Stir Fry in a #wok{}.\n" {
+		t.Errorf("unexpected output:\n%s", stdout)
+	}
+
+	stdout, _, err = run("--list-templates")
+	if err != nil || !strings.Contains(stdout, "cocktail") || !strings.Contains(stdout, "weeknight    Quick dinner") {
+		t.Errorf("unexpected template list (err=%v):\n%s", err, stdout)
+	}
+	if _, stderr, err := run(); err == nil || !strings.Contains(stderr, "title is required") {
+		t.Errorf("expected a missing title error, got err=%v stderr=%q", err, stderr)
+	}
+	if _, stderr, err := run("X", "--template", "nope"); err == nil || !strings.Contains(stderr, "unknown template") {
+		t.Errorf("expected an unknown template error, got err=%v stderr=%q", err, stderr)
+	}
+}
+
+func TestCLI_Config(t *testing.T) {
+	binary, err := filepath.Abs("cook_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	recipePath := filepath.Join(dir, "recipe.cook")
+	if err := os.WriteFile(recipePath, []byte("Mix @flour{1%kg} with @milk{1%l} and @potatoes{3}.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "aisle.conf"), []byte("[cold stuff]\nmilk\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	run := func(env []string, args ...string) (string, string, error) {
+		cmd := exec.Command(binary, args...)
+		cmd.Dir = dir
+		cmd.Env = append(append(os.Environ(), "XDG_CONFIG_HOME="+filepath.Join(dir, "config")), env...)
+		var stdout, stderr bytes.Buffer
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+		err := cmd.Run()
+		return stdout.String(), stderr.String(), err
+	}
+
+	for _, args := range [][]string{{"units", "imperial"}, {"format", "html"}, {"aisle", filepath.Join(dir, "aisle.conf")}} {
+		if _, stderr, err := run(nil, append([]string{"config", "set"}, args...)...); err != nil {
+			t.Fatalf("config set %v failed: %v\nstderr: %s", args, err, stderr)
+		}
+	}
+	if _, stderr, err := run(nil, "config", "set", "units", "furlongs"); err == nil || !strings.Contains(stderr, "unknown unit system") {
+		t.Errorf("expected an invalid units error, got err=%v stderr=%q", err, stderr)
+	}
+	if stdout, _, err := run(nil, "config", "get", "units"); err != nil || stdout != "imperial\n" {
+		t.Errorf("config get units = %q, %v", stdout, err)
+	}
+	if stdout, _, err := run([]string{"COOK_UNITS=us"}, "config", "list"); err != nil || !strings.Contains(stdout, "units: us  # from COOK_UNITS") {
+		t.Errorf("unexpected config list (err=%v):\n%s", err, stdout)
+	}
+
+	// Config < env < flags
+	stdout, stderr, err := run(nil, "ingredients", recipePath)
+	if err != nil {
+		t.Fatalf("ingredients failed: %v\nstderr: %s", err, stderr)
+	}
+	if !strings.Contains(stdout, "oz flour") {
+		t.Errorf("expected imperial units from the config, got:\n%s", stdout)
+	}
+	stdout, _, _ = run([]string{"COOK_UNITS=metric"}, "ingredients", recipePath)
+	if !strings.Contains(stdout, "kg") {
+		t.Errorf("expected metric units from COOK_UNITS, got:\n%s", stdout)
+	}
+	stdout, _, _ = run([]string{"COOK_UNITS=metric"}, "ingredients", recipePath, "--unit", "us")
+	if !strings.Contains(stdout, "qt milk") {
+		t.Errorf("expected US units from --unit, got:\n%s", stdout)
+	}
+	if stdout, _, _ := run(nil, "render", recipePath); !strings.Contains(stdout, "<") {
+		t.Errorf("expected HTML from the configured format, got:\n%s", stdout)
+	}
+	if stdout, _, _ := run(nil, "shopping-list", recipePath); !strings.Contains(stdout, "cold stuff:\n  ☐ milk") {
+		t.Errorf("expected the configured aisle, got:\n%s", stdout)
+	}
+
+	if _, stderr, err := run(nil, "config", "unset", "format"); err != nil {
+		t.Fatalf("config unset failed: %v\nstderr: %s", err, stderr)
+	}
+	if stdout, _, _ := run(nil, "render", recipePath); strings.Contains(stdout, "<html") {
+		t.Errorf("expected Markdown after unsetting the format, got:\n%s", stdout)
+	}
+	if stdout, _, err := run(nil, "config", "path"); err != nil || strings.TrimSpace(stdout) != filepath.Join(dir, "config", "cook", "config.yaml") {
+		t.Errorf("config path = %q, %v", stdout, err)
+	}
+}
+
+func TestChangedRecipes(t *testing.T) {
+	now := time.Now()
+	before := map[string]fileState{
+		"r/Negroni.cook":   {size: 10, modTime: now},
+		"r/Negroni.jpg":    {size: 100, modTime: now},
+		"r/Spritz.cook":    {size: 10, modTime: now},
+		"r/Spritz-1.png":   {size: 100, modTime: now},
+		"r/Sour.cook":      {size: 10, modTime: now},
+		"r/other/Sour.jpg": {size: 100, modTime: now},
+	}
+	after := map[string]fileState{
+		"r/Negroni.cook":   {size: 10, modTime: now},
+		"r/Negroni.jpg":    {size: 200, modTime: now.Add(time.Second)},
+		"r/Spritz.cook":    {size: 10, modTime: now},
+		"r/Sour.cook":      {size: 12, modTime: now.Add(time.Second)},
+		"r/Mule.cook":      {size: 10, modTime: now},
+		"r/other/Sour.jpg": {size: 200, modTime: now.Add(time.Second)},
+	}
+	got := strings.Join(changedRecipes(before, after), ",")
+	if expected := "r/Mule.cook,r/Negroni.cook,r/Sour.cook,r/Spritz.cook"; got != expected {
+		t.Errorf("changedRecipes = %s, want %s", got, expected)
+	}
+	if got := changedRecipes(after, after); len(got) != 0 {
+		t.Errorf("expected no changes, got %v", got)
+	}
+}
+
+func TestCLI_RenderDirectoryWatch(t *testing.T) {
+	dir := t.TempDir()
+	recipes := filepath.Join(dir, "recipes")
+	site := filepath.Join(dir, "site")
+	if err := os.MkdirAll(filepath.Join(recipes, "drinks"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(recipes, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("Soup.cook", "Boil @water{1%l}.\n")
+	write(filepath.Join("drinks", "Negroni.cook"), "Stir @gin{30%ml} with @ice{}.\n")
+
+	if _, stderr, err := runCLI("render", recipes, "-f", "markdown"); err == nil || !strings.Contains(stderr, "--output") {
+		t.Errorf("expected an error without --output, got err=%v stderr=%q", err, stderr)
+	}
+
+	cmd := exec.Command("./cook_test", "render", recipes, "-f", "markdown", "-o", site, "--watch")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	}()
+
+	waitFor := func(name, text string) {
+		t.Helper()
+		deadline := time.Now().Add(10 * time.Second)
+		for time.Now().Before(deadline) {
+			if content, err := os.ReadFile(filepath.Join(site, name)); err == nil && strings.Contains(string(content), text) {
+				return
+			}
+			time.Sleep(50 * time.Millisecond)
+		}
+		t.Fatalf("%s does not contain %q\nstderr: %s", name, text, stderr.String())
+	}
+	waitFor("soup.md", "water")
+	waitFor(filepath.Join("drinks", "negroni.md"), "gin")
+
+	write(filepath.Join("drinks", "Negroni.cook"), "Stir @gin{30%ml} and @Campari{30%ml} with @ice{}.\n")
+	waitFor(filepath.Join("drinks", "negroni.md"), "Campari")
+	write("Stew.cook", "Simmer @beans{400%g}.\n")
+	waitFor("stew.md", "beans")
+}
+
+func TestCLI_RenderDirectory(t *testing.T) {
+	dir := t.TempDir()
+	recipes := filepath.Join(dir, "recipes")
+	site := filepath.Join(dir, "public")
+	if err := os.MkdirAll(filepath.Join(recipes, "Main Courses"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"Soup.cook":                   "---\ntitle: Tomato Soup\n---\nBoil @tomatoes{4}.\n",
+		"Soup.jpg":                    "jpeg",
+		"Main Courses/Beef Stew.cook": "Simmer @beef{500%g}.\n",
+		"Broken.cook":                 "---\nimages: missing.jpg\n---\nStir.\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(recipes, filepath.FromSlash(name)), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	_, stderr, err := runCLI("render", recipes, "--format", "html", "--out", site, "--jobs", "2")
+	if err == nil || !strings.Contains(stderr, "1 of 3 recipes could not be rendered") {
+		t.Errorf("expected the broken recipe to fail, got err=%v, stderr: %s", err, stderr)
+	}
+	for _, name := range []string{"soup.html", "soup.jpg", "main-courses/beef-stew.html"} {
+		if _, err := os.Stat(filepath.Join(site, filepath.FromSlash(name))); err != nil {
+			t.Errorf("expected %s to be written: %v", name, err)
+		}
+	}
+	index, err := os.ReadFile(filepath.Join(site, "index.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(index), `<li><a href="main-courses/beef-stew.html">Beef Stew</a></li>
+  <li><a href="soup.html">Tomato Soup</a></li>`) {
+		t.Errorf("unexpected index:\n%s", index)
+	}
+}
+
+func TestCLI_GroupIngredients(t *testing.T) {
+	recipePath := filepath.Join(t.TempDir(), "Pancakes.cook")
+	if err := os.WriteFile(recipePath, []byte("Mix @flour{200%g} and @eggs{2}.\n\nFry in @butter{1%tbsp}.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	stdout, stderr, err := runCLI("render", recipePath, "--group-ingredients", "step")
+	if err != nil {
+		t.Fatalf("render failed: %v\nstderr: %s", err, stderr)
+	}
+	if !strings.Contains(stdout, "### Step 1\n\n- **200 g** flour\n- **2** eggs\n\n### Step 2\n\n- **1 tbsp** butter\n") {
+		t.Errorf("expected ingredients grouped by step, got:\n%s", stdout)
+	}
+
+	_, stderr, err = runCLI("render", recipePath, "--group-ingredients", "aisle")
+	if err == nil || !strings.Contains(stderr, "unknown ingredient grouping") {
+		t.Errorf("expected an error for an unknown grouping, got err=%v, stderr: %s", err, stderr)
+	}
+}
+
+func TestCLI_RenderTemplate(t *testing.T) {
+	dir := t.TempDir()
+	recipePath := filepath.Join(dir, "Toast.cook")
+	if err := os.WriteFile(recipePath, []byte("---\ntitle: Toast\n---\nToast the @bread{2%slices}.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	theme := filepath.Join(dir, "theme")
+	if err := os.Mkdir(theme, 0755); err != nil {
+		t.Fatal(err)
+	}
+	template := `<title>{{.Title}}</title>{{range .Ingredients}}<li>{{.Amount}} {{.Unit}} {{.Name}}</li>{{end}}`
+	if err := os.WriteFile(filepath.Join(theme, "recipe.html"), []byte(template), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	stdout, stderr, err := runCLI("render", recipePath, "--format", "html", "--template", theme)
+	if err != nil {
+		t.Fatalf("render failed: %v\nstderr: %s", err, stderr)
+	}
+	if strings.TrimSpace(stdout) != "<title>Toast</title><li>2 slices bread</li>" {
+		t.Errorf("expected the template output, got:\n%s", stdout)
+	}
+
+	_, stderr, err = runCLI("render", recipePath, "--format", "print", "--template", theme)
+	if err == nil || !strings.Contains(stderr, "has no print.html template") {
+		t.Errorf("expected an error for the missing print template, got err=%v, stderr: %s", err, stderr)
+	}
+	_, stderr, err = runCLI("render", recipePath, "--format", "markdown", "--template", theme)
+	if err == nil || !strings.Contains(stderr, "only applies to the html and print formats") {
+		t.Errorf("expected an error for markdown, got err=%v, stderr: %s", err, stderr)
+	}
+}
+
+func TestCLI_RenderQRCode(t *testing.T) {
+	recipePath := getExampleRecipePath("Negroni.cook")
+
+	stdout, stderr, err := runCLI("render", recipePath, "--format", "print", "--qr-url", "https://example.com/recipes/negroni")
+	if err != nil {
+		t.Fatalf("render failed: %v\nstderr: %s", err, stderr)
+	}
+	if !strings.Contains(stdout, `<div class="recipe-qr"><svg`) || !strings.Contains(stdout, "https://example.com/recipes/negroni</span>") {
+		t.Errorf("expected a QR code with the URL, got:\n%s", stdout)
+	}
+
+	_, stderr, err = runCLI("render", recipePath, "--format", "markdown", "--qr-url", "https://example.com/recipes/negroni")
+	if err == nil || !strings.Contains(stderr, "only applies to the print format") {
+		t.Errorf("expected an error for markdown, got err=%v, stderr: %s", err, stderr)
+	}
+}
+
+func TestCLI_RenderQuantityNotes(t *testing.T) {
+	recipePath := filepath.Join(t.TempDir(), "Bread.cook")
+	if err := os.WriteFile(recipePath, []byte("---\nservings: 2\n---\nMix @flour{200%g} and @water{150%ml}.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	stdout, stderr, err := runCLI("render", recipePath, "--transform", "servings=4", "--quantity-notes", "original")
+	if err != nil {
+		t.Fatalf("render failed: %v\nstderr: %s", err, stderr)
+	}
+	if !strings.Contains(stdout, "**400 g** flour (200 g for 2 servings)") {
+		t.Errorf("expected the original quantity, got:\n%s", stdout)
+	}
+
+	_, stderr, err = runCLI("render", recipePath, "--quantity-notes", "per-portion")
+	if err == nil || !strings.Contains(stderr, "unknown quantity notes") {
+		t.Errorf("expected an error for an unknown note, got err=%v, stderr: %s", err, stderr)
+	}
+}
+
+func TestCLI_UnicodeFractions(t *testing.T) {
+	recipePath := filepath.Join(t.TempDir(), "Custard.cook")
+	if err := os.WriteFile(recipePath, []byte("Whisk @milk{1.5%cups} with @sugar{0.75%cup}.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	stdout, stderr, err := runCLI("render", recipePath, "--unicode-fractions")
+	if err != nil {
+		t.Fatalf("render failed: %v\nstderr: %s", err, stderr)
+	}
+	if !strings.Contains(stdout, "**1½ cups** milk") {
+		t.Errorf("expected Unicode fractions, got:\n%s", stdout)
+	}
+	stdout, stderr, err = runCLI("shopping-list", recipePath, "--simple", "--unicode-fractions")
+	if err != nil {
+		t.Fatalf("shopping-list failed: %v\nstderr: %s", err, stderr)
+	}
+	if !strings.Contains(stdout, "sugar: ¾ cup") {
+		t.Errorf("expected Unicode fractions, got:\n%s", stdout)
+	}
+}
+
+func TestCLI_RenameFromTitle(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"pizza2.cook":    "---\ntitle: Neapolitan Pizza\n---\nKnead @flour{500%g}.\n\nBake.\n",
+		"pizza2.jpg":     "jpeg",
+		"pizza2-1.png":   "png",
+		"pizza2.1.jpg":   "jpeg",
+		"pizza22.jpg":    "another recipe's image",
+		"soup.cook":      "Boil @water{1%l}.\n",
+		"stew.cook":      "---\ntitle: Beef Stew\n---\nSimmer @beef{500%g}.\n",
+		"Beef Stew.cook": "Someone else's stew.\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stdout, _, err := runCLI("rename", "--from-title", filepath.Join(dir, "pizza2.cook"), "--dry-run")
+	if err != nil {
+		t.Fatalf("dry run failed: %v", err)
+	}
+	if !strings.Contains(stdout, "pizza2.1.jpg → "+filepath.Join(dir, "Neapolitan Pizza.1.jpg")) {
+		t.Errorf("expected the step image in the dry run, got:\n%s", stdout)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "pizza2.cook")); err != nil {
+		t.Error("dry run renamed the recipe")
+	}
+
+	_, stderr, err := runCLI("rename", "--from-title", dir)
+	if err == nil || !strings.Contains(stderr, "already exists") {
+		t.Errorf("expected the taken name to fail, got err=%v stderr=%q", err, stderr)
+	}
+	for _, name := range []string{"Neapolitan Pizza.cook", "Neapolitan Pizza.jpg", "Neapolitan Pizza-1.png", "Neapolitan Pizza.1.jpg", "pizza22.jpg", "soup.cook", "stew.cook"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("expected %s: %v", name, err)
+		}
+	}
+
+	if _, _, err := runCLI("rename", "--from-title", "--slug", filepath.Join(dir, "Neapolitan Pizza.cook")); err != nil {
+		t.Fatalf("slug rename failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "neapolitan-pizza.1.jpg")); err != nil {
+		t.Errorf("expected the slugified step image: %v", err)
+	}
+}
+
+func TestCLI_Dedupe(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"pancakes.cook":  "Whisk @flour{200%g}, @milk{300%ml}, @eggs{2} and @sugar{1%tbsp}.\n",
+		"Pancakes2.cook": "---\ntitle: Fluffy Pancakes\n---\nWhisk @flour{1%cup}, @milk{1%cup}, @eggs{2}, @sugar{1%tbsp} and @butter{1%tbsp}, then rest.\n",
+		"soup.cook":      "Simmer @tomatoes{6}, @onion{1} and @stock{1%l}.\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stdout, _, err := runCLI("dedupe", dir, "--report")
+	if err != nil {
+		t.Fatalf("dedupe failed: %v", err)
+	}
+	for _, want := range []string{"Pancakes2.cook ~ pancakes.cook (80% same ingredients)", "Pancakes2.cook: Fluffy Pancakes", "Only in Pancakes2.cook: butter"} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("expected %q in:\n%s", want, stdout)
+		}
+	}
+	if strings.Contains(stdout, "soup") {
+		t.Errorf("soup is not a duplicate:\n%s", stdout)
+	}
+
+	stdout, _, err = runCLI("dedupe", dir, "--threshold", "0.9", "--json")
+	if err != nil {
+		t.Fatalf("dedupe --json failed: %v", err)
+	}
+	if strings.TrimSpace(stdout) != "[]" {
+		t.Errorf("expected no duplicates at 0.9, got %s", stdout)
+	}
+	if _, _, err := runCLI("dedupe", dir, "--threshold", "2"); err == nil {
+		t.Error("expected an error for a threshold above 1")
+	}
+}
+
+func TestCLI_Suggest(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"fried-rice.cook": "Fry @rice{300%g} with @onion{1}, @eggs{2} and @soy sauce{2%tbsp}.\n",
+		"pilaf.cook":      "Toast @rice{300%g} with @onions{2} and @chicken thighs{4}.\n",
+		"tacos.cook":      "Fill @tortillas{8} with @beef{500%g}.\n",
+		"pantry.conf":     "[pantry]\nrice = \"1%kg\"\n\n[fridge]\negg = \"6\"\n\"soy sauce\" = \"1%bottle\"\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stdout, _, err := runCLI("suggest", "--have", "chicken,rice,onion", dir)
+	if err != nil {
+		t.Fatalf("suggest failed: %v", err)
+	}
+	if !strings.HasPrefix(stdout, "pilaf (pilaf.cook) - 3 of 3 ingredients\n") || !strings.Contains(stdout, "  Missing: eggs, soy sauce") || strings.Contains(stdout, "tacos") {
+		t.Errorf("unexpected suggestions:\n%s", stdout)
+	}
+
+	stdout, _, err = runCLI("suggest", "--pantry", filepath.Join(dir, "pantry.conf"), "--max-missing", "1", "--json", dir)
+	if err != nil {
+		t.Fatalf("suggest --pantry failed: %v", err)
+	}
+	var items []struct {
+		Path    string   `json:"path"`
+		Missing []string `json:"missing"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &items); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, stdout)
+	}
+	if len(items) != 1 || items[0].Path != "fried-rice.cook" || strings.Join(items[0].Missing, ",") != "onion" {
+		t.Errorf("unexpected suggestions: %+v", items)
+	}
+
+	if _, stderr, err := runCLI("suggest", dir); err == nil || !strings.Contains(stderr, "--have") {
+		t.Errorf("expected an error without ingredients, got err=%v stderr=%q", err, stderr)
+	}
+}
+
+func TestCLI_Print(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"tiramisu.cook":       "---\ntitle: Tiramisu\ntags: dessert\n---\nLayer @mascarpone{250%g}.\n",
+		"cakes/brownies.cook": "---\ntitle: Brownies\ntags: Dessert, chocolate\n---\nBake @chocolate{200%g}.\n",
+		"pasta.cook":          "---\ntitle: Pasta\ntags: dinner\n---\nBoil @pasta{500%g}.\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	output := filepath.Join(t.TempDir(), "desserts.html")
+	if _, stderr, err := runCLI("print", dir, "--tag", "dessert", "--title", "Desserts", "-o", output); err != nil {
+		t.Fatalf("print failed: %v\nstderr: %s", err, stderr)
+	}
+	content, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	booklet := string(content)
+	brownies := strings.Index(booklet, "<a href=\"#recipe-brownies\">Brownies</a>")
+	tiramisu := strings.Index(booklet, "<a href=\"#recipe-tiramisu\">Tiramisu</a>")
+	if brownies < 0 || tiramisu < brownies || !strings.Contains(booklet, ">Desserts</h1>") {
+		t.Errorf("expected a Desserts booklet listing Brownies and Tiramisu, got:\n%s", booklet)
+	}
+	if strings.Contains(booklet, "Pasta") {
+		t.Errorf("expected recipes without the tag to be left out, got:\n%s", booklet)
+	}
+
+	stdout, stderr, err := runCLI("print", dir, "--chapters")
+	if err != nil {
+		t.Fatalf("print --chapters failed: %v\nstderr: %s", err, stderr)
+	}
+	if strings.Count(stdout, "class=\"booklet-chapter\"") != 2 {
+		t.Errorf("expected a dessert and a dinner chapter, got:\n%s", stdout)
+	}
+
+	if _, stderr, err := runCLI("print", dir, "--tag", "breakfast"); err == nil || !strings.Contains(stderr, "no recipes") {
+		t.Errorf("expected an error without matching recipes, got err=%v stderr=%s", err, stderr)
+	}
+}
+
+func TestCLI_Stats(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"pasta.cook":   "---\ncuisine: Italian\ntags: dinner\n---\nBoil @pasta{500%g} with @salt{} for ~{10%minutes}.\n\nToss with @tomatoes{400%g}.\n",
+		"salsa.cook":   "---\ncuisine: Mexican\ntags: dinner, quick\n---\nChop @tomatoes{3} and @lime{1}.\n",
+		"caprese.cook": "---\ncuisine: Italian\n---\nSlice @tomatoes{2} and @mozzarella{1}.\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stdout, _, err := runCLI("stats", filepath.Join(dir, "pasta.cook"))
+	if err != nil {
+		t.Fatalf("stats failed: %v", err)
+	}
+	for _, want := range []string{"Ingredients:  3 (3 different)", "Steps:        2", "Timers:       1 (10m0s)"} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("expected %q in:\n%s", want, stdout)
+		}
+	}
+
+	stdout, _, err = runCLI("stats", dir, "--top", "1")
+	if err != nil {
+		t.Fatalf("stats of a directory failed: %v", err)
+	}
+	for _, want := range []string{"Recipes: 3", "Ingredients:\n  tomatoes  3\n\nCuisines:\n  italian  2\n"} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("expected %q in:\n%s", want, stdout)
+		}
+	}
+
+	stdout, _, err = runCLI("stats", dir, "--json")
+	if err != nil {
+		t.Fatalf("stats --json failed: %v", err)
+	}
+	var stats cooklang.LibraryStats
+	if err := json.Unmarshal([]byte(stdout), &stats); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, stdout)
+	}
+	if stats.Recipes != 3 || len(stats.Tags) != 2 || stats.Tags[0] != (cooklang.StatCount{Name: "dinner", Count: 2}) {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestCLI_BulkProgressAndErrors(t *testing.T) {
+	dir := t.TempDir()
+	pasta := filepath.Join(dir, "pasta.cook")
+	if err := os.WriteFile(pasta, []byte("Boil @pasta{500%g} with @salt{1%tsp}.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	missing := filepath.Join(dir, "missing.cook")
+
+	stdout, stderr, err := runCLI("shopping-list", missing, pasta, "--progress", "--simple")
+	if err == nil {
+		t.Error("expected an error for the missing recipe")
+	}
+	if !strings.Contains(stdout, "pasta") {
+		t.Errorf("expected the list of the other recipe, got:\n%s", stdout)
+	}
+	for _, want := range []string{"[1/2] " + missing, "[2/2] " + pasta, missing + ": failed to read file", "1 of 2 files failed"} {
+		if !strings.Contains(stderr, want) {
+			t.Errorf("expected %q in stderr:\n%s", want, stderr)
+		}
+	}
+
+	_, stderr, _ = runCLI("shopping-list", missing, pasta, "--simple")
+	if strings.Contains(stderr, "[1/2]") {
+		t.Errorf("expected no progress without --progress:\n%s", stderr)
+	}
+
+	_, stderr, err = runCLI("ingredients", missing)
+	if err == nil || strings.Count(stderr, "failed to read file") != 1 {
+		t.Errorf("expected the failure of a single file once, got err=%v stderr=%q", err, stderr)
+	}
+
+	if _, _, err := runCLI("shopping-list", pasta, "--progress", "--quiet"); err == nil {
+		t.Error("expected --progress and --quiet to be mutually exclusive")
+	}
+}
+
+func TestCLI_Nutrition(t *testing.T) {
+	dir := t.TempDir()
+	recipePath := filepath.Join(dir, "lasagna.cook")
+	content := "---\nservings: 4\nnutrition:\n  calories: 650\n  fat: 32g\n  sodium: 900mg\n  protein: 28g\n---\nLayer @pasta{12%sheets}.\n"
+	if err := os.WriteFile(recipePath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	stdout, stderr, err := runCLI("nutrition", recipePath)
+	if err != nil {
+		t.Fatalf("nutrition failed: %v\nstderr: %s", err, stderr)
+	}
+	for _, want := range []string{"4 servings per recipe", "  Calories     650\n", "  Total Fat    32g  41%", "  Sodium     900mg  39%"} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("expected %q in output, got:\n%s", want, stdout)
+		}
+	}
+
+	stdout, _, err = runCLI("nutrition", recipePath, "--format", "html")
+	if err != nil || !strings.Contains(stdout, `<section class="nutrition-facts">`) {
+		t.Errorf("expected an HTML panel, got %v:\n%s", err, stdout)
+	}
+	stdout, _, err = runCLI("nutrition", recipePath, "--format", "json")
+	if err != nil || !strings.Contains(stdout, `"calories": 650`) || !strings.Contains(stdout, `"daily_value": 41`) {
+		t.Errorf("expected JSON facts, got %v:\n%s", err, stdout)
+	}
+
+	plain := filepath.Join(dir, "water.cook")
+	if err := os.WriteFile(plain, []byte("Boil @water{1%l}.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, stderr, err := runCLI("nutrition", plain); err == nil || !strings.Contains(stderr, "no nutrition data") {
+		t.Errorf("expected a no nutrition data error, got %v: %s", err, stderr)
+	}
+}
+
+func TestCLI_Render_BakersPercentages(t *testing.T) {
+	recipePath := filepath.Join(t.TempDir(), "bread.cook")
+	if err := os.WriteFile(recipePath, []byte("Mix @bread flour{500%g}, @water{375%g} and @salt{10%g}.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	stdout, stderr, err := runCLI("render", recipePath, "--bakers-percentages")
+	if err != nil {
+		t.Fatalf("render failed: %v\nstderr: %s", err, stderr)
+	}
+	for _, want := range []string{"## Baker's Percentages", "| water | 375 | 75% |", "**Hydration:** 75%"} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("expected %q in output, got:\n%s", want, stdout)
+		}
+	}
+
+	stdout, _, err = runCLI("render", recipePath, "--format", "html", "--bakers-percentages")
+	if err != nil || !strings.Contains(stdout, `<section class="bakers-percentages">`) {
+		t.Errorf("expected an HTML table, got %v:\n%s", err, stdout)
+	}
+}
+
+func TestCLI_ShoppingListReportsUnconsolidated(t *testing.T) {
+	dir := t.TempDir()
+	bread := filepath.Join(dir, "bread.cook")
+	cake := filepath.Join(dir, "cake.cook")
+	if err := os.WriteFile(bread, []byte("Mix @flour{500%g} with @water{300%ml}.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(cake, []byte("Mix @flour{1%kg} and @flour{1%cup}.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	stdout, stderr, err := runCLI("shopping-list", "--simple", bread, cake)
+	if err != nil {
+		t.Fatalf("shopping-list failed: %v\nstderr: %s", err, stderr)
+	}
+	if !strings.Contains(stdout, "1.5 kg") {
+		t.Errorf("expected the flour added up in kg, got:\n%s", stdout)
+	}
+	if !strings.Contains(stderr, "flour (1 cup) is listed separately: cup (volume) does not convert to kg (mass)") {
+		t.Errorf("expected a warning about the cup of flour, got stderr: %s", stderr)
+	}
+}
+
+func TestCLI_IngredientsProfile(t *testing.T) {
+	negroniPath := getExampleRecipePath("Negroni.cook")
+
+	stdout, stderr, err := runCLI("ingredients", negroniPath, "--unit", "us")
+	if err != nil {
+		t.Fatalf("ingredients failed: %v\nstderr: %s", err, stderr)
+	}
+	if !strings.Contains(stdout, "1 2/3 oz gin") {
+		t.Errorf("expected bar measures for a cocktail by default, got:\n%s", stdout)
+	}
+
+	stdout, _, err = runCLI("ingredients", negroniPath, "--unit", "us", "--profile", "precise")
+	if err != nil || !strings.Contains(stdout, "3.38 tbsp gin") {
+		t.Errorf("expected precise measures with --profile precise, got %v:\n%s", err, stdout)
+	}
+
+	if _, _, err := runCLI("ingredients", negroniPath, "--unit", "us", "--profile", "chemist"); err == nil {
+		t.Error("expected an error for an unknown profile")
+	}
+}
+
+func TestCLI_PreferredUnits(t *testing.T) {
+	dir := t.TempDir()
+	recipePath := filepath.Join(dir, "cake.cook")
+	prefsPath := filepath.Join(dir, "units.yaml")
+	if err := os.WriteFile(recipePath, []byte("Cream @butter{250%g} with @sugar{1%kg} and @eggs{1%dozen}.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(prefsPath, []byte("butter: oz\neggs: count\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	stdout, stderr, err := runCLI("ingredients", recipePath, "--unit", "metric", "--preferred-units", prefsPath)
+	if err != nil {
+		t.Fatalf("ingredients failed: %v\nstderr: %s", err, stderr)
+	}
+	for _, want := range []string{"8.82 oz butter", "1 kg sugar", "12 eggs"} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("expected %q, got:\n%s", want, stdout)
+		}
+	}
+
+	stdout, stderr, err = runCLI("render", recipePath, "--preferred-units", prefsPath)
+	if err != nil {
+		t.Fatalf("render failed: %v\nstderr: %s", err, stderr)
+	}
+	if !strings.Contains(stdout, "8.82 oz") {
+		t.Errorf("expected butter in ounces, got:\n%s", stdout)
+	}
+
+	if _, stderr, err := runCLI("shopping-list", recipePath, "--preferred-units", filepath.Join(dir, "missing.yaml")); err == nil || !strings.Contains(stderr, "failed to load preferred units") {
+		t.Errorf("expected an error for a missing file, got %v: %s", err, stderr)
+	}
+}
+
+func TestCLI_ScaleYield(t *testing.T) {
+	recipePath := filepath.Join(t.TempDir(), "cookies.cook")
+	if err := os.WriteFile(recipePath, []byte("---\nyield: 24 cookies\n---\nMix @butter{100%g} and @flour{250%g}.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	stdout, stderr, err := runCLI("scale", recipePath, "--yield", "48 cookies")
+	if err != nil {
+		t.Fatalf("scale failed: %v\nstderr: %s", err, stderr)
+	}
+	for _, want := range []string{"yield: 48 cookies", "@flour{500%g}"} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("expected %q in output, got:\n%s", want, stdout)
+		}
+	}
+
+	if _, stderr, err := runCLI("scale", recipePath, "--yield", "2 loaves"); err == nil || !strings.Contains(stderr, "incompatible units") {
+		t.Errorf("expected an error for another yield unit, got %v: %s", err, stderr)
+	}
+	if _, stderr, err := runCLI("scale", recipePath, "--yield", "48 cookies", "--factor", "2"); err == nil || !strings.Contains(stderr, "more than one") {
+		t.Errorf("expected an error for --yield with --factor, got %v: %s", err, stderr)
+	}
+}
+
+func TestCLI_ScaleRoundCounts(t *testing.T) {
+	recipePath := filepath.Join(t.TempDir(), "omelette.cook")
+	if err := os.WriteFile(recipePath, []byte("---\nservings: 2\n---\nWhisk @eggs{3} with @milk{100%ml}.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	stdout, stderr, err := runCLI("scale", recipePath, "--factor", "0.5", "--round-counts", "up")
+	if err != nil {
+		t.Fatalf("scale failed: %v\nstderr: %s", err, stderr)
+	}
+	if !strings.Contains(stdout, "@eggs{2%}") || !strings.Contains(stdout, "@milk{50%ml}") {
+		t.Errorf("expected 2 eggs and 50 ml milk, got:\n%s", stdout)
+	}
+
+	stdout, stderr, err = runCLI("shopping-list", recipePath, "--servings", "1", "--round-counts", "half", "--simple")
+	if err != nil {
+		t.Fatalf("shopping-list failed: %v\nstderr: %s", err, stderr)
+	}
+	if !strings.Contains(stdout, "eggs: 1.5") {
+		t.Errorf("expected 1.5 eggs, got:\n%s", stdout)
+	}
+
+	if _, stderr, err := runCLI("scale", recipePath, "--factor", "2", "--round-counts", "down"); err == nil || !strings.Contains(stderr, "unknown count rounding") {
+		t.Errorf("expected an error for an unknown rounding, got %v: %s", err, stderr)
+	}
+}
+
+func TestCLI_Menu(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"pancakes.cook":     "---\ntitle: Pancakes\nservings: 2\n---\nMix @flour{200%g} and @eggs{2}.\n",
+		"dinner/pasta.cook": "---\ntitle: Pasta\nservings: 2\n---\nBoil @pasta{250%g} with @eggs{1}.\n",
+		"week.menu":         "== Monday ==\n\nBreakfast:\n- @./pancakes{2}\n\nDinner:\n- @./dinner/pasta{4%servings}\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	menuPath := filepath.Join(dir, "week.menu")
+
+	stdout, stderr, err := runCLI("menu", menuPath)
+	if err != nil {
+		t.Fatalf("menu failed: %v\nstderr: %s", err, stderr)
+	}
+	for _, want := range []string{"Monday", "Breakfast", "Pancakes (x2)", "Dinner", "Pasta (4 servings)"} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("expected %q in output, got:\n%s", want, stdout)
+		}
+	}
+
+	stdout, stderr, err = runCLI("menu", menuPath, "--shopping-list")
+	if err != nil {
+		t.Fatalf("menu --shopping-list failed: %v\nstderr: %s", err, stderr)
+	}
+	for _, want := range []string{"flour: 400 g", "pasta: 500 g", "eggs: 6"} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("expected %q in shopping list, got:\n%s", want, stdout)
+		}
+	}
+
+	if err := os.WriteFile(menuPath, []byte("== Monday ==\n@./missing{}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, stderr, err := runCLI("menu", menuPath); err == nil || !strings.Contains(stderr, "missing") {
+		t.Errorf("expected an error for a missing recipe, got %v: %s", err, stderr)
+	}
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/hilli/cooklang"
+	"github.com/spf13/cobra"
+)
+
+var (
+	menuDir          string
+	menuShoppingList bool
+	menuJSON         bool
+)
+
+var menuCmd = &cobra.Command{
+	Use:   "menu <file.menu>",
+	Short: "Show a meal plan, or the shopping list for it",
+	Long: `Read a .menu file and show its days, meals and recipes, each scaled
+as the menu asks. A .menu file is Cooklang: sections are days, text
+ending in a colon such as "Breakfast:" starts a meal, and recipe
+references are the dishes, scaled by a factor ({2}), to servings
+({4%servings}) or to a yield ({48%cookies}).
+
+  == Monday ==
+  Breakfast:
+  - @./Pancakes{2}
+  Dinner:
+  - @./Lasagne{4%servings}
+
+Recipe paths are relative to --dir, which defaults to the directory of
+the menu file. With --shopping-list, the ingredients of all the scaled
+recipes are combined into one shopping list, grouped by aisle.
+
+Examples:
+  cook menu week.menu
+  cook menu week.menu --shopping-list
+  cook menu plans/week.menu --dir ./recipes --shopping-list --json`,
+	Args:              cobra.ExactArgs(1),
+	RunE:              runMenu,
+	ValidArgsFunction: completeMenuFile,
+}
+
+func init() {
+	menuCmd.Flags().StringVarP(&menuDir, "dir", "d", "", "Recipe directory the menu's paths are relative to (default: the menu file's directory)")
+	menuCmd.Flags().BoolVar(&menuShoppingList, "shopping-list", false, "Show the shopping list for the whole menu")
+	menuCmd.Flags().BoolVarP(&menuJSON, "json", "j", false, "Output as JSON")
+	rootCmd.AddCommand(menuCmd)
+
+	_ = menuCmd.RegisterFlagCompletionFunc("dir", func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		return nil, cobra.ShellCompDirectiveFilterDirs
+	})
+}
+
+func runMenu(cmd *cobra.Command, args []string) error {
+	menu, err := cooklang.ParseMenuFile(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", args[0], err)
+	}
+	dir := menuDir
+	if dir == "" {
+		dir = filepath.Dir(args[0])
+	}
+	cmd.SilenceUsage = true
+
+	// Recipes that fail to parse are reported; the menu can use the others
+	library, err := cooklang.LoadLibrary(dir)
+	if err != nil {
+		printWarning("%v", err)
+	}
+
+	// Recipes that cannot be resolved are reported; the rest are shown
+	resolved, resolveErr := menu.Resolve(library)
+	if len(resolved) == 0 {
+		if resolveErr != nil {
+			return resolveErr
+		}
+		return fmt.Errorf("%s has no recipes", args[0])
+	}
+
+	if menuShoppingList {
+		err = showMenuShoppingList(resolved)
+	} else {
+		err = showMenu(resolved)
+	}
+	if err != nil {
+		return err
+	}
+	return resolveErr
+}
+
+// showMenu lists the scaled recipes of the menu by day and meal.
+func showMenu(resolved []cooklang.ResolvedMenuRecipe) error {
+	if menuJSON {
+		type menuRecipe struct {
+			Day      string  `json:"day,omitempty"`
+			Meal     string  `json:"meal,omitempty"`
+			Path     string  `json:"path"`
+			Title    string  `json:"title"`
+			Servings float32 `json:"servings,omitempty"`
+			Scale    string  `json:"scale,omitempty"`
+		}
+		recipes := make([]menuRecipe, len(resolved))
+		for i, item := range resolved {
+			recipes[i] = menuRecipe{
+				Day:      item.Day,
+				Meal:     item.Meal,
+				Path:     item.Path,
+				Title:    menuRecipeTitle(item),
+				Servings: item.Recipe.Servings,
+				Scale:    menuRecipeScale(item.MenuRecipe),
+			}
+		}
+		return outputJSON(recipes)
+	}
+
+	day, meal := "", ""
+	for i, item := range resolved {
+		if i == 0 || item.Day != day {
+			if i > 0 {
+				fmt.Println()
+			}
+			if item.Day != "" {
+				fmt.Println(item.Day)
+			}
+			day, meal = item.Day, ""
+		}
+		if item.Meal != meal {
+			fmt.Printf("  %s\n", item.Meal)
+			meal = item.Meal
+		}
+		line := "  • " + menuRecipeTitle(item)
+		if item.Meal != "" {
+			line = "  " + line
+		}
+		if scale := menuRecipeScale(item.MenuRecipe); scale != "" {
+			line += " (" + scale + ")"
+		}
+		fmt.Println(line)
+	}
+	return nil
+}
+
+// showMenuShoppingList shows the combined shopping list of the scaled recipes.
+func showMenuShoppingList(resolved []cooklang.ResolvedMenuRecipe) error {
+	recipes := make([]*cooklang.Recipe, len(resolved))
+	for i, item := range resolved {
+		recipes[i] = item.Recipe
+	}
+	list, err := cooklang.CreateShoppingList(recipes...)
+	if err != nil {
+		return fmt.Errorf("failed to create shopping list: %w", err)
+	}
+	if list.Report != nil {
+		for _, leftover := range list.Report.Unconsolidated {
+			amount := strings.TrimSpace(leftover.Ingredient.Quantity.String() + " " + leftover.Ingredient.Unit)
+			printWarning("%s (%s) is listed separately: %s", leftover.Ingredient.Name, amount, leftover.Reason)
+		}
+	}
+
+	if menuJSON {
+		return outputJSON(struct {
+			Recipes []string                    `json:"recipes"`
+			Items   []cooklang.ShoppingListItem `json:"items"`
+		}{Recipes: list.Recipes, Items: list.SortedItems(cooklang.OrderAlphabetical)})
+	}
+
+	items := list.SortedItems(cooklang.OrderByAisle)
+	fmt.Println("Shopping List")
+	fmt.Printf("For %d recipes on the menu\n", len(resolved))
+	displayDetailedShoppingList(items, cooklang.OrderByAisle)
+	fmt.Println()
+	fmt.Printf("Total: %d unique ingredients\n", len(items))
+	return nil
+}
+
+// menuRecipeTitle returns the title of a menu recipe, or its path if it has none.
+func menuRecipeTitle(item cooklang.ResolvedMenuRecipe) string {
+	if item.Recipe.Title != "" {
+		return item.Recipe.Title
+	}
+	return strings.TrimPrefix(item.Path, "./")
+}
+
+// menuRecipeScale describes how the menu scales a recipe, e.g. "x2" or
+// "4 servings", or "" if it is used as written.
+func menuRecipeScale(ref cooklang.MenuRecipe) string {
+	if ref.Quantity <= 0 {
+		return ""
+	}
+	quantity := strconv.FormatFloat(float64(ref.Quantity), 'f', -1, 32)
+	if ref.Unit == "" {
+		return "x" + quantity
+	}
+	return quantity + " " + ref.Unit
+}
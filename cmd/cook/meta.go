@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/hilli/cooklang"
+	"github.com/spf13/cobra"
+)
+
+var (
+	metaDryRun bool
+	metaJSON   bool
+)
+
+var metaCmd = &cobra.Command{
+	Use:   "meta",
+	Short: "Edit the metadata of many recipes at once",
+	Long: `Edit the frontmatter metadata of recipes in bulk.
+
+Every subcommand takes .cook files and directories, which are searched
+recursively (hidden directories are skipped); without paths the current
+directory is used. Only recipes whose metadata changes are rewritten, and
+their key order, comments and nested values are kept.
+
+Use --dry-run to see what would change without writing any file.
+
+Examples:
+  cook meta set author "Jane" ./recipes --dry-run
+  cook meta add-tag vegan ./recipes/salads
+  cook meta remove-tag quick Pancakes.cook
+  cook meta delete source ./recipes
+  cook meta normalize-dates ./recipes`,
+}
+
+var metaSetCmd = &cobra.Command{
+	Use:   "set <key> <value> [file|dir...]",
+	Short: "Set a metadata value",
+	Args:  cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runMeta(args[2:], cooklang.SetMetadataOp(args[0], args[1]))
+	},
+}
+
+var metaDeleteCmd = &cobra.Command{
+	Use:   "delete <key> [file|dir...]",
+	Short: "Remove a metadata key",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runMeta(args[1:], cooklang.DeleteMetadataOp(args[0]))
+	},
+}
+
+var metaAddTagCmd = &cobra.Command{
+	Use:   "add-tag <tag> [file|dir...]",
+	Short: "Add a tag to recipes that do not have it",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runMeta(args[1:], cooklang.AddTagOp(args[0]))
+	},
+}
+
+var metaRemoveTagCmd = &cobra.Command{
+	Use:   "remove-tag <tag> [file|dir...]",
+	Short: "Remove a tag",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runMeta(args[1:], cooklang.RemoveTagOp(args[0]))
+	},
+}
+
+var metaNormalizeDatesCmd = &cobra.Command{
+	Use:   "normalize-dates [file|dir...]",
+	Short: "Rewrite dates as YYYY-MM-DD",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runMeta(args, cooklang.NormalizeDatesOp())
+	},
+}
+
+func init() {
+	metaCmd.PersistentFlags().BoolVarP(&metaDryRun, "dry-run", "n", false, "Show what would change without writing files")
+	metaCmd.PersistentFlags().BoolVarP(&metaJSON, "json", "j", false, "Output the change report as JSON")
+	// Complete recipe files after the key, value or tag arguments
+	metaSetCmd.ValidArgsFunction = completeCookFilesAfter(2)
+	metaDeleteCmd.ValidArgsFunction = completeCookFilesAfter(1)
+	metaAddTagCmd.ValidArgsFunction = completeCookFilesAfter(1)
+	metaRemoveTagCmd.ValidArgsFunction = completeCookFilesAfter(1)
+	metaNormalizeDatesCmd.ValidArgsFunction = completeCookFiles
+	metaCmd.AddCommand(metaSetCmd, metaDeleteCmd, metaAddTagCmd, metaRemoveTagCmd, metaNormalizeDatesCmd)
+	rootCmd.AddCommand(metaCmd)
+}
+
+func runMeta(paths []string, op cooklang.MetadataOperation) error {
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+	report, err := cooklang.BulkEditor{DryRun: metaDryRun, Atomic: true}.Apply(paths, op)
+
+	if metaJSON {
+		if jsonErr := outputJSON(report); jsonErr != nil {
+			return jsonErr
+		}
+		return err
+	}
+
+	changed := report.Changed()
+	for _, file := range changed {
+		fmt.Println(file.Path)
+		for _, change := range file.Changes {
+			switch change.Type {
+			case cooklang.ChangeAdded:
+				fmt.Printf("  + %s: %s\n", change.Key, change.New)
+			case cooklang.ChangeRemoved:
+				fmt.Printf("  - %s: %s\n", change.Key, change.Old)
+			default:
+				fmt.Printf("  ~ %s: %s → %s\n", change.Key, change.Old, change.New)
+			}
+		}
+	}
+
+	if metaDryRun {
+		printInfo("Dry run: %d of %d recipes would change", len(changed), len(report.Files))
+	} else {
+		printSuccess("Updated %d of %d recipes", len(changed), len(report.Files))
+	}
+	return err
+}
@@ -0,0 +1,231 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/hilli/cooklang"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var (
+	newTemplate      string
+	newServings      float64
+	newTags          []string
+	newAuthor        string
+	newMeta          []string
+	newOutput        string
+	newForce         bool
+	newNoInput       bool
+	newListTemplates bool
+)
+
+var newCmd = &cobra.Command{
+	Use:   "new [title]",
+	Short: "Create a new recipe from a template",
+	Long: `Create a new .cook file from a template, with the title, servings, tags
+and author in its frontmatter.
+
+Values that are not given as flags are asked for when running in a terminal
+(press enter to skip one); use --no-input to never ask. The recipe is saved as
+"<title>.cook" in the current directory unless --output is given.
+
+Built-in templates are basic (the default), baking, cocktail and blank. Your
+own templates are the .cook files in ~/.config/cook/templates (or
+$XDG_CONFIG_HOME/cook/templates); a template with the name of a built-in one
+replaces it. Templates may use {{.Title}}, {{.Servings}}, {{.Author}} and
+{{.Tags}}, and a comment on the first line ("-- Quick dinner") describes it.
+
+Examples:
+  cook new "Onion Soup"
+  cook new "Sourdough" --template baking --servings 2 --tags bread,weekend
+  cook new "Negroni" -t cocktail --author Sam --meta cuisine=Italian --no-input
+  cook new "Pancakes" --output -
+  cook new --list-templates`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runNew,
+}
+
+func init() {
+	newCmd.Flags().StringVarP(&newTemplate, "template", "t", "basic", "Template to start from")
+	newCmd.Flags().Float64VarP(&newServings, "servings", "s", 0, "Number of servings")
+	newCmd.Flags().StringSliceVar(&newTags, "tags", nil, "Tags (comma-separated)")
+	newCmd.Flags().StringVar(&newAuthor, "author", "", "Recipe author")
+	newCmd.Flags().StringArrayVar(&newMeta, "meta", nil, "Additional metadata as key=value (repeatable)")
+	newCmd.Flags().StringVarP(&newOutput, "output", "o", "", "File or directory to write to (- for stdout; default: <title>.cook)")
+	newCmd.Flags().BoolVarP(&newForce, "force", "f", false, "Overwrite an existing file")
+	newCmd.Flags().BoolVar(&newNoInput, "no-input", false, "Do not ask for missing values")
+	newCmd.Flags().BoolVarP(&newListTemplates, "list-templates", "l", false, "List the available templates")
+	_ = newCmd.RegisterFlagCompletionFunc("template", completeTemplateFlag)
+	_ = newCmd.RegisterFlagCompletionFunc("servings", completeServingsFlag)
+	rootCmd.AddCommand(newCmd)
+}
+
+func runNew(cmd *cobra.Command, args []string) error {
+	userTemplates, err := cooklang.LoadRecipeTemplates(templatesDir())
+	if err != nil {
+		return fmt.Errorf("failed to load templates: %w", err)
+	}
+	builtin := cooklang.BuiltinRecipeTemplates()
+
+	if newListTemplates {
+		for _, t := range builtin {
+			if _, overridden := cooklang.FindRecipeTemplate(t.Name, userTemplates); !overridden {
+				fmt.Printf("%-12s %s\n", t.Name, t.Description)
+			}
+		}
+		for _, t := range userTemplates {
+			fmt.Printf("%-12s %s (%s)\n", t.Name, t.Description, filepath.Join(templatesDir(), t.Name+".cook"))
+		}
+		return nil
+	}
+
+	template, ok := cooklang.FindRecipeTemplate(newTemplate, builtin, userTemplates)
+	if !ok {
+		return fmt.Errorf("unknown template %q (see cook new --list-templates)", newTemplate)
+	}
+
+	data := cooklang.ScaffoldData{Servings: float32(newServings), Tags: newTags, Author: newAuthor}
+	if len(args) == 1 {
+		data.Title = strings.TrimSpace(args[0])
+	}
+	for _, entry := range newMeta {
+		key, value, ok := strings.Cut(entry, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return fmt.Errorf("invalid --meta %q: use key=value", entry)
+		}
+		if data.Metadata == nil {
+			data.Metadata = map[string]string{}
+		}
+		data.Metadata[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+
+	if !newNoInput && term.IsTerminal(int(os.Stdin.Fd())) {
+		if err := promptScaffoldData(cmd, bufio.NewReader(os.Stdin), &data); err != nil {
+			return err
+		}
+	}
+	if data.Title == "" {
+		return fmt.Errorf("a title is required: cook new \"<title>\"")
+	}
+
+	content, err := template.Scaffold(data)
+	if err != nil {
+		return err
+	}
+
+	if newOutput == "-" {
+		fmt.Print(content)
+		return nil
+	}
+	path := newOutput
+	if info, err := os.Stat(path); path == "" || (err == nil && info.IsDir()) {
+		name := cooklang.FilenameFromTitle(data.Title)
+		if name == "" {
+			return fmt.Errorf("cannot make a file name from title %q; use --output", data.Title)
+		}
+		path = filepath.Join(path, name)
+	}
+	flags := os.O_WRONLY | os.O_CREATE | os.O_EXCL
+	if newForce {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	}
+	file, err := os.OpenFile(path, flags, 0644)
+	if errors.Is(err, fs.ErrExist) {
+		return fmt.Errorf("%s already exists (use --force to overwrite)", path)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	if _, err := file.WriteString(content); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	printSuccess("Created %s from the %s template", path, template.Name)
+	return nil
+}
+
+// promptScaffoldData asks for the values that were not given as flags.
+func promptScaffoldData(cmd *cobra.Command, in *bufio.Reader, data *cooklang.ScaffoldData) error {
+	ask := func(question string) (string, error) {
+		fmt.Fprintf(diagnostics, "%s: ", question)
+		line, err := in.ReadString('\n')
+		if err != nil && !(errors.Is(err, io.EOF) && line != "") {
+			return "", err
+		}
+		return strings.TrimSpace(line), nil
+	}
+
+	for data.Title == "" {
+		title, err := ask("Title")
+		if err != nil {
+			return err
+		}
+		data.Title = title
+	}
+	for !cmd.Flags().Changed("servings") {
+		answer, err := ask("Servings (optional)")
+		if err != nil {
+			return err
+		}
+		if answer == "" {
+			break
+		}
+		servings, err := strconv.ParseFloat(answer, 32)
+		if err == nil && servings > 0 {
+			data.Servings = float32(servings)
+			break
+		}
+		printWarning("Servings must be a positive number")
+	}
+	if !cmd.Flags().Changed("tags") {
+		answer, err := ask("Tags, comma-separated (optional)")
+		if err != nil {
+			return err
+		}
+		for _, tag := range strings.Split(answer, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				data.Tags = append(data.Tags, tag)
+			}
+		}
+	}
+	if !cmd.Flags().Changed("author") {
+		answer, err := ask("Author (optional)")
+		if err != nil {
+			return err
+		}
+		data.Author = answer
+	}
+	return nil
+}
+
+// templatesDir returns the directory with the user's recipe templates.
+func templatesDir() string {
+	return filepath.Join(cooklang.ConfigDir(), "templates")
+}
+
+// completeTemplateFlag provides shell completion for the --template flag
+func completeTemplateFlag(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+	userTemplates, _ := cooklang.LoadRecipeTemplates(templatesDir())
+	var names []string
+	for _, t := range cooklang.BuiltinRecipeTemplates() {
+		if _, overridden := cooklang.FindRecipeTemplate(t.Name, userTemplates); !overridden {
+			names = append(names, t.Name+"\t"+t.Description)
+		}
+	}
+	for _, t := range userTemplates {
+		names = append(names, t.Name+"\t"+t.Description)
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
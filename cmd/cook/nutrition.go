@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hilli/cooklang/renderers"
+	"github.com/spf13/cobra"
+)
+
+var nutritionFormat string
+
+var nutritionCmd = &cobra.Command{
+	Use:   "nutrition <recipe-file>",
+	Short: "Show a recipe's nutrition facts per serving",
+	Long: `Show the nutrition facts per serving of a recipe, read from its metadata:
+
+  ---
+  servings: 4
+  nutrition:
+    calories: 650
+    fat: 32g
+    sodium: 900mg
+    protein: 28g
+  ---
+
+as a text table, as an HTML panel or SVG image in the style of a US nutrition
+facts label, or as JSON.
+
+Examples:
+  cook nutrition lasagna.cook
+  cook nutrition lasagna.cook --format html > label.html
+  cook nutrition lasagna.cook --format svg > label.svg
+  cook nutrition lasagna.cook --format json`,
+	Args:              cobra.ExactArgs(1),
+	RunE:              runNutrition,
+	ValidArgsFunction: completeCookFiles,
+}
+
+func init() {
+	nutritionCmd.Flags().StringVarP(&nutritionFormat, "format", "f", "text", "Output format (text, html, svg, json)")
+	rootCmd.AddCommand(nutritionCmd)
+
+	_ = nutritionCmd.RegisterFlagCompletionFunc("format", completeFormats("text", "html", "svg", "json"))
+}
+
+func runNutrition(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+	format := strings.ToLower(nutritionFormat)
+	switch format {
+	case "text", "html", "svg", "json":
+	default:
+		return fmt.Errorf("unsupported format: %s (supported: text, html, svg, json)", nutritionFormat)
+	}
+
+	recipe, err := readRecipeFile(args[0])
+	if err != nil {
+		return err
+	}
+	facts, ok := renderers.NutritionFromRecipe(recipe)
+	if !ok {
+		return fmt.Errorf("%s has no nutrition data", args[0])
+	}
+
+	switch format {
+	case "json":
+		return outputJSON(struct {
+			*renderers.NutritionFacts
+			Rows []renderers.NutritionRow `json:"rows"`
+		}{facts, facts.Rows()})
+	case "html", "svg":
+		panel, err := renderers.NutritionLabelRenderer{Facts: facts, SVG: format == "svg"}.Render(recipe, renderers.RendererOptions{})
+		if err != nil {
+			return err
+		}
+		fmt.Print(panel)
+		return nil
+	}
+	printNutritionFacts(facts)
+	return nil
+}
+
+// printNutritionFacts writes the facts as a table of nutrients, amounts and percent
+// daily values
+func printNutritionFacts(facts *renderers.NutritionFacts) {
+	fmt.Println("Nutrition Facts")
+	if facts.Servings > 0 {
+		fmt.Printf("%g servings per recipe\n", facts.Servings)
+	}
+	if facts.ServingSize != "" {
+		fmt.Printf("Serving size %s\n", facts.ServingSize)
+	}
+	fmt.Println()
+
+	rows := [][3]string{{"Calories", fmt.Sprintf("%.0f", facts.Calories), ""}}
+	for _, row := range facts.Rows() {
+		name := row.Name
+		if row.Sub {
+			name = "  " + name
+		}
+		dailyValue := ""
+		if row.DailyValue > 0 {
+			dailyValue = fmt.Sprintf("%d%%", row.DailyValue)
+		}
+		rows = append(rows, [3]string{name, row.Amount, dailyValue})
+	}
+	var widths [3]int
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], len(cell))
+		}
+	}
+	for _, row := range rows {
+		line := fmt.Sprintf("  %s  %s  %s", padRight(row[0], widths[0]), padLeft(row[1], widths[1]), padLeft(row[2], widths[2]))
+		fmt.Println(strings.TrimRight(line, " "))
+	}
+	fmt.Println()
+	fmt.Println("% Daily Values are based on a 2,000 calorie diet.")
+}
@@ -2,8 +2,10 @@ package main
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/hilli/cooklang"
+	"github.com/hilli/cooklang/renderers"
 	"github.com/spf13/cobra"
 )
 
@@ -24,13 +26,18 @@ The parse command validates the recipe syntax and displays:
   • Timers
   • Step-by-step instructions
 
+Ingredients, cookware and timers are colored when the output is a terminal;
+use --no-color or set NO_COLOR to turn this off. Steps are wrapped to the
+width in $COLUMNS (default 80). --detailed lists every component of every
+step instead.
+
 Examples:
   cook parse recipe.cook
   cook parse recipe.cook --json
   cook parse recipe.cook --detailed`,
 	Args:              cobra.ExactArgs(1),
 	RunE:              runParse,
-	ValidArgsFunction: completeCookFiles,
+	ValidArgsFunction: completeCookFile,
 }
 
 func init() {
@@ -51,11 +58,22 @@ func runParse(cmd *cobra.Command, args []string) error {
 		return outputJSON(recipe)
 	}
 
-	displayRecipe(recipe, filename, parseDetailed)
+	if parseDetailed {
+		displayRecipe(recipe, filename)
+		return nil
+	}
+
+	renderer := renderers.TerminalRenderer{NoColor: !colorEnabled(os.Stdout), Width: terminalWidth()}
+	output, err := renderer.RenderRecipe(recipe)
+	if err != nil {
+		return err
+	}
+	fmt.Print(output)
 	return nil
 }
 
-func displayRecipe(recipe *cooklang.Recipe, filename string, detailed bool) {
+// displayRecipe prints the recipe's metadata and a breakdown of every step's components.
+func displayRecipe(recipe *cooklang.Recipe, filename string) {
 	fmt.Printf("📄 Recipe: %s\n", filename)
 	fmt.Println(string(make([]byte, 60)))
 
@@ -109,8 +127,8 @@ func displayRecipe(recipe *cooklang.Recipe, filename string, detailed bool) {
 		fmt.Println("\nCookware:")
 		for _, item := range cookware {
 			display := item.RenderDisplay()
-			if item.Quantity > 1 {
-				display = fmt.Sprintf("%s (x%d)", display, item.Quantity)
+			if quantity := item.QuantityLabel(); quantity != "" {
+				display = fmt.Sprintf("%s (%s)", display, quantity)
 			}
 			if item.Annotation != "" {
 				display += fmt.Sprintf(" (%s)", item.Annotation)
@@ -124,15 +142,8 @@ func displayRecipe(recipe *cooklang.Recipe, filename string, detailed bool) {
 	step := recipe.FirstStep
 	stepNum := 1
 	for step != nil {
-		if detailed {
-			fmt.Printf("\nStep %d (detailed):\n", stepNum)
-			displayDetailedStep(step)
-		} else {
-			text := getStepText(step)
-			if text != "" {
-				fmt.Printf("%d. %s\n", stepNum, text)
-			}
-		}
+		fmt.Printf("\nStep %d (detailed):\n", stepNum)
+		displayDetailedStep(step)
 		step = step.NextStep
 		stepNum++
 	}
@@ -165,6 +176,8 @@ func getStepText(step *cooklang.Step) string {
 			if display != "" {
 				text += "[" + display + "]"
 			}
+		case *cooklang.Temperature:
+			text += comp.RenderDisplay()
 		}
 		currentComponent = currentComponent.GetNext()
 	}
@@ -185,7 +198,9 @@ func displayDetailedStep(step *cooklang.Step) {
 			fmt.Printf("%s Ingredient: %s\n", prefix, display)
 		case *cooklang.Cookware:
 			display := comp.RenderDisplay()
-			if comp.Quantity > 1 {
+			if comp.QuantityText != "" {
+				display = fmt.Sprintf("%s (qty: %s)", display, comp.QuantityText)
+			} else if comp.Quantity > 1 {
 				display = fmt.Sprintf("%s (qty: %d)", display, comp.Quantity)
 			}
 			if comp.Annotation != "" {
@@ -198,6 +213,8 @@ func displayDetailedStep(step *cooklang.Step) {
 				display += fmt.Sprintf(" (%s)", comp.Annotation)
 			}
 			fmt.Printf("%s Timer: %s\n", prefix, display)
+		case *cooklang.Temperature:
+			fmt.Printf("%s Temperature: %s\n", prefix, comp.RenderDisplay())
 		case *cooklang.Instruction:
 			fmt.Printf("%s Text: %q\n", prefix, comp.RenderDisplay())
 		}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hilli/cooklang"
+	"github.com/hilli/cooklang/renderers"
+	"github.com/spf13/cobra"
+)
+
+var (
+	printOutput   string
+	printTags     []string
+	printTitle    string
+	printSubtitle string
+	printChapters bool
+	printLocale   string
+	printImages   string
+)
+
+var printCmd = &cobra.Command{
+	Use:   "print <directory>",
+	Short: "Print a collection of recipes as one booklet",
+	Long: `Render the recipes of a directory and its subdirectories as one
+print-optimized HTML booklet: a cover, a table of contents linking to
+every recipe, and each recipe on a page of its own. Open it in a browser
+and print it, or save it as PDF.
+
+The recipes are sorted by title. With --tag, only recipes with all the
+given tags are included. With --chapters, the recipes are grouped into a
+chapter per tag, by each recipe's first tag; untagged recipes come last.
+
+Images are linked relative to --output, or embedded with --images=embed
+for a single file to share.
+
+Examples:
+  cook print ./recipes -o cookbook.html
+  cook print ./recipes --tag dessert -o desserts.html
+  cook print ./recipes --chapters --title "Family Recipes" --subtitle "Winter 2026" -o family.html
+  cook print ./recipes --locale de --images embed -o rezepte.html`,
+	Args:              cobra.ExactArgs(1),
+	RunE:              runPrint,
+	ValidArgsFunction: completeDirectory,
+}
+
+func init() {
+	printCmd.Flags().StringVarP(&printOutput, "output", "o", "", "Output file (default: stdout)")
+	printCmd.Flags().StringSliceVarP(&printTags, "tag", "t", nil, "Only include recipes with this tag (repeatable)")
+	printCmd.Flags().StringVar(&printTitle, "title", "", "Title on the cover (default: Recipes)")
+	printCmd.Flags().StringVar(&printSubtitle, "subtitle", "", "Line under the title on the cover")
+	printCmd.Flags().BoolVar(&printChapters, "chapters", false, "Group the recipes into a chapter per tag")
+	printCmd.Flags().StringVarP(&printLocale, "locale", "l", "", "Language of headings and labels (e.g., de, fr); default English")
+	printCmd.Flags().StringVar(&printImages, "images", "link", "How the booklet refers to images: link or embed")
+	rootCmd.AddCommand(printCmd)
+
+	_ = printCmd.RegisterFlagCompletionFunc("locale", cobra.FixedCompletions(renderers.Locales(), cobra.ShellCompDirectiveNoFileComp))
+	_ = printCmd.RegisterFlagCompletionFunc("images", cobra.FixedCompletions([]string{"link", "embed"}, cobra.ShellCompDirectiveNoFileComp))
+}
+
+func runPrint(cmd *cobra.Command, args []string) error {
+	source := args[0]
+	info, err := os.Stat(source)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", source, err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", source)
+	}
+	mode, err := cooklang.ParseImageMode(printImages)
+	if err != nil {
+		return err
+	}
+	if mode == cooklang.ImagesCopied {
+		return fmt.Errorf("--images=copy is not supported by cook print (use link or embed)")
+	}
+	cmd.SilenceUsage = true
+
+	files, err := collectCookFiles([]string{source})
+	if err != nil {
+		return err
+	}
+	var recipes []*cooklang.Recipe
+	for _, filename := range files {
+		recipe, err := readRecipeFile(filename)
+		if err != nil {
+			printWarning("%s: %v", filename, err)
+			continue
+		}
+		if !hasAllTags(recipe, printTags) {
+			continue
+		}
+		recipe.AddDetectedImages(filename)
+		if recipe.Images, err = cooklang.ImageSources(recipe, filepath.Dir(filename), printOutput, mode); err != nil {
+			return err
+		}
+		if err := cooklang.SetStepImageSources(recipe, filepath.Dir(filename), printOutput, mode); err != nil {
+			return err
+		}
+		recipes = append(recipes, recipe)
+	}
+	if len(recipes) == 0 {
+		if len(printTags) > 0 {
+			return fmt.Errorf("no recipes in %s are tagged %s", source, strings.Join(printTags, ", "))
+		}
+		return fmt.Errorf("no .cook files found in %s", source)
+	}
+	sort.SliceStable(recipes, func(i, j int) bool {
+		return strings.ToLower(recipes[i].Title) < strings.ToLower(recipes[j].Title)
+	})
+
+	renderer := renderers.PrintRenderer{Options: renderers.RendererOptions{Locale: printLocale}}
+	booklet, err := renderer.RenderBooklet(recipes, renderers.Booklet{Title: printTitle, Subtitle: printSubtitle, Chapters: printChapters})
+	if err != nil {
+		return err
+	}
+
+	if printOutput == "" {
+		fmt.Print(booklet)
+		return nil
+	}
+	if dir := filepath.Dir(printOutput); dir != "." && dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("failed to create output directory: %w", err)
+		}
+	}
+	if err := os.WriteFile(printOutput, []byte(booklet), 0o644); err != nil {
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+	printSuccess("Printed %d recipes to %s", len(recipes), printOutput)
+	return nil
+}
+
+// hasAllTags reports whether a recipe has all the tags, ignoring case.
+func hasAllTags(recipe *cooklang.Recipe, tags []string) bool {
+	for _, tag := range tags {
+		found := false
+		for _, recipeTag := range recipe.Tags {
+			if strings.EqualFold(recipeTag, tag) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/hilli/cooklang"
+	"github.com/hilli/cooklang/naming"
+	"github.com/spf13/cobra"
+)
+
+var (
+	renameFromTitle bool
+	renameSlug      bool
+	renameDryRun    bool
+)
+
+var renameCmd = &cobra.Command{
+	Use:   "rename --from-title <recipe-file|directory> [...]",
+	Short: "Rename recipe files to match their titles",
+	Long: `Rename recipe files to match the title in their metadata, e.g.
+"pizza2.cook" with "title: Neapolitan Pizza" becomes "Neapolitan Pizza.cook".
+Directories are searched recursively for .cook files.
+
+Images found by the recipe's name ("pizza2.jpg", "pizza2-1.png", "pizza2.0.jpg")
+are renamed with it, so they are still detected. Recipes without a title are
+skipped, and nothing of a recipe is renamed if a new name is already taken.
+
+Examples:
+  cook rename --from-title recipes/
+  cook rename --from-title pizza2.cook --dry-run
+  cook rename --from-title --slug recipes/   # neapolitan-pizza.cook`,
+	Args:              cobra.MinimumNArgs(1),
+	RunE:              runRename,
+	ValidArgsFunction: completeCookFiles,
+}
+
+func init() {
+	renameCmd.Flags().BoolVar(&renameFromTitle, "from-title", false, "Name files after the title in their metadata (required)")
+	renameCmd.Flags().BoolVar(&renameSlug, "slug", false, "Use slugified names, e.g. neapolitan-pizza.cook")
+	renameCmd.Flags().BoolVarP(&renameDryRun, "dry-run", "n", false, "Show the renames without renaming")
+	_ = renameCmd.MarkFlagRequired("from-title")
+	rootCmd.AddCommand(renameCmd)
+}
+
+func runRename(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+	files, err := collectCookFiles(args)
+	if err != nil {
+		return err
+	}
+
+	renamed, failed := 0, 0
+	for _, file := range files {
+		name, err := titleFilename(file)
+		if err != nil {
+			printWarning("%s: %v", file, err)
+			failed++
+			continue
+		}
+		if name == "" {
+			printVerbose("Skipping %s: no title", file)
+			continue
+		}
+		renames, err := naming.RenameRecipe(file, name, renameDryRun)
+		for _, rename := range renames {
+			if renameDryRun {
+				fmt.Printf("%s → %s\n", rename.From, rename.To)
+			} else {
+				printSuccess("Renamed %s → %s", rename.From, rename.To)
+			}
+		}
+		if err != nil {
+			printWarning("%v", err)
+			failed++
+			continue
+		}
+		if len(renames) > 0 {
+			renamed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d recipes could not be renamed", failed, len(files))
+	}
+	if renameDryRun {
+		printInfo("%d of %d recipes would be renamed", renamed, len(files))
+	} else {
+		printInfo("Renamed %d of %d recipes", renamed, len(files))
+	}
+	return nil
+}
+
+// titleFilename returns the file name for a recipe's title metadata, or "" if it has
+// no title. The title is not inferred from the file name.
+func titleFilename(file string) (string, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return "", fmt.Errorf("failed to read file: %w", err)
+	}
+	recipe, err := cooklang.ParseBytes(content, parseOptions()...)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse recipe: %w", err)
+	}
+	if !renameSlug {
+		return naming.SuggestFilename(recipe), nil
+	}
+	if slug := naming.Slugify(recipe.Title); slug != "" {
+		return slug + ".cook", nil
+	}
+	return "", nil
+}
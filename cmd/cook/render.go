@@ -2,21 +2,51 @@ package main
 
 import (
 	"fmt"
+	"net/url"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"strings"
+	"time"
 
+	"github.com/hilli/cooklang"
+	"github.com/hilli/cooklang/batch"
 	"github.com/hilli/cooklang/renderers"
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 )
 
 var (
-	renderFormat string
-	renderOutput string
+	renderFormat    string
+	renderFlavor    string
+	renderOutput    string
+	renderTransform string
+	renderLocale    string
+	renderTemp      string
+	renderImages    string
+	renderEmail     bool
+	renderImageBase string
+	renderWatch     bool
+	renderFractions bool
+	renderNutrition bool
+	renderBakers    bool
+	renderGroup     string
+	renderTemplate  string
+	renderQRURL     string
+	renderNotes     string
+	renderPreferred string
+	renderJobs      int
+)
+
+// How often cook render --watch checks for changes, and how long files must be
+// unchanged before they are rendered, so an editor's save is rendered once
+var (
+	renderWatchInterval = 250 * time.Millisecond
+	renderWatchDebounce = 300 * time.Millisecond
 )
 
 var renderCmd = &cobra.Command{
-	Use:   "render <recipe-file>",
+	Use:   "render <recipe-file|dir>",
 	Short: "Render a recipe in different formats",
 	Long: `Render a Cooklang recipe in various output formats.
 
@@ -25,70 +55,491 @@ Supported formats:
   • markdown - Markdown format (default)
   • html     - HTML format
   • print    - Print-optimized HTML (single page, embedded CSS)
+  • voice    - Step-by-step JSON for voice assistants (Alexa/Google)
+  • ssml     - SSML narration of the whole recipe for text-to-speech
+  • mermaid  - Mermaid flowchart of steps, ingredients and cookware
+  • docx     - Word document to edit in Word, LibreOffice or Google Docs
 
 Examples:
   cook render recipe.cook
   cook render recipe.cook --format=html
   cook render recipe.cook --format=print --output=recipe.html
   cook render recipe.cook --format=markdown --output=recipe.md
-  cook render recipe.cook -f html -o recipe.html`,
+  cook render recipe.cook --format=markdown --flavor=notion -o recipe.md
+  cook render recipe.cook -f html -o recipe.html
+  cook render recipe.cook --format=voice --output=recipe.json
+  cook render recipe.cook --format=ssml --output=recipe.ssml
+  cook render recipe.cook --format=mermaid >> README.md
+  cook render recipe.cook --format=docx -o recipe.docx
+  cook render recipe.cook --transform scale=2,units=metric
+  cook render recipe.cook --transform servings=4,vegan
+  cook render recipe.cook --format=html --locale=de
+  cook render recipe.cook --temperature=fahrenheit
+  cook render recipe.cook --temperature=both
+  cook render recipe.cook -f print -o out/recipe.html --images=embed
+  cook render recipe.cook -f html -o preview.html --watch
+  cook render recipe.cook -f html -o recipe.html --template my-theme/
+  cook render recipe.cook --email --image-base-url https://example.com/recipes/
+  cook render recipe.cook -f print --qr-url https://example.com/recipes/pasta
+  cook render recipes/ -f html -o site/
+  cook render recipes/ -f html -o site/ --watch
+
+With --flavor, the markdown format writes for an import into Notion
+(notion: the recipe information as a table, notes as callouts) or a
+Confluence page (confluence: wiki markup with h1. headings, tables and
+{info} panels for notes). Rendering a directory names Confluence pages .txt.
+
+The markdown, html and print formats write their headings and labels
+("Ingredients", "optional", ...) in the language given with --locale:
+da, de, en, es, fr, it, nl or sv. The recipe itself is not translated.
+
+Temperatures in the steps ("180°C") and oven_temp metadata are shown as
+written unless --temperature selects celsius, fahrenheit or both
+("180°C / 355°F"). With --unicode-fractions, the
+markdown, html and print formats write quantities as fractions such as 1½
+and ¾ instead of 1.5 and 0.75.
+
+The html and print formats show the recipe's images, and step photos
+(Recipe.0.jpg for the first step, or a step_images list in the
+frontmatter) under their steps. With --images=link (default) they refer
+to the image files, with paths rewritten relative to --output; embed inlines them as data URIs for a single self-contained file;
+copy copies them next to --output, named after it. The docx format embeds
+the first image.
+
+With --template, the html and print formats use the html/template files
+(*.html, *.tmpl) in a directory instead of the built-in layout: recipe.html
+for html and print.html for print. Templates get the recipe, its labels,
+metadata, ingredients and steps; see the renderers.TemplateData docs. The
+html template writes the whole page.
+
+With --email, the html format writes a page to paste into or send from an
+email client: laid out with tables no wider than 600px, with every element
+styled by its own style attribute and no <style> block. Only images with
+http(s) URLs are shown; --image-base-url is the address the --output
+directory is published at, and the image paths are resolved against it.
+--email selects the html format if --format is not given.
+
+With --quantity-notes, ingredient lists note the servings the quantities are
+for (servings), the quantity before --transform scaled it (original), or the
+quantity for one serving (per-serving).
+
+With --preferred-units, ingredients are shown in the units listed for them
+in a YAML file ("butter: g", "eggs: count", "olive oil: tbsp") after any
+--transform units=... conversion, instead of the unit picked by size.
+
+With --qr-url, the print format adds a QR code linking to that address, so
+the printed recipe leads back to the digital original.
+
+A directory renders every .cook file in it and its subdirectories into the
+--output directory, several at once (--jobs, default: one per CPU). The
+folder structure is kept with lowercase, dash-separated names and an
+extension for the format: "Main Courses/Beef Stew.cook" becomes
+main-courses/beef-stew.html. The html and print formats copy the images
+next to each page unless --images says otherwise, and write an index.html
+linking to every recipe.
+
+With --watch, cook keeps running and renders recipes again whenever they or
+their images change, until you press Ctrl+C. A burst of changes, such as an
+editor saving a file, is rendered once. For a directory, only the changed
+recipes are rendered. Without --output, the latest rendering replaces the
+previous one in the terminal.`,
 	Args:              cobra.ExactArgs(1),
 	RunE:              runRender,
-	ValidArgsFunction: completeCookFiles,
+	ValidArgsFunction: completeCookFile,
 }
 
 func init() {
-	renderCmd.Flags().StringVarP(&renderFormat, "format", "f", "markdown", "Output format (cooklang, markdown, html, print)")
-	renderCmd.Flags().StringVarP(&renderOutput, "output", "o", "", "Output file (default: stdout)")
+	renderCmd.Flags().StringVarP(&renderFormat, "format", "f", "markdown", "Output format (cooklang, markdown, html, print, voice, ssml, mermaid, docx)")
+	renderCmd.Flags().StringVar(&renderFlavor, "flavor", "", "Markdown dialect of the markdown format: notion or confluence (default: CommonMark)")
+	renderCmd.Flags().StringVarP(&renderOutput, "output", "o", "", "Output file, or directory when rendering a directory (default: stdout)")
+	renderCmd.Flags().StringVar(&renderOutput, "out", "", "Alias for --output")
+	_ = renderCmd.Flags().MarkHidden("out")
+	renderCmd.Flags().StringVarP(&renderTransform, "transform", "t", "", "Transform pipeline applied before rendering (e.g., scale=2,units=metric,vegan)")
+	renderCmd.Flags().StringVarP(&renderLocale, "locale", "l", "", "Language of headings and labels (e.g., de, fr); default English")
+	renderCmd.Flags().StringVar(&renderTemp, "temperature", "", "Show temperatures in celsius, fahrenheit or both (default: as written)")
+	renderCmd.Flags().StringVar(&renderImages, "images", "link", "How html and print output refer to images: link, embed or copy")
+	renderCmd.Flags().BoolVar(&renderEmail, "email", false, "Write html for email clients: inline styles and a table layout up to 600px wide")
+	renderCmd.Flags().StringVar(&renderImageBase, "image-base-url", "", "Address relative image paths are resolved against with --email")
+	renderCmd.Flags().BoolVar(&renderFractions, "unicode-fractions", false, "Write quantities as Unicode fractions (1½ cups)")
+	renderCmd.Flags().BoolVar(&renderNutrition, "nutrition-label", false, "Add a nutrition facts panel to the html and print formats")
+	renderCmd.Flags().BoolVar(&renderBakers, "bakers-percentages", false, "Add a table of baker's percentages and hydration to the markdown, html and print formats")
+	renderCmd.Flags().StringVar(&renderGroup, "group-ingredients", "", "Group the ingredient list by step or section (default: one list)")
+	renderCmd.Flags().StringVar(&renderTemplate, "template", "", "Directory with recipe.html or print.html templates for the html and print formats")
+	renderCmd.Flags().StringVar(&renderNotes, "quantity-notes", "", "Note servings, original or per-serving quantities next to ingredients")
+	renderCmd.Flags().StringVar(&renderPreferred, "preferred-units", "", "YAML file of preferred units per ingredient (butter: g, eggs: count)")
+	renderCmd.Flags().StringVar(&renderQRURL, "qr-url", "", "URL of the recipe online, printed as a QR code by the print format")
+	renderCmd.Flags().BoolVarP(&renderWatch, "watch", "w", false, "Render again whenever the recipe or its images change")
+	renderCmd.Flags().IntVar(&renderJobs, "jobs", 0, "Recipes of a directory rendered at once (default: one per CPU)")
 	rootCmd.AddCommand(renderCmd)
 
 	// Register flag completion
-	_ = renderCmd.RegisterFlagCompletionFunc("format", completeFormatFlag)
+	_ = renderCmd.RegisterFlagCompletionFunc("format", completeFormats("cooklang", "markdown", "html", "print", "voice", "ssml", "mermaid", "docx"))
+	_ = renderCmd.RegisterFlagCompletionFunc("flavor", cobra.FixedCompletions([]string{"notion", "confluence"}, cobra.ShellCompDirectiveNoFileComp))
+	_ = renderCmd.RegisterFlagCompletionFunc("locale", cobra.FixedCompletions(renderers.Locales(), cobra.ShellCompDirectiveNoFileComp))
+	_ = renderCmd.RegisterFlagCompletionFunc("temperature", cobra.FixedCompletions([]string{"celsius", "fahrenheit", "both"}, cobra.ShellCompDirectiveNoFileComp))
+	_ = renderCmd.RegisterFlagCompletionFunc("group-ingredients", cobra.FixedCompletions([]string{"step", "section"}, cobra.ShellCompDirectiveNoFileComp))
+	_ = renderCmd.RegisterFlagCompletionFunc("quantity-notes", cobra.FixedCompletions([]string{"servings", "original", "per-serving"}, cobra.ShellCompDirectiveNoFileComp))
+	_ = renderCmd.RegisterFlagCompletionFunc("template", cobra.FixedCompletions(nil, cobra.ShellCompDirectiveFilterDirs))
+	_ = renderCmd.MarkFlagFilename("preferred-units", "yaml", "yml")
+	_ = renderCmd.RegisterFlagCompletionFunc("images", cobra.FixedCompletions([]string{"link", "embed", "copy"}, cobra.ShellCompDirectiveNoFileComp))
 }
 
 func runRender(cmd *cobra.Command, args []string) error {
-	filename := args[0]
-	recipe, err := readRecipeFile(filename)
+	source := args[0]
+	info, err := os.Stat(source)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", source, err)
+	}
+	if renderLocale != "" && !isBundledLocale(renderLocale) {
+		printWarning("No translation for locale %q; using English labels (available: %s)", renderLocale, strings.Join(renderers.Locales(), ", "))
+	}
+	switch renderers.IngredientGrouping(renderGroup) {
+	case renderers.GroupAll, renderers.GroupByStep, renderers.GroupBySection:
+	default:
+		return fmt.Errorf("unknown ingredient grouping: %s (use step or section)", renderGroup)
+	}
+	switch renderers.QuantityNote(renderNotes) {
+	case renderers.NoteNone, renderers.NoteServings, renderers.NoteOriginal, renderers.NotePerServing:
+	default:
+		return fmt.Errorf("unknown quantity notes: %s (use servings, original or per-serving)", renderNotes)
+	}
+	if renderEmail && !cmd.Flags().Changed("format") {
+		renderFormat = "html"
+	}
+	extension, ok := renderExtensions[strings.ToLower(renderFormat)]
+	if !ok {
+		return fmt.Errorf("unsupported format: %s (supported: cooklang, markdown, html, print, voice, ssml, mermaid, docx)", renderFormat)
+	}
+	if renderQRURL != "" && strings.ToLower(renderFormat) != "print" {
+		return fmt.Errorf("--qr-url only applies to the print format")
+	}
+	if renderEmail {
+		if strings.ToLower(renderFormat) != "html" {
+			return fmt.Errorf("--email only applies to the html format")
+		}
+		if renderTemplate != "" {
+			return fmt.Errorf("--email and --template cannot be used together")
+		}
+		if base, err := url.Parse(renderImageBase); renderImageBase != "" && (err != nil || !base.IsAbs()) {
+			return fmt.Errorf("--image-base-url must be an absolute URL, e.g. https://example.com/recipes/")
+		}
+	} else if renderImageBase != "" {
+		return fmt.Errorf("--image-base-url only applies with --email")
+	}
+	flavor, err := renderers.ParseMarkdownFlavor(renderFlavor)
 	if err != nil {
 		return err
 	}
+	if format := strings.ToLower(renderFormat); renderFlavor != "" && format != "markdown" && format != "md" {
+		return fmt.Errorf("--flavor only applies to the markdown format")
+	}
+	if flavor == renderers.MarkdownConfluence {
+		extension = ".txt"
+	}
+	if renderTemplate != "" {
+		name := renderers.HTMLTemplateName
+		switch strings.ToLower(renderFormat) {
+		case "html":
+		case "print":
+			name = renderers.PrintTemplateName
+		default:
+			return fmt.Errorf("--template only applies to the html and print formats")
+		}
+		// Check the templates once; each rendering reads them again, so --watch
+		// picks up template changes with the next recipe change
+		tmpl, err := renderers.ParseTemplateDir(renderTemplate)
+		if err != nil {
+			return err
+		}
+		if tmpl.Lookup(name) == nil {
+			return fmt.Errorf("%s has no %s template", renderTemplate, name)
+		}
+	}
+
+	// render writes one recipe to its output: the --output file, a file in the
+	// --output directory, or stdout
+	render := func(filename string) error {
+		return renderRecipeFile(filename, renderOutput)
+	}
+	if info.IsDir() {
+		if renderOutput == "" {
+			return fmt.Errorf("rendering a directory needs --output <dir>")
+		}
+		if !cmd.Flags().Changed("images") {
+			renderImages = string(cooklang.ImagesCopied)
+		}
+		render = func(filename string) error {
+			rel, err := filepath.Rel(source, filename)
+			if err != nil {
+				return err
+			}
+			return renderRecipeFile(filename, filepath.Join(renderOutput, filepath.FromSlash(batch.OutputPath(filepath.ToSlash(rel), extension))))
+		}
+	} else if renderWatch && renderOutput == "" && term.IsTerminal(int(os.Stdout.Fd())) {
+		// Show only the latest rendering
+		render = func(filename string) error {
+			fmt.Print("\x1b[H\x1b[2J")
+			return renderRecipeFile(filename, "")
+		}
+	}
+
+	watcher, err := newRenderWatcher(source, render)
+	if err != nil {
+		return err
+	}
+	if info.IsDir() && len(watcher.recipes()) == 0 && !renderWatch {
+		return fmt.Errorf("no .cook files found in %s", source)
+	}
+	var failed int
+	if info.IsDir() {
+		cmd.SilenceUsage = true
+		if failed, err = renderDirectory(source, extension); err != nil {
+			return err
+		}
+	} else {
+		for _, filename := range watcher.recipes() {
+			if err := render(filename); err != nil {
+				if !renderWatch {
+					return err
+				}
+				printWarning("%s: %v", filename, err)
+			}
+		}
+	}
+	if !renderWatch {
+		if failed > 0 {
+			return fmt.Errorf("%d of %d recipes could not be rendered", failed, len(watcher.recipes()))
+		}
+		return nil
+	}
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+	defer stop()
+	printInfo("Watching %s for changes (press Ctrl+C to stop)", source)
+	watcher.run(ctx, renderWatchInterval, renderWatchDebounce)
+	return nil
+}
+
+// renderExtensions maps the render formats to the file extensions used when
+// rendering a directory.
+var renderExtensions = map[string]string{
+	"cooklang": ".cook",
+	"cook":     ".cook",
+	"markdown": ".md",
+	"md":       ".md",
+	"html":     ".html",
+	"print":    ".html",
+	"voice":    ".json",
+	"ssml":     ".ssml",
+	"mermaid":  ".md",
+	"docx":     ".docx",
+}
+
+// renderDirectory renders every recipe of a directory into the --output
+// directory with the batch package, and returns how many failed.
+func renderDirectory(source, extension string) (int, error) {
+	renderer, options, err := renderSettings()
+	if err != nil {
+		return 0, err
+	}
+	format := strings.ToLower(renderFormat)
+	opts := batch.Options{
+		Output:          renderOutput,
+		Extension:       extension,
+		Workers:         renderJobs,
+		RendererOptions: options,
+		Parse:           parseOptions(),
+		Prepare:         prepareRenderRecipe,
+		Progress: func(p batch.Progress) {
+			printProgress(p.Done, p.Total, filepath.Join(source, filepath.FromSlash(p.Entry.Source)))
+			if p.Entry.Err != nil {
+				printWarning("%s: %v", filepath.Join(source, p.Entry.Source), p.Entry.Err)
+				return
+			}
+			printSuccess("Rendered to: %s", filepath.Join(renderOutput, filepath.FromSlash(p.Entry.Output)))
+		},
+	}
+	if format == "html" || format == "print" {
+		if opts.Images, err = cooklang.ParseImageMode(renderImages); err != nil {
+			return 0, err
+		}
+		opts.Index = true
+		if format == "html" && renderTemplate == "" && !renderEmail {
+			opts.Document = func(rendered string, recipe *cooklang.Recipe) string {
+				return wrapHTMLDocument(rendered, recipe, options.Language())
+			}
+		}
+	} else if format == "docx" {
+		opts.Images = cooklang.ImagesEmbedded
+	} else {
+		opts.Parse = append(opts.Parse, cooklang.WithoutImageDetection())
+	}
 
-	var output string
+	result, err := batch.Render(source, renderer, opts)
+	if err != nil {
+		return 0, err
+	}
+	if result.Index != "" {
+		printSuccess("Wrote index: %s", filepath.Join(renderOutput, result.Index))
+	}
+	return len(result.Failed), nil
+}
+
+// prepareRenderRecipe applies --transform and --temperature to a recipe.
+func prepareRenderRecipe(recipe *cooklang.Recipe) (*cooklang.Recipe, error) {
+	if renderTransform != "" {
+		pipeline, err := cooklang.ParseTransformPipeline(renderTransform)
+		if err != nil {
+			return nil, err
+		}
+		recipe, err = pipeline.Apply(recipe)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if renderTemp != "" && !strings.EqualFold(renderTemp, "both") {
+		scale, err := cooklang.ParseTemperatureScale(renderTemp)
+		if err != nil {
+			return nil, err
+		}
+		recipe = recipe.ConvertTemperaturesTo(scale)
+	}
+	return recipe, nil
+}
 
-	switch strings.ToLower(renderFormat) {
+// renderSettings returns the renderer and options selected by the render flags.
+func renderSettings() (renderers.Renderer, renderers.RendererOptions, error) {
+	options := renderers.RendererOptions{Locale: renderLocale, GroupIngredients: renderers.IngredientGrouping(renderGroup), QuantityNotes: renderers.QuantityNote(renderNotes), URL: renderQRURL, NutritionLabel: renderNutrition, BakersPercentages: renderBakers}
+	if renderFractions {
+		options.Quantities = &cooklang.FormatOptions{UnicodeFractions: true}
+	}
+	options.BothTemperatureScales = strings.EqualFold(renderTemp, "both")
+	if renderPreferred != "" {
+		prefs, err := cooklang.LoadPreferredUnits(renderPreferred)
+		if err != nil {
+			return nil, options, fmt.Errorf("failed to load preferred units: %w", err)
+		}
+		options.PreferredUnits = prefs
+	}
+
+	format := strings.ToLower(renderFormat)
+	var renderer renderers.Renderer
+	switch format {
 	case "cooklang", "cook":
-		renderer := renderers.NewCooklangRenderer()
-		output = renderer.RenderRecipe(recipe)
+		renderer = renderers.CooklangRenderer{}
 	case "markdown", "md":
-		renderer := renderers.NewMarkdownRenderer()
-		output = renderer.RenderRecipe(recipe)
+		flavor, err := renderers.ParseMarkdownFlavor(renderFlavor)
+		if err != nil {
+			return nil, options, err
+		}
+		renderer = renderers.MarkdownRenderer{Flavor: flavor}
 	case "html":
-		renderer := renderers.NewHTMLRenderer()
-		output = wrapHTMLDocument(renderer.RenderRecipe(recipe), recipe)
+		renderer = renderers.HTMLRenderer{}
+		if renderEmail {
+			renderer = renderers.EmailRenderer{ImageBaseURL: renderImageBase}
+		}
 	case "print":
-		renderer := renderers.NewPrintRenderer()
-		output = renderer.RenderRecipe(recipe)
+		renderer = renderers.PrintRenderer{}
+	case "voice":
+		renderer = renderers.VoiceRenderer{}
+	case "ssml":
+		renderer = renderers.SSMLRenderer{}
+	case "mermaid":
+		renderer = renderers.MermaidRenderer{}
+	case "docx":
+		renderer = renderers.DocxRenderer{}
 	default:
-		return fmt.Errorf("unsupported format: %s (supported: cooklang, markdown, html, print)", renderFormat)
+		return nil, options, fmt.Errorf("unsupported format: %s (supported: cooklang, markdown, html, print, voice, ssml, mermaid, docx)", renderFormat)
+	}
+	if renderTemplate != "" {
+		tmpl, err := renderers.ParseTemplateDir(renderTemplate)
+		if err != nil {
+			return nil, options, err
+		}
+		if format == "print" {
+			renderer = renderers.PrintRenderer{Template: tmpl}
+		} else {
+			renderer = renderers.HTMLRenderer{Template: tmpl}
+		}
+	}
+	return renderer, options, nil
+}
+
+// renderRecipeFile renders one recipe with the render flags and writes it to
+// output, or to stdout if output is empty.
+func renderRecipeFile(filename, output string) error {
+	recipe, err := readRecipeFile(filename)
+	if err != nil {
+		return err
+	}
+	if recipe, err = prepareRenderRecipe(recipe); err != nil {
+		return err
+	}
+	renderer, options, err := renderSettings()
+	if err != nil {
+		return err
+	}
+
+	format := strings.ToLower(renderFormat)
+	if format == "html" || format == "print" {
+		recipe.AddDetectedImages(filename)
+		mode, err := cooklang.ParseImageMode(renderImages)
+		if err != nil {
+			return err
+		}
+		options.Images, err = cooklang.ImageSources(recipe, filepath.Dir(filename), output, mode)
+		if err != nil {
+			return err
+		}
+		if err := cooklang.SetStepImageSources(recipe, filepath.Dir(filename), output, mode); err != nil {
+			return err
+		}
+	} else if format == "docx" {
+		if output == "" && term.IsTerminal(int(os.Stdout.Fd())) {
+			return fmt.Errorf("the docx format writes a binary file; use --output <file>.docx")
+		}
+		recipe.AddDetectedImages(filename)
+		options.Images, err = cooklang.ImageSources(recipe, filepath.Dir(filename), output, cooklang.ImagesEmbedded)
+		if err != nil {
+			return err
+		}
+	}
+
+	rendered, err := renderer.Render(recipe, options)
+	if err != nil {
+		return err
+	}
+	if format == "html" && renderTemplate == "" && !renderEmail {
+		rendered = wrapHTMLDocument(rendered, recipe, options.Language())
 	}
 
 	// Output to file or stdout
-	if renderOutput != "" {
+	if output != "" {
 		// Create directory if it doesn't exist
-		dir := filepath.Dir(renderOutput)
+		dir := filepath.Dir(output)
 		if dir != "." && dir != "" {
 			if err := os.MkdirAll(dir, 0o755); err != nil {
 				return fmt.Errorf("failed to create output directory: %w", err)
 			}
 		}
 
-		if err := os.WriteFile(renderOutput, []byte(output), 0o644); err != nil {
+		if err := os.WriteFile(output, []byte(rendered), 0o644); err != nil {
 			return fmt.Errorf("failed to write output file: %w", err)
 		}
-		printSuccess("Rendered to: %s", renderOutput)
+		printSuccess("Rendered to: %s", output)
+	} else if format == "docx" {
+		fmt.Print(rendered)
 	} else {
-		fmt.Println(output)
+		fmt.Println(rendered)
 	}
 
 	return nil
 }
+
+// isBundledLocale reports whether renderers has translations for a locale such as "de" or "fr-CA".
+func isBundledLocale(locale string) bool {
+	language, _, _ := strings.Cut(strings.ReplaceAll(strings.ToLower(locale), "_", "-"), "-")
+	_, ok := renderers.Translations[language]
+	return ok
+}
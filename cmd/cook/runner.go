@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/hilli/cooklang"
+)
+
+// fileError is the failure of one file in a bulk operation
+type fileError struct {
+	File string
+	Err  error
+}
+
+func (e fileError) Error() string {
+	return fmt.Sprintf("%s: %v", e.File, e.Err)
+}
+
+func (e fileError) Unwrap() error {
+	return e.Err
+}
+
+// multiError collects the failures of a bulk operation, so that one broken file
+// does not stop the others. With several files, each failure is reported as a
+// warning when it happens and the error only sums them up.
+type multiError struct {
+	Errors []fileError
+	Total  int // Files in the operation
+}
+
+func (e *multiError) Error() string {
+	if e.Total == 1 {
+		return e.Errors[0].Error()
+	}
+	return fmt.Sprintf("%d of %d files failed", len(e.Errors), e.Total)
+}
+
+// Unwrap returns the failures, for errors.Is and errors.As
+func (e *multiError) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i, err := range e.Errors {
+		errs[i] = err
+	}
+	return errs
+}
+
+// add records the failure of a file, reporting it as a warning unless it is the
+// only file
+func (e *multiError) add(file string, err error) {
+	e.Errors = append(e.Errors, fileError{File: file, Err: err})
+	if e.Total > 1 {
+		printWarning("%s: %v", file, err)
+	}
+}
+
+// err returns the collected failures, or nil if there were none
+func (e *multiError) err() error {
+	if len(e.Errors) == 0 {
+		return nil
+	}
+	return e
+}
+
+// forEachFile runs fn for every file of a bulk operation, reporting progress with
+// --progress. Failures do not stop the other files: they are reported as warnings
+// and returned together as a *multiError.
+func forEachFile(files []string, fn func(file string) error) error {
+	errs := &multiError{Total: len(files)}
+	for i, file := range files {
+		printProgress(i+1, len(files), file)
+		if err := fn(file); err != nil {
+			errs.add(file, err)
+		}
+	}
+	return errs.err()
+}
+
+// printProgress reports that a bulk operation reached a file (only shown with
+// --progress)
+func printProgress(done, total int, file string) {
+	if !progressMode || quietMode {
+		return
+	}
+	printDiagnostic(colorGray, "›", "[%d/%d] %s", done, total, file)
+}
+
+// readRecipeFiles reads and parses recipe files, skipping those that fail. It
+// returns the recipes with the files they came from, and a *multiError if any
+// file failed.
+func readRecipeFiles(filenames []string) ([]*cooklang.Recipe, []string, error) {
+	recipes := make([]*cooklang.Recipe, 0, len(filenames))
+	read := make([]string, 0, len(filenames))
+	err := forEachFile(filenames, func(filename string) error {
+		recipe, err := readRecipeFile(filename)
+		if err != nil {
+			return err
+		}
+		recipes = append(recipes, recipe)
+		read = append(read, filename)
+		return nil
+	})
+	return recipes, read, err
+}
@@ -15,20 +15,27 @@ import (
 var (
 	scaleServings int
 	scaleFactor   float64
+	scaleYield    string
 	scaleUnit     string
+	scaleProfile  string
 	scaleOutput   string
 	scaleFormat   string
 	scaleJSON     bool
+	scaleTimers   bool
+	scaleCookware bool
+	scaleRound    string
 )
 
 var scaleCmd = &cobra.Command{
-	Use:   "scale <recipe.cook> [--servings N | --factor F]",
+	Use:   "scale <recipe.cook> [--servings N | --factor F | --yield \"N unit\"]",
 	Short: "Scale a recipe's ingredients",
-	Long: `Scale a recipe's ingredients by servings or a custom factor.
+	Long: `Scale a recipe's ingredients by servings, yield or a custom factor.
 
 The scale command adjusts all ingredient quantities in a recipe based on:
   - Target number of servings (--servings)
   - Custom scaling factor (--factor)
+  - Target yield (--yield), for recipes with yield metadata such as
+    "yield: 24 cookies"
 
 Examples:
   # Scale to 4 servings
@@ -37,6 +44,9 @@ Examples:
   # Scale by 1.5x
   cook scale recipe.cook --factor 1.5
 
+  # Make 48 cookies from a recipe that makes 24
+  cook scale cookies.cook --yield "48 cookies"
+
   # Scale and convert units
   cook scale recipe.cook --servings 6 --unit metric
 
@@ -44,10 +54,16 @@ Examples:
   cook scale recipe.cook --servings 2 --output scaled.cook
 
   # Scale and output as JSON
-  cook scale recipe.cook --factor 0.5 --json`,
+  cook scale recipe.cook --factor 0.5 --json
+
+  # Also scale timers and cookware counts
+  cook scale recipe.cook --factor 3 --scale-timers --scale-cookware
+
+  # Halve the recipe, rounding eggs and other counted items up to whole ones
+  cook scale recipe.cook --factor 0.5 --round-counts up`,
 	Args:              cobra.ExactArgs(1),
 	RunE:              runScale,
-	ValidArgsFunction: completeCookFiles,
+	ValidArgsFunction: completeCookFile,
 }
 
 func init() {
@@ -55,15 +71,22 @@ func init() {
 
 	scaleCmd.Flags().IntVarP(&scaleServings, "servings", "s", 0, "Target number of servings")
 	scaleCmd.Flags().Float64VarP(&scaleFactor, "factor", "f", 0, "Scaling factor (e.g., 0.5 for half, 2 for double)")
+	scaleCmd.Flags().StringVarP(&scaleYield, "yield", "y", "", "Target yield (e.g., \"48 cookies\" or 3%loaves)")
 	scaleCmd.Flags().StringVarP(&scaleUnit, "unit", "u", "", "Convert to unit system (metric/imperial)")
+	scaleCmd.Flags().StringVar(&scaleProfile, "profile", "auto", "How --unit rounds and picks units: precise, bartender, baking, auto")
 	scaleCmd.Flags().StringVarP(&scaleOutput, "output", "o", "", "Output file (default: stdout)")
 	scaleCmd.Flags().StringVar(&scaleFormat, "format", "cooklang", "Output format: cooklang, markdown, html, json")
 	scaleCmd.Flags().BoolVar(&scaleJSON, "json", false, "Output as JSON")
+	scaleCmd.Flags().BoolVar(&scaleTimers, "scale-timers", false, "Scale timer durations proportionally")
+	scaleCmd.Flags().BoolVar(&scaleCookware, "scale-cookware", false, "Scale cookware counts proportionally (rounded up)")
+	scaleCmd.Flags().StringVar(&scaleRound, "round-counts", "keep", "Round counted ingredients such as eggs: keep, up, half")
 
 	// Register flag completions
 	_ = scaleCmd.RegisterFlagCompletionFunc("servings", completeServingsFlag)
 	_ = scaleCmd.RegisterFlagCompletionFunc("unit", completeUnitFlag)
-	_ = scaleCmd.RegisterFlagCompletionFunc("format", completeFormatFlag)
+	_ = scaleCmd.RegisterFlagCompletionFunc("profile", completeProfileFlag)
+	_ = scaleCmd.RegisterFlagCompletionFunc("round-counts", completeCountRoundingFlag)
+	_ = scaleCmd.RegisterFlagCompletionFunc("format", completeFormats("cooklang", "markdown", "html", "json"))
 }
 
 func runScale(cmd *cobra.Command, args []string) error {
@@ -74,11 +97,21 @@ func runScale(cmd *cobra.Command, args []string) error {
 	}
 
 	// Validate scaling parameters
-	if scaleServings == 0 && scaleFactor == 0 {
-		return fmt.Errorf("must specify either --servings or --factor")
+	given := 0
+	for _, set := range []bool{scaleServings > 0, scaleFactor > 0, scaleYield != ""} {
+		if set {
+			given++
+		}
 	}
-	if scaleServings > 0 && scaleFactor > 0 {
-		return fmt.Errorf("cannot specify both --servings and --factor")
+	if given == 0 {
+		return fmt.Errorf("must specify either --servings, --factor or --yield")
+	}
+	if given > 1 {
+		return fmt.Errorf("cannot specify more than one of --servings, --factor and --yield")
+	}
+	rounding, err := cooklang.ParseCountRounding(scaleRound)
+	if err != nil {
+		return err
 	}
 
 	// Scale the recipe using library methods
@@ -94,16 +127,29 @@ func runScale(cmd *cobra.Command, args []string) error {
 		}
 		scale = float64(scaleServings) / float64(originalServings)
 		printInfo("Scaling from %.0f to %d servings (factor: %.2fx)", originalServings, scaleServings, scale)
-		scaledRecipe = recipe.ScaleToServings(float64(scaleServings))
+	} else if scaleYield != "" {
+		quantity, unit := cooklang.ParseYield(scaleYield)
+		if quantity <= 0 {
+			return fmt.Errorf("invalid yield %q (use a quantity and unit, e.g. \"48 cookies\")", scaleYield)
+		}
+		if scale, err = cooklang.ScaleByYield(recipe, quantity, unit); err != nil {
+			return err
+		}
+		target := cooklang.Yield{Quantity: quantity, Unit: unit}
+		printInfo("Scaling from %s to %s (factor: %.2fx)", recipe.Yield, target, scale)
 	} else {
 		scale = scaleFactor
 		printInfo("Scaling by factor: %.2fx", scale)
-		scaledRecipe = recipe.Scale(scaleFactor)
 	}
+	scaledRecipe = recipe.ScaleWithOptions(scale, cooklang.ScaleOptions{
+		ScaleTimers:   scaleTimers,
+		ScaleCookware: scaleCookware,
+		CountRounding: rounding,
+	})
 
 	// Apply unit conversion if requested
 	if scaleUnit != "" {
-		if err := convertRecipeUnits(scaledRecipe, scaleUnit); err != nil {
+		if err := convertRecipeUnits(scaledRecipe, scaleUnit, scaleProfile); err != nil {
 			return fmt.Errorf("unit conversion failed: %w", err)
 		}
 		printInfo("Converted to %s units", scaleUnit)
@@ -145,7 +191,8 @@ func runScale(cmd *cobra.Command, args []string) error {
 }
 
 // convertRecipeUnits converts all ingredients in a recipe to the specified unit system
-func convertRecipeUnits(recipe *cooklang.Recipe, unitSystem string) error {
+// with the named conversion profile
+func convertRecipeUnits(recipe *cooklang.Recipe, unitSystem, profileName string) error {
 	ingredients := recipe.GetIngredients()
 	if ingredients == nil || len(ingredients.Ingredients) == 0 {
 		return nil
@@ -163,12 +210,16 @@ func convertRecipeUnits(recipe *cooklang.Recipe, unitSystem string) error {
 	default:
 		return fmt.Errorf("unknown unit system: %s (use metric, imperial, or us)", unitSystem)
 	}
+	profile, err := conversionProfile(profileName, recipe)
+	if err != nil {
+		return err
+	}
 
 	// Convert ingredients in-place by walking the recipe steps
 	for step := recipe.FirstStep; step != nil; step = step.NextStep {
 		for component := step.FirstComponent; component != nil; component = component.GetNext() {
 			if ing, ok := component.(*cooklang.Ingredient); ok {
-				converted := ing.ConvertToSystem(system)
+				converted := ing.ConvertToSystemWithProfile(system, profile)
 				ing.Quantity = converted.Quantity
 				ing.Unit = converted.Unit
 				ing.TypedUnit = converted.TypedUnit
@@ -184,13 +235,17 @@ func formatScaledRecipe(recipe *cooklang.Recipe, format string) (string, error)
 	switch strings.ToLower(format) {
 	case "cooklang", "cook":
 		renderer := renderers.NewCooklangRenderer()
-		return renderer.RenderRecipe(recipe), nil
+		return renderer.RenderRecipe(recipe)
 	case "markdown", "md":
 		renderer := renderers.NewMarkdownRenderer()
-		return renderer.RenderRecipe(recipe), nil
+		return renderer.RenderRecipe(recipe)
 	case "html":
 		renderer := renderers.NewHTMLRenderer()
-		return wrapHTMLDocument(renderer.RenderRecipe(recipe), recipe), nil
+		body, err := renderer.RenderRecipe(recipe)
+		if err != nil {
+			return "", err
+		}
+		return wrapHTMLDocument(body, recipe, "en"), nil
 	case "json":
 		return formatScaledJSON(recipe, 1.0)
 	default:
@@ -214,7 +269,7 @@ func formatScaledJSON(recipe *cooklang.Recipe, factor float64) (string, error) {
 				"name":    ing.Name,
 				"display": ing.RenderDisplay(),
 			}
-			if ing.Quantity != 0 {
+			if !ing.Quantity.IsZero() {
 				ingMap["quantity"] = ing.Quantity
 			}
 			if ing.Unit != "" {
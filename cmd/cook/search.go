@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hilli/cooklang"
+	"github.com/spf13/cobra"
+)
+
+var (
+	searchDir   string
+	searchIndex string
+	searchLimit int
+	searchJSON  bool
+)
+
+var searchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Search a recipe collection",
+	Long: `Search the recipes in a directory and list matches, most relevant first.
+
+Plain words must each appear in the title, tags, cuisine, ingredients or
+description. Qualifiers narrow the search:
+
+  tag:cocktail         recipe has the tag
+  cuisine:italian      recipe is of the cuisine
+  ingredient:gin       recipe uses the ingredient (also i:gin; fuzzy)
+  -ingredient:olives   recipe does not use the ingredient (also -i:olives)
+  title:negroni        title contains the text
+  time:30m             total time is at most 30 minutes (also time<30m)
+  difficulty:easy      difficulty is easy, medium or hard (also
+                       difficulty:easy,medium)
+
+Use --index to keep a search index on disk; only changed recipes are
+re-parsed on the next search, which speeds up large collections.
+
+Examples:
+  cook search gin
+  cook search "tag:cocktail i:gin time<10m" --dir ~/recipes
+  cook search "difficulty:easy tag:dessert"
+  cook search 'ingredient:"lime juice" -i:rum' --limit 5
+  cook search chicken --dir ~/recipes --index ~/recipes/.cook-index.json`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runSearch,
+}
+
+func init() {
+	searchCmd.Flags().StringVarP(&searchDir, "dir", "d", ".", "Recipe directory to search")
+	searchCmd.Flags().StringVar(&searchIndex, "index", "", "Search index file to load and update")
+	searchCmd.Flags().IntVarP(&searchLimit, "limit", "n", 0, "Maximum number of results (0 for all)")
+	searchCmd.Flags().BoolVarP(&searchJSON, "json", "j", false, "Output as JSON")
+	_ = searchCmd.MarkFlagDirname("dir")
+	rootCmd.AddCommand(searchCmd)
+}
+
+func runSearch(cmd *cobra.Command, args []string) error {
+	var index *cooklang.SearchIndex
+	var err error
+	if searchIndex != "" {
+		index, err = cooklang.LoadSearchIndex(searchDir, searchIndex)
+	} else {
+		index, err = cooklang.BuildSearchIndex(searchDir)
+	}
+	if err != nil {
+		if len(index.Recipes) == 0 {
+			return fmt.Errorf("failed to index recipes in %s: %w", searchDir, err)
+		}
+		printWarning("Some recipes could not be indexed: %v", err)
+	}
+	if searchIndex != "" {
+		if err := index.Save(searchIndex); err != nil {
+			printWarning("Could not save search index: %v", err)
+		} else {
+			printVerbose("Saved search index to %s", searchIndex)
+		}
+	}
+
+	query, err := index.ParseQuery(strings.Join(args, " "))
+	if err != nil {
+		return err
+	}
+	results := query.Limit(searchLimit).Results()
+
+	if searchJSON {
+		return outputJSON(results)
+	}
+
+	for _, result := range results {
+		fmt.Printf("%s (%s)\n", result.Recipe.Title, result.Recipe.Path)
+		printVerbose("  score %.2f: %s", result.Score, strings.Join(result.Matches, ", "))
+	}
+	printInfo("%d of %d recipes match", len(results), len(index.Recipes))
+	return nil
+}
@@ -0,0 +1,495 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"html"
+	"io/fs"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hilli/cooklang"
+	"github.com/hilli/cooklang/cooklangpb"
+	"github.com/hilli/cooklang/renderers"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+)
+
+var (
+	serveAddr     string
+	serveNoReload bool
+	serveGRPCAddr string
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve [dir]",
+	Short: "Browse a recipe collection in a web browser",
+	Long: `Start a local web server that renders the recipes in a directory.
+
+The index page lists all recipes and supports the same queries as
+'cook search' (e.g. "i:gin time<10m") plus filtering by tag. Recipe pages
+are rendered with the HTML renderer, embed Schema.org JSON-LD, and show the
+recipe's images, which are served from the recipe directory.
+
+A JSON API for other apps is served under /api:
+  GET  /api/recipes                       list recipes (?q=query, ?tag=tag)
+  GET  /api/recipes/{slug}                one recipe (?scale=2, ?servings=4,
+                                          ?transform=units=metric)
+  GET  /api/recipes/{slug}/shopping-list  shopping list (same parameters)
+  POST /api/shopping-list                 {"recipes": [{"slug": "Negroni",
+                                          "scale": 2}], "units": "metric"}
+
+Pages reload automatically when .cook files are added, changed or removed
+(disable with --no-reload).
+
+With --grpc, the CooklangService gRPC service (Parse, Render and
+ShoppingList; see cooklangpb/cooklang.proto) is served on a second address.
+
+Examples:
+  cook serve
+  cook serve ~/recipes
+  cook serve ~/recipes --addr :9000
+  cook serve ~/recipes --grpc localhost:9090`,
+	Args:              cobra.MaximumNArgs(1),
+	RunE:              runServe,
+	ValidArgsFunction: completeDirectory,
+}
+
+func init() {
+	serveCmd.Flags().StringVarP(&serveAddr, "addr", "a", "localhost:8080", "Address to listen on")
+	serveCmd.Flags().BoolVar(&serveNoReload, "no-reload", false, "Do not reload pages when recipe files change")
+	serveCmd.Flags().StringVar(&serveGRPCAddr, "grpc", "", "Also serve the gRPC API on this address (e.g. localhost:9090)")
+	rootCmd.AddCommand(serveCmd)
+}
+
+func runServe(cmd *cobra.Command, args []string) error {
+	dir := "."
+	if len(args) > 0 {
+		dir = args[0]
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", dir)
+	}
+
+	server, err := newRecipeServer(dir, !serveNoReload)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+	defer stop()
+	if server.liveReload {
+		go server.watch(ctx, time.Second)
+	}
+
+	httpServer := &http.Server{Addr: serveAddr, Handler: server, ReadHeaderTimeout: 10 * time.Second}
+	httpServer.RegisterOnShutdown(server.close)
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = httpServer.Shutdown(shutdownCtx)
+	}()
+
+	if serveGRPCAddr != "" {
+		grpcServer, err := startGRPCServer(ctx, serveGRPCAddr)
+		if err != nil {
+			return err
+		}
+		defer grpcServer.Stop()
+		printSuccess("Serving the gRPC API at %s", serveGRPCAddr)
+	}
+
+	printSuccess("Serving %d recipes from %s at http://%s/", server.recipeCount(), dir, serveAddr)
+	if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// startGRPCServer serves the CooklangService on addr until ctx is done.
+func startGRPCServer(ctx context.Context, addr string) (*grpc.Server, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen for gRPC: %w", err)
+	}
+	grpcServer := grpc.NewServer()
+	cooklangpb.RegisterCooklangServiceServer(grpcServer, cooklangpb.NewServer())
+	go func() {
+		if err := grpcServer.Serve(listener); err != nil {
+			printWarning("gRPC server stopped: %v", err)
+		}
+	}()
+	go func() {
+		<-ctx.Done()
+		grpcServer.GracefulStop()
+	}()
+	return grpcServer, nil
+}
+
+// recipeServer serves a recipe collection as a browsable site.
+type recipeServer struct {
+	dir        string
+	liveReload bool
+	mux        *http.ServeMux
+
+	mu          sync.RWMutex
+	library     *cooklang.Library
+	index       *cooklang.SearchIndex
+	fingerprint uint64
+	changed     chan struct{} // Closed when the recipes are reloaded
+	closed      chan struct{} // Closed when the server shuts down
+	closeOnce   sync.Once
+}
+
+// newRecipeServer loads the recipes in dir and sets up the routes.
+func newRecipeServer(dir string, liveReload bool) (*recipeServer, error) {
+	s := &recipeServer{
+		dir:        dir,
+		liveReload: liveReload,
+		mux:        http.NewServeMux(),
+		changed:    make(chan struct{}),
+		closed:     make(chan struct{}),
+	}
+	s.mux.HandleFunc("GET /{$}", s.handleIndex)
+	s.mux.HandleFunc("GET /recipe/{path...}", s.handleRecipe)
+	s.mux.HandleFunc("GET /images/{path...}", s.handleImage)
+	if liveReload {
+		s.mux.HandleFunc("GET /events", s.handleEvents)
+	}
+	s.registerAPI()
+
+	fingerprint, err := recipeFingerprint(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan %s: %w", dir, err)
+	}
+	if err := s.reload(fingerprint); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *recipeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	printVerbose("%s %s", r.Method, r.URL.Path)
+	s.mux.ServeHTTP(w, r)
+}
+
+// reload parses the collection again and notifies live reload listeners.
+func (s *recipeServer) reload(fingerprint uint64) error {
+	library, err := cooklang.LoadLibrary(s.dir)
+	if err != nil {
+		if library.Len() == 0 && s.library == nil {
+			return fmt.Errorf("failed to load recipes from %s: %w", s.dir, err)
+		}
+		printWarning("Some recipes could not be loaded: %v", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.library = library
+	s.index = library.Index()
+	s.fingerprint = fingerprint
+	close(s.changed)
+	s.changed = make(chan struct{})
+	return nil
+}
+
+// refresh reloads the collection if any .cook file was added, changed or removed.
+func (s *recipeServer) refresh() (bool, error) {
+	fingerprint, err := recipeFingerprint(s.dir)
+	if err != nil {
+		return false, err
+	}
+	s.mu.RLock()
+	unchanged := fingerprint == s.fingerprint
+	s.mu.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+	return true, s.reload(fingerprint)
+}
+
+// watch checks for changed recipes every interval until ctx is done.
+func (s *recipeServer) watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if changed, err := s.refresh(); err != nil {
+				printWarning("Could not check for recipe changes: %v", err)
+			} else if changed {
+				printInfo("Recipes changed, reloaded %d recipes", s.recipeCount())
+			}
+		}
+	}
+}
+
+// close ends open live reload connections so the server can shut down.
+func (s *recipeServer) close() {
+	s.closeOnce.Do(func() { close(s.closed) })
+}
+
+func (s *recipeServer) recipeCount() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.library.Len()
+}
+
+// recipeFingerprint hashes the names, sizes and modification times of all .cook files under dir.
+func recipeFingerprint(dir string) (uint64, error) {
+	hash := fnv.New64a()
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if p != dir && strings.HasPrefix(d.Name(), ".") {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if filepath.Ext(p) != ".cook" {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(hash, "%s\x00%d\x00%d\n", p, info.Size(), info.ModTime().UnixNano())
+		return nil
+	})
+	return hash.Sum64(), err
+}
+
+func (s *recipeServer) handleIndex(w http.ResponseWriter, r *http.Request) {
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	tag := strings.TrimSpace(r.URL.Query().Get("tag"))
+
+	s.mu.RLock()
+	library, index := s.library, s.index
+	s.mu.RUnlock()
+
+	var body strings.Builder
+	body.WriteString("<h1>Recipes</h1>\n")
+	fmt.Fprintf(&body, "<form class=\"search\" action=\"/\"><input type=\"search\" name=\"q\" value=\"%s\" placeholder=\"Search, e.g. i:gin time&lt;10m\" autofocus>", html.EscapeString(query))
+	if tag != "" {
+		fmt.Fprintf(&body, "<input type=\"hidden\" name=\"tag\" value=\"%s\">", html.EscapeString(tag))
+	}
+	body.WriteString("<button>Search</button></form>\n")
+
+	if tags := library.Tags(); len(tags) > 0 {
+		body.WriteString("<nav class=\"tags\">")
+		for _, t := range tags {
+			class := ""
+			if strings.EqualFold(t, tag) {
+				class = " class=\"active\""
+			}
+			fmt.Fprintf(&body, "<a href=\"%s\"%s>%s</a> ", html.EscapeString(indexURL(query, t)), class, html.EscapeString(t))
+		}
+		if tag != "" {
+			fmt.Fprintf(&body, "<a href=\"%s\">all</a>", html.EscapeString(indexURL(query, "")))
+		}
+		body.WriteString("</nav>\n")
+	}
+
+	entries := library.Entries
+	if query != "" || tag != "" {
+		q, err := index.ParseQuery(query)
+		if err != nil {
+			fmt.Fprintf(&body, "<p class=\"error\">%s</p>\n", html.EscapeString(err.Error()))
+			s.writePage(w, "Recipes", "", body.String())
+			return
+		}
+		if tag != "" {
+			q.Tag(tag)
+		}
+		entries = nil
+		for _, result := range q.Results() {
+			entries = append(entries, result.Entry)
+		}
+	}
+
+	if len(entries) == 0 {
+		body.WriteString("<p>No recipes found.</p>\n")
+	} else {
+		body.WriteString("<ul class=\"recipes\">\n")
+		for _, entry := range entries {
+			fmt.Fprintf(&body, "  <li><a href=\"%s\">%s</a>", html.EscapeString(recipeURL(entry.Path)), html.EscapeString(entry.Name()))
+			if entry.Recipe != nil && len(entry.Recipe.Tags) > 0 {
+				fmt.Fprintf(&body, " <span class=\"recipe-tags\">%s</span>", html.EscapeString(strings.Join(entry.Recipe.Tags, ", ")))
+			}
+			body.WriteString("</li>\n")
+		}
+		body.WriteString("</ul>\n")
+	}
+	s.writePage(w, "Recipes", "", body.String())
+}
+
+func (s *recipeServer) handleRecipe(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	entry, ok := s.library.Get(r.PathValue("path"))
+	s.mu.RUnlock()
+	if !ok || entry.Recipe == nil {
+		http.NotFound(w, r)
+		return
+	}
+	recipe := entry.Recipe
+
+	var images, imageURLs []string
+	for _, img := range recipe.Images {
+		src := recipeImageURL(entry.Path, img)
+		images = append(images, fmt.Sprintf("<img class=\"recipe-image\" src=\"%s\" alt=\"%s\">", html.EscapeString(src), html.EscapeString(entry.Name())))
+		if strings.HasPrefix(src, "/") {
+			src = "http://" + r.Host + src
+		}
+		imageURLs = append(imageURLs, src)
+	}
+
+	head, err := renderers.JSONLDRenderer{}.RenderRecipeScriptTag(recipe, &renderers.JSONLDOptions{
+		URL:    "http://" + r.Host + recipeURL(entry.Path),
+		Images: imageURLs,
+	})
+	if err != nil {
+		printWarning("Could not render JSON-LD for %s: %v", entry.Path, err)
+		head = ""
+	}
+
+	// Step images are served like the recipe's own images
+	page := recipe.Clone()
+	for step := page.FirstStep; step != nil; step = step.NextStep {
+		for i, img := range step.Images {
+			step.Images[i] = recipeImageURL(entry.Path, img)
+		}
+	}
+	recipeHTML, err := renderers.HTMLRenderer{}.RenderRecipe(page)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	body := "<p><a href=\"/\">← All recipes</a></p>\n" + strings.Join(images, "\n") + "\n" + recipeHTML
+	s.writePage(w, entry.Name(), head, body)
+}
+
+func (s *recipeServer) handleImage(w http.ResponseWriter, r *http.Request) {
+	p := r.PathValue("path")
+	if !filepath.IsLocal(filepath.FromSlash(p)) || !cooklang.IsImageFile(p) {
+		http.NotFound(w, r)
+		return
+	}
+	http.ServeFile(w, r, filepath.Join(s.dir, filepath.FromSlash(p)))
+}
+
+// handleEvents streams a "reload" server-sent event when the recipes change.
+func (s *recipeServer) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+	s.mu.RLock()
+	changed := s.changed
+	s.mu.RUnlock()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	_, _ = fmt.Fprint(w, ": connected\n\n")
+	flusher.Flush()
+
+	select {
+	case <-changed:
+		_, _ = fmt.Fprint(w, "data: reload\n\n")
+		flusher.Flush()
+	case <-r.Context().Done():
+	case <-s.closed:
+	}
+}
+
+// writePage writes a complete HTML page around the body.
+func (s *recipeServer) writePage(w http.ResponseWriter, title, head, body string) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	var page strings.Builder
+	page.WriteString("<!DOCTYPE html>\n<html lang=\"en\">\n<head>\n")
+	page.WriteString("<meta charset=\"UTF-8\">\n<meta name=\"viewport\" content=\"width=device-width, initial-scale=1.0\">\n")
+	fmt.Fprintf(&page, "<title>%s</title>\n", html.EscapeString(title))
+	page.WriteString(serveStyles)
+	if head != "" {
+		page.WriteString(head + "\n")
+	}
+	if s.liveReload {
+		page.WriteString("<script>new EventSource(\"/events\").onmessage = function () { location.reload(); };</script>\n")
+	}
+	page.WriteString("</head>\n<body>\n")
+	page.WriteString(body)
+	page.WriteString("</body>\n</html>\n")
+	_, _ = w.Write([]byte(page.String()))
+}
+
+// indexURL returns the URL of the index page with a search query and tag filter.
+func indexURL(query, tag string) string {
+	values := url.Values{}
+	if query != "" {
+		values.Set("q", query)
+	}
+	if tag != "" {
+		values.Set("tag", tag)
+	}
+	if len(values) == 0 {
+		return "/"
+	}
+	return "/?" + values.Encode()
+}
+
+// recipeURL returns the page URL of a recipe, e.g. "/recipe/sauces/Hollandaise".
+func recipeURL(entryPath string) string {
+	return "/recipe/" + escapePath(strings.TrimSuffix(entryPath, ".cook"))
+}
+
+// recipeImageURL returns the URL of an image listed by a recipe. Image paths are
+// relative to the recipe's directory; absolute URLs are returned unchanged.
+func recipeImageURL(entryPath, image string) string {
+	if strings.Contains(image, "://") {
+		return image
+	}
+	return "/images/" + escapePath(path.Join(path.Dir(entryPath), filepath.ToSlash(image)))
+}
+
+// escapePath escapes each segment of a slash-separated path for use in a URL.
+func escapePath(p string) string {
+	segments := strings.Split(p, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+const serveStyles = `<style>
+  body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", sans-serif; max-width: 50em; margin: 2em auto; padding: 0 1em; line-height: 1.5; color: #222; }
+  a { color: #a0522d; }
+  .search input { width: 70%; padding: 0.4em; font-size: 1em; }
+  .search button { padding: 0.4em 1em; font-size: 1em; }
+  .tags { margin: 1em 0; }
+  .tags a { display: inline-block; margin: 0 0.3em 0.3em 0; padding: 0.1em 0.6em; border-radius: 1em; background: #f3ebe4; text-decoration: none; }
+  .tags a.active { background: #a0522d; color: #fff; }
+  .recipes li { margin: 0.3em 0; }
+  .recipe-tags { color: #888; font-size: 0.85em; }
+  .recipe-image { max-width: 100%; border-radius: 6px; }
+  .step-image { display: block; max-width: 100%; max-height: 20em; margin: 0.5em 0; border-radius: 6px; }
+  .error { color: #b00020; }
+  .ingredient, .cookware, .timer, .temperature { font-weight: 600; }
+  .reserved-callout { border-left: 3px solid #a0522d; padding-left: 0.5em; color: #555; }
+  .recipe-note { margin: 1em 0; padding: 0.5em 1em; border-left: 3px solid #ccc; background: #faf7f2; font-style: italic; color: #555; }
+</style>
+`
@@ -2,7 +2,9 @@ package main
 
 import (
 	"fmt"
-	"sort"
+	"io"
+	"os"
+	"strings"
 
 	"github.com/hilli/cooklang"
 	"github.com/spf13/cobra"
@@ -14,12 +16,23 @@ var (
 	shoppingListServings int
 	shoppingListUnit     string
 	shoppingListSimple   bool
+	shoppingListExport   string
+	shoppingListOutput   string
+	shoppingListWebhook  string
+	shoppingListHeaders  []string
+	shoppingListSort     string
+	shoppingListAisle    string
+	shoppingListFraction bool
+	shoppingListProfile  string
+	shoppingListPrefer   string
+	shoppingListRound    string
+	shoppingListFormat   string
 )
 
 var shoppingListCmd = &cobra.Command{
 	Use:     "shopping-list <recipe-files...>",
 	Short:   "Create a shopping list from multiple recipes",
-	Aliases: []string{"shop", "list"},
+	Aliases: []string{"shop"},
 	Long: `Create a consolidated shopping list from one or more recipe files.
 
 Automatically consolidates ingredients with the same name and compatible units.
@@ -28,6 +41,27 @@ Perfect for meal planning and batch cooking.
 Options:
   --servings N  Scale each recipe to N servings before combining (ideal for meal planning)
   --scale F     Scale the final shopping list by factor F (for batch cooking)
+  --sort ORDER  Order items alphabetically, by recipe, or by aisle. The list is grouped
+                by aisle by default; --simple, --json and --export sort by name.
+  --round-counts R
+                Round eggs and other counted items after scaling: keep (default),
+                up to whole items, or to the nearest half
+  --profile P   How --unit rounds and picks units: precise, bartender (30 ml per oz,
+                dashes, barspoons), baking, or auto (default: bartender when every
+                recipe is a cocktail, precise otherwise)
+  --unicode-fractions
+                Write quantities as fractions such as 1½ and ¾ (not in --json or csv)
+  --format F    Write the list for other programs: csv (name, quantity, unit,
+                category, notes, recipes), json (structured quantities, categories
+                and source recipes) or cooklang ([category] sections of
+                "name: amount" lines). Use -o to write it to a file.
+  --aisle FILE  Assign aisles from an aisle.conf file ([aisle] headers followed by
+                ingredient names, synonyms separated by |) instead of guessing them.
+                Set a default with: cook config set aisle FILE
+  --preferred-units FILE
+                Show ingredients in the units of a YAML file (butter: g, eggs: count,
+                olive oil: tbsp) instead of the unit picked by size.
+                Set a default with: cook config set preferred_units FILE
   
 Note: --servings and --scale are mutually exclusive.
 
@@ -39,13 +73,28 @@ Examples:
   cook shop monday.cook tuesday.cook wednesday.cook --servings 4
 
   # Batch cooking: double the entire shopping list
-  cook list meal-prep.cook --scale 2.0
+  cook shop meal-prep.cook --scale 2.0
 
   # Convert units while scaling to servings
-  cook list recipes/*.cook --servings 4 --unit metric
+  cook shop recipes/*.cook --servings 4 --unit metric
 
   # Simple output format
-  cook list meal-prep.cook --simple`,
+  cook shop meal-prep.cook --simple
+
+  # Group the list by the recipe that needs each ingredient
+  cook shop monday.cook tuesday.cook --sort recipe
+
+  # Export as a Markdown checklist or CSV for a spreadsheet
+  cook shop dinner.cook dessert.cook --export markdown
+  cook shop recipes/*.cook --export csv -o shopping.csv
+
+  # Machine-readable lists for other programs
+  cook shop dinner.cook dessert.cook --format json
+  cook shop recipes/*.cook --format cooklang -o shopping.txt
+
+  # Send the list to a task manager or automation service
+  cook shop dinner.cook --export webhook --webhook-url https://hooks.example.com/shopping \
+    --webhook-header "Authorization: Bearer $TOKEN"`,
 	Args:              cobra.MinimumNArgs(1),
 	RunE:              runShoppingList,
 	ValidArgsFunction: completeCookFiles,
@@ -56,12 +105,30 @@ func init() {
 	shoppingListCmd.Flags().Float64VarP(&shoppingListScale, "scale", "S", 1.0, "Scale all quantities by this factor")
 	shoppingListCmd.Flags().IntVarP(&shoppingListServings, "servings", "s", 0, "Scale each recipe to this many servings before combining")
 	shoppingListCmd.Flags().StringVarP(&shoppingListUnit, "unit", "u", "", "Convert to unit system (metric, imperial, us)")
+	shoppingListCmd.Flags().StringVar(&shoppingListRound, "round-counts", "keep", "Round counted ingredients such as eggs after scaling: keep, up, half")
+	shoppingListCmd.Flags().StringVar(&shoppingListProfile, "profile", "auto", "How --unit rounds and picks units: precise, bartender, baking, auto")
 	shoppingListCmd.Flags().BoolVar(&shoppingListSimple, "simple", false, "Simple format (ingredient: quantity)")
+	shoppingListCmd.Flags().StringVar(&shoppingListExport, "export", "", "Export format: markdown, csv, webhook")
+	shoppingListCmd.Flags().StringVar(&shoppingListFormat, "format", "", "Output format: csv, json, cooklang")
+	shoppingListCmd.Flags().StringVarP(&shoppingListOutput, "output", "o", "", "Output file for --format and --export markdown|csv (default: stdout)")
+	shoppingListCmd.Flags().StringVar(&shoppingListWebhook, "webhook-url", "", "URL to POST the list to with --export webhook")
+	shoppingListCmd.Flags().StringVar(&shoppingListSort, "sort", "", "Item order: alphabetical, recipe, aisle")
+	shoppingListCmd.Flags().BoolVar(&shoppingListFraction, "unicode-fractions", false, "Write quantities as Unicode fractions (1½ cups)")
+	shoppingListCmd.Flags().StringVar(&shoppingListAisle, "aisle", "", "aisle.conf file assigning ingredients to aisles")
+	shoppingListCmd.Flags().StringVar(&shoppingListPrefer, "preferred-units", "", "YAML file of preferred units per ingredient (butter: g, eggs: count)")
+	shoppingListCmd.Flags().StringArrayVar(&shoppingListHeaders, "webhook-header", nil, "Extra webhook request header as \"Name: value\" (repeatable)")
 	rootCmd.AddCommand(shoppingListCmd)
 
 	// Register flag completions
 	_ = shoppingListCmd.RegisterFlagCompletionFunc("servings", completeServingsFlag)
 	_ = shoppingListCmd.RegisterFlagCompletionFunc("unit", completeUnitFlag)
+	_ = shoppingListCmd.RegisterFlagCompletionFunc("profile", completeProfileFlag)
+	_ = shoppingListCmd.RegisterFlagCompletionFunc("round-counts", completeCountRoundingFlag)
+	_ = shoppingListCmd.RegisterFlagCompletionFunc("sort", cobra.FixedCompletions([]string{"alphabetical", "recipe", "aisle"}, cobra.ShellCompDirectiveNoFileComp))
+	_ = shoppingListCmd.MarkFlagFilename("aisle", "conf")
+	_ = shoppingListCmd.MarkFlagFilename("preferred-units", "yaml", "yml")
+	_ = shoppingListCmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions([]string{"csv", "json", "cooklang"}, cobra.ShellCompDirectiveNoFileComp))
+	_ = shoppingListCmd.RegisterFlagCompletionFunc("export", cobra.FixedCompletions([]string{"markdown", "csv", "webhook"}, cobra.ShellCompDirectiveNoFileComp))
 }
 
 func runShoppingList(cmd *cobra.Command, args []string) error {
@@ -69,6 +136,21 @@ func runShoppingList(cmd *cobra.Command, args []string) error {
 	if shoppingListServings > 0 && shoppingListScale != 1.0 {
 		return fmt.Errorf("cannot specify both --servings and --scale; use --servings to normalize recipes to a household size, or --scale to multiply the final list")
 	}
+	switch {
+	case shoppingListFormat != "" && (shoppingListExport != "" || shoppingListJSON || shoppingListSimple):
+		return fmt.Errorf("--format cannot be combined with --export, --json or --simple")
+	case shoppingListFormat != "" && shoppingListSort != "":
+		return fmt.Errorf("--format sorts the list itself; it cannot be combined with --sort")
+	}
+	switch shoppingListFormat {
+	case "", "csv", "json", "cooklang":
+	default:
+		return fmt.Errorf("invalid format: %s (use csv, json, or cooklang)", shoppingListFormat)
+	}
+	rounding, err := cooklang.ParseCountRounding(shoppingListRound)
+	if err != nil {
+		return err
+	}
 
 	// Validate unit system if provided
 	var unitSystem cooklang.UnitSystem
@@ -89,9 +171,21 @@ func runShoppingList(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	recipes, err := readMultipleRecipes(args)
-	if err != nil {
-		return err
+	// The categorized list is grouped by aisle unless another order is asked for
+	order := cooklang.OrderAlphabetical
+	if shoppingListSort != "" {
+		var err error
+		if order, err = cooklang.ParseShoppingListOrder(shoppingListSort); err != nil {
+			return err
+		}
+	} else if !shoppingListSimple && !shoppingListJSON && shoppingListExport == "" {
+		order = cooklang.OrderByAisle
+	}
+
+	// Recipes that fail are reported; the list is made from the others
+	recipes, files, readErr := readRecipeFiles(args)
+	if len(recipes) == 0 {
+		return readErr
 	}
 
 	// Create shopping list
@@ -111,26 +205,136 @@ func runShoppingList(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Ingredients that could not be added up are listed once per unit; say why
+	if shoppingList.Report != nil {
+		for _, leftover := range shoppingList.Report.Unconsolidated {
+			amount := strings.TrimSpace(leftover.Ingredient.Quantity.String() + " " + leftover.Ingredient.Unit)
+			printWarning("%s (%s) is listed separately: %s", leftover.Ingredient.Name, amount, leftover.Reason)
+		}
+	}
+
+	if shoppingListAisle != "" {
+		aisles, err := cooklang.LoadAisleConfig(shoppingListAisle)
+		if err != nil {
+			return fmt.Errorf("failed to load aisles: %w", err)
+		}
+		shoppingList.Aisles = aisles
+	}
+
 	// Convert to unit system if requested
 	if hasUnitSystem {
-		shoppingList.Ingredients = shoppingList.Ingredients.ConvertToSystem(unitSystem)
+		profile, err := conversionProfile(shoppingListProfile, recipes...)
+		if err != nil {
+			return err
+		}
+		shoppingList.Ingredients = shoppingList.Ingredients.ConvertToSystemWithProfile(unitSystem, profile)
+	}
+	if shoppingList.Ingredients, err = applyPreferredUnits(shoppingList.Ingredients, shoppingListPrefer); err != nil {
+		return err
 	}
 
 	// Scale if requested (only when not using --servings)
 	if shoppingListScale != 1.0 {
 		shoppingList = shoppingList.Scale(shoppingListScale)
 	}
+	shoppingList.Ingredients = shoppingList.Ingredients.RoundCounts(rounding)
 
 	// Output
-	if shoppingListJSON {
-		return outputJSON(shoppingList)
+	switch {
+	case shoppingListFormat != "":
+		err = writeShoppingList(shoppingList)
+	case shoppingListExport != "":
+		err = exportShoppingList(shoppingList.SortedItems(order))
+	case shoppingListJSON:
+		err = outputJSON(struct {
+			Recipes []string                    `json:"recipes"`
+			Items   []cooklang.ShoppingListItem `json:"items"`
+		}{Recipes: shoppingList.Recipes, Items: shoppingList.SortedItems(order)})
+	default:
+		displayShoppingList(shoppingList.SortedItems(order), order, recipes, files)
+	}
+	if err != nil {
+		return err
+	}
+	return readErr
+}
+
+// exportShoppingList sends the items to the exporter chosen with --export.
+func exportShoppingList(items []cooklang.ShoppingListItem) error {
+	var exporter cooklang.ShoppingListExporter
+	switch shoppingListExport {
+	case "markdown", "csv":
+		var out io.Writer = os.Stdout
+		if shoppingListOutput != "" {
+			file, err := os.Create(shoppingListOutput)
+			if err != nil {
+				return fmt.Errorf("failed to create %s: %w", shoppingListOutput, err)
+			}
+			defer func() { _ = file.Close() }()
+			out = file
+		}
+		if shoppingListExport == "markdown" {
+			exporter = &cooklang.MarkdownExporter{Writer: out, Title: "Shopping List", Quantities: shoppingListQuantities()}
+		} else {
+			exporter = &cooklang.CSVExporter{Writer: out}
+		}
+	case "webhook":
+		if shoppingListWebhook == "" {
+			return fmt.Errorf("--export webhook requires --webhook-url")
+		}
+		headers := make(map[string]string)
+		for _, header := range shoppingListHeaders {
+			name, value, ok := strings.Cut(header, ":")
+			if !ok || strings.TrimSpace(name) == "" {
+				return fmt.Errorf("invalid --webhook-header %q: use \"Name: value\"", header)
+			}
+			headers[strings.TrimSpace(name)] = strings.TrimSpace(value)
+		}
+		exporter = &cooklang.WebhookExporter{URL: shoppingListWebhook, Headers: headers}
+	default:
+		return fmt.Errorf("invalid export format: %s (use markdown, csv, or webhook)", shoppingListExport)
 	}
 
-	displayShoppingList(shoppingList, recipes, args)
+	if err := exporter.ExportItems(items); err != nil {
+		return fmt.Errorf("export failed: %w", err)
+	}
+	switch {
+	case shoppingListExport == "webhook":
+		printSuccess("Sent %d items to %s", len(items), shoppingListWebhook)
+	case shoppingListOutput != "":
+		printSuccess("Shopping list written to %s", shoppingListOutput)
+	}
 	return nil
 }
 
-func displayShoppingList(list *cooklang.ShoppingList, recipes []*cooklang.Recipe, filenames []string) {
+// writeShoppingList writes the list in the format chosen with --format, to the
+// --output file or stdout.
+func writeShoppingList(list *cooklang.ShoppingList) error {
+	var output string
+	switch shoppingListFormat {
+	case "csv":
+		output = list.RenderCSV()
+	case "json":
+		var err error
+		if output, err = list.RenderJSON(); err != nil {
+			return fmt.Errorf("failed to encode shopping list: %w", err)
+		}
+	case "cooklang":
+		output = list.RenderCooklang()
+	}
+
+	if shoppingListOutput == "" {
+		fmt.Print(output)
+		return nil
+	}
+	if err := os.WriteFile(shoppingListOutput, []byte(output), 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", shoppingListOutput, err)
+	}
+	printSuccess("Shopping list written to %s", shoppingListOutput)
+	return nil
+}
+
+func displayShoppingList(items []cooklang.ShoppingListItem, order cooklang.ShoppingListOrder, recipes []*cooklang.Recipe, filenames []string) {
 	fmt.Println("Shopping List")
 	fmt.Println(string(make([]byte, 60)))
 
@@ -171,108 +375,83 @@ func displayShoppingList(list *cooklang.ShoppingList, recipes []*cooklang.Recipe
 
 	// Display ingredients
 	if shoppingListSimple {
-		displaySimpleShoppingList(list)
+		displaySimpleShoppingList(items)
 	} else {
-		displayDetailedShoppingList(list)
+		displayDetailedShoppingList(items, order)
 	}
 
 	// Summary
 	fmt.Println()
-	fmt.Printf("Total: %d unique ingredients\n", list.Count())
+	fmt.Printf("Total: %d unique ingredients\n", len(items))
 }
 
-func displaySimpleShoppingList(list *cooklang.ShoppingList) {
-	shoppingMap := list.ToMap()
-
-	// Sort keys alphabetically
-	keys := make([]string, 0, len(shoppingMap))
-	for name := range shoppingMap {
-		keys = append(keys, name)
-	}
-	sort.Strings(keys)
-
-	for _, name := range keys {
-		fmt.Printf("%s: %s\n", name, shoppingMap[name])
+func displaySimpleShoppingList(items []cooklang.ShoppingListItem) {
+	for _, item := range items {
+		fmt.Printf("%s: %s\n", item.Name, shoppingListAmount(item))
 	}
 }
 
-func displayDetailedShoppingList(list *cooklang.ShoppingList) {
-	// Group by category (rough heuristic based on common ingredient names)
-	produce := []*cooklang.Ingredient{}
-	dairy := []*cooklang.Ingredient{}
-	meat := []*cooklang.Ingredient{}
-	pantry := []*cooklang.Ingredient{}
-	spices := []*cooklang.Ingredient{}
-	other := []*cooklang.Ingredient{}
-
-	for _, ing := range list.Ingredients.Ingredients {
-		name := ing.Name
-
-		// Categorize (very simple heuristic)
-		switch {
-		case contains(name, []string{"lettuce", "tomato", "onion", "garlic", "carrot", "potato", "cucumber", "pepper", "spinach", "kale"}):
-			produce = append(produce, ing)
-		case contains(name, []string{"milk", "cream", "cheese", "butter", "yogurt", "parmesan"}):
-			dairy = append(dairy, ing)
-		case contains(name, []string{"chicken", "beef", "pork", "fish", "lamb", "turkey", "bacon", "sausage"}):
-			meat = append(meat, ing)
-		case contains(name, []string{"salt", "pepper", "cinnamon", "cumin", "paprika", "oregano", "basil", "thyme", "vanilla"}):
-			spices = append(spices, ing)
-		case contains(name, []string{"flour", "sugar", "rice", "pasta", "bread", "oil", "vinegar", "sauce"}):
-			pantry = append(pantry, ing)
-		default:
-			other = append(other, ing)
-		}
+// shoppingListQuantities returns how to write quantities: as Unicode fractions
+// with --unicode-fractions, otherwise nil for decimals.
+func shoppingListQuantities() *cooklang.FormatOptions {
+	if !shoppingListFraction {
+		return nil
 	}
-
-	displayCategory("🥬 Produce", produce)
-	displayCategory("🧀 Dairy & Eggs", dairy)
-	displayCategory("🥩 Meat & Seafood", meat)
-	displayCategory("🏺 Pantry", pantry)
-	displayCategory("🌶️  Spices & Seasonings", spices)
-	displayCategory("📦 Other", other)
+	return &cooklang.FormatOptions{UnicodeFractions: true}
 }
 
-func contains(s string, substrs []string) bool {
-	for _, substr := range substrs {
-		if len(s) >= len(substr) {
-			for i := 0; i <= len(s)-len(substr); i++ {
-				if s[i:i+len(substr)] == substr {
-					return true
-				}
-			}
-		}
+// shoppingListAmount formats an item's amount for the printed lists.
+func shoppingListAmount(item cooklang.ShoppingListItem) string {
+	if quantities := shoppingListQuantities(); quantities != nil {
+		return item.FormatAmount(*quantities)
 	}
-	return false
+	return item.Amount()
 }
 
-// sortIngredients sorts a slice of ingredients alphabetically by name
-func sortIngredients(ingredients []*cooklang.Ingredient) {
-	sort.Slice(ingredients, func(i, j int) bool {
-		return ingredients[i].Name < ingredients[j].Name
-	})
+// aisleTitles are the headings for the aisles of cooklang.ShoppingAisles.
+var aisleTitles = map[string]string{
+	"Produce":             "🥬 Produce",
+	"Dairy & Eggs":        "🧀 Dairy & Eggs",
+	"Meat & Seafood":      "🥩 Meat & Seafood",
+	"Pantry":              "🏺 Pantry",
+	"Spices & Seasonings": "🌶️  Spices & Seasonings",
+	"Other":               "📦 Other",
 }
 
-func displayCategory(title string, ingredients []*cooklang.Ingredient) {
-	if len(ingredients) == 0 {
-		return
-	}
-
-	// Sort alphabetically
-	sortIngredients(ingredients)
+// displayDetailedShoppingList prints a checklist, with a heading per aisle or recipe
+// when the items are ordered that way.
+func displayDetailedShoppingList(items []cooklang.ShoppingListItem, order cooklang.ShoppingListOrder) {
+	group := ""
+	for i, item := range items {
+		var title string
+		switch order {
+		case cooklang.OrderByAisle:
+			title = aisleTitles[item.Aisle]
+			if title == "" {
+				title = item.Aisle
+			}
+		case cooklang.OrderByRecipe:
+			title = "📖 Other"
+			if len(item.SourceRecipes) > 0 {
+				title = "📖 " + item.SourceRecipes[0]
+			}
+		}
+		if i == 0 || title != group {
+			if title != "" {
+				fmt.Printf("\n%s:\n", title)
+			}
+			group = title
+		}
 
-	fmt.Printf("\n%s:\n", title)
-	for _, ing := range ingredients {
-		if ing.Quantity == -1 {
-			fmt.Printf("  ☐ %s (some)\n", ing.Name)
-		} else if ing.Unit == "" {
-			fmt.Printf("  ☐ %s: %.2g\n", ing.Name, ing.Quantity)
+		line := "  ☐ " + item.Name
+		if item.Quantity > 0 {
+			line += ": " + shoppingListAmount(item)
 		} else {
-			if ing.Quantity == float32(int(ing.Quantity)) {
-				fmt.Printf("  ☐ %s: %.0f %s\n", ing.Name, ing.Quantity, ing.Unit)
-			} else {
-				fmt.Printf("  ☐ %s: %.2g %s\n", ing.Name, ing.Quantity, ing.Unit)
-			}
+			line += " (" + shoppingListAmount(item) + ")"
+		}
+		if len(item.Notes) > 0 {
+			line += " — " + strings.Join(item.Notes, "; ")
 		}
+		fmt.Println(line)
 	}
 }
@@ -0,0 +1,435 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hilli/cooklang"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var startCmd = &cobra.Command{
+	Use:   "start <recipe.cook>",
+	Short: "Cook a recipe step by step",
+	Long: `Walk through a recipe one step at a time in the terminal.
+
+The session opens with a mise-en-place checklist of every ingredient and piece
+of cookware. Each following screen shows one step with the ingredients it uses
+and its timers. Started timers count down at the bottom of the screen and ring
+the terminal bell when they are done.
+
+Keys:
+  n, →, enter      Next step (from the checklist: start cooking)
+  p, ←             Previous step
+  <number> enter   Jump to a step, e.g. 3 then enter
+  t                Start the current step's timers
+  m                Back to the mise-en-place checklist
+  ↑/↓, j/k         Move through the checklist
+  space, x         Tick off a checklist item
+  q, ctrl-c        Quit
+
+Keys can also be piped in when stdin is not a terminal; the session ends at
+the end of the input.
+
+Examples:
+  cook start lasagna.cook
+  cook start recipes/Negroni.cook --scale 2`,
+	Args:              cobra.ExactArgs(1),
+	RunE:              runStart,
+	ValidArgsFunction: completeCookFile,
+}
+
+var startScale float64
+
+func init() {
+	startCmd.Flags().Float64Var(&startScale, "scale", 1, "Scale the ingredients by this factor")
+	rootCmd.AddCommand(startCmd)
+}
+
+func runStart(cmd *cobra.Command, args []string) error {
+	if startScale <= 0 {
+		return fmt.Errorf("--scale must be positive, got %g", startScale)
+	}
+	recipe, err := readRecipeFile(args[0])
+	if err != nil {
+		return err
+	}
+	if startScale != 1 {
+		recipe = recipe.Scale(startScale)
+	}
+	session := newCookingSession(recipe, colorEnabled(os.Stdout))
+	if len(session.steps) == 0 {
+		return fmt.Errorf("%s has no steps", args[0])
+	}
+
+	var out io.Writer = os.Stdout
+	clear := colorEnabled(os.Stdout)
+	if fd := int(os.Stdin.Fd()); term.IsTerminal(fd) {
+		state, err := term.MakeRaw(fd)
+		if err != nil {
+			return fmt.Errorf("failed to read keys from the terminal: %w", err)
+		}
+		defer func() { _ = term.Restore(fd, state) }()
+		// Raw mode does not turn "\n" into a carriage return and line feed
+		out = crlfWriter{os.Stdout}
+		clear = true
+	}
+	return session.run(os.Stdin, out, clear)
+}
+
+// cookingSession is the state of a `cook start` session: the checklist, the
+// steps, the page being shown and the running timers.
+type cookingSession struct {
+	title     string
+	checklist []checklistItem
+	steps     []cookingStep
+	page      int    // 0 is the checklist, n is step n
+	cursor    int    // Selected checklist item
+	jump      string // Step number being typed
+	timers    []*cookingTimer
+	color     bool
+}
+
+// checklistItem is an ingredient or piece of cookware on the mise-en-place checklist.
+type checklistItem struct {
+	text string
+	done bool
+}
+
+// cookingStep is a numbered step prepared for display.
+type cookingStep struct {
+	section     string
+	notes       []string
+	text        string
+	ingredients []string
+	timers      []*cooklang.Timer
+}
+
+// cookingTimer is a started countdown.
+type cookingTimer struct {
+	label string
+	step  int
+	end   time.Time
+	rung  bool
+}
+
+// newCookingSession prepares a recipe for cooking.
+func newCookingSession(recipe *cooklang.Recipe, color bool) *cookingSession {
+	s := &cookingSession{title: recipe.Title, color: color}
+	for _, ingredient := range recipe.GetIngredients().Ingredients {
+		s.checklist = append(s.checklist, checklistItem{text: ingredient.RenderDisplay()})
+	}
+	for _, cookware := range recipe.GetCookware() {
+		text := cookware.Name
+		if cookware.Annotation != "" {
+			text += " (" + cookware.Annotation + ")"
+		}
+		s.checklist = append(s.checklist, checklistItem{text: text})
+	}
+
+	numbers := recipe.StepNumbers()
+	var section string
+	var notes []string
+	for step := range recipe.Steps() {
+		cs := cookingStep{}
+		var text strings.Builder
+		for component := range step.Components() {
+			switch comp := component.(type) {
+			case *cooklang.Section:
+				section = comp.Name
+			case *cooklang.Note:
+				notes = append(notes, comp.Text)
+			case *cooklang.Instruction:
+				text.WriteString(comp.Text)
+			case *cooklang.Ingredient:
+				text.WriteString(s.style(colorGreen, comp.Name))
+				cs.ingredients = append(cs.ingredients, comp.RenderDisplay())
+			case *cooklang.Cookware:
+				text.WriteString(s.style(colorBlue, comp.Name))
+			case *cooklang.Timer:
+				text.WriteString(s.style(colorYellow, comp.RenderDisplay()))
+				cs.timers = append(cs.timers, comp)
+			case *cooklang.Temperature:
+				text.WriteString(comp.RenderDisplay())
+			case *cooklang.RecipeReference:
+				text.WriteString(comp.Path)
+			}
+		}
+		if _, ok := numbers[step]; !ok {
+			continue
+		}
+		cs.section, cs.notes, cs.text = section, notes, strings.TrimSpace(text.String())
+		notes = nil
+		s.steps = append(s.steps, cs)
+	}
+	return s
+}
+
+// run shows the session on out and handles keys from in until the user quits or
+// the input ends. With clear set, every screen replaces the previous one.
+func (s *cookingSession) run(in io.Reader, out io.Writer, clear bool) error {
+	keys := make(chan string)
+	go readKeys(in, keys)
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	draw := func() {
+		if clear {
+			fmt.Fprint(out, "\033[H\033[2J")
+		}
+		fmt.Fprint(out, s.view(time.Now()))
+	}
+	draw()
+	for {
+		select {
+		case key, ok := <-keys:
+			if !ok || s.handleKey(key, time.Now()) {
+				return nil
+			}
+			draw()
+		case <-ticker.C:
+			if s.tick(time.Now()) {
+				fmt.Fprint(out, "\a")
+			}
+			if s.timersRunning() {
+				draw()
+			}
+		}
+	}
+}
+
+// handleKey applies a key press and reports whether the session should end.
+func (s *cookingSession) handleKey(key string, now time.Time) bool {
+	if len(key) == 1 && key[0] >= '0' && key[0] <= '9' {
+		s.jump += key
+		return false
+	}
+	if s.jump != "" && key == "enter" {
+		if n, err := strconv.Atoi(s.jump); err == nil && n >= 1 && n <= len(s.steps) {
+			s.page = n
+		}
+		s.jump = ""
+		return false
+	}
+	s.jump = ""
+
+	switch key {
+	case "q", "ctrl-c":
+		return true
+	case "n", "right", "enter", "l":
+		s.page = min(s.page+1, len(s.steps))
+	case "p", "left", "h":
+		s.page = max(s.page-1, 0)
+	case "m":
+		s.page = 0
+	case "t":
+		s.startTimers(now)
+	case "up", "k":
+		if s.page == 0 && s.cursor > 0 {
+			s.cursor--
+		}
+	case "down", "j":
+		if s.page == 0 && s.cursor < len(s.checklist)-1 {
+			s.cursor++
+		}
+	case "space", "x":
+		if s.page == 0 && s.cursor < len(s.checklist) {
+			s.checklist[s.cursor].done = !s.checklist[s.cursor].done
+		} else if key == "space" {
+			s.page = min(s.page+1, len(s.steps))
+		}
+	}
+	return false
+}
+
+// startTimers starts the timers of the current step that are not already running.
+func (s *cookingSession) startTimers(now time.Time) {
+	if s.page == 0 {
+		return
+	}
+	for _, timer := range s.steps[s.page-1].timers {
+		duration, err := timer.ParsedDuration()
+		if err != nil {
+			continue
+		}
+		label := timer.RenderDisplay()
+		if timer.Name != "" && timer.Duration != "" {
+			label = timer.Name + " (" + label + ")"
+		}
+		running := false
+		for _, t := range s.timers {
+			if t.step == s.page && t.label == label && !t.rung {
+				running = true
+			}
+		}
+		if !running {
+			s.timers = append(s.timers, &cookingTimer{label: label, step: s.page, end: now.Add(duration)})
+		}
+	}
+}
+
+// tick marks timers that ran out and reports whether any did since the last tick.
+func (s *cookingSession) tick(now time.Time) bool {
+	rang := false
+	for _, timer := range s.timers {
+		if !timer.rung && !now.Before(timer.end) {
+			timer.rung = true
+			rang = true
+		}
+	}
+	return rang
+}
+
+// timersRunning reports whether any timer is still counting down.
+func (s *cookingSession) timersRunning() bool {
+	for _, timer := range s.timers {
+		if !timer.rung {
+			return true
+		}
+	}
+	return false
+}
+
+// view renders the current page, the timers and the key help.
+func (s *cookingSession) view(now time.Time) string {
+	var b strings.Builder
+	if s.title != "" {
+		fmt.Fprintf(&b, "%s\n\n", s.style(colorBold, s.title))
+	}
+
+	if s.page == 0 {
+		fmt.Fprintf(&b, "%s\n\n", s.style(colorBold, "Mise en place"))
+		for i, item := range s.checklist {
+			marker, box := "  ", "[ ]"
+			if i == s.cursor {
+				marker = "> "
+			}
+			if item.done {
+				box = "[x]"
+			}
+			fmt.Fprintf(&b, "%s%s %s\n", marker, box, item.text)
+		}
+		if len(s.checklist) == 0 {
+			b.WriteString("  Nothing to prepare.\n")
+		}
+	} else {
+		step := s.steps[s.page-1]
+		header := fmt.Sprintf("Step %d of %d", s.page, len(s.steps))
+		if step.section != "" {
+			header += " · " + step.section
+		}
+		fmt.Fprintf(&b, "%s\n\n", s.style(colorBold, header))
+		for _, note := range step.notes {
+			fmt.Fprintf(&b, "  %s\n\n", s.style(colorGray, "> "+note))
+		}
+		fmt.Fprintf(&b, "  %s\n", step.text)
+		if len(step.ingredients) > 0 {
+			b.WriteString("\n  Ingredients:\n")
+			for _, ingredient := range step.ingredients {
+				fmt.Fprintf(&b, "    • %s\n", ingredient)
+			}
+		}
+		if len(step.timers) > 0 {
+			b.WriteString("\n  Timers (press t to start):\n")
+			for _, timer := range step.timers {
+				fmt.Fprintf(&b, "    ⏲ %s\n", timer.RenderDisplay())
+			}
+		}
+	}
+
+	if len(s.timers) > 0 {
+		b.WriteString("\n" + s.style(colorBold, "Timers") + "\n")
+		for _, timer := range s.timers {
+			status := "done"
+			if !timer.rung {
+				status = formatCountdown(timer.end.Sub(now))
+			}
+			fmt.Fprintf(&b, "  Step %d: %s  %s\n", timer.step, timer.label, s.style(colorYellow, status))
+		}
+	}
+
+	help := "n next · p previous · <number> enter jump · t start timers · m mise en place · q quit"
+	if s.page == 0 {
+		help = "↑/↓ move · space tick off · n start cooking · q quit"
+	}
+	if s.jump != "" {
+		help = "Go to step " + s.jump + "…"
+	}
+	fmt.Fprintf(&b, "\n%s\n", s.style(colorGray, help))
+	return b.String()
+}
+
+// style colors text when the session uses colors.
+func (s *cookingSession) style(color, text string) string {
+	if !s.color || text == "" {
+		return text
+	}
+	return color + text + colorReset
+}
+
+// formatCountdown formats the time left on a timer as m:ss or h:mm:ss.
+func formatCountdown(d time.Duration) string {
+	seconds := int((d + time.Second - 1) / time.Second)
+	if seconds >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", seconds/3600, seconds/60%60, seconds%60)
+	}
+	return fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
+}
+
+// readKeys reads key presses from in and sends their names to keys: printable
+// characters as themselves, and "enter", "space", "up", "down", "left", "right"
+// and "ctrl-c". keys is closed when the input ends.
+func readKeys(in io.Reader, keys chan<- string) {
+	defer close(keys)
+	buf := make([]byte, 64)
+	for {
+		n, err := in.Read(buf)
+		for _, key := range decodeKeys(buf[:n]) {
+			keys <- key
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// decodeKeys splits raw terminal input into key names.
+func decodeKeys(data []byte) []string {
+	arrows := map[byte]string{'A': "up", 'B': "down", 'C': "right", 'D': "left"}
+	var keys []string
+	for i := 0; i < len(data); i++ {
+		switch c := data[i]; {
+		case c == 0x1b && i+2 < len(data) && (data[i+1] == '[' || data[i+1] == 'O'):
+			if name, ok := arrows[data[i+2]]; ok {
+				keys = append(keys, name)
+			}
+			i += 2
+		case c == '\r' || c == '\n':
+			keys = append(keys, "enter")
+		case c == ' ':
+			keys = append(keys, "space")
+		case c == 0x03:
+			keys = append(keys, "ctrl-c")
+		case c >= '!' && c <= '~':
+			keys = append(keys, string(c))
+		}
+	}
+	return keys
+}
+
+// crlfWriter writes "\r\n" for every "\n", for terminals in raw mode.
+type crlfWriter struct {
+	w io.Writer
+}
+
+func (c crlfWriter) Write(p []byte) (int, error) {
+	if _, err := c.w.Write([]byte(strings.ReplaceAll(string(p), "\n", "\r\n"))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
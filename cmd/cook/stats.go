@@ -0,0 +1,134 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/hilli/cooklang"
+	"github.com/spf13/cobra"
+)
+
+var (
+	statsTop  int
+	statsJSON bool
+)
+
+var statsCmd = &cobra.Command{
+	Use:   "stats [recipe-file|directory]",
+	Short: "Show statistics of a recipe or collection",
+	Long: `Show statistics of a recipe, or of a collection of recipes searched
+recursively in a directory (default: current directory).
+
+For a recipe: its ingredients, steps, sections, timers, total timer time,
+estimated active time and word count. For a collection: the number of recipes,
+their average size, and the most used ingredients, cuisines and tags.
+
+Examples:
+  cook stats Pancakes.cook
+  cook stats ./recipes
+  cook stats ./recipes --top 20
+  cook stats ./recipes --json`,
+	Args:              cobra.MaximumNArgs(1),
+	RunE:              runStats,
+	ValidArgsFunction: completeCookFiles,
+}
+
+func init() {
+	statsCmd.Flags().IntVar(&statsTop, "top", 10, "Most used ingredients, cuisines and tags to show (0 for all)")
+	statsCmd.Flags().BoolVarP(&statsJSON, "json", "j", false, "Output as JSON")
+	rootCmd.AddCommand(statsCmd)
+}
+
+func runStats(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+	path := "."
+	if len(args) == 1 {
+		path = args[0]
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	if !info.IsDir() {
+		recipe, err := readRecipeFile(path)
+		if err != nil {
+			return err
+		}
+		stats := recipe.Stats()
+		if statsJSON {
+			return outputJSON(stats)
+		}
+		printRecipeStats(recipe.Title, stats)
+		return nil
+	}
+
+	library, err := cooklang.LoadLibrary(path)
+	if err != nil {
+		if library.Len() == 0 {
+			return fmt.Errorf("failed to load recipes from %s: %w", path, err)
+		}
+		printWarning("Some recipes could not be loaded: %v", err)
+	}
+	stats := library.Stats()
+	if statsJSON {
+		return outputJSON(stats)
+	}
+	printLibraryStats(stats)
+	return nil
+}
+
+// printRecipeStats writes a recipe's statistics, one per line
+func printRecipeStats(title string, stats cooklang.RecipeStats) {
+	if title != "" {
+		fmt.Printf("%s\n\n", title)
+	}
+	rows := [][2]string{
+		{"Ingredients", fmt.Sprintf("%d (%d different)", stats.Ingredients, stats.UniqueIngredients)},
+		{"Cookware", fmt.Sprint(stats.Cookware)},
+		{"Steps", fmt.Sprint(stats.Steps)},
+		{"Sections", fmt.Sprint(stats.Sections)},
+		{"Timers", fmt.Sprintf("%d (%s)", stats.Timers, stats.TimerTime)},
+		{"Active time", stats.ActiveTime.String()},
+		{"Words", fmt.Sprint(stats.Words)},
+	}
+	for _, row := range rows {
+		fmt.Printf("  %s  %s\n", padRight(row[0]+":", 12), row[1])
+	}
+}
+
+// printLibraryStats writes a collection's statistics with the most used
+// ingredients, cuisines and tags
+func printLibraryStats(stats cooklang.LibraryStats) {
+	fmt.Printf("Recipes: %d\n", stats.Recipes)
+	if stats.Recipes == 0 {
+		return
+	}
+	fmt.Printf("Average: %.1f ingredients, %.1f steps, %s active\n",
+		stats.AverageIngredients, stats.AverageSteps, stats.AverageActiveTime.Round(time.Second))
+	for _, list := range []struct {
+		title  string
+		counts []cooklang.StatCount
+	}{
+		{"Ingredients", stats.Ingredients},
+		{"Cuisines", stats.Cuisines},
+		{"Tags", stats.Tags},
+	} {
+		if len(list.counts) == 0 {
+			continue
+		}
+		counts := list.counts
+		if statsTop > 0 && len(counts) > statsTop {
+			counts = counts[:statsTop]
+		}
+		width := 0
+		for _, count := range counts {
+			width = max(width, len([]rune(count.Name)))
+		}
+		fmt.Printf("\n%s:\n", list.title)
+		for _, count := range counts {
+			fmt.Printf("  %s  %d\n", padRight(count.Name, width), count.Count)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hilli/cooklang"
+	"github.com/hilli/cooklang/pantry"
+	"github.com/spf13/cobra"
+)
+
+var (
+	suggestHave       []string
+	suggestPantry     string
+	suggestMaxMissing int
+	suggestLimit      int
+	suggestJSON       bool
+)
+
+var suggestCmd = &cobra.Command{
+	Use:   "suggest [directory]",
+	Short: "Suggest recipes you can cook with what you have",
+	Long: `Suggest recipes from a collection (default: current directory) for the
+ingredients you have, those you have most of the ingredients for first, with
+the ingredients you would still need to buy.
+
+Give the ingredients with --have (repeat it or separate them with commas), or
+use the items of a pantry.conf file with --pantry (default: the pantry setting
+of cook config), or both. Names match fuzzily, so "egg" finds "eggs". Optional
+ingredients are never listed as missing.
+
+Examples:
+  cook suggest --have chicken,rice,onion ./recipes
+  cook suggest --pantry pantry.conf --max-missing 0 ./recipes
+  cook suggest --have gin,lime --limit 5 --json`,
+	Args:              cobra.MaximumNArgs(1),
+	RunE:              runSuggest,
+	ValidArgsFunction: completeDirectory,
+}
+
+func init() {
+	suggestCmd.Flags().StringSliceVar(&suggestHave, "have", nil, "Ingredients you have (repeatable)")
+	suggestCmd.Flags().StringVar(&suggestPantry, "pantry", "", "pantry.conf file with the ingredients you have")
+	suggestCmd.Flags().IntVar(&suggestMaxMissing, "max-missing", -1, "Only recipes missing at most this many ingredients")
+	suggestCmd.Flags().IntVar(&suggestLimit, "limit", 0, "Show at most this many recipes (0 for all)")
+	suggestCmd.Flags().BoolVarP(&suggestJSON, "json", "j", false, "Output as JSON")
+	rootCmd.AddCommand(suggestCmd)
+}
+
+// suggestItem is a suggested recipe in the JSON output
+type suggestItem struct {
+	Title    string   `json:"title"`
+	Path     string   `json:"path"`
+	Coverage float64  `json:"coverage"`
+	Have     []string `json:"have"`
+	Missing  []string `json:"missing"`
+	Optional []string `json:"optional,omitempty"`
+}
+
+func runSuggest(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+	available := suggestHave
+	if suggestPantry != "" {
+		p, err := pantry.ParseFile(suggestPantry)
+		if err != nil {
+			return fmt.Errorf("failed to read pantry: %w", err)
+		}
+		for _, location := range p.Locations {
+			for _, item := range location.Items {
+				available = append(available, item.Name)
+			}
+		}
+	}
+	if len(available) == 0 {
+		return errors.New("no ingredients: use --have or --pantry")
+	}
+
+	dir := "."
+	if len(args) == 1 {
+		dir = args[0]
+	}
+	library, err := cooklang.LoadLibrary(dir)
+	if err != nil {
+		if library.Len() == 0 {
+			return fmt.Errorf("failed to load recipes from %s: %w", dir, err)
+		}
+		printWarning("Some recipes could not be loaded: %v", err)
+	}
+	printVerbose("Loaded %d recipes from %s, matching %d ingredients", library.Len(), dir, len(available))
+
+	var matches []cooklang.IngredientMatch
+	for _, match := range library.MatchByIngredients(available) {
+		if suggestMaxMissing >= 0 && len(match.Missing) > suggestMaxMissing {
+			continue
+		}
+		matches = append(matches, match)
+		if suggestLimit > 0 && len(matches) == suggestLimit {
+			break
+		}
+	}
+
+	if suggestJSON {
+		items := make([]suggestItem, 0, len(matches))
+		for _, match := range matches {
+			items = append(items, suggestItem{
+				Title:    match.Entry.Name(),
+				Path:     match.Entry.Path,
+				Coverage: match.Coverage,
+				Have:     match.Have,
+				Missing:  match.Missing,
+				Optional: match.Optional,
+			})
+		}
+		return outputJSON(items)
+	}
+
+	for _, match := range matches {
+		have := len(match.Have)
+		fmt.Printf("%s (%s) - %d of %d ingredients\n", match.Entry.Name(), match.Entry.Path, have, have+len(match.Missing))
+		if len(match.Missing) > 0 {
+			fmt.Printf("  Missing: %s\n", strings.Join(match.Missing, ", "))
+		}
+	}
+	if len(matches) == 0 {
+		printWarning("No recipes use these ingredients")
+	} else {
+		printInfo("%d of %d recipes", len(matches), library.Len())
+	}
+	return nil
+}
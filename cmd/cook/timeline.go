@@ -0,0 +1,123 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/hilli/cooklang/renderers"
+	"github.com/spf13/cobra"
+)
+
+var (
+	timelineServeAt     string
+	timelineICS         string
+	timelineStepMinutes int
+	timelineJSON        bool
+)
+
+var timelineCmd = &cobra.Command{
+	Use:   "timeline <recipe.cook> --serve-at TIME",
+	Short: "Plan when to start each step to serve on time",
+	Long: `Work backwards from the time a dish should be served and show when each
+step has to start. A step takes as long as its longest timer (the upper bound
+of ranges) or --step-minutes if it has no timers.
+
+--serve-at accepts "19:00" (today), "2006-01-02 19:00", or an RFC 3339 time.
+Use --ics to write the timeline as an iCalendar file with an alarm for each
+step, ready to import into a calendar app ("-" writes it to stdout).
+
+Examples:
+  cook timeline roast.cook --serve-at 19:00
+  cook timeline roast.cook --serve-at 19:00 --ics dinner.ics
+  cook timeline roast.cook --serve-at "2025-12-24 18:30" --step-minutes 10
+  cook timeline roast.cook --serve-at 19:00 --json`,
+	Args:              cobra.ExactArgs(1),
+	RunE:              runTimeline,
+	ValidArgsFunction: completeCookFile,
+}
+
+func init() {
+	timelineCmd.Flags().StringVar(&timelineServeAt, "serve-at", "", "When the dish should be ready (required)")
+	timelineCmd.Flags().StringVar(&timelineICS, "ics", "", "Write the timeline as an iCalendar file (- for stdout)")
+	timelineCmd.Flags().IntVar(&timelineStepMinutes, "step-minutes", int(renderers.DefaultStepDuration/time.Minute), "Estimated minutes for steps without timers")
+	timelineCmd.Flags().BoolVarP(&timelineJSON, "json", "j", false, "Output the timeline as JSON")
+	_ = timelineCmd.MarkFlagRequired("serve-at")
+	rootCmd.AddCommand(timelineCmd)
+}
+
+func runTimeline(cmd *cobra.Command, args []string) error {
+	serveAt, err := parseServeAt(timelineServeAt, time.Now())
+	if err != nil {
+		return err
+	}
+	if timelineStepMinutes <= 0 {
+		return fmt.Errorf("--step-minutes must be positive, got %d", timelineStepMinutes)
+	}
+
+	recipe, err := readRecipeFile(args[0])
+	if err != nil {
+		return err
+	}
+	renderer := renderers.ICSRenderer{StepDuration: time.Duration(timelineStepMinutes) * time.Minute}
+
+	if timelineICS != "" {
+		ics := renderer.RenderICS(recipe, serveAt)
+		if timelineICS == "-" {
+			fmt.Print(ics)
+			return nil
+		}
+		if err := os.WriteFile(timelineICS, []byte(ics), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", timelineICS, err)
+		}
+		printSuccess("Timeline written to %s", timelineICS)
+		return nil
+	}
+
+	events := renderer.Timeline(recipe, serveAt)
+	if timelineJSON {
+		return outputJSON(events)
+	}
+
+	for _, event := range events {
+		label := fmt.Sprintf("Step %d", event.Step)
+		if event.Section != "" {
+			label += " (" + event.Section + ")"
+		}
+		duration := event.Duration().String()
+		if event.Estimate {
+			duration = "~" + duration
+		}
+		fmt.Printf("%s  %s: %s [%s]\n", event.Start.Format("15:04"), label, event.Text, formatTimelineDuration(duration))
+	}
+	fmt.Printf("%s  Serve\n", serveAt.Format("15:04"))
+	if len(events) > 0 {
+		printInfo("Start at %s (%s before serving)", events[0].Start.Format("Mon 15:04"), serveAt.Sub(events[0].Start))
+	}
+	return nil
+}
+
+// formatTimelineDuration drops zero seconds from whole-minute durations ("1h30m0s" -> "1h30m").
+func formatTimelineDuration(duration string) string {
+	if strings.HasSuffix(duration, "m0s") {
+		return strings.TrimSuffix(duration, "0s")
+	}
+	return duration
+}
+
+// parseServeAt parses a serving time given as "15:04" (on the day of now),
+// "2006-01-02 15:04", or RFC 3339, in the local time zone.
+func parseServeAt(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02 15:04", value, now.Location()); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("15:04", value, now.Location()); err == nil {
+		return time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), 0, 0, now.Location()), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --serve-at %q: use 19:00, \"2006-01-02 19:00\", or RFC 3339", value)
+}
@@ -4,11 +4,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"html"
+	"io"
 	"os"
+	"strconv"
 	"strings"
 
 	"github.com/hilli/cooklang"
-	"github.com/hilli/cooklang/parser"
 )
 
 // readRecipeFile reads and parses a recipe file with the specified parser mode
@@ -18,29 +19,30 @@ func readRecipeFile(filename string) (*cooklang.Recipe, error) {
 		return nil, fmt.Errorf("failed to read file: %w", err)
 	}
 
-	// Create parser with appropriate mode
-	p := parser.New()
-	p.ExtendedMode = !canonicalMode // Extended mode is default (canonicalMode=false)
-
-	parsedRecipe, err := p.ParseString(string(content))
+	recipe, err := cooklang.ParseBytes(content, parseOptions()...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse recipe: %w", err)
 	}
-
-	// Convert to cooklang.Recipe type
-	recipe := cooklang.ToCooklangRecipe(parsedRecipe)
+	recipe.InferTitle(filename, false)
+	printVerbose("Parsed %s (%d ingredients, extended mode: %v)", filename, len(recipe.GetIngredients().Ingredients), !canonicalMode)
 	return recipe, nil
 }
 
-// readMultipleRecipes reads and parses multiple recipe files
+// parseOptions returns the library parse options for the global flags.
+// Extended mode is the default (canonicalMode=false).
+func parseOptions() []cooklang.ParseOption {
+	if canonicalMode {
+		return []cooklang.ParseOption{cooklang.WithCanonicalMode()}
+	}
+	return []cooklang.ParseOption{cooklang.WithExtendedMode()}
+}
+
+// readMultipleRecipes reads and parses multiple recipe files, failing if any of
+// them fails (after trying them all)
 func readMultipleRecipes(filenames []string) ([]*cooklang.Recipe, error) {
-	recipes := make([]*cooklang.Recipe, 0, len(filenames))
-	for _, filename := range filenames {
-		recipe, err := readRecipeFile(filename)
-		if err != nil {
-			return nil, fmt.Errorf("error reading %s: %w", filename, err)
-		}
-		recipes = append(recipes, recipe)
+	recipes, _, err := readRecipeFiles(filenames)
+	if err != nil {
+		return nil, err
 	}
 	return recipes, nil
 }
@@ -55,26 +57,97 @@ func outputJSON(data interface{}) error {
 	return nil
 }
 
-// printSuccess prints a success message
+// ANSI color codes used for diagnostics and cook start
+const (
+	colorReset  = "\033[0m"
+	colorBold   = "\033[1m"
+	colorRed    = "\033[31m"
+	colorGreen  = "\033[32m"
+	colorYellow = "\033[33m"
+	colorBlue   = "\033[34m"
+	colorGray   = "\033[90m"
+)
+
+// diagnostics is where status messages are written. Diagnostics always go to
+// stderr so that stdout only carries command output (recipes, JSON, lists).
+var diagnostics io.Writer = os.Stderr
+
+// useColor reports whether diagnostics should be colored: not disabled by
+// --no-color or the NO_COLOR environment variable, and stderr is a terminal.
+func useColor() bool {
+	return colorEnabled(diagnostics)
+}
+
+// terminalWidth returns the width of the terminal from $COLUMNS, or 80.
+func terminalWidth() int {
+	if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns > 0 {
+		return columns
+	}
+	return 80
+}
+
+// colorEnabled reports whether output to w should be colored: not disabled by
+// --no-color or the NO_COLOR environment variable, and w is a terminal.
+func colorEnabled(w io.Writer) bool {
+	if noColor || os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// printDiagnostic writes a symbol-prefixed message to the diagnostics stream
+func printDiagnostic(color, symbol, format string, args ...interface{}) {
+	if useColor() {
+		symbol = color + symbol + colorReset
+	}
+	fmt.Fprintf(diagnostics, symbol+" "+format+"\n", args...)
+}
+
+// printSuccess prints a success message (suppressed by --quiet)
 func printSuccess(format string, args ...interface{}) {
-	fmt.Printf("✓ "+format+"\n", args...)
+	if quietMode {
+		return
+	}
+	printDiagnostic(colorGreen, "✓", format, args...)
 }
 
 // printWarning prints a warning message
 func printWarning(format string, args ...interface{}) {
-	fmt.Printf("⚠ "+format+"\n", args...)
+	printDiagnostic(colorYellow, "⚠", format, args...)
 }
 
-// printInfo prints an info message
+// printInfo prints an info message (suppressed by --quiet)
 func printInfo(format string, args ...interface{}) {
-	fmt.Printf("ℹ "+format+"\n", args...)
+	if quietMode {
+		return
+	}
+	printDiagnostic(colorBlue, "ℹ", format, args...)
+}
+
+// printVerbose prints a debug message (only shown with --verbose)
+func printVerbose(format string, args ...interface{}) {
+	if !verboseMode || quietMode {
+		return
+	}
+	printDiagnostic(colorGray, "…", format, args...)
+}
+
+// printError prints an error message
+func printError(err error) {
+	printDiagnostic(colorRed, "✗", "%v", err)
 }
 
 // wrapHTMLDocument wraps an HTML fragment in a complete HTML document with proper charset
-func wrapHTMLDocument(content string, recipe *cooklang.Recipe) string {
+// and the given language (e.g., "en")
+func wrapHTMLDocument(content string, recipe *cooklang.Recipe, lang string) string {
 	var sb strings.Builder
 	sb.WriteString("<!DOCTYPE html>\n")
-	sb.WriteString("<html lang=\"en\">\n")
+	fmt.Fprintf(&sb, "<html lang=\"%s\">\n", html.EscapeString(lang))
 	sb.WriteString("<head>\n")
 	sb.WriteString("  <meta charset=\"UTF-8\">\n")
 	sb.WriteString("  <meta name=\"viewport\" content=\"width=device-width, initial-scale=1.0\">\n")
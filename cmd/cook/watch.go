@@ -0,0 +1,204 @@
+package main
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hilli/cooklang"
+)
+
+// fileState is what the render watcher compares to notice a changed file.
+type fileState struct {
+	size    int64
+	modTime time.Time
+}
+
+// renderWatcher renders recipes again when they or their images change. It
+// polls the files, like cook serve does, so it works the same on every platform
+// and on network drives.
+type renderWatcher struct {
+	source string                  // Recipe file or directory being watched
+	render func(path string) error // Renders one recipe
+	files  map[string]fileState    // Recipes and images at the last check
+}
+
+// newRenderWatcher records the current state of the recipes and images of source,
+// a .cook file or a directory.
+func newRenderWatcher(source string, render func(path string) error) (*renderWatcher, error) {
+	files, err := scanRenderSources(source)
+	if err != nil {
+		return nil, err
+	}
+	return &renderWatcher{source: source, render: render, files: files}, nil
+}
+
+// recipes returns the watched .cook files, sorted.
+func (w *renderWatcher) recipes() []string {
+	var recipes []string
+	for path := range w.files {
+		if filepath.Ext(path) == ".cook" {
+			recipes = append(recipes, path)
+		}
+	}
+	sort.Strings(recipes)
+	return recipes
+}
+
+// run checks for changes every interval until ctx is done. Changed recipes are
+// rendered once nothing has changed for the debounce duration.
+func (w *renderWatcher) run(ctx context.Context, interval, debounce time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	pending := map[string]bool{}
+	var lastChange time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			changed, err := w.poll()
+			if err != nil {
+				printWarning("Could not check for changes: %v", err)
+				continue
+			}
+			if len(changed) > 0 {
+				for _, path := range changed {
+					pending[path] = true
+				}
+				lastChange = time.Now()
+				continue
+			}
+			if len(pending) == 0 || time.Since(lastChange) < debounce {
+				continue
+			}
+			paths := make([]string, 0, len(pending))
+			for path := range pending {
+				paths = append(paths, path)
+			}
+			sort.Strings(paths)
+			clear(pending)
+			for _, path := range paths {
+				if err := w.render(path); err != nil {
+					printWarning("%s: %v", path, err)
+				}
+			}
+		}
+	}
+}
+
+// poll scans the files again and returns the recipes that need rendering.
+func (w *renderWatcher) poll() ([]string, error) {
+	files, err := scanRenderSources(w.source)
+	if err != nil {
+		return nil, err
+	}
+	changed := changedRecipes(w.files, files)
+	w.files = files
+	return changed, nil
+}
+
+// scanRenderSources returns the state of the recipes and images to watch: for a
+// file, the recipe and the images next to it; for a directory, every recipe and
+// image in it and its subdirectories, skipping hidden ones.
+func scanRenderSources(source string) (map[string]fileState, error) {
+	files := map[string]fileState{}
+	add := func(path string, info fs.FileInfo) {
+		files[path] = fileState{size: info.Size(), modTime: info.ModTime()}
+	}
+
+	info, err := os.Stat(source)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		add(source, info)
+		entries, err := os.ReadDir(filepath.Dir(source))
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			path := filepath.Join(filepath.Dir(source), entry.Name())
+			if entry.IsDir() || !cooklang.IsImageFile(path) || !isRecipeImage(source, path) {
+				continue
+			}
+			if info, err := entry.Info(); err == nil {
+				add(path, info)
+			}
+		}
+		return files, nil
+	}
+
+	err = filepath.WalkDir(source, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if strings.HasPrefix(d.Name(), ".") && path != source {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() || (filepath.Ext(path) != ".cook" && !cooklang.IsImageFile(path)) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		add(path, info)
+		return nil
+	})
+	return files, err
+}
+
+// changedRecipes compares two scans and returns the recipes that were added or
+// changed, or whose images were added, changed or removed, sorted.
+func changedRecipes(before, after map[string]fileState) []string {
+	changed := map[string]bool{}
+	var images []string
+	for path, state := range after {
+		if previous, ok := before[path]; ok && previous == state {
+			continue
+		}
+		if filepath.Ext(path) == ".cook" {
+			changed[path] = true
+		} else {
+			images = append(images, path)
+		}
+	}
+	for path := range before {
+		if _, ok := after[path]; !ok && filepath.Ext(path) != ".cook" {
+			images = append(images, path)
+		}
+	}
+	for _, image := range images {
+		for path := range after {
+			if filepath.Ext(path) == ".cook" && isRecipeImage(path, image) {
+				changed[path] = true
+			}
+		}
+	}
+
+	recipes := make([]string, 0, len(changed))
+	for path := range changed {
+		recipes = append(recipes, path)
+	}
+	sort.Strings(recipes)
+	return recipes
+}
+
+// isRecipeImage reports whether image is named like one of the recipe's images:
+// "Recipe.jpg", "Recipe-1.png" or a step image such as "Recipe.0.jpg".
+func isRecipeImage(recipe, image string) bool {
+	if filepath.Dir(recipe) != filepath.Dir(image) {
+		return false
+	}
+	name := strings.TrimSuffix(filepath.Base(recipe), ".cook")
+	base := strings.TrimSuffix(filepath.Base(image), filepath.Ext(image))
+	return base == name || strings.HasPrefix(base, name+".") || strings.HasPrefix(base, name+"-")
+}
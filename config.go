@@ -0,0 +1,255 @@
+package cooklang
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+)
+
+// Config holds user preferences for tools built on this package, such as the cook
+// CLI, which reads them from ~/.config/cook/config.yaml:
+//
+//	units: metric
+//	format: html
+//	aisle: ~/recipes/config/aisle.conf
+//	pantry: ~/recipes/config/pantry.conf
+//	preferred_units: ~/recipes/config/units.yaml
+//	locale: de
+//	canonical: false
+//
+// Empty fields mean "no preference", so the tool's own default applies.
+type Config struct {
+	Units  string `yaml:"units,omitempty"`  // Unit system to convert to: metric, imperial or us
+	Format string `yaml:"format,omitempty"` // Default renderer, e.g. "markdown" or "html"
+	Aisle  string `yaml:"aisle,omitempty"`  // Path of an aisle.conf file, see AisleConfig
+	Pantry string `yaml:"pantry,omitempty"` // Path of a pantry.conf file, see the pantry package
+	// Path of a YAML file of preferred units per ingredient, see PreferredUnits
+	PreferredUnits string `yaml:"preferred_units,omitempty"`
+	Locale         string `yaml:"locale,omitempty"`    // Language of rendered headings and labels, e.g. "de"
+	Canonical      bool   `yaml:"canonical,omitempty"` // Parse in canonical mode instead of extended mode
+}
+
+// ConfigKeys lists the keys accepted by Config.Get and Config.Set, in file order.
+var ConfigKeys = []string{"units", "format", "aisle", "pantry", "preferred_units", "locale", "canonical"}
+
+// configEnv maps config keys to the environment variables that override them.
+var configEnv = map[string]string{
+	"units":           "COOK_UNITS",
+	"format":          "COOK_FORMAT",
+	"aisle":           "COOK_AISLE",
+	"pantry":          "COOK_PANTRY",
+	"preferred_units": "COOK_PREFERRED_UNITS",
+	"locale":          "COOK_LOCALE",
+	"canonical":       "COOK_CANONICAL",
+}
+
+// ConfigDir returns the directory for cook's configuration: $XDG_CONFIG_HOME/cook,
+// or ~/.config/cook.
+//
+// Returns:
+//   - string: The configuration directory (it may not exist)
+func ConfigDir() string {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "cook")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".config", "cook")
+	}
+	return filepath.Join(home, ".config", "cook")
+}
+
+// DefaultConfigPath returns the path of the config file in ConfigDir.
+//
+// Returns:
+//   - string: The path of config.yaml (it may not exist)
+func DefaultConfigPath() string {
+	return filepath.Join(ConfigDir(), "config.yaml")
+}
+
+// LoadConfig reads a config file. A missing file is not an error: it gives an
+// empty Config, so the defaults apply.
+//
+// Parameters:
+//   - path: The path of the YAML config file
+//
+// Returns:
+//   - *Config: The configuration
+//   - error: An error if the file cannot be read, is not valid YAML, has unknown
+//     keys or has invalid values
+//
+// Example:
+//
+//	config, err := cooklang.LoadConfig(cooklang.DefaultConfigPath())
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	config.ApplyEnv()
+func LoadConfig(path string) (*Config, error) {
+	config := &Config{}
+	content, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return config, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.UnmarshalWithOptions(content, config, yaml.Strict()); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return config, nil
+}
+
+// Save writes the config to a YAML file, creating its directory if needed.
+//
+// Parameters:
+//   - path: The path of the config file
+//
+// Returns:
+//   - error: An error if the file cannot be written
+//
+// Example:
+//
+//	config, _ := cooklang.LoadConfig(path)
+//	_ = config.Set("units", "metric")
+//	err := config.Save(path)
+func (c *Config) Save(path string) error {
+	content, err := yaml.Marshal(c)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(string(content)) == "{}" {
+		content = nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return writeFileAtomic(path, content, 0644)
+}
+
+// ApplyEnv overrides the config with the COOK_UNITS, COOK_FORMAT, COOK_AISLE,
+// COOK_PANTRY, COOK_PREFERRED_UNITS, COOK_LOCALE and COOK_CANONICAL environment
+// variables that are set.
+//
+// Returns:
+//   - error: An error naming the variable if its value is invalid
+func (c *Config) ApplyEnv() error {
+	for _, key := range ConfigKeys {
+		if value, ok := os.LookupEnv(configEnv[key]); ok && value != "" {
+			if err := c.Set(key, value); err != nil {
+				return fmt.Errorf("%s: %w", configEnv[key], err)
+			}
+		}
+	}
+	return nil
+}
+
+// ConfigEnvVar returns the environment variable that overrides a config key, or ""
+// for an unknown key.
+func ConfigEnvVar(key string) string {
+	return configEnv[key]
+}
+
+// Get returns the value of a config key as text ("" if unset).
+//
+// Parameters:
+//   - key: One of ConfigKeys
+//
+// Returns:
+//   - string: The value
+//   - error: An error if the key is unknown
+func (c *Config) Get(key string) (string, error) {
+	switch key {
+	case "units":
+		return c.Units, nil
+	case "format":
+		return c.Format, nil
+	case "aisle":
+		return c.Aisle, nil
+	case "pantry":
+		return c.Pantry, nil
+	case "preferred_units":
+		return c.PreferredUnits, nil
+	case "locale":
+		return c.Locale, nil
+	case "canonical":
+		return strconv.FormatBool(c.Canonical), nil
+	}
+	return "", unknownConfigKeyError(key)
+}
+
+// Set changes a config key. An empty value resets the key to its default.
+//
+// Parameters:
+//   - key: One of ConfigKeys
+//   - value: The new value; units must be metric, imperial or us, and canonical
+//     true or false
+//
+// Returns:
+//   - error: An error if the key is unknown or the value is invalid
+//
+// Example:
+//
+//	err := config.Set("units", "imperial")
+func (c *Config) Set(key, value string) error {
+	value = strings.TrimSpace(value)
+	switch key {
+	case "units":
+		if value != "" {
+			system, err := ParseUnitSystem(value)
+			if err != nil {
+				return err
+			}
+			value = string(system)
+		}
+		c.Units = value
+	case "format":
+		c.Format = strings.ToLower(value)
+	case "aisle":
+		c.Aisle = value
+	case "pantry":
+		c.Pantry = value
+	case "preferred_units":
+		c.PreferredUnits = value
+	case "locale":
+		c.Locale = value
+	case "canonical":
+		canonical := false
+		if value != "" {
+			var err error
+			if canonical, err = strconv.ParseBool(value); err != nil {
+				return fmt.Errorf("canonical must be true or false, not %q", value)
+			}
+		}
+		c.Canonical = canonical
+	default:
+		return unknownConfigKeyError(key)
+	}
+	return nil
+}
+
+// Validate checks the config values.
+//
+// Returns:
+//   - error: An error if the unit system is unknown
+func (c *Config) Validate() error {
+	if c.Units != "" {
+		if _, err := ParseUnitSystem(c.Units); err != nil {
+			return fmt.Errorf("units: %w", err)
+		}
+	}
+	return nil
+}
+
+func unknownConfigKeyError(key string) error {
+	return fmt.Errorf("unknown config key %q (use %s)", key, strings.Join(ConfigKeys, ", "))
+}
@@ -0,0 +1,92 @@
+package cooklang
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+
+	config, err := LoadConfig(filepath.Join(dir, "missing.yaml"))
+	if err != nil || *config != (Config{}) {
+		t.Fatalf("expected an empty config for a missing file, got %+v, %v", config, err)
+	}
+
+	path := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(path, []byte("units: imperial\nformat: html\ncanonical: true\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	config, err = LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Units != "imperial" || config.Format != "html" || !config.Canonical {
+		t.Errorf("unexpected config: %+v", config)
+	}
+
+	for name, content := range map[string]string{
+		"unknown key": "colour: red\n",
+		"bad units":   "units: furlongs\n",
+		"bad yaml":    "units: [\n",
+	} {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadConfig(path); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestConfig_SaveRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cook", "config.yaml")
+	config := &Config{}
+	for key, value := range map[string]string{"units": "US", "aisle": "~/aisle.conf", "locale": "de", "canonical": "true"} {
+		if err := config.Set(key, value); err != nil {
+			t.Errorf("Set(%q, %q) failed: %v", key, value, err)
+		}
+	}
+	if err := config.Set("canonical", "maybe"); err == nil {
+		t.Error("expected an error for canonical=maybe")
+	}
+	if err := config.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *loaded != *config || loaded.Units != "us" {
+		t.Errorf("round trip changed the config: %+v, want %+v", loaded, config)
+	}
+
+	if err := loaded.Set("units", ""); err != nil {
+		t.Fatal(err)
+	}
+	if value, _ := loaded.Get("units"); value != "" {
+		t.Errorf("expected units to be reset, got %q", value)
+	}
+	if _, err := loaded.Get("colour"); err == nil || !strings.Contains(err.Error(), "unknown config key") {
+		t.Errorf("expected an unknown key error, got %v", err)
+	}
+}
+
+func TestConfig_ApplyEnv(t *testing.T) {
+	t.Setenv("COOK_UNITS", "metric")
+	t.Setenv("COOK_CANONICAL", "true")
+	config := &Config{Units: "us", Format: "html"}
+	if err := config.ApplyEnv(); err != nil {
+		t.Fatal(err)
+	}
+	if config.Units != "metric" || !config.Canonical || config.Format != "html" {
+		t.Errorf("unexpected config: %+v", config)
+	}
+
+	t.Setenv("COOK_UNITS", "furlongs")
+	if err := config.ApplyEnv(); err == nil || !strings.Contains(err.Error(), "COOK_UNITS") {
+		t.Errorf("expected an error naming COOK_UNITS, got %v", err)
+	}
+}
@@ -0,0 +1,234 @@
+package cooklang
+
+import (
+	"fmt"
+	"math"
+	"slices"
+)
+
+// ConsolidationReport tells what ConsolidateByNameWithReport added up and which
+// ingredients it had to keep apart from others of the same name, and why.
+type ConsolidationReport struct {
+	Merged         []MergedIngredient         `json:"merged,omitempty"`         // Entries that add up several ingredients
+	Unconsolidated []UnconsolidatedIngredient `json:"unconsolidated,omitempty"` // Entries listed next to another entry of the same name
+	Scaling        []RecipeScaling            `json:"scaling,omitempty"`        // How each recipe was scaled, for CreateShoppingListWithPlan
+}
+
+// MergedIngredient is an entry of a consolidated list that adds up several ingredients.
+type MergedIngredient struct {
+	Ingredient *Ingredient   `json:"ingredient"` // The entry in the consolidated list
+	Sources    []*Ingredient `json:"sources"`    // The ingredients it adds up, in list order
+}
+
+// UnconsolidatedIngredient is an entry of a consolidated list that could not be added
+// to the first entry of the same name.
+type UnconsolidatedIngredient struct {
+	Ingredient *Ingredient `json:"ingredient"` // The entry in the consolidated list
+	Reason     string      `json:"reason"`     // Why it stays apart, e.g. "cup (volume) does not convert to g (mass)"
+}
+
+// consolidationGroup is a set of ingredients of one name that add up.
+type consolidationGroup struct {
+	ingredients []*Ingredient
+	some        bool // A "some" quantity, which is never added up
+}
+
+// ConsolidateByNameWithReport consolidates ingredients with the same name like
+// ConsolidateByName and reports what it did. Ingredients of a name are added up per
+// unit category: masses with masses, volumes with volumes, and counted items (see
+// Ingredient.IsCount) with counted items, so 2 eggs and 1 piece make 3. Each
+// category is added up in targetUnit if it converts to it, or else in the best of
+// the units the ingredients use: the largest one in which the total is at least 1
+// (500 g and 1 kg make 1.5 kg). Entries are listed in the order their names first
+// appear. References to ingredients added earlier (see Ingredient.IsReference) are
+// left out.
+//
+// Parameters:
+//   - targetUnit: The unit to convert ingredients to (empty string to pick one per category)
+//
+// Returns:
+//   - *IngredientList: A new list with consolidated ingredients
+//   - *ConsolidationReport: The entries that were added up and the ones kept apart
+//   - error: Any error encountered during consolidation
+//
+// Example:
+//
+//	list := cooklang.NewIngredientList()
+//	list.Add(&cooklang.Ingredient{Name: "flour", Quantity: cooklang.NewQuantity(200, 1), Unit: "g", TypedUnit: cooklang.CreateTypedUnit("g")})
+//	list.Add(&cooklang.Ingredient{Name: "flour", Quantity: cooklang.NewQuantity(1, 1), Unit: "cup", TypedUnit: cooklang.CreateTypedUnit("cup")})
+//	consolidated, report, _ := list.ConsolidateByNameWithReport("")
+//	for _, leftover := range report.Unconsolidated {
+//	    fmt.Printf("%s: %s\n", leftover.Ingredient.Name, leftover.Reason)
+//	    // flour: cup (volume) does not convert to g (mass)
+//	}
+func (il *IngredientList) ConsolidateByNameWithReport(targetUnit string) (*IngredientList, *ConsolidationReport, error) {
+	consolidated := NewIngredientList()
+	report := &ConsolidationReport{}
+
+	// Group ingredients by name, in order of first appearance
+	var names []string
+	byName := make(map[string][]*consolidationGroup)
+	for _, ingredient := range il.Ingredients {
+		if ingredient.IsReference() {
+			continue
+		}
+		groups, seen := byName[ingredient.Name]
+		if !seen {
+			names = append(names, ingredient.Name)
+		}
+		byName[ingredient.Name] = addToConsolidationGroup(groups, ingredient)
+	}
+
+	for _, name := range names {
+		var first *Ingredient
+		for _, group := range byName[name] {
+			entry, merged := group.consolidate(targetUnit)
+			consolidated.Add(entry)
+			if merged {
+				report.Merged = append(report.Merged, MergedIngredient{Ingredient: entry, Sources: group.ingredients})
+			}
+			if first == nil {
+				first = entry
+				continue
+			}
+			report.Unconsolidated = append(report.Unconsolidated, UnconsolidatedIngredient{
+				Ingredient: entry,
+				Reason:     consolidationReason(entry, first),
+			})
+		}
+	}
+
+	return consolidated, report, nil
+}
+
+// addToConsolidationGroup adds an ingredient to the group it adds up with: counted
+// items with counted items, unitless amounts with unitless amounts, and units with
+// units they convert to. "some" quantities get a group of their own.
+func addToConsolidationGroup(groups []*consolidationGroup, ingredient *Ingredient) []*consolidationGroup {
+	if ingredient.Quantity.IsSome() {
+		return append(groups, &consolidationGroup{ingredients: []*Ingredient{ingredient}, some: true})
+	}
+	for _, group := range groups {
+		if group.some {
+			continue
+		}
+		other := group.ingredients[0]
+		switch {
+		case ingredient.IsCount() && other.IsCount(),
+			ingredient.Unit == "" && other.Unit == "",
+			ingredient.Unit != "" && ingredient.Unit == other.Unit,
+			ingredient.Unit != "" && other.Unit != "" && ingredient.CanConvertTo(other.Unit):
+			group.ingredients = append(group.ingredients, ingredient)
+			return groups
+		}
+	}
+	return append(groups, &consolidationGroup{ingredients: []*Ingredient{ingredient}})
+}
+
+// consolidate adds up the group's ingredients, reporting whether it added up several.
+// A single ingredient is returned as it is, or converted to targetUnit.
+func (g *consolidationGroup) consolidate(targetUnit string) (*Ingredient, bool) {
+	first := g.ingredients[0]
+	unit := g.unit(targetUnit)
+	if len(g.ingredients) == 1 {
+		if unit != first.Unit {
+			if converted, err := first.ConvertTo(unit); err == nil {
+				return converted, false
+			}
+		}
+		return first, false
+	}
+
+	total, approximate := g.total(unit)
+	if !total.HasAmount() {
+		return first, false
+	}
+	typedUnit := first.TypedUnit
+	switch {
+	case unit == "":
+		typedUnit = nil
+	case unit != first.Unit:
+		typedUnit = CreateTypedUnit(unit)
+	}
+	return &Ingredient{
+		Name:        first.Name,
+		Quantity:    total, // Summing a range with anything produces a range
+		Unit:        unit,
+		TypedUnit:   typedUnit,
+		Approximate: approximate, // A sum including an estimate is itself an estimate
+	}, true
+}
+
+// unit returns the unit to add the group up in: none or the first unit for counted
+// items, targetUnit if the group converts to it, the group's unit if all its
+// ingredients share one, or else the largest of their units in which the total is at
+// least 1.
+func (g *consolidationGroup) unit(targetUnit string) string {
+	first := g.ingredients[0]
+	if g.some || first.Unit == "" {
+		return first.Unit
+	}
+	if first.IsCount() {
+		return countUnit(g.ingredients)
+	}
+	if targetUnit != "" && first.CanConvertTo(targetUnit) {
+		return targetUnit
+	}
+
+	var candidates []string
+	for _, ingredient := range g.ingredients {
+		if !slices.Contains(candidates, ingredient.Unit) {
+			candidates = append(candidates, ingredient.Unit)
+		}
+	}
+	if len(candidates) == 1 {
+		return first.Unit
+	}
+
+	best, bestTotal := first.Unit, math.Inf(-1)
+	for _, candidate := range candidates {
+		total, _ := g.total(candidate)
+		amount := total.Float()
+		switch {
+		case bestTotal < 1 && amount > bestTotal, // Nothing reaches 1 yet: prefer the larger total
+			amount >= 1 && amount < bestTotal: // The smallest total of at least 1 is the largest unit
+			best, bestTotal = candidate, amount
+		}
+	}
+	return best
+}
+
+// total adds up the group's quantities in unit.
+func (g *consolidationGroup) total(unit string) (Quantity, bool) {
+	var total Quantity
+	var approximate bool
+	for _, ingredient := range g.ingredients {
+		quantity := ingredient.Quantity
+		if ingredient.Unit != unit && !ingredient.IsCount() {
+			converted, err := ingredient.ConvertTo(unit)
+			if err != nil {
+				continue
+			}
+			quantity = converted.Quantity
+		}
+		total = total.Add(quantity) // Ranges add bound by bound
+		approximate = approximate || ingredient.Approximate
+	}
+	return total, approximate
+}
+
+// consolidationReason says why entry could not be added to first, an entry of the
+// same name.
+func consolidationReason(entry, first *Ingredient) string {
+	switch {
+	case entry.Quantity.IsSome() || first.Quantity.IsSome():
+		return "no quantity to add up"
+	case entry.Unit == "" || first.Unit == "":
+		return "amounts with and without a unit do not add up"
+	}
+	entryType, firstType := entry.GetUnitType(), first.GetUnitType()
+	if entryType != "" && firstType != "" && entryType != firstType {
+		return fmt.Sprintf("%s (%s) does not convert to %s (%s)", entry.Unit, entryType, first.Unit, firstType)
+	}
+	return fmt.Sprintf("%s does not convert to %s", entry.Unit, first.Unit)
+}
@@ -0,0 +1,67 @@
+package cooklang
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestConsolidateByNameWithReport(t *testing.T) {
+	recipe, err := ParseString(`Mix @flour{200%g}, @milk{1%cup} and @eggs{2}.
+Add @flour{1%cup}, @flour{0.3%kg}, @milk{8%tbsp} and @eggs{1}.
+Season with @salt{} and @salt{1%tsp}.
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	consolidated, report, err := recipe.GetIngredients().ConsolidateByNameWithReport("")
+	if err != nil {
+		t.Fatalf("ConsolidateByNameWithReport failed: %v", err)
+	}
+
+	var entries []string
+	for _, ingredient := range consolidated.Ingredients {
+		entries = append(entries, strings.TrimSpace(ingredient.Name+" "+ingredient.Quantity.String()+" "+ingredient.Unit))
+	}
+	want := "flour 500 g,flour 1 cup,milk 1.5 cup,eggs 3,salt some,salt 1 tsp"
+	if got := strings.Join(entries, ","); got != want {
+		t.Errorf("entries = %s\nwant      %s", got, want)
+	}
+
+	if len(report.Merged) != 3 || len(report.Merged[0].Sources) != 2 || report.Merged[0].Ingredient.Name != "flour" {
+		t.Errorf("unexpected merged entries: %+v", report.Merged)
+	}
+	var leftovers []string
+	for _, leftover := range report.Unconsolidated {
+		leftovers = append(leftovers, leftover.Ingredient.Name+": "+leftover.Reason)
+	}
+	want = "flour: cup (volume) does not convert to g (mass),salt: no quantity to add up"
+	if got := strings.Join(leftovers, ","); got != want {
+		t.Errorf("unconsolidated = %s\nwant             %s", got, want)
+	}
+}
+
+func TestConsolidateByNameBestUnit(t *testing.T) {
+	tests := []struct {
+		recipe string
+		want   string
+	}{
+		{"@butter{500%g} and @butter{1%kg}", "1.5 kg"},
+		{"@butter{1%kg} and @butter{500%g}", "1.5 kg"},
+		{"@butter{300%g} and @butter{0.2%kg}", "500 g"},
+		{"@butter{250%g} and @butter{250%g}", "500 g"},
+	}
+	for _, tt := range tests {
+		recipe, err := ParseString(tt.recipe + ".\n")
+		if err != nil {
+			t.Fatal(err)
+		}
+		consolidated, err := recipe.GetIngredients().ConsolidateByName("")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := consolidated.ToMap()["butter"]; got != tt.want {
+			t.Errorf("%s: butter = %q, want %q", tt.recipe, got, tt.want)
+		}
+	}
+}
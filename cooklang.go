@@ -1,7 +1,10 @@
 package cooklang
 
 import (
+	"errors"
 	"fmt"
+	"maps"
+	"math"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -27,9 +30,9 @@ import (
 //	fmt.Println(recipe.Title)
 //	ingredients := recipe.GetIngredients()
 type Recipe struct {
-	Title       string    `json:"title,omitempty"`       // Recipe title from frontmatter
+	Title       string    `json:"title,omitempty"`       // Recipe title from frontmatter, or inferred (see TitleInferred)
 	Cuisine     string    `json:"cuisine,omitempty"`     // Cuisine type (e.g., "Italian", "Mexican")
-	Date        time.Time `json:"date,omitempty"`        // Recipe date in YYYY-MM-DD format
+	Date        time.Time `json:"date,omitzero"`         // Recipe date in YYYY-MM-DD format
 	Description string    `json:"description,omitempty"` // Brief recipe description
 	Difficulty  string    `json:"difficulty,omitempty"`  // Difficulty level (e.g., "easy", "medium", "hard")
 	PrepTime    string    `json:"prep_time,omitempty"`   // Preparation time (e.g., "15 minutes")
@@ -38,15 +41,30 @@ type Recipe struct {
 	Author      string    `json:"author,omitempty"`      // Recipe author name
 	Images      []string  `json:"images,omitempty"`      // Image filenames associated with the recipe
 	Servings    float32   `json:"servings,omitempty"`    // Number of servings this recipe makes
+	Yield       Yield     `json:"yield,omitzero"`        // What the recipe makes from the "yield" metadata (e.g., 24 cookies)
 	Tags        []string  `json:"tags,omitempty"`        // Recipe tags for categorization
 	FirstStep   *Step     `json:"first_step,omitempty"`  // First step in the linked list of recipe steps
+
+	CustomUnits   CustomUnits `json:"custom_units,omitempty"`   // Custom units from the "units" frontmatter key
+	TitleInferred bool        `json:"title_inferred,omitempty"` // Title was inferred from the file name or a section header, not set in metadata
+
+	// OriginalServings is the number of servings before the recipe was first scaled;
+	// 0 for recipes that were not scaled or have no servings.
+	OriginalServings float32 `json:"original_servings,omitempty"`
 	CooklangRenderable
+
+	renderer func(*Recipe) string // Renderer set by SetRenderer or SetRendererFunc, re-bound to copies
 }
 
 // CooklangRenderable provides rendering capabilities for recipe components.
 // It allows custom rendering functions to be attached to recipes and their components.
 type CooklangRenderable struct {
-	RenderFunc func() string `json:"-"` // Custom rendering function
+	// RenderFunc is a custom rendering function.
+	//
+	// Deprecated: Set a recipe's renderer with Recipe.SetRenderer or
+	// Recipe.SetRendererFunc, which copies of the recipe keep, or render it once with
+	// Recipe.RenderWith.
+	RenderFunc func() string `json:"-"`
 }
 
 // Metadata stores arbitrary key-value pairs for recipe metadata not covered by structured fields.
@@ -95,20 +113,24 @@ var canonicalUnits = map[UnitSystem]map[string]string{
 var commonUnitMappings = map[UnitSystem]map[string]map[string]string{
 	UnitSystemMetric: {
 		"volume": {
-			"large": "l",  // for volumes >= 1000ml
-			"small": "ml", // for volumes < 1000ml
+			"large": "l",  // for volumes >= 1000ml (litres are the ceiling, even at catering scale)
+			"small": "ml", // for volumes < 1000ml (millilitres are the floor)
 		},
 		"mass": {
-			"large": "kg", // for mass >= 1000g
-			"small": "g",  // for mass < 1000g
+			"huge":  "tonne", // for mass >= 1000kg
+			"large": "kg",    // for mass >= 1000g
+			"small": "g",     // for mass >= 1g
+			"tiny":  "mg",    // for mass >= 1mg
+			"micro": "µg",    // for mass < 1mg (nutrition-scale amounts)
 		},
 	},
 	UnitSystemUS: {
 		"volume": {
-			"large":  "qt",   // for large volumes
-			"medium": "cup",  // for medium volumes
-			"small":  "tbsp", // for small volumes
-			"tiny":   "tsp",  // for very small volumes
+			"huge":   "gallon", // for very large volumes
+			"large":  "qt",     // for large volumes
+			"medium": "cup",    // for medium volumes
+			"small":  "tbsp",   // for small volumes
+			"tiny":   "tsp",    // for very small volumes (teaspoons are the floor)
 		},
 	},
 	UnitSystemImperial: {
@@ -135,6 +157,8 @@ var cookingUnitConversions = map[string]float64{
 
 	// Keep metric units
 	"ml": 1.0,
+	"cl": 10.0,
+	"dl": 100.0,
 	"l":  1000.0,
 }
 
@@ -143,8 +167,16 @@ var cookingMassConversions = map[string]float64{
 	// Mass conversions to grams
 	"oz": 28.3495,
 	"lb": 453.592,
-	"kg": 1000.0,
-	"g":  1.0,
+
+	// Metric mass units from nutrition scale to catering scale
+	"µg":    0.000001, // micro sign
+	"μg":    0.000001, // Greek mu
+	"ug":    0.000001,
+	"mcg":   0.000001,
+	"mg":    0.001,
+	"g":     1.0,
+	"kg":    1000.0,
+	"tonne": 1000000.0,
 }
 
 // convertCookingUnit converts between common cooking units using ml or grams as an intermediate.
@@ -192,10 +224,11 @@ func getCookingUnitType(unit string) string {
 // StepComponent represents a component within a recipe step (ingredient, instruction, timer, or cookware).
 // Components are organized as a linked list within each step, allowing iteration through the sequence of actions.
 type StepComponent interface {
-	isStepComponent()       // Marker method
-	Render() string         // Renders the component as Cooklang syntax
-	SetNext(StepComponent)  // Sets the next component in the linked list
-	GetNext() StepComponent // Gets the next component in the linked list
+	isStepComponent()            // Marker method
+	Render() string              // Renders the component as Cooklang syntax
+	SetNext(StepComponent)       // Sets the next component in the linked list
+	GetNext() StepComponent      // Gets the next component in the linked list
+	GetPosition() SourcePosition // Where the component is in the recipe source
 }
 
 // Step represents a single step in a recipe's instructions.
@@ -204,13 +237,15 @@ type StepComponent interface {
 //
 // Steps are traversed by following the NextStep pointer to iterate through the recipe's instructions.
 type Step struct {
-	FirstComponent StepComponent `json:"first_component,omitempty"` // First component in this step
-	NextStep       *Step         `json:"next_step,omitempty"`       // Next step in the recipe
+	FirstComponent StepComponent  `json:"first_component,omitempty"` // First component in this step
+	NextStep       *Step          `json:"next_step,omitempty"`       // Next step in the recipe
+	Position       SourcePosition `json:"position,omitzero"`         // Where the step is in the recipe source
+	Images         []string       `json:"images,omitempty"`          // Step images ("Recipe.0.jpg" or from StepImagesKey), relative to the recipe's directory
 	CooklangRenderable
 }
 
 // HasDisplayableContent returns true if the step contains any content that should be displayed
-// to users (ingredients, cookware, timers, non-whitespace text, sections, or notes).
+// to users (ingredients, cookware, timers, temperatures, non-whitespace text, sections, or notes).
 // Steps containing only comments or whitespace-only text return false.
 func (s *Step) HasDisplayableContent() bool {
 	if s == nil || s.FirstComponent == nil {
@@ -220,7 +255,7 @@ func (s *Step) HasDisplayableContent() bool {
 	current := s.FirstComponent
 	for current != nil {
 		switch comp := current.(type) {
-		case *Ingredient, *Cookware, *Timer, *Section, *Note, *RecipeReference:
+		case *Ingredient, *Cookware, *Timer, *Temperature, *Section, *Note, *RecipeReference, *Extension:
 			return true
 		case *Instruction:
 			// Check if text has any non-whitespace content
@@ -235,33 +270,48 @@ func (s *Step) HasDisplayableContent() bool {
 	return false
 }
 
-func (Instruction) isStepComponent() {}
-func (Timer) isStepComponent()       {}
-func (Cookware) isStepComponent()    {}
-func (Ingredient) isStepComponent()  {}
-func (Section) isStepComponent()     {}
-func (Comment) isStepComponent()     {}
-func (Note) isStepComponent()        {}
+// IsNote reports whether the step is a note ("> ..." lines) rather than a cooking
+// step. Notes hold background, tips or anecdotes; renderers show them apart from the
+// numbered instructions and leave them out of structured instructions.
+func (s *Step) IsNote() bool {
+	if s == nil {
+		return false
+	}
+	_, ok := s.FirstComponent.(*Note)
+	return ok
+}
+
+func (Instruction) isStepComponent()     {}
+func (Timer) isStepComponent()           {}
+func (Cookware) isStepComponent()        {}
+func (Ingredient) isStepComponent()      {}
+func (Section) isStepComponent()         {}
+func (Comment) isStepComponent()         {}
+func (Note) isStepComponent()            {}
 func (RecipeReference) isStepComponent() {}
+func (Extension) isStepComponent()       {}
 
 // Render returns the Cooklang syntax representation of this ingredient.
-// Examples: "@flour{500%g}", "@salt{}", "@milk{2%cups}(cold)", "@yeast{=1%packet}", "@?thyme{2%sprigs}"
+// Examples: "@flour{500%g}", "@salt{}", "@milk{2%cups}(cold)", "@yeast{=1%packet}", "@?thyme{2%sprigs}", "@rice{~2%cups}", "@&flour{}"
 func (i Ingredient) Render() string {
 	var result string
 	prefix := "@"
 	if i.Optional {
 		prefix = "@?"
+	} else if i.Reference {
+		prefix = "@&"
 	}
 	fixedPrefix := ""
 	if i.Fixed {
 		fixedPrefix = "="
 	}
-	if i.Quantity > 0 {
-		result = fmt.Sprintf("%s%s{%s%g%%%s}", prefix, i.Name, fixedPrefix, i.Quantity, i.Unit)
-	} else if i.Quantity == -1 {
-		// -1 indicates "some" quantity
-		result = fmt.Sprintf("%s%s{}", prefix, i.Name)
+	if i.Approximate {
+		fixedPrefix += "~"
+	}
+	if i.Quantity.HasAmount() {
+		result = fmt.Sprintf("%s%s{%s%s%%%s}", prefix, i.Name, fixedPrefix, i.Quantity, i.Unit)
 	} else {
+		// "some" or no quantity
 		result = fmt.Sprintf("%s%s{}", prefix, i.Name)
 	}
 	if i.Annotation != "" {
@@ -271,20 +321,18 @@ func (i Ingredient) Render() string {
 }
 
 // RenderDisplay returns ingredient in plain text format suitable for display.
-// Examples: "2 cups flour", "500 g flour", "salt", "2 sprigs thyme (optional)"
+// Examples: "2 cups flour", "500 g flour", "1-2 tsp salt", "salt", "2 sprigs thyme (optional)", "≈2 onion"
 // Uses bartender-friendly fraction formatting (e.g., "1/2 oz" instead of "0.5 oz")
 // When quantity is unspecified (e.g., @salt{}), returns just the ingredient name.
 // Optional ingredients have "(optional)" appended.
 func (i Ingredient) RenderDisplay() string {
 	var result string
-	if i.Quantity > 0 && i.Unit != "" {
-		qtyStr := FormatAsFractionDefault(float64(i.Quantity))
-		result = fmt.Sprintf("%s %s %s", qtyStr, i.Unit, i.Name)
-	} else if i.Quantity > 0 {
-		qtyStr := FormatAsFractionDefault(float64(i.Quantity))
-		result = fmt.Sprintf("%s %s", qtyStr, i.Name)
+	if i.Quantity.HasAmount() && i.Unit != "" {
+		result = fmt.Sprintf("%s %s %s", i.displayQuantity(), i.Unit, i.Name)
+	} else if i.Quantity.HasAmount() {
+		result = fmt.Sprintf("%s %s", i.displayQuantity(), i.Name)
 	} else {
-		// Quantity == -1 (unspecified) or 0: just use the ingredient name
+		// "some" or no quantity: just use the ingredient name
 		result = i.Name
 	}
 	if i.Optional {
@@ -303,21 +351,27 @@ func (inst Instruction) RenderDisplay() string {
 	return inst.Text
 }
 
-// Render returns the Cooklang syntax representation of this timer.
-// Examples: "~{10%minutes}", "~boil{15%min}"
+// Render returns the Cooklang syntax representation of this timer, including the
+// unit, so parsing the result gives the same timer.
+// Examples: "~{10%minutes}", "~boil{15%min}", "~roast time{4%hours}"
 func (t Timer) Render() string {
-	var result string
-	if t.Name != "" {
-		result = fmt.Sprintf("~%s{%s}", t.Name, t.Duration)
-	} else {
-		result = fmt.Sprintf("~{%s}", t.Duration)
+	amount := t.Duration
+	if t.Unit != "" {
+		amount += "%" + t.Unit
 	}
+	result := fmt.Sprintf("~%s{%s}", t.Name, amount)
 	if t.Annotation != "" {
 		result += fmt.Sprintf("(%s)", t.Annotation)
 	}
 	return result
 }
 
+// String returns the timer in human-readable form, e.g. "10 minutes"; see
+// RenderDisplay.
+func (t Timer) String() string {
+	return t.RenderDisplay()
+}
+
 // RenderDisplay returns timer in plain text format suitable for display.
 // Returns the duration with unit if available, or just the duration.
 // If the timer has a name but no duration, returns the name.
@@ -337,10 +391,12 @@ func (t Timer) RenderDisplay() string {
 }
 
 // Render returns the Cooklang syntax representation of this cookware.
-// Examples: "#pot{}", "#bowl{2}", "#oven{}(preheated)"
+// Examples: "#pot{}", "#bowl{2}", "#pan{two}", "#oven{}(preheated)"
 func (c Cookware) Render() string {
 	var result string
-	if c.Quantity > 1 {
+	if c.QuantityText != "" {
+		result = fmt.Sprintf("#%s{%s}", c.Name, c.QuantityText)
+	} else if c.Quantity > 1 {
 		result = fmt.Sprintf("#%s{%d}", c.Name, c.Quantity)
 	} else {
 		result = fmt.Sprintf("#%s{}", c.Name)
@@ -357,6 +413,22 @@ func (c Cookware) RenderDisplay() string {
 	return c.Name
 }
 
+// QuantityLabel returns how many of the cookware are needed, the way renderers show
+// it next to the name: the quantity as written for word quantities ("two"), "x2"
+// for two or more, and "" for a single item.
+//
+// Returns:
+//   - string: The quantity label, e.g. "x2" for #bowl{2}
+func (c Cookware) QuantityLabel() string {
+	if c.QuantityText != "" {
+		return c.QuantityText
+	}
+	if c.Quantity > 1 {
+		return fmt.Sprintf("x%d", c.Quantity)
+	}
+	return ""
+}
+
 // Render returns the Cooklang syntax representation of this section.
 // Examples: "== Section Name =="
 func (s Section) Render() string {
@@ -401,20 +473,32 @@ func (n Note) RenderDisplay() string {
 //
 // Example Cooklang syntax: @flour{500%g}, @salt{}, @milk{2%cups}
 //
-// The Quantity field uses -1 to represent "some" (unspecified amount).
+// The Quantity field holds the exact amount, a range, or "some" (SomeQuantity) for an
+// unspecified amount.
 // The Fixed field indicates a quantity that should not scale with servings (e.g., @salt{=1%tsp}).
 // The Optional field indicates an optional ingredient (e.g., @?thyme{2%sprigs}).
+// The Reference field marks a later mention of an ingredient added earlier (e.g.,
+// "fold in the reserved @&flour{}"), which is not added to the shopping list again.
+// The Approximate field indicates an estimated quantity (e.g., @onion{~2} or @rice{about 2%cups}).
 type Ingredient struct {
-	Name           string        `json:"name,omitempty"`           // Ingredient name (e.g., "flour", "sugar")
-	Quantity       float32       `json:"quantity,omitempty"`       // Amount (-1 means "some", 0 means none specified)
-	Unit           string        `json:"unit,omitempty"`           // Unit of measurement (e.g., "g", "cup", "tbsp")
-	Fixed          bool          `json:"fixed,omitempty"`          // Fixed quantity doesn't scale with servings
-	Optional       bool          `json:"optional,omitempty"`       // Optional ingredient (can be omitted)
-	TypedUnit      *units.Unit   `json:"typed_unit,omitempty"`     // Typed unit for conversion operations
-	Subinstruction string        `json:"value,omitempty"`          // Additional preparation instructions
-	Annotation     string        `json:"annotation,omitempty"`     // Optional annotation (e.g., "finely chopped")
-	NextComponent  StepComponent `json:"next_component,omitempty"` // Next component in the step
+	Name           string         `json:"name,omitempty"`           // Ingredient name (e.g., "flour", "sugar")
+	Quantity       Quantity       `json:"quantity,omitzero"`        // Exact amount, range like {1-2%tsp}, or "some"; 0 means none specified
+	Unit           string         `json:"unit,omitempty"`           // Unit of measurement (e.g., "g", "cup", "tbsp")
+	Fixed          bool           `json:"fixed,omitempty"`          // Fixed quantity doesn't scale with servings
+	Optional       bool           `json:"optional,omitempty"`       // Optional ingredient (can be omitted)
+	Reference      bool           `json:"reference,omitempty"`      // Reference to an ingredient added earlier (e.g., @&flour{}); see IsReference
+	Approximate    bool           `json:"approximate,omitempty"`    // Quantity is an estimate (e.g., @onion{~2}); survives scaling and conversion
+	TypedUnit      *units.Unit    `json:"typed_unit,omitempty"`     // Typed unit for conversion operations
+	Subinstruction string         `json:"value,omitempty"`          // Additional preparation instructions
+	Annotation     string         `json:"annotation,omitempty"`     // Optional annotation (e.g., "finely chopped")
+	Position       SourcePosition `json:"position,omitzero"`        // Where the component is in the recipe source
+	NextComponent  StepComponent  `json:"next_component,omitempty"` // Next component in the step
+	// OriginalQuantity is the quantity before the recipe was first scaled, in the
+	// ingredient's current unit; zero for ingredients that were not scaled.
+	OriginalQuantity Quantity `json:"original_quantity,omitzero"`
 	CooklangRenderable
+
+	customUnits CustomUnits // Custom units declared by the recipe, used for conversions
 }
 
 // NewIngredient creates a new Ingredient with proper unit typing for conversion operations.
@@ -423,7 +507,8 @@ type Ingredient struct {
 //
 // Parameters:
 //   - name: The ingredient name (e.g., "vodka", "sugar")
-//   - quantity: The amount (-1 means "some" unspecified amount)
+//   - quantity: The amount (-1 means "some" unspecified amount); simple fractions
+//     such as 1.0/3 are stored exactly
 //   - unit: The unit of measurement (e.g., "ml", "oz", "g", "cups")
 //
 // Example:
@@ -434,7 +519,7 @@ type Ingredient struct {
 func NewIngredient(name string, quantity float32, unit string) *Ingredient {
 	return &Ingredient{
 		Name:      name,
-		Quantity:  quantity,
+		Quantity:  quantityFromFloat32(quantity),
 		Unit:      unit,
 		TypedUnit: CreateTypedUnit(unit),
 	}
@@ -443,8 +528,9 @@ func NewIngredient(name string, quantity float32, unit string) *Ingredient {
 // Instruction represents a text instruction within a recipe step.
 // This is plain text that provides cooking directions.
 type Instruction struct {
-	Text          string        `json:"text,omitempty"`           // Instruction text
-	NextComponent StepComponent `json:"next_component,omitempty"` // Next component in the step
+	Text          string         `json:"text,omitempty"`           // Instruction text
+	Position      SourcePosition `json:"position,omitzero"`        // Where the component is in the recipe source
+	NextComponent StepComponent  `json:"next_component,omitempty"` // Next component in the step
 	CooklangRenderable
 }
 
@@ -453,12 +539,13 @@ type Instruction struct {
 //
 // Example Cooklang syntax: ~{10%minutes}, ~boil{15%min}
 type Timer struct {
-	Duration      string        `json:"duration,omitempty"`       // Duration value (e.g., "10")
-	Name          string        `json:"name,omitempty"`           // Timer name/description (e.g., "boil", "rest")
-	Text          string        `json:"text,omitempty"`           // Full timer text
-	Unit          string        `json:"unit,omitempty"`           // Time unit (e.g., "minutes", "hours")
-	Annotation    string        `json:"annotation,omitempty"`     // Optional annotation
-	NextComponent StepComponent `json:"next_component,omitempty"` // Next component in the step
+	Duration      string         `json:"duration,omitempty"`       // Duration value (e.g., "10")
+	Name          string         `json:"name,omitempty"`           // Timer name/description (e.g., "boil", "rest")
+	Text          string         `json:"text,omitempty"`           // Full timer text
+	Unit          string         `json:"unit,omitempty"`           // Time unit (e.g., "minutes", "hours")
+	Annotation    string         `json:"annotation,omitempty"`     // Optional annotation
+	Position      SourcePosition `json:"position,omitzero"`        // Where the component is in the recipe source
+	NextComponent StepComponent  `json:"next_component,omitempty"` // Next component in the step
 	CooklangRenderable
 }
 
@@ -466,10 +553,12 @@ type Timer struct {
 //
 // Example Cooklang syntax: #pot{}, #bowl{2}, #oven{}
 type Cookware struct {
-	Name          string        `json:"name,omitempty"`           // Cookware name (e.g., "pot", "bowl", "oven")
-	Quantity      int           `json:"quantity,omitempty"`       // Number of items needed (default 1)
-	Annotation    string        `json:"annotation,omitempty"`     // Optional annotation (e.g., "large", "non-stick")
-	NextComponent StepComponent `json:"next_component,omitempty"` // Next component in the step
+	Name          string         `json:"name,omitempty"`           // Cookware name (e.g., "pot", "bowl", "oven")
+	Quantity      int            `json:"quantity,omitempty"`       // Number of items needed (default 1)
+	QuantityText  string         `json:"quantity_text,omitempty"`  // Quantity as written when it is not a number (e.g., "two", "a pair of")
+	Annotation    string         `json:"annotation,omitempty"`     // Optional annotation (e.g., "large", "non-stick")
+	Position      SourcePosition `json:"position,omitzero"`        // Where the component is in the recipe source
+	NextComponent StepComponent  `json:"next_component,omitempty"` // Next component in the step
 	CooklangRenderable
 }
 
@@ -478,8 +567,9 @@ type Cookware struct {
 //
 // Example Cooklang syntax: = Dough, == Filling ==
 type Section struct {
-	Name          string        `json:"name,omitempty"`           // Section name (e.g., "Dough", "Filling")
-	NextComponent StepComponent `json:"next_component,omitempty"` // Next component in the step
+	Name          string         `json:"name,omitempty"`           // Section name (e.g., "Dough", "Filling")
+	Position      SourcePosition `json:"position,omitzero"`        // Where the component is in the recipe source
+	NextComponent StepComponent  `json:"next_component,omitempty"` // Next component in the step
 	CooklangRenderable
 }
 
@@ -490,9 +580,10 @@ type Section struct {
 // - Line comment: -- This is a comment
 // - Block comment: [- This is a block comment -]
 type Comment struct {
-	Text          string        `json:"text,omitempty"`           // Comment text
-	IsBlock       bool          `json:"is_block,omitempty"`       // True if this is a block comment [- -]
-	NextComponent StepComponent `json:"next_component,omitempty"` // Next component in the step
+	Text          string         `json:"text,omitempty"`           // Comment text
+	IsBlock       bool           `json:"is_block,omitempty"`       // True if this is a block comment [- -]
+	Position      SourcePosition `json:"position,omitzero"`        // Where the component is in the recipe source
+	NextComponent StepComponent  `json:"next_component,omitempty"` // Next component in the step
 	CooklangRenderable
 }
 
@@ -505,30 +596,56 @@ type Comment struct {
 // > This is a multi-line note
 // > that continues here.
 type Note struct {
-	Text          string        `json:"text,omitempty"`           // Note text
-	NextComponent StepComponent `json:"next_component,omitempty"` // Next component in the step
+	Text          string         `json:"text,omitempty"`           // Note text
+	Position      SourcePosition `json:"position,omitzero"`        // Where the component is in the recipe source
+	NextComponent StepComponent  `json:"next_component,omitempty"` // Next component in the step
 	CooklangRenderable
 }
 
 // RecipeReference represents a reference to another recipe file (e.g., @./sauces/Hollandaise{150%g}).
 // Path is relative to the recipe root directory, without the .cook extension.
 type RecipeReference struct {
-	Path          string        `json:"path"`                     // Relative path to the referenced recipe
-	Quantity      float32       `json:"quantity,omitempty"`       // Quantity (scaling factor, servings, or unit amount)
-	Unit          string        `json:"unit,omitempty"`           // Unit (e.g., "servings", "ml", or empty for factor)
-	NextComponent StepComponent `json:"next_component,omitempty"` // Next component in the step
+	Path          string         `json:"path"`                     // Relative path to the referenced recipe
+	Quantity      float32        `json:"quantity,omitempty"`       // Quantity (scaling factor, servings, or unit amount)
+	Unit          string         `json:"unit,omitempty"`           // Unit (e.g., "servings", "ml", or empty for factor)
+	Position      SourcePosition `json:"position,omitzero"`        // Where the component is in the recipe source
+	NextComponent StepComponent  `json:"next_component,omitempty"` // Next component in the step
+	CooklangRenderable
+}
+
+// Extension is a custom component read by a handler registered with
+// parser.RegisterExtension, such as "&wine pairing{Riesling}", whose handler returned
+// a component type of its own. The Cooklang renderer writes it back as it was
+// written; the other renderers leave it out.
+type Extension struct {
+	Kind          string         `json:"kind"`                     // Component type the handler returned, e.g. "pairing"
+	Name          string         `json:"name,omitempty"`           // Name the handler returned, e.g. "wine pairing"
+	Value         string         `json:"value,omitempty"`          // Value the handler returned, e.g. "Riesling"
+	Quantity      string         `json:"quantity,omitempty"`       // Quantity the handler returned, as written
+	Unit          string         `json:"unit,omitempty"`           // Unit the handler returned
+	Source        string         `json:"source"`                   // Source text, e.g. "&wine pairing{Riesling}"
+	Position      SourcePosition `json:"position,omitzero"`        // Where the component is in the recipe source
+	NextComponent StepComponent  `json:"next_component,omitempty"` // Next component in the step
 	CooklangRenderable
 }
 
 // ParseFile reads and parses a Cooklang recipe file, returning a Recipe object.
-// It automatically detects and includes associated image files matching the recipe filename.
+// It automatically detects and includes associated image files matching the recipe filename,
+// and infers the title from the file name if the recipe has no title metadata (see InferTitle).
 //
 // Image detection looks for files with the same base name:
 //   - Recipe.cook → Recipe.jpg, Recipe.png, Recipe.jpeg
 //   - Recipe.cook → Recipe-1.jpg, Recipe-2.png, etc. (numbered variants)
+//   - Recipe.cook → Recipe.0.jpg, Recipe.1.png, etc. (step images, see Step.Images)
+//
+// Options select extended mode, disable image detection (WithoutImageDetection),
+// register custom units, add metadata defaults (WithDirectoryDefaults reads the
+// directory's _defaults.yml), enable logging or limit the file size; see ParseOption.
+// Use ParseFileWithOptions to customize image detection.
 //
 // Parameters:
 //   - filename: Path to the .cook file to parse
+//   - opts: Optional parse options
 //
 // Returns:
 //   - *Recipe: The parsed recipe with all metadata, steps, and detected images
@@ -542,71 +659,56 @@ type RecipeReference struct {
 //	}
 //	fmt.Printf("Recipe: %s\n", recipe.Title)
 //	fmt.Printf("Servings: %.0f\n", recipe.Servings)
-func ParseFile(filename string) (*Recipe, error) {
-	content, err := os.ReadFile(filename)
+func ParseFile(filename string, opts ...ParseOption) (*Recipe, error) {
+	config := newParseConfig(opts)
+	content, err := config.readFile(filename)
 	if err != nil {
 		return nil, err
 	}
-	p := parser.New()
-	parsedRecipe, err := p.ParseBytes(content)
+	if config.directoryDefaults {
+		defaults, err := LoadDefaults(filepath.Dir(filename))
+		if err != nil {
+			return nil, err
+		}
+		// Defaults given with WithDefaults win over the directory's
+		config.defaults = Metadata(mergeDefaults(parser.Metadata(maps.Clone(config.defaults)), defaults))
+	}
+	recipe, err := config.parse(filename, string(content))
 	if err != nil {
 		return nil, err
 	}
-	recipe := ToCooklangRecipe(parsedRecipe)
+	recipe.InferTitle(filename, false)
 
 	// Auto-detect and add images from filesystem
-	detectedImages := findRecipeImages(filename)
-	if len(detectedImages) > 0 {
-		// Merge detected images with existing ones, avoiding duplicates
-		recipe.Images = mergeUniqueStrings(recipe.Images, detectedImages)
-		// Update metadata to reflect the merged images
-		if len(recipe.Images) > 0 {
-			recipe.Metadata["images"] = strings.Join(recipe.Images, ", ")
-		}
+	if config.file.DetectImages {
+		recipe.DetectImages(filename, config.file)
+		config.debug("detected images", "source", filename, "images", len(recipe.Images))
 	}
 
 	return recipe, nil
 }
 
-// findRecipeImages looks for image files matching the recipe filename pattern.
-// For a recipe file "Recipe.cook", it searches for:
-// - Recipe.jpg, Recipe.jpeg, Recipe.png (base image)
-// - Recipe-1.jpg, Recipe-2.jpg, etc. (numbered variants)
-// Returns just the filenames (not full paths) of found images.
-func findRecipeImages(cookFilePath string) []string {
-	dir := filepath.Dir(cookFilePath)
-	baseName := strings.TrimSuffix(filepath.Base(cookFilePath), ".cook")
-
-	var images []string
-	extensions := []string{".jpg", ".jpeg", ".png"}
-
-	// Check for base image (e.g., Recipe.jpg)
-	for _, ext := range extensions {
-		imagePath := filepath.Join(dir, baseName+ext)
-		if fileExists(imagePath) {
-			images = append(images, baseName+ext)
-		}
-	}
-
-	// Check for numbered images (e.g., Recipe-1.jpg, Recipe-2.jpg)
-	// We'll check up to 99 numbered variants
-	for i := 1; i <= 99; i++ {
-		foundAny := false
-		for _, ext := range extensions {
-			numberedName := fmt.Sprintf("%s-%d%s", baseName, i, ext)
-			imagePath := filepath.Join(dir, numberedName)
-			if fileExists(imagePath) {
-				images = append(images, numberedName)
-				foundAny = true
-			}
-		}
-		// If we didn't find any images for this number, stop searching
-		if !foundAny {
-			break
-		}
-	}
-
-	return images
+// ParseFileWithOptions reads and parses a recipe file like ParseFile, with control
+// over image detection.
+//
+// Parameters:
+//   - filename: Path to the .cook file to parse
+//   - opts: Image detection settings
+//   - options: Optional parse options, as for ParseFile
+//
+// Returns:
+//   - *Recipe: The parsed recipe
+//   - error: Any error encountered during file reading or parsing
+//
+// Example:
+//
+//	recipe, err := cooklang.ParseFileWithOptions("recipes/lasagna.cook", cooklang.ParseFileOptions{
+//	    DetectImages:    true,
+//	    ImagePatterns:   []string{"{name}", "photos/{name}.step{step}"},
+//	    ImageExtensions: []string{".webp", ".jpg"},
+//	})
+func ParseFileWithOptions(filename string, opts ParseFileOptions, options ...ParseOption) (*Recipe, error) {
+	return ParseFile(filename, append(options, func(c *parseConfig) { c.file = opts })...)
 }
 
 // fileExists checks if a file exists and is not a directory.
@@ -649,6 +751,7 @@ func mergeUniqueStrings(slice1, slice2 []string) []string {
 //
 // Parameters:
 //   - content: The raw Cooklang recipe content as bytes
+//   - opts: Optional parse options (see ParseOption)
 //
 // Returns:
 //   - *Recipe: The parsed recipe with all metadata and steps
@@ -657,14 +760,9 @@ func mergeUniqueStrings(slice1, slice2 []string) []string {
 // Example:
 //
 //	content := []byte("---\ntitle: Quick Pasta\n---\n\nBoil @water{2%L} and add @pasta{100%g}.")
-//	recipe, err := cooklang.ParseBytes(content)
-func ParseBytes(content []byte) (*Recipe, error) {
-	p := parser.New()
-	parsedRecipe, err := p.ParseBytes(content)
-	if err != nil {
-		return nil, err
-	}
-	return ToCooklangRecipe(parsedRecipe), nil
+//	recipe, err := cooklang.ParseBytes(content, cooklang.WithExtendedMode())
+func ParseBytes(content []byte, opts ...ParseOption) (*Recipe, error) {
+	return newParseConfig(opts).parse("input", string(content))
 }
 
 // ParseString parses Cooklang recipe content from a string.
@@ -672,6 +770,7 @@ func ParseBytes(content []byte) (*Recipe, error) {
 //
 // Parameters:
 //   - content: The Cooklang recipe content as a string
+//   - opts: Optional parse options (see ParseOption)
 //
 // Returns:
 //   - *Recipe: The parsed recipe with all metadata and steps
@@ -681,13 +780,8 @@ func ParseBytes(content []byte) (*Recipe, error) {
 //
 //	content := "---\ntitle: Quick Pasta\n---\n\nBoil @water{2%L}."
 //	recipe, err := cooklang.ParseString(content)
-func ParseString(content string) (*Recipe, error) {
-	p := parser.New()
-	recipe, err := p.ParseString(content)
-	if err != nil {
-		return nil, err
-	}
-	return ToCooklangRecipe(recipe), nil
+func ParseString(content string, opts ...ParseOption) (*Recipe, error) {
+	return newParseConfig(opts).parse("input", content)
 }
 
 // CreateTypedUnit attempts to find a unit in go-units or creates a new one if not found.
@@ -712,6 +806,27 @@ func CreateTypedUnit(unitStr string) *units.Unit {
 	return &newUnit
 }
 
+// IsKnownUnit reports whether a unit can be converted: it is a cooking unit such as
+// "cup" or "tbsp", a bar unit such as "dash", or a unit known to go-units such as "g".
+// Units such as "clove" or "pinch" are valid Cooklang but are not known. Units a
+// recipe declares itself are in Recipe.CustomUnits.
+//
+// Example:
+//
+//	cooklang.IsKnownUnit("tablespoons") // true
+//	cooklang.IsKnownUnit("cloves")      // false
+func IsKnownUnit(unit string) bool {
+	if unit == "" {
+		return false
+	}
+	if isCookingUnit(unit) || GetCocktailUnit(unit) != nil {
+		return true
+	}
+	// Units created by CreateTypedUnit are registered with go-units too, but have no quantity
+	found, err := units.Find(unit)
+	return err == nil && found.Quantity != ""
+}
+
 // ToCooklangRecipe converts a parser.Recipe to a cooklang.Recipe.
 // This is the internal function that transforms the parser's output into the high-level Recipe structure
 // with all metadata fields populated and step components organized as linked lists.
@@ -742,40 +857,31 @@ func ToCooklangRecipe(pRecipe *parser.Recipe) *Recipe {
 	if author, ok := pRecipe.Metadata["author"]; ok {
 		recipe.Author = author
 	}
-	if servingsStr, ok := pRecipe.Metadata["servings"]; ok {
-		if servings, err := strconv.ParseFloat(servingsStr, 32); err == nil {
-			recipe.Servings = float32(servings)
-		}
+	if servings, err := recipe.Metadata.GetFloat("servings"); err == nil {
+		recipe.Servings = float32(servings)
 	}
 	// Default to 1 serving if not specified or invalid
 	if recipe.Servings <= 0 {
 		recipe.Servings = 1
 	}
-	if dateStr, ok := pRecipe.Metadata["date"]; ok {
-		if date, err := time.Parse("2006-01-02", dateStr); err == nil {
-			recipe.Date = date
-		}
+	if yieldQuantity, yieldUnit := ParseYield(pRecipe.Metadata["yield"]); yieldQuantity > 0 {
+		recipe.Yield = Yield{Quantity: yieldQuantity, Unit: yieldUnit}
 	}
-	if imgsStr, ok := pRecipe.Metadata["images"]; ok {
-		// Assuming images are comma-separated
-		recipe.Images = strings.Split(strings.TrimSpace(imgsStr), ",")
-		for i := range recipe.Images {
-			recipe.Images[i] = strings.TrimSpace(recipe.Images[i])
-		}
+	if date, err := recipe.Metadata.GetDate("date"); err == nil {
+		recipe.Date = date
 	}
-	if tagsStr, ok := pRecipe.Metadata["tags"]; ok {
-		// Assuming tags are comma-separated
-		recipe.Tags = strings.Split(strings.TrimSpace(tagsStr), ",")
-		for i := range recipe.Tags {
-			recipe.Tags[i] = strings.TrimSpace(recipe.Tags[i])
-		}
+	if images := recipe.Metadata.GetStringSlice("images"); images != nil {
+		recipe.Images = images
+	}
+	if tags := recipe.Metadata.GetStringSlice("tags"); tags != nil {
+		recipe.Tags = tags
 	}
 
 	var prevStep *Step
 
 	for _, step := range pRecipe.Steps {
 
-		newStep := &Step{}
+		newStep := &Step{Position: step.Position}
 
 		var prevComponent StepComponent
 
@@ -785,35 +891,31 @@ func ToCooklangRecipe(pRecipe *parser.Recipe) *Recipe {
 
 			switch component.Type {
 			case "ingredient":
-				var quant float32
-				if component.Quantity == "some" {
-					quant = -1 // Use -1 to indicate "some" quantity
-				} else {
-					quant64, err := strconv.ParseFloat(component.Quantity, 32)
-					if err != nil {
-						quant = -1 // Default to "some" if parsing fails
-					} else {
-						quant = float32(quant64)
-					}
+				// Decimals, fractions and ranges such as "1/3", "1-2", or "2 to 3"
+				quant, err := ParseQuantity(component.Quantity)
+				if err != nil {
+					quant = SomeQuantity() // Default to "some" if parsing fails
 				}
 				stepComp = &Ingredient{
-					Name:       component.Name,
-					Quantity:   quant,
-					Unit:       component.Unit,
-					Fixed:      component.Fixed,
-					Optional:   component.Optional,
-					TypedUnit:  CreateTypedUnit(component.Unit),
-					Annotation: component.Value,
+					Name:        component.Name,
+					Quantity:    quant,
+					Unit:        component.Unit,
+					Fixed:       component.Fixed,
+					Optional:    component.Optional,
+					Reference:   component.Reference,
+					Approximate: component.Approximate,
+					TypedUnit:   CreateTypedUnit(component.Unit),
+					Annotation:  component.Value,
+					Position:    component.Position,
 				}
 			case "cookware":
-				cookwareQuant, err := strconv.Atoi(component.Quantity)
-				if err != nil {
-					cookwareQuant = 1 // Default to 1 if parsing fails
-				}
+				cookwareQuant, quantityText := parseCookwareQuantity(component.Quantity)
 				stepComp = &Cookware{
-					Name:       component.Name,
-					Quantity:   cookwareQuant,
-					Annotation: component.Value,
+					Name:         component.Name,
+					Quantity:     cookwareQuant,
+					QuantityText: quantityText,
+					Annotation:   component.Value,
+					Position:     component.Position,
 				}
 			case "timer":
 				stepComp = &Timer{
@@ -821,28 +923,50 @@ func ToCooklangRecipe(pRecipe *parser.Recipe) *Recipe {
 					Unit:       component.Unit,
 					Name:       component.Name,
 					Annotation: component.Value,
+					Position:   component.Position,
 				}
 			case "text":
-				stepComp = &Instruction{
-					Text: component.Value,
+				// Temperatures in the text ("180°C") become components of their own;
+				// they share the position of the text they were found in
+				parts := splitTemperatures(component.Value)
+				for _, part := range parts {
+					switch part := part.(type) {
+					case *Instruction:
+						part.Position = component.Position
+					case *Temperature:
+						part.Position = component.Position
+					}
 				}
+				for _, part := range parts[:len(parts)-1] {
+					if newStep.FirstComponent == nil {
+						newStep.FirstComponent = part
+					} else {
+						prevComponent.SetNext(part)
+					}
+					prevComponent = part
+				}
+				stepComp = parts[len(parts)-1]
 			case "section":
 				stepComp = &Section{
-					Name: component.Name,
+					Name:     component.Name,
+					Position: component.Position,
 				}
 			case "comment":
 				stepComp = &Comment{
-					Text:    component.Value,
-					IsBlock: false,
+					Text:     component.Value,
+					IsBlock:  false,
+					Position: component.Position,
 				}
 			case "blockComment":
 				stepComp = &Comment{
-					Text:    component.Value,
-					IsBlock: true,
+					Text:     component.Value,
+					IsBlock:  true,
+					Position: component.Position,
 				}
 			case "note":
 				stepComp = &Note{
-					Text: component.Value,
+					Text:     component.Value,
+					Position: component.Position,
 				}
 			case "recipeReference":
 				var refQty float32
@@ -860,6 +984,20 @@ func ToCooklangRecipe(pRecipe *parser.Recipe) *Recipe {
 					Path:     component.Name,
 					Quantity: refQty,
 					Unit:     component.Unit,
+					Position: component.Position,
+				}
+			default:
+				// Custom components of a type of their own (see parser.RegisterExtension)
+				if component.Source != "" {
+					stepComp = &Extension{
+						Kind:     component.Type,
+						Name:     component.Name,
+						Value:    component.Value,
+						Quantity: component.Quantity,
+						Unit:     component.Unit,
+						Source:   component.Source,
+						Position: component.Position,
+					}
 				}
 			}
 
@@ -884,9 +1022,36 @@ func ToCooklangRecipe(pRecipe *parser.Recipe) *Recipe {
 		}
 	}
 
+	recipe.addStepImages()
+
+	// Register custom units declared in the frontmatter (invalid declarations are skipped)
+	if unitsStr, ok := pRecipe.Metadata["units"]; ok {
+		recipe.CustomUnits, _ = ParseCustomUnits(unitsStr)
+		recipe.SetCustomUnits(nil)
+	}
+
 	return recipe
 }
 
+// cookwareNumbers are the number words cookware quantities are counted from.
+var cookwareNumbers = map[string]int{
+	"one": 1, "two": 2, "three": 3, "four": 4, "five": 5, "six": 6,
+	"seven": 7, "eight": 8, "nine": 9, "ten": 10, "eleven": 11, "twelve": 12,
+}
+
+// parseCookwareQuantity reads a cookware quantity: whole numbers are counted, and
+// anything else is kept as written, counting number words ("two") and 1 otherwise.
+func parseCookwareQuantity(quantity string) (int, string) {
+	if n, err := strconv.Atoi(quantity); err == nil {
+		return n, ""
+	}
+	quantity = strings.TrimSpace(quantity)
+	if n, ok := cookwareNumbers[strings.ToLower(quantity)]; ok {
+		return n, quantity
+	}
+	return 1, quantity
+}
+
 // Render returns a human-readable representation of the recipe.
 // If a custom renderer has been set via SetRenderer or SetRendererFunc, it will be used.
 // Otherwise, a default text format is used showing metadata, ingredients, and steps.
@@ -964,22 +1129,36 @@ func (i *Ingredient) ConvertTo(targetUnitStr string) (*Ingredient, error) {
 		return nil, fmt.Errorf("ingredient has no typed unit")
 	}
 
-	if i.Quantity == -1 {
+	if i.Quantity.IsSome() {
 		return nil, fmt.Errorf("cannot convert ingredients with 'some' quantity")
 	}
 
+	// Custom units declared by the recipe are converted via their base unit
+	if converted, handled, err := i.convertCustomUnit(targetUnitStr); handled {
+		return converted, err
+	}
+
 	// Try custom cooking unit conversions first
 	if isCookingUnit(i.Unit) && isCookingUnit(targetUnitStr) {
-		convertedValue, err := convertCookingUnit(float64(i.Quantity), i.Unit, targetUnitStr)
-		if err == nil {
+		if _, err := convertCookingUnit(i.Quantity.Min(), i.Unit, targetUnitStr); err == nil {
 			targetUnit := CreateTypedUnit(targetUnitStr)
 			converted := &Ingredient{
-				Name:           i.Name,
-				Quantity:       float32(convertedValue),
+				Name: i.Name,
+				Quantity: i.Quantity.mapBounds(func(v float64) float64 {
+					value, _ := convertCookingUnit(v, i.Unit, targetUnitStr)
+					return value
+				}),
+				Fixed:          i.Fixed,
+				Optional:       i.Optional,
+				Reference:      i.Reference,
+				Approximate:    i.Approximate,
 				Unit:           targetUnitStr,
 				TypedUnit:      targetUnit,
 				Subinstruction: i.Subinstruction,
+				Annotation:     i.Annotation,
+				Position:       i.Position,
 				NextComponent:  i.NextComponent,
+				customUnits:    i.customUnits,
 			}
 			return converted, nil
 		}
@@ -993,19 +1172,28 @@ func (i *Ingredient) ConvertTo(targetUnitStr string) (*Ingredient, error) {
 	}
 
 	// Convert using go-units
-	convertedValue, err := units.ConvertFloat(float64(i.Quantity), *i.TypedUnit, targetUnit)
-	if err != nil {
+	if _, err := units.ConvertFloat(i.Quantity.Min(), *i.TypedUnit, targetUnit); err != nil {
 		return nil, fmt.Errorf("cannot convert from %s to %s: %v", i.Unit, targetUnitStr, err)
 	}
 
 	// Create a new ingredient with converted values
 	converted := &Ingredient{
-		Name:           i.Name,
-		Quantity:       float32(convertedValue.Float()),
+		Name: i.Name,
+		Quantity: i.Quantity.mapBounds(func(v float64) float64 {
+			value, _ := units.ConvertFloat(v, *i.TypedUnit, targetUnit)
+			return value.Float()
+		}),
+		Fixed:          i.Fixed,
+		Optional:       i.Optional,
+		Reference:      i.Reference,
+		Approximate:    i.Approximate,
 		Unit:           targetUnitStr,
 		TypedUnit:      &targetUnit,
 		Subinstruction: i.Subinstruction,
+		Annotation:     i.Annotation,
+		Position:       i.Position,
 		NextComponent:  i.NextComponent,
+		customUnits:    i.customUnits,
 	}
 
 	return converted, nil
@@ -1032,13 +1220,17 @@ func (i *Ingredient) CanConvertTo(targetUnitStr string) bool {
 		return false
 	}
 
-	if i.Quantity == -1 {
+	if i.Quantity.IsSome() {
 		return false // Can't convert "some" quantities
 	}
 
+	if _, handled, err := i.convertCustomUnit(targetUnitStr); handled {
+		return err == nil
+	}
+
 	// Try custom cooking unit conversions first
 	if isCookingUnit(i.Unit) && isCookingUnit(targetUnitStr) {
-		_, err := convertCookingUnit(float64(i.Quantity), i.Unit, targetUnitStr)
+		_, err := convertCookingUnit(i.Quantity.Min(), i.Unit, targetUnitStr)
 		return err == nil
 	}
 
@@ -1049,7 +1241,7 @@ func (i *Ingredient) CanConvertTo(targetUnitStr string) bool {
 		targetUnit = units.NewUnit(targetUnitStr, targetUnitStr)
 	}
 
-	_, err = units.ConvertFloat(float64(i.Quantity), *i.TypedUnit, targetUnit)
+	_, err = units.ConvertFloat(i.Quantity.Min(), *i.TypedUnit, targetUnit)
 	return err == nil
 }
 
@@ -1071,6 +1263,11 @@ func (i *Ingredient) GetUnitType() string {
 		return ""
 	}
 
+	// Units declared by the recipe take the type of their base unit
+	if customType := i.customUnitType(); customType != "" {
+		return customType
+	}
+
 	// Check for custom cooking units first
 	if cookingType := getCookingUnitType(i.Unit); cookingType != "" {
 		return cookingType
@@ -1202,13 +1399,40 @@ func (r *RecipeReference) Render() string {
 	return fmt.Sprintf("@%s{}", r.Path)
 }
 
+// SetNext sets the next component in the step's linked list.
+// This implements the StepComponent interface for recipe step traversal.
+func (e *Extension) SetNext(next StepComponent) {
+	e.NextComponent = next
+}
+
+// GetNext returns the next component in the step's linked list.
+// This implements the StepComponent interface for recipe step traversal.
+func (e *Extension) GetNext() StepComponent {
+	return e.NextComponent
+}
+
+// Render returns the custom component as it was written.
+func (e *Extension) Render() string {
+	return e.Source
+}
+
 // IngredientList represents a collection of ingredients with unit consolidation capabilities.
 // It provides methods for grouping, converting, and consolidating ingredients for shopping lists
 // and recipe scaling operations.
+//
+// The ingredients of a list from Recipe.GetIngredients are copies, detached from the
+// recipe's steps, so changing or converting them leaves the recipe alone; ApplyTo
+// writes them back.
 type IngredientList struct {
 	Ingredients []*Ingredient // The list of ingredients
+
+	origins map[*Ingredient]int // Recipe ingredients the entries were copied from, by their index in WalkIngredients order; nil for lists not taken from a recipe
 }
 
+// ErrDetachedIngredients is returned, wrapped, by IngredientList.ApplyTo for lists
+// whose ingredients are not from the recipe.
+var ErrDetachedIngredients = errors.New("ingredients are not from the recipe")
+
 // NewIngredientList creates a new empty ingredient list.
 //
 // Returns:
@@ -1269,10 +1493,12 @@ func (il *IngredientList) GetIngredientsByName(name string) []*Ingredient {
 // This is useful for creating shopping lists where multiple mentions of the same ingredient
 // should be combined into a single entry.
 //
-// If targetUnit is empty, the method attempts to find a common unit from the ingredients.
-// If targetUnit is specified, all compatible ingredients are converted to that unit before consolidation.
+// If targetUnit is empty, each unit category (mass, volume, ...) is added up in the best
+// of the units its ingredients use. If targetUnit is specified, all compatible ingredients
+// are converted to that unit before consolidation.
 //
-// Ingredients with "some" quantity (-1) or incompatible units are kept separate.
+// Ingredients with "some" quantities or incompatible units are kept separate; use
+// ConsolidateByNameWithReport to learn which and why.
 //
 // Parameters:
 //   - targetUnit: The unit to convert all ingredients to (empty string to auto-detect)
@@ -1289,107 +1515,8 @@ func (il *IngredientList) GetIngredientsByName(name string) []*Ingredient {
 //	consolidated, _ := list.ConsolidateByName("")
 //	// consolidated will have one "flour" entry with 250g
 func (il *IngredientList) ConsolidateByName(targetUnit string) (*IngredientList, error) {
-	consolidated := NewIngredientList()
-	ingredientMap := make(map[string][]*Ingredient)
-
-	// Group ingredients by name
-	for _, ingredient := range il.Ingredients {
-		ingredientMap[ingredient.Name] = append(ingredientMap[ingredient.Name], ingredient)
-	}
-
-	// Process each group
-	for name, ingredients := range ingredientMap {
-		if len(ingredients) == 1 {
-			// Single ingredient - convert to target unit if specified
-			ing := ingredients[0]
-			if targetUnit != "" && ing.Unit != "" && ing.Unit != targetUnit && ing.CanConvertTo(targetUnit) {
-				converted, err := ing.ConvertTo(targetUnit)
-				if err == nil {
-					consolidated.Add(converted)
-					continue
-				}
-			}
-			// No conversion needed or possible, add as-is
-			consolidated.Add(ing)
-			continue
-		}
-
-		// Multiple ingredients with same name - try to consolidate
-		var totalQuantity float32
-		var unitToUse string
-		var typedUnit *units.Unit
-		var hasConvertibleUnits bool
-
-		// Check if we should use the target unit or find a common unit
-		if targetUnit != "" {
-			unitToUse = targetUnit
-			typedUnit = CreateTypedUnit(targetUnit)
-			hasConvertibleUnits = true
-		} else {
-			// Use the unit from the first ingredient that has a unit
-			for _, ing := range ingredients {
-				if ing.Unit != "" {
-					unitToUse = ing.Unit
-					typedUnit = ing.TypedUnit
-					hasConvertibleUnits = true
-					break
-				}
-			}
-		}
-
-		// Try to convert and sum quantities
-		for _, ingredient := range ingredients {
-			if ingredient.Quantity == -1 {
-				// "Some" quantity - add separately
-				consolidated.Add(ingredient)
-				continue
-			}
-
-			if ingredient.Unit == "" {
-				// Unitless ingredient - add to list separately if we have units, or sum if all unitless
-				if !hasConvertibleUnits {
-					totalQuantity += ingredient.Quantity
-				} else {
-					// Add unitless ingredient separately
-					consolidated.Add(ingredient)
-				}
-				continue
-			}
-
-			if hasConvertibleUnits && ingredient.CanConvertTo(unitToUse) {
-				converted, err := ingredient.ConvertTo(unitToUse)
-				if err != nil {
-					// Can't convert, add separately
-					consolidated.Add(ingredient)
-					continue
-				}
-				totalQuantity += converted.Quantity
-			} else if ingredient.Unit == unitToUse || unitToUse == "" {
-				// Same unit or no target unit specified
-				totalQuantity += ingredient.Quantity
-				if unitToUse == "" {
-					unitToUse = ingredient.Unit
-					typedUnit = ingredient.TypedUnit
-				}
-			} else {
-				// Different unit that can't be converted, add separately
-				consolidated.Add(ingredient)
-			}
-		}
-
-		// Add consolidated ingredient if we have something to consolidate
-		if totalQuantity > 0 {
-			consolidatedIngredient := &Ingredient{
-				Name:      name,
-				Quantity:  totalQuantity,
-				Unit:      unitToUse,
-				TypedUnit: typedUnit,
-			}
-			consolidated.Add(consolidatedIngredient)
-		}
-	}
-
-	return consolidated, nil
+	consolidated, _, err := il.ConsolidateByNameWithReport(targetUnit)
+	return consolidated, err
 }
 
 // ToMap returns a map of ingredient names to their formatted quantities.
@@ -1398,7 +1525,9 @@ func (il *IngredientList) ConsolidateByName(targetUnit string) (*IngredientList,
 // The quantity formatting follows these rules:
 //   - Whole numbers are shown without decimals (e.g., "100 g")
 //   - Fractional quantities show one decimal place (e.g., "1.5 cup")
-//   - "Some" quantities (-1) are displayed as "some" or "some [unit]"
+//   - "Some" quantities are displayed as "some" or "some [unit]"
+//   - Ranges are displayed with both bounds (e.g., "1-2 tsp")
+//   - Approximate quantities are prefixed with "≈" (e.g., "≈2 cup")
 //   - Unitless ingredients show just the quantity or "some"
 //
 // Returns:
@@ -1417,39 +1546,47 @@ func (il *IngredientList) ConsolidateByName(targetUnit string) (*IngredientList,
 func (il *IngredientList) ToMap() map[string]string {
 	result := make(map[string]string)
 	for _, ingredient := range il.Ingredients {
-		key := ingredient.Name
-		if ingredient.Unit != "" {
-			if ingredient.Quantity == -1 {
-				result[key] = "some " + ingredient.Unit
-			} else {
-				// Use %g to avoid scientific notation for reasonable numbers
-				quantity := ingredient.Quantity
-				if quantity == float32(int(quantity)) {
-					// Show as integer if it's a whole number
-					result[key] = fmt.Sprintf("%.0f %s", quantity, ingredient.Unit)
-				} else {
-					result[key] = fmt.Sprintf("%.1f %s", quantity, ingredient.Unit)
-				}
-			}
-		} else {
-			if ingredient.Quantity == -1 {
-				result[key] = "some"
-			} else if ingredient.Quantity > 0 {
-				if ingredient.Quantity == float32(int(ingredient.Quantity)) {
-					result[key] = fmt.Sprintf("%.0f", ingredient.Quantity)
-				} else {
-					result[key] = fmt.Sprintf("%.1f", ingredient.Quantity)
-				}
-			} else {
-				result[key] = "some"
-			}
-		}
+		result[ingredient.Name] = ingredient.shoppingAmount()
 	}
 	return result
 }
 
+// shoppingAmount formats the ingredient's quantity and unit as in IngredientList.ToMap.
+func (i Ingredient) shoppingAmount() string {
+	var amount string
+	switch {
+	case i.Quantity.IsSome(), i.Unit == "" && !i.Quantity.HasAmount():
+		amount = "some"
+	case i.Quantity.IsRange():
+		amount = formatMapQuantity(i.Quantity.Min()) + "-" + formatMapQuantity(i.Quantity.Max())
+	default:
+		amount = formatMapQuantity(i.Quantity.Min())
+	}
+	if i.Unit != "" {
+		amount += " " + i.Unit
+	}
+	if i.Approximate && i.Quantity.HasAmount() {
+		amount = ApproximatePrefix + amount
+	}
+	return amount
+}
+
+// IsReference reports whether the ingredient refers back to one added in an earlier
+// step, written with "@&" as in "fold in the reserved @&flour{}". References are
+// highlighted in the step text like other ingredients, but GetIngredients and
+// consolidation leave them out, so the ingredient is only bought once.
+func (i *Ingredient) IsReference() bool {
+	return i.Reference
+}
+
 // GetIngredients returns all ingredients from a recipe, extracted from all steps.
-// This traverses the recipe's linked list structure to collect every ingredient mention.
+// This traverses the recipe's linked list structure to collect every ingredient mention,
+// except references to ingredients added earlier (see Ingredient.IsReference).
+//
+// The ingredients are copies that are not linked into the recipe's steps, so the list
+// can be changed, converted and consolidated without changing the recipe. To change
+// the recipe's ingredients, apply the list to it with IngredientList.ApplyTo, or walk
+// its steps with WalkIngredients.
 //
 // Returns:
 //   - *IngredientList: A list containing all ingredients in order of appearance
@@ -1463,21 +1600,86 @@ func (il *IngredientList) ToMap() map[string]string {
 //	}
 func (r *Recipe) GetIngredients() *IngredientList {
 	ingredientList := NewIngredientList()
+	ingredientList.origins = make(map[*Ingredient]int)
+
+	mention := 0
+	r.WalkIngredients(func(ingredient *Ingredient) bool {
+		if !ingredient.IsReference() {
+			detached := copyComponent(ingredient).(*Ingredient)
+			ingredientList.Add(detached)
+			ingredientList.origins[detached] = mention
+		}
+		mention++
+		return true
+	})
 
-	// Walk through all steps and components to find ingredients
-	currentStep := r.FirstStep
-	for currentStep != nil {
-		currentComponent := currentStep.FirstComponent
-		for currentComponent != nil {
-			if ingredient, ok := currentComponent.(*Ingredient); ok {
-				ingredientList.Add(ingredient)
-			}
-			currentComponent = currentComponent.GetNext()
+	return ingredientList
+}
+
+// ApplyTo writes the ingredients of a list from GetIngredients back into the recipe,
+// e.g. after converting them: each ingredient replaces the one it was copied from,
+// which keeps its place in its step and its source position. Lists made from such a
+// list by ConvertToSystem, ConvertToSystemWithProfile, ConvertToSystemBartender,
+// ApplyPreferredUnits and RoundCounts can be applied too. Ingredients added to the
+// list, and the entries of consolidated lists, are not from the recipe and are left
+// out.
+//
+// Parameters:
+//   - recipe: The recipe the list was taken from, or a clone of it
+//
+// Returns:
+//   - error: ErrDetachedIngredients, wrapped, if the list was not taken from a recipe
+//     or refers to ingredients the recipe does not have; the recipe is then unchanged
+//
+// Example:
+//
+//	us := recipe.GetIngredients().ConvertToSystem(cooklang.UnitSystemUS)
+//	if err := us.ApplyTo(recipe); err != nil {
+//	    log.Fatal(err)
+//	}
+func (il *IngredientList) ApplyTo(recipe *Recipe) error {
+	if il.origins == nil {
+		return fmt.Errorf("%w: the list was not taken from a recipe", ErrDetachedIngredients)
+	}
+	var mentions []*Ingredient
+	recipe.WalkIngredients(func(ingredient *Ingredient) bool {
+		mentions = append(mentions, ingredient)
+		return true
+	})
+	for _, ingredient := range il.Ingredients {
+		if origin, ok := il.origins[ingredient]; ok && origin >= len(mentions) {
+			return fmt.Errorf("%w: the recipe has %d ingredients, %s is number %d", ErrDetachedIngredients, len(mentions), ingredient.Name, origin+1)
 		}
-		currentStep = currentStep.NextStep
 	}
 
-	return ingredientList
+	for _, ingredient := range il.Ingredients {
+		origin, ok := il.origins[ingredient]
+		if !ok {
+			continue
+		}
+		target := mentions[origin]
+		updated := *ingredient
+		updated.Position, updated.NextComponent, updated.customUnits = target.Position, target.NextComponent, target.customUnits
+		*target = updated
+	}
+	return nil
+}
+
+// mapIngredients returns a list of f applied to each ingredient, keeping track of the
+// recipe ingredient each result was copied from (see ApplyTo).
+func (il *IngredientList) mapIngredients(f func(*Ingredient) *Ingredient) *IngredientList {
+	result := NewIngredientList()
+	if il.origins != nil {
+		result.origins = make(map[*Ingredient]int, len(il.origins))
+	}
+	for _, ingredient := range il.Ingredients {
+		mapped := f(ingredient)
+		result.Add(mapped)
+		if origin, ok := il.origins[ingredient]; ok {
+			result.origins[mapped] = origin
+		}
+	}
+	return result
 }
 
 // GetCookware returns all cookware items from a recipe, extracted from all steps.
@@ -1495,18 +1697,10 @@ func (r *Recipe) GetIngredients() *IngredientList {
 func (r *Recipe) GetCookware() []*Cookware {
 	var cookware []*Cookware
 
-	// Walk through all steps and components to find cookware
-	currentStep := r.FirstStep
-	for currentStep != nil {
-		currentComponent := currentStep.FirstComponent
-		for currentComponent != nil {
-			if cw, ok := currentComponent.(*Cookware); ok {
-				cookware = append(cookware, cw)
-			}
-			currentComponent = currentComponent.GetNext()
-		}
-		currentStep = currentStep.NextStep
-	}
+	r.WalkCookware(func(cw *Cookware) bool {
+		cookware = append(cookware, cw)
+		return true
+	})
 
 	return cookware
 }
@@ -1526,21 +1720,16 @@ func (r *Recipe) GetCookware() []*Cookware {
 //	metricList := recipe.GetIngredients()
 //	usList := metricList.ConvertToSystem(cooklang.UnitSystemUS)
 func (il *IngredientList) ConvertToSystem(system UnitSystem) *IngredientList {
-	result := NewIngredientList()
-
-	for _, ingredient := range il.Ingredients {
-		converted := ingredient.ConvertToSystem(system)
-		result.Add(converted)
-	}
-
-	return result
+	return il.mapIngredients(func(ingredient *Ingredient) *Ingredient {
+		return ingredient.ConvertToSystem(system)
+	})
 }
 
 // ConvertToSystem converts an ingredient to the target unit system.
 // The conversion selects an appropriate unit based on the ingredient's unit type
 // (mass or volume) and converts the quantity accordingly.
 //
-// If the ingredient has no TypedUnit or has "some" quantity (-1), a copy is returned unchanged.
+// If the ingredient has no TypedUnit or has a "some" quantity, a copy is returned unchanged.
 //
 // Parameters:
 //   - system: The target unit system (UnitSystemMetric, UnitSystemUS, UnitSystemImperial)
@@ -1554,14 +1743,20 @@ func (il *IngredientList) ConvertToSystem(system UnitSystem) *IngredientList {
 //	usFlour := flour.ConvertToSystem(cooklang.UnitSystemUS)
 //	fmt.Printf("%v %s\n", usFlour.Quantity, usFlour.Unit) // "17.6 oz"
 func (i *Ingredient) ConvertToSystem(system UnitSystem) *Ingredient {
-	if i.TypedUnit == nil || i.Quantity == -1 {
+	if i.TypedUnit == nil || i.Quantity.IsSome() {
 		// Return a copy of the ingredient if it can't be converted
 		return &Ingredient{
 			Name:           i.Name,
 			Quantity:       i.Quantity,
+			Fixed:          i.Fixed,
+			Optional:       i.Optional,
+			Reference:      i.Reference,
+			Approximate:    i.Approximate,
 			Unit:           i.Unit,
 			TypedUnit:      i.TypedUnit,
 			Subinstruction: i.Subinstruction,
+			Annotation:     i.Annotation,
+			Position:       i.Position,
 			NextComponent:  i.NextComponent,
 		}
 	}
@@ -1573,9 +1768,15 @@ func (i *Ingredient) ConvertToSystem(system UnitSystem) *Ingredient {
 		return &Ingredient{
 			Name:           i.Name,
 			Quantity:       i.Quantity,
+			Fixed:          i.Fixed,
+			Optional:       i.Optional,
+			Reference:      i.Reference,
+			Approximate:    i.Approximate,
 			Unit:           i.Unit,
 			TypedUnit:      i.TypedUnit,
 			Subinstruction: i.Subinstruction,
+			Annotation:     i.Annotation,
+			Position:       i.Position,
 			NextComponent:  i.NextComponent,
 		}
 	}
@@ -1584,7 +1785,7 @@ func (i *Ingredient) ConvertToSystem(system UnitSystem) *Ingredient {
 	if converted, err := i.ConvertTo(canonicalUnit); err == nil {
 		// Check if we should use a more appropriate unit based on quantity
 		if alternatives, hasAlternatives := commonUnitMappings[system][unitType]; hasAlternatives {
-			bestUnit := i.getBestUnit(converted.Quantity, canonicalUnit, alternatives)
+			bestUnit := i.getBestUnit(converted.Quantity.Float(), canonicalUnit, alternatives)
 			if bestUnit != canonicalUnit {
 				if finalConverted, err := converted.ConvertTo(bestUnit); err == nil {
 					return finalConverted
@@ -1598,33 +1799,58 @@ func (i *Ingredient) ConvertToSystem(system UnitSystem) *Ingredient {
 	return &Ingredient{
 		Name:           i.Name,
 		Quantity:       i.Quantity,
+		Fixed:          i.Fixed,
+		Optional:       i.Optional,
+		Reference:      i.Reference,
+		Approximate:    i.Approximate,
 		Unit:           i.Unit,
 		TypedUnit:      i.TypedUnit,
 		Subinstruction: i.Subinstruction,
+		Annotation:     i.Annotation,
+		Position:       i.Position,
 		NextComponent:  i.NextComponent,
 	}
 }
 
-// getBestUnit selects the most appropriate unit based on quantity
-func (i *Ingredient) getBestUnit(quantity float32, defaultUnit string, alternatives map[string]string) string {
+// getBestUnit selects the most appropriate unit based on quantity, which is given in defaultUnit.
+// Volumes are compared in millilitres and masses in grams, so the thresholds apply to every system.
+func (i *Ingredient) getBestUnit(quantity float64, defaultUnit string, alternatives map[string]string) string {
 	unitType := i.GetUnitType()
 
 	switch unitType {
 	case "volume":
+		ml := quantity
+		if converted, err := convertCookingUnit(ml, defaultUnit, "ml"); err == nil {
+			ml = converted
+		}
 		switch {
-		case quantity >= 946.4 && alternatives["large"] != "": // ~1 quart
+		case ml >= 3785.4 && alternatives["huge"] != "": // ~1 gallon
+			return alternatives["huge"]
+		case ml >= 946.4 && alternatives["large"] != "": // ~1 quart
 			return alternatives["large"]
-		case quantity >= 236.6 && alternatives["medium"] != "": // ~1 cup
+		case ml >= 236.6 && alternatives["medium"] != "": // ~1 cup
 			return alternatives["medium"]
-		case quantity >= 14.8 && alternatives["small"] != "": // ~1 tbsp
+		case ml >= 14.8 && alternatives["small"] != "": // ~1 tbsp
 			return alternatives["small"]
-		case quantity < 14.8 && alternatives["tiny"] != "":
+		case ml < 14.8 && alternatives["tiny"] != "":
 			return alternatives["tiny"]
+		case alternatives["small"] != "":
+			return alternatives["small"]
 		}
 	case "mass":
+		grams := quantity
+		if converted, err := convertCookingUnit(grams, defaultUnit, "g"); err == nil {
+			grams = converted
+		}
 		switch {
-		case quantity >= 1000 && alternatives["large"] != "": // 1kg or more
+		case grams >= 1000000 && alternatives["huge"] != "": // 1 tonne or more
+			return alternatives["huge"]
+		case grams >= 1000 && alternatives["large"] != "": // 1kg or more
 			return alternatives["large"]
+		case grams > 0 && grams < 0.001 && alternatives["micro"] != "": // less than 1mg
+			return alternatives["micro"]
+		case grams > 0 && grams < 1 && alternatives["tiny"] != "": // less than 1g
+			return alternatives["tiny"]
 		case alternatives["small"] != "":
 			return alternatives["small"]
 		}
@@ -1667,14 +1893,9 @@ func (il *IngredientList) ConvertToSystemWithConsolidation(system UnitSystem) (*
 //	ingredients := cocktail.GetIngredients()
 //	usBar := ingredients.ConvertToSystemBartender(cooklang.UnitSystemUS)
 func (il *IngredientList) ConvertToSystemBartender(system UnitSystem) *IngredientList {
-	result := NewIngredientList()
-
-	for _, ingredient := range il.Ingredients {
-		converted := ingredient.ConvertToSystemBartender(system)
-		result.Add(converted)
-	}
-
-	return result
+	return il.mapIngredients(func(ingredient *Ingredient) *Ingredient {
+		return ingredient.ConvertToSystemBartender(system)
+	})
 }
 
 // ConvertToSystemBartender converts an ingredient using bartender-friendly conversions.
@@ -1700,10 +1921,11 @@ func (il *IngredientList) ConvertToSystemBartender(system UnitSystem) *Ingredien
 //	fmt.Printf("%v %s\n", usVodka.Quantity, usVodka.Unit) // "1.5 oz"
 func (i *Ingredient) ConvertToSystemBartender(system UnitSystem) *Ingredient {
 	// Skip conversion for ingredients without quantities
-	if i.Quantity == -1 {
+	if i.Quantity.IsSome() {
 		return &Ingredient{
 			Name:           i.Name,
 			Quantity:       i.Quantity,
+			Approximate:    i.Approximate,
 			Unit:           i.Unit,
 			TypedUnit:      i.TypedUnit,
 			Subinstruction: i.Subinstruction,
@@ -1720,6 +1942,7 @@ func (i *Ingredient) ConvertToSystemBartender(system UnitSystem) *Ingredient {
 		return &Ingredient{
 			Name:           i.Name,
 			Quantity:       i.Quantity,
+			Approximate:    i.Approximate,
 			Unit:           i.Unit,
 			TypedUnit:      i.TypedUnit,
 			Subinstruction: i.Subinstruction,
@@ -1731,7 +1954,7 @@ func (i *Ingredient) ConvertToSystemBartender(system UnitSystem) *Ingredient {
 	// Calculate ml value for smart unit selection decisions
 	var mlValue float64
 	if unitInfo != nil && unitInfo.MlValue > 0 {
-		mlValue = float64(i.Quantity) * unitInfo.MlValue
+		mlValue = i.Quantity.Min() * unitInfo.MlValue
 	}
 
 	// IMPORTANT: For very small amounts (≤3ml), always convert to dashes
@@ -1741,7 +1964,8 @@ func (i *Ingredient) ConvertToSystemBartender(system UnitSystem) *Ingredient {
 		result := SelectBestUnit(mlValue, system)
 		return &Ingredient{
 			Name:           i.Name,
-			Quantity:       float32(result.Value),
+			Quantity:       i.Quantity.rescaledTo(result.Value),
+			Approximate:    i.Approximate,
 			Unit:           result.Unit,
 			TypedUnit:      nil,
 			Subinstruction: i.Subinstruction,
@@ -1756,6 +1980,7 @@ func (i *Ingredient) ConvertToSystemBartender(system UnitSystem) *Ingredient {
 		return &Ingredient{
 			Name:           i.Name,
 			Quantity:       i.Quantity,
+			Approximate:    i.Approximate,
 			Unit:           i.Unit,
 			TypedUnit:      i.TypedUnit,
 			Subinstruction: i.Subinstruction,
@@ -1766,10 +1991,11 @@ func (i *Ingredient) ConvertToSystemBartender(system UnitSystem) *Ingredient {
 
 	// Use bartender conversion for volume units
 	if unitInfo != nil && unitInfo.MlValue > 0 {
-		result := ConvertVolumeBartender(float64(i.Quantity), i.Unit, system)
+		result := ConvertVolumeBartender(i.Quantity.Min(), i.Unit, system)
 		return &Ingredient{
 			Name:           i.Name,
-			Quantity:       float32(result.Value),
+			Quantity:       i.Quantity.rescaledTo(result.Value),
+			Approximate:    i.Approximate,
 			Unit:           result.Unit,
 			TypedUnit:      nil, // Clear typed unit since we're using bartender conversion
 			Subinstruction: i.Subinstruction,
@@ -1794,15 +2020,21 @@ func (i *Ingredient) ConvertToSystemBartender(system UnitSystem) *Ingredient {
 //	vodka := cooklang.NewIngredient("vodka", 1.5, "oz")
 //	fmt.Println(vodka.FormatQuantityBartender()) // "1 1/2 oz"
 func (i *Ingredient) FormatQuantityBartender() string {
-	if i.Quantity == -1 {
+	if i.Quantity.IsSome() {
 		return "some"
 	}
-	if i.Quantity == 0 {
+	if i.Quantity.IsZero() {
 		return ""
 	}
 
+	if i.Quantity.IsRange() {
+		// Ranges pluralize by their upper bound, e.g. "1-2 dashes"
+		upper := FormatBartenderValue(SmartUnitResult{Value: i.Quantity.Max(), Unit: i.Unit})
+		return FormatAsFractionDefault(i.Quantity.Min()) + "-" + upper
+	}
+
 	result := SmartUnitResult{
-		Value: float64(i.Quantity),
+		Value: i.Quantity.Min(),
 		Unit:  i.Unit,
 	}
 	return FormatBartenderValue(result)
@@ -1826,8 +2058,31 @@ func (i *Ingredient) FormatQuantityBartender() string {
 //	    fmt.Printf("%s: %s\n", name, qty)
 //	}
 func (r *Recipe) GetShoppingListInSystem(system UnitSystem) (map[string]string, error) {
+	return r.GetShoppingListInSystemWithProfile(system, ProfilePrecise)
+}
+
+// GetShoppingListInSystemWithProfile returns a shopping list like GetShoppingListInSystem,
+// converting with a conversion profile. ProfileAuto uses ProfileForRecipe.
+//
+// Parameters:
+//   - system: The target unit system (UnitSystemMetric, UnitSystemUS, UnitSystemImperial)
+//   - profile: How to round amounts and pick units
+//
+// Returns:
+//   - map[string]string: A map of ingredient names to formatted quantities
+//   - error: Any error encountered during conversion
+//
+// Example:
+//
+//	negroni, _ := cooklang.ParseFile("Negroni.cook")
+//	list, _ := negroni.GetShoppingListInSystemWithProfile(cooklang.UnitSystemUS, cooklang.ProfileAuto)
+//	// gin: 1 oz, Angostura: 2 dash
+func (r *Recipe) GetShoppingListInSystemWithProfile(system UnitSystem, profile ConversionProfile) (map[string]string, error) {
+	if profile == ProfileAuto {
+		profile = ProfileForRecipe(r)
+	}
 	ingredients := r.GetIngredients()
-	converted := ingredients.ConvertToSystem(system)
+	converted := ingredients.ConvertToSystemWithProfile(system, profile)
 	consolidated, err := converted.ConsolidateByName("")
 	if err != nil {
 		return nil, err
@@ -1945,8 +2200,12 @@ func (r *Recipe) GetCollectedIngredientsMap() (map[string]string, error) {
 // ShoppingList represents a consolidated list of ingredients from multiple recipes.
 // It combines ingredients across recipes and provides a unified shopping list with recipe attribution.
 type ShoppingList struct {
-	Ingredients *IngredientList `json:"ingredients"`       // Consolidated ingredient list
-	Recipes     []string        `json:"recipes,omitempty"` // List of recipe titles included
+	Ingredients *IngredientList      `json:"ingredients"`       // Consolidated ingredient list
+	Recipes     []string             `json:"recipes,omitempty"` // List of recipe titles included
+	Aisles      *AisleConfig         `json:"-"`                 // Aisle assignments for SortedItems; nil uses IngredientAisle
+	Report      *ConsolidationReport `json:"-"`                 // What consolidation added up and kept apart; nil for lists not made by consolidation
+
+	sources map[string]*shoppingSource // Recipes and notes per ingredient name, for Items
 }
 
 // CreateShoppingList creates a consolidated shopping list from multiple recipes.
@@ -1992,14 +2251,16 @@ func CreateShoppingList(recipes ...*Recipe) (*ShoppingList, error) {
 
 	// Create a combined ingredient list and consolidate
 	combinedList := &IngredientList{Ingredients: allIngredients}
-	consolidated, err := combinedList.ConsolidateByName("")
+	consolidated, report, err := combinedList.ConsolidateByNameWithReport("")
 	if err != nil {
 		return nil, err
 	}
 
 	return &ShoppingList{
 		Ingredients: consolidated,
+		Report:      report,
 		Recipes:     recipeNames,
+		sources:     shoppingSources(recipes),
 	}, nil
 }
 
@@ -2042,14 +2303,16 @@ func CreateShoppingListWithUnit(targetUnit string, recipes ...*Recipe) (*Shoppin
 
 	// Create a combined ingredient list and consolidate with unit conversion
 	combinedList := &IngredientList{Ingredients: allIngredients}
-	consolidated, err := combinedList.ConsolidateByName(targetUnit)
+	consolidated, report, err := combinedList.ConsolidateByNameWithReport(targetUnit)
 	if err != nil {
 		return nil, err
 	}
 
 	return &ShoppingList{
 		Ingredients: consolidated,
+		Report:      report,
 		Recipes:     recipeNames,
+		sources:     shoppingSources(recipes),
 	}, nil
 }
 
@@ -2106,14 +2369,16 @@ func CreateShoppingListForServings(targetServings float64, recipes ...*Recipe) (
 
 	// Create a combined ingredient list and consolidate
 	combinedList := &IngredientList{Ingredients: allIngredients}
-	consolidated, err := combinedList.ConsolidateByName("")
+	consolidated, report, err := combinedList.ConsolidateByNameWithReport("")
 	if err != nil {
 		return nil, err
 	}
 
 	return &ShoppingList{
 		Ingredients: consolidated,
+		Report:      report,
 		Recipes:     recipeNames,
+		sources:     shoppingSources(recipes),
 	}, nil
 }
 
@@ -2163,19 +2428,22 @@ func CreateShoppingListForServingsWithUnit(targetServings float64, targetUnit st
 
 	// Create a combined ingredient list and consolidate with unit conversion
 	combinedList := &IngredientList{Ingredients: allIngredients}
-	consolidated, err := combinedList.ConsolidateByName(targetUnit)
+	consolidated, report, err := combinedList.ConsolidateByNameWithReport(targetUnit)
 	if err != nil {
 		return nil, err
 	}
 
 	return &ShoppingList{
 		Ingredients: consolidated,
+		Report:      report,
 		Recipes:     recipeNames,
+		sources:     shoppingSources(recipes),
 	}, nil
 }
 
 // ToMap returns the shopping list as a map of ingredient names to formatted quantities.
-// This is a convenience method that delegates to IngredientList.ToMap().
+// This is a convenience method that delegates to IngredientList.ToMap(). Map order is
+// random; use Items or SortedItems for a stable order.
 //
 // Returns:
 //   - map[string]string: A map of ingredient names to quantity strings
@@ -2195,7 +2463,7 @@ func (sl *ShoppingList) ToMap() map[string]string {
 
 // Scale scales all ingredients in the shopping list by the given multiplier.
 // This is useful when adjusting recipe servings or batch cooking.
-// Ingredients with "some" quantity (-1) are not scaled.
+// Ingredients with "some" quantities are not scaled.
 //
 // Parameters:
 //   - multiplier: The scaling factor (e.g., 2.0 for double, 0.5 for half)
@@ -2216,12 +2484,13 @@ func (sl *ShoppingList) Scale(multiplier float64) *ShoppingList {
 	scaledIngredients := make([]*Ingredient, len(sl.Ingredients.Ingredients))
 	for i, ingredient := range sl.Ingredients.Ingredients {
 		scaledIngredient := &Ingredient{
-			Name:     ingredient.Name,
-			Quantity: ingredient.Quantity,
-			Unit:     ingredient.Unit,
+			Name:        ingredient.Name,
+			Quantity:    ingredient.Quantity,
+			Unit:        ingredient.Unit,
+			Approximate: ingredient.Approximate,
 		}
-		if ingredient.Quantity > 0 {
-			scaledIngredient.Quantity = ingredient.Quantity * float32(multiplier)
+		if ingredient.Quantity.HasAmount() {
+			scaledIngredient.Quantity = ingredient.Quantity.Scale(multiplier)
 		}
 		scaledIngredients[i] = scaledIngredient
 	}
@@ -2229,6 +2498,9 @@ func (sl *ShoppingList) Scale(multiplier float64) *ShoppingList {
 	return &ShoppingList{
 		Ingredients: &IngredientList{Ingredients: scaledIngredients},
 		Recipes:     sl.Recipes,
+		Aisles:      sl.Aisles,
+		Report:      sl.Report,
+		sources:     sl.sources,
 	}
 }
 
@@ -2248,12 +2520,22 @@ func (sl *ShoppingList) Count() int {
 	return len(sl.Ingredients.Ingredients)
 }
 
+// ScaleOptions controls how Recipe.ScaleWithOptions scales non-ingredient components
+// and rounds counted ingredients. Ingredient quantities are always scaled (except
+// fixed and "some" quantities).
+type ScaleOptions struct {
+	ScaleTimers   bool          // Scale numeric timer durations proportionally
+	ScaleCookware bool          // Scale cookware counts proportionally, rounding up to whole items
+	CountRounding CountRounding // How to round counted ingredients such as eggs (see Ingredient.IsCount)
+}
+
 // Scale creates a new recipe with all ingredient quantities scaled by the given factor.
 // This is useful for adjusting recipe servings or batch cooking.
 // Timers, cookware, and instructions are copied unchanged.
-// Ingredients with "some" quantity (-1) are not scaled.
+// Ingredients with "some" quantities are not scaled.
 //
-// The servings metadata is also updated if present.
+// The servings and yield metadata are also updated if present.
+// Scale is equivalent to ScaleWithOptions with zero-value options.
 //
 // Parameters:
 //   - factor: The scaling factor (e.g., 2.0 for double, 0.5 for half)
@@ -2267,138 +2549,164 @@ func (sl *ShoppingList) Count() int {
 //	doubled := recipe.Scale(2.0)  // Double all quantities
 //	halved := recipe.Scale(0.5)   // Half all quantities
 func (r *Recipe) Scale(factor float64) *Recipe {
-	// Create a copy of the recipe
-	scaledRecipe := &Recipe{
-		Title:       r.Title,
-		Cuisine:     r.Cuisine,
-		Date:        r.Date,
-		Description: r.Description,
-		Difficulty:  r.Difficulty,
-		PrepTime:    r.PrepTime,
-		TotalTime:   r.TotalTime,
-		Author:      r.Author,
-		Images:      r.Images,
-		Tags:        r.Tags,
-		Metadata:    make(map[string]string),
-	}
-
-	// Copy metadata
-	for k, v := range r.Metadata {
-		scaledRecipe.Metadata[k] = v
-	}
+	return r.ScaleWithOptions(factor, ScaleOptions{})
+}
+
+// ScaleWithOptions creates a deep copy of the recipe (see Clone) with quantities scaled by the
+// given factor, so the original recipe is never modified.
+// Timers and cookware are only scaled when requested through opts.
+//
+// Parameters:
+//   - factor: The scaling factor (e.g., 2.0 for double, 0.5 for half)
+//   - opts: Which non-ingredient components should also be scaled
+//
+// Returns:
+//   - *Recipe: A new recipe with scaled quantities
+//
+// Example:
+//
+//	recipe, _ := cooklang.ParseFile("stock.cook")
+//	big := recipe.ScaleWithOptions(3, cooklang.ScaleOptions{ScaleCookware: true})
+//	half := recipe.ScaleWithOptions(0.5, cooklang.ScaleOptions{CountRounding: cooklang.CountRoundUp}) // 3 eggs → 2
+func (r *Recipe) ScaleWithOptions(factor float64, opts ScaleOptions) *Recipe {
+	scaledRecipe := r.Clone()
 
 	// Update servings if present
 	if r.Servings > 0 {
+		if scaledRecipe.OriginalServings == 0 {
+			scaledRecipe.OriginalServings = r.Servings
+		}
 		scaledRecipe.Servings = r.Servings * float32(factor)
 		scaledRecipe.Metadata["servings"] = strconv.FormatFloat(float64(scaledRecipe.Servings), 'f', -1, 32)
 	}
+	scaledRecipe.scaleYield(r.yield(), factor)
 
-	// Scale the steps and ingredients
-	var lastStep *Step
-	for step := r.FirstStep; step != nil; step = step.NextStep {
-		newStep := &Step{}
-
-		// Copy and scale components
-		var lastComponent StepComponent
+	for step := scaledRecipe.FirstStep; step != nil; step = step.NextStep {
 		for component := step.FirstComponent; component != nil; component = component.GetNext() {
-			var newComponent StepComponent
-
 			switch comp := component.(type) {
 			case *Ingredient:
-				// Scale the ingredient (unless it's fixed or "some")
-				newQty := comp.Quantity
-				if newQty > 0 && !comp.Fixed { // Don't scale "some" (-1), zero, or fixed quantities
-					newQty = comp.Quantity * float32(factor)
-				}
-				newComponent = &Ingredient{
-					Name:           comp.Name,
-					Quantity:       newQty,
-					Unit:           comp.Unit,
-					Fixed:          comp.Fixed,
-					TypedUnit:      comp.TypedUnit,
-					Subinstruction: comp.Subinstruction,
-					Annotation:     comp.Annotation,
+				// Don't scale "some", zero, or fixed quantities
+				if comp.Quantity.HasAmount() && !comp.Fixed {
+					if comp.OriginalQuantity.IsZero() {
+						comp.OriginalQuantity = comp.Quantity
+					}
+					comp.Quantity = comp.Quantity.Scale(factor)
+					if comp.IsCount() {
+						comp.Quantity = comp.Quantity.RoundCount(opts.CountRounding)
+					}
 				}
-
 			case *Timer:
-				// Copy timer unchanged
-				newComponent = &Timer{
-					Name:       comp.Name,
-					Duration:   comp.Duration,
-					Unit:       comp.Unit,
-					Text:       comp.Text,
-					Annotation: comp.Annotation,
+				if opts.ScaleTimers {
+					comp.Duration = scaleDuration(comp.Duration, factor)
 				}
-
 			case *Cookware:
-				// Copy cookware unchanged
-				newComponent = &Cookware{
-					Name:       comp.Name,
-					Quantity:   comp.Quantity,
-					Annotation: comp.Annotation,
-				}
-
-			case *Instruction:
-				// Copy instruction unchanged
-				newComponent = &Instruction{
-					Text: comp.Text,
-				}
-
-			case *Section:
-				// Copy section unchanged
-				newComponent = &Section{
-					Name: comp.Name,
-				}
-
-			case *Comment:
-				// Copy comment unchanged
-				newComponent = &Comment{
-					Text:    comp.Text,
-					IsBlock: comp.IsBlock,
-				}
-
-			case *Note:
-				// Copy note unchanged
-				newComponent = &Note{
-					Text: comp.Text,
-				}
-
-			case *RecipeReference:
-				// Copy recipe reference unchanged (scaling is handled externally via resolver)
-				newComponent = &RecipeReference{
-					Path:     comp.Path,
-					Quantity: comp.Quantity,
-					Unit:     comp.Unit,
+				// Word quantities are only scaled if they are numbers ("two")
+				if _, isNumber := cookwareNumbers[strings.ToLower(comp.QuantityText)]; opts.ScaleCookware && comp.Quantity > 0 && (comp.QuantityText == "" || isNumber) {
+					scaled := int(math.Ceil(float64(comp.Quantity) * factor))
+					if scaled != comp.Quantity {
+						comp.Quantity, comp.QuantityText = scaled, ""
+					}
 				}
 			}
-
-			// Link components (skip nil - unhandled types)
-			if newComponent == nil {
-				continue
-			}
-			if lastComponent == nil {
-				newStep.FirstComponent = newComponent
-			} else {
-				lastComponent.SetNext(newComponent)
-			}
-			lastComponent = newComponent
 		}
+	}
 
-		// Only add steps that have displayable content
-		if !newStep.HasDisplayableContent() {
-			continue
-		}
+	return scaledRecipe
+}
 
-		// Link steps
-		if scaledRecipe.FirstStep == nil {
-			scaledRecipe.FirstStep = newStep
-		} else {
-			lastStep.NextStep = newStep
+// copyRecipeFields returns a copy of the recipe's top-level fields without any steps.
+// Slices and the metadata map are copied so the result can be modified independently.
+// A renderer set with SetRenderer or SetRendererFunc renders the copy; a RenderFunc
+// assigned directly is bound to the original recipe and is not copied.
+func (r *Recipe) copyRecipeFields() *Recipe {
+	recipe := &Recipe{
+		Title:         r.Title,
+		TitleInferred: r.TitleInferred,
+		Cuisine:       r.Cuisine,
+		Date:          r.Date,
+		Description:   r.Description,
+		Difficulty:    r.Difficulty,
+		PrepTime:      r.PrepTime,
+		TotalTime:     r.TotalTime,
+		Author:        r.Author,
+		Servings:      r.Servings,
+		Yield:         r.Yield,
+		Metadata:      make(Metadata, len(r.Metadata)),
+
+		OriginalServings: r.OriginalServings,
+	}
+	if r.renderer != nil {
+		recipe.SetRendererFunc(r.renderer)
+	}
+	if r.Images != nil {
+		recipe.Images = append([]string(nil), r.Images...)
+	}
+	if r.Tags != nil {
+		recipe.Tags = append([]string(nil), r.Tags...)
+	}
+	for k, v := range r.Metadata {
+		recipe.Metadata[k] = v
+	}
+	if r.CustomUnits != nil {
+		recipe.CustomUnits = CustomUnits{}
+		for name, unit := range r.CustomUnits {
+			recipe.CustomUnits[name] = unit
 		}
-		lastStep = newStep
 	}
+	return recipe
+}
 
-	return scaledRecipe
+// copyComponent returns an unlinked copy of a step component with all fields preserved.
+// Returns nil for component types it does not know about.
+func copyComponent(component StepComponent) StepComponent {
+	switch comp := component.(type) {
+	case *Ingredient:
+		c := *comp
+		c.NextComponent = nil
+		if comp.TypedUnit != nil {
+			typedUnit := *comp.TypedUnit
+			c.TypedUnit = &typedUnit
+		}
+		return &c
+	case *Timer:
+		c := *comp
+		c.NextComponent = nil
+		return &c
+	case *Cookware:
+		c := *comp
+		c.NextComponent = nil
+		return &c
+	case *Instruction:
+		c := *comp
+		c.NextComponent = nil
+		return &c
+	case *Section:
+		c := *comp
+		c.NextComponent = nil
+		return &c
+	case *Comment:
+		c := *comp
+		c.NextComponent = nil
+		return &c
+	case *Note:
+		c := *comp
+		c.NextComponent = nil
+		return &c
+	case *Temperature:
+		c := *comp
+		c.NextComponent = nil
+		return &c
+	case *RecipeReference:
+		// Scaling of referenced recipes is handled externally via the resolver
+		c := *comp
+		c.NextComponent = nil
+		return &c
+	case *Extension:
+		c := *comp
+		c.NextComponent = nil
+		return &c
+	}
+	return nil
 }
 
 // ScaleToServings creates a new recipe scaled to the target number of servings.
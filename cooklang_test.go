@@ -1,6 +1,9 @@
 package cooklang
 
 import (
+	"encoding/json"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/hilli/cooklang/parser"
@@ -99,6 +102,35 @@ Mix ingredients in #bowl{2}.
 	}
 }
 
+func TestCookwareQuantityText(t *testing.T) {
+	parsed, err := ParseString("Heat #pan{two}(preheated), #pot{a pair of} and #bowl{3}.")
+	if err != nil {
+		t.Fatalf("Failed to parse recipe: %v", err)
+	}
+	tests := []struct {
+		quantity int
+		text     string
+		label    string
+		render   string
+	}{
+		{2, "two", "two", "#pan{two}(preheated)"},
+		{1, "a pair of", "a pair of", "#pot{a pair of}"},
+		{3, "", "x3", "#bowl{3}"},
+	}
+	cookware := parsed.GetCookware()
+	for i, tt := range tests {
+		cw := cookware[i]
+		if cw.Quantity != tt.quantity || cw.QuantityText != tt.text || cw.QuantityLabel() != tt.label || cw.Render() != tt.render {
+			t.Errorf("%s: got quantity %d, text %q, label %q, render %q", cw.Name, cw.Quantity, cw.QuantityText, cw.QuantityLabel(), cw.Render())
+		}
+	}
+
+	scaled := parsed.ScaleWithOptions(2, ScaleOptions{ScaleCookware: true}).GetCookware()
+	if scaled[0].Render() != "#pan{4}(preheated)" || scaled[1].Render() != "#pot{a pair of}" {
+		t.Errorf("expected number words to scale and other text to be kept, got %s and %s", scaled[0].Render(), scaled[1].Render())
+	}
+}
+
 func TestGetCookwareEmpty(t *testing.T) {
 	recipe := `---
 title: Test Recipe
@@ -116,3 +148,117 @@ Mix @flour{200%g} with @water{100%ml}.
 		t.Errorf("Expected 0 cookware items, got %d", len(cookware))
 	}
 }
+
+func TestStepIsNote(t *testing.T) {
+	recipe, err := ParseString("> Use ripe bananas.\n\nMash the @bananas{3}.")
+	if err != nil {
+		t.Fatalf("Failed to parse recipe: %v", err)
+	}
+
+	if !recipe.FirstStep.IsNote() {
+		t.Error("Expected the first step to be a note")
+	}
+	if recipe.FirstStep.NextStep == nil || recipe.FirstStep.NextStep.IsNote() {
+		t.Error("Expected the second step to be a cooking step")
+	}
+}
+
+func TestExtensionComponent(t *testing.T) {
+	err := parser.RegisterExtension('&', func(name, content string) (parser.Component, error) {
+		return parser.Component{Type: "pairing", Name: name, Value: content}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { parser.UnregisterExtension('&') })
+
+	source := "Serve with &wine pairing{Riesling}."
+	recipe, err := ParseString(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	extension, ok := recipe.FirstStep.FirstComponent.GetNext().(*Extension)
+	if !ok {
+		t.Fatalf("expected an extension component, got %#v", recipe.FirstStep.FirstComponent.GetNext())
+	}
+	if extension.Kind != "pairing" || extension.Name != "wine pairing" || extension.Value != "Riesling" {
+		t.Errorf("unexpected extension: %+v", extension)
+	}
+	if extension.Position.Column != 12 {
+		t.Errorf("expected the extension at column 12, got %v", extension.Position)
+	}
+	if got := recipe.Render(); !strings.HasSuffix(got, "\n"+source) {
+		t.Errorf("expected Render to keep the custom component, got %q", got)
+	}
+	if !recipe.Clone().Equal(recipe) {
+		t.Error("expected the clone to equal the recipe")
+	}
+
+	data, err := json.Marshal(recipe)
+	if err != nil {
+		t.Fatal(err)
+	}
+	restored, err := FromJSON(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !restored.Equal(recipe) {
+		t.Errorf("expected the recipe to survive JSON, got %s", data)
+	}
+}
+
+func TestIngredientReference(t *testing.T) {
+	recipe, err := ParseString("Whisk @flour{500%g} with @sugar{100%g}.\n\nFold in the reserved @&flour{} and @&Sugar{50%g}.\n")
+	if err != nil {
+		t.Fatalf("ParseString failed: %v", err)
+	}
+
+	references := func(recipe *Recipe) []string {
+		var rendered []string
+		recipe.WalkIngredients(func(ingredient *Ingredient) bool {
+			if ingredient.IsReference() {
+				rendered = append(rendered, ingredient.Render())
+			}
+			return true
+		})
+		return rendered
+	}
+	if got, want := references(recipe), []string{"@&flour{}", "@&Sugar{50%g}"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("references = %v, want %v", got, want)
+	}
+
+	ingredients := recipe.GetIngredients().Ingredients
+	if len(ingredients) != 2 || ingredients[0].Name != "flour" || ingredients[1].Name != "sugar" {
+		t.Errorf("GetIngredients() = %v, want flour and sugar once", ingredients)
+	}
+	list, err := CreateShoppingList(recipe, recipe.Scale(2))
+	if err != nil {
+		t.Fatalf("CreateShoppingList failed: %v", err)
+	}
+	if got := list.ToMap(); got["flour"] != "1500 g" || got["sugar"] != "300 g" || len(got) != 2 {
+		t.Errorf("shopping list = %v, want 1500 g flour and 300 g sugar", got)
+	}
+
+	// Lists built by hand leave references out when consolidating too
+	mixed := &IngredientList{Ingredients: []*Ingredient{
+		NewIngredient("flour", 200, "g"),
+		{Name: "flour", Quantity: NewQuantity(100, 1), Unit: "g", Reference: true},
+	}}
+	consolidated, _ := mixed.ConsolidateByName("")
+	if got := consolidated.ToMap()["flour"]; got != "200 g" {
+		t.Errorf("consolidated flour = %s, want 200 g", got)
+	}
+
+	// References survive cloning, scaling and JSON
+	data, err := json.Marshal(recipe.Scale(2))
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	decoded, err := FromJSON(data)
+	if err != nil {
+		t.Fatalf("FromJSON failed: %v", err)
+	}
+	if got, want := references(decoded), []string{"@&flour{}", "@&Sugar{100%g}"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("decoded references = %v, want %v", got, want)
+	}
+}
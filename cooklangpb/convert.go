@@ -0,0 +1,279 @@
+// Package cooklangpb provides protocol buffer types for Cooklang recipes, converters
+// to and from cooklang.Recipe, and a gRPC service that parses, renders and builds
+// shopping lists for recipes.
+//
+// The types are generated from cooklang.proto, which other languages can use to
+// talk to the service or to read recipes written by Go programs:
+//
+//	msg := cooklangpb.FromRecipe(recipe)
+//	data, _ := proto.Marshal(msg)
+//
+//	// Later, or in another process
+//	var decoded cooklangpb.Recipe
+//	_ = proto.Unmarshal(data, &decoded)
+//	recipe, err := cooklangpb.ToRecipe(&decoded)
+//
+// The service is served by NewServer and hosted by `cook serve --grpc`:
+//
+//	server := grpc.NewServer()
+//	cooklangpb.RegisterCooklangServiceServer(server, cooklangpb.NewServer())
+package cooklangpb
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/hilli/cooklang"
+)
+
+// dateLayout is the format of Recipe.date.
+const dateLayout = "2006-01-02"
+
+// FromRecipe converts a recipe to its protocol buffer form.
+//
+// Parameters:
+//   - recipe: The recipe to convert
+//
+// Returns:
+//   - *Recipe: The protocol buffer recipe, or nil if recipe is nil
+//
+// Example:
+//
+//	recipe, _ := cooklang.ParseFile("Pancakes.cook")
+//	data, err := proto.Marshal(cooklangpb.FromRecipe(recipe))
+func FromRecipe(recipe *cooklang.Recipe) *Recipe {
+	if recipe == nil {
+		return nil
+	}
+	msg := &Recipe{
+		Title:         recipe.Title,
+		TitleInferred: recipe.TitleInferred,
+		Cuisine:       recipe.Cuisine,
+		Description:   recipe.Description,
+		Difficulty:    recipe.Difficulty,
+		PrepTime:      recipe.PrepTime,
+		TotalTime:     recipe.TotalTime,
+		Author:        recipe.Author,
+		Servings:      recipe.Servings,
+		Images:        slices.Clone(recipe.Images),
+		Tags:          slices.Clone(recipe.Tags),
+		Metadata:      maps.Clone(recipe.Metadata),
+	}
+	if !recipe.Date.IsZero() {
+		msg.Date = recipe.Date.Format(dateLayout)
+	}
+	if len(recipe.CustomUnits) > 0 {
+		msg.CustomUnits = make(map[string]*CustomUnit, len(recipe.CustomUnits))
+		for name, unit := range recipe.CustomUnits {
+			msg.CustomUnits[name] = &CustomUnit{Amount: unit.Amount, Unit: unit.Unit}
+		}
+	}
+
+	for step := range recipe.Steps() {
+		s := &Step{Images: slices.Clone(step.Images), Position: fromPosition(step.Position)}
+		for component := range step.Components() {
+			if c := fromComponent(component); c != nil {
+				s.Components = append(s.Components, c)
+			}
+		}
+		msg.Steps = append(msg.Steps, s)
+	}
+	return msg
+}
+
+// ToRecipe converts a protocol buffer recipe back to a cooklang.Recipe.
+//
+// Parameters:
+//   - msg: The protocol buffer recipe
+//
+// Returns:
+//   - *cooklang.Recipe: The recipe
+//   - error: An error if the date is not YYYY-MM-DD or a component is empty
+//
+// Example:
+//
+//	var msg cooklangpb.Recipe
+//	if err := proto.Unmarshal(data, &msg); err != nil {
+//	    log.Fatal(err)
+//	}
+//	recipe, err := cooklangpb.ToRecipe(&msg)
+func ToRecipe(msg *Recipe) (*cooklang.Recipe, error) {
+	if msg == nil {
+		return nil, fmt.Errorf("no recipe")
+	}
+	recipe := &cooklang.Recipe{
+		Title:         msg.GetTitle(),
+		TitleInferred: msg.GetTitleInferred(),
+		Cuisine:       msg.GetCuisine(),
+		Description:   msg.GetDescription(),
+		Difficulty:    msg.GetDifficulty(),
+		PrepTime:      msg.GetPrepTime(),
+		TotalTime:     msg.GetTotalTime(),
+		Author:        msg.GetAuthor(),
+		Servings:      msg.GetServings(),
+		Images:        slices.Clone(msg.GetImages()),
+		Tags:          slices.Clone(msg.GetTags()),
+		Metadata:      cooklang.Metadata{},
+	}
+	maps.Copy(recipe.Metadata, msg.GetMetadata())
+	if msg.GetDate() != "" {
+		date, err := time.Parse(dateLayout, msg.GetDate())
+		if err != nil {
+			return nil, fmt.Errorf("invalid date %q: %w", msg.GetDate(), err)
+		}
+		recipe.Date = date
+	}
+	if len(msg.GetCustomUnits()) > 0 {
+		recipe.CustomUnits = cooklang.CustomUnits{}
+		for name, unit := range msg.GetCustomUnits() {
+			recipe.CustomUnits[name] = cooklang.CustomUnit{Amount: unit.GetAmount(), Unit: unit.GetUnit()}
+		}
+	}
+
+	var lastStep *cooklang.Step
+	for i, s := range msg.GetSteps() {
+		step := &cooklang.Step{Images: slices.Clone(s.GetImages()), Position: toPosition(s.GetPosition())}
+		var lastComponent cooklang.StepComponent
+		for j, c := range s.GetComponents() {
+			component := toComponent(c)
+			if component == nil {
+				return nil, fmt.Errorf("step %d, component %d: no component kind set", i+1, j+1)
+			}
+			if lastComponent == nil {
+				step.FirstComponent = component
+			} else {
+				lastComponent.SetNext(component)
+			}
+			lastComponent = component
+		}
+		if lastStep == nil {
+			recipe.FirstStep = step
+		} else {
+			lastStep.NextStep = step
+		}
+		lastStep = step
+	}
+
+	// Point the ingredients at the recipe's custom units
+	if len(recipe.CustomUnits) > 0 {
+		recipe.SetCustomUnits(nil)
+	}
+	return recipe, nil
+}
+
+// fromComponent converts a step component, or returns nil for unknown types.
+func fromComponent(component cooklang.StepComponent) *Component {
+	c := &Component{Position: fromPosition(component.GetPosition())}
+	switch comp := component.(type) {
+	case *cooklang.Instruction:
+		c.Kind = &Component_Text{Text: &Text{Text: comp.Text}}
+	case *cooklang.Ingredient:
+		quantity, quantityMax := fromQuantity(comp.Quantity)
+		c.Kind = &Component_Ingredient{Ingredient: &Ingredient{
+			Name:        comp.Name,
+			Quantity:    quantity,
+			QuantityMax: quantityMax,
+			Unit:        comp.Unit,
+			Fixed:       comp.Fixed,
+			Optional:    comp.Optional,
+			Approximate: comp.Approximate,
+			Preparation: comp.Subinstruction,
+			Annotation:  comp.Annotation,
+		}}
+	case *cooklang.Cookware:
+		c.Kind = &Component_Cookware{Cookware: &Cookware{Name: comp.Name, Quantity: int32(comp.Quantity), Annotation: comp.Annotation}}
+	case *cooklang.Timer:
+		c.Kind = &Component_Timer{Timer: &Timer{Name: comp.Name, Duration: comp.Duration, Unit: comp.Unit, Text: comp.Text, Annotation: comp.Annotation}}
+	case *cooklang.Temperature:
+		c.Kind = &Component_Temperature{Temperature: &Temperature{Value: comp.Value, ValueMax: comp.ValueMax, Scale: string(comp.Scale), Text: comp.Text}}
+	case *cooklang.RecipeReference:
+		c.Kind = &Component_RecipeReference{RecipeReference: &RecipeReference{Path: comp.Path, Quantity: comp.Quantity, Unit: comp.Unit}}
+	case *cooklang.Section:
+		c.Kind = &Component_Section{Section: &Section{Name: comp.Name}}
+	case *cooklang.Note:
+		c.Kind = &Component_Note{Note: &Note{Text: comp.Text}}
+	case *cooklang.Comment:
+		c.Kind = &Component_Comment{Comment: &Comment{Text: comp.Text, IsBlock: comp.IsBlock}}
+	default:
+		return nil
+	}
+	return c
+}
+
+// fromQuantity splits an ingredient quantity into the message's quantity (-1 for
+// "some") and quantity_max (0 unless it is a range) fields.
+func fromQuantity(q cooklang.Quantity) (quantity, quantityMax float32) {
+	if q.IsRange() {
+		return float32(q.Min()), float32(q.Max())
+	}
+	return float32(q.Min()), 0
+}
+
+// toQuantity rebuilds an ingredient quantity from the message's quantity fields.
+func toQuantity(quantity, quantityMax float32) cooklang.Quantity {
+	low := cooklang.QuantityFromFloat(float64(quantity))
+	if quantityMax > quantity && quantity >= 0 {
+		return cooklang.NewRangeQuantity(low, cooklang.QuantityFromFloat(float64(quantityMax)))
+	}
+	return low
+}
+
+// toComponent converts a component back, or returns nil if no kind is set.
+func toComponent(c *Component) cooklang.StepComponent {
+	position := toPosition(c.GetPosition())
+	switch kind := c.GetKind().(type) {
+	case *Component_Text:
+		return &cooklang.Instruction{Text: kind.Text.GetText(), Position: position}
+	case *Component_Ingredient:
+		i := kind.Ingredient
+		return &cooklang.Ingredient{
+			Name:           i.GetName(),
+			Quantity:       toQuantity(i.GetQuantity(), i.GetQuantityMax()),
+			Unit:           i.GetUnit(),
+			Fixed:          i.GetFixed(),
+			Optional:       i.GetOptional(),
+			Approximate:    i.GetApproximate(),
+			TypedUnit:      cooklang.CreateTypedUnit(i.GetUnit()),
+			Subinstruction: i.GetPreparation(),
+			Annotation:     i.GetAnnotation(),
+			Position:       position,
+		}
+	case *Component_Cookware:
+		cw := kind.Cookware
+		return &cooklang.Cookware{Name: cw.GetName(), Quantity: int(cw.GetQuantity()), Annotation: cw.GetAnnotation(), Position: position}
+	case *Component_Timer:
+		t := kind.Timer
+		return &cooklang.Timer{Name: t.GetName(), Duration: t.GetDuration(), Unit: t.GetUnit(), Text: t.GetText(), Annotation: t.GetAnnotation(), Position: position}
+	case *Component_Temperature:
+		t := kind.Temperature
+		return &cooklang.Temperature{Value: t.GetValue(), ValueMax: t.GetValueMax(), Scale: cooklang.TemperatureScale(t.GetScale()), Text: t.GetText(), Position: position}
+	case *Component_RecipeReference:
+		ref := kind.RecipeReference
+		return &cooklang.RecipeReference{Path: ref.GetPath(), Quantity: ref.GetQuantity(), Unit: ref.GetUnit(), Position: position}
+	case *Component_Section:
+		return &cooklang.Section{Name: kind.Section.GetName(), Position: position}
+	case *Component_Note:
+		return &cooklang.Note{Text: kind.Note.GetText(), Position: position}
+	case *Component_Comment:
+		return &cooklang.Comment{Text: kind.Comment.GetText(), IsBlock: kind.Comment.GetIsBlock(), Position: position}
+	}
+	return nil
+}
+
+// fromPosition converts a source position, or returns nil if it is unknown.
+func fromPosition(p cooklang.SourcePosition) *SourcePosition {
+	if !p.IsValid() {
+		return nil
+	}
+	return &SourcePosition{Line: int32(p.Line), Column: int32(p.Column), Offset: int32(p.Offset), EndOffset: int32(p.EndOffset)}
+}
+
+// toPosition converts a source position; nil becomes the zero (unknown) position.
+func toPosition(p *SourcePosition) cooklang.SourcePosition {
+	if p == nil {
+		return cooklang.SourcePosition{}
+	}
+	return cooklang.SourcePosition{Line: int(p.GetLine()), Column: int(p.GetColumn()), Offset: int(p.GetOffset()), EndOffset: int(p.GetEndOffset())}
+}
@@ -0,0 +1,99 @@
+package cooklangpb
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/hilli/cooklang"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestRecipeRoundTrip(t *testing.T) {
+	files, err := filepath.Glob("../example_recipes/*.cook")
+	if err != nil || len(files) == 0 {
+		t.Fatalf("no example recipes found: %v", err)
+	}
+
+	for _, file := range files {
+		recipe, err := cooklang.ParseFile(file, cooklang.WithExtendedMode())
+		if err != nil {
+			t.Fatalf("%s: %v", file, err)
+		}
+		data, err := proto.Marshal(FromRecipe(recipe))
+		if err != nil {
+			t.Fatalf("%s: marshal failed: %v", file, err)
+		}
+		var msg Recipe
+		if err := proto.Unmarshal(data, &msg); err != nil {
+			t.Fatalf("%s: unmarshal failed: %v", file, err)
+		}
+		restored, err := ToRecipe(&msg)
+		if err != nil {
+			t.Fatalf("%s: ToRecipe failed: %v", file, err)
+		}
+		if !restored.Equal(recipe) {
+			t.Errorf("%s: round trip changed the recipe\n got: %s\nwant: %s", file, restored.Render(), recipe.Render())
+		}
+	}
+}
+
+func TestRecipeAllComponents(t *testing.T) {
+	recipe, err := cooklang.ParseString("---\ntitle: Stew\ndate: 2024-03-01\nunits: {scoop: 30 g}\n---\n== Base ==\nBrown @beef{500%g}(diced) in a #pot{2}. -- keep stirring\n\n> Use a heavy pot.\n\nAdd @flour{1%scoop} and @./Stock{2%servings}, simmer for ~stew{2%hours} at 160°C.", cooklang.WithExtendedMode())
+	if err != nil {
+		t.Fatal(err)
+	}
+	recipe.FirstStep.Images = []string{"Stew.0.jpg"}
+
+	msg := FromRecipe(recipe)
+	if msg.GetDate() != "2024-03-01" {
+		t.Errorf("expected date 2024-03-01, got %q", msg.GetDate())
+	}
+	if pos := msg.GetSteps()[0].GetComponents()[0].GetPosition(); pos.GetLine() != 6 {
+		t.Errorf("expected the first component on line 6, got %v", pos)
+	}
+
+	restored, err := ToRecipe(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !restored.Equal(recipe) {
+		t.Errorf("round trip changed the recipe\n got: %s\nwant: %s", restored.Render(), recipe.Render())
+	}
+	if restored.FirstStep.FirstComponent.GetPosition() != recipe.FirstStep.FirstComponent.GetPosition() {
+		t.Error("expected positions to be kept")
+	}
+
+	// Custom units apply to the converted ingredients
+	var flour *cooklang.Ingredient
+	restored.WalkIngredients(func(i *cooklang.Ingredient) bool {
+		if i.Name == "flour" {
+			flour = i
+		}
+		return flour == nil
+	})
+	if converted, err := flour.ConvertTo("g"); err != nil || converted.Quantity.Min() != 30 {
+		t.Errorf("expected 30 g of flour, got %v, %v", converted, err)
+	}
+
+	// Changing the message does not change the recipe it came from
+	msg.Tags = append(msg.Tags, "changed")
+	msg.Metadata["title"] = "Changed"
+	if recipe.Metadata["title"] != "Stew" {
+		t.Error("the message shares its metadata with the recipe")
+	}
+}
+
+func TestToRecipeErrors(t *testing.T) {
+	if _, err := ToRecipe(nil); err == nil {
+		t.Error("expected an error for a nil recipe")
+	}
+	if _, err := ToRecipe(&Recipe{Date: "March 1st"}); err == nil {
+		t.Error("expected an error for an invalid date")
+	}
+	if _, err := ToRecipe(&Recipe{Steps: []*Step{{Components: []*Component{{}}}}}); err == nil {
+		t.Error("expected an error for a component without a kind")
+	}
+	if recipe := FromRecipe(nil); recipe != nil {
+		t.Error("expected nil for a nil recipe")
+	}
+}
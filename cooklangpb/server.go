@@ -0,0 +1,168 @@
+package cooklangpb
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/hilli/cooklang"
+	"github.com/hilli/cooklang/parser"
+	"github.com/hilli/cooklang/renderers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxSourceSize is the largest recipe source the server parses.
+const maxSourceSize = 1 << 20
+
+// sourceLimits caps the steps, components and frontmatter nesting of parsed sources.
+var sourceLimits = parser.Limits{MaxSteps: 10000, MaxComponentsPerStep: 5000, MaxNesting: 32}
+
+// Server implements CooklangServiceServer with the cooklang and renderers packages.
+// It keeps no state, so one Server can handle any number of concurrent calls.
+type Server struct {
+	UnimplementedCooklangServiceServer
+}
+
+// NewServer creates a CooklangService implementation.
+//
+// Returns:
+//   - *Server: The service, ready to register with RegisterCooklangServiceServer
+//
+// Example:
+//
+//	listener, _ := net.Listen("tcp", ":9090")
+//	server := grpc.NewServer()
+//	cooklangpb.RegisterCooklangServiceServer(server, cooklangpb.NewServer())
+//	log.Fatal(server.Serve(listener))
+func NewServer() *Server {
+	return &Server{}
+}
+
+// Parse parses Cooklang source into a recipe.
+func (s *Server) Parse(_ context.Context, req *ParseRequest) (*ParseResponse, error) {
+	recipe, err := parseSource(req.GetSource(), req.GetExtended())
+	if err != nil {
+		return nil, err
+	}
+	return &ParseResponse{Recipe: FromRecipe(recipe)}, nil
+}
+
+// Render renders a recipe in the requested format: cooklang, markdown (the
+// default), html, print or voice. HTML is returned as a fragment without a
+// surrounding document.
+func (s *Server) Render(_ context.Context, req *RenderRequest) (*RenderResponse, error) {
+	recipe, err := recipeFromInput(req.GetRecipe())
+	if err != nil {
+		return nil, err
+	}
+	options := renderers.RendererOptions{Locale: req.GetLocale()}
+
+	var renderer renderers.Renderer
+	switch strings.ToLower(req.GetFormat()) {
+	case "cooklang", "cook":
+		renderer = renderers.CooklangRenderer{}
+	case "", "markdown", "md":
+		renderer = renderers.MarkdownRenderer{}
+	case "html":
+		renderer = renderers.HTMLRenderer{}
+	case "print":
+		renderer = renderers.PrintRenderer{}
+	case "voice":
+		renderer = renderers.VoiceRenderer{}
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unsupported format: %s (supported: cooklang, markdown, html, print, voice)", req.GetFormat())
+	}
+	output, err := renderer.Render(recipe, options)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &RenderResponse{Output: output}, nil
+}
+
+// ShoppingList consolidates the ingredients of the requested recipes, optionally
+// converted to a unit system.
+func (s *Server) ShoppingList(_ context.Context, req *ShoppingListRequest) (*ShoppingListResponse, error) {
+	if len(req.GetRecipes()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "no recipes given")
+	}
+	var recipes []*cooklang.Recipe
+	for _, input := range req.GetRecipes() {
+		recipe, err := recipeFromInput(input)
+		if err != nil {
+			return nil, err
+		}
+		recipes = append(recipes, recipe)
+	}
+
+	list, err := cooklang.CreateShoppingList(recipes...)
+	if err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	if req.GetUnits() != "" {
+		system, err := cooklang.ParseUnitSystem(req.GetUnits())
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		list.Ingredients = list.Ingredients.ConvertToSystem(system)
+	}
+
+	resp := &ShoppingListResponse{Recipes: list.Recipes}
+	for _, item := range list.Items() {
+		resp.Items = append(resp.Items, &ShoppingItem{
+			Name:          item.Name,
+			Quantity:      item.Quantity,
+			QuantityMax:   item.QuantityMax,
+			Unit:          item.Unit,
+			Approximate:   item.Approximate,
+			Display:       item.Amount(),
+			Notes:         item.Notes,
+			SourceRecipes: item.SourceRecipes,
+		})
+	}
+	return resp, nil
+}
+
+// recipeFromInput parses or converts a RecipeInput and applies its scale.
+func recipeFromInput(input *RecipeInput) (*cooklang.Recipe, error) {
+	var recipe *cooklang.Recipe
+	var err error
+	switch in := input.GetInput().(type) {
+	case *RecipeInput_Source:
+		recipe, err = parseSource(in.Source, input.GetExtended())
+	case *RecipeInput_Recipe:
+		recipe, err = ToRecipe(in.Recipe)
+		if err != nil {
+			err = status.Error(codes.InvalidArgument, err.Error())
+		}
+	default:
+		err = status.Error(codes.InvalidArgument, "no recipe given")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if scale := input.GetScale(); scale != 0 {
+		if scale < 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid scale %g", scale)
+		}
+		recipe = recipe.Scale(scale)
+	}
+	return recipe, nil
+}
+
+// parseSource parses Cooklang source, reporting errors as InvalidArgument.
+func parseSource(source string, extended bool) (*cooklang.Recipe, error) {
+	opts := []cooklang.ParseOption{cooklang.WithMaxSize(maxSourceSize), cooklang.WithLimits(sourceLimits)}
+	if extended {
+		opts = append(opts, cooklang.WithExtendedMode())
+	}
+	recipe, err := cooklang.ParseString(source, opts...)
+	if errors.Is(err, cooklang.ErrRecipeTooLarge) || errors.Is(err, cooklang.ErrLimitExceeded) {
+		return nil, status.Error(codes.ResourceExhausted, err.Error())
+	}
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return recipe, nil
+}
@@ -0,0 +1,118 @@
+package cooklangpb
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// newTestClient starts the service on an in-memory listener.
+func newTestClient(t *testing.T) CooklangServiceClient {
+	t.Helper()
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	RegisterCooklangServiceServer(server, NewServer())
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return NewCooklangServiceClient(conn)
+}
+
+func TestServer(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+	source := "---\ntitle: Pancakes\nservings: 2\n---\nWhisk @flour{200%g} and @eggs{2} in a #bowl{}."
+
+	parsed, err := client.Parse(ctx, &ParseRequest{Source: source})
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if parsed.GetRecipe().GetTitle() != "Pancakes" || len(parsed.GetRecipe().GetSteps()) != 1 {
+		t.Errorf("unexpected recipe: %v", parsed.GetRecipe())
+	}
+
+	rendered, err := client.Render(ctx, &RenderRequest{
+		Recipe: &RecipeInput{Input: &RecipeInput_Recipe{Recipe: parsed.GetRecipe()}, Scale: 2},
+	})
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if !strings.Contains(rendered.GetOutput(), "# Pancakes") || !strings.Contains(rendered.GetOutput(), "400 g") {
+		t.Errorf("expected scaled Markdown, got:\n%s", rendered.GetOutput())
+	}
+
+	list, err := client.ShoppingList(ctx, &ShoppingListRequest{Recipes: []*RecipeInput{
+		{Input: &RecipeInput_Source{Source: source}},
+		{Input: &RecipeInput_Source{Source: "Add @flour{100%g}."}},
+	}})
+	if err != nil {
+		t.Fatalf("ShoppingList failed: %v", err)
+	}
+	var flour *ShoppingItem
+	for _, item := range list.GetItems() {
+		if item.GetName() == "flour" {
+			flour = item
+		}
+	}
+	if flour == nil || flour.GetQuantity() != 300 || flour.GetDisplay() != "300 g" {
+		t.Errorf("expected 300 g flour, got %v", flour)
+	}
+}
+
+func TestServerErrors(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		call func() error
+		code codes.Code
+	}{
+		{"render without recipe", func() error {
+			_, err := client.Render(ctx, &RenderRequest{})
+			return err
+		}, codes.InvalidArgument},
+		{"unknown format", func() error {
+			_, err := client.Render(ctx, &RenderRequest{Recipe: &RecipeInput{Input: &RecipeInput_Source{Source: "Boil @water{}."}}, Format: "pdf"})
+			return err
+		}, codes.InvalidArgument},
+		{"source too large", func() error {
+			_, err := client.Parse(ctx, &ParseRequest{Source: strings.Repeat("a", maxSourceSize+1)})
+			return err
+		}, codes.ResourceExhausted},
+		{"too many steps", func() error {
+			_, err := client.Parse(ctx, &ParseRequest{Source: strings.Repeat("a\n\n", sourceLimits.MaxSteps+1)})
+			return err
+		}, codes.ResourceExhausted},
+		{"empty shopping list", func() error {
+			_, err := client.ShoppingList(ctx, &ShoppingListRequest{})
+			return err
+		}, codes.InvalidArgument},
+		{"unknown unit system", func() error {
+			_, err := client.ShoppingList(ctx, &ShoppingListRequest{Recipes: []*RecipeInput{{Input: &RecipeInput_Source{Source: "Boil @water{1%l}."}}}, Units: "cubits"})
+			return err
+		}, codes.InvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code := status.Code(tt.call()); code != tt.code {
+				t.Errorf("expected %v, got %v", tt.code, code)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package cooklang
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+)
+
+// CountRounding chooses how scaling rounds the amounts of counted ingredients, such
+// as @eggs{2}: half a recipe of 3 eggs is 1.5 eggs, which few cooks can measure.
+type CountRounding string
+
+const (
+	// CountKeepFraction keeps the exact amount (1.5 eggs). It is the zero value.
+	CountKeepFraction CountRounding = ""
+	// CountRoundUp rounds up to whole items (2 eggs), so a shopping list is never short.
+	CountRoundUp CountRounding = "up"
+	// CountNearestHalf rounds to the nearest half item, but never below a half
+	// (1.5 eggs, 2.5 lemons).
+	CountNearestHalf CountRounding = "half"
+)
+
+// countRoundingNames are the names accepted by ParseCountRounding, for error messages.
+var countRoundingNames = []string{"keep", string(CountRoundUp), string(CountNearestHalf)}
+
+// ParseCountRounding converts a rounding name (keep, up, half) to a CountRounding.
+// Matching is case-insensitive, and "" is CountKeepFraction.
+func ParseCountRounding(name string) (CountRounding, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	switch name {
+	case "", "keep":
+		return CountKeepFraction, nil
+	case string(CountRoundUp), string(CountNearestHalf):
+		return CountRounding(name), nil
+	}
+	return "", fmt.Errorf("unknown count rounding: %s (use %s)", name, strings.Join(countRoundingNames, ", "))
+}
+
+// RoundCount rounds a number of items. Ranges are rounded bound by bound, and
+// "some" is returned unchanged.
+//
+// Parameters:
+//   - rounding: How to round
+//
+// Returns:
+//   - Quantity: The rounded quantity
+//
+// Example:
+//
+//	eggs := cooklang.NewQuantity(3, 1).Scale(0.5) // 1 1/2
+//	eggs.RoundCount(cooklang.CountRoundUp)        // 2
+func (q Quantity) RoundCount(rounding CountRounding) Quantity {
+	switch rounding {
+	case CountRoundUp:
+		return q.mapBounds(math.Ceil)
+	case CountNearestHalf:
+		return q.mapBounds(func(v float64) float64 {
+			if v <= 0 {
+				return v
+			}
+			return max(math.Round(v*2)/2, 0.5)
+		})
+	}
+	return q
+}
+
+// IsCount reports whether the ingredient is counted in items, like @eggs{2}: it has
+// an amount and either no unit or a unit of single items such as "pieces" or
+// "whole". Counted ingredients are rounded by ScaleOptions.CountRounding and added
+// up with each other by ConsolidateByNameWithReport.
+func (i *Ingredient) IsCount() bool {
+	return i.Quantity.HasAmount() && (i.Unit == "" || countUnits[strings.ToLower(i.Unit)] == 1)
+}
+
+// RoundCounts rounds the amounts of counted ingredients (see Ingredient.IsCount),
+// e.g. after a shopping list was scaled to a number of servings.
+//
+// Parameters:
+//   - rounding: How to round
+//
+// Returns:
+//   - *IngredientList: A new list with the rounded ingredients
+//
+// Example:
+//
+//	list, _ := cooklang.CreateShoppingListForServings(3, recipe)
+//	rounded := list.Ingredients.RoundCounts(cooklang.CountRoundUp) // 2.5 eggs → 3
+func (il *IngredientList) RoundCounts(rounding CountRounding) *IngredientList {
+	return il.mapIngredients(func(ingredient *Ingredient) *Ingredient {
+		if ingredient.IsCount() && rounding != CountKeepFraction {
+			rounded := *ingredient
+			rounded.Quantity = ingredient.Quantity.RoundCount(rounding)
+			return &rounded
+		}
+		return ingredient
+	})
+}
+
+// countUnit returns the unit a group of counted ingredients is added up in: none if
+// any of them has none, or else the first one's.
+func countUnit(ingredients []*Ingredient) string {
+	if slices.ContainsFunc(ingredients, func(i *Ingredient) bool { return i.Unit == "" }) {
+		return ""
+	}
+	return ingredients[0].Unit
+}
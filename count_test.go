@@ -0,0 +1,91 @@
+package cooklang
+
+import (
+	"testing"
+)
+
+func TestParseCountRounding(t *testing.T) {
+	for name, want := range map[string]CountRounding{"": CountKeepFraction, "keep": CountKeepFraction, "Up": CountRoundUp, " half ": CountNearestHalf} {
+		got, err := ParseCountRounding(name)
+		if err != nil || got != want {
+			t.Errorf("ParseCountRounding(%q) = %q, %v, want %q", name, got, err, want)
+		}
+	}
+	if _, err := ParseCountRounding("down"); err == nil {
+		t.Error("expected an error for an unknown rounding")
+	}
+}
+
+func TestQuantityRoundCount(t *testing.T) {
+	tests := []struct {
+		quantity string
+		rounding CountRounding
+		want     string
+	}{
+		{"2.5", CountKeepFraction, "2.5"},
+		{"2.5", CountRoundUp, "3"},
+		{"2.1", CountRoundUp, "3"},
+		{"2.2", CountNearestHalf, "2"},
+		{"2.3", CountNearestHalf, "2.5"},
+		{"0.1", CountNearestHalf, "0.5"},
+		{"1.5-2.5", CountRoundUp, "2-3"},
+		{"some", CountRoundUp, "some"},
+	}
+	for _, tt := range tests {
+		q, err := ParseQuantity(tt.quantity)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := q.RoundCount(tt.rounding).String(); got != tt.want {
+			t.Errorf("%s.RoundCount(%q) = %s, want %s", tt.quantity, tt.rounding, got, tt.want)
+		}
+	}
+}
+
+func TestScaleWithOptionsCountRounding(t *testing.T) {
+	recipe, err := ParseString("Whisk @eggs{3} with @milk{250%ml} and @lemon{1%whole}.\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	kept := recipe.Scale(0.5).GetIngredients().Ingredients
+	if kept[0].Quantity.String() != "1.5" {
+		t.Errorf("expected 1.5 eggs without rounding, got %v", kept[0].Quantity)
+	}
+
+	rounded := recipe.ScaleWithOptions(0.5, ScaleOptions{CountRounding: CountRoundUp}).GetIngredients().Ingredients
+	for i, want := range []string{"2", "125", "1"} {
+		if got := rounded[i].Quantity.String(); got != want {
+			t.Errorf("%s: got %s, want %s", rounded[i].Name, got, want)
+		}
+	}
+}
+
+func TestConsolidateCountedItems(t *testing.T) {
+	list := NewIngredientList()
+	list.Add(NewIngredient("eggs", 2, ""))
+	list.Add(NewIngredient("eggs", 1, "piece"))
+	list.Add(NewIngredient("eggs", 0.5, "dozen"))
+
+	consolidated, report, err := list.ConsolidateByNameWithReport("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(consolidated.Ingredients) != 2 {
+		t.Fatalf("expected counted eggs and dozens, got %d entries", len(consolidated.Ingredients))
+	}
+	if eggs := consolidated.Ingredients[0]; eggs.Unit != "" || eggs.Quantity.String() != "3" {
+		t.Errorf("expected 3 eggs, got %v %s", eggs.Quantity, eggs.Unit)
+	}
+	if len(report.Merged) != 1 || len(report.Merged[0].Sources) != 2 {
+		t.Errorf("expected one merged entry of two eggs, got %+v", report.Merged)
+	}
+
+	rounded := NewIngredientList()
+	rounded.Add(NewIngredient("eggs", 2.5, ""))
+	rounded.Add(NewIngredient("flour", 2.5, "kg"))
+	rounded = rounded.RoundCounts(CountRoundUp)
+	if rounded.Ingredients[0].Quantity.String() != "3" || rounded.Ingredients[1].Quantity.String() != "2.5" {
+		t.Errorf("expected only the eggs rounded, got %v and %v", rounded.Ingredients[0].Quantity, rounded.Ingredients[1].Quantity)
+	}
+}
@@ -0,0 +1,220 @@
+package cooklang
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// CustomUnit defines a domain-specific unit in terms of a known unit,
+// e.g. a "scoop" of 30 g or a "shot" of 40 ml.
+type CustomUnit struct {
+	Amount float64 `json:"amount"` // How many base units one custom unit equals
+	Unit   string  `json:"unit"`   // The base unit (e.g., "g", "ml")
+}
+
+// CustomUnits maps custom unit names to their definitions.
+// Custom units can be declared in a recipe's frontmatter under the "units" key:
+//
+//	---
+//	units: {scoop: 30 g, shot: 40 ml}
+//	---
+//
+// or attached to a parsed recipe with Recipe.SetCustomUnits (e.g., for units shared
+// by a whole collection). Ingredients using a custom unit can then be converted to
+// and from its base unit, and are consolidated correctly in shopping lists.
+type CustomUnits map[string]CustomUnit
+
+// ParseCustomUnits parses a custom unit declaration such as "{scoop: 30 g, shot: 40 ml}".
+// The surrounding braces are optional. Invalid entries are skipped and reported in
+// the returned error, while all valid entries are still returned.
+//
+// Parameters:
+//   - spec: The unit declarations as "name: amount unit" pairs separated by commas
+//
+// Returns:
+//   - CustomUnits: The successfully parsed unit definitions
+//   - error: An error describing any invalid entries
+//
+// Example:
+//
+//	units, err := cooklang.ParseCustomUnits("{scoop: 30 g, shot: 40 ml}")
+//	fmt.Println(units["scoop"].Amount, units["scoop"].Unit) // 30 g
+func ParseCustomUnits(spec string) (CustomUnits, error) {
+	spec = strings.TrimSpace(spec)
+	spec = strings.TrimPrefix(spec, "{")
+	spec = strings.TrimSuffix(spec, "}")
+
+	units := CustomUnits{}
+	var invalid []string
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, definition, ok := strings.Cut(entry, ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			invalid = append(invalid, entry)
+			continue
+		}
+		unit, err := parseCustomUnitDefinition(definition)
+		if err != nil {
+			invalid = append(invalid, entry)
+			continue
+		}
+		units[name] = unit
+	}
+
+	if len(invalid) > 0 {
+		return units, fmt.Errorf("invalid custom unit declarations: %s", strings.Join(invalid, "; "))
+	}
+	return units, nil
+}
+
+// parseCustomUnitDefinition parses "30 g" or "30g" into a CustomUnit.
+func parseCustomUnitDefinition(definition string) (CustomUnit, error) {
+	definition = strings.TrimSpace(definition)
+	split := strings.IndexFunc(definition, func(r rune) bool {
+		return !unicode.IsDigit(r) && r != '.' && r != '/' && r != ' '
+	})
+	if split <= 0 {
+		return CustomUnit{}, fmt.Errorf("invalid unit definition: %q", definition)
+	}
+	amount, err := ParseFraction(strings.TrimSpace(definition[:split]))
+	if err != nil || amount <= 0 {
+		return CustomUnit{}, fmt.Errorf("invalid unit amount: %q", definition)
+	}
+	return CustomUnit{Amount: amount, Unit: strings.TrimSpace(definition[split:])}, nil
+}
+
+// String formats the declarations in frontmatter syntax, sorted by name.
+func (cu CustomUnits) String() string {
+	names := make([]string, 0, len(cu))
+	for name := range cu {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		unit := cu[name]
+		parts = append(parts, fmt.Sprintf("%s: %s %s", name, FormatAsFractionDefault(unit.Amount), unit.Unit))
+	}
+	return "{" + strings.Join(parts, ", ") + "}"
+}
+
+// SetCustomUnits registers custom units for this recipe, so its ingredients can be
+// converted to and from them. Units already declared in the recipe's frontmatter take
+// precedence, which lets collection-wide definitions be overridden per recipe.
+//
+// Parameters:
+//   - units: The custom unit definitions to register
+//
+// Example:
+//
+//	collectionUnits, _ := cooklang.ParseCustomUnits("scoop: 30 g")
+//	recipe, _ := cooklang.ParseFile("shake.cook")
+//	recipe.SetCustomUnits(collectionUnits)
+//	protein := recipe.GetIngredients().Ingredients[0] // @protein{2%scoop}
+//	grams, _ := protein.ConvertTo("g")                // 60 g
+func (r *Recipe) SetCustomUnits(units CustomUnits) {
+	merged := CustomUnits{}
+	for name, unit := range units {
+		merged[name] = unit
+	}
+	for name, unit := range r.CustomUnits {
+		merged[name] = unit
+	}
+	r.CustomUnits = merged
+
+	for step := r.FirstStep; step != nil; step = step.NextStep {
+		for component := step.FirstComponent; component != nil; component = component.GetNext() {
+			if ingredient, ok := component.(*Ingredient); ok {
+				ingredient.customUnits = merged
+			}
+		}
+	}
+}
+
+// lookup returns the definition of a custom unit, if declared.
+func (cu CustomUnits) lookup(unit string) (CustomUnit, bool) {
+	if unit == "" || cu == nil {
+		return CustomUnit{}, false
+	}
+	def, ok := cu[unit]
+	return def, ok
+}
+
+// convertCustomUnit converts an ingredient whose unit, or the target unit, is a custom unit.
+// The second return value is false if neither unit is custom. Base units are resolved
+// without custom units, so a definition cannot refer to another custom unit.
+func (i *Ingredient) convertCustomUnit(targetUnit string) (*Ingredient, bool, error) {
+	if def, ok := i.customUnits.lookup(i.Unit); ok {
+		// Custom → base unit, then on to the target
+		result := i.withQuantity(i.Quantity.Scale(def.Amount), def.Unit)
+		if def.Unit != targetUnit {
+			result.customUnits = nil
+			converted, err := result.ConvertTo(targetUnit)
+			if err != nil {
+				return nil, true, err
+			}
+			result = converted
+		}
+		result.customUnits = i.customUnits
+		return result, true, nil
+	}
+
+	if def, ok := i.customUnits.lookup(targetUnit); ok {
+		// Source → the custom unit's base unit, then divide into custom units
+		base := *i
+		base.customUnits = nil
+		if i.Unit != def.Unit {
+			converted, err := base.ConvertTo(def.Unit)
+			if err != nil {
+				return nil, true, err
+			}
+			base = *converted
+		}
+		result := base.withQuantity(base.Quantity.Scale(1/def.Amount), targetUnit)
+		result.customUnits = i.customUnits
+		return result, true, nil
+	}
+
+	return nil, false, nil
+}
+
+// withQuantity returns a copy of the ingredient with a new quantity and unit.
+func (i *Ingredient) withQuantity(quantity Quantity, unit string) *Ingredient {
+	return &Ingredient{
+		Name:           i.Name,
+		Quantity:       quantity,
+		Fixed:          i.Fixed,
+		Optional:       i.Optional,
+		Reference:      i.Reference,
+		Approximate:    i.Approximate,
+		Unit:           unit,
+		TypedUnit:      CreateTypedUnit(unit),
+		Subinstruction: i.Subinstruction,
+		Annotation:     i.Annotation,
+		Position:       i.Position,
+		NextComponent:  i.NextComponent,
+		customUnits:    i.customUnits,
+	}
+}
+
+// customUnitType returns the unit type ("mass", "volume", ...) of a custom unit's base unit.
+func (i *Ingredient) customUnitType() string {
+	def, ok := i.customUnits.lookup(i.Unit)
+	if !ok {
+		return ""
+	}
+	if cookingType := getCookingUnitType(def.Unit); cookingType != "" {
+		return cookingType
+	}
+	if typed := CreateTypedUnit(def.Unit); typed != nil {
+		return typed.Quantity
+	}
+	return ""
+}
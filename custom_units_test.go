@@ -0,0 +1,107 @@
+package cooklang
+
+import (
+	"testing"
+)
+
+func TestParseCustomUnits(t *testing.T) {
+	units, err := ParseCustomUnits("{scoop: 30 g, shot: 40ml, pinch: 1/8 tsp}")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(units) != 3 {
+		t.Fatalf("expected 3 units, got %d: %v", len(units), units)
+	}
+	if units["scoop"] != (CustomUnit{Amount: 30, Unit: "g"}) {
+		t.Errorf("scoop = %+v, want 30 g", units["scoop"])
+	}
+	if units["shot"] != (CustomUnit{Amount: 40, Unit: "ml"}) {
+		t.Errorf("shot = %+v, want 40 ml", units["shot"])
+	}
+	if units["pinch"].Amount != 0.125 {
+		t.Errorf("pinch amount = %v, want 0.125", units["pinch"].Amount)
+	}
+	if got := units.String(); got != "{pinch: 1/8 tsp, scoop: 30 g, shot: 40 ml}" {
+		t.Errorf("String() = %q", got)
+	}
+
+	partial, err := ParseCustomUnits("scoop: 30 g, bogus, cup: lots")
+	if err == nil {
+		t.Error("expected error for invalid declarations")
+	}
+	if _, ok := partial["scoop"]; !ok || len(partial) != 1 {
+		t.Errorf("expected only scoop to be parsed, got %v", partial)
+	}
+}
+
+func TestCustomUnitsFromFrontmatter(t *testing.T) {
+	recipe, err := ParseString(`---
+units: {scoop: 30 g, shot: 40 ml}
+---
+Blend @protein{2%scoop} with @protein{40%g} and @espresso{1%shot}.`)
+	if err != nil {
+		t.Fatalf("failed to parse recipe: %v", err)
+	}
+	if len(recipe.CustomUnits) != 2 {
+		t.Fatalf("expected 2 custom units, got %v", recipe.CustomUnits)
+	}
+
+	espresso := findIngredient(recipe.GetIngredients().Ingredients, "espresso")
+	if espresso == nil {
+		t.Fatal("espresso not found")
+	}
+	if espresso.GetUnitType() != "volume" {
+		t.Errorf("espresso unit type = %q, want volume", espresso.GetUnitType())
+	}
+	ml, err := espresso.ConvertTo("ml")
+	if err != nil || ml.Quantity.Min() != 40 {
+		t.Errorf("1 shot in ml = %v (err %v), want 40", ml, err)
+	}
+	tbsp, err := espresso.ConvertTo("tbsp")
+	if err != nil || !floatClose(tbsp.Quantity.Min(), 2.705, 0.01) {
+		t.Errorf("1 shot in tbsp = %v (err %v), want ~2.7", tbsp, err)
+	}
+
+	list, err := CreateShoppingList(recipe)
+	if err != nil {
+		t.Fatalf("failed to create shopping list: %v", err)
+	}
+	protein := findIngredient(list.Ingredients.Ingredients, "protein")
+	if protein == nil {
+		t.Fatal("protein not found in shopping list")
+	}
+	if protein.Unit != "scoop" || !floatClose(protein.Quantity.Min(), 3.333, 0.01) {
+		t.Errorf("consolidated protein = %v %s, want ~3.33 scoop", protein.Quantity, protein.Unit)
+	}
+}
+
+func TestSetCustomUnits(t *testing.T) {
+	recipe, err := ParseString(`---
+units: {scoop: 25 g}
+---
+Add @protein{2%scoop} and @ice{1%cup} to the @milk{1%glass}.`)
+	if err != nil {
+		t.Fatalf("failed to parse recipe: %v", err)
+	}
+
+	collection, _ := ParseCustomUnits("scoop: 30 g, glass: 250 ml")
+	recipe.SetCustomUnits(collection)
+
+	ingredients := recipe.GetIngredients().Ingredients
+	protein := findIngredient(ingredients, "protein")
+	grams, err := protein.ConvertTo("g")
+	if err != nil || grams.Quantity.Min() != 50 {
+		t.Errorf("2 scoop = %v g (err %v), want 50 (recipe definition wins)", grams, err)
+	}
+
+	milk := findIngredient(ingredients, "milk")
+	if !milk.CanConvertTo("l") {
+		t.Error("expected glass to be convertible to litres")
+	}
+
+	metric := recipe.Scale(2).GetIngredients().ConvertToSystem(UnitSystemMetric)
+	milk = findIngredient(metric.Ingredients, "milk")
+	if milk == nil || milk.Unit != "ml" || milk.Quantity.Min() != 500 {
+		t.Errorf("scaled metric milk = %+v, want 500 ml", milk)
+	}
+}
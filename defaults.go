@@ -0,0 +1,77 @@
+package cooklang
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/goccy/go-yaml"
+	"github.com/hilli/cooklang/parser"
+)
+
+// DefaultsFile is the name of a directory's metadata defaults file. Its keys, such
+// as author, cuisine, locale and tags, are added to every recipe parsed from the
+// directory with WithDirectoryDefaults, and to every recipe in a Library, unless the
+// recipe sets the key itself.
+//
+// Example _defaults.yml:
+//
+//	author: Grandma Rose
+//	cuisine: Danish
+//	locale: da
+//	tags: [family, classic]
+const DefaultsFile = "_defaults.yml"
+
+// LoadDefaults reads the DefaultsFile of a directory. It is written like a recipe's
+// YAML frontmatter, without the "---" lines, and its values are read the same way.
+//
+// Parameters:
+//   - dir: The directory
+//
+// Returns:
+//   - Metadata: The default metadata, or nil if the directory has no defaults file
+//   - error: An error if the file cannot be read or is not valid YAML
+//
+// Example:
+//
+//	defaults, err := cooklang.LoadDefaults("recipes/grandma")
+//	fmt.Println(defaults["author"]) // Grandma Rose
+func LoadDefaults(dir string) (Metadata, error) {
+	filename := filepath.Join(dir, DefaultsFile)
+	content, err := os.ReadFile(filename)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	// The frontmatter parser accepts anything, so check that the file is YAML first
+	var mapping map[string]any
+	if err := yaml.Unmarshal(content, &mapping); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", filename, err)
+	}
+	parsed, err := parser.New().ParseString("---\n" + string(content) + "\n---\n")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", filename, err)
+	}
+	return Metadata(parsed.Metadata), nil
+}
+
+// mergeDefaults adds the defaults to metadata for the keys it does not have; the
+// recipe's own values win, even when empty.
+func mergeDefaults(metadata parser.Metadata, defaults Metadata) parser.Metadata {
+	if len(defaults) == 0 {
+		return metadata
+	}
+	if metadata == nil {
+		metadata = make(parser.Metadata, len(defaults))
+	}
+	for key, value := range defaults {
+		if _, ok := metadata[key]; !ok {
+			metadata[key] = value
+		}
+	}
+	return metadata
+}
@@ -0,0 +1,113 @@
+package cooklang
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadDefaults(t *testing.T) {
+	dir := t.TempDir()
+	defaults, err := LoadDefaults(dir)
+	if err != nil || defaults != nil {
+		t.Fatalf("LoadDefaults without a defaults file = %v, %v", defaults, err)
+	}
+
+	if err := writeFile(filepath.Join(dir, DefaultsFile), "author: Grandma Rose\ncuisine: Danish\ntags: [family, classic]\n"); err != nil {
+		t.Fatal(err)
+	}
+	defaults, err = LoadDefaults(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if defaults["author"] != "Grandma Rose" || defaults["cuisine"] != "Danish" || defaults["tags"] != "family, classic" {
+		t.Errorf("defaults = %v", defaults)
+	}
+
+	if err := writeFile(filepath.Join(dir, DefaultsFile), "author: [unclosed\n"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadDefaults(dir); err == nil || !strings.Contains(err.Error(), DefaultsFile) {
+		t.Errorf("expected an error naming the defaults file, got %v", err)
+	}
+}
+
+func TestParseFileWithDirectoryDefaults(t *testing.T) {
+	dir := t.TempDir()
+	recipePath := filepath.Join(dir, "Kringle.cook")
+	if err := writeFile(filepath.Join(dir, DefaultsFile), "author: Grandma Rose\ncuisine: Danish\nlocale: da\ntags: [family, classic]\n"); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeFile(recipePath, "---\ncuisine: Swedish\ntags: [baking]\n---\nKnead @flour{500%g}."); err != nil {
+		t.Fatal(err)
+	}
+
+	recipe, err := ParseFile(recipePath, WithDirectoryDefaults())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if recipe.Author != "Grandma Rose" || recipe.Metadata["locale"] != "da" {
+		t.Errorf("expected the author and locale from the defaults, got %q and %q", recipe.Author, recipe.Metadata["locale"])
+	}
+	if recipe.Cuisine != "Swedish" || strings.Join(recipe.Tags, ",") != "baking" {
+		t.Errorf("expected the recipe's own cuisine and tags to win, got %q and %v", recipe.Cuisine, recipe.Tags)
+	}
+
+	recipe, err = ParseFile(recipePath, WithDirectoryDefaults(), WithDefaults(Metadata{"author": "Rose"}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if recipe.Author != "Rose" {
+		t.Errorf("expected WithDefaults to win over the defaults file, got %q", recipe.Author)
+	}
+
+	recipe, err = ParseFile(recipePath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if recipe.Author != "" {
+		t.Errorf("expected no defaults without the option, got author %q", recipe.Author)
+	}
+}
+
+func TestLoadLibraryDefaults(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"grandma/" + DefaultsFile:  "author: Grandma Rose\ntags: family\n",
+		"grandma/kringle.cook":     "Knead @flour{500%g}.",
+		"grandma/aebleskiver.cook": "---\nauthor: Aunt Inge\n---\nFry @batter{1%l}.",
+		"other/soup.cook":          "Boil @water{1%l}.",
+		"broken/" + DefaultsFile:   "author: [unclosed\n",
+		"broken/stew.cook":         "Stew @beef{1%kg}.",
+	}
+	for name, content := range files {
+		if err := writeFile(filepath.Join(dir, name), content); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	library, err := LoadLibrary(dir)
+	if err == nil || strings.Count(err.Error(), DefaultsFile) != 1 {
+		t.Errorf("expected one error for the broken defaults file, got %v", err)
+	}
+	if library.Len() != 4 {
+		t.Fatalf("expected 4 recipes, got %d", library.Len())
+	}
+	for path, author := range map[string]string{
+		"grandma/kringle":     "Grandma Rose",
+		"grandma/aebleskiver": "Aunt Inge",
+		"other/soup":          "",
+		"broken/stew":         "",
+	} {
+		entry, ok := library.Get(path)
+		if !ok {
+			t.Fatalf("%s not loaded", path)
+		}
+		if entry.Recipe.Author != author {
+			t.Errorf("%s author = %q, want %q", path, entry.Recipe.Author, author)
+		}
+	}
+	if got := len(library.ByTag("family")); got != 2 {
+		t.Errorf("expected 2 recipes tagged family from the defaults, got %d", got)
+	}
+}
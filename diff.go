@@ -0,0 +1,253 @@
+package cooklang
+
+import (
+	"sort"
+	"strings"
+)
+
+// ChangeType describes how an element differs between two recipes.
+type ChangeType string
+
+const (
+	ChangeAdded    ChangeType = "added"
+	ChangeRemoved  ChangeType = "removed"
+	ChangeModified ChangeType = "modified"
+)
+
+// RecipeDiff is a structured, semantic diff between two versions of a recipe.
+type RecipeDiff struct {
+	Ingredients []IngredientChange `json:"ingredients,omitempty"` // Ingredient additions, removals, and quantity changes
+	Steps       []StepChange       `json:"steps,omitempty"`       // Step additions, removals, and rewrites
+	Metadata    []MetadataChange   `json:"metadata,omitempty"`    // Metadata key changes
+}
+
+// IngredientChange describes a change to a single (consolidated) ingredient.
+// Old and New are unlinked copies, so changing them does not change the recipes.
+type IngredientChange struct {
+	Type          ChangeType  `json:"type"`
+	Name          string      `json:"name"`
+	Old           *Ingredient `json:"old,omitempty"`            // Ingredient in the old recipe (nil when added)
+	New           *Ingredient `json:"new,omitempty"`            // Ingredient in the new recipe (nil when removed)
+	QuantityDelta float32     `json:"quantity_delta,omitempty"` // New minus old, in the new ingredient's unit
+	HasDelta      bool        `json:"has_delta,omitempty"`      // True if QuantityDelta could be computed
+}
+
+// StepChange describes a change to a recipe step.
+// OldIndex and NewIndex are 1-based; zero means the step does not exist on that side.
+type StepChange struct {
+	Type     ChangeType `json:"type"`
+	OldIndex int        `json:"old_index,omitempty"`
+	NewIndex int        `json:"new_index,omitempty"`
+	Old      string     `json:"old,omitempty"`
+	New      string     `json:"new,omitempty"`
+}
+
+// MetadataChange describes a change to a metadata key.
+type MetadataChange struct {
+	Type ChangeType `json:"type"`
+	Key  string     `json:"key"`
+	Old  string     `json:"old,omitempty"`
+	New  string     `json:"new,omitempty"`
+}
+
+// Diff computes a semantic diff between two recipes.
+// Ingredients are compared after consolidating duplicates by name, steps are
+// aligned using a longest-common-subsequence match on their Cooklang text, and
+// metadata is compared key by key.
+//
+// Parameters:
+//   - a: The old version of the recipe (nil is an empty recipe)
+//   - b: The new version of the recipe (nil is an empty recipe)
+//
+// Returns:
+//   - *RecipeDiff: The structured differences (empty when the recipes match)
+//
+// Example:
+//
+//	oldRecipe, _ := cooklang.ParseFile("v1/pancakes.cook")
+//	newRecipe, _ := cooklang.ParseFile("v2/pancakes.cook")
+//	diff := cooklang.Diff(oldRecipe, newRecipe)
+//	for _, change := range diff.Ingredients {
+//	    fmt.Printf("%s %s\n", change.Type, change.Name)
+//	}
+func Diff(a, b *Recipe) *RecipeDiff {
+	if a == nil {
+		a = &Recipe{}
+	}
+	if b == nil {
+		b = &Recipe{}
+	}
+	return &RecipeDiff{
+		Ingredients: diffIngredients(a, b),
+		Steps:       diffSteps(stepTexts(a), stepTexts(b)),
+		Metadata:    diffMetadata(a.Metadata, b.Metadata),
+	}
+}
+
+// HasChanges reports whether the diff contains any changes.
+func (d *RecipeDiff) HasChanges() bool {
+	return len(d.Ingredients) > 0 || len(d.Steps) > 0 || len(d.Metadata) > 0
+}
+
+// diffIngredients compares the consolidated ingredient lists of two recipes.
+func diffIngredients(a, b *Recipe) []IngredientChange {
+	oldByName := collectIngredientsByName(a)
+	newByName := collectIngredientsByName(b)
+
+	names := make([]string, 0, len(oldByName)+len(newByName))
+	for name := range oldByName {
+		names = append(names, name)
+	}
+	for name := range newByName {
+		if _, ok := oldByName[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var changes []IngredientChange
+	for _, name := range names {
+		oldIng, inOld := oldByName[name]
+		newIng, inNew := newByName[name]
+
+		switch {
+		case !inOld:
+			changes = append(changes, IngredientChange{Type: ChangeAdded, Name: name, New: newIng})
+		case !inNew:
+			changes = append(changes, IngredientChange{Type: ChangeRemoved, Name: name, Old: oldIng})
+		case !oldIng.Quantity.Equal(newIng.Quantity) || oldIng.Unit != newIng.Unit:
+			change := IngredientChange{Type: ChangeModified, Name: name, Old: oldIng, New: newIng}
+			if oldIng.Quantity.HasAmount() && newIng.Quantity.HasAmount() {
+				oldQty := oldIng.Quantity
+				if oldIng.Unit != newIng.Unit {
+					if converted, err := oldIng.ConvertTo(newIng.Unit); err == nil {
+						oldQty = converted.Quantity
+						change.HasDelta = true
+					}
+				} else {
+					change.HasDelta = true
+				}
+				if change.HasDelta {
+					change.QuantityDelta = float32(newIng.Quantity.Min() - oldQty.Min())
+				}
+			}
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
+
+// collectIngredientsByName consolidates a recipe's ingredients and indexes unlinked
+// copies of them by name. When an ingredient appears with incompatible units, the
+// first entry is used.
+func collectIngredientsByName(r *Recipe) map[string]*Ingredient {
+	result := make(map[string]*Ingredient)
+	collected, err := r.GetCollectedIngredients()
+	if err != nil {
+		collected = r.GetIngredients()
+	}
+	for _, ing := range collected.Ingredients {
+		if _, exists := result[ing.Name]; !exists {
+			result[ing.Name] = copyComponent(ing).(*Ingredient)
+		}
+	}
+	return result
+}
+
+// stepTexts renders each step of a recipe back to Cooklang syntax.
+func stepTexts(r *Recipe) []string {
+	var texts []string
+	for step := r.FirstStep; step != nil; step = step.NextStep {
+		var sb strings.Builder
+		for component := step.FirstComponent; component != nil; component = component.GetNext() {
+			sb.WriteString(component.Render())
+		}
+		texts = append(texts, strings.TrimSpace(sb.String()))
+	}
+	return texts
+}
+
+// diffSteps aligns two step sequences with an LCS table and reports the edits.
+// A run of removals directly followed by additions is reported as modifications.
+func diffSteps(oldSteps, newSteps []string) []StepChange {
+	n, m := len(oldSteps), len(newSteps)
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if oldSteps[i] == newSteps[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var changes []StepChange
+	var removed, added []StepChange
+	flush := func() {
+		paired := min(len(removed), len(added))
+		for k := 0; k < paired; k++ {
+			changes = append(changes, StepChange{
+				Type:     ChangeModified,
+				OldIndex: removed[k].OldIndex,
+				NewIndex: added[k].NewIndex,
+				Old:      removed[k].Old,
+				New:      added[k].New,
+			})
+		}
+		changes = append(changes, removed[paired:]...)
+		changes = append(changes, added[paired:]...)
+		removed, added = nil, nil
+	}
+
+	i, j := 0, 0
+	for i < n || j < m {
+		switch {
+		case i < n && j < m && oldSteps[i] == newSteps[j]:
+			flush()
+			i++
+			j++
+		case j < m && (i == n || lcs[i][j+1] >= lcs[i+1][j]):
+			added = append(added, StepChange{Type: ChangeAdded, NewIndex: j + 1, New: newSteps[j]})
+			j++
+		default:
+			removed = append(removed, StepChange{Type: ChangeRemoved, OldIndex: i + 1, Old: oldSteps[i]})
+			i++
+		}
+	}
+	flush()
+
+	return changes
+}
+
+// diffMetadata compares two metadata maps key by key, in sorted key order.
+func diffMetadata(oldMeta, newMeta Metadata) []MetadataChange {
+	keys := make([]string, 0, len(oldMeta)+len(newMeta))
+	for k := range oldMeta {
+		keys = append(keys, k)
+	}
+	for k := range newMeta {
+		if _, ok := oldMeta[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	var changes []MetadataChange
+	for _, key := range keys {
+		oldVal, inOld := oldMeta[key]
+		newVal, inNew := newMeta[key]
+		switch {
+		case !inOld:
+			changes = append(changes, MetadataChange{Type: ChangeAdded, Key: key, New: newVal})
+		case !inNew:
+			changes = append(changes, MetadataChange{Type: ChangeRemoved, Key: key, Old: oldVal})
+		case oldVal != newVal:
+			changes = append(changes, MetadataChange{Type: ChangeModified, Key: key, Old: oldVal, New: newVal})
+		}
+	}
+	return changes
+}
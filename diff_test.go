@@ -0,0 +1,132 @@
+package cooklang
+
+import (
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	oldRecipe, err := ParseString(`---
+title: Pancakes
+servings: 2
+---
+Mix @flour{200%g} and @milk{300%ml}.
+
+Add @salt{1%pinch}.
+
+Fry in a #pan{}.`)
+	if err != nil {
+		t.Fatalf("failed to parse old recipe: %v", err)
+	}
+
+	newRecipe, err := ParseString(`---
+title: Pancakes
+servings: 4
+author: Jane
+---
+Mix @flour{0.4%kg} and @milk{300%ml}.
+
+Add @sugar{2%tbsp}.
+
+Fry in a #pan{}.
+
+Serve warm.`)
+	if err != nil {
+		t.Fatalf("failed to parse new recipe: %v", err)
+	}
+
+	diff := Diff(oldRecipe, newRecipe)
+	if !diff.HasChanges() {
+		t.Fatal("expected changes")
+	}
+
+	ingredientChanges := map[string]IngredientChange{}
+	for _, c := range diff.Ingredients {
+		ingredientChanges[c.Name] = c
+	}
+	if len(ingredientChanges) != 3 {
+		t.Errorf("expected 3 ingredient changes, got %d: %+v", len(ingredientChanges), diff.Ingredients)
+	}
+	if c := ingredientChanges["sugar"]; c.Type != ChangeAdded {
+		t.Errorf("expected sugar to be added, got %q", c.Type)
+	}
+	if c := ingredientChanges["salt"]; c.Type != ChangeRemoved {
+		t.Errorf("expected salt to be removed, got %q", c.Type)
+	}
+	flour := ingredientChanges["flour"]
+	if flour.Type != ChangeModified {
+		t.Errorf("expected flour to be modified, got %q", flour.Type)
+	}
+	if !flour.HasDelta || flour.QuantityDelta < 0.199 || flour.QuantityDelta > 0.201 {
+		t.Errorf("expected flour delta of 0.2 kg, got %v (has delta: %v)", flour.QuantityDelta, flour.HasDelta)
+	}
+
+	var modified, added int
+	for _, c := range diff.Steps {
+		switch c.Type {
+		case ChangeModified:
+			modified++
+		case ChangeAdded:
+			added++
+		case ChangeRemoved:
+			t.Errorf("unexpected removed step: %+v", c)
+		}
+	}
+	if modified != 2 || added != 1 {
+		t.Errorf("expected 2 modified and 1 added step, got %d modified, %d added: %+v", modified, added, diff.Steps)
+	}
+
+	metaChanges := map[string]ChangeType{}
+	for _, c := range diff.Metadata {
+		metaChanges[c.Key] = c.Type
+	}
+	if metaChanges["servings"] != ChangeModified || metaChanges["author"] != ChangeAdded {
+		t.Errorf("unexpected metadata changes: %+v", diff.Metadata)
+	}
+}
+
+func TestDiffIdentical(t *testing.T) {
+	recipe, err := ParseString("Mix @flour{200%g} with @water{100%ml}.")
+	if err != nil {
+		t.Fatalf("failed to parse recipe: %v", err)
+	}
+	if diff := Diff(recipe, recipe); diff.HasChanges() {
+		t.Errorf("expected no changes, got %+v", diff)
+	}
+}
+
+func TestDiffIngredientsAreUnlinkedCopies(t *testing.T) {
+	oldRecipe, _ := ParseString("Mix @flour{200%g} in a #bowl{}.")
+	newRecipe, _ := ParseString("Mix @flour{300%g} in a #bowl{}.")
+
+	diff := Diff(oldRecipe, newRecipe)
+	if len(diff.Ingredients) != 1 {
+		t.Fatalf("expected 1 ingredient change, got %+v", diff.Ingredients)
+	}
+	change := diff.Ingredients[0]
+	if change.Old.NextComponent != nil || change.New.NextComponent != nil {
+		t.Error("expected ingredients without linked components")
+	}
+	change.New.Quantity = NewQuantity(1, 1)
+	if got := newRecipe.GetIngredients().Ingredients[0].Quantity; !got.Equal(NewQuantity(300, 1)) {
+		t.Errorf("changing the diff changed the recipe: quantity %v", got)
+	}
+}
+
+func TestDiffNil(t *testing.T) {
+	recipe, _ := ParseString("---\ntitle: Soup\n---\nBoil @water{1%l}.")
+
+	added := Diff(nil, recipe)
+	if len(added.Ingredients) != 1 || added.Ingredients[0].Type != ChangeAdded {
+		t.Errorf("expected water to be added, got %+v", added.Ingredients)
+	}
+	if len(added.Steps) != 1 || added.Steps[0].Type != ChangeAdded {
+		t.Errorf("expected one added step, got %+v", added.Steps)
+	}
+	removed := Diff(recipe, nil)
+	if len(removed.Metadata) == 0 || removed.Metadata[0].Type != ChangeRemoved {
+		t.Errorf("expected removed metadata, got %+v", removed.Metadata)
+	}
+	if Diff(nil, nil).HasChanges() {
+		t.Error("two nil recipes should not differ")
+	}
+}
@@ -0,0 +1,76 @@
+package cooklang
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Difficulty is a recipe's difficulty level. The difficulty metadata is free text;
+// ParseDifficulty reads it as one of the levels, so recipes can be filtered by it.
+type Difficulty string
+
+const (
+	// DifficultyUnknown is a missing difficulty, or one that is not a known level.
+	DifficultyUnknown Difficulty = ""
+	DifficultyEasy    Difficulty = "easy"
+	DifficultyMedium  Difficulty = "medium"
+	DifficultyHard    Difficulty = "hard"
+)
+
+// Difficulties are the difficulty levels, from easy to hard.
+var Difficulties = []Difficulty{DifficultyEasy, DifficultyMedium, DifficultyHard}
+
+// difficultyNames are the names of each level, in English and in the languages of
+// the renderer locales (da, de, es, fr, it, nl, sv), lowercased.
+var difficultyNames = map[Difficulty][]string{
+	DifficultyEasy: {
+		"easy", "simple", "beginner", "basic",
+		"let", "nem", "leicht", "einfach", "fácil", "facil", "sencillo",
+		"facile", "makkelijk", "eenvoudig", "lätt", "enkel",
+	},
+	DifficultyMedium: {
+		"medium", "moderate", "intermediate", "average",
+		"mellem", "mittel", "mittelschwer", "media", "medio",
+		"moyen", "moyenne", "gemiddeld", "medel", "medelsvår",
+	},
+	DifficultyHard: {
+		"hard", "difficult", "advanced", "challenging", "expert",
+		"svær", "schwer", "schwierig", "difícil", "dificil",
+		"difficile", "moeilijk", "svår",
+	},
+}
+
+// ParseDifficulty reads a difficulty such as "Easy", "advanced" or "schwierig" as a
+// level. Matching is case-insensitive and accepts English synonyms and the names
+// used by the renderer locales; "" is DifficultyUnknown.
+//
+// Parameters:
+//   - text: The difficulty text
+//
+// Returns:
+//   - Difficulty: The level
+//   - error: An error if the text is not a known difficulty
+//
+// Example:
+//
+//	level, err := cooklang.ParseDifficulty("Intermediate") // DifficultyMedium
+func ParseDifficulty(text string) (Difficulty, error) {
+	key := strings.ToLower(strings.TrimSpace(text))
+	if key == "" {
+		return DifficultyUnknown, nil
+	}
+	for _, level := range Difficulties {
+		if slices.Contains(difficultyNames[level], key) {
+			return level, nil
+		}
+	}
+	return DifficultyUnknown, fmt.Errorf("unknown difficulty: %s (use easy, medium or hard)", text)
+}
+
+// DifficultyLevel returns the recipe's difficulty metadata as a level, or
+// DifficultyUnknown if it is missing or not a known difficulty.
+func (r *Recipe) DifficultyLevel() Difficulty {
+	level, _ := ParseDifficulty(r.Difficulty)
+	return level
+}
@@ -0,0 +1,60 @@
+package cooklang
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseDifficulty(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Difficulty
+		ok    bool
+	}{
+		{"easy", DifficultyEasy, true},
+		{" Simple ", DifficultyEasy, true},
+		{"Intermediate", DifficultyMedium, true},
+		{"moyen", DifficultyMedium, true},
+		{"Schwierig", DifficultyHard, true},
+		{"svår", DifficultyHard, true},
+		{"", DifficultyUnknown, true},
+		{"tricky", DifficultyUnknown, false},
+	}
+	for _, tt := range tests {
+		got, err := ParseDifficulty(tt.input)
+		if got != tt.want || (err == nil) != tt.ok {
+			t.Errorf("ParseDifficulty(%q) = %q, %v; want %q, ok %v", tt.input, got, err, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestSetMetadataValidatesDifficulty(t *testing.T) {
+	editor, err := NewFrontmatterEditorFromBytes([]byte("---\ntitle: Bread\n---\nBake the @dough{}.\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := editor.SetMetadata("difficulty", "tricky"); err == nil || !strings.Contains(err.Error(), "not a valid difficulty") {
+		t.Errorf("expected an error for an unknown difficulty, got %v", err)
+	}
+	if err := editor.SetMetadata("difficulty", "Advanced"); err != nil {
+		t.Fatalf("SetMetadata: %v", err)
+	}
+	if level := editor.recipe.DifficultyLevel(); level != DifficultyHard {
+		t.Errorf("DifficultyLevel() = %q, want %q", level, DifficultyHard)
+	}
+}
+
+func TestLibraryDifficulty(t *testing.T) {
+	library := newSearchLibrary(t)
+
+	if entries := library.ByDifficulty(DifficultyEasy); len(entries) != 2 {
+		t.Errorf("expected two easy recipes, got %d", len(entries))
+	}
+	entries := library.Filter(LibraryFilter{Tags: []string{"cocktail"}, Difficulties: []Difficulty{DifficultyHard}})
+	if len(entries) != 1 || entries[0].Name() != "Dry Martini" {
+		t.Errorf("expected Dry Martini, got %v", entries)
+	}
+	if entries := library.ByDifficulty(DifficultyUnknown); len(entries) != 0 {
+		t.Errorf("recipes without a difficulty should not be indexed, got %d", len(entries))
+	}
+}
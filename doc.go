@@ -90,11 +90,11 @@
 //	recipe, _ := cooklang.ParseFile("recipe.cook")
 //
 //	// Render as Markdown
-//	markdown := recipe.RenderWith(renderers.MarkdownRenderer{})
+//	markdown, _ := recipe.RenderWith(renderers.Bind(renderers.MarkdownRenderer{}, renderers.RendererOptions{}))
 //	fmt.Println(markdown)
 //
-//	// Render as HTML
-//	html := recipe.RenderWith(renderers.HTMLRenderer{})
+//	// Render as HTML, with German headings
+//	html, err := recipe.RenderWith(renderers.Bind(renderers.HTMLRenderer{}, renderers.RendererOptions{Locale: "de"}))
 //
 //	// Set a custom renderer
 //	recipe.SetRenderer(renderers.CooklangRenderer{})
@@ -107,14 +107,12 @@
 // efficient traversal and manipulation:
 //
 //	// Walk through all steps
-//	currentStep := recipe.FirstStep
 //	stepNum := 1
-//	for currentStep != nil {
+//	for step := range recipe.Steps() {
 //	    fmt.Printf("Step %d:\n", stepNum)
 //
 //	    // Walk through components in this step
-//	    component := currentStep.FirstComponent
-//	    for component != nil {
+//	    for component := range step.Components() {
 //	        switch c := component.(type) {
 //	        case *cooklang.Ingredient:
 //	            fmt.Printf("  Add %s (%.1f %s)\n", c.Name, c.Quantity, c.Unit)
@@ -125,13 +123,14 @@
 //	        case *cooklang.Cookware:
 //	            fmt.Printf("  Using: %s\n", c.Name)
 //	        }
-//	        component = component.GetNext()
 //	    }
-//
-//	    currentStep = currentStep.NextStep
 //	    stepNum++
 //	}
 //
+// The FirstStep/NextStep and FirstComponent/GetNext links remain available for code
+// that edits the lists. WalkComponents, WalkIngredients and WalkCookware visit
+// components with a callback that can stop the walk early.
+//
 // # Cooklang Syntax
 //
 // The parser supports standard Cooklang syntax:
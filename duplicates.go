@@ -0,0 +1,172 @@
+package cooklang
+
+import (
+	"crypto/sha256"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// DuplicateOptions controls Library.FindDuplicatesWithOptions.
+type DuplicateOptions struct {
+	// IngredientSimilarity is the Jaccard similarity of two recipes' ingredient sets
+	// (shared ingredients divided by all ingredients of both) from which they count
+	// as duplicates. Default 0.8.
+	IngredientSimilarity float64
+	// MinIngredients is how many different ingredients both recipes need for their
+	// ingredient sets to be compared, so that two-ingredient drinks are not all
+	// duplicates of each other. Default 3.
+	MinIngredients int
+}
+
+// DefaultDuplicateOptions returns the options FindDuplicates uses.
+//
+// Returns:
+//   - DuplicateOptions: An ingredient similarity of 0.8 with at least 3 ingredients
+func DefaultDuplicateOptions() DuplicateOptions {
+	return DuplicateOptions{IngredientSimilarity: 0.8, MinIngredients: 3}
+}
+
+// Duplicate is a pair of library recipes that look like the same recipe.
+type Duplicate struct {
+	A                    *LibraryEntry `json:"a"`
+	B                    *LibraryEntry `json:"b"`
+	IngredientSimilarity float64       `json:"ingredient_similarity"` // Jaccard similarity of the ingredient sets, 0 to 1
+	SameSteps            bool          `json:"same_steps"`            // The steps read the same, ignoring case, punctuation, whitespace and quantities
+}
+
+// FindDuplicates finds recipes that look like the same recipe, e.g. one imported
+// twice under different names, using DefaultDuplicateOptions. See
+// FindDuplicatesWithOptions.
+//
+// Returns:
+//   - []Duplicate: The pairs of duplicates, most similar first
+//
+// Example:
+//
+//	for _, d := range library.FindDuplicates() {
+//	    fmt.Printf("%s ~ %s (%.0f%%)\n", d.A.Path, d.B.Path, d.IngredientSimilarity*100)
+//	}
+func (l *Library) FindDuplicates() []Duplicate {
+	return l.FindDuplicatesWithOptions(DefaultDuplicateOptions())
+}
+
+// FindDuplicatesWithOptions finds pairs of recipes whose ingredient sets are at
+// least opts.IngredientSimilarity alike, or whose step text is the same once
+// lowercased and stripped of punctuation, whitespace and quantities. Ingredient
+// names are compared case-insensitively.
+//
+// Parameters:
+//   - opts: The similarity threshold and minimum number of ingredients; zero
+//     fields take the defaults
+//
+// Returns:
+//   - []Duplicate: The pairs of duplicates, with A before B in path order, sorted
+//     by same steps first, then by ingredient similarity, then by path
+func (l *Library) FindDuplicatesWithOptions(opts DuplicateOptions) []Duplicate {
+	defaults := DefaultDuplicateOptions()
+	if opts.IngredientSimilarity <= 0 {
+		opts.IngredientSimilarity = defaults.IngredientSimilarity
+	}
+	if opts.MinIngredients <= 0 {
+		opts.MinIngredients = defaults.MinIngredients
+	}
+
+	type fingerprint struct {
+		ingredients map[string]bool
+		steps       [sha256.Size]byte
+		hasSteps    bool
+	}
+	prints := make([]fingerprint, len(l.Entries))
+	for i, entry := range l.Entries {
+		if entry.Recipe == nil {
+			continue
+		}
+		prints[i].ingredients = map[string]bool{}
+		for _, ingredient := range entry.Recipe.GetIngredients().Ingredients {
+			if key := libraryKey(ingredient.Name); key != "" {
+				prints[i].ingredients[key] = true
+			}
+		}
+		if text := normalizedStepText(entry.Recipe); text != "" {
+			prints[i].steps = sha256.Sum256([]byte(text))
+			prints[i].hasSteps = true
+		}
+	}
+
+	var duplicates []Duplicate
+	for i := range l.Entries {
+		for j := i + 1; j < len(l.Entries); j++ {
+			a, b := prints[i], prints[j]
+			similarity := jaccard(a.ingredients, b.ingredients)
+			sameSteps := a.hasSteps && b.hasSteps && a.steps == b.steps
+			similar := len(a.ingredients) >= opts.MinIngredients && len(b.ingredients) >= opts.MinIngredients &&
+				similarity >= opts.IngredientSimilarity
+			if sameSteps || similar {
+				duplicates = append(duplicates, Duplicate{
+					A:                    l.Entries[i],
+					B:                    l.Entries[j],
+					IngredientSimilarity: similarity,
+					SameSteps:            sameSteps,
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(duplicates, func(i, j int) bool {
+		if duplicates[i].SameSteps != duplicates[j].SameSteps {
+			return duplicates[i].SameSteps
+		}
+		return duplicates[i].IngredientSimilarity > duplicates[j].IngredientSimilarity
+	})
+	return duplicates
+}
+
+// jaccard returns the size of the intersection of two sets divided by the size of
+// their union, or 0 if both are empty.
+func jaccard(a, b map[string]bool) float64 {
+	shared := 0
+	for key := range a {
+		if b[key] {
+			shared++
+		}
+	}
+	union := len(a) + len(b) - shared
+	if union == 0 {
+		return 0
+	}
+	return float64(shared) / float64(union)
+}
+
+// normalizedStepText returns a recipe's instructions as lowercase words separated
+// by single spaces, without punctuation, quantities, comments, notes or section
+// names.
+func normalizedStepText(r *Recipe) string {
+	words := strings.FieldsFunc(strings.ToLower(stepPlainText(r)), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(words, " ")
+}
+
+// stepPlainText returns the text of a recipe's steps as read aloud: instruction
+// text, ingredient and cookware names and timer durations, without quantities,
+// comments, notes or section names.
+func stepPlainText(r *Recipe) string {
+	var text strings.Builder
+	for step := range r.Steps() {
+		for component := range step.Components() {
+			switch c := component.(type) {
+			case *Instruction:
+				text.WriteString(c.Text)
+			case *Ingredient:
+				text.WriteString(c.Name)
+			case *Cookware:
+				text.WriteString(c.Name)
+			case *Timer:
+				text.WriteString(strings.TrimSpace(c.Duration + " " + c.Unit))
+			}
+		}
+		text.WriteByte('\n')
+	}
+	return text.String()
+}
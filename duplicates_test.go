@@ -0,0 +1,56 @@
+package cooklang
+
+import (
+	"testing"
+)
+
+func TestLibraryFindDuplicates(t *testing.T) {
+	library := NewLibrary()
+	for path, content := range map[string]string{
+		"pancakes.cook":          "Whisk @flour{200%g}, @milk{300%ml}, @eggs{2} and @sugar{1%tbsp}.\n\nFry in a #pan{} for ~{2%minutes}.",
+		"imported/Pancakes.cook": "WHISK @Flour{1%cup}, @milk{1%cup}, @eggs{2} and @sugar{1%tbsp}!\n\n-- from the web\nFry in a #pan{}   for ~{2%minutes}.",
+		"crepes.cook":            "Whisk @flour{100%g}, @milk{300%ml}, @eggs{3}, @sugar{1%tsp} and @butter{20%g}. Rest, then fry thinly.",
+		"gin-tonic.cook":         "Pour @gin{50%ml} over ice and top with @tonic{150%ml}.",
+		"gin-tonic-v2.cook":      "Top @gin{40%ml} with @tonic{120%ml} and a lime wedge.",
+		"soup.cook":              "Simmer @tomatoes{6}, @onion{1} and @stock{1%l}.",
+	} {
+		recipe, err := ParseString(content)
+		if err != nil {
+			t.Fatal(err)
+		}
+		library.Add(path, recipe)
+	}
+
+	duplicates := library.FindDuplicates()
+	if len(duplicates) != 3 {
+		t.Fatalf("expected 3 pairs, got %d: %+v", len(duplicates), duplicates)
+	}
+	first := duplicates[0]
+	if first.A.Path != "imported/Pancakes.cook" || first.B.Path != "pancakes.cook" || !first.SameSteps || first.IngredientSimilarity != 1 {
+		t.Errorf("expected the imported pancakes first with the same steps, got %s ~ %s %+v", first.A.Path, first.B.Path, first)
+	}
+	for _, d := range duplicates[1:] {
+		if d.SameSteps || d.IngredientSimilarity != 0.8 || d.B.Path == "gin-tonic-v2.cook" {
+			t.Errorf("expected the crêpes at 0.8 similarity, got %s ~ %s %+v", d.A.Path, d.B.Path, d)
+		}
+	}
+
+	strict := library.FindDuplicatesWithOptions(DuplicateOptions{IngredientSimilarity: 0.9})
+	if len(strict) != 1 {
+		t.Errorf("expected only the same steps at 0.9, got %d pairs", len(strict))
+	}
+	loose := library.FindDuplicatesWithOptions(DuplicateOptions{MinIngredients: 2})
+	if len(loose) != 4 {
+		t.Errorf("expected the gin and tonics with 2 ingredients, got %d pairs", len(loose))
+	}
+}
+
+func TestNormalizedStepText(t *testing.T) {
+	recipe, err := ParseString("== Batter ==\nWhisk @flour{200%g} in a #bowl{}, -- quietly\nthen rest ~{10%min}.\n\n> A note.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := normalizedStepText(recipe), "whisk flour in a bowl then rest 10 min"; got != want {
+		t.Errorf("normalizedStepText = %q, want %q", got, want)
+	}
+}
@@ -1,6 +1,7 @@
 package cooklang_test
 
 import (
+	"encoding/json"
 	"fmt"
 	"log"
 	"sort"
@@ -50,6 +51,24 @@ Cook in a #pan{} over medium heat for ~{3%minutes}.`)
 	// Quick Omelette
 }
 
+// ExampleParseOption demonstrates configuring the parser with options
+func ExampleParseOption() {
+	units := cooklang.CustomUnits{"scoop": {Amount: 60, Unit: "g"}}
+	recipe, err := cooklang.ParseString("Serve @ice cream{2%scoop}. -- or sorbet",
+		cooklang.WithExtendedMode(),
+		cooklang.WithUnitRegistry(units),
+		cooklang.WithMaxSize(64*1024),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	grams, _ := recipe.GetIngredients().Ingredients[0].ConvertTo("g")
+	fmt.Printf("%s %s\n", grams.Quantity, grams.Unit)
+	// Output:
+	// 120 g
+}
+
 // ExampleRecipe_GetIngredients shows how to extract all ingredients from a recipe
 func ExampleRecipe_GetIngredients() {
 	recipeText := `Mix @flour{200%g}, @sugar{150%g}, and @butter{100%g}.
@@ -64,9 +83,9 @@ Add @eggs{2} and @vanilla{1%tsp}.`
 	fmt.Printf("Found %d ingredients:\n", len(ingredients.Ingredients))
 	for _, ing := range ingredients.Ingredients {
 		if ing.Unit != "" {
-			fmt.Printf("- %s: %.0f %s\n", ing.Name, ing.Quantity, ing.Unit)
+			fmt.Printf("- %s: %s %s\n", ing.Name, ing.Quantity, ing.Unit)
 		} else {
-			fmt.Printf("- %s: %.0f\n", ing.Name, ing.Quantity)
+			fmt.Printf("- %s: %s\n", ing.Name, ing.Quantity)
 		}
 	}
 	// Output:
@@ -118,8 +137,8 @@ func ExampleIngredient_ConvertTo() {
 		log.Fatal(err)
 	}
 
-	fmt.Printf("Original: %.0f %s\n", water.Quantity, water.Unit)
-	fmt.Printf("Converted: %.2f %s\n", converted.Quantity, converted.Unit)
+	fmt.Printf("Original: %s %s\n", water.Quantity, water.Unit)
+	fmt.Printf("Converted: %.2f %s\n", converted.Quantity.Float(), converted.Unit)
 	// Output:
 	// Original: 500 ml
 	// Converted: 2.11 cup
@@ -162,7 +181,7 @@ func ExampleIngredientList_ConvertToSystem() {
 
 	fmt.Println("Metric ingredients:")
 	for _, ing := range metric.Ingredients {
-		fmt.Printf("- %s: %.0f %s\n", ing.Name, ing.Quantity, ing.Unit)
+		fmt.Printf("- %s: %.0f %s\n", ing.Name, ing.Quantity.Float(), ing.Unit)
 	}
 	// Output:
 	// Metric ingredients:
@@ -195,7 +214,7 @@ Add @sugar{50%g} for sweetness.`
 
 	fmt.Println("Consolidated ingredients:")
 	for _, ing := range consolidated.Ingredients {
-		fmt.Printf("- %s: %.0f %s\n", ing.Name, ing.Quantity, ing.Unit)
+		fmt.Printf("- %s: %s %s\n", ing.Name, ing.Quantity, ing.Unit)
 	}
 	// Output:
 	// Consolidated ingredients:
@@ -226,9 +245,9 @@ Second layer: @cheese{150%g} and @tomato{3}.`
 	fmt.Println("Shopping list:")
 	for _, ing := range collected.Ingredients {
 		if ing.Unit != "" {
-			fmt.Printf("- %s: %.0f %s\n", ing.Name, ing.Quantity, ing.Unit)
+			fmt.Printf("- %s: %s %s\n", ing.Name, ing.Quantity, ing.Unit)
 		} else {
-			fmt.Printf("- %s: %.0f\n", ing.Name, ing.Quantity)
+			fmt.Printf("- %s: %s\n", ing.Name, ing.Quantity)
 		}
 	}
 	// Output:
@@ -328,6 +347,38 @@ func ExampleShoppingList_Scale() {
 	// - sugar: 200 g
 }
 
+// ExampleShoppingList_SortedItems demonstrates an ordered shopping list grouped by aisle
+func ExampleShoppingList_SortedItems() {
+	pasta, _ := cooklang.ParseString(`---
+title: Pasta
+---
+Cook @pasta{400%g}. Fry @garlic{2%cloves}(sliced) in @olive oil{2%tbsp}, toss and top with @parmesan{50%g}.`)
+	salad, _ := cooklang.ParseString(`---
+title: Salad
+---
+Toss @lettuce{1} with @olive oil{3%tbsp}.`)
+
+	shoppingList, _ := cooklang.CreateShoppingList(pasta, salad)
+
+	aisle := ""
+	for _, item := range shoppingList.SortedItems(cooklang.OrderByAisle) {
+		if item.Aisle != aisle {
+			aisle = item.Aisle
+			fmt.Printf("%s:\n", aisle)
+		}
+		fmt.Printf("- %s %v %v\n", item, item.SourceRecipes, item.Notes)
+	}
+	// Output:
+	// Produce:
+	// - garlic (2 cloves) [Pasta] [sliced]
+	// - lettuce (1) [Salad] []
+	// Dairy & Eggs:
+	// - parmesan (50 g) [Pasta] []
+	// Pantry:
+	// - olive oil (5 tbsp) [Pasta Salad] []
+	// - pasta (400 g) [Pasta] []
+}
+
 // ExampleRecipe_GetMetricShoppingList shows getting a shopping list in metric units
 func ExampleRecipe_GetMetricShoppingList() {
 	recipeText := `Add @flour{2%cup} and @butter{4%oz}.`
@@ -520,6 +571,44 @@ Rest for ~cooling{5%minutes}.`
 	// Total timers: 2
 }
 
+// ExampleTimer_ParsedDuration demonstrates converting timers to time.Duration
+// and totalling the timers of a recipe
+func ExampleTimer_ParsedDuration() {
+	recipe, _ := cooklang.ParseString(`Boil ~pasta{10%minutes} while frying ~sauce{5%min}.
+
+Rest for ~{1-2%hours}.`)
+
+	for _, timer := range recipe.GetTimers() {
+		low, high, _ := timer.DurationRange()
+		fmt.Printf("%s: %v to %v\n", timer.RenderDisplay(), low, high)
+	}
+	fmt.Println("All timers:", recipe.TotalTimerTime())
+	fmt.Println("Active time:", recipe.TotalActiveTime())
+	// Output:
+	// 10 minutes: 10m0s to 10m0s
+	// 5 min: 5m0s to 5m0s
+	// 1-2 hours: 1h0m0s to 2h0m0s
+	// All timers: 1h15m0s
+	// Active time: 1h10m0s
+}
+
+// ExampleRecipe_ConvertTemperatures converts the temperatures in a recipe's steps
+// between Celsius and Fahrenheit
+func ExampleRecipe_ConvertTemperatures() {
+	recipe, _ := cooklang.ParseString(`Preheat the #oven{} to 180°C.
+
+Fry in @oil{} at 170-180 °C, then cool to 4°C.`)
+
+	us := recipe.ConvertTemperatures(cooklang.UnitSystemUS)
+	for i, t := range us.GetTemperatures() {
+		fmt.Printf("%s -> %s\n", recipe.GetTemperatures()[i].Render(), t.RenderDisplay())
+	}
+	// Output:
+	// 180°C -> 355°F
+	// 170-180 °C -> 340-355°F
+	// 4°C -> 39°F
+}
+
 // ExampleCookware demonstrates working with cookware items
 func ExampleCookware() {
 	recipeText := `Use a #large pot{} and #wooden spoons{2}.`
@@ -559,9 +648,9 @@ Mix @flour{200%g} with @milk{300%ml} and @eggs{2}.`
 	ingredients := doubled.GetIngredients()
 	for _, ing := range ingredients.Ingredients {
 		if ing.Unit != "" {
-			fmt.Printf("- %s: %.0f %s\n", ing.Name, ing.Quantity, ing.Unit)
+			fmt.Printf("- %s: %s %s\n", ing.Name, ing.Quantity, ing.Unit)
 		} else {
-			fmt.Printf("- %s: %.0f\n", ing.Name, ing.Quantity)
+			fmt.Printf("- %s: %s\n", ing.Name, ing.Quantity)
 		}
 	}
 	// Output:
@@ -572,6 +661,73 @@ Mix @flour{200%g} with @milk{300%ml} and @eggs{2}.`
 	// - eggs: 4
 }
 
+// ExampleRecipe_Clone demonstrates editing a copy of a recipe without changing the original
+func ExampleRecipe_Clone() {
+	recipe, err := cooklang.ParseString("Add @sugar{100%g} to the #bowl{}.")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	lessSweet := recipe.Clone()
+	sugar := lessSweet.FirstStep.FirstComponent.GetNext().(*cooklang.Ingredient)
+	sugar.Quantity = cooklang.NewQuantity(60, 1)
+
+	original := recipe.FirstStep.FirstComponent.GetNext().(*cooklang.Ingredient)
+	fmt.Println(original.Quantity, sugar.Quantity)
+	fmt.Println(recipe.Equal(lessSweet), recipe.Equal(recipe.Clone()))
+	// Output:
+	// 100 60
+	// false true
+}
+
+// ExampleRecipe_Steps demonstrates ranging over steps and components
+func ExampleRecipe_Steps() {
+	recipe, err := cooklang.ParseString("Crack @eggs{2} into a #bowl{}.\n\nWhisk in @milk{100%ml}.")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for step := range recipe.Steps() {
+		for component := range step.Components() {
+			if ingredient, ok := component.(*cooklang.Ingredient); ok {
+				fmt.Println(ingredient.Name)
+			}
+		}
+	}
+
+	recipe.WalkCookware(func(cw *cooklang.Cookware) bool {
+		fmt.Println("uses", cw.Name)
+		return true
+	})
+	// Output:
+	// eggs
+	// milk
+	// uses bowl
+}
+
+// ExampleFromJSON demonstrates storing a recipe as JSON and reading it back
+func ExampleFromJSON() {
+	recipe, err := cooklang.ParseString("Add @sugar{100%g} to the #bowl{}.")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	data, err := json.Marshal(recipe)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	restored, err := cooklang.FromJSON(data)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(restored.Equal(recipe))
+	fmt.Println(restored.GetIngredients().Ingredients[0].Name)
+	// Output:
+	// true
+	// sugar
+}
+
 // ExampleRecipe_ScaleToServings demonstrates scaling a recipe to target servings
 func ExampleRecipe_ScaleToServings() {
 	recipeText := `---
@@ -590,7 +746,7 @@ Mix @flour{300%g}, @sugar{150%g}, and @butter{100%g}.`
 
 	ingredients := scaled.GetIngredients()
 	for _, ing := range ingredients.Ingredients {
-		fmt.Printf("- %s: %.0f %s\n", ing.Name, ing.Quantity, ing.Unit)
+		fmt.Printf("- %s: %s %s\n", ing.Name, ing.Quantity, ing.Unit)
 	}
 	// Output:
 	// Original: 12 servings
@@ -637,3 +793,185 @@ Serve @rice{150%g} with @olive oil{1%tbsp}.`
 	// - pasta: 400 g
 	// - rice: 600 g
 }
+
+// ExampleLibrary_Filter demonstrates building a library from recipes and
+// filtering it by tag and ingredient
+func ExampleLibrary_Filter() {
+	negroni, _ := cooklang.ParseString(`---
+title: Negroni
+tags: gin, bitter
+---
+Stir @gin{30%ml}, @vermouth{30%ml} and @Campari{30%ml}.`)
+
+	gimlet, _ := cooklang.ParseString(`---
+title: Gimlet
+tags: gin, sour
+---
+Shake @gin{60%ml} with @lime cordial{20%ml}.`)
+
+	library := cooklang.NewLibrary()
+	library.Add("Negroni.cook", negroni)
+	library.Add("Gimlet.cook", gimlet)
+
+	for _, entry := range library.Filter(cooklang.LibraryFilter{
+		Tags:        []string{"gin"},
+		Ingredients: []string{"lime cordial"},
+	}) {
+		fmt.Println(entry.Name(), "-", entry.Path)
+	}
+	fmt.Println("Tags:", library.Tags())
+	// Output:
+	// Gimlet - Gimlet.cook
+	// Tags: [bitter gin sour]
+}
+
+// ExampleFormat rewrites a recipe in the normalized Cooklang style used by cook fmt
+func ExampleFormat() {
+	src := "---\nservings: 2\ntitle: Toast\n---\nToast  @bread{ 2 % slices }\nuntil golden.\n\n\n\nSpread @butter{0.5%tbsp} on top.\n"
+
+	formatted, err := cooklang.Format([]byte(src), cooklang.FormatOptions{
+		QuantityStyle: cooklang.QuantityStyleFraction,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Print(string(formatted))
+	// Output:
+	// ---
+	// title: Toast
+	// servings: 2
+	// ---
+	//
+	// Toast @bread{2%slices} until golden.
+	//
+	// Spread @butter{1/2%tbsp} on top.
+}
+
+// ExampleSourcePosition maps parsed ingredients back to where they are written
+// in the recipe source
+func ExampleSourcePosition() {
+	source := "Boil @water{1%l}.\n\nAdd @pasta{500%g}."
+	recipe, err := cooklang.ParseString(source)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for _, ingredient := range recipe.GetIngredients().Ingredients {
+		pos := ingredient.Position
+		fmt.Printf("%s at %s: %s\n", ingredient.Name, pos, source[pos.Offset:pos.EndOffset])
+	}
+	// Output:
+	// water at 1:6: @water{1%l}
+	// pasta at 3:5: @pasta{500%g}
+}
+
+// ExampleMetadata_GetDuration demonstrates reading typed metadata values and
+// validating metadata against a schema.
+func ExampleMetadata_GetDuration() {
+	recipe, err := cooklang.ParseString(`---
+title: Lasagna
+servings: 6
+prep_time: 1 hour 15 minutes
+tags: italian, pasta
+rating: excellent
+---
+Layer @pasta sheets{12} with @ragu{1%l}.`)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	prep, _ := recipe.Metadata.GetDuration("prep_time")
+	servings, _ := recipe.Metadata.GetFloat("servings")
+	fmt.Println("Prep:", prep)
+	fmt.Println("Servings:", servings)
+	fmt.Println("Tags:", recipe.Metadata.GetStringSlice("tags"))
+
+	schema := cooklang.DefaultMetadataSchema()
+	schema.Fields["rating"] = cooklang.MetadataInt
+	fmt.Println(schema.Validate(recipe.Metadata))
+	// Output:
+	// Prep: 1h15m0s
+	// Servings: 6
+	// Tags: [italian pasta]
+	// metadata "rating": "excellent" is not a valid integer
+}
+
+// ExampleRecipeTemplate_Scaffold demonstrates creating a new recipe file from a
+// built-in template.
+func ExampleRecipeTemplate_Scaffold() {
+	template, _ := cooklang.FindRecipeTemplate("cocktail", cooklang.BuiltinRecipeTemplates())
+	content, err := template.Scaffold(cooklang.ScaffoldData{
+		Title:  "Gin Fizz",
+		Tags:   []string{"gin", "summer"},
+		Author: "Sam",
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println(cooklang.FilenameFromTitle("Gin Fizz"))
+	fmt.Print(content)
+	// Output:
+	// Gin Fizz.cook
+	// ---
+	// title: Gin Fizz
+	// author: Sam
+	// servings: 1
+	// tags:
+	//   - gin
+	//   - summer
+	// course: drink
+	// ---
+	// Pour @gin{50%ml} and @tonic water{150%ml} into a #highball glass{} filled with @ice{}.
+	//
+	// Garnish with a @lime wedge{1}.
+}
+
+// ExampleParseAisleConfig groups a shopping list by the aisles of a store.
+func ExampleParseAisleConfig() {
+	recipe, _ := cooklang.ParseString("Mash @potatoes{1%kg} with @butter{50%g}, @milk{100%ml} and @nutmeg{}.")
+	list, _ := cooklang.CreateShoppingList(recipe)
+	list.Aisles, _ = cooklang.ParseAisleConfig(`
+[vegetables]
+potatoes|potato
+
+[fridge]
+milk
+butter
+`)
+
+	aisle := ""
+	for _, item := range list.SortedItems(cooklang.OrderByAisle) {
+		if item.Aisle != aisle {
+			aisle = item.Aisle
+			fmt.Printf("%s:\n", aisle)
+		}
+		fmt.Printf("  %s\n", item)
+	}
+	// Output:
+	// vegetables:
+	//   potatoes (1 kg)
+	// fridge:
+	//   butter (50 g)
+	//   milk (100 ml)
+	// Other:
+	//   nutmeg
+}
+
+// ExampleFormatQuantity writes quantities as fractions, in ASCII or Unicode.
+func ExampleFormatQuantity() {
+	unicode := cooklang.FormatOptions{UnicodeFractions: true, MaxDenominator: 16}
+	for _, value := range []float64{0.5, 1.5, 2.75, 1.0 / 3.0, 0.3125} {
+		fmt.Printf("%-6.4g %-6s %s\n", value, cooklang.FormatQuantity(value, cooklang.FormatOptions{}), cooklang.FormatQuantity(value, unicode))
+	}
+
+	// Values without a close fraction are written as decimals
+	fmt.Println(cooklang.FormatQuantity(0.4, cooklang.FormatOptions{MaxDenominator: 4}))
+	// Output:
+	// 0.5    1/2    ½
+	// 1.5    1 1/2  1½
+	// 2.75   2 3/4  2¾
+	// 0.3333 1/3    ⅓
+	// 0.3125 5/16   ⁵⁄₁₆
+	// 0.4
+}
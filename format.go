@@ -0,0 +1,338 @@
+package cooklang
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hilli/cooklang/parser"
+)
+
+// FormatOptions controls how Format normalizes a recipe and how FormatQuantity
+// writes a number. Format uses QuantityStyle; FormatQuantity uses the others.
+type FormatOptions struct {
+	// QuantityStyle selects how ingredient quantities are written. The zero value,
+	// QuantityStyleAuto, writes decimals where they are exact (0.5) and fractions
+	// otherwise (1/3). Quantities are only rewritten when the new form has exactly the
+	// same value, so QuantityStyleDecimal never turns 1/3 into 0.333.
+	QuantityStyle QuantityStyle
+
+	// UnicodeFractions writes fractions as vulgar fraction characters ("1½", "¾")
+	// instead of ASCII ("1 1/2", "3/4"). Fractions without their own character are
+	// written with superscript and subscript digits ("⁵⁄₁₆").
+	UnicodeFractions bool
+	// MaxDenominator is the largest denominator to write a fraction with; values
+	// that need a larger one are written as decimals. Zero means DefaultMaxDenominator.
+	MaxDenominator int
+	// Tolerance is how close a value must be to a fraction to be written as one.
+	// Zero means DefaultFractionTolerance.
+	Tolerance float64
+}
+
+// frontmatterKeyOrder is the order of well-known frontmatter keys in formatted
+// recipes. Other keys follow in alphabetical order.
+var frontmatterKeyOrder = []string{
+	"title", "cuisine", "description", "difficulty", "author", "source", "date",
+	"servings", "prep_time", "cook_time", "total_time", "tags", "images", "image", "units",
+}
+
+// Format rewrites Cooklang source in a normalized style:
+//   - frontmatter keys in a consistent order (title, cuisine, description, ... then
+//     the remaining keys alphabetically), with "key: value" spacing
+//   - each step on one line, steps separated by exactly one blank line
+//   - runs of spaces and tabs collapsed, trailing whitespace removed
+//   - ingredient quantities written in the chosen QuantityStyle, and no stray spaces
+//     inside {} (so @flour{ 1/2 % cup } becomes @flour{0.5%cup})
+//   - sections written as "== Name ==", notes as "> text" and comments as "-- text"
+//
+// Formatting does not change what the recipe means: it parses to the same steps,
+// ingredients, cookware and timers. Block comments are left as they are.
+//
+// Parameters:
+//   - src: The Cooklang source
+//   - opts: Formatting options
+//
+// Returns:
+//   - []byte: The formatted source, ending in a single newline
+//   - error: An error if src cannot be parsed
+//
+// Example:
+//
+//	src, _ := os.ReadFile("Pancakes.cook")
+//	formatted, err := cooklang.Format(src, cooklang.FormatOptions{QuantityStyle: cooklang.QuantityStyleFraction})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	os.WriteFile("Pancakes.cook", formatted, 0644)
+func Format(src []byte, opts FormatOptions) ([]byte, error) {
+	p := parser.New()
+	p.ExtendedMode = true
+	if _, err := p.ParseBytes(src); err != nil {
+		return nil, fmt.Errorf("failed to parse recipe: %w", err)
+	}
+
+	text := strings.ReplaceAll(string(src), "\r\n", "\n")
+	frontmatter, body, err := splitFrontmatter(text)
+	if err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	if frontmatter != nil {
+		out.WriteString("---\n")
+		for _, line := range formatFrontmatter(frontmatter) {
+			out.WriteString(line + "\n")
+		}
+		out.WriteString("---\n")
+	}
+
+	paragraphs := formatBody(body, opts)
+	if len(paragraphs) > 0 && frontmatter != nil {
+		out.WriteString("\n")
+	}
+	out.WriteString(strings.Join(paragraphs, "\n\n"))
+	if len(paragraphs) > 0 {
+		out.WriteString("\n")
+	}
+	return out.Bytes(), nil
+}
+
+// splitFrontmatter splits source into its frontmatter lines (nil if there is no
+// frontmatter) and the recipe body.
+func splitFrontmatter(text string) ([]string, string, error) {
+	if !strings.HasPrefix(text, "---\n") && strings.TrimRight(text, " \t") != "---" {
+		return nil, text, nil
+	}
+	lines := strings.Split(text, "\n")
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimRight(lines[i], " \t") == "---" {
+			return lines[1:i], strings.Join(lines[i+1:], "\n"), nil
+		}
+	}
+	return nil, "", fmt.Errorf("frontmatter is not closed with ---")
+}
+
+// frontmatterEntry is a top-level frontmatter key with its value lines and the
+// comments directly above it.
+type frontmatterEntry struct {
+	key   string
+	lines []string
+}
+
+// formatFrontmatter orders the top-level keys of the frontmatter and normalizes the
+// spacing of "key: value" lines. Nested values are kept as written.
+func formatFrontmatter(lines []string) []string {
+	var entries []frontmatterEntry
+	var pending []string // comments and blank lines waiting for the next key
+	for _, line := range lines {
+		line = strings.TrimRight(line, " \t")
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "" || (strings.HasPrefix(line, "#") && line == trimmed):
+			if trimmed != "" {
+				pending = append(pending, line)
+			}
+		case line == trimmed && !strings.HasPrefix(line, "-") && strings.Contains(line, ":"):
+			key, value, _ := strings.Cut(line, ":")
+			key = strings.TrimSpace(key)
+			line = key + ":"
+			if value = strings.TrimSpace(value); value != "" {
+				line += " " + value
+			}
+			entries = append(entries, frontmatterEntry{key: key, lines: append(pending, line)})
+			pending = nil
+		case len(entries) > 0:
+			// Nested value (indented lines or "- item" lists) of the previous key
+			last := &entries[len(entries)-1]
+			last.lines = append(last.lines, pending...)
+			last.lines = append(last.lines, line)
+			pending = nil
+		default:
+			pending = append(pending, line)
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return frontmatterKeyLess(entries[i].key, entries[j].key)
+	})
+
+	var result []string
+	for _, entry := range entries {
+		result = append(result, entry.lines...)
+	}
+	return append(result, pending...)
+}
+
+// frontmatterKeyLess reports whether frontmatter key a comes before key b: well-known
+// keys in frontmatterKeyOrder first, then the other keys alphabetically.
+func frontmatterKeyLess(a, b string) bool {
+	rank := func(key string) int {
+		for i, known := range frontmatterKeyOrder {
+			if strings.EqualFold(key, known) {
+				return i
+			}
+		}
+		return len(frontmatterKeyOrder)
+	}
+	ra, rb := rank(a), rank(b)
+	if ra != rb {
+		return ra < rb
+	}
+	if ra == len(frontmatterKeyOrder) {
+		return strings.ToLower(a) < strings.ToLower(b)
+	}
+	return false
+}
+
+var (
+	// formatComponentPattern matches the {} part of an ingredient, cookware item or timer.
+	formatComponentPattern = regexp.MustCompile(`([@#~])([^@#~{}\n]*)\{([^{}\n]*)\}`)
+	// formatSectionPattern matches a section line such as "= Dough" or "== Dough ==".
+	formatSectionPattern = regexp.MustCompile(`^=+\s*(.*?)\s*=*$`)
+	// formatLeadingZeroPattern matches numbers with leading zeros, which Cooklang
+	// keeps as text rather than evaluating ("01/2").
+	formatLeadingZeroPattern = regexp.MustCompile(`(^|[^\d.])0\d`)
+)
+
+// formatBody formats the recipe body and returns its paragraphs.
+func formatBody(body string, opts FormatOptions) []string {
+	var paragraphs []string
+	var current []string
+	flush := func() {
+		if len(current) > 0 {
+			paragraphs = append(paragraphs, formatParagraph(current, opts))
+			current = nil
+		}
+	}
+	inBlockComment := false
+	for _, line := range strings.Split(body, "\n") {
+		if strings.TrimSpace(line) == "" && !inBlockComment {
+			flush()
+			continue
+		}
+		current = append(current, line)
+		// Blank lines inside a block comment do not end the paragraph
+		if strings.LastIndex(line, "[-") > strings.LastIndex(line, "-]") {
+			inBlockComment = true
+		} else if strings.Contains(line, "-]") {
+			inBlockComment = false
+		}
+	}
+	flush()
+	return paragraphs
+}
+
+// formatParagraph joins the text lines of a step into one line. Sections, notes,
+// metadata lines and lines ending in a comment keep their own line.
+func formatParagraph(lines []string, opts FormatOptions) string {
+	// Block comments are kept as written
+	for _, line := range lines {
+		if strings.Contains(line, "[-") || strings.Contains(line, "-]") {
+			for i := range lines {
+				lines[i] = strings.TrimRight(lines[i], " \t")
+			}
+			return strings.Join(lines, "\n")
+		}
+	}
+
+	var result []string
+	var text []string
+	flush := func() {
+		if len(text) > 0 {
+			result = append(result, strings.Join(text, " "))
+			text = nil
+		}
+	}
+	for _, line := range lines {
+		line = formatComponents(strings.Join(strings.Fields(line), " "), opts)
+		switch {
+		case strings.HasPrefix(line, ">>"):
+			flush()
+			result = append(result, line)
+		case strings.HasPrefix(line, ">"):
+			flush()
+			result = append(result, "> "+strings.TrimSpace(strings.TrimPrefix(line, ">")))
+		case strings.HasPrefix(line, "="):
+			flush()
+			name := formatSectionPattern.FindStringSubmatch(line)[1]
+			if name == "" {
+				result = append(result, "==")
+			} else {
+				result = append(result, "== "+name+" ==")
+			}
+		case strings.Contains(line, "--"):
+			before, comment, _ := strings.Cut(line, "--")
+			comment = "-- " + strings.TrimSpace(comment)
+			if before = strings.TrimSpace(before); before != "" {
+				text = append(text, before+" "+comment)
+			} else {
+				flush()
+				text = append(text, comment)
+			}
+			// A line comment runs to the end of the line
+			flush()
+		default:
+			text = append(text, line)
+		}
+	}
+	flush()
+	return strings.Join(result, "\n")
+}
+
+// formatComponents normalizes the {} parts of the components in a line.
+func formatComponents(line string, opts FormatOptions) string {
+	return formatComponentPattern.ReplaceAllStringFunc(line, func(match string) string {
+		m := formatComponentPattern.FindStringSubmatch(match)
+		sigil, name, content := m[1], m[2], strings.TrimSpace(m[3])
+		amount, unit, hasUnit := strings.Cut(content, "%")
+		amount = strings.TrimSpace(amount)
+		if sigil == "@" {
+			amount = formatIngredientAmount(amount, opts.QuantityStyle)
+		}
+		if hasUnit {
+			amount += "%" + strings.TrimSpace(unit)
+		}
+		return sigil + name + "{" + amount + "}"
+	})
+}
+
+// formatIngredientAmount rewrites an ingredient amount in the given style, keeping the
+// fixed (=) and approximate (~, about) markers. Amounts that are not numbers, or that
+// cannot be written in the style without changing their value, are kept as written.
+func formatIngredientAmount(amount string, style QuantityStyle) string {
+	rest := amount
+	prefix := ""
+	if strings.HasPrefix(rest, "=") {
+		prefix = "="
+		rest = strings.TrimSpace(rest[1:])
+	}
+	switch {
+	case strings.HasPrefix(rest, "~"):
+		prefix += "~"
+		rest = strings.TrimSpace(rest[1:])
+	case len(rest) > 6 && strings.EqualFold(rest[:6], "about "):
+		prefix += rest[:6]
+		rest = strings.TrimSpace(rest[6:])
+	}
+	if rest == "" || strings.EqualFold(rest, "some") || formatLeadingZeroPattern.MatchString(rest) {
+		return prefix + rest
+	}
+
+	q, err := ParseQuantity(rest)
+	if err != nil {
+		return prefix + rest
+	}
+	formatted := q.Format(style)
+	if check, err := ParseQuantity(formatted); err != nil || !check.equal(q) {
+		return prefix + rest
+	}
+	return prefix + formatted
+}
+
+// equal reports whether two quantities have exactly the same value.
+func (q Quantity) equal(other Quantity) bool {
+	return q.isSome == other.isSome && q.IsRange() == other.IsRange() &&
+		q.low.normalized() == other.low.normalized() && q.high.normalized() == other.high.normalized()
+}
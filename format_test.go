@@ -0,0 +1,144 @@
+package cooklang
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/hilli/cooklang/parser"
+	"github.com/hilli/cooklang/token"
+)
+
+func TestFormat(t *testing.T) {
+	src := "---\r\n" +
+		"tags:\r\n" +
+		"  - breakfast\r\n" +
+		"servings:   4\r\n" +
+		"# Where it came from\r\n" +
+		"source: Grandma\r\n" +
+		"title: Pancakes  \r\n" +
+		"---\r\n" +
+		"\r\n" +
+		"\r\n" +
+		"= Batter\r\n" +
+		"Whisk   @flour{ 1/2 % cup }  with\r\n" +
+		"@milk{0.25%l} and @sugar{=~1/3%cup}.   \r\n" +
+		"\r\n" +
+		"\r\n" +
+		"\r\n" +
+		"Add @salt{} and @eggs{01/2}. --  not too much\r\n" +
+		">Best served warm.\r\n" +
+		"\r\n" +
+		"Fry in a #pan{ } for ~{ 3 % minutes }.\r\n"
+
+	expected := `---
+title: Pancakes
+# Where it came from
+source: Grandma
+servings: 4
+tags:
+  - breakfast
+---
+
+== Batter ==
+Whisk @flour{0.5%cup} with @milk{0.25%l} and @sugar{=~1/3%cup}.
+
+Add @salt{} and @eggs{01/2}. -- not too much
+> Best served warm.
+
+Fry in a #pan{} for ~{3%minutes}.
+`
+	formatted, err := Format([]byte(src), FormatOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(formatted) != expected {
+		t.Errorf("unexpected formatting:\n%s\nexpected:\n%s", formatted, expected)
+	}
+
+	again, err := Format(formatted, FormatOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(again) != string(formatted) {
+		t.Errorf("formatting is not idempotent:\n%s", again)
+	}
+}
+
+func TestFormatQuantityStyles(t *testing.T) {
+	src := "Mix @flour{2.5%cups}, @sugar{1/3%cup}, @butter{0.125%cup}, @salt{about 0.5%tsp} and @oil{1 - 1.5%tbsp}.\n"
+	tests := []struct {
+		style    QuantityStyle
+		expected string
+	}{
+		{QuantityStyleAuto, "Mix @flour{2.5%cups}, @sugar{1/3%cup}, @butter{0.125%cup}, @salt{about 0.5%tsp} and @oil{1-1.5%tbsp}.\n"},
+		{QuantityStyleFraction, "Mix @flour{2 1/2%cups}, @sugar{1/3%cup}, @butter{1/8%cup}, @salt{about 1/2%tsp} and @oil{1-1 1/2%tbsp}.\n"},
+		// 1/3 has no exact decimal, so it is kept as a fraction
+		{QuantityStyleDecimal, "Mix @flour{2.5%cups}, @sugar{1/3%cup}, @butter{0.125%cup}, @salt{about 0.5%tsp} and @oil{1-1.5%tbsp}.\n"},
+	}
+	for _, tt := range tests {
+		formatted, err := Format([]byte(src), FormatOptions{QuantityStyle: tt.style})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(formatted) != tt.expected {
+			t.Errorf("style %d: expected %q, got %q", tt.style, tt.expected, formatted)
+		}
+	}
+}
+
+func TestFormatPreservesMeaning(t *testing.T) {
+	sources := []string{
+		"Preheat the #oven{} to 180°C.\nMix @flour{ 200 %g} and @water{ 1/2 %l}\nin a #bowl{2}.\n\n\nBake ~bake{ 25% minutes}.",
+		"---\nservings: 2\ntitle: Toast\n---\n[- Old family\n\nrecipe -]\nToast @bread{2%slices}.",
+		"== Sauce\nSimmer @tomatoes{1 1/2%cans} -- stir often\nwith @basil{}(torn).",
+	}
+	p := parser.New()
+	p.ExtendedMode = true
+	for _, src := range sources {
+		formatted, err := Format([]byte(src), FormatOptions{QuantityStyle: QuantityStyleFraction})
+		if err != nil {
+			t.Fatal(err)
+		}
+		before, err := p.ParseString(src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		after, err := p.ParseBytes(formatted)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(before.Steps) != len(after.Steps) {
+			t.Errorf("step count changed from %d to %d:\n%s", len(before.Steps), len(after.Steps), formatted)
+		}
+		if got, want := componentsOf(after), componentsOf(before); got != want {
+			t.Errorf("components changed:\n%s\nexpected:\n%s\nformatted:\n%s", got, want, formatted)
+		}
+		beforeMeta, _ := json.Marshal(before.Metadata)
+		afterMeta, _ := json.Marshal(after.Metadata)
+		if string(beforeMeta) != string(afterMeta) {
+			t.Errorf("metadata changed from %s to %s", beforeMeta, afterMeta)
+		}
+	}
+}
+
+func TestFormatErrors(t *testing.T) {
+	if _, err := Format([]byte("---\ntitle: Unclosed\nMix @flour{}."), FormatOptions{}); err == nil {
+		t.Error("expected an error for unclosed frontmatter")
+	}
+}
+
+// componentsOf returns the non-text components of a parsed recipe as JSON,
+// without their source positions.
+func componentsOf(recipe *parser.Recipe) string {
+	var components []parser.Component
+	for _, step := range recipe.Steps {
+		for _, c := range step.Components {
+			if c.Type != "text" {
+				c.Position = token.SourcePosition{}
+				components = append(components, c)
+			}
+		}
+	}
+	data, _ := json.Marshal(components)
+	return string(data)
+}
@@ -3,40 +3,16 @@ package cooklang
 import (
 	"fmt"
 	"math"
+	"strconv"
+	"strings"
 )
 
-// fractionEntry represents a common fraction with its decimal value
-type fractionEntry struct {
-	numerator   int
-	denominator int
-	value       float64
-}
-
-// commonFractions lists fractions in order of preference (simpler fractions first)
-// This ordering ensures we prefer 1/2 over 4/8, etc.
-var commonFractions = []fractionEntry{
-	{1, 2, 0.5},
-	{1, 4, 0.25},
-	{3, 4, 0.75},
-	{1, 3, 1.0 / 3.0},
-	{2, 3, 2.0 / 3.0},
-	{1, 8, 0.125},
-	{3, 8, 0.375},
-	{5, 8, 0.625},
-	{7, 8, 0.875},
-	{1, 6, 1.0 / 6.0},
-	{5, 6, 5.0 / 6.0},
-	{1, 12, 1.0 / 12.0},
-	{5, 12, 5.0 / 12.0},
-	{7, 12, 7.0 / 12.0},
-	{11, 12, 11.0 / 12.0},
-}
-
 // DefaultFractionTolerance is the default tolerance for matching fractions
 const DefaultFractionTolerance = 0.02
 
 // FormatAsFraction converts a float to a human-readable fraction string.
-// It handles whole numbers, simple fractions, and mixed numbers.
+// It handles whole numbers, simple fractions, and mixed numbers. It is FormatQuantity
+// limited to the denominators of everyday fractions (up to twelfths).
 //
 // Examples:
 //   - 0.5 → "1/2"
@@ -52,56 +28,7 @@ const DefaultFractionTolerance = 0.02
 // Returns:
 //   - A human-readable string representation
 func FormatAsFraction(value float64, tolerance float64) string {
-	if tolerance <= 0 {
-		tolerance = DefaultFractionTolerance
-	}
-
-	// Handle negative values
-	if value < 0 {
-		return "-" + FormatAsFraction(-value, tolerance)
-	}
-
-	// Handle zero
-	if value == 0 {
-		return "0"
-	}
-
-	// Extract whole number part
-	whole := int(value)
-	frac := value - float64(whole)
-
-	// If it's very close to a whole number, return just the whole number
-	if frac < tolerance {
-		return fmt.Sprintf("%d", whole)
-	}
-
-	// If the fractional part is very close to 1, round up
-	if frac > 1.0-tolerance {
-		return fmt.Sprintf("%d", whole+1)
-	}
-
-	// Try to match the fractional part to a common fraction
-	for _, f := range commonFractions {
-		if math.Abs(frac-f.value) < tolerance {
-			if whole > 0 {
-				return fmt.Sprintf("%d %d/%d", whole, f.numerator, f.denominator)
-			}
-			return fmt.Sprintf("%d/%d", f.numerator, f.denominator)
-		}
-	}
-
-	// Fallback: format as decimal
-	if whole > 0 {
-		// Format fractional part with 1-2 decimal places
-		formatted := formatDecimal(frac)
-		if formatted == "0" {
-			return fmt.Sprintf("%d", whole)
-		}
-		// Return as decimal (e.g., "2.75" not "2 0.75")
-		return formatDecimal(value)
-	}
-
-	return formatDecimal(value)
+	return FormatQuantity(value, FormatOptions{MaxDenominator: niceFractionMaxDenominator, Tolerance: tolerance})
 }
 
 // FormatAsFractionDefault uses the default tolerance for fraction matching.
@@ -122,6 +49,111 @@ func FormatAsFractionDefault(value float64) string {
 	return FormatAsFraction(value, DefaultFractionTolerance)
 }
 
+// DefaultMaxDenominator is the largest denominator FormatQuantity uses when
+// FormatOptions.MaxDenominator is zero.
+const DefaultMaxDenominator = 16
+
+// fractionDenominators are the denominators FormatQuantity tries, in order:
+// the ones found in recipes and on measuring cups and spoons.
+var fractionDenominators = []int{2, 3, 4, 6, 8, 12, 16, 32, 64}
+
+// niceFractionMaxDenominator is the largest denominator used by FormatAsFraction,
+// IsNiceFraction and RoundToNiceFraction.
+const niceFractionMaxDenominator = 12
+
+// nearestFraction returns the simplest fraction numerator/denominator within
+// tolerance of frac (0 < frac < 1), trying fractionDenominators up to maxDenominator.
+func nearestFraction(frac float64, maxDenominator int, tolerance float64) (numerator, denominator int, ok bool) {
+	for _, denominator := range fractionDenominators {
+		if denominator > maxDenominator {
+			break
+		}
+		numerator := int(math.Round(frac * float64(denominator)))
+		if numerator == 0 || numerator == denominator || math.Abs(frac-float64(numerator)/float64(denominator)) >= tolerance {
+			continue
+		}
+		return numerator, denominator, true
+	}
+	return 0, 0, false
+}
+
+// FormatQuantity writes a number as a whole number, a fraction or a mixed number,
+// falling back to a decimal when no fraction with a small enough denominator is
+// close. Use it to display quantities the way cooks write them.
+//
+// Parameters:
+//   - value: The number to format
+//   - opts: UnicodeFractions, MaxDenominator and Tolerance; QuantityStyle is ignored
+//
+// Returns:
+//   - string: The formatted number
+//
+// Example:
+//
+//	unicode := cooklang.FormatOptions{UnicodeFractions: true, MaxDenominator: 16}
+//	cooklang.FormatQuantity(0.5, unicode)                   // "½"
+//	cooklang.FormatQuantity(1.5, unicode)                   // "1½"
+//	cooklang.FormatQuantity(0.3125, unicode)                // "⁵⁄₁₆"
+//	cooklang.FormatQuantity(2.75, cooklang.FormatOptions{}) // "2 3/4"
+//	cooklang.FormatQuantity(0.3, cooklang.FormatOptions{MaxDenominator: 4}) // "0.3"
+func FormatQuantity(value float64, opts FormatOptions) string {
+	if value < 0 {
+		return "-" + FormatQuantity(-value, opts)
+	}
+	tolerance := opts.Tolerance
+	if tolerance <= 0 {
+		tolerance = DefaultFractionTolerance
+	}
+	maxDenominator := opts.MaxDenominator
+	if maxDenominator <= 0 {
+		maxDenominator = DefaultMaxDenominator
+	}
+
+	whole := math.Floor(value)
+	frac := value - whole
+	if frac < tolerance {
+		return strconv.FormatFloat(whole, 'f', -1, 64)
+	}
+	if frac > 1-tolerance {
+		return strconv.FormatFloat(whole+1, 'f', -1, 64)
+	}
+
+	if numerator, denominator, ok := nearestFraction(frac, maxDenominator, tolerance); ok {
+		return mixedNumber(int64(whole), int64(numerator), int64(denominator), opts.UnicodeFractions)
+	}
+	return formatDecimal(value)
+}
+
+// mixedNumber writes whole and numerator/denominator as "2 1/2" (ASCII) or "2½"
+// (Unicode), or just the fraction when whole is 0. FormatQuantity and
+// Quantity.Format both write fractions with it.
+func mixedNumber(whole, numerator, denominator int64, unicode bool) string {
+	fraction := formatFraction(int(numerator), int(denominator), unicode)
+	switch {
+	case whole == 0:
+		return fraction
+	case unicode:
+		return strconv.FormatInt(whole, 10) + fraction
+	default:
+		return strconv.FormatInt(whole, 10) + " " + fraction
+	}
+}
+
+// formatFraction writes numerator/denominator in ASCII or Unicode.
+func formatFraction(numerator, denominator int, unicode bool) string {
+	if !unicode {
+		return fmt.Sprintf("%d/%d", numerator, denominator)
+	}
+	for character, value := range unicodeFractions {
+		if value == (rational{num: int64(numerator), den: int64(denominator)}) {
+			return string(character)
+		}
+	}
+	superscript := strings.NewReplacer("0", "⁰", "1", "¹", "2", "²", "3", "³", "4", "⁴", "5", "⁵", "6", "⁶", "7", "⁷", "8", "⁸", "9", "⁹")
+	subscript := strings.NewReplacer("0", "₀", "1", "₁", "2", "₂", "3", "₃", "4", "₄", "5", "₅", "6", "₆", "7", "₇", "8", "₈", "9", "₉")
+	return superscript.Replace(strconv.Itoa(numerator)) + "⁄" + subscript.Replace(strconv.Itoa(denominator))
+}
+
 // formatDecimal formats a decimal number nicely, removing unnecessary trailing zeros.
 func formatDecimal(value float64) string {
 	// For very small values, use more precision
@@ -234,17 +266,11 @@ func IsNiceFraction(value float64, tolerance float64) bool {
 		return true
 	}
 
-	// Check common fractions
-	for _, f := range commonFractions {
-		if math.Abs(frac-f.value) < tolerance {
-			return true
-		}
-	}
-
-	return false
+	_, _, ok := nearestFraction(frac, niceFractionMaxDenominator, tolerance)
+	return ok
 }
 
-// RoundToNiceFraction rounds a value to the nearest common fraction.
+// RoundToNiceFraction rounds a value to the simplest common fraction within tolerance.
 // This is useful for bartender mode where clean measurements are preferred.
 // If no common fraction is within tolerance, the original value is returned.
 //
@@ -276,22 +302,8 @@ func RoundToNiceFraction(value float64, tolerance float64) float64 {
 		return whole + 1
 	}
 
-	// Find the nearest common fraction
-	bestMatch := frac
-	bestDiff := tolerance + 1 // Start with a value that won't match
-
-	for _, f := range commonFractions {
-		diff := math.Abs(frac - f.value)
-		if diff < bestDiff {
-			bestDiff = diff
-			bestMatch = f.value
-		}
-	}
-
-	// Only use the match if it's within tolerance
-	if bestDiff <= tolerance {
-		return whole + bestMatch
+	if numerator, denominator, ok := nearestFraction(frac, niceFractionMaxDenominator, tolerance); ok {
+		return whole + float64(numerator)/float64(denominator)
 	}
-
 	return value // Return unchanged if no good match
 }
@@ -237,3 +237,51 @@ func TestRoundTrip(t *testing.T) {
 		}
 	}
 }
+
+func TestFormatQuantity(t *testing.T) {
+	unicode := FormatOptions{UnicodeFractions: true, MaxDenominator: 16}
+	tests := []struct {
+		value    float64
+		opts     FormatOptions
+		expected string
+	}{
+		{0.5, unicode, "½"},
+		{1.5, unicode, "1½"},
+		{2.75, unicode, "2¾"},
+		{1.0 / 3.0, unicode, "⅓"},
+		{0.3125, unicode, "⁵⁄₁₆"},
+		{1.0 / 12.0, unicode, "¹⁄₁₂"},
+		{3, unicode, "3"},
+		{0.99, unicode, "1"},
+		{-0.25, unicode, "-¼"},
+		{0, unicode, "0"},
+		{2.75, FormatOptions{}, "2 3/4"},
+		{0.0625, FormatOptions{}, "1/16"},
+		{0.0625, FormatOptions{MaxDenominator: 8}, "0.062"},
+		{0.3, FormatOptions{MaxDenominator: 4}, "0.3"},
+		{0.3, FormatOptions{MaxDenominator: 4, Tolerance: 0.06}, "1/3"},
+		{1.37, FormatOptions{UnicodeFractions: true}, "1⅜"},
+		{12.34, FormatOptions{MaxDenominator: 2}, "12.3"},
+	}
+	for _, tt := range tests {
+		if got := FormatQuantity(tt.value, tt.opts); got != tt.expected {
+			t.Errorf("FormatQuantity(%v, %+v) = %q, want %q", tt.value, tt.opts, got, tt.expected)
+		}
+	}
+}
+
+func TestFormattersAgree(t *testing.T) {
+	for _, text := range []string{"1/2", "2 1/4", "1/3", "5/6", "3/8", "1 7/12", "3", "12.5"} {
+		q, err := ParseQuantity(text)
+		if err != nil {
+			t.Fatalf("ParseQuantity(%q): %v", text, err)
+		}
+		exact := q.Format(QuantityStyleFraction)
+		if got := FormatQuantity(q.Float(), FormatOptions{}); got != exact {
+			t.Errorf("FormatQuantity(%s) = %q, Quantity.Format = %q", text, got, exact)
+		}
+		if got := FormatAsFractionDefault(q.Float()); got != exact {
+			t.Errorf("FormatAsFractionDefault(%s) = %q, Quantity.Format = %q", text, got, exact)
+		}
+	}
+}
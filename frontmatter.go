@@ -2,11 +2,16 @@ package cooklang
 
 import (
 	"fmt"
+	"io"
 	"os"
-	"regexp"
+	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/goccy/go-yaml/ast"
+	yamlparser "github.com/goccy/go-yaml/parser"
 )
 
 // FrontmatterEditor provides CRUD operations for recipe frontmatter metadata.
@@ -15,6 +20,9 @@ import (
 // The editor works with the structured Recipe fields (title, cuisine, servings, etc.)
 // as well as custom metadata fields, providing a unified interface for metadata management.
 //
+// Saving only rewrites the keys that changed: key order, comments, nested maps and
+// lists, and values the editor did not touch are kept as they are in the file.
+//
 // Example:
 //
 //	editor, err := cooklang.NewFrontmatterEditor("recipe.cook")
@@ -25,9 +33,13 @@ import (
 //	editor.SetMetadata("servings", "4")
 //	editor.Save()
 type FrontmatterEditor struct {
-	filePath string
-	content  string
-	recipe   *Recipe
+	filePath    string
+	content     string
+	recipe      *Recipe
+	schema      *MetadataSchema
+	frontmatter string            // The YAML between the --- lines as read, "" if there is none
+	original    map[string]string // The metadata as read, to find what changed
+	atomicSave  bool              // Save through a temporary file and rename
 }
 
 // NewFrontmatterEditor creates a new FrontmatterEditor for the given recipe file.
@@ -54,16 +66,93 @@ func NewFrontmatterEditor(filePath string) (*FrontmatterEditor, error) {
 		return nil, fmt.Errorf("failed to read file: %w", err)
 	}
 
+	editor, err := NewFrontmatterEditorFromBytes(content)
+	if err != nil {
+		return nil, err
+	}
+	editor.filePath = filePath
+	return editor, nil
+}
+
+// NewFrontmatterEditorFromBytes creates a FrontmatterEditor for recipe content held in
+// memory. The editor has no file: use [FrontmatterEditor.WriteTo],
+// [FrontmatterEditor.GetUpdatedContent] or [FrontmatterEditor.SaveAs] to get the result.
+//
+// Parameters:
+//   - content: The Cooklang recipe content
+//
+// Returns:
+//   - *FrontmatterEditor: An editor instance ready for metadata operations
+//   - error: Any error encountered during parsing
+//
+// Example:
+//
+//	editor, err := cooklang.NewFrontmatterEditorFromBytes(body)
+//	if err != nil {
+//	    http.Error(w, err.Error(), http.StatusBadRequest)
+//	    return
+//	}
+//	editor.SetMetadata("servings", "4")
+//	editor.WriteTo(w)
+func NewFrontmatterEditorFromBytes(content []byte) (*FrontmatterEditor, error) {
 	recipe, err := ParseBytes(content)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse recipe: %w", err)
 	}
 
-	return &FrontmatterEditor{
-		filePath: filePath,
-		content:  string(content),
-		recipe:   recipe,
-	}, nil
+	editor := &FrontmatterEditor{
+		content: string(content),
+		recipe:  recipe,
+		schema:  DefaultMetadataSchema(),
+	}
+	editor.frontmatter, _ = editor.splitContent()
+	editor.original = editor.currentValues()
+	return editor, nil
+}
+
+// NewFrontmatterEditorFromReader creates a FrontmatterEditor for recipe content read
+// from r, such as an HTTP request body. Like [NewFrontmatterEditorFromBytes], the
+// editor has no file.
+//
+// Parameters:
+//   - r: The reader to read the Cooklang recipe content from
+//
+// Returns:
+//   - *FrontmatterEditor: An editor instance ready for metadata operations
+//   - error: Any error encountered during reading or parsing
+func NewFrontmatterEditorFromReader(r io.Reader) (*FrontmatterEditor, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read recipe: %w", err)
+	}
+	return NewFrontmatterEditorFromBytes(content)
+}
+
+// SetAtomicSave makes [FrontmatterEditor.Save] and [FrontmatterEditor.SaveAs] write to
+// a temporary file in the same directory and rename it over the target, so readers
+// never see a partially written recipe. The file keeps its permissions.
+//
+// Parameters:
+//   - atomic: Whether to save atomically
+func (fe *FrontmatterEditor) SetAtomicSave(atomic bool) {
+	fe.atomicSave = atomic
+}
+
+// SetSchema sets the schema that [FrontmatterEditor.SetMetadata] validates values
+// against. The default is [DefaultMetadataSchema].
+//
+// Parameters:
+//   - schema: The metadata schema
+//
+// Example:
+//
+//	schema := cooklang.DefaultMetadataSchema()
+//	schema.Fields["rating"] = cooklang.MetadataInt
+//	schema.DateLayouts = []string{"02.01.2006"}
+//	editor.SetSchema(schema)
+//	editor.SetMetadata("date", "15.01.2024")
+func (fe *FrontmatterEditor) SetSchema(schema *MetadataSchema) {
+	fe.schema = schema
 }
 
 // GetMetadata retrieves a metadata value by key.
@@ -211,14 +300,15 @@ func (fe *FrontmatterEditor) GetAllMetadata() map[string]string {
 // replacing the entire array, use [FrontmatterEditor.AppendToArray] and
 // [FrontmatterEditor.RemoveFromArray] instead.
 //
-// For structured fields (servings, date), the value is validated and parsed.
+// Values are validated against the editor's schema (see [FrontmatterEditor.SetSchema]),
+// so servings must be a number, times durations and dates use the schema's layouts.
 //
 // Parameters:
 //   - key: The metadata key to set
 //   - value: The value to set (comma-separated for array fields)
 //
 // Returns:
-//   - error: Validation error for structured fields (e.g., invalid date format)
+//   - error: Validation error for typed fields (e.g., invalid date format)
 //
 // Example:
 //
@@ -229,6 +319,10 @@ func (fe *FrontmatterEditor) GetAllMetadata() map[string]string {
 //	editor.SetMetadata("date", "2024-01-15")
 //	editor.Save()
 func (fe *FrontmatterEditor) SetMetadata(key, value string) error {
+	if err := fe.schema.ValidateValue(key, value); err != nil {
+		return err
+	}
+
 	// Update the recipe object
 	switch key {
 	case "title":
@@ -253,14 +347,11 @@ func (fe *FrontmatterEditor) SetMetadata(key, value string) error {
 		fe.recipe.Author = value
 		fe.recipe.Metadata[key] = value
 	case "servings":
-		servings, err := strconv.ParseFloat(value, 32)
-		if err != nil {
-			return fmt.Errorf("invalid servings value: %w", err)
-		}
+		servings, _ := strconv.ParseFloat(strings.TrimSpace(value), 32)
 		fe.recipe.Servings = float32(servings)
 		fe.recipe.Metadata[key] = value
 	case "date":
-		date, err := time.Parse("2006-01-02", value)
+		date, err := fe.schema.ParseDate(value)
 		if err != nil {
 			return fmt.Errorf("invalid date format (use YYYY-MM-DD): %w", err)
 		}
@@ -348,7 +439,8 @@ func (fe *FrontmatterEditor) DeleteMetadata(key string) error {
 // The recipe body (instructions) is preserved; only the frontmatter is updated.
 //
 // Returns:
-//   - error: Any error encountered during file writing
+//   - error: Any error encountered during file writing, or an error if the editor
+//     was created from content in memory
 //
 // Example:
 //
@@ -358,6 +450,9 @@ func (fe *FrontmatterEditor) DeleteMetadata(key string) error {
 //	    log.Fatal(err)
 //	}
 func (fe *FrontmatterEditor) Save() error {
+	if fe.filePath == "" {
+		return fmt.Errorf("editor has no file; use SaveAs or WriteTo")
+	}
 	return fe.SaveAs(fe.filePath)
 }
 
@@ -376,28 +471,76 @@ func (fe *FrontmatterEditor) Save() error {
 //	editor.SetMetadata("title", "Updated Recipe")
 //	editor.SaveAs("recipe_v2.cook")
 func (fe *FrontmatterEditor) SaveAs(filePath string) error {
-	// Get the recipe content after frontmatter
-	recipeBody := fe.extractRecipeBody()
-
-	// Render the frontmatter
-	frontmatter := fe.renderFrontmatter()
-
-	// Combine frontmatter and body
-	newContent := frontmatter + "\n" + recipeBody
+	newContent := fe.GetUpdatedContent()
 
 	// Write to file
-	if err := os.WriteFile(filePath, []byte(newContent), 0644); err != nil {
+	write := os.WriteFile
+	if fe.atomicSave {
+		write = writeFileAtomic
+	}
+	if err := write(filePath, []byte(newContent), 0644); err != nil {
 		return fmt.Errorf("failed to write file: %w", err)
 	}
 
 	// Update internal state if saving to the same file
 	if filePath == fe.filePath {
 		fe.content = newContent
+		fe.frontmatter, _ = fe.splitContent()
+		fe.original = fe.currentValues()
 	}
 
 	return nil
 }
 
+// WriteTo writes the updated recipe content to w. It implements io.WriterTo.
+//
+// Parameters:
+//   - w: The writer to write to
+//
+// Returns:
+//   - int64: The number of bytes written
+//   - error: Any error encountered during writing
+//
+// Example:
+//
+//	editor, _ := cooklang.NewFrontmatterEditorFromReader(r.Body)
+//	editor.SetMetadata("title", "Updated Title")
+//	editor.WriteTo(w)
+func (fe *FrontmatterEditor) WriteTo(w io.Writer) (int64, error) {
+	n, err := io.WriteString(w, fe.GetUpdatedContent())
+	return int64(n), err
+}
+
+// writeFileAtomic writes data to a temporary file next to path and renames it over
+// path. An existing file keeps its permissions; a new file gets perm.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	if info, err := os.Stat(path); err == nil {
+		perm = info.Mode().Perm()
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	// Clean up on failure; after a successful rename the temporary file is gone
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), perm); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
 // GetContent returns the original file content as read from disk.
 //
 // Returns:
@@ -426,28 +569,151 @@ func (fe *FrontmatterEditor) GetUpdatedContent() string {
 
 // extractRecipeBody extracts the recipe content (instructions) after the frontmatter.
 func (fe *FrontmatterEditor) extractRecipeBody() string {
-	// Match YAML frontmatter delimited by ---
-	re := regexp.MustCompile(`(?s)^---\n.*?\n---\n`)
-	body := re.ReplaceAllString(fe.content, "")
-
-	// If no frontmatter found, return original content
-	if body == fe.content {
-		// Check if content starts with frontmatter
-		if strings.HasPrefix(fe.content, "---\n") {
-			// Find the end of frontmatter
-			parts := strings.SplitN(fe.content, "\n---\n", 2)
-			if len(parts) == 2 {
-				return parts[1]
-			}
+	_, body := fe.splitContent()
+	return body
+}
+
+// splitContent splits the file content into the frontmatter YAML ("" if there is
+// none) and the recipe body.
+func (fe *FrontmatterEditor) splitContent() (string, string) {
+	content := strings.ReplaceAll(fe.content, "\r\n", "\n")
+	lines, body, err := splitFrontmatter(content)
+	if err != nil || lines == nil {
+		return "", content
+	}
+	return strings.Join(lines, "\n"), body
+}
+
+// currentValues returns the metadata as it would be written. SetMetadata and
+// DeleteMetadata keep the metadata map up to date; tags and images come from the
+// recipe fields, which AppendToArray and RemoveFromArray update.
+func (fe *FrontmatterEditor) currentValues() map[string]string {
+	values := make(map[string]string, len(fe.recipe.Metadata))
+	for k, v := range fe.recipe.Metadata {
+		values[k] = v
+	}
+	for key, items := range map[string][]string{"tags": fe.recipe.Tags, "images": fe.recipe.Images} {
+		if len(items) == 0 {
+			delete(values, key)
+		} else {
+			values[key] = strings.Join(items, ", ")
 		}
-		return fe.content
 	}
+	return values
+}
 
-	return strings.TrimLeft(body, "\n")
+// Changes returns the metadata values that differ from the content the editor was
+// created with (or last saved to its file), sorted by key.
+//
+// Returns:
+//   - []MetadataChange: The changed values; empty if nothing changed
+//
+// Example:
+//
+//	editor.SetMetadata("author", "Sam")
+//	for _, change := range editor.Changes() {
+//	    fmt.Printf("%s %s: %q → %q\n", change.Type, change.Key, change.Old, change.New)
+//	}
+func (fe *FrontmatterEditor) Changes() []MetadataChange {
+	return diffMetadata(fe.original, fe.currentValues())
 }
 
-// renderFrontmatter renders the current recipe metadata as YAML frontmatter.
+// renderFrontmatter renders the current recipe metadata as YAML frontmatter,
+// editing the original frontmatter where possible.
 func (fe *FrontmatterEditor) renderFrontmatter() string {
+	if yaml, ok := fe.updateFrontmatter(); ok {
+		return "---\n" + yaml + "---"
+	}
+	return fe.renderNewFrontmatter()
+}
+
+// updateFrontmatter applies the metadata changes to the original frontmatter YAML:
+// changed values are replaced in place, deleted keys are removed and new keys are
+// added at the end. It reports false if there is no frontmatter or it cannot be
+// edited as YAML.
+func (fe *FrontmatterEditor) updateFrontmatter() (string, bool) {
+	if strings.TrimSpace(fe.frontmatter) == "" {
+		return "", false
+	}
+	file, err := yamlparser.ParseBytes([]byte(fe.frontmatter+"\n"), yamlparser.ParseComments)
+	if err != nil || len(file.Docs) != 1 {
+		return "", false
+	}
+	mapping, ok := file.Docs[0].Body.(*ast.MappingNode)
+	if !ok {
+		return "", false
+	}
+
+	current := fe.currentValues()
+	seen := make(map[string]bool)
+	var entries []*ast.MappingValueNode
+	for _, entry := range mapping.Values {
+		key := strings.Trim(entry.Key.String(), `"'`)
+		seen[key] = true
+		value, exists := current[key]
+		original, existed := fe.original[key]
+		switch {
+		case existed && !exists:
+			continue // Deleted
+		case exists && value != original:
+			updated, err := fe.yamlEntry(key, value)
+			if err != nil {
+				return "", false
+			}
+			// Keep a comment after a single-line value
+			if comment := entry.Value.GetComment(); comment != nil && !strings.Contains(value, "\n") {
+				_ = updated.Value.SetComment(comment)
+			}
+			entry.Value = updated.Value
+		}
+		entries = append(entries, entry)
+	}
+
+	var added []string
+	for key, value := range current {
+		if !seen[key] && value != fe.original[key] {
+			added = append(added, key)
+		}
+	}
+	sort.Slice(added, func(i, j int) bool { return frontmatterKeyLess(added[i], added[j]) })
+	for _, key := range added {
+		entry, err := fe.yamlEntry(key, current[key])
+		if err != nil {
+			return "", false
+		}
+		entries = append(entries, entry)
+	}
+
+	mapping.Values = entries
+	return strings.TrimRight(file.String(), "\n") + "\n", true
+}
+
+// yamlEntry builds the YAML node for a metadata key and value. List fields become
+// block sequences and multi-line values literal block scalars.
+func (fe *FrontmatterEditor) yamlEntry(key, value string) (*ast.MappingValueNode, error) {
+	var lines []string
+	if fe.schema.Type(key) == MetadataStringSlice {
+		lines = append(lines, key+":")
+		for _, item := range splitAndTrim(value) {
+			lines = append(lines, "  - "+item)
+		}
+	} else {
+		lines = renderYAMLValue(key, value)
+	}
+	file, err := yamlparser.ParseBytes([]byte(strings.Join(lines, "\n")+"\n"), 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(file.Docs) == 1 {
+		if mapping, ok := file.Docs[0].Body.(*ast.MappingNode); ok && len(mapping.Values) == 1 {
+			return mapping.Values[0], nil
+		}
+	}
+	return nil, fmt.Errorf("cannot write %s as YAML", key)
+}
+
+// renderNewFrontmatter renders the current recipe metadata as new YAML frontmatter.
+func (fe *FrontmatterEditor) renderNewFrontmatter() string {
 	var lines []string
 	lines = append(lines, "---")
 
@@ -470,7 +736,8 @@ func (fe *FrontmatterEditor) renderFrontmatter() string {
 	if !fe.recipe.Date.IsZero() {
 		lines = append(lines, fmt.Sprintf("date: %s", fe.recipe.Date.Format("2006-01-02")))
 	}
-	if fe.recipe.Servings > 0 {
+	// Servings default to 1 when a recipe has none; only write them if they are set
+	if _, ok := fe.recipe.Metadata["servings"]; ok && fe.recipe.Servings > 0 {
 		lines = append(lines, fmt.Sprintf("servings: %g", fe.recipe.Servings))
 	}
 	if fe.recipe.PrepTime != "" {
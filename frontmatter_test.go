@@ -1,6 +1,7 @@
 package cooklang
 
 import (
+	"bytes"
 	"os"
 	"path/filepath"
 	"strings"
@@ -822,3 +823,156 @@ Mix @tequila{60%ml} with @lime juice{30%ml}.
 		t.Error("Recipe body was not preserved")
 	}
 }
+
+func TestFrontmatterEditor_PreservesYAMLStructure(t *testing.T) {
+	content := `---
+# Family favourite
+title: Lasagna # from Nonna
+nutrition:
+  calories: 650
+  protein: 32g
+servings: 6
+tags: [italian, pasta]
+equipment:
+  - name: baking dish
+    size: 9x13
+cuisine: Italian
+---
+
+Layer @pasta sheets{12} with @ragu{1%l}.
+`
+	tmpFile := filepath.Join(t.TempDir(), "lasagna.cook")
+	if err := os.WriteFile(tmpFile, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	editor, err := NewFrontmatterEditor(tmpFile)
+	if err != nil {
+		t.Fatalf("Failed to create editor: %v", err)
+	}
+
+	if unchanged := editor.GetUpdatedContent(); unchanged != content {
+		t.Errorf("Saving without changes should keep the file as is, got:\n%s", unchanged)
+	}
+
+	if err := editor.SetMetadata("title", "Weeknight Lasagna"); err != nil {
+		t.Fatal(err)
+	}
+	if err := editor.SetMetadata("servings", "8"); err != nil {
+		t.Fatal(err)
+	}
+	if err := editor.AppendToArray("tags", "baked"); err != nil {
+		t.Fatal(err)
+	}
+	if err := editor.DeleteMetadata("cuisine"); err != nil {
+		t.Fatal(err)
+	}
+	if err := editor.SetMetadata("source", "Nonna"); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `---
+# Family favourite
+title: Weeknight Lasagna # from Nonna
+nutrition:
+  calories: 650
+  protein: 32g
+servings: 8
+tags:
+  - italian
+  - pasta
+  - baked
+equipment:
+  - name: baking dish
+    size: 9x13
+source: Nonna
+---
+
+Layer @pasta sheets{12} with @ragu{1%l}.
+`
+	if updated := editor.GetUpdatedContent(); updated != expected {
+		t.Errorf("Unexpected content:\n%s\nwant:\n%s", updated, expected)
+	}
+
+	if err := editor.Save(); err != nil {
+		t.Fatal(err)
+	}
+	reloaded, err := NewFrontmatterEditor(tmpFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tags, _ := reloaded.GetMetadata("tags"); tags != "italian, pasta, baked" {
+		t.Errorf("After reload, tags = %q", tags)
+	}
+	if reloaded.GetUpdatedContent() != expected {
+		t.Errorf("Saving the reloaded file should not change it, got:\n%s", reloaded.GetUpdatedContent())
+	}
+}
+
+func TestFrontmatterEditor_InMemory(t *testing.T) {
+	content := "---\ntitle: Toast\n---\n\nToast @bread{2%slices}.\n"
+
+	editor, err := NewFrontmatterEditorFromReader(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("Failed to create editor: %v", err)
+	}
+	if err := editor.SetMetadata("servings", "2"); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	n, err := editor.WriteTo(&buf)
+	if err != nil {
+		t.Fatalf("WriteTo failed: %v", err)
+	}
+	expected := "---\ntitle: Toast\nservings: 2\n---\n\nToast @bread{2%slices}.\n"
+	if buf.String() != expected || n != int64(len(expected)) {
+		t.Errorf("WriteTo wrote %d bytes:\n%s\nwant:\n%s", n, buf.String(), expected)
+	}
+
+	if err := editor.Save(); err == nil {
+		t.Error("Save should fail for an editor without a file")
+	}
+	tmpFile := filepath.Join(t.TempDir(), "toast.cook")
+	if err := editor.SaveAs(tmpFile); err != nil {
+		t.Fatalf("SaveAs failed: %v", err)
+	}
+	if saved, _ := os.ReadFile(tmpFile); string(saved) != expected {
+		t.Errorf("SaveAs wrote:\n%s", saved)
+	}
+}
+
+func TestFrontmatterEditor_AtomicSave(t *testing.T) {
+	dir := t.TempDir()
+	tmpFile := filepath.Join(dir, "toast.cook")
+	if err := os.WriteFile(tmpFile, []byte("---\ntitle: Toast\n---\nToast @bread{}.\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	editor, err := NewFrontmatterEditor(tmpFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	editor.SetAtomicSave(true)
+	if err := editor.SetMetadata("title", "Better Toast"); err != nil {
+		t.Fatal(err)
+	}
+	if err := editor.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	saved, _ := os.ReadFile(tmpFile)
+	if !strings.Contains(string(saved), "title: Better Toast") {
+		t.Errorf("Atomic save did not write the update:\n%s", saved)
+	}
+	info, err := os.Stat(tmpFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("Atomic save changed permissions to %v", info.Mode().Perm())
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("Atomic save left temporary files behind: %v", entries)
+	}
+}
@@ -0,0 +1,59 @@
+package cooklang
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"slices"
+	"strings"
+)
+
+// HashIgnoredMetadataKeys are metadata keys that Recipe.Hash leaves out: bookkeeping
+// that changes without the recipe changing, such as when it was last cooked.
+var HashIgnoredMetadataKeys = []string{
+	"last_cooked", "last_made", "times_cooked", "cooked_count",
+	"updated", "modified", "last_modified", "hash",
+}
+
+// Hash returns a stable content hash of the recipe, as 64 lowercase hex digits, to use
+// as a cache key for rendered output. The hash covers the recipe's structure: its
+// metadata, steps and components in the JSON schema of MarshalJSON. It does not
+// change with source positions, so reformatting a recipe with blank lines or
+// indentation keeps its hash, nor with the order of metadata keys or the metadata
+// in HashIgnoredMetadataKeys, which are matched case-insensitively.
+//
+// Returns:
+//   - string: The hex-encoded SHA-256 hash
+//
+// Example:
+//
+//	recipe, _ := cooklang.ParseFile("pancakes.cook")
+//	key := recipe.Hash()
+//	if html, ok := cache[key]; ok {
+//	    return html
+//	}
+func (r *Recipe) Hash() string {
+	fields := recipeFields(*comparableFields(r))
+	fields.Date = r.Date.UTC()
+	for key := range fields.Metadata {
+		if slices.Contains(HashIgnoredMetadataKeys, strings.ToLower(key)) {
+			delete(fields.Metadata, key)
+		}
+	}
+
+	doc := recipeJSON{SchemaVersion: JSONSchemaVersion, recipeFields: &fields, Steps: []stepJSON{}}
+	for step := range r.Steps() {
+		s := stepJSON{Images: step.Images, Components: []json.RawMessage{}}
+		for component := range step.Components() {
+			data, _ := marshalComponent(comparableComponent(component))
+			s.Components = append(s.Components, data)
+		}
+		doc.Steps = append(doc.Steps, s)
+	}
+
+	// Parsed recipes always encode; values set in code that JSON cannot hold, such as
+	// NaN servings, leave out what they are part of
+	hash := sha256.New()
+	_ = json.NewEncoder(hash).Encode(doc)
+	return hex.EncodeToString(hash.Sum(nil))
+}
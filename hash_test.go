@@ -0,0 +1,66 @@
+package cooklang
+
+import (
+	"testing"
+)
+
+func TestRecipeHash(t *testing.T) {
+	base := "---\ntitle: Pancakes\nservings: 4\nlast_cooked: 2026-01-02\n---\nMix @flour{200%g} and @milk{300%ml}.\n\nFry in a #pan{} for ~{3%minutes}.\n"
+	hash := func(t *testing.T, content string) string {
+		t.Helper()
+		recipe, err := ParseString(content)
+		if err != nil {
+			t.Fatalf("ParseString failed: %v", err)
+		}
+		return recipe.Hash()
+	}
+	want := hash(t, base)
+	if len(want) != 64 {
+		t.Fatalf("Hash() = %q, want 64 hex digits", want)
+	}
+
+	tests := []struct {
+		name    string
+		content string
+		same    bool
+	}{
+		{"identical", base, true},
+		{"blank lines", "---\ntitle: Pancakes\nservings: 4\nlast_cooked: 2026-01-02\n---\n\n\nMix @flour{200%g} and @milk{300%ml}.\n\n\n\nFry in a #pan{} for ~{3%minutes}.\n\n", true},
+		{"metadata order", "---\nservings: 4\nlast_cooked: 2026-01-02\ntitle: Pancakes\n---\nMix @flour{200%g} and @milk{300%ml}.\n\nFry in a #pan{} for ~{3%minutes}.\n", true},
+		{"ignored metadata", "---\ntitle: Pancakes\nservings: 4\nLast_Cooked: 2026-03-04\nTimes_Cooked: 7\n---\nMix @flour{200%g} and @milk{300%ml}.\n\nFry in a #pan{} for ~{3%minutes}.\n", true},
+		{"quantity", "---\ntitle: Pancakes\nservings: 4\nlast_cooked: 2026-01-02\n---\nMix @flour{250%g} and @milk{300%ml}.\n\nFry in a #pan{} for ~{3%minutes}.\n", false},
+		{"unit", "---\ntitle: Pancakes\nservings: 4\nlast_cooked: 2026-01-02\n---\nMix @flour{200%oz} and @milk{300%ml}.\n\nFry in a #pan{} for ~{3%minutes}.\n", false},
+		{"text", "---\ntitle: Pancakes\nservings: 4\nlast_cooked: 2026-01-02\n---\nWhisk @flour{200%g} and @milk{300%ml}.\n\nFry in a #pan{} for ~{3%minutes}.\n", false},
+		{"steps", "---\ntitle: Pancakes\nservings: 4\nlast_cooked: 2026-01-02\n---\nMix @flour{200%g} and @milk{300%ml}.\nFry in a #pan{} for ~{3%minutes}.\n", false},
+		{"servings", "---\ntitle: Pancakes\nservings: 2\nlast_cooked: 2026-01-02\n---\nMix @flour{200%g} and @milk{300%ml}.\n\nFry in a #pan{} for ~{3%minutes}.\n", false},
+		{"metadata", "---\ntitle: Pancakes\nservings: 4\nlast_cooked: 2026-01-02\nsource: Grandma\n---\nMix @flour{200%g} and @milk{300%ml}.\n\nFry in a #pan{} for ~{3%minutes}.\n", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hash(t, tt.content); (got == want) != tt.same {
+				t.Errorf("Hash() = %s, base %s; want same = %v", got, want, tt.same)
+			}
+		})
+	}
+}
+
+func TestRecipeHashKeepsRecipe(t *testing.T) {
+	recipe, err := ParseString("---\nlast_cooked: 2026-01-02\n---\nMix @flour{200%g}.\n")
+	if err != nil {
+		t.Fatalf("ParseString failed: %v", err)
+	}
+	position := recipe.FirstStep.FirstComponent.GetPosition()
+
+	if recipe.Hash() != recipe.Hash() {
+		t.Error("Hash() is not stable")
+	}
+	if recipe.Metadata["last_cooked"] == "" {
+		t.Error("Hash() removed metadata from the recipe")
+	}
+	if recipe.FirstStep.FirstComponent.GetPosition() != position || !position.IsValid() {
+		t.Error("Hash() changed source positions of the recipe")
+	}
+	if recipe.Scale(2).Hash() == recipe.Hash() {
+		t.Error("scaled recipe has the same hash")
+	}
+}
@@ -0,0 +1,173 @@
+package cooklang
+
+import (
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// DefaultImagePatterns are the image names, without extension, that ParseFile looks
+// for next to a recipe. In a pattern, "{name}" is the recipe's file name without
+// ".cook", "{n}" numbers extra recipe images 1, 2, ... (up to the first missing
+// number), and "{step}" is the 0-based index of a step, for step images.
+var DefaultImagePatterns = []string{"{name}", "{name}-{n}", "{name}.{step}"}
+
+// DefaultImageExtensions are the image file extensions that ParseFile looks for, in
+// order of preference.
+var DefaultImageExtensions = []string{".jpg", ".jpeg", ".png"}
+
+// maxNumberedImages is the highest "{n}" tried when detecting numbered images.
+const maxNumberedImages = 99
+
+// ParseFileOptions controls how ParseFileWithOptions reads a recipe file.
+//
+// Example:
+//
+//	opts := cooklang.DefaultParseFileOptions()
+//	opts.ImageExtensions = append(opts.ImageExtensions, ".webp")
+//	recipe, err := cooklang.ParseFileWithOptions("Pancakes.cook", opts)
+type ParseFileOptions struct {
+	DetectImages    bool     // Add images found next to the recipe file to the recipe and its steps
+	ImagePatterns   []string // Image names to look for, relative to the recipe's directory (default DefaultImagePatterns)
+	ImageExtensions []string // Image extensions to look for, e.g. ".webp" (default DefaultImageExtensions)
+}
+
+// DefaultParseFileOptions returns the options ParseFile uses: image detection with
+// the default patterns and extensions.
+//
+// Returns:
+//   - ParseFileOptions: The default options
+func DefaultParseFileOptions() ParseFileOptions {
+	return ParseFileOptions{DetectImages: true}
+}
+
+// AddDetectedImages adds the images next to a recipe file that follow the default
+// naming convention to the recipe and its steps, as ParseFile does.
+//
+// Parameters:
+//   - filename: Path of the recipe's .cook file
+func (r *Recipe) AddDetectedImages(filename string) {
+	r.DetectImages(filename, DefaultParseFileOptions())
+}
+
+// DetectImages looks for images next to a recipe file using the patterns and
+// extensions in opts (the DetectImages field is ignored). Recipe images are added to
+// Images and the "images" metadata, skipping those already listed; step images
+// ("Recipe.0.jpg" for the first step) are added to the Images of their step.
+//
+// Steps are counted from 0 in the order they appear, across sections, leaving out
+// notes and steps with only comments.
+//
+// Parameters:
+//   - filename: Path of the recipe's .cook file
+//   - opts: The patterns and extensions to look for
+//
+// Example:
+//
+//	recipe.DetectImages("Pancakes.cook", cooklang.ParseFileOptions{ImagePatterns: []string{"{name}.{step}"}})
+//	fmt.Println(recipe.FirstStep.Images) // [Pancakes.0.jpg]
+func (r *Recipe) DetectImages(filename string, opts ParseFileOptions) {
+	patterns := opts.ImagePatterns
+	if len(patterns) == 0 {
+		patterns = DefaultImagePatterns
+	}
+	extensions := opts.ImageExtensions
+	if len(extensions) == 0 {
+		extensions = DefaultImageExtensions
+	}
+
+	dir := filepath.Dir(filename)
+	baseName := strings.TrimSuffix(filepath.Base(filename), ".cook")
+	steps := r.imageSteps()
+
+	var detectedImages []string
+	for _, pattern := range patterns {
+		pattern = strings.ReplaceAll(pattern, "{name}", baseName)
+		switch {
+		case strings.Contains(pattern, "{step}"):
+			for i, step := range steps {
+				name := strings.ReplaceAll(pattern, "{step}", strconv.Itoa(i))
+				step.Images = mergeUniqueStrings(step.Images, findImageFiles(dir, name, extensions))
+			}
+		case strings.Contains(pattern, "{n}"):
+			for n := 1; n <= maxNumberedImages; n++ {
+				found := findImageFiles(dir, strings.ReplaceAll(pattern, "{n}", strconv.Itoa(n)), extensions)
+				// Stop at the first number without an image
+				if len(found) == 0 {
+					break
+				}
+				detectedImages = append(detectedImages, found...)
+			}
+		default:
+			detectedImages = append(detectedImages, findImageFiles(dir, pattern, extensions)...)
+		}
+	}
+
+	if len(detectedImages) > 0 {
+		// Merge detected images with existing ones, avoiding duplicates
+		r.Images = mergeUniqueStrings(r.Images, detectedImages)
+		// Update metadata to reflect the merged images
+		if r.Metadata == nil {
+			r.Metadata = make(Metadata)
+		}
+		r.Metadata["images"] = strings.Join(r.Images, ", ")
+	}
+}
+
+// StepImagesKey is the frontmatter key listing step images, one entry per step in
+// the order image detection numbers them (Recipe.0.jpg is the first step's image);
+// an empty entry skips a step:
+//
+//	step_images: [dough.jpg, "", baked.jpg]
+const StepImagesKey = "step_images"
+
+// addStepImages adds the step images listed under StepImagesKey to their steps.
+func (r *Recipe) addStepImages() {
+	value, ok := r.Metadata[StepImagesKey]
+	if !ok {
+		return
+	}
+	steps := r.imageSteps()
+	for i, name := range strings.Split(value, ",") {
+		// Flow lists keep their quotes, so "" is an empty entry too
+		if name = strings.Trim(strings.TrimSpace(name), `"'`); name != "" && i < len(steps) {
+			steps[i].Images = mergeUniqueStrings(steps[i].Images, []string{name})
+		}
+	}
+}
+
+// imageSteps returns the steps that step images are numbered by: every step with
+// displayable content except notes.
+func (r *Recipe) imageSteps() []*Step {
+	var steps []*Step
+	for step := r.FirstStep; step != nil; step = step.NextStep {
+		if step.HasDisplayableContent() && !step.IsNote() {
+			steps = append(steps, step)
+		}
+	}
+	return steps
+}
+
+// findImageFiles returns name plus each extension that exists as a file in dir,
+// slash-separated and relative to dir.
+func findImageFiles(dir, name string, extensions []string) []string {
+	var found []string
+	for _, ext := range extensions {
+		if !strings.HasPrefix(ext, ".") {
+			ext = "." + ext
+		}
+		if fileExists(filepath.Join(dir, filepath.FromSlash(name+ext))) {
+			found = append(found, name+ext)
+		}
+	}
+	return found
+}
+
+// findRecipeImages looks for recipe images matching the default patterns:
+// Recipe.jpg, Recipe.jpeg and Recipe.png, then Recipe-1.jpg, Recipe-2.png, etc.
+// Returns just the filenames (not full paths) of found images.
+func findRecipeImages(cookFilePath string) []string {
+	var recipe Recipe
+	recipe.DetectImages(cookFilePath, ParseFileOptions{})
+	return recipe.Images
+}
@@ -3,6 +3,7 @@ package cooklang
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -429,3 +430,68 @@ func TestFileExists(t *testing.T) {
 		t.Errorf("fileExists returned true for directory")
 	}
 }
+
+func TestParseFileWithStepImages(t *testing.T) {
+	tmpDir := t.TempDir()
+	cookFile := filepath.Join(tmpDir, "Pancakes.cook")
+	content := "> Best on a Sunday.\n\nWhisk @eggs{2} and @milk{250%ml}.\n\nFry in a #pan{}.\n"
+	files := []string{"Pancakes.jpg", "Pancakes.0.png", "Pancakes.1.jpg", "Pancakes.2.jpg", "photos/Pancakes-step1.webp"}
+	_ = os.WriteFile(cookFile, []byte(content), 0644)
+	for _, name := range files {
+		_ = os.MkdirAll(filepath.Join(tmpDir, filepath.Dir(name)), 0755)
+		_ = os.WriteFile(filepath.Join(tmpDir, name), []byte("img"), 0644)
+	}
+
+	recipe, err := ParseFile(cookFile)
+	if err != nil {
+		t.Fatalf("ParseFile failed: %v", err)
+	}
+	if !reflect.DeepEqual(recipe.Images, []string{"Pancakes.jpg"}) {
+		t.Errorf("step images should not be recipe images, got %v", recipe.Images)
+	}
+	var stepImages [][]string
+	for step := recipe.FirstStep; step != nil; step = step.NextStep {
+		stepImages = append(stepImages, step.Images)
+	}
+	// The note is not numbered
+	expected := [][]string{nil, {"Pancakes.0.png"}, {"Pancakes.1.jpg"}}
+	if !reflect.DeepEqual(stepImages, expected) {
+		t.Errorf("step images = %q, want %q", stepImages, expected)
+	}
+
+	recipe, err = ParseFileWithOptions(cookFile, ParseFileOptions{
+		DetectImages:    true,
+		ImagePatterns:   []string{"photos/{name}-step{step}"},
+		ImageExtensions: []string{"webp"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recipe.Images) != 0 || recipe.FirstStep.NextStep.NextStep.Images[0] != "photos/Pancakes-step1.webp" {
+		t.Errorf("custom patterns: recipe images %v, last step images %v", recipe.Images, recipe.FirstStep.NextStep.NextStep.Images)
+	}
+
+	recipe, err = ParseFileWithOptions(cookFile, ParseFileOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recipe.Images) != 0 || recipe.FirstStep.NextStep.Images != nil {
+		t.Error("expected no images with detection disabled")
+	}
+}
+
+func TestStepImagesFrontmatter(t *testing.T) {
+	recipe, err := ParseString("---\nstep_images: [dough.jpg, \"\", baked.jpg]\n---\nMix @flour{500%g}.\n\n> Let it rest.\n\nKnead.\n\nBake.\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var stepImages [][]string
+	for step := recipe.FirstStep; step != nil; step = step.NextStep {
+		stepImages = append(stepImages, step.Images)
+	}
+	// The note is not numbered, and the empty entry skips the second step
+	expected := [][]string{{"dough.jpg"}, nil, nil, {"baked.jpg"}}
+	if !reflect.DeepEqual(stepImages, expected) {
+		t.Errorf("step images = %q, want %q", stepImages, expected)
+	}
+}
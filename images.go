@@ -0,0 +1,544 @@
+package cooklang
+
+import (
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif" // Register GIF decoding for dimension checks
+	"image/jpeg"
+	"image/png"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// WebVariantSuffix is inserted before the extension of a web-sized image variant,
+// so "Pancakes.jpg" gets the variant "Pancakes.web.jpg". Variants are never picked
+// up by image auto-detection, and they are kept as long as their original is in use.
+const WebVariantSuffix = ".web"
+
+// DefaultWebImageSize is the default longest edge, in pixels, of a web-sized variant.
+const DefaultWebImageSize = 1600
+
+// imageExtensions are the file extensions treated as images when auditing a collection.
+var imageExtensions = map[string]bool{".jpg": true, ".jpeg": true, ".png": true, ".gif": true, ".webp": true}
+
+// CollectionImage is an image file found in a recipe collection.
+type CollectionImage struct {
+	Path       string   `json:"path"`                  // Relative to the collection root, slash-separated
+	Recipes    []string `json:"recipes,omitempty"`     // Library paths of the recipes using the image
+	Size       int64    `json:"size"`                  // File size in bytes
+	Width      int      `json:"width,omitempty"`       // Pixel width (0 if the format could not be decoded)
+	Height     int      `json:"height,omitempty"`      // Pixel height (0 if the format could not be decoded)
+	WebVariant string   `json:"web_variant,omitempty"` // Relative path of the existing web-sized variant
+}
+
+// ImageAudit describes the images of a recipe collection: those used by recipes,
+// and orphans that no recipe links to.
+//
+// An image is used by a recipe when it follows the auto-detection naming convention
+// ("Recipe.jpg", "Recipe-1.png", step images like "Recipe.0.jpg", ...) or is listed in
+// the recipe's images frontmatter.
+type ImageAudit struct {
+	Root    string             `json:"root"`
+	Images  []*CollectionImage `json:"images"`  // Images used by at least one recipe, in path order
+	Orphans []*CollectionImage `json:"orphans"` // Images and variants not used by any recipe, in path order
+}
+
+// AuditImages scans the library's root directory for images and matches them
+// against the library's recipes. Hidden directories are skipped.
+//
+// Parameters:
+//   - library: A library loaded from a directory (its Root is scanned)
+//
+// Returns:
+//   - *ImageAudit: The used and orphaned images
+//   - error: An error if the directory could not be scanned
+//
+// Example:
+//
+//	library, _ := cooklang.LoadLibrary("recipes")
+//	audit, err := cooklang.AuditImages(library)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	for _, orphan := range audit.Orphans {
+//	    fmt.Println("unused:", orphan.Path)
+//	}
+func AuditImages(library *Library) (*ImageAudit, error) {
+	root := library.Root
+	if root == "" {
+		root = "."
+	}
+
+	used := make(map[string][]string)
+	for _, entry := range library.Entries {
+		for _, ref := range recipeImageRefs(entry) {
+			used[ref] = append(used[ref], entry.Path)
+		}
+	}
+
+	images := make(map[string]*CollectionImage)
+	variants := make(map[string]*CollectionImage)
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if p != root && strings.HasPrefix(d.Name(), ".") {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if !IsImageFile(p) {
+			return nil
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		img, err := statImage(p, filepath.ToSlash(rel))
+		if err != nil {
+			return err
+		}
+		if IsWebVariant(img.Path) {
+			variants[img.Path] = img
+		} else {
+			images[img.Path] = img
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan images in %s: %w", root, err)
+	}
+
+	audit := &ImageAudit{Root: root, Images: []*CollectionImage{}, Orphans: []*CollectionImage{}}
+	keptVariants := make(map[string]bool)
+	for p, img := range images {
+		if variant, ok := variants[WebVariantPath(p)]; ok {
+			img.WebVariant = variant.Path
+		}
+		if recipes, ok := used[p]; ok {
+			img.Recipes = recipes
+			audit.Images = append(audit.Images, img)
+			keptVariants[img.WebVariant] = true
+		} else {
+			audit.Orphans = append(audit.Orphans, img)
+		}
+	}
+	// A variant is an orphan when its original is missing or unused
+	for p, variant := range variants {
+		if !keptVariants[p] {
+			audit.Orphans = append(audit.Orphans, variant)
+		}
+	}
+
+	sortImages(audit.Images)
+	sortImages(audit.Orphans)
+	return audit, nil
+}
+
+// recipeImageRefs returns the collection-relative paths of the images a recipe uses.
+// Remote URLs and absolute paths are ignored.
+func recipeImageRefs(entry *LibraryEntry) []string {
+	if entry.Recipe == nil {
+		return nil
+	}
+	names := append([]string(nil), entry.Recipe.Images...)
+	if single := entry.Recipe.Metadata["image"]; single != "" {
+		names = append(names, single)
+	}
+	for step := entry.Recipe.FirstStep; step != nil; step = step.NextStep {
+		names = append(names, step.Images...)
+	}
+
+	dir := path.Dir(entry.Path)
+	var refs []string
+	for _, name := range names {
+		name = filepath.ToSlash(strings.TrimSpace(name))
+		if name == "" || strings.Contains(name, "://") || path.IsAbs(name) {
+			continue
+		}
+		refs = append(refs, path.Join(dir, name))
+	}
+	return refs
+}
+
+// statImage reads an image's size and, where the format can be decoded, its dimensions.
+func statImage(p, rel string) (*CollectionImage, error) {
+	info, err := os.Stat(p)
+	if err != nil {
+		return nil, err
+	}
+	img := &CollectionImage{Path: rel, Size: info.Size()}
+
+	file, err := os.Open(p)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+	if config, _, err := image.DecodeConfig(file); err == nil {
+		img.Width, img.Height = config.Width, config.Height
+	}
+	return img, nil
+}
+
+// sortImages orders images by path.
+func sortImages(images []*CollectionImage) {
+	sort.Slice(images, func(i, j int) bool { return images[i].Path < images[j].Path })
+}
+
+// Oversized returns the used images larger than maxBytes on disk.
+//
+// Parameters:
+//   - maxBytes: The largest acceptable file size
+//
+// Returns:
+//   - []*CollectionImage: The oversized originals, in path order
+func (a *ImageAudit) Oversized(maxBytes int64) []*CollectionImage {
+	var result []*CollectionImage
+	for _, img := range a.Images {
+		if img.Size > maxBytes {
+			result = append(result, img)
+		}
+	}
+	return result
+}
+
+// MissingVariants returns the used images whose longest edge exceeds maxDimension
+// and which have no web-sized variant yet. Images that cannot be decoded are skipped.
+//
+// Parameters:
+//   - maxDimension: The longest edge, in pixels, of a web-sized image
+//
+// Returns:
+//   - []*CollectionImage: The images that need a variant, in path order
+func (a *ImageAudit) MissingVariants(maxDimension int) []*CollectionImage {
+	var result []*CollectionImage
+	for _, img := range a.Images {
+		if img.WebVariant == "" && max(img.Width, img.Height) > maxDimension {
+			result = append(result, img)
+		}
+	}
+	return result
+}
+
+// IsImageFile reports whether a path has an image file extension (JPEG, PNG, GIF or WebP).
+func IsImageFile(p string) bool {
+	return imageExtensions[strings.ToLower(filepath.Ext(p))]
+}
+
+// IsWebVariant reports whether an image path names a web-sized variant (e.g., "Pancakes.web.jpg").
+func IsWebVariant(p string) bool {
+	stem := strings.TrimSuffix(p, filepath.Ext(p))
+	return strings.HasSuffix(stem, WebVariantSuffix)
+}
+
+// WebVariantPath returns the path of the web-sized variant of an image,
+// e.g. "sauces/Hollandaise.png" becomes "sauces/Hollandaise.web.png".
+func WebVariantPath(p string) string {
+	ext := filepath.Ext(p)
+	return strings.TrimSuffix(p, ext) + WebVariantSuffix + ext
+}
+
+// WriteWebVariant writes a downscaled copy of a JPEG or PNG image next to it, fitting
+// its longest edge within maxDimension and keeping the original format. An image that
+// already fits is re-encoded at its original size.
+//
+// Parameters:
+//   - src: Path of the original image
+//   - maxDimension: The longest edge, in pixels, of the variant
+//
+// Returns:
+//   - string: Path of the written variant
+//   - error: An error if the image could not be decoded or written
+//
+// Example:
+//
+//	variant, err := cooklang.WriteWebVariant("recipes/Pancakes.jpg", cooklang.DefaultWebImageSize)
+//	// variant == "recipes/Pancakes.web.jpg"
+func WriteWebVariant(src string, maxDimension int) (string, error) {
+	ext := strings.ToLower(filepath.Ext(src))
+	if ext != ".jpg" && ext != ".jpeg" && ext != ".png" {
+		return "", fmt.Errorf("cannot resize %s: only JPEG and PNG images are supported", src)
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return "", err
+	}
+	img, _, err := image.Decode(in)
+	_ = in.Close()
+	if err != nil {
+		return "", fmt.Errorf("failed to decode %s: %w", src, err)
+	}
+
+	bounds := img.Bounds()
+	if longest := max(bounds.Dx(), bounds.Dy()); longest > maxDimension {
+		width := max(1, bounds.Dx()*maxDimension/longest)
+		height := max(1, bounds.Dy()*maxDimension/longest)
+		img = downscale(img, width, height)
+	}
+
+	dst := WebVariantPath(src)
+	out, err := os.Create(dst)
+	if err != nil {
+		return "", err
+	}
+	if ext == ".png" {
+		err = png.Encode(out, img)
+	} else {
+		err = jpeg.Encode(out, img, &jpeg.Options{Quality: 82})
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(dst)
+		return "", fmt.Errorf("failed to write %s: %w", dst, err)
+	}
+	return dst, nil
+}
+
+// downscale resizes an image to width x height by averaging the source pixels
+// that fall into each destination pixel (a box filter).
+func downscale(src image.Image, width, height int) *image.NRGBA {
+	bounds := src.Bounds()
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		y0 := bounds.Min.Y + y*bounds.Dy()/height
+		y1 := max(y0+1, bounds.Min.Y+(y+1)*bounds.Dy()/height)
+		for x := 0; x < width; x++ {
+			x0 := bounds.Min.X + x*bounds.Dx()/width
+			x1 := max(x0+1, bounds.Min.X+(x+1)*bounds.Dx()/width)
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					c := color.NRGBAModel.Convert(src.At(sx, sy)).(color.NRGBA)
+					r += uint64(c.R)
+					g += uint64(c.G)
+					b += uint64(c.B)
+					a += uint64(c.A)
+					n++
+				}
+			}
+			dst.SetNRGBA(x, y, color.NRGBA{R: uint8(r / n), G: uint8(g / n), B: uint8(b / n), A: uint8(a / n)})
+		}
+	}
+	return dst
+}
+
+// ImageMode says how rendered output refers to a recipe's images.
+type ImageMode string
+
+const (
+	ImagesLinked   ImageMode = "link"  // Refer to the image files where they are
+	ImagesEmbedded ImageMode = "embed" // Inline images as base64 data URIs, for single-file output
+	ImagesCopied   ImageMode = "copy"  // Copy images next to the output file
+)
+
+// ParseImageMode parses an image mode name: "link", "embed" or "copy".
+//
+// Parameters:
+//   - s: The mode name (case-insensitive)
+//
+// Returns:
+//   - ImageMode: The mode
+//   - error: An error if the name is unknown
+func ParseImageMode(s string) (ImageMode, error) {
+	switch mode := ImageMode(strings.ToLower(strings.TrimSpace(s))); mode {
+	case ImagesLinked, ImagesEmbedded, ImagesCopied:
+		return mode, nil
+	}
+	return "", fmt.Errorf("unknown image mode %q (supported: link, embed, copy)", s)
+}
+
+// ImageSources returns the src values that output written to outputPath should use
+// for the recipe's images, which are relative to recipeDir:
+//
+//   - ImagesLinked rewrites the paths relative to the output's directory, so they
+//     keep working when the output is written elsewhere (unchanged if outputPath is "")
+//   - ImagesEmbedded reads the images and returns them as data URIs
+//   - ImagesCopied copies the images next to the output, named after it
+//     ("Pancakes.html" gets "Pancakes.jpg", "Pancakes-1.png", ...), and returns their names
+//
+// Remote images (URLs and data URIs) are returned unchanged.
+//
+// Parameters:
+//   - recipe: The recipe whose Images to resolve
+//   - recipeDir: The directory of the recipe file
+//   - outputPath: The file the rendered output is written to ("" for stdout)
+//   - mode: How to refer to the images
+//
+// Returns:
+//   - []string: One src per image, in the order of recipe.Images
+//   - error: An error if an image could not be read or copied
+//
+// Example:
+//
+//	sources, err := cooklang.ImageSources(recipe, "recipes", "out/Pancakes.html", cooklang.ImagesEmbedded)
+//	renderer := renderers.PrintRenderer{Options: renderers.RendererOptions{Images: sources}}
+func ImageSources(recipe *Recipe, recipeDir, outputPath string, mode ImageMode) ([]string, error) {
+	if mode == ImagesCopied && outputPath == "" {
+		return nil, fmt.Errorf("copying images requires an output file")
+	}
+	stem := strings.TrimSuffix(filepath.Base(outputPath), filepath.Ext(outputPath))
+	return imageSources(recipe.Images, recipeDir, outputPath, stem, mode)
+}
+
+// SetStepImageSources replaces the Images of each step with the src values output
+// written to outputPath should use, as ImageSources does for the recipe's images.
+// ImagesCopied names the copies after the output and the step, as image detection
+// finds them: "Pancakes.html" gets "Pancakes.0.jpg" for the first step's image.
+//
+// Parameters:
+//   - recipe: The recipe whose step images to resolve; its steps are changed
+//   - recipeDir: The directory of the recipe file
+//   - outputPath: The file the rendered output is written to ("" for stdout)
+//   - mode: How to refer to the images
+//
+// Returns:
+//   - error: An error if an image could not be read or copied
+//
+// Example:
+//
+//	err := cooklang.SetStepImageSources(recipe, "recipes", "out/Pancakes.html", cooklang.ImagesCopied)
+//	fmt.Println(recipe.FirstStep.Images) // [Pancakes.0.jpg]
+func SetStepImageSources(recipe *Recipe, recipeDir, outputPath string, mode ImageMode) error {
+	stem := strings.TrimSuffix(filepath.Base(outputPath), filepath.Ext(outputPath))
+	for i, step := range recipe.imageSteps() {
+		if len(step.Images) == 0 {
+			continue
+		}
+		if mode == ImagesCopied && outputPath == "" {
+			return fmt.Errorf("copying images requires an output file")
+		}
+		sources, err := imageSources(step.Images, recipeDir, outputPath, stem+"."+strconv.Itoa(i), mode)
+		if err != nil {
+			return err
+		}
+		step.Images = sources
+	}
+	return nil
+}
+
+// imageSources returns the src values of images for output written to outputPath;
+// ImagesCopied names the copies stem, stem-1, stem-2, ...
+func imageSources(names []string, recipeDir, outputPath, stem string, mode ImageMode) ([]string, error) {
+	var sources []string
+	for i, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" || isRemoteImage(name) {
+			sources = append(sources, name)
+			continue
+		}
+		src := name
+		if !filepath.IsAbs(src) {
+			src = filepath.Join(recipeDir, filepath.FromSlash(name))
+		}
+
+		switch mode {
+		case ImagesEmbedded:
+			uri, err := ImageDataURI(src)
+			if err != nil {
+				return nil, err
+			}
+			sources = append(sources, uri)
+		case ImagesCopied:
+			copied := stem
+			if i > 0 {
+				copied = fmt.Sprintf("%s-%d", stem, i)
+			}
+			copied += strings.ToLower(filepath.Ext(src))
+			if err := copyFile(src, filepath.Join(filepath.Dir(outputPath), copied)); err != nil {
+				return nil, err
+			}
+			sources = append(sources, copied)
+		default:
+			sources = append(sources, linkedImage(name, src, outputPath))
+		}
+	}
+	return sources, nil
+}
+
+// ImageDataURI reads an image and returns it as a base64 data URI
+// ("data:image/png;base64,...") that can be used as an img src.
+//
+// Parameters:
+//   - p: Path of the image
+//
+// Returns:
+//   - string: The data URI
+//   - error: An error if the image could not be read
+func ImageDataURI(p string) (string, error) {
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return "", err
+	}
+	contentType := mime.TypeByExtension(strings.ToLower(filepath.Ext(p)))
+	if contentType == "" {
+		contentType = http.DetectContentType(data)
+	}
+	return "data:" + contentType + ";base64," + base64.StdEncoding.EncodeToString(data), nil
+}
+
+// isRemoteImage reports whether an image reference is a URL or data URI rather than a file.
+func isRemoteImage(name string) bool {
+	return strings.Contains(name, "://") || strings.HasPrefix(name, "data:")
+}
+
+// linkedImage returns the path of an image relative to the directory of outputPath,
+// or name unchanged when there is no output file or no relative path.
+func linkedImage(name, src, outputPath string) string {
+	if outputPath == "" || filepath.IsAbs(name) {
+		return name
+	}
+	absSrc, err := filepath.Abs(src)
+	if err != nil {
+		return name
+	}
+	absOut, err := filepath.Abs(filepath.Dir(outputPath))
+	if err != nil {
+		return name
+	}
+	rel, err := filepath.Rel(absOut, absSrc)
+	if err != nil {
+		return name
+	}
+	return filepath.ToSlash(rel)
+}
+
+// copyFile copies src to dst, unless they are the same file.
+func copyFile(src, dst string) error {
+	if absSrc, err := filepath.Abs(src); err == nil {
+		if absDst, err := filepath.Abs(dst); err == nil && absSrc == absDst {
+			return nil
+		}
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = in.Close() }()
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return fmt.Errorf("failed to copy %s: %w", src, err)
+	}
+	return out.Close()
+}
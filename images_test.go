@@ -0,0 +1,235 @@
+package cooklang
+
+import (
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeTestImage writes a solid width x height image, encoded by file extension.
+func writeTestImage(t *testing.T, path string, width, height int) {
+	t.Helper()
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.SetNRGBA(x, y, color.NRGBA{R: 200, G: 100, B: 50, A: 255})
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = file.Close() }()
+	if filepath.Ext(path) == ".png" {
+		err = png.Encode(file, img)
+	} else {
+		err = jpeg.Encode(file, img, nil)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func imagePaths(images []*CollectionImage) []string {
+	paths := []string{}
+	for _, img := range images {
+		paths = append(paths, img.Path)
+	}
+	return paths
+}
+
+func TestAuditImages(t *testing.T) {
+	dir := t.TempDir()
+	recipes := map[string]string{
+		"Pancakes.cook":   "Mix @flour{200%g}.",
+		"sauces/Dip.cook": "---\nimages: photo.png, https://example.com/dip.jpg\n---\nStir @yogurt{1%cup}.",
+	}
+	for name, content := range recipes {
+		if err := writeFile(filepath.Join(dir, name), content); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeTestImage(t, filepath.Join(dir, "Pancakes.jpg"), 40, 20)
+	writeTestImage(t, filepath.Join(dir, "Pancakes-1.png"), 8, 8)
+	writeTestImage(t, filepath.Join(dir, "sauces", "photo.png"), 30, 60)
+	writeTestImage(t, filepath.Join(dir, "sauces", "photo.web.png"), 5, 10)
+	writeTestImage(t, filepath.Join(dir, "Old.png"), 4, 4)
+	writeTestImage(t, filepath.Join(dir, "Gone.web.jpg"), 4, 4)
+	writeTestImage(t, filepath.Join(dir, ".git", "Hidden.png"), 4, 4)
+
+	library, err := LoadLibrary(dir)
+	if err != nil {
+		t.Fatalf("LoadLibrary error: %v", err)
+	}
+	audit, err := AuditImages(library)
+	if err != nil {
+		t.Fatalf("AuditImages error: %v", err)
+	}
+
+	if got, want := imagePaths(audit.Images), []string{"Pancakes-1.png", "Pancakes.jpg", "sauces/photo.png"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Images = %v, want %v", got, want)
+	}
+	if got, want := imagePaths(audit.Orphans), []string{"Gone.web.jpg", "Old.png"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Orphans = %v, want %v", got, want)
+	}
+
+	photo := audit.Images[2]
+	if photo.WebVariant != "sauces/photo.web.png" || photo.Width != 30 || photo.Height != 60 {
+		t.Errorf("photo = %+v, want 30x60 with web variant", photo)
+	}
+	if !reflect.DeepEqual(photo.Recipes, []string{"sauces/Dip.cook"}) {
+		t.Errorf("photo recipes = %v, want [sauces/Dip.cook]", photo.Recipes)
+	}
+
+	if got, want := imagePaths(audit.MissingVariants(16)), []string{"Pancakes.jpg"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("MissingVariants(16) = %v, want %v", got, want)
+	}
+	if got := audit.Oversized(1 << 20); len(got) != 0 {
+		t.Errorf("Oversized(1MB) = %v, want none", imagePaths(got))
+	}
+	if got := audit.Oversized(0); len(got) != 3 {
+		t.Errorf("Oversized(0) = %v, want all 3 images", imagePaths(got))
+	}
+}
+
+func TestWriteWebVariant(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("downscales to fit", func(t *testing.T) {
+		src := filepath.Join(dir, "Wide.jpg")
+		writeTestImage(t, src, 40, 20)
+		variant, err := WriteWebVariant(src, 10)
+		if err != nil {
+			t.Fatalf("WriteWebVariant error: %v", err)
+		}
+		if variant != filepath.Join(dir, "Wide.web.jpg") {
+			t.Errorf("variant = %q, want Wide.web.jpg", variant)
+		}
+		file, err := os.Open(variant)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer func() { _ = file.Close() }()
+		config, format, err := image.DecodeConfig(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if format != "jpeg" || config.Width != 10 || config.Height != 5 {
+			t.Errorf("variant is %s %dx%d, want jpeg 10x5", format, config.Width, config.Height)
+		}
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		src := filepath.Join(dir, "Anim.gif")
+		if err := writeFile(src, "GIF89a"); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := WriteWebVariant(src, 10); err == nil {
+			t.Error("expected an error for GIF images")
+		}
+	})
+}
+
+func TestWebVariantPath(t *testing.T) {
+	if got := WebVariantPath("sauces/Hollandaise.png"); got != "sauces/Hollandaise.web.png" {
+		t.Errorf("WebVariantPath = %q", got)
+	}
+	if !IsWebVariant("sauces/Hollandaise.web.png") || IsWebVariant("sauces/Hollandaise.png") {
+		t.Error("IsWebVariant did not recognize variants")
+	}
+}
+
+func TestImageSources(t *testing.T) {
+	dir := t.TempDir()
+	recipeDir := filepath.Join(dir, "recipes")
+	writeTestImage(t, filepath.Join(recipeDir, "Pancakes.png"), 4, 4)
+	writeTestImage(t, filepath.Join(recipeDir, "photos", "stack.jpg"), 4, 4)
+	recipe := &Recipe{Images: []string{"Pancakes.png", "photos/stack.jpg", "https://example.com/p.jpg"}}
+	output := filepath.Join(dir, "site", "breakfast.html")
+
+	linked, err := ImageSources(recipe, recipeDir, output, ImagesLinked)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"../recipes/Pancakes.png", "../recipes/photos/stack.jpg", "https://example.com/p.jpg"}
+	if !reflect.DeepEqual(linked, expected) {
+		t.Errorf("linked sources = %q", linked)
+	}
+	if stdout, _ := ImageSources(recipe, recipeDir, "", ImagesLinked); !reflect.DeepEqual(stdout, recipe.Images) {
+		t.Errorf("expected paths unchanged without an output file, got %q", stdout)
+	}
+
+	embedded, err := ImageSources(recipe, recipeDir, output, ImagesEmbedded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(embedded[0], "data:image/png;base64,iVBORw0KGgo") || !strings.HasPrefix(embedded[1], "data:image/jpeg;base64,") {
+		t.Errorf("unexpected data URIs: %.40q", embedded)
+	}
+
+	copied, err := ImageSources(recipe, recipeDir, output, ImagesCopied)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(copied, []string{"breakfast.png", "breakfast-1.jpg", "https://example.com/p.jpg"}) {
+		t.Errorf("copied sources = %q", copied)
+	}
+	for _, name := range copied[:2] {
+		if _, err := os.Stat(filepath.Join(dir, "site", name)); err != nil {
+			t.Errorf("expected %s to be copied: %v", name, err)
+		}
+	}
+
+	if _, err := ImageSources(recipe, recipeDir, "", ImagesCopied); err == nil {
+		t.Error("expected an error when copying without an output file")
+	}
+	if _, err := ImageSources(&Recipe{Images: []string{"Missing.jpg"}}, recipeDir, output, ImagesEmbedded); err == nil {
+		t.Error("expected an error for a missing image")
+	}
+	if _, err := ParseImageMode("inline"); err == nil {
+		t.Error("expected an error for an unknown image mode")
+	}
+}
+
+func TestSetStepImageSources(t *testing.T) {
+	dir := t.TempDir()
+	recipeDir := filepath.Join(dir, "recipes")
+	writeTestImage(t, filepath.Join(recipeDir, "Pancakes.0.png"), 4, 4)
+	writeTestImage(t, filepath.Join(recipeDir, "Pancakes.1.jpg"), 4, 4)
+	recipe, err := ParseString("> Best on a Sunday.\n\nWhisk @eggs{2}.\n\nFry in a #pan{}.\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	recipe.FirstStep.NextStep.Images = []string{"Pancakes.0.png"}
+	recipe.FirstStep.NextStep.NextStep.Images = []string{"Pancakes.1.jpg", "https://example.com/fry.jpg"}
+	output := filepath.Join(dir, "site", "breakfast.html")
+
+	linked := recipe.Clone()
+	if err := SetStepImageSources(linked, recipeDir, output, ImagesLinked); err != nil {
+		t.Fatal(err)
+	}
+	if got := linked.FirstStep.NextStep.Images; !reflect.DeepEqual(got, []string{"../recipes/Pancakes.0.png"}) {
+		t.Errorf("linked step images = %q", got)
+	}
+
+	if err := SetStepImageSources(recipe, recipeDir, output, ImagesCopied); err != nil {
+		t.Fatal(err)
+	}
+	if got := recipe.FirstStep.NextStep.NextStep.Images; !reflect.DeepEqual(got, []string{"breakfast.1.jpg", "https://example.com/fry.jpg"}) {
+		t.Errorf("copied step images = %q", got)
+	}
+	for _, name := range []string{"breakfast.0.png", "breakfast.1.jpg"} {
+		if _, err := os.Stat(filepath.Join(dir, "site", name)); err != nil {
+			t.Errorf("expected %s to be copied: %v", name, err)
+		}
+	}
+}
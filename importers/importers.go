@@ -0,0 +1,147 @@
+// Package importers converts recipe collections from other apps into Cooklang.
+//
+// Supported formats are Paprika exports (.paprikarecipes archives or single
+// .paprikarecipe files), Mealie recipe JSON, and Nextcloud Cookbook folders
+// (recipe.json with an optional full.jpg photo). Each recipe is mapped to a
+// Schema.org Recipe and converted with cooklang.ImportSchemaOrg, so ingredient
+// quantities are parsed on a best-effort basis and ingredients are linked into
+// the steps that mention them.
+package importers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/hilli/cooklang"
+)
+
+// Format identifies the app a collection is imported from.
+type Format string
+
+const (
+	FormatPaprika   Format = "paprika"   // Paprika export (.paprikarecipes or .paprikarecipe)
+	FormatMealie    Format = "mealie"    // Mealie recipe JSON (one recipe, a list, or an API page)
+	FormatNextcloud Format = "nextcloud" // Nextcloud Cookbook recipe.json file or folder of recipes
+)
+
+// Formats lists the supported import formats.
+var Formats = []Format{FormatPaprika, FormatMealie, FormatNextcloud}
+
+// Recipe is a recipe converted to Cooklang.
+type Recipe struct {
+	Name     string // Recipe title
+	Cooklang string // The recipe in Cooklang format
+	Image    []byte // Main photo, if the export includes one
+	ImageExt string // Extension for Image (e.g., ".jpg")
+}
+
+// FileName returns a file name for the recipe based on its title, e.g. "Fluffy Pancakes.cook".
+// Characters that are not allowed in file names are removed.
+func (r Recipe) FileName() string {
+	return cooklang.FilenameFromTitle(r.Name)
+}
+
+// ParseFormat parses a format name such as "paprika" (case-insensitive).
+//
+// Parameters:
+//   - name: The format name
+//
+// Returns:
+//   - Format: The format
+//   - error: An error listing the supported formats if the name is unknown
+func ParseFormat(name string) (Format, error) {
+	for _, format := range Formats {
+		if strings.EqualFold(name, string(format)) {
+			return format, nil
+		}
+	}
+	names := make([]string, len(Formats))
+	for i, format := range Formats {
+		names[i] = string(format)
+	}
+	return "", fmt.Errorf("unknown import format %q (supported: %s)", name, strings.Join(names, ", "))
+}
+
+// ImportFile converts the recipes in an export file or folder.
+//
+// Parameters:
+//   - format: The app the export comes from
+//   - path: The export file, or for Nextcloud Cookbook also a folder of recipes
+//
+// Returns:
+//   - []Recipe: The converted recipes
+//   - error: An error if the export cannot be read; recipes that fail to convert are reported
+//     together with the recipes that did convert
+//
+// Example:
+//
+//	recipes, err := importers.ImportFile(importers.FormatPaprika, "backup.paprikarecipes")
+//	for _, recipe := range recipes {
+//	    os.WriteFile(recipe.FileName(), []byte(recipe.Cooklang), 0644)
+//	}
+func ImportFile(format Format, path string) ([]Recipe, error) {
+	switch format {
+	case FormatPaprika:
+		return ImportPaprikaFile(path)
+	case FormatMealie:
+		return ImportMealieFile(path)
+	case FormatNextcloud:
+		return ImportNextcloudPath(path)
+	}
+	return nil, fmt.Errorf("unknown import format %q", format)
+}
+
+// convertRecipe converts a Schema.org Recipe node to Cooklang and appends notes
+// as Cooklang notes ("> ...").
+func convertRecipe(node map[string]interface{}, notes []string) (Recipe, error) {
+	node["@type"] = "Recipe"
+	data, err := json.Marshal(node)
+	if err != nil {
+		return Recipe{}, err
+	}
+	text, err := cooklang.ImportSchemaOrg(data, "")
+	if err != nil {
+		return Recipe{}, err
+	}
+	for _, note := range notes {
+		for _, paragraph := range strings.Split(note, "\n") {
+			if paragraph = strings.Join(strings.Fields(paragraph), " "); paragraph != "" {
+				text += "\n> " + paragraph + "\n"
+			}
+		}
+	}
+
+	name, _ := node["name"].(string)
+	return Recipe{Name: strings.TrimSpace(name), Cooklang: text}, nil
+}
+
+// setImage attaches a photo to a recipe, detecting its file extension.
+func (r *Recipe) setImage(data []byte) {
+	ext := ""
+	switch http.DetectContentType(data) {
+	case "image/jpeg":
+		ext = ".jpg"
+	case "image/png":
+		ext = ".png"
+	case "image/webp":
+		ext = ".webp"
+	case "image/gif":
+		ext = ".gif"
+	}
+	if ext != "" {
+		r.Image, r.ImageExt = data, ext
+	}
+}
+
+// splitLines splits text into trimmed, non-empty lines.
+func splitLines(text string) []string {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
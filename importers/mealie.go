@@ -0,0 +1,178 @@
+package importers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// mealieRecipe is a recipe as returned by the Mealie API (/api/recipes/{slug}).
+type mealieRecipe struct {
+	Name               string              `json:"name"`
+	Description        string              `json:"description"`
+	RecipeYield        string              `json:"recipeYield"`
+	RecipeServings     float64             `json:"recipeServings"`
+	PrepTime           string              `json:"prepTime"`
+	PerformTime        string              `json:"performTime"`
+	TotalTime          string              `json:"totalTime"`
+	OrgURL             string              `json:"orgURL"`
+	RecipeCategory     []mealieName        `json:"recipeCategory"`
+	Tags               []mealieName        `json:"tags"`
+	Tools              []mealieName        `json:"tools"`
+	RecipeIngredient   []mealieIngredient  `json:"recipeIngredient"`
+	RecipeInstructions []mealieInstruction `json:"recipeInstructions"`
+	Notes              []mealieInstruction `json:"notes"`
+}
+
+// mealieName is a category, tag, tool, unit or food reference.
+type mealieName struct {
+	Name string `json:"name"`
+}
+
+// mealieIngredient is an ingredient line, parsed into parts or kept as a note.
+type mealieIngredient struct {
+	Quantity      float64     `json:"quantity"`
+	Unit          *mealieName `json:"unit"`
+	Food          *mealieName `json:"food"`
+	Note          string      `json:"note"`
+	Title         string      `json:"title"` // Starts a group of ingredients
+	OriginalText  string      `json:"originalText"`
+	DisableAmount bool        `json:"disableAmount"`
+}
+
+// line returns the ingredient as a line of text, e.g. "2 cup flour, sifted".
+func (i mealieIngredient) line() string {
+	if i.Food == nil || i.Food.Name == "" {
+		// Unparsed ingredients keep the whole line in the note
+		if i.Note != "" {
+			return i.Note
+		}
+		return i.OriginalText
+	}
+	var parts []string
+	if i.Quantity > 0 && !i.DisableAmount {
+		parts = append(parts, strconv.FormatFloat(i.Quantity, 'f', -1, 64))
+		if i.Unit != nil && i.Unit.Name != "" {
+			parts = append(parts, i.Unit.Name)
+		}
+	}
+	parts = append(parts, i.Food.Name)
+	line := strings.Join(parts, " ")
+	if i.Note != "" {
+		line += ", " + i.Note
+	}
+	return line
+}
+
+// mealieInstruction is a step or note; a title starts a new section.
+type mealieInstruction struct {
+	Title string `json:"title"`
+	Text  string `json:"text"`
+}
+
+// ImportMealieFile converts a Mealie recipe JSON file.
+//
+// Parameters:
+//   - path: A JSON file with one recipe, a list of recipes, or a page of the recipes API ({"items": [...]})
+//
+// Returns:
+//   - []Recipe: The converted recipes
+//   - error: An error if the file cannot be read, joined with errors for recipes that fail to convert
+func ImportMealieFile(path string) ([]Recipe, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ImportMealie(data)
+}
+
+// ImportMealie converts Mealie recipe JSON: one recipe, a list of recipes, or a page
+// of the recipes API ({"items": [...]}).
+//
+// Parameters:
+//   - data: The JSON document
+//
+// Returns:
+//   - []Recipe: The converted recipes
+//   - error: An error if the JSON is invalid, joined with errors for recipes that fail to convert
+func ImportMealie(data []byte) ([]Recipe, error) {
+	var list []mealieRecipe
+	if err := json.Unmarshal(data, &list); err != nil {
+		var page struct {
+			Items []mealieRecipe `json:"items"`
+		}
+		if err := json.Unmarshal(data, &page); err == nil && page.Items != nil {
+			list = page.Items
+		} else {
+			var single mealieRecipe
+			if err := json.Unmarshal(data, &single); err != nil {
+				return nil, fmt.Errorf("invalid Mealie JSON: %w", err)
+			}
+			list = []mealieRecipe{single}
+		}
+	}
+
+	var recipes []Recipe
+	var errs []error
+	for i, m := range list {
+		recipe, err := convertMealieRecipe(m)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("recipe %d (%s): %w", i+1, m.Name, err))
+			continue
+		}
+		recipes = append(recipes, recipe)
+	}
+	return recipes, errors.Join(errs...)
+}
+
+// convertMealieRecipe converts one Mealie recipe.
+func convertMealieRecipe(m mealieRecipe) (Recipe, error) {
+	var ingredients []string
+	for _, ingredient := range m.RecipeIngredient {
+		if line := ingredient.line(); line != "" {
+			ingredients = append(ingredients, line)
+		}
+	}
+
+	// Titled instructions start a section, as a Schema.org HowToSection
+	var instructions []interface{}
+	for _, step := range m.RecipeInstructions {
+		item := map[string]interface{}{"@type": "HowToStep", "text": step.Text}
+		if step.Title != "" {
+			item = map[string]interface{}{"@type": "HowToSection", "name": step.Title, "itemListElement": []interface{}{item}}
+		}
+		instructions = append(instructions, item)
+	}
+
+	yield := m.RecipeYield
+	if m.RecipeServings > 0 {
+		yield = strconv.FormatFloat(m.RecipeServings, 'f', -1, 64)
+	}
+	node := map[string]interface{}{
+		"name":               m.Name,
+		"description":        m.Description,
+		"recipeIngredient":   ingredients,
+		"recipeInstructions": instructions,
+		"recipeYield":        yield,
+		"prepTime":           m.PrepTime,
+		"cookTime":           m.PerformTime,
+		"totalTime":          m.TotalTime,
+		"recipeCategory":     m.RecipeCategory,
+		"keywords":           m.Tags,
+		"tool":               m.Tools,
+		"url":                m.OrgURL,
+	}
+
+	var notes []string
+	for _, note := range m.Notes {
+		text := note.Text
+		if note.Title != "" {
+			text = note.Title + ": " + text
+		}
+		notes = append(notes, text)
+	}
+	return convertRecipe(node, notes)
+}
@@ -0,0 +1,67 @@
+package importers
+
+import (
+	"strings"
+	"testing"
+)
+
+const mealieRecipeJSON = `{
+  "name": "Pesto Pasta",
+  "description": "Quick weeknight pasta.",
+  "recipeYield": "2 portions",
+  "recipeServings": 2,
+  "totalTime": "20 minutes",
+  "orgURL": "https://example.com/pesto",
+  "recipeCategory": [{"name": "Dinner"}],
+  "tags": [{"name": "Quick"}, {"name": "Italian"}],
+  "tools": [{"name": "Food processor"}],
+  "recipeIngredient": [
+    {"quantity": 200, "unit": {"name": "gram"}, "food": {"name": "spaghetti"}, "note": ""},
+    {"quantity": 1, "unit": {"name": "cup"}, "food": {"name": "basil"}, "note": "packed"},
+    {"quantity": 0, "unit": null, "food": null, "note": "Parmesan to serve"}
+  ],
+  "recipeInstructions": [
+    {"title": "Pesto", "text": "Blend the basil in the food processor."},
+    {"title": "", "text": "Boil the spaghetti for 10 minutes and toss with the pesto."}
+  ],
+  "notes": [{"title": "Tip", "text": "Save some pasta water."}]
+}`
+
+func TestImportMealie(t *testing.T) {
+	for name, input := range map[string]string{
+		"single": mealieRecipeJSON,
+		"list":   "[" + mealieRecipeJSON + "]",
+		"page":   `{"page": 1, "items": [` + mealieRecipeJSON + `]}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			recipes, err := ImportMealie([]byte(input))
+			if err != nil {
+				t.Fatalf("ImportMealie failed: %v", err)
+			}
+			if len(recipes) != 1 || recipes[0].Name != "Pesto Pasta" {
+				t.Fatalf("unexpected recipes: %+v", recipes)
+			}
+			text := recipes[0].Cooklang
+			for _, want := range []string{
+				"servings: 2",
+				"total_time: 20 minutes",
+				"category: Dinner",
+				"  - Quick",
+				"source: https://example.com/pesto",
+				"Gather @Parmesan to serve{}.",
+				"= Pesto =",
+				"Blend the @basil{1%cup}(packed) in the #Food processor{}.",
+				"Boil the @spaghetti{200%g} for ~{10%minutes} and toss with the pesto.",
+				"> Tip: Save some pasta water.",
+			} {
+				if !strings.Contains(text, want) {
+					t.Errorf("converted recipe is missing %q:\n%s", want, text)
+				}
+			}
+		})
+	}
+
+	if _, err := ImportMealie([]byte("{not json")); err == nil {
+		t.Error("expected an error for invalid JSON")
+	}
+}
@@ -0,0 +1,98 @@
+package importers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ImportNextcloudPath converts Nextcloud Cookbook recipes. The path is a recipe.json
+// file or a folder, such as the Cookbook folder in Nextcloud Files, that is searched
+// for recipe.json files. A full.jpg next to a recipe.json is used as its photo.
+//
+// Parameters:
+//   - path: A recipe.json file or a folder of recipe folders
+//
+// Returns:
+//   - []Recipe: The converted recipes
+//   - error: An error if the path cannot be read, joined with errors for recipes that fail to convert
+func ImportNextcloudPath(path string) ([]Recipe, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		recipe, err := importNextcloudFile(path)
+		if err != nil {
+			return nil, err
+		}
+		return []Recipe{recipe}, nil
+	}
+
+	var recipes []Recipe
+	var errs []error
+	err = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			errs = append(errs, err)
+			return nil
+		}
+		if d.IsDir() || d.Name() != "recipe.json" {
+			return nil
+		}
+		recipe, err := importNextcloudFile(p)
+		if err != nil {
+			errs = append(errs, err)
+			return nil
+		}
+		recipes = append(recipes, recipe)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(recipes) == 0 && len(errs) == 0 {
+		return nil, fmt.Errorf("no recipe.json files found in %s", path)
+	}
+	return recipes, errors.Join(errs...)
+}
+
+// importNextcloudFile converts a recipe.json file and picks up its full.jpg photo.
+func importNextcloudFile(path string) (Recipe, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Recipe{}, err
+	}
+	recipe, err := ImportNextcloud(data)
+	if err != nil {
+		return Recipe{}, fmt.Errorf("%s: %w", path, err)
+	}
+	if photo, err := os.ReadFile(filepath.Join(filepath.Dir(path), "full.jpg")); err == nil {
+		recipe.setImage(photo)
+	}
+	return recipe, nil
+}
+
+// ImportNextcloud converts a Nextcloud Cookbook recipe.json document. Nextcloud
+// Cookbook stores recipes as Schema.org JSON, including tools, which become cookware.
+//
+// Parameters:
+//   - data: The recipe.json contents
+//
+// Returns:
+//   - Recipe: The converted recipe
+//   - error: An error if the JSON is invalid
+func ImportNextcloud(data []byte) (Recipe, error) {
+	var node map[string]interface{}
+	if err := json.Unmarshal(data, &node); err != nil {
+		return Recipe{}, fmt.Errorf("invalid Nextcloud Cookbook JSON: %w", err)
+	}
+	// Only web images are kept; local paths point into Nextcloud, whose photo is full.jpg
+	if image, ok := node["image"].(string); ok && !strings.HasPrefix(image, "http://") && !strings.HasPrefix(image, "https://") {
+		delete(node, "image")
+	}
+	return convertRecipe(node, nil)
+}
@@ -0,0 +1,80 @@
+package importers
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestImportNextcloudPath(t *testing.T) {
+	dir := t.TempDir()
+	recipeDir := filepath.Join(dir, "Shakshuka")
+	if err := os.MkdirAll(recipeDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	recipeJSON := `{
+  "@context": "http://schema.org",
+  "@type": "Recipe",
+  "name": "Shakshuka",
+  "image": "/apps/cookbook/recipes/12/image",
+  "recipeYield": 3,
+  "prepTime": "PT10M",
+  "cookTime": "PT25M",
+  "recipeCategory": "Breakfast",
+  "keywords": "eggs,spicy",
+  "tool": ["skillet"],
+  "recipeIngredient": ["6 eggs", "400 g canned tomatoes", "1 tsp cumin"],
+  "recipeInstructions": ["Simmer the tomatoes and cumin in the skillet for 15 minutes.", "Crack in the eggs and cook until set."]
+}`
+	if err := os.WriteFile(filepath.Join(recipeDir, "recipe.json"), []byte(recipeJSON), 0644); err != nil {
+		t.Fatal(err)
+	}
+	jpeg := []byte("\xff\xd8\xff\xe0\x00\x10JFIF\x00")
+	if err := os.WriteFile(filepath.Join(recipeDir, "full.jpg"), jpeg, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	recipes, err := ImportNextcloudPath(dir)
+	if err != nil {
+		t.Fatalf("ImportNextcloudPath failed: %v", err)
+	}
+	if len(recipes) != 1 {
+		t.Fatalf("expected 1 recipe, got %d", len(recipes))
+	}
+	got := recipes[0]
+	for _, want := range []string{
+		"title: Shakshuka",
+		"servings: 3",
+		"prep_time: 10 minutes",
+		"  - spicy",
+		"Simmer the @canned tomatoes{400%g} and @cumin{1%tsp} in the #skillet{} for ~{15%minutes}.",
+		"Crack in the @eggs{6} and cook until set.",
+	} {
+		if !strings.Contains(got.Cooklang, want) {
+			t.Errorf("converted recipe is missing %q:\n%s", want, got.Cooklang)
+		}
+	}
+	if strings.Contains(got.Cooklang, "/apps/cookbook") {
+		t.Errorf("local image path should be dropped:\n%s", got.Cooklang)
+	}
+	if got.ImageExt != ".jpg" {
+		t.Errorf("expected full.jpg as the photo, got extension %q", got.ImageExt)
+	}
+
+	if _, err := ImportNextcloudPath(t.TempDir()); err == nil {
+		t.Error("expected an error for a folder without recipes")
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	if format, err := ParseFormat("Paprika"); err != nil || format != FormatPaprika {
+		t.Errorf("ParseFormat(Paprika) = %q, %v", format, err)
+	}
+	if _, err := ParseFormat("evernote"); err == nil || !strings.Contains(err.Error(), "paprika, mealie, nextcloud") {
+		t.Errorf("expected an error listing the formats, got %v", err)
+	}
+	if name := (Recipe{Name: "Mac/Cheese: Deluxe"}).FileName(); name != "MacCheese Deluxe.cook" {
+		t.Errorf("unexpected file name %q", name)
+	}
+}
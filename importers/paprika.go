@@ -0,0 +1,136 @@
+package importers
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// paprikaRecipe is a recipe in a Paprika export.
+type paprikaRecipe struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Ingredients string   `json:"ingredients"` // One ingredient per line
+	Directions  string   `json:"directions"`  // One step per line
+	Notes       string   `json:"notes"`
+	Servings    string   `json:"servings"`
+	PrepTime    string   `json:"prep_time"`
+	CookTime    string   `json:"cook_time"`
+	TotalTime   string   `json:"total_time"`
+	Source      string   `json:"source"`
+	SourceURL   string   `json:"source_url"`
+	Categories  []string `json:"categories"`
+	PhotoData   string   `json:"photo_data"` // Base64-encoded photo
+}
+
+// ImportPaprikaFile converts a Paprika export: a .paprikarecipes archive holding many
+// recipes, or a single gzipped .paprikarecipe file.
+//
+// Parameters:
+//   - path: The export file
+//
+// Returns:
+//   - []Recipe: The converted recipes, with photos where the export includes them
+//   - error: An error if the file cannot be read, joined with errors for recipes that fail to convert
+func ImportPaprikaFile(path string) ([]Recipe, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ImportPaprika(data)
+}
+
+// ImportPaprika converts Paprika export data (.paprikarecipes or .paprikarecipe contents).
+//
+// Parameters:
+//   - data: The export file contents
+//
+// Returns:
+//   - []Recipe: The converted recipes
+//   - error: An error if the data is not a Paprika export, joined with errors for recipes that fail to convert
+func ImportPaprika(data []byte) ([]Recipe, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		recipe, err := convertPaprikaEntry("recipe", bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("not a Paprika export: %w", err)
+		}
+		return []Recipe{recipe}, nil
+	}
+
+	var recipes []Recipe
+	var errs []error
+	for _, file := range archive.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+		rc, err := file.Open()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", file.Name, err))
+			continue
+		}
+		recipe, err := convertPaprikaEntry(file.Name, rc)
+		_ = rc.Close()
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		recipes = append(recipes, recipe)
+	}
+	return recipes, errors.Join(errs...)
+}
+
+// convertPaprikaEntry converts one gzipped Paprika recipe.
+func convertPaprikaEntry(name string, r io.Reader) (Recipe, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return Recipe{}, fmt.Errorf("%s: %w", name, err)
+	}
+	defer func() { _ = gz.Close() }()
+
+	var p paprikaRecipe
+	if err := json.NewDecoder(gz).Decode(&p); err != nil {
+		return Recipe{}, fmt.Errorf("%s: invalid recipe JSON: %w", name, err)
+	}
+
+	var ingredients []string
+	for _, line := range splitLines(p.Ingredients) {
+		// Paprika has no ingredient groups; lines like "For the sauce:" are headings
+		if !strings.HasSuffix(line, ":") {
+			ingredients = append(ingredients, line)
+		}
+	}
+	node := map[string]interface{}{
+		"name":               p.Name,
+		"description":        p.Description,
+		"recipeIngredient":   ingredients,
+		"recipeInstructions": splitLines(p.Directions),
+		"recipeYield":        p.Servings,
+		"prepTime":           p.PrepTime,
+		"cookTime":           p.CookTime,
+		"totalTime":          p.TotalTime,
+		"keywords":           p.Categories,
+		"url":                p.SourceURL,
+	}
+	if p.SourceURL == "" && p.Source != "" {
+		node["url"] = p.Source
+	}
+
+	recipe, err := convertRecipe(node, []string{p.Notes})
+	if err != nil {
+		return Recipe{}, fmt.Errorf("%s: %w", name, err)
+	}
+	if p.PhotoData != "" {
+		if photo, err := base64.StdEncoding.DecodeString(p.PhotoData); err == nil {
+			recipe.setImage(photo)
+		}
+	}
+	return recipe, nil
+}
@@ -0,0 +1,110 @@
+package importers
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"image"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+// gzipJSON returns a value as gzipped JSON, like a .paprikarecipe file.
+func gzipJSON(t *testing.T, value interface{}) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if err := json.NewEncoder(gz).Encode(value); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestImportPaprika(t *testing.T) {
+	var photo bytes.Buffer
+	if err := png.Encode(&photo, image.NewGray(image.Rect(0, 0, 2, 2))); err != nil {
+		t.Fatal(err)
+	}
+	soup := paprikaRecipe{
+		Name:        "Tomato Soup",
+		Ingredients: "For the soup:\n1 kg tomatoes\n2 cloves garlic, sliced\n\n500 ml stock",
+		Directions:  "Roast the tomatoes and garlic for 30 minutes.\n\nBlend with the stock.",
+		Notes:       "Freezes well.\nServe with bread.",
+		Servings:    "4 servings",
+		CookTime:    "40 mins",
+		SourceURL:   "https://example.com/soup",
+		Categories:  []string{"Soups", "Vegetarian"},
+		PhotoData:   base64.StdEncoding.EncodeToString(photo.Bytes()),
+	}
+	toast := paprikaRecipe{Name: "Toast", Ingredients: "1 slice bread", Directions: "Toast the bread."}
+
+	var archive bytes.Buffer
+	zw := zip.NewWriter(&archive)
+	for _, r := range []paprikaRecipe{soup, toast} {
+		w, err := zw.Create(r.Name + ".paprikarecipe")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write(gzipJSON(t, r)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	w, err := zw.Create("broken.paprikarecipe")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = w.Write([]byte("not gzip"))
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	recipes, err := ImportPaprika(archive.Bytes())
+	if err == nil || !strings.Contains(err.Error(), "broken.paprikarecipe") {
+		t.Errorf("expected an error for the broken recipe, got %v", err)
+	}
+	if len(recipes) != 2 {
+		t.Fatalf("expected 2 recipes, got %d", len(recipes))
+	}
+
+	got := recipes[0]
+	if got.Name != "Tomato Soup" || got.FileName() != "Tomato Soup.cook" {
+		t.Errorf("unexpected name %q / file name %q", got.Name, got.FileName())
+	}
+	for _, want := range []string{
+		"servings: 4",
+		"yield: 4 servings",
+		"cook_time: 40 mins",
+		"  - Soups",
+		"source: https://example.com/soup",
+		"Roast the @tomatoes{1%kg} and @garlic{2%cloves}(sliced) for ~{30%minutes}.",
+		"Blend with the @stock{500%ml}.",
+		"> Freezes well.\n",
+		"> Serve with bread.\n",
+	} {
+		if !strings.Contains(got.Cooklang, want) {
+			t.Errorf("converted recipe is missing %q:\n%s", want, got.Cooklang)
+		}
+	}
+	if strings.Contains(got.Cooklang, "For the soup") {
+		t.Errorf("ingredient heading should be skipped:\n%s", got.Cooklang)
+	}
+	if got.ImageExt != ".png" || !bytes.Equal(got.Image, photo.Bytes()) {
+		t.Errorf("expected the PNG photo, got %d bytes with extension %q", len(got.Image), got.ImageExt)
+	}
+
+	// A single .paprikarecipe file
+	recipes, err = ImportPaprika(gzipJSON(t, toast))
+	if err != nil || len(recipes) != 1 || !strings.Contains(recipes[0].Cooklang, "Toast the @bread{1%slice}.") {
+		t.Errorf("single recipe import failed: %v %+v", err, recipes)
+	}
+
+	if _, err := ImportPaprika([]byte("plain text")); err == nil {
+		t.Error("expected an error for data that is not a Paprika export")
+	}
+}
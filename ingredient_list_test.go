@@ -1,6 +1,7 @@
 package cooklang
 
 import (
+	"errors"
 	"testing"
 )
 
@@ -23,7 +24,7 @@ Add @vanilla{some} extract.
 	ingredients := recipe.GetIngredients()
 	t.Logf("Found %d ingredients:", len(ingredients.Ingredients))
 	for i, ing := range ingredients.Ingredients {
-		t.Logf("  %d: %s - %g %s", i+1, ing.Name, ing.Quantity, ing.Unit)
+		t.Logf("  %d: %s - %g %s", i+1, ing.Name, ing.Quantity.Min(), ing.Unit)
 	}
 
 	if len(ingredients.Ingredients) != 7 {
@@ -45,11 +46,11 @@ Add @vanilla{some} extract.
 		}
 	}
 
-	// Check flour - should be consolidated (500g + 500g = 1000g)
+	// Check flour - should be consolidated in the larger unit (500g + 0.5kg = 1kg)
 	if flour, exists := ingredientMap["flour"]; exists {
 		t.Logf("Consolidated flour: %s", flour)
-		if flour != "1000 g" {
-			t.Errorf("Expected flour to be '1000 g', got '%s'", flour)
+		if flour != "1 kg" {
+			t.Errorf("Expected flour to be '1 kg', got '%s'", flour)
 		}
 	} else {
 		t.Error("Flour not found in consolidated list")
@@ -139,10 +140,10 @@ Mix with @flour{200%g} and @sugar{100%g}.
 	}
 
 	// Check quantities
-	expectedQuantities := []float32{500, 200}
+	expectedQuantities := []float64{500, 200}
 	for i, ing := range flourIngredients {
-		if ing.Quantity != expectedQuantities[i] {
-			t.Errorf("Expected flour quantity %g, got %g", expectedQuantities[i], ing.Quantity)
+		if ing.Quantity.Min() != expectedQuantities[i] {
+			t.Errorf("Expected flour quantity %g, got %v", expectedQuantities[i], ing.Quantity)
 		}
 	}
 }
@@ -166,7 +167,7 @@ func TestUnitTypeIdentification(t *testing.T) {
 	for _, test := range tests {
 		ingredient := &Ingredient{
 			Name:      "test",
-			Quantity:  1,
+			Quantity:  NewQuantity(1, 1),
 			Unit:      test.unit,
 			TypedUnit: CreateTypedUnit(test.unit),
 		}
@@ -181,3 +182,94 @@ func TestUnitTypeIdentification(t *testing.T) {
 		}
 	}
 }
+
+func TestGetIngredientsDetached(t *testing.T) {
+	recipe, err := ParseString("Mix @flour{500%g} and @milk{1%cup} in a #bowl{}.\n\nFold in the @&flour{} and @flour{100%g}.")
+	if err != nil {
+		t.Fatalf("Failed to parse recipe: %v", err)
+	}
+	before, components := recipe.Render(), countComponents(recipe)
+
+	ingredients := recipe.GetIngredients()
+	for _, ingredient := range ingredients.Ingredients {
+		if ingredient.NextComponent != nil {
+			t.Errorf("%s: expected a detached copy, got a link to %T", ingredient.Name, ingredient.NextComponent)
+		}
+	}
+	ingredients.Ingredients[0].Quantity = NewQuantity(1, 1)
+	ingredients.Ingredients[0].Unit = "kg"
+	if _, err := ingredients.ConsolidateByName(""); err != nil {
+		t.Fatalf("ConsolidateByName failed: %v", err)
+	}
+	_ = ingredients.ConvertToSystem(UnitSystemUS)
+	if got := recipe.Render(); got != before {
+		t.Errorf("changing the list changed the recipe:\n%s\nwant:\n%s", got, before)
+	}
+	if got := countComponents(recipe); got != components {
+		t.Errorf("expected the steps to keep their %d components, got %d", components, got)
+	}
+}
+
+// countComponents counts the components of a recipe's steps.
+func countComponents(recipe *Recipe) int {
+	count := 0
+	for step := range recipe.Steps() {
+		for range step.Components() {
+			count++
+		}
+	}
+	return count
+}
+
+func TestIngredientListApplyTo(t *testing.T) {
+	recipe, err := ParseString("Mix @flour{500%g} and @milk{1%cup}.\n\nFold in the @&flour{} and @eggs{2.5}.")
+	if err != nil {
+		t.Fatalf("Failed to parse recipe: %v", err)
+	}
+
+	converted := recipe.GetIngredients().ConvertToSystem(UnitSystemUS).RoundCounts(CountRoundUp)
+	converted.Add(NewIngredient("salt", 1, "tsp")) // Not from the recipe
+	clone := recipe.Clone()
+	if err := converted.ApplyTo(clone); err != nil {
+		t.Fatalf("ApplyTo failed: %v", err)
+	}
+
+	got := clone.GetIngredients().Ingredients
+	if len(got) != 3 {
+		t.Fatalf("expected 3 ingredients, got %d", len(got))
+	}
+	if got[0].Name != "flour" || got[0].Unit != "oz" || got[0].Quantity.Min() != converted.Ingredients[0].Quantity.Min() {
+		t.Errorf("expected flour in oz, got %v %s %s", got[0].Quantity, got[0].Unit, got[0].Name)
+	}
+	if got[2].Name != "eggs" || got[2].Quantity.Min() != 3 {
+		t.Errorf("expected 3 eggs, got %v %s", got[2].Quantity, got[2].Name)
+	}
+	if got[2].Position != recipe.GetIngredients().Ingredients[2].Position {
+		t.Errorf("expected the eggs to keep their position, got %v", got[2].Position)
+	}
+	var reference *Ingredient
+	clone.WalkIngredients(func(ingredient *Ingredient) bool {
+		if ingredient.IsReference() {
+			reference = ingredient
+		}
+		return true
+	})
+	if reference == nil || reference.Name != "flour" {
+		t.Errorf("expected the reference to be kept, got %+v", reference)
+	}
+	if recipe.GetIngredients().Ingredients[0].Unit != "g" {
+		t.Error("ApplyTo changed the original recipe")
+	}
+
+	// Lists that are not from the recipe
+	if err := NewIngredientList().ApplyTo(clone); !errors.Is(err, ErrDetachedIngredients) {
+		t.Errorf("expected ErrDetachedIngredients for a new list, got %v", err)
+	}
+	small, _ := ParseString("Boil @water{1%l}.")
+	if err := converted.ApplyTo(small); !errors.Is(err, ErrDetachedIngredients) {
+		t.Errorf("expected ErrDetachedIngredients for another recipe, got %v", err)
+	}
+	if small.GetIngredients().Ingredients[0].Name != "water" {
+		t.Error("a failed ApplyTo changed the recipe")
+	}
+}
@@ -0,0 +1,91 @@
+package cooklang
+
+import (
+	"sort"
+)
+
+// IngredientMatch is a recipe found by Library.MatchByIngredients, with the
+// ingredients it needs split into those available and those missing.
+type IngredientMatch struct {
+	Entry    *LibraryEntry `json:"entry"`
+	Have     []string      `json:"have"`               // Ingredients of the recipe that are available
+	Missing  []string      `json:"missing"`            // Ingredients of the recipe to buy; optional ones are left out
+	Coverage float64       `json:"coverage"`           // Share of the ingredients available, not counting missing optional ones, 0 to 1
+	Optional []string      `json:"optional,omitempty"` // Optional ingredients that are not available
+}
+
+// MatchByIngredients answers "what can I cook?": it ranks the library's recipes by
+// how many of their ingredients are available, listing the missing ones.
+// Ingredient names match case-insensitively and fuzzily, as in searches: "egg"
+// finds "eggs", and "chicken" finds "chicken thighs". Optional ingredients never
+// count as missing.
+//
+// Parameters:
+//   - available: The ingredients at hand, e.g. from a pantry
+//
+// Returns:
+//   - []IngredientMatch: The recipes using at least one available ingredient, those
+//     with the most of their ingredients available first, then those using the most
+//     available ingredients, then by path
+//
+// Example:
+//
+//	for _, match := range library.MatchByIngredients([]string{"chicken", "rice", "onion"}) {
+//	    fmt.Printf("%s: missing %s\n", match.Entry.Name(), strings.Join(match.Missing, ", "))
+//	}
+func (l *Library) MatchByIngredients(available []string) []IngredientMatch {
+	var keys []string
+	for _, name := range available {
+		if key := libraryKey(name); key != "" {
+			keys = append(keys, key)
+		}
+	}
+
+	var matches []IngredientMatch
+	for _, entry := range l.Entries {
+		if entry.Recipe == nil {
+			continue
+		}
+		match := IngredientMatch{Entry: entry, Have: []string{}, Missing: []string{}}
+		seen := map[string]bool{}
+		for _, ingredient := range entry.Recipe.GetIngredients().Ingredients {
+			key := libraryKey(ingredient.Name)
+			if key == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+			switch {
+			case isAvailable(key, keys):
+				match.Have = append(match.Have, ingredient.Name)
+			case ingredient.Optional:
+				match.Optional = append(match.Optional, ingredient.Name)
+			default:
+				match.Missing = append(match.Missing, ingredient.Name)
+			}
+		}
+		if len(match.Have) == 0 {
+			continue
+		}
+		match.Coverage = float64(len(match.Have)) / float64(len(match.Have)+len(match.Missing))
+		matches = append(matches, match)
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Coverage != matches[j].Coverage {
+			return matches[i].Coverage > matches[j].Coverage
+		}
+		return len(matches[i].Have) > len(matches[j].Have)
+	})
+	return matches
+}
+
+// isAvailable reports whether an ingredient, by its lowercased name, is one of the
+// available ones.
+func isAvailable(ingredient string, available []string) bool {
+	for _, key := range available {
+		if ingredientSimilarity(ingredient, key) >= fuzzyThreshold {
+			return true
+		}
+	}
+	return false
+}
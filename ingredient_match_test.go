@@ -0,0 +1,42 @@
+package cooklang
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLibraryMatchByIngredients(t *testing.T) {
+	library := NewLibrary()
+	for path, content := range map[string]string{
+		"fried-rice.cook": "Fry @rice{300%g} with @onion{1}, @eggs{2} and @soy sauce{2%tbsp}.",
+		"pilaf.cook":      "Toast @rice{300%g} with @onions{2}, then add @chicken thighs{4} and @?parsley{}.",
+		"curry.cook":      "Simmer @chicken{500%g}, @coconut milk{400%ml}, @curry paste{2%tbsp} and @lime{1}.",
+		"tacos.cook":      "Fill @tortillas{8} with @beef{500%g}.",
+	} {
+		recipe, err := ParseString(content)
+		if err != nil {
+			t.Fatal(err)
+		}
+		library.Add(path, recipe)
+	}
+
+	matches := library.MatchByIngredients([]string{"Chicken", "rice", "onion", " egg "})
+	var got []string
+	for _, match := range matches {
+		got = append(got, match.Entry.Path+": "+strings.Join(match.Missing, ","))
+	}
+	if want := "pilaf.cook: |fried-rice.cook: soy sauce|curry.cook: coconut milk,curry paste,lime"; strings.Join(got, "|") != want {
+		t.Fatalf("matches = %q", got)
+	}
+	pilaf := matches[0]
+	if pilaf.Coverage != 1 || strings.Join(pilaf.Have, ",") != "rice,onions,chicken thighs" || strings.Join(pilaf.Optional, ",") != "parsley" {
+		t.Errorf("unexpected pilaf match: %+v", pilaf)
+	}
+	if matches[1].Coverage != 0.75 {
+		t.Errorf("fried rice coverage = %v, want 0.75", matches[1].Coverage)
+	}
+
+	if matches := library.MatchByIngredients(nil); len(matches) != 0 {
+		t.Errorf("expected no matches without ingredients, got %d", len(matches))
+	}
+}
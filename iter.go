@@ -0,0 +1,109 @@
+package cooklang
+
+import "iter"
+
+// Steps returns an iterator over the recipe's steps in order, for use with range.
+//
+// Returns:
+//   - iter.Seq[*Step]: The steps; empty for a nil recipe
+//
+// Example:
+//
+//	for step := range recipe.Steps() {
+//	    fmt.Println(len(step.Images))
+//	}
+func (r *Recipe) Steps() iter.Seq[*Step] {
+	return func(yield func(*Step) bool) {
+		if r == nil {
+			return
+		}
+		for step := r.FirstStep; step != nil; step = step.NextStep {
+			if !yield(step) {
+				return
+			}
+		}
+	}
+}
+
+// Components returns an iterator over the step's components in order, for use with
+// range.
+//
+// Returns:
+//   - iter.Seq[StepComponent]: The components; empty for a nil step
+//
+// Example:
+//
+//	for component := range step.Components() {
+//	    if ingredient, ok := component.(*cooklang.Ingredient); ok {
+//	        fmt.Println(ingredient.Name)
+//	    }
+//	}
+func (s *Step) Components() iter.Seq[StepComponent] {
+	return func(yield func(StepComponent) bool) {
+		if s == nil {
+			return
+		}
+		for component := s.FirstComponent; component != nil; component = component.GetNext() {
+			if !yield(component) {
+				return
+			}
+		}
+	}
+}
+
+// WalkComponents calls fn for every component of every step, in order. Walking stops
+// early when fn returns false.
+//
+// Parameters:
+//   - fn: Called with each step and one of its components
+//
+// Example:
+//
+//	recipe.WalkComponents(func(step *cooklang.Step, c cooklang.StepComponent) bool {
+//	    _, isTimer := c.(*cooklang.Timer)
+//	    return !isTimer // stop at the first timer
+//	})
+func (r *Recipe) WalkComponents(fn func(step *Step, component StepComponent) bool) {
+	for step := range r.Steps() {
+		for component := range step.Components() {
+			if !fn(step, component) {
+				return
+			}
+		}
+	}
+}
+
+// WalkIngredients calls fn for every ingredient in the recipe, in order of
+// appearance. Walking stops early when fn returns false.
+//
+// Parameters:
+//   - fn: Called with each ingredient
+//
+// Example:
+//
+//	recipe.WalkIngredients(func(i *cooklang.Ingredient) bool {
+//	    fmt.Println(i.Name)
+//	    return true
+//	})
+func (r *Recipe) WalkIngredients(fn func(ingredient *Ingredient) bool) {
+	r.WalkComponents(func(_ *Step, component StepComponent) bool {
+		if ingredient, ok := component.(*Ingredient); ok {
+			return fn(ingredient)
+		}
+		return true
+	})
+}
+
+// WalkCookware calls fn for every cookware item in the recipe, in order of
+// appearance. Walking stops early when fn returns false.
+//
+// Parameters:
+//   - fn: Called with each cookware item
+func (r *Recipe) WalkCookware(fn func(cookware *Cookware) bool) {
+	r.WalkComponents(func(_ *Step, component StepComponent) bool {
+		if cw, ok := component.(*Cookware); ok {
+			return fn(cw)
+		}
+		return true
+	})
+}
@@ -0,0 +1,68 @@
+package cooklang
+
+import "testing"
+
+func TestRecipeIterators(t *testing.T) {
+	recipe, err := ParseString("Crack @eggs{2} into a #bowl{}.\n\nWhisk with @milk{100%ml} for ~{1%minute}.\n\nHeat the #pan{}.")
+	if err != nil {
+		t.Fatalf("ParseString failed: %v", err)
+	}
+
+	var steps int
+	var components int
+	for step := range recipe.Steps() {
+		steps++
+		for range step.Components() {
+			components++
+		}
+	}
+	if steps != 3 {
+		t.Errorf("expected 3 steps, got %d", steps)
+	}
+	if components == 0 {
+		t.Error("expected components")
+	}
+
+	var names []string
+	recipe.WalkIngredients(func(i *Ingredient) bool {
+		names = append(names, i.Name)
+		return true
+	})
+	if len(names) != 2 || names[0] != "eggs" || names[1] != "milk" {
+		t.Errorf("unexpected ingredients: %v", names)
+	}
+
+	var cookware []string
+	recipe.WalkCookware(func(cw *Cookware) bool {
+		cookware = append(cookware, cw.Name)
+		return true
+	})
+	if len(cookware) != 2 || cookware[0] != "bowl" || cookware[1] != "pan" {
+		t.Errorf("unexpected cookware: %v", cookware)
+	}
+
+	// Stopping early
+	var visited int
+	recipe.WalkComponents(func(_ *Step, c StepComponent) bool {
+		visited++
+		_, isTimer := c.(*Timer)
+		return !isTimer
+	})
+	if visited == 0 || visited >= components {
+		t.Errorf("expected the walk to stop at the timer, visited %d of %d", visited, components)
+	}
+
+	// Breaking out of a range loop must not panic
+	for range recipe.Steps() {
+		break
+	}
+
+	var nilRecipe *Recipe
+	for range nilRecipe.Steps() {
+		t.Error("nil recipe should have no steps")
+	}
+	var nilStep *Step
+	for range nilStep.Components() {
+		t.Error("nil step should have no components")
+	}
+}
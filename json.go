@@ -0,0 +1,270 @@
+package cooklang
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// JSONSchemaVersion is the version of the JSON schema written by Recipe.MarshalJSON.
+// It is increased when the schema changes in a way older readers cannot handle;
+// FromJSON rejects documents with a newer version. Version 2 writes ingredient
+// quantities as exact strings ("1/3", "1-2", "some"); version 1 documents, with
+// numeric quantities and a separate quantity_max, are still read.
+const JSONSchemaVersion = 2
+
+// ErrUnsupportedJSON is returned by FromJSON for documents it cannot read: a newer
+// schema version or an unknown component type.
+var ErrUnsupportedJSON = errors.New("unsupported recipe JSON")
+
+// Component type names used in the "type" field of JSON components.
+const (
+	jsonTypeIngredient  = "ingredient"
+	jsonTypeText        = "text"
+	jsonTypeTimer       = "timer"
+	jsonTypeCookware    = "cookware"
+	jsonTypeSection     = "section"
+	jsonTypeComment     = "comment"
+	jsonTypeNote        = "note"
+	jsonTypeTemperature = "temperature"
+	jsonTypeReference   = "recipe_reference"
+	jsonTypeExtension   = "extension"
+)
+
+// recipeFields has the fields of Recipe without its methods, so it can be encoded
+// with the struct tags instead of Recipe.MarshalJSON.
+type recipeFields Recipe
+
+// recipeJSON is the document written by Recipe.MarshalJSON.
+type recipeJSON struct {
+	SchemaVersion int `json:"schema_version"`
+	*recipeFields
+	Steps []stepJSON `json:"steps"`
+}
+
+// stepJSON is a step with its components as an array.
+type stepJSON struct {
+	Position   SourcePosition    `json:"position,omitzero"`
+	Images     []string          `json:"images,omitempty"`
+	Components []json.RawMessage `json:"components"`
+}
+
+// MarshalJSON encodes the recipe as a flat JSON document: the metadata fields, a
+// "schema_version", and "steps" as an array of steps whose "components" are arrays
+// of objects with a "type" field ("ingredient", "text", "timer", "cookware",
+// "section", "comment", "note", "temperature", "recipe_reference" or "extension").
+// The schema is described in docs/JSON.md; FromJSON reads it back.
+//
+// Returns:
+//   - []byte: The JSON document
+//   - error: An error if a component cannot be encoded
+//
+// Example:
+//
+//	data, err := json.MarshalIndent(recipe, "", "  ")
+func (r *Recipe) MarshalJSON() ([]byte, error) {
+	if r == nil {
+		return []byte("null"), nil
+	}
+	fields := recipeFields(*r)
+	fields.FirstStep = nil
+
+	doc := recipeJSON{SchemaVersion: JSONSchemaVersion, recipeFields: &fields, Steps: []stepJSON{}}
+	for step := range r.Steps() {
+		s := stepJSON{Position: step.Position, Images: step.Images, Components: []json.RawMessage{}}
+		for component := range step.Components() {
+			data, err := marshalComponent(component)
+			if err != nil {
+				return nil, err
+			}
+			s.Components = append(s.Components, data)
+		}
+		doc.Steps = append(doc.Steps, s)
+	}
+	return json.Marshal(doc)
+}
+
+// UnmarshalJSON decodes a document written by MarshalJSON into the recipe,
+// replacing its contents. See FromJSON.
+//
+// Parameters:
+//   - data: The JSON document
+//
+// Returns:
+//   - error: An error if the document is invalid or unsupported
+func (r *Recipe) UnmarshalJSON(data []byte) error {
+	fields := recipeFields{}
+	doc := recipeJSON{recipeFields: &fields}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	if doc.SchemaVersion > JSONSchemaVersion {
+		return fmt.Errorf("%w: schema version %d (supported up to %d)", ErrUnsupportedJSON, doc.SchemaVersion, JSONSchemaVersion)
+	}
+
+	var lastStep *Step
+	for i, s := range doc.Steps {
+		step := &Step{Position: s.Position, Images: s.Images}
+		var lastComponent StepComponent
+		for j, raw := range s.Components {
+			component, err := unmarshalComponent(raw)
+			if err != nil {
+				return fmt.Errorf("step %d, component %d: %w", i+1, j+1, err)
+			}
+			if lastComponent == nil {
+				step.FirstComponent = component
+			} else {
+				lastComponent.SetNext(component)
+			}
+			lastComponent = component
+		}
+		if lastStep == nil {
+			fields.FirstStep = step
+		} else {
+			lastStep.NextStep = step
+		}
+		lastStep = step
+	}
+
+	*r = Recipe(fields)
+	// Point the ingredients at the recipe's custom units
+	if len(r.CustomUnits) > 0 {
+		r.SetCustomUnits(nil)
+	}
+	return nil
+}
+
+// FromJSON reconstructs a recipe from a document written by Recipe.MarshalJSON,
+// e.g. one stored in a database or received from an API.
+//
+// Parameters:
+//   - data: The JSON document
+//
+// Returns:
+//   - *Recipe: The recipe
+//   - error: An error if the document is invalid, or wraps ErrUnsupportedJSON if it
+//     has a newer schema version or unknown component types
+//
+// Example:
+//
+//	data, _ := json.Marshal(recipe)
+//	restored, err := cooklang.FromJSON(data)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(restored.Equal(recipe)) // true
+func FromJSON(data []byte) (*Recipe, error) {
+	recipe := &Recipe{}
+	if err := recipe.UnmarshalJSON(data); err != nil {
+		return nil, err
+	}
+	return recipe, nil
+}
+
+// marshalComponent encodes an unlinked copy of a component with its type name.
+func marshalComponent(component StepComponent) ([]byte, error) {
+	switch c := copyComponent(component).(type) {
+	case *Ingredient:
+		c.TypedUnit = nil // Derived from Unit when decoding
+		return json.Marshal(struct {
+			Type string `json:"type"`
+			*Ingredient
+		}{jsonTypeIngredient, c})
+	case *Instruction:
+		return json.Marshal(struct {
+			Type string `json:"type"`
+			*Instruction
+		}{jsonTypeText, c})
+	case *Timer:
+		return json.Marshal(struct {
+			Type string `json:"type"`
+			*Timer
+		}{jsonTypeTimer, c})
+	case *Cookware:
+		return json.Marshal(struct {
+			Type string `json:"type"`
+			*Cookware
+		}{jsonTypeCookware, c})
+	case *Section:
+		return json.Marshal(struct {
+			Type string `json:"type"`
+			*Section
+		}{jsonTypeSection, c})
+	case *Comment:
+		return json.Marshal(struct {
+			Type string `json:"type"`
+			*Comment
+		}{jsonTypeComment, c})
+	case *Note:
+		return json.Marshal(struct {
+			Type string `json:"type"`
+			*Note
+		}{jsonTypeNote, c})
+	case *Temperature:
+		return json.Marshal(struct {
+			Type string `json:"type"`
+			*Temperature
+		}{jsonTypeTemperature, c})
+	case *RecipeReference:
+		return json.Marshal(struct {
+			Type string `json:"type"`
+			*RecipeReference
+		}{jsonTypeReference, c})
+	case *Extension:
+		return json.Marshal(struct {
+			Type string `json:"type"`
+			*Extension
+		}{jsonTypeExtension, c})
+	}
+	return nil, fmt.Errorf("cannot encode component of type %T", component)
+}
+
+// unmarshalComponent decodes a component by its type name.
+func unmarshalComponent(data []byte) (StepComponent, error) {
+	var header struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return nil, err
+	}
+
+	var component StepComponent
+	switch header.Type {
+	case jsonTypeIngredient:
+		component = &Ingredient{}
+	case jsonTypeText:
+		component = &Instruction{}
+	case jsonTypeTimer:
+		component = &Timer{}
+	case jsonTypeCookware:
+		component = &Cookware{}
+	case jsonTypeSection:
+		component = &Section{}
+	case jsonTypeComment:
+		component = &Comment{}
+	case jsonTypeNote:
+		component = &Note{}
+	case jsonTypeTemperature:
+		component = &Temperature{}
+	case jsonTypeReference:
+		component = &RecipeReference{}
+	case jsonTypeExtension:
+		component = &Extension{}
+	default:
+		return nil, fmt.Errorf("%w: component type %q", ErrUnsupportedJSON, header.Type)
+	}
+	if err := json.Unmarshal(data, component); err != nil {
+		return nil, err
+	}
+	if ingredient, ok := component.(*Ingredient); ok {
+		ingredient.TypedUnit = CreateTypedUnit(ingredient.Unit)
+		// Schema version 1 wrote a range's upper bound separately
+		var legacy struct {
+			QuantityMax float64 `json:"quantity_max"`
+		}
+		if err := json.Unmarshal(data, &legacy); err == nil && ingredient.Quantity.HasAmount() && legacy.QuantityMax > ingredient.Quantity.Max() {
+			ingredient.Quantity = NewRangeQuantity(ingredient.Quantity, QuantityFromFloat(legacy.QuantityMax))
+		}
+	}
+	return component, nil
+}
@@ -0,0 +1,143 @@
+package cooklang
+
+import (
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecipeJSONRoundTrip(t *testing.T) {
+	files, err := filepath.Glob("example_recipes/*.cook")
+	if err != nil || len(files) == 0 {
+		t.Fatalf("no example recipes found: %v", err)
+	}
+	files = append(files, "")
+
+	for _, file := range files {
+		var recipe *Recipe
+		if file == "" {
+			// Every component type in one recipe
+			recipe, err = ParseString("---\ntitle: Stew\ndate: 2024-03-01\nunits: {scoop: 30 g}\n---\n== Base ==\nBrown @beef{500%g}(diced) in a #pot{2}. -- keep stirring\n\n> Use a heavy pot.\n\nAdd @flour{1%scoop} and @./Stock{2%servings}, simmer for ~stew{2%hours} at 160°C.", WithExtendedMode())
+		} else {
+			recipe, err = ParseFile(file, WithExtendedMode())
+		}
+		if err != nil {
+			t.Fatalf("%s: %v", file, err)
+		}
+		recipe.FirstStep.Images = []string{"step.jpg"}
+
+		data, err := json.Marshal(recipe)
+		if err != nil {
+			t.Fatalf("%s: marshal failed: %v", file, err)
+		}
+		if strings.Contains(string(data), "next_component") || strings.Contains(string(data), "first_step") {
+			t.Errorf("%s: expected a flat document, got %s", file, data)
+		}
+
+		restored, err := FromJSON(data)
+		if err != nil {
+			t.Fatalf("%s: FromJSON failed: %v", file, err)
+		}
+		if !restored.Equal(recipe) {
+			t.Errorf("%s: round trip changed the recipe\n got: %s\nwant: %s", file, restored.Render(), recipe.Render())
+		}
+		if restored.FirstStep.Position != recipe.FirstStep.Position {
+			t.Errorf("%s: expected step positions to be kept", file)
+		}
+	}
+}
+
+func TestRecipeJSONSchema(t *testing.T) {
+	recipe, err := ParseString("---\nunits: {scoop: 30 g}\n---\nMix @flour{2%scoop} in a #bowl{}.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(recipe)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var doc struct {
+		SchemaVersion int `json:"schema_version"`
+		Steps         []struct {
+			Components []map[string]any `json:"components"`
+		} `json:"steps"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc.SchemaVersion != JSONSchemaVersion {
+		t.Errorf("expected schema version %d, got %d", JSONSchemaVersion, doc.SchemaVersion)
+	}
+	if len(doc.Steps) != 1 {
+		t.Fatalf("expected 1 step, got %d", len(doc.Steps))
+	}
+	var types []string
+	for _, component := range doc.Steps[0].Components {
+		types = append(types, component["type"].(string))
+		if _, ok := component["typed_unit"]; ok {
+			t.Error("typed_unit should not be written")
+		}
+	}
+	if got := strings.Join(types, ","); got != "text,ingredient,text,cookware,text" {
+		t.Errorf("unexpected component types: %s", got)
+	}
+
+	// Custom units still apply to the decoded ingredients
+	restored, err := FromJSON(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	flour := restored.GetIngredients().Ingredients[0]
+	if converted, err := flour.ConvertTo("g"); err != nil || converted.Quantity.Min() != 60 {
+		t.Errorf("expected 60 g, got %v, %v", converted, err)
+	}
+}
+
+func TestFromJSONErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		unsupported bool
+	}{
+		{"invalid JSON", `{"steps": [`, false},
+		{"newer schema", `{"schema_version": 99, "steps": []}`, true},
+		{"unknown component", `{"schema_version": 1, "steps": [{"components": [{"type": "video"}]}]}`, true},
+		{"legacy linked list", `{"title": "Old", "first_step": {"first_component": {"text": "Mix"}}}`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := FromJSON([]byte(tt.input))
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if errors.Is(err, ErrUnsupportedJSON) != tt.unsupported {
+				t.Errorf("errors.Is(err, ErrUnsupportedJSON) = %v, want %v (err: %v)", !tt.unsupported, tt.unsupported, err)
+			}
+		})
+	}
+
+	recipe, err := FromJSON([]byte(`{"title": "Empty"}`))
+	if err != nil || recipe.Title != "Empty" || recipe.FirstStep != nil {
+		t.Errorf("expected an empty recipe, got %+v, %v", recipe, err)
+	}
+}
+
+func TestFromJSONSchemaVersion1(t *testing.T) {
+	recipe, err := FromJSON([]byte(`{"schema_version": 1, "steps": [{"components": [
+		{"type": "ingredient", "name": "salt", "quantity": 1, "quantity_max": 2, "unit": "tsp"},
+		{"type": "ingredient", "name": "pepper", "quantity": -1},
+		{"type": "ingredient", "name": "sugar", "quantity": 0.3333333, "unit": "cup"}
+	]}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"salt": "1-2", "pepper": "some", "sugar": "1/3"}
+	for _, ingredient := range recipe.GetIngredients().Ingredients {
+		if got := ingredient.Quantity.String(); got != want[ingredient.Name] {
+			t.Errorf("%s quantity = %q, want %q", ingredient.Name, got, want[ingredient.Name])
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package lexer
+
+import (
+	"testing"
+
+	"github.com/hilli/cooklang/token"
+)
+
+func FuzzNextToken(f *testing.F) {
+	for _, seed := range []string{
+		"Toast @bread{2%slices} for ~{3%minutes}.",
+		"---\ntitle: X\n---\n== Section ==\n>> servings: 2\n> note\n-- comment\n[- block -]",
+		"@?salt{=1%pinch} #pot{2}(large) ~roast time{1-2%h} @@./Sauce{}",
+		"\r\n\t ½\xff",
+	} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, input string) {
+		l := New(input)
+		end := 0
+		// Every token but EOF consumes input, so there are at most as many tokens as bytes
+		for i := 0; i <= len(input)+1; i++ {
+			tok := l.NextToken()
+			if tok.Type == token.EOF {
+				return
+			}
+			if tok.Pos < end || tok.End <= tok.Pos || tok.End > len(input) {
+				t.Fatalf("token %q has range %d-%d after offset %d in %d bytes", tok.Literal, tok.Pos, tok.End, end, len(input))
+			}
+			end = tok.End
+		}
+		t.Fatalf("no EOF after %d tokens", len(input)+1)
+	})
+}
@@ -1,6 +1,8 @@
 package lexer
 
 import (
+	"slices"
+	"sort"
 	"strings"
 	"unicode"
 	"unicode/utf8"
@@ -15,14 +17,65 @@ type Lexer struct {
 	ch            rune          // Now supports Unicode
 	tokenBuffer   []token.Token // Buffer for putback tokens
 	documentStart bool          // True if we're still at the very beginning of the document
+	lineStarts    []int         // Byte offsets at which each line starts
+	lastEnd       int           // End offset of the last token returned and not put back
+	extensions    []rune        // Prefixes of custom components, see AddExtensionPrefix
 }
 
 func New(input string) *Lexer {
-	l := &Lexer{input: input, documentStart: true}
-	l.readChar()
+	l := &Lexer{}
+	l.Reset(input)
 	return l
 }
 
+// Reset prepares the lexer to read a new input, reusing its buffers. It lets a
+// Lexer be pooled instead of allocating one per input.
+func (l *Lexer) Reset(input string) {
+	lineStarts := l.lineStarts[:0]
+	if lineStarts == nil {
+		lineStarts = make([]int, 0, strings.Count(input, "\n")+1)
+	}
+	lineStarts = append(lineStarts, 0)
+	for i := 0; i < len(input); i++ {
+		if input[i] == '\n' || (input[i] == '\r' && (i+1 == len(input) || input[i+1] != '\n')) {
+			lineStarts = append(lineStarts, i+1)
+		}
+	}
+	*l = Lexer{input: input, documentStart: true, lineStarts: lineStarts, tokenBuffer: l.tokenBuffer[:0]}
+	l.readChar()
+}
+
+// AddExtensionPrefix makes the lexer read custom components that start with prefix,
+// such as "&wine pairing{Riesling}" for '&', as EXTENSION tokens: the prefix, a name
+// and a pair of braces on the same line. Reset forgets the prefixes.
+func (l *Lexer) AddExtensionPrefix(prefix rune) {
+	l.extensions = append(l.extensions, prefix)
+}
+
+// Offset returns the byte offset just past the last token returned by NextToken
+// (and not put back), so a parser can find where a multi-token construct ends.
+func (l *Lexer) Offset() int {
+	return l.lastEnd
+}
+
+// SourcePosition returns the position of the input between the byte offsets start
+// and end, with the line and column of start.
+//
+// Example:
+//
+//	l := lexer.New("Boil.\nAdd @salt{}.")
+//	pos := l.SourcePosition(10, 17)
+//	fmt.Println(pos) // 2:5
+func (l *Lexer) SourcePosition(start, end int) token.SourcePosition {
+	line := sort.Search(len(l.lineStarts), func(i int) bool { return l.lineStarts[i] > start })
+	return token.SourcePosition{
+		Line:      line,
+		Column:    start - l.lineStarts[line-1] + 1,
+		Offset:    start,
+		EndOffset: end,
+	}
+}
+
 func (l *Lexer) readChar() {
 	if l.readPosition >= len(l.input) {
 		l.ch = 0
@@ -39,14 +92,30 @@ func (l *Lexer) readChar() {
 	}
 }
 
+// NextToken returns the next token, with Pos and End set to the byte range of the
+// input it was read from.
 func (l *Lexer) NextToken() token.Token {
-	// Check buffer first
-	if len(l.tokenBuffer) > 0 {
-		tok := l.tokenBuffer[0]
-		l.tokenBuffer = l.tokenBuffer[1:]
+	// Check buffer first; the token put back last is on top
+	if n := len(l.tokenBuffer); n > 0 {
+		tok := l.tokenBuffer[n-1]
+		l.tokenBuffer = l.tokenBuffer[:n-1]
+		l.lastEnd = tok.End
 		return tok
 	}
 
+	start := l.position
+	tok := l.nextToken()
+	tok.Pos, tok.End = start, l.position
+	if tok.Type == token.NOTE || tok.Type == token.METADATA {
+		// Notes and metadata lines consume the line break that ends them; it is not part of the token
+		tok.End = start + len(strings.TrimRight(l.input[start:l.position], "\r\n"))
+	}
+	l.lastEnd = tok.End
+	return tok
+}
+
+// nextToken reads the next token from the input.
+func (l *Lexer) nextToken() token.Token {
 	var tok token.Token
 
 	// Handle whitespace as tokens instead of skipping
@@ -61,6 +130,12 @@ func (l *Lexer) NextToken() token.Token {
 		l.documentStart = false
 	}
 
+	if slices.Contains(l.extensions, l.ch) {
+		if tok, ok := l.readExtension(); ok {
+			return tok
+		}
+	}
+
 	switch l.ch {
 	case '[':
 		// Check for block comment opening [-
@@ -86,9 +161,12 @@ func (l *Lexer) NextToken() token.Token {
 			return l.readSectionHeader()
 		}
 		tok = newToken(token.SECTION, l.ch)
-	case '>': // Note block
+	case '>': // Note block or ">> key: value" metadata line
 		// Check if this is at the start of a line (note block)
 		if l.position == 0 || (l.position > 0 && (l.input[l.position-1] == '\n' || l.input[l.position-1] == '\r')) {
+			if l.isMetadataLine() {
+				return l.readMetadataLine()
+			}
 			return l.readNote()
 		}
 		// Otherwise treat as regular text
@@ -132,16 +210,20 @@ func (l *Lexer) NextToken() token.Token {
 		if l.peekChar() == '.' && (l.peekCharAt(1) == '/' || (l.peekCharAt(1) == '.' && l.peekCharAt(2) == '/')) {
 			return l.readRecipeReferencePath()
 		}
-		// Check for optional ingredient: @?
-		if l.peekChar() == '?' {
-			// Peek at the character after the '?' to see if it's an identifier
-			charAfterQuestion := l.peekCharAt(1)
-			if isIdentifierChar(charAfterQuestion) || charAfterQuestion == '_' {
+		// Check for optional ingredient (@?) or a reference to an earlier ingredient (@&)
+		if l.peekChar() == '?' || l.peekChar() == '&' {
+			// Peek at the character after the '?' or '&' to see if it's an identifier
+			charAfterMarker := l.peekCharAt(1)
+			if isIdentifierChar(charAfterMarker) || charAfterMarker == '_' {
 				ch := l.ch
-				l.readChar() // consume '?'
-				tok = token.Token{Type: token.OPTIONAL_INGREDIENT, Literal: string(ch) + string(l.ch)}
+				l.readChar() // consume '?' or '&'
+				var tokenType token.TokenType = token.OPTIONAL_INGREDIENT
+				if l.ch == '&' {
+					tokenType = token.REFERENCE_INGREDIENT
+				}
+				tok = token.Token{Type: tokenType, Literal: string(ch) + string(l.ch)}
 			} else {
-				// @? not followed by identifier - treat @ as text
+				// @? or @& not followed by identifier - treat @ as text
 				tok = newToken(token.ILLEGAL, l.ch)
 			}
 		} else if isIdentifierChar(l.peekChar()) || l.peekChar() == '_' {
@@ -220,7 +302,19 @@ func (l *Lexer) NextToken() token.Token {
 	return tok
 }
 
+// asciiLiterals holds the literal of every single-byte token, so reading one does
+// not allocate.
+var asciiLiterals = func() (literals [utf8.RuneSelf]string) {
+	for i := range literals {
+		literals[i] = string(rune(i))
+	}
+	return literals
+}()
+
 func newToken(tokenType token.TokenType, ch rune) token.Token {
+	if ch >= 0 && ch < utf8.RuneSelf {
+		return token.Token{Type: tokenType, Literal: asciiLiterals[ch]}
+	}
 	return token.Token{Type: tokenType, Literal: string(ch)}
 }
 
@@ -236,6 +330,30 @@ func (l *Lexer) peekChar() rune {
 	}
 }
 
+// readExtension reads a custom component: the prefix at the current character,
+// directly followed by a name and a pair of braces on the same line. It reads
+// nothing if there is no such component.
+func (l *Lexer) readExtension() (token.Token, bool) {
+	if next := l.peekChar(); !isIdentifierChar(next) && next != '_' {
+		return token.Token{}, false
+	}
+	rest := l.input[l.readPosition:]
+	open := strings.IndexAny(rest, "{}@#~\n\r")
+	if open < 0 || rest[open] != '{' {
+		return token.Token{}, false
+	}
+	closing := strings.IndexAny(rest[open+1:], "{}\n\r")
+	if closing < 0 || rest[open+1+closing] != '}' {
+		return token.Token{}, false
+	}
+
+	end := l.readPosition + open + 1 + closing + 1
+	tok := token.Token{Type: token.EXTENSION, Literal: l.input[l.position:end]}
+	l.readPosition = end
+	l.readChar()
+	return tok, true
+}
+
 func (l *Lexer) readWhitespace() token.Token {
 	position := l.position
 	for l.ch == ' ' || l.ch == '\t' {
@@ -403,10 +521,15 @@ func (l *Lexer) readComment() token.Token {
 
 // PeekToken returns the next token without advancing the lexer position
 func (l *Lexer) PeekToken() token.Token {
+	if n := len(l.tokenBuffer); n > 0 {
+		return l.tokenBuffer[n-1]
+	}
+
 	// Save current state
 	savedPosition := l.position
 	savedReadPosition := l.readPosition
 	savedCh := l.ch
+	savedLastEnd := l.lastEnd
 
 	// Get next token
 	tok := l.NextToken()
@@ -415,14 +538,19 @@ func (l *Lexer) PeekToken() token.Token {
 	l.position = savedPosition
 	l.readPosition = savedReadPosition
 	l.ch = savedCh
+	l.lastEnd = savedLastEnd
 
 	return tok
 }
 
 // PutBackToken puts a token back into the buffer to be returned by the next NextToken call
 func (l *Lexer) PutBackToken(tok token.Token) {
-	// Add to the beginning of the buffer
-	l.tokenBuffer = append([]token.Token{tok}, l.tokenBuffer...)
+	// The buffer is a stack: the token put back last is returned first
+	l.tokenBuffer = append(l.tokenBuffer, tok)
+	// Tokens cover the input without gaps, so the last token read ends where this one starts
+	if tok.Pos < l.lastEnd {
+		l.lastEnd = tok.Pos
+	}
 }
 
 // readBlockComment reads a block comment starting with [- and ending with -]
@@ -583,8 +711,8 @@ func (l *Lexer) readNote() token.Token {
 			break // Blank line ends the note
 		}
 
-		// If next line starts with >, continue reading the note
-		if l.ch == '>' {
+		// If next line starts with >, continue reading the note, unless it is a metadata line
+		if l.ch == '>' && !l.isMetadataLine() {
 			continue
 		}
 
@@ -600,3 +728,45 @@ func (l *Lexer) readNote() token.Token {
 		Literal: strings.TrimSpace(noteContent.String()),
 	}
 }
+
+// isMetadataLine reports whether the line at the current position is a classic
+// ">> key: value" metadata line.
+func (l *Lexer) isMetadataLine() bool {
+	rest := l.input[l.position:]
+	if !strings.HasPrefix(rest, ">>") {
+		return false
+	}
+	if end := strings.IndexAny(rest, "\r\n"); end >= 0 {
+		rest = rest[:end]
+	}
+	return strings.Contains(rest, ":")
+}
+
+// readMetadataLine reads a ">> key: value" metadata line, including the line break
+// that ends it. The literal is the line without the ">>" marker, e.g. "key: value".
+func (l *Lexer) readMetadataLine() token.Token {
+	// Skip the >> marker
+	l.readChar()
+	l.readChar()
+
+	lineStart := l.position
+	for l.ch != '\n' && l.ch != '\r' && l.ch != 0 {
+		l.readChar()
+	}
+	content := l.input[lineStart:l.position]
+
+	switch l.ch {
+	case '\r':
+		l.readChar() // consume \r
+		if l.ch == '\n' {
+			l.readChar() // consume \n in CRLF
+		}
+	case '\n':
+		l.readChar() // consume \n
+	}
+
+	return token.Token{
+		Type:    token.METADATA,
+		Literal: strings.TrimSpace(content),
+	}
+}
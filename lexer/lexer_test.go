@@ -633,6 +633,42 @@ func TestNote(t *testing.T) {
 				{token.EOF, ""},
 			},
 		},
+		{
+			name:  "metadata lines",
+			input: ">> servings: 4\r\n>>source:Grandma\nMix",
+			expectedTokens: []struct {
+				tokenType token.TokenType
+				literal   string
+			}{
+				{token.METADATA, "servings: 4"},
+				{token.METADATA, "source:Grandma"},
+				{token.IDENT, "Mix"},
+				{token.EOF, ""},
+			},
+		},
+		{
+			name:  "metadata line ends a note",
+			input: "> A tip\n>> author: Sam",
+			expectedTokens: []struct {
+				tokenType token.TokenType
+				literal   string
+			}{
+				{token.NOTE, "A tip"},
+				{token.METADATA, "author: Sam"},
+				{token.EOF, ""},
+			},
+		},
+		{
+			name:  ">> without a colon is a note",
+			input: ">> Quoted tip",
+			expectedTokens: []struct {
+				tokenType token.TokenType
+				literal   string
+			}{
+				{token.NOTE, "> Quoted tip"},
+				{token.EOF, ""},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -781,3 +817,203 @@ func TestOptionalIngredient(t *testing.T) {
 		})
 	}
 }
+
+// TestReferenceIngredient tests tokenization of references to earlier ingredients @&
+func TestReferenceIngredient(t *testing.T) {
+	type expectedToken struct {
+		tokenType token.TokenType
+		literal   string
+	}
+	tests := []struct {
+		name           string
+		input          string
+		expectedTokens []expectedToken
+	}{
+		{
+			name:  "reference with braces",
+			input: "the @&flour{}",
+			expectedTokens: []expectedToken{
+				{token.IDENT, "the"},
+				{token.WHITESPACE, " "},
+				{token.REFERENCE_INGREDIENT, "@&"},
+				{token.IDENT, "flour"},
+				{token.LBRACE, "{"},
+				{token.RBRACE, "}"},
+				{token.EOF, ""},
+			},
+		},
+		{
+			name:  "@& not followed by identifier - treated as text",
+			input: "salt @& pepper",
+			expectedTokens: []expectedToken{
+				{token.IDENT, "salt"},
+				{token.WHITESPACE, " "},
+				{token.ILLEGAL, "@"},
+				{token.ILLEGAL, "&"},
+				{token.WHITESPACE, " "},
+				{token.IDENT, "pepper"},
+				{token.EOF, ""},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := New(tt.input)
+			for i, expected := range tt.expectedTokens {
+				tok := l.NextToken()
+				if tok.Type != expected.tokenType {
+					t.Errorf("token[%d]: expected type %s, got %s", i, expected.tokenType, tok.Type)
+				}
+				if tok.Literal != expected.literal {
+					t.Errorf("token[%d]: expected literal %q, got %q", i, expected.literal, tok.Literal)
+				}
+			}
+		})
+	}
+}
+
+func TestExtensionPrefix(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []token.Token
+	}{
+		{
+			name:  "custom component",
+			input: "Serve &wine pairing{Riesling}.",
+			expected: []token.Token{
+				{Type: token.IDENT, Literal: "Serve", Pos: 0, End: 5},
+				{Type: token.WHITESPACE, Literal: " ", Pos: 5, End: 6},
+				{Type: token.EXTENSION, Literal: "&wine pairing{Riesling}", Pos: 6, End: 29},
+				{Type: token.PERIOD, Literal: ".", Pos: 29, End: 30},
+			},
+		},
+		{
+			name:  "no braces - treated as text",
+			input: "salt &pepper",
+			expected: []token.Token{
+				{Type: token.IDENT, Literal: "salt", Pos: 0, End: 4},
+				{Type: token.WHITESPACE, Literal: " ", Pos: 4, End: 5},
+				{Type: token.ILLEGAL, Literal: "&", Pos: 5, End: 6},
+				{Type: token.IDENT, Literal: "pepper", Pos: 6, End: 12},
+			},
+		},
+		{
+			name:  "braces on the next line - treated as text",
+			input: "&wine\n{x}",
+			expected: []token.Token{
+				{Type: token.ILLEGAL, Literal: "&", Pos: 0, End: 1},
+				{Type: token.IDENT, Literal: "wine", Pos: 1, End: 5},
+				{Type: token.NEWLINE, Literal: "\n", Pos: 5, End: 6},
+			},
+		},
+		{
+			name:  "ingredient before the braces - treated as text",
+			input: "& @salt{}",
+			expected: []token.Token{
+				{Type: token.ILLEGAL, Literal: "&", Pos: 0, End: 1},
+				{Type: token.WHITESPACE, Literal: " ", Pos: 1, End: 2},
+				{Type: token.INGREDIENT, Literal: "@", Pos: 2, End: 3},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := New(tt.input)
+			l.AddExtensionPrefix('&')
+			for i, expected := range tt.expected {
+				if tok := l.NextToken(); tok != expected {
+					t.Errorf("token[%d]: expected %+v, got %+v", i, expected, tok)
+				}
+			}
+		})
+	}
+
+	// Reset forgets the prefixes
+	l := New("")
+	l.AddExtensionPrefix('&')
+	l.Reset("&wine{x}")
+	if tok := l.NextToken(); tok.Type != token.ILLEGAL {
+		t.Errorf("expected the prefix to be text after Reset, got %+v", tok)
+	}
+}
+
+func TestTokenPositions(t *testing.T) {
+	input := "Add @salt{}\r\n== Dough ==\nüber -- note"
+	l := New(input)
+
+	expected := []struct {
+		literal string
+		text    string
+	}{
+		{"Add", "Add"},
+		{" ", " "},
+		{"@", "@"},
+		{"salt", "salt"},
+		{"{", "{"},
+		{"}", "}"},
+		{"\n", "\r\n"},
+		{"Dough", "== Dough =="},
+		{"\n", "\n"},
+		{"über", "über"},
+		{" ", " "},
+		{"note", "-- note"},
+	}
+	for _, want := range expected {
+		tok := l.NextToken()
+		if tok.Literal != want.literal || input[tok.Pos:tok.End] != want.text {
+			t.Fatalf("expected %q from %q, got %q from %q", want.literal, want.text, tok.Literal, input[tok.Pos:tok.End])
+		}
+		if l.Offset() != tok.End {
+			t.Errorf("expected offset %d after %q, got %d", tok.End, tok.Literal, l.Offset())
+		}
+	}
+
+	// Putting a token back moves the offset back to where it starts
+	tok := l.NextToken()
+	l.PutBackToken(tok)
+	if tok.Type != token.EOF || l.Offset() != tok.Pos {
+		t.Errorf("unexpected offset %d after putting back %+v", l.Offset(), tok)
+	}
+
+	for _, tt := range []struct {
+		offset    int
+		line, col int
+	}{
+		{0, 1, 1}, {4, 1, 5}, {13, 2, 1}, {25, 3, 1}, {31, 3, 7}, // Columns count bytes: "ü" is two
+	} {
+		pos := l.SourcePosition(tt.offset, tt.offset+1)
+		if pos.Line != tt.line || pos.Column != tt.col || pos.Offset != tt.offset || pos.EndOffset != tt.offset+1 {
+			t.Errorf("offset %d: expected %d:%d, got %+v", tt.offset, tt.line, tt.col, pos)
+		}
+	}
+}
+
+func TestPutBackAndReset(t *testing.T) {
+	l := New("@salt{}")
+	first := l.NextToken()
+	second := l.NextToken()
+	l.PutBackToken(second)
+	l.PutBackToken(first)
+
+	if peeked := l.PeekToken(); peeked != first {
+		t.Errorf("PeekToken = %+v, want %+v", peeked, first)
+	}
+	if tok := l.NextToken(); tok != first {
+		t.Errorf("first NextToken = %+v, want %+v", tok, first)
+	}
+	if tok := l.NextToken(); tok != second {
+		t.Errorf("second NextToken = %+v, want %+v", tok, second)
+	}
+
+	l.Reset("Boil.\nAdd @water{}.")
+	tok := l.NextToken()
+	if tok.Type != token.IDENT || tok.Literal != "Boil" {
+		t.Errorf("after Reset: got %+v", tok)
+	}
+	if pos := l.SourcePosition(10, 16); pos.Line != 2 || pos.Column != 5 {
+		t.Errorf("after Reset: position %v, want 2:5", pos)
+	}
+}
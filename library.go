@@ -0,0 +1,435 @@
+package cooklang
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// LibraryEntry is a recipe loaded into a Library together with where it came from.
+type LibraryEntry struct {
+	Path   string  `json:"path"` // Path relative to the library root, using forward slashes (e.g., "sauces/Hollandaise.cook")
+	Recipe *Recipe `json:"recipe"`
+}
+
+// Name returns the recipe title, which is inferred from the file name if the recipe has no title metadata.
+func (e *LibraryEntry) Name() string {
+	if e.Recipe != nil && e.Recipe.Title != "" {
+		return e.Recipe.Title
+	}
+	return TitleFromFilename(e.Path)
+}
+
+// Library is an in-memory collection of recipes, indexed by title, tag, cuisine and
+// ingredient. It saves consumers from reimplementing directory walking and indexing.
+//
+// All index lookups are case-insensitive. A Library also implements RecipeResolver,
+// so recipe references can be resolved against it without touching the filesystem.
+//
+// Example:
+//
+//	library, err := cooklang.LoadLibrary("recipes")
+//	if err != nil {
+//	    log.Printf("some recipes could not be loaded: %v", err)
+//	}
+//	for _, entry := range library.Filter(cooklang.LibraryFilter{
+//	    Tags:        []string{"italian"},
+//	    Ingredients: []string{"chicken"},
+//	}) {
+//	    fmt.Println(entry.Name(), entry.Path)
+//	}
+type Library struct {
+	Root    string          `json:"root,omitempty"`
+	Entries []*LibraryEntry `json:"entries"`
+
+	byPath       map[string]*LibraryEntry
+	byTitle      map[string][]*LibraryEntry
+	byTag        map[string][]*LibraryEntry
+	byCuisine    map[string][]*LibraryEntry
+	byIngredient map[string][]*LibraryEntry
+	byDifficulty map[string][]*LibraryEntry
+}
+
+// LibraryFilter selects library entries. Every non-empty field must match; within
+// Tags and Ingredients, all listed values must be present. Comparisons are case-insensitive.
+type LibraryFilter struct {
+	Title        string       // Substring of the recipe title
+	Tags         []string     // Tags the recipe must have
+	Cuisine      string       // Exact cuisine
+	Ingredients  []string     // Ingredient names the recipe must use
+	Difficulties []Difficulty // Difficulty levels the recipe may have, any of them
+}
+
+// NewLibrary creates an empty library. Use Load to populate it from a directory,
+// or Add to register recipes that were parsed elsewhere.
+func NewLibrary() *Library {
+	return &Library{
+		byPath:       make(map[string]*LibraryEntry),
+		byTitle:      make(map[string][]*LibraryEntry),
+		byTag:        make(map[string][]*LibraryEntry),
+		byCuisine:    make(map[string][]*LibraryEntry),
+		byIngredient: make(map[string][]*LibraryEntry),
+		byDifficulty: make(map[string][]*LibraryEntry),
+	}
+}
+
+// LoadLibrary creates a library from all .cook files found recursively under dir.
+// This is a convenience wrapper around NewLibrary and Load.
+//
+// Parameters:
+//   - dir: The directory to scan
+//
+// Returns:
+//   - *Library: The library, containing every recipe that parsed successfully
+//   - error: An error joining all walk and parse failures, or nil
+//
+// Example:
+//
+//	library, err := cooklang.LoadLibrary("recipes")
+//	fmt.Printf("%d recipes\n", library.Len())
+func LoadLibrary(dir string) (*Library, error) {
+	library := NewLibrary()
+	err := library.Load(dir)
+	return library, err
+}
+
+// Load recursively parses all .cook files under dir and adds them to the library.
+// Hidden directories (e.g., ".git") are skipped. The metadata of a directory's
+// DefaultsFile (_defaults.yml) is added to the recipes in it, unless a recipe sets
+// the key itself. A file that fails to parse does not stop the load; all failures
+// are returned together once the walk is complete.
+//
+// Parameters:
+//   - dir: The directory to scan; it becomes the library Root
+//
+// Returns:
+//   - error: An error joining all walk and parse failures, or nil
+func (l *Library) Load(dir string) error {
+	l.Root = dir
+
+	// Each directory's defaults are read once; recipes in a directory with an
+	// unreadable defaults file are loaded without defaults
+	defaults := make(map[string]Metadata)
+	var defaultsErrs []error
+	errs := walkCookFiles(dir, func(path, rel string) error {
+		recipeDir := filepath.Dir(path)
+		dirDefaults, ok := defaults[recipeDir]
+		if !ok {
+			var err error
+			if dirDefaults, err = LoadDefaults(recipeDir); err != nil {
+				defaultsErrs = append(defaultsErrs, err)
+			}
+			defaults[recipeDir] = dirDefaults
+		}
+		recipe, err := ParseFile(path, WithDefaults(dirDefaults))
+		if err != nil {
+			return err
+		}
+		l.Add(rel, recipe)
+		return nil
+	})
+	return errors.Join(append(defaultsErrs, errs...)...)
+}
+
+// walkCookFiles calls fn for every .cook file under dir, with its path and its
+// slash-separated path relative to dir. Hidden directories are skipped. Errors from
+// the walk and from fn are collected rather than stopping the walk.
+func walkCookFiles(dir string, fn func(path, rel string) error) []error {
+	var errs []error
+	walkErr := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			errs = append(errs, err)
+			if d != nil && d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			if path != dir && strings.HasPrefix(d.Name(), ".") {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if filepath.Ext(path) != ".cook" {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			rel = path
+		}
+		if err := fn(path, filepath.ToSlash(rel)); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", path, err))
+		}
+		return nil
+	})
+	if walkErr != nil {
+		errs = append(errs, walkErr)
+	}
+	return errs
+}
+
+// Add registers a recipe under the given path and indexes it. Adding a recipe
+// with a path that is already present replaces the earlier entry.
+//
+// Parameters:
+//   - path: The recipe path, relative to the library root
+//   - recipe: The parsed recipe
+//
+// Returns:
+//   - *LibraryEntry: The new entry
+func (l *Library) Add(path string, recipe *Recipe) *LibraryEntry {
+	if l.byPath == nil {
+		*l = *NewLibrary()
+	}
+	if _, exists := l.byPath[path]; exists {
+		l.remove(path)
+	}
+
+	entry := &LibraryEntry{Path: path, Recipe: recipe}
+	l.Entries = append(l.Entries, entry)
+	sort.Slice(l.Entries, func(i, j int) bool {
+		return l.Entries[i].Path < l.Entries[j].Path
+	})
+	l.index(entry)
+	return entry
+}
+
+// remove drops the entry at path and rebuilds the indexes.
+func (l *Library) remove(path string) {
+	entries := l.Entries[:0]
+	for _, entry := range l.Entries {
+		if entry.Path != path {
+			entries = append(entries, entry)
+		}
+	}
+
+	root := l.Root
+	*l = *NewLibrary()
+	l.Root = root
+	l.Entries = entries
+	for _, entry := range entries {
+		l.index(entry)
+	}
+}
+
+// index adds an entry to all lookup maps.
+func (l *Library) index(entry *LibraryEntry) {
+	l.byPath[entry.Path] = entry
+	appendUnique(l.byTitle, libraryKey(entry.Name()), entry)
+
+	recipe := entry.Recipe
+	if recipe == nil {
+		return
+	}
+	for _, tag := range recipe.Tags {
+		appendUnique(l.byTag, libraryKey(tag), entry)
+	}
+	if recipe.Cuisine != "" {
+		appendUnique(l.byCuisine, libraryKey(recipe.Cuisine), entry)
+	}
+	for _, ingredient := range recipe.GetIngredients().Ingredients {
+		appendUnique(l.byIngredient, libraryKey(ingredient.Name), entry)
+	}
+	appendUnique(l.byDifficulty, string(recipe.DifficultyLevel()), entry)
+}
+
+// appendUnique adds an entry to an index bucket unless it is already present.
+func appendUnique(index map[string][]*LibraryEntry, key string, entry *LibraryEntry) {
+	if key == "" {
+		return
+	}
+	for _, existing := range index[key] {
+		if existing == entry {
+			return
+		}
+	}
+	index[key] = append(index[key], entry)
+}
+
+// libraryKey normalizes an index key for case-insensitive lookups.
+func libraryKey(s string) string {
+	return strings.ToLower(strings.TrimSpace(s))
+}
+
+// Len returns the number of recipes in the library.
+func (l *Library) Len() int {
+	return len(l.Entries)
+}
+
+// Get returns the entry with the given path, relative to the library root.
+// The ".cook" extension is optional.
+func (l *Library) Get(path string) (*LibraryEntry, bool) {
+	path = filepath.ToSlash(filepath.Clean(path))
+	if !strings.HasSuffix(path, ".cook") {
+		path += ".cook"
+	}
+	entry, ok := l.byPath[path]
+	return entry, ok
+}
+
+// Resolve implements RecipeResolver, looking up a recipe reference path such as
+// "./sauces/Hollandaise" in the library.
+func (l *Library) Resolve(path string) (*Recipe, error) {
+	entry, ok := l.Get(path)
+	if !ok {
+		return nil, fmt.Errorf("recipe %q not found in library", path)
+	}
+	return entry.Recipe, nil
+}
+
+// ByTitle returns the entries whose title (or file name, if untitled) matches exactly.
+func (l *Library) ByTitle(title string) []*LibraryEntry {
+	return l.byTitle[libraryKey(title)]
+}
+
+// ByTag returns the entries tagged with the given tag.
+func (l *Library) ByTag(tag string) []*LibraryEntry {
+	return l.byTag[libraryKey(tag)]
+}
+
+// ByCuisine returns the entries of the given cuisine.
+func (l *Library) ByCuisine(cuisine string) []*LibraryEntry {
+	return l.byCuisine[libraryKey(cuisine)]
+}
+
+// ByIngredient returns the entries that use the given ingredient.
+func (l *Library) ByIngredient(name string) []*LibraryEntry {
+	return l.byIngredient[libraryKey(name)]
+}
+
+// ByDifficulty returns the entries whose difficulty metadata is the given level
+// (see ParseDifficulty).
+func (l *Library) ByDifficulty(level Difficulty) []*LibraryEntry {
+	return l.byDifficulty[string(level)]
+}
+
+// Tags returns all tags used in the library, sorted and lowercased.
+func (l *Library) Tags() []string {
+	return sortedKeys(l.byTag)
+}
+
+// Cuisines returns all cuisines used in the library, sorted and lowercased.
+func (l *Library) Cuisines() []string {
+	return sortedKeys(l.byCuisine)
+}
+
+// Ingredients returns all ingredient names used in the library, sorted and lowercased.
+func (l *Library) Ingredients() []string {
+	return sortedKeys(l.byIngredient)
+}
+
+// sortedKeys returns the keys of an index in sorted order.
+func sortedKeys(index map[string][]*LibraryEntry) []string {
+	keys := make([]string, 0, len(index))
+	for key := range index {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Filter returns the entries matching every criterion of the filter, in path order.
+// An empty filter returns all entries.
+//
+// Parameters:
+//   - filter: The criteria to match
+//
+// Returns:
+//   - []*LibraryEntry: The matching entries
+//
+// Example:
+//
+//	cocktails := library.Filter(cooklang.LibraryFilter{Tags: []string{"gin"}, Cuisine: "italian"})
+func (l *Library) Filter(filter LibraryFilter) []*LibraryEntry {
+	var result []*LibraryEntry
+	title := libraryKey(filter.Title)
+	for _, entry := range l.Entries {
+		if title != "" && !strings.Contains(libraryKey(entry.Name()), title) {
+			continue
+		}
+		if filter.Cuisine != "" && !containsEntry(l.ByCuisine(filter.Cuisine), entry) {
+			continue
+		}
+		if !matchesAll(filter.Tags, l.ByTag, entry) || !matchesAll(filter.Ingredients, l.ByIngredient, entry) {
+			continue
+		}
+		if len(filter.Difficulties) > 0 && (entry.Recipe == nil || !slices.Contains(filter.Difficulties, entry.Recipe.DifficultyLevel())) {
+			continue
+		}
+		result = append(result, entry)
+	}
+	return result
+}
+
+// matchesAll reports whether the entry is found under every key using lookup.
+func matchesAll(keys []string, lookup func(string) []*LibraryEntry, entry *LibraryEntry) bool {
+	for _, key := range keys {
+		if !containsEntry(lookup(key), entry) {
+			return false
+		}
+	}
+	return true
+}
+
+// containsEntry reports whether entries contains entry.
+func containsEntry(entries []*LibraryEntry, entry *LibraryEntry) bool {
+	for _, e := range entries {
+		if e == entry {
+			return true
+		}
+	}
+	return false
+}
+
+// Search returns the entries whose title, tags, cuisine, ingredient names or
+// description contain the query, in path order. Matching is case-insensitive;
+// an empty query returns all entries.
+//
+// Parameters:
+//   - query: The text to look for
+//
+// Returns:
+//   - []*LibraryEntry: The matching entries
+//
+// Example:
+//
+//	for _, entry := range library.Search("lime") {
+//	    fmt.Println(entry.Name())
+//	}
+func (l *Library) Search(query string) []*LibraryEntry {
+	query = libraryKey(query)
+	var result []*LibraryEntry
+	for _, entry := range l.Entries {
+		if query == "" || entryMatches(entry, query) {
+			result = append(result, entry)
+		}
+	}
+	return result
+}
+
+// entryMatches reports whether any searchable field of the entry contains query.
+func entryMatches(entry *LibraryEntry, query string) bool {
+	if strings.Contains(libraryKey(entry.Name()), query) {
+		return true
+	}
+	recipe := entry.Recipe
+	if recipe == nil {
+		return false
+	}
+	fields := []string{recipe.Cuisine, recipe.Description}
+	fields = append(fields, recipe.Tags...)
+	for _, ingredient := range recipe.GetIngredients().Ingredients {
+		fields = append(fields, ingredient.Name)
+	}
+	for _, field := range fields {
+		if strings.Contains(libraryKey(field), query) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,148 @@
+package cooklang
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newTestLibrary writes a small recipe collection to a temporary directory and loads it.
+func newTestLibrary(t *testing.T) (*Library, error) {
+	t.Helper()
+	dir := t.TempDir()
+	files := map[string]string{
+		"chicken_cacciatore.cook": "---\ntitle: Chicken Cacciatore\ncuisine: Italian\ntags: italian, dinner\n---\nBrown @chicken thighs{6} with @tomatoes{400%g}.",
+		"mains/risotto.cook":      "---\ntitle: Risotto\ncuisine: Italian\ntags: italian, vegetarian\n---\nStir @arborio rice{300%g} into @stock{1%l}.",
+		"mains/tacos.cook":        "---\ntitle: Chicken Tacos\ncuisine: Mexican\ntags: dinner\ndescription: Weeknight tacos with lime\n---\nFill @tortillas{8} with @chicken{500%g}.",
+		"sauces/salsa.cook":       "Chop @tomatoes{3} and @lime{1}.",
+		".git/ignored.cook":       "Ignore @me{}.",
+		"notes.txt":               "not a recipe",
+	}
+	for name, content := range files {
+		if err := writeFile(filepath.Join(dir, name), content); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return LoadLibrary(dir)
+}
+
+func TestLoadLibrary(t *testing.T) {
+	library, err := newTestLibrary(t)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if library.Len() != 4 {
+		t.Fatalf("expected 4 recipes, got %d", library.Len())
+	}
+	paths := make([]string, 0, library.Len())
+	for _, entry := range library.Entries {
+		paths = append(paths, entry.Path)
+	}
+	want := "chicken_cacciatore.cook,mains/risotto.cook,mains/tacos.cook,sauces/salsa.cook"
+	if got := strings.Join(paths, ","); got != want {
+		t.Errorf("paths = %s, want %s", got, want)
+	}
+
+	if got := library.Tags(); strings.Join(got, ",") != "dinner,italian,vegetarian" {
+		t.Errorf("Tags() = %v", got)
+	}
+	if got := library.Cuisines(); strings.Join(got, ",") != "italian,mexican" {
+		t.Errorf("Cuisines() = %v", got)
+	}
+
+	salsa, ok := library.Get("sauces/salsa")
+	if !ok || salsa.Name() != "salsa" {
+		t.Errorf("Get(sauces/salsa) = %v, %v", salsa, ok)
+	}
+	if _, err := library.Resolve("./mains/risotto"); err != nil {
+		t.Errorf("Resolve(./mains/risotto) failed: %v", err)
+	}
+	if _, err := library.Resolve("missing"); err == nil {
+		t.Error("expected error resolving a missing recipe")
+	}
+}
+
+func TestLibraryIndexes(t *testing.T) {
+	library, err := newTestLibrary(t)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		result []*LibraryEntry
+		want   int
+	}{
+		{"ByTitle", library.ByTitle("risotto"), 1},
+		{"ByTitle untitled", library.ByTitle("Salsa"), 1},
+		{"ByTag", library.ByTag("Italian"), 2},
+		{"ByCuisine", library.ByCuisine("mexican"), 1},
+		{"ByIngredient", library.ByIngredient("tomatoes"), 2},
+		{"ByIngredient missing", library.ByIngredient("saffron"), 0},
+	}
+	for _, tt := range tests {
+		if len(tt.result) != tt.want {
+			t.Errorf("%s: got %d entries, want %d", tt.name, len(tt.result), tt.want)
+		}
+	}
+}
+
+func TestLibraryFilterAndSearch(t *testing.T) {
+	library, err := newTestLibrary(t)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	names := func(entries []*LibraryEntry) string {
+		result := make([]string, 0, len(entries))
+		for _, entry := range entries {
+			result = append(result, entry.Name())
+		}
+		return strings.Join(result, ",")
+	}
+
+	tests := []struct {
+		name   string
+		filter LibraryFilter
+		want   string
+	}{
+		{"empty", LibraryFilter{}, "Chicken Cacciatore,Risotto,Chicken Tacos,salsa"},
+		{"tag", LibraryFilter{Tags: []string{"italian"}}, "Chicken Cacciatore,Risotto"},
+		{"tag and ingredient", LibraryFilter{Tags: []string{"italian"}, Ingredients: []string{"chicken thighs"}}, "Chicken Cacciatore"},
+		{"all tags", LibraryFilter{Tags: []string{"italian", "dinner"}}, "Chicken Cacciatore"},
+		{"cuisine", LibraryFilter{Cuisine: "Mexican"}, "Chicken Tacos"},
+		{"title", LibraryFilter{Title: "chicken"}, "Chicken Cacciatore,Chicken Tacos"},
+		{"no match", LibraryFilter{Tags: []string{"dessert"}}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := names(library.Filter(tt.filter)); got != tt.want {
+				t.Errorf("Filter() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if got := names(library.Search("LIME")); got != "Chicken Tacos,salsa" {
+		t.Errorf("Search(LIME) = %q", got)
+	}
+}
+
+func TestLibraryAddReplaces(t *testing.T) {
+	library := NewLibrary()
+	first, _ := ParseString("---\ntags: old\n---\nMix @flour{}.")
+	second, _ := ParseString("---\ntags: new\n---\nMix @sugar{}.")
+
+	library.Add("cake.cook", first)
+	library.Add("cake.cook", second)
+
+	if library.Len() != 1 {
+		t.Fatalf("expected 1 recipe, got %d", library.Len())
+	}
+	if len(library.ByTag("old")) != 0 || len(library.ByIngredient("flour")) != 0 {
+		t.Error("expected replaced recipe to be removed from indexes")
+	}
+	if len(library.ByTag("new")) != 1 {
+		t.Error("expected new recipe to be indexed")
+	}
+}
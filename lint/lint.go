@@ -0,0 +1,594 @@
+// Package lint checks Cooklang recipes for mistakes that parse fine but make a
+// recipe less useful: ingredients mentioned in the text without being marked with @,
+// timers without a unit, missing servings, quantities that cannot be read, duplicate
+// metadata keys and units that cannot be converted.
+//
+// Lint checks a parsed recipe; LintSource and LintFile also check the recipe text,
+// which finds problems the parsed recipe no longer shows (duplicate metadata keys and
+// unreadable quantities). Rules can be disabled or given another severity with a
+// Linter.
+//
+// Example:
+//
+//	issues, err := lint.LintFile("Pancakes.cook")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	for _, issue := range issues {
+//	    fmt.Println(issue) // warning: step 2: timer "10" has no unit [timer-unit]
+//	}
+package lint
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hilli/cooklang"
+	"github.com/hilli/cooklang/parser"
+)
+
+// Severity is how serious an issue is.
+type Severity int
+
+const (
+	// Info issues are suggestions.
+	Info Severity = iota
+	// Warning issues make a recipe less useful, e.g. a timer that cannot be started.
+	Warning
+	// Error issues lose information, e.g. a quantity that is read as "some".
+	Error
+)
+
+// String returns "info", "warning" or "error".
+func (s Severity) String() string {
+	switch s {
+	case Warning:
+		return "warning"
+	case Error:
+		return "error"
+	default:
+		return "info"
+	}
+}
+
+// MarshalText encodes the severity as its name, e.g. in JSON.
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText decodes a severity name.
+func (s *Severity) UnmarshalText(text []byte) error {
+	severity, err := ParseSeverity(string(text))
+	if err != nil {
+		return err
+	}
+	*s = severity
+	return nil
+}
+
+// ParseSeverity parses "info", "warning" or "error" (case-insensitive).
+func ParseSeverity(name string) (Severity, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "info":
+		return Info, nil
+	case "warning", "warn":
+		return Warning, nil
+	case "error":
+		return Error, nil
+	default:
+		return Info, fmt.Errorf("unknown severity %q (use info, warning, or error)", name)
+	}
+}
+
+// Issue is a problem found in a recipe.
+type Issue struct {
+	Rule     string   `json:"rule"`           // Name of the rule that found the issue, e.g. "timer-unit"
+	Severity Severity `json:"severity"`       // How serious the issue is
+	Message  string   `json:"message"`        // Description of the issue
+	Step     int      `json:"step,omitempty"` // Step number, or 0 if the issue is not in a step
+	Line     int      `json:"line,omitempty"` // Line in the recipe text, or 0 if unknown
+}
+
+// String returns the issue as "severity: [line N: | step N: ]message [rule]".
+func (i Issue) String() string {
+	location := ""
+	switch {
+	case i.Line > 0:
+		location = fmt.Sprintf("line %d: ", i.Line)
+	case i.Step > 0:
+		location = fmt.Sprintf("step %d: ", i.Step)
+	}
+	return fmt.Sprintf("%s: %s%s [%s]", i.Severity, location, i.Message, i.Rule)
+}
+
+// Input is what a rule checks: the parsed recipe and, when available, its text.
+type Input struct {
+	Recipe *cooklang.Recipe
+	Source []byte         // Recipe text; nil when linting a parsed recipe only
+	Parsed *parser.Recipe // Parser output for Source; nil when Source is nil
+
+	// KnownIngredients are extra ingredient names the unmarked-ingredient rule looks for
+	KnownIngredients []string
+}
+
+// Rule is a named check. Check returns the issues it finds; the linter fills in
+// their Rule and Severity.
+type Rule struct {
+	Name        string
+	Description string
+	Severity    Severity // Default severity of the rule's issues
+	Check       func(in *Input) []Issue
+}
+
+// DefaultRules are the rules a zero Linter runs.
+var DefaultRules = []Rule{
+	{
+		Name:        "unmarked-ingredient",
+		Description: "An ingredient is mentioned in a step before it is marked with @",
+		Severity:    Warning,
+		Check:       checkUnmarkedIngredients,
+	},
+	{
+		Name:        "timer-unit",
+		Description: "A timer has no unit, so it is read as minutes",
+		Severity:    Warning,
+		Check:       checkTimerUnits,
+	},
+	{
+		Name:        "missing-servings",
+		Description: "The recipe has no servings metadata, so it cannot be scaled to a number of servings",
+		Severity:    Warning,
+		Check:       checkServings,
+	},
+	{
+		Name:        "unparseable-quantity",
+		Description: "A quantity cannot be read as a number, fraction or range (needs the recipe text)",
+		Severity:    Error,
+		Check:       checkQuantities,
+	},
+	{
+		Name:        "duplicate-metadata",
+		Description: "A metadata key is set more than once; only the last value is kept (needs the recipe text)",
+		Severity:    Error,
+		Check:       checkDuplicateMetadata,
+	},
+	{
+		Name:        "unknown-unit",
+		Description: "A unit cannot be converted; declare it in the units metadata if it should be",
+		Severity:    Info,
+		Check:       checkUnits,
+	},
+	{
+		Name:        "suspicious-temperature",
+		Description: "A temperature is implausible for cooking, e.g. 350°C where 350°F is meant, or oven_temp has no scale",
+		Severity:    Warning,
+		Check:       checkTemperatures,
+	},
+	{
+		Name:        "inconsistent-times",
+		Description: "A time in the metadata is not a duration, or total_time is shorter than prep_time or cook_time, or their sum",
+		Severity:    Warning,
+		Check:       checkTimes,
+	},
+	{
+		Name:        "dangling-reference",
+		Description: "An ingredient reference (@&) has no earlier ingredient of that name, so it is left off the shopping list",
+		Severity:    Warning,
+		Check:       checkReferences,
+	},
+}
+
+// Linter runs a set of rules. The zero value runs DefaultRules with their default
+// severities.
+//
+// Example:
+//
+//	linter := lint.Linter{
+//	    Disabled:   []string{"unknown-unit"},
+//	    Severities: map[string]lint.Severity{"missing-servings": lint.Error},
+//	}
+//	issues := linter.Lint(recipe)
+type Linter struct {
+	Rules            []Rule                 // Rules to run (default: DefaultRules)
+	Disabled         []string               // Names of rules to skip
+	Severities       map[string]Severity    // Severity overrides by rule name
+	KnownIngredients []string               // Extra ingredient names for the unmarked-ingredient rule
+	Parser           *parser.CooklangParser // Parser for LintSource and LintFile (default: parser.New())
+}
+
+// Lint checks a parsed recipe with the default rules. Rules that need the recipe
+// text are skipped; use LintSource or LintFile to run them too.
+//
+// Parameters:
+//   - recipe: The recipe to check
+//
+// Returns:
+//   - []Issue: The issues found, sorted by position (nil if there are none)
+func Lint(recipe *cooklang.Recipe) []Issue {
+	return Linter{}.Lint(recipe)
+}
+
+// LintSource parses and checks recipe text with the default rules.
+func LintSource(source []byte) ([]Issue, error) {
+	return Linter{}.LintSource(source)
+}
+
+// LintFile reads, parses and checks a recipe file with the default rules.
+func LintFile(path string) ([]Issue, error) {
+	return Linter{}.LintFile(path)
+}
+
+// Lint checks a parsed recipe. Rules that need the recipe text find nothing.
+func (l Linter) Lint(recipe *cooklang.Recipe) []Issue {
+	return l.run(&Input{Recipe: recipe, KnownIngredients: l.KnownIngredients})
+}
+
+// LintSource parses and checks recipe text.
+//
+// Parameters:
+//   - source: The Cooklang recipe text
+//
+// Returns:
+//   - []Issue: The issues found, sorted by position
+//   - error: An error if the recipe cannot be parsed
+func (l Linter) LintSource(source []byte) ([]Issue, error) {
+	p := l.Parser
+	if p == nil {
+		p = parser.New()
+	}
+	parsed, err := p.ParseBytes(source)
+	if err != nil {
+		return nil, err
+	}
+	return l.run(&Input{
+		Recipe:           cooklang.ToCooklangRecipe(parsed),
+		Source:           source,
+		Parsed:           parsed,
+		KnownIngredients: l.KnownIngredients,
+	}), nil
+}
+
+// LintFile reads, parses and checks a recipe file.
+func (l Linter) LintFile(path string) ([]Issue, error) {
+	source, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return l.LintSource(source)
+}
+
+// run applies the enabled rules and sorts the issues.
+func (l Linter) run(in *Input) []Issue {
+	rules := l.Rules
+	if rules == nil {
+		rules = DefaultRules
+	}
+	var issues []Issue
+	for _, rule := range rules {
+		if slices.Contains(l.Disabled, rule.Name) || rule.Check == nil {
+			continue
+		}
+		severity := rule.Severity
+		if override, ok := l.Severities[rule.Name]; ok {
+			severity = override
+		}
+		for _, issue := range rule.Check(in) {
+			issue.Rule = rule.Name
+			issue.Severity = severity
+			issues = append(issues, issue)
+		}
+	}
+	sort.SliceStable(issues, func(i, j int) bool {
+		a, b := issues[i], issues[j]
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Step < b.Step
+	})
+	return issues
+}
+
+// checkUnmarkedIngredients reports ingredients mentioned in a step's text before
+// any step marks them with @.
+func checkUnmarkedIngredients(in *Input) []Issue {
+	if in.Recipe == nil {
+		return nil
+	}
+	names := make(map[string]string) // lowercase name -> name as written
+	for _, ingredient := range in.Recipe.GetIngredients().Ingredients {
+		names[strings.ToLower(ingredient.Name)] = ingredient.Name
+	}
+	for _, name := range in.KnownIngredients {
+		if _, ok := names[strings.ToLower(name)]; !ok && name != "" {
+			names[strings.ToLower(name)] = name
+		}
+	}
+	patterns := make(map[string]*regexp.Regexp, len(names))
+	for lower := range names {
+		patterns[lower] = regexp.MustCompile(`(?i)\b` + regexp.QuoteMeta(lower) + `(?:e?s)?\b`)
+	}
+
+	var issues []Issue
+	marked := make(map[string]bool)
+	numbers := in.Recipe.StepNumbers()
+	for step := in.Recipe.FirstStep; step != nil; step = step.NextStep {
+		var text strings.Builder
+		for component := step.FirstComponent; component != nil; component = component.GetNext() {
+			switch comp := component.(type) {
+			case *cooklang.Ingredient:
+				marked[strings.ToLower(comp.Name)] = true
+			case *cooklang.Instruction:
+				text.WriteString(comp.Text)
+				text.WriteString(" ")
+			}
+		}
+		for _, lower := range sortedKeys(patterns) {
+			if !marked[lower] && patterns[lower].MatchString(text.String()) {
+				issues = append(issues, Issue{
+					Step:    numbers[step],
+					Line:    step.Position.Line,
+					Message: fmt.Sprintf("%q is mentioned before it is marked as an ingredient (@%s{})", names[lower], names[lower]),
+				})
+				marked[lower] = true // Report each ingredient once
+			}
+		}
+	}
+	return issues
+}
+
+// checkTimerUnits reports timers whose duration has no unit.
+func checkTimerUnits(in *Input) []Issue {
+	if in.Recipe == nil {
+		return nil
+	}
+	var issues []Issue
+	numbers := in.Recipe.StepNumbers()
+	for step := in.Recipe.FirstStep; step != nil; step = step.NextStep {
+		for component := step.FirstComponent; component != nil; component = component.GetNext() {
+			timer, ok := component.(*cooklang.Timer)
+			if !ok || timer.Unit != "" || timer.Duration == "" {
+				continue
+			}
+			if _, err := strconv.ParseFloat(strings.TrimSpace(timer.Duration), 64); err != nil {
+				if _, qerr := cooklang.ParseQuantity(timer.Duration); qerr != nil {
+					continue // "1 hour 30 minutes" or "overnight" carry their own unit
+				}
+			}
+			issues = append(issues, Issue{
+				Step:    numbers[step],
+				Line:    timer.Position.Line,
+				Message: fmt.Sprintf("timer %q has no unit; add one, e.g. ~{%s%%minutes}", timer.RenderDisplay(), timer.Duration),
+			})
+		}
+	}
+	return issues
+}
+
+// checkTemperatures reports implausible temperatures in the steps and in the
+// temperature metadata, and temperature metadata without a scale.
+func checkTemperatures(in *Input) []Issue {
+	if in.Recipe == nil {
+		return nil
+	}
+	var issues []Issue
+	for _, key := range sortedKeys(in.Recipe.Metadata) {
+		if !cooklang.IsTemperatureMetadataKey(key) {
+			continue
+		}
+		value := in.Recipe.Metadata[key]
+		t, err := cooklang.ParseTemperature(value)
+		if err != nil {
+			issues = append(issues, Issue{Message: fmt.Sprintf("%s %q is not a temperature with a scale; write e.g. \"%s: 180°C\"", key, value, key)})
+			continue
+		}
+		if reason := t.Suspicious(true); reason != "" {
+			issues = append(issues, Issue{Message: fmt.Sprintf("%s: %s", key, reason)})
+		}
+	}
+	numbers := in.Recipe.StepNumbers()
+	for step := in.Recipe.FirstStep; step != nil; step = step.NextStep {
+		for component := step.FirstComponent; component != nil; component = component.GetNext() {
+			if t, ok := component.(*cooklang.Temperature); ok {
+				if reason := t.Suspicious(false); reason != "" {
+					issues = append(issues, Issue{Step: numbers[step], Line: t.Position.Line, Message: reason})
+				}
+			}
+		}
+	}
+	return issues
+}
+
+// checkTimes reports prep, cook and total times that are not durations, and total
+// times shorter than the prep and cook time they include.
+func checkTimes(in *Input) []Issue {
+	if in.Recipe == nil {
+		return nil
+	}
+	var issues []Issue
+	for _, key := range []string{"prep_time", "cook_time", "total_time", "time"} {
+		value, ok := in.Recipe.Metadata.GetString(key)
+		if !ok || strings.TrimSpace(value) == "" {
+			continue
+		}
+		if _, err := in.Recipe.Metadata.GetDuration(key); err != nil {
+			issues = append(issues, Issue{Message: fmt.Sprintf("%s %q is not a duration; write e.g. \"1 hour 30 minutes\"", key, value)})
+		}
+	}
+
+	times := in.Recipe.Times()
+	if times.Total <= 0 || times.TotalComputed {
+		return issues
+	}
+	switch {
+	case times.Prep > 0 && times.Cook > 0 && times.Total < times.Prep+times.Cook:
+		issues = append(issues, Issue{Message: fmt.Sprintf("total time %s is shorter than prep_time plus cook_time (%s)", times.Total, times.Prep+times.Cook)})
+	case times.Total < times.Prep:
+		issues = append(issues, Issue{Message: fmt.Sprintf("total time %s is shorter than prep_time (%s)", times.Total, times.Prep)})
+	case times.Total < times.Cook:
+		issues = append(issues, Issue{Message: fmt.Sprintf("total time %s is shorter than cook_time (%s)", times.Total, times.Cook)})
+	}
+	return issues
+}
+
+// checkReferences reports ingredient references (@&flour{}) that do not refer back to
+// an ingredient of the same name added in an earlier or the same step.
+func checkReferences(in *Input) []Issue {
+	if in.Recipe == nil {
+		return nil
+	}
+	var issues []Issue
+	added := make(map[string]bool)
+	numbers := in.Recipe.StepNumbers()
+	for step := in.Recipe.FirstStep; step != nil; step = step.NextStep {
+		for component := step.FirstComponent; component != nil; component = component.GetNext() {
+			ingredient, ok := component.(*cooklang.Ingredient)
+			if !ok {
+				continue
+			}
+			lower := strings.ToLower(ingredient.Name)
+			if !ingredient.IsReference() {
+				added[lower] = true
+				continue
+			}
+			if !added[lower] {
+				issues = append(issues, Issue{
+					Step:    numbers[step],
+					Line:    ingredient.Position.Line,
+					Message: fmt.Sprintf("@&%s{} refers to no earlier ingredient; add it with @%s{} first", ingredient.Name, ingredient.Name),
+				})
+				added[lower] = true // Report each ingredient once
+			}
+		}
+	}
+	return issues
+}
+
+// checkServings reports recipes without servings metadata.
+func checkServings(in *Input) []Issue {
+	if in.Recipe == nil {
+		return nil
+	}
+	if servings, ok := in.Recipe.Metadata.GetString("servings"); ok {
+		if value, err := in.Recipe.Metadata.GetFloat("servings"); err != nil || value <= 0 {
+			return []Issue{{Message: fmt.Sprintf("servings %q is not a positive number", servings)}}
+		}
+		return nil
+	}
+	return []Issue{{Message: "no servings metadata; add e.g. \"servings: 4\""}}
+}
+
+// checkQuantities reports ingredient and cookware quantities the parser could not read.
+func checkQuantities(in *Input) []Issue {
+	if in.Parsed == nil {
+		return nil
+	}
+	var issues []Issue
+	for _, step := range in.Parsed.Steps {
+		for _, component := range step.Components {
+			quantity := strings.TrimSpace(component.Quantity)
+			if quantity == "" || quantity == "some" {
+				continue
+			}
+			switch component.Type {
+			case "ingredient":
+				if _, err := strconv.ParseFloat(quantity, 64); err == nil {
+					continue
+				}
+				if amount, err := cooklang.ParseQuantity(quantity); err == nil && !amount.IsSome() {
+					continue
+				}
+				issues = append(issues, Issue{
+					Line:    component.Position.Line,
+					Message: fmt.Sprintf("quantity %q of %q is not a number, fraction or range; it is read as \"some\"", quantity, component.Name),
+				})
+			case "cookware":
+				if _, err := strconv.Atoi(quantity); err == nil {
+					continue
+				}
+				issues = append(issues, Issue{
+					Line:    component.Position.Line,
+					Message: fmt.Sprintf("cookware quantity %q of %q is not a whole number; it is read as 1", quantity, component.Name),
+				})
+			}
+		}
+	}
+	return issues
+}
+
+// frontmatterKey matches a top-level key in YAML frontmatter.
+var frontmatterKey = regexp.MustCompile(`^([A-Za-z0-9_][^:#]*?)\s*:`)
+
+// checkDuplicateMetadata reports frontmatter keys that are set more than once.
+func checkDuplicateMetadata(in *Input) []Issue {
+	if in.Source == nil {
+		return nil
+	}
+	lines := strings.Split(strings.ReplaceAll(string(in.Source), "\r\n", "\n"), "\n")
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != "---" {
+		return nil
+	}
+	var issues []Issue
+	seen := make(map[string]int)
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == "---" {
+			break
+		}
+		m := frontmatterKey.FindStringSubmatch(lines[i])
+		if m == nil {
+			continue
+		}
+		key := strings.ToLower(m[1])
+		if first, ok := seen[key]; ok {
+			issues = append(issues, Issue{
+				Line:    i + 1,
+				Message: fmt.Sprintf("metadata key %q is already set on line %d; the earlier value is ignored", m[1], first),
+			})
+			continue
+		}
+		seen[key] = i + 1
+	}
+	return issues
+}
+
+// checkUnits reports ingredient units that cannot be converted.
+func checkUnits(in *Input) []Issue {
+	if in.Recipe == nil {
+		return nil
+	}
+	var issues []Issue
+	reported := make(map[string]bool)
+	numbers := in.Recipe.StepNumbers()
+	for step := in.Recipe.FirstStep; step != nil; step = step.NextStep {
+		for component := step.FirstComponent; component != nil; component = component.GetNext() {
+			ingredient, ok := component.(*cooklang.Ingredient)
+			if !ok || ingredient.Unit == "" || reported[ingredient.Unit] {
+				continue
+			}
+			if _, custom := in.Recipe.CustomUnits[ingredient.Unit]; custom || cooklang.IsKnownUnit(ingredient.Unit) {
+				continue
+			}
+			reported[ingredient.Unit] = true
+			issues = append(issues, Issue{
+				Step:    numbers[step],
+				Line:    ingredient.Position.Line,
+				Message: fmt.Sprintf("unit %q (%s) cannot be converted or combined with other units", ingredient.Unit, ingredient.Name),
+			})
+		}
+	}
+	return issues
+}
+
+// sortedKeys returns the keys of m in order, so issues are reported deterministically.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
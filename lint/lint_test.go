@@ -0,0 +1,207 @@
+package lint
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/hilli/cooklang"
+)
+
+func rulesOf(issues []Issue) []string {
+	rules := make([]string, len(issues))
+	for i, issue := range issues {
+		rules[i] = issue.Rule
+	}
+	return rules
+}
+
+func TestLintSource(t *testing.T) {
+	source := `---
+title: Pancakes
+servings: 4
+title: Fluffy Pancakes
+---
+Whisk the flour with @milk{300%ml} and @eggs{two}.
+
+Fold in @flour{200%g} and a pinch of @salt{1%pinch}.
+
+Rest for ~{10}, then fry in a #pan{big}.
+`
+	issues, err := LintSource([]byte(source))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []struct {
+		rule     string
+		severity Severity
+		step     int
+		line     int
+		message  string
+	}{
+		{"duplicate-metadata", Error, 0, 4, `"title" is already set on line 2`},
+		{"unparseable-quantity", Error, 0, 6, `quantity "two" of "eggs"`},
+		{"unmarked-ingredient", Warning, 1, 6, `"flour" is mentioned before it is marked`},
+		{"unknown-unit", Info, 2, 8, `unit "pinch"`},
+		{"unparseable-quantity", Error, 0, 10, `cookware quantity "big" of "pan"`},
+		{"timer-unit", Warning, 3, 10, `timer "10" has no unit`},
+	}
+	if len(issues) != len(expected) {
+		t.Fatalf("expected %d issues, got %d:\n%v", len(expected), len(issues), issues)
+	}
+	for i, want := range expected {
+		got := issues[i]
+		if got.Rule != want.rule || got.Severity != want.severity || got.Step != want.step || got.Line != want.line || !strings.Contains(got.Message, want.message) {
+			t.Errorf("issue %d: expected %+v, got %+v", i, want, got)
+		}
+	}
+}
+
+func TestLintRecipe(t *testing.T) {
+	recipe, err := cooklang.ParseString("Boil the water.\n\nAdd @water{1%l} and @pasta{500%g}. Cook ~{8%minutes}.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	issues := Lint(recipe)
+	if got := strings.Join(rulesOf(issues), ","); got != "missing-servings,unmarked-ingredient" {
+		t.Fatalf("unexpected issues: %v", issues)
+	}
+	if issues[1].Step != 1 || issues[1].String() != `warning: line 1: "water" is mentioned before it is marked as an ingredient (@water{}) [unmarked-ingredient]` {
+		t.Errorf("unexpected issue: %s", issues[1])
+	}
+}
+
+func TestLintClean(t *testing.T) {
+	source := "---\nservings: 2\nunits: {scoop: 30 g}\n---\nMix @flour{2%scoop} with @milk{1%cup} for ~{2%minutes}.\n"
+	issues, err := LintSource([]byte(source))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 0 {
+		t.Errorf("expected no issues, got %v", issues)
+	}
+}
+
+func TestLinterConfiguration(t *testing.T) {
+	recipe, err := cooklang.ParseString("Season with salt and add @pepper{1%pinch}.")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	linter := Linter{
+		Disabled:         []string{"unknown-unit"},
+		Severities:       map[string]Severity{"missing-servings": Error},
+		KnownIngredients: []string{"salt"},
+	}
+	issues := linter.Lint(recipe)
+	if got := strings.Join(rulesOf(issues), ","); got != "missing-servings,unmarked-ingredient" {
+		t.Fatalf("unexpected issues: %v", issues)
+	}
+	if issues[0].Severity != Error {
+		t.Errorf("expected the severity override, got %s", issues[0].Severity)
+	}
+
+	custom := Linter{Rules: []Rule{{
+		Name:     "needs-title",
+		Severity: Warning,
+		Check: func(in *Input) []Issue {
+			if in.Recipe.Title == "" {
+				return []Issue{{Message: "no title"}}
+			}
+			return nil
+		},
+	}}}
+	if issues := custom.Lint(recipe); len(issues) != 1 || issues[0].String() != "warning: no title [needs-title]" {
+		t.Errorf("unexpected issues from a custom rule: %v", issues)
+	}
+}
+
+func TestSeverity(t *testing.T) {
+	for _, name := range []string{"info", "Warning", "ERROR"} {
+		severity, err := ParseSeverity(name)
+		if err != nil || !strings.EqualFold(severity.String(), name) {
+			t.Errorf("ParseSeverity(%q) = %v, %v", name, severity, err)
+		}
+	}
+	if _, err := ParseSeverity("fatal"); err == nil {
+		t.Error("expected an error for an unknown severity")
+	}
+
+	data, err := json.Marshal(Issue{Rule: "timer-unit", Severity: Warning, Message: "m", Step: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"rule":"timer-unit","severity":"warning","message":"m","step":2}` {
+		t.Errorf("unexpected JSON: %s", data)
+	}
+	var issue Issue
+	if err := json.Unmarshal(data, &issue); err != nil || issue.Severity != Warning {
+		t.Errorf("unexpected round trip: %+v, %v", issue, err)
+	}
+}
+
+func TestLintTemperatures(t *testing.T) {
+	source := "---\nservings: 4\noven_temp: 180F\ntemperature: hot\n---\nPreheat the #oven{} to 350°C.\n\nBake the @bread{} for ~{30%minutes}.\n"
+	issues, err := LintSource([]byte(source))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{`oven_temp: 180°F is cooler than an oven is set; did you mean 180°C?`, `temperature "hot" is not a temperature with a scale`, `350°C is hotter than a home oven; did you mean 350°F?`}
+	if len(issues) != len(expected) {
+		t.Fatalf("expected %d issues, got %v", len(expected), issues)
+	}
+	for i, want := range expected {
+		if issues[i].Rule != "suspicious-temperature" || !strings.Contains(issues[i].Message, want) {
+			t.Errorf("issue %d: expected %q, got %s", i, want, issues[i])
+		}
+	}
+	if issues[2].Line != 6 || issues[2].Step != 1 {
+		t.Errorf("expected the step temperature on line 6, got %s", issues[2])
+	}
+}
+
+func TestLintTimes(t *testing.T) {
+	tests := []struct {
+		name     string
+		metadata string
+		expected []string
+	}{
+		{"consistent", "prep_time: 15 minutes\ncook_time: 1 hour\ntotal_time: 1h30m", nil},
+		{"computed total", "prep_time: 15 minutes\ncook_time: 1 hour", nil},
+		{"too short", "prep_time: 20 minutes\ncook_time: 40 minutes\ntotal_time: 45 minutes", []string{"total time 45m0s is shorter than prep_time plus cook_time (1h0m0s)"}},
+		{"shorter than prep", "prep_time: 2 hours\ntotal_time: 1 hour", []string{"total time 1h0m0s is shorter than prep_time (2h0m0s)"}},
+		{"not a duration", "prep_time: a while\ncook_time: 30 minutes", []string{`prep_time "a while" is not a duration`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := "---\nservings: 2\n" + tt.metadata + "\n---\nBake the @bread{}.\n"
+			issues, err := LintSource([]byte(source))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(issues) != len(tt.expected) {
+				t.Fatalf("expected %d issues, got %v", len(tt.expected), issues)
+			}
+			for i, want := range tt.expected {
+				if issues[i].Rule != "inconsistent-times" || issues[i].Severity != Warning || !strings.Contains(issues[i].Message, want) {
+					t.Errorf("issue %d: expected %q, got %s", i, want, issues[i])
+				}
+			}
+		})
+	}
+}
+
+func TestLintReferences(t *testing.T) {
+	source := "---\nservings: 2\n---\nWhisk @flour{500%g} and use @&Flour{} right away.\n\nFold in the @&sugar{} and the rest of the @&sugar{}.\n"
+	issues, err := LintSource([]byte(source))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 1 {
+		t.Fatalf("expected one issue, got %v", issues)
+	}
+	if issue := issues[0]; issue.Rule != "dangling-reference" || issue.Line != 6 || issue.Step != 2 || !strings.Contains(issue.Message, "@&sugar{} refers to no earlier ingredient") {
+		t.Errorf("unexpected issue: %s", issue)
+	}
+}
@@ -1,9 +1,12 @@
 package cooklang
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"regexp"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/hilli/cooklang/parser"
@@ -15,9 +18,20 @@ type Menu struct {
 }
 
 // MenuDay represents a single day or section in a menu, containing recipe references.
+// Recipes lists all of the day's recipes; Meals groups them by the meal they are
+// listed under.
 type MenuDay struct {
 	Name    string       `json:"name"`
 	Date    *time.Time   `json:"date,omitempty"`
+	Meals   []MenuMeal   `json:"meals,omitempty"`
+	Recipes []MenuRecipe `json:"recipes"`
+}
+
+// MenuMeal represents a meal within a menu day, such as "Breakfast:", and the recipe
+// references listed under it. Recipes listed before the first meal of a day are in a
+// meal without a name.
+type MenuMeal struct {
+	Name    string       `json:"name"`
 	Recipes []MenuRecipe `json:"recipes"`
 }
 
@@ -30,8 +44,18 @@ type MenuRecipe struct {
 
 var dateRegexp = regexp.MustCompile(`(\d{4}-\d{2}-\d{2})`)
 
+// mealRegexp matches a meal name such as "Breakfast:" at the end of the text before
+// a recipe reference, optionally followed by a "-" list marker.
+var mealRegexp = regexp.MustCompile(`([\p{L}\p{N}][\p{L}\p{N} '&/-]*):\s*-?\s*$`)
+
 // ParseMenuString parses a menu from a string. A .menu file is a valid Cooklang file
-// using sections for days and recipe references for dishes.
+// using sections for days, recipe references for dishes, and text ending in a colon,
+// such as "Breakfast:", for the meals of a day.
+//
+// Example:
+//
+//	menu, _ := cooklang.ParseMenuString("== Monday ==\n\nBreakfast:\n- @./Pancakes{2}\n\nDinner:\n- @./Lasagne{4%servings}\n")
+//	fmt.Println(menu.Days[0].Meals[1].Name) // Dinner
 func ParseMenuString(s string) (*Menu, error) {
 	p := parser.New()
 	p.ExtendedMode = true
@@ -42,6 +66,7 @@ func ParseMenuString(s string) (*Menu, error) {
 
 	menu := &Menu{}
 	var currentDay *MenuDay
+	meal, newMeal := "", false
 
 	for _, step := range recipe.Steps {
 		for _, comp := range step.Components {
@@ -60,6 +85,12 @@ func ParseMenuString(s string) (*Menu, error) {
 				}
 				menu.Days = append(menu.Days, day)
 				currentDay = &menu.Days[len(menu.Days)-1]
+				meal, newMeal = "", false
+
+			case "text":
+				if match := mealRegexp.FindStringSubmatch(comp.Value); match != nil {
+					meal, newMeal = strings.TrimSpace(strings.TrimPrefix(match[1], "- ")), true
+				}
 
 			case "recipeReference":
 				ref := MenuRecipe{
@@ -82,6 +113,12 @@ func ParseMenuString(s string) (*Menu, error) {
 					currentDay = &menu.Days[len(menu.Days)-1]
 				}
 				currentDay.Recipes = append(currentDay.Recipes, ref)
+				if newMeal || len(currentDay.Meals) == 0 {
+					currentDay.Meals = append(currentDay.Meals, MenuMeal{Name: meal})
+					newMeal = false
+				}
+				last := &currentDay.Meals[len(currentDay.Meals)-1]
+				last.Recipes = append(last.Recipes, ref)
 			}
 		}
 	}
@@ -97,3 +134,93 @@ func ParseMenuFile(filename string) (*Menu, error) {
 	}
 	return ParseMenuString(string(content))
 }
+
+// ResolvedMenuRecipe is a recipe of a menu, read by a RecipeResolver and scaled by
+// the quantity and unit the menu gives it.
+type ResolvedMenuRecipe struct {
+	Day  string // Name of the day
+	Meal string // Name of the meal, or "" for recipes not listed under a meal
+	MenuRecipe
+	Recipe *Recipe // The recipe, scaled
+}
+
+// Resolve reads each recipe of the menu with the resolver, such as a Library, and
+// scales it like ResolveAndScale: by a factor ("@./Pancakes{2}"), to servings
+// ("{4%servings}") or to a yield ("{48%cookies}"). A day without Meals, such as one
+// built in code, uses its Recipes. A recipe that cannot be resolved or scaled does
+// not stop the others.
+//
+// Parameters:
+//   - resolver: Resolves the menu's recipe paths, e.g. a Library of the recipe directory
+//
+// Returns:
+//   - []ResolvedMenuRecipe: The scaled recipes, in menu order
+//   - error: An error joining all recipes that could not be resolved or scaled, or nil
+//
+// Example:
+//
+//	library, _ := cooklang.LoadLibrary("recipes")
+//	menu, _ := cooklang.ParseMenuFile("recipes/week.menu")
+//	recipes, err := menu.Resolve(library)
+func (m *Menu) Resolve(resolver RecipeResolver) ([]ResolvedMenuRecipe, error) {
+	var resolved []ResolvedMenuRecipe
+	var errs []error
+	for _, day := range m.Days {
+		meals := day.Meals
+		if len(meals) == 0 && len(day.Recipes) > 0 {
+			meals = []MenuMeal{{Recipes: day.Recipes}}
+		}
+		for _, meal := range meals {
+			for _, ref := range meal.Recipes {
+				recipe, err := ResolveAndScale(resolver, &RecipeReference{Path: ref.Path, Quantity: ref.Quantity, Unit: ref.Unit})
+				if err != nil {
+					errs = append(errs, menuError(day, err))
+					continue
+				}
+				resolved = append(resolved, ResolvedMenuRecipe{Day: day.Name, Meal: meal.Name, MenuRecipe: ref, Recipe: recipe})
+			}
+		}
+	}
+	return resolved, errors.Join(errs...)
+}
+
+// ShoppingList resolves and scales the menu's recipes (see Resolve) and combines
+// their ingredients into one shopping list. A recipe used on several days is
+// shopped for each time.
+//
+// Parameters:
+//   - resolver: Resolves the menu's recipe paths, e.g. a Library of the recipe directory
+//
+// Returns:
+//   - *ShoppingList: The consolidated shopping list of the recipes that were resolved
+//   - error: An error joining all recipes that could not be resolved or scaled, or nil;
+//     the list is nil only if no recipe could be resolved or consolidation failed
+//
+// Example:
+//
+//	library, _ := cooklang.LoadLibrary("recipes")
+//	menu, _ := cooklang.ParseMenuFile("recipes/week.menu")
+//	list, err := menu.ShoppingList(library)
+func (m *Menu) ShoppingList(resolver RecipeResolver) (*ShoppingList, error) {
+	resolved, resolveErr := m.Resolve(resolver)
+	if len(resolved) == 0 && resolveErr != nil {
+		return nil, resolveErr
+	}
+	recipes := make([]*Recipe, len(resolved))
+	for i, item := range resolved {
+		recipes[i] = item.Recipe
+	}
+	list, err := CreateShoppingList(recipes...)
+	if err != nil {
+		return nil, errors.Join(resolveErr, err)
+	}
+	return list, resolveErr
+}
+
+// menuError adds the day to an error resolving one of its recipes.
+func menuError(day MenuDay, err error) error {
+	if day.Name == "" {
+		return err
+	}
+	return fmt.Errorf("%s: %w", day.Name, err)
+}
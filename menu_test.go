@@ -1,6 +1,7 @@
 package cooklang
 
 import (
+	"strings"
 	"testing"
 	"time"
 )
@@ -278,3 +279,112 @@ func TestParseMenuSingleDay(t *testing.T) {
 		t.Errorf("expected 3 recipes, got %d", len(menu.Days[0].Recipes))
 	}
 }
+
+func TestParseMenuMeals(t *testing.T) {
+	input := `== Monday ==
+
+@./coffee{}
+
+Breakfast:
+- @./pancakes{2}
+- @./juice{}
+
+Dinner:
+- @./pasta{4%servings}
+
+== Tuesday ==
+
+Lunch: @./soup{}
+`
+	menu, err := ParseMenuString(input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(menu.Days) != 2 {
+		t.Fatalf("expected 2 days, got %d", len(menu.Days))
+	}
+	monday := menu.Days[0]
+	if len(monday.Recipes) != 4 {
+		t.Errorf("expected 4 recipes on Monday, got %d", len(monday.Recipes))
+	}
+	var meals []string
+	for _, meal := range monday.Meals {
+		meals = append(meals, meal.Name+"="+strings.Repeat("x", len(meal.Recipes)))
+	}
+	if got := strings.Join(meals, " "); got != "=x Breakfast=xx Dinner=x" {
+		t.Errorf("Monday meals = %q", got)
+	}
+	if got := monday.Meals[2].Recipes[0]; got.Path != "./pasta" || got.Quantity != 4 || got.Unit != "servings" {
+		t.Errorf("dinner recipe = %+v", got)
+	}
+	if len(menu.Days[1].Meals) != 1 || menu.Days[1].Meals[0].Name != "Lunch" {
+		t.Errorf("Tuesday meals = %+v", menu.Days[1].Meals)
+	}
+}
+
+func TestMenuResolveAndShoppingList(t *testing.T) {
+	library := NewLibrary()
+	pancakes, _ := ParseString("---\ntitle: Pancakes\nservings: 2\n---\nMix @flour{200%g} and @eggs{2}.")
+	pasta, _ := ParseString("---\ntitle: Pasta\nservings: 2\n---\nBoil @pasta{250%g} with @eggs{1}.")
+	library.Add("pancakes.cook", pancakes)
+	library.Add("dinner/pasta.cook", pasta)
+
+	menu, err := ParseMenuString("== Monday ==\nBreakfast:\n- @./pancakes{2}\nDinner:\n- @./dinner/pasta{4%servings}\n\n== Tuesday ==\nDinner:\n- @./missing{}\n")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	resolved, err := menu.Resolve(library)
+	if err == nil || !strings.Contains(err.Error(), "Tuesday") || !strings.Contains(err.Error(), "missing") {
+		t.Errorf("expected an error for the missing recipe on Tuesday, got %v", err)
+	}
+	if len(resolved) != 2 {
+		t.Fatalf("expected 2 resolved recipes, got %d", len(resolved))
+	}
+	if resolved[0].Day != "Monday" || resolved[0].Meal != "Breakfast" || resolved[1].Meal != "Dinner" {
+		t.Errorf("resolved = %+v", resolved)
+	}
+	if resolved[1].Recipe.Servings != 4 {
+		t.Errorf("expected pasta scaled to 4 servings, got %v", resolved[1].Recipe.Servings)
+	}
+
+	list, err := menu.ShoppingList(library)
+	if err == nil {
+		t.Error("expected an error for the missing recipe")
+	}
+	if list == nil {
+		t.Fatal("expected a shopping list from the resolved recipes")
+	}
+	amounts := list.ToMap()
+	for name, want := range map[string]string{"flour": "400 g", "pasta": "500 g", "eggs": "6"} {
+		if amounts[name] != want {
+			t.Errorf("%s = %q, want %q", name, amounts[name], want)
+		}
+	}
+}
+
+func TestMenuResolveWithoutMeals(t *testing.T) {
+	library := NewLibrary()
+	soup, _ := ParseString("---\ntitle: Soup\nservings: 2\n---\nBoil @carrots{300%g}.")
+	library.Add("soup.cook", soup)
+
+	menu := &Menu{Days: []MenuDay{
+		{Name: "Monday", Recipes: []MenuRecipe{{Path: "./soup", Quantity: 4, Unit: "servings"}}},
+		{Name: "Tuesday", Recipes: []MenuRecipe{{Path: "./soup"}}},
+	}}
+	resolved, err := menu.Resolve(library)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(resolved) != 2 || resolved[0].Day != "Monday" || resolved[0].Meal != "" || resolved[0].Recipe.Servings != 4 {
+		t.Fatalf("resolved = %+v", resolved)
+	}
+
+	list, err := menu.ShoppingList(library)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := list.ToMap()["carrots"]; got != "900 g" {
+		t.Errorf("carrots = %q, want 900 g", got)
+	}
+}
@@ -0,0 +1,224 @@
+package cooklang
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// mergedMetadataKeys are the metadata keys MergeRecipes sets from the merged fields
+// rather than keeping the sources' values.
+var mergedMetadataKeys = map[string]bool{
+	"title": true, "description": true, "servings": true, "prep_time": true, "total_time": true,
+	"tags": true, "images": true, "image": true, "author": true, "cuisine": true,
+	"difficulty": true, "date": true, "units": true,
+}
+
+// MergeRecipes combines recipes into one, e.g. a starter, main and dessert into a
+// single printable menu with a combined shopping list. Each recipe becomes a
+// section named after its title ("Recipe 2" for recipes without one), and its own
+// sections are renamed "Title: Section". The recipes are copied, so they are not
+// changed.
+//
+// The merged recipe's metadata is combined: tags and images are joined without
+// duplicates, authors and cuisines are listed once each, prep and total times are
+// added up when all recipes that set them can be read, and servings, difficulty and other
+// metadata are kept when all recipes that set them agree. Cookware used by several
+// recipes is deduplicated: every use takes the name of the first, so "Large pot"
+// and "large pot" are one item in cookware lists. Custom units are joined, with the
+// first recipe's definition winning when two differ.
+//
+// Parameters:
+//   - name: The title of the merged recipe
+//   - recipes: The recipes to merge, in order; nil recipes are skipped
+//
+// Returns:
+//   - *Recipe: The merged recipe
+//
+// Example:
+//
+//	menu := cooklang.MergeRecipes("Sunday dinner", soup, roast, tart)
+//	list, _ := cooklang.CreateShoppingList(menu)
+func MergeRecipes(name string, recipes ...*Recipe) *Recipe {
+	merged := &Recipe{Title: name, Metadata: Metadata{}}
+	if name != "" {
+		merged.Metadata["title"] = name
+	}
+
+	var sources []*Recipe
+	for _, recipe := range recipes {
+		if recipe != nil {
+			sources = append(sources, recipe)
+		}
+	}
+
+	cookware := map[string]string{} // Lowercase name to the first spelling
+	var lastStep *Step
+	appendStep := func(step *Step) {
+		if lastStep == nil {
+			merged.FirstStep = step
+		} else {
+			lastStep.NextStep = step
+		}
+		lastStep = step
+	}
+	for i, source := range sources {
+		title := source.Title
+		if title == "" {
+			title = fmt.Sprintf("Recipe %d", i+1)
+		}
+		appendStep(&Step{FirstComponent: &Section{Name: title}})
+
+		copied := source.Clone()
+		for step := copied.FirstStep; step != nil; {
+			next := step.NextStep
+			step.NextStep = nil
+			for component := range step.Components() {
+				switch c := component.(type) {
+				case *Section:
+					if c.Name == "" {
+						c.Name = title
+					} else {
+						c.Name = title + ": " + c.Name
+					}
+				case *Cookware:
+					key := strings.ToLower(c.Name)
+					if first, ok := cookware[key]; ok {
+						c.Name = first
+					} else {
+						cookware[key] = c.Name
+					}
+				}
+			}
+			appendStep(step)
+			step = next
+		}
+		for unitName, unit := range source.CustomUnits {
+			if _, ok := merged.CustomUnits[unitName]; !ok {
+				if merged.CustomUnits == nil {
+					merged.CustomUnits = CustomUnits{}
+				}
+				merged.CustomUnits[unitName] = unit
+			}
+		}
+	}
+
+	mergeRecipeFields(merged, sources)
+	return merged
+}
+
+// mergeRecipeFields sets the merged recipe's fields and metadata from its sources.
+func mergeRecipeFields(merged *Recipe, sources []*Recipe) {
+	var authors, cuisines, difficulties []string
+	var servings []float32
+	var prep, total time.Duration
+	var prepOK, totalOK bool
+	for _, source := range sources {
+		merged.Tags = appendMissing(merged.Tags, source.Tags...)
+		merged.Images = appendMissing(merged.Images, source.Images...)
+		authors = appendMissing(authors, source.Author)
+		cuisines = appendMissing(cuisines, source.Cuisine)
+		difficulties = appendMissing(difficulties, source.Difficulty)
+		// Parsed recipes without servings get 1, which does not count as set
+		if source.Servings > 0 && (source.Servings != 1 || source.Metadata["servings"] != "") && !slices.Contains(servings, source.Servings) {
+			servings = append(servings, source.Servings)
+		}
+		prep, prepOK = addHumanDuration(prep, prepOK, source.PrepTime)
+		total, totalOK = addHumanDuration(total, totalOK, source.TotalTime)
+	}
+
+	merged.Author = strings.Join(authors, ", ")
+	merged.Cuisine = strings.Join(cuisines, ", ")
+	if len(difficulties) == 1 {
+		merged.Difficulty = difficulties[0]
+	}
+	if len(servings) == 1 {
+		merged.Servings = servings[0]
+		merged.Metadata["servings"] = strconv.FormatFloat(float64(servings[0]), 'f', -1, 32)
+	}
+	if prepOK && prep > 0 {
+		merged.PrepTime = formatHumanDuration(prep)
+	}
+	if totalOK && total > 0 {
+		merged.TotalTime = formatHumanDuration(total)
+	}
+	for key, value := range map[string]string{
+		"author": merged.Author, "cuisine": merged.Cuisine, "difficulty": merged.Difficulty,
+		"prep_time": merged.PrepTime, "total_time": merged.TotalTime,
+		"tags": strings.Join(merged.Tags, ", "), "images": strings.Join(merged.Images, ", "),
+	} {
+		if value != "" {
+			merged.Metadata[key] = value
+		}
+	}
+	if len(merged.CustomUnits) > 0 {
+		merged.Metadata["units"] = merged.CustomUnits.String()
+	}
+
+	// Other metadata is kept when every recipe that sets it agrees
+	conflicting := map[string]bool{}
+	for _, source := range sources {
+		for key, value := range source.Metadata {
+			if mergedMetadataKeys[key] || conflicting[key] {
+				continue
+			}
+			if existing, ok := merged.Metadata[key]; ok && existing != value {
+				delete(merged.Metadata, key)
+				conflicting[key] = true
+				continue
+			}
+			merged.Metadata[key] = value
+		}
+	}
+}
+
+// addHumanDuration adds a recipe's time to a sum of times. Empty times count as
+// zero; once a time cannot be read, the sum is unknown for good (ok is false with a
+// negative sum).
+func addHumanDuration(sum time.Duration, ok bool, value string) (time.Duration, bool) {
+	if sum < 0 || strings.TrimSpace(value) == "" {
+		return sum, ok
+	}
+	d, parsed := parseHumanDuration(value)
+	if !parsed {
+		return -1, false
+	}
+	return sum + d, true
+}
+
+// appendMissing appends the non-empty values that are not in list yet.
+func appendMissing(list []string, values ...string) []string {
+	for _, value := range values {
+		if value != "" && !slices.Contains(list, value) {
+			list = append(list, value)
+		}
+	}
+	return list
+}
+
+// formatHumanDuration writes a duration as "1 hour 30 minutes", the way recipes
+// write times; durations under a minute are written in seconds.
+func formatHumanDuration(d time.Duration) string {
+	if d < time.Minute {
+		return fmt.Sprintf("%d seconds", int(d.Seconds()))
+	}
+	var parts []string
+	hours, minutes := int(d/time.Hour), int(d%time.Hour/time.Minute)
+	if hours > 0 {
+		parts = append(parts, pluralUnit(hours, "hour"))
+	}
+	if minutes > 0 {
+		parts = append(parts, pluralUnit(minutes, "minute"))
+	}
+	return strings.Join(parts, " ")
+}
+
+// pluralUnit writes "1 hour" or "2 hours".
+func pluralUnit(n int, unit string) string {
+	if n == 1 {
+		return "1 " + unit
+	}
+	return strconv.Itoa(n) + " " + unit + "s"
+}
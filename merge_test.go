@@ -0,0 +1,96 @@
+package cooklang
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMergeRecipes(t *testing.T) {
+	parse := func(source string) *Recipe {
+		t.Helper()
+		recipe, err := ParseString(source)
+		if err != nil {
+			t.Fatalf("Failed to parse recipe: %v", err)
+		}
+		return recipe
+	}
+	soup := parse(`---
+title: Soup
+servings: 4
+tags: [starter, vegan]
+prep_time: 10 minutes
+course: dinner
+---
+Simmer @carrots{500%g} in a #Large pot{}.`)
+	roast := parse(`---
+title: Roast
+servings: 4
+tags: [main]
+prep_time: 1 hour
+author: Ann
+course: dinner
+---
+== Prepare ==
+Season @chicken{1}.
+
+== Cook ==
+Roast in the #oven{}, then rest in the #large pot{}.`)
+	untitled := parse("Serve @ice cream{2%scoops}.")
+
+	menu := MergeRecipes("Sunday dinner", soup, nil, roast, untitled)
+
+	var sections []string
+	for step := range menu.Steps() {
+		if section, ok := step.FirstComponent.(*Section); ok {
+			sections = append(sections, section.Name)
+		}
+	}
+	if got := strings.Join(sections, "|"); got != "Soup|Roast|Roast: Prepare|Roast: Cook|Recipe 3" {
+		t.Errorf("sections = %q", got)
+	}
+
+	if menu.Title != "Sunday dinner" || menu.Servings != 4 || menu.Author != "Ann" {
+		t.Errorf("title, servings, author = %q, %v, %q", menu.Title, menu.Servings, menu.Author)
+	}
+	if got := strings.Join(menu.Tags, ","); got != "starter,vegan,main" {
+		t.Errorf("tags = %q", got)
+	}
+	if menu.PrepTime != "1 hour 10 minutes" || menu.TotalTime != "" {
+		t.Errorf("prep time = %q, total time = %q", menu.PrepTime, menu.TotalTime)
+	}
+	if menu.Metadata["course"] != "dinner" || menu.Metadata["servings"] != "4" || menu.Metadata["title"] != "Sunday dinner" {
+		t.Errorf("metadata = %v", menu.Metadata)
+	}
+
+	var cookware []string
+	for _, item := range menu.GetCookware() {
+		cookware = append(cookware, item.Name)
+	}
+	if got := strings.Join(cookware, ","); got != "Large pot,oven,Large pot" {
+		t.Errorf("cookware = %q, want the second pot named like the first", got)
+	}
+	if n := len(menu.GetIngredients().Ingredients); n != 3 {
+		t.Errorf("expected 3 ingredients, got %d", n)
+	}
+
+	// The sources are not changed
+	if roast.GetCookware()[1].Name != "large pot" || roast.Title != "Roast" {
+		t.Error("MergeRecipes changed a source recipe")
+	}
+}
+
+func TestMergeRecipesConflictingMetadata(t *testing.T) {
+	a := &Recipe{Title: "A", Servings: 2, Difficulty: "easy", Metadata: Metadata{"course": "lunch", "source": "book"}}
+	b := &Recipe{Title: "B", Servings: 4, Difficulty: "hard", Metadata: Metadata{"course": "dinner"}}
+
+	merged := MergeRecipes("Menu", a, b)
+	if merged.Servings != 0 || merged.Difficulty != "" {
+		t.Errorf("servings = %v, difficulty = %q; want them left out when the recipes differ", merged.Servings, merged.Difficulty)
+	}
+	if _, ok := merged.Metadata["course"]; ok {
+		t.Errorf("expected conflicting course to be left out, got %v", merged.Metadata)
+	}
+	if merged.Metadata["source"] != "book" {
+		t.Errorf("expected source to be kept, got %v", merged.Metadata)
+	}
+}
@@ -0,0 +1,341 @@
+package cooklang
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrMetadataNotSet is returned by the typed Metadata accessors when a key is missing
+// or empty.
+var ErrMetadataNotSet = errors.New("metadata not set")
+
+// DefaultDateLayouts are the layouts tried, in order, when reading date metadata.
+var DefaultDateLayouts = []string{
+	"2006-01-02",
+	time.RFC3339,
+	"2006-01-02 15:04",
+	"2 January 2006",
+	"January 2, 2006",
+	"Jan 2, 2006",
+}
+
+// MetadataType is the type of a metadata value in a MetadataSchema.
+type MetadataType int
+
+const (
+	MetadataString      MetadataType = iota // Any text
+	MetadataInt                             // A whole number, e.g. "4"
+	MetadataNumber                          // A number, e.g. "2.5"
+	MetadataDuration                        // A duration, e.g. "1 hour 30 minutes", "1h30m" or "90" (minutes)
+	MetadataStringSlice                     // A comma-separated list, e.g. "italian, pasta"
+	MetadataDate                            // A date in one of the schema's date layouts
+	MetadataDifficulty                      // A difficulty level, e.g. "easy" (see ParseDifficulty)
+)
+
+// String returns the name of the type.
+func (t MetadataType) String() string {
+	switch t {
+	case MetadataInt:
+		return "integer"
+	case MetadataNumber:
+		return "number"
+	case MetadataDuration:
+		return "duration"
+	case MetadataStringSlice:
+		return "list"
+	case MetadataDate:
+		return "date"
+	case MetadataDifficulty:
+		return "difficulty"
+	default:
+		return "string"
+	}
+}
+
+// MetadataSchema describes the types of metadata keys, so their values can be
+// validated and read consistently. Keys that are not in Fields are strings.
+//
+// Example:
+//
+//	schema := cooklang.DefaultMetadataSchema()
+//	schema.Fields["rating"] = cooklang.MetadataInt
+//	schema.DateLayouts = append(schema.DateLayouts, "02.01.2006")
+//	if err := schema.Validate(recipe.Metadata); err != nil {
+//	    fmt.Println(err) // metadata "rating": "great" is not a valid integer
+//	}
+type MetadataSchema struct {
+	Fields      map[string]MetadataType // The type of each known key
+	DateLayouts []string                // Layouts for date values (default DefaultDateLayouts)
+}
+
+// DefaultMetadataSchema returns a new schema for the well-known metadata keys:
+// servings is a number, prep_time, cook_time, total_time and time are durations,
+// date is a date, difficulty is a difficulty level, and tags and images are lists.
+// The schema can be extended freely.
+//
+// Returns:
+//   - *MetadataSchema: A new schema with the well-known keys
+func DefaultMetadataSchema() *MetadataSchema {
+	return &MetadataSchema{
+		Fields: map[string]MetadataType{
+			"servings":   MetadataNumber,
+			"prep_time":  MetadataDuration,
+			"cook_time":  MetadataDuration,
+			"total_time": MetadataDuration,
+			"time":       MetadataDuration,
+			"date":       MetadataDate,
+			"difficulty": MetadataDifficulty,
+			"tags":       MetadataStringSlice,
+			"images":     MetadataStringSlice,
+		},
+		DateLayouts: append([]string(nil), DefaultDateLayouts...),
+	}
+}
+
+// Type returns the type of a metadata key; keys the schema does not know are strings.
+//
+// Parameters:
+//   - key: The metadata key
+//
+// Returns:
+//   - MetadataType: The type of the key's values
+func (s *MetadataSchema) Type(key string) MetadataType {
+	return s.Fields[key]
+}
+
+// ValidateValue checks that a value has the type the schema declares for its key.
+// Empty values are always valid.
+//
+// Parameters:
+//   - key: The metadata key
+//   - value: The value to check
+//
+// Returns:
+//   - error: An error describing the invalid value, or nil
+//
+// Example:
+//
+//	err := cooklang.DefaultMetadataSchema().ValidateValue("servings", "four")
+//	fmt.Println(err) // metadata "servings": "four" is not a valid number
+func (s *MetadataSchema) ValidateValue(key, value string) error {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	var err error
+	switch s.Type(key) {
+	case MetadataInt:
+		_, err = strconv.Atoi(value)
+	case MetadataNumber:
+		_, err = strconv.ParseFloat(value, 64)
+	case MetadataDuration:
+		_, err = parseMetadataDuration(value)
+	case MetadataDate:
+		_, err = s.ParseDate(value)
+	case MetadataDifficulty:
+		_, err = ParseDifficulty(value)
+	}
+	if err != nil {
+		return fmt.Errorf("metadata %q: %q is not a valid %s", key, value, s.Type(key))
+	}
+	return nil
+}
+
+// Validate checks every value in the metadata against the schema.
+//
+// Parameters:
+//   - metadata: The metadata to check
+//
+// Returns:
+//   - error: All invalid values, joined in key order; nil if every value is valid
+func (s *MetadataSchema) Validate(metadata Metadata) error {
+	keys := make([]string, 0, len(metadata))
+	for key := range metadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var errs []error
+	for _, key := range keys {
+		if err := s.ValidateValue(key, metadata[key]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ParseDate parses a date using the schema's date layouts, or DefaultDateLayouts if
+// it has none.
+//
+// Parameters:
+//   - value: The date text
+//
+// Returns:
+//   - time.Time: The date
+//   - error: An error if no layout matches
+func (s *MetadataSchema) ParseDate(value string) (time.Time, error) {
+	layouts := s.DateLayouts
+	if len(layouts) == 0 {
+		layouts = DefaultDateLayouts
+	}
+	return parseMetadataDate(value, layouts)
+}
+
+// GetString returns a metadata value with surrounding whitespace removed.
+//
+// Parameters:
+//   - key: The metadata key
+//
+// Returns:
+//   - string: The value
+//   - bool: true if the key is set to a non-empty value
+func (m Metadata) GetString(key string) (string, bool) {
+	value := strings.TrimSpace(m[key])
+	return value, value != ""
+}
+
+// GetInt returns a metadata value as a whole number.
+//
+// Parameters:
+//   - key: The metadata key
+//
+// Returns:
+//   - int: The value
+//   - error: ErrMetadataNotSet if the key is missing, or a parse error
+//
+// Example:
+//
+//	rating, err := recipe.Metadata.GetInt("rating")
+func (m Metadata) GetInt(key string) (int, error) {
+	value, ok := m.GetString(key)
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrMetadataNotSet, key)
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("metadata %q: %q is not an integer", key, value)
+	}
+	return n, nil
+}
+
+// GetFloat returns a metadata value as a number.
+//
+// Parameters:
+//   - key: The metadata key
+//
+// Returns:
+//   - float64: The value
+//   - error: ErrMetadataNotSet if the key is missing, or a parse error
+//
+// Example:
+//
+//	servings, err := recipe.Metadata.GetFloat("servings")
+func (m Metadata) GetFloat(key string) (float64, error) {
+	value, ok := m.GetString(key)
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrMetadataNotSet, key)
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("metadata %q: %q is not a number", key, value)
+	}
+	return f, nil
+}
+
+// GetDuration returns a metadata value such as "1 hour 30 minutes", "1h30m" or
+// "90 min" as a duration. A plain number is read as minutes.
+//
+// Parameters:
+//   - key: The metadata key
+//
+// Returns:
+//   - time.Duration: The duration
+//   - error: ErrMetadataNotSet if the key is missing, or a parse error
+//
+// Example:
+//
+//	prep, err := recipe.Metadata.GetDuration("prep_time") // "15 minutes" → 15m0s
+func (m Metadata) GetDuration(key string) (time.Duration, error) {
+	value, ok := m.GetString(key)
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrMetadataNotSet, key)
+	}
+	d, err := parseMetadataDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("metadata %q: %w", key, err)
+	}
+	return d, nil
+}
+
+// GetStringSlice returns a comma-separated metadata value (as YAML lists are stored)
+// as a list with surrounding whitespace and empty items removed.
+//
+// Parameters:
+//   - key: The metadata key
+//
+// Returns:
+//   - []string: The items; nil if the key is missing or empty
+//
+// Example:
+//
+//	tags := recipe.Metadata.GetStringSlice("tags") // "italian, pasta" → ["italian" "pasta"]
+func (m Metadata) GetStringSlice(key string) []string {
+	value, ok := m.GetString(key)
+	if !ok {
+		return nil
+	}
+	return splitAndTrim(value)
+}
+
+// GetDate returns a metadata value as a date, trying the given layouts in order, or
+// DefaultDateLayouts if none are given.
+//
+// Parameters:
+//   - key: The metadata key
+//   - layouts: Optional time layouts to try instead of DefaultDateLayouts
+//
+// Returns:
+//   - time.Time: The date
+//   - error: ErrMetadataNotSet if the key is missing, or a parse error
+//
+// Example:
+//
+//	date, err := recipe.Metadata.GetDate("date", "02.01.2006")
+func (m Metadata) GetDate(key string, layouts ...string) (time.Time, error) {
+	value, ok := m.GetString(key)
+	if !ok {
+		return time.Time{}, fmt.Errorf("%w: %s", ErrMetadataNotSet, key)
+	}
+	if len(layouts) == 0 {
+		layouts = DefaultDateLayouts
+	}
+	date, err := parseMetadataDate(value, layouts)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("metadata %q: %w", key, err)
+	}
+	return date, nil
+}
+
+// parseMetadataDuration parses a human-readable duration.
+func parseMetadataDuration(value string) (time.Duration, error) {
+	d, ok := parseHumanDuration(value)
+	if !ok {
+		return 0, fmt.Errorf("%q is not a duration", value)
+	}
+	return d, nil
+}
+
+// parseMetadataDate parses a date with the first layout that matches.
+func parseMetadataDate(value string, layouts []string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range layouts {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%q is not a date (expected e.g. %s)", value, layouts[0])
+}
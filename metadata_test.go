@@ -0,0 +1,138 @@
+package cooklang
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMetadataAccessors(t *testing.T) {
+	m := Metadata{
+		"servings":  " 2.5 ",
+		"rating":    "4",
+		"prep_time": "1 hour 30 minutes",
+		"cook_time": "45",
+		"tags":      "italian, , pasta ",
+		"date":      "January 15, 2024",
+		"made":      "15.01.2024",
+		"empty":     "  ",
+	}
+
+	if servings, err := m.GetFloat("servings"); err != nil || servings != 2.5 {
+		t.Errorf("GetFloat(servings) = %v, %v", servings, err)
+	}
+	if rating, err := m.GetInt("rating"); err != nil || rating != 4 {
+		t.Errorf("GetInt(rating) = %v, %v", rating, err)
+	}
+	if _, err := m.GetInt("servings"); err == nil {
+		t.Error("expected an error for a non-integer value")
+	}
+	if prep, err := m.GetDuration("prep_time"); err != nil || prep != 90*time.Minute {
+		t.Errorf("GetDuration(prep_time) = %v, %v", prep, err)
+	}
+	if cook, err := m.GetDuration("cook_time"); err != nil || cook != 45*time.Minute {
+		t.Errorf("GetDuration(cook_time) = %v, %v", cook, err)
+	}
+	if tags := m.GetStringSlice("tags"); !reflect.DeepEqual(tags, []string{"italian", "pasta"}) {
+		t.Errorf("GetStringSlice(tags) = %q", tags)
+	}
+	if date, err := m.GetDate("date"); err != nil || !date.Equal(time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("GetDate(date) = %v, %v", date, err)
+	}
+	if _, err := m.GetDate("made"); err == nil {
+		t.Error("expected an error for a date in an unknown layout")
+	}
+	if made, err := m.GetDate("made", "02.01.2006"); err != nil || made.Day() != 15 {
+		t.Errorf("GetDate(made) with custom layout = %v, %v", made, err)
+	}
+
+	for _, key := range []string{"missing", "empty"} {
+		if _, err := m.GetInt(key); !errors.Is(err, ErrMetadataNotSet) {
+			t.Errorf("GetInt(%s): expected ErrMetadataNotSet, got %v", key, err)
+		}
+		if m.GetStringSlice(key) != nil {
+			t.Errorf("GetStringSlice(%s): expected nil", key)
+		}
+	}
+}
+
+func TestMetadataSchemaValidate(t *testing.T) {
+	schema := DefaultMetadataSchema()
+	schema.Fields["rating"] = MetadataInt
+
+	err := schema.Validate(Metadata{
+		"servings":   "four",
+		"prep_time":  "quick",
+		"date":       "yesterday",
+		"rating":     "4.5",
+		"difficulty": "tricky",
+		"tags":       "anything, goes",
+		"source":     "Grandma",
+		"cook_time":  "",
+	})
+	if err == nil {
+		t.Fatal("expected validation errors")
+	}
+	expected := []string{
+		`metadata "date": "yesterday" is not a valid date`,
+		`metadata "difficulty": "tricky" is not a valid difficulty`,
+		`metadata "prep_time": "quick" is not a valid duration`,
+		`metadata "rating": "4.5" is not a valid integer`,
+		`metadata "servings": "four" is not a valid number`,
+	}
+	if got := strings.Split(err.Error(), "\n"); !reflect.DeepEqual(got, expected) {
+		t.Errorf("unexpected errors:\n%s", err)
+	}
+
+	if err := DefaultMetadataSchema().Validate(Metadata{"servings": "4", "date": "2024-01-15", "total_time": "1h"}); err != nil {
+		t.Errorf("expected valid metadata, got %v", err)
+	}
+}
+
+func TestFrontmatterEditorSchema(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "Toast.cook")
+	if err := os.WriteFile(path, []byte("---\ntitle: Toast\n---\nToast @bread{2%slices}.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	editor, err := NewFrontmatterEditor(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := editor.SetMetadata("prep_time", "soon"); err == nil {
+		t.Error("expected an error for an invalid duration")
+	}
+	if err := editor.SetMetadata("date", "15.01.2024"); err == nil {
+		t.Error("expected an error for a date in an unknown layout")
+	}
+
+	schema := DefaultMetadataSchema()
+	schema.DateLayouts = []string{"02.01.2006"}
+	editor.SetSchema(schema)
+	if err := editor.SetMetadata("date", "15.01.2024"); err != nil {
+		t.Fatalf("SetMetadata with custom date layout: %v", err)
+	}
+	if date, _ := editor.GetMetadata("date"); date != "2024-01-15" {
+		t.Errorf("expected date 2024-01-15, got %q", date)
+	}
+}
+
+func TestParseWithTypedMetadata(t *testing.T) {
+	recipe, err := ParseString("---\nservings: 3\ndate: March 5, 2025\ntags: quick,, breakfast\n---\nToast @bread{2%slices}.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if recipe.Servings != 3 {
+		t.Errorf("expected 3 servings, got %v", recipe.Servings)
+	}
+	if recipe.Date.Format("2006-01-02") != "2025-03-05" {
+		t.Errorf("expected date 2025-03-05, got %v", recipe.Date)
+	}
+	if !reflect.DeepEqual(recipe.Tags, []string{"quick", "breakfast"}) {
+		t.Errorf("unexpected tags %q", recipe.Tags)
+	}
+}
@@ -0,0 +1,180 @@
+// Package naming derives URL slugs and file names from recipes, and renames recipe
+// files together with their images.
+//
+// Example:
+//
+//	naming.Slugify("Crème Brûlée (v2)") // "creme-brulee-v2"
+//	naming.SuggestFilename(recipe)      // "Crème Brûlée.cook"
+package naming
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/hilli/cooklang"
+)
+
+// foldedLetters spells accented and special Latin letters in ASCII for Slugify.
+var foldedLetters = map[rune]string{
+	'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'ä': "a", 'å': "a", 'ā': "a", 'ą': "a",
+	'æ': "ae", 'ç': "c", 'ć': "c", 'č': "c", 'ď': "d", 'đ': "d", 'ð': "d",
+	'è': "e", 'é': "e", 'ê': "e", 'ë': "e", 'ē': "e", 'ę': "e", 'ě': "e", 'ğ': "g",
+	'ì': "i", 'í': "i", 'î': "i", 'ï': "i", 'ı': "i", 'ł': "l", 'ñ': "n", 'ń': "n", 'ň': "n",
+	'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ö': "o", 'ø': "o", 'ō': "o", 'œ': "oe",
+	'ř': "r", 'ß': "ss", 'ś': "s", 'š': "s", 'ş': "s", 'ť': "t", 'þ': "th",
+	'ù': "u", 'ú': "u", 'û': "u", 'ü': "u", 'ů': "u", 'ū': "u", 'ý': "y", 'ÿ': "y",
+	'ź': "z", 'ż': "z", 'ž': "z",
+}
+
+// Slugify turns a title into a lowercase, dash-separated slug for URLs and file
+// names. Accented Latin letters are spelled in ASCII ("é" becomes "e", "ß" becomes
+// "ss"), other letters and digits are kept, and runs of anything else become a
+// single "-".
+//
+// Parameters:
+//   - title: The text to slugify
+//
+// Returns:
+//   - string: The slug, e.g. "gin-tonic" for "Gin & Tonic"; empty if title has no
+//     letters or digits
+//
+// Example:
+//
+//	slug := naming.Slugify("Crème Brûlée (v2)") // "creme-brulee-v2"
+func Slugify(title string) string {
+	var slug strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(title) {
+		folded, ok := foldedLetters[r]
+		if !ok {
+			if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+				dash = true
+				continue
+			}
+			folded = string(r)
+		}
+		if dash && slug.Len() > 0 {
+			slug.WriteByte('-')
+		}
+		slug.WriteString(folded)
+		dash = false
+	}
+	return slug.String()
+}
+
+// SuggestFilename returns the file name a recipe would be found under by its
+// title: the title with the characters file systems do not allow in names
+// (<>:"/\|?* and control characters) replaced by spaces, plus ".cook". Inferring
+// the title from the file name gives the title back.
+//
+// Parameters:
+//   - recipe: The recipe
+//
+// Returns:
+//   - string: The file name, e.g. "Neapolitan Pizza.cook"; empty if the recipe has
+//     no title
+func SuggestFilename(recipe *cooklang.Recipe) string {
+	if recipe == nil {
+		return ""
+	}
+	name := strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`<>:"/\|?*`, r) || unicode.IsControl(r) {
+			return ' '
+		}
+		return r
+	}, recipe.Title)
+	name = strings.Trim(strings.Join(strings.Fields(name), " "), ". ")
+	if name == "" {
+		return ""
+	}
+	return name + ".cook"
+}
+
+// Rename is a file moved by RenameRecipe.
+type Rename struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// RenameRecipe renames a recipe file within its directory, together with the
+// images ParseFile detects by its name ("Pizza.jpg", "Pizza-1.png", "Pizza.0.jpg"
+// for "Pizza.cook"), so they are still found under the new name. Nothing is
+// renamed if a new name is taken by another file.
+//
+// Parameters:
+//   - filename: The recipe file
+//   - name: The new file name, e.g. "Neapolitan Pizza.cook"
+//   - dryRun: Only return the renames, without renaming
+//
+// Returns:
+//   - []Rename: The renames, images first and the recipe last; none if the recipe
+//     already has the name
+//   - error: An error if the recipe cannot be read, a new name is taken, or a file
+//     cannot be renamed; the renames done before are returned with it
+//
+// Example:
+//
+//	recipe, _ := cooklang.ParseFile("pizza.cook")
+//	renames, err := naming.RenameRecipe("pizza.cook", naming.SuggestFilename(recipe), false)
+func RenameRecipe(filename, name string, dryRun bool) ([]Rename, error) {
+	if filepath.Base(name) != name || !strings.HasSuffix(name, ".cook") {
+		return nil, fmt.Errorf("invalid recipe file name %q", name)
+	}
+	dir := filepath.Dir(filename)
+	target := filepath.Join(dir, name)
+	if filepath.Base(filename) == name {
+		return nil, nil
+	}
+
+	recipe, err := cooklang.ParseFile(filename, cooklang.WithoutImageDetection())
+	if err != nil {
+		return nil, err
+	}
+	oldBase := strings.TrimSuffix(filepath.Base(filename), ".cook")
+	newBase := strings.TrimSuffix(name, ".cook")
+
+	// Detect only the images found by the recipe's name, not those it lists
+	detected := &cooklang.Recipe{FirstStep: recipe.FirstStep}
+	detected.AddDetectedImages(filename)
+	images := detected.Images
+	for step := range detected.Steps() {
+		images = append(images, step.Images...)
+	}
+
+	var renames []Rename
+	for _, image := range images {
+		if rest, ok := strings.CutPrefix(image, oldBase); ok {
+			renames = append(renames, Rename{From: filepath.Join(dir, filepath.FromSlash(image)), To: filepath.Join(dir, filepath.FromSlash(newBase+rest))})
+		}
+	}
+	renames = append(renames, Rename{From: filename, To: target})
+
+	for _, rename := range renames {
+		if taken(rename) {
+			return nil, fmt.Errorf("cannot rename %s: %s already exists", rename.From, rename.To)
+		}
+	}
+	if dryRun {
+		return renames, nil
+	}
+	for i, rename := range renames {
+		if err := os.Rename(rename.From, rename.To); err != nil {
+			return renames[:i], err
+		}
+	}
+	return renames, nil
+}
+
+// taken reports whether the rename's target is another file than its source. On
+// case-insensitive file systems, "pizza.cook" and "Pizza.cook" are the same file.
+func taken(rename Rename) bool {
+	to, err := os.Stat(rename.To)
+	if err != nil {
+		return false
+	}
+	from, err := os.Stat(rename.From)
+	return err != nil || !os.SameFile(from, to)
+}
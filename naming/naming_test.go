@@ -0,0 +1,116 @@
+package naming
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hilli/cooklang"
+)
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		title    string
+		expected string
+	}{
+		{"Pancakes", "pancakes"},
+		{"Gin & Tonic (v2)", "gin-tonic-v2"},
+		{"Crème Brûlée", "creme-brulee"},
+		{"Smørrebrød", "smorrebrod"},
+		{"Æbleskiver", "aebleskiver"},
+		{"Käsespätzle mit Weißbier", "kasespatzle-mit-weissbier"},
+		{"  -- Borscht / Борщ --  ", "borscht-борщ"},
+		{"!!!", ""},
+	}
+	for _, tt := range tests {
+		if got := Slugify(tt.title); got != tt.expected {
+			t.Errorf("Slugify(%q) = %q, want %q", tt.title, got, tt.expected)
+		}
+	}
+}
+
+func TestSuggestFilename(t *testing.T) {
+	tests := []struct {
+		title    string
+		expected string
+	}{
+		{"Neapolitan Pizza", "Neapolitan Pizza.cook"},
+		{"Crème Brûlée", "Crème Brûlée.cook"},
+		{"Salt/Pepper: the \"Basics\"?", "Salt Pepper the Basics.cook"},
+		{"Mom's pie...", "Mom's pie.cook"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := SuggestFilename(&cooklang.Recipe{Title: tt.title}); got != tt.expected {
+			t.Errorf("SuggestFilename(%q) = %q, want %q", tt.title, got, tt.expected)
+		}
+	}
+	if got := SuggestFilename(nil); got != "" {
+		t.Errorf("SuggestFilename(nil) = %q, want empty", got)
+	}
+}
+
+func TestRenameRecipe(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"pizza.cook":   "---\nimages: oven.jpg\n---\nKnead @flour{500%g}.\n\nBake.\n",
+		"pizza.jpg":    "jpeg",
+		"pizza-1.png":  "png",
+		"pizza.1.jpg":  "jpeg",
+		"oven.jpg":     "listed in the metadata",
+		"pizzas.jpg":   "another recipe's image",
+		"Taken.cook":   "Another recipe.\n",
+		"Taken.0.jpg":  "jpeg",
+		"pizza.0.jpeg": "jpeg",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	source := filepath.Join(dir, "pizza.cook")
+
+	if _, err := RenameRecipe(source, "Taken.cook", false); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("expected an error for a taken name, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "pizza.jpg")); err != nil {
+		t.Error("expected nothing to be renamed when a name is taken")
+	}
+
+	renames, err := RenameRecipe(source, "Neapolitan Pizza.cook", true)
+	if err != nil {
+		t.Fatalf("dry run failed: %v", err)
+	}
+	if len(renames) != 5 || renames[4].To != filepath.Join(dir, "Neapolitan Pizza.cook") {
+		t.Errorf("renames = %+v, want the 4 detected images and the recipe last", renames)
+	}
+	if _, err := os.Stat(source); err != nil {
+		t.Error("dry run renamed the recipe")
+	}
+
+	if _, err := RenameRecipe(source, "Neapolitan Pizza.cook", false); err != nil {
+		t.Fatalf("RenameRecipe failed: %v", err)
+	}
+	recipe, err := cooklang.ParseFile(filepath.Join(dir, "Neapolitan Pizza.cook"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(recipe.Images, ","); got != "oven.jpg,Neapolitan Pizza.jpg,Neapolitan Pizza-1.png" {
+		t.Errorf("images = %s", got)
+	}
+	if got := strings.Join(recipe.FirstStep.NextStep.Images, ","); got != "Neapolitan Pizza.1.jpg" {
+		t.Errorf("step images = %s", got)
+	}
+	for _, name := range []string{"oven.jpg", "pizzas.jpg"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("expected %s to keep its name", name)
+		}
+	}
+
+	if renames, err := RenameRecipe(filepath.Join(dir, "Neapolitan Pizza.cook"), "Neapolitan Pizza.cook", false); err != nil || renames != nil {
+		t.Errorf("expected no renames for the same name, got %+v, %v", renames, err)
+	}
+	if _, err := RenameRecipe(filepath.Join(dir, "Taken.cook"), "../Taken.cook", false); err == nil {
+		t.Error("expected an error for a name with a directory")
+	}
+}
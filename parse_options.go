@@ -0,0 +1,239 @@
+package cooklang
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/hilli/cooklang/parser"
+)
+
+// ErrRecipeTooLarge is returned when a recipe is larger than the limit set with
+// WithMaxSize.
+var ErrRecipeTooLarge = errors.New("recipe too large")
+
+// ErrLimitExceeded is returned, wrapped in a *parser.LimitError, when a recipe
+// exceeds a limit set with WithLimits.
+var ErrLimitExceeded = parser.ErrLimitExceeded
+
+// ParseOption configures ParseFile, ParseBytes and ParseString.
+//
+// Example:
+//
+//	recipe, err := cooklang.ParseFile("Pancakes.cook",
+//	    cooklang.WithExtendedMode(),
+//	    cooklang.WithoutImageDetection(),
+//	    cooklang.WithMaxSize(1<<20),
+//	)
+type ParseOption func(*parseConfig)
+
+// parseConfig holds the settings collected from ParseOptions.
+type parseConfig struct {
+	extended bool
+	file     ParseFileOptions
+	units    CustomUnits
+	logger   *slog.Logger
+	maxSize  int64
+	limits   parser.Limits
+	defaults Metadata
+	// directoryDefaults makes ParseFile read the DefaultsFile next to the recipe.
+	directoryDefaults bool
+}
+
+// newParseConfig returns the default settings with the options applied.
+func newParseConfig(opts []ParseOption) *parseConfig {
+	config := &parseConfig{file: DefaultParseFileOptions()}
+	for _, opt := range opts {
+		opt(config)
+	}
+	return config
+}
+
+// WithExtendedMode enables the extended syntax beyond the canonical Cooklang
+// specification, as the cook CLI uses by default: ingredient and cookware
+// annotations, recipe references and more.
+//
+// Returns:
+//   - ParseOption: The option
+func WithExtendedMode() ParseOption {
+	return func(c *parseConfig) { c.extended = true }
+}
+
+// WithCanonicalMode parses strictly by the canonical Cooklang specification. This is
+// the default; it undoes an earlier WithExtendedMode.
+//
+// Returns:
+//   - ParseOption: The option
+func WithCanonicalMode() ParseOption {
+	return func(c *parseConfig) { c.extended = false }
+}
+
+// WithoutImageDetection stops ParseFile from looking for images next to the recipe
+// file. ParseBytes and ParseString never detect images.
+//
+// Returns:
+//   - ParseOption: The option
+func WithoutImageDetection() ParseOption {
+	return func(c *parseConfig) { c.file.DetectImages = false }
+}
+
+// WithUnitRegistry registers custom units on the parsed recipe (see
+// Recipe.SetCustomUnits), so conversions and shopping lists understand units such as
+// "scoop" without declaring them in every recipe. Units declared in the recipe's
+// frontmatter take precedence.
+//
+// Parameters:
+//   - units: The custom units, e.g. from ParseCustomUnits
+//
+// Returns:
+//   - ParseOption: The option
+func WithUnitRegistry(units CustomUnits) ParseOption {
+	return func(c *parseConfig) { c.units = units }
+}
+
+// WithLogger logs what the parser does (the input size, mode, and detected images)
+// at debug level, and rejected input at warn level.
+//
+// Parameters:
+//   - logger: The logger; nil disables logging
+//
+// Returns:
+//   - ParseOption: The option
+func WithLogger(logger *slog.Logger) ParseOption {
+	return func(c *parseConfig) { c.logger = logger }
+}
+
+// WithMaxSize rejects recipes larger than maxBytes with ErrRecipeTooLarge before
+// they are parsed; ParseFile checks the file size before reading it. Zero or a
+// negative size means no limit.
+//
+// Parameters:
+//   - maxBytes: The largest accepted recipe, in bytes
+//
+// Returns:
+//   - ParseOption: The option
+func WithMaxSize(maxBytes int64) ParseOption {
+	return func(c *parseConfig) { c.maxSize = maxBytes }
+}
+
+// WithLimits caps the input size, steps, components per step and frontmatter
+// nesting of parsed recipes, so untrusted uploads cannot use unbounded memory.
+// Recipes over a limit are rejected with an error matching ErrLimitExceeded, which
+// errors.As turns into a *parser.LimitError naming the limit; ParseFile checks
+// Limits.MaxBytes against the file size before reading it.
+//
+// Parameters:
+//   - limits: The limits; zero fields mean no limit
+//
+// Returns:
+//   - ParseOption: The option
+//
+// Example:
+//
+//	recipe, err := cooklang.ParseBytes(upload, cooklang.WithLimits(parser.Limits{
+//	    MaxBytes:             1 << 20,
+//	    MaxSteps:             500,
+//	    MaxComponentsPerStep: 200,
+//	    MaxNesting:           8,
+//	}))
+//	if errors.Is(err, cooklang.ErrLimitExceeded) {
+//	    // reject the upload
+//	}
+func WithLimits(limits parser.Limits) ParseOption {
+	return func(c *parseConfig) { c.limits = limits }
+}
+
+// WithDefaults adds metadata to the parsed recipe for the keys it does not set
+// itself, such as an author shared by a whole collection. The recipe's own values
+// win.
+//
+// Parameters:
+//   - defaults: The default metadata
+//
+// Returns:
+//   - ParseOption: The option
+func WithDefaults(defaults Metadata) ParseOption {
+	return func(c *parseConfig) { c.defaults = defaults }
+}
+
+// WithDirectoryDefaults makes ParseFile read the DefaultsFile (_defaults.yml) in
+// the recipe's directory, if there is one, and add its metadata to the recipe like
+// WithDefaults. ParseBytes and ParseString have no directory and ignore it.
+//
+// Returns:
+//   - ParseOption: The option
+//
+// Example:
+//
+//	// recipes/grandma/_defaults.yml sets "author: Grandma Rose"
+//	recipe, _ := cooklang.ParseFile("recipes/grandma/Kringle.cook", cooklang.WithDirectoryDefaults())
+//	fmt.Println(recipe.Author) // Grandma Rose
+func WithDirectoryDefaults() ParseOption {
+	return func(c *parseConfig) { c.directoryDefaults = true }
+}
+
+// checkSize returns ErrRecipeTooLarge if size exceeds the configured limit.
+func (c *parseConfig) checkSize(name string, size int64) error {
+	if c.maxSize <= 0 || size <= c.maxSize {
+		return nil
+	}
+	c.warn("recipe too large", "source", name, "bytes", size, "max_bytes", c.maxSize)
+	return fmt.Errorf("%w: %s is %d bytes (limit %d)", ErrRecipeTooLarge, name, size, c.maxSize)
+}
+
+// parse parses content into a Recipe with the configured mode and units.
+func (c *parseConfig) parse(name, content string) (*Recipe, error) {
+	if err := c.checkSize(name, int64(len(content))); err != nil {
+		return nil, err
+	}
+	p := parser.New()
+	p.ExtendedMode = c.extended
+	p.Limits = c.limits
+	parsedRecipe, err := p.ParseString(content)
+	if err != nil {
+		if errors.Is(err, ErrLimitExceeded) {
+			c.warn("recipe exceeds a parser limit", "source", name, "error", err)
+		}
+		return nil, err
+	}
+	parsedRecipe.Metadata = mergeDefaults(parsedRecipe.Metadata, c.defaults)
+	recipe := ToCooklangRecipe(parsedRecipe)
+	if c.units != nil {
+		recipe.SetCustomUnits(c.units)
+	}
+	c.debug("parsed recipe", "source", name, "bytes", len(content), "extended", c.extended)
+	return recipe, nil
+}
+
+// debug logs a debug message if a logger is set.
+func (c *parseConfig) debug(msg string, args ...any) {
+	if c.logger != nil {
+		c.logger.Debug(msg, args...)
+	}
+}
+
+// warn logs a warning if a logger is set.
+func (c *parseConfig) warn(msg string, args ...any) {
+	if c.logger != nil {
+		c.logger.Warn(msg, args...)
+	}
+}
+
+// readFile reads a recipe file, checking its size first.
+func (c *parseConfig) readFile(filename string) ([]byte, error) {
+	if c.maxSize > 0 || c.limits.MaxBytes > 0 {
+		info, err := os.Stat(filename)
+		if err != nil {
+			return nil, err
+		}
+		if err := c.checkSize(filename, info.Size()); err != nil {
+			return nil, err
+		}
+		if c.limits.MaxBytes > 0 && info.Size() > int64(c.limits.MaxBytes) {
+			c.warn("recipe exceeds a parser limit", "source", filename, "bytes", info.Size(), "max_bytes", c.limits.MaxBytes)
+			return nil, &parser.LimitError{Limit: "bytes", Max: c.limits.MaxBytes}
+		}
+	}
+	return os.ReadFile(filename)
+}
@@ -0,0 +1,107 @@
+package cooklang
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hilli/cooklang/parser"
+)
+
+func hasComment(recipe *Recipe) bool {
+	for step := recipe.FirstStep; step != nil; step = step.NextStep {
+		for component := step.FirstComponent; component != nil; component = component.GetNext() {
+			if _, ok := component.(*Comment); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func TestParseOptionsMode(t *testing.T) {
+	source := "Boil @water{1%L}. -- until it bubbles\n"
+
+	canonical, err := ParseString(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	extended, err := ParseString(source, WithExtendedMode())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hasComment(canonical) || !hasComment(extended) {
+		t.Errorf("expected comments only in extended mode (canonical %v, extended %v)", hasComment(canonical), hasComment(extended))
+	}
+
+	again, err := ParseBytes([]byte(source), WithExtendedMode(), WithCanonicalMode())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hasComment(again) {
+		t.Error("WithCanonicalMode should undo WithExtendedMode")
+	}
+}
+
+func TestParseOptionsFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "Affogato.cook")
+	if err := os.WriteFile(path, []byte("Pour @espresso{1%shot} over @ice cream{2%scoop}.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "Affogato.jpg"), []byte("img"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	units := CustomUnits{"scoop": {Amount: 60, Unit: "g"}}
+
+	recipe, err := ParseFile(path, WithoutImageDetection(), WithUnitRegistry(units), WithLogger(logger))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recipe.Images) != 0 {
+		t.Errorf("expected no images, got %v", recipe.Images)
+	}
+	if _, ok := recipe.CustomUnits["scoop"]; !ok {
+		t.Errorf("expected the scoop unit to be registered, got %v", recipe.CustomUnits)
+	}
+	if !strings.Contains(logs.String(), "parsed recipe") || strings.Contains(logs.String(), "detected images") {
+		t.Errorf("unexpected log output:\n%s", logs.String())
+	}
+
+	if recipe, err := ParseFile(path); err != nil || len(recipe.Images) != 1 {
+		t.Errorf("expected image detection by default, got %v, %v", recipe, err)
+	}
+
+	if _, err := ParseFile(path, WithMaxSize(10)); !errors.Is(err, ErrRecipeTooLarge) {
+		t.Errorf("expected ErrRecipeTooLarge, got %v", err)
+	}
+	if _, err := ParseString("Toast.", WithMaxSize(10)); err != nil {
+		t.Errorf("expected a small recipe to be accepted, got %v", err)
+	}
+}
+
+func TestWithLimits(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "Toast.cook")
+	if err := os.WriteFile(path, []byte("Toast @bread{2%slices}.\n\nButter it.\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var limitErr *parser.LimitError
+	if _, err := ParseFile(path, WithLimits(parser.Limits{MaxBytes: 10})); !errors.Is(err, ErrLimitExceeded) || !errors.As(err, &limitErr) || limitErr.Limit != "bytes" {
+		t.Errorf("expected the file to exceed the byte limit, got %v", err)
+	}
+	if _, err := ParseFile(path, WithLimits(parser.Limits{MaxSteps: 1})); !errors.As(err, &limitErr) || limitErr.Limit != "steps" {
+		t.Errorf("expected the file to exceed the step limit, got %v", err)
+	}
+	if _, err := ParseFile(path, WithLimits(parser.Limits{MaxBytes: 100, MaxSteps: 2, MaxComponentsPerStep: 3})); err != nil {
+		t.Errorf("expected the recipe to be accepted, got %v", err)
+	}
+}
@@ -0,0 +1,99 @@
+package parser
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const benchSmallRecipe = `Toast @bread{2%slices} for ~{3%minutes}.`
+
+const benchMediumRecipe = `---
+title: Pancakes
+servings: 4
+tags: [breakfast, sweet]
+prep_time: 10 minutes
+---
+>> source: Grandma
+
+== Batter ==
+
+Whisk @flour{250%g}, @sugar{1½%tbsp} and a pinch of @salt{} in a #large bowl{}.
+
+Add @eggs{2}, @milk{500%ml} and @butter{50%g}(melted) and whisk until smooth. -- no lumps
+
+> Let the batter rest if you have time.
+
+== Frying ==
+
+Heat a #frying pan{} over medium heat for ~{2%minutes}.
+
+Pour in @@./Whipped Cream{200%ml} [- optional -] and fry for ~cook{1-2%minutes} on each side.
+`
+
+// benchLargeRecipe is the medium recipe's steps repeated to about 40 KB.
+var benchLargeRecipe = benchMediumRecipe + strings.Repeat(benchMediumRecipe[strings.Index(benchMediumRecipe, "== Batter"):], 60)
+
+func benchmarkParse(b *testing.B, input string) {
+	p := New()
+	p.ExtendedMode = true
+	b.SetBytes(int64(len(input)))
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := p.ParseString(input); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParseSmall(b *testing.B)  { benchmarkParse(b, benchSmallRecipe) }
+func BenchmarkParseMedium(b *testing.B) { benchmarkParse(b, benchMediumRecipe) }
+func BenchmarkParseLarge(b *testing.B)  { benchmarkParse(b, benchLargeRecipe) }
+
+// BenchmarkParseParallel parses with one shared parser from all goroutines.
+func BenchmarkParseParallel(b *testing.B) {
+	p := New()
+	p.ExtendedMode = true
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := p.ParseString(benchMediumRecipe); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func TestParseConcurrent(t *testing.T) {
+	p := New()
+	p.ExtendedMode = true
+	expected, err := p.ParseString(benchMediumRecipe)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	inputs := []string{benchSmallRecipe, benchMediumRecipe}
+	errs := make(chan error, 16)
+	for i := range 16 {
+		go func() {
+			for j := range 50 {
+				input := inputs[(i+j)%2]
+				recipe, err := p.ParseString(input)
+				if err == nil && input == benchMediumRecipe && !reflect.DeepEqual(recipe, expected) {
+					err = fmt.Errorf("goroutine %d: concurrent parse differs from sequential parse", i)
+				}
+				if err != nil {
+					errs <- err
+					return
+				}
+			}
+			errs <- nil
+		}()
+	}
+	for range 16 {
+		if err := <-errs; err != nil {
+			t.Error(err)
+		}
+	}
+}
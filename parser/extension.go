@@ -0,0 +1,112 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ErrInvalidExtension is returned, wrapped, by RegisterExtension for a prefix that
+// cannot start a custom component.
+var ErrInvalidExtension = errors.New("invalid extension")
+
+// ExtensionHandler turns a custom component into a Component. It is given the name
+// between the prefix and the opening brace and the content of the braces: for
+// "&wine pairing{Riesling}" they are "wine pairing" and "Riesling". The parser sets
+// the component's Position and Source.
+//
+// A handler can return any component: one of the built-in types ("text", "comment",
+// "ingredient", ...) is treated like the same syntax written out, and any other Type
+// is kept as an extension component (cooklang.Extension). A handler error stops the
+// parse.
+type ExtensionHandler func(name, content string) (Component, error)
+
+// reservedExtensionPrefixes are the characters Cooklang syntax already uses, which
+// cannot start a custom component.
+const reservedExtensionPrefixes = "@#~=>-[]{}()%/.,;:\\\"'`|"
+
+var (
+	extensionsMu sync.RWMutex
+	extensions   = map[rune]ExtensionHandler{}
+)
+
+// RegisterExtension registers a handler for custom components that start with
+// prefix, such as "&wine pairing{Riesling}" for '&', so downstream tools can extend
+// the syntax without forking the lexer. A custom component is the prefix directly
+// followed by a name and a pair of braces on the same line; anything else starting
+// with the prefix stays text, as does the prefix in the middle of a word.
+//
+// Extensions apply to every parser. Registering a prefix again replaces its handler;
+// letters, digits, spaces and characters Cooklang syntax uses cannot be prefixes.
+//
+// Parameters:
+//   - prefix: The character that starts the custom component
+//   - handler: Turns the custom component into a Component
+//
+// Returns:
+//   - error: ErrInvalidExtension, wrapped, if prefix cannot be used or handler is nil
+//
+// Example:
+//
+//	err := parser.RegisterExtension('&', func(name, content string) (parser.Component, error) {
+//	    return parser.Component{Type: "pairing", Name: name, Value: content}, nil
+//	})
+//	recipe, _ := parser.New().ParseString("Serve with &wine pairing{Riesling}.")
+//	// recipe.Steps[0].Components[1]: {Type: "pairing", Name: "wine pairing", Value: "Riesling"}
+func RegisterExtension(prefix rune, handler ExtensionHandler) error {
+	switch {
+	case handler == nil:
+		return fmt.Errorf("%w: no handler for %q", ErrInvalidExtension, prefix)
+	case unicode.IsLetter(prefix), unicode.IsNumber(prefix), unicode.IsSpace(prefix),
+		!unicode.IsGraphic(prefix), strings.ContainsRune(reservedExtensionPrefixes, prefix):
+		return fmt.Errorf("%w: %q cannot start a custom component", ErrInvalidExtension, prefix)
+	}
+	extensionsMu.Lock()
+	defer extensionsMu.Unlock()
+	extensions[prefix] = handler
+	return nil
+}
+
+// UnregisterExtension removes the handler for prefix, so it is text again.
+func UnregisterExtension(prefix rune) {
+	extensionsMu.Lock()
+	defer extensionsMu.Unlock()
+	delete(extensions, prefix)
+}
+
+// registeredExtensions returns a copy of the registered handlers, so a parse is not
+// affected by registrations made while it runs.
+func registeredExtensions() map[rune]ExtensionHandler {
+	extensionsMu.RLock()
+	defer extensionsMu.RUnlock()
+	if len(extensions) == 0 {
+		return nil
+	}
+	handlers := make(map[rune]ExtensionHandler, len(extensions))
+	for prefix, handler := range extensions {
+		handlers[prefix] = handler
+	}
+	return handlers
+}
+
+// parseExtension calls the handler for a custom component read by the lexer, such
+// as "&wine pairing{Riesling}".
+func parseExtension(handlers map[rune]ExtensionHandler, source string) (Component, error) {
+	prefix, size := utf8.DecodeRuneInString(source)
+	rest := source[size:]
+	open := strings.IndexByte(rest, '{')
+	name, content := rest[:open], strings.TrimSuffix(rest[open+1:], "}")
+
+	component, err := handlers[prefix](strings.TrimSpace(name), strings.TrimSpace(content))
+	if err != nil {
+		return component, fmt.Errorf("%c%s: %w", prefix, name, err)
+	}
+	if component.Type == "" {
+		component.Type = "extension"
+	}
+	component.Source = source
+	return component, nil
+}
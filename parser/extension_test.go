@@ -0,0 +1,95 @@
+package parser
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRegisterExtension(t *testing.T) {
+	pairing := func(name, content string) (Component, error) {
+		return Component{Type: "pairing", Name: name, Value: content}, nil
+	}
+	if err := RegisterExtension('&', pairing); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	t.Cleanup(func() { UnregisterExtension('&') })
+
+	recipe, err := New().ParseString("Serve with &wine pairing{ Riesling } & bread.")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	components := recipe.Steps[0].Components
+	if len(components) != 3 {
+		t.Fatalf("Expected 3 components, got %+v", components)
+	}
+	got := components[1]
+	want := Component{Type: "pairing", Name: "wine pairing", Value: "Riesling", Source: "&wine pairing{ Riesling }"}
+	if got.Position.Offset != 11 || got.Position.EndOffset != 36 {
+		t.Errorf("Expected the component at 11-36, got %+v", got.Position)
+	}
+	got.Position = want.Position
+	if got != want {
+		t.Errorf("Expected %+v, got %+v", want, got)
+	}
+	if components[2].Value != " & bread." {
+		t.Errorf("Expected the prefix without braces to stay text, got %q", components[2].Value)
+	}
+
+	UnregisterExtension('&')
+	recipe, err = New().ParseString("Serve with &wine pairing{Riesling}.")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if components := recipe.Steps[0].Components; len(components) != 1 || components[0].Value != "Serve with &wine pairing{Riesling}." {
+		t.Errorf("Expected text after UnregisterExtension, got %+v", components)
+	}
+}
+
+func TestRegisterExtensionBuiltinTypes(t *testing.T) {
+	// A handler may turn a custom component into built-in syntax, e.g. a comment
+	err := RegisterExtension('$', func(name, content string) (Component, error) {
+		if name != "nutrition" {
+			return Component{}, errors.New("unknown block")
+		}
+		return Component{Type: "comment", Value: "nutrition: " + content}, nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	t.Cleanup(func() { UnregisterExtension('$') })
+
+	p := New()
+	p.ExtendedMode = true
+	recipe, err := p.ParseString("Add @salt{1%tsp} $nutrition{sodium 2300mg} and stir.")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var types []string
+	for _, component := range recipe.Steps[0].Components {
+		types = append(types, component.Type)
+	}
+	if strings.Join(types, ",") != "text,ingredient,text,comment,text" {
+		t.Errorf("Unexpected components: %v", types)
+	}
+	if comment := recipe.Steps[0].Components[3]; comment.Value != "nutrition: sodium 2300mg" {
+		t.Errorf("Unexpected comment: %+v", comment)
+	}
+
+	_, err = p.ParseString("Stir.\n$wine{red}")
+	if err == nil || !strings.Contains(err.Error(), "$wine: unknown block") {
+		t.Errorf("Expected the handler error, got %v", err)
+	}
+}
+
+func TestRegisterExtensionInvalid(t *testing.T) {
+	handler := func(name, content string) (Component, error) { return Component{}, nil }
+	for _, prefix := range []rune{'@', '#', '~', '{', '-', 'a', '7', ' ', '\n'} {
+		if err := RegisterExtension(prefix, handler); !errors.Is(err, ErrInvalidExtension) {
+			t.Errorf("%q: expected ErrInvalidExtension, got %v", prefix, err)
+		}
+	}
+	if err := RegisterExtension('&', nil); !errors.Is(err, ErrInvalidExtension) {
+		t.Errorf("nil handler: expected ErrInvalidExtension, got %v", err)
+	}
+}
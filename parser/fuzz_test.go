@@ -0,0 +1,40 @@
+package parser
+
+import (
+	"testing"
+)
+
+// fuzzSeeds are recipes covering the syntax, added to the corpus in testdata/fuzz.
+var fuzzSeeds = []string{
+	benchSmallRecipe,
+	benchMediumRecipe,
+	"Roast for ~roast time{4%hours}(covered) and ~rest a while.",
+	"Add @?salt{=1%pinch} and @eggs{~2} to the #pot{2}(large).",
+	"@salt and pepper\n\n-- comment\n[- block -]\n\n> note",
+	"---\ntitle: X\n---\n== Section ==\n>> servings: 2\n",
+	"@{}#{}~{}@@{}",
+	"~{",
+	"@flour{1/2%cup",
+}
+
+func FuzzParseString(f *testing.F) {
+	for _, seed := range fuzzSeeds {
+		f.Add(seed, false)
+		f.Add(seed, true)
+	}
+	f.Fuzz(func(t *testing.T, input string, extended bool) {
+		p := New()
+		p.ExtendedMode = extended
+		recipe, err := p.ParseString(input)
+		if err != nil {
+			return
+		}
+		for _, step := range recipe.Steps {
+			for _, component := range step.Components {
+				if component.Position.Offset < 0 || component.Position.EndOffset > len(input) || component.Position.Offset > component.Position.EndOffset {
+					t.Fatalf("component %+v has position %+v outside the input (%d bytes)", component, component.Position, len(input))
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,87 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrLimitExceeded is returned, wrapped in a *LimitError, when a recipe exceeds one
+// of the parser's Limits.
+var ErrLimitExceeded = errors.New("parser limit exceeded")
+
+// Limits caps how much a parser accepts, so services can parse untrusted recipes
+// without letting a crafted upload use unbounded memory. Zero means no limit.
+//
+// Example:
+//
+//	p := parser.New()
+//	p.Limits = parser.Limits{MaxBytes: 1 << 20, MaxSteps: 500, MaxComponentsPerStep: 200, MaxNesting: 8}
+//	recipe, err := p.ParseString(upload)
+//	if errors.Is(err, parser.ErrLimitExceeded) {
+//	    // reject the upload
+//	}
+type Limits struct {
+	MaxBytes             int // Largest accepted input, in bytes
+	MaxSteps             int // Most steps, counting notes
+	MaxComponentsPerStep int // Most components in one step; consecutive text counts once
+	MaxNesting           int // Deepest nesting of the YAML frontmatter, counting indentation and [ ] or { } levels
+}
+
+// LimitError reports which limit a recipe exceeded. It matches ErrLimitExceeded
+// with errors.Is.
+type LimitError struct {
+	Limit string // What was counted, e.g. "steps" or "components in a step"
+	Max   int    // The limit
+	Line  int    // Line at which the limit was exceeded; 0 for the input size
+}
+
+// Error describes the exceeded limit.
+func (e *LimitError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("%v: more than %d %s at line %d", ErrLimitExceeded, e.Max, e.Limit, e.Line)
+	}
+	return fmt.Sprintf("%v: more than %d %s", ErrLimitExceeded, e.Max, e.Limit)
+}
+
+// Unwrap returns ErrLimitExceeded.
+func (e *LimitError) Unwrap() error {
+	return ErrLimitExceeded
+}
+
+// exceeded reports whether count is over a limit; a limit of zero or less is no limit.
+func exceeded(count, limit int) bool {
+	return limit > 0 && count > limit
+}
+
+// frontmatterNesting returns how deeply YAML frontmatter nests: top-level keys are
+// at level 1, every deeper indentation adds a level, and so does every open [ or {
+// within a line.
+func frontmatterNesting(content string) int {
+	var indents []int
+	deepest := 0
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		indent := countLeadingSpaces(line)
+		for len(indents) > 0 && indents[len(indents)-1] >= indent {
+			indents = indents[:len(indents)-1]
+		}
+		indents = append(indents, indent)
+
+		depth := len(indents)
+		deepest = max(deepest, depth)
+		for _, r := range trimmed {
+			switch r {
+			case '[', '{':
+				depth++
+				deepest = max(deepest, depth)
+			case ']', '}':
+				depth--
+			}
+		}
+	}
+	return deepest
+}
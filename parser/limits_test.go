@@ -0,0 +1,62 @@
+package parser
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestLimits(t *testing.T) {
+	tests := []struct {
+		name   string
+		limits Limits
+		input  string
+		limit  string // Exceeded limit; empty if the input is accepted
+		line   int
+	}{
+		{"no limits", Limits{}, strings.Repeat("Stir @sugar{1%tsp}.\n\n", 1000), "", 0},
+		{"bytes", Limits{MaxBytes: 10}, "Boil @water{1%l} for ~{5%minutes}.", "bytes", 0},
+		{"bytes at the limit", Limits{MaxBytes: 6}, "Toast.", "", 0},
+		{"steps", Limits{MaxSteps: 2}, "One.\n\nTwo.\n\nThree.\n\nFour.", "steps", 5},
+		{"steps at the limit", Limits{MaxSteps: 2}, "One.\n\n> A note\n", "", 0},
+		{"notes count as steps", Limits{MaxSteps: 2}, "One.\n\n> A note\n\nTwo.", "steps", 5},
+		{"components", Limits{MaxComponentsPerStep: 4}, "Mix @a{} @b{} @c{}.", "components in a step", 1},
+		{"text counts once", Limits{MaxComponentsPerStep: 5}, "Mix the @flour{} with\nsome @water{} please", "", 0},
+		{"components per step", Limits{MaxComponentsPerStep: 3}, "Mix @a{}.\n\nMix @b{}.", "", 0},
+		{"nesting", Limits{MaxNesting: 2}, "---\nnutrition:\n  per serving:\n    calories: 300\n---\nEat.", "nesting levels", 1},
+		{"flow nesting", Limits{MaxNesting: 2}, "---\ntags: [[[a]]]\n---\nEat.", "nesting levels", 1},
+		{"nesting at the limit", Limits{MaxNesting: 2}, "---\ntitle: Toast\ntags:\n  - quick\n---\nEat.", "", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := New()
+			p.ExtendedMode = true
+			p.Limits = tt.limits
+			_, err := p.ParseString(tt.input)
+			if tt.limit == "" {
+				if err != nil {
+					t.Fatalf("expected the recipe to be accepted, got %v", err)
+				}
+				return
+			}
+			var limitErr *LimitError
+			if !errors.Is(err, ErrLimitExceeded) || !errors.As(err, &limitErr) {
+				t.Fatalf("expected a LimitError, got %v", err)
+			}
+			if limitErr.Limit != tt.limit || limitErr.Line != tt.line {
+				t.Errorf("got limit %q at line %d, want %q at line %d", limitErr.Limit, limitErr.Line, tt.limit, tt.line)
+			}
+		})
+	}
+}
+
+func TestLimitErrorMessage(t *testing.T) {
+	err := &LimitError{Limit: "steps", Max: 500, Line: 1201}
+	if got := err.Error(); got != "parser limit exceeded: more than 500 steps at line 1201" {
+		t.Errorf("Error() = %q", got)
+	}
+	err = &LimitError{Limit: "bytes", Max: 1024}
+	if got := err.Error(); got != "parser limit exceeded: more than 1024 bytes" {
+		t.Errorf("Error() = %q", got)
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"io"
 	"strconv"
 	"strings"
+	"sync"
+	"unicode/utf8"
 
 	"github.com/hilli/cooklang/lexer"
 	"github.com/hilli/cooklang/token"
@@ -20,24 +22,36 @@ type Recipe struct {
 
 // Step represents a cooking step with its components
 type Step struct {
-	Components []Component `json:"components" yaml:"steps"`
+	Components []Component          `json:"components" yaml:"steps"`
+	Position   token.SourcePosition `json:"position,omitzero" yaml:"-"` // Where the step is in the source, from its first to its last component
 }
 
 // Component represents a component within a step
 type Component struct {
-	Type     string `json:"type" yaml:"type"` // "text", "ingredient", "cookware", "timer"
-	Value    string `json:"value,omitempty" yaml:"value,omitempty"`
-	Name     string `json:"name,omitempty" yaml:"name,omitempty"`
-	Quantity string `json:"quantity,omitempty" yaml:"quantity,omitempty"`
-	Unit     string `json:"unit,omitempty" yaml:"units,omitempty"`
-	Fixed    bool   `json:"fixed,omitempty" yaml:"fixed,omitempty"`       // Fixed quantity doesn't scale with servings
-	Optional bool   `json:"optional,omitempty" yaml:"optional,omitempty"` // Optional ingredient
+	Type        string `json:"type" yaml:"type"` // "text", "ingredient", "cookware", "timer"
+	Value       string `json:"value,omitempty" yaml:"value,omitempty"`
+	Name        string `json:"name,omitempty" yaml:"name,omitempty"`
+	Quantity    string `json:"quantity,omitempty" yaml:"quantity,omitempty"`
+	Unit        string `json:"unit,omitempty" yaml:"units,omitempty"`
+	Fixed       bool   `json:"fixed,omitempty" yaml:"fixed,omitempty"`             // Fixed quantity doesn't scale with servings
+	Optional    bool   `json:"optional,omitempty" yaml:"optional,omitempty"`       // Optional ingredient
+	Reference   bool   `json:"reference,omitempty" yaml:"reference,omitempty"`     // Reference to an ingredient added earlier (e.g., "@&flour{}")
+	Approximate bool   `json:"approximate,omitempty" yaml:"approximate,omitempty"` // Quantity is an estimate (e.g., "~2" or "about 2")
+	Source      string `json:"source,omitempty" yaml:"source,omitempty"`           // Source text of a custom component (see RegisterExtension)
+
+	Position token.SourcePosition `json:"position,omitzero" yaml:"-"` // Where the component is in the source
 }
 
-// CooklangParser handles parsing of cooklang recipes
+// CooklangParser handles parsing of cooklang recipes.
+//
+// A parser keeps no state between parses, so one parser can be reused for many
+// recipes and is safe for concurrent use by multiple goroutines, as long as its
+// fields are not changed while it is parsing.
 type CooklangParser struct {
-	CooklangSpecVersion int
-	ExtendedMode        bool // Enable extended spec features
+	CooklangSpecVersion  int
+	ExtendedMode         bool   // Enable extended spec features
+	DisableMetadataLines bool   // Treat classic ">> key: value" lines as notes instead of metadata
+	Limits               Limits // Caps on the input size, steps, components and nesting; zero values mean no limit
 }
 
 // New creates a new CooklangParser
@@ -47,10 +61,26 @@ func New() *CooklangParser {
 	}
 }
 
+// lexerPool holds lexers for reuse between parses, so their buffers are not
+// allocated for every recipe.
+var lexerPool = sync.Pool{New: func() any { return new(lexer.Lexer) }}
+
 // ParseString parses a cooklang recipe from a string
 func (p *CooklangParser) ParseString(input string) (*Recipe, error) {
-	l := lexer.New(input)
-	return p.parseTokens(l)
+	if exceeded(len(input), p.Limits.MaxBytes) {
+		return nil, &LimitError{Limit: "bytes", Max: p.Limits.MaxBytes}
+	}
+	l := lexerPool.Get().(*lexer.Lexer)
+	l.Reset(input)
+	handlers := registeredExtensions()
+	for prefix := range handlers {
+		l.AddExtensionPrefix(prefix)
+	}
+	defer func() {
+		l.Reset("") // Don't keep the input alive
+		lexerPool.Put(l)
+	}()
+	return p.parseTokens(l, handlers)
 }
 
 // ParseBytes parses a cooklang recipe from a byte slice
@@ -68,7 +98,7 @@ func (p *CooklangParser) ParseReader(reader io.Reader) (*Recipe, error) {
 }
 
 // parseTokens handles the actual parsing logic
-func (p *CooklangParser) parseTokens(l *lexer.Lexer) (*Recipe, error) {
+func (p *CooklangParser) parseTokens(l *lexer.Lexer, handlers map[rune]ExtensionHandler) (*Recipe, error) {
 	recipe := &Recipe{
 		Metadata: make(map[string]string),
 		Steps:    []Step{},
@@ -76,6 +106,47 @@ func (p *CooklangParser) parseTokens(l *lexer.Lexer) (*Recipe, error) {
 
 	// Parse tokens and build recipe
 	currentStep := Step{Components: []Component{}}
+	components := 0 // Components in the current step as counted by Limits
+
+	// appendComponent appends a component to the current step
+	appendComponent := func(component Component) {
+		last := len(currentStep.Components) - 1
+		if component.Type != "text" || last < 0 || currentStep.Components[last].Type != "text" {
+			components++
+		}
+		currentStep.Components = append(currentStep.Components, component)
+	}
+
+	// add appends a component to the current step, covering the source from start
+	// to the end of the last token read
+	add := func(component Component, start int) {
+		component.Position = l.SourcePosition(start, l.Offset())
+		appendComponent(component)
+	}
+
+	// endStep adds the current step to the recipe, if it has components, and starts
+	// a new one
+	endStep := func() {
+		if len(currentStep.Components) > 0 {
+			recipe.Steps = append(recipe.Steps, currentStep)
+			currentStep = Step{Components: []Component{}}
+			components = 0
+		}
+	}
+
+	// addNote adds a note as a step of its own. Notes appear in recipe details but
+	// not during cooking, so they are kept apart from the cooking instructions.
+	addNote := func(value string, start int) {
+		endStep()
+		add(Component{
+			Type:  "note",
+			Value: value,
+		}, start)
+		endStep()
+	}
+
+	// frontmatter holds the YAML frontmatter, which takes precedence over ">>" lines
+	var frontmatter Metadata
 
 	for {
 		tok := l.NextToken()
@@ -86,151 +157,151 @@ func (p *CooklangParser) parseTokens(l *lexer.Lexer) (*Recipe, error) {
 		switch tok.Type {
 		case token.YAML_FRONTMATTER:
 			// Parse YAML frontmatter into metadata
+			if exceeded(frontmatterNesting(tok.Literal), p.Limits.MaxNesting) {
+				return nil, &LimitError{Limit: "nesting levels", Max: p.Limits.MaxNesting, Line: l.SourcePosition(tok.Pos, tok.End).Line}
+			}
 			metadata, err := p.parseYAMLMetadata(tok.Literal)
 			if err != nil {
 				return nil, fmt.Errorf("failed to parse YAML frontmatter: %w", err)
 			}
-			recipe.Metadata = metadata
+			frontmatter = metadata
+			for key, value := range metadata {
+				recipe.Metadata[key] = value
+			}
 
 		case token.LINE_BREAK:
 			// Hard line break (backslash at EOL) - preserve as newline within step
-			currentStep.Components = append(currentStep.Components, Component{
+			add(Component{
 				Type:  "text",
 				Value: "\n",
-			})
+			}, tok.Pos)
 
 		case token.NEWLINE:
 			// Handle newlines: single newline = space, double newline = new step
 			nextTok := l.NextToken()
 			if nextTok.Type == token.NEWLINE {
 				// Double newline (blank line) - create new step
-				if len(currentStep.Components) > 0 {
-					recipe.Steps = append(recipe.Steps, currentStep)
-					currentStep = Step{Components: []Component{}}
-				}
+				endStep()
 			} else if nextTok.Type == token.EOF {
 				// End of file after newline - don't add space, just break
 				break
 			} else {
 				// Single newline - convert to space
 				if len(currentStep.Components) > 0 {
-					currentStep.Components = append(currentStep.Components, Component{
-						Type:  "text",
-						Value: " ",
+					appendComponent(Component{
+						Type:     "text",
+						Value:    " ",
+						Position: l.SourcePosition(tok.Pos, tok.End),
 					})
 				}
 				// Process the next token immediately here
 				switch nextTok.Type {
 				case token.LINE_BREAK:
 					// Hard line break after a newline-converted-to-space
-					currentStep.Components = append(currentStep.Components, Component{
+					add(Component{
 						Type:  "text",
 						Value: "\n",
-					})
+					}, nextTok.Pos)
 				case token.RECIPE_REFERENCE:
 					ref, err := p.parseRecipeReference(l, nextTok.Literal)
 					if err != nil {
 						return nil, fmt.Errorf("failed to parse recipe reference: %w", err)
 					}
-					currentStep.Components = append(currentStep.Components, ref)
-				case token.INGREDIENT, token.OPTIONAL_INGREDIENT:
+					add(ref, nextTok.Pos)
+				case token.INGREDIENT, token.OPTIONAL_INGREDIENT, token.REFERENCE_INGREDIENT:
 					ingredient, err := p.parseIngredient(l)
 					if err != nil {
 						return nil, fmt.Errorf("failed to parse ingredient: %w", err)
 					}
-					if nextTok.Type == token.OPTIONAL_INGREDIENT {
-						ingredient.Optional = true
-					}
-					currentStep.Components = append(currentStep.Components, ingredient)
+					ingredient.Optional = nextTok.Type == token.OPTIONAL_INGREDIENT
+					ingredient.Reference = nextTok.Type == token.REFERENCE_INGREDIENT
+					add(ingredient, nextTok.Pos)
 				case token.COOKWARE:
 					cookware, err := p.parseCookware(l)
 					if err != nil {
 						return nil, fmt.Errorf("failed to parse cookware: %w", err)
 					}
-					currentStep.Components = append(currentStep.Components, cookware)
+					add(cookware, nextTok.Pos)
 				case token.COOKTIME:
 					timer, err := p.parseTimer(l)
 					if err != nil {
 						return nil, fmt.Errorf("failed to parse timer: %w", err)
 					}
-					currentStep.Components = append(currentStep.Components, timer)
+					add(timer, nextTok.Pos)
+				case token.EXTENSION:
+					extension, err := parseExtension(handlers, nextTok.Literal)
+					if err != nil {
+						return nil, fmt.Errorf("failed to parse custom component: %w", err)
+					}
+					add(extension, nextTok.Pos)
 				case token.WHITESPACE:
-					currentStep.Components = append(currentStep.Components, Component{
+					add(Component{
 						Type:  "text",
 						Value: nextTok.Literal,
-					})
+					}, nextTok.Pos)
 				case token.IDENT:
-					currentStep.Components = append(currentStep.Components, Component{
+					add(Component{
 						Type:  "text",
 						Value: nextTok.Literal,
-					})
+					}, nextTok.Pos)
 				case token.COMMENT:
 					// Only create comment components in extended mode
 					if p.ExtendedMode {
-						currentStep.Components = append(currentStep.Components, Component{
+						add(Component{
 							Type:  "comment",
 							Value: nextTok.Literal,
-						})
+						}, nextTok.Pos)
 					}
 					// In canonical mode, ignore comments
 				case token.BLOCK_COMMENT:
 					// Only create block comment components in extended mode
 					if p.ExtendedMode {
-						currentStep.Components = append(currentStep.Components, Component{
+						add(Component{
 							Type:  "blockComment",
 							Value: nextTok.Literal,
-						})
+						}, nextTok.Pos)
 					}
 					// In canonical mode, ignore block comments
 				case token.SECTION_HEADER:
 					// Section headers start a new step
-					if len(currentStep.Components) > 0 {
-						recipe.Steps = append(recipe.Steps, currentStep)
-						currentStep = Step{Components: []Component{}}
-					}
-					currentStep.Components = append(currentStep.Components, Component{
+					endStep()
+					add(Component{
 						Type: "section",
 						Name: nextTok.Literal,
-					})
+					}, nextTok.Pos)
 				case token.NOTE:
-					// Notes are standalone blocks that appear in recipe details but not during cooking
-					// Notes always start a new step to keep them separate from cooking instructions
-					if len(currentStep.Components) > 0 {
-						recipe.Steps = append(recipe.Steps, currentStep)
-						currentStep = Step{Components: []Component{}}
+					addNote(nextTok.Literal, nextTok.Pos)
+				case token.METADATA:
+					if p.DisableMetadataLines {
+						addNote("> "+nextTok.Literal, nextTok.Pos)
+					} else {
+						setMetadataLine(recipe.Metadata, frontmatter, nextTok.Literal)
 					}
-					currentStep.Components = append(currentStep.Components, Component{
-						Type:  "note",
-						Value: nextTok.Literal,
-					})
-					// Add the note step immediately and start fresh for next content
-					recipe.Steps = append(recipe.Steps, currentStep)
-					currentStep = Step{Components: []Component{}}
 				default:
-					currentStep.Components = append(currentStep.Components, Component{
+					add(Component{
 						Type:  "text",
 						Value: nextTok.Literal,
-					})
+					}, nextTok.Pos)
 				}
 			}
 
 		case token.COMMENT:
 			// Only create comment components in extended mode
 			if p.ExtendedMode {
-				currentStep.Components = append(currentStep.Components, Component{
+				add(Component{
 					Type:  "comment",
 					Value: tok.Literal,
-				})
+				}, tok.Pos)
 			}
 			// In canonical mode, ignore comments
 
 		case token.BLOCK_COMMENT:
 			// Only create block comment components in extended mode
 			if p.ExtendedMode {
-				currentStep.Components = append(currentStep.Components, Component{
+				add(Component{
 					Type:  "blockComment",
 					Value: tok.Literal,
-				})
+				}, tok.Pos)
 			}
 			// In canonical mode, ignore block comments
 
@@ -238,41 +309,34 @@ func (p *CooklangParser) parseTokens(l *lexer.Lexer) (*Recipe, error) {
 			// Section headers start a new step (if current has content) and add section component
 			// In canonical mode, sections are treated as step separators
 			// In extended mode, sections create section components
-			if len(currentStep.Components) > 0 {
-				recipe.Steps = append(recipe.Steps, currentStep)
-				currentStep = Step{Components: []Component{}}
-			}
+			endStep()
 			// Add section as a component (in both modes for now, renderers can decide what to do)
-			currentStep.Components = append(currentStep.Components, Component{
+			add(Component{
 				Type: "section",
 				Name: tok.Literal, // Section name
-			})
+			}, tok.Pos)
 
 		case token.NOTE:
 			// Notes are standalone blocks that appear in recipe details but not during cooking
-			// Notes always start a new step to keep them separate from cooking instructions
-			if len(currentStep.Components) > 0 {
-				recipe.Steps = append(recipe.Steps, currentStep)
-				currentStep = Step{Components: []Component{}}
+			addNote(tok.Literal, tok.Pos)
+
+		case token.METADATA:
+			// Classic ">> key: value" metadata lines add to the frontmatter metadata
+			if p.DisableMetadataLines {
+				addNote("> "+tok.Literal, tok.Pos)
+			} else {
+				setMetadataLine(recipe.Metadata, frontmatter, tok.Literal)
 			}
-			currentStep.Components = append(currentStep.Components, Component{
-				Type:  "note",
-				Value: tok.Literal,
-			})
-			// Add the note step immediately and start fresh for next content
-			recipe.Steps = append(recipe.Steps, currentStep)
-			currentStep = Step{Components: []Component{}}
 
-		case token.INGREDIENT, token.OPTIONAL_INGREDIENT:
+		case token.INGREDIENT, token.OPTIONAL_INGREDIENT, token.REFERENCE_INGREDIENT:
 			// Parse ingredient
 			ingredient, err := p.parseIngredient(l)
 			if err != nil {
 				return nil, fmt.Errorf("failed to parse ingredient: %w", err)
 			}
-			if tok.Type == token.OPTIONAL_INGREDIENT {
-				ingredient.Optional = true
-			}
-			currentStep.Components = append(currentStep.Components, ingredient)
+			ingredient.Optional = tok.Type == token.OPTIONAL_INGREDIENT
+			ingredient.Reference = tok.Type == token.REFERENCE_INGREDIENT
+			add(ingredient, tok.Pos)
 
 		case token.RECIPE_REFERENCE:
 			// Parse recipe reference
@@ -280,7 +344,7 @@ func (p *CooklangParser) parseTokens(l *lexer.Lexer) (*Recipe, error) {
 			if err != nil {
 				return nil, fmt.Errorf("failed to parse recipe reference: %w", err)
 			}
-			currentStep.Components = append(currentStep.Components, ref)
+			add(ref, tok.Pos)
 
 		case token.COOKWARE:
 			// Parse cookware
@@ -288,7 +352,7 @@ func (p *CooklangParser) parseTokens(l *lexer.Lexer) (*Recipe, error) {
 			if err != nil {
 				return nil, fmt.Errorf("failed to parse cookware: %w", err)
 			}
-			currentStep.Components = append(currentStep.Components, cookware)
+			add(cookware, tok.Pos)
 
 		case token.COOKTIME:
 			// Parse timer
@@ -296,45 +360,80 @@ func (p *CooklangParser) parseTokens(l *lexer.Lexer) (*Recipe, error) {
 			if err != nil {
 				return nil, fmt.Errorf("failed to parse timer: %w", err)
 			}
-			currentStep.Components = append(currentStep.Components, timer)
+			add(timer, tok.Pos)
+
+		case token.EXTENSION:
+			// Parse a custom component with its registered handler
+			extension, err := parseExtension(handlers, tok.Literal)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse custom component: %w", err)
+			}
+			add(extension, tok.Pos)
 
 		case token.WHITESPACE:
 			// Handle whitespace as text component
-			currentStep.Components = append(currentStep.Components, Component{
+			add(Component{
 				Type:  "text",
 				Value: tok.Literal,
-			})
+			}, tok.Pos)
 
 		case token.IDENT:
 			// Regular text
-			currentStep.Components = append(currentStep.Components, Component{
+			add(Component{
 				Type:  "text",
 				Value: tok.Literal,
-			})
+			}, tok.Pos)
 
 		default:
 			// Other tokens like punctuation, numbers, etc.
-			currentStep.Components = append(currentStep.Components, Component{
+			add(Component{
 				Type:  "text",
 				Value: tok.Literal,
-			})
+			}, tok.Pos)
 		}
 
-		// Check if we need to start a new step (simplified logic)
-		// In a real implementation, you'd want more sophisticated step detection
+		// Stop as soon as a limit is exceeded, before the recipe grows any further
+		if exceeded(components, p.Limits.MaxComponentsPerStep) {
+			return nil, &LimitError{Limit: "components in a step", Max: p.Limits.MaxComponentsPerStep, Line: l.SourcePosition(tok.Pos, tok.End).Line}
+		}
+		if exceeded(len(recipe.Steps), p.Limits.MaxSteps) {
+			return nil, &LimitError{Limit: "steps", Max: p.Limits.MaxSteps, Line: l.SourcePosition(tok.Pos, tok.End).Line}
+		}
 	}
 
 	// Add the current step if it has components
-	if len(currentStep.Components) > 0 {
-		recipe.Steps = append(recipe.Steps, currentStep)
+	endStep()
+	if exceeded(len(recipe.Steps), p.Limits.MaxSteps) {
+		return nil, &LimitError{Limit: "steps", Max: p.Limits.MaxSteps, Line: l.SourcePosition(l.Offset(), l.Offset()).Line}
 	}
 
 	// Compress consecutive text elements in all steps
 	p.compressTextElements(recipe)
 
+	// A step spans from its first to its last component
+	for i := range recipe.Steps {
+		components := recipe.Steps[i].Components
+		recipe.Steps[i].Position = components[0].Position
+		recipe.Steps[i].Position.EndOffset = components[len(components)-1].Position.EndOffset
+	}
+
 	return recipe, nil
 }
 
+// setMetadataLine adds a classic "key: value" metadata line to metadata. Keys set in
+// the YAML frontmatter win; a later line with the same key replaces an earlier one.
+func setMetadataLine(metadata, frontmatter Metadata, line string) {
+	key, value, _ := strings.Cut(line, ":")
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return
+	}
+	if _, ok := frontmatter[key]; ok {
+		return
+	}
+	metadata[key] = strings.TrimSpace(value)
+}
+
 // blockScalarType represents the type and chomping mode of a YAML block scalar
 type blockScalarType struct {
 	style    string // "literal" (|) or "folded" (>)
@@ -624,12 +723,13 @@ func (p *CooklangParser) parseRecipeReference(l *lexer.Lexer, path string) (Comp
 	// Check for quantity in braces
 	tok := l.NextToken()
 	if tok.Type == token.LBRACE {
-		quantity, unit, _, err := p.parseQuantityAndUnit(l)
+		quantity, unit, _, isApproximate, err := p.parseQuantityAndUnit(l)
 		if err != nil {
 			return component, err
 		}
 		component.Quantity = quantity
 		component.Unit = unit
+		component.Approximate = isApproximate
 		return component, nil
 	}
 
@@ -657,7 +757,7 @@ func (p *CooklangParser) parseIngredient(l *lexer.Lexer) (Component, error) {
 			for _, t := range nameTokens {
 				nameParts = append(nameParts, t.Literal)
 			}
-			quantity, unit, isFixed, err := p.parseQuantityAndUnit(l)
+			quantity, unit, isFixed, isApproximate, err := p.parseQuantityAndUnit(l)
 			if err != nil {
 				return component, err
 			}
@@ -666,6 +766,7 @@ func (p *CooklangParser) parseIngredient(l *lexer.Lexer) (Component, error) {
 			component.Unit = unit
 			component.Name = strings.Join(nameParts, "")
 			component.Fixed = isFixed
+			component.Approximate = isApproximate
 
 			// Check for instruction in parentheses
 			tok := l.NextToken()
@@ -762,7 +863,7 @@ func (p *CooklangParser) parseCookware(l *lexer.Lexer) (Component, error) {
 			for _, t := range nameTokens {
 				nameParts = append(nameParts, t.Literal)
 			}
-			quantity, _, _, err := p.parseQuantityAndUnit(l) // isFixed ignored - cookware doesn't scale
+			quantity, _, _, _, err := p.parseQuantityAndUnit(l) // isFixed ignored - cookware doesn't scale
 			if err != nil {
 				return component, err
 			}
@@ -840,17 +941,21 @@ func (p *CooklangParser) parseTimer(l *lexer.Lexer) (Component, error) {
 	switch tok.Type {
 	case token.IDENT:
 		if p.ExtendedMode {
-			// Extended mode: allow multi-word timer names
-			var nameTokens []string
-			nameTokens = append(nameTokens, tok.Literal)
+			// Extended mode: allow multi-word timer names when braces follow
+			nameTokens := []token.Token{tok}
 
+		collectName:
 			for {
 				nextTok := l.NextToken()
 				switch nextTok.Type {
 				case token.LBRACE:
 					// Found braces - parse quantity/unit
-					component.Name = strings.Join(nameTokens, "")
-					quantity, unit, _, err := p.parseQuantityAndUnit(l) // isFixed ignored - timers don't scale
+					var nameParts []string
+					for _, t := range nameTokens {
+						nameParts = append(nameParts, t.Literal)
+					}
+					component.Name = strings.Join(nameParts, "")
+					quantity, unit, _, _, err := p.parseQuantityAndUnit(l) // isFixed ignored - timers don't scale
 					if err != nil {
 						return component, err
 					}
@@ -858,16 +963,18 @@ func (p *CooklangParser) parseTimer(l *lexer.Lexer) (Component, error) {
 					component.Quantity = quantity
 					component.Unit = unit
 					return component, nil
-				case token.WHITESPACE:
-					// Add whitespace to name parts
-					nameTokens = append(nameTokens, nextTok.Literal)
-				case token.IDENT:
-					// Additional word in timer name
-					nameTokens = append(nameTokens, nextTok.Literal)
+				case token.WHITESPACE, token.IDENT:
+					// Whitespace or an additional word in the timer name
+					nameTokens = append(nameTokens, nextTok)
 				default:
-					// Hit something else - put it back and stop
+					// No braces - like in canonical mode, only the first word is the
+					// name; put back the rest (in reverse order) and stop
 					l.PutBackToken(nextTok)
-					component.Name = strings.TrimSpace(strings.Join(nameTokens, ""))
+					for i := len(nameTokens) - 1; i > 0; i-- {
+						l.PutBackToken(nameTokens[i])
+					}
+					component.Name = tok.Literal
+					break collectName
 				}
 			}
 		} else {
@@ -878,7 +985,7 @@ func (p *CooklangParser) parseTimer(l *lexer.Lexer) (Component, error) {
 			nextTok := l.NextToken()
 			if nextTok.Type == token.LBRACE {
 				// Parse quantity/unit
-				quantity, unit, _, err := p.parseQuantityAndUnit(l) // isFixed ignored - timers don't scale
+				quantity, unit, _, _, err := p.parseQuantityAndUnit(l) // isFixed ignored - timers don't scale
 				if err != nil {
 					return component, err
 				}
@@ -892,7 +999,7 @@ func (p *CooklangParser) parseTimer(l *lexer.Lexer) (Component, error) {
 		}
 	case token.LBRACE:
 		// Anonymous timer - parse quantity/unit directly
-		quantity, unit, _, err := p.parseQuantityAndUnit(l) // isFixed ignored - timers don't scale
+		quantity, unit, _, _, err := p.parseQuantityAndUnit(l) // isFixed ignored - timers don't scale
 		if err != nil {
 			return component, err
 		}
@@ -926,8 +1033,9 @@ func (p *CooklangParser) parseTimer(l *lexer.Lexer) (Component, error) {
 }
 
 // parseQuantityAndUnit parses quantity and units from within braces
-// Returns quantity, unit, isFixed (true if quantity has = prefix), and error
-func (p *CooklangParser) parseQuantityAndUnit(l *lexer.Lexer) (string, string, bool, error) {
+// Returns quantity, unit, isFixed (true if quantity has = prefix),
+// isApproximate (true if quantity has a ~ or "about" prefix), and error
+func (p *CooklangParser) parseQuantityAndUnit(l *lexer.Lexer) (string, string, bool, bool, error) {
 	var quantityParts []string
 	var unit string
 	var foundPercent bool
@@ -943,7 +1051,7 @@ func (p *CooklangParser) parseQuantityAndUnit(l *lexer.Lexer) (string, string, b
 	// Process tokens starting with the current one
 	for tok.Type != token.RBRACE {
 		if tok.Type == token.EOF {
-			return "", "", false, fmt.Errorf("unexpected EOF while parsing quantity/unit")
+			return "", "", false, false, fmt.Errorf("unexpected EOF while parsing quantity/unit")
 		}
 
 		if tok.Type == token.PERCENT {
@@ -965,6 +1073,9 @@ func (p *CooklangParser) parseQuantityAndUnit(l *lexer.Lexer) (string, string, b
 			// Before % is quantity
 			if tok.Type == token.INT || tok.Type == token.IDENT || tok.Type == token.DASH || tok.Type == token.DIVIDE || tok.Type == token.PERIOD || tok.Type == token.WHITESPACE {
 				quantityParts = append(quantityParts, tok.Literal)
+			} else if tok.Type == token.ILLEGAL && tok.Literal == "~" {
+				// A lone ~ inside braces marks an approximate quantity
+				quantityParts = append(quantityParts, tok.Literal)
 			}
 		}
 		tok = l.NextToken()
@@ -976,6 +1087,8 @@ func (p *CooklangParser) parseQuantityAndUnit(l *lexer.Lexer) (string, string, b
 	// Trim whitespace from quantity, but preserve internal spaces
 	quantity = strings.TrimSpace(quantity)
 
+	quantity, isApproximate := splitApproximate(quantity)
+
 	if quantity == "" {
 		quantity = "some"
 	} else {
@@ -987,7 +1100,23 @@ func (p *CooklangParser) parseQuantityAndUnit(l *lexer.Lexer) (string, string, b
 	unit = strings.TrimSpace(unit)
 
 	// Don't set default units - spec expects empty string when no units provided
-	return quantity, unit, isFixed, nil
+	return quantity, unit, isFixed, isApproximate, nil
+}
+
+// approximatePrefixes are the words that mark a quantity as an estimate,
+// longest first so "approximately" is not mistaken for "approx".
+var approximatePrefixes = []string{"approximately ", "approx. ", "approx ", "about ", "circa ", "ca. ", "~"}
+
+// splitApproximate strips a leading approximation marker such as "~" or "about "
+// from a quantity, reporting whether one was found.
+func splitApproximate(quantity string) (string, bool) {
+	lower := strings.ToLower(quantity)
+	for _, prefix := range approximatePrefixes {
+		if strings.HasPrefix(lower, prefix) {
+			return strings.TrimSpace(quantity[len(prefix):]), true
+		}
+	}
+	return quantity, false
 }
 
 // evaluateFraction converts fraction strings like "1/2" to decimal representation "0.5"
@@ -1057,29 +1186,34 @@ func (p *CooklangParser) evaluateFraction(quantity string) string {
 	return quantity
 }
 
+// unicodeFractions maps Unicode fraction characters to their decimal values.
+var unicodeFractions = map[rune]float64{
+	'½': 0.5,     // VULGAR FRACTION ONE HALF
+	'¼': 0.25,    // VULGAR FRACTION ONE QUARTER
+	'¾': 0.75,    // VULGAR FRACTION THREE QUARTERS
+	'⅓': 1.0 / 3, // VULGAR FRACTION ONE THIRD
+	'⅔': 2.0 / 3, // VULGAR FRACTION TWO THIRDS
+	'⅕': 0.2,     // VULGAR FRACTION ONE FIFTH
+	'⅖': 0.4,     // VULGAR FRACTION TWO FIFTHS
+	'⅗': 0.6,     // VULGAR FRACTION THREE FIFTHS
+	'⅘': 0.8,     // VULGAR FRACTION FOUR FIFTHS
+	'⅙': 1.0 / 6, // VULGAR FRACTION ONE SIXTH
+	'⅚': 5.0 / 6, // VULGAR FRACTION FIVE SIXTHS
+	'⅐': 1.0 / 7, // VULGAR FRACTION ONE SEVENTH
+	'⅛': 0.125,   // VULGAR FRACTION ONE EIGHTH
+	'⅜': 0.375,   // VULGAR FRACTION THREE EIGHTHS
+	'⅝': 0.625,   // VULGAR FRACTION FIVE EIGHTHS
+	'⅞': 0.875,   // VULGAR FRACTION SEVEN EIGHTHS
+	'⅑': 1.0 / 9, // VULGAR FRACTION ONE NINTH
+	'⅒': 0.1,     // VULGAR FRACTION ONE TENTH
+}
+
 // convertUnicodeFractions converts Unicode fraction characters to decimal
 // Supports both simple fractions (½) and mixed fractions (1½)
 func (p *CooklangParser) convertUnicodeFractions(quantity string) string {
-	// Map of Unicode fraction characters to their decimal values
-	unicodeFractions := map[rune]float64{
-		'½': 0.5,     // VULGAR FRACTION ONE HALF
-		'¼': 0.25,    // VULGAR FRACTION ONE QUARTER
-		'¾': 0.75,    // VULGAR FRACTION THREE QUARTERS
-		'⅓': 1.0 / 3, // VULGAR FRACTION ONE THIRD
-		'⅔': 2.0 / 3, // VULGAR FRACTION TWO THIRDS
-		'⅕': 0.2,     // VULGAR FRACTION ONE FIFTH
-		'⅖': 0.4,     // VULGAR FRACTION TWO FIFTHS
-		'⅗': 0.6,     // VULGAR FRACTION THREE FIFTHS
-		'⅘': 0.8,     // VULGAR FRACTION FOUR FIFTHS
-		'⅙': 1.0 / 6, // VULGAR FRACTION ONE SIXTH
-		'⅚': 5.0 / 6, // VULGAR FRACTION FIVE SIXTHS
-		'⅐': 1.0 / 7, // VULGAR FRACTION ONE SEVENTH
-		'⅛': 0.125,   // VULGAR FRACTION ONE EIGHTH
-		'⅜': 0.375,   // VULGAR FRACTION THREE EIGHTHS
-		'⅝': 0.625,   // VULGAR FRACTION FIVE EIGHTHS
-		'⅞': 0.875,   // VULGAR FRACTION SEVEN EIGHTHS
-		'⅑': 1.0 / 9, // VULGAR FRACTION ONE NINTH
-		'⅒': 0.1,     // VULGAR FRACTION ONE TENTH
+	// Unicode fractions are never ASCII, so plain quantities need no further checks
+	if isASCII(quantity) {
+		return quantity
 	}
 
 	// Check if the string contains any Unicode fractions
@@ -1142,50 +1276,62 @@ func (p *CooklangParser) convertUnicodeFractions(quantity string) string {
 	return quantity
 }
 
-// compressTextElements merges consecutive text components into single components
+// isASCII reports whether s only contains ASCII characters.
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// compressTextElements merges consecutive text components into single components.
+// Hard line breaks ("\n") are kept as separate components. Steps are compacted in
+// place, since merging never makes them longer.
 func (p *CooklangParser) compressTextElements(recipe *Recipe) {
+	var text strings.Builder
 	for stepIndex := range recipe.Steps {
 		step := &recipe.Steps[stepIndex]
 		if len(step.Components) <= 1 {
 			continue // No compression needed for steps with 0 or 1 components
 		}
 
-		var compressed []Component
-		var textBuffer []string
-
-		flushText := func() {
-			if len(textBuffer) > 0 {
-				compressedText := strings.Join(textBuffer, "")
-				compressed = append(compressed, Component{
-					Type:  "text",
-					Value: compressedText,
-				})
-				textBuffer = nil
+		components := step.Components
+		compressed := components[:0]
+		for i := 0; i < len(components); {
+			component := components[i]
+			if component.Type != "text" || component.Value == "\n" {
+				compressed = append(compressed, component)
+				i++
+				continue
 			}
-		}
 
-		for _, component := range step.Components {
-			if component.Type == "text" {
-				if component.Value == "\n" {
-					// Hard line break — flush preceding text and keep as separate component
-					flushText()
-					compressed = append(compressed, component)
-				} else {
-					// Accumulate text components without adding spaces
-					textBuffer = append(textBuffer, component.Value)
+			// Find the run of text components starting here
+			end, size := i+1, len(component.Value)
+			for end < len(components) && components[end].Type == "text" && components[end].Value != "\n" {
+				size += len(components[end].Value)
+				end++
+			}
+			if end-i > 1 {
+				text.Reset()
+				text.Grow(size)
+				for _, part := range components[i:end] {
+					text.WriteString(part.Value)
 				}
-			} else {
-				// Non-text component: flush any accumulated text first
-				flushText()
-				// Add the non-text component
-				compressed = append(compressed, component)
+				component = Component{
+					Type:     "text",
+					Value:    text.String(),
+					Position: component.Position,
+				}
+				component.Position.EndOffset = components[end-1].Position.EndOffset
 			}
+			compressed = append(compressed, component)
+			i = end
 		}
 
-		// Flush any remaining text at the end
-		flushText()
-
-		// Replace the step's components with the compressed version
+		// Clear the components left over after compacting so they can be collected
+		clear(components[len(compressed):])
 		step.Components = compressed
 	}
 }
@@ -1,6 +1,8 @@
 package parser
 
 import (
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/hilli/cooklang/lexer"
@@ -1267,6 +1269,59 @@ Mix @flour{200%g} and @water{100%ml}.
 	}
 }
 
+func TestMetadataLines(t *testing.T) {
+	input := `---
+title: Toast
+servings: 2
+---
+>> servings: 4
+>> source: Grandma's kitchen
+>> tags: breakfast, quick
+
+Toast @bread{2%slices}.
+>> time required: 5 minutes
+Serve.`
+
+	recipe, err := New().ParseString(input)
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+
+	expected := Metadata{
+		"title":         "Toast",
+		"servings":      "2", // frontmatter wins
+		"source":        "Grandma's kitchen",
+		"tags":          "breakfast, quick",
+		"time required": "5 minutes",
+	}
+	if !reflect.DeepEqual(recipe.Metadata, expected) {
+		t.Errorf("Expected metadata %v, got %v", expected, recipe.Metadata)
+	}
+	if len(recipe.Steps) != 1 {
+		t.Fatalf("Expected 1 step, got %d: %+v", len(recipe.Steps), recipe.Steps)
+	}
+	var text strings.Builder
+	for _, comp := range recipe.Steps[0].Components {
+		text.WriteString(comp.Value + comp.Name)
+	}
+	if text.String() != "Toast bread. Serve." {
+		t.Errorf("Unexpected step text %q", text.String())
+	}
+
+	p := New()
+	p.DisableMetadataLines = true
+	recipe, err = p.ParseString(">> servings: 4\n\nToast @bread{}.")
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+	if len(recipe.Metadata) != 0 {
+		t.Errorf("Expected no metadata, got %v", recipe.Metadata)
+	}
+	if len(recipe.Steps) != 2 || recipe.Steps[0].Components[0].Type != "note" || recipe.Steps[0].Components[0].Value != "> servings: 4" {
+		t.Errorf("Expected the metadata line as a note, got %+v", recipe.Steps)
+	}
+}
+
 // TestNoteWithSections tests that notes work correctly with sections
 func TestNoteWithSections(t *testing.T) {
 	input := `= Preparation
@@ -1322,6 +1377,44 @@ Cook for ~{10%minutes}.`
 	}
 }
 
+// TestReferenceIngredient tests parsing of references to earlier ingredients with @& syntax
+func TestReferenceIngredient(t *testing.T) {
+	for _, extended := range []bool{false, true} {
+		p := New()
+		p.ExtendedMode = extended
+		recipe, err := p.ParseString("Mix @flour{500%g}.\n\nFold in the reserved @&flour{100%g} and @&sugar{}.")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(recipe.Steps) != 2 {
+			t.Fatalf("Expected 2 steps, got %d", len(recipe.Steps))
+		}
+		var ingredients []Component
+		for _, step := range recipe.Steps {
+			for _, component := range step.Components {
+				if component.Type == "ingredient" {
+					ingredients = append(ingredients, component)
+				}
+			}
+		}
+		expected := []Component{
+			{Type: "ingredient", Name: "flour", Quantity: "500", Unit: "g"},
+			{Type: "ingredient", Name: "flour", Quantity: "100", Unit: "g", Reference: true},
+			{Type: "ingredient", Name: "sugar", Quantity: "some", Reference: true},
+		}
+		if len(ingredients) != len(expected) {
+			t.Fatalf("Expected %d ingredients, got %+v", len(expected), ingredients)
+		}
+		for i, want := range expected {
+			got := ingredients[i]
+			got.Position = want.Position
+			if got != want {
+				t.Errorf("extended=%v, ingredient %d: expected %+v, got %+v", extended, i, want, got)
+			}
+		}
+	}
+}
+
 // TestOptionalIngredient tests parsing of optional ingredients with @? syntax
 func TestOptionalIngredient(t *testing.T) {
 	tests := []struct {
@@ -1431,3 +1524,172 @@ func TestOptionalIngredient(t *testing.T) {
 		})
 	}
 }
+
+// TestExtendedTimerNames tests multi-word timer names in extended mode, and that
+// timers without braces take one word (this used to loop forever)
+func TestExtendedTimerNames(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []Component
+	}{
+		{
+			input: "Roast for ~roast time{4%hours}.",
+			expected: []Component{
+				{Type: "text", Value: "Roast for "},
+				{Type: "timer", Name: "roast time", Quantity: "4", Unit: "hours"},
+				{Type: "text", Value: "."},
+			},
+		},
+		{
+			input: "Let it ~rest.",
+			expected: []Component{
+				{Type: "text", Value: "Let it "},
+				{Type: "timer", Name: "rest"},
+				{Type: "text", Value: "."},
+			},
+		},
+		{
+			input: "Simmer ~gently for a while",
+			expected: []Component{
+				{Type: "text", Value: "Simmer "},
+				{Type: "timer", Name: "gently"},
+				{Type: "text", Value: " for a while"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			p := New()
+			p.ExtendedMode = true
+			recipe, err := p.ParseString(tt.input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(recipe.Steps) != 1 || len(recipe.Steps[0].Components) != len(tt.expected) {
+				t.Fatalf("Expected 1 step with %d components, got %+v", len(tt.expected), recipe.Steps)
+			}
+			for i, expected := range tt.expected {
+				actual := recipe.Steps[0].Components[i]
+				if actual.Type != expected.Type || actual.Name != expected.Name || actual.Value != expected.Value ||
+					actual.Quantity != expected.Quantity || actual.Unit != expected.Unit {
+					t.Errorf("Component %d: expected %+v, got %+v", i, expected, actual)
+				}
+			}
+		})
+	}
+}
+
+func TestApproximateIngredient(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected Component
+	}{
+		{
+			name:     "tilde prefix",
+			input:    "Chop @onion{~2}.",
+			expected: Component{Type: "ingredient", Name: "onion", Quantity: "2", Approximate: true},
+		},
+		{
+			name:     "tilde with fraction and unit",
+			input:    "Add @flour{~ 1/2%cup}.",
+			expected: Component{Type: "ingredient", Name: "flour", Quantity: "0.5", Unit: "cup", Approximate: true},
+		},
+		{
+			name:     "about",
+			input:    "Cook @rice{about 2%cups}.",
+			expected: Component{Type: "ingredient", Name: "rice", Quantity: "2", Unit: "cups", Approximate: true},
+		},
+		{
+			name:     "approx with period",
+			input:    "Add @stock{approx. 500%ml}.",
+			expected: Component{Type: "ingredient", Name: "stock", Quantity: "500", Unit: "ml", Approximate: true},
+		},
+		{
+			name:     "approximate range",
+			input:    "Add @salt{~1-2%tsp}.",
+			expected: Component{Type: "ingredient", Name: "salt", Quantity: "1-2", Unit: "tsp", Approximate: true},
+		},
+		{
+			name:     "fixed and approximate",
+			input:    "Add @yeast{=~1%packet}.",
+			expected: Component{Type: "ingredient", Name: "yeast", Quantity: "1", Unit: "packet", Fixed: true, Approximate: true},
+		},
+		{
+			name:     "exact quantity",
+			input:    "Add @eggs{2}.",
+			expected: Component{Type: "ingredient", Name: "eggs", Quantity: "2"},
+		},
+		{
+			name:     "word alone is not a marker",
+			input:    "Add @spice{about}.",
+			expected: Component{Type: "ingredient", Name: "spice", Quantity: "about"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recipe, err := New().ParseString(tt.input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(recipe.Steps) == 0 || len(recipe.Steps[0].Components) < 2 {
+				t.Fatalf("Expected an ingredient component, got %+v", recipe.Steps)
+			}
+			actual := recipe.Steps[0].Components[1]
+			actual.Position = token.SourcePosition{} // Positions are covered by TestSourcePositions
+			if actual != tt.expected {
+				t.Errorf("expected %+v, got %+v", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestSourcePositions(t *testing.T) {
+	input := "---\ntitle: Soup\n---\nChop @onion{1}(diced)\nand heat in a #pot{}.\n\n> Tastes better tomorrow.\n\nSimmer ~{20%minutes} ü @salt{}.\n"
+	recipe, err := New().ParseString(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []struct {
+		step      int
+		text      string
+		line, col int
+	}{
+		{0, "Chop ", 4, 1},
+		{0, "@onion{1}(diced)", 4, 6},
+		{0, "\nand heat in a ", 4, 22},
+		{0, "#pot{}", 5, 15},
+		{0, ".", 5, 21},
+		{1, "> Tastes better tomorrow.", 7, 1},
+		{2, "Simmer ", 9, 1},
+		{2, "~{20%minutes}", 9, 8},
+		{2, " ü ", 9, 21},
+		{2, "@salt{}", 9, 25},
+		{2, ".", 9, 32},
+	}
+	i := 0
+	for s, step := range recipe.Steps {
+		for _, c := range step.Components {
+			if i >= len(expected) {
+				t.Fatalf("unexpected component %+v", c)
+			}
+			want := expected[i]
+			i++
+			pos := c.Position
+			if s != want.step || input[pos.Offset:pos.EndOffset] != want.text || pos.Line != want.line || pos.Column != want.col {
+				t.Errorf("component %+v: expected %q at %d:%d in step %d, got %q at %s in step %d",
+					c, want.text, want.line, want.col, want.step, input[pos.Offset:pos.EndOffset], pos, s)
+			}
+		}
+	}
+	if i != len(expected) {
+		t.Errorf("expected %d components, got %d", len(expected), i)
+	}
+
+	if pos := recipe.Steps[0].Position; input[pos.Offset:pos.EndOffset] != "Chop @onion{1}(diced)\nand heat in a #pot{}." || pos.String() != "4:1" {
+		t.Errorf("unexpected step position %+v", pos)
+	}
+}
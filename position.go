@@ -0,0 +1,67 @@
+package cooklang
+
+import "github.com/hilli/cooklang/token"
+
+// SourcePosition is the location of a step or component in the recipe source: the
+// line and byte column where it starts and the byte range it covers. Recipes built
+// in code, and components created by scaling or conversion from them, have the zero
+// position (see SourcePosition.IsValid).
+//
+// Example:
+//
+//	recipe, _ := cooklang.ParseString("Boil @water{1%l}.\n\nAdd @pasta{500%g}.")
+//	for _, ingredient := range recipe.GetIngredients().Ingredients {
+//	    fmt.Println(ingredient.Name, ingredient.Position) // water 1:6, pasta 3:5
+//	}
+type SourcePosition = token.SourcePosition
+
+// GetPosition returns where the ingredient is in the recipe source.
+func (i *Ingredient) GetPosition() SourcePosition {
+	return i.Position
+}
+
+// GetPosition returns where the instruction is in the recipe source.
+func (inst *Instruction) GetPosition() SourcePosition {
+	return inst.Position
+}
+
+// GetPosition returns where the timer is in the recipe source.
+func (t *Timer) GetPosition() SourcePosition {
+	return t.Position
+}
+
+// GetPosition returns where the cookware is in the recipe source.
+func (c *Cookware) GetPosition() SourcePosition {
+	return c.Position
+}
+
+// GetPosition returns where the section header is in the recipe source.
+func (s *Section) GetPosition() SourcePosition {
+	return s.Position
+}
+
+// GetPosition returns where the comment is in the recipe source.
+func (cm *Comment) GetPosition() SourcePosition {
+	return cm.Position
+}
+
+// GetPosition returns where the note is in the recipe source.
+func (n *Note) GetPosition() SourcePosition {
+	return n.Position
+}
+
+// GetPosition returns where the recipe reference is in the recipe source.
+func (r *RecipeReference) GetPosition() SourcePosition {
+	return r.Position
+}
+
+// GetPosition returns where the custom component is in the recipe source.
+func (e *Extension) GetPosition() SourcePosition {
+	return e.Position
+}
+
+// GetPosition returns where the temperature is in the recipe source. Temperatures
+// share the position of the text they were found in.
+func (t *Temperature) GetPosition() SourcePosition {
+	return t.Position
+}
@@ -0,0 +1,52 @@
+package cooklang
+
+import "testing"
+
+func TestSourcePositions(t *testing.T) {
+	source := "---\nservings: 2\n---\n== Dough ==\nMix @flour{500%g}\nin a #bowl{}.\n\nBake at 220°C for ~{25%minutes}.\n"
+	recipe, err := ParseString(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type located struct {
+		text      string
+		line, col int
+	}
+	var got []located
+	for step := recipe.FirstStep; step != nil; step = step.NextStep {
+		pos := step.Position
+		got = append(got, located{"step: " + source[pos.Offset:pos.EndOffset], pos.Line, pos.Column})
+		for c := step.FirstComponent; c != nil; c = c.GetNext() {
+			if _, ok := c.(*Instruction); ok {
+				continue
+			}
+			pos := c.GetPosition()
+			got = append(got, located{source[pos.Offset:pos.EndOffset], pos.Line, pos.Column})
+		}
+	}
+
+	expected := []located{
+		{"step: == Dough ==\nMix @flour{500%g}\nin a #bowl{}.", 4, 1},
+		{"== Dough ==", 4, 1},
+		{"@flour{500%g}", 5, 5},
+		{"#bowl{}", 6, 6},
+		{"step: Bake at 220°C for ~{25%minutes}.", 8, 1},
+		// Temperatures share the position of the text they were found in
+		{"Bake at 220°C for ", 8, 1},
+		{"~{25%minutes}", 8, 20},
+	}
+	if len(got) != len(expected) {
+		t.Fatalf("expected %d positions, got %d: %+v", len(expected), len(got), got)
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Errorf("position %d: expected %+v, got %+v", i, expected[i], got[i])
+		}
+	}
+
+	// Recipes built in code have no position
+	if pos := NewIngredient("salt", 1, "tsp").GetPosition(); pos.IsValid() || pos.String() != "-" {
+		t.Errorf("expected no position, got %s", pos)
+	}
+}
@@ -0,0 +1,204 @@
+package cooklang
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+)
+
+// PreferredUnits maps ingredient names to the unit to show them in, overriding the
+// unit ConvertToSystem picks by size: butter always in grams, olive oil always in
+// tablespoons. The unit "count" shows an ingredient as a number of items, without a
+// unit. Names are matched case-insensitively; read a file with LoadPreferredUnits.
+type PreferredUnits map[string]string
+
+// CountUnit is the preferred unit for ingredients counted as items, such as eggs.
+const CountUnit = "count"
+
+// countUnits are the units of counted items, with how many items each one is.
+var countUnits = map[string]float64{
+	"piece": 1, "pieces": 1, "pc": 1, "pcs": 1, "each": 1, "ea": 1, "whole": 1,
+	"dozen": 12, "doz": 12,
+}
+
+// ParsePreferredUnits reads preferred units in YAML, one ingredient per line:
+//
+//	butter: g
+//	eggs: count
+//	olive oil: tbsp
+//
+// Parameters:
+//   - r: The YAML input
+//
+// Returns:
+//   - PreferredUnits: The preferred units, keyed by lowercase ingredient name
+//   - error: An error if the YAML is invalid or a unit is empty
+//
+// Example:
+//
+//	prefs, err := cooklang.ParsePreferredUnits(strings.NewReader("butter: g\neggs: count"))
+func ParsePreferredUnits(r io.Reader) (PreferredUnits, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var raw map[string]string
+	if err := yaml.Unmarshal(content, &raw); err != nil {
+		return nil, fmt.Errorf("failed to read preferred units: %w", err)
+	}
+	prefs := make(PreferredUnits, len(raw))
+	for name, unit := range raw {
+		unit = strings.TrimSpace(unit)
+		if unit == "" {
+			return nil, fmt.Errorf("preferred unit of %s is empty", name)
+		}
+		prefs[strings.ToLower(strings.TrimSpace(name))] = unit
+	}
+	return prefs, nil
+}
+
+// LoadPreferredUnits reads a YAML file of preferred units (see ParsePreferredUnits).
+//
+// Parameters:
+//   - path: The path of the YAML file
+//
+// Returns:
+//   - PreferredUnits: The preferred units
+//   - error: An error naming the file if it cannot be read or parsed
+//
+// Example:
+//
+//	prefs, err := cooklang.LoadPreferredUnits("units.yaml")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	metric := recipe.ConvertToSystem(cooklang.UnitSystemMetric).ApplyPreferredUnits(prefs)
+func LoadPreferredUnits(path string) (PreferredUnits, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	prefs, err := ParsePreferredUnits(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return prefs, nil
+}
+
+// Unit returns the preferred unit of an ingredient, matching its name
+// case-insensitively.
+//
+// Parameters:
+//   - name: The ingredient name
+//
+// Returns:
+//   - string: The preferred unit, or CountUnit
+//   - bool: Whether the ingredient has a preferred unit
+func (p PreferredUnits) Unit(name string) (string, bool) {
+	unit, ok := p[strings.ToLower(strings.TrimSpace(name))]
+	return unit, ok
+}
+
+// ApplyPreferredUnit converts an ingredient to its preferred unit. Ingredients
+// without a preferred unit, without an amount or whose unit does not convert to the
+// preferred one are returned as they are. For CountUnit, pieces and dozens become a
+// number of items.
+//
+// Parameters:
+//   - prefs: The preferred units
+//
+// Returns:
+//   - *Ingredient: The ingredient in its preferred unit, or the ingredient itself
+//
+// Example:
+//
+//	prefs := cooklang.PreferredUnits{"butter": "g"}
+//	butter := cooklang.NewIngredient("butter", 0.25, "kg").ApplyPreferredUnit(prefs)
+//	fmt.Printf("%v %s\n", butter.Quantity, butter.Unit) // "250 g"
+func (i *Ingredient) ApplyPreferredUnit(prefs PreferredUnits) *Ingredient {
+	unit, ok := prefs.Unit(i.Name)
+	if !ok || !i.Quantity.HasAmount() || strings.EqualFold(unit, i.Unit) {
+		return i
+	}
+	if strings.EqualFold(unit, CountUnit) {
+		items, ok := countUnits[strings.ToLower(i.Unit)]
+		if !ok {
+			return i
+		}
+		counted := *i
+		counted.Quantity = i.Quantity.Scale(items)
+		counted.Unit = ""
+		counted.TypedUnit = nil
+		return &counted
+	}
+	if i.Unit == "" {
+		return i
+	}
+	converted, err := i.ConvertTo(unit)
+	if err != nil {
+		return i
+	}
+	return converted
+}
+
+// ApplyPreferredUnits converts every ingredient of the list to its preferred unit
+// (see Ingredient.ApplyPreferredUnit).
+//
+// Parameters:
+//   - prefs: The preferred units
+//
+// Returns:
+//   - *IngredientList: A new list with the converted ingredients
+//
+// Example:
+//
+//	list := recipe.GetIngredients().ConvertToSystem(cooklang.UnitSystemUS)
+//	list = list.ApplyPreferredUnits(prefs)
+func (il *IngredientList) ApplyPreferredUnits(prefs PreferredUnits) *IngredientList {
+	return il.mapIngredients(func(ingredient *Ingredient) *Ingredient {
+		return ingredient.ApplyPreferredUnit(prefs)
+	})
+}
+
+// ApplyPreferredUnits returns a copy of the recipe with every ingredient in its
+// preferred unit (see Ingredient.ApplyPreferredUnit). Apply it after
+// ConvertToSystem, so the preferences win over the units picked by size.
+//
+// Parameters:
+//   - prefs: The preferred units
+//
+// Returns:
+//   - *Recipe: The converted copy; the recipe itself is not changed
+//
+// Example:
+//
+//	us := recipe.ConvertToSystem(cooklang.UnitSystemUS).ApplyPreferredUnits(cooklang.PreferredUnits{"olive oil": "tbsp"})
+func (r *Recipe) ApplyPreferredUnits(prefs PreferredUnits) *Recipe {
+	converted := r.Clone()
+	if len(prefs) == 0 {
+		return converted
+	}
+	for step := converted.FirstStep; step != nil; step = step.NextStep {
+		for component := step.FirstComponent; component != nil; component = component.GetNext() {
+			if ing, ok := component.(*Ingredient); ok {
+				result := ing.ApplyPreferredUnit(prefs)
+				if result == ing {
+					continue
+				}
+				if ing.OriginalQuantity.HasAmount() && ing.Quantity.Min() != 0 {
+					// Keep the original quantity in the same unit as the quantity
+					ing.OriginalQuantity = ing.OriginalQuantity.Scale(result.Quantity.Min() / ing.Quantity.Min())
+				}
+				ing.Quantity = result.Quantity
+				ing.Unit = result.Unit
+				ing.TypedUnit = result.TypedUnit
+			}
+		}
+	}
+	return converted
+}
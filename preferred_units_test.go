@@ -0,0 +1,72 @@
+package cooklang
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParsePreferredUnits(t *testing.T) {
+	prefs, err := ParsePreferredUnits(strings.NewReader("Butter: g\neggs: count\nolive oil: tbsp\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if unit, ok := prefs.Unit("butter"); !ok || unit != "g" {
+		t.Errorf("Unit(butter) = %q, %v, want g", unit, ok)
+	}
+	if unit, ok := prefs.Unit("Olive Oil"); !ok || unit != "tbsp" {
+		t.Errorf("Unit(Olive Oil) = %q, %v, want tbsp", unit, ok)
+	}
+	if _, ok := prefs.Unit("flour"); ok {
+		t.Error("expected no preferred unit for flour")
+	}
+	if _, err := ParsePreferredUnits(strings.NewReader("butter: \"\"\n")); err == nil {
+		t.Error("expected an error for an empty unit")
+	}
+
+	path := filepath.Join(t.TempDir(), "units.yaml")
+	if err := os.WriteFile(path, []byte("butter: [g]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadPreferredUnits(path); err == nil || !strings.Contains(err.Error(), path) {
+		t.Errorf("expected an error naming the file, got %v", err)
+	}
+}
+
+func TestApplyPreferredUnit(t *testing.T) {
+	prefs := PreferredUnits{"butter": "g", "eggs": CountUnit, "olive oil": "tbsp", "flour": "cup"}
+	tests := []struct {
+		ingredient *Ingredient
+		want       string
+	}{
+		{NewIngredient("butter", 0.25, "kg"), "250 g"},
+		{NewIngredient("olive oil", 30, "ml"), "2.03 tbsp"},
+		{NewIngredient("eggs", 1, "dozen"), "12"},
+		{NewIngredient("eggs", 3, ""), "3"},
+		{NewIngredient("flour", 200, "g"), "200 g"}, // Mass does not convert to cups
+		{NewIngredient("salt", 5, "g"), "5 g"},
+	}
+	for _, tt := range tests {
+		got := tt.ingredient.ApplyPreferredUnit(prefs)
+		if display := strings.TrimSpace(got.Quantity.String() + " " + got.Unit); display != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.ingredient.Name, display, tt.want)
+		}
+	}
+}
+
+func TestRecipeApplyPreferredUnits(t *testing.T) {
+	recipe, err := ParseString("Melt @butter{500%g} and fry @eggs{6}.\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Converted to US units, 500 g of butter becomes 1.1 lb; the preference shows ounces
+	us := recipe.ConvertToSystem(UnitSystemUS).ApplyPreferredUnits(PreferredUnits{"butter": "oz"})
+	butter := us.GetIngredients().Ingredients[0]
+	if butter.Unit != "oz" || butter.Quantity.String() != "17.6" {
+		t.Errorf("expected 17.6 oz butter, got %v %s", butter.Quantity, butter.Unit)
+	}
+	if original := recipe.GetIngredients().Ingredients[0]; original.Unit != "g" {
+		t.Errorf("the recipe itself changed to %s", original.Unit)
+	}
+}
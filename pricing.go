@@ -0,0 +1,352 @@
+package cooklang
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+)
+
+// Price is what an ingredient costs: Amount buys Quantity of Unit, e.g. 2.50 for
+// 250 g of butter. Without a unit the price is per item, e.g. 0.30 per egg.
+type Price struct {
+	Name     string  `json:"name"`
+	Amount   float64 `json:"amount"`         // Price of Quantity of Unit
+	Quantity float64 `json:"quantity"`       // How much the price buys (1 if not given)
+	Unit     string  `json:"unit,omitempty"` // Unit of Quantity; empty for a price per item
+}
+
+// PriceList holds ingredient prices, read from a YAML or CSV file with
+// LoadPriceList. Ingredient names are matched case-insensitively.
+type PriceList struct {
+	Currency string  `json:"currency,omitempty"` // ISO 4217 code or symbol, e.g. "EUR" or "€"
+	Prices   []Price `json:"prices"`
+}
+
+// currencySymbols are written before amounts by FormatCurrency instead of the code.
+var currencySymbols = map[string]string{
+	"EUR": "€", "USD": "$", "GBP": "£", "JPY": "¥", "INR": "₹", "CHF": "CHF ",
+}
+
+// priceListYAML is the YAML price list file: prices are "1.20/kg", "2.50/250 g",
+// "0.30" (per item) or {price: 1.20, per: 1 kg}.
+type priceListYAML struct {
+	Currency string                 `yaml:"currency"`
+	Prices   map[string]interface{} `yaml:"prices"`
+}
+
+// ParsePriceListYAML reads a price list in YAML:
+//
+//	currency: EUR
+//	prices:
+//	  flour: 1.20/kg
+//	  butter: 2.50/250 g
+//	  eggs: 0.30
+//	  milk: {price: 1.10, per: 1 l}
+//
+// A price without a unit is per item.
+//
+// Parameters:
+//   - r: The YAML input
+//
+// Returns:
+//   - *PriceList: The prices, sorted by name
+//   - error: An error if the YAML or a price is invalid
+//
+// Example:
+//
+//	prices, err := cooklang.ParsePriceListYAML(strings.NewReader("prices: {flour: 1.20/kg}"))
+func ParsePriceListYAML(r io.Reader) (*PriceList, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var raw priceListYAML
+	if err := yaml.UnmarshalWithOptions(content, &raw, yaml.Strict()); err != nil {
+		return nil, fmt.Errorf("failed to read price list: %w", err)
+	}
+
+	list := &PriceList{Currency: raw.Currency, Prices: []Price{}}
+	for name, value := range raw.Prices {
+		var price Price
+		var err error
+		switch v := value.(type) {
+		case string:
+			price, err = parsePrice(v)
+		case uint64, int64, float64:
+			price, err = parsePrice(fmt.Sprint(v))
+		case map[string]interface{}:
+			spec := fmt.Sprint(v["price"])
+			if per, ok := v["per"]; ok {
+				spec += "/" + fmt.Sprint(per)
+			}
+			price, err = parsePrice(spec)
+		default:
+			err = fmt.Errorf("invalid price %v", value)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("price of %s: %w", name, err)
+		}
+		price.Name = name
+		list.Prices = append(list.Prices, price)
+	}
+	sort.Slice(list.Prices, func(i, j int) bool { return list.Prices[i].Name < list.Prices[j].Name })
+	return list, nil
+}
+
+// parsePrice parses "1.20/kg", "2.50/250 g", "€0.30" or "0.30" into a Price.
+func parsePrice(s string) (Price, error) {
+	amount, per, _ := strings.Cut(s, "/")
+	value, err := strconv.ParseFloat(strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(amount), "$€£¥")), 64)
+	if err != nil || value < 0 {
+		return Price{}, fmt.Errorf("invalid price %q", s)
+	}
+	price := Price{Amount: value, Quantity: 1}
+	per = strings.TrimSpace(per)
+	if per == "" {
+		return price, nil
+	}
+	if unit, err := parseCustomUnitDefinition(per); err == nil {
+		price.Quantity, price.Unit = unit.Amount, unit.Unit
+	} else if !strings.ContainsAny(per[:1], "0123456789.") {
+		price.Unit = per
+	} else {
+		return Price{}, fmt.Errorf("invalid price %q", s)
+	}
+	return price, nil
+}
+
+// ParsePriceListCSV reads a price list CSV with the same columns as a receipt
+// (name, qty, unit, price; see ParseReceiptCSV): each line is the price of qty of
+// unit, and an empty qty means 1. A till receipt therefore works as a price list.
+//
+// Parameters:
+//   - r: The CSV input
+//
+// Returns:
+//   - *PriceList: The prices, in file order and without a currency
+//   - error: Any error encountered while reading or parsing the CSV
+func ParsePriceListCSV(r io.Reader) (*PriceList, error) {
+	receipt, err := ParseReceiptCSV(r)
+	if err != nil {
+		return nil, err
+	}
+	list := &PriceList{Prices: []Price{}}
+	for _, item := range receipt.Items {
+		quantity := float64(item.Quantity)
+		if quantity <= 0 {
+			quantity = 1
+		}
+		list.Prices = append(list.Prices, Price{Name: item.Name, Amount: item.Price, Quantity: quantity, Unit: item.Unit})
+	}
+	return list, nil
+}
+
+// LoadPriceList reads a price list file: CSV for .csv files, YAML otherwise.
+//
+// Parameters:
+//   - path: The path of the price list
+//
+// Returns:
+//   - *PriceList: The prices
+//   - error: An error naming the file if it cannot be read or parsed
+//
+// Example:
+//
+//	prices, err := cooklang.LoadPriceList("prices.yaml")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	estimate := recipe.EstimateCost(prices)
+//	fmt.Println(estimate.Format(estimate.Total))
+func LoadPriceList(path string) (*PriceList, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	var list *PriceList
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		list, err = ParsePriceListCSV(f)
+	} else {
+		list, err = ParsePriceListYAML(f)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return list, nil
+}
+
+// Lookup returns the price of an ingredient, matching its name case-insensitively.
+// When a name is listed more than once, the first price is used.
+//
+// Parameters:
+//   - name: The ingredient name
+//
+// Returns:
+//   - Price: The price
+//   - bool: Whether the ingredient has a price
+func (pl *PriceList) Lookup(name string) (Price, bool) {
+	if pl == nil {
+		return Price{}, false
+	}
+	for _, price := range pl.Prices {
+		if strings.EqualFold(price.Name, name) {
+			return price, true
+		}
+	}
+	return Price{}, false
+}
+
+// Cost returns what an amount of an ingredient costs at this price, converting
+// the ingredient to the price's unit when they differ.
+//
+// Parameters:
+//   - ingredient: The ingredient and the amount used
+//
+// Returns:
+//   - float64: The cost
+//   - bool: False if the ingredient has no amount or its unit cannot be converted
+//     to the price's unit
+func (p Price) Cost(ingredient *Ingredient) (float64, bool) {
+	if ingredient == nil || !ingredient.Quantity.HasAmount() {
+		return 0, false
+	}
+	amount := ingredient.Quantity.Float()
+	if !strings.EqualFold(ingredient.Unit, p.Unit) {
+		if ingredient.Unit == "" || p.Unit == "" {
+			return 0, false
+		}
+		converted, err := ingredient.ConvertTo(p.Unit)
+		if err != nil {
+			return 0, false
+		}
+		amount = converted.Quantity.Float()
+	}
+	quantity := p.Quantity
+	if quantity <= 0 {
+		quantity = 1
+	}
+	return p.Amount * amount / quantity, true
+}
+
+// CostItem is the cost of one ingredient in a CostEstimate.
+type CostItem struct {
+	Ingredient *Ingredient `json:"ingredient"`
+	Price      Price       `json:"price"`
+	Cost       float64     `json:"cost"`
+}
+
+// CostEstimate is what a recipe or shopping list costs at the prices of a
+// PriceList, with the cost of each ingredient.
+type CostEstimate struct {
+	Currency string        `json:"currency,omitempty"`
+	Items    []CostItem    `json:"items"`              // Priced ingredients, in list order
+	Unpriced []*Ingredient `json:"unpriced,omitempty"` // No price, no amount, or a unit that does not convert to the price's
+	Total    float64       `json:"total"`              // Sum of the item costs
+}
+
+// EstimateCost estimates what the recipe costs at the given prices. Ingredients
+// used more than once are added up first, as on a shopping list; ranges are
+// costed at their upper bound.
+//
+// Parameters:
+//   - prices: The ingredient prices
+//
+// Returns:
+//   - *CostEstimate: The total and the cost of each ingredient
+//
+// Example:
+//
+//	prices, _ := cooklang.LoadPriceList("prices.yaml")
+//	estimate := recipe.EstimateCost(prices)
+//	for _, item := range estimate.Items {
+//	    fmt.Printf("%-20s %s\n", item.Ingredient.Name, estimate.Format(item.Cost))
+//	}
+func (r *Recipe) EstimateCost(prices *PriceList) *CostEstimate {
+	list, err := CreateShoppingList(r)
+	if err != nil {
+		return estimateCost(r.GetIngredients().Ingredients, prices)
+	}
+	return list.EstimateCost(prices)
+}
+
+// EstimateCost estimates what the shopping list costs at the given prices. Ranges
+// are costed at their upper bound.
+//
+// Parameters:
+//   - prices: The ingredient prices
+//
+// Returns:
+//   - *CostEstimate: The total and the cost of each ingredient
+func (sl *ShoppingList) EstimateCost(prices *PriceList) *CostEstimate {
+	if sl == nil || sl.Ingredients == nil {
+		return estimateCost(nil, prices)
+	}
+	return estimateCost(sl.Ingredients.Ingredients, prices)
+}
+
+// estimateCost prices each of the ingredients.
+func estimateCost(ingredients []*Ingredient, prices *PriceList) *CostEstimate {
+	estimate := &CostEstimate{Items: []CostItem{}}
+	if prices != nil {
+		estimate.Currency = prices.Currency
+	}
+	for _, ingredient := range ingredients {
+		price, ok := prices.Lookup(ingredient.Name)
+		if !ok {
+			estimate.Unpriced = append(estimate.Unpriced, ingredient)
+			continue
+		}
+		cost, ok := price.Cost(ingredient)
+		if !ok {
+			estimate.Unpriced = append(estimate.Unpriced, ingredient)
+			continue
+		}
+		estimate.Items = append(estimate.Items, CostItem{Ingredient: ingredient, Price: price, Cost: cost})
+		estimate.Total += cost
+	}
+	return estimate
+}
+
+// Format writes an amount in the estimate's currency, see FormatCurrency.
+func (e *CostEstimate) Format(amount float64) string {
+	return FormatCurrency(amount, e.Currency)
+}
+
+// FormatCurrency writes an amount of money with two decimals and its currency:
+// the symbol before the amount for EUR, USD, GBP, JPY, INR, CHF and symbols ("€"),
+// other codes after it ("12.50 DKK"), and no currency when it is empty.
+//
+// Parameters:
+//   - amount: The amount
+//   - currency: An ISO 4217 code, a currency symbol, or ""
+//
+// Returns:
+//   - string: The formatted amount, e.g. "€12.50"
+func FormatCurrency(amount float64, currency string) string {
+	value := strconv.FormatFloat(math.Abs(amount), 'f', 2, 64)
+	sign := ""
+	if amount < 0 && value != "0.00" {
+		sign = "-"
+	}
+	currency = strings.TrimSpace(currency)
+	if symbol, ok := currencySymbols[strings.ToUpper(currency)]; ok {
+		return sign + symbol + value
+	}
+	switch {
+	case currency == "":
+		return sign + value
+	case len(currency) == 3 && strings.ToUpper(currency) == currency:
+		return sign + value + " " + currency
+	default:
+		return sign + currency + value
+	}
+}
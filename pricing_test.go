@@ -0,0 +1,116 @@
+package cooklang
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestParsePriceListYAML(t *testing.T) {
+	prices, err := ParsePriceListYAML(strings.NewReader(`currency: EUR
+prices:
+  flour: 1.20/kg
+  butter: 2.50/250 g
+  eggs: 0.30
+  milk: {price: 1.10, per: 1 l}
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if prices.Currency != "EUR" || len(prices.Prices) != 4 {
+		t.Fatalf("unexpected price list: %+v", prices)
+	}
+	tests := []Price{
+		{Name: "butter", Amount: 2.50, Quantity: 250, Unit: "g"},
+		{Name: "eggs", Amount: 0.30, Quantity: 1},
+		{Name: "flour", Amount: 1.20, Quantity: 1, Unit: "kg"},
+		{Name: "milk", Amount: 1.10, Quantity: 1, Unit: "l"},
+	}
+	for i, want := range tests {
+		if prices.Prices[i] != want {
+			t.Errorf("price %d = %+v, want %+v", i, prices.Prices[i], want)
+		}
+	}
+
+	for name, data := range map[string]string{
+		"bad price":   "prices: {flour: cheap}",
+		"bad unit":    "prices: {flour: 1.20/250}",
+		"unknown key": "currency: EUR\nshop: Aldi\n",
+	} {
+		if _, err := ParsePriceListYAML(strings.NewReader(data)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestParsePriceListCSV(t *testing.T) {
+	prices, err := ParsePriceListCSV(strings.NewReader("name,qty,unit,price\nFlour,1,kg,2.49\nlemon,,,0.50\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if price, ok := prices.Lookup("flour"); !ok || price.Amount != 2.49 || price.Quantity != 1 || price.Unit != "kg" {
+		t.Errorf("flour = %+v, %v", price, ok)
+	}
+	if price, ok := prices.Lookup("Lemon"); !ok || price.Quantity != 1 || price.Unit != "" {
+		t.Errorf("lemon = %+v, %v", price, ok)
+	}
+}
+
+func TestRecipeEstimateCost(t *testing.T) {
+	recipe, err := ParseString("Mix @flour{500%g}, @butter{125%g}, @eggs{2}, more @flour{250%g}, @salt{} and @saffron{1%pinch}.")
+	if err != nil {
+		t.Fatalf("Failed to parse recipe: %v", err)
+	}
+	prices := &PriceList{Currency: "EUR", Prices: []Price{
+		{Name: "Flour", Amount: 1.20, Quantity: 1, Unit: "kg"},
+		{Name: "butter", Amount: 2.50, Quantity: 250, Unit: "g"},
+		{Name: "eggs", Amount: 0.30, Quantity: 1},
+		{Name: "salt", Amount: 0.50, Quantity: 1, Unit: "kg"},
+	}}
+
+	estimate := recipe.EstimateCost(prices)
+	costs := map[string]float64{}
+	for _, item := range estimate.Items {
+		costs[item.Ingredient.Name] = item.Cost
+	}
+	want := map[string]float64{"flour": 0.90, "butter": 1.25, "eggs": 0.60}
+	for name, cost := range want {
+		if math.Abs(costs[name]-cost) > 0.001 {
+			t.Errorf("%s costs %v, want %v", name, costs[name], cost)
+		}
+	}
+	if math.Abs(estimate.Total-2.75) > 0.001 {
+		t.Errorf("total = %v, want 2.75", estimate.Total)
+	}
+	var unpriced []string
+	for _, ingredient := range estimate.Unpriced {
+		unpriced = append(unpriced, ingredient.Name)
+	}
+	if strings.Join(unpriced, ",") != "salt,saffron" {
+		t.Errorf("unpriced = %v, want salt (no amount) and saffron (no price)", unpriced)
+	}
+	if got := estimate.Format(estimate.Total); got != "€2.75" {
+		t.Errorf("Format = %q, want €2.75", got)
+	}
+}
+
+func TestFormatCurrency(t *testing.T) {
+	tests := []struct {
+		amount   float64
+		currency string
+		want     string
+	}{
+		{12.5, "EUR", "€12.50"},
+		{3, "usd", "$3.00"},
+		{12.5, "DKK", "12.50 DKK"},
+		{0.456, "", "0.46"},
+		{1, "kr ", "kr1.00"},
+		{-2, "GBP", "-£2.00"},
+		{-0.001, "", "0.00"},
+	}
+	for _, tt := range tests {
+		if got := FormatCurrency(tt.amount, tt.currency); got != tt.want {
+			t.Errorf("FormatCurrency(%v, %q) = %q, want %q", tt.amount, tt.currency, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package cooklang
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+)
+
+// ConversionProfile chooses how conversions to a unit system round amounts and pick
+// units, for the kind of recipe being converted.
+type ConversionProfile string
+
+const (
+	// ProfilePrecise converts exactly (29.5735 ml/oz) and picks a unit by size, as
+	// ConvertToSystem does.
+	ProfilePrecise ConversionProfile = "precise"
+	// ProfileBartender converts like a bartender (30 ml/oz), with dashes for tiny
+	// amounts, barspoons and friendly fractions, as ConvertToSystemBartender does.
+	ProfileBartender ConversionProfile = "bartender"
+	// ProfileBaking converts exactly but keeps masses in grams or ounces, never
+	// kilograms, rounded to a tenth under 10 and to whole units above, measures a
+	// quarter cup and more in cups, and rounds cups and spoons to fractions such as
+	// 1/3 or 3/4.
+	ProfileBaking ConversionProfile = "baking"
+	// ProfileAuto uses ProfileBartender for cocktail recipes and ProfilePrecise for
+	// everything else (see ProfileForRecipe).
+	ProfileAuto ConversionProfile = "auto"
+)
+
+// conversionProfiles lists the profile names, for error messages.
+var conversionProfiles = []ConversionProfile{ProfilePrecise, ProfileBartender, ProfileBaking, ProfileAuto}
+
+// ParseConversionProfile converts a profile name (precise, bartender, baking, auto)
+// to a ConversionProfile. Matching is case-insensitive, and "" is ProfilePrecise.
+func ParseConversionProfile(name string) (ConversionProfile, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return ProfilePrecise, nil
+	}
+	if profile := ConversionProfile(name); slices.Contains(conversionProfiles, profile) {
+		return profile, nil
+	}
+	names := make([]string, len(conversionProfiles))
+	for i, profile := range conversionProfiles {
+		names[i] = string(profile)
+	}
+	return "", fmt.Errorf("unknown conversion profile: %s (use %s)", name, strings.Join(names, ", "))
+}
+
+// Mode returns the ConversionMode of the profile: BartenderMode for ProfileBartender
+// and PreciseMode for the others.
+func (p ConversionProfile) Mode() ConversionMode {
+	if p == ProfileBartender {
+		return BartenderMode
+	}
+	return PreciseMode
+}
+
+// cocktailWords are tags and course names that mark a cocktail recipe.
+var cocktailWords = []string{"cocktail", "cocktails", "drink", "drinks", "mocktail", "mocktails"}
+
+// ProfileForRecipe returns the conversion profile that suits a recipe:
+// ProfileBartender for cocktails, which are recipes tagged or with a course or
+// category such as "cocktail" or "drinks", or that measure in dashes, splashes,
+// barspoons, jiggers or ponies; ProfilePrecise for everything else.
+//
+// Parameters:
+//   - recipe: The recipe
+//
+// Returns:
+//   - ConversionProfile: ProfileBartender or ProfilePrecise
+//
+// Example:
+//
+//	negroni, _ := cooklang.ParseFile("Negroni.cook") // tags: cocktail
+//	profile := cooklang.ProfileForRecipe(negroni)   // ProfileBartender
+func ProfileForRecipe(recipe *Recipe) ConversionProfile {
+	for _, tag := range recipe.Tags {
+		if slices.Contains(cocktailWords, strings.ToLower(strings.TrimSpace(tag))) {
+			return ProfileBartender
+		}
+	}
+	for _, key := range []string{"course", "category"} {
+		if slices.Contains(cocktailWords, strings.ToLower(strings.TrimSpace(recipe.Metadata[key]))) {
+			return ProfileBartender
+		}
+	}
+	for _, ingredient := range recipe.GetIngredients().Ingredients {
+		if IsCocktailSpecificUnit(ingredient.Unit) {
+			return ProfileBartender
+		}
+	}
+	return ProfilePrecise
+}
+
+// ProfileForRecipes returns ProfileBartender if every recipe is a cocktail (see
+// ProfileForRecipe), and ProfilePrecise otherwise, e.g. for a shopping list.
+func ProfileForRecipes(recipes ...*Recipe) ConversionProfile {
+	if len(recipes) == 0 {
+		return ProfilePrecise
+	}
+	for _, recipe := range recipes {
+		if ProfileForRecipe(recipe) != ProfileBartender {
+			return ProfilePrecise
+		}
+	}
+	return ProfileBartender
+}
+
+// ConvertToSystemWithProfile converts an ingredient to the target unit system with a
+// conversion profile. ProfileAuto cannot tell a cocktail from a single ingredient and
+// converts precisely; resolve it with ProfileForRecipe first.
+//
+// Parameters:
+//   - system: The target unit system (UnitSystemMetric, UnitSystemUS, UnitSystemImperial)
+//   - profile: How to round amounts and pick units
+//
+// Returns:
+//   - *Ingredient: A new ingredient with converted quantity and unit
+//
+// Example:
+//
+//	flour := cooklang.NewIngredient("flour", 1.25, "kg")
+//	baking := flour.ConvertToSystemWithProfile(cooklang.UnitSystemMetric, cooklang.ProfileBaking)
+//	fmt.Printf("%v %s\n", baking.Quantity, baking.Unit) // "1250 g"
+func (i *Ingredient) ConvertToSystemWithProfile(system UnitSystem, profile ConversionProfile) *Ingredient {
+	switch profile {
+	case ProfileBartender:
+		return i.ConvertToSystemBartender(system)
+	case ProfileBaking:
+		return i.convertToSystemBaking(system)
+	default:
+		return i.ConvertToSystem(system)
+	}
+}
+
+// convertToSystemBaking converts precisely, then keeps masses in the system's small
+// unit and rounds amounts the way baking recipes write them.
+func (i *Ingredient) convertToSystemBaking(system UnitSystem) *Ingredient {
+	converted := i.ConvertToSystem(system)
+	if !converted.Quantity.HasAmount() {
+		return converted
+	}
+	switch converted.GetUnitType() {
+	case "mass":
+		if small := canonicalUnits[system]["mass"]; small != "" && converted.Unit != small {
+			if inSmall, err := converted.ConvertTo(small); err == nil {
+				converted = inSmall
+			}
+		}
+		converted.Quantity = converted.Quantity.mapBounds(roundBakingAmount)
+	case "volume":
+		if system == UnitSystemMetric {
+			converted.Quantity = converted.Quantity.mapBounds(roundBakingAmount)
+		} else {
+			// Bakers measure a quarter cup and more in cups, not tablespoons
+			if converted.Unit == "tbsp" && converted.Quantity.Min() >= 4 && system == UnitSystemUS {
+				if inCups, err := converted.ConvertTo("cup"); err == nil {
+					converted = inCups
+				}
+			}
+			converted.Quantity = converted.Quantity.mapBounds(func(v float64) float64 {
+				return RoundToNiceFraction(v, 0.05)
+			})
+		}
+	}
+	return converted
+}
+
+// roundBakingAmount rounds to a tenth under 10 and to whole units above.
+func roundBakingAmount(value float64) float64 {
+	if value < 10 {
+		return math.Round(value*10) / 10
+	}
+	return math.Round(value)
+}
+
+// ConvertToSystemWithProfile converts all ingredients to the target unit system with a
+// conversion profile (see Ingredient.ConvertToSystemWithProfile).
+//
+// Parameters:
+//   - system: The target unit system (UnitSystemMetric, UnitSystemUS, UnitSystemImperial)
+//   - profile: How to round amounts and pick units
+//
+// Returns:
+//   - *IngredientList: A new list with converted ingredients
+//
+// Example:
+//
+//	list, _ := cooklang.CreateShoppingList(negroni, daiquiri)
+//	profile := cooklang.ProfileForRecipes(negroni, daiquiri) // ProfileBartender
+//	usList := list.Ingredients.ConvertToSystemWithProfile(cooklang.UnitSystemUS, profile)
+func (il *IngredientList) ConvertToSystemWithProfile(system UnitSystem, profile ConversionProfile) *IngredientList {
+	return il.mapIngredients(func(ingredient *Ingredient) *Ingredient {
+		return ingredient.ConvertToSystemWithProfile(system, profile)
+	})
+}
@@ -0,0 +1,92 @@
+package cooklang
+
+import (
+	"testing"
+)
+
+func TestParseConversionProfile(t *testing.T) {
+	for name, want := range map[string]ConversionProfile{"": ProfilePrecise, "Bartender": ProfileBartender, " baking ": ProfileBaking, "auto": ProfileAuto} {
+		got, err := ParseConversionProfile(name)
+		if err != nil || got != want {
+			t.Errorf("ParseConversionProfile(%q) = %q, %v, want %q", name, got, err, want)
+		}
+	}
+	if _, err := ParseConversionProfile("chemist"); err == nil {
+		t.Error("expected an error for an unknown profile")
+	}
+	if ProfileBartender.Mode() != BartenderMode || ProfileBaking.Mode() != PreciseMode {
+		t.Error("unexpected conversion modes")
+	}
+}
+
+func TestProfileForRecipe(t *testing.T) {
+	tests := []struct {
+		recipe string
+		want   ConversionProfile
+	}{
+		{"---\ntags: [cocktail, gin]\n---\nStir @gin{50%ml}.\n", ProfileBartender},
+		{"---\ncategory: Cocktails\n---\nStir @gin{50%ml}.\n", ProfileBartender},
+		{"Shake @rum{60%ml} with @bitters{2%dashes}.\n", ProfileBartender},
+		{"---\ntags: [dessert]\n---\nWhisk @cream{200%ml}.\n", ProfilePrecise},
+	}
+	for _, tt := range tests {
+		recipe, err := ParseString(tt.recipe)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := ProfileForRecipe(recipe); got != tt.want {
+			t.Errorf("ProfileForRecipe(%q) = %q, want %q", tt.recipe, got, tt.want)
+		}
+	}
+
+	cocktail, _ := ParseString("---\ntags: cocktail\n---\nStir @gin{50%ml}.\n")
+	cake, _ := ParseString("Whisk @cream{200%ml}.\n")
+	if got := ProfileForRecipes(cocktail, cocktail); got != ProfileBartender {
+		t.Errorf("ProfileForRecipes(cocktails) = %q, want bartender", got)
+	}
+	if got := ProfileForRecipes(cocktail, cake); got != ProfilePrecise {
+		t.Errorf("ProfileForRecipes(cocktail, cake) = %q, want precise", got)
+	}
+}
+
+func TestConvertToSystemWithProfile(t *testing.T) {
+	tests := []struct {
+		ingredient *Ingredient
+		system     UnitSystem
+		profile    ConversionProfile
+		want       string
+	}{
+		{NewIngredient("gin", 45, "ml"), UnitSystemUS, ProfileBartender, "1.5 oz"},
+		{NewIngredient("bitters", 2, "ml"), UnitSystemMetric, ProfileBartender, "2 dash"},
+		{NewIngredient("gin", 45, "ml"), UnitSystemUS, ProfilePrecise, "3.04 tbsp"},
+		{NewIngredient("flour", 1.25, "kg"), UnitSystemMetric, ProfileBaking, "1250 g"},
+		{NewIngredient("yeast", 0.0074, "kg"), UnitSystemMetric, ProfileBaking, "7.4 g"},
+		{NewIngredient("milk", 160, "ml"), UnitSystemUS, ProfileBaking, "2/3 cup"},
+	}
+	for _, tt := range tests {
+		got := tt.ingredient.ConvertToSystemWithProfile(tt.system, tt.profile)
+		if amount := got.Quantity.String() + " " + got.Unit; amount != tt.want {
+			t.Errorf("%s %v %s as %s/%s = %s, want %s", tt.ingredient.Name, tt.ingredient.Quantity, tt.ingredient.Unit, tt.system, tt.profile, amount, tt.want)
+		}
+	}
+}
+
+func TestRecipeConvertToSystemWithAutoProfile(t *testing.T) {
+	recipe, err := ParseString("---\ntags: cocktail\n---\nStir @gin{45%ml} with @bitters{2%ml}.\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	list, err := recipe.GetShoppingListInSystemWithProfile(UnitSystemUS, ProfileAuto)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if list["gin"] != "1.5 oz" || list["bitters"] != "2 dash" {
+		t.Errorf("unexpected bartender shopping list: %v", list)
+	}
+
+	converted := recipe.ConvertToSystemWithProfile(UnitSystemUS, ProfileAuto)
+	gin := converted.GetIngredients().GetIngredientsByName("gin")[0]
+	if gin.Quantity.String() != "1.5" || gin.Unit != "oz" {
+		t.Errorf("gin = %v %s, want 1.5 oz", gin.Quantity, gin.Unit)
+	}
+}
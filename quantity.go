@@ -0,0 +1,560 @@
+package cooklang
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// QuantityStyle selects how a Quantity is formatted as text.
+type QuantityStyle int
+
+const (
+	// QuantityStyleAuto uses decimals for values that terminate within three decimal
+	// places (0.5, 1.25) and fractions for everything else (1/3, 2/3).
+	QuantityStyleAuto QuantityStyle = iota
+	// QuantityStyleDecimal always uses decimals; values that do not terminate within
+	// three places are rounded like FormatQuantity's decimals (0.33, 12.3).
+	QuantityStyleDecimal
+	// QuantityStyleFraction uses (mixed) fractions such as "1/2" and "2 1/3" where
+	// FormatQuantity would (halves, thirds, quarters, ... sixteenths), and decimals
+	// otherwise.
+	QuantityStyleFraction
+)
+
+// maxExactDenominator is the largest denominator tried when recovering an exact
+// fraction from a floating point value (e.g., 0.33333334 → 1/3).
+const maxExactDenominator = 64
+
+// ApproximatePrefix is shown before approximate quantities in display output (e.g., "≈2 cups").
+const ApproximatePrefix = "≈"
+
+// rational is an exact fraction num/den kept in lowest terms with den > 0.
+// The zero value represents 0, and 0 is always stored as the zero value so that
+// equal rationals compare equal with ==.
+type rational struct {
+	num, den int64
+}
+
+// maxRationalDenominator bounds the denominators produced by arithmetic. Results
+// that would need a larger denominator (or overflow int64) are rounded to the
+// nearest fraction with a small denominator or to nine decimal places.
+const maxRationalDenominator = 1_000_000_000
+
+func newRational(num, den int64) rational {
+	if den == 0 || num == 0 {
+		return rational{}
+	}
+	if den < 0 {
+		num, den = -num, -den
+	}
+	if g := gcd(abs64(num), den); g > 1 {
+		num, den = num/g, den/g
+	}
+	return rational{num: num, den: den}
+}
+
+func (r rational) normalized() rational {
+	if r.den == 0 {
+		return rational{num: 0, den: 1}
+	}
+	return r
+}
+
+func (r rational) add(o rational) rational {
+	return ratFromBig(new(big.Rat).Add(r.big(), o.big()))
+}
+
+func (r rational) mul(o rational) rational {
+	return ratFromBig(new(big.Rat).Mul(r.big(), o.big()))
+}
+
+func (r rational) big() *big.Rat {
+	r = r.normalized()
+	return big.NewRat(r.num, r.den)
+}
+
+// ratFromBig converts an exact result back to a rational, rounding it when it
+// does not fit (see maxRationalDenominator).
+func ratFromBig(b *big.Rat) rational {
+	if b.Num().IsInt64() && b.Denom().IsInt64() && b.Denom().Int64() <= maxRationalDenominator {
+		return newRational(b.Num().Int64(), b.Denom().Int64())
+	}
+	f, _ := b.Float64()
+	return ratFromFloat(f, 64)
+}
+
+func (r rational) float() float64 {
+	r = r.normalized()
+	return float64(r.num) / float64(r.den)
+}
+
+// terminates reports whether the fraction has an exact decimal form with at most three places.
+func (r rational) terminates() bool {
+	r = r.normalized()
+	return 1000%r.den == 0
+}
+
+// ratFromFloat recovers an exact fraction from a float, preferring small denominators.
+// precision is the bit size used for the decimal fallback (32 or 64).
+func ratFromFloat(v float64, precision int) rational {
+	if v == math.Trunc(v) && math.Abs(v) < 1<<53 {
+		return newRational(int64(v), 1)
+	}
+	tolerance := 1e-7 * math.Max(1, math.Abs(v))
+	for den := int64(2); den <= maxExactDenominator; den++ {
+		num := math.Round(v * float64(den))
+		if math.Abs(v-num/float64(den)) <= tolerance {
+			return newRational(int64(num), den)
+		}
+	}
+	r, err := ratFromDecimal(strconv.FormatFloat(v, 'f', -1, precision))
+	if err != nil {
+		// Only possible for values too large or precise for int64; keep three decimals
+		return newRational(int64(math.Round(v*1000)), 1000)
+	}
+	return r
+}
+
+// ratFromDecimal parses a plain decimal string such as "1.125" exactly.
+func ratFromDecimal(s string) (rational, error) {
+	s = strings.TrimSpace(s)
+	negative := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+	intPart, fracPart, _ := strings.Cut(s, ".")
+	if len(fracPart) > 9 {
+		fracPart = fracPart[:9]
+	}
+	if intPart == "" {
+		intPart = "0"
+	}
+	digits, err := strconv.ParseInt(intPart+fracPart, 10, 64)
+	if err != nil {
+		return rational{}, fmt.Errorf("invalid decimal: %q", s)
+	}
+	den := int64(1)
+	for range fracPart {
+		den *= 10
+	}
+	if negative {
+		digits = -digits
+	}
+	return newRational(digits, den), nil
+}
+
+func gcd(a, b int64) int64 {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+func abs64(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// Quantity is an exact recipe amount, as used by Ingredient.Quantity. It can hold a
+// single value stored as a fraction (so 1/3 stays 1/3 instead of becoming
+// 0.33333334), a range such as "2-3", or the unspecified amount "some". The zero
+// value is a quantity of 0. Quantities can be compared with == and Equal.
+//
+// Example:
+//
+//	q, _ := cooklang.ParseQuantity("1/3")
+//	fmt.Println(q.Scale(2))                              // "2/3"
+//	fmt.Println(q.Add(cooklang.NewQuantity(1, 1)))       // "1 1/3"
+//	fmt.Println(q.Format(cooklang.QuantityStyleDecimal)) // "0.33"
+type Quantity struct {
+	low, high rational
+	isRange   bool
+	isSome    bool
+}
+
+// NewQuantity creates an exact quantity num/den. A zero denominator yields a quantity of 0.
+//
+// Example:
+//
+//	half := cooklang.NewQuantity(1, 2)
+func NewQuantity(num, den int64) Quantity {
+	r := newRational(num, den)
+	return Quantity{low: r, high: r}
+}
+
+// NewRangeQuantity creates a range quantity from its lower and upper bounds.
+// If either bound is itself a range, its outermost value is used.
+//
+// Example:
+//
+//	q := cooklang.NewRangeQuantity(cooklang.NewQuantity(2, 1), cooklang.NewQuantity(3, 1)) // "2-3"
+func NewRangeQuantity(low, high Quantity) Quantity {
+	if low.high.float() > high.low.float() {
+		low, high = high, low
+	}
+	q := Quantity{low: low.low, high: high.high}
+	q.isRange = q.low.normalized() != q.high.normalized()
+	return q
+}
+
+// SomeQuantity returns the unspecified amount ("some"), as in @salt{}.
+func SomeQuantity() Quantity {
+	return Quantity{isSome: true}
+}
+
+// QuantityFromFloat converts a float to a Quantity, recovering simple fractions
+// (0.3333333 → 1/3). Negative values yield "some", matching the -1 that Min
+// returns for "some".
+func QuantityFromFloat(v float64) Quantity {
+	if v < 0 {
+		return SomeQuantity()
+	}
+	r := ratFromFloat(v, 64)
+	return Quantity{low: r, high: r}
+}
+
+// quantityFromFloat32 is QuantityFromFloat for float32 values, whose decimal
+// fallback uses the shortest float32 representation.
+func quantityFromFloat32(v float32) Quantity {
+	if v < 0 {
+		return SomeQuantity()
+	}
+	r := ratFromFloat(float64(v), 32)
+	return Quantity{low: r, high: r}
+}
+
+// ParseQuantity parses a quantity string. Supported forms are integers ("2"),
+// decimals ("0.5"), fractions ("1/3"), mixed numbers ("2 1/2"), Unicode fractions
+// ("½", "1½"), ranges of any of these ("2-3", "1/2-1", "2 to 3"), and "some" or the empty
+// string for an unspecified amount.
+//
+// Parameters:
+//   - s: The quantity text
+//
+// Returns:
+//   - Quantity: The exact parsed quantity
+//   - error: An error if the text is not a quantity
+//
+// Example:
+//
+//	q, err := cooklang.ParseQuantity("2 1/2")
+func ParseQuantity(s string) (Quantity, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || strings.EqualFold(s, "some") {
+		return SomeQuantity(), nil
+	}
+
+	if lowStr, highStr, ok := cutRange(s); ok {
+		low, err := parseRational(lowStr)
+		if err != nil {
+			return Quantity{}, err
+		}
+		high, err := parseRational(highStr)
+		if err != nil {
+			return Quantity{}, err
+		}
+		return NewRangeQuantity(Quantity{low: low, high: low}, Quantity{low: high, high: high}), nil
+	}
+
+	r, err := parseRational(s)
+	if err != nil {
+		return Quantity{}, err
+	}
+	return Quantity{low: r, high: r}, nil
+}
+
+// cutRange splits a range quantity written as "low-high" or "low to high".
+func cutRange(s string) (low, high string, ok bool) {
+	if low, high, ok = strings.Cut(s, "-"); ok && strings.TrimSpace(low) != "" {
+		return low, high, true
+	}
+	fields := strings.Fields(s)
+	for idx, field := range fields {
+		if strings.EqualFold(field, "to") && idx > 0 && idx < len(fields)-1 {
+			return strings.Join(fields[:idx], " "), strings.Join(fields[idx+1:], " "), true
+		}
+	}
+	return "", "", false
+}
+
+// unicodeFractions maps Unicode vulgar fractions to their values.
+var unicodeFractions = map[rune]rational{
+	'½': {1, 2}, '⅓': {1, 3}, '⅔': {2, 3}, '¼': {1, 4}, '¾': {3, 4},
+	'⅕': {1, 5}, '⅖': {2, 5}, '⅗': {3, 5}, '⅘': {4, 5}, '⅙': {1, 6},
+	'⅚': {5, 6}, '⅛': {1, 8}, '⅜': {3, 8}, '⅝': {5, 8}, '⅞': {7, 8},
+}
+
+// parseRational parses a single (non-range) numeric quantity exactly.
+func parseRational(s string) (rational, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return rational{}, fmt.Errorf("empty quantity")
+	}
+
+	// Unicode fraction, optionally preceded by a whole number ("1½")
+	runes := []rune(s)
+	if frac, ok := unicodeFractions[runes[len(runes)-1]]; ok {
+		whole := strings.TrimSpace(string(runes[:len(runes)-1]))
+		result := frac
+		if whole != "" {
+			w, err := strconv.ParseInt(whole, 10, 64)
+			if err != nil {
+				return rational{}, fmt.Errorf("invalid quantity: %q", s)
+			}
+			result = result.add(rational{num: w, den: 1})
+		}
+		return result, nil
+	}
+
+	// Fraction, optionally preceded by a whole number ("2 1/2")
+	if numStr, denStr, ok := strings.Cut(s, "/"); ok {
+		var whole int64
+		numStr = strings.TrimSpace(numStr)
+		if fields := strings.Fields(numStr); len(fields) == 2 {
+			w, err := strconv.ParseInt(fields[0], 10, 64)
+			if err != nil {
+				return rational{}, fmt.Errorf("invalid quantity: %q", s)
+			}
+			whole = w
+			numStr = fields[1]
+		}
+		num, err := strconv.ParseInt(numStr, 10, 64)
+		if err != nil {
+			return rational{}, fmt.Errorf("invalid quantity: %q", s)
+		}
+		den, err := strconv.ParseInt(strings.TrimSpace(denStr), 10, 64)
+		if err != nil || den == 0 {
+			return rational{}, fmt.Errorf("invalid quantity: %q", s)
+		}
+		return newRational(num, den).add(rational{num: whole, den: 1}), nil
+	}
+
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsInf(f, 0) || math.IsNaN(f) {
+		return rational{}, fmt.Errorf("invalid quantity: %q", s)
+	}
+	// Decimals longer than ratFromDecimal keeps are float renderings of fractions,
+	// e.g. "0.3333333333333333" from the parser's evaluated "1/3"
+	if _, frac, _ := strings.Cut(s, "."); len(frac) <= 9 {
+		if r, err := ratFromDecimal(s); err == nil {
+			return r, nil
+		}
+	}
+	return ratFromFloat(f, 64), nil // Exponents such as "1e3"
+}
+
+// IsSome reports whether the quantity is the unspecified amount "some".
+func (q Quantity) IsSome() bool {
+	return q.isSome
+}
+
+// IsRange reports whether the quantity is a range such as "2-3".
+func (q Quantity) IsRange() bool {
+	return q.isRange && !q.isSome
+}
+
+// IsZero reports whether the quantity is exactly 0.
+func (q Quantity) IsZero() bool {
+	return !q.isSome && q.high.normalized().num == 0
+}
+
+// Min returns the lower bound of the quantity (the value itself for non-ranges).
+// It returns -1 for "some".
+func (q Quantity) Min() float64 {
+	if q.isSome {
+		return -1
+	}
+	return q.low.float()
+}
+
+// Max returns the upper bound of the quantity (the value itself for non-ranges).
+// It returns -1 for "some".
+func (q Quantity) Max() float64 {
+	if q.isSome {
+		return -1
+	}
+	return q.high.float()
+}
+
+// Float returns the quantity as a float. Ranges return their upper bound so that
+// shopping quantities are never short, and "some" returns -1.
+func (q Quantity) Float() float64 {
+	return q.Max()
+}
+
+// Scale multiplies the quantity by a factor. Simple factors such as 1.5 or 1/3 are
+// applied exactly. "some" is returned unchanged.
+//
+// Example:
+//
+//	q := cooklang.NewQuantity(1, 3).Scale(1.5) // 1/2
+func (q Quantity) Scale(factor float64) Quantity {
+	if q.isSome {
+		return q
+	}
+	f := ratFromFloat(factor, 64)
+	return Quantity{low: q.low.mul(f), high: q.high.mul(f), isRange: q.isRange}
+}
+
+// Add returns the sum of two quantities. Ranges add bound by bound.
+// Adding "some" leaves the other quantity unchanged; "some" plus "some" is "some".
+//
+// Example:
+//
+//	total := cooklang.NewQuantity(1, 3).Add(cooklang.NewQuantity(1, 6)) // 1/2
+func (q Quantity) Add(other Quantity) Quantity {
+	switch {
+	case q.isSome:
+		return other
+	case other.isSome:
+		return q
+	}
+	return Quantity{
+		low:     q.low.add(other.low),
+		high:    q.high.add(other.high),
+		isRange: q.isRange || other.isRange,
+	}
+}
+
+// Format renders the quantity as text in the given style.
+// Ranges are formatted as "low-high" and "some" is formatted as "some".
+//
+// Example:
+//
+//	q, _ := cooklang.ParseQuantity("2.5")
+//	q.Format(cooklang.QuantityStyleFraction) // "2 1/2"
+//	q.Format(cooklang.QuantityStyleDecimal)  // "2.5"
+func (q Quantity) Format(style QuantityStyle) string {
+	if q.isSome {
+		return "some"
+	}
+	if q.IsRange() {
+		return formatRational(q.low, style) + "-" + formatRational(q.high, style)
+	}
+	return formatRational(q.low, style)
+}
+
+// String formats the quantity with QuantityStyleAuto.
+func (q Quantity) String() string {
+	return q.Format(QuantityStyleAuto)
+}
+
+// Equal reports whether two quantities are the same amount, range or "some".
+func (q Quantity) Equal(other Quantity) bool {
+	return q.isSome == other.isSome && q.IsRange() == other.IsRange() &&
+		q.low.normalized() == other.low.normalized() && q.high.normalized() == other.high.normalized()
+}
+
+// HasAmount reports whether the quantity is a positive amount, as opposed to 0 or
+// "some". Ranges have an amount when their lower bound is positive.
+func (q Quantity) HasAmount() bool {
+	return !q.isSome && q.low.normalized().num > 0
+}
+
+// mapBounds applies f to both bounds of the quantity, e.g. to convert it to
+// another unit. "some" is returned unchanged.
+func (q Quantity) mapBounds(f func(float64) float64) Quantity {
+	if q.isSome {
+		return q
+	}
+	if !q.IsRange() {
+		return QuantityFromFloat(f(q.low.float()))
+	}
+	return NewRangeQuantity(QuantityFromFloat(f(q.low.float())), QuantityFromFloat(f(q.high.float())))
+}
+
+// rescaledTo returns the quantity with its lower bound replaced by low, keeping the
+// ratio between the bounds of a range. It is used when a conversion only computes
+// the lower bound, e.g. bartender unit selection.
+func (q Quantity) rescaledTo(low float64) Quantity {
+	if !q.IsRange() || q.Min() <= 0 {
+		return QuantityFromFloat(low)
+	}
+	return NewRangeQuantity(QuantityFromFloat(low), QuantityFromFloat(q.Max()*low/q.Min()))
+}
+
+// MarshalText implements encoding.TextMarshaler using QuantityStyleAuto, so
+// quantities are written to JSON as exact strings such as "1/3" or "1-2".
+func (q Quantity) MarshalText() ([]byte, error) {
+	return []byte(q.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler using ParseQuantity.
+func (q *Quantity) UnmarshalText(text []byte) error {
+	parsed, err := ParseQuantity(string(text))
+	if err != nil {
+		return err
+	}
+	*q = parsed
+	return nil
+}
+
+// UnmarshalJSON reads a quantity written as a string ("1/3", "1-2", "some") or,
+// as in older documents, as a number where -1 means "some".
+func (q *Quantity) UnmarshalJSON(data []byte) error {
+	var number float64
+	if err := json.Unmarshal(data, &number); err == nil {
+		*q = QuantityFromFloat(number)
+		return nil
+	}
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return fmt.Errorf("invalid quantity %s", data)
+	}
+	return q.UnmarshalText([]byte(text))
+}
+
+// formatRational formats a single exact value in the given style.
+func formatRational(r rational, style QuantityStyle) string {
+	r = r.normalized()
+	if r.den == 1 {
+		return strconv.FormatInt(r.num, 10)
+	}
+
+	useFraction := false
+	switch style {
+	case QuantityStyleAuto:
+		useFraction = !r.terminates() && r.den <= maxExactDenominator
+	case QuantityStyleFraction:
+		useFraction = slices.Contains(fractionDenominators, int(r.den)) && r.den <= DefaultMaxDenominator
+	}
+
+	switch {
+	case useFraction:
+		// Written like FormatQuantity, but from the exact fraction
+		sign, num := "", r.num
+		if num < 0 {
+			sign, num = "-", -num
+		}
+		return sign + mixedNumber(num/r.den, num%r.den, r.den, false)
+	case r.terminates():
+		return strconv.FormatFloat(r.float(), 'f', -1, 64)
+	case r.float() < 0:
+		return "-" + formatDecimal(-r.float())
+	default:
+		return formatDecimal(r.float()) // Rounded like FormatQuantity's decimal fallback
+	}
+}
+
+// displayQuantity formats the quantity with human-friendly fractions, including both
+// bounds for ranges (e.g., "1/2-1"). Approximate quantities are prefixed with "≈".
+func (i Ingredient) displayQuantity() string {
+	qty := i.Quantity.Format(QuantityStyleFraction)
+	if i.Approximate {
+		qty = ApproximatePrefix + qty
+	}
+	return qty
+}
+
+// formatMapQuantity formats a quantity for IngredientList.ToMap.
+func formatMapQuantity(quantity float64) string {
+	if quantity == math.Trunc(quantity) {
+		return fmt.Sprintf("%.0f", quantity)
+	}
+	return fmt.Sprintf("%.1f", quantity)
+}
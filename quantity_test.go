@@ -0,0 +1,274 @@
+package cooklang
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseQuantity(t *testing.T) {
+	tests := []struct {
+		input    string
+		auto     string
+		fraction string
+		some     bool
+		isRange  bool
+	}{
+		{"2", "2", "2", false, false},
+		{"0.5", "0.5", "1/2", false, false},
+		{"1/3", "1/3", "1/3", false, false},
+		{"2 1/2", "2.5", "2 1/2", false, false},
+		{"1½", "1.5", "1 1/2", false, false},
+		{"⅔", "2/3", "2/3", false, false},
+		{"2-3", "2-3", "2-3", false, true},
+		{"1/2-1", "0.5-1", "1/2-1", false, true},
+		{"some", "some", "some", true, false},
+		{"", "some", "some", true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			q, err := ParseQuantity(tt.input)
+			if err != nil {
+				t.Fatalf("ParseQuantity(%q) error: %v", tt.input, err)
+			}
+			if got := q.Format(QuantityStyleAuto); got != tt.auto {
+				t.Errorf("auto format = %q, want %q", got, tt.auto)
+			}
+			if got := q.Format(QuantityStyleFraction); got != tt.fraction {
+				t.Errorf("fraction format = %q, want %q", got, tt.fraction)
+			}
+			if q.IsSome() != tt.some {
+				t.Errorf("IsSome() = %v, want %v", q.IsSome(), tt.some)
+			}
+			if q.IsRange() != tt.isRange {
+				t.Errorf("IsRange() = %v, want %v", q.IsRange(), tt.isRange)
+			}
+		})
+	}
+}
+
+func TestParseQuantityErrors(t *testing.T) {
+	for _, input := range []string{"lots", "1/0", "2-", "a-b"} {
+		if _, err := ParseQuantity(input); err == nil {
+			t.Errorf("ParseQuantity(%q) expected error", input)
+		}
+	}
+}
+
+func TestQuantityArithmetic(t *testing.T) {
+	third := NewQuantity(1, 3)
+
+	if got := third.Scale(2).String(); got != "2/3" {
+		t.Errorf("1/3 * 2 = %q, want 2/3", got)
+	}
+	if got := third.Scale(1.5).String(); got != "0.5" {
+		t.Errorf("1/3 * 1.5 = %q, want 0.5", got)
+	}
+	if got := third.Add(NewQuantity(1, 6)).Format(QuantityStyleFraction); got != "1/2" {
+		t.Errorf("1/3 + 1/6 = %q, want 1/2", got)
+	}
+	if got := third.Add(SomeQuantity()).String(); got != "1/3" {
+		t.Errorf("1/3 + some = %q, want 1/3", got)
+	}
+
+	r, _ := ParseQuantity("1-2")
+	if got := r.Scale(1.5).Format(QuantityStyleFraction); got != "1 1/2-3" {
+		t.Errorf("(1-2) * 1.5 = %q, want 1 1/2-3", got)
+	}
+	if r.Float() != 2 || r.Min() != 1 {
+		t.Errorf("range bounds = %v-%v, want 1-2", r.Min(), r.Max())
+	}
+	if got := SomeQuantity().Scale(3); !got.IsSome() {
+		t.Errorf("some * 3 = %v, want some", got)
+	}
+}
+
+func TestQuantityFromFloat(t *testing.T) {
+	tests := []struct {
+		input float64
+		want  string
+	}{
+		{float64(float32(1.0 / 3.0)), "1/3"},
+		{0.125, "0.125"},
+		{1234.5678, "1234.6"}, // Rounded like FormatQuantity
+		{-1, "some"},
+	}
+	for _, tt := range tests {
+		if got := QuantityFromFloat(tt.input).String(); got != tt.want {
+			t.Errorf("QuantityFromFloat(%v) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestIngredientQuantity(t *testing.T) {
+	recipe, err := ParseString("Add @sugar{1/3%cup} and @salt{}.")
+	if err != nil {
+		t.Fatalf("failed to parse recipe: %v", err)
+	}
+	ingredients := recipe.GetIngredients().Ingredients
+
+	sugar := findIngredient(ingredients, "sugar")
+	if sugar == nil {
+		t.Fatal("sugar not found")
+	}
+	if got := sugar.Quantity.String(); got != "1/3" {
+		t.Errorf("sugar quantity = %q, want 1/3", got)
+	}
+	if got := sugar.Render(); !strings.Contains(got, "{1/3%cup}") {
+		t.Errorf("sugar render = %q, want it to contain {1/3%%cup}", got)
+	}
+
+	salt := findIngredient(ingredients, "salt")
+	if salt == nil || !salt.Quantity.IsSome() {
+		t.Errorf("expected salt quantity to be some, got %+v", salt)
+	}
+
+	if got := sugar.Quantity.Scale(3); !got.Equal(NewQuantity(1, 1)) {
+		t.Errorf("sugar scaled by 3 = %v, want exactly 1", got)
+	}
+}
+
+func TestRangeIngredients(t *testing.T) {
+	recipe, err := ParseString(`---
+servings: 2
+---
+Season with @salt{1-2%tsp} and add @eggs{2 to 3}.
+
+Finish with @salt{1%tsp} and @sugar{1/2-1%cup}.`)
+	if err != nil {
+		t.Fatalf("failed to parse recipe: %v", err)
+	}
+	ingredients := recipe.GetIngredients().Ingredients
+
+	salt := findIngredient(ingredients, "salt")
+	if salt == nil {
+		t.Fatal("salt not found")
+	}
+	if got := salt.Quantity.String(); got != "1-2" {
+		t.Errorf("salt range = %v, want 1-2", got)
+	}
+	if got := salt.Render(); got != "@salt{1-2%tsp}" {
+		t.Errorf("salt render = %q, want @salt{1-2%%tsp}", got)
+	}
+	if got := salt.RenderDisplay(); got != "1-2 tsp salt" {
+		t.Errorf("salt display = %q, want \"1-2 tsp salt\"", got)
+	}
+
+	eggs := findIngredient(ingredients, "eggs")
+	if eggs == nil || eggs.Quantity.String() != "2-3" {
+		t.Errorf("eggs amount = %v, want 2-3", eggs)
+	}
+
+	sugar := findIngredient(ingredients, "sugar")
+	if sugar == nil || sugar.Quantity.Format(QuantityStyleFraction) != "1/2-1" {
+		t.Errorf("sugar amount = %v, want 1/2-1", sugar)
+	}
+
+	t.Run("scaling scales both bounds", func(t *testing.T) {
+		scaled := recipe.Scale(2)
+		salt := findIngredient(scaled.GetIngredients().Ingredients, "salt")
+		if salt == nil || salt.Quantity.String() != "2-4" {
+			t.Errorf("scaled salt = %+v, want 2-4", salt)
+		}
+	})
+
+	t.Run("summing with a range produces a range", func(t *testing.T) {
+		list, err := CreateShoppingList(recipe)
+		if err != nil {
+			t.Fatalf("failed to create shopping list: %v", err)
+		}
+		salt := findIngredient(list.Ingredients.Ingredients, "salt")
+		if salt == nil || salt.Quantity.String() != "2-3" {
+			t.Errorf("consolidated salt = %+v, want 2-3", salt)
+		}
+		if got := list.ToMap()["salt"]; got != "2-3 tsp" {
+			t.Errorf("shopping list salt = %q, want \"2-3 tsp\"", got)
+		}
+	})
+
+	t.Run("conversion keeps the range", func(t *testing.T) {
+		converted, err := sugar.ConvertTo("ml")
+		if err != nil {
+			t.Fatalf("ConvertTo error: %v", err)
+		}
+		if !floatClose(converted.Quantity.Max(), 236.588, 0.01) {
+			t.Errorf("converted upper bound = %v, want ~236.6", converted.Quantity.Max())
+		}
+	})
+}
+
+func TestApproximateIngredients(t *testing.T) {
+	recipe, err := ParseString(`Chop @onion{~2} and cook @rice{about 1/2%cup}.
+
+Add @rice{1%cup} and @salt{~1-2%tsp}.`)
+	if err != nil {
+		t.Fatalf("failed to parse recipe: %v", err)
+	}
+	ingredients := recipe.GetIngredients().Ingredients
+
+	onion := findIngredient(ingredients, "onion")
+	if onion == nil || !onion.Approximate || !onion.Quantity.Equal(NewQuantity(2, 1)) {
+		t.Fatalf("onion = %+v, want approximate 2", onion)
+	}
+	if got := onion.Render(); got != "@onion{~2%}" {
+		t.Errorf("onion render = %q, want @onion{~2%%}", got)
+	}
+	if got := onion.RenderDisplay(); got != "≈2 onion" {
+		t.Errorf("onion display = %q, want \"≈2 onion\"", got)
+	}
+
+	salt := findIngredient(ingredients, "salt")
+	if salt == nil || !salt.Approximate || salt.Quantity.String() != "1-2" {
+		t.Fatalf("salt = %+v, want approximate 1-2", salt)
+	}
+	if got := salt.RenderDisplay(); got != "≈1-2 tsp salt" {
+		t.Errorf("salt display = %q, want \"≈1-2 tsp salt\"", got)
+	}
+
+	t.Run("round trip", func(t *testing.T) {
+		reparsed, err := ParseString(salt.Render())
+		if err != nil {
+			t.Fatalf("failed to reparse %q: %v", salt.Render(), err)
+		}
+		again := findIngredient(reparsed.GetIngredients().Ingredients, "salt")
+		if again == nil || !again.Approximate || again.Quantity.Max() != 2 {
+			t.Errorf("reparsed salt = %+v, want approximate 1-2", again)
+		}
+	})
+
+	t.Run("scaling stays approximate", func(t *testing.T) {
+		scaled := recipe.Scale(2)
+		onion := findIngredient(scaled.GetIngredients().Ingredients, "onion")
+		if onion == nil || !onion.Approximate || !onion.Quantity.Equal(NewQuantity(4, 1)) {
+			t.Errorf("scaled onion = %+v, want approximate 4", onion)
+		}
+	})
+
+	t.Run("conversion stays approximate", func(t *testing.T) {
+		rice := findIngredient(ingredients, "rice")
+		converted, err := rice.ConvertTo("ml")
+		if err != nil {
+			t.Fatalf("ConvertTo error: %v", err)
+		}
+		if !converted.Approximate {
+			t.Errorf("converted rice = %+v, want approximate", converted)
+		}
+	})
+
+	t.Run("summing with an estimate is an estimate", func(t *testing.T) {
+		list, err := CreateShoppingList(recipe)
+		if err != nil {
+			t.Fatalf("failed to create shopping list: %v", err)
+		}
+		rice := findIngredient(list.Ingredients.Ingredients, "rice")
+		if rice == nil || !rice.Approximate || !rice.Quantity.Equal(NewQuantity(3, 2)) {
+			t.Errorf("consolidated rice = %+v, want approximate 1.5", rice)
+		}
+		if got := list.ToMap()["rice"]; got != "≈1.5 cup" {
+			t.Errorf("shopping list rice = %q, want \"≈1.5 cup\"", got)
+		}
+		if got := list.Scale(2).Ingredients.ToMap()["rice"]; got != "≈3 cup" {
+			t.Errorf("scaled shopping list rice = %q, want \"≈3 cup\"", got)
+		}
+	})
+}
@@ -0,0 +1,332 @@
+package cooklang
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ReceiptItem is a single purchased line from a till receipt.
+type ReceiptItem struct {
+	Name     string  `json:"name"`
+	Quantity float32 `json:"quantity,omitempty"` // Purchased amount (0 if not recorded)
+	Unit     string  `json:"unit,omitempty"`     // Unit of the purchased amount
+	Price    float64 `json:"price,omitempty"`    // Price paid for this line
+}
+
+// Receipt is a list of purchased items, typically imported from a CSV export.
+type Receipt struct {
+	Items []ReceiptItem `json:"items"`
+}
+
+// Total returns the sum of all item prices on the receipt.
+func (r *Receipt) Total() float64 {
+	var total float64
+	for _, item := range r.Items {
+		total += item.Price
+	}
+	return total
+}
+
+// ParseReceiptCSV reads a purchased-items CSV with the columns name, qty, unit, price.
+// A header row is detected and skipped when its quantity column is not numeric.
+// Empty quantity or price cells are treated as zero.
+//
+// Parameters:
+//   - r: The CSV input
+//
+// Returns:
+//   - *Receipt: The parsed receipt
+//   - error: Any error encountered while reading or parsing the CSV
+//
+// Example:
+//
+//	f, _ := os.Open("receipt.csv")
+//	defer f.Close()
+//	receipt, err := cooklang.ParseReceiptCSV(f)
+func ParseReceiptCSV(r io.Reader) (*Receipt, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read receipt CSV: %w", err)
+	}
+
+	receipt := &Receipt{Items: []ReceiptItem{}}
+	for i, record := range records {
+		if len(record) == 0 || strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+		if len(record) < 4 {
+			return nil, fmt.Errorf("receipt line %d: expected 4 columns (name, qty, unit, price), got %d", i+1, len(record))
+		}
+
+		qtyStr := strings.TrimSpace(record[1])
+		priceStr := strings.TrimSpace(record[3])
+
+		var qty float64
+		if qtyStr != "" {
+			qty, err = ParseFraction(qtyStr)
+			if err != nil {
+				if i == 0 {
+					continue // Header row
+				}
+				return nil, fmt.Errorf("receipt line %d: invalid quantity %q", i+1, qtyStr)
+			}
+		}
+
+		var price float64
+		if priceStr != "" {
+			price, err = strconv.ParseFloat(strings.TrimLeft(priceStr, "$€£"), 64)
+			if err != nil {
+				return nil, fmt.Errorf("receipt line %d: invalid price %q", i+1, priceStr)
+			}
+		}
+
+		receipt.Items = append(receipt.Items, ReceiptItem{
+			Name:     strings.TrimSpace(record[0]),
+			Quantity: float32(qty),
+			Unit:     strings.TrimSpace(record[2]),
+			Price:    price,
+		})
+	}
+
+	return receipt, nil
+}
+
+// ParseReceiptFile reads and parses a purchased-items CSV file from disk.
+func ParseReceiptFile(filename string) (*Receipt, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+	return ParseReceiptCSV(f)
+}
+
+// PriceList turns the receipt into a price list: each item costs what was paid for
+// the amount bought. Lines for the same item (case-insensitive) are added up, and
+// lines without a price are skipped.
+//
+// Parameters:
+//   - currency: The currency of the receipt's prices, e.g. "EUR"
+//
+// Returns:
+//   - *PriceList: The prices, in receipt order
+//
+// Example:
+//
+//	receipt, _ := cooklang.ParseReceiptFile("receipt.csv")
+//	prices := receipt.PriceList("EUR")
+//	estimate := recipe.EstimateCost(prices)
+func (r *Receipt) PriceList(currency string) *PriceList {
+	list := &PriceList{Currency: currency, Prices: []Price{}}
+	if r == nil {
+		return list
+	}
+	purchased, order := r.purchases()
+	for _, key := range order {
+		item := purchased[key]
+		if item.Price <= 0 {
+			continue
+		}
+		list.Prices = append(list.Prices, receiptPrice(item))
+	}
+	return list
+}
+
+// MergeReceipt updates the price list with the prices paid on a receipt (see
+// Receipt.PriceList): an item that is already listed gets the new price, and
+// other items are added.
+//
+// Parameters:
+//   - receipt: The receipt with the latest prices
+//
+// Example:
+//
+//	prices, _ := cooklang.LoadPriceList("prices.csv")
+//	receipt, _ := cooklang.ParseReceiptFile("receipt.csv")
+//	prices.MergeReceipt(receipt)
+func (pl *PriceList) MergeReceipt(receipt *Receipt) {
+	for _, price := range receipt.PriceList(pl.Currency).Prices {
+		replaced := false
+		for i := range pl.Prices {
+			if strings.EqualFold(pl.Prices[i].Name, price.Name) {
+				price.Name = pl.Prices[i].Name
+				pl.Prices[i] = price
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			pl.Prices = append(pl.Prices, price)
+		}
+	}
+}
+
+// receiptPrice is the price paid for a receipt item; without a quantity it is per item.
+func receiptPrice(item *ReceiptItem) Price {
+	if item.Quantity <= 0 {
+		return Price{Name: item.Name, Amount: item.Price, Quantity: 1}
+	}
+	return Price{Name: item.Name, Amount: item.Price, Quantity: float64(item.Quantity), Unit: item.Unit}
+}
+
+// purchases adds up the receipt lines for each item, by lowercased name, and
+// returns the names in receipt order.
+func (r *Receipt) purchases() (map[string]*ReceiptItem, []string) {
+	purchased := make(map[string]*ReceiptItem, len(r.Items))
+	var order []string
+	for _, item := range r.Items {
+		key := strings.ToLower(item.Name)
+		if total, exists := purchased[key]; exists {
+			addReceiptLine(total, item)
+		} else {
+			item := item
+			purchased[key] = &item
+			order = append(order, key)
+		}
+	}
+	return purchased, order
+}
+
+// ReconciledItem pairs a shopping list ingredient with what was actually bought.
+// Purchased sums every receipt line for the item.
+type ReconciledItem struct {
+	Ingredient *Ingredient  `json:"ingredient"`
+	Purchased  *ReceiptItem `json:"purchased"`
+	Shortfall  float32      `json:"shortfall,omitempty"` // Amount still missing, in the ingredient's unit
+	Estimated  float64      `json:"estimated,omitempty"` // Estimated cost of the ingredient; 0 if it has no price
+	Actual     float64      `json:"actual"`              // Price paid for the item
+}
+
+// ReconciliationReport summarizes the differences between a shopping list and a receipt.
+type ReconciliationReport struct {
+	Matched        []ReconciledItem `json:"matched,omitempty"`  // Items both needed and bought
+	Missing        []*Ingredient    `json:"missing,omitempty"`  // Needed but not bought
+	Extra          []ReceiptItem    `json:"extra,omitempty"`    // Bought but not on the list
+	Currency       string           `json:"currency,omitempty"` // Currency of the price list
+	EstimatedSpend float64          `json:"estimated_spend"`    // Estimated cost of the whole shopping list
+	ActualSpend    float64          `json:"actual_spend"`       // Total price on the receipt
+}
+
+// Reconcile compares the shopping list against a receipt of purchased items.
+// Items are matched by name (case-insensitive); receipt lines for the same item
+// are added up. When a purchased quantity can be converted to the ingredient's
+// unit and falls short of what is needed, the difference is reported as a
+// Shortfall. With a price list, each matched item and the whole list are also
+// estimated (see ShoppingList.EstimateCost), to compare with what was spent; use
+// PriceList.MergeReceipt to update the prices from the receipt afterwards.
+//
+// Parameters:
+//   - receipt: The purchased items to compare against (nil is an empty receipt)
+//   - prices: The prices to estimate the spend with (nil for no estimate)
+//
+// Returns:
+//   - *ReconciliationReport: Matched, missing, and extra items plus the estimated
+//     and actual spend
+//
+// Example:
+//
+//	list, _ := cooklang.CreateShoppingList(recipes...)
+//	prices, _ := cooklang.LoadPriceList("prices.yaml")
+//	receipt, _ := cooklang.ParseReceiptFile("receipt.csv")
+//	report := list.Reconcile(receipt, prices)
+//	fmt.Printf("Estimated %s, spent %s\n",
+//	    cooklang.FormatCurrency(report.EstimatedSpend, report.Currency),
+//	    cooklang.FormatCurrency(report.ActualSpend, report.Currency))
+//	prices.MergeReceipt(receipt)
+func (sl *ShoppingList) Reconcile(receipt *Receipt, prices *PriceList) *ReconciliationReport {
+	if receipt == nil {
+		receipt = &Receipt{}
+	}
+	report := &ReconciliationReport{ActualSpend: receipt.Total()}
+
+	estimated := make(map[*Ingredient]float64)
+	if prices != nil {
+		estimate := sl.EstimateCost(prices)
+		report.Currency, report.EstimatedSpend = estimate.Currency, estimate.Total
+		for _, item := range estimate.Items {
+			estimated[item.Ingredient] = item.Cost
+		}
+	}
+
+	purchased, _ := receipt.purchases()
+	matched := make(map[string]bool)
+	if sl != nil && sl.Ingredients != nil {
+		for _, ing := range sl.Ingredients.Ingredients {
+			key := strings.ToLower(ing.Name)
+			item, ok := purchased[key]
+			if !ok {
+				report.Missing = append(report.Missing, ing)
+				continue
+			}
+			matched[key] = true
+			report.Matched = append(report.Matched, ReconciledItem{
+				Ingredient: ing,
+				Purchased:  item,
+				Shortfall:  purchaseShortfall(ing, item),
+				Estimated:  estimated[ing],
+				Actual:     item.Price,
+			})
+		}
+	}
+
+	for _, item := range receipt.Items {
+		if !matched[strings.ToLower(item.Name)] {
+			report.Extra = append(report.Extra, item)
+		}
+	}
+
+	return report
+}
+
+// addReceiptLine adds another receipt line for the same item to total. Prices are
+// summed; quantities are summed in total's unit, and the total quantity becomes
+// unknown (0) when a line's quantity cannot be converted to it.
+func addReceiptLine(total *ReceiptItem, line ReceiptItem) {
+	total.Price += line.Price
+	if total.Quantity <= 0 || line.Quantity <= 0 {
+		total.Quantity = 0
+		return
+	}
+	if strings.EqualFold(total.Unit, line.Unit) {
+		total.Quantity += line.Quantity
+		return
+	}
+	converted, err := NewIngredient(line.Name, line.Quantity, line.Unit).ConvertTo(total.Unit)
+	if err != nil {
+		total.Quantity = 0
+		return
+	}
+	total.Quantity += float32(converted.Quantity.Float())
+}
+
+// purchaseShortfall returns how much of the ingredient is still missing after a purchase,
+// or 0 if the purchase covers it or the quantities cannot be compared.
+func purchaseShortfall(ing *Ingredient, item *ReceiptItem) float32 {
+	needed := float32(ing.Quantity.Float())
+	if !ing.Quantity.HasAmount() || item.Quantity <= 0 {
+		return 0
+	}
+
+	bought := item.Quantity
+	if !strings.EqualFold(item.Unit, ing.Unit) {
+		boughtIng := NewIngredient(item.Name, item.Quantity, item.Unit)
+		converted, err := boughtIng.ConvertTo(ing.Unit)
+		if err != nil {
+			return 0
+		}
+		bought = float32(converted.Quantity.Float())
+	}
+
+	if bought >= needed {
+		return 0
+	}
+	return needed - bought
+}
@@ -0,0 +1,219 @@
+package cooklang
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestParseReceiptCSV(t *testing.T) {
+	csvData := `name,qty,unit,price
+Flour,1,kg,2.49
+milk,500,ml,$0.99
+chocolate,,,3.50
+`
+	receipt, err := ParseReceiptCSV(strings.NewReader(csvData))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(receipt.Items) != 3 {
+		t.Fatalf("expected 3 items, got %d", len(receipt.Items))
+	}
+	if receipt.Items[0].Name != "Flour" || receipt.Items[0].Quantity != 1 || receipt.Items[0].Unit != "kg" {
+		t.Errorf("unexpected first item: %+v", receipt.Items[0])
+	}
+	if receipt.Items[1].Price != 0.99 {
+		t.Errorf("expected price 0.99, got %v", receipt.Items[1].Price)
+	}
+	if total := receipt.Total(); total < 6.97 || total > 6.99 {
+		t.Errorf("expected total 6.98, got %v", total)
+	}
+}
+
+func TestParseReceiptCSVErrors(t *testing.T) {
+	tests := map[string]string{
+		"too few columns": "flour,1,kg\n",
+		"bad quantity":    "flour,1,kg,1.00\nsugar,lots,g,1.00\n",
+		"bad price":       "flour,1,kg,cheap\n",
+	}
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ParseReceiptCSV(strings.NewReader(data)); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestShoppingListReconcile(t *testing.T) {
+	recipe, err := ParseString("Mix @flour{1.5%kg}, @milk{250%ml} and @eggs{3}.")
+	if err != nil {
+		t.Fatalf("failed to parse recipe: %v", err)
+	}
+	list, err := CreateShoppingList(recipe)
+	if err != nil {
+		t.Fatalf("failed to create shopping list: %v", err)
+	}
+
+	receipt := &Receipt{Items: []ReceiptItem{
+		{Name: "flour", Quantity: 1000, Unit: "g", Price: 2.5},
+		{Name: "Milk", Quantity: 1, Unit: "l", Price: 1.2},
+		{Name: "chocolate", Quantity: 1, Price: 3},
+	}}
+
+	report := list.Reconcile(receipt, nil)
+
+	if report.ActualSpend != 6.7 {
+		t.Errorf("expected actual spend 6.7, got %v", report.ActualSpend)
+	}
+	if len(report.Missing) != 1 || report.Missing[0].Name != "eggs" {
+		t.Errorf("expected eggs to be missing, got %+v", report.Missing)
+	}
+	if len(report.Extra) != 1 || report.Extra[0].Name != "chocolate" {
+		t.Errorf("expected chocolate to be extra, got %+v", report.Extra)
+	}
+	if len(report.Matched) != 2 {
+		t.Fatalf("expected 2 matched items, got %d", len(report.Matched))
+	}
+	for _, m := range report.Matched {
+		switch m.Ingredient.Name {
+		case "flour":
+			if m.Shortfall < 0.49 || m.Shortfall > 0.51 {
+				t.Errorf("expected flour shortfall of 0.5 kg, got %v", m.Shortfall)
+			}
+		case "milk":
+			if m.Shortfall != 0 {
+				t.Errorf("expected no milk shortfall, got %v", m.Shortfall)
+			}
+		}
+	}
+}
+
+func TestShoppingListReconcileSumsDuplicateLines(t *testing.T) {
+	recipe, err := ParseString("Mix @flour{1.5%kg} and @milk{2%l}.")
+	if err != nil {
+		t.Fatalf("failed to parse recipe: %v", err)
+	}
+	list, err := CreateShoppingList(recipe)
+	if err != nil {
+		t.Fatalf("failed to create shopping list: %v", err)
+	}
+
+	report := list.Reconcile(&Receipt{Items: []ReceiptItem{
+		{Name: "flour", Quantity: 1, Unit: "kg", Price: 2},
+		{Name: "Flour", Quantity: 500, Unit: "g", Price: 1.5},
+		{Name: "milk", Quantity: 1, Unit: "l", Price: 1},
+		{Name: "milk", Quantity: 1, Unit: "l", Price: 1},
+	}}, nil)
+
+	if len(report.Matched) != 2 || len(report.Extra) != 0 {
+		t.Fatalf("expected 2 matched and no extra items, got %+v", report)
+	}
+	for _, m := range report.Matched {
+		if m.Shortfall != 0 {
+			t.Errorf("%s: expected no shortfall, got %v", m.Ingredient.Name, m.Shortfall)
+		}
+		if m.Ingredient.Name == "flour" && (m.Purchased.Price != 3.5 || m.Purchased.Quantity != 1.5) {
+			t.Errorf("expected 1.5 kg flour for 3.5, got %+v", m.Purchased)
+		}
+	}
+}
+
+func TestShoppingListReconcileNilReceipt(t *testing.T) {
+	recipe, _ := ParseString("Add @salt{1%tsp}.")
+	list, err := CreateShoppingList(recipe)
+	if err != nil {
+		t.Fatalf("failed to create shopping list: %v", err)
+	}
+	report := list.Reconcile(nil, nil)
+	if len(report.Missing) != 1 || report.ActualSpend != 0 {
+		t.Errorf("expected salt to be missing and no spend, got %+v", report)
+	}
+}
+
+func TestShoppingListReconcileWithPrices(t *testing.T) {
+	recipe, _ := ParseString("Mix @flour{2%kg}, @milk{500%ml} and @eggs{4}.")
+	list, err := CreateShoppingList(recipe)
+	if err != nil {
+		t.Fatalf("failed to create shopping list: %v", err)
+	}
+	prices := &PriceList{Currency: "EUR", Prices: []Price{
+		{Name: "flour", Amount: 1.2, Quantity: 1, Unit: "kg"},
+		{Name: "milk", Amount: 1, Quantity: 1, Unit: "l"},
+		{Name: "eggs", Amount: 0.3, Quantity: 1},
+	}}
+	receipt := &Receipt{Items: []ReceiptItem{
+		{Name: "flour", Quantity: 2, Unit: "kg", Price: 3},
+		{Name: "milk", Quantity: 1, Unit: "l", Price: 1.1},
+	}}
+
+	report := list.Reconcile(receipt, prices)
+	if report.Currency != "EUR" || math.Abs(report.EstimatedSpend-4.1) > 0.001 || math.Abs(report.ActualSpend-4.1) > 0.001 {
+		t.Errorf("expected EUR 4.10 estimated and spent, got %s %v and %v", report.Currency, report.EstimatedSpend, report.ActualSpend)
+	}
+	for _, m := range report.Matched {
+		switch m.Ingredient.Name {
+		case "flour":
+			if math.Abs(m.Estimated-2.4) > 0.001 || m.Actual != 3 {
+				t.Errorf("flour: expected 2.40 estimated and 3 spent, got %v and %v", m.Estimated, m.Actual)
+			}
+		case "milk":
+			if math.Abs(m.Estimated-0.5) > 0.001 || m.Actual != 1.1 {
+				t.Errorf("milk: expected 0.50 estimated and 1.10 spent, got %v and %v", m.Estimated, m.Actual)
+			}
+		}
+	}
+}
+
+func TestReceiptPriceList(t *testing.T) {
+	receipt := &Receipt{Items: []ReceiptItem{
+		{Name: "flour", Quantity: 1, Unit: "kg", Price: 2},
+		{Name: "Flour", Quantity: 500, Unit: "g", Price: 1.5},
+		{Name: "lemons", Quantity: 3, Price: 1.2},
+		{Name: "bag", Price: 0.5},
+		{Name: "coupon", Quantity: 1},
+	}}
+
+	prices := receipt.PriceList("EUR")
+	if prices.Currency != "EUR" || len(prices.Prices) != 3 {
+		t.Fatalf("expected 3 EUR prices, got %+v", prices)
+	}
+	want := []Price{
+		{Name: "flour", Amount: 3.5, Quantity: 1.5, Unit: "kg"},
+		{Name: "lemons", Amount: 1.2, Quantity: 3},
+		{Name: "bag", Amount: 0.5, Quantity: 1},
+	}
+	for i, price := range want {
+		if prices.Prices[i] != price {
+			t.Errorf("price %d = %+v, want %+v", i, prices.Prices[i], price)
+		}
+	}
+}
+
+func TestPriceListMergeReceipt(t *testing.T) {
+	prices := &PriceList{Currency: "EUR", Prices: []Price{
+		{Name: "Flour", Amount: 1.2, Quantity: 1, Unit: "kg"},
+		{Name: "eggs", Amount: 0.3, Quantity: 1},
+	}}
+	prices.MergeReceipt(&Receipt{Items: []ReceiptItem{
+		{Name: "flour", Quantity: 2, Unit: "kg", Price: 3},
+		{Name: "milk", Quantity: 1, Unit: "l", Price: 1.1},
+	}})
+
+	want := []Price{
+		{Name: "Flour", Amount: 3, Quantity: 2, Unit: "kg"},
+		{Name: "eggs", Amount: 0.3, Quantity: 1},
+		{Name: "milk", Amount: 1.1, Quantity: 1, Unit: "l"},
+	}
+	if len(prices.Prices) != len(want) {
+		t.Fatalf("expected %d prices, got %+v", len(want), prices.Prices)
+	}
+	for i, price := range want {
+		if prices.Prices[i] != price {
+			t.Errorf("price %d = %+v, want %+v", i, prices.Prices[i], price)
+		}
+	}
+	if price, _ := prices.Lookup("flour"); price.Amount != 3 {
+		t.Errorf("expected the new flour price, got %+v", price)
+	}
+}
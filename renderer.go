@@ -1,20 +1,36 @@
 package cooklang
 
+// Renderer renders a recipe in one output format and reports when it cannot, such
+// as a format that needs a title the recipe lacks. Recipe.RenderWith takes a
+// Renderer; the renderers package adapts its renderers with renderers.Bind.
+//
+// Example implementation:
+//
+//	type JSONRenderer struct{}
+//	func (jr JSONRenderer) Render(recipe *cooklang.Recipe) (string, error) {
+//	    data, err := json.MarshalIndent(recipe, "", "  ")
+//	    return string(data), err
+//	}
+type Renderer interface {
+	Render(recipe *Recipe) (string, error)
+}
+
 // RecipeRenderer interface defines how recipes can be rendered to different output formats.
-// Implementations can render recipes as Markdown, HTML, plain text, or any custom format.
+// Implementations can render recipes as Markdown, HTML, plain text, or any custom format,
+// and return an error for recipes they cannot render.
 //
 // Example implementation:
 //
 //	type JSONRenderer struct{}
-//	func (jr JSONRenderer) RenderRecipe(recipe *cooklang.Recipe) string {
-//	    data, _ := json.MarshalIndent(recipe, "", "  ")
-//	    return string(data)
+//	func (jr JSONRenderer) RenderRecipe(recipe *cooklang.Recipe) (string, error) {
+//	    data, err := json.MarshalIndent(recipe, "", "  ")
+//	    return string(data), err
 //	}
 type RecipeRenderer interface {
-	RenderRecipe(recipe *Recipe) string
+	RenderRecipe(recipe *Recipe) (string, error)
 }
 
-// RendererFunc is a function type that implements RecipeRenderer.
+// RendererFunc is a function type that implements RecipeRenderer and Renderer.
 // This allows using plain functions as renderers without creating a new type.
 //
 // Example:
@@ -22,16 +38,24 @@ type RecipeRenderer interface {
 //	simpleRenderer := cooklang.RendererFunc(func(r *cooklang.Recipe) string {
 //	    return fmt.Sprintf("# %s\n\nServings: %.0f", r.Title, r.Servings)
 //	})
-//	output := recipe.RenderWith(simpleRenderer)
+//	output, _ := recipe.RenderWith(simpleRenderer)
 type RendererFunc func(*Recipe) string
 
-// RenderRecipe implements the RecipeRenderer interface for RendererFunc.
-func (f RendererFunc) RenderRecipe(recipe *Recipe) string {
-	return f(recipe)
+// RenderRecipe implements the RecipeRenderer interface for RendererFunc; it never fails.
+func (f RendererFunc) RenderRecipe(recipe *Recipe) (string, error) {
+	return f(recipe), nil
+}
+
+// Render implements the Renderer interface for RendererFunc; it never fails.
+func (f RendererFunc) Render(recipe *Recipe) (string, error) {
+	return f(recipe), nil
 }
 
 // SetRenderer allows setting a custom renderer for a recipe.
 // Once set, calling Render() will use this custom renderer instead of the default.
+// Copies made by Clone, Scale, ConvertToSystem and the other transforms keep the
+// renderer and render themselves with it. Render cannot return errors, so it
+// returns an empty string when the renderer fails; use RenderWith to get the error.
 //
 // Parameters:
 //   - renderer: A RecipeRenderer implementation
@@ -42,13 +66,19 @@ func (f RendererFunc) RenderRecipe(recipe *Recipe) string {
 //	recipe.SetRenderer(renderers.MarkdownRenderer{})
 //	markdown := recipe.Render()
 func (r *Recipe) SetRenderer(renderer RecipeRenderer) {
-	r.RenderFunc = func() string {
-		return renderer.RenderRecipe(r)
-	}
+	r.SetRendererFunc(func(recipe *Recipe) string {
+		output, err := renderer.RenderRecipe(recipe)
+		if err != nil {
+			return ""
+		}
+		return output
+	})
 }
 
-// SetRendererFunc allows setting a custom renderer function for a recipe
+// SetRendererFunc allows setting a custom renderer function for a recipe.
+// Like SetRenderer, the function is kept by copies of the recipe.
 func (r *Recipe) SetRendererFunc(renderFunc func(*Recipe) string) {
+	r.renderer = renderFunc
 	r.RenderFunc = func() string {
 		return renderFunc(r)
 	}
@@ -58,16 +88,17 @@ func (r *Recipe) SetRendererFunc(renderFunc func(*Recipe) string) {
 // This allows one-time rendering without setting a permanent renderer on the recipe.
 //
 // Parameters:
-//   - renderer: A RecipeRenderer implementation to use for rendering
+//   - renderer: A Renderer implementation to use for rendering
 //
 // Returns:
 //   - string: The rendered recipe in the format defined by the renderer
+//   - error: The renderer's error, if it could not render the recipe
 //
 // Example:
 //
 //	recipe, _ := cooklang.ParseFile("recipe.cook")
-//	markdown := recipe.RenderWith(renderers.MarkdownRenderer{})
-//	html := recipe.RenderWith(renderers.HTMLRenderer{})
-func (r *Recipe) RenderWith(renderer RecipeRenderer) string {
-	return renderer.RenderRecipe(r)
+//	markdown, err := recipe.RenderWith(renderers.Bind(renderers.MarkdownRenderer{}, renderers.RendererOptions{}))
+//	html, err := recipe.RenderWith(renderers.Bind(renderers.HTMLRenderer{}, renderers.RendererOptions{Locale: "de"}))
+func (r *Recipe) RenderWith(renderer Renderer) (string, error) {
+	return renderer.Render(r)
 }
@@ -0,0 +1,96 @@
+package renderers
+
+import (
+	"fmt"
+	"html"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/hilli/cooklang"
+)
+
+// BakersPercentagesRenderer renders a recipe's baker's percentages (see
+// cooklang.Recipe.BakersPercentages) as a table of each ingredient's grams and
+// percentage of the flour, followed by the hydration: a Markdown table, or an HTML
+// fragment. The Markdown, HTML and Print renderers embed the table with
+// RendererOptions.BakersPercentages.
+//
+// Example usage:
+//
+//	recipe, _ := cooklang.ParseFile("sourdough.cook")
+//	table, err := renderers.BakersPercentagesRenderer{}.Render(recipe, renderers.RendererOptions{})
+//	// ## Baker's Percentages
+//	//
+//	// | Ingredient | Grams | % |
+//	// |---|--:|--:|
+//	// | bread flour | 500 | 100% |
+//	// | water | 350 | 70% |
+//	// ...
+type BakersPercentagesRenderer struct {
+	HTML bool // Render an HTML fragment instead of Markdown
+}
+
+// Render renders the baker's percentages table. Recipes without flour whose mass is
+// known return cooklang.ErrNoFlour.
+func (br BakersPercentagesRenderer) Render(recipe *cooklang.Recipe, opts RendererOptions) (string, error) {
+	formula, err := opts.prepare(recipe).BakersPercentages()
+	if err != nil {
+		return "", err
+	}
+	if br.HTML {
+		return bakersHTML(formula), nil
+	}
+	return bakersMarkdown(formula), nil
+}
+
+// bakersMarkdown renders the Markdown table.
+func bakersMarkdown(formula *cooklang.BakersPercentages) string {
+	var result strings.Builder
+	result.WriteString("## Baker's Percentages\n\n")
+	result.WriteString("| Ingredient | Grams | % |\n")
+	result.WriteString("|---|--:|--:|\n")
+	for _, ingredient := range formula.Ingredients {
+		name := strings.ReplaceAll(ingredient.Name, "|", `\|`)
+		if ingredient.Flour {
+			name = "**" + name + "**"
+		}
+		fmt.Fprintf(&result, "| %s | %s | %s%% |\n", name, formatBakers(ingredient.Grams), formatBakers(ingredient.Percent))
+	}
+	fmt.Fprintf(&result, "\n**Hydration:** %s%%\n\n", formatBakers(formula.Hydration))
+	if len(formula.Unconverted) > 0 {
+		fmt.Fprintf(&result, "_Not weighed: %s_\n\n", strings.Join(formula.Unconverted, ", "))
+	}
+	return result.String()
+}
+
+// bakersHTML renders the HTML fragment.
+func bakersHTML(formula *cooklang.BakersPercentages) string {
+	var result strings.Builder
+	result.WriteString("<section class=\"bakers-percentages\">\n")
+	result.WriteString("  <h2>Baker's Percentages</h2>\n")
+	result.WriteString("  <table>\n")
+	result.WriteString("    <thead><tr><th>Ingredient</th><th>Grams</th><th>%</th></tr></thead>\n")
+	result.WriteString("    <tbody>\n")
+	for _, ingredient := range formula.Ingredients {
+		class := ""
+		if ingredient.Flour {
+			class = " class=\"flour\""
+		}
+		fmt.Fprintf(&result, "      <tr%s><td>%s</td><td>%s</td><td>%s%%</td></tr>\n",
+			class, html.EscapeString(ingredient.Name), formatBakers(ingredient.Grams), formatBakers(ingredient.Percent))
+	}
+	result.WriteString("    </tbody>\n")
+	result.WriteString("  </table>\n")
+	fmt.Fprintf(&result, "  <p class=\"hydration\"><strong>Hydration:</strong> %s%%</p>\n", formatBakers(formula.Hydration))
+	if len(formula.Unconverted) > 0 {
+		fmt.Fprintf(&result, "  <p class=\"unweighed\">Not weighed: %s</p>\n", html.EscapeString(strings.Join(formula.Unconverted, ", ")))
+	}
+	result.WriteString("</section>\n")
+	return result.String()
+}
+
+// formatBakers formats grams and percentages to a tenth.
+func formatBakers(value float64) string {
+	return strconv.FormatFloat(math.Round(value*10)/10, 'f', -1, 64)
+}
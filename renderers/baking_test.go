@@ -0,0 +1,80 @@
+package renderers
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/hilli/cooklang"
+)
+
+func TestBakersPercentagesRenderer(t *testing.T) {
+	recipe, err := cooklang.ParseString(`---
+title: Country Loaf
+---
+Mix @bread flour{500%g}, @water{350%g} and @salt{10%g}.
+
+Add @yeast{1%tsp} and a few @seeds{}.
+`)
+	if err != nil {
+		t.Fatalf("Failed to parse recipe: %v", err)
+	}
+
+	table, err := BakersPercentagesRenderer{}.Render(recipe, RendererOptions{})
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	expected := `## Baker's Percentages
+
+| Ingredient | Grams | % |
+|---|--:|--:|
+| **bread flour** | 500 | 100% |
+| water | 350 | 70% |
+| salt | 10 | 2% |
+| yeast | 3 | 0.6% |
+
+**Hydration:** 70%
+
+_Not weighed: seeds_
+
+`
+	if table != expected {
+		t.Errorf("unexpected table:\n%s\nwant:\n%s", table, expected)
+	}
+
+	fragment, err := BakersPercentagesRenderer{HTML: true}.Render(recipe, RendererOptions{})
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	for _, want := range []string{
+		`<tr class="flour"><td>bread flour</td><td>500</td><td>100%</td></tr>`,
+		`<p class="hydration"><strong>Hydration:</strong> 70%</p>`,
+	} {
+		if !strings.Contains(fragment, want) {
+			t.Errorf("expected %q in:\n%s", want, fragment)
+		}
+	}
+
+	// The option embeds the table after the ingredients
+	opts := RendererOptions{BakersPercentages: true}
+	markdown, _ := MarkdownRenderer{}.Render(recipe, opts)
+	if !strings.Contains(markdown, "- **some** seeds\n\n## Baker's Percentages\n") {
+		t.Errorf("expected the table after the ingredients, got:\n%s", markdown)
+	}
+	for name, renderer := range map[string]Renderer{"html": HTMLRenderer{}, "print": PrintRenderer{}} {
+		if output, _ := renderer.Render(recipe, opts); !strings.Contains(output, `<section class="bakers-percentages">`) {
+			t.Errorf("%s: expected the table, got:\n%s", name, output)
+		}
+	}
+	if output := renderRecipe(t, MarkdownRenderer{}, recipe); strings.Contains(output, "Baker's Percentages") {
+		t.Errorf("expected no table by default, got:\n%s", output)
+	}
+
+	cocktail, _ := cooklang.ParseString("Stir @gin{50%ml} with @ice{}.")
+	if _, err := (BakersPercentagesRenderer{}).Render(cocktail, RendererOptions{}); !errors.Is(err, cooklang.ErrNoFlour) {
+		t.Errorf("expected ErrNoFlour, got %v", err)
+	}
+	if output, _ := (MarkdownRenderer{}).Render(cocktail, opts); strings.Contains(output, "Baker's Percentages") {
+		t.Errorf("expected no table without flour, got:\n%s", output)
+	}
+}
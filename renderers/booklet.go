@@ -0,0 +1,236 @@
+package renderers
+
+import (
+	"fmt"
+	"html"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hilli/cooklang"
+	"github.com/hilli/cooklang/naming"
+)
+
+// Booklet sets the cover and chapters of a PrintRenderer booklet.
+type Booklet struct {
+	Title    string // Title on the cover (default: the Recipes label)
+	Subtitle string // Line under the title, e.g. "Desserts, winter 2026"
+	// Chapters groups the recipes into a chapter per tag, in alphabetical order, by
+	// each recipe's first tag; untagged recipes come last, under the Other label.
+	Chapters bool
+}
+
+// bookletCSS starts each recipe and chapter on a new page, after the cover and the
+// table of contents. Print engines that support target-counter, such as Paged.js
+// and WeasyPrint, add the page numbers to the table of contents.
+const bookletCSS = `
+<style>
+  .booklet-cover {
+    min-height: 24cm;
+    display: flex;
+    flex-direction: column;
+    justify-content: center;
+    text-align: center;
+    break-after: page;
+    page-break-after: always;
+  }
+
+  .booklet-title {
+    font-size: 32pt;
+    margin-bottom: 0.3em;
+  }
+
+  .booklet-subtitle {
+    font-size: 14pt;
+    font-style: italic;
+    color: #555;
+  }
+
+  .booklet-contents {
+    break-after: page;
+    page-break-after: always;
+  }
+
+  .booklet-contents h2 {
+    font-size: 18pt;
+    margin-bottom: 0.75em;
+  }
+
+  .booklet-contents h3 {
+    font-size: 12pt;
+    margin: 0.75em 0 0.25em;
+  }
+
+  .booklet-contents ul {
+    list-style: none;
+  }
+
+  .booklet-contents li {
+    padding: 0.15em 0;
+  }
+
+  .booklet-contents a {
+    color: inherit;
+    text-decoration: none;
+  }
+
+  .booklet-contents a::after {
+    content: leader('.') target-counter(attr(href), page);
+  }
+
+  .booklet-chapter {
+    font-size: 24pt;
+    margin-bottom: 0.75em;
+    break-before: page;
+    page-break-before: always;
+  }
+
+  .recipe-print + .recipe-print {
+    break-before: page;
+    page-break-before: always;
+  }
+</style>
+`
+
+// bookletChapter is a chapter of a booklet; the title is empty without chapters.
+type bookletChapter struct {
+	title   string
+	recipes []*cooklang.Recipe
+	ids     []string
+}
+
+// RenderBooklet renders several recipes as one print-optimized HTML document: a
+// cover, a table of contents linking to each recipe, and each recipe on a page of
+// its own, grouped into chapters per tag if booklet.Chapters is set. Each recipe
+// shows its own images; Options.Images, Options.URL and Template are not used.
+//
+// Parameters:
+//   - recipes: The recipes, in the order they appear in the booklet
+//   - booklet: The cover title and subtitle, and whether to add chapters
+//
+// Returns:
+//   - string: The HTML document
+//   - error: An error if there are no recipes
+//
+// Example:
+//
+//	library, _ := cooklang.LoadLibrary("recipes")
+//	var desserts []*cooklang.Recipe
+//	for _, entry := range library.ByTag("dessert") {
+//	    desserts = append(desserts, entry.Recipe)
+//	}
+//	page, err := renderers.PrintRenderer{}.RenderBooklet(desserts, renderers.Booklet{Title: "Desserts"})
+func (pr PrintRenderer) RenderBooklet(recipes []*cooklang.Recipe, booklet Booklet) (string, error) {
+	if len(recipes) == 0 {
+		return "", fmt.Errorf("a booklet needs at least one recipe")
+	}
+	pr.Options.Images = nil
+	labels := pr.Options.labels()
+	title := booklet.Title
+	if title == "" {
+		title = labels.Recipes
+	}
+	chapters := bookletChapters(recipes, booklet.Chapters, labels)
+
+	var result strings.Builder
+	result.WriteString("<!DOCTYPE html>\n")
+	fmt.Fprintf(&result, "<html lang=\"%s\">\n", html.EscapeString(pr.Options.Language()))
+	result.WriteString("<head>\n")
+	result.WriteString("  <meta charset=\"UTF-8\">\n")
+	result.WriteString("  <meta name=\"viewport\" content=\"width=device-width, initial-scale=1.0\">\n")
+	fmt.Fprintf(&result, "  <title>%s</title>\n", html.EscapeString(title))
+	result.WriteString(printCSS)
+	result.WriteString(bookletCSS)
+	result.WriteString("</head>\n")
+	result.WriteString("<body>\n")
+
+	// Cover
+	result.WriteString("<section class=\"booklet-cover\">\n")
+	fmt.Fprintf(&result, "  <h1 class=\"booklet-title\">%s</h1>\n", html.EscapeString(title))
+	if booklet.Subtitle != "" {
+		fmt.Fprintf(&result, "  <p class=\"booklet-subtitle\">%s</p>\n", html.EscapeString(booklet.Subtitle))
+	}
+	result.WriteString("</section>\n")
+
+	// Table of contents
+	result.WriteString("<nav class=\"booklet-contents\">\n")
+	fmt.Fprintf(&result, "  <h2>%s</h2>\n", html.EscapeString(labels.Contents))
+	for _, chapter := range chapters {
+		if chapter.title != "" {
+			fmt.Fprintf(&result, "  <h3>%s</h3>\n", html.EscapeString(chapter.title))
+		}
+		result.WriteString("  <ul>\n")
+		for i, recipe := range chapter.recipes {
+			name := recipe.Title
+			if name == "" {
+				name = labels.Recipe
+			}
+			fmt.Fprintf(&result, "    <li><a href=\"#%s\">%s</a></li>\n", html.EscapeString(chapter.ids[i]), html.EscapeString(name))
+		}
+		result.WriteString("  </ul>\n")
+	}
+	result.WriteString("</nav>\n")
+
+	// Recipes
+	for _, chapter := range chapters {
+		if chapter.title != "" {
+			fmt.Fprintf(&result, "<h1 class=\"booklet-chapter\">%s</h1>\n", html.EscapeString(chapter.title))
+		}
+		for i, recipe := range chapter.recipes {
+			pr.writeRecipe(&result, pr.Options.prepare(recipe), labels, nil, chapter.ids[i])
+		}
+	}
+
+	result.WriteString("</body>\n")
+	result.WriteString("</html>\n")
+	return result.String(), nil
+}
+
+// bookletChapters groups the recipes into chapters by their first tag, or into one
+// untitled chapter, and gives each recipe a unique anchor id.
+func bookletChapters(recipes []*cooklang.Recipe, byTag bool, labels Strings) []bookletChapter {
+	var chapters []bookletChapter
+	if !byTag {
+		chapters = []bookletChapter{{recipes: recipes}}
+	} else {
+		index := make(map[string]int)
+		var untagged []*cooklang.Recipe
+		for _, recipe := range recipes {
+			if len(recipe.Tags) == 0 {
+				untagged = append(untagged, recipe)
+				continue
+			}
+			tag := recipe.Tags[0]
+			i, ok := index[strings.ToLower(tag)]
+			if !ok {
+				i = len(chapters)
+				index[strings.ToLower(tag)] = i
+				chapters = append(chapters, bookletChapter{title: tag})
+			}
+			chapters[i].recipes = append(chapters[i].recipes, recipe)
+		}
+		sort.SliceStable(chapters, func(i, j int) bool {
+			return strings.ToLower(chapters[i].title) < strings.ToLower(chapters[j].title)
+		})
+		if len(untagged) > 0 {
+			chapters = append(chapters, bookletChapter{title: labels.Other, recipes: untagged})
+		}
+	}
+
+	used := make(map[string]bool)
+	for c := range chapters {
+		for _, recipe := range chapters[c].recipes {
+			base := "recipe"
+			if slug := naming.Slugify(recipe.Title); slug != "" {
+				base += "-" + slug
+			}
+			id := base
+			for n := 2; used[id]; n++ {
+				id = base + "-" + strconv.Itoa(n)
+			}
+			used[id] = true
+			chapters[c].ids = append(chapters[c].ids, id)
+		}
+	}
+	return chapters
+}
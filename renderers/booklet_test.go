@@ -0,0 +1,73 @@
+package renderers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hilli/cooklang"
+)
+
+func TestRenderBooklet(t *testing.T) {
+	var recipes []*cooklang.Recipe
+	for _, source := range []string{
+		"---\ntitle: Tiramisu\ntags: dessert, italian\nimages: tiramisu.jpg\n---\nLayer @mascarpone{250%g} and @savoiardi{12}.\n",
+		"---\ntitle: Pasta & Sauce\ntags: dinner\n---\nBoil @pasta{500%g}.\n",
+		"---\ntitle: Brownies\ntags: Dessert\n---\nBake @chocolate{200%g} at 180°C.\n",
+		"---\ntitle: Toast\n---\nToast @bread{2%slices}.\n",
+		"---\ntitle: Toast\n---\nToast @bread{4%slices}.\n",
+	} {
+		recipe, err := cooklang.ParseString(source)
+		if err != nil {
+			t.Fatalf("Failed to parse recipe: %v", err)
+		}
+		recipes = append(recipes, recipe)
+	}
+
+	renderer := PrintRenderer{Options: RendererOptions{Images: []string{"cover.jpg"}}}
+	booklet, err := renderer.RenderBooklet(recipes, Booklet{Title: "Family Recipes", Subtitle: "Winter"})
+	if err != nil {
+		t.Fatalf("RenderBooklet failed: %v", err)
+	}
+	for _, expected := range []string{
+		"<title>Family Recipes</title>",
+		"<h1 class=\"booklet-title\">Family Recipes</h1>\n  <p class=\"booklet-subtitle\">Winter</p>",
+		"<h2>Contents</h2>\n  <ul>\n    <li><a href=\"#recipe-tiramisu\">Tiramisu</a></li>\n    <li><a href=\"#recipe-pasta-sauce\">Pasta &amp; Sauce</a></li>",
+		"<a href=\"#recipe-toast-2\">Toast</a>",
+		"<div class=\"recipe-print\" id=\"recipe-tiramisu\">",
+		"<div class=\"recipe-print\" id=\"recipe-toast-2\">",
+		"src=\"tiramisu.jpg\"",
+		".recipe-print + .recipe-print {\n    break-before: page;",
+	} {
+		if !strings.Contains(booklet, expected) {
+			t.Errorf("expected booklet to contain %q, got:\n%s", expected, booklet)
+		}
+	}
+	if strings.Count(booklet, "<div class=\"recipe-print\"") != 5 || strings.Count(booklet, "<html") != 1 {
+		t.Errorf("expected one document with five recipes, got:\n%s", booklet)
+	}
+	if strings.Contains(booklet, "cover.jpg") || strings.Contains(booklet, "booklet-chapter\">") {
+		t.Errorf("expected no shared images and no chapters, got:\n%s", booklet)
+	}
+
+	// Chapters by first tag, alphabetically, with untagged recipes last
+	booklet, err = PrintRenderer{Options: RendererOptions{Locale: "de"}}.RenderBooklet(recipes, Booklet{Chapters: true})
+	if err != nil {
+		t.Fatalf("RenderBooklet failed: %v", err)
+	}
+	dessert := strings.Index(booklet, "<h1 class=\"booklet-chapter\">dessert</h1>")
+	dinner := strings.Index(booklet, "<h1 class=\"booklet-chapter\">dinner</h1>")
+	other := strings.Index(booklet, "<h1 class=\"booklet-chapter\">Sonstiges</h1>")
+	if dessert < 0 || dinner < dessert || other < dinner {
+		t.Errorf("expected dessert, dinner and Sonstiges chapters in order, got:\n%s", booklet)
+	}
+	if brownies := strings.Index(booklet, "id=\"recipe-brownies\""); brownies < dessert || brownies > dinner {
+		t.Errorf("expected Brownies in the dessert chapter, got:\n%s", booklet)
+	}
+	if !strings.Contains(booklet, "<title>Rezepte</title>") || !strings.Contains(booklet, "<h2>Inhalt</h2>") {
+		t.Errorf("expected German cover and contents labels, got:\n%s", booklet)
+	}
+
+	if _, err := (PrintRenderer{}).RenderBooklet(nil, Booklet{}); err == nil {
+		t.Error("expected an error for a booklet without recipes")
+	}
+}
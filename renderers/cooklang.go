@@ -10,12 +10,19 @@ import (
 // CooklangRenderer renders recipes in the original Cooklang format
 type CooklangRenderer struct{}
 
-func (cr CooklangRenderer) RenderRecipe(recipe *cooklang.Recipe) string {
+// Render renders the recipe as Cooklang. Of the options, only Units, PreferredUnits
+// and NoImages apply; the others are for display formats.
+func (cr CooklangRenderer) Render(recipe *cooklang.Recipe, opts RendererOptions) (string, error) {
+	return cr.RenderRecipe(opts.prepare(recipe))
+}
+
+// RenderRecipe renders the recipe as Cooklang, as it was parsed.
+func (cr CooklangRenderer) RenderRecipe(recipe *cooklang.Recipe) (string, error) {
 	var result strings.Builder
 	var metadata strings.Builder
 
-	// Collect metadata
-	if recipe.Title != "" {
+	// Collect metadata; inferred titles stay out of the frontmatter
+	if recipe.Title != "" && !recipe.TitleInferred {
 		metadata.WriteString(fmt.Sprintf("title: %s\n", recipe.Title))
 	}
 	if recipe.Cuisine != "" {
@@ -42,6 +49,14 @@ func (cr CooklangRenderer) RenderRecipe(recipe *cooklang.Recipe) string {
 	if recipe.Servings > 0 {
 		metadata.WriteString(fmt.Sprintf("servings: %g\n", recipe.Servings))
 	}
+	if !recipe.Yield.IsZero() {
+		// Keep the quantity%unit format if the metadata was written in it
+		yield := recipe.Yield.String()
+		if strings.Contains(recipe.Metadata["yield"], "%") {
+			yield = recipe.Metadata["yield"]
+		}
+		metadata.WriteString(fmt.Sprintf("yield: %s\n", yield))
+	}
 	if len(recipe.Tags) > 0 {
 		metadata.WriteString("tags:\n")
 		for _, tag := range recipe.Tags {
@@ -82,7 +97,7 @@ func (cr CooklangRenderer) RenderRecipe(recipe *cooklang.Recipe) string {
 		currentStep = currentStep.NextStep
 	}
 
-	return result.String()
+	return result.String(), nil
 }
 
 // DefaultCooklangRenderer is the default instance of CooklangRenderer
@@ -0,0 +1,353 @@
+package renderers
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"image"
+	_ "image/gif" // Register decoders for image.DecodeConfig
+	_ "image/jpeg"
+	_ "image/png"
+	"strings"
+
+	"github.com/hilli/cooklang"
+)
+
+// DocxRenderer renders a recipe as a Word document (.docx) that can be edited in
+// Word, LibreOffice or Google Docs: the title, the first image, a table of the
+// recipe information, the ingredient list and the numbered steps, with headings in
+// the options' locale. Sections get their own heading and restart the numbering.
+//
+// The output is a ZIP archive, returned as a string of bytes. The first image is
+// embedded if it is a data URI in RendererOptions.Images or recipe.Images, as made
+// by cooklang.ImageSources with cooklang.ImagesEmbedded; other image sources are
+// left out.
+//
+// Example:
+//
+//	recipe, _ := cooklang.ParseFile("recipes/Pancakes.cook")
+//	recipe.AddDetectedImages("recipes/Pancakes.cook")
+//	images, _ := cooklang.ImageSources(recipe, "recipes", "", cooklang.ImagesEmbedded)
+//	docx, err := renderers.DocxRenderer{}.Render(recipe, renderers.RendererOptions{Images: images})
+//	if err == nil {
+//	    os.WriteFile("Pancakes.docx", []byte(docx), 0o644)
+//	}
+type DocxRenderer struct {
+	Options RendererOptions // Locale, units and quantity format; the zero value renders English
+}
+
+// docxMaxImageWidth is the widest image in a document, in EMUs (6 inches, the
+// width of a page without its margins).
+const docxMaxImageWidth = 6 * 914400
+
+// docxEMUPerPixel converts image pixels to EMUs at 96 dpi.
+const docxEMUPerPixel = 9525
+
+// Render renders the recipe as a Word document with the given options. Of the
+// options, the ones for display apply except the nutrition label, baker's
+// percentages and URL.
+func (dr DocxRenderer) Render(recipe *cooklang.Recipe, opts RendererOptions) (string, error) {
+	dr.Options = opts
+	return dr.RenderRecipe(recipe)
+}
+
+// RenderRecipe renders the recipe as a Word document with the renderer's Options.
+//
+// Parameters:
+//   - recipe: The parsed Cooklang recipe to render
+//
+// Returns:
+//   - string: The .docx file's bytes
+//   - error: An error wrapping ErrEncoding if the archive cannot be written
+func (dr DocxRenderer) RenderRecipe(recipe *cooklang.Recipe) (string, error) {
+	data := dr.Options.templateData(recipe)
+	picture := docxFirstImage(data.Images)
+
+	files := []struct{ name, content string }{
+		{"[Content_Types].xml", docxContentTypes},
+		{"_rels/.rels", docxRootRels},
+		{"docProps/core.xml", docxCoreProperties(data.Title, data.Language)},
+		{"word/styles.xml", fmt.Sprintf(docxStyles, docxEscape(data.Language))},
+		{"word/numbering.xml", docxNumbering(data.Steps)},
+		{"word/_rels/document.xml.rels", docxDocumentRels(picture)},
+		{"word/document.xml", docxDocument(data, picture)},
+	}
+	if picture != nil {
+		files = append(files, struct{ name, content string }{"word/media/image1." + picture.format, string(picture.data)})
+	}
+
+	var out bytes.Buffer
+	archive := zip.NewWriter(&out)
+	for _, file := range files {
+		w, err := archive.CreateHeader(&zip.FileHeader{Name: file.name, Method: zip.Deflate})
+		if err != nil {
+			return "", fmt.Errorf("%w: docx: %w", ErrEncoding, err)
+		}
+		if _, err := w.Write([]byte(file.content)); err != nil {
+			return "", fmt.Errorf("%w: docx: %w", ErrEncoding, err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return "", fmt.Errorf("%w: docx: %w", ErrEncoding, err)
+	}
+	return out.String(), nil
+}
+
+// docxImage is an image embedded in a document, with its size in EMUs.
+type docxImage struct {
+	data          []byte
+	format        string // "jpeg", "png" or "gif", also the file extension
+	width, height int
+}
+
+// docxFirstImage decodes the first image source if it is a data URI of a JPEG,
+// PNG or GIF image, and returns nil otherwise.
+func docxFirstImage(sources []string) *docxImage {
+	if len(sources) == 0 {
+		return nil
+	}
+	header, payload, ok := strings.Cut(sources[0], ",")
+	if !ok || !strings.HasPrefix(header, "data:") || !strings.HasSuffix(header, ";base64") {
+		return nil
+	}
+	data, err := base64.StdEncoding.DecodeString(payload)
+	if err != nil {
+		return nil
+	}
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || config.Width == 0 || config.Height == 0 {
+		return nil
+	}
+	width, height := config.Width*docxEMUPerPixel, config.Height*docxEMUPerPixel
+	if width > docxMaxImageWidth {
+		height = height * docxMaxImageWidth / width
+		width = docxMaxImageWidth
+	}
+	return &docxImage{data: data, format: format, width: width, height: height}
+}
+
+// docxDocument writes word/document.xml.
+func docxDocument(data TemplateData, picture *docxImage) string {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"` +
+		` xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"` +
+		` xmlns:wp="http://schemas.openxmlformats.org/drawingml/2006/wordprocessingDrawing"` +
+		` xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main"` +
+		` xmlns:pic="http://schemas.openxmlformats.org/drawingml/2006/picture">` + "\n")
+	b.WriteString("<w:body>\n")
+
+	docxParagraph(&b, "Title", 0, docxRun(data.Title, false, false))
+	if picture != nil {
+		fmt.Fprintf(&b, `<w:p><w:r><w:drawing><wp:inline distT="0" distB="0" distL="0" distR="0">`+
+			`<wp:extent cx="%[1]d" cy="%[2]d"/><wp:docPr id="1" name="Picture 1"/>`+
+			`<a:graphic><a:graphicData uri="http://schemas.openxmlformats.org/drawingml/2006/picture">`+
+			`<pic:pic><pic:nvPicPr><pic:cNvPr id="1" name="image1.%[3]s"/><pic:cNvPicPr/></pic:nvPicPr>`+
+			`<pic:blipFill><a:blip r:embed="rIdImage1"/><a:stretch><a:fillRect/></a:stretch></pic:blipFill>`+
+			`<pic:spPr><a:xfrm><a:off x="0" y="0"/><a:ext cx="%[1]d" cy="%[2]d"/></a:xfrm>`+
+			`<a:prstGeom prst="rect"><a:avLst/></a:prstGeom></pic:spPr></pic:pic>`+
+			`</a:graphicData></a:graphic></wp:inline></w:drawing></w:r></w:p>`+"\n",
+			picture.width, picture.height, picture.format)
+	}
+
+	// Recipe information as a two-column table
+	if len(data.Metadata) > 0 {
+		docxParagraph(&b, "Heading1", 0, docxRun(data.Labels.RecipeInformation, false, false))
+		b.WriteString(`<w:tbl><w:tblPr><w:tblStyle w:val="TableGrid"/><w:tblW w:w="5000" w:type="pct"/></w:tblPr>`)
+		b.WriteString(`<w:tblGrid><w:gridCol w:w="2500"/><w:gridCol w:w="6500"/></w:tblGrid>` + "\n")
+		for _, field := range data.Metadata {
+			fmt.Fprintf(&b, "<w:tr><w:tc><w:p>%s</w:p></w:tc><w:tc><w:p>%s</w:p></w:tc></w:tr>\n",
+				docxRun(field.Label, true, false), docxRun(field.Value, false, false))
+		}
+		b.WriteString("</w:tbl>\n")
+	}
+
+	if len(data.Ingredients) > 0 {
+		docxParagraph(&b, "Heading1", 0, docxRun(data.Labels.Ingredients, false, false))
+		for _, group := range data.IngredientGroups {
+			if group.Title != "" {
+				docxParagraph(&b, "Heading2", 0, docxRun(group.Title, false, false))
+			}
+			for _, ingredient := range group.Ingredients {
+				runs := docxRun(ingredient.Name, false, false)
+				if amount := strings.TrimSpace(ingredient.Amount + " " + ingredient.Unit); amount != "" {
+					runs = docxRun(amount+" ", true, false) + runs
+				}
+				if ingredient.Annotation != "" {
+					runs += docxRun(", "+ingredient.Annotation, false, false)
+				}
+				if ingredient.Optional {
+					runs += docxRun(" ("+data.Labels.Optional+")", false, true)
+				}
+				if ingredient.Note != "" {
+					runs += docxRun(" ("+ingredient.Note+")", false, true)
+				}
+				docxParagraph(&b, "ListParagraph", docxBulletList, runs)
+			}
+		}
+	}
+
+	docxParagraph(&b, "Heading1", 0, docxRun(data.Labels.Instructions, false, false))
+	list := docxFirstStepList
+	for i, step := range data.Steps {
+		if step.NewSection {
+			docxParagraph(&b, "Heading2", 0, docxRun(step.Section, false, false))
+			if i > 0 {
+				list++
+			}
+		}
+		if step.Note {
+			docxParagraph(&b, "", 0, docxRun(step.Text, false, true))
+			continue
+		}
+		runs := docxRun(step.Text, false, false)
+		for _, callout := range step.Callouts {
+			runs += "<w:r><w:br/></w:r>" + docxRun("↩ "+callout, false, true)
+		}
+		docxParagraph(&b, "ListParagraph", list, runs)
+	}
+
+	b.WriteString(`<w:sectPr><w:pgMar w:top="1440" w:right="1440" w:bottom="1440" w:left="1440" w:header="708" w:footer="708" w:gutter="0"/></w:sectPr>` + "\n")
+	b.WriteString("</w:body>\n</w:document>\n")
+	return b.String()
+}
+
+// Numbering instances of word/numbering.xml: one bulleted list for the
+// ingredients, and one numbered list per section of steps.
+const (
+	docxBulletList    = 1
+	docxFirstStepList = 2
+)
+
+// docxParagraph writes a paragraph with a style and, if list is not 0, in a
+// numbering instance.
+func docxParagraph(b *strings.Builder, style string, list int, runs string) {
+	b.WriteString("<w:p>")
+	if style != "" || list != 0 {
+		b.WriteString("<w:pPr>")
+		if style != "" {
+			fmt.Fprintf(b, `<w:pStyle w:val="%s"/>`, style)
+		}
+		if list != 0 {
+			fmt.Fprintf(b, `<w:numPr><w:ilvl w:val="0"/><w:numId w:val="%d"/></w:numPr>`, list)
+		}
+		b.WriteString("</w:pPr>")
+	}
+	b.WriteString(runs)
+	b.WriteString("</w:p>\n")
+}
+
+// docxRun returns a run of text, in bold or italics.
+func docxRun(text string, bold, italic bool) string {
+	var b strings.Builder
+	b.WriteString("<w:r>")
+	if bold || italic {
+		b.WriteString("<w:rPr>")
+		if bold {
+			b.WriteString("<w:b/>")
+		}
+		if italic {
+			b.WriteString("<w:i/>")
+		}
+		b.WriteString("</w:rPr>")
+	}
+	b.WriteString(`<w:t xml:space="preserve">` + docxEscape(text) + "</w:t></w:r>")
+	return b.String()
+}
+
+// docxEscape escapes text for XML, replacing characters XML cannot contain.
+func docxEscape(text string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(text))
+	return b.String()
+}
+
+// docxNumbering writes word/numbering.xml, with a numbered list for each section.
+func docxNumbering(steps []TemplateStep) string {
+	lists := 1
+	for i, step := range steps {
+		if step.NewSection && i > 0 {
+			lists++
+		}
+	}
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<w:numbering xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">` + "\n")
+	b.WriteString(`<w:abstractNum w:abstractNumId="0"><w:multiLevelType w:val="singleLevel"/>` +
+		`<w:lvl w:ilvl="0"><w:start w:val="1"/><w:numFmt w:val="bullet"/><w:lvlText w:val="•"/><w:lvlJc w:val="left"/>` +
+		`<w:pPr><w:ind w:left="720" w:hanging="360"/></w:pPr></w:lvl></w:abstractNum>` + "\n")
+	b.WriteString(`<w:abstractNum w:abstractNumId="1"><w:multiLevelType w:val="singleLevel"/>` +
+		`<w:lvl w:ilvl="0"><w:start w:val="1"/><w:numFmt w:val="decimal"/><w:lvlText w:val="%1."/><w:lvlJc w:val="left"/>` +
+		`<w:pPr><w:ind w:left="720" w:hanging="360"/></w:pPr></w:lvl></w:abstractNum>` + "\n")
+	fmt.Fprintf(&b, `<w:num w:numId="%d"><w:abstractNumId w:val="0"/></w:num>`+"\n", docxBulletList)
+	for i := range lists {
+		fmt.Fprintf(&b, `<w:num w:numId="%d"><w:abstractNumId w:val="1"/>`+
+			`<w:lvlOverride w:ilvl="0"><w:startOverride w:val="1"/></w:lvlOverride></w:num>`+"\n", docxFirstStepList+i)
+	}
+	b.WriteString("</w:numbering>\n")
+	return b.String()
+}
+
+// docxDocumentRels writes word/_rels/document.xml.rels.
+func docxDocumentRels(picture *docxImage) string {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` + "\n")
+	b.WriteString(`<Relationship Id="rIdStyles" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>` + "\n")
+	b.WriteString(`<Relationship Id="rIdNumbering" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/numbering" Target="numbering.xml"/>` + "\n")
+	if picture != nil {
+		fmt.Fprintf(&b, `<Relationship Id="rIdImage1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/image" Target="media/image1.%s"/>`+"\n", picture.format)
+	}
+	b.WriteString("</Relationships>\n")
+	return b.String()
+}
+
+// docxCoreProperties writes docProps/core.xml, the document's title and language.
+func docxCoreProperties(title, language string) string {
+	return xml.Header + `<cp:coreProperties xmlns:cp="http://schemas.openxmlformats.org/package/2006/metadata/core-properties"` +
+		` xmlns:dc="http://purl.org/dc/elements/1.1/">` +
+		"<dc:title>" + docxEscape(title) + "</dc:title><dc:language>" + docxEscape(language) + "</dc:language>" +
+		"</cp:coreProperties>\n"
+}
+
+// docxContentTypes is [Content_Types].xml, the media types of the parts.
+const docxContentTypes = xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+<Default Extension="xml" ContentType="application/xml"/>
+<Default Extension="jpeg" ContentType="image/jpeg"/>
+<Default Extension="png" ContentType="image/png"/>
+<Default Extension="gif" ContentType="image/gif"/>
+<Override PartName="/word/document.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.document.main+xml"/>
+<Override PartName="/word/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.styles+xml"/>
+<Override PartName="/word/numbering.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.numbering+xml"/>
+<Override PartName="/docProps/core.xml" ContentType="application/vnd.openxmlformats-package.core-properties+xml"/>
+</Types>
+`
+
+// docxRootRels is _rels/.rels, which points to the document and its properties.
+const docxRootRels = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="word/document.xml"/>
+<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/package/2006/relationships/metadata/core-properties" Target="docProps/core.xml"/>
+</Relationships>
+`
+
+// docxStyles is word/styles.xml, with the language (%s) as the proofing language.
+const docxStyles = xml.Header + `<w:styles xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">
+<w:docDefaults>
+<w:rPrDefault><w:rPr><w:rFonts w:ascii="Calibri" w:hAnsi="Calibri" w:eastAsia="Calibri" w:cs="Calibri"/><w:sz w:val="22"/><w:lang w:val="%s"/></w:rPr></w:rPrDefault>
+<w:pPrDefault><w:pPr><w:spacing w:after="120" w:line="276" w:lineRule="auto"/></w:pPr></w:pPrDefault>
+</w:docDefaults>
+<w:style w:type="paragraph" w:default="1" w:styleId="Normal"><w:name w:val="Normal"/><w:qFormat/></w:style>
+<w:style w:type="paragraph" w:styleId="Title"><w:name w:val="Title"/><w:basedOn w:val="Normal"/><w:next w:val="Normal"/><w:qFormat/><w:pPr><w:spacing w:after="240"/></w:pPr><w:rPr><w:sz w:val="52"/></w:rPr></w:style>
+<w:style w:type="paragraph" w:styleId="Heading1"><w:name w:val="heading 1"/><w:basedOn w:val="Normal"/><w:next w:val="Normal"/><w:qFormat/><w:pPr><w:keepNext/><w:spacing w:before="360" w:after="120"/><w:outlineLvl w:val="0"/></w:pPr><w:rPr><w:b/><w:sz w:val="32"/></w:rPr></w:style>
+<w:style w:type="paragraph" w:styleId="Heading2"><w:name w:val="heading 2"/><w:basedOn w:val="Normal"/><w:next w:val="Normal"/><w:qFormat/><w:pPr><w:keepNext/><w:spacing w:before="240" w:after="80"/><w:outlineLvl w:val="1"/></w:pPr><w:rPr><w:b/><w:sz w:val="26"/></w:rPr></w:style>
+<w:style w:type="paragraph" w:styleId="ListParagraph"><w:name w:val="List Paragraph"/><w:basedOn w:val="Normal"/><w:qFormat/><w:pPr><w:ind w:left="720"/></w:pPr></w:style>
+<w:style w:type="table" w:styleId="TableGrid"><w:name w:val="Table Grid"/><w:tblPr><w:tblBorders>` +
+	`<w:top w:val="single" w:sz="4" w:space="0" w:color="auto"/><w:left w:val="single" w:sz="4" w:space="0" w:color="auto"/>` +
+	`<w:bottom w:val="single" w:sz="4" w:space="0" w:color="auto"/><w:right w:val="single" w:sz="4" w:space="0" w:color="auto"/>` +
+	`<w:insideH w:val="single" w:sz="4" w:space="0" w:color="auto"/><w:insideV w:val="single" w:sz="4" w:space="0" w:color="auto"/>` +
+	`</w:tblBorders><w:tblCellMar><w:left w:w="108" w:type="dxa"/><w:right w:w="108" w:type="dxa"/></w:tblCellMar></w:tblPr></w:style>
+</w:styles>
+`
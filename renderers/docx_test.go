@@ -0,0 +1,131 @@
+package renderers
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"image"
+	"image/png"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/hilli/cooklang"
+)
+
+// readDocx returns the parts of a .docx file by name, checking that the XML parts
+// are well-formed.
+func readDocx(t *testing.T, docx string) map[string]string {
+	t.Helper()
+	archive, err := zip.NewReader(strings.NewReader(docx), int64(len(docx)))
+	if err != nil {
+		t.Fatalf("output is not a ZIP archive: %v", err)
+	}
+	parts := make(map[string]string)
+	for _, file := range archive.File {
+		r, err := file.Open()
+		if err != nil {
+			t.Fatalf("failed to open %s: %v", file.Name, err)
+		}
+		content, err := io.ReadAll(r)
+		r.Close()
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", file.Name, err)
+		}
+		parts[file.Name] = string(content)
+		if strings.HasSuffix(file.Name, ".xml") || strings.HasSuffix(file.Name, ".rels") {
+			decoder := xml.NewDecoder(bytes.NewReader(content))
+			for {
+				if _, err := decoder.Token(); err == io.EOF {
+					break
+				} else if err != nil {
+					t.Fatalf("%s is not well-formed: %v\n%s", file.Name, err, content)
+				}
+			}
+		}
+	}
+	return parts
+}
+
+func TestDocxRenderer(t *testing.T) {
+	recipe, err := cooklang.ParseString(`---
+title: Fish & Chips
+servings: 2
+prep_time: 20 minutes
+---
+
+== Batter ==
+Whisk @flour{200%g} and @beer{250%ml}(cold) in a #bowl{}.
+
+> Keep the batter cold.
+
+== Frying ==
+Heat @oil{} to 180°C.
+
+Fry the @cod{2%fillets} for ~{6%minutes}.
+`)
+	if err != nil {
+		t.Fatalf("Failed to parse recipe: %v", err)
+	}
+
+	docx, err := DocxRenderer{}.Render(recipe, RendererOptions{})
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	parts := readDocx(t, docx)
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "word/document.xml", "word/styles.xml", "word/numbering.xml", "word/_rels/document.xml.rels", "docProps/core.xml"} {
+		if _, ok := parts[name]; !ok {
+			t.Errorf("expected a %s part", name)
+		}
+	}
+
+	document := parts["word/document.xml"]
+	for _, expected := range []string{
+		`<w:pStyle w:val="Title"/></w:pPr><w:r><w:t xml:space="preserve">Fish &amp; Chips</w:t>`,
+		`<w:t xml:space="preserve">Servings</w:t></w:r></w:p></w:tc><w:tc><w:p><w:r><w:t xml:space="preserve">2</w:t>`,
+		`<w:numId w:val="1"/></w:numPr></w:pPr><w:r><w:rPr><w:b/></w:rPr><w:t xml:space="preserve">250 ml </w:t></w:r><w:r><w:t xml:space="preserve">beer</w:t></w:r><w:r><w:t xml:space="preserve">, cold</w:t>`,
+		`<w:t xml:space="preserve">Batter</w:t>`,
+		`<w:numId w:val="2"/></w:numPr></w:pPr><w:r><w:t xml:space="preserve">Whisk flour and beer in a bowl.</w:t>`,
+		`<w:r><w:rPr><w:i/></w:rPr><w:t xml:space="preserve">Keep the batter cold.</w:t>`,
+		`<w:numId w:val="3"/></w:numPr></w:pPr><w:r><w:t xml:space="preserve">Heat oil to 180°C.</w:t>`,
+		`<w:numId w:val="3"/></w:numPr></w:pPr><w:r><w:t xml:space="preserve">Fry the cod for 6 minutes.</w:t>`,
+	} {
+		if !strings.Contains(document, expected) {
+			t.Errorf("expected document to contain %q, got:\n%s", expected, document)
+		}
+	}
+	if strings.Contains(document, "<w:drawing>") {
+		t.Error("expected no image without a data URI")
+	}
+	if !strings.Contains(parts["word/numbering.xml"], `<w:num w:numId="3">`) {
+		t.Errorf("expected a numbered list per section, got:\n%s", parts["word/numbering.xml"])
+	}
+	if !strings.Contains(parts["docProps/core.xml"], "<dc:title>Fish &amp; Chips</dc:title>") {
+		t.Errorf("expected the title in the properties, got:\n%s", parts["docProps/core.xml"])
+	}
+
+	// German labels, and the first image embedded and scaled to the page width
+	var picture bytes.Buffer
+	if err := png.Encode(&picture, image.NewRGBA(image.Rect(0, 0, 1152, 576))); err != nil {
+		t.Fatal(err)
+	}
+	opts := RendererOptions{Locale: "de", Images: []string{"data:image/png;base64," + base64.StdEncoding.EncodeToString(picture.Bytes()), "data:image/png;base64,AAAA"}}
+	docx, err = DocxRenderer{}.Render(recipe, opts)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	parts = readDocx(t, docx)
+	if parts["word/media/image1.png"] != picture.String() {
+		t.Error("expected the first image as word/media/image1.png")
+	}
+	if !strings.Contains(parts["word/document.xml"], `<wp:extent cx="5486400" cy="2743200"/>`) {
+		t.Errorf("expected the image scaled to 6 inches, got:\n%s", parts["word/document.xml"])
+	}
+	if !strings.Contains(parts["word/_rels/document.xml.rels"], `Target="media/image1.png"`) {
+		t.Errorf("expected a relationship to the image, got:\n%s", parts["word/_rels/document.xml.rels"])
+	}
+	if !strings.Contains(parts["word/document.xml"], ">Zutaten</w:t>") || !strings.Contains(parts["word/styles.xml"], `<w:lang w:val="de"/>`) {
+		t.Error("expected German labels and proofing language")
+	}
+}
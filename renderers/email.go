@@ -0,0 +1,251 @@
+package renderers
+
+import (
+	"fmt"
+	"html"
+	"net/url"
+	"strings"
+
+	"github.com/hilli/cooklang"
+)
+
+// EmailRenderer renders recipes as a single HTML document to paste into or send
+// from an email client. Email clients drop <style> blocks and classes and ignore
+// most layout CSS, so the recipe is laid out with tables up to 600px wide and every
+// element is styled with its own style attribute.
+//
+// Email clients cannot load images from relative paths, and many block data URIs,
+// so only images with http(s) URLs are shown; relative paths are resolved against
+// ImageBaseURL, and left out without it. The nutrition label and baker's
+// percentages are not included.
+//
+// Example usage:
+//
+//	recipe, _ := cooklang.ParseFile("recipes/pasta.cook")
+//	email := renderers.EmailRenderer{ImageBaseURL: "https://example.com/recipes/"}
+//	page, err := email.Render(recipe, renderers.RendererOptions{})
+type EmailRenderer struct {
+	Options RendererOptions // Locale and labels; the zero value renders English
+	// ImageBaseURL is the address relative image paths are resolved against, e.g.
+	// "https://example.com/recipes/" for "pasta.jpg".
+	ImageBaseURL string
+}
+
+// Inline styles of the email elements
+const (
+	emailFont           = "font-family: Georgia, 'Times New Roman', serif; color: #222222;"
+	emailTitleStyle     = "margin: 0 0 12px; font-size: 26px; line-height: 1.2; " + emailFont
+	emailH2Style        = "margin: 24px 0 8px; padding-bottom: 4px; border-bottom: 1px solid #999999; font-size: 18px; " + emailFont
+	emailH3Style        = "margin: 16px 0 6px; font-size: 15px; " + emailFont
+	emailTextStyle      = "margin: 0 0 12px; font-size: 15px; line-height: 1.5; " + emailFont
+	emailListStyle      = "margin: 0 0 12px; padding-left: 24px; font-size: 15px; line-height: 1.5; " + emailFont
+	emailItemStyle      = "margin: 0 0 6px;"
+	emailLabelStyle     = "padding: 4px 12px 4px 0; text-align: left; vertical-align: top; font-size: 14px; white-space: nowrap; " + emailFont
+	emailValueStyle     = "padding: 4px 0; vertical-align: top; font-size: 14px; " + emailFont
+	emailNoteStyle      = "margin: 0 0 12px; padding: 8px 12px; border-left: 3px solid #cccccc; background-color: #f7f7f7; font-size: 14px; font-style: italic; " + emailFont
+	emailMutedStyle     = "color: #666666; font-style: italic;"
+	emailImageStyle     = "display: block; width: 100%; max-width: 552px; height: auto; margin: 0 0 12px; border: 0;"
+	emailWrapStyle      = "background-color: #f4f4f4;"
+	emailCardStyle      = "max-width: 600px; background-color: #ffffff;"
+	emailTableAttrs     = `role="presentation" width="100%" cellpadding="0" cellspacing="0" border="0"`
+	emailImageWidth     = 552 // The card's width less its padding
+	emailStepImageWidth = 528 // The image width less the list's indent
+	emailCardPadding    = "padding: 24px;"
+)
+
+// Render renders the recipe as an email HTML document with the given options.
+func (er EmailRenderer) Render(recipe *cooklang.Recipe, opts RendererOptions) (string, error) {
+	er.Options = opts
+	return er.RenderRecipe(recipe)
+}
+
+// RenderRecipe renders the recipe as an email HTML document with the renderer's
+// Options. An ImageBaseURL that is not an absolute URL returns an error.
+func (er EmailRenderer) RenderRecipe(recipe *cooklang.Recipe) (string, error) {
+	var base *url.URL
+	if er.ImageBaseURL != "" {
+		var err error
+		if base, err = url.Parse(er.ImageBaseURL); err != nil || !base.IsAbs() {
+			return "", fmt.Errorf("image base URL must be an absolute URL: %s", er.ImageBaseURL)
+		}
+	}
+	data := er.Options.templateData(recipe)
+	labels := data.Labels
+	var result strings.Builder
+
+	result.WriteString("<!DOCTYPE html>\n")
+	fmt.Fprintf(&result, "<html lang=\"%s\">\n", html.EscapeString(data.Language))
+	result.WriteString("<head>\n")
+	result.WriteString("  <meta charset=\"UTF-8\">\n")
+	result.WriteString("  <meta name=\"viewport\" content=\"width=device-width, initial-scale=1.0\">\n")
+	fmt.Fprintf(&result, "  <title>%s</title>\n", html.EscapeString(data.Title))
+	result.WriteString("</head>\n")
+	fmt.Fprintf(&result, "<body style=\"margin: 0; padding: 0; %s\">\n", emailWrapStyle)
+	fmt.Fprintf(&result, "<table %s style=\"%s\">\n<tr><td align=\"center\" style=\"padding: 16px;\">\n", emailTableAttrs, emailWrapStyle)
+	fmt.Fprintf(&result, "<table %s style=\"%s\">\n<tr><td style=\"%s\">\n", emailTableAttrs, emailCardStyle, emailCardPadding)
+
+	fmt.Fprintf(&result, "  <h1 style=\"%s\">%s</h1>\n", emailTitleStyle, html.EscapeString(data.Title))
+	for _, src := range data.Images {
+		if src, ok := emailImageURL(base, src); ok {
+			fmt.Fprintf(&result, "  <img src=\"%s\" alt=\"%s\" width=\"%d\" style=\"%s\">\n", html.EscapeString(src), html.EscapeString(data.Title), emailImageWidth, emailImageStyle)
+		}
+	}
+	if data.Recipe.Description != "" {
+		fmt.Fprintf(&result, "  <p style=\"%s\">%s</p>\n", emailTextStyle, html.EscapeString(data.Recipe.Description))
+	}
+
+	var fields []TemplateField
+	for _, field := range data.Metadata {
+		if field.Key != "description" {
+			fields = append(fields, field)
+		}
+	}
+	if len(fields) > 0 {
+		fmt.Fprintf(&result, "  <h2 style=\"%s\">%s</h2>\n", emailH2Style, html.EscapeString(labels.RecipeInformation))
+		fmt.Fprintf(&result, "  <table %s>\n", emailTableAttrs)
+		for _, field := range fields {
+			fmt.Fprintf(&result, "    <tr><th style=\"%s\">%s</th><td style=\"%s\">%s</td></tr>\n",
+				emailLabelStyle, html.EscapeString(field.Label), emailValueStyle, html.EscapeString(field.Value))
+		}
+		result.WriteString("  </table>\n")
+	}
+
+	if len(data.Ingredients) > 0 {
+		fmt.Fprintf(&result, "  <h2 style=\"%s\">%s</h2>\n", emailH2Style, html.EscapeString(labels.Ingredients))
+		for _, group := range data.IngredientGroups {
+			if group.Title != "" {
+				fmt.Fprintf(&result, "  <h3 style=\"%s\">%s</h3>\n", emailH3Style, html.EscapeString(group.Title))
+			}
+			fmt.Fprintf(&result, "  <ul style=\"%s\">\n", emailListStyle)
+			for _, ingredient := range group.Ingredients {
+				fmt.Fprintf(&result, "    <li style=\"%s\">", emailItemStyle)
+				if amount := strings.TrimSpace(ingredient.Amount + " " + ingredient.Unit); amount != "" {
+					fmt.Fprintf(&result, "<strong>%s</strong> ", html.EscapeString(amount))
+				}
+				result.WriteString(html.EscapeString(ingredient.Name))
+				if ingredient.Annotation != "" {
+					result.WriteString(", " + html.EscapeString(ingredient.Annotation))
+				}
+				if ingredient.Note != "" {
+					fmt.Fprintf(&result, " <span style=\"%s\">(%s)</span>", emailMutedStyle, html.EscapeString(ingredient.Note))
+				}
+				if ingredient.Optional {
+					fmt.Fprintf(&result, " <span style=\"%s\">(%s)</span>", emailMutedStyle, html.EscapeString(labels.Optional))
+				}
+				result.WriteString("</li>\n")
+			}
+			result.WriteString("  </ul>\n")
+		}
+	}
+
+	// Each section and each run of steps between notes is a list of its own; start
+	// keeps the numbering going after a note.
+	fmt.Fprintf(&result, "  <h2 style=\"%s\">%s</h2>\n", emailH2Style, html.EscapeString(labels.Instructions))
+	inList := false
+	closeList := func() {
+		if inList {
+			result.WriteString("  </ol>\n")
+			inList = false
+		}
+	}
+	for _, step := range data.Steps {
+		if step.NewSection {
+			closeList()
+			fmt.Fprintf(&result, "  <h3 style=\"%s\">%s</h3>\n", emailH3Style, html.EscapeString(step.Section))
+		}
+		if step.Note {
+			closeList()
+			fmt.Fprintf(&result, "  <p style=\"%s\">%s</p>\n", emailNoteStyle, html.EscapeString(step.Text))
+			continue
+		}
+		if !inList {
+			if step.Number > 1 {
+				fmt.Fprintf(&result, "  <ol start=\"%d\" style=\"%s\">\n", step.Number, emailListStyle)
+			} else {
+				fmt.Fprintf(&result, "  <ol style=\"%s\">\n", emailListStyle)
+			}
+			inList = true
+		}
+		fmt.Fprintf(&result, "    <li style=\"%s\">", emailItemStyle)
+		var text strings.Builder
+		for component := range step.Step.Components() {
+			er.renderComponent(&text, component, labels)
+		}
+		result.WriteString(strings.TrimSpace(text.String()))
+		for _, callout := range step.Callouts {
+			fmt.Fprintf(&result, "<br><span style=\"%s\">↩ %s</span>", emailMutedStyle, html.EscapeString(callout))
+		}
+		for _, src := range step.Images {
+			if src, ok := emailImageURL(base, src); ok {
+				fmt.Fprintf(&result, "<img src=\"%s\" alt=\"%s\" width=\"%d\" style=\"%s\">", html.EscapeString(src), html.EscapeString(data.Title), emailStepImageWidth, emailImageStyle)
+			}
+		}
+		result.WriteString("</li>\n")
+	}
+	closeList()
+
+	result.WriteString("</td></tr>\n</table>\n")
+	result.WriteString("</td></tr>\n</table>\n")
+	result.WriteString("</body>\n")
+	result.WriteString("</html>\n")
+	return result.String(), nil
+}
+
+// renderComponent writes a step component with inline markup: ingredients in bold
+// and cookware in italics.
+func (er EmailRenderer) renderComponent(result *strings.Builder, component cooklang.StepComponent, labels Strings) {
+	switch comp := component.(type) {
+	case *cooklang.Ingredient:
+		fmt.Fprintf(result, "<strong>%s</strong>", html.EscapeString(comp.Name))
+		if comp.Quantity.HasAmount() {
+			fmt.Fprintf(result, " (%s)", html.EscapeString(strings.TrimSpace(er.Options.amount(comp)+" "+comp.Unit)))
+		}
+		if comp.Optional {
+			fmt.Fprintf(result, " <span style=\"%s\">(%s)</span>", emailMutedStyle, html.EscapeString(labels.Optional))
+		}
+		if comp.Annotation != "" {
+			fmt.Fprintf(result, " (%s)", html.EscapeString(comp.Annotation))
+		}
+	case *cooklang.Cookware:
+		fmt.Fprintf(result, "<em>%s</em>", html.EscapeString(comp.Name))
+		if quantity := comp.QuantityLabel(); quantity != "" {
+			fmt.Fprintf(result, " (%s)", html.EscapeString(quantity))
+		}
+		if comp.Annotation != "" {
+			fmt.Fprintf(result, " (%s)", html.EscapeString(comp.Annotation))
+		}
+	case *cooklang.Timer:
+		text := comp.String()
+		if comp.Name != "" {
+			text = comp.Name + " (" + text + ")"
+		}
+		result.WriteString("⏲️ " + html.EscapeString(text))
+	case *cooklang.Instruction:
+		result.WriteString(html.EscapeString(comp.Text))
+	case *cooklang.Temperature:
+		result.WriteString(html.EscapeString(er.Options.temperature(comp)))
+	case *cooklang.Comment:
+		fmt.Fprintf(result, "<span style=\"%s\">(%s)</span>", emailMutedStyle, html.EscapeString(comp.Text))
+	}
+}
+
+// emailImageURL returns the absolute URL of an image source, resolving relative
+// paths against base. Data URIs, and relative paths without a base, are not shown.
+func emailImageURL(base *url.URL, src string) (string, bool) {
+	ref, err := url.Parse(strings.ReplaceAll(src, "\\", "/"))
+	if err != nil {
+		return "", false
+	}
+	switch {
+	case ref.Scheme == "http" || ref.Scheme == "https":
+		return ref.String(), true
+	case ref.Scheme != "" || base == nil:
+		return "", false
+	}
+	if !strings.HasSuffix(base.Path, "/") {
+		directory := *base
+		directory.Path += "/"
+		base = &directory
+	}
+	return base.ResolveReference(ref).String(), true
+}
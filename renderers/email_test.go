@@ -0,0 +1,69 @@
+package renderers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hilli/cooklang"
+)
+
+func TestEmailRenderer(t *testing.T) {
+	recipe, err := cooklang.ParseString(`---
+title: Fish & Chips
+servings: 2
+images: photos/fish chips.jpg, https://cdn.example.com/fish.jpg
+---
+
+== Batter ==
+Whisk @flour{200%g} and @beer{250%ml}(cold) in a #bowl{}.
+
+> Keep the batter cold.
+
+Rest for ~{10%minutes}.
+
+== Frying ==
+Heat @oil{} to 180°C.
+`)
+	if err != nil {
+		t.Fatalf("Failed to parse recipe: %v", err)
+	}
+
+	page := renderRecipe(t, EmailRenderer{ImageBaseURL: "https://example.com/recipes"}, recipe)
+	for _, expected := range []string{
+		"<!DOCTYPE html>",
+		`<table role="presentation" width="100%" cellpadding="0" cellspacing="0" border="0" style="max-width: 600px;`,
+		">Fish &amp; Chips</h1>",
+		`<img src="https://example.com/recipes/photos/fish%20chips.jpg"`,
+		`<img src="https://cdn.example.com/fish.jpg"`,
+		">Servings</th><td style=",
+		"<strong>250 ml</strong> beer, cold</li>",
+		"Whisk <strong>flour</strong> (200 g) and <strong>beer</strong> (250 ml) (cold) in a <em>bowl</em>.</li>",
+		">Keep the batter cold.</p>\n  <ol start=\"2\"",
+		">Frying</h3>\n  <ol style=",
+	} {
+		if !strings.Contains(page, expected) {
+			t.Errorf("expected email to contain %q, got:\n%s", expected, page)
+		}
+	}
+	for _, unexpected := range []string{"<style", "class="} {
+		if strings.Contains(page, unexpected) {
+			t.Errorf("expected no %q in email, got:\n%s", unexpected, page)
+		}
+	}
+
+	// Relative images are left out without a base URL
+	page = renderRecipe(t, EmailRenderer{}, recipe)
+	if strings.Contains(page, "photos/fish") || !strings.Contains(page, "https://cdn.example.com/fish.jpg") {
+		t.Errorf("expected only the absolute image, got:\n%s", page)
+	}
+
+	// Email clients block data URIs
+	page = renderRecipe(t, EmailRenderer{Options: RendererOptions{Images: []string{"data:image/png;base64,AAAA"}}}, recipe)
+	if strings.Contains(page, "<img") {
+		t.Errorf("expected no data URI image, got:\n%s", page)
+	}
+
+	if _, err := (EmailRenderer{ImageBaseURL: "recipes/"}).RenderRecipe(recipe); err == nil {
+		t.Error("expected an error for a relative base URL")
+	}
+}
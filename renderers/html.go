@@ -3,16 +3,36 @@ package renderers
 import (
 	"fmt"
 	"html"
+	"html/template"
 	"strings"
 
 	"github.com/hilli/cooklang"
 )
 
 // HTMLRenderer renders recipes in HTML format
-type HTMLRenderer struct{}
+type HTMLRenderer struct {
+	Options RendererOptions // Locale and labels; the zero value renders English
+	// Template renders the recipe with the set's HTMLTemplateName template instead
+	// of the built-in markup; see ParseTemplates and TemplateData.
+	Template *template.Template
+}
+
+// Render renders the recipe as an HTML fragment with the given options, or with
+// Template if it is set.
+func (hr HTMLRenderer) Render(recipe *cooklang.Recipe, opts RendererOptions) (string, error) {
+	hr.Options = opts
+	return hr.RenderRecipe(recipe)
+}
 
-func (hr HTMLRenderer) RenderRecipe(recipe *cooklang.Recipe) string {
+// RenderRecipe renders the recipe as an HTML fragment with the renderer's Options,
+// or with Template if it is set.
+func (hr HTMLRenderer) RenderRecipe(recipe *cooklang.Recipe) (string, error) {
+	if hr.Template != nil {
+		return executeTemplate(hr.Template, HTMLTemplateName, hr.Options.templateData(recipe))
+	}
 	var result strings.Builder
+	labels := hr.Options.labels()
+	recipe = hr.Options.prepare(recipe)
 
 	result.WriteString("<div class=\"recipe\">\n")
 
@@ -21,81 +41,81 @@ func (hr HTMLRenderer) RenderRecipe(recipe *cooklang.Recipe) string {
 		result.WriteString(fmt.Sprintf("  <h1 class=\"recipe-title\">%s</h1>\n", html.EscapeString(recipe.Title)))
 	}
 
+	images := hr.Options.Images
+	if hr.Options.NoImages {
+		images = nil
+	}
+	for _, src := range images {
+		fmt.Fprintf(&result, "  <img class=\"recipe-image\" src=\"%s\" alt=\"%s\">\n", html.EscapeString(src), html.EscapeString(recipe.Title))
+	}
+
 	// Metadata section
 
 	result.WriteString("  <div class=\"recipe-info\">\n")
-	result.WriteString("    <h2>Recipe Information</h2>\n")
+	fmt.Fprintf(&result, "    <h2>%s</h2>\n", html.EscapeString(labels.RecipeInformation))
 	result.WriteString("    <dl>\n")
 
 	if recipe.Description != "" {
-		result.WriteString(fmt.Sprintf("      <dt>Description</dt><dd>%s</dd>\n", html.EscapeString(recipe.Description)))
+		result.WriteString(fmt.Sprintf("      <dt>%s</dt><dd>%s</dd>\n", html.EscapeString(labels.Description), html.EscapeString(recipe.Description)))
 	}
 	if recipe.Cuisine != "" {
-		result.WriteString(fmt.Sprintf("      <dt>Cuisine</dt><dd>%s</dd>\n", html.EscapeString(recipe.Cuisine)))
+		result.WriteString(fmt.Sprintf("      <dt>%s</dt><dd>%s</dd>\n", html.EscapeString(labels.Cuisine), html.EscapeString(recipe.Cuisine)))
 	}
 	if recipe.Difficulty != "" {
-		result.WriteString(fmt.Sprintf("      <dt>Difficulty</dt><dd>%s</dd>\n", html.EscapeString(recipe.Difficulty)))
+		result.WriteString(fmt.Sprintf("      <dt>%s</dt><dd>%s</dd>\n", html.EscapeString(labels.Difficulty), html.EscapeString(labels.difficulty(recipe.Difficulty))))
 	}
 	if recipe.PrepTime != "" {
-		result.WriteString(fmt.Sprintf("      <dt>Prep Time</dt><dd>%s</dd>\n", html.EscapeString(recipe.PrepTime)))
+		result.WriteString(fmt.Sprintf("      <dt>%s</dt><dd>%s</dd>\n", html.EscapeString(labels.PrepTime), html.EscapeString(recipe.PrepTime)))
 	}
 	if recipe.TotalTime != "" {
-		result.WriteString(fmt.Sprintf("      <dt>Total Time</dt><dd>%s</dd>\n", html.EscapeString(recipe.TotalTime)))
+		result.WriteString(fmt.Sprintf("      <dt>%s</dt><dd>%s</dd>\n", html.EscapeString(labels.TotalTime), html.EscapeString(recipe.TotalTime)))
+	}
+	if oven, ok := recipe.OvenTemperature(); ok {
+		result.WriteString(fmt.Sprintf("      <dt>%s</dt><dd>%s</dd>\n", html.EscapeString(labels.Oven), html.EscapeString(hr.Options.temperature(oven))))
 	}
 	if recipe.Author != "" {
-		result.WriteString(fmt.Sprintf("      <dt>Author</dt><dd>%s</dd>\n", html.EscapeString(recipe.Author)))
+		result.WriteString(fmt.Sprintf("      <dt>%s</dt><dd>%s</dd>\n", html.EscapeString(labels.Author), html.EscapeString(recipe.Author)))
 	}
 	if recipe.Servings > 0 {
-		result.WriteString(fmt.Sprintf("      <dt>Servings</dt><dd>%g</dd>\n", recipe.Servings))
+		result.WriteString(fmt.Sprintf("      <dt>%s</dt><dd>%g</dd>\n", html.EscapeString(labels.Servings), recipe.Servings))
 	}
 	if len(recipe.Tags) > 0 {
-		result.WriteString(fmt.Sprintf("      <dt>Tags</dt><dd>%s</dd>\n", html.EscapeString(strings.Join(recipe.Tags, ", "))))
+		result.WriteString(fmt.Sprintf("      <dt>%s</dt><dd>%s</dd>\n", html.EscapeString(labels.Tags), html.EscapeString(strings.Join(recipe.Tags, ", "))))
 	}
 
 	result.WriteString("    </dl>\n")
 	result.WriteString("  </div>\n")
 
 	// Ingredients list
-	ingredients := recipe.GetIngredients()
-	if len(ingredients.Ingredients) > 0 {
+	if groups := hr.Options.ingredientGroups(recipe, labels, true); len(groups) > 0 {
 		result.WriteString("  <div class=\"recipe-ingredients\">\n")
-		result.WriteString("    <h2>Ingredients</h2>\n")
-		result.WriteString("    <ul>\n")
-
-		for _, ingredient := range ingredients.Ingredients {
-			result.WriteString("      <li>")
-			if ingredient.Quantity > 0 {
-				if ingredient.Unit != "" {
-					result.WriteString(fmt.Sprintf("<span class=\"quantity\">%g %s</span> <span class=\"ingredient\">%s</span>",
-						ingredient.Quantity, html.EscapeString(ingredient.Unit), html.EscapeString(ingredient.Name)))
-				} else {
-					result.WriteString(fmt.Sprintf("<span class=\"quantity\">%g</span> <span class=\"ingredient\">%s</span>",
-						ingredient.Quantity, html.EscapeString(ingredient.Name)))
-				}
-			} else if ingredient.Quantity == -1 {
-				// "some" quantity
-				if ingredient.Unit != "" {
-					result.WriteString(fmt.Sprintf("<span class=\"quantity\">some %s</span> <span class=\"ingredient\">%s</span>",
-						html.EscapeString(ingredient.Unit), html.EscapeString(ingredient.Name)))
-				} else {
-					result.WriteString(fmt.Sprintf("<span class=\"quantity\">some</span> <span class=\"ingredient\">%s</span>",
-						html.EscapeString(ingredient.Name)))
-				}
-			} else {
-				result.WriteString(fmt.Sprintf("<span class=\"ingredient\">%s</span>", html.EscapeString(ingredient.Name)))
+		fmt.Fprintf(&result, "    <h2>%s</h2>\n", html.EscapeString(labels.Ingredients))
+
+		for _, group := range groups {
+			if group.Title != "" {
+				fmt.Fprintf(&result, "    <h3 class=\"ingredient-group\">%s</h3>\n", html.EscapeString(group.Title))
+			}
+			result.WriteString("    <ul>\n")
+			for _, ingredient := range group.Ingredients {
+				hr.renderIngredientItem(&result, recipe, ingredient, labels)
 			}
-			result.WriteString("</li>\n")
+			result.WriteString("    </ul>\n")
 		}
 
-		result.WriteString("    </ul>\n")
 		result.WriteString("  </div>\n")
 	}
 
+	if panel := hr.Options.nutritionLabel(recipe); panel != "" {
+		result.WriteString(panel)
+	}
+	result.WriteString(hr.Options.bakersTable(recipe, true))
+
 	// Instructions
 	result.WriteString("  <div class=\"recipe-instructions\">\n")
-	result.WriteString("    <h2>Instructions</h2>\n")
+	fmt.Fprintf(&result, "    <h2>%s</h2>\n", html.EscapeString(labels.Instructions))
 	result.WriteString("    <ol>\n")
 
+	callouts := reservedCallouts(recipe, labels)
 	currentStep := recipe.FirstStep
 	for currentStep != nil {
 		// Check if the first component is a section - render it specially
@@ -112,15 +132,17 @@ func (hr HTMLRenderer) RenderRecipe(recipe *cooklang.Recipe) string {
 			if currentComponent != nil {
 				result.WriteString("      <li class=\"recipe-step\">\n        ")
 				for currentComponent != nil {
-					hr.renderComponent(&result, currentComponent)
+					hr.renderComponent(&result, currentComponent, labels)
 					currentComponent = currentComponent.GetNext()
 				}
+				hr.renderCallouts(&result, callouts[currentStep])
+				hr.renderStepImages(&result, currentStep.Images, recipe.Title)
 				result.WriteString("\n      </li>\n")
 			}
 		} else if note, ok := firstComp.(*cooklang.Note); ok {
-			// Render notes as blockquotes outside the ordered list
+			// Render notes as asides outside the ordered list
 			result.WriteString("    </ol>\n")
-			result.WriteString(fmt.Sprintf("    <blockquote class=\"recipe-note\">%s</blockquote>\n", html.EscapeString(note.Text)))
+			result.WriteString(fmt.Sprintf("    <aside class=\"recipe-note\">%s</aside>\n", html.EscapeString(note.Text)))
 			result.WriteString("    <ol>\n")
 		} else {
 			result.WriteString("      <li class=\"recipe-step\">\n        ")
@@ -128,9 +150,11 @@ func (hr HTMLRenderer) RenderRecipe(recipe *cooklang.Recipe) string {
 			// Render components in HTML format
 			currentComponent := currentStep.FirstComponent
 			for currentComponent != nil {
-				hr.renderComponent(&result, currentComponent)
+				hr.renderComponent(&result, currentComponent, labels)
 				currentComponent = currentComponent.GetNext()
 			}
+			hr.renderCallouts(&result, callouts[currentStep])
+			hr.renderStepImages(&result, currentStep.Images, recipe.Title)
 
 			result.WriteString("\n      </li>\n")
 		}
@@ -141,33 +165,67 @@ func (hr HTMLRenderer) RenderRecipe(recipe *cooklang.Recipe) string {
 	result.WriteString("  </div>\n")
 	result.WriteString("</div>\n")
 
-	return result.String()
+	return result.String(), nil
+}
+
+// renderIngredientItem renders an ingredient as an item of the ingredient list
+func (hr HTMLRenderer) renderIngredientItem(result *strings.Builder, recipe *cooklang.Recipe, ingredient *cooklang.Ingredient, labels Strings) {
+	result.WriteString("      <li>")
+	if ingredient.Quantity.HasAmount() {
+		if ingredient.Unit != "" {
+			result.WriteString(fmt.Sprintf("<span class=\"quantity\">%s %s</span> <span class=\"ingredient\">%s</span>",
+				html.EscapeString(hr.Options.amount(ingredient)), html.EscapeString(ingredient.Unit), html.EscapeString(ingredient.Name)))
+		} else {
+			result.WriteString(fmt.Sprintf("<span class=\"quantity\">%s</span> <span class=\"ingredient\">%s</span>",
+				html.EscapeString(hr.Options.amount(ingredient)), html.EscapeString(ingredient.Name)))
+		}
+	} else if ingredient.Quantity.IsSome() {
+		// "some" quantity
+		if ingredient.Unit != "" {
+			result.WriteString(fmt.Sprintf("<span class=\"quantity\">%s %s</span> <span class=\"ingredient\">%s</span>",
+				html.EscapeString(labels.Some), html.EscapeString(ingredient.Unit), html.EscapeString(ingredient.Name)))
+		} else {
+			result.WriteString(fmt.Sprintf("<span class=\"quantity\">%s</span> <span class=\"ingredient\">%s</span>",
+				html.EscapeString(labels.Some), html.EscapeString(ingredient.Name)))
+		}
+	} else {
+		result.WriteString(fmt.Sprintf("<span class=\"ingredient\">%s</span>", html.EscapeString(ingredient.Name)))
+	}
+	if ingredient.Annotation != "" {
+		fmt.Fprintf(result, "<span class=\"annotation\">, %s</span>", html.EscapeString(ingredient.Annotation))
+	}
+	if note := hr.Options.quantityNote(recipe, ingredient, labels); note != "" {
+		fmt.Fprintf(result, " <span class=\"quantity-note\">(%s)</span>", html.EscapeString(note))
+	}
+	result.WriteString("</li>\n")
 }
 
 // renderComponent renders a single component in HTML format
-func (hr HTMLRenderer) renderComponent(result *strings.Builder, currentComponent cooklang.StepComponent) {
+func (hr HTMLRenderer) renderComponent(result *strings.Builder, currentComponent cooklang.StepComponent, labels Strings) {
 	switch comp := currentComponent.(type) {
 	case *cooklang.Ingredient:
 		ingredientClass := "ingredient"
 		if comp.Optional {
 			ingredientClass = "ingredient optional"
+		} else if comp.Reference {
+			ingredientClass = "ingredient reference"
 		}
-		if comp.Quantity > 0 {
-			fmt.Fprintf(result, "<span class=\"%s\">%s</span> <span class=\"quantity\">(%g %s)</span>",
-				ingredientClass, html.EscapeString(comp.Name), comp.Quantity, html.EscapeString(comp.Unit))
+		if comp.Quantity.HasAmount() {
+			fmt.Fprintf(result, "<span class=\"%s\">%s</span> <span class=\"quantity\">(%s)</span>",
+				ingredientClass, html.EscapeString(comp.Name), html.EscapeString(strings.TrimSpace(hr.Options.amount(comp)+" "+comp.Unit)))
 		} else {
 			fmt.Fprintf(result, "<span class=\"%s\">%s</span>", ingredientClass, html.EscapeString(comp.Name))
 		}
 		if comp.Optional {
-			result.WriteString(" <span class=\"optional-marker\">(optional)</span>")
+			fmt.Fprintf(result, " <span class=\"optional-marker\">(%s)</span>", html.EscapeString(labels.Optional))
 		}
 		if comp.Annotation != "" {
 			fmt.Fprintf(result, " <span class=\"annotation\">(%s)</span>", html.EscapeString(comp.Annotation))
 		}
 	case *cooklang.Cookware:
-		if comp.Quantity > 1 {
-			fmt.Fprintf(result, "<span class=\"cookware\">%s</span> <span class=\"quantity\">(x%d)</span>",
-				html.EscapeString(comp.Name), comp.Quantity)
+		if quantity := comp.QuantityLabel(); quantity != "" {
+			fmt.Fprintf(result, "<span class=\"cookware\">%s</span> <span class=\"quantity\">(%s)</span>",
+				html.EscapeString(comp.Name), html.EscapeString(quantity))
 		} else {
 			fmt.Fprintf(result, "<span class=\"cookware\">%s</span>", html.EscapeString(comp.Name))
 		}
@@ -177,15 +235,17 @@ func (hr HTMLRenderer) renderComponent(result *strings.Builder, currentComponent
 	case *cooklang.Timer:
 		if comp.Name != "" {
 			fmt.Fprintf(result, "<span class=\"timer\">⏲️ %s (%s)</span>",
-				html.EscapeString(comp.Name), html.EscapeString(comp.Duration))
+				html.EscapeString(comp.Name), html.EscapeString(comp.String()))
 		} else {
-			fmt.Fprintf(result, "<span class=\"timer\">⏲️ %s</span>", html.EscapeString(comp.Duration))
+			fmt.Fprintf(result, "<span class=\"timer\">⏲️ %s</span>", html.EscapeString(comp.String()))
 		}
 		if comp.Annotation != "" {
 			fmt.Fprintf(result, " <span class=\"annotation\">(%s)</span>", html.EscapeString(comp.Annotation))
 		}
 	case *cooklang.Instruction:
 		result.WriteString(html.EscapeString(comp.Text))
+	case *cooklang.Temperature:
+		fmt.Fprintf(result, "<span class=\"temperature\">%s</span>", html.EscapeString(hr.Options.temperature(comp)))
 	case *cooklang.Section:
 		// Sections are handled specially in the main render loop
 		if comp.Name != "" {
@@ -195,8 +255,22 @@ func (hr HTMLRenderer) renderComponent(result *strings.Builder, currentComponent
 		// Render comments as HTML comments (hidden) or as styled span
 		fmt.Fprintf(result, "<span class=\"comment\">(%s)</span>", html.EscapeString(comp.Text))
 	case *cooklang.Note:
-		// Notes render as blockquotes
-		fmt.Fprintf(result, "<blockquote class=\"recipe-note\">%s</blockquote>", html.EscapeString(comp.Text))
+		// Notes render as asides
+		fmt.Fprintf(result, "<aside class=\"recipe-note\">%s</aside>", html.EscapeString(comp.Text))
+	}
+}
+
+// renderCallouts renders the reserved outputs used by a step as callouts
+func (hr HTMLRenderer) renderCallouts(result *strings.Builder, callouts []string) {
+	for _, callout := range callouts {
+		fmt.Fprintf(result, "\n        <aside class=\"reserved-callout\">%s</aside>", html.EscapeString(callout))
+	}
+}
+
+// renderStepImages renders a step's images below its text
+func (hr HTMLRenderer) renderStepImages(result *strings.Builder, images []string, title string) {
+	for _, src := range images {
+		fmt.Fprintf(result, "\n        <img class=\"step-image\" src=\"%s\" alt=\"%s\">", html.EscapeString(src), html.EscapeString(title))
 	}
 }
 
@@ -0,0 +1,368 @@
+package renderers
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hilli/cooklang"
+)
+
+// htmlTag is a start tag found while checking a document.
+type htmlTag struct {
+	name  string
+	attrs map[string]string
+}
+
+// voidElements never have an end tag.
+var voidElements = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true, "hr": true,
+	"img": true, "input": true, "link": true, "meta": true, "source": true, "track": true, "wbr": true,
+}
+
+// rawTextElements contain text that is not parsed as markup.
+var rawTextElements = map[string]bool{"script": true, "style": true}
+
+var htmlEntityPattern = regexp.MustCompile(`^&(#[0-9]+|#x[0-9a-fA-F]+|[a-zA-Z][a-zA-Z0-9]*);`)
+
+// checkHTML verifies that doc is well-formed HTML: every tag is closed in order,
+// attribute values are quoted, and text contains no raw '<' or bare '&'. It returns
+// the start tags found so callers can look for injected elements or attributes.
+//
+// This is a deliberately strict checker for the markup our renderers generate,
+// not a general-purpose HTML5 parser.
+func checkHTML(doc string) ([]htmlTag, error) {
+	var tags []htmlTag
+	var stack []string
+
+	line := func(pos int) int { return strings.Count(doc[:pos], "\n") + 1 }
+
+	checkText := func(text string, start int) error {
+		for i := strings.IndexByte(text, '&'); i >= 0; {
+			if !htmlEntityPattern.MatchString(text[i:]) {
+				return fmt.Errorf("line %d: bare '&' in %q", line(start+i), text)
+			}
+			next := strings.IndexByte(text[i+1:], '&')
+			if next < 0 {
+				break
+			}
+			i += next + 1
+		}
+		return nil
+	}
+
+	pos := 0
+	for pos < len(doc) {
+		lt := strings.IndexByte(doc[pos:], '<')
+		if lt < 0 {
+			if err := checkText(doc[pos:], pos); err != nil {
+				return nil, err
+			}
+			break
+		}
+		if err := checkText(doc[pos:pos+lt], pos); err != nil {
+			return nil, err
+		}
+		pos += lt
+		rest := doc[pos:]
+
+		switch {
+		case strings.HasPrefix(rest, "<!--"):
+			end := strings.Index(rest, "-->")
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: unterminated comment", line(pos))
+			}
+			pos += end + len("-->")
+
+		case strings.HasPrefix(rest, "<!"):
+			end := strings.IndexByte(rest, '>')
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: unterminated declaration", line(pos))
+			}
+			pos += end + 1
+
+		case strings.HasPrefix(rest, "</"):
+			end := strings.IndexByte(rest, '>')
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: unterminated end tag", line(pos))
+			}
+			name := strings.ToLower(strings.TrimSpace(rest[2:end]))
+			if len(stack) == 0 {
+				return nil, fmt.Errorf("line %d: </%s> without matching start tag", line(pos), name)
+			}
+			if open := stack[len(stack)-1]; open != name {
+				return nil, fmt.Errorf("line %d: </%s> closes <%s>", line(pos), name, open)
+			}
+			stack = stack[:len(stack)-1]
+			pos += end + 1
+
+		default:
+			tag, length, selfClosing, err := parseStartTag(rest)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line(pos), err)
+			}
+			for _, value := range tag.attrs {
+				if err := checkText(value, pos); err != nil {
+					return nil, err
+				}
+			}
+			tags = append(tags, tag)
+			pos += length
+
+			if rawTextElements[tag.name] {
+				end := strings.Index(strings.ToLower(doc[pos:]), "</"+tag.name)
+				if end < 0 {
+					return nil, fmt.Errorf("line %d: unterminated <%s>", line(pos), tag.name)
+				}
+				pos += end
+			}
+			if !voidElements[tag.name] && !selfClosing {
+				stack = append(stack, tag.name)
+			}
+		}
+	}
+
+	if len(stack) > 0 {
+		return nil, fmt.Errorf("unclosed elements at end of document: %v", stack)
+	}
+	return tags, nil
+}
+
+// parseStartTag parses a start tag at the beginning of s, returning the tag,
+// its length, and whether it was self-closing ("/>").
+func parseStartTag(s string) (htmlTag, int, bool, error) {
+	isNameChar := func(c byte) bool {
+		return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-'
+	}
+
+	i := 1
+	for i < len(s) && isNameChar(s[i]) {
+		i++
+	}
+	if i == 1 {
+		return htmlTag{}, 0, false, fmt.Errorf("unescaped '<' in text: %q", truncate(s, 20))
+	}
+	tag := htmlTag{name: strings.ToLower(s[1:i]), attrs: map[string]string{}}
+
+	for {
+		for i < len(s) && (s[i] == ' ' || s[i] == '\n' || s[i] == '\t') {
+			i++
+		}
+		if i >= len(s) {
+			return htmlTag{}, 0, false, fmt.Errorf("unterminated <%s> tag", tag.name)
+		}
+		if s[i] == '>' {
+			return tag, i + 1, false, nil
+		}
+		if strings.HasPrefix(s[i:], "/>") {
+			return tag, i + 2, true, nil
+		}
+
+		start := i
+		for i < len(s) && (isNameChar(s[i]) || s[i] == ':') {
+			i++
+		}
+		if i == start {
+			return htmlTag{}, 0, false, fmt.Errorf("invalid attribute in <%s>: %q", tag.name, truncate(s[i:], 20))
+		}
+		name := strings.ToLower(s[start:i])
+		if _, duplicate := tag.attrs[name]; duplicate {
+			return htmlTag{}, 0, false, fmt.Errorf("duplicate attribute %q in <%s>", name, tag.name)
+		}
+
+		if i >= len(s) || s[i] != '=' {
+			tag.attrs[name] = ""
+			continue
+		}
+		i++
+		if i >= len(s) || s[i] != '"' {
+			return htmlTag{}, 0, false, fmt.Errorf("unquoted value for %q in <%s>", name, tag.name)
+		}
+		end := strings.IndexByte(s[i+1:], '"')
+		if end < 0 {
+			return htmlTag{}, 0, false, fmt.Errorf("unterminated value for %q in <%s>", name, tag.name)
+		}
+		tag.attrs[name] = s[i+1 : i+1+end]
+		i += end + 2
+	}
+}
+
+func truncate(s string, n int) string {
+	if len(s) > n {
+		return s[:n]
+	}
+	return s
+}
+
+// htmlFixtures is the corpus rendered by the validity tests. It covers every
+// component type plus hostile content that must come out escaped.
+var htmlFixtures = map[string]string{
+	"hostile metadata": `---
+title: <script>alert("title")</script>
+description: "A & B <b>bold</b> </div>"
+author: Eve <eve@example.com>
+cuisine: <img src=x onerror=alert(1)>
+difficulty: "\"><script>alert(1)</script>"
+prep_time: 5 <i>min</i>
+total_time: 10 & more
+tags: <script>, a&b, "quote"
+image: x" onerror="alert(1).jpg
+oven_temp: <b>hot</b> & ready
+---
+Mix @<b>flour</b>{1%<i>cup</i>}(sifted </li>) in a #<bowl>{}(big & "deep").
+
+Wait ~<script>{5%min</span>}.
+
+Sweeten with @sugar{2%<i>cup} and @honey{}.
+`,
+	"hostile steps": `---
+title: Steps & Sections
+---
+== <h1>Section</h1> ==
+
+Add @salt{1-2%tsp} & @pepper{}(to taste <3).
+
+> Note with <script>alert("note")</script> & more.
+
+-- Comment </ol></div>
+
+== ==
+
+Serve @garnish{}(optional) </body></html>.
+`,
+	"hostile amounts": `---
+title: Amounts
+servings: 2
+oven_temp: 180°C
+---
+Add @flour{~1/3%<b>cup</b>}, @eggs{2-3} and @milk{1%cup "&amp;"}, then bake at 200°C.
+`,
+	"every component": `---
+title: Everything
+servings: 2.5
+tags: one, two
+---
+== Prep ==
+
+Combine @flour{250%g}, @eggs{2}, @milk{1/3%cup}(cold), @?vanilla{1%tsp} and @sugar{} in a #bowl{2}.
+
+> Do not overmix.
+
+Bake for ~bake{25%minutes} in the #oven{}. -- check at 20
+
+== Serve ==
+
+Slice and serve.
+`,
+	"empty": ``,
+}
+
+// loadHTMLFixtures returns the fixture corpus plus the example recipes.
+func loadHTMLFixtures(t *testing.T) map[string]*cooklang.Recipe {
+	t.Helper()
+	recipes := make(map[string]*cooklang.Recipe)
+	for name, content := range htmlFixtures {
+		recipe, err := cooklang.ParseString(content)
+		if err != nil {
+			t.Fatalf("failed to parse fixture %q: %v", name, err)
+		}
+		recipes[name] = recipe
+	}
+
+	// Image paths come from the filesystem or frontmatter and end up in attributes
+	withImage, _ := cooklang.ParseString("---\ntitle: \"Quoted\" <Title>\n---\nServe.")
+	withImage.Images = []string{`x" onerror="alert(1)`}
+	recipes["hostile image"] = withImage
+
+	files, _ := filepath.Glob(filepath.Join("..", "example_recipes", "*.cook"))
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", file, err)
+		}
+		recipe, err := cooklang.ParseBytes(content)
+		if err != nil {
+			t.Fatalf("failed to parse %s: %v", file, err)
+		}
+		recipes[filepath.Base(file)] = recipe
+	}
+	return recipes
+}
+
+// checkNoInjectedMarkup fails if user content turned into elements or attributes.
+func checkNoInjectedMarkup(t *testing.T, tags []htmlTag) {
+	t.Helper()
+	allowed := map[string]bool{
+		"html": true, "head": true, "meta": true, "title": true, "style": true, "body": true,
+		"div": true, "span": true, "h1": true, "h2": true, "h3": true, "p": true, "img": true,
+		"ul": true, "ol": true, "li": true, "dl": true, "dt": true, "dd": true, "blockquote": true, "aside": true,
+	}
+	for _, tag := range tags {
+		if !allowed[tag.name] {
+			t.Errorf("unexpected <%s> element in output", tag.name)
+		}
+		for attr := range tag.attrs {
+			if strings.HasPrefix(attr, "on") {
+				t.Errorf("event handler attribute %q on <%s>", attr, tag.name)
+			}
+		}
+	}
+}
+
+func TestHTMLValidity(t *testing.T) {
+	// Options that rewrite amounts, temperatures and notes must escape them too
+	options := RendererOptions{
+		Quantities:            &cooklang.FormatOptions{UnicodeFractions: true},
+		QuantityNotes:         NoteOriginal,
+		BothTemperatureScales: true,
+		NutritionLabel:        true,
+	}
+	renderers := map[string]cooklang.RecipeRenderer{
+		"html":               HTMLRenderer{},
+		"print":              PrintRenderer{},
+		"html with options":  HTMLRenderer{Options: options},
+		"print with options": PrintRenderer{Options: options},
+	}
+
+	for fixture, recipe := range loadHTMLFixtures(t) {
+		for name, renderer := range renderers {
+			t.Run(name+"/"+fixture, func(t *testing.T) {
+				output := renderRecipe(t, renderer, recipe)
+				tags, err := checkHTML(output)
+				if err != nil {
+					t.Fatalf("output is not well-formed: %v\n%s", err, output)
+				}
+				checkNoInjectedMarkup(t, tags)
+			})
+		}
+	}
+}
+
+func TestCheckHTML(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     string
+		wantErr bool
+	}{
+		{"valid", `<!DOCTYPE html><div class="a"><br><img src="x" alt=""/><p>a &amp; b &#169;</p></div>`, false},
+		{"style raw text", `<style>a > b { content: "<"; }</style>`, false},
+		{"unclosed", `<div><span></div>`, true},
+		{"stray end tag", `</div>`, true},
+		{"unescaped lt", `<p>1 < 2</p>`, true},
+		{"bare ampersand", `<p>A & B</p>`, true},
+		{"unquoted attribute", `<img src=x>`, true},
+		{"bare ampersand in attribute", `<img alt="A & B">`, true},
+		{"left open", `<ol><li>step</li>`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := checkHTML(tt.doc)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkHTML() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,259 @@
+package renderers
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hilli/cooklang"
+)
+
+// DefaultStepDuration is the time estimated for a step that has no timers.
+const DefaultStepDuration = 5 * time.Minute
+
+// ICSRenderer renders a recipe as an iCalendar (ICS) cooking timeline. Starting from the
+// time the dish should be served, it works backwards through the steps: each step takes
+// as long as its longest timer (the upper bound for ranges), or StepDuration if it has
+// none. Every step becomes a calendar event with an alarm when it starts, and steps with
+// timers get a second alarm when the timer runs out.
+//
+// Example usage:
+//
+//	recipe, _ := cooklang.ParseFile("roast.cook")
+//	serveAt := time.Date(2025, 12, 24, 19, 0, 0, 0, time.Local)
+//	ics := renderers.ICSRenderer{}.RenderICS(recipe, serveAt)
+//	os.WriteFile("dinner.ics", []byte(ics), 0644)
+type ICSRenderer struct {
+	StepDuration time.Duration // Estimate for steps without timers (default DefaultStepDuration)
+	Stamp        time.Time     // Creation time written to DTSTAMP (default: now)
+	ServeAt      time.Time     // When the dish should be ready, for Render
+}
+
+// TimelineEvent is a step scheduled on the cooking timeline.
+type TimelineEvent struct {
+	Step     int       `json:"step"`              // 1-based step number
+	Section  string    `json:"section,omitempty"` // Section the step belongs to
+	Text     string    `json:"text"`              // Step text
+	Start    time.Time `json:"start"`
+	End      time.Time `json:"end"`
+	Timers   []string  `json:"timers,omitempty"` // Timers in the step, e.g. "10 minutes"
+	Estimate bool      `json:"estimate"`         // True if the duration is an estimate (no timers)
+}
+
+// Duration returns how long the event lasts.
+func (e TimelineEvent) Duration() time.Duration {
+	return e.End.Sub(e.Start)
+}
+
+// Timeline schedules the recipe's steps so that the last one ends at serveAt.
+// Steps without text (e.g., only comments) are skipped.
+//
+// Parameters:
+//   - recipe: The parsed Cooklang recipe
+//   - serveAt: When the dish should be ready
+//
+// Returns:
+//   - []TimelineEvent: The steps in order, with start and end times
+func (ir ICSRenderer) Timeline(recipe *cooklang.Recipe, serveAt time.Time) []TimelineEvent {
+	estimate := ir.StepDuration
+	if estimate <= 0 {
+		estimate = DefaultStepDuration
+	}
+
+	var events []TimelineEvent
+	var durations []time.Duration
+	var section string
+	for step := recipe.FirstStep; step != nil; step = step.NextStep {
+		var text strings.Builder
+		var timers []string
+		var longest time.Duration
+		for component := step.FirstComponent; component != nil; component = component.GetNext() {
+			switch comp := component.(type) {
+			case *cooklang.Section:
+				section = comp.Name
+			case *cooklang.Instruction:
+				text.WriteString(comp.Text)
+			case *cooklang.Temperature:
+				text.WriteString(comp.RenderDisplay())
+			case *cooklang.Ingredient:
+				text.WriteString(comp.Name)
+			case *cooklang.Cookware:
+				text.WriteString(comp.Name)
+			case *cooklang.Timer:
+				display := comp.RenderDisplay()
+				text.WriteString(display)
+				timers = append(timers, display)
+				if _, high, err := comp.DurationRange(); err == nil && high > longest {
+					longest = high
+				}
+			}
+		}
+
+		stepText := strings.Join(strings.Fields(text.String()), " ")
+		if stepText == "" {
+			continue
+		}
+		event := TimelineEvent{Step: len(events) + 1, Section: section, Text: stepText, Timers: timers}
+		if longest == 0 {
+			longest = estimate
+			event.Estimate = true
+		}
+		events = append(events, event)
+		durations = append(durations, longest)
+	}
+
+	// Work backwards from the serving time
+	end := serveAt
+	for i := len(events) - 1; i >= 0; i-- {
+		events[i].End = end
+		events[i].Start = end.Add(-durations[i])
+		end = events[i].Start
+	}
+	return events
+}
+
+// Render returns the cooking timeline as an iCalendar document ending at ServeAt.
+// Of the options, only Units and PreferredUnits apply, to the quantities in the
+// step texts.
+//
+// Returns:
+//   - string: The iCalendar document, with CRLF line endings
+//   - error: An error if ServeAt is not set
+func (ir ICSRenderer) Render(recipe *cooklang.Recipe, opts RendererOptions) (string, error) {
+	if ir.ServeAt.IsZero() {
+		return "", errors.New("ICSRenderer.ServeAt is not set")
+	}
+	return ir.RenderICS(opts.prepare(recipe), ir.ServeAt), nil
+}
+
+// RenderICS returns the cooking timeline as an iCalendar document, with one event per
+// step and a final event at serveAt. Times are written in UTC.
+//
+// Parameters:
+//   - recipe: The parsed Cooklang recipe
+//   - serveAt: When the dish should be ready
+//
+// Returns:
+//   - string: The iCalendar document, with CRLF line endings
+func (ir ICSRenderer) RenderICS(recipe *cooklang.Recipe, serveAt time.Time) string {
+	name := recipe.Title
+	if name == "" {
+		name = "Recipe"
+	}
+	stamp := ir.Stamp
+	if stamp.IsZero() {
+		stamp = time.Now()
+	}
+	uidBase := fmt.Sprintf("%s-%d", icsSlug(name), serveAt.Unix())
+
+	var lines []string
+	add := func(format string, args ...interface{}) {
+		lines = append(lines, fmt.Sprintf(format, args...))
+	}
+
+	add("BEGIN:VCALENDAR")
+	add("VERSION:2.0")
+	add("PRODID:-//hilli//cooklang//EN")
+	add("CALSCALE:GREGORIAN")
+	add("METHOD:PUBLISH")
+	add("X-WR-CALNAME:%s", icsEscape(name))
+
+	for _, event := range ir.Timeline(recipe, serveAt) {
+		summary := fmt.Sprintf("%s: step %d", name, event.Step)
+		if event.Section != "" {
+			summary += " (" + event.Section + ")"
+		}
+		description := event.Text
+		if event.Estimate {
+			description += fmt.Sprintf("\n\nEstimated time: %s", event.Duration())
+		}
+
+		add("BEGIN:VEVENT")
+		add("UID:%s-step-%d@cooklang", uidBase, event.Step)
+		add("DTSTAMP:%s", icsTime(stamp))
+		add("DTSTART:%s", icsTime(event.Start))
+		add("DTEND:%s", icsTime(event.End))
+		add("SUMMARY:%s", icsEscape(summary))
+		add("DESCRIPTION:%s", icsEscape(description))
+		add("BEGIN:VALARM")
+		add("ACTION:DISPLAY")
+		add("DESCRIPTION:%s", icsEscape(fmt.Sprintf("Step %d: %s", event.Step, event.Text)))
+		add("TRIGGER:PT0S")
+		add("END:VALARM")
+		if len(event.Timers) > 0 {
+			add("BEGIN:VALARM")
+			add("ACTION:DISPLAY")
+			add("DESCRIPTION:%s", icsEscape("Timer done: "+strings.Join(event.Timers, ", ")))
+			add("TRIGGER;RELATED=END:PT0S")
+			add("END:VALARM")
+		}
+		add("END:VEVENT")
+	}
+
+	add("BEGIN:VEVENT")
+	add("UID:%s-serve@cooklang", uidBase)
+	add("DTSTAMP:%s", icsTime(stamp))
+	add("DTSTART:%s", icsTime(serveAt))
+	add("DTEND:%s", icsTime(serveAt))
+	add("SUMMARY:%s", icsEscape("Serve "+name))
+	add("BEGIN:VALARM")
+	add("ACTION:DISPLAY")
+	add("DESCRIPTION:%s", icsEscape(name+" is ready to serve"))
+	add("TRIGGER:PT0S")
+	add("END:VALARM")
+	add("END:VEVENT")
+	add("END:VCALENDAR")
+
+	var result strings.Builder
+	for _, line := range lines {
+		result.WriteString(icsFold(line))
+		result.WriteString("\r\n")
+	}
+	return result.String()
+}
+
+// icsTime formats a time as an iCalendar UTC date-time.
+func icsTime(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+// icsEscape escapes text values as required by RFC 5545.
+func icsEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
+
+// icsFold folds a content line into lines of at most 75 octets, continuing with a
+// leading space, without splitting UTF-8 characters.
+func icsFold(line string) string {
+	const limit = 75
+	if len(line) <= limit {
+		return line
+	}
+	var result strings.Builder
+	width := 0
+	for _, r := range line {
+		size := len(string(r))
+		if width+size > limit {
+			result.WriteString("\r\n ")
+			width = 1
+		}
+		result.WriteRune(r)
+		width += size
+	}
+	return result.String()
+}
+
+// icsSlug turns a recipe name into a UID-safe identifier.
+func icsSlug(name string) string {
+	var slug strings.Builder
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			slug.WriteRune(r)
+		case slug.Len() > 0 && !strings.HasSuffix(slug.String(), "-"):
+			slug.WriteByte('-')
+		}
+	}
+	return strings.TrimSuffix(slug.String(), "-")
+}
@@ -0,0 +1,123 @@
+package renderers
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hilli/cooklang"
+)
+
+const icsTestRecipe = `---
+title: Roast Chicken; Herbs, Lemon
+---
+== Prep ==
+
+Rub the @chicken{1} with @butter{50%g}.
+
+-- Any herbs will do.
+
+Roast for ~{1-1.5%hours}, then rest ~{15%minutes}.
+
+== Serve ==
+
+Carve and serve.
+`
+
+func TestICSRenderer_Timeline(t *testing.T) {
+	recipe, err := cooklang.ParseString(icsTestRecipe)
+	if err != nil {
+		t.Fatalf("Failed to parse recipe: %v", err)
+	}
+	serveAt := time.Date(2025, 12, 24, 19, 0, 0, 0, time.UTC)
+
+	events := ICSRenderer{StepDuration: 10 * time.Minute}.Timeline(recipe, serveAt)
+	if len(events) != 3 {
+		t.Fatalf("Expected 3 events, got %d: %+v", len(events), events)
+	}
+
+	expected := []struct {
+		start    string
+		end      string
+		section  string
+		estimate bool
+	}{
+		{"17:10", "17:20", "Prep", true},
+		{"17:20", "18:50", "Prep", false}, // The longest timer, at its upper bound
+		{"18:50", "19:00", "Serve", true},
+	}
+	for i, want := range expected {
+		event := events[i]
+		if got := event.Start.Format("15:04"); got != want.start {
+			t.Errorf("Event %d: expected start %s, got %s", i+1, want.start, got)
+		}
+		if got := event.End.Format("15:04"); got != want.end {
+			t.Errorf("Event %d: expected end %s, got %s", i+1, want.end, got)
+		}
+		if event.Section != want.section || event.Estimate != want.estimate || event.Step != i+1 {
+			t.Errorf("Event %d: unexpected %+v", i+1, event)
+		}
+	}
+	if len(events[1].Timers) != 2 || events[1].Timers[0] != "1-1.5 hours" {
+		t.Errorf("Expected both timers on step 2, got %v", events[1].Timers)
+	}
+	if events[0].Text != "Rub the chicken with butter." {
+		t.Errorf("Unexpected step text %q", events[0].Text)
+	}
+}
+
+func TestICSRenderer_RenderICS(t *testing.T) {
+	recipe, err := cooklang.ParseString(icsTestRecipe)
+	if err != nil {
+		t.Fatalf("Failed to parse recipe: %v", err)
+	}
+	serveAt := time.Date(2025, 12, 24, 19, 0, 0, 0, time.UTC)
+	stamp := time.Date(2025, 12, 1, 8, 0, 0, 0, time.UTC)
+
+	ics := ICSRenderer{Stamp: stamp}.RenderICS(recipe, serveAt)
+
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\nVERSION:2.0\r\n",
+		"X-WR-CALNAME:Roast Chicken\\; Herbs\\, Lemon\r\n",
+		"DTSTAMP:20251201T080000Z\r\n",
+		"DTSTART:20251224T172500Z\r\nDTEND:20251224T185500Z\r\n",
+		"TRIGGER;RELATED=END:PT0S\r\n",
+		"SUMMARY:Serve Roast Chicken\\; Herbs\\, Lemon\r\n",
+		"UID:roast-chicken-herbs-lemon-",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(ics, want) {
+			t.Errorf("Expected ICS to contain %q, got:\n%s", want, ics)
+		}
+	}
+	if got := strings.Count(ics, "BEGIN:VEVENT"); got != 4 {
+		t.Errorf("Expected 4 events (3 steps and serving), got %d", got)
+	}
+	if got, want := strings.Count(ics, "BEGIN:VALARM"), strings.Count(ics, "END:VALARM"); got != 5 || got != want {
+		t.Errorf("Expected 5 balanced alarms, got %d BEGIN and %d END", got, want)
+	}
+
+	for _, line := range strings.Split(strings.TrimSuffix(ics, "\r\n"), "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("Line longer than 75 octets: %q", line)
+		}
+		if strings.Contains(line, "\n") {
+			t.Errorf("Bare newline in line: %q", line)
+		}
+	}
+}
+
+func TestICSFold(t *testing.T) {
+	line := "DESCRIPTION:" + strings.Repeat("ä", 60)
+	folded := icsFold(line)
+	parts := strings.Split(folded, "\r\n ")
+	if len(parts) != 2 {
+		t.Fatalf("Expected 2 folded parts, got %d: %q", len(parts), folded)
+	}
+	if len(parts[0]) > 75 || len(parts[1])+1 > 75 {
+		t.Errorf("Folded lines too long: %q", folded)
+	}
+	if strings.Join(parts, "") != line {
+		t.Errorf("Folding changed the content: %q", folded)
+	}
+}
@@ -25,10 +25,10 @@ import (
 //	renderer := renderers.JSONLDRenderer{}
 //
 //	// Get JSON-LD as a map for further manipulation
-//	data := renderer.RenderRecipe(recipe, nil)
+//	data := renderer.BuildRecipe(recipe, nil)
 //
 //	// Get JSON-LD as a formatted JSON string
-//	jsonStr, _ := renderer.RenderRecipeJSON(recipe, nil)
+//	jsonStr, _ := renderer.RenderRecipe(recipe)
 //
 //	// Get a complete <script> tag ready for HTML embedding
 //	scriptTag, _ := renderer.RenderRecipeScriptTag(recipe, &renderers.JSONLDOptions{
@@ -38,7 +38,9 @@ import (
 //	        RatingCount: 42,
 //	    },
 //	})
-type JSONLDRenderer struct{}
+type JSONLDRenderer struct {
+	Page *JSONLDOptions // Page data such as the URL and rating, for Render (optional)
+}
 
 // JSONLDOptions allows customization of the JSON-LD output with application-specific data
 // that may not be available in the cooklang Recipe itself.
@@ -138,8 +140,9 @@ type VideoObject struct {
 	Duration string
 }
 
-// RenderRecipe returns a JSON-LD object as a map for flexible manipulation.
-// This is useful when you need to modify the output before serialization.
+// BuildRecipe returns a JSON-LD object as a map for flexible manipulation.
+// This is useful when you need to modify the output before serialization; use
+// RenderRecipe or Render for the JSON string.
 //
 // The returned map follows the Schema.org Recipe specification with these properties:
 //   - @context: Always "https://schema.org"
@@ -148,15 +151,17 @@ type VideoObject struct {
 //   - description: From recipe.Description
 //   - author: From recipe.Author (as Person object)
 //   - image: From opts.Images or recipe.Images
-//   - recipeYield: From recipe.Servings
+//   - recipeYield: From recipe.Yield and recipe.Servings (an array if both are set)
 //   - prepTime: From recipe.PrepTime (converted to ISO 8601 duration)
-//   - totalTime: From recipe.TotalTime (converted to ISO 8601 duration)
+//   - cookTime: From the cook_time metadata (converted to ISO 8601 duration)
+//   - totalTime: From recipe.TotalTime, or recipe.Times() (converted to ISO 8601 duration)
 //   - recipeCategory: From opts.RecipeCategory or recipe.Metadata["category"]
 //   - recipeCuisine: From recipe.Cuisine
 //   - keywords: From recipe.Tags merged with opts.Keywords
 //   - recipeIngredient: Array of ingredient strings
 //   - recipeInstructions: Array of HowToStep objects
-//   - tool: Array of cookware/tool names
+//   - tool: Array of HowToTool objects with the cookware's name, requiredQuantity
+//     and annotation as description
 //   - datePublished: From opts.DatePublished or recipe.Date
 //   - dateModified: From opts.DateModified
 //   - url: From opts.URL
@@ -169,7 +174,7 @@ type VideoObject struct {
 //
 // Returns:
 //   - map[string]interface{}: The JSON-LD object as a map
-func (jr JSONLDRenderer) RenderRecipe(recipe *cooklang.Recipe, opts *JSONLDOptions) map[string]interface{} {
+func (jr JSONLDRenderer) BuildRecipe(recipe *cooklang.Recipe, opts *JSONLDOptions) map[string]interface{} {
 	if opts == nil {
 		opts = &JSONLDOptions{}
 	}
@@ -214,16 +219,27 @@ func (jr JSONLDRenderer) RenderRecipe(recipe *cooklang.Recipe, opts *JSONLDOptio
 		}
 	}
 
-	// Recipe Yield (servings)
+	// Recipe Yield (yield and servings)
+	var servings string
 	if recipe.Servings > 0 {
 		if recipe.Servings == float32(int(recipe.Servings)) {
-			data["recipeYield"] = fmt.Sprintf("%d serving", int(recipe.Servings))
+			servings = fmt.Sprintf("%d serving", int(recipe.Servings))
 			if recipe.Servings != 1 {
-				data["recipeYield"] = fmt.Sprintf("%d servings", int(recipe.Servings))
+				servings = fmt.Sprintf("%d servings", int(recipe.Servings))
 			}
 		} else {
-			data["recipeYield"] = fmt.Sprintf("%.1f servings", recipe.Servings)
+			servings = fmt.Sprintf("%.1f servings", recipe.Servings)
+		}
+	}
+	if yield := recipe.Yield.String(); yield != "" {
+		// Servings default to 1, so they are only added when the metadata sets them
+		if _, ok := recipe.Metadata["servings"]; ok && servings != "" {
+			data["recipeYield"] = []string{yield, servings}
+		} else {
+			data["recipeYield"] = yield
 		}
+	} else if servings != "" {
+		data["recipeYield"] = servings
 	}
 
 	// Prep Time (ISO 8601 duration)
@@ -233,12 +249,24 @@ func (jr JSONLDRenderer) RenderRecipe(recipe *cooklang.Recipe, opts *JSONLDOptio
 		}
 	}
 
-	// Total Time (ISO 8601 duration)
+	// Cook Time (ISO 8601 duration)
+	if cookTime := recipe.Metadata["cook_time"]; cookTime != "" {
+		if duration := ParseDurationToISO8601(cookTime); duration != "" {
+			data["cookTime"] = duration
+		}
+	}
+
+	// Total Time (ISO 8601 duration), or prep plus cook time
 	if recipe.TotalTime != "" {
 		if duration := ParseDurationToISO8601(recipe.TotalTime); duration != "" {
 			data["totalTime"] = duration
 		}
 	}
+	if _, ok := data["totalTime"]; !ok {
+		if total := recipe.Times().Total; total > 0 {
+			data["totalTime"] = durationToISO8601(total)
+		}
+	}
 
 	// Recipe Category
 	category := opts.RecipeCategory
@@ -281,17 +309,7 @@ func (jr JSONLDRenderer) RenderRecipe(recipe *cooklang.Recipe, opts *JSONLDOptio
 	}
 
 	// Tools (cookware)
-	cookware := recipe.GetCookware()
-	if len(cookware) > 0 {
-		// Deduplicate cookware names
-		seen := make(map[string]bool)
-		tools := make([]string, 0, len(cookware))
-		for _, cw := range cookware {
-			if !seen[cw.Name] {
-				seen[cw.Name] = true
-				tools = append(tools, cw.Name)
-			}
-		}
+	if tools := jr.buildTools(recipe); len(tools) > 0 {
 		data["tool"] = tools
 	}
 
@@ -368,6 +386,35 @@ func (jr JSONLDRenderer) RenderRecipe(recipe *cooklang.Recipe, opts *JSONLDOptio
 	return data
 }
 
+// buildTools creates HowToTool objects from the recipe's cookware. Cookware used in
+// several steps is listed once, with the first annotation and the largest quantity.
+func (jr JSONLDRenderer) buildTools(recipe *cooklang.Recipe) []interface{} {
+	var tools []interface{}
+	byName := make(map[string]map[string]interface{})
+	for _, cw := range recipe.GetCookware() {
+		var quantity interface{} = max(cw.Quantity, 1)
+		if cw.QuantityText != "" {
+			quantity = cw.QuantityText
+		}
+		tool, seen := byName[cw.Name]
+		if !seen {
+			tool = map[string]interface{}{
+				"@type":            "HowToTool",
+				"name":             cw.Name,
+				"requiredQuantity": quantity,
+			}
+			byName[cw.Name] = tool
+			tools = append(tools, tool)
+		} else if n, ok := tool["requiredQuantity"].(int); ok && cw.QuantityText == "" && cw.Quantity > n {
+			tool["requiredQuantity"] = cw.Quantity
+		}
+		if _, ok := tool["description"]; !ok && cw.Annotation != "" {
+			tool["description"] = cw.Annotation
+		}
+	}
+	return tools
+}
+
 // buildInstructions converts recipe steps to Schema.org HowToStep/HowToSection objects.
 func (jr JSONLDRenderer) buildInstructions(recipe *cooklang.Recipe) []interface{} {
 	var instructions []interface{}
@@ -377,6 +424,12 @@ func (jr JSONLDRenderer) buildInstructions(recipe *cooklang.Recipe) []interface{
 
 	currentStep := recipe.FirstStep
 	for currentStep != nil {
+		// Notes are background, not instructions
+		if currentStep.IsNote() {
+			currentStep = currentStep.NextStep
+			continue
+		}
+
 		// Build step text from components
 		var stepText strings.Builder
 		var isSection bool
@@ -390,6 +443,8 @@ func (jr JSONLDRenderer) buildInstructions(recipe *cooklang.Recipe) []interface{
 				sectionName = comp.Name
 			case *cooklang.Instruction:
 				stepText.WriteString(comp.Text)
+			case *cooklang.Temperature:
+				stepText.WriteString(comp.RenderDisplay())
 			case *cooklang.Ingredient:
 				stepText.WriteString(comp.Name)
 			case *cooklang.Cookware:
@@ -432,6 +487,11 @@ func (jr JSONLDRenderer) buildInstructions(recipe *cooklang.Recipe) []interface{
 				"position": position,
 				"text":     text,
 			}
+			if len(currentStep.Images) == 1 {
+				step["image"] = currentStep.Images[0]
+			} else if len(currentStep.Images) > 1 {
+				step["image"] = currentStep.Images
+			}
 			position++
 
 			if currentSection != nil {
@@ -462,7 +522,8 @@ func (jr JSONLDRenderer) buildInstructions(recipe *cooklang.Recipe) []interface{
 //
 // Returns:
 //   - string: The JSON-LD as an indented JSON string
-//   - error: Any error during JSON marshaling
+//   - error: ErrMissingTitle for recipes without a title, which Schema.org recipes
+//     need as their name, or an error wrapping ErrEncoding
 //
 // Example:
 //
@@ -473,17 +534,64 @@ func (jr JSONLDRenderer) buildInstructions(recipe *cooklang.Recipe) []interface{
 //	    log.Fatal(err)
 //	}
 //	fmt.Println(jsonStr)
+//
+// Deprecated: Set Page and use Render, which also takes RendererOptions.
 func (jr JSONLDRenderer) RenderRecipeJSON(recipe *cooklang.Recipe, opts *JSONLDOptions) (string, error) {
-	data := jr.RenderRecipe(recipe, opts)
+	return JSONLDRenderer{Page: opts}.Render(recipe, RendererOptions{})
+}
+
+// RenderRecipe renders the recipe as an indented JSON-LD string with the renderer's
+// Page data, like Render without options.
+//
+// Parameters:
+//   - recipe: The parsed Cooklang recipe to render
+//
+// Returns:
+//   - string: The JSON-LD as an indented JSON string
+//   - error: As returned by Render
+func (jr JSONLDRenderer) RenderRecipe(recipe *cooklang.Recipe) (string, error) {
+	return jr.Render(recipe, RendererOptions{})
+}
+
+// Render renders the recipe as an indented JSON-LD string, using the renderer's Page
+// data. Of the options, only Units, PreferredUnits and NoImages apply; NoImages
+// also leaves out Page.Images.
+//
+// Parameters:
+//   - recipe: The parsed Cooklang recipe to render
+//   - opts: The renderer options
+//
+// Returns:
+//   - string: The JSON-LD as an indented JSON string
+//   - error: ErrMissingTitle for recipes without a title, which Schema.org recipes
+//     need as their name, or an error wrapping ErrEncoding
+//
+// Example:
+//
+//	renderer := renderers.JSONLDRenderer{Page: &renderers.JSONLDOptions{URL: "https://example.com/recipes/margarita"}}
+//	jsonStr, err := renderer.Render(recipe, renderers.RendererOptions{})
+func (jr JSONLDRenderer) Render(recipe *cooklang.Recipe, opts RendererOptions) (string, error) {
+	page := jr.Page
+	if opts.NoImages && page != nil {
+		withoutImages := *page
+		withoutImages.Images = nil
+		page = &withoutImages
+	}
+	if recipe.Title == "" {
+		return "", ErrMissingTitle
+	}
+	data := jr.BuildRecipe(opts.prepare(recipe), page)
 	bytes, err := json.MarshalIndent(data, "", "  ")
 	if err != nil {
-		return "", fmt.Errorf("failed to marshal JSON-LD: %w", err)
+		return "", fmt.Errorf("%w: JSON-LD: %w", ErrEncoding, err)
 	}
 	return string(bytes), nil
 }
 
 // RenderRecipeScriptTag returns a complete HTML <script> tag with JSON-LD content.
-// This is ready to be embedded directly in an HTML page's <head> section.
+// This is ready to be embedded directly in an HTML page's <head> section. Recipe
+// text cannot close the script element: "<", ">" and "&" are written as \u003c,
+// \u003e and \u0026, which JSON parsers read back as the original characters.
 //
 // The output format is:
 //
@@ -501,7 +609,7 @@ func (jr JSONLDRenderer) RenderRecipeJSON(recipe *cooklang.Recipe, opts *JSONLDO
 //
 // Returns:
 //   - string: The complete script tag with JSON-LD content
-//   - error: Any error during JSON marshaling
+//   - error: As returned by Render
 //
 // Example:
 //
@@ -515,11 +623,29 @@ func (jr JSONLDRenderer) RenderRecipeJSON(recipe *cooklang.Recipe, opts *JSONLDO
 //	}
 //	// Embed scriptTag in your HTML template
 func (jr JSONLDRenderer) RenderRecipeScriptTag(recipe *cooklang.Recipe, opts *JSONLDOptions) (string, error) {
-	jsonStr, err := jr.RenderRecipeJSON(recipe, opts)
+	jsonStr, err := JSONLDRenderer{Page: opts}.Render(recipe, RendererOptions{})
 	if err != nil {
 		return "", err
 	}
-	return fmt.Sprintf("<script type=\"application/ld+json\">\n%s\n</script>", jsonStr), nil
+	return fmt.Sprintf("<script type=\"application/ld+json\">\n%s\n</script>", scriptSafeJSON(jsonStr)), nil
+}
+
+// scriptSafeReplacer escapes the characters that can end a <script> element early
+// ("</script>", "<!--") or break it as JavaScript (line and paragraph separators).
+var scriptSafeReplacer = strings.NewReplacer(
+	"<", `\u003c`,
+	">", `\u003e`,
+	"&", `\u0026`,
+	"\u2028", `\u2028`,
+	"\u2029", `\u2029`,
+)
+
+// scriptSafeJSON makes JSON safe to embed in an HTML <script> element, so a
+// description such as "</script><script>alert(1)</script>" stays inside the JSON-LD.
+// The escapes are valid JSON string escapes; encoding/json writes the same ones, so
+// already escaped JSON is returned unchanged.
+func scriptSafeJSON(jsonStr string) string {
+	return scriptSafeReplacer.Replace(jsonStr)
 }
 
 // ParseDurationToISO8601 converts human-readable duration strings to ISO 8601 format.
@@ -631,6 +757,22 @@ func ParseDurationToISO8601(duration string) string {
 	return result
 }
 
+// durationToISO8601 writes a duration in ISO 8601 format, e.g. 1h15m → "PT1H15M".
+func durationToISO8601(d time.Duration) string {
+	hours, minutes, seconds := int(d/time.Hour), int(d%time.Hour/time.Minute), int(d%time.Minute/time.Second)
+	result := "PT"
+	if hours > 0 {
+		result += fmt.Sprintf("%dH", hours)
+	}
+	if minutes > 0 {
+		result += fmt.Sprintf("%dM", minutes)
+	}
+	if seconds > 0 || result == "PT" {
+		result += fmt.Sprintf("%dS", seconds)
+	}
+	return result
+}
+
 // NewJSONLDRenderer creates a new JSON-LD renderer.
 // This is a convenience function that returns a configured JSONLDRenderer instance.
 func NewJSONLDRenderer() JSONLDRenderer {
@@ -2,6 +2,8 @@ package renderers
 
 import (
 	"encoding/json"
+	"fmt"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -35,7 +37,7 @@ Strain into a salt-rimmed #coupe glass{}.
 	}
 
 	renderer := JSONLDRenderer{}
-	data := renderer.RenderRecipe(recipe, nil)
+	data := renderer.BuildRecipe(recipe, nil)
 
 	// Check required fields
 	if data["@context"] != "https://schema.org" {
@@ -114,12 +116,36 @@ Strain into a salt-rimmed #coupe glass{}.
 	}
 
 	// Check tools/cookware
-	tools, ok := data["tool"].([]string)
+	tools, ok := data["tool"].([]interface{})
 	if !ok {
-		t.Fatalf("Expected tool to be []string, got %T", data["tool"])
+		t.Fatalf("Expected tool to be []interface{}, got %T", data["tool"])
 	}
 	if len(tools) != 2 {
-		t.Errorf("Expected 2 tools, got %d: %v", len(tools), tools)
+		t.Fatalf("Expected 2 tools, got %d: %v", len(tools), tools)
+	}
+	tool, ok := tools[0].(map[string]interface{})
+	if !ok || tool["@type"] != "HowToTool" || tool["name"] != "cocktail shaker" || tool["requiredQuantity"] != 1 {
+		t.Errorf("Expected a HowToTool for the cocktail shaker, got %v", tools[0])
+	}
+}
+
+func TestJSONLDRenderer_Tools(t *testing.T) {
+	recipe, err := cooklang.ParseString("Heat #pan{two}(non-stick) and #bowl{}.\n\nWhisk in the #bowl{3}(large).")
+	if err != nil {
+		t.Fatalf("Failed to parse recipe: %v", err)
+	}
+	tools := JSONLDRenderer{}.BuildRecipe(recipe, nil)["tool"].([]interface{})
+	want := []map[string]interface{}{
+		{"@type": "HowToTool", "name": "pan", "requiredQuantity": "two", "description": "non-stick"},
+		{"@type": "HowToTool", "name": "bowl", "requiredQuantity": 3, "description": "large"},
+	}
+	if len(tools) != len(want) {
+		t.Fatalf("Expected %d tools, got %v", len(want), tools)
+	}
+	for i, tool := range tools {
+		if fmt.Sprint(tool) != fmt.Sprint(want[i]) {
+			t.Errorf("tool %d = %v, want %v", i, tool, want[i])
+		}
 	}
 }
 
@@ -156,7 +182,7 @@ Mix @ingredient{1%cup}.
 	}
 
 	renderer := JSONLDRenderer{}
-	data := renderer.RenderRecipe(recipe, opts)
+	data := renderer.BuildRecipe(recipe, opts)
 
 	// Check URL
 	if data["url"] != "https://example.com/recipes/test" {
@@ -234,7 +260,7 @@ Mix @ingredient{}.
 	}
 
 	renderer := JSONLDRenderer{}
-	data := renderer.RenderRecipe(recipe, opts)
+	data := renderer.BuildRecipe(recipe, opts)
 
 	images, ok := data["image"].([]string)
 	if !ok {
@@ -365,7 +391,7 @@ Garnish with @lime wheel{1}.
 	}
 
 	renderer := JSONLDRenderer{}
-	data := renderer.RenderRecipe(recipe, nil)
+	data := renderer.BuildRecipe(recipe, nil)
 
 	instructions, ok := data["recipeInstructions"].([]interface{})
 	if !ok {
@@ -417,7 +443,7 @@ Mix @ingredient{}.
 	}
 
 	renderer := JSONLDRenderer{}
-	data := renderer.RenderRecipe(recipe, opts)
+	data := renderer.BuildRecipe(recipe, opts)
 
 	video, ok := data["video"].(map[string]interface{})
 	if !ok {
@@ -453,7 +479,7 @@ func TestJSONLDRenderer_MultipleServings(t *testing.T) {
 			}
 
 			renderer := JSONLDRenderer{}
-			data := renderer.RenderRecipe(recipe, nil)
+			data := renderer.BuildRecipe(recipe, nil)
 
 			if data["recipeYield"] != test.expected {
 				t.Errorf("Expected recipeYield to be %q, got %v", test.expected, data["recipeYield"])
@@ -462,9 +488,86 @@ func TestJSONLDRenderer_MultipleServings(t *testing.T) {
 	}
 }
 
+func TestJSONLDRenderer_Yield(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected interface{}
+	}{
+		{"yield only", "---\nyield: 24 cookies\n---\nMix @flour{250%g}.", "24 cookies"},
+		{"yield and servings", "---\nyield: 2%loaves\nservings: 16\n---\nMix @flour{1%kg}.", []string{"2 loaves", "16 servings"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			recipe, err := cooklang.ParseString(test.input)
+			if err != nil {
+				t.Fatalf("Failed to parse recipe: %v", err)
+			}
+
+			data := JSONLDRenderer{}.BuildRecipe(recipe, nil)
+			if !reflect.DeepEqual(data["recipeYield"], test.expected) {
+				t.Errorf("Expected recipeYield to be %v, got %v", test.expected, data["recipeYield"])
+			}
+		})
+	}
+}
+
+func TestJSONLDRenderer_Times(t *testing.T) {
+	recipe, err := cooklang.ParseString("---\ntitle: Bread\nprep_time: 20 minutes\ncook_time: 45 min\n---\nBake the @dough{}.")
+	if err != nil {
+		t.Fatalf("Failed to parse recipe: %v", err)
+	}
+
+	data := JSONLDRenderer{}.BuildRecipe(recipe, nil)
+	expected := map[string]string{"prepTime": "PT20M", "cookTime": "PT45M", "totalTime": "PT1H5M"}
+	for key, want := range expected {
+		if data[key] != want {
+			t.Errorf("Expected %s to be %q, got %v", key, want, data[key])
+		}
+	}
+}
+
 func TestNewJSONLDRenderer(t *testing.T) {
 	renderer := NewJSONLDRenderer()
 	if renderer != (JSONLDRenderer{}) {
 		t.Error("NewJSONLDRenderer should return an empty JSONLDRenderer struct")
 	}
 }
+
+func TestJSONLDRenderer_RenderRecipeScriptTagHostileContent(t *testing.T) {
+	recipe, err := cooklang.ParseString(`---
+title: </script><script>alert('title')</script>
+author: Eve <eve@example.com>
+---
+Mix @</script>{1%cup}(<img src=x onerror=alert(1)>) in a #<bowl>{}.
+`)
+	if err != nil {
+		t.Fatalf("Failed to parse recipe: %v", err)
+	}
+	recipe.Description = "<!-- A & B </SCRIPT> \u2028"
+
+	scriptTag, err := JSONLDRenderer{}.RenderRecipeScriptTag(recipe, &JSONLDOptions{URL: "https://example.com/?a=1&b=<2>"})
+	if err != nil {
+		t.Fatalf("Failed to render script tag: %v", err)
+	}
+
+	content := strings.TrimSuffix(strings.TrimPrefix(scriptTag, "<script type=\"application/ld+json\">\n"), "\n</script>")
+	for _, unsafe := range []string{"<", ">", "&", "\u2028"} {
+		if strings.Contains(content, unsafe) {
+			t.Errorf("script content contains %q:\n%s", unsafe, scriptTag)
+		}
+	}
+
+	// The escapes read back as the original text
+	var data map[string]any
+	if err := json.Unmarshal([]byte(content), &data); err != nil {
+		t.Fatalf("script content is not valid JSON: %v\n%s", err, content)
+	}
+	if data["name"] != "</script><script>alert('title')</script>" {
+		t.Errorf("name = %q", data["name"])
+	}
+	if data["description"] != "<!-- A & B </SCRIPT> \u2028" {
+		t.Errorf("description = %q", data["description"])
+	}
+}
@@ -0,0 +1,258 @@
+package renderers
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/hilli/cooklang"
+)
+
+// Strings holds the labels the Markdown, HTML and Print renderers write around a
+// recipe's own text. Recipe content such as ingredient names and steps is never
+// translated.
+type Strings struct {
+	RecipeInformation string // Heading of the metadata section
+	Description       string
+	Cuisine           string
+	Date              string
+	Difficulty        string
+	PrepTime          string
+	TotalTime         string
+	Author            string
+	Servings          string
+	Tags              string
+	Images            string
+	Ingredients       string // Heading of the ingredient list
+	Cookware          string // Heading of the cookware list
+	Instructions      string // Heading of the steps
+	Optional          string // Marker for optional ingredients
+	Some              string // Amount of ingredients without a quantity
+	Recipe            string // Page title of untitled recipes
+	Prep              string // Short label for the prep time
+	Total             string // Short label for the total time
+	By                string // Label before the author
+	Oven              string // Label of the oven temperature, see cooklang.Recipe.OvenTemperature
+	// Reserved is the callout on steps that use an output reserved earlier. It is a
+	// format string with the amount and item (%[1]s) and the step number (%[2]d).
+	Reserved       string
+	ReservedOutput string // Used in Reserved when the reservation names no item
+	// Step heads a step's ingredients when they are grouped by step. It is a format
+	// string with the step number (%d).
+	Step string
+	// ForServings, PerServing and Originally write the quantity notes of
+	// RendererOptions.QuantityNotes. They are format strings with the number of
+	// servings or the amount (%s).
+	ForServings string
+	PerServing  string
+	Originally  string
+	// Contents, Recipes and Other label the table of contents, the cover and the
+	// chapter of untagged recipes of a PrintRenderer booklet.
+	Contents string
+	Recipes  string
+	Other    string
+	// Easy, Medium and Hard name the difficulty levels of cooklang.ParseDifficulty;
+	// other difficulty text is shown as written.
+	Easy   string
+	Medium string
+	Hard   string
+}
+
+// Translations holds the bundled labels by language code: da (Danish), de (German),
+// en (English), es (Spanish), fr (French), it (Italian), nl (Dutch) and sv (Swedish).
+// Add an entry to support another language.
+var Translations = map[string]Strings{
+	"en": {
+		RecipeInformation: "Recipe Information", Description: "Description", Cuisine: "Cuisine", Date: "Date",
+		Difficulty: "Difficulty", PrepTime: "Prep Time", TotalTime: "Total Time", Author: "Author",
+		Servings: "Servings", Tags: "Tags", Images: "Images", Ingredients: "Ingredients", Cookware: "Cookware",
+		Instructions: "Instructions", Optional: "optional", Some: "some", Recipe: "Recipe",
+		Prep: "Prep", Total: "Total", By: "By", Oven: "Oven", Step: "Step %d",
+		Reserved: "Use the reserved %[1]s from step %[2]d", ReservedOutput: "output",
+		ForServings: "for %s servings", PerServing: "%s per serving", Originally: "originally %s",
+		Contents: "Contents", Recipes: "Recipes", Other: "Other",
+		Easy: "Easy", Medium: "Medium", Hard: "Hard",
+	},
+	"da": {
+		RecipeInformation: "Om opskriften", Description: "Beskrivelse", Cuisine: "Køkken", Date: "Dato",
+		Difficulty: "Sværhedsgrad", PrepTime: "Forberedelsestid", TotalTime: "Samlet tid", Author: "Forfatter",
+		Servings: "Portioner", Tags: "Tags", Images: "Billeder", Ingredients: "Ingredienser", Cookware: "Køkkenudstyr",
+		Instructions: "Fremgangsmåde", Optional: "valgfri", Some: "lidt", Recipe: "Opskrift",
+		Prep: "Forberedelse", Total: "I alt", By: "Af", Oven: "Ovn", Step: "Trin %d",
+		Reserved: "Brug det gemte fra trin %[2]d: %[1]s", ReservedOutput: "resultat",
+		ForServings: "til %s portioner", PerServing: "%s pr. portion", Originally: "oprindeligt %s",
+		Contents: "Indhold", Recipes: "Opskrifter", Other: "Andet",
+		Easy: "Let", Medium: "Mellem", Hard: "Svær",
+	},
+	"de": {
+		RecipeInformation: "Rezeptinformationen", Description: "Beschreibung", Cuisine: "Küche", Date: "Datum",
+		Difficulty: "Schwierigkeit", PrepTime: "Vorbereitungszeit", TotalTime: "Gesamtzeit", Author: "Autor",
+		Servings: "Portionen", Tags: "Schlagwörter", Images: "Bilder", Ingredients: "Zutaten", Cookware: "Küchengeräte",
+		Instructions: "Zubereitung", Optional: "optional", Some: "etwas", Recipe: "Rezept",
+		Prep: "Vorbereitung", Total: "Gesamt", By: "Von", Oven: "Ofen", Step: "Schritt %d",
+		Reserved: "Das Zurückbehaltene aus Schritt %[2]d verwenden: %[1]s", ReservedOutput: "Ergebnis",
+		ForServings: "für %s Portionen", PerServing: "%s pro Portion", Originally: "ursprünglich %s",
+		Contents: "Inhalt", Recipes: "Rezepte", Other: "Sonstiges",
+		Easy: "Leicht", Medium: "Mittel", Hard: "Schwer",
+	},
+	"es": {
+		RecipeInformation: "Información de la receta", Description: "Descripción", Cuisine: "Cocina", Date: "Fecha",
+		Difficulty: "Dificultad", PrepTime: "Tiempo de preparación", TotalTime: "Tiempo total", Author: "Autor",
+		Servings: "Raciones", Tags: "Etiquetas", Images: "Imágenes", Ingredients: "Ingredientes", Cookware: "Utensilios",
+		Instructions: "Preparación", Optional: "opcional", Some: "un poco", Recipe: "Receta",
+		Prep: "Preparación", Total: "Total", By: "Por", Oven: "Horno", Step: "Paso %d",
+		Reserved: "Usa lo reservado en el paso %[2]d: %[1]s", ReservedOutput: "resultado",
+		ForServings: "para %s raciones", PerServing: "%s por ración", Originally: "originalmente %s",
+		Contents: "Índice", Recipes: "Recetas", Other: "Otras",
+		Easy: "Fácil", Medium: "Media", Hard: "Difícil",
+	},
+	"fr": {
+		RecipeInformation: "Informations sur la recette", Description: "Description", Cuisine: "Cuisine", Date: "Date",
+		Difficulty: "Difficulté", PrepTime: "Temps de préparation", TotalTime: "Temps total", Author: "Auteur",
+		Servings: "Portions", Tags: "Étiquettes", Images: "Images", Ingredients: "Ingrédients", Cookware: "Ustensiles",
+		Instructions: "Étapes", Optional: "facultatif", Some: "un peu", Recipe: "Recette",
+		Prep: "Préparation", Total: "Total", By: "Par", Oven: "Four", Step: "Étape %d",
+		Reserved: "Utiliser ce qui a été réservé à l'étape %[2]d : %[1]s", ReservedOutput: "préparation",
+		ForServings: "pour %s portions", PerServing: "%s par portion", Originally: "à l'origine %s",
+		Contents: "Sommaire", Recipes: "Recettes", Other: "Autres",
+		Easy: "Facile", Medium: "Moyen", Hard: "Difficile",
+	},
+	"it": {
+		RecipeInformation: "Informazioni sulla ricetta", Description: "Descrizione", Cuisine: "Cucina", Date: "Data",
+		Difficulty: "Difficoltà", PrepTime: "Tempo di preparazione", TotalTime: "Tempo totale", Author: "Autore",
+		Servings: "Porzioni", Tags: "Tag", Images: "Immagini", Ingredients: "Ingredienti", Cookware: "Utensili",
+		Instructions: "Procedimento", Optional: "facoltativo", Some: "q.b.", Recipe: "Ricetta",
+		Prep: "Preparazione", Total: "Totale", By: "Di", Oven: "Forno", Step: "Passaggio %d",
+		Reserved: "Usa quanto messo da parte al passaggio %[2]d: %[1]s", ReservedOutput: "preparazione",
+		ForServings: "per %s porzioni", PerServing: "%s a porzione", Originally: "originariamente %s",
+		Contents: "Indice", Recipes: "Ricette", Other: "Altro",
+		Easy: "Facile", Medium: "Media", Hard: "Difficile",
+	},
+	"nl": {
+		RecipeInformation: "Receptinformatie", Description: "Beschrijving", Cuisine: "Keuken", Date: "Datum",
+		Difficulty: "Moeilijkheid", PrepTime: "Voorbereidingstijd", TotalTime: "Totale tijd", Author: "Auteur",
+		Servings: "Porties", Tags: "Tags", Images: "Afbeeldingen", Ingredients: "Ingrediënten", Cookware: "Keukengerei",
+		Instructions: "Bereiding", Optional: "optioneel", Some: "wat", Recipe: "Recept",
+		Prep: "Voorbereiding", Total: "Totaal", By: "Door", Oven: "Oven", Step: "Stap %d",
+		Reserved: "Gebruik wat bewaard is uit stap %[2]d: %[1]s", ReservedOutput: "resultaat",
+		ForServings: "voor %s porties", PerServing: "%s per portie", Originally: "oorspronkelijk %s",
+		Contents: "Inhoud", Recipes: "Recepten", Other: "Overig",
+		Easy: "Makkelijk", Medium: "Gemiddeld", Hard: "Moeilijk",
+	},
+	"sv": {
+		RecipeInformation: "Om receptet", Description: "Beskrivning", Cuisine: "Kök", Date: "Datum",
+		Difficulty: "Svårighetsgrad", PrepTime: "Förberedelsetid", TotalTime: "Total tid", Author: "Författare",
+		Servings: "Portioner", Tags: "Taggar", Images: "Bilder", Ingredients: "Ingredienser", Cookware: "Köksredskap",
+		Instructions: "Gör så här", Optional: "valfri", Some: "lite", Recipe: "Recept",
+		Prep: "Förberedelse", Total: "Totalt", By: "Av", Oven: "Ugn", Step: "Steg %d",
+		Reserved: "Använd det som sparades i steg %[2]d: %[1]s", ReservedOutput: "resultatet",
+		ForServings: "för %s portioner", PerServing: "%s per portion", Originally: "ursprungligen %s",
+		Contents: "Innehåll", Recipes: "Recept", Other: "Övrigt",
+		Easy: "Lätt", Medium: "Medel", Hard: "Svår",
+	},
+}
+
+// Locales returns the language codes in Translations, sorted.
+func Locales() []string {
+	locales := make([]string, 0, len(Translations))
+	for locale := range Translations {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// LocaleStrings returns the labels for a locale such as "de", "fr-CA" or "da_DK".
+// The region is ignored; unknown locales get English labels.
+//
+// Parameters:
+//   - locale: A language code, optionally with a region
+//
+// Returns:
+//   - Strings: The labels, with any missing translation filled in from English
+func LocaleStrings(locale string) Strings {
+	language := strings.ToLower(locale)
+	if i := strings.IndexAny(language, "-_"); i >= 0 {
+		language = language[:i]
+	}
+	labels, ok := Translations[language]
+	if !ok {
+		return Translations["en"]
+	}
+	return labels.withDefaults(Translations["en"])
+}
+
+// withDefaults returns s with empty labels taken from defaults.
+func (s Strings) withDefaults(defaults Strings) Strings {
+	v := reflect.ValueOf(&s).Elem()
+	d := reflect.ValueOf(defaults)
+	for i := 0; i < v.NumField(); i++ {
+		if v.Field(i).String() == "" {
+			v.Field(i).SetString(d.Field(i).String())
+		}
+	}
+	return s
+}
+
+// labels returns the labels to render with.
+func (o RendererOptions) labels() Strings {
+	labels := LocaleStrings(o.Locale)
+	if o.Strings != nil {
+		labels = o.Strings.withDefaults(labels)
+	}
+	return labels
+}
+
+// Language returns the language of the labels as a code for HTML lang attributes,
+// e.g. "de" for the locale "de-AT". It is "en" when the locale has no translation,
+// unless custom Strings are given.
+func (o RendererOptions) Language() string {
+	language := strings.ToLower(o.Locale)
+	if i := strings.IndexAny(language, "-_"); i >= 0 {
+		language = language[:i]
+	}
+	if _, ok := Translations[language]; !ok && (o.Strings == nil || language == "") {
+		return "en"
+	}
+	return language
+}
+
+// temperature formats a temperature in the preferred scale, or in both.
+func (o RendererOptions) temperature(t *cooklang.Temperature) string {
+	if o.TemperatureScale != "" {
+		t = t.ConvertTo(o.TemperatureScale)
+	}
+	if o.BothTemperatureScales {
+		return t.RenderBothScales()
+	}
+	return t.RenderDisplay()
+}
+
+// difficulty returns the name of a difficulty level in the labels' language, or the
+// difficulty as written if it is not a known level.
+func (s Strings) difficulty(text string) string {
+	level, _ := cooklang.ParseDifficulty(text)
+	switch level {
+	case cooklang.DifficultyEasy:
+		return s.Easy
+	case cooklang.DifficultyMedium:
+		return s.Medium
+	case cooklang.DifficultyHard:
+		return s.Hard
+	}
+	return text
+}
+
+// stepHeading formats the heading of a step's ingredients.
+func (s Strings) stepHeading(step int) string {
+	return fmt.Sprintf(s.Step, step)
+}
+
+// reservedCallout formats the callout for a reserved output used in a later step.
+func (s Strings) reservedCallout(what string, step int) string {
+	if what == "" {
+		what = s.ReservedOutput
+	}
+	return fmt.Sprintf(s.Reserved, what, step)
+}
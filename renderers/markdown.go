@@ -21,11 +21,40 @@ func simpleTitle(s string) string {
 	return strings.Join(words, " ")
 }
 
-// MarkdownRenderer renders recipes in Markdown format
-type MarkdownRenderer struct{}
+// MarkdownRenderer renders recipes in Markdown format, or in the Markdown or wiki
+// markup of Notion and Confluence with Flavor.
+//
+// Example:
+//
+//	notion := renderers.MarkdownRenderer{Flavor: renderers.MarkdownNotion}
+//	page, err := notion.RenderRecipe(recipe)
+type MarkdownRenderer struct {
+	Options RendererOptions // Locale and labels; the zero value renders English
+	Flavor  MarkdownFlavor  // Dialect to write; the zero value writes CommonMark
+
+	shortcodes SiteFlavor // Writes ingredients as shortcodes of this site generator, for SiteMarkdownRenderer
+}
+
+// Render renders the recipe as Markdown with the given options.
+func (mr MarkdownRenderer) Render(recipe *cooklang.Recipe, opts RendererOptions) (string, error) {
+	mr.Options = opts
+	return mr.RenderRecipe(recipe)
+}
+
+// RenderRecipe renders the recipe as Markdown with the renderer's Options. An
+// unknown Flavor returns an error.
+func (mr MarkdownRenderer) RenderRecipe(recipe *cooklang.Recipe) (string, error) {
+	switch mr.Flavor {
+	case MarkdownCommon, MarkdownNotion:
+	case MarkdownConfluence:
+		return mr.renderConfluence(recipe), nil
+	default:
+		return "", fmt.Errorf("unknown Markdown flavor: %s (use notion or confluence)", mr.Flavor)
+	}
 
-func (mr MarkdownRenderer) RenderRecipe(recipe *cooklang.Recipe) string {
 	var result strings.Builder
+	labels := mr.Options.labels()
+	recipe = mr.Options.prepare(recipe)
 
 	// Title
 	if recipe.Title != "" {
@@ -33,45 +62,47 @@ func (mr MarkdownRenderer) RenderRecipe(recipe *cooklang.Recipe) string {
 	}
 
 	// Metadata section
-	if recipe.Description != "" || recipe.Cuisine != "" || recipe.Difficulty != "" ||
+	if mr.Flavor == MarkdownNotion {
+		mr.renderNotionInformation(&result, recipe, labels)
+	} else if recipe.Description != "" || recipe.Cuisine != "" || recipe.Difficulty != "" ||
 		recipe.PrepTime != "" || recipe.TotalTime != "" || recipe.Author != "" ||
 		recipe.Servings > 0 || len(recipe.Tags) > 0 || len(recipe.Images) > 0 ||
 		!recipe.Date.IsZero() || len(recipe.Metadata) > 0 {
-		result.WriteString("## Recipe Information\n\n")
+		fmt.Fprintf(&result, "## %s\n\n", labels.RecipeInformation)
 
 		if recipe.Description != "" {
-			result.WriteString(fmt.Sprintf("**Description:** %s\n\n", recipe.Description))
+			result.WriteString(fmt.Sprintf("**%s:** %s\n\n", labels.Description, recipe.Description))
 		}
 		if recipe.Cuisine != "" {
-			result.WriteString(fmt.Sprintf("**Cuisine:** %s\n\n", recipe.Cuisine))
+			result.WriteString(fmt.Sprintf("**%s:** %s\n\n", labels.Cuisine, recipe.Cuisine))
 		}
 		if !recipe.Date.IsZero() {
-			result.WriteString(fmt.Sprintf("**Date:** %s\n\n", recipe.Date.Format("2006-01-02")))
+			result.WriteString(fmt.Sprintf("**%s:** %s\n\n", labels.Date, recipe.Date.Format("2006-01-02")))
 		}
 		if recipe.Difficulty != "" {
-			result.WriteString(fmt.Sprintf("**Difficulty:** %s\n\n", recipe.Difficulty))
+			result.WriteString(fmt.Sprintf("**%s:** %s\n\n", labels.Difficulty, labels.difficulty(recipe.Difficulty)))
 		}
 		if recipe.PrepTime != "" {
-			result.WriteString(fmt.Sprintf("**Prep Time:** %s\n\n", recipe.PrepTime))
+			result.WriteString(fmt.Sprintf("**%s:** %s\n\n", labels.PrepTime, recipe.PrepTime))
 		}
 		if recipe.TotalTime != "" {
-			result.WriteString(fmt.Sprintf("**Total Time:** %s\n\n", recipe.TotalTime))
+			result.WriteString(fmt.Sprintf("**%s:** %s\n\n", labels.TotalTime, recipe.TotalTime))
 		}
 		if recipe.Author != "" {
-			result.WriteString(fmt.Sprintf("**Author:** %s\n\n", recipe.Author))
+			result.WriteString(fmt.Sprintf("**%s:** %s\n\n", labels.Author, recipe.Author))
 		}
 		if recipe.Servings > 0 {
-			result.WriteString(fmt.Sprintf("**Servings:** %g\n\n", recipe.Servings))
+			result.WriteString(fmt.Sprintf("**%s:** %g\n\n", labels.Servings, recipe.Servings))
 		}
 		if len(recipe.Tags) > 0 {
-			result.WriteString("**Tags:**\n")
+			fmt.Fprintf(&result, "**%s:**\n", labels.Tags)
 			for _, tag := range recipe.Tags {
 				result.WriteString(fmt.Sprintf("  - %s\n", tag))
 			}
 			result.WriteString("\n")
 		}
 		if len(recipe.Images) > 0 {
-			result.WriteString("**Images:**\n")
+			fmt.Fprintf(&result, "**%s:**\n", labels.Images)
 			for _, img := range recipe.Images {
 				result.WriteString(fmt.Sprintf("  - %s\n", img))
 			}
@@ -83,47 +114,40 @@ func (mr MarkdownRenderer) RenderRecipe(recipe *cooklang.Recipe) string {
 				// Skip fields already displayed above
 				if key != "title" && key != "cuisine" && key != "date" && key != "description" &&
 					key != "difficulty" && key != "prep_time" && key != "total_time" &&
-					key != "author" && key != "servings" && key != "tags" && key != "images" && key != "image" {
-					result.WriteString(fmt.Sprintf("**%s:** %s\n\n", simpleTitle(strings.ReplaceAll(key, "_", " ")), value))
+					key != "author" && key != "servings" && key != "tags" && key != "images" && key != "image" && key != cooklang.StepImagesKey &&
+					mr.Options.showMetadata(key) {
+					result.WriteString(fmt.Sprintf("**%s:** %s\n\n", simpleTitle(strings.ReplaceAll(key, "_", " ")), mr.Options.metadataValue(key, value)))
 				}
 			}
 		}
 	}
 
+	mr.renderBody(&result, recipe, labels)
+	return result.String(), nil
+}
+
+// renderBody writes the ingredient list and the instructions.
+func (mr MarkdownRenderer) renderBody(result *strings.Builder, recipe *cooklang.Recipe, labels Strings) {
 	// Ingredients list
-	ingredients := recipe.GetIngredients()
-	if len(ingredients.Ingredients) > 0 {
-		result.WriteString("## Ingredients\n\n")
-
-		for _, ingredient := range ingredients.Ingredients {
-			result.WriteString("- ")
-			optionalSuffix := ""
-			if ingredient.Optional {
-				optionalSuffix = " *(optional)*"
+	if groups := mr.Options.ingredientGroups(recipe, labels, true); len(groups) > 0 {
+		fmt.Fprintf(result, "## %s\n\n", labels.Ingredients)
+
+		for _, group := range groups {
+			if group.Title != "" {
+				fmt.Fprintf(result, "### %s\n\n", group.Title)
 			}
-			if ingredient.Quantity > 0 {
-				if ingredient.Unit != "" {
-					result.WriteString(fmt.Sprintf("**%g %s** %s%s\n", ingredient.Quantity, ingredient.Unit, ingredient.Name, optionalSuffix))
-				} else {
-					result.WriteString(fmt.Sprintf("**%g** %s%s\n", ingredient.Quantity, ingredient.Name, optionalSuffix))
-				}
-			} else if ingredient.Quantity == -1 {
-				// "some" quantity
-				if ingredient.Unit != "" {
-					result.WriteString(fmt.Sprintf("**some %s** %s%s\n", ingredient.Unit, ingredient.Name, optionalSuffix))
-				} else {
-					result.WriteString(fmt.Sprintf("**some** %s%s\n", ingredient.Name, optionalSuffix))
-				}
-			} else {
-				result.WriteString(fmt.Sprintf("%s%s\n", ingredient.Name, optionalSuffix))
+			for _, ingredient := range group.Ingredients {
+				mr.renderIngredientItem(result, recipe, ingredient, labels)
 			}
+			result.WriteString("\n")
 		}
-		result.WriteString("\n")
 	}
+	result.WriteString(mr.Options.bakersTable(recipe, false))
 
 	// Instructions
-	result.WriteString("## Instructions\n\n")
+	fmt.Fprintf(result, "## %s\n\n", labels.Instructions)
 
+	callouts := reservedCallouts(recipe, labels)
 	stepNum := 1
 	currentStep := recipe.FirstStep
 	for currentStep != nil {
@@ -140,15 +164,18 @@ func (mr MarkdownRenderer) RenderRecipe(recipe *cooklang.Recipe) string {
 			if currentComponent != nil {
 				result.WriteString(fmt.Sprintf("%d. ", stepNum))
 				for currentComponent != nil {
-					mr.renderComponent(&result, currentComponent)
+					mr.renderComponent(result, currentComponent, labels)
 					currentComponent = currentComponent.GetNext()
 				}
+				mr.renderCallouts(result, callouts[currentStep])
 				result.WriteString("\n\n")
 				stepNum++
 			}
-		} else if note, ok := firstComp.(*cooklang.Note); ok {
-			// Render notes as blockquotes without step numbers
-			result.WriteString(fmt.Sprintf("> %s\n\n", note.Text))
+		} else if note, ok := firstComp.(*cooklang.Note); ok && mr.Flavor == MarkdownNotion {
+			result.WriteString(notionCallout(note.Text))
+		} else if ok {
+			// Render notes as italic blockquotes without step numbers
+			result.WriteString(fmt.Sprintf("> *%s*\n\n", note.Text))
 			// Don't increment step number for notes
 		} else {
 			result.WriteString(fmt.Sprintf("%d. ", stepNum))
@@ -156,25 +183,63 @@ func (mr MarkdownRenderer) RenderRecipe(recipe *cooklang.Recipe) string {
 			// Render components in markdown-friendly format
 			currentComponent := currentStep.FirstComponent
 			for currentComponent != nil {
-				mr.renderComponent(&result, currentComponent)
+				mr.renderComponent(result, currentComponent, labels)
 				currentComponent = currentComponent.GetNext()
 			}
+			mr.renderCallouts(result, callouts[currentStep])
 
 			result.WriteString("\n\n")
 			stepNum++
 		}
 		currentStep = currentStep.NextStep
 	}
+}
 
-	return result.String()
+// renderIngredientItem renders an ingredient as an item of the ingredient list
+func (mr MarkdownRenderer) renderIngredientItem(result *strings.Builder, recipe *cooklang.Recipe, ingredient *cooklang.Ingredient, labels Strings) {
+	result.WriteString("- ")
+	if mr.shortcodes != "" {
+		result.WriteString(mr.ingredientShortcode(ingredient) + "\n")
+		return
+	}
+	optionalSuffix := ""
+	if ingredient.Annotation != "" {
+		optionalSuffix = ", " + ingredient.Annotation
+	}
+	if note := mr.Options.quantityNote(recipe, ingredient, labels); note != "" {
+		optionalSuffix += " (" + note + ")"
+	}
+	if ingredient.Optional {
+		optionalSuffix += " *(" + labels.Optional + ")*"
+	}
+	if ingredient.Quantity.HasAmount() {
+		if ingredient.Unit != "" {
+			result.WriteString(fmt.Sprintf("**%s %s** %s%s\n", mr.Options.amount(ingredient), ingredient.Unit, ingredient.Name, optionalSuffix))
+		} else {
+			result.WriteString(fmt.Sprintf("**%s** %s%s\n", mr.Options.amount(ingredient), ingredient.Name, optionalSuffix))
+		}
+	} else if ingredient.Quantity.IsSome() {
+		// "some" quantity
+		if ingredient.Unit != "" {
+			result.WriteString(fmt.Sprintf("**%s %s** %s%s\n", labels.Some, ingredient.Unit, ingredient.Name, optionalSuffix))
+		} else {
+			result.WriteString(fmt.Sprintf("**%s** %s%s\n", labels.Some, ingredient.Name, optionalSuffix))
+		}
+	} else {
+		result.WriteString(fmt.Sprintf("%s%s\n", ingredient.Name, optionalSuffix))
+	}
 }
 
 // renderComponent renders a single component in markdown format
-func (mr MarkdownRenderer) renderComponent(result *strings.Builder, currentComponent cooklang.StepComponent) {
+func (mr MarkdownRenderer) renderComponent(result *strings.Builder, currentComponent cooklang.StepComponent, labels Strings) {
 	switch comp := currentComponent.(type) {
 	case *cooklang.Ingredient:
-		if comp.Quantity > 0 {
-			fmt.Fprintf(result, "**%s** (%g %s)", comp.Name, comp.Quantity, comp.Unit)
+		if mr.shortcodes != "" {
+			result.WriteString(mr.ingredientShortcode(comp))
+			return
+		}
+		if comp.Quantity.HasAmount() {
+			fmt.Fprintf(result, "**%s** (%s)", comp.Name, strings.TrimSpace(mr.Options.amount(comp)+" "+comp.Unit))
 		} else {
 			fmt.Fprintf(result, "**%s**", comp.Name)
 		}
@@ -182,11 +247,11 @@ func (mr MarkdownRenderer) renderComponent(result *strings.Builder, currentCompo
 			fmt.Fprintf(result, " (%s)", comp.Annotation)
 		}
 		if comp.Optional {
-			result.WriteString(" *(optional)*")
+			fmt.Fprintf(result, " *(%s)*", labels.Optional)
 		}
 	case *cooklang.Cookware:
-		if comp.Quantity > 1 {
-			fmt.Fprintf(result, "*%s* (x%d)", comp.Name, comp.Quantity)
+		if quantity := comp.QuantityLabel(); quantity != "" {
+			fmt.Fprintf(result, "*%s* (%s)", comp.Name, quantity)
 		} else {
 			fmt.Fprintf(result, "*%s*", comp.Name)
 		}
@@ -195,15 +260,17 @@ func (mr MarkdownRenderer) renderComponent(result *strings.Builder, currentCompo
 		}
 	case *cooklang.Timer:
 		if comp.Name != "" {
-			fmt.Fprintf(result, "⏲️ %s (%s)", comp.Name, comp.Duration)
+			fmt.Fprintf(result, "⏲️ %s (%s)", comp.Name, comp)
 		} else {
-			fmt.Fprintf(result, "⏲️ %s", comp.Duration)
+			fmt.Fprintf(result, "⏲️ %s", comp)
 		}
 		if comp.Annotation != "" {
 			fmt.Fprintf(result, " (%s)", comp.Annotation)
 		}
 	case *cooklang.Instruction:
 		result.WriteString(comp.Text)
+	case *cooklang.Temperature:
+		result.WriteString(mr.Options.temperature(comp))
 	case *cooklang.Section:
 		// Sections handled specially in the main render loop
 		if comp.Name != "" {
@@ -213,8 +280,19 @@ func (mr MarkdownRenderer) renderComponent(result *strings.Builder, currentCompo
 		// Render comments as italicized text
 		fmt.Fprintf(result, "*(%s)*", comp.Text)
 	case *cooklang.Note:
-		// Render notes as blockquotes (Markdown style)
-		fmt.Fprintf(result, "\n\n> %s\n\n", comp.Text)
+		// Render notes as italic blockquotes (Markdown style)
+		if mr.Flavor == MarkdownNotion {
+			result.WriteString("\n\n" + notionCallout(comp.Text))
+			return
+		}
+		fmt.Fprintf(result, "\n\n> *%s*\n\n", comp.Text)
+	}
+}
+
+// renderCallouts renders the reserved outputs used by a step as blockquotes inside the list item
+func (mr MarkdownRenderer) renderCallouts(result *strings.Builder, callouts []string) {
+	for _, callout := range callouts {
+		fmt.Fprintf(result, "\n\n   > ↩ %s", callout)
 	}
 }
 
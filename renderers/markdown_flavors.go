@@ -0,0 +1,191 @@
+package renderers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hilli/cooklang"
+)
+
+// MarkdownFlavor selects the dialect the MarkdownRenderer writes, for wikis and
+// note-taking apps that import Markdown their own way.
+type MarkdownFlavor string
+
+const (
+	MarkdownCommon     MarkdownFlavor = ""           // CommonMark, for READMEs and most Markdown tools
+	MarkdownNotion     MarkdownFlavor = "notion"     // Notion import: the recipe information as a table and notes as callouts
+	MarkdownConfluence MarkdownFlavor = "confluence" // Confluence wiki markup: h1. headings, ||tables|| and {info} panels for notes
+)
+
+// ParseMarkdownFlavor parses a Markdown flavor name: "notion", "confluence", or
+// "markdown" or "" for CommonMark.
+//
+// Parameters:
+//   - s: The flavor name (case-insensitive)
+//
+// Returns:
+//   - MarkdownFlavor: The flavor
+//   - error: An error if the name is unknown
+func ParseMarkdownFlavor(s string) (MarkdownFlavor, error) {
+	switch flavor := MarkdownFlavor(strings.ToLower(strings.TrimSpace(s))); flavor {
+	case MarkdownCommon, MarkdownNotion, MarkdownConfluence:
+		return flavor, nil
+	case "markdown", "commonmark":
+		return MarkdownCommon, nil
+	}
+	return "", fmt.Errorf("unknown Markdown flavor: %s (use notion or confluence)", s)
+}
+
+// tableCell escapes text for a cell of a Markdown table.
+func tableCell(text string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(text, "|", `\|`), "\n", " ")
+}
+
+// renderNotionInformation writes the description, the images and the recipe
+// information as a table, which Notion imports as a simple table.
+func (mr MarkdownRenderer) renderNotionInformation(result *strings.Builder, recipe *cooklang.Recipe, labels Strings) {
+	if recipe.Description != "" {
+		fmt.Fprintf(result, "%s\n\n", recipe.Description)
+	}
+	for _, src := range mr.Options.images(recipe) {
+		if !strings.HasPrefix(src, "data:") {
+			fmt.Fprintf(result, "![%s](%s)\n\n", recipe.Title, strings.ReplaceAll(src, " ", "%20"))
+		}
+	}
+
+	var headers, values []string
+	for _, field := range mr.Options.templateFields(recipe, labels) {
+		if field.Key != "description" {
+			headers = append(headers, tableCell(field.Label))
+			values = append(values, tableCell(field.Value))
+		}
+	}
+	if len(headers) == 0 {
+		return
+	}
+	fmt.Fprintf(result, "## %s\n\n", labels.RecipeInformation)
+	fmt.Fprintf(result, "| %s |\n", strings.Join(headers, " | "))
+	fmt.Fprintf(result, "|%s\n", strings.Repeat("---|", len(headers)))
+	fmt.Fprintf(result, "| %s |\n\n", strings.Join(values, " | "))
+}
+
+// notionCallout writes a note as a Notion callout.
+func notionCallout(text string) string {
+	return "<aside>\n💡 " + text + "\n</aside>\n\n"
+}
+
+// confluenceEscaper escapes the characters that are markup in Confluence wiki text.
+var confluenceEscaper = strings.NewReplacer(
+	`\`, `\\`, "*", `\*`, "_", `\_`, "{", `\{`, "}", `\}`, "[", `\[`, "]", `\]`,
+	"|", `\|`, "!", `\!`, "^", `\^`, "~", `\~`, "+", `\+`,
+)
+
+// renderConfluence writes the recipe in Confluence wiki markup: the recipe
+// information as a table with a heading column, bulleted ingredients, and steps
+// numbered from 1 in each section, with notes as {info} panels between them. The
+// step numbers are written out, so notes do not restart the numbering. Images that
+// are data URIs and the baker's percentages are left out.
+func (mr MarkdownRenderer) renderConfluence(recipe *cooklang.Recipe) string {
+	data := mr.Options.templateData(recipe)
+	labels := data.Labels
+	var result strings.Builder
+
+	fmt.Fprintf(&result, "h1. %s\n\n", confluenceEscaper.Replace(data.Title))
+	for _, src := range data.Images {
+		if !strings.HasPrefix(src, "data:") {
+			fmt.Fprintf(&result, "!%s!\n\n", src)
+		}
+	}
+	if len(data.Metadata) > 0 {
+		fmt.Fprintf(&result, "h2. %s\n\n", confluenceEscaper.Replace(labels.RecipeInformation))
+		for _, field := range data.Metadata {
+			fmt.Fprintf(&result, "||%s|%s|\n", confluenceEscaper.Replace(field.Label), confluenceEscaper.Replace(strings.ReplaceAll(field.Value, "\n", " ")))
+		}
+		result.WriteString("\n")
+	}
+
+	if len(data.Ingredients) > 0 {
+		fmt.Fprintf(&result, "h2. %s\n\n", confluenceEscaper.Replace(labels.Ingredients))
+		for _, group := range data.IngredientGroups {
+			if group.Title != "" {
+				fmt.Fprintf(&result, "h3. %s\n\n", confluenceEscaper.Replace(group.Title))
+			}
+			for _, ingredient := range group.Ingredients {
+				result.WriteString("* ")
+				if amount := strings.TrimSpace(ingredient.Amount + " " + ingredient.Unit); amount != "" {
+					fmt.Fprintf(&result, "*%s* ", confluenceEscaper.Replace(amount))
+				}
+				result.WriteString(confluenceEscaper.Replace(ingredient.Name))
+				if ingredient.Annotation != "" {
+					result.WriteString(", " + confluenceEscaper.Replace(ingredient.Annotation))
+				}
+				if ingredient.Note != "" {
+					fmt.Fprintf(&result, " (%s)", confluenceEscaper.Replace(ingredient.Note))
+				}
+				if ingredient.Optional {
+					fmt.Fprintf(&result, " _(%s)_", confluenceEscaper.Replace(labels.Optional))
+				}
+				result.WriteString("\n")
+			}
+			result.WriteString("\n")
+		}
+	}
+
+	fmt.Fprintf(&result, "h2. %s\n\n", confluenceEscaper.Replace(labels.Instructions))
+	for _, step := range data.Steps {
+		if step.NewSection {
+			fmt.Fprintf(&result, "h3. %s\n\n", confluenceEscaper.Replace(step.Section))
+		}
+		if step.Note {
+			fmt.Fprintf(&result, "{info}%s{info}\n\n", confluenceEscaper.Replace(step.Text))
+			continue
+		}
+		fmt.Fprintf(&result, "%d. ", step.Number)
+		for component := range step.Step.Components() {
+			mr.renderConfluenceComponent(&result, component, labels)
+		}
+		for _, callout := range step.Callouts {
+			fmt.Fprintf(&result, " \\\\ _↩ %s_", confluenceEscaper.Replace(callout))
+		}
+		result.WriteString("\n\n")
+	}
+	return result.String()
+}
+
+// renderConfluenceComponent writes a step component in Confluence wiki markup:
+// ingredients in bold and cookware in italics, as the Markdown renderer does.
+func (mr MarkdownRenderer) renderConfluenceComponent(result *strings.Builder, component cooklang.StepComponent, labels Strings) {
+	switch comp := component.(type) {
+	case *cooklang.Ingredient:
+		fmt.Fprintf(result, "*%s*", confluenceEscaper.Replace(comp.Name))
+		if comp.Quantity.HasAmount() {
+			fmt.Fprintf(result, " (%s)", confluenceEscaper.Replace(strings.TrimSpace(mr.Options.amount(comp)+" "+comp.Unit)))
+		}
+		if comp.Annotation != "" {
+			fmt.Fprintf(result, " (%s)", confluenceEscaper.Replace(comp.Annotation))
+		}
+		if comp.Optional {
+			fmt.Fprintf(result, " _(%s)_", confluenceEscaper.Replace(labels.Optional))
+		}
+	case *cooklang.Cookware:
+		fmt.Fprintf(result, "_%s_", confluenceEscaper.Replace(comp.Name))
+		if quantity := comp.QuantityLabel(); quantity != "" {
+			fmt.Fprintf(result, " (%s)", confluenceEscaper.Replace(quantity))
+		}
+		if comp.Annotation != "" {
+			fmt.Fprintf(result, " (%s)", confluenceEscaper.Replace(comp.Annotation))
+		}
+	case *cooklang.Timer:
+		text := comp.String()
+		if comp.Name != "" {
+			text = comp.Name + " (" + text + ")"
+		}
+		result.WriteString("⏲️ " + confluenceEscaper.Replace(text))
+	case *cooklang.Instruction:
+		result.WriteString(confluenceEscaper.Replace(comp.Text))
+	case *cooklang.Temperature:
+		result.WriteString(confluenceEscaper.Replace(mr.Options.temperature(comp)))
+	case *cooklang.Comment:
+		fmt.Fprintf(result, "_(%s)_", confluenceEscaper.Replace(comp.Text))
+	}
+}
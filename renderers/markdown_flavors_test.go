@@ -0,0 +1,87 @@
+package renderers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hilli/cooklang"
+)
+
+const flavorRecipe = `---
+title: Fish & Chips
+servings: 2
+prep_time: 20 minutes
+---
+
+== Batter ==
+Whisk @flour{200%g} and @beer{250%ml}(cold) in a #bowl{}.
+
+> Keep the batter cold.
+
+Rest for ~{10%minutes}.
+
+== Frying ==
+Heat @oil{} to 180°C.
+`
+
+func TestMarkdownFlavors(t *testing.T) {
+	recipe, err := cooklang.ParseString(flavorRecipe)
+	if err != nil {
+		t.Fatalf("Failed to parse recipe: %v", err)
+	}
+
+	notion := renderRecipe(t, MarkdownRenderer{Flavor: MarkdownNotion}, recipe)
+	for _, expected := range []string{
+		"# Fish & Chips\n\n## Recipe Information\n\n| Prep Time | Servings |\n|---|---|\n| 20 minutes | 2 |\n\n",
+		"- **250 ml** beer, cold\n",
+		"<aside>\n💡 Keep the batter cold.\n</aside>\n\n2. Rest for",
+	} {
+		if !strings.Contains(notion, expected) {
+			t.Errorf("expected Notion output to contain %q, got:\n%s", expected, notion)
+		}
+	}
+	if strings.Contains(notion, "**Servings:**") || strings.Contains(notion, "> *Keep") {
+		t.Errorf("expected no metadata list or blockquote notes, got:\n%s", notion)
+	}
+
+	confluence := renderRecipe(t, MarkdownRenderer{Flavor: MarkdownConfluence}, recipe)
+	for _, expected := range []string{
+		"h1. Fish & Chips\n\nh2. Recipe Information\n\n||Prep Time|20 minutes|\n||Servings|2|\n\n",
+		"h2. Ingredients\n\n* *200 g* flour\n* *250 ml* beer, cold\n",
+		"h3. Batter\n\n1. ",
+		"*flour* (200 g)",
+		"_bowl_",
+		"{info}Keep the batter cold.{info}\n\n2. Rest for ⏲️ 10 minutes.",
+		"h3. Frying\n\n1. ",
+	} {
+		if !strings.Contains(confluence, expected) {
+			t.Errorf("expected Confluence output to contain %q, got:\n%s", expected, confluence)
+		}
+	}
+	if strings.Contains(confluence, "# ") || strings.Contains(confluence, "**") {
+		t.Errorf("expected no Markdown in Confluence output, got:\n%s", confluence)
+	}
+
+	// Wiki markup in the recipe is escaped
+	recipe, _ = cooklang.ParseString("---\ntitle: Salt [to taste] *fine*\n---\nAdd @salt{}.\n")
+	confluence = renderRecipe(t, MarkdownRenderer{Flavor: MarkdownConfluence}, recipe)
+	if !strings.Contains(confluence, `h1. Salt \[to taste\] \*fine\*`) {
+		t.Errorf("expected the title escaped, got:\n%s", confluence)
+	}
+
+	if _, err := (MarkdownRenderer{Flavor: "wiki"}).RenderRecipe(recipe); err == nil {
+		t.Error("expected an error for an unknown flavor")
+	}
+}
+
+func TestParseMarkdownFlavor(t *testing.T) {
+	tests := map[string]MarkdownFlavor{"": MarkdownCommon, "markdown": MarkdownCommon, "Notion": MarkdownNotion, "confluence": MarkdownConfluence}
+	for input, expected := range tests {
+		if flavor, err := ParseMarkdownFlavor(input); err != nil || flavor != expected {
+			t.Errorf("ParseMarkdownFlavor(%q) = %q, %v; want %q", input, flavor, err, expected)
+		}
+	}
+	if _, err := ParseMarkdownFlavor("wiki"); err == nil {
+		t.Error("expected an error for an unknown flavor")
+	}
+}
@@ -0,0 +1,166 @@
+package renderers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hilli/cooklang"
+)
+
+// MermaidRenderer renders a recipe as a Mermaid flowchart for embedding in
+// Markdown docs: the steps in order, each ingredient with its amount feeding into
+// the step that adds it, and cookware as nodes linked to every step that uses it.
+// References to ingredients added earlier (@&flour{}) link the ingredient to the
+// later step as well. Sections become subgraphs; notes are left out.
+//
+// Example:
+//
+//	recipe, _ := cooklang.ParseString("Whisk @flour{500%g} in a #bowl{}.\n\nBake for ~{30%minutes}.")
+//	fmt.Print(renderers.MermaidRenderer{}.RenderDiagram(recipe))
+//	// flowchart TD
+//	//     step1["1. Whisk flour in a bowl."]
+//	//     ingredient1(["500 g flour"]) --> step1
+//	//     cookware1{{"bowl"}} -.- step1
+//	//     step2["2. Bake for 30 minutes."]
+//	//     step1 --> step2
+type MermaidRenderer struct {
+	Options   RendererOptions // Units and quantity format; the zero value keeps them as written
+	Direction string          // Flowchart direction: "TD" (top down, the default) or "LR" (left to right)
+}
+
+// mermaidStepLength is the longest step text shown in a node, in characters.
+const mermaidStepLength = 80
+
+// Render renders the recipe as a fenced Mermaid flowchart with the given options.
+// Of the options, only Units, PreferredUnits, Quantities and the temperature scales
+// apply.
+func (mr MermaidRenderer) Render(recipe *cooklang.Recipe, opts RendererOptions) (string, error) {
+	mr.Options = opts
+	return mr.RenderRecipe(recipe)
+}
+
+// RenderRecipe renders the recipe as a Mermaid flowchart in a ```mermaid code
+// block, ready to paste into Markdown.
+//
+// Parameters:
+//   - recipe: The parsed Cooklang recipe to render
+//
+// Returns:
+//   - string: The fenced flowchart
+//   - error: Always nil; for the Renderer and cooklang.RecipeRenderer interfaces
+func (mr MermaidRenderer) RenderRecipe(recipe *cooklang.Recipe) (string, error) {
+	return "```mermaid\n" + mr.RenderDiagram(recipe) + "```\n", nil
+}
+
+// RenderDiagram renders the recipe as a Mermaid flowchart without the code fence,
+// e.g. for a <pre class="mermaid"> element rendered by mermaid.js.
+//
+// Parameters:
+//   - recipe: The parsed Cooklang recipe to render
+//
+// Returns:
+//   - string: The flowchart definition
+func (mr MermaidRenderer) RenderDiagram(recipe *cooklang.Recipe) string {
+	recipe = mr.Options.prepare(recipe)
+	direction := strings.ToUpper(mr.Direction)
+	if direction != "LR" {
+		direction = "TD"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "flowchart %s\n", direction)
+
+	cookware := make(map[string]string)    // Cookware name -> node ID
+	ingredients := make(map[string]string) // Lower-case ingredient name -> node ID of its first use
+	ingredientNodes, sections, steps := 0, 0, 0
+	indent := "    "
+	section := ""
+	for step := range recipe.Steps() {
+		if step.IsNote() {
+			continue
+		}
+
+		var text strings.Builder
+		var lines []string
+		linked := make(map[string]bool)
+		stepID := fmt.Sprintf("step%d", steps+1)
+		for component := range step.Components() {
+			switch comp := component.(type) {
+			case *cooklang.Section:
+				if section != "" {
+					b.WriteString("    end\n")
+				}
+				section = comp.Name
+				sections++
+				indent = "        "
+				fmt.Fprintf(&b, "    subgraph section%d [%s]\n", sections, mermaidLabel(section))
+			case *cooklang.Instruction:
+				text.WriteString(comp.Text)
+			case *cooklang.Temperature:
+				text.WriteString(mr.Options.temperature(comp))
+			case *cooklang.Timer:
+				text.WriteString(comp.RenderDisplay())
+			case *cooklang.Cookware:
+				text.WriteString(comp.Name)
+				id, seen := cookware[comp.Name]
+				switch {
+				case !seen:
+					id = fmt.Sprintf("cookware%d", len(cookware)+1)
+					cookware[comp.Name] = id
+					lines = append(lines, fmt.Sprintf("%s{{%s}} -.- %s", id, mermaidLabel(comp.Name), stepID))
+				case !linked[id]:
+					lines = append(lines, fmt.Sprintf("%s -.- %s", id, stepID))
+				}
+				linked[id] = true // One link per step, however often the step uses it
+			case *cooklang.Ingredient:
+				text.WriteString(comp.Name)
+				key := strings.ToLower(comp.Name)
+				if id, seen := ingredients[key]; seen && comp.IsReference() {
+					lines = append(lines, fmt.Sprintf("%s -.-> %s", id, stepID))
+					continue
+				}
+				// An ingredient added again gets a node of its own, with its own amount
+				ingredientNodes++
+				id := fmt.Sprintf("ingredient%d", ingredientNodes)
+				if _, seen := ingredients[key]; !seen {
+					ingredients[key] = id
+				}
+				label := comp.Name
+				if comp.Quantity.HasAmount() {
+					label = strings.Join(strings.Fields(mr.Options.amount(comp)+" "+comp.Unit+" "+comp.Name), " ")
+				}
+				lines = append(lines, fmt.Sprintf("%s([%s]) --> %s", id, mermaidLabel(label), stepID))
+			}
+		}
+
+		summary := strings.Join(strings.Fields(text.String()), " ")
+		if summary == "" {
+			continue
+		}
+		steps++
+		if runes := []rune(summary); len(runes) > mermaidStepLength {
+			summary = string(runes[:mermaidStepLength-1])
+			if space := strings.LastIndex(summary, " "); space > 0 {
+				summary = summary[:space] // Shorten at a word boundary
+			}
+			summary += "…"
+		}
+		fmt.Fprintf(&b, "%s%s[%s]\n", indent, stepID, mermaidLabel(fmt.Sprintf("%d. %s", steps, summary)))
+		for _, line := range lines {
+			b.WriteString(indent + line + "\n")
+		}
+		if steps > 1 {
+			fmt.Fprintf(&b, "%sstep%d --> %s\n", indent, steps-1, stepID)
+		}
+	}
+	if section != "" {
+		b.WriteString("    end\n")
+	}
+	return b.String()
+}
+
+// mermaidLabel quotes a node label, writing double quotes as the #quot; entity,
+// which Mermaid labels cannot otherwise contain.
+func mermaidLabel(text string) string {
+	return `"` + strings.ReplaceAll(text, `"`, "#quot;") + `"`
+}
@@ -0,0 +1,73 @@
+package renderers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hilli/cooklang"
+)
+
+func TestMermaidRenderer(t *testing.T) {
+	recipe, err := cooklang.ParseString(`---
+title: Pancakes
+---
+
+== Batter ==
+Whisk @flour{250%g} and @milk{500%ml} in a #bowl{}.
+
+> Let the batter rest if you have time.
+
+Add @flour{2%tbsp} if the batter is thin.
+
+== Cooking ==
+Heat a #pan{} to 200°C and fry the @&flour{} mixture in the #pan{} for ~{2%minutes}.
+
+Serve with @maple syrup{}.
+`)
+	if err != nil {
+		t.Fatalf("Failed to parse recipe: %v", err)
+	}
+
+	expected := "```mermaid\n" + `flowchart TD
+    subgraph section1 ["Batter"]
+        step1["1. Whisk flour and milk in a bowl."]
+        ingredient1(["250 g flour"]) --> step1
+        ingredient2(["500 ml milk"]) --> step1
+        cookware1{{"bowl"}} -.- step1
+        step2["2. Add flour if the batter is thin."]
+        ingredient3(["2 tbsp flour"]) --> step2
+        step1 --> step2
+    end
+    subgraph section2 ["Cooking"]
+        step3["3. Heat a pan to 200°C and fry the flour mixture in the pan for 2 minutes."]
+        cookware2{{"pan"}} -.- step3
+        ingredient1 -.-> step3
+        step2 --> step3
+        step4["4. Serve with maple syrup."]
+        ingredient4(["maple syrup"]) --> step4
+        step3 --> step4
+    end
+` + "```\n"
+	if output := renderRecipe(t, MermaidRenderer{}, recipe); output != expected {
+		t.Errorf("unexpected flowchart:\n%s\nwant:\n%s", output, expected)
+	}
+
+	// Direction, quoting and long steps
+	recipe, _ = cooklang.ParseString(`Cut the @tortillas{2} into "wedges" and bake them until they are crisp and golden all over, turning them once.`)
+	output, err := MermaidRenderer{Direction: "lr"}.Render(recipe, RendererOptions{})
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if !strings.HasPrefix(output, "```mermaid\nflowchart LR\n") {
+		t.Errorf("expected a left-to-right flowchart, got:\n%s", output)
+	}
+	if !strings.Contains(output, `step1["1. Cut the tortillas into #quot;wedges#quot; and bake`) {
+		t.Errorf("expected quotes as #quot;, got:\n%s", output)
+	}
+	if !strings.Contains(output, ` crisp and golden…"]`) {
+		t.Errorf("expected the step text to be shortened, got:\n%s", output)
+	}
+	if !strings.Contains(output, `ingredient1(["2 tortillas"]) --> step1`) {
+		t.Errorf("expected a unitless amount, got:\n%s", output)
+	}
+}
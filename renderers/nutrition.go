@@ -0,0 +1,310 @@
+package renderers
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/hilli/cooklang"
+)
+
+// ErrNoNutrition is returned by NutritionLabelRenderer for recipes without
+// nutrition data.
+var ErrNoNutrition = errors.New("recipe has no nutrition data")
+
+// NutritionFacts are the nutrients in one serving of a recipe, in the units of a
+// US nutrition facts label. Nutrients that are zero are left off the label.
+type NutritionFacts struct {
+	ServingSize   string  `json:"serving_size,omitempty"` // e.g. "1 slice (120g)"
+	Servings      float64 `json:"servings,omitempty"`     // Servings in the recipe
+	Calories      float64 `json:"calories"`               // kcal
+	Fat           float64 `json:"fat,omitempty"`          // g
+	SaturatedFat  float64 `json:"saturated_fat,omitempty"`
+	TransFat      float64 `json:"trans_fat,omitempty"`
+	Cholesterol   float64 `json:"cholesterol,omitempty"` // mg
+	Sodium        float64 `json:"sodium,omitempty"`      // mg
+	Carbohydrates float64 `json:"carbohydrates,omitempty"`
+	Fiber         float64 `json:"fiber,omitempty"`
+	Sugar         float64 `json:"sugar,omitempty"`
+	Protein       float64 `json:"protein,omitempty"`
+}
+
+// NutritionRow is a line of a nutrition facts label.
+type NutritionRow struct {
+	Name       string `json:"name"`                  // e.g. "Saturated Fat"
+	Amount     string `json:"amount"`                // e.g. "2.5g"
+	DailyValue int    `json:"daily_value,omitempty"` // Percent of the daily value; 0 if it has none
+	Sub        bool   `json:"sub,omitempty"`         // Part of the row above, e.g. fiber of carbohydrates
+}
+
+// nutrient describes a row of the label: its metadata key, the unit the facts
+// keep it in, and its FDA daily value (0 for none).
+type nutrient struct {
+	key        string
+	name       string
+	unit       string
+	dailyValue float64
+	sub        bool
+	value      func(*NutritionFacts) *float64
+}
+
+// nutrients are the rows of the label in order, with the FDA daily values for
+// adults and children over 4.
+var nutrients = []nutrient{
+	{"fat", "Total Fat", "g", 78, false, func(f *NutritionFacts) *float64 { return &f.Fat }},
+	{"saturated_fat", "Saturated Fat", "g", 20, true, func(f *NutritionFacts) *float64 { return &f.SaturatedFat }},
+	{"trans_fat", "Trans Fat", "g", 0, true, func(f *NutritionFacts) *float64 { return &f.TransFat }},
+	{"cholesterol", "Cholesterol", "mg", 300, false, func(f *NutritionFacts) *float64 { return &f.Cholesterol }},
+	{"sodium", "Sodium", "mg", 2300, false, func(f *NutritionFacts) *float64 { return &f.Sodium }},
+	{"carbohydrates", "Total Carbohydrate", "g", 275, false, func(f *NutritionFacts) *float64 { return &f.Carbohydrates }},
+	{"fiber", "Dietary Fiber", "g", 28, true, func(f *NutritionFacts) *float64 { return &f.Fiber }},
+	{"sugar", "Total Sugars", "g", 0, true, func(f *NutritionFacts) *float64 { return &f.Sugar }},
+	{"protein", "Protein", "g", 0, false, func(f *NutritionFacts) *float64 { return &f.Protein }},
+}
+
+// NutritionFromRecipe reads the nutrition facts per serving from a recipe's
+// metadata: "calories", "fat", "saturated_fat", "trans_fat", "cholesterol",
+// "sodium", "carbohydrates", "fiber", "sugar", "protein" and "serving_size", as
+// nested under "nutrition:" or at the top level. Amounts may have a unit ("32g",
+// "400 mg", "650 kcal", "2700 kJ"); without one they are taken in the label's unit.
+//
+// Parameters:
+//   - recipe: The recipe
+//
+// Returns:
+//   - *NutritionFacts: The facts per serving
+//   - bool: False if the recipe has no nutrition metadata that can be read
+//
+// Example:
+//
+//	if facts, ok := renderers.NutritionFromRecipe(recipe); ok {
+//	    fmt.Printf("%.0f kcal per serving\n", facts.Calories)
+//	}
+func NutritionFromRecipe(recipe *cooklang.Recipe) (*NutritionFacts, bool) {
+	facts := &NutritionFacts{ServingSize: recipe.Metadata["serving_size"]}
+	if recipe.Metadata["servings"] != "" {
+		facts.Servings = float64(recipe.Servings)
+	}
+	found := false
+	if value, ok := parseNutrientAmount(recipe.Metadata["calories"], "kcal"); ok {
+		facts.Calories, found = value, true
+	}
+	for _, n := range nutrients {
+		if value, ok := parseNutrientAmount(recipe.Metadata[n.key], n.unit); ok {
+			*n.value(facts) = value
+			found = true
+		}
+	}
+	if !found {
+		return nil, false
+	}
+	return facts, true
+}
+
+// parseNutrientAmount reads an amount such as "32g" or "650 kcal" in unit ("g",
+// "mg" or "kcal").
+func parseNutrientAmount(text, unit string) (float64, bool) {
+	text = strings.TrimSpace(text)
+	end := strings.IndexFunc(text, func(r rune) bool { return (r < '0' || r > '9') && r != '.' })
+	if end < 0 {
+		end = len(text)
+	}
+	value, err := strconv.ParseFloat(text[:end], 64)
+	if err != nil || value < 0 {
+		return 0, false
+	}
+	switch from := strings.ToLower(strings.TrimSpace(text[end:])); {
+	case from == "" || from == unit:
+	case unit == "kcal" && (from == "cal" || from == "calories" || from == "kcals"):
+	case unit == "kcal" && from == "kj":
+		value /= 4.184
+	case unit == "g" && from == "mg":
+		value /= 1000
+	case unit == "mg" && from == "g":
+		value *= 1000
+	case (from == "mcg" || from == "µg") && unit == "mg":
+		value /= 1000
+	default:
+		return 0, false
+	}
+	return value, true
+}
+
+// Rows returns the lines of the label below the calories, leaving out nutrients
+// that are zero. Amounts are rounded the way labels round them.
+//
+// Returns:
+//   - []NutritionRow: The rows in label order
+func (f NutritionFacts) Rows() []NutritionRow {
+	var rows []NutritionRow
+	for _, n := range nutrients {
+		value := *n.value(&f)
+		if value <= 0 {
+			continue
+		}
+		row := NutritionRow{Name: n.name, Amount: formatNutrient(value) + n.unit, Sub: n.sub}
+		if n.dailyValue > 0 {
+			row.DailyValue = int(math.Round(value / n.dailyValue * 100))
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// formatNutrient rounds an amount to whole numbers, or to halves below 5.
+func formatNutrient(value float64) string {
+	if value < 5 {
+		value = math.Round(value*2) / 2
+	} else {
+		value = math.Round(value)
+	}
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// NutritionLabelRenderer renders a recipe's nutrition facts per serving as a panel
+// in the style of a US nutrition facts label: an HTML fragment with its own styles,
+// or an SVG image. The HTML and Print renderers embed the HTML panel with
+// RendererOptions.NutritionLabel.
+//
+// Example usage:
+//
+//	recipe, _ := cooklang.ParseFile("lasagna.cook") // with nutrition metadata
+//	panel, err := renderers.NutritionLabelRenderer{}.Render(recipe, renderers.RendererOptions{})
+//	svg, err := renderers.NutritionLabelRenderer{SVG: true}.Render(recipe, renderers.RendererOptions{})
+type NutritionLabelRenderer struct {
+	Facts *NutritionFacts // Facts to show instead of the recipe's nutrition metadata
+	SVG   bool            // Render an SVG image instead of HTML
+}
+
+// Render renders the nutrition facts panel. Recipes without nutrition data, and
+// options with NoNutrition, return ErrNoNutrition.
+func (nr NutritionLabelRenderer) Render(recipe *cooklang.Recipe, opts RendererOptions) (string, error) {
+	facts := nr.Facts
+	if facts == nil {
+		var ok bool
+		if facts, ok = NutritionFromRecipe(recipe); !ok {
+			return "", ErrNoNutrition
+		}
+	}
+	if opts.NoNutrition {
+		return "", ErrNoNutrition
+	}
+	if nr.SVG {
+		return nutritionSVG(facts), nil
+	}
+	return nutritionHTML(facts), nil
+}
+
+// nutritionLabelCSS styles the HTML panel.
+const nutritionLabelCSS = `.nutrition-facts{border:1px solid #000;padding:4px 6px;width:15em;font:0.85em Helvetica,Arial,sans-serif;color:#000;background:#fff}
+.nutrition-facts h2{margin:0;font-size:2em;font-weight:900;line-height:1}
+.nutrition-facts p{margin:0}
+.nutrition-facts .serving{border-bottom:8px solid #000;padding-bottom:2px}
+.nutrition-facts .calories{display:flex;justify-content:space-between;font-weight:900;font-size:1.6em;border-bottom:4px solid #000}
+.nutrition-facts table{width:100%;border-collapse:collapse}
+.nutrition-facts td{border-top:1px solid #000;padding:1px 0}
+.nutrition-facts td.dv{text-align:right;font-weight:700}
+.nutrition-facts tr.sub td:first-child{padding-left:1em}
+.nutrition-facts .footnote{border-top:4px solid #000;font-size:0.75em;padding-top:2px}`
+
+// nutritionHTML renders the HTML panel.
+func nutritionHTML(facts *NutritionFacts) string {
+	var result strings.Builder
+	result.WriteString("<section class=\"nutrition-facts\">\n")
+	fmt.Fprintf(&result, "  <style>%s</style>\n", nutritionLabelCSS)
+	result.WriteString("  <h2>Nutrition Facts</h2>\n")
+	result.WriteString("  <div class=\"serving\">\n")
+	if facts.Servings > 0 {
+		fmt.Fprintf(&result, "    <p>%s</p>\n", html.EscapeString(servingsLine(facts.Servings)))
+	}
+	if facts.ServingSize != "" {
+		fmt.Fprintf(&result, "    <p><strong>Serving size</strong> %s</p>\n", html.EscapeString(facts.ServingSize))
+	}
+	result.WriteString("  </div>\n")
+	fmt.Fprintf(&result, "  <p class=\"calories\"><span>Calories</span><span>%.0f</span></p>\n", facts.Calories)
+	result.WriteString("  <table>\n")
+	result.WriteString("    <tr><td></td><td class=\"dv\">% Daily Value*</td></tr>\n")
+	for _, row := range facts.Rows() {
+		class, name := "", "<strong>"+html.EscapeString(row.Name)+"</strong>"
+		if row.Sub {
+			class, name = " class=\"sub\"", html.EscapeString(row.Name)
+		}
+		dailyValue := ""
+		if row.DailyValue > 0 {
+			dailyValue = fmt.Sprintf("%d%%", row.DailyValue)
+		}
+		fmt.Fprintf(&result, "    <tr%s><td>%s %s</td><td class=\"dv\">%s</td></tr>\n", class, name, html.EscapeString(row.Amount), dailyValue)
+	}
+	result.WriteString("  </table>\n")
+	result.WriteString("  <p class=\"footnote\">* The % Daily Value tells you how much a nutrient in a serving of food contributes to a daily diet. 2,000 calories a day is used for general nutrition advice.</p>\n")
+	result.WriteString("</section>\n")
+	return result.String()
+}
+
+// nutritionSVG renders the panel as an SVG image, 240 pixels wide.
+func nutritionSVG(facts *NutritionFacts) string {
+	const width, left, right = 240, 8, 232
+	var body strings.Builder
+	y := 30
+	text := func(x int, anchor, weight string, size int, s string) {
+		fmt.Fprintf(&body, "  <text x=\"%d\" y=\"%d\" text-anchor=\"%s\" font-weight=\"%s\" font-size=\"%d\">%s</text>\n", x, y, anchor, weight, size, html.EscapeString(s))
+	}
+	rule := func(thickness int) {
+		fmt.Fprintf(&body, "  <rect x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\"/>\n", left, y, right-left, thickness)
+	}
+
+	text(left, "start", "900", 26, "Nutrition Facts")
+	if facts.Servings > 0 {
+		y += 18
+		text(left, "start", "normal", 12, servingsLine(facts.Servings))
+	}
+	if facts.ServingSize != "" {
+		y += 16
+		text(left, "start", "bold", 12, "Serving size "+facts.ServingSize)
+	}
+	y += 6
+	rule(8)
+	y += 30
+	text(left, "start", "900", 20, "Calories")
+	text(right, "end", "900", 20, fmt.Sprintf("%.0f", facts.Calories))
+	y += 5
+	rule(4)
+	y += 15
+	text(right, "end", "bold", 10, "% Daily Value*")
+	for _, row := range facts.Rows() {
+		y += 3
+		rule(1)
+		y += 14
+		x, weight := left, "bold"
+		if row.Sub {
+			x, weight = left+12, "normal"
+		}
+		text(x, "start", weight, 12, row.Name+" "+row.Amount)
+		if row.DailyValue > 0 {
+			text(right, "end", "bold", 12, fmt.Sprintf("%d%%", row.DailyValue))
+		}
+	}
+	y += 5
+	rule(4)
+	y += 14
+	text(left, "start", "normal", 9, "* Percent Daily Values are based on a 2,000 calorie diet.")
+	y += 10
+
+	var result strings.Builder
+	fmt.Fprintf(&result, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" viewBox=\"0 0 %d %d\" font-family=\"Helvetica, Arial, sans-serif\">\n", width, y, width, y)
+	fmt.Fprintf(&result, "  <rect x=\"1\" y=\"1\" width=\"%d\" height=\"%d\" fill=\"#fff\" stroke=\"#000\"/>\n", width-2, y-2)
+	result.WriteString(body.String())
+	result.WriteString("</svg>\n")
+	return result.String()
+}
+
+// servingsLine writes "4 servings per recipe".
+func servingsLine(servings float64) string {
+	if servings == 1 {
+		return "1 serving per recipe"
+	}
+	return strconv.FormatFloat(servings, 'f', -1, 64) + " servings per recipe"
+}
@@ -0,0 +1,133 @@
+package renderers
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hilli/cooklang"
+)
+
+const nutritionRecipe = `---
+title: Lasagna
+servings: 4
+nutrition:
+  calories: 650 kcal
+  fat: 32g
+  saturated_fat: 14g
+  sodium: 0.9g
+  carbohydrates: 55
+  fiber: 4.2g
+  protein: 28g
+---
+Layer @pasta{12%sheets} with @ragu{1%l}.
+`
+
+func TestNutritionFromRecipe(t *testing.T) {
+	recipe, err := cooklang.ParseString(nutritionRecipe)
+	if err != nil {
+		t.Fatal(err)
+	}
+	facts, ok := NutritionFromRecipe(recipe)
+	if !ok {
+		t.Fatal("expected nutrition facts")
+	}
+	if facts.Servings != 4 || facts.Calories != 650 || facts.Sodium != 900 || facts.Carbohydrates != 55 {
+		t.Errorf("unexpected facts: %+v", facts)
+	}
+
+	var rows []string
+	for _, row := range facts.Rows() {
+		rows = append(rows, fmt.Sprintf("%s %s %d%% %v", row.Name, row.Amount, row.DailyValue, row.Sub))
+	}
+	want := "Total Fat 32g 41% false,Saturated Fat 14g 70% true,Sodium 900mg 39% false,Total Carbohydrate 55g 20% false,Dietary Fiber 4g 15% true,Protein 28g 0% false"
+	if got := strings.Join(rows, ","); got != want {
+		t.Errorf("rows = %s\nwant   %s", got, want)
+	}
+
+	plain, _ := cooklang.ParseString("Boil @water{1%l}.\n")
+	if _, ok := NutritionFromRecipe(plain); ok {
+		t.Error("expected no nutrition facts without metadata")
+	}
+}
+
+func TestParseNutrientAmount(t *testing.T) {
+	tests := []struct {
+		text, unit string
+		want       float64
+		ok         bool
+	}{
+		{"32g", "g", 32, true},
+		{"400 mg", "g", 0.4, true},
+		{"1.2 g", "mg", 1200, true},
+		{"650", "kcal", 650, true},
+		{"650 Calories", "kcal", 650, true},
+		{"4184 kJ", "kcal", 1000, true},
+		{"lots", "g", 0, false},
+		{"12 cups", "g", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseNutrientAmount(tt.text, tt.unit)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("parseNutrientAmount(%q, %q) = %v, %v, want %v, %v", tt.text, tt.unit, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestNutritionLabelRenderer(t *testing.T) {
+	recipe, err := cooklang.ParseString(nutritionRecipe)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	panel, err := NutritionLabelRenderer{}.Render(recipe, RendererOptions{})
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	for _, want := range []string{`<section class="nutrition-facts">`, "4 servings per recipe", "<span>650</span>", `<tr class="sub"><td>Saturated Fat 14g</td><td class="dv">70%</td></tr>`} {
+		if !strings.Contains(panel, want) {
+			t.Errorf("expected %q in panel:\n%s", want, panel)
+		}
+	}
+
+	svg, err := NutritionLabelRenderer{SVG: true}.Render(recipe, RendererOptions{})
+	if err != nil {
+		t.Fatalf("Render SVG failed: %v", err)
+	}
+	if !strings.HasPrefix(svg, "<svg ") || !strings.Contains(svg, ">Total Fat 32g</text>") {
+		t.Errorf("unexpected SVG:\n%s", svg)
+	}
+
+	if _, err := (NutritionLabelRenderer{}).Render(recipe, RendererOptions{NoNutrition: true}); !errors.Is(err, ErrNoNutrition) {
+		t.Errorf("expected ErrNoNutrition with NoNutrition, got %v", err)
+	}
+	plain, _ := cooklang.ParseString("Boil @water{1%l}.\n")
+	if _, err := (NutritionLabelRenderer{}).Render(plain, RendererOptions{}); !errors.Is(err, ErrNoNutrition) {
+		t.Errorf("expected ErrNoNutrition without metadata, got %v", err)
+	}
+	panel, err = NutritionLabelRenderer{Facts: &NutritionFacts{Calories: 120, Protein: 3}}.Render(plain, RendererOptions{})
+	if err != nil || !strings.Contains(panel, "<strong>Protein</strong> 3g") {
+		t.Errorf("expected a panel from the given facts, got %v:\n%s", err, panel)
+	}
+}
+
+func TestNutritionLabelEmbedded(t *testing.T) {
+	recipe, err := cooklang.ParseString(nutritionRecipe)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, renderer := range map[string]Renderer{"html": HTMLRenderer{}, "print": PrintRenderer{}} {
+		with, err := renderer.Render(recipe, RendererOptions{NutritionLabel: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(with, `<section class="nutrition-facts">`) {
+			t.Errorf("%s: expected the nutrition panel", name)
+		}
+		without, _ := renderer.Render(recipe, RendererOptions{})
+		if strings.Contains(without, "nutrition-facts") {
+			t.Errorf("%s: expected no nutrition panel by default", name)
+		}
+	}
+}
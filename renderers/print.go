@@ -3,6 +3,7 @@ package renderers
 import (
 	"fmt"
 	"html"
+	"html/template"
 	"strings"
 
 	"github.com/hilli/cooklang"
@@ -10,7 +11,12 @@ import (
 
 // PrintRenderer renders recipes as print-optimized HTML designed to fit on a single page.
 // It includes embedded CSS for clean printing without browser chrome or interactive elements.
-type PrintRenderer struct{}
+type PrintRenderer struct {
+	Options RendererOptions // Locale and labels; the zero value renders English
+	// Template renders the recipe with the set's PrintTemplateName template instead
+	// of the built-in page; see ParseTemplates and TemplateData.
+	Template *template.Template
+}
 
 // printCSS contains embedded CSS optimized for single-page recipe printing
 const printCSS = `
@@ -128,6 +134,13 @@ const printCSS = `
     border-bottom: none;
   }
 
+  .ingredient-group {
+    font-size: 10pt;
+    font-weight: bold;
+    margin: 0.6em 0 0.2em;
+    color: #555;
+  }
+
   .ingredient-qty {
     font-weight: bold;
     display: inline-block;
@@ -168,6 +181,16 @@ const printCSS = `
     line-height: 1.5em;
   }
 
+  .instructions-list li.note {
+    font-style: italic;
+    color: #555;
+  }
+
+  .instructions-list li.note::before {
+    content: none;
+    counter-increment: none;
+  }
+
   .ing {
     font-weight: bold;
   }
@@ -180,7 +203,7 @@ const printCSS = `
     font-style: italic;
   }
 
-  .tmr {
+  .tmr, .temp {
     background: #f5f5f5;
     padding: 0.1em 0.3em;
     border-radius: 3px;
@@ -193,12 +216,32 @@ const printCSS = `
     color: #666;
   }
 
+  .quantity-note {
+    font-size: 9pt;
+    color: #666;
+  }
+
   .optional-marker {
     font-size: 9pt;
     color: #888;
     font-style: italic;
   }
 
+  .reserved {
+    display: block;
+    font-size: 9pt;
+    border-left: 2px solid #999;
+    padding-left: 0.4em;
+  }
+
+  .step-image {
+    display: block;
+    max-width: 60%;
+    max-height: 4cm;
+    margin: 0.3em 0;
+    border-radius: 3px;
+  }
+
   .recipe-footer {
     margin-top: 1em;
     padding-top: 0.5em;
@@ -213,6 +256,22 @@ const printCSS = `
     font-style: italic;
   }
 
+  .recipe-qr {
+    display: flex;
+    align-items: center;
+    gap: 0.5em;
+    margin-top: 0.5em;
+    font-size: 8pt;
+    color: #888;
+    word-break: break-all;
+  }
+
+  .recipe-qr svg {
+    width: 2.5cm;
+    height: 2.5cm;
+    flex-shrink: 0;
+  }
+
   /* Print-specific adjustments */
   @media print {
     body {
@@ -236,32 +295,68 @@ const printCSS = `
 </style>
 `
 
-func (pr PrintRenderer) RenderRecipe(recipe *cooklang.Recipe) string {
+// Render renders the recipe as a print-optimized HTML document with the given
+// options, or with Template if it is set.
+func (pr PrintRenderer) Render(recipe *cooklang.Recipe, opts RendererOptions) (string, error) {
+	pr.Options = opts
+	return pr.RenderRecipe(recipe)
+}
+
+// RenderRecipe renders the recipe as a print-optimized HTML document with the
+// renderer's Options, or with Template if it is set. A URL too long for a QR code
+// returns an error wrapping ErrEncoding.
+func (pr PrintRenderer) RenderRecipe(recipe *cooklang.Recipe) (string, error) {
+	var qr *qrCode
+	if pr.Options.URL != "" {
+		var err error
+		if qr, err = encodeQR(pr.Options.URL); err != nil {
+			return "", fmt.Errorf("%w: QR code: %w", ErrEncoding, err)
+		}
+	}
+	if pr.Template != nil {
+		return executeTemplate(pr.Template, PrintTemplateName, pr.Options.templateData(recipe))
+	}
 	var result strings.Builder
+	labels := pr.Options.labels()
+	recipe = pr.Options.prepare(recipe)
 
 	// HTML document structure
 	result.WriteString("<!DOCTYPE html>\n")
-	result.WriteString("<html lang=\"en\">\n")
+	fmt.Fprintf(&result, "<html lang=\"%s\">\n", html.EscapeString(pr.Options.Language()))
 	result.WriteString("<head>\n")
 	result.WriteString("  <meta charset=\"UTF-8\">\n")
 	result.WriteString("  <meta name=\"viewport\" content=\"width=device-width, initial-scale=1.0\">\n")
 	if recipe.Title != "" {
 		result.WriteString(fmt.Sprintf("  <title>%s</title>\n", html.EscapeString(recipe.Title)))
 	} else {
-		result.WriteString("  <title>Recipe</title>\n")
+		fmt.Fprintf(&result, "  <title>%s</title>\n", html.EscapeString(labels.Recipe))
 	}
 	result.WriteString(printCSS)
 	result.WriteString("</head>\n")
 	result.WriteString("<body>\n")
-	result.WriteString("<div class=\"recipe-print\">\n")
+	pr.writeRecipe(&result, recipe, labels, qr, "")
+	result.WriteString("</body>\n")
+	result.WriteString("</html>\n")
+
+	return result.String(), nil
+}
+
+// writeRecipe writes the page of a recipe, with the id attribute of its booklet
+// anchor if id is set, and the QR code if qr is set.
+func (pr PrintRenderer) writeRecipe(result *strings.Builder, recipe *cooklang.Recipe, labels Strings, qr *qrCode, id string) {
+	if id != "" {
+		fmt.Fprintf(result, "<div class=\"recipe-print\" id=\"%s\">\n", html.EscapeString(id))
+	} else {
+		result.WriteString("<div class=\"recipe-print\">\n")
+	}
 
 	// Header section
 	result.WriteString("  <div class=\"recipe-header\">\n")
 
 	// Add image if available (use first image)
-	if len(recipe.Images) > 0 {
+	if images := pr.Options.images(recipe); len(images) > 0 {
 		result.WriteString(fmt.Sprintf("    <img class=\"recipe-image\" src=\"%s\" alt=\"%s\">\n",
-			html.EscapeString(recipe.Images[0]),
+			html.EscapeString(images[0]),
 			html.EscapeString(recipe.Title)))
 	}
 
@@ -276,22 +371,25 @@ func (pr PrintRenderer) RenderRecipe(recipe *cooklang.Recipe) string {
 	// Metadata line
 	var metaItems []string
 	if recipe.Servings > 0 {
-		metaItems = append(metaItems, fmt.Sprintf("<span class=\"recipe-meta-item\"><span class=\"recipe-meta-label\">Servings:</span> %g</span>", recipe.Servings))
+		metaItems = append(metaItems, fmt.Sprintf("<span class=\"recipe-meta-item\"><span class=\"recipe-meta-label\">%s:</span> %g</span>", html.EscapeString(labels.Servings), recipe.Servings))
 	}
 	if recipe.PrepTime != "" {
-		metaItems = append(metaItems, fmt.Sprintf("<span class=\"recipe-meta-item\"><span class=\"recipe-meta-label\">Prep:</span> %s</span>", html.EscapeString(recipe.PrepTime)))
+		metaItems = append(metaItems, fmt.Sprintf("<span class=\"recipe-meta-item\"><span class=\"recipe-meta-label\">%s:</span> %s</span>", html.EscapeString(labels.Prep), html.EscapeString(recipe.PrepTime)))
 	}
 	if recipe.TotalTime != "" {
-		metaItems = append(metaItems, fmt.Sprintf("<span class=\"recipe-meta-item\"><span class=\"recipe-meta-label\">Total:</span> %s</span>", html.EscapeString(recipe.TotalTime)))
+		metaItems = append(metaItems, fmt.Sprintf("<span class=\"recipe-meta-item\"><span class=\"recipe-meta-label\">%s:</span> %s</span>", html.EscapeString(labels.Total), html.EscapeString(recipe.TotalTime)))
+	}
+	if oven, ok := recipe.OvenTemperature(); ok {
+		metaItems = append(metaItems, fmt.Sprintf("<span class=\"recipe-meta-item\"><span class=\"recipe-meta-label\">%s:</span> %s</span>", html.EscapeString(labels.Oven), html.EscapeString(pr.Options.temperature(oven))))
 	}
 	if recipe.Difficulty != "" {
-		metaItems = append(metaItems, fmt.Sprintf("<span class=\"recipe-meta-item\"><span class=\"recipe-meta-label\">Difficulty:</span> %s</span>", html.EscapeString(recipe.Difficulty)))
+		metaItems = append(metaItems, fmt.Sprintf("<span class=\"recipe-meta-item\"><span class=\"recipe-meta-label\">%s:</span> %s</span>", html.EscapeString(labels.Difficulty), html.EscapeString(labels.difficulty(recipe.Difficulty))))
 	}
 	if recipe.Cuisine != "" {
-		metaItems = append(metaItems, fmt.Sprintf("<span class=\"recipe-meta-item\"><span class=\"recipe-meta-label\">Cuisine:</span> %s</span>", html.EscapeString(recipe.Cuisine)))
+		metaItems = append(metaItems, fmt.Sprintf("<span class=\"recipe-meta-item\"><span class=\"recipe-meta-label\">%s:</span> %s</span>", html.EscapeString(labels.Cuisine), html.EscapeString(recipe.Cuisine)))
 	}
 	if recipe.Author != "" {
-		metaItems = append(metaItems, fmt.Sprintf("<span class=\"recipe-meta-item\"><span class=\"recipe-meta-label\">By:</span> %s</span>", html.EscapeString(recipe.Author)))
+		metaItems = append(metaItems, fmt.Sprintf("<span class=\"recipe-meta-item\"><span class=\"recipe-meta-label\">%s:</span> %s</span>", html.EscapeString(labels.By), html.EscapeString(recipe.Author)))
 	}
 
 	if len(metaItems) > 0 {
@@ -306,38 +404,57 @@ func (pr PrintRenderer) RenderRecipe(recipe *cooklang.Recipe) string {
 	result.WriteString("  <div class=\"recipe-body\">\n")
 
 	// Ingredients column
-	ingredients := recipe.GetIngredients()
 	result.WriteString("    <div class=\"recipe-ingredients\">\n")
-	result.WriteString("      <h2>Ingredients</h2>\n")
-	if len(ingredients.Ingredients) > 0 {
+	fmt.Fprintf(result, "      <h2>%s</h2>\n", html.EscapeString(labels.Ingredients))
+	for _, group := range pr.Options.ingredientGroups(recipe, labels, false) {
+		if group.Title != "" {
+			fmt.Fprintf(result, "      <h3 class=\"ingredient-group\">%s</h3>\n", html.EscapeString(group.Title))
+		}
 		result.WriteString("      <ul class=\"ingredients-list\">\n")
-		for _, ingredient := range ingredients.Ingredients {
+		for _, ingredient := range group.Ingredients {
 			optionalClass := ""
 			if ingredient.Optional {
 				optionalClass = " optional"
 			}
 			result.WriteString(fmt.Sprintf("        <li class=\"%s\">", optionalClass))
-			qtyStr := pr.formatQuantity(ingredient.Quantity, ingredient.Unit)
+			qtyStr := pr.formatQuantity(ingredient, labels)
 			if qtyStr != "" {
 				result.WriteString(fmt.Sprintf("<span class=\"ingredient-qty\">%s</span> ", qtyStr))
 			}
 			result.WriteString(fmt.Sprintf("<span class=\"ingredient-name\">%s</span>", html.EscapeString(ingredient.Name)))
+			if ingredient.Annotation != "" {
+				fmt.Fprintf(result, "<span class=\"annotation\">, %s</span>", html.EscapeString(ingredient.Annotation))
+			}
+			if note := pr.Options.quantityNote(recipe, ingredient, labels); note != "" {
+				fmt.Fprintf(result, " <span class=\"quantity-note\">(%s)</span>", html.EscapeString(note))
+			}
 			if ingredient.Optional {
-				result.WriteString(" <span class=\"optional-marker\">(optional)</span>")
+				fmt.Fprintf(result, " <span class=\"optional-marker\">(%s)</span>", html.EscapeString(labels.Optional))
 			}
 			result.WriteString("</li>\n")
 		}
 		result.WriteString("      </ul>\n")
 	}
+	if panel := pr.Options.nutritionLabel(recipe); panel != "" {
+		result.WriteString(panel)
+	}
+	result.WriteString(pr.Options.bakersTable(recipe, true))
 	result.WriteString("    </div>\n\n")
 
 	// Instructions column
 	result.WriteString("    <div class=\"recipe-instructions\">\n")
-	result.WriteString("      <h2>Instructions</h2>\n")
+	fmt.Fprintf(result, "      <h2>%s</h2>\n", html.EscapeString(labels.Instructions))
 	result.WriteString("      <ol class=\"instructions-list\">\n")
 
+	callouts := reservedCallouts(recipe, labels)
 	currentStep := recipe.FirstStep
 	for currentStep != nil {
+		if note, ok := currentStep.FirstComponent.(*cooklang.Note); ok {
+			// Notes are unnumbered asides between the steps
+			fmt.Fprintf(result, "        <li class=\"note\">%s</li>\n", html.EscapeString(note.Text))
+			currentStep = currentStep.NextStep
+			continue
+		}
 		result.WriteString("        <li>")
 		currentComponent := currentStep.FirstComponent
 		for currentComponent != nil {
@@ -346,28 +463,38 @@ func (pr PrintRenderer) RenderRecipe(recipe *cooklang.Recipe) string {
 				optionalClass := ""
 				if comp.Optional {
 					optionalClass = " optional"
+				} else if comp.Reference {
+					optionalClass = " reference"
 				}
 				result.WriteString(fmt.Sprintf("<span class=\"ing%s\">%s</span>", optionalClass, html.EscapeString(comp.Name)))
-				if comp.Quantity > 0 {
-					qtyStr := pr.formatQuantity(comp.Quantity, comp.Unit)
+				if comp.Quantity.HasAmount() {
+					qtyStr := pr.formatQuantity(comp, labels)
 					result.WriteString(fmt.Sprintf(" <span class=\"qty\">(%s)</span>", qtyStr))
 				}
 				if comp.Optional {
-					result.WriteString(" <span class=\"optional-marker\">(optional)</span>")
+					fmt.Fprintf(result, " <span class=\"optional-marker\">(%s)</span>", html.EscapeString(labels.Optional))
 				}
 			case *cooklang.Cookware:
 				result.WriteString(fmt.Sprintf("<span class=\"cw\">%s</span>", html.EscapeString(comp.Name)))
 			case *cooklang.Timer:
 				if comp.Name != "" {
-					result.WriteString(fmt.Sprintf("<span class=\"tmr\">%s: %s</span>", html.EscapeString(comp.Name), html.EscapeString(comp.Duration)))
+					result.WriteString(fmt.Sprintf("<span class=\"tmr\">%s: %s</span>", html.EscapeString(comp.Name), html.EscapeString(comp.String())))
 				} else {
-					result.WriteString(fmt.Sprintf("<span class=\"tmr\">%s</span>", html.EscapeString(comp.Duration)))
+					result.WriteString(fmt.Sprintf("<span class=\"tmr\">%s</span>", html.EscapeString(comp.String())))
 				}
 			case *cooklang.Instruction:
 				result.WriteString(html.EscapeString(comp.Text))
+			case *cooklang.Temperature:
+				fmt.Fprintf(result, "<span class=\"temp\">%s</span>", html.EscapeString(pr.Options.temperature(comp)))
 			}
 			currentComponent = currentComponent.GetNext()
 		}
+		for _, callout := range callouts[currentStep] {
+			result.WriteString(fmt.Sprintf(" <span class=\"reserved\">↩ %s</span>", html.EscapeString(callout)))
+		}
+		for _, src := range currentStep.Images {
+			fmt.Fprintf(result, "<img class=\"step-image\" src=\"%s\" alt=\"%s\">", html.EscapeString(src), html.EscapeString(recipe.Title))
+		}
 		result.WriteString("</li>\n")
 		currentStep = currentStep.NextStep
 	}
@@ -379,7 +506,7 @@ func (pr PrintRenderer) RenderRecipe(recipe *cooklang.Recipe) string {
 	// Footer with tags and date
 	var footerLeft, footerRight string
 	if len(recipe.Tags) > 0 {
-		footerLeft = fmt.Sprintf("<span class=\"recipe-tags\">Tags: %s</span>", html.EscapeString(strings.Join(recipe.Tags, ", ")))
+		footerLeft = fmt.Sprintf("<span class=\"recipe-tags\">%s: %s</span>", html.EscapeString(labels.Tags), html.EscapeString(strings.Join(recipe.Tags, ", ")))
 	}
 	if !recipe.Date.IsZero() {
 		footerRight = recipe.Date.Format("2006-01-02")
@@ -392,37 +519,40 @@ func (pr PrintRenderer) RenderRecipe(recipe *cooklang.Recipe) string {
 		result.WriteString("  </div>\n")
 	}
 
-	result.WriteString("</div>\n")
-	result.WriteString("</body>\n")
-	result.WriteString("</html>\n")
+	// QR code linking back to the recipe online
+	if qr != nil {
+		fmt.Fprintf(result, "  <div class=\"recipe-qr\">%s<span>%s</span></div>\n", qr.SVG(), html.EscapeString(pr.Options.URL))
+	}
 
-	return result.String()
+	result.WriteString("</div>\n")
 }
 
-// formatQuantity formats a quantity and unit for display
-func (pr PrintRenderer) formatQuantity(qty float32, unit string) string {
-	if qty <= 0 {
-		if qty == -1 {
+// formatQuantity formats an ingredient's quantity and unit for display, HTML-escaped
+func (pr PrintRenderer) formatQuantity(ingredient *cooklang.Ingredient, labels Strings) string {
+	qty, unit := ingredient.Quantity, ingredient.Unit
+	if !qty.HasAmount() {
+		if qty.IsSome() {
 			if unit != "" {
-				return fmt.Sprintf("some %s", unit)
+				return fmt.Sprintf("%s %s", html.EscapeString(labels.Some), html.EscapeString(unit))
 			}
-			return "some"
+			return html.EscapeString(labels.Some)
 		}
 		return ""
 	}
 
-	// Format quantity nicely (avoid .0 for whole numbers)
-	var qtyStr string
-	if qty == float32(int(qty)) {
-		qtyStr = fmt.Sprintf("%d", int(qty))
-	} else {
-		qtyStr = fmt.Sprintf("%g", qty)
+	if pr.Options.Quantities != nil {
+		return html.EscapeString(strings.TrimSpace(pr.Options.amount(ingredient) + " " + unit))
+	}
+
+	qtyStr := qty.String()
+	if ingredient.Approximate {
+		qtyStr = cooklang.ApproximatePrefix + qtyStr
 	}
 
 	if unit != "" {
-		return fmt.Sprintf("%s %s", qtyStr, unit)
+		return fmt.Sprintf("%s %s", html.EscapeString(qtyStr), html.EscapeString(unit))
 	}
-	return qtyStr
+	return html.EscapeString(qtyStr)
 }
 
 // DefaultPrintRenderer is the default instance of PrintRenderer
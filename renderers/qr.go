@@ -0,0 +1,436 @@
+package renderers
+
+import (
+	"fmt"
+	"strings"
+)
+
+// qrVersion describes the error correction blocks of a QR code version at error
+// correction level M.
+type qrVersion struct {
+	ecPerBlock int   // Error correction codewords per block
+	blocks     []int // Data codewords of each block
+	alignment  []int // Row and column centres of the alignment patterns
+}
+
+// qrVersions are the QR code versions 1 to 10 at level M, which hold up to 213
+// bytes: enough for recipe URLs.
+var qrVersions = []qrVersion{
+	{10, []int{16}, nil},
+	{16, []int{28}, []int{6, 18}},
+	{26, []int{44}, []int{6, 22}},
+	{18, []int{32, 32}, []int{6, 26}},
+	{24, []int{43, 43}, []int{6, 30}},
+	{16, []int{27, 27, 27, 27}, []int{6, 34}},
+	{18, []int{31, 31, 31, 31}, []int{6, 22, 38}},
+	{22, []int{38, 38, 39, 39}, []int{6, 24, 42}},
+	{22, []int{36, 36, 36, 37, 37}, []int{6, 26, 46}},
+	{26, []int{43, 43, 43, 43, 44}, []int{6, 28, 50}},
+}
+
+// qrCode is a QR code symbol: modules[y][x] is true for dark modules.
+type qrCode struct {
+	size     int
+	modules  [][]bool
+	function [][]bool // Finder, timing, alignment, format and version modules
+}
+
+// encodeQR encodes text in byte mode as the smallest QR code of versions 1 to 10
+// at error correction level M.
+func encodeQR(text string) (*qrCode, error) {
+	data := []byte(text)
+	version := 0
+	for v := range qrVersions {
+		countBits := 8
+		if v+1 >= 10 {
+			countBits = 16
+		}
+		if 4+countBits+8*len(data) <= 8*qrVersions[v].dataCodewords() {
+			version = v + 1
+			break
+		}
+	}
+	if version == 0 {
+		return nil, fmt.Errorf("text too long for a QR code: %d bytes (at most 213)", len(data))
+	}
+	info := qrVersions[version-1]
+
+	// Mode indicator, character count, data, terminator and padding
+	var bits qrBits
+	bits.append(0b0100, 4)
+	if version >= 10 {
+		bits.append(len(data), 16)
+	} else {
+		bits.append(len(data), 8)
+	}
+	for _, b := range data {
+		bits.append(int(b), 8)
+	}
+	capacity := 8 * info.dataCodewords()
+	bits.append(0, min(4, capacity-len(bits)))
+	bits.append(0, (8-len(bits)%8)%8)
+	codewords := bits.bytes()
+	for pad := byte(0xEC); len(codewords) < info.dataCodewords(); pad ^= 0xEC ^ 0x11 {
+		codewords = append(codewords, pad)
+	}
+
+	qr := newQRCode(version)
+	qr.drawCodewords(info.interleave(codewords))
+	qr.applyBestMask()
+	return qr, nil
+}
+
+// dataCodewords returns the number of data codewords of the version.
+func (v qrVersion) dataCodewords() int {
+	total := 0
+	for _, n := range v.blocks {
+		total += n
+	}
+	return total
+}
+
+// interleave splits the data codewords into blocks, adds each block's error
+// correction codewords and interleaves the blocks.
+func (v qrVersion) interleave(data []byte) []byte {
+	divisor := reedSolomonDivisor(v.ecPerBlock)
+	var blocks, ecBlocks [][]byte
+	for _, n := range v.blocks {
+		blocks = append(blocks, data[:n])
+		ecBlocks = append(ecBlocks, reedSolomonRemainder(data[:n], divisor))
+		data = data[n:]
+	}
+	var result []byte
+	for i := 0; i < v.blocks[len(v.blocks)-1]; i++ {
+		for _, block := range blocks {
+			if i < len(block) {
+				result = append(result, block[i])
+			}
+		}
+	}
+	for i := 0; i < v.ecPerBlock; i++ {
+		for _, block := range ecBlocks {
+			result = append(result, block[i])
+		}
+	}
+	return result
+}
+
+// newQRCode returns a symbol of the version with its function patterns drawn and
+// the format area reserved.
+func newQRCode(version int) *qrCode {
+	size := 17 + 4*version
+	qr := &qrCode{size: size, modules: make([][]bool, size), function: make([][]bool, size)}
+	for y := range size {
+		qr.modules[y] = make([]bool, size)
+		qr.function[y] = make([]bool, size)
+	}
+
+	for i := range size {
+		qr.set(6, i, i%2 == 0)
+		qr.set(i, 6, i%2 == 0)
+	}
+	qr.drawFinder(3, 3)
+	qr.drawFinder(size-4, 3)
+	qr.drawFinder(3, size-4)
+
+	alignment := qrVersions[version-1].alignment
+	last := len(alignment) - 1
+	for i, x := range alignment {
+		for j, y := range alignment {
+			if (i == 0 && j == 0) || (i == 0 && j == last) || (i == last && j == 0) {
+				continue // Overlaps a finder pattern
+			}
+			for dy := -2; dy <= 2; dy++ {
+				for dx := -2; dx <= 2; dx++ {
+					qr.set(x+dx, y+dy, max(abs(dx), abs(dy)) != 1)
+				}
+			}
+		}
+	}
+
+	qr.drawFormat(0) // Reserve the format area; applyBestMask draws the real one
+	if version >= 7 {
+		rem := version
+		for range 12 {
+			rem = (rem << 1) ^ ((rem >> 11) * 0x1F25)
+		}
+		bits := version<<12 | rem
+		for i := range 18 {
+			dark := (bits>>i)&1 != 0
+			a, b := size-11+i%3, i/3
+			qr.set(a, b, dark)
+			qr.set(b, a, dark)
+		}
+	}
+	return qr
+}
+
+// set sets a function module.
+func (qr *qrCode) set(x, y int, dark bool) {
+	qr.modules[y][x] = dark
+	qr.function[y][x] = true
+}
+
+// drawFinder draws a finder pattern and its separator around the centre (x, y).
+func (qr *qrCode) drawFinder(x, y int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			xx, yy := x+dx, y+dy
+			if xx >= 0 && xx < qr.size && yy >= 0 && yy < qr.size {
+				dist := max(abs(dx), abs(dy))
+				qr.set(xx, yy, dist != 2 && dist != 4)
+			}
+		}
+	}
+}
+
+// drawFormat draws both copies of the format information for level M and a mask.
+func (qr *qrCode) drawFormat(mask int) {
+	bits := qrFormatBits(mask)
+	bit := func(i int) bool { return (bits>>i)&1 != 0 }
+	for i := range 6 {
+		qr.set(8, i, bit(i))
+	}
+	qr.set(8, 7, bit(6))
+	qr.set(8, 8, bit(7))
+	qr.set(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		qr.set(14-i, 8, bit(i))
+	}
+	for i := range 8 {
+		qr.set(qr.size-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		qr.set(8, qr.size-15+i, bit(i))
+	}
+	qr.set(8, qr.size-8, true) // The dark module
+}
+
+// qrFormatBits returns the 15 format bits for level M and a mask.
+func qrFormatBits(mask int) int {
+	data := 0b00<<3 | mask // Level M
+	rem := data
+	for range 10 {
+		rem = (rem << 1) ^ ((rem >> 9) * 0x537)
+	}
+	return (data<<10 | rem) ^ 0x5412
+}
+
+// drawCodewords fills the data area in the zig-zag order of the standard.
+func (qr *qrCode) drawCodewords(data []byte) {
+	i := 0
+	for right := qr.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5 // Skip the vertical timing pattern
+		}
+		upward := (right+1)&2 == 0
+		for vert := range qr.size {
+			y := vert
+			if upward {
+				y = qr.size - 1 - vert
+			}
+			for j := range 2 {
+				x := right - j
+				if !qr.function[y][x] && i < len(data)*8 {
+					qr.modules[y][x] = (data[i>>3]>>(7-i&7))&1 != 0
+					i++
+				}
+			}
+		}
+	}
+}
+
+// qrMasks are the eight data mask patterns; a module is flipped where the mask is true.
+var qrMasks = [8]func(x, y int) bool{
+	func(x, y int) bool { return (x+y)%2 == 0 },
+	func(x, y int) bool { return y%2 == 0 },
+	func(x, y int) bool { return x%3 == 0 },
+	func(x, y int) bool { return (x+y)%3 == 0 },
+	func(x, y int) bool { return (x/3+y/2)%2 == 0 },
+	func(x, y int) bool { return x*y%2+x*y%3 == 0 },
+	func(x, y int) bool { return (x*y%2+x*y%3)%2 == 0 },
+	func(x, y int) bool { return ((x+y)%2+x*y%3)%2 == 0 },
+}
+
+// applyMask flips the data modules where the mask is true; applying it twice undoes it.
+func (qr *qrCode) applyMask(mask int) {
+	for y := range qr.size {
+		for x := range qr.size {
+			if !qr.function[y][x] && qrMasks[mask](x, y) {
+				qr.modules[y][x] = !qr.modules[y][x]
+			}
+		}
+	}
+}
+
+// applyBestMask applies the mask with the lowest penalty score and draws its
+// format information.
+func (qr *qrCode) applyBestMask() {
+	best, bestPenalty := 0, -1
+	for mask := range qrMasks {
+		qr.applyMask(mask)
+		qr.drawFormat(mask)
+		if penalty := qr.penalty(); bestPenalty < 0 || penalty < bestPenalty {
+			best, bestPenalty = mask, penalty
+		}
+		qr.applyMask(mask)
+	}
+	qr.applyMask(best)
+	qr.drawFormat(best)
+}
+
+// penalty scores how hard the symbol is to scan, by the four rules of the standard:
+// runs of one color, 2×2 blocks, finder-like patterns and the dark/light balance.
+func (qr *qrCode) penalty() int {
+	penalty := 0
+	dark := 0
+	finderLike := func(line []bool, i int) bool {
+		// 1:1:3:1:1 dark pattern with four light modules on either side
+		pattern := []bool{true, false, true, true, true, false, true}
+		for k, want := range pattern {
+			if line[i+k] != want {
+				return false
+			}
+		}
+		light := func(from int) bool {
+			for k := from; k < from+4; k++ {
+				if k >= 0 && k < len(line) && line[k] {
+					return false
+				}
+			}
+			return true
+		}
+		return light(i-4) || light(i+7)
+	}
+	for _, vertical := range []bool{false, true} {
+		for a := range qr.size {
+			line := make([]bool, qr.size)
+			for b := range qr.size {
+				if vertical {
+					line[b] = qr.modules[b][a]
+				} else {
+					line[b] = qr.modules[a][b]
+				}
+			}
+			run := 1
+			for b := 1; b <= qr.size; b++ {
+				if b < qr.size && line[b] == line[b-1] {
+					run++
+					continue
+				}
+				if run >= 5 {
+					penalty += run - 2
+				}
+				run = 1
+			}
+			for b := 0; b+7 <= qr.size; b++ {
+				if finderLike(line, b) {
+					penalty += 40
+				}
+			}
+		}
+	}
+	for y := range qr.size {
+		for x := range qr.size {
+			if qr.modules[y][x] {
+				dark++
+			}
+			if x+1 < qr.size && y+1 < qr.size {
+				c := qr.modules[y][x]
+				if qr.modules[y][x+1] == c && qr.modules[y+1][x] == c && qr.modules[y+1][x+1] == c {
+					penalty += 3
+				}
+			}
+		}
+	}
+	total := qr.size * qr.size
+	penalty += 10 * (abs(dark*20-total*10) / total)
+	return penalty
+}
+
+// SVG returns the symbol as an SVG image with a four-module quiet zone, scaled to
+// its container.
+func (qr *qrCode) SVG() string {
+	var path strings.Builder
+	for y := range qr.size {
+		for x := range qr.size {
+			if qr.modules[y][x] {
+				fmt.Fprintf(&path, "M%d %dh1v1h-1z", x+4, y+4)
+			}
+		}
+	}
+	side := qr.size + 8
+	return fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %d %d" shape-rendering="crispEdges"><rect width="%d" height="%d" fill="#fff"/><path d="%s" fill="#000"/></svg>`,
+		side, side, side, side, path.String())
+}
+
+// qrBits is a bit buffer, most significant bit first.
+type qrBits []bool
+
+// append appends the n low bits of value.
+func (b *qrBits) append(value, n int) {
+	for i := n - 1; i >= 0; i-- {
+		*b = append(*b, (value>>i)&1 != 0)
+	}
+}
+
+// bytes packs the bits into bytes; the length must be a multiple of 8.
+func (b qrBits) bytes() []byte {
+	result := make([]byte, len(b)/8)
+	for i, bit := range b {
+		if bit {
+			result[i/8] |= 1 << (7 - i%8)
+		}
+	}
+	return result
+}
+
+// reedSolomonDivisor returns the generator polynomial of the given degree over
+// GF(256), without its leading coefficient.
+func reedSolomonDivisor(degree int) []byte {
+	result := make([]byte, degree)
+	result[degree-1] = 1
+	root := byte(1)
+	for range degree {
+		for j := range result {
+			result[j] = gfMultiply(result[j], root)
+			if j+1 < len(result) {
+				result[j] ^= result[j+1]
+			}
+		}
+		root = gfMultiply(root, 0x02)
+	}
+	return result
+}
+
+// reedSolomonRemainder returns the error correction codewords of data.
+func reedSolomonRemainder(data, divisor []byte) []byte {
+	result := make([]byte, len(divisor))
+	for _, b := range data {
+		factor := b ^ result[0]
+		copy(result, result[1:])
+		result[len(result)-1] = 0
+		for i, coefficient := range divisor {
+			result[i] ^= gfMultiply(coefficient, factor)
+		}
+	}
+	return result
+}
+
+// gfMultiply multiplies in GF(2^8) modulo x^8 + x^4 + x^3 + x^2 + 1.
+func gfMultiply(x, y byte) byte {
+	z := 0
+	for i := 7; i >= 0; i-- {
+		z = (z << 1) ^ ((z >> 7) * 0x11D)
+		z ^= int((y>>i)&1) * int(x)
+	}
+	return byte(z)
+}
+
+// abs returns the absolute value of an int.
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
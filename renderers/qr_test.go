@@ -0,0 +1,183 @@
+package renderers
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/hilli/cooklang"
+)
+
+func TestReedSolomonRemainder(t *testing.T) {
+	// "HELLO WORLD" as a version 1-M symbol, from the ISO/IEC 18004 worked example
+	data := []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17}
+	want := []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23}
+	if got := reedSolomonRemainder(data, reedSolomonDivisor(10)); !bytes.Equal(got, want) {
+		t.Errorf("reedSolomonRemainder = %v, want %v", got, want)
+	}
+}
+
+func TestQRFormatAndVersionBits(t *testing.T) {
+	for mask, want := range map[int]int{0: 0b101010000010010, 1: 0b101000100100101, 4: 0b100010111111001, 7: 0b100101010100000} {
+		if got := qrFormatBits(mask); got != want {
+			t.Errorf("qrFormatBits(%d) = %015b, want %015b", mask, got, want)
+		}
+	}
+	qr := newQRCode(7)
+	var bits int
+	for i := 17; i >= 0; i-- {
+		bits = bits<<1 | boolBit(qr.modules[i/3][qr.size-11+i%3])
+	}
+	if bits != 0b000111110010010100 {
+		t.Errorf("version 7 information = %018b", bits)
+	}
+}
+
+func TestEncodeQRRoundTrip(t *testing.T) {
+	for _, text := range []string{"https://example.com/r/1", "https://recipes.example.com/desserts/chocolate-chip-cookies?servings=24&units=metric", strings.Repeat("x", 213)} {
+		qr, err := encodeQR(text)
+		if err != nil {
+			t.Fatalf("encodeQR(%d bytes): %v", len(text), err)
+		}
+		if got := decodeQR(t, qr); got != text {
+			t.Errorf("decoded %q, want %q", got, text)
+		}
+	}
+	if _, err := encodeQR(strings.Repeat("x", 214)); err == nil {
+		t.Error("expected an error for text that does not fit version 10")
+	}
+}
+
+func TestPrintRendererQRCode(t *testing.T) {
+	recipe, err := cooklang.ParseString("Toast @bread{2%slices}.")
+	if err != nil {
+		t.Fatalf("Failed to parse recipe: %v", err)
+	}
+	output, err := PrintRenderer{}.Render(recipe, RendererOptions{URL: "https://example.com/toast?a=1&b=2"})
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if !strings.Contains(output, `<div class="recipe-qr"><svg xmlns="http://www.w3.org/2000/svg"`) || !strings.Contains(output, "https://example.com/toast?a=1&amp;b=2</span>") {
+		t.Errorf("expected a QR code with the escaped URL, got:\n%s", output)
+	}
+	if output, _ := (PrintRenderer{}).Render(recipe, RendererOptions{}); strings.Contains(output, "recipe-qr\">") {
+		t.Errorf("expected no QR code without a URL, got:\n%s", output)
+	}
+	if _, err := (PrintRenderer{}).Render(recipe, RendererOptions{URL: strings.Repeat("x", 300)}); err == nil {
+		t.Error("expected an error for a URL too long for a QR code")
+	}
+}
+
+// decodeQR reads a level M byte-mode symbol back, checking the format information
+// and every block's error correction codewords.
+func decodeQR(t *testing.T, qr *qrCode) string {
+	t.Helper()
+	version := (qr.size - 17) / 4
+	info := qrVersions[version-1]
+
+	// Format information around the top-left finder, most significant bit first
+	var format int
+	for x := 0; x <= 8; x++ {
+		if x != 6 {
+			format = format<<1 | boolBit(qr.modules[8][x])
+		}
+	}
+	for y := 7; y >= 0; y-- {
+		if y != 6 {
+			format = format<<1 | boolBit(qr.modules[y][8])
+		}
+	}
+	mask := -1
+	for m := range qrMasks {
+		if qrFormatBits(m) == format {
+			mask = m
+		}
+	}
+	if mask < 0 {
+		t.Fatalf("invalid format information %015b", format)
+	}
+
+	// Read the data modules column pair by column pair, undoing the mask
+	reserved := newQRCode(version).function
+	var bits qrBits
+	for col := qr.size - 1; col > 0; col -= 2 {
+		if col == 6 {
+			col--
+		}
+		up := ((qr.size-1-col)/2)%2 == 0
+		if col < 6 {
+			up = ((qr.size-2-col)/2)%2 == 0
+		}
+		for i := range qr.size {
+			y := i
+			if up {
+				y = qr.size - 1 - i
+			}
+			for _, x := range []int{col, col - 1} {
+				if !reserved[y][x] {
+					bits = append(bits, qr.modules[y][x] != qrMasks[mask](x, y))
+				}
+			}
+		}
+	}
+	codewords := bits[:len(bits)/8*8].bytes()
+
+	// De-interleave and check the error correction codewords of each block
+	blocks := make([][]byte, len(info.blocks))
+	i := 0
+	for k := 0; k < info.blocks[len(info.blocks)-1]; k++ {
+		for b, n := range info.blocks {
+			if k < n {
+				blocks[b] = append(blocks[b], codewords[i])
+				i++
+			}
+		}
+	}
+	for k := 0; k < info.ecPerBlock; k++ {
+		for b := range blocks {
+			blocks[b] = append(blocks[b], codewords[i])
+			i++
+		}
+	}
+	var data []byte
+	for b, n := range info.blocks {
+		ec := reedSolomonRemainder(blocks[b][:n], reedSolomonDivisor(info.ecPerBlock))
+		if !bytes.Equal(ec, blocks[b][n:]) {
+			t.Fatalf("block %d has wrong error correction codewords", b)
+		}
+		data = append(data, blocks[b][:n]...)
+	}
+
+	// Byte mode: 0100, the length, then the bytes
+	var stream qrBits
+	for _, b := range data {
+		stream.append(int(b), 8)
+	}
+	read := func(n int) int {
+		v := 0
+		for _, bit := range stream[:n] {
+			v = v<<1 | boolBit(bit)
+		}
+		stream = stream[n:]
+		return v
+	}
+	if mode := read(4); mode != 0b0100 {
+		t.Fatalf("mode %04b, want byte mode", mode)
+	}
+	length := read(8)
+	if version >= 10 {
+		length = length<<8 | read(8)
+	}
+	text := make([]byte, length)
+	for k := range text {
+		text[k] = byte(read(8))
+	}
+	return string(text)
+}
+
+func boolBit(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
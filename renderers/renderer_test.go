@@ -1,8 +1,11 @@
 package renderers
 
 import (
+	"errors"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/hilli/cooklang"
 )
@@ -26,7 +29,7 @@ func TestBasicRenderers(t *testing.T) {
 
 	// Step 1 components
 	inst1 := &cooklang.Instruction{Text: "Boil "}
-	ing1 := &cooklang.Ingredient{Name: "water", Quantity: 2, Unit: "liters"}
+	ing1 := &cooklang.Ingredient{Name: "water", Quantity: cooklang.NewQuantity(2, 1), Unit: "liters"}
 	inst2 := &cooklang.Instruction{Text: " in a "}
 	cookware1 := &cooklang.Cookware{Name: "large pot", Quantity: 1}
 	inst3 := &cooklang.Instruction{Text: "."}
@@ -42,7 +45,7 @@ func TestBasicRenderers(t *testing.T) {
 	recipe.FirstStep = step1
 
 	t.Run("CooklangRenderer", func(t *testing.T) {
-		output := recipe.RenderWith(Default.Cooklang)
+		output := renderWith(t, recipe, Default.Cooklang)
 		if !strings.Contains(output, "---") {
 			t.Errorf("Expected YAML frontmatter delimiter '---', got: %s", output)
 		}
@@ -61,7 +64,7 @@ func TestBasicRenderers(t *testing.T) {
 	})
 
 	t.Run("MarkdownRenderer", func(t *testing.T) {
-		output := recipe.RenderWith(Default.Markdown)
+		output := renderWith(t, recipe, Default.Markdown)
 		if !strings.Contains(output, "# Test Pasta") {
 			t.Errorf("Expected Markdown title format, got: %s", output)
 		}
@@ -74,7 +77,7 @@ func TestBasicRenderers(t *testing.T) {
 	})
 
 	t.Run("HTMLRenderer", func(t *testing.T) {
-		output := recipe.RenderWith(Default.HTML)
+		output := renderWith(t, recipe, Default.HTML)
 		if !strings.Contains(output, "<h1 class=\"recipe-title\">Test Pasta</h1>") {
 			t.Errorf("Expected HTML title format, got: %s", output)
 		}
@@ -87,7 +90,7 @@ func TestBasicRenderers(t *testing.T) {
 	})
 
 	t.Run("PrintRenderer", func(t *testing.T) {
-		output := recipe.RenderWith(Default.Print)
+		output := renderWith(t, recipe, Default.Print)
 
 		// Check for complete HTML document structure
 		if !strings.Contains(output, "<!DOCTYPE html>") {
@@ -188,7 +191,7 @@ func TestBasicRenderers(t *testing.T) {
 			Servings: 4,
 		}
 
-		output := recipeWithArrays.RenderWith(Default.Cooklang)
+		output := renderWith(t, recipeWithArrays, Default.Cooklang)
 
 		// Check that tags are formatted as YAML array
 		if !strings.Contains(output, "tags:\n") {
@@ -224,3 +227,651 @@ func TestBasicRenderers(t *testing.T) {
 		}
 	})
 }
+
+func TestRenderersShowApproximateQuantities(t *testing.T) {
+	recipe, err := cooklang.ParseString("Chop @onion{~2} and add @stock{about 500%ml}.")
+	if err != nil {
+		t.Fatalf("Failed to parse recipe: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		output   string
+		expected []string
+	}{
+		{"cooklang", renderRecipe(t, CooklangRenderer{}, recipe), []string{"@onion{~2%}", "@stock{~500%ml}"}},
+		{"markdown", renderRecipe(t, MarkdownRenderer{}, recipe), []string{"**≈2** onion", "**≈500 ml** stock"}},
+		{"html", renderRecipe(t, HTMLRenderer{}, recipe), []string{`<span class="quantity">≈2</span>`, `<span class="quantity">≈500 ml</span>`}},
+		{"print", renderRecipe(t, PrintRenderer{}, recipe), []string{`<span class="ingredient-qty">≈2</span>`, `<span class="qty">(≈500 ml)</span>`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, want := range tt.expected {
+				if !strings.Contains(tt.output, want) {
+					t.Errorf("Expected output to contain %q, got:\n%s", want, tt.output)
+				}
+			}
+		})
+	}
+
+	voice := VoiceRenderer{}.BuildRecipe(recipe)
+	if got := voice.Ingredients[1].Speech; got != "about 500 ml of stock" {
+		t.Errorf("Expected approximate speech, got %q", got)
+	}
+	if !voice.Ingredients[0].Approximate {
+		t.Errorf("Expected voice ingredient to be approximate: %+v", voice.Ingredients[0])
+	}
+}
+
+func TestRenderersKeepIngredientAnnotations(t *testing.T) {
+	recipe, err := cooklang.ParseString("---\nservings: 2\n---\nWhisk @milk{1%l}(cold) with @eggs{2}(large) and @salt(fine).")
+	if err != nil {
+		t.Fatalf("Failed to parse recipe: %v", err)
+	}
+	scaled := recipe.Scale(2).ConvertToSystem(cooklang.UnitSystemUS)
+
+	tests := []struct {
+		name     string
+		output   string
+		expected []string
+	}{
+		{"cooklang", renderRecipe(t, CooklangRenderer{}, recipe), []string{"@milk{1%l}(cold)", "@eggs{2%}(large)", "@salt{}(fine)"}},
+		{"cooklang scaled and converted", renderRecipe(t, CooklangRenderer{}, scaled), []string{"(cold)", "@eggs{4%}(large)", "@salt{}(fine)"}},
+		{"markdown", renderRecipe(t, MarkdownRenderer{}, recipe), []string{"**1 l** milk, cold", "**2** eggs, large", "**some** salt, fine", "**milk** (1 l) (cold)", "**eggs** (2) (large)"}},
+		{"html", renderRecipe(t, HTMLRenderer{}, recipe), []string{
+			`<span class="ingredient">milk</span><span class="annotation">, cold</span>`,
+			`<span class="ingredient">eggs</span> <span class="quantity">(2)</span> <span class="annotation">(large)</span>`,
+		}},
+		{"print", renderRecipe(t, PrintRenderer{}, recipe), []string{`<span class="ingredient-name">salt</span><span class="annotation">, fine</span>`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, want := range tt.expected {
+				if !strings.Contains(tt.output, want) {
+					t.Errorf("Expected output to contain %q, got:\n%s", want, tt.output)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderersShowReservedCallouts(t *testing.T) {
+	recipe, err := cooklang.ParseString("Simmer the @tomato sauce{500%ml} (reserve 240 ml for step 3).\n\n== Pasta ==\n\nBoil the @pasta{200%g}.\n\nToss with the reserved sauce.")
+	if err != nil {
+		t.Fatalf("Failed to parse recipe: %v", err)
+	}
+
+	callout := "Use the reserved 240 ml tomato sauce from step 1"
+	tests := []struct {
+		name     string
+		output   string
+		expected string
+	}{
+		{"markdown", renderRecipe(t, MarkdownRenderer{}, recipe), "2. Toss with the reserved sauce.\n\n   > ↩ " + callout + "\n\n"},
+		{"html", renderRecipe(t, HTMLRenderer{}, recipe), `<aside class="reserved-callout">` + callout + `</aside>`},
+		{"print", renderRecipe(t, PrintRenderer{}, recipe), `<span class="reserved">↩ ` + callout + `</span></li>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !strings.Contains(tt.output, tt.expected) {
+				t.Errorf("Expected output to contain %q, got:\n%s", tt.expected, tt.output)
+			}
+			if strings.Count(tt.output, callout) != 1 {
+				t.Errorf("Expected exactly one callout, got:\n%s", tt.output)
+			}
+		})
+	}
+}
+
+func TestRenderersShowStepImages(t *testing.T) {
+	recipe, err := cooklang.ParseString("---\ntitle: Pancakes\nstep_images: [whisk.jpg, \"\"]\n---\nWhisk @eggs{2}.\n\nFry in a #pan{}.\n")
+	if err != nil {
+		t.Fatalf("Failed to parse recipe: %v", err)
+	}
+
+	image := `<img class="step-image" src="whisk.jpg" alt="Pancakes">`
+	tests := []struct {
+		name     string
+		output   string
+		expected string
+	}{
+		{"html", renderRecipe(t, HTMLRenderer{}, recipe), image},
+		{"print", renderRecipe(t, PrintRenderer{}, recipe), image + "</li>"},
+		{"email", renderRecipe(t, EmailRenderer{ImageBaseURL: "https://example.com/"}, recipe), `<img src="https://example.com/whisk.jpg"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !strings.Contains(tt.output, tt.expected) {
+				t.Errorf("Expected output to contain %q, got:\n%s", tt.expected, tt.output)
+			}
+			if strings.Count(tt.output, "whisk.jpg") != 1 {
+				t.Errorf("Expected the image once, got:\n%s", tt.output)
+			}
+		})
+	}
+
+	steps := JSONLDRenderer{}.BuildRecipe(recipe, nil)["recipeInstructions"].([]interface{})
+	if image := steps[0].(map[string]interface{})["image"]; image != "whisk.jpg" {
+		t.Errorf("Expected the first HowToStep image to be whisk.jpg, got %v", image)
+	}
+	if image, ok := steps[1].(map[string]interface{})["image"]; ok {
+		t.Errorf("Expected no image on the second HowToStep, got %v", image)
+	}
+}
+
+func TestRenderersWithInferredTitle(t *testing.T) {
+	recipe, err := cooklang.ParseString("Pour @gin{50%ml} over @ice{}.")
+	if err != nil {
+		t.Fatalf("Failed to parse recipe: %v", err)
+	}
+	recipe.InferTitle("Gin_and_Tonic.cook", false)
+
+	if got := renderRecipe(t, CooklangRenderer{}, recipe); strings.Contains(got, "title:") {
+		t.Errorf("Inferred title should not be written as frontmatter, got:\n%s", got)
+	}
+	if got := renderRecipe(t, MarkdownRenderer{}, recipe); !strings.HasPrefix(got, "# Gin and Tonic\n") {
+		t.Errorf("Expected Markdown heading with the inferred title, got:\n%s", got)
+	}
+	data, err := JSONLDRenderer{}.RenderRecipeJSON(recipe, nil)
+	if err != nil {
+		t.Fatalf("JSON-LD error: %v", err)
+	}
+	if !strings.Contains(data, `"name": "Gin and Tonic"`) {
+		t.Errorf("Expected JSON-LD name from the inferred title, got:\n%s", data)
+	}
+}
+
+func TestRenderersLocalizedLabels(t *testing.T) {
+	recipe, err := cooklang.ParseString("---\ntitle: Salat\nservings: 2\n---\nMix @lettuce{1} with @salt{} and @chives{}(?).\n\nServe the dressing (reserve 50 ml for step 3).\n\nDrizzle the reserved dressing.")
+	if err != nil {
+		t.Fatalf("Failed to parse recipe: %v", err)
+	}
+
+	german := RendererOptions{Locale: "de-AT"}
+	tests := []struct {
+		name     string
+		output   string
+		expected []string
+	}{
+		{"markdown", renderRecipe(t, MarkdownRenderer{Options: german}, recipe),
+			[]string{"## Rezeptinformationen", "**Portionen:** 2", "## Zutaten", "## Zubereitung", "**etwas** salt", "Das Zurückbehaltene aus Schritt 2 verwenden: 50 ml"}},
+		{"html", renderRecipe(t, HTMLRenderer{Options: german}, recipe),
+			[]string{"<h2>Zutaten</h2>", "<h2>Zubereitung</h2>", "<dt>Portionen</dt>", `<span class="quantity">etwas</span>`}},
+		{"print", renderRecipe(t, PrintRenderer{Options: german}, recipe),
+			[]string{`<html lang="de">`, "<h2>Zutaten</h2>", "<h2>Zubereitung</h2>", "Portionen:"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, want := range tt.expected {
+				if !strings.Contains(tt.output, want) {
+					t.Errorf("Expected output to contain %q, got:\n%s", want, tt.output)
+				}
+			}
+			for _, english := range []string{"Ingredients", "Instructions"} {
+				if strings.Contains(tt.output, english) {
+					t.Errorf("Expected no English label %q, got:\n%s", english, tt.output)
+				}
+			}
+		})
+	}
+
+	// Custom strings override the locale's labels; the rest still come from the locale
+	custom := RendererOptions{Locale: "fr", Strings: &Strings{Ingredients: "Il vous faut"}}
+	output := renderRecipe(t, MarkdownRenderer{Options: custom}, recipe)
+	if !strings.Contains(output, "## Il vous faut") || !strings.Contains(output, "## Étapes") {
+		t.Errorf("Expected custom and French labels, got:\n%s", output)
+	}
+}
+
+func TestRenderersTemperatures(t *testing.T) {
+	recipe, err := cooklang.ParseString("Preheat the #oven{} to 180°C.")
+	if err != nil {
+		t.Fatalf("Failed to parse recipe: %v", err)
+	}
+
+	oven, err := cooklang.ParseString("---\noven_temp: 350F\n---\nBake the @bread{}.")
+	if err != nil {
+		t.Fatalf("Failed to parse recipe: %v", err)
+	}
+
+	fahrenheit := RendererOptions{TemperatureScale: cooklang.Fahrenheit}
+	both := RendererOptions{BothTemperatureScales: true}
+	tests := []struct {
+		name     string
+		output   string
+		expected string
+	}{
+		{"markdown", renderRecipe(t, MarkdownRenderer{}, recipe), "to 180°C."},
+		{"markdown fahrenheit", renderRecipe(t, MarkdownRenderer{Options: fahrenheit}, recipe), "to 355°F."},
+		{"html", renderRecipe(t, HTMLRenderer{}, recipe), `to <span class="temperature">180°C</span>.`},
+		{"html fahrenheit", renderRecipe(t, HTMLRenderer{Options: fahrenheit}, recipe), `<span class="temperature">355°F</span>`},
+		{"print fahrenheit", renderRecipe(t, PrintRenderer{Options: fahrenheit}, recipe), `<span class="temp">355°F</span>`},
+		{"cooklang", renderRecipe(t, NewCooklangRenderer(), recipe), "Preheat the #oven{} to 180°C."},
+		{"voice", VoiceRenderer{}.BuildRecipe(recipe).Steps[0].Display, "Preheat the oven to 180°C."},
+		{"markdown both", renderRecipe(t, MarkdownRenderer{Options: both}, recipe), "to 180°C / 355°F."},
+		{"markdown oven_temp", renderRecipe(t, MarkdownRenderer{Options: both}, oven), "**Oven Temp:** 350°F / 175°C"},
+		{"html oven_temp", renderRecipe(t, HTMLRenderer{Options: fahrenheit}, oven), "<dt>Oven</dt><dd>350°F</dd>"},
+		{"print oven_temp", renderRecipe(t, PrintRenderer{Options: both}, oven), "Oven:</span> 350°F / 175°C"},
+	}
+	for _, tt := range tests {
+		if !strings.Contains(tt.output, tt.expected) {
+			t.Errorf("%s: expected output to contain %q, got:\n%s", tt.name, tt.expected, tt.output)
+		}
+	}
+}
+
+func TestRenderersIngredientReferences(t *testing.T) {
+	recipe, err := cooklang.ParseString("Whisk @flour{500%g}.\n\nFold in the reserved @&flour{}.")
+	if err != nil {
+		t.Fatalf("Failed to parse recipe: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		output   string
+		expected string
+		listed   string // Ingredient list entry, which must appear once
+	}{
+		{"markdown", renderRecipe(t, MarkdownRenderer{}, recipe), "Fold in the reserved **flour**.", "- **500 g** flour"},
+		{"html", renderRecipe(t, HTMLRenderer{}, recipe), `reserved <span class="ingredient reference">flour</span>.`, `<span class="ingredient">flour</span></li>`},
+		{"print", renderRecipe(t, PrintRenderer{}, recipe), `reserved <span class="ing reference">flour</span>.`, `<span class="ingredient-name">flour</span>`},
+		{"cooklang", renderRecipe(t, NewCooklangRenderer(), recipe), "Fold in the reserved @&flour{}.", "Whisk @flour{500%g}."},
+	}
+	for _, tt := range tests {
+		if !strings.Contains(tt.output, tt.expected) {
+			t.Errorf("%s: expected output to contain %q, got:\n%s", tt.name, tt.expected, tt.output)
+		}
+		if count := strings.Count(tt.output, tt.listed); count != 1 {
+			t.Errorf("%s: expected %q once, found %d times:\n%s", tt.name, tt.listed, count, tt.output)
+		}
+	}
+}
+
+func TestRenderersUnicodeFractions(t *testing.T) {
+	recipe, err := cooklang.ParseString("Whisk @milk{1.5%cups} with @sugar{~0.25%cup} and @salt{1/2-1%tsp}.")
+	if err != nil {
+		t.Fatalf("Failed to parse recipe: %v", err)
+	}
+
+	unicode := RendererOptions{Quantities: &cooklang.FormatOptions{UnicodeFractions: true, MaxDenominator: 16}}
+	tests := []struct {
+		name     string
+		output   string
+		expected string
+	}{
+		{"markdown", renderRecipe(t, MarkdownRenderer{}, recipe), "**1.5 cups** milk"},
+		{"markdown unicode", renderRecipe(t, MarkdownRenderer{Options: unicode}, recipe), "**1½ cups** milk"},
+		{"markdown unicode range", renderRecipe(t, MarkdownRenderer{Options: unicode}, recipe), "**½-1 tsp** salt"},
+		{"html unicode", renderRecipe(t, HTMLRenderer{Options: unicode}, recipe), `<span class="quantity">≈¼ cup</span>`},
+		{"print unicode", renderRecipe(t, PrintRenderer{Options: unicode}, recipe), `<span class="ingredient-qty">1½ cups</span>`},
+		{"terminal unicode", renderRecipe(t, TerminalRenderer{Options: unicode, NoColor: true}, recipe), "milk (1½ cups)"},
+	}
+	for _, tt := range tests {
+		if !strings.Contains(tt.output, tt.expected) {
+			t.Errorf("%s: expected output to contain %q, got:\n%s", tt.name, tt.expected, tt.output)
+		}
+	}
+}
+
+func TestRenderersQuantityNotes(t *testing.T) {
+	recipe, err := cooklang.ParseString(`---
+servings: 2
+---
+Mix @flour{200%g} with @salt{} and @yeast{=7%g}.`)
+	if err != nil {
+		t.Fatalf("Failed to parse recipe: %v", err)
+	}
+	scaled := recipe.ScaleWithOptions(2, cooklang.ScaleOptions{})
+
+	notes := func(note QuantityNote) RendererOptions { return RendererOptions{QuantityNotes: note} }
+	tests := []struct {
+		name     string
+		output   string
+		expected string
+	}{
+		{"markdown servings", renderRecipe(t, MarkdownRenderer{Options: notes(NoteServings)}, scaled), "**400 g** flour (for 4 servings)"},
+		{"markdown original", renderRecipe(t, MarkdownRenderer{Options: notes(NoteOriginal)}, scaled), "**400 g** flour (200 g for 2 servings)"},
+		{"markdown per serving", renderRecipe(t, MarkdownRenderer{Options: notes(NotePerServing)}, scaled), "**400 g** flour (100 g per serving)"},
+		{"markdown fixed", renderRecipe(t, MarkdownRenderer{Options: notes(NotePerServing)}, scaled), "**7 g** yeast\n"},
+		{"markdown unscaled", renderRecipe(t, MarkdownRenderer{Options: notes(NoteOriginal)}, recipe), "**200 g** flour\n"},
+		{"html original", renderRecipe(t, HTMLRenderer{Options: notes(NoteOriginal)}, scaled), `<span class="quantity-note">(200 g for 2 servings)</span>`},
+		{"print per serving", renderRecipe(t, PrintRenderer{Options: notes(NotePerServing)}, scaled), `<span class="quantity-note">(100 g per serving)</span>`},
+		{"german", renderRecipe(t, MarkdownRenderer{Options: RendererOptions{Locale: "de", QuantityNotes: NotePerServing}}, scaled), "(100 g pro Portion)"},
+	}
+	for _, tt := range tests {
+		if !strings.Contains(tt.output, tt.expected) {
+			t.Errorf("%s: expected output to contain %q, got:\n%s", tt.name, tt.expected, tt.output)
+		}
+	}
+	output := renderRecipe(t, MarkdownRenderer{Options: notes(NotePerServing)}, scaled)
+	if strings.Contains(output, "salt (") {
+		t.Errorf("expected no note for salt without an amount, got:\n%s", output)
+	}
+}
+
+func TestRenderersGroupIngredients(t *testing.T) {
+	recipe, err := cooklang.ParseString(`== Dough ==
+
+Mix @flour{200%g} and @eggs{2}.
+
+> Let the batter rest if you have time.
+
+Rest for ~{10%minutes}.
+
+== Cooking ==
+
+Fry in @butter{1%tbsp}.
+`)
+	if err != nil {
+		t.Fatalf("Failed to parse recipe: %v", err)
+	}
+
+	byStep := RendererOptions{GroupIngredients: GroupByStep}
+	markdown := renderRecipe(t, MarkdownRenderer{Options: byStep}, recipe)
+	if !strings.Contains(markdown, "### Dough: Step 1\n\n- **200 g** flour\n- **2** eggs\n\n### Cooking: Step 1\n\n- **1 tbsp** butter\n") {
+		t.Errorf("Markdown: expected ingredients grouped by step, got:\n%s", markdown)
+	}
+	// The print renderer numbers steps through the whole recipe; notes are not numbered
+	plain, err := cooklang.ParseString("Mix @flour{200%g}.\n\n> A tip.\n\nStir.\n\nFry in @butter{1%tbsp}.\n")
+	if err != nil {
+		t.Fatalf("Failed to parse recipe: %v", err)
+	}
+	print := renderRecipe(t, PrintRenderer{Options: byStep}, plain)
+	for _, expected := range []string{`<h3 class="ingredient-group">Step 1</h3>`, `<h3 class="ingredient-group">Step 3</h3>`} {
+		if !strings.Contains(print, expected) {
+			t.Errorf("Print: expected %q, got:\n%s", expected, print)
+		}
+	}
+
+	bySection := RendererOptions{GroupIngredients: GroupBySection, Locale: "de"}
+	html := renderRecipe(t, HTMLRenderer{Options: bySection}, recipe)
+	for _, expected := range []string{`<h3 class="ingredient-group">Dough</h3>`, `<h3 class="ingredient-group">Cooking</h3>`} {
+		if !strings.Contains(html, expected) {
+			t.Errorf("HTML: expected %q, got:\n%s", expected, html)
+		}
+	}
+	if strings.Count(html, "<ul>") != 2 {
+		t.Errorf("HTML: expected one list per section, got:\n%s", html)
+	}
+
+	german := MarkdownRenderer{Options: RendererOptions{GroupIngredients: GroupByStep, Locale: "de"}}
+	if output := renderRecipe(t, german, recipe); !strings.Contains(output, "### Dough: Schritt 1") {
+		t.Errorf("expected a German step heading, got:\n%s", output)
+	}
+
+	// Without grouping, the list is unchanged
+	if output := renderRecipe(t, MarkdownRenderer{}, recipe); strings.Contains(output, "Step 1") {
+		t.Errorf("expected one ingredient list, got:\n%s", output)
+	}
+}
+
+func TestRenderersTimerUnits(t *testing.T) {
+	recipe, err := cooklang.ParseString("Roast for ~roast time{4%hours}, then rest ~{10%minutes}.", cooklang.WithExtendedMode())
+	if err != nil {
+		t.Fatalf("Failed to parse recipe: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		output   string
+		expected string
+	}{
+		{"cooklang", renderRecipe(t, CooklangRenderer{}, recipe), "Roast for ~roast time{4%hours}, then rest ~{10%minutes}."},
+		{"markdown", renderRecipe(t, MarkdownRenderer{}, recipe), "⏲️ roast time (4 hours), then rest ⏲️ 10 minutes."},
+		{"html", renderRecipe(t, HTMLRenderer{}, recipe), `<span class="timer">⏲️ roast time (4 hours)</span>`},
+		{"print", renderRecipe(t, PrintRenderer{}, recipe), `<span class="tmr">roast time: 4 hours</span>`},
+	}
+	for _, tt := range tests {
+		if !strings.Contains(tt.output, tt.expected) {
+			t.Errorf("%s: expected output to contain %q, got:\n%s", tt.name, tt.expected, tt.output)
+		}
+	}
+}
+
+func TestRenderersNotes(t *testing.T) {
+	recipe, err := cooklang.ParseString("---\ntitle: Toast\n---\n> Best with day-old bread.\n\nToast @bread{2%slices}.\n\nServe warm.")
+	if err != nil {
+		t.Fatalf("Failed to parse recipe: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		output   string
+		expected string
+	}{
+		{"markdown", renderRecipe(t, MarkdownRenderer{}, recipe), "> *Best with day-old bread.*"},
+		{"html", renderRecipe(t, HTMLRenderer{}, recipe), `<aside class="recipe-note">Best with day-old bread.</aside>`},
+		{"print", renderRecipe(t, PrintRenderer{}, recipe), `<li class="note">Best with day-old bread.</li>`},
+	}
+	for _, tt := range tests {
+		if !strings.Contains(tt.output, tt.expected) {
+			t.Errorf("%s: expected output to contain %q, got:\n%s", tt.name, tt.expected, tt.output)
+		}
+	}
+
+	jsonld, err := JSONLDRenderer{}.RenderRecipeJSON(recipe, nil)
+	if err != nil {
+		t.Fatalf("Failed to render JSON-LD: %v", err)
+	}
+	if strings.Contains(jsonld, "day-old") {
+		t.Errorf("expected notes to be left out of JSON-LD instructions, got:\n%s", jsonld)
+	}
+}
+
+func TestRenderersImageSources(t *testing.T) {
+	recipe := &cooklang.Recipe{Title: "Toast", Images: []string{"Toast.jpg"}}
+	options := RendererOptions{Images: []string{"data:image/jpeg;base64,AAAA"}}
+
+	if output := renderRecipe(t, HTMLRenderer{}, recipe); strings.Contains(output, "<img") {
+		t.Errorf("HTML should only show images given in the options:\n%s", output)
+	}
+	if output := renderRecipe(t, HTMLRenderer{Options: options}, recipe); !strings.Contains(output, `<img class="recipe-image" src="data:image/jpeg;base64,AAAA" alt="Toast">`) {
+		t.Errorf("HTML missing the image:\n%s", output)
+	}
+	if output := renderRecipe(t, PrintRenderer{}, recipe); !strings.Contains(output, `src="Toast.jpg"`) {
+		t.Error("Print should fall back to recipe.Images")
+	}
+	if output := renderRecipe(t, PrintRenderer{Options: options}, recipe); !strings.Contains(output, `src="data:image/jpeg;base64,AAAA"`) || strings.Contains(output, "Toast.jpg") {
+		t.Error("Print should use the image sources from the options")
+	}
+}
+
+func TestRendererInterface(t *testing.T) {
+	recipe, err := cooklang.ParseString(`---
+title: Pancakes
+images: pancakes.jpg
+calories: 350
+---
+Mix @milk{2%cup} with @flour{1%cup} and bake at 400°F.
+`)
+	if err != nil {
+		t.Fatalf("Failed to parse recipe: %v", err)
+	}
+
+	all := map[string]Renderer{
+		"cooklang": CooklangRenderer{},
+		"markdown": MarkdownRenderer{},
+		"html":     HTMLRenderer{},
+		"print":    PrintRenderer{},
+		"email":    EmailRenderer{},
+		"terminal": TerminalRenderer{NoColor: true},
+		"jsonld":   JSONLDRenderer{},
+		"voice":    VoiceRenderer{},
+		"ssml":     SSMLRenderer{},
+		"mermaid":  MermaidRenderer{},
+		"docx":     DocxRenderer{},
+		"site":     SiteMarkdownRenderer{},
+		"ics":      ICSRenderer{ServeAt: time.Date(2025, 12, 24, 19, 0, 0, 0, time.UTC)},
+	}
+	for name, renderer := range all {
+		output, err := renderer.Render(recipe, RendererOptions{Units: cooklang.UnitSystemMetric})
+		if err != nil {
+			t.Errorf("%s: Render failed: %v", name, err)
+			continue
+		}
+		if strings.Contains(output, "cup") {
+			t.Errorf("%s: expected metric quantities, got:\n%s", name, output)
+		}
+	}
+	if !strings.Contains(recipe.Render(), "@milk{2%cup}") {
+		t.Error("Render changed the recipe")
+	}
+
+	// Render's options replace the renderer's own
+	markdown := MarkdownRenderer{Options: RendererOptions{Locale: "de"}}
+	if output, _ := markdown.Render(recipe, RendererOptions{Locale: "fr"}); !strings.Contains(output, "## Ingrédients") {
+		t.Errorf("expected French labels, got:\n%s", output)
+	}
+
+	hidden := RendererOptions{NoImages: true, NoNutrition: true, Images: []string{"data:image/jpeg;base64,AAAA"}}
+	for name, renderer := range map[string]Renderer{"markdown": MarkdownRenderer{}, "html": HTMLRenderer{}, "print": PrintRenderer{}, "jsonld": JSONLDRenderer{}, "site": SiteMarkdownRenderer{}} {
+		output, _ := renderer.Render(recipe, hidden)
+		if strings.Contains(output, "pancakes.jpg") || strings.Contains(output, "data:image") {
+			t.Errorf("%s: expected no images, got:\n%s", name, output)
+		}
+		if strings.Contains(output, "350") {
+			t.Errorf("%s: expected no nutrition, got:\n%s", name, output)
+		}
+	}
+	if output := renderRecipe(t, MarkdownRenderer{}, recipe); !strings.Contains(output, "**Calories:** 350") || !strings.Contains(output, "pancakes.jpg") {
+		t.Errorf("expected images and nutrition by default, got:\n%s", output)
+	}
+
+	if _, err := (ICSRenderer{}).Render(recipe, RendererOptions{}); err == nil {
+		t.Error("expected an error without ServeAt")
+	}
+
+	// The deprecated wrappers match Render
+	voice, _ := VoiceRenderer{}.Render(recipe, RendererOptions{})
+	if old, _ := (VoiceRenderer{}).RenderRecipeJSON(recipe); old != voice {
+		t.Errorf("RenderRecipeJSON differs from Render:\n%s\n%s", old, voice)
+	}
+	page := &JSONLDOptions{URL: "https://example.com/pancakes"}
+	jsonLD, _ := JSONLDRenderer{Page: page}.Render(recipe, RendererOptions{})
+	if old, _ := (JSONLDRenderer{}).RenderRecipeJSON(recipe, page); old != jsonLD || !strings.Contains(jsonLD, page.URL) {
+		t.Errorf("RenderRecipeJSON differs from Render:\n%s\n%s", old, jsonLD)
+	}
+
+	// Every renderer's RenderRecipe returns the output as a string, like Render
+	for name, renderer := range map[string]cooklang.RecipeRenderer{
+		"html":   HTMLRenderer{},
+		"jsonld": JSONLDRenderer{Page: page},
+		"voice":  VoiceRenderer{},
+		"ssml":   SSMLRenderer{},
+	} {
+		output, err := renderer.RenderRecipe(recipe)
+		if err != nil || output == "" {
+			t.Errorf("%s: RenderRecipe = %q, %v", name, output, err)
+		}
+	}
+	if output, _ := (JSONLDRenderer{Page: page}).RenderRecipe(recipe); output != jsonLD {
+		t.Errorf("JSON-LD RenderRecipe differs from Render:\n%s\n%s", output, jsonLD)
+	}
+	if output, _ := (VoiceRenderer{}).RenderRecipe(recipe); output != voice {
+		t.Errorf("voice RenderRecipe differs from Render:\n%s\n%s", output, voice)
+	}
+}
+
+func TestLocaleStrings(t *testing.T) {
+	if got := LocaleStrings("da_DK").Ingredients; got != "Ingredienser" {
+		t.Errorf("Expected Danish labels for da_DK, got %q", got)
+	}
+	if got := LocaleStrings("xx").Ingredients; got != "Ingredients" {
+		t.Errorf("Expected English labels for an unknown locale, got %q", got)
+	}
+	if got := (RendererOptions{Locale: "xx"}).Language(); got != "en" {
+		t.Errorf("Expected English as the language of an unknown locale, got %q", got)
+	}
+	for _, locale := range Locales() {
+		labels := Translations[locale]
+		v := reflect.ValueOf(labels)
+		for i := 0; i < v.NumField(); i++ {
+			if v.Field(i).String() == "" {
+				t.Errorf("Translation %q is missing %s", locale, v.Type().Field(i).Name)
+			}
+		}
+		if callout := labels.reservedCallout("50 ml", 2); strings.Contains(callout, "%!") || !strings.Contains(callout, "50 ml") || !strings.Contains(callout, "2") {
+			t.Errorf("Translation %q has a broken Reserved format: %q", locale, callout)
+		}
+	}
+}
+
+func TestLocalizedDifficulty(t *testing.T) {
+	recipe, err := cooklang.ParseString("---\ntitle: Bread\ndifficulty: Advanced\n---\nBake the @dough{}.")
+	if err != nil {
+		t.Fatalf("Failed to parse recipe: %v", err)
+	}
+	if output, _ := (MarkdownRenderer{}).Render(recipe, RendererOptions{Locale: "de"}); !strings.Contains(output, "**Schwierigkeit:** Schwer") {
+		t.Errorf("expected the German name of the level, got:\n%s", output)
+	}
+	if output, _ := (HTMLRenderer{}).Render(recipe, RendererOptions{}); !strings.Contains(output, "<dd>Hard</dd>") {
+		t.Errorf("expected the English name of the level, got:\n%s", output)
+	}
+
+	recipe.Difficulty = "needs patience"
+	if output, _ := (MarkdownRenderer{}).Render(recipe, RendererOptions{Locale: "de"}); !strings.Contains(output, "**Schwierigkeit:** needs patience") {
+		t.Errorf("expected other difficulties as written, got:\n%s", output)
+	}
+}
+
+// renderRecipe renders a recipe with RenderRecipe, failing the test on errors.
+func renderRecipe(t *testing.T, renderer cooklang.RecipeRenderer, recipe *cooklang.Recipe) string {
+	t.Helper()
+	output, err := renderer.RenderRecipe(recipe)
+	if err != nil {
+		t.Fatalf("RenderRecipe failed: %v", err)
+	}
+	return output
+}
+
+// renderWith renders a recipe with Recipe.RenderWith and the default options.
+func renderWith(t *testing.T, recipe *cooklang.Recipe, renderer Renderer) string {
+	t.Helper()
+	output, err := recipe.RenderWith(Bind(renderer, RendererOptions{}))
+	if err != nil {
+		t.Fatalf("RenderWith failed: %v", err)
+	}
+	return output
+}
+
+func TestBind(t *testing.T) {
+	recipe, _ := cooklang.ParseString("Mix @bread flour{500%g} and @water{350%g}.")
+	bound := Bind(BakersPercentagesRenderer{}, RendererOptions{Units: cooklang.UnitSystemUS})
+	output, err := recipe.RenderWith(bound)
+	if err != nil || !strings.Contains(output, "**Hydration:** 70%") {
+		t.Errorf("RenderWith returned %q, %v", output, err)
+	}
+
+	recipe.SetRenderer(bound.(cooklang.RecipeRenderer))
+	if got := recipe.Render(); got != output {
+		t.Errorf("SetRenderer rendered %q, want %q", got, output)
+	}
+
+	// Errors are returned by RenderWith and render as nothing with SetRenderer
+	noFlour, _ := cooklang.ParseString("Boil @water{1%L}.")
+	if _, err := noFlour.RenderWith(bound); !errors.Is(err, cooklang.ErrNoFlour) {
+		t.Errorf("expected ErrNoFlour, got %v", err)
+	}
+	noFlour.SetRenderer(bound.(cooklang.RecipeRenderer))
+	if got := noFlour.Render(); got != "" {
+		t.Errorf("expected no output for a failed render, got %q", got)
+	}
+}
+
+func TestRendererErrors(t *testing.T) {
+	untitled, _ := cooklang.ParseString("Toast @bread{2%slices}.")
+	for name, renderer := range map[string]Renderer{"jsonld": JSONLDRenderer{}, "site": SiteMarkdownRenderer{}} {
+		if _, err := renderer.Render(untitled, RendererOptions{}); !errors.Is(err, ErrMissingTitle) {
+			t.Errorf("%s: expected ErrMissingTitle, got %v", name, err)
+		}
+	}
+	if output, err := (MarkdownRenderer{}).RenderRecipe(untitled); err != nil || !strings.Contains(output, "bread") {
+		t.Errorf("expected Markdown without a title, got %q, %v", output, err)
+	}
+
+	recipe, _ := cooklang.ParseString("---\ntitle: Toast\n---\nToast @bread{2%slices}.")
+	print := PrintRenderer{Options: RendererOptions{URL: "https://example.com/" + strings.Repeat("x", 200)}}
+	if output, err := print.RenderRecipe(recipe); !errors.Is(err, ErrEncoding) || output != "" {
+		t.Errorf("expected ErrEncoding for a URL too long for a QR code, got %q, %v", output, err)
+	}
+}
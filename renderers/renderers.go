@@ -5,21 +5,57 @@
 //   - MarkdownRenderer: Renders recipes as Markdown
 //   - HTMLRenderer: Renders recipes as HTML
 //   - PrintRenderer: Renders recipes as print-optimized HTML
+//   - EmailRenderer: Renders recipes as HTML with inline styles for email clients
+//   - TerminalRenderer: Renders recipes as text for terminals, with ANSI colors
 //   - JSONLDRenderer: Renders recipes as Schema.org JSON-LD for SEO
+//   - VoiceRenderer: Renders recipes as step-by-step JSON for voice assistants
+//   - SSMLRenderer: Renders recipes as SSML narration for text-to-speech
+//   - MermaidRenderer: Renders recipes as Mermaid flowcharts of steps, ingredients and cookware
+//   - DocxRenderer: Renders recipes as editable Word documents
+//   - ICSRenderer: Renders a recipe's cooking timeline as an iCalendar file
+//   - SiteMarkdownRenderer: Renders recipes as Hugo or Jekyll pages with YAML frontmatter
 //
 // Example usage:
 //
 //	recipe, _ := cooklang.ParseFile("recipe.cook")
 //
 //	// Use the default renderers
-//	html := renderers.Default.HTML.RenderRecipe(recipe)
-//	markdown := renderers.Default.Markdown.RenderRecipe(recipe)
+//	html, err := renderers.Default.HTML.RenderRecipe(recipe)
+//	markdown, err := renderers.Default.Markdown.RenderRecipe(recipe)
 //
 //	// For JSON-LD (SEO structured data)
-//	jsonLD, _ := renderers.Default.JSONLD.RenderRecipeJSON(recipe, nil)
+//	jsonLD, _ := renderers.Default.JSONLD.Render(recipe, renderers.RendererOptions{})
+//
+//	// Headings and labels in another language
+//	german := renderers.MarkdownRenderer{Options: renderers.RendererOptions{Locale: "de"}}
+//	markdown, err = german.RenderRecipe(recipe)
+//
+// Every renderer implements the Renderer interface, whose Render method takes the
+// RendererOptions per call, so the format can be chosen at run time:
+//
+//	var renderer renderers.Renderer = renderers.PrintRenderer{}
+//	page, err := renderer.Render(recipe, renderers.RendererOptions{Units: cooklang.UnitSystemMetric})
 package renderers
 
-import "github.com/hilli/cooklang"
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hilli/cooklang"
+)
+
+// Errors returned by the renderers, so that servers and batch jobs can tell why a
+// recipe failed to render, with errors.Is.
+var (
+	// ErrMissingTitle is returned for recipes without a title by formats that need
+	// one, such as JSON-LD and site pages.
+	ErrMissingTitle = errors.New("recipe has no title")
+	// ErrEncoding is wrapped by errors writing the output, such as JSON and YAML
+	// marshaling errors and a URL too long for a QR code.
+	ErrEncoding = errors.New("encoding failed")
+)
 
 // All default renderer instances for convenience
 var (
@@ -29,13 +65,27 @@ var (
 		Markdown MarkdownRenderer
 		HTML     HTMLRenderer
 		Print    PrintRenderer
+		Email    EmailRenderer
+		Terminal TerminalRenderer
 		JSONLD   JSONLDRenderer
+		Voice    VoiceRenderer
+		SSML     SSMLRenderer
+		Mermaid  MermaidRenderer
+		Docx     DocxRenderer
+		ICS      ICSRenderer
 	}{
 		Cooklang: CooklangRenderer{},
 		Markdown: MarkdownRenderer{},
 		HTML:     HTMLRenderer{},
 		Print:    PrintRenderer{},
+		Email:    EmailRenderer{},
+		Terminal: TerminalRenderer{},
 		JSONLD:   JSONLDRenderer{},
+		Voice:    VoiceRenderer{},
+		SSML:     SSMLRenderer{},
+		Mermaid:  MermaidRenderer{},
+		Docx:     DocxRenderer{},
+		ICS:      ICSRenderer{},
 	}
 )
 
@@ -58,3 +108,352 @@ func NewHTMLRenderer() cooklang.RecipeRenderer {
 func NewPrintRenderer() cooklang.RecipeRenderer {
 	return PrintRenderer{}
 }
+
+// Renderer renders a recipe in one output format. Every renderer in this package
+// implements it, so a format can be chosen at run time and given the same options.
+//
+// Example:
+//
+//	var renderer renderers.Renderer = renderers.HTMLRenderer{}
+//	html, err := renderer.Render(recipe, renderers.RendererOptions{
+//	    Locale: "de",
+//	    Units:  cooklang.UnitSystemMetric,
+//	})
+type Renderer interface {
+	// Render renders the recipe with opts, which replace the renderer's own
+	// Options field, if it has one. Renderers ignore options that do not apply to
+	// their format.
+	Render(recipe *cooklang.Recipe, opts RendererOptions) (string, error)
+}
+
+// Bind returns a cooklang.Renderer that renders with renderer and opts, for
+// Recipe.RenderWith. It also implements cooklang.RecipeRenderer for
+// Recipe.SetRenderer.
+//
+// Parameters:
+//   - renderer: Any renderer of this package
+//   - opts: The options to render with
+//
+// Returns:
+//   - cooklang.Renderer: The renderer with its options
+//
+// Example:
+//
+//	html, err := recipe.RenderWith(renderers.Bind(renderers.HTMLRenderer{}, renderers.RendererOptions{Locale: "de"}))
+func Bind(renderer Renderer, opts RendererOptions) cooklang.Renderer {
+	return boundRenderer{renderer: renderer, opts: opts}
+}
+
+// boundRenderer is a Renderer with its options, returned by Bind.
+type boundRenderer struct {
+	renderer Renderer
+	opts     RendererOptions
+}
+
+// Render renders the recipe with the bound options.
+func (br boundRenderer) Render(recipe *cooklang.Recipe) (string, error) {
+	return br.renderer.Render(recipe, br.opts)
+}
+
+// RenderRecipe renders the recipe with the bound options.
+func (br boundRenderer) RenderRecipe(recipe *cooklang.Recipe) (string, error) {
+	return br.renderer.Render(recipe, br.opts)
+}
+
+// RendererOptions holds settings shared by the renderers. The Markdown, HTML, Print
+// and Terminal renderers keep them in their Options field; Renderer.Render takes
+// them per call.
+//
+// Example:
+//
+//	renderer := renderers.HTMLRenderer{Options: renderers.RendererOptions{Locale: "de"}}
+//	html, _ := renderer.RenderRecipe(recipe) // "Zutaten", "Zubereitung", ...
+type RendererOptions struct {
+	Locale  string   // Language of the labels, e.g. "de" or "fr-CA" (default: English)
+	Strings *Strings // Labels overriding the locale's; empty fields keep the locale's label
+	// Units converts ingredient quantities and temperatures to a unit system before
+	// rendering; empty keeps them as written.
+	Units cooklang.UnitSystem
+	// PreferredUnits shows ingredients in the units they are listed under, such as
+	// butter in grams, after the conversion to Units.
+	PreferredUnits cooklang.PreferredUnits
+	// TemperatureScale shows temperatures in the reader's preferred scale
+	// (cooklang.Celsius or cooklang.Fahrenheit); empty keeps them as written.
+	TemperatureScale cooklang.TemperatureScale
+	// BothTemperatureScales shows temperatures in the steps and temperature metadata
+	// such as oven_temp in both scales, e.g. "180°C / 355°F", starting with
+	// TemperatureScale if set.
+	BothTemperatureScales bool
+	// Images are the image sources to show instead of recipe.Images, e.g. data URIs
+	// or rewritten paths from cooklang.ImageSources. The HTML renderer only shows
+	// images given here; the Print renderer falls back to recipe.Images.
+	Images []string
+	// NoImages leaves out the recipe's images, including Images.
+	NoImages bool
+	// NoNutrition leaves out nutrition metadata such as "calories" and "protein".
+	NoNutrition bool
+	// NutritionLabel adds a nutrition facts panel (see NutritionLabelRenderer) to the
+	// HTML and Print output of recipes with nutrition metadata, unless NoNutrition.
+	NutritionLabel bool
+	// BakersPercentages adds a table of baker's percentages and the hydration (see
+	// BakersPercentagesRenderer) to the Markdown, HTML and Print output of recipes
+	// with flour.
+	BakersPercentages bool
+	// Quantities writes ingredient quantities with cooklang.FormatQuantity, e.g. as
+	// Unicode fractions ("1½ cups"); nil keeps them as parsed ("1.5 cups").
+	Quantities *cooklang.FormatOptions
+	// QuantityNotes adds a note to the amounts in the Markdown, HTML and Print
+	// ingredient lists, such as the amount per serving; the zero value adds none.
+	QuantityNotes QuantityNote
+	// URL is the address of the recipe online. The Print renderer prints it as a QR
+	// code, so a printed recipe links back to the digital original.
+	URL string
+	// GroupIngredients splits the ingredient list into one sub-list per step or per
+	// section; the zero value lists all ingredients together.
+	GroupIngredients IngredientGrouping
+}
+
+// nutritionKeys are the metadata keys left out by RendererOptions.NoNutrition,
+// named after the Schema.org NutritionInformation properties.
+var nutritionKeys = map[string]bool{
+	"calories": true, "carbohydrates": true, "cholesterol": true, "fat": true,
+	"fiber": true, "protein": true, "saturated_fat": true, "sodium": true,
+	"sugar": true, "trans_fat": true, "unsaturated_fat": true,
+}
+
+// prepare returns the recipe to render: converted to Units and PreferredUnits and
+// without images when the options ask for it. The recipe itself is not changed.
+func (o RendererOptions) prepare(recipe *cooklang.Recipe) *cooklang.Recipe {
+	if o.Units != "" {
+		recipe = recipe.ConvertToSystem(o.Units)
+	}
+	if len(o.PreferredUnits) > 0 {
+		recipe = recipe.ApplyPreferredUnits(o.PreferredUnits)
+	} else if o.Units == "" && o.NoImages {
+		recipe = recipe.Clone()
+	}
+	if o.NoImages {
+		recipe.Images = nil
+		for step := range recipe.Steps() {
+			step.Images = nil
+		}
+	}
+	return recipe
+}
+
+// images returns the image sources to show for a recipe.
+func (o RendererOptions) images(recipe *cooklang.Recipe) []string {
+	if o.NoImages {
+		return nil
+	}
+	if len(o.Images) > 0 {
+		return o.Images
+	}
+	return recipe.Images
+}
+
+// nutritionLabel returns the nutrition facts panel to embed, or "" if the options
+// do not ask for one or the recipe has no nutrition data.
+func (o RendererOptions) nutritionLabel(recipe *cooklang.Recipe) string {
+	if !o.NutritionLabel {
+		return ""
+	}
+	panel, err := NutritionLabelRenderer{}.Render(recipe, o)
+	if err != nil {
+		return ""
+	}
+	return panel
+}
+
+// bakersTable returns the baker's percentages table to embed, as HTML or Markdown,
+// or "" if the options do not ask for one or the recipe has no flour.
+func (o RendererOptions) bakersTable(recipe *cooklang.Recipe, asHTML bool) string {
+	if !o.BakersPercentages {
+		return ""
+	}
+	table, err := BakersPercentagesRenderer{HTML: asHTML}.Render(recipe, RendererOptions{})
+	if err != nil {
+		return ""
+	}
+	return table
+}
+
+// metadataValue returns the value of an additional metadata field for display:
+// temperatures such as oven_temp in the preferred scales, other values as they are.
+func (o RendererOptions) metadataValue(key, value string) string {
+	if !cooklang.IsTemperatureMetadataKey(key) {
+		return value
+	}
+	t, err := cooklang.ParseTemperature(value)
+	if err != nil {
+		return value
+	}
+	return o.temperature(t)
+}
+
+// showMetadata reports whether an additional metadata field should be rendered.
+func (o RendererOptions) showMetadata(key string) bool {
+	return !o.NoNutrition || !nutritionKeys[strings.ToLower(key)]
+}
+
+// amount returns an ingredient's amount for display, prefixed with
+// cooklang.ApproximatePrefix when the quantity is an estimate (e.g., "≈2"). With
+// Quantities set, the bounds are written with cooklang.FormatQuantity.
+func (o RendererOptions) amount(ingredient *cooklang.Ingredient) string {
+	amount := ingredient.Quantity.String()
+	if o.Quantities != nil {
+		amount = cooklang.FormatQuantity(ingredient.Quantity.Min(), *o.Quantities)
+		if ingredient.Quantity.IsRange() {
+			amount += "-" + cooklang.FormatQuantity(ingredient.Quantity.Max(), *o.Quantities)
+		}
+	}
+	if ingredient.Approximate {
+		return cooklang.ApproximatePrefix + amount
+	}
+	return amount
+}
+
+// QuantityNote selects the note RendererOptions.QuantityNotes adds to ingredient
+// amounts.
+type QuantityNote string
+
+const (
+	NoteNone       QuantityNote = ""            // No note
+	NoteServings   QuantityNote = "servings"    // The servings the amount is for: "2 cups (for 4 servings)"
+	NoteOriginal   QuantityNote = "original"    // The amount before scaling: "2 cups (1 cup for 2 servings)"
+	NotePerServing QuantityNote = "per-serving" // The amount per serving: "2 cups (½ cup per serving)"
+)
+
+// quantityNote returns the note QuantityNotes adds to an ingredient's amount, or ""
+// if there is none: ingredients without an amount, unscaled ingredients for
+// NoteOriginal and recipes without servings for the other notes get none.
+func (o RendererOptions) quantityNote(recipe *cooklang.Recipe, ingredient *cooklang.Ingredient, labels Strings) string {
+	if !ingredient.Quantity.HasAmount() {
+		return ""
+	}
+	withUnit := func(quantity cooklang.Quantity) string {
+		scaled := *ingredient
+		scaled.Quantity = quantity
+		return strings.TrimSpace(o.amount(&scaled) + " " + ingredient.Unit)
+	}
+	servings := func(n float32) string {
+		return strconv.FormatFloat(float64(n), 'f', -1, 32)
+	}
+	switch o.QuantityNotes {
+	case NoteServings:
+		if recipe.Servings > 0 {
+			return fmt.Sprintf(labels.ForServings, servings(recipe.Servings))
+		}
+	case NoteOriginal:
+		original := ingredient.OriginalQuantity
+		if !original.HasAmount() || original.Equal(ingredient.Quantity) {
+			return ""
+		}
+		if recipe.OriginalServings > 0 {
+			return withUnit(original) + " " + fmt.Sprintf(labels.ForServings, servings(recipe.OriginalServings))
+		}
+		return fmt.Sprintf(labels.Originally, withUnit(original))
+	case NotePerServing:
+		if recipe.Servings > 0 && recipe.Servings != 1 && !ingredient.Fixed {
+			return fmt.Sprintf(labels.PerServing, withUnit(ingredient.Quantity.Scale(1/float64(recipe.Servings))))
+		}
+	}
+	return ""
+}
+
+// IngredientGrouping selects how the Markdown, HTML and Print renderers group the
+// ingredient list.
+type IngredientGrouping string
+
+const (
+	GroupAll       IngredientGrouping = ""        // One list with every ingredient
+	GroupByStep    IngredientGrouping = "step"    // One sub-list per step, e.g. "Step 3"
+	GroupBySection IngredientGrouping = "section" // One sub-list per recipe section
+)
+
+// ingredientGroup is a titled part of the ingredient list. The title is empty for
+// the ungrouped list and for ingredients before the first section.
+type ingredientGroup struct {
+	Title       string
+	Ingredients []*cooklang.Ingredient
+}
+
+// ingredientGroups splits the recipe's ingredients as set by GroupIngredients.
+// Steps are numbered as the renderer numbers them: from 1 in each section when
+// numberBySection is set, otherwise through the whole recipe. Notes are not
+// numbered, and steps and sections without ingredients are left out.
+func (o RendererOptions) ingredientGroups(recipe *cooklang.Recipe, labels Strings, numberBySection bool) []ingredientGroup {
+	if o.GroupIngredients != GroupByStep && o.GroupIngredients != GroupBySection {
+		ingredients := recipe.GetIngredients().Ingredients
+		if len(ingredients) == 0 {
+			return nil
+		}
+		return []ingredientGroup{{Ingredients: ingredients}}
+	}
+
+	var groups []ingredientGroup
+	section := ""
+	stepNum := 0
+	for step := range recipe.Steps() {
+		if s, ok := step.FirstComponent.(*cooklang.Section); ok {
+			section = s.Name
+			if numberBySection {
+				stepNum = 0
+			}
+			if o.GroupIngredients == GroupBySection {
+				groups = append(groups, ingredientGroup{Title: section})
+			}
+			if numberBySection && s.GetNext() == nil {
+				continue // A heading on its own is not a numbered step
+			}
+		}
+		if step.IsNote() {
+			continue
+		}
+		stepNum++
+
+		var ingredients []*cooklang.Ingredient
+		for component := range step.Components() {
+			if ingredient, ok := component.(*cooklang.Ingredient); ok {
+				ingredients = append(ingredients, ingredient)
+			}
+		}
+		if o.GroupIngredients == GroupBySection {
+			if len(groups) == 0 {
+				groups = append(groups, ingredientGroup{})
+			}
+			groups[len(groups)-1].Ingredients = append(groups[len(groups)-1].Ingredients, ingredients...)
+			continue
+		}
+		if len(ingredients) == 0 {
+			continue
+		}
+		title := labels.stepHeading(stepNum)
+		if numberBySection && section != "" {
+			title = section + ": " + title
+		}
+		groups = append(groups, ingredientGroup{Title: title, Ingredients: ingredients})
+	}
+
+	nonEmpty := groups[:0]
+	for _, group := range groups {
+		if len(group.Ingredients) > 0 {
+			nonEmpty = append(nonEmpty, group)
+		}
+	}
+	return nonEmpty
+}
+
+// reservedCallouts returns the callouts to show on each step that uses an output
+// reserved in an earlier step, e.g. "Use the reserved 240 ml tomato sauce from step 2".
+func reservedCallouts(recipe *cooklang.Recipe, labels Strings) map[*cooklang.Step][]string {
+	callouts := make(map[*cooklang.Step][]string)
+	for _, res := range recipe.GetReservations() {
+		if res.To == nil || res.ToStep <= res.FromStep {
+			continue
+		}
+		what := strings.TrimSpace(res.Amount + " " + res.Item)
+		callouts[res.To] = append(callouts[res.To], labels.reservedCallout(what, res.FromStep))
+	}
+	return callouts
+}
@@ -0,0 +1,220 @@
+package renderers
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/hilli/cooklang"
+)
+
+// SiteFlavor selects the static site generator the SiteMarkdownRenderer writes for.
+type SiteFlavor string
+
+const (
+	SiteHugo   SiteFlavor = "hugo"   // Hugo shortcodes: {{< ingredient name="flour" >}}
+	SiteJekyll SiteFlavor = "jekyll" // Jekyll includes: {% include ingredient.html name="flour" %}
+)
+
+// SiteMarkdownRenderer renders recipes as Markdown pages for static site generators
+// such as Hugo and Jekyll. The page starts with YAML frontmatter holding the recipe's
+// metadata, its ingredient and cookware lists, ISO 8601 durations and the Schema.org
+// JSON-LD (under "jsonld"), followed by the ingredient list and the instructions.
+// Ingredients are written as shortcodes (Hugo) or includes (Jekyll) named
+// "ingredient", with name, quantity, unit, note and optional parameters, so the
+// site's theme decides how they look.
+//
+// Example:
+//
+//	renderer := renderers.SiteMarkdownRenderer{Flavor: renderers.SiteHugo, Layout: "recipe"}
+//	page, err := renderer.Render(recipe, renderers.RendererOptions{})
+type SiteMarkdownRenderer struct {
+	Options RendererOptions // Locale and labels; the zero value renders English
+	Flavor  SiteFlavor      // Site generator to write for (default: SiteHugo)
+	Layout  string          // Frontmatter "layout" value, e.g. "recipe"; overrides the recipe's own (optional)
+	Page    *JSONLDOptions  // Page data for the JSON-LD, such as the URL (optional)
+}
+
+// Render renders the recipe as a site page with the given options.
+func (sr SiteMarkdownRenderer) Render(recipe *cooklang.Recipe, opts RendererOptions) (string, error) {
+	sr.Options = opts
+	return sr.RenderRecipe(recipe)
+}
+
+// RenderRecipe renders the recipe as a site page with the renderer's Options.
+func (sr SiteMarkdownRenderer) RenderRecipe(recipe *cooklang.Recipe) (string, error) {
+	flavor := sr.Flavor
+	if flavor == "" {
+		flavor = SiteHugo
+	}
+	if flavor != SiteHugo && flavor != SiteJekyll {
+		return "", fmt.Errorf("unknown site flavor: %s (use hugo or jekyll)", flavor)
+	}
+	recipe = sr.Options.prepare(recipe)
+	frontmatter, err := sr.frontmatter(recipe)
+	if err != nil {
+		return "", err
+	}
+
+	var result strings.Builder
+	result.WriteString("---\n")
+	result.Write(frontmatter)
+	result.WriteString("---\n\n")
+	md := MarkdownRenderer{Options: sr.Options, shortcodes: flavor}
+	md.renderBody(&result, recipe, sr.Options.labels())
+	return result.String(), nil
+}
+
+// siteStructuredKeys are the metadata keys written from the recipe's fields, or
+// left out with them.
+var siteStructuredKeys = map[string]bool{
+	"title": true, "date": true, "description": true, "author": true,
+	"cuisine": true, "difficulty": true, "servings": true, "tags": true, "images": true,
+	"image": true, "prep_time": true, "total_time": true,
+}
+
+// frontmatter returns the page's YAML frontmatter, without the "---" lines.
+func (sr SiteMarkdownRenderer) frontmatter(recipe *cooklang.Recipe) ([]byte, error) {
+	var fields yaml.MapSlice
+	add := func(key string, value interface{}) {
+		fields = append(fields, yaml.MapItem{Key: key, Value: value})
+	}
+	if recipe.Title != "" {
+		add("title", recipe.Title)
+	}
+	if !recipe.Date.IsZero() {
+		add("date", recipe.Date.Format("2006-01-02"))
+	}
+	if sr.Layout != "" {
+		add("layout", sr.Layout)
+	}
+	if recipe.Description != "" {
+		add("description", recipe.Description)
+	}
+	if recipe.Author != "" {
+		add("author", recipe.Author)
+	}
+	if recipe.Cuisine != "" {
+		add("cuisine", recipe.Cuisine)
+	}
+	if recipe.Difficulty != "" {
+		add("difficulty", recipe.Difficulty)
+	}
+	if recipe.Servings > 0 {
+		if recipe.Servings == float32(int(recipe.Servings)) {
+			add("servings", int(recipe.Servings))
+		} else {
+			add("servings", recipe.Servings)
+		}
+	}
+	if len(recipe.Tags) > 0 {
+		add("tags", recipe.Tags)
+	}
+	if images := sr.Options.images(recipe); len(images) > 0 {
+		add("images", images)
+	}
+	if recipe.PrepTime != "" {
+		add("prep_time", recipe.PrepTime)
+	}
+	if recipe.TotalTime != "" {
+		add("total_time", recipe.TotalTime)
+	}
+
+	// Additional metadata, in a stable order
+	keys := make([]string, 0, len(recipe.Metadata))
+	for key := range recipe.Metadata {
+		if !siteStructuredKeys[key] && (key != "layout" || sr.Layout == "") && sr.Options.showMetadata(key) {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		add(key, sr.Options.metadataValue(key, recipe.Metadata[key]))
+	}
+
+	// Generated fields
+	var ingredients []string
+	for _, ingredient := range recipe.GetIngredients().Ingredients {
+		ingredients = append(ingredients, sr.ingredientLine(ingredient))
+	}
+	if len(ingredients) > 0 {
+		add("ingredients", ingredients)
+	}
+	var cookware []string
+	for _, item := range recipe.GetCookware() {
+		if !slices.Contains(cookware, item.Name) {
+			cookware = append(cookware, item.Name)
+		}
+	}
+	if len(cookware) > 0 {
+		add("cookware", cookware)
+	}
+	durations := map[string]string{"prep_time": recipe.PrepTime, "total_time": recipe.TotalTime}
+	for _, key := range keys {
+		if strings.HasSuffix(key, "_time") {
+			durations[key] = recipe.Metadata[key]
+		}
+	}
+	for _, key := range slices.Sorted(maps.Keys(durations)) {
+		if iso := ParseDurationToISO8601(durations[key]); durations[key] != "" && iso != "" {
+			add(key+"_iso", iso)
+		}
+	}
+	jsonLD, err := JSONLDRenderer{Page: sr.Page}.Render(recipe, RendererOptions{NoImages: sr.Options.NoImages, NoNutrition: sr.Options.NoNutrition})
+	if err != nil {
+		return nil, err
+	}
+	add("jsonld", jsonLD)
+
+	out, err := yaml.MarshalWithOptions(fields, yaml.UseLiteralStyleIfMultiline(true))
+	if err != nil {
+		return nil, fmt.Errorf("%w: frontmatter: %w", ErrEncoding, err)
+	}
+	return out, nil
+}
+
+// ingredientLine returns an ingredient as a line of the frontmatter ingredient
+// list, e.g. "200 g flour".
+func (sr SiteMarkdownRenderer) ingredientLine(ingredient *cooklang.Ingredient) string {
+	parts := []string{}
+	if ingredient.Quantity.HasAmount() {
+		parts = append(parts, sr.Options.amount(ingredient))
+	}
+	if ingredient.Unit != "" {
+		parts = append(parts, ingredient.Unit)
+	}
+	parts = append(parts, ingredient.Name)
+	return strings.Join(parts, " ")
+}
+
+// ingredientShortcode returns an ingredient as a shortcode or include of the
+// renderer's site flavor.
+func (mr MarkdownRenderer) ingredientShortcode(ingredient *cooklang.Ingredient) string {
+	params := []string{shortcodeParam("name", ingredient.Name)}
+	if ingredient.Quantity.HasAmount() {
+		params = append(params, shortcodeParam("quantity", mr.Options.amount(ingredient)))
+	} else if ingredient.Quantity.IsSome() {
+		params = append(params, shortcodeParam("quantity", mr.Options.labels().Some))
+	}
+	if ingredient.Unit != "" {
+		params = append(params, shortcodeParam("unit", ingredient.Unit))
+	}
+	if ingredient.Annotation != "" {
+		params = append(params, shortcodeParam("note", ingredient.Annotation))
+	}
+	if ingredient.Optional {
+		params = append(params, `optional="true"`)
+	}
+	if mr.shortcodes == SiteJekyll {
+		return "{% include ingredient.html " + strings.Join(params, " ") + " %}"
+	}
+	return "{{< ingredient " + strings.Join(params, " ") + " >}}"
+}
+
+// shortcodeParam returns a quoted shortcode parameter. Double quotes in the value
+// are written as &quot; so the value cannot end the parameter early.
+func shortcodeParam(name, value string) string {
+	return name + `="` + strings.ReplaceAll(value, `"`, "&quot;") + `"`
+}
@@ -0,0 +1,95 @@
+package renderers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/goccy/go-yaml"
+	"github.com/hilli/cooklang"
+)
+
+func TestSiteMarkdownRenderer(t *testing.T) {
+	recipe, err := cooklang.ParseString(`---
+title: Pancakes
+date: 2024-03-01
+prep_time: 15 minutes
+cook_time: 1 hour
+tags: [breakfast, sweet]
+servings: 4
+---
+Mix @flour{200%g} with @milk{300%ml}(cold) and @salt in a #bowl{}.
+
+Fry for ~{3%minutes}.
+`)
+	if err != nil {
+		t.Fatalf("Failed to parse recipe: %v", err)
+	}
+
+	output, err := SiteMarkdownRenderer{Layout: "recipe"}.Render(recipe, RendererOptions{})
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	frontmatter, body, ok := strings.Cut(strings.TrimPrefix(output, "---\n"), "\n---\n")
+	if !strings.HasPrefix(output, "---\n") || !ok {
+		t.Fatalf("expected YAML frontmatter, got:\n%s", output)
+	}
+	var fields struct {
+		Title       string   `yaml:"title"`
+		Date        string   `yaml:"date"`
+		Layout      string   `yaml:"layout"`
+		Servings    float64  `yaml:"servings"`
+		Tags        []string `yaml:"tags"`
+		CookTime    string   `yaml:"cook_time"`
+		Ingredients []string `yaml:"ingredients"`
+		Cookware    []string `yaml:"cookware"`
+		PrepISO     string   `yaml:"prep_time_iso"`
+		CookISO     string   `yaml:"cook_time_iso"`
+		JSONLD      string   `yaml:"jsonld"`
+	}
+	if err := yaml.Unmarshal([]byte(frontmatter), &fields); err != nil {
+		t.Fatalf("frontmatter is not valid YAML: %v\n%s", err, frontmatter)
+	}
+	if fields.Title != "Pancakes" || fields.Date != "2024-03-01" || fields.Layout != "recipe" || fields.Servings != 4 {
+		t.Errorf("unexpected frontmatter fields: %+v", fields)
+	}
+	if strings.Join(fields.Tags, ",") != "breakfast,sweet" || fields.CookTime != "1 hour" {
+		t.Errorf("expected tags and metadata to be carried over, got: %+v", fields)
+	}
+	if strings.Join(fields.Ingredients, ",") != "200 g flour,300 ml milk,salt" || strings.Join(fields.Cookware, ",") != "bowl" {
+		t.Errorf("unexpected ingredients or cookware: %v %v", fields.Ingredients, fields.Cookware)
+	}
+	if fields.PrepISO != "PT15M" || fields.CookISO != "PT1H" {
+		t.Errorf("expected ISO 8601 durations, got %q and %q", fields.PrepISO, fields.CookISO)
+	}
+	if !strings.Contains(fields.JSONLD, `"@type": "Recipe"`) {
+		t.Errorf("expected JSON-LD in the frontmatter, got: %s", fields.JSONLD)
+	}
+
+	for _, want := range []string{
+		"## Ingredients",
+		`- {{< ingredient name="flour" quantity="200" unit="g" >}}`,
+		`1. Mix {{< ingredient name="flour" quantity="200" unit="g" >}} with {{< ingredient name="milk" quantity="300" unit="ml" note="cold" >}}`,
+		`{{< ingredient name="salt" quantity="some" >}} in a *bowl*.`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected body to contain %q, got:\n%s", want, body)
+		}
+	}
+	if strings.Contains(body, "# Pancakes") {
+		t.Errorf("expected the title only in the frontmatter, got:\n%s", body)
+	}
+
+	jekyll, _ := SiteMarkdownRenderer{Flavor: SiteJekyll}.Render(recipe, RendererOptions{})
+	if !strings.Contains(jekyll, `{% include ingredient.html name="milk" quantity="300" unit="ml" note="cold" %}`) {
+		t.Errorf("expected Jekyll includes, got:\n%s", jekyll)
+	}
+	if _, err := (SiteMarkdownRenderer{Flavor: "gatsby"}).Render(recipe, RendererOptions{}); err == nil {
+		t.Error("expected an error for an unknown flavor")
+	}
+}
+
+func TestShortcodeParamEscapesQuotes(t *testing.T) {
+	if got := shortcodeParam("note", `the "good" one`); got != `note="the &quot;good&quot; one"` {
+		t.Errorf("shortcodeParam = %s", got)
+	}
+}
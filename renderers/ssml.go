@@ -0,0 +1,70 @@
+package renderers
+
+import (
+	"strings"
+
+	"github.com/hilli/cooklang"
+)
+
+// SSMLRenderer renders a recipe as one SSML document for guided cooking, to feed
+// text-to-speech engines and voice assistants that read a whole recipe aloud. It
+// narrates the intro, each section and step, and the outro from the
+// VoiceRenderer structure: ingredients are called out with their amounts ("half a
+// cup of flour"), and steps with timers pause before offering to start them. For
+// step-by-step navigation, use the "ssml" field of each VoiceRenderer step instead.
+//
+// Example:
+//
+//	recipe, _ := cooklang.ParseFile("pancakes.cook")
+//	ssml, _ := renderers.SSMLRenderer{}.RenderRecipe(recipe)
+//	// <speak>
+//	// <p>Let's make Pancakes. ...</p>
+//	// <p>Step 1. Whisk <emphasis level="moderate">half a cup of flour</emphasis> ...</p>
+//	// ...
+//	// </speak>
+type SSMLRenderer struct{}
+
+// RenderRecipe renders the recipe as an SSML document.
+//
+// Parameters:
+//   - recipe: The parsed Cooklang recipe to render
+//
+// Returns:
+//   - string: The SSML document
+//   - error: Always nil; for the Renderer and cooklang.RecipeRenderer interfaces
+func (sr SSMLRenderer) RenderRecipe(recipe *cooklang.Recipe) (string, error) {
+	voice := VoiceRenderer{}.BuildRecipe(recipe)
+
+	var b strings.Builder
+	b.WriteString("<speak>\n")
+	b.WriteString("<p>" + ssmlEscaper.Replace(voice.Intro) + "</p>\n")
+	section := ""
+	for _, step := range voice.Steps {
+		if step.Section != section {
+			section = step.Section
+			b.WriteString(`<p><emphasis level="strong">` + ssmlEscaper.Replace(section) + "</emphasis></p>\n")
+		}
+		speech := strings.TrimSuffix(strings.TrimPrefix(step.SSML, "<speak>"), "</speak>")
+		b.WriteString("<p>" + speech + "</p>\n")
+		if len(step.Timers) > 0 {
+			b.WriteString(`<break strength="x-strong"/>` + "\n")
+		}
+	}
+	b.WriteString("<p>" + ssmlEscaper.Replace(voice.Outro) + "</p>\n")
+	b.WriteString("</speak>\n")
+	return b.String(), nil
+}
+
+// Render renders the recipe as an SSML document. Of the options, only Units,
+// PreferredUnits and NoImages apply.
+//
+// Parameters:
+//   - recipe: The parsed Cooklang recipe to render
+//   - opts: The renderer options
+//
+// Returns:
+//   - string: The SSML document
+//   - error: Always nil
+func (sr SSMLRenderer) Render(recipe *cooklang.Recipe, opts RendererOptions) (string, error) {
+	return sr.RenderRecipe(opts.prepare(recipe))
+}
@@ -0,0 +1,296 @@
+package renderers
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/hilli/cooklang"
+)
+
+// Names of the templates HTMLRenderer and PrintRenderer execute from their Template
+// set, so one directory can hold a theme for both.
+const (
+	HTMLTemplateName  = "recipe.html"
+	PrintTemplateName = "print.html"
+)
+
+// TemplateData is the context user templates are executed with. Text fields are
+// plain text, escaped by html/template; TemplateStep.HTML is the step as the built-in
+// HTML renderer marks it up. Steps are numbered from 1 in each section, and steps
+// that only start a section are left out.
+//
+// Example template:
+//
+//	<h1>{{.Title}}</h1>
+//	<ul>{{range .Ingredients}}<li>{{.Amount}} {{.Unit}} {{.Name}}</li>{{end}}</ul>
+//	<ol>{{range .Steps}}{{if not .Note}}<li>{{.HTML}}</li>{{end}}{{end}}</ol>
+type TemplateData struct {
+	Recipe           *cooklang.Recipe          // The recipe, after unit conversion
+	Title            string                    // The title, or the Recipe label for untitled recipes
+	Language         string                    // Language code for the lang attribute, e.g. "de"
+	Labels           Strings                   // Headings and labels in the options' locale
+	Images           []string                  // Image sources to show (RendererOptions.Images or recipe.Images)
+	Metadata         []TemplateField           // Description, cuisine, times, author, ... and other metadata
+	Ingredients      []TemplateIngredient      // All ingredients in order of appearance
+	IngredientGroups []TemplateIngredientGroup // Ingredients as grouped by RendererOptions.GroupIngredients
+	Steps            []TemplateStep            // Steps and notes in order
+	URL              string                    // RendererOptions.URL
+	QRCode           template.HTML             // URL as an SVG QR code; empty without a URL
+}
+
+// TemplateField is a labelled recipe information field, e.g. "Prep Time: 10 minutes".
+type TemplateField struct {
+	Key   string // Metadata key, e.g. "prep_time"
+	Label string // Label in the options' locale, e.g. "Prep Time"
+	Value string
+}
+
+// TemplateIngredient is an ingredient with its amount formatted for display.
+type TemplateIngredient struct {
+	Name       string
+	Amount     string // e.g. "1½", "≈2" or the Some label; empty without a quantity
+	Unit       string
+	Annotation string
+	Optional   bool
+	Note       string // RendererOptions.QuantityNotes note, e.g. "1 cup for 2 servings"
+	Ingredient *cooklang.Ingredient
+}
+
+// TemplateIngredientGroup is a titled part of the ingredient list; the title is
+// empty when the ingredients are not grouped.
+type TemplateIngredientGroup struct {
+	Title       string
+	Ingredients []TemplateIngredient
+}
+
+// TemplateStep is a step or note of the recipe.
+type TemplateStep struct {
+	Number     int           // Step number as the renderer numbers it; 0 for notes
+	Section    string        // Section the step belongs to
+	NewSection bool          // True for the first step of a section
+	Note       bool          // True for notes, whose Text is the note
+	Text       string        // The step as plain text
+	HTML       template.HTML // The step as marked up by the HTML renderer
+	Callouts   []string      // Reserved outputs used by the step
+	Images     []string      // The step's images (cooklang.Step.Images)
+	Step       *cooklang.Step
+}
+
+// ParseTemplates parses the *.html and *.tmpl files at the root of fsys, such as
+// os.DirFS(dir) or an embed.FS, into one template set. The set should define
+// HTMLTemplateName, PrintTemplateName or both; other files can hold shared
+// templates ({{define "header"}}...{{end}}).
+//
+// Parameters:
+//   - fsys: The file system with the templates
+//
+// Returns:
+//   - *template.Template: The template set, for HTMLRenderer.Template or PrintRenderer.Template
+//   - error: An error if there are no templates or one does not parse
+//
+// Example:
+//
+//	//go:embed theme/*.html
+//	var theme embed.FS
+//
+//	sub, _ := fs.Sub(theme, "theme")
+//	tmpl, err := renderers.ParseTemplates(sub)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	html, err := renderers.HTMLRenderer{Template: tmpl}.Render(recipe, renderers.RendererOptions{})
+func ParseTemplates(fsys fs.FS) (*template.Template, error) {
+	var files []string
+	for _, pattern := range []string{"*.html", "*.tmpl"} {
+		matches, err := fs.Glob(fsys, pattern)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, matches...)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no templates (*.html, *.tmpl) found")
+	}
+	sort.Strings(files)
+	return template.New(files[0]).ParseFS(fsys, files...)
+}
+
+// ParseTemplateDir parses the *.html and *.tmpl files in a directory, like
+// ParseTemplates.
+//
+// Parameters:
+//   - dir: The template directory
+//
+// Returns:
+//   - *template.Template: The template set
+//   - error: An error if the directory has no templates or one does not parse
+//
+// Example:
+//
+//	tmpl, err := renderers.ParseTemplateDir("my-theme")
+func ParseTemplateDir(dir string) (*template.Template, error) {
+	tmpl, err := ParseTemplates(os.DirFS(dir))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", dir, err)
+	}
+	return tmpl, nil
+}
+
+// executeTemplate renders the recipe with the named template of a set.
+func executeTemplate(tmpl *template.Template, name string, data TemplateData) (string, error) {
+	if tmpl.Lookup(name) == nil {
+		return "", fmt.Errorf("template %s is not defined", name)
+	}
+	var out bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&out, name, data); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
+
+// templateData builds the template context.
+func (o RendererOptions) templateData(recipe *cooklang.Recipe) TemplateData {
+	labels := o.labels()
+	recipe = o.prepare(recipe)
+	data := TemplateData{
+		Recipe:   recipe,
+		Title:    recipe.Title,
+		Language: o.Language(),
+		Labels:   labels,
+		Images:   o.images(recipe),
+		Metadata: o.templateFields(recipe, labels),
+		URL:      o.URL,
+	}
+	if data.Title == "" {
+		data.Title = labels.Recipe
+	}
+	if o.URL != "" {
+		if qr, err := encodeQR(o.URL); err == nil {
+			data.QRCode = template.HTML(qr.SVG())
+		}
+	}
+
+	for _, ingredient := range recipe.GetIngredients().Ingredients {
+		data.Ingredients = append(data.Ingredients, o.templateIngredient(recipe, ingredient, labels))
+	}
+	for _, group := range o.ingredientGroups(recipe, labels, true) {
+		templateGroup := TemplateIngredientGroup{Title: group.Title}
+		for _, ingredient := range group.Ingredients {
+			templateGroup.Ingredients = append(templateGroup.Ingredients, o.templateIngredient(recipe, ingredient, labels))
+		}
+		data.IngredientGroups = append(data.IngredientGroups, templateGroup)
+	}
+
+	callouts := reservedCallouts(recipe, labels)
+	markup := HTMLRenderer{Options: o}
+	section, newSection := "", false
+	stepNum := 0
+	for step := range recipe.Steps() {
+		if note, ok := step.FirstComponent.(*cooklang.Note); ok {
+			data.Steps = append(data.Steps, TemplateStep{Section: section, Note: true, Text: note.Text, HTML: template.HTML(template.HTMLEscapeString(note.Text)), Step: step})
+			continue
+		}
+		if s, ok := step.FirstComponent.(*cooklang.Section); ok {
+			section, newSection, stepNum = s.Name, true, 0
+			if s.GetNext() == nil {
+				continue
+			}
+		}
+		stepNum++
+
+		var text, stepHTML strings.Builder
+		for component := range step.Components() {
+			switch comp := component.(type) {
+			case *cooklang.Section, *cooklang.Note:
+				continue
+			case *cooklang.Instruction:
+				text.WriteString(comp.Text)
+			case *cooklang.Ingredient:
+				text.WriteString(comp.Name)
+			case *cooklang.Cookware:
+				text.WriteString(comp.Name)
+			case *cooklang.Timer:
+				text.WriteString(comp.String())
+			case *cooklang.Temperature:
+				text.WriteString(o.temperature(comp))
+			}
+			markup.renderComponent(&stepHTML, component, labels)
+		}
+		data.Steps = append(data.Steps, TemplateStep{
+			Number:     stepNum,
+			Section:    section,
+			NewSection: newSection,
+			Text:       strings.TrimSpace(text.String()),
+			HTML:       template.HTML(strings.TrimSpace(stepHTML.String())),
+			Callouts:   callouts[step],
+			Images:     step.Images,
+			Step:       step,
+		})
+		newSection = false
+	}
+	return data
+}
+
+// templateIngredient formats an ingredient for templates.
+func (o RendererOptions) templateIngredient(recipe *cooklang.Recipe, ingredient *cooklang.Ingredient, labels Strings) TemplateIngredient {
+	item := TemplateIngredient{
+		Name:       ingredient.Name,
+		Unit:       ingredient.Unit,
+		Annotation: ingredient.Annotation,
+		Optional:   ingredient.Optional,
+		Note:       o.quantityNote(recipe, ingredient, labels),
+		Ingredient: ingredient,
+	}
+	if ingredient.Quantity.HasAmount() {
+		item.Amount = o.amount(ingredient)
+	} else if ingredient.Quantity.IsSome() {
+		item.Amount = labels.Some
+	}
+	return item
+}
+
+// templateFields lists the recipe information in the order the built-in renderers
+// show it, followed by the other metadata sorted by key.
+func (o RendererOptions) templateFields(recipe *cooklang.Recipe, labels Strings) []TemplateField {
+	var fields []TemplateField
+	add := func(key, label, value string) {
+		if value != "" {
+			fields = append(fields, TemplateField{Key: key, Label: label, Value: value})
+		}
+	}
+	add("description", labels.Description, recipe.Description)
+	add("cuisine", labels.Cuisine, recipe.Cuisine)
+	if !recipe.Date.IsZero() {
+		add("date", labels.Date, recipe.Date.Format("2006-01-02"))
+	}
+	add("difficulty", labels.Difficulty, labels.difficulty(recipe.Difficulty))
+	add("prep_time", labels.PrepTime, recipe.PrepTime)
+	add("total_time", labels.TotalTime, recipe.TotalTime)
+	add("author", labels.Author, recipe.Author)
+	if recipe.Servings > 0 {
+		add("servings", labels.Servings, fmt.Sprintf("%g", recipe.Servings))
+	}
+	add("tags", labels.Tags, strings.Join(recipe.Tags, ", "))
+
+	var keys []string
+	for key := range recipe.Metadata {
+		switch key {
+		case "title", "cuisine", "date", "description", "difficulty", "prep_time", "total_time",
+			"author", "servings", "tags", "images", "image", "units", cooklang.StepImagesKey:
+			continue
+		}
+		if o.showMetadata(key) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		add(key, simpleTitle(strings.ReplaceAll(key, "_", " ")), o.metadataValue(key, recipe.Metadata[key]))
+	}
+	return fields
+}
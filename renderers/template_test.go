@@ -0,0 +1,106 @@
+package renderers
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/hilli/cooklang"
+)
+
+var testTheme = fstest.MapFS{
+	"recipe.html": {Data: []byte(`{{template "head" .}}<h1>{{.Title}}</h1>
+{{range .Metadata}}<p class="{{.Key}}">{{.Label}}: {{.Value}}</p>
+{{end}}{{range .Ingredients}}<li>{{.Amount}} {{.Unit}} {{.Name}}{{if .Optional}} ({{$.Labels.Optional}}){{end}}</li>
+{{end}}{{range .Steps}}{{if .NewSection}}<h2>{{.Section}}</h2>
+{{end}}{{if .Note}}<aside>{{.Text}}</aside>{{else}}<p>{{.Number}}. {{.Text}} | {{.HTML}}</p>{{end}}
+{{end}}`)},
+	"print.html":    {Data: []byte(`{{template "head" .}}<h1 class="print">{{.Title}}</h1>`)},
+	"partials.tmpl": {Data: []byte(`{{define "head"}}<html lang="{{.Language}}">{{end}}`)},
+}
+
+func TestTemplates(t *testing.T) {
+	recipe, err := cooklang.ParseString(`---
+title: Fish & Chips
+prep_time: 20 minutes
+---
+== Batter ==
+
+Whisk @flour{200%g} with @beer{250%ml} and @salt{}(optional).
+
+> Cold beer makes a lighter batter.
+
+== Frying ==
+
+Fry the @fish{2} for ~{4%minutes}.
+`)
+	if err != nil {
+		t.Fatalf("Failed to parse recipe: %v", err)
+	}
+	tmpl, err := ParseTemplates(testTheme)
+	if err != nil {
+		t.Fatalf("ParseTemplates failed: %v", err)
+	}
+
+	output, err := HTMLRenderer{Template: tmpl}.Render(recipe, RendererOptions{Locale: "de"})
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	for _, expected := range []string{
+		`<html lang="de"><h1>Fish &amp; Chips</h1>`,
+		`<p class="prep_time">Vorbereitungszeit: 20 minutes</p>`,
+		`<li>200 g flour</li>`,
+		`<h2>Batter</h2>`,
+		`<p>1. Whisk flour with beer and salt. | Whisk <span class="ingredient">flour</span>`,
+		`<aside>Cold beer makes a lighter batter.</aside>`,
+		`<h2>Frying</h2>`,
+		`<p>1. Fry the fish for 4 minutes.`,
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("expected %q in output:\n%s", expected, output)
+		}
+	}
+
+	output, err = PrintRenderer{Template: tmpl}.Render(recipe, RendererOptions{})
+	if err != nil || output != `<html lang="en"><h1 class="print">Fish &amp; Chips</h1>` {
+		t.Errorf("unexpected print output %q (err %v)", output, err)
+	}
+
+	// Without the renderer's template, Render and RenderRecipe fail
+	onlyPrint, err := ParseTemplates(fstest.MapFS{"print.html": testTheme["print.html"], "partials.tmpl": testTheme["partials.tmpl"]})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := (HTMLRenderer{Template: onlyPrint}).Render(recipe, RendererOptions{}); err == nil || !strings.Contains(err.Error(), "recipe.html") {
+		t.Errorf("expected an error for the missing template, got %v", err)
+	}
+	if output, err := (HTMLRenderer{Template: onlyPrint}).RenderRecipe(recipe); err == nil || output != "" {
+		t.Errorf("expected an error for the missing template, got %q, %v", output, err)
+	}
+}
+
+func TestParseTemplateDir(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := ParseTemplateDir(dir); err == nil {
+		t.Error("expected an error for a directory without templates")
+	}
+	if err := os.WriteFile(filepath.Join(dir, "recipe.html"), []byte(`{{.Title`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ParseTemplateDir(dir); err == nil {
+		t.Error("expected a parse error")
+	}
+	if err := os.WriteFile(filepath.Join(dir, "recipe.html"), []byte(`<h1>{{.Title}}</h1>`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tmpl, err := ParseTemplateDir(dir)
+	if err != nil {
+		t.Fatalf("ParseTemplateDir failed: %v", err)
+	}
+	recipe := &cooklang.Recipe{}
+	if output := renderRecipe(t, HTMLRenderer{Template: tmpl}, recipe); output != "<h1>Recipe</h1>" {
+		t.Errorf("expected the Recipe label for untitled recipes, got %q", output)
+	}
+}
@@ -0,0 +1,307 @@
+package renderers
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/hilli/cooklang"
+)
+
+// ANSI escape codes used by TerminalRenderer
+const (
+	ansiReset     = "\033[0m"
+	ansiBold      = "\033[1m"
+	ansiDim       = "\033[2m"
+	ansiItalic    = "\033[3m"
+	ansiUnderline = "\033[4m"
+	ansiGreen     = "\033[32m"
+	ansiYellow    = "\033[33m"
+	ansiCyan      = "\033[36m"
+)
+
+// ansiPattern matches the escape codes TerminalRenderer writes.
+var ansiPattern = regexp.MustCompile("\033\\[[0-9;]*m")
+
+// defaultTerminalWidth is the line width used when TerminalRenderer.Width is not set.
+const defaultTerminalWidth = 80
+
+// TerminalRenderer renders recipes as plain text for terminals: the metadata in a
+// box, an aligned ingredient list, the cookware, and numbered steps wrapped to the
+// line width. Ingredient names are colored and quantities bold, unless NoColor is
+// set.
+//
+// Example:
+//
+//	renderer := renderers.TerminalRenderer{NoColor: os.Getenv("NO_COLOR") != ""}
+//	output, _ := renderer.RenderRecipe(recipe)
+//	fmt.Print(output)
+type TerminalRenderer struct {
+	Options RendererOptions // Locale and labels; the zero value renders English
+	NoColor bool            // Plain text without ANSI escape codes
+	Width   int             // Line width to wrap at (default 80)
+}
+
+// Render renders the recipe for display in a terminal with the given options.
+func (tr TerminalRenderer) Render(recipe *cooklang.Recipe, opts RendererOptions) (string, error) {
+	tr.Options = opts
+	return tr.RenderRecipe(recipe)
+}
+
+// RenderRecipe renders the recipe for display in a terminal.
+func (tr TerminalRenderer) RenderRecipe(recipe *cooklang.Recipe) (string, error) {
+	var result strings.Builder
+	labels := tr.Options.labels()
+	recipe = tr.Options.prepare(recipe)
+
+	tr.renderHeader(&result, recipe, labels)
+
+	ingredients := recipe.GetIngredients().Ingredients
+	if len(ingredients) > 0 {
+		fmt.Fprintf(&result, "%s\n", tr.style(ansiBold, labels.Ingredients))
+		amounts := make([]string, len(ingredients))
+		amountWidth := 0
+		for i, ingredient := range ingredients {
+			amounts[i] = tr.ingredientAmount(ingredient, labels)
+			amountWidth = max(amountWidth, utf8.RuneCountInString(amounts[i]))
+		}
+		for i, ingredient := range ingredients {
+			result.WriteString("  • ")
+			if amountWidth > 0 {
+				padding := strings.Repeat(" ", amountWidth-utf8.RuneCountInString(amounts[i]))
+				result.WriteString(tr.style(ansiBold, amounts[i]) + padding + "  ")
+			}
+			result.WriteString(tr.style(ansiGreen, ingredient.Name))
+			if ingredient.Annotation != "" {
+				result.WriteString(tr.style(ansiDim, ", "+ingredient.Annotation))
+			}
+			if ingredient.Optional {
+				result.WriteString(tr.style(ansiDim, " ("+labels.Optional+")"))
+			}
+			result.WriteString("\n")
+		}
+		result.WriteString("\n")
+	}
+
+	cookware := recipe.GetCookware()
+	if len(cookware) > 0 {
+		fmt.Fprintf(&result, "%s\n", tr.style(ansiBold, labels.Cookware))
+		for _, item := range cookware {
+			result.WriteString("  • " + tr.style(ansiCyan, item.Name))
+			if quantity := item.QuantityLabel(); quantity != "" {
+				fmt.Fprintf(&result, " (%s)", quantity)
+			}
+			if item.Annotation != "" {
+				result.WriteString(tr.style(ansiDim, ", "+item.Annotation))
+			}
+			result.WriteString("\n")
+		}
+		result.WriteString("\n")
+	}
+
+	fmt.Fprintf(&result, "%s\n", tr.style(ansiBold, labels.Instructions))
+	callouts := reservedCallouts(recipe, labels)
+	numbers := recipe.StepNumbers()
+	for step := range recipe.Steps() {
+		var text strings.Builder
+		for component := range step.Components() {
+			switch comp := component.(type) {
+			case *cooklang.Section:
+				if comp.Name != "" {
+					fmt.Fprintf(&result, "\n  %s\n", tr.style(ansiBold+ansiUnderline, comp.Name))
+				}
+			case *cooklang.Note:
+				result.WriteString(tr.wrap(tr.style(ansiDim+ansiItalic, comp.Text), "  > ", "    "))
+			default:
+				tr.renderComponent(&text, component)
+			}
+		}
+
+		number, ok := numbers[step]
+		if !ok {
+			continue
+		}
+		prefix := fmt.Sprintf("  %d. ", number)
+		indent := strings.Repeat(" ", utf8.RuneCountInString(prefix))
+		result.WriteString(tr.wrap(text.String(), prefix, indent))
+		for _, callout := range callouts[step] {
+			result.WriteString(tr.wrap(tr.style(ansiDim, "↩ "+callout), indent, indent+"  "))
+		}
+	}
+	return result.String(), nil
+}
+
+// renderHeader renders the title and metadata in a box.
+func (tr TerminalRenderer) renderHeader(result *strings.Builder, recipe *cooklang.Recipe, labels Strings) {
+	var lines []string
+	field := func(label, value string) {
+		if value != "" {
+			lines = append(lines, label+": "+value)
+		}
+	}
+	field(labels.Description, recipe.Description)
+	field(labels.Cuisine, recipe.Cuisine)
+	if !recipe.Date.IsZero() {
+		field(labels.Date, recipe.Date.Format("2006-01-02"))
+	}
+	field(labels.Difficulty, labels.difficulty(recipe.Difficulty))
+	field(labels.PrepTime, recipe.PrepTime)
+	field(labels.TotalTime, recipe.TotalTime)
+	field(labels.Author, recipe.Author)
+	if recipe.Servings > 0 {
+		field(labels.Servings, fmt.Sprintf("%g", recipe.Servings))
+	}
+	field(labels.Tags, strings.Join(recipe.Tags, ", "))
+
+	// Any additional metadata, in a stable order
+	var keys []string
+	for key := range recipe.Metadata {
+		switch key {
+		case "title", "cuisine", "date", "description", "difficulty", "prep_time", "total_time",
+			"author", "servings", "tags", "images", "image", "units", cooklang.StepImagesKey:
+			continue
+		}
+		if !tr.Options.showMetadata(key) {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		field(simpleTitle(strings.ReplaceAll(key, "_", " ")), tr.Options.metadataValue(key, recipe.Metadata[key]))
+	}
+
+	if recipe.Title == "" && len(lines) == 0 {
+		return
+	}
+
+	// Wrap long values to fit the box, and size the box to its longest line
+	inner := tr.width() - 4
+	var wrapped []string
+	for _, line := range lines {
+		wrapped = append(wrapped, wrapWords(line, inner, "", "  ")...)
+	}
+	var title []string
+	if recipe.Title != "" {
+		title = wrapWords(recipe.Title, inner, "", "")
+	}
+	boxWidth := 0
+	for _, line := range append(title, wrapped...) {
+		boxWidth = max(boxWidth, utf8.RuneCountInString(line))
+	}
+
+	row := func(text, code string) {
+		padding := strings.Repeat(" ", boxWidth-utf8.RuneCountInString(text))
+		fmt.Fprintf(result, "│ %s%s │\n", tr.style(code, text), padding)
+	}
+	border := strings.Repeat("─", boxWidth+2)
+	fmt.Fprintf(result, "╭%s╮\n", border)
+	for _, line := range title {
+		row(line, ansiBold)
+	}
+	if len(title) > 0 && len(wrapped) > 0 {
+		fmt.Fprintf(result, "├%s┤\n", border)
+	}
+	for _, line := range wrapped {
+		row(line, "")
+	}
+	fmt.Fprintf(result, "╰%s╯\n\n", border)
+}
+
+// renderComponent renders a step component as styled text.
+func (tr TerminalRenderer) renderComponent(result *strings.Builder, component cooklang.StepComponent) {
+	switch comp := component.(type) {
+	case *cooklang.Instruction:
+		result.WriteString(comp.Text)
+	case *cooklang.Ingredient:
+		result.WriteString(tr.style(ansiGreen, comp.Name))
+		if comp.Quantity.HasAmount() {
+			amount := strings.TrimSpace(tr.Options.amount(comp) + " " + comp.Unit)
+			result.WriteString(" (" + tr.style(ansiBold, amount) + ")")
+		}
+		if comp.Annotation != "" {
+			result.WriteString(tr.style(ansiDim, " ("+comp.Annotation+")"))
+		}
+	case *cooklang.Cookware:
+		result.WriteString(tr.style(ansiCyan, comp.Name))
+		if comp.Annotation != "" {
+			result.WriteString(tr.style(ansiDim, " ("+comp.Annotation+")"))
+		}
+	case *cooklang.Timer:
+		display := comp.RenderDisplay()
+		if comp.Name != "" && comp.Duration != "" {
+			display = comp.Name + " (" + display + ")"
+		}
+		result.WriteString(tr.style(ansiYellow, "⏲ "+display))
+	case *cooklang.Temperature:
+		result.WriteString(tr.style(ansiBold, tr.Options.temperature(comp)))
+	case *cooklang.RecipeReference:
+		result.WriteString(tr.style(ansiUnderline, comp.Path))
+	case *cooklang.Comment:
+		result.WriteString(tr.style(ansiDim, "("+comp.Text+")"))
+	}
+}
+
+// ingredientAmount formats an ingredient's amount and unit for the ingredient list.
+func (tr TerminalRenderer) ingredientAmount(ingredient *cooklang.Ingredient, labels Strings) string {
+	switch {
+	case ingredient.Quantity.HasAmount():
+		return strings.TrimSpace(tr.Options.amount(ingredient) + " " + ingredient.Unit)
+	case ingredient.Quantity.IsSome():
+		return strings.TrimSpace(labels.Some + " " + ingredient.Unit)
+	}
+	return ""
+}
+
+// style wraps text in an ANSI escape code, unless colors are disabled.
+func (tr TerminalRenderer) style(code, text string) string {
+	if tr.NoColor || code == "" || text == "" {
+		return text
+	}
+	return code + text + ansiReset
+}
+
+// width returns the line width to wrap at.
+func (tr TerminalRenderer) width() int {
+	if tr.Width > 0 {
+		return tr.Width
+	}
+	return defaultTerminalWidth
+}
+
+// wrap wraps styled text to the line width, starting with prefix and indenting
+// continuation lines with indent.
+func (tr TerminalRenderer) wrap(text, prefix, indent string) string {
+	return strings.Join(wrapWords(text, tr.width(), prefix, indent), "\n") + "\n"
+}
+
+// wrapWords breaks text at whitespace into lines of at most width visible
+// characters (ANSI escape codes do not count). The first line starts with prefix,
+// the others with indent. Words longer than a line are not broken.
+func wrapWords(text string, width int, prefix, indent string) []string {
+	var lines []string
+	line, lineWidth, empty := prefix, visibleWidth(prefix), true
+	for _, word := range strings.Fields(text) {
+		wordWidth := visibleWidth(word)
+		if !empty && lineWidth+1+wordWidth > width {
+			lines = append(lines, line)
+			line, lineWidth, empty = indent, visibleWidth(indent), true
+		}
+		if !empty {
+			line += " "
+			lineWidth++
+		}
+		line += word
+		lineWidth += wordWidth
+		empty = false
+	}
+	return append(lines, line)
+}
+
+// visibleWidth returns the number of characters text takes up in a terminal,
+// ignoring ANSI escape codes.
+func visibleWidth(text string) int {
+	return utf8.RuneCountInString(ansiPattern.ReplaceAllString(text, ""))
+}
@@ -0,0 +1,66 @@
+package renderers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hilli/cooklang"
+)
+
+func TestTerminalRenderer(t *testing.T) {
+	recipe, err := cooklang.ParseString(`---
+title: Pancakes
+servings: 4
+---
+== Batter ==
+
+Whisk @flour{125%g}, @eggs{2} and @salt{} in a #bowl{}(large) until smooth and no lumps remain, then leave the batter to rest for ~rest{10%minutes}.
+
+> Use room temperature eggs.
+
+Fry in a #pan{} at 180°C with @butter{}(optional).
+`)
+	if err != nil {
+		t.Fatalf("Failed to parse recipe: %v", err)
+	}
+
+	output := renderRecipe(t, TerminalRenderer{NoColor: true, Width: 60}, recipe)
+	if strings.Contains(output, "\033[") {
+		t.Error("NoColor output contains ANSI escape codes")
+	}
+	for _, expected := range []string{
+		"│ Pancakes    │",
+		"│ Servings: 4 │",
+		"  • 125 g  flour\n",
+		"  • 2      eggs\n",
+		"  • some   salt\n",
+		"  • bowl, large\n",
+		"\n  Batter\n",
+		"  1. Whisk flour (125 g), eggs (2) and salt in a bowl\n     (large) until",
+		"rest for ⏲ rest (10 minutes).",
+		"  > Use room temperature eggs.\n",
+		"  2. Fry in a pan at 180°C with butter (optional).\n",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("output missing %q:\n%s", expected, output)
+		}
+	}
+	for _, line := range strings.Split(output, "\n") {
+		if visibleWidth(line) > 60 {
+			t.Errorf("line longer than 60 characters: %q", line)
+		}
+	}
+
+	colored := renderRecipe(t, TerminalRenderer{Width: 60}, recipe)
+	if !strings.Contains(colored, ansiGreen+"flour"+ansiReset) || !strings.Contains(colored, ansiBold+"125 g"+ansiReset) {
+		t.Errorf("expected colored ingredient names and bold quantities:\n%q", colored)
+	}
+	if ansiPattern.ReplaceAllString(colored, "") != output {
+		t.Error("colored output should match the plain output without escape codes")
+	}
+
+	german := renderRecipe(t, TerminalRenderer{NoColor: true, Options: RendererOptions{Locale: "de"}}, recipe)
+	if !strings.Contains(german, "Zutaten\n") || !strings.Contains(german, "Küchengeräte\n") || !strings.Contains(german, "Portionen: 4") {
+		t.Errorf("expected German labels:\n%s", german)
+	}
+}
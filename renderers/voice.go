@@ -0,0 +1,374 @@
+package renderers
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hilli/cooklang"
+)
+
+// VoiceRenderer renders recipes as structured JSON for voice assistants such as
+// Alexa skills or Google Assistant actions. Each step carries a spoken prompt paired
+// with its display text, the ingredient amounts it uses, and timer hints, so a skill
+// can implement "next step", "repeat" and "what do I need?" navigation directly from
+// a .cook source.
+//
+// Example usage:
+//
+//	recipe, _ := cooklang.ParseFile("pancakes.cook")
+//	renderer := renderers.VoiceRenderer{}
+//
+//	// Get the structure for further manipulation
+//	voice := renderer.BuildRecipe(recipe)
+//	fmt.Println(voice.Steps[0].Speech)
+//
+//	// Get it as a formatted JSON string
+//	jsonStr, _ := renderer.RenderRecipe(recipe)
+type VoiceRenderer struct{}
+
+// VoiceRecipe is the top-level voice assistant representation of a recipe.
+type VoiceRecipe struct {
+	Name        string            `json:"name"`
+	Description string            `json:"description,omitempty"`
+	Servings    float32           `json:"servings,omitempty"`
+	Source      string            `json:"source,omitempty"`   // Recipe source or URL, if known
+	Intro       string            `json:"intro"`              // Spoken when the recipe is opened
+	Reprompt    string            `json:"reprompt"`           // Spoken when the user stays silent
+	Outro       string            `json:"outro"`              // Spoken after the last step
+	Ingredients []VoiceIngredient `json:"ingredients"`        // Everything needed, for "what do I need?"
+	Cookware    []string          `json:"cookware,omitempty"` // Distinct cookware names
+	Sections    []string          `json:"sections,omitempty"` // Section names in order of appearance
+	Steps       []VoiceStep       `json:"steps"`              // Steps in order, for "next step" navigation
+	TotalSteps  int               `json:"total_steps"`
+	Utterances  []VoiceUtterance  `json:"utterances"` // Sample utterances paired with navigation intents
+}
+
+// VoiceIngredient is an ingredient with its amount split out for speech.
+type VoiceIngredient struct {
+	Name        string   `json:"name"`
+	Quantity    *float32 `json:"quantity,omitempty"` // Omitted when unspecified ("some")
+	Unit        string   `json:"unit,omitempty"`
+	Display     string   `json:"display"` // e.g., "1/2 cup flour"
+	Speech      string   `json:"speech"`  // e.g., "half a cup of flour"
+	Optional    bool     `json:"optional,omitempty"`
+	Approximate bool     `json:"approximate,omitempty"` // Quantity is an estimate; Speech says "about"
+}
+
+// VoiceTimer is a timer hint that a skill can offer to start.
+type VoiceTimer struct {
+	Name    string `json:"name,omitempty"`
+	Display string `json:"display"`           // e.g., "10 minutes"
+	Seconds int    `json:"seconds,omitempty"` // Omitted when the duration is not numeric
+	Prompt  string `json:"prompt"`            // e.g., "Shall I set a timer for 10 minutes?"
+}
+
+// VoiceStep is a single navigable step.
+type VoiceStep struct {
+	Number      int               `json:"number"`            // 1-based step number
+	Section     string            `json:"section,omitempty"` // Section the step belongs to
+	Display     string            `json:"display"`           // Step text for screens
+	Speech      string            `json:"speech"`            // Step text for speech, prefixed with the step number
+	SSML        string            `json:"ssml"`              // Speech as SSML, with ingredient amounts and timer prompts
+	Ingredients []VoiceIngredient `json:"ingredients,omitempty"`
+	Timers      []VoiceTimer      `json:"timers,omitempty"`
+}
+
+// VoiceUtterance pairs a sample utterance with the navigation intent it triggers.
+type VoiceUtterance struct {
+	Intent     string   `json:"intent"`
+	Utterances []string `json:"utterances"`
+}
+
+// defaultVoiceUtterances are the navigation intents every voice recipe supports.
+var defaultVoiceUtterances = []VoiceUtterance{
+	{Intent: "NextStep", Utterances: []string{"next", "next step", "what's next", "continue"}},
+	{Intent: "PreviousStep", Utterances: []string{"back", "previous step", "go back"}},
+	{Intent: "RepeatStep", Utterances: []string{"repeat", "say that again", "what was that"}},
+	{Intent: "ListIngredients", Utterances: []string{"what do I need", "list the ingredients", "ingredients"}},
+	{Intent: "StepIngredients", Utterances: []string{"what goes in this step", "how much do I need"}},
+	{Intent: "StartTimer", Utterances: []string{"start the timer", "set a timer", "yes"}},
+	{Intent: "StartOver", Utterances: []string{"start over", "from the beginning"}},
+}
+
+// BuildRecipe builds the voice assistant structure for a recipe; RenderRecipe and
+// Render write it as JSON.
+// Comments and notes are skipped; sections are attached to the steps that follow them.
+//
+// Parameters:
+//   - recipe: The parsed Cooklang recipe to render
+//
+// Returns:
+//   - VoiceRecipe: The voice assistant representation
+func (vr VoiceRenderer) BuildRecipe(recipe *cooklang.Recipe) VoiceRecipe {
+	name := recipe.Title
+	if name == "" {
+		name = "this recipe"
+	}
+
+	voice := VoiceRecipe{
+		Name:        name,
+		Servings:    recipe.Servings,
+		Description: recipe.Description,
+		Ingredients: []VoiceIngredient{},
+		Steps:       []VoiceStep{},
+		Utterances:  defaultVoiceUtterances,
+		Source:      recipe.Metadata["source"],
+	}
+
+	for _, ing := range recipe.GetIngredients().Ingredients {
+		voice.Ingredients = append(voice.Ingredients, newVoiceIngredient(ing))
+	}
+
+	seen := make(map[string]bool)
+	for _, cw := range recipe.GetCookware() {
+		if !seen[cw.Name] {
+			seen[cw.Name] = true
+			voice.Cookware = append(voice.Cookware, cw.Name)
+		}
+	}
+
+	var section string
+	for step := recipe.FirstStep; step != nil; step = step.NextStep {
+		var display, ssml strings.Builder
+		var ingredients []VoiceIngredient
+		var timers []VoiceTimer
+
+		for component := step.FirstComponent; component != nil; component = component.GetNext() {
+			switch comp := component.(type) {
+			case *cooklang.Section:
+				section = comp.Name
+				voice.Sections = append(voice.Sections, comp.Name)
+			case *cooklang.Instruction:
+				display.WriteString(comp.Text)
+				ssml.WriteString(ssmlEscaper.Replace(comp.Text))
+			case *cooklang.Temperature:
+				display.WriteString(comp.RenderDisplay())
+				ssml.WriteString(ssmlEscaper.Replace(comp.RenderDisplay()))
+			case *cooklang.Ingredient:
+				display.WriteString(comp.Name)
+				ingredient := newVoiceIngredient(comp)
+				ingredients = append(ingredients, ingredient)
+				if comp.IsReference() {
+					ssml.WriteString(ssmlEscaper.Replace(comp.Name))
+				} else {
+					// Ingredients are called out with their amounts, e.g. "half a cup of flour"
+					ssml.WriteString(`<emphasis level="moderate">` + ssmlEscaper.Replace(ingredient.Speech) + "</emphasis>")
+				}
+			case *cooklang.Cookware:
+				display.WriteString(comp.Name)
+				ssml.WriteString(ssmlEscaper.Replace(comp.Name))
+			case *cooklang.Timer:
+				timer := newVoiceTimer(comp)
+				display.WriteString(timer.Display)
+				ssml.WriteString(ssmlEscaper.Replace(timer.Display))
+				timers = append(timers, timer)
+			}
+		}
+
+		text := strings.Join(strings.Fields(display.String()), " ")
+		if text == "" {
+			continue
+		}
+
+		number := len(voice.Steps) + 1
+		voice.Steps = append(voice.Steps, VoiceStep{
+			Number:      number,
+			Section:     section,
+			Display:     text,
+			Speech:      fmt.Sprintf("Step %d. %s", number, text),
+			SSML:        stepSSML(number, ssml.String(), timers),
+			Ingredients: ingredients,
+			Timers:      timers,
+		})
+	}
+	voice.TotalSteps = len(voice.Steps)
+
+	voice.Intro = fmt.Sprintf("Let's make %s. It has %s and %s.", name,
+		pluralize(len(voice.Ingredients), "ingredient"), pluralize(voice.TotalSteps, "step"))
+	if recipe.Servings > 0 {
+		voice.Intro += fmt.Sprintf(" It serves %s.", cooklang.FormatAsFractionDefault(float64(recipe.Servings)))
+	}
+	voice.Intro += ` Say "what do I need" to hear the ingredients, or "next" to begin.`
+	voice.Reprompt = `Say "next" for the next step, or "repeat" to hear this step again.`
+	voice.Outro = "That was the last step. Enjoy!"
+
+	return voice
+}
+
+// RenderRecipeJSON returns the voice assistant structure as a formatted (indented) JSON string.
+//
+// Parameters:
+//   - recipe: The parsed Cooklang recipe to render
+//
+// Returns:
+//   - string: The voice assistant JSON
+//   - error: An error wrapping ErrEncoding if the JSON cannot be written
+//
+// Example:
+//
+//	recipe, _ := cooklang.ParseFile("recipe.cook")
+//	jsonStr, err := renderers.VoiceRenderer{}.RenderRecipeJSON(recipe)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(jsonStr)
+//
+// Deprecated: Use Render, which also takes RendererOptions.
+func (vr VoiceRenderer) RenderRecipeJSON(recipe *cooklang.Recipe) (string, error) {
+	return vr.Render(recipe, RendererOptions{})
+}
+
+// RenderRecipe renders the recipe as indented voice assistant JSON, like Render
+// without options.
+//
+// Parameters:
+//   - recipe: The parsed Cooklang recipe to render
+//
+// Returns:
+//   - string: The voice assistant JSON
+//   - error: An error wrapping ErrEncoding if the JSON cannot be written
+func (vr VoiceRenderer) RenderRecipe(recipe *cooklang.Recipe) (string, error) {
+	return vr.Render(recipe, RendererOptions{})
+}
+
+// Render renders the recipe as indented voice assistant JSON. Of the options, only
+// Units, PreferredUnits and NoImages apply.
+//
+// Parameters:
+//   - recipe: The parsed Cooklang recipe to render
+//   - opts: The renderer options
+//
+// Returns:
+//   - string: The voice assistant JSON
+//   - error: An error wrapping ErrEncoding if the JSON cannot be written
+//
+// Example:
+//
+//	jsonStr, err := renderers.VoiceRenderer{}.Render(recipe, renderers.RendererOptions{Units: cooklang.UnitSystemMetric})
+func (vr VoiceRenderer) Render(recipe *cooklang.Recipe, opts RendererOptions) (string, error) {
+	bytes, err := json.MarshalIndent(vr.BuildRecipe(opts.prepare(recipe)), "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("%w: voice JSON: %w", ErrEncoding, err)
+	}
+	return string(bytes), nil
+}
+
+// ssmlEscaper escapes text for SSML, which is XML.
+var ssmlEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;", "'", "&apos;")
+
+// stepSSML wraps a step's spoken text in a <speak> element, prefixed with the step
+// number. Each timer's prompt follows after a pause, giving the listener time to
+// start it.
+func stepSSML(number int, text string, timers []VoiceTimer) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "<speak>Step %d. %s", number, strings.Join(strings.Fields(text), " "))
+	for _, timer := range timers {
+		b.WriteString(` <break time="1s"/>` + ssmlEscaper.Replace(timer.Prompt))
+	}
+	b.WriteString("</speak>")
+	return b.String()
+}
+
+// newVoiceIngredient converts an ingredient into its voice representation.
+func newVoiceIngredient(ing *cooklang.Ingredient) VoiceIngredient {
+	voice := VoiceIngredient{
+		Name:        ing.Name,
+		Unit:        ing.Unit,
+		Display:     ing.RenderDisplay(),
+		Speech:      speakIngredient(ing),
+		Optional:    ing.Optional,
+		Approximate: ing.Approximate,
+	}
+	if ing.Quantity.HasAmount() {
+		quantity := float32(ing.Quantity.Min())
+		voice.Quantity = &quantity
+	}
+	return voice
+}
+
+// speakIngredient phrases an ingredient amount for speech, e.g. "half a cup of flour".
+func speakIngredient(ing *cooklang.Ingredient) string {
+	var speech string
+	switch {
+	case !ing.Quantity.HasAmount():
+		speech = "some " + ing.Name
+	case ing.Unit == "":
+		speech = speakAmount(ing) + " " + ing.Name
+	default:
+		speech = speakAmount(ing) + " " + ing.Unit + " of " + ing.Name
+	}
+	if ing.Optional {
+		speech += ", optional"
+	}
+	return speech
+}
+
+// speakAmount phrases an ingredient quantity, reading common fractions as words.
+// Approximate quantities are introduced with "about".
+func speakAmount(ing *cooklang.Ingredient) string {
+	if ing.Approximate {
+		return "about " + speakExactAmount(ing)
+	}
+	return speakExactAmount(ing)
+}
+
+// speakExactAmount phrases the numeric part of an ingredient quantity.
+func speakExactAmount(ing *cooklang.Ingredient) string {
+	amount := ing.Quantity
+	if amount.IsRange() {
+		return strings.Replace(amount.Format(cooklang.QuantityStyleFraction), "-", " to ", 1)
+	}
+	switch text := amount.Format(cooklang.QuantityStyleFraction); text {
+	case "1/2":
+		if ing.Unit != "" {
+			return "half a"
+		}
+		return "half"
+	case "1/4":
+		return "a quarter"
+	case "3/4":
+		return "three quarters"
+	case "1/3":
+		return "a third"
+	case "2/3":
+		return "two thirds"
+	default:
+		return text
+	}
+}
+
+// newVoiceTimer converts a timer into a voice timer hint.
+func newVoiceTimer(timer *cooklang.Timer) VoiceTimer {
+	display := timer.RenderDisplay()
+	voice := VoiceTimer{
+		Name:    timer.Name,
+		Display: display,
+		Seconds: timerSeconds(timer),
+	}
+	if voice.Seconds > 0 {
+		voice.Prompt = fmt.Sprintf("Shall I set a timer for %s?", display)
+	} else {
+		voice.Prompt = fmt.Sprintf("This step takes %s.", display)
+	}
+	return voice
+}
+
+// timerSeconds returns a timer's duration in seconds, or 0 if it cannot be determined.
+// Ranges use their lower bound.
+func timerSeconds(timer *cooklang.Timer) int {
+	d, err := timer.ParsedDuration()
+	if err != nil {
+		return 0
+	}
+	return int(d.Round(time.Second) / time.Second)
+}
+
+// pluralize formats a count with a singular or plural noun.
+func pluralize(count int, noun string) string {
+	if count == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", count, noun)
+}
@@ -0,0 +1,182 @@
+package renderers
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/hilli/cooklang"
+)
+
+func TestVoiceRenderer_BuildRecipe(t *testing.T) {
+	recipeContent := `---
+title: Pancakes
+servings: 4
+source: https://example.com/pancakes
+---
+
+== Batter ==
+
+Whisk @flour{1/2%cup}, @eggs{2} and @salt{} in a #bowl{}.
+
+-- Lumps are fine.
+
+Rest for ~{10%minutes}.
+
+== Cooking ==
+
+Fry in a #pan{} for ~flip{90%seconds} per side, adding @butter{1-2%tbsp}.
+
+Serve with @maple syrup{}(optional).
+`
+
+	recipe, err := cooklang.ParseString(recipeContent)
+	if err != nil {
+		t.Fatalf("Failed to parse recipe: %v", err)
+	}
+
+	voice := VoiceRenderer{}.BuildRecipe(recipe)
+
+	if voice.Name != "Pancakes" {
+		t.Errorf("Expected name 'Pancakes', got %q", voice.Name)
+	}
+	if voice.Source != "https://example.com/pancakes" {
+		t.Errorf("Expected source to match, got %q", voice.Source)
+	}
+	expectedIntro := `Let's make Pancakes. It has 5 ingredients and 4 steps. It serves 4. Say "what do I need" to hear the ingredients, or "next" to begin.`
+	if voice.Intro != expectedIntro {
+		t.Errorf("Unexpected intro:\n got: %s\nwant: %s", voice.Intro, expectedIntro)
+	}
+	if voice.TotalSteps != 4 || len(voice.Steps) != 4 {
+		t.Fatalf("Expected 4 steps, got %d (%d)", voice.TotalSteps, len(voice.Steps))
+	}
+	if len(voice.Sections) != 2 || voice.Sections[1] != "Cooking" {
+		t.Errorf("Expected sections [Batter Cooking], got %v", voice.Sections)
+	}
+	if len(voice.Cookware) != 2 {
+		t.Errorf("Expected 2 cookware items, got %v", voice.Cookware)
+	}
+	if len(voice.Utterances) == 0 || voice.Utterances[0].Intent != "NextStep" {
+		t.Errorf("Expected navigation utterances, got %v", voice.Utterances)
+	}
+
+	first := voice.Steps[0]
+	if first.Number != 1 || first.Section != "Batter" {
+		t.Errorf("Unexpected first step: %+v", first)
+	}
+	if first.Speech != "Step 1. Whisk flour, eggs and salt in a bowl." {
+		t.Errorf("Unexpected first step speech: %q", first.Speech)
+	}
+	if len(first.Ingredients) != 3 {
+		t.Fatalf("Expected 3 ingredients in first step, got %d", len(first.Ingredients))
+	}
+	speech := []string{"half a cup of flour", "2 eggs", "some salt"}
+	for i, want := range speech {
+		if first.Ingredients[i].Speech != want {
+			t.Errorf("Ingredient %d speech = %q, want %q", i, first.Ingredients[i].Speech, want)
+		}
+	}
+	wantSSML := `<speak>Step 1. Whisk <emphasis level="moderate">half a cup of flour</emphasis>, <emphasis level="moderate">2 eggs</emphasis> and <emphasis level="moderate">some salt</emphasis> in a bowl.</speak>`
+	if first.SSML != wantSSML {
+		t.Errorf("Unexpected first step SSML:\n got: %s\nwant: %s", first.SSML, wantSSML)
+	}
+	if first.Ingredients[2].Quantity != nil {
+		t.Errorf("Expected no quantity for salt, got %v", *first.Ingredients[2].Quantity)
+	}
+
+	rest := voice.Steps[1]
+	if len(rest.Timers) != 1 || rest.Timers[0].Seconds != 600 {
+		t.Fatalf("Expected a 600 second timer, got %+v", rest.Timers)
+	}
+	if rest.Timers[0].Prompt != "Shall I set a timer for 10 minutes?" {
+		t.Errorf("Unexpected timer prompt: %q", rest.Timers[0].Prompt)
+	}
+	if want := `<speak>Step 2. Rest for 10 minutes. <break time="1s"/>Shall I set a timer for 10 minutes?</speak>`; rest.SSML != want {
+		t.Errorf("Unexpected timer step SSML:\n got: %s\nwant: %s", rest.SSML, want)
+	}
+
+	fry := voice.Steps[2]
+	if fry.Section != "Cooking" {
+		t.Errorf("Expected third step in 'Cooking', got %q", fry.Section)
+	}
+	if len(fry.Timers) != 1 || fry.Timers[0].Name != "flip" || fry.Timers[0].Seconds != 90 {
+		t.Errorf("Unexpected flip timer: %+v", fry.Timers)
+	}
+	if fry.Ingredients[0].Speech != "1 to 2 tbsp of butter" {
+		t.Errorf("Unexpected range speech: %q", fry.Ingredients[0].Speech)
+	}
+}
+
+func TestVoiceRenderer_RenderRecipeJSON(t *testing.T) {
+	recipe, err := cooklang.ParseString("Boil @water{1%l} for ~{overnight}.")
+	if err != nil {
+		t.Fatalf("Failed to parse recipe: %v", err)
+	}
+
+	jsonStr, err := VoiceRenderer{}.RenderRecipeJSON(recipe)
+	if err != nil {
+		t.Fatalf("Failed to render JSON: %v", err)
+	}
+
+	var voice VoiceRecipe
+	if err := json.Unmarshal([]byte(jsonStr), &voice); err != nil {
+		t.Fatalf("Output is not valid JSON: %v", err)
+	}
+	if voice.Name != "this recipe" {
+		t.Errorf("Expected fallback name, got %q", voice.Name)
+	}
+	if len(voice.Steps) != 1 || len(voice.Steps[0].Timers) != 1 {
+		t.Fatalf("Expected one step with one timer, got %+v", voice.Steps)
+	}
+	timer := voice.Steps[0].Timers[0]
+	if timer.Seconds != 0 || timer.Prompt != "This step takes overnight." {
+		t.Errorf("Unexpected non-numeric timer: %+v", timer)
+	}
+}
+
+func TestSSMLRenderer(t *testing.T) {
+	recipe, err := cooklang.ParseString(`---
+title: Fish & Chips
+---
+== Batter ==
+
+Whisk @flour{1/2%cup} with @beer{250%ml} <cold>.
+
+== Frying ==
+
+Fry in a #pot{} for ~{4%minutes}, then add the @&flour{}.
+`)
+	if err != nil {
+		t.Fatalf("Failed to parse recipe: %v", err)
+	}
+
+	ssml, err := SSMLRenderer{}.Render(recipe, RendererOptions{})
+	if err != nil {
+		t.Fatalf("Failed to render SSML: %v", err)
+	}
+	want := `<speak>
+<p>Let&apos;s make Fish &amp; Chips. It has 2 ingredients and 2 steps. It serves 1. Say &quot;what do I need&quot; to hear the ingredients, or &quot;next&quot; to begin.</p>
+<p><emphasis level="strong">Batter</emphasis></p>
+<p>Step 1. Whisk <emphasis level="moderate">half a cup of flour</emphasis> with <emphasis level="moderate">250 ml of beer</emphasis> &lt;cold&gt;.</p>
+<p><emphasis level="strong">Frying</emphasis></p>
+<p>Step 2. Fry in a pot for 4 minutes, then add the flour. <break time="1s"/>Shall I set a timer for 4 minutes?</p>
+<break strength="x-strong"/>
+<p>That was the last step. Enjoy!</p>
+</speak>
+`
+	if ssml != want {
+		t.Errorf("Unexpected SSML:\n got: %s\nwant: %s", ssml, want)
+	}
+
+	// The document is well-formed XML
+	decoder := xml.NewDecoder(strings.NewReader(ssml))
+	for {
+		if _, err := decoder.Token(); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("SSML is not well-formed: %v", err)
+		}
+	}
+}
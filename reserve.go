@@ -0,0 +1,243 @@
+package cooklang
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// reserveClause matches the body of a reservation such as "reserve 240 ml for step 6"
+// or "set aside half of the sauce for step 4".
+const reserveClause = `(?i)(?:reserve|set\s+aside)\s+([^()]+?)\s+for\s+step\s+(\d+)`
+
+var (
+	// reserveInText finds reservations written in parentheses in the step text.
+	reserveInText = regexp.MustCompile(`\(\s*` + reserveClause + `\s*\)`)
+	// reserveAnnotation matches an ingredient annotation that is a reservation.
+	reserveAnnotation = regexp.MustCompile(`^\s*` + reserveClause + `\s*$`)
+)
+
+// Reservation is a partial output that is set aside in one step and used in a later
+// one, such as part of a sauce kept back for serving. Reservations are written in
+// parentheses in the step text or as an ingredient annotation:
+//
+//	Simmer the @tomato sauce{500%ml} (reserve 240 ml for step 6).
+//	Add @stock{1%l}(set aside 200 ml for step 4).
+//	Whisk the dressing (reserve half of the dressing for step 5).
+//
+// Steps are numbered from 1 across the whole recipe, skipping steps that only hold
+// notes, comments or section headers.
+type Reservation struct {
+	FromStep    int         `json:"from_step"`              // Step where the output is set aside
+	ToStep      int         `json:"to_step"`                // Step where it is used
+	Item        string      `json:"item,omitempty"`         // What is reserved (e.g., "tomato sauce"); empty if unknown
+	Amount      string      `json:"amount,omitempty"`       // Amount as written (e.g., "240 ml", "half")
+	Quantity    float32     `json:"quantity,omitempty"`     // Parsed amount (-1 if not numeric, e.g. "half"); lower bound for ranges
+	QuantityMax float32     `json:"quantity_max,omitempty"` // Upper bound for range amounts (0 if not a range)
+	Unit        string      `json:"unit,omitempty"`         // Unit of the amount (e.g., "ml")
+	Text        string      `json:"text"`                   // The reservation as written, without parentheses
+	From        *Step       `json:"-"`                      // Step where the output is set aside
+	To          *Step       `json:"-"`                      // Step where it is used (nil if the step does not exist)
+	Ingredient  *Ingredient `json:"-"`                      // Ingredient the reservation is taken from, if known
+}
+
+// String returns a short description of the reservation.
+// Example: "240 ml tomato sauce from step 2 to step 6"
+func (r Reservation) String() string {
+	what := strings.TrimSpace(r.Amount + " " + r.Item)
+	if what == "" {
+		what = "reserved output"
+	}
+	return fmt.Sprintf("%s from step %d to step %d", what, r.FromStep, r.ToStep)
+}
+
+// StepNumbers returns the 1-based number of each step as used by reservations ("for step 6").
+// Steps that only hold notes, comments or section headers are not numbered.
+//
+// Returns:
+//   - map[*Step]int: The number of each numbered step
+func (r *Recipe) StepNumbers() map[*Step]int {
+	numbers := make(map[*Step]int)
+	for step := r.FirstStep; step != nil; step = step.NextStep {
+		if isNumberedStep(step) {
+			numbers[step] = len(numbers) + 1
+		}
+	}
+	return numbers
+}
+
+// isNumberedStep reports whether a step holds instructions rather than only
+// notes, comments or a section header.
+func isNumberedStep(step *Step) bool {
+	for component := step.FirstComponent; component != nil; component = component.GetNext() {
+		switch comp := component.(type) {
+		case *Instruction:
+			if strings.TrimSpace(comp.Text) != "" {
+				return true
+			}
+		case *Ingredient, *Cookware, *Timer, *Temperature, *RecipeReference:
+			return true
+		}
+	}
+	return false
+}
+
+// GetReservations returns the partial outputs that are set aside for later steps,
+// in the order they appear in the recipe.
+//
+// Returns:
+//   - []Reservation: The reservations, linking the step they are made in to the step using them
+//
+// Example:
+//
+//	recipe, _ := cooklang.ParseString("Simmer the @tomato sauce{500%ml} (reserve 240 ml for step 2).\n\nServe with the reserved sauce.")
+//	for _, res := range recipe.GetReservations() {
+//	    fmt.Println(res) // 240 ml tomato sauce from step 1 to step 2
+//	}
+func (r *Recipe) GetReservations() []Reservation {
+	numbers := r.StepNumbers()
+	steps := make(map[int]*Step, len(numbers))
+	for step, number := range numbers {
+		steps[number] = step
+	}
+
+	var reservations []Reservation
+	for step := r.FirstStep; step != nil; step = step.NextStep {
+		number, ok := numbers[step]
+		if !ok {
+			continue
+		}
+		var lastIngredient *Ingredient
+		for component := step.FirstComponent; component != nil; component = component.GetNext() {
+			var matches [][]string
+			var from *Ingredient
+			switch comp := component.(type) {
+			case *Ingredient:
+				lastIngredient = comp
+				if m := reserveAnnotation.FindStringSubmatch(comp.Annotation); m != nil {
+					matches, from = [][]string{m}, comp
+				}
+			case *Instruction:
+				matches, from = reserveInText.FindAllStringSubmatch(comp.Text, -1), lastIngredient
+			}
+			for _, m := range matches {
+				res := newReservation(m[1], from)
+				res.Text = strings.TrimSpace(strings.Trim(strings.TrimSpace(m[0]), "()"))
+				res.FromStep, res.From = number, step
+				res.ToStep, _ = strconv.Atoi(m[2])
+				res.To = steps[res.ToStep]
+				reservations = append(reservations, res)
+			}
+		}
+	}
+	return reservations
+}
+
+// newReservation parses the amount and item of a reservation, e.g. "240 ml",
+// "1/2 cup of the sauce" or "half". Without an explicit item, the reservation is
+// taken from the ingredient it annotates or follows.
+func newReservation(amount string, ingredient *Ingredient) Reservation {
+	res := Reservation{Quantity: -1}
+	words := strings.Fields(amount)
+	for i, word := range words {
+		if strings.EqualFold(word, "of") {
+			item := words[i+1:]
+			if len(item) > 0 && strings.EqualFold(item[0], "the") {
+				item = item[1:]
+			}
+			res.Item = strings.Join(item, " ")
+			words = words[:i]
+			break
+		}
+	}
+	res.Amount = strings.Join(words, " ")
+
+	if len(words) > 0 {
+		if q, err := ParseQuantity(words[0]); err == nil && q.Min() > 0 {
+			res.Quantity = float32(q.Min())
+			if q.IsRange() {
+				res.QuantityMax = float32(q.Max())
+			}
+			res.Unit = strings.Join(words[1:], " ")
+		}
+	}
+
+	if ingredient != nil && (res.Item == "" || strings.EqualFold(res.Item, ingredient.Name)) {
+		res.Ingredient = ingredient
+		if res.Item == "" {
+			res.Item = ingredient.Name
+		}
+	}
+	return res
+}
+
+// ValidateReservations checks that every reservation points to a later step that
+// exists and mentions what was reserved (the item, or the word "reserved"), and that
+// no more of an ingredient is reserved than the recipe uses.
+//
+// Returns:
+//   - error: All problems found, joined; nil if the reservations are consistent
+//
+// Example:
+//
+//	if err := recipe.ValidateReservations(); err != nil {
+//	    fmt.Println(err) // step 1 reserves 240 ml tomato sauce for step 6, but the recipe has 2 steps
+//	}
+func (r *Recipe) ValidateReservations() error {
+	var errs []error
+	stepCount := len(r.StepNumbers())
+	reserved := make(map[*Ingredient]float32)
+
+	for _, res := range r.GetReservations() {
+		what := strings.TrimSpace(res.Amount + " " + res.Item)
+		switch {
+		case res.ToStep <= res.FromStep:
+			errs = append(errs, fmt.Errorf("step %d reserves %s for step %d, which is not a later step", res.FromStep, what, res.ToStep))
+		case res.To == nil:
+			errs = append(errs, fmt.Errorf("step %d reserves %s for step %d, but the recipe has %d steps", res.FromStep, what, res.ToStep, stepCount))
+		case !stepUsesReservation(res.To, res.Item):
+			errs = append(errs, fmt.Errorf("step %d reserves %s, but step %d does not use it", res.FromStep, what, res.ToStep))
+		}
+
+		if res.Ingredient == nil || res.Quantity <= 0 || !res.Ingredient.Quantity.HasAmount() {
+			continue
+		}
+		available := float32(res.Ingredient.Quantity.Float())
+		if res.Unit != res.Ingredient.Unit {
+			converted, err := res.Ingredient.ConvertTo(res.Unit)
+			if err != nil {
+				continue
+			}
+			available = float32(converted.Quantity.Float())
+		}
+		reserved[res.Ingredient] += res.Quantity
+		if reserved[res.Ingredient] > available*1.001 {
+			errs = append(errs, fmt.Errorf("step %d reserves %s, but only %s %s %s is used",
+				res.FromStep, what, res.Ingredient.Quantity.Format(QuantityStyleFraction), res.Ingredient.Unit, res.Ingredient.Name))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// stepUsesReservation reports whether a step mentions the reserved item or the word "reserved".
+func stepUsesReservation(step *Step, item string) bool {
+	var text strings.Builder
+	for component := step.FirstComponent; component != nil; component = component.GetNext() {
+		switch comp := component.(type) {
+		case *Instruction:
+			text.WriteString(comp.Text)
+		case *Ingredient:
+			text.WriteString(comp.Name + " " + comp.Annotation)
+		case *RecipeReference:
+			text.WriteString(comp.Path)
+		}
+		text.WriteString(" ")
+	}
+	content := strings.ToLower(text.String())
+	if strings.Contains(content, "reserved") || strings.Contains(content, "set aside") {
+		return true
+	}
+	return item != "" && strings.Contains(content, strings.ToLower(item))
+}
@@ -0,0 +1,108 @@
+package cooklang
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGetReservations(t *testing.T) {
+	recipe, err := ParseString(`Simmer the @tomato sauce{500%ml} (reserve 240 ml for step 4).
+
+> Notes are not numbered.
+
+Add @stock{1%l}(set aside 1/2 cup for step 3) and whisk the dressing (reserve half of the dressing for step 4).
+
+Moisten the rice with the reserved stock.
+
+Serve with the dressing and reserved sauce.`)
+	if err != nil {
+		t.Fatalf("Failed to parse recipe: %v", err)
+	}
+
+	reservations := recipe.GetReservations()
+	expected := []struct {
+		from, to int
+		item     string
+		amount   string
+		quantity float32
+		unit     string
+		fromIng  string
+	}{
+		{1, 4, "tomato sauce", "240 ml", 240, "ml", "tomato sauce"},
+		{2, 3, "stock", "1/2 cup", 0.5, "cup", "stock"},
+		{2, 4, "dressing", "half", -1, "", ""},
+	}
+	if len(reservations) != len(expected) {
+		t.Fatalf("Expected %d reservations, got %d: %+v", len(expected), len(reservations), reservations)
+	}
+	numbers := recipe.StepNumbers()
+	for i, want := range expected {
+		res := reservations[i]
+		if res.FromStep != want.from || res.ToStep != want.to || res.Item != want.item || res.Amount != want.amount ||
+			res.Quantity != want.quantity || res.Unit != want.unit {
+			t.Errorf("Reservation %d: unexpected %+v", i+1, res)
+		}
+		if numbers[res.From] != want.from || numbers[res.To] != want.to {
+			t.Errorf("Reservation %d: step links do not match step numbers", i+1)
+		}
+		if (res.Ingredient == nil) != (want.fromIng == "") || (res.Ingredient != nil && res.Ingredient.Name != want.fromIng) {
+			t.Errorf("Reservation %d: unexpected ingredient %+v", i+1, res.Ingredient)
+		}
+	}
+	if got := reservations[0].String(); got != "240 ml tomato sauce from step 1 to step 4" {
+		t.Errorf("Unexpected String(): %q", got)
+	}
+	if reservations[1].Text != "set aside 1/2 cup for step 3" {
+		t.Errorf("Unexpected text %q", reservations[1].Text)
+	}
+
+	if err := recipe.ValidateReservations(); err != nil {
+		t.Errorf("Expected valid reservations, got: %v", err)
+	}
+}
+
+func TestValidateReservations(t *testing.T) {
+	tests := []struct {
+		name     string
+		recipe   string
+		expected []string
+	}{
+		{
+			name:     "missing step",
+			recipe:   "Make the @sauce{300%ml} (reserve 100 ml for step 6).\n\nServe with the reserved sauce.",
+			expected: []string{"step 1 reserves 100 ml sauce for step 6, but the recipe has 2 steps"},
+		},
+		{
+			name:     "earlier step",
+			recipe:   "Toast the @bread{2%slices}.\n\nMake the @sauce{300%ml} (reserve 100 ml for step 1).",
+			expected: []string{"step 2 reserves 100 ml sauce for step 1, which is not a later step"},
+		},
+		{
+			name:     "not used",
+			recipe:   "Make the @sauce{300%ml} (reserve 100 ml for step 2).\n\nServe the @bread{2%slices}.",
+			expected: []string{"step 1 reserves 100 ml sauce, but step 2 does not use it"},
+		},
+		{
+			name:     "more than the ingredient",
+			recipe:   "Warm the @stock{1%l}(reserve 800 ml for step 2) and (reserve 300 ml for step 2).\n\nAdd the reserved stock.",
+			expected: []string{"step 1 reserves 300 ml stock, but only 1 l stock is used"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recipe, err := ParseString(tt.recipe)
+			if err != nil {
+				t.Fatalf("Failed to parse recipe: %v", err)
+			}
+			err = recipe.ValidateReservations()
+			if err == nil {
+				t.Fatal("Expected a validation error")
+			}
+			for _, want := range tt.expected {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("Expected error to contain %q, got: %v", want, err)
+				}
+			}
+		})
+	}
+}
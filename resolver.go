@@ -60,7 +60,8 @@ func (r *FileSystemResolver) Resolve(path string) (*Recipe, error) {
 	return recipe, nil
 }
 
-// ParseYield parses a yield metadata value in Cooklang format (e.g., "500%ml", "2%loaves").
+// ParseYield parses a yield metadata value in Cooklang format (e.g., "500%ml", "2%loaves")
+// or as a quantity followed by a unit (e.g., "24 cookies", "2 loaves").
 // Returns quantity and unit, or 0 and empty string if parsing fails.
 func ParseYield(yieldStr string) (float64, string) {
 	if yieldStr == "" {
@@ -78,35 +79,37 @@ func ParseYield(yieldStr string) (float64, string) {
 		return qty, unit
 	}
 
-	// Try plain number
-	qty, err := strconv.ParseFloat(strings.TrimSpace(yieldStr), 64)
+	// Try plain number, or a number followed by a unit
+	qtyStr, unit, _ := strings.Cut(strings.TrimSpace(yieldStr), " ")
+	qty, err := strconv.ParseFloat(qtyStr, 64)
 	if err != nil {
 		return 0, ""
 	}
-	return qty, ""
+	return qty, strings.TrimSpace(unit)
 }
 
 // ScaleByYield calculates a scaling factor for a recipe reference based on yield metadata.
-// If the recipe has yield metadata matching the requested unit, it calculates
+// If the recipe's yield (Recipe.Yield, or its yield metadata) matches the requested unit,
+// ignoring case and a plural "s" or "es", it calculates
 // targetQuantity / yieldQuantity as the scaling factor.
 //
 // This is an experimental feature per the Cooklang spec.
 func ScaleByYield(recipe *Recipe, targetQuantity float64, targetUnit string) (float64, error) {
 	yieldStr, ok := recipe.Metadata["yield"]
-	if !ok {
+	if !ok && recipe.Yield.IsZero() {
 		return 0, fmt.Errorf("recipe has no yield metadata")
 	}
 
-	yieldQty, yieldUnit := ParseYield(yieldStr)
-	if yieldQty <= 0 {
+	yield := recipe.yield()
+	if yield.IsZero() {
 		return 0, fmt.Errorf("invalid yield quantity in %q", yieldStr)
 	}
 
-	if !strings.EqualFold(yieldUnit, targetUnit) {
-		return 0, fmt.Errorf("incompatible units: recipe yields %q but %q requested", yieldUnit, targetUnit)
+	if !sameYieldUnit(yield.Unit, targetUnit) {
+		return 0, fmt.Errorf("incompatible units: recipe yields %q but %q requested", yield.Unit, targetUnit)
 	}
 
-	return targetQuantity / yieldQty, nil
+	return targetQuantity / yield.Quantity, nil
 }
 
 // ResolveAndScale resolves a recipe reference and scales it according to the reference's
@@ -135,11 +138,11 @@ func ResolveAndScale(resolver RecipeResolver, ref *RecipeReference) (*Recipe, er
 
 	default:
 		// Units-based scaling (experimental)
-		factor, err := ScaleByYield(recipe, float64(ref.Quantity), ref.Unit)
+		scaled, err := recipe.ScaleToYield(float64(ref.Quantity), ref.Unit)
 		if err != nil {
 			return nil, fmt.Errorf("cannot scale %q: %w", ref.Path, err)
 		}
-		return recipe.Scale(factor), nil
+		return scaled, nil
 	}
 }
 
@@ -7,8 +7,8 @@ import (
 
 func TestParseYield(t *testing.T) {
 	tests := []struct {
-		input   string
-		wantQty float64
+		input    string
+		wantQty  float64
 		wantUnit string
 	}{
 		{"500%ml", 500, "ml"},
@@ -16,6 +16,8 @@ func TestParseYield(t *testing.T) {
 		{"1.5%kg", 1.5, "kg"},
 		{"100%g", 100, "g"},
 		{"3", 3, ""},
+		{"24 cookies", 24, "cookies"},
+		{"2 loaves", 2, "loaves"},
 		{"", 0, ""},
 		{"invalid", 0, ""},
 	}
@@ -260,7 +262,7 @@ Whisk @butter{250%g} with @lemon juice{50%ml}.
 		if ing.Name == "butter" {
 			foundButter = true
 			// 250 * 0.3 = 75
-			if ing.Quantity != 75 {
+			if ing.Quantity.Min() != 75 {
 				t.Errorf("expected butter quantity 75, got %v", ing.Quantity)
 			}
 		}
@@ -319,7 +321,7 @@ func TestResolveAndScaleNoQuantity(t *testing.T) {
 
 	ingredients := recipe.GetIngredients()
 	for _, ing := range ingredients.Ingredients {
-		if ing.Name == "flour" && ing.Quantity != 500 {
+		if ing.Name == "flour" && ing.Quantity.Min() != 500 {
 			t.Errorf("expected flour quantity 500 (unscaled), got %v", ing.Quantity)
 		}
 	}
@@ -0,0 +1,251 @@
+package cooklang
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// RecipeTemplate is a starting point for new recipe files. Its Source is a Cooklang
+// recipe, optionally with frontmatter, that may use text/template actions with the
+// fields of ScaffoldData, e.g. "{{.Title}}" or "{{.Date.Format \"2006-01-02\"}}".
+type RecipeTemplate struct {
+	Name        string // Name used to pick the template, e.g. "baking"
+	Description string // One-line description for template listings
+	Source      string // The template text
+}
+
+// ScaffoldData is the information RecipeTemplate.Scaffold fills in. Zero values are
+// left out, so the template's own frontmatter values are kept.
+type ScaffoldData struct {
+	Title    string
+	Servings float32
+	Tags     []string
+	Author   string
+	Date     time.Time
+	Metadata map[string]string // Additional frontmatter fields
+}
+
+// builtinRecipeTemplates are the templates returned by BuiltinRecipeTemplates.
+var builtinRecipeTemplates = []RecipeTemplate{
+	{
+		Name:        "basic",
+		Description: "A few example steps with an ingredient, cookware and a timer",
+		Source: `-- Steps are separated by blank lines. Mark @ingredients{amount%unit}, #cookware{} and ~{timers%minutes}.
+
+Chop the @onion{1}.
+
+Fry it in a #pan{} with @butter{1%tbsp} for ~{5%minutes}.
+`,
+	},
+	{
+		Name:        "baking",
+		Description: "Dough, baking and finishing sections with an oven temperature",
+		Source: `---
+prep_time: 30 minutes
+total_time: 1 hour
+---
+== Dough ==
+
+Mix @flour{500%g}, @salt{1%tsp} and @water{300%ml} in a #large bowl{}.
+
+Knead for ~{10%minutes} and let rest for ~{1%hour}.
+
+== Baking ==
+
+Preheat the oven to 220°C.
+
+Bake on a #baking sheet{} for ~{25%minutes}.
+
+== Finishing ==
+
+Let cool on a #wire rack{} for ~{15%minutes}.
+`,
+	},
+	{
+		Name:        "cocktail",
+		Description: "A single-serving drink with glass and garnish",
+		Source: `---
+servings: 1
+course: drink
+---
+Pour @gin{50%ml} and @tonic water{150%ml} into a #highball glass{} filled with @ice{}.
+
+Garnish with a @lime wedge{1}.
+`,
+	},
+	{
+		Name:        "blank",
+		Description: "Frontmatter only",
+		Source:      "",
+	},
+}
+
+// BuiltinRecipeTemplates returns the templates that come with the package: "basic",
+// "baking", "cocktail" and "blank".
+//
+// Returns:
+//   - []RecipeTemplate: A copy of the built-in templates
+//
+// Example:
+//
+//	for _, t := range cooklang.BuiltinRecipeTemplates() {
+//	    fmt.Printf("%-10s %s\n", t.Name, t.Description)
+//	}
+func BuiltinRecipeTemplates() []RecipeTemplate {
+	return append([]RecipeTemplate(nil), builtinRecipeTemplates...)
+}
+
+// LoadRecipeTemplates reads user-defined templates from the .cook files in a
+// directory. A template is named after its file ("weeknight.cook" is "weeknight").
+// A comment on the first line ("-- Quick dinner for two") becomes its description
+// and is not copied into new recipes.
+//
+// Parameters:
+//   - dir: The directory with the templates
+//
+// Returns:
+//   - []RecipeTemplate: The templates sorted by name; empty if dir does not exist
+//   - error: An error if the directory or a template cannot be read
+//
+// Example:
+//
+//	templates, err := cooklang.LoadRecipeTemplates(filepath.Join(home, ".config", "cook", "templates"))
+func LoadRecipeTemplates(dir string) ([]RecipeTemplate, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.cook"))
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(dir); errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+
+	var templates []RecipeTemplate
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		source := strings.ReplaceAll(string(content), "\r\n", "\n")
+		t := RecipeTemplate{Name: strings.TrimSuffix(filepath.Base(path), ".cook"), Source: source}
+		firstLine, rest, _ := strings.Cut(source, "\n")
+		if description, ok := strings.CutPrefix(firstLine, "--"); ok {
+			t.Description = strings.TrimSpace(description)
+			t.Source = rest
+		}
+		templates = append(templates, t)
+	}
+	sort.Slice(templates, func(i, j int) bool { return templates[i].Name < templates[j].Name })
+	return templates, nil
+}
+
+// FindRecipeTemplate returns the template with the given name. Later lists take
+// precedence, so user templates passed after the built-in ones override them.
+//
+// Parameters:
+//   - name: The template name
+//   - lists: Template lists to search
+//
+// Returns:
+//   - RecipeTemplate: The template
+//   - bool: false if no template has the name
+//
+// Example:
+//
+//	t, ok := cooklang.FindRecipeTemplate("baking", cooklang.BuiltinRecipeTemplates(), userTemplates)
+func FindRecipeTemplate(name string, lists ...[]RecipeTemplate) (RecipeTemplate, bool) {
+	for i := len(lists) - 1; i >= 0; i-- {
+		for _, t := range lists[i] {
+			if t.Name == name {
+				return t, true
+			}
+		}
+	}
+	return RecipeTemplate{}, false
+}
+
+// Scaffold creates the content of a new recipe file from the template: template
+// actions are filled in with data, and the title, servings, tags, author, date and
+// additional metadata are set in the frontmatter, keeping the template's other
+// frontmatter fields.
+//
+// Parameters:
+//   - data: The values for the new recipe
+//
+// Returns:
+//   - string: The recipe file content
+//   - error: An error if the template is invalid, a value is rejected by the metadata
+//     schema, or the result is not a valid recipe
+//
+// Example:
+//
+//	t, _ := cooklang.FindRecipeTemplate("basic", cooklang.BuiltinRecipeTemplates())
+//	content, err := t.Scaffold(cooklang.ScaffoldData{Title: "Onion Soup", Servings: 4})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	os.WriteFile(cooklang.FilenameFromTitle("Onion Soup"), []byte(content), 0644)
+func (t RecipeTemplate) Scaffold(data ScaffoldData) (string, error) {
+	tmpl, err := template.New(t.Name).Option("missingkey=zero").Parse(t.Source)
+	if err != nil {
+		return "", fmt.Errorf("template %s: %w", t.Name, err)
+	}
+	var body bytes.Buffer
+	if err := tmpl.Execute(&body, data); err != nil {
+		return "", fmt.Errorf("template %s: %w", t.Name, err)
+	}
+
+	editor, err := NewFrontmatterEditorFromBytes(body.Bytes())
+	if err != nil {
+		return "", fmt.Errorf("template %s: %w", t.Name, err)
+	}
+	values := map[string]string{}
+	for key, value := range data.Metadata {
+		values[key] = value
+	}
+	if data.Title != "" {
+		values["title"] = data.Title
+	}
+	if data.Servings > 0 {
+		values["servings"] = fmt.Sprintf("%g", data.Servings)
+	}
+	if len(data.Tags) > 0 {
+		values["tags"] = strings.Join(data.Tags, ", ")
+	}
+	if data.Author != "" {
+		values["author"] = data.Author
+	}
+	if !data.Date.IsZero() {
+		values["date"] = data.Date.Format("2006-01-02")
+	}
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return frontmatterKeyLess(keys[i], keys[j]) })
+	for _, key := range keys {
+		if err := editor.SetMetadata(key, values[key]); err != nil {
+			return "", fmt.Errorf("%s: %w", key, err)
+		}
+	}
+
+	content := editor.GetUpdatedContent()
+	// Order the frontmatter like Format does, so the title comes first
+	if lines, body, err := splitFrontmatter(content); err == nil && len(lines) > 0 {
+		content = "---\n" + strings.Join(formatFrontmatter(lines), "\n") + "\n---\n" + body
+	}
+	if !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	if _, err := ParseString(content); err != nil {
+		return "", fmt.Errorf("template %s does not produce a valid recipe: %w", t.Name, err)
+	}
+	return content, nil
+}
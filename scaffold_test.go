@@ -0,0 +1,114 @@
+package cooklang
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecipeTemplate_Scaffold(t *testing.T) {
+	data := ScaffoldData{
+		Title:    "Onion Soup",
+		Servings: 4,
+		Tags:     []string{"soup", "french"},
+		Author:   "Sam",
+		Date:     time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+		Metadata: map[string]string{"cuisine": "French"},
+	}
+
+	for _, tmpl := range BuiltinRecipeTemplates() {
+		t.Run(tmpl.Name, func(t *testing.T) {
+			content, err := tmpl.Scaffold(data)
+			if err != nil {
+				t.Fatalf("Scaffold failed: %v", err)
+			}
+			if !strings.HasPrefix(content, "---\ntitle: Onion Soup\n") {
+				t.Errorf("expected the title first, got:\n%s", content)
+			}
+			recipe, err := ParseString(content)
+			if err != nil {
+				t.Fatalf("scaffolded recipe does not parse: %v", err)
+			}
+			if recipe.Title != "Onion Soup" || recipe.Servings != 4 || recipe.Author != "Sam" || recipe.Cuisine != "French" ||
+				strings.Join(recipe.Tags, ",") != "soup,french" || recipe.Date != data.Date {
+				t.Errorf("unexpected metadata: %+v", recipe)
+			}
+		})
+	}
+}
+
+func TestRecipeTemplate_ScaffoldKeepsTemplateFrontmatter(t *testing.T) {
+	tmpl := RecipeTemplate{Name: "drink", Source: "---\nservings: 1\ncourse: drink # keep\n---\nShake {{.Title}} with @ice{}.\n"}
+
+	content, err := tmpl.Scaffold(ScaffoldData{Title: "Sour"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "---\ntitle: Sour\nservings: 1\ncourse: drink # keep\n---\nShake Sour with @ice{}.\n"
+	if content != expected {
+		t.Errorf("got:\n%s\nwant:\n%s", content, expected)
+	}
+
+	content, err = tmpl.Scaffold(ScaffoldData{Title: "Sour", Servings: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(content, "servings: 2\n") || strings.Contains(content, "servings: 1") {
+		t.Errorf("expected servings to be replaced, got:\n%s", content)
+	}
+}
+
+func TestRecipeTemplate_ScaffoldErrors(t *testing.T) {
+	if _, err := (RecipeTemplate{Name: "broken", Source: "{{.Title"}).Scaffold(ScaffoldData{}); err == nil {
+		t.Error("expected an error for an invalid template")
+	}
+	if _, err := (RecipeTemplate{Name: "unknown", Source: "{{.Cuisine}}"}).Scaffold(ScaffoldData{}); err == nil {
+		t.Error("expected an error for an unknown field")
+	}
+	if _, err := (RecipeTemplate{Name: "basic"}).Scaffold(ScaffoldData{Metadata: map[string]string{"servings": "lots"}}); err == nil {
+		t.Error("expected an error for invalid servings")
+	}
+}
+
+func TestLoadRecipeTemplates(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"weeknight.cook": "-- Quick dinner for two\nCook {{.Title}}.\n",
+		"basic.cook":     "Just {{.Title}}.\n",
+		"notes.txt":      "not a template",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	templates, err := LoadRecipeTemplates(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(templates) != 2 || templates[0].Name != "basic" || templates[1].Name != "weeknight" {
+		t.Fatalf("unexpected templates: %+v", templates)
+	}
+	if templates[1].Description != "Quick dinner for two" || templates[1].Source != "Cook {{.Title}}.\n" {
+		t.Errorf("unexpected weeknight template: %+v", templates[1])
+	}
+
+	// User templates override built-in ones of the same name
+	basic, ok := FindRecipeTemplate("basic", BuiltinRecipeTemplates(), templates)
+	if !ok || basic.Source != "Just {{.Title}}.\n" {
+		t.Errorf("expected the user's basic template, got %+v", basic)
+	}
+	if _, ok := FindRecipeTemplate("cocktail", BuiltinRecipeTemplates(), templates); !ok {
+		t.Error("expected the built-in cocktail template")
+	}
+	if _, ok := FindRecipeTemplate("missing", BuiltinRecipeTemplates(), templates); ok {
+		t.Error("expected no template named missing")
+	}
+
+	if templates, err := LoadRecipeTemplates(filepath.Join(dir, "missing")); err != nil || templates != nil {
+		t.Errorf("expected no templates for a missing directory, got %v, %v", templates, err)
+	}
+}
@@ -0,0 +1,106 @@
+package cooklang
+
+import (
+	"testing"
+)
+
+func TestScaleWithOptions(t *testing.T) {
+	r, err := ParseString(`---
+servings: 2
+---
+Mix @?flour{500%g}(sifted) in a #bowl{2} and rest for ~{10%minutes}.`)
+	if err != nil {
+		t.Fatalf("Failed to parse recipe: %v", err)
+	}
+
+	t.Run("default options preserve fields", func(t *testing.T) {
+		scaled := r.ScaleWithOptions(2, ScaleOptions{})
+
+		flour := findIngredient(scaled.GetIngredients().Ingredients, "flour")
+		if flour == nil {
+			t.Fatal("flour not found")
+		}
+		if !floatClose(flour.Quantity.Min(), 1000, 0.1) {
+			t.Errorf("flour quantity = %v, want 1000", flour.Quantity)
+		}
+		if !flour.Optional {
+			t.Error("flour should still be optional")
+		}
+		if flour.TypedUnit == nil {
+			t.Error("flour typed unit was dropped")
+		}
+
+		cookware := scaled.GetCookware()
+		if len(cookware) != 1 || cookware[0].Quantity != 2 {
+			t.Errorf("cookware = %+v, want a single bowl with quantity 2", cookware)
+		}
+		timers := scaled.GetTimers()
+		if len(timers) != 1 || timers[0].Duration != "10" {
+			t.Errorf("timers = %+v, want a single 10 minute timer", timers)
+		}
+	})
+
+	t.Run("scale timers and cookware", func(t *testing.T) {
+		scaled := r.ScaleWithOptions(1.5, ScaleOptions{ScaleTimers: true, ScaleCookware: true})
+
+		cookware := scaled.GetCookware()
+		if len(cookware) != 1 || cookware[0].Quantity != 3 {
+			t.Errorf("cookware = %+v, want a single bowl with quantity 3", cookware)
+		}
+		timers := scaled.GetTimers()
+		if len(timers) != 1 || timers[0].Duration != "15" {
+			t.Errorf("timers = %+v, want a single 15 minute timer", timers)
+		}
+	})
+
+	t.Run("scale timer ranges", func(t *testing.T) {
+		r, err := ParseString("Simmer for ~{10-15%minutes}, then ~{overnight}.")
+		if err != nil {
+			t.Fatalf("Failed to parse recipe: %v", err)
+		}
+		timers := r.ScaleWithOptions(2, ScaleOptions{ScaleTimers: true}).GetTimers()
+		if len(timers) != 2 || timers[0].Duration != "20-30" || timers[1].Duration != "overnight" {
+			t.Errorf("timers = %+v, want 20-30 and overnight", timers)
+		}
+	})
+
+	t.Run("original is not modified", func(t *testing.T) {
+		scaled := r.ScaleWithOptions(3, ScaleOptions{ScaleTimers: true, ScaleCookware: true})
+		scaled.Metadata["title"] = "changed"
+
+		flour := findIngredient(r.GetIngredients().Ingredients, "flour")
+		if flour == nil || !floatClose(flour.Quantity.Min(), 500, 0.1) {
+			t.Errorf("original flour changed: %+v", flour)
+		}
+		if _, ok := r.Metadata["title"]; ok {
+			t.Error("original metadata was modified")
+		}
+		if r.FirstStep.FirstComponent == scaled.FirstStep.FirstComponent {
+			t.Error("components are shared between original and scaled recipe")
+		}
+	})
+
+	t.Run("scaling keeps the original quantities", func(t *testing.T) {
+		scaled := r.ScaleWithOptions(2, ScaleOptions{}).ScaleWithOptions(1.5, ScaleOptions{})
+
+		if scaled.OriginalServings != 2 {
+			t.Errorf("original servings = %v, want 2", scaled.OriginalServings)
+		}
+		flour := findIngredient(scaled.GetIngredients().Ingredients, "flour")
+		if flour == nil {
+			t.Fatal("flour not found")
+		}
+		if !floatClose(flour.Quantity.Min(), 1500, 0.1) || !floatClose(flour.OriginalQuantity.Min(), 500, 0.1) {
+			t.Errorf("flour = %v, originally %v; want 1500, originally 500", flour.Quantity, flour.OriginalQuantity)
+		}
+
+		converted := scaled.ConvertToSystem(UnitSystemImperial)
+		flour = findIngredient(converted.GetIngredients().Ingredients, "flour")
+		if flour == nil || flour.Unit == "g" {
+			t.Fatalf("flour was not converted: %+v", flour)
+		}
+		if ratio := flour.Quantity.Min() / flour.OriginalQuantity.Min(); !floatClose(ratio, 3, 0.01) {
+			t.Errorf("converted flour = %v %s, originally %v; want the original in the same unit", flour.Quantity, flour.Unit, flour.OriginalQuantity)
+		}
+	})
+}
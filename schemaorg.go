@@ -0,0 +1,600 @@
+package cooklang
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ldJSONScript matches <script type="application/ld+json"> blocks.
+	ldJSONScript = regexp.MustCompile(`(?is)<script[^>]*type\s*=\s*["']?application/ld\+json["']?[^>]*>(.*?)</script>`)
+	// microdataRecipe matches the element that starts a Schema.org Recipe in microdata.
+	microdataRecipe = regexp.MustCompile(`(?i)itemtype\s*=\s*["']https?://schema\.org/Recipe/?["']`)
+	// microdataProp matches an opening tag with an itemprop attribute.
+	microdataProp = regexp.MustCompile(`(?is)<([a-z][a-z0-9]*)\b([^>]*?)\bitemprop\s*=\s*["']([^"']+)["']([^>]*)>`)
+	// htmlAttr matches a quoted HTML attribute.
+	htmlAttr = regexp.MustCompile(`(?is)\b([a-z-]+)\s*=\s*["']([^"']*)["']`)
+	// htmlTag matches any HTML tag.
+	htmlTag = regexp.MustCompile(`(?s)<[^>]*>`)
+	// isoDuration matches ISO 8601 durations such as "PT1H30M" or "P1DT2H".
+	isoDuration = regexp.MustCompile(`(?i)^P(?:(\d+(?:\.\d+)?)D)?(?:T(?:(\d+(?:\.\d+)?)H)?(?:(\d+(?:\.\d+)?)M)?(?:(\d+(?:\.\d+)?)S)?)?$`)
+	// leadingNumber matches the first number in a text such as "Serves 4".
+	leadingNumber = regexp.MustCompile(`\d+(?:[.,]\d+)?`)
+	// gluedAmount splits amounts written without a space, such as "200g".
+	gluedAmount = regexp.MustCompile(`^(\d+(?:[.,]\d+)?)([a-zA-Zµ]+)\.?$`)
+	// stepTimer matches durations in step text, such as "25 minutes" or "1-2 hours".
+	stepTimer = regexp.MustCompile(`(?i)\b(\d+(?:[.,]\d+)?(?:\s*(?:-|–|to)\s*\d+(?:[.,]\d+)?)?)\s*(seconds?|secs?|minutes?|mins?|hours?|hrs?)\b`)
+	// rangeSeparator matches "to" and dashes between the bounds of a timer range.
+	rangeSeparator = regexp.MustCompile(`\s*(?:–|to)\s*`)
+	// lineBreakTag matches HTML tags that end a line of instructions.
+	lineBreakTag = regexp.MustCompile(`(?i)<br\s*/?>|</p>|</li>`)
+	// cooklangMarker matches characters that would start Cooklang syntax in plain text.
+	cooklangMarker = regexp.MustCompile(`([@#~])(\S)`)
+)
+
+// importUnits maps unit words found in ingredient lines to the units written in Cooklang.
+var importUnits = map[string]string{
+	"g": "g", "gram": "g", "grams": "g", "gr": "g",
+	"kg": "kg", "kilogram": "kg", "kilograms": "kg",
+	"mg": "mg", "milligram": "mg", "milligrams": "mg",
+	"lb": "lb", "lbs": "lb", "pound": "lb", "pounds": "lb",
+	"oz": "oz", "ounce": "oz", "ounces": "oz",
+	"clove": "clove", "cloves": "cloves",
+	"pinch": "pinch", "pinches": "pinches",
+	"can": "can", "cans": "cans",
+	"slice": "slice", "slices": "slices",
+	"sprig": "sprig", "sprigs": "sprigs",
+	"bunch": "bunch", "bunches": "bunches",
+	"handful": "handful", "handfuls": "handfuls",
+	"stick": "stick", "sticks": "sticks",
+	"piece": "piece", "pieces": "pieces",
+	"package": "package", "packages": "packages",
+	"T": "tbsp", "Tbs": "tbsp", "tbs": "tbsp", "t": "tsp",
+}
+
+// ImportSchemaOrg converts a Schema.org Recipe into Cooklang source. The input can be an
+// HTML page with JSON-LD (<script type="application/ld+json">) or microdata markup, or a
+// JSON-LD document. Ingredient lines such as "2 1/2 cups flour, sifted" become
+// @flour{2.5%cup}(sifted) where a step first mentions them, and tools become cookware
+// the same way; whatever no step mentions is gathered in an opening step. Durations in
+// the steps become timers, and the recipe's name, description, yield, times, cuisine,
+// category, keywords, images and author become metadata.
+//
+// Parameters:
+//   - data: The HTML page or JSON-LD document
+//   - source: URL the page was fetched from, stored as "source" metadata (may be empty)
+//
+// Returns:
+//   - string: The recipe in Cooklang format
+//   - error: An error if no Schema.org Recipe is found
+//
+// Example:
+//
+//	page, _ := os.ReadFile("pancakes.html")
+//	text, err := cooklang.ImportSchemaOrg(page, "https://example.com/pancakes")
+//	if err == nil {
+//	    os.WriteFile("Pancakes.cook", []byte(text), 0644)
+//	}
+func ImportSchemaOrg(data []byte, source string) (string, error) {
+	node := findSchemaOrgRecipe(data)
+	if node == nil {
+		return "", fmt.Errorf("no Schema.org Recipe found")
+	}
+	if source != "" && schemaString(node["url"]) == "" {
+		node["url"] = source
+	}
+
+	var result strings.Builder
+	if frontmatter := schemaOrgFrontmatter(node); len(frontmatter) > 0 {
+		result.WriteString("---\n" + strings.Join(frontmatter, "\n") + "\n---\n\n")
+	}
+
+	var ingredients []*importedIngredient
+	for _, line := range schemaStrings(firstOf(node, "recipeIngredient", "ingredients")) {
+		if ingredient := parseIngredientLine(line); ingredient != nil {
+			ingredients = append(ingredients, ingredient)
+		}
+	}
+	for _, tool := range schemaStrings(node["tool"]) {
+		if name := sanitizeCooklangName(tool); name != "" {
+			ingredients = append(ingredients, &importedIngredient{name: name, cookware: true})
+		}
+	}
+
+	var steps []string
+	for _, step := range schemaInstructions(node["recipeInstructions"]) {
+		if step.section != "" {
+			steps = append(steps, "= "+step.section+" =")
+			continue
+		}
+		steps = append(steps, linkStepText(step.text, ingredients))
+	}
+
+	var unused []string
+	for _, ingredient := range ingredients {
+		if !ingredient.used {
+			unused = append(unused, ingredient.cooklang())
+		}
+	}
+	if len(unused) > 0 {
+		steps = append([]string{"Gather " + joinList(unused) + "."}, steps...)
+	}
+	result.WriteString(strings.Join(steps, "\n\n"))
+	result.WriteString("\n")
+	return result.String(), nil
+}
+
+// ParseSchemaOrg converts a Schema.org Recipe with ImportSchemaOrg and parses the result.
+//
+// Parameters:
+//   - data: The HTML page or JSON-LD document
+//   - source: URL the page was fetched from (may be empty)
+//
+// Returns:
+//   - *Recipe: The imported recipe
+//   - error: An error if no Schema.org Recipe is found or the result cannot be parsed
+func ParseSchemaOrg(data []byte, source string) (*Recipe, error) {
+	text, err := ImportSchemaOrg(data, source)
+	if err != nil {
+		return nil, err
+	}
+	return ParseString(text)
+}
+
+// findSchemaOrgRecipe returns the first Schema.org Recipe in a JSON-LD document or in
+// the JSON-LD scripts or microdata of an HTML page.
+func findSchemaOrgRecipe(data []byte) map[string]interface{} {
+	trimmed := strings.TrimSpace(string(data))
+	if strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+		var doc interface{}
+		if err := json.Unmarshal([]byte(trimmed), &doc); err == nil {
+			return findRecipeNode(doc)
+		}
+	}
+
+	for _, m := range ldJSONScript.FindAllStringSubmatch(trimmed, -1) {
+		script := strings.TrimSpace(m[1])
+		script = strings.TrimSuffix(strings.TrimPrefix(script, "<![CDATA["), "]]>")
+		script = strings.TrimSuffix(strings.TrimPrefix(script, "<!--"), "-->")
+		var doc interface{}
+		if err := json.Unmarshal([]byte(script), &doc); err != nil {
+			continue
+		}
+		if node := findRecipeNode(doc); node != nil {
+			return node
+		}
+	}
+	return parseMicrodata(trimmed)
+}
+
+// findRecipeNode searches a JSON-LD value, including @graph lists, for a node typed Recipe.
+func findRecipeNode(doc interface{}) map[string]interface{} {
+	switch value := doc.(type) {
+	case []interface{}:
+		for _, item := range value {
+			if node := findRecipeNode(item); node != nil {
+				return node
+			}
+		}
+	case map[string]interface{}:
+		for _, t := range schemaStrings(value["@type"]) {
+			if strings.EqualFold(t, "Recipe") || strings.HasSuffix(t, "/Recipe") {
+				return value
+			}
+		}
+		return findRecipeNode(value["@graph"])
+	}
+	return nil
+}
+
+// parseMicrodata collects the itemprop values after a Schema.org Recipe itemtype into
+// a JSON-LD style node. Nested items are flattened, so only the first value of
+// single-valued properties is used.
+func parseMicrodata(page string) map[string]interface{} {
+	loc := microdataRecipe.FindStringIndex(page)
+	if loc == nil {
+		return nil
+	}
+	page = page[loc[0]:]
+
+	props := make(map[string][]interface{})
+	for _, m := range microdataProp.FindAllStringSubmatchIndex(page, -1) {
+		tag := strings.ToLower(page[m[2]:m[3]])
+		attrs := make(map[string]string)
+		for _, a := range htmlAttr.FindAllStringSubmatch(page[m[4]:m[5]]+" "+page[m[8]:m[9]], -1) {
+			attrs[strings.ToLower(a[1])] = html.UnescapeString(a[2])
+		}
+		if _, scoped := attrs["itemscope"]; scoped || strings.Contains(strings.ToLower(page[m[0]:m[1]]), "itemscope") {
+			continue
+		}
+
+		value, ok := attrs["content"]
+		if !ok {
+			switch tag {
+			case "img", "source":
+				value = attrs["src"]
+			case "a", "link":
+				value = attrs["href"]
+			case "time":
+				value = attrs["datetime"]
+			}
+		}
+		if value == "" {
+			end := strings.Index(strings.ToLower(page[m[1]:]), "</"+tag)
+			if end < 0 {
+				continue
+			}
+			value = page[m[1] : m[1]+end]
+		}
+		for _, name := range strings.Fields(page[m[6]:m[7]]) {
+			props[name] = append(props[name], value)
+		}
+	}
+
+	node := map[string]interface{}{"@type": "Recipe"}
+	for name, values := range props {
+		node[name] = values
+	}
+	if _, ok := node["recipeInstructions"]; !ok && len(props["text"]) > 0 {
+		node["recipeInstructions"] = props["text"]
+	}
+	return node
+}
+
+// schemaOrgFrontmatter renders the recipe's metadata as YAML frontmatter lines.
+func schemaOrgFrontmatter(node map[string]interface{}) []string {
+	var lines []string
+	add := func(key, value string) {
+		if value != "" {
+			lines = append(lines, renderYAMLValue(key, value)...)
+		}
+	}
+	addList := func(key string, values []string) {
+		if len(values) > 0 {
+			lines = append(lines, key+":")
+			for _, value := range values {
+				lines = append(lines, "  - "+value)
+			}
+		}
+	}
+
+	add("title", schemaString(node["name"]))
+	add("description", schemaString(node["description"]))
+	add("author", schemaString(node["author"]))
+	if yield := schemaString(node["recipeYield"]); yield != "" {
+		servings := leadingNumber.FindString(yield)
+		add("servings", strings.ReplaceAll(servings, ",", "."))
+		if yield != servings {
+			add("yield", yield)
+		}
+	}
+	add("prep_time", humanizeISODuration(schemaString(node["prepTime"])))
+	add("cook_time", humanizeISODuration(schemaString(node["cookTime"])))
+	add("total_time", humanizeISODuration(schemaString(node["totalTime"])))
+	add("cuisine", strings.Join(schemaStrings(node["recipeCuisine"]), ", "))
+	add("category", strings.Join(schemaStrings(node["recipeCategory"]), ", "))
+	if published := schemaString(node["datePublished"]); len(published) >= 10 {
+		if _, err := time.Parse("2006-01-02", published[:10]); err == nil {
+			add("date", published[:10])
+		}
+	}
+
+	var tags []string
+	for _, keyword := range schemaStrings(node["keywords"]) {
+		tags = append(tags, splitAndTrim(keyword)...)
+	}
+	addList("tags", tags)
+	addList("images", schemaStrings(node["image"]))
+	add("source", schemaString(node["url"]))
+	return lines
+}
+
+// humanizeISODuration turns an ISO 8601 duration ("PT1H30M") into "1 hour 30 minutes".
+// Other values are returned as they are.
+func humanizeISODuration(value string) string {
+	m := isoDuration.FindStringSubmatch(strings.TrimSpace(value))
+	if m == nil || value == "P" || strings.EqualFold(value, "PT") {
+		return value
+	}
+	var parts []string
+	for i, unit := range []string{"day", "hour", "minute", "second"} {
+		n, err := strconv.ParseFloat(m[i+1], 64)
+		if err != nil || n == 0 {
+			continue
+		}
+		if n != 1 {
+			unit += "s"
+		}
+		parts = append(parts, strconv.FormatFloat(n, 'f', -1, 64)+" "+unit)
+	}
+	return strings.Join(parts, " ")
+}
+
+// schemaInstruction is a step or the start of a section in recipeInstructions.
+type schemaInstruction struct {
+	text    string
+	section string
+}
+
+// schemaInstructions flattens recipeInstructions, which may be text, a list of strings,
+// HowToStep items, or HowToSection items holding steps.
+func schemaInstructions(value interface{}) []schemaInstruction {
+	var steps []schemaInstruction
+	switch v := value.(type) {
+	case string:
+		text := lineBreakTag.ReplaceAllString(v, "\n")
+		for _, line := range strings.Split(text, "\n") {
+			if line = cleanSchemaText(line); line != "" {
+				steps = append(steps, schemaInstruction{text: line})
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			steps = append(steps, schemaInstructions(item)...)
+		}
+	case map[string]interface{}:
+		if items, ok := v["itemListElement"]; ok {
+			if name := cleanSchemaText(schemaString(v["name"])); name != "" && isSchemaType(v, "HowToSection") {
+				steps = append(steps, schemaInstruction{section: name})
+			}
+			return append(steps, schemaInstructions(items)...)
+		}
+		text := schemaString(v["text"])
+		if text == "" {
+			text = schemaString(v["name"])
+		}
+		return schemaInstructions(text)
+	}
+	return steps
+}
+
+// isSchemaType reports whether a JSON-LD node has the given @type.
+func isSchemaType(node map[string]interface{}, name string) bool {
+	for _, t := range schemaStrings(node["@type"]) {
+		if strings.EqualFold(t, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// firstOf returns the first of the given properties that is set.
+func firstOf(node map[string]interface{}, keys ...string) interface{} {
+	for _, key := range keys {
+		if value, ok := node[key]; ok {
+			return value
+		}
+	}
+	return nil
+}
+
+// schemaString returns the text of a JSON-LD value: a string, a number, the name,
+// url or @value of an object, or the first item of a list.
+func schemaString(value interface{}) string {
+	values := schemaStrings(value)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// schemaStrings returns the texts of a JSON-LD value that may be a single value or a list.
+func schemaStrings(value interface{}) []string {
+	switch v := value.(type) {
+	case string:
+		if text := cleanSchemaText(v); text != "" {
+			return []string{text}
+		}
+	case float64:
+		return []string{strconv.FormatFloat(v, 'f', -1, 64)}
+	case []interface{}:
+		var result []string
+		for _, item := range v {
+			result = append(result, schemaStrings(item)...)
+		}
+		return result
+	case map[string]interface{}:
+		for _, key := range []string{"name", "url", "@value", "text"} {
+			if text := schemaString(v[key]); text != "" {
+				return []string{text}
+			}
+		}
+	}
+	return nil
+}
+
+// cleanSchemaText strips HTML tags and entities and collapses whitespace.
+func cleanSchemaText(text string) string {
+	text = html.UnescapeString(htmlTag.ReplaceAllString(text, " "))
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// importedIngredient is an ingredient line or tool parsed from a Schema.org recipe.
+type importedIngredient struct {
+	name       string
+	quantity   string
+	unit       string
+	annotation string
+	cookware   bool // A tool rather than an ingredient
+	used       bool // Whether a step mentions the ingredient
+}
+
+// cooklang renders the ingredient or cookware in Cooklang syntax.
+func (i *importedIngredient) cooklang() string {
+	if i.cookware {
+		return "#" + i.name + "{}"
+	}
+	amount := i.quantity
+	if i.unit != "" {
+		amount += "%" + i.unit
+	}
+	result := "@" + i.name + "{" + amount + "}"
+	if i.annotation != "" {
+		result += "(" + i.annotation + ")"
+	}
+	return result
+}
+
+// parseIngredientLine splits an ingredient line such as "2 1/2 cups flour, sifted"
+// into quantity, unit, name and annotation. It returns nil for empty lines.
+func parseIngredientLine(line string) *importedIngredient {
+	line = cleanSchemaText(line)
+	ingredient := &importedIngredient{}
+
+	// Parenthesised and comma-separated details become the annotation
+	var notes []string
+	for {
+		open := strings.Index(line, "(")
+		end := strings.Index(line, ")")
+		if open < 0 || end < open {
+			break
+		}
+		notes = append(notes, strings.TrimSpace(line[open+1:end]))
+		line = strings.TrimSpace(line[:open] + " " + line[end+1:])
+	}
+	if name, note, ok := strings.Cut(line, ","); ok {
+		line = name
+		notes = append([]string{strings.TrimSpace(note)}, notes...)
+	}
+
+	fields := strings.Fields(line)
+	if len(fields) > 0 {
+		if m := gluedAmount.FindStringSubmatch(fields[0]); m != nil && lookupImportUnit(m[2]) != "" {
+			fields = append([]string{m[1], m[2]}, fields[1:]...)
+		}
+	}
+	for n := min(3, len(fields)-1); n > 0; n-- {
+		q, err := ParseQuantity(strings.ReplaceAll(strings.Join(fields[:n], " "), ",", "."))
+		if err == nil && !q.IsSome() && !q.IsZero() {
+			ingredient.quantity = q.String()
+			fields = fields[n:]
+			break
+		}
+	}
+	if ingredient.quantity != "" && len(fields) > 1 {
+		if unit := lookupImportUnit(fields[0] + " " + fields[1]); unit != "" {
+			ingredient.unit, fields = unit, fields[2:]
+		} else if unit := lookupImportUnit(fields[0]); unit != "" {
+			ingredient.unit, fields = unit, fields[1:]
+		}
+	}
+	if len(fields) > 1 && strings.EqualFold(fields[0], "of") {
+		fields = fields[1:]
+	}
+
+	ingredient.name = sanitizeCooklangName(strings.Join(fields, " "))
+	ingredient.annotation = strings.NewReplacer("(", "", ")", "").Replace(strings.Join(notes, ", "))
+	if ingredient.name == "" {
+		return nil
+	}
+	return ingredient
+}
+
+// lookupImportUnit returns the Cooklang unit for a unit word, or "" if it is not a unit.
+func lookupImportUnit(word string) string {
+	word = strings.TrimSuffix(word, ".")
+	if unit, ok := importUnits[word]; ok {
+		return unit
+	}
+	if unit, ok := importUnits[strings.ToLower(word)]; ok && len(word) > 1 {
+		return unit
+	}
+	if len(word) > 1 {
+		if unit := GetCocktailUnit(word); unit != nil {
+			return unit.Name
+		}
+	}
+	return ""
+}
+
+// sanitizeCooklangName removes characters that would end an ingredient name early.
+func sanitizeCooklangName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune("@#~{}%()[]", r) {
+			return -1
+		}
+		return r
+	}, name)
+	return strings.Trim(strings.Join(strings.Fields(name), " "), " .,;:-")
+}
+
+// textSpan is a part of a step's text replaced by Cooklang markup.
+type textSpan struct {
+	start, end int
+	markup     string
+}
+
+// linkStepText escapes Cooklang syntax in a step and marks up the first mention of
+// each ingredient that no earlier step used, as well as durations as timers.
+func linkStepText(text string, ingredients []*importedIngredient) string {
+	text = cooklangMarker.ReplaceAllString(text, "$1 $2")
+	text = strings.NewReplacer("--", "–", "[-", "[ -").Replace(text)
+	text = strings.TrimLeft(text, ">= ")
+
+	var spans []textSpan
+	overlaps := func(start, end int) bool {
+		for _, span := range spans {
+			if start < span.end && end > span.start {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, ingredient := range ingredients {
+		if ingredient.used {
+			continue
+		}
+		candidates := []string{ingredient.name}
+		if words := strings.Fields(ingredient.name); len(words) > 1 && len(words[len(words)-1]) > 2 {
+			candidates = append(candidates, words[len(words)-1])
+		}
+		for _, candidate := range candidates {
+			pattern := regexp.MustCompile(`(?i)\b` + regexp.QuoteMeta(candidate) + `(?:e?s)?\b`)
+			for _, loc := range pattern.FindAllStringIndex(text, -1) {
+				if !overlaps(loc[0], loc[1]) {
+					spans = append(spans, textSpan{loc[0], loc[1], ingredient.cooklang()})
+					ingredient.used = true
+					break
+				}
+			}
+			if ingredient.used {
+				break
+			}
+		}
+	}
+
+	for _, m := range stepTimer.FindAllStringSubmatchIndex(text, -1) {
+		if overlaps(m[0], m[1]) {
+			continue
+		}
+		amount := rangeSeparator.ReplaceAllString(text[m[2]:m[3]], "-")
+		amount = strings.ReplaceAll(strings.ReplaceAll(amount, " ", ""), ",", ".")
+		spans = append(spans, textSpan{m[0], m[1], "~{" + amount + "%" + text[m[4]:m[5]] + "}"})
+	}
+
+	sort.Slice(spans, func(i, j int) bool { return spans[i].start < spans[j].start })
+	var result strings.Builder
+	last := 0
+	for _, span := range spans {
+		result.WriteString(text[last:span.start])
+		result.WriteString(span.markup)
+		last = span.end
+	}
+	result.WriteString(text[last:])
+	return result.String()
+}
+
+// joinList joins items as "a, b and c".
+func joinList(items []string) string {
+	if len(items) < 2 {
+		return strings.Join(items, "")
+	}
+	return strings.Join(items[:len(items)-1], ", ") + " and " + items[len(items)-1]
+}
@@ -0,0 +1,167 @@
+package cooklang
+
+import (
+	"strings"
+	"testing"
+)
+
+const schemaOrgPage = `<!DOCTYPE html>
+<html><head>
+<title>Pancakes | Example Kitchen</title>
+<script type="application/ld+json">
+{
+  "@context": "https://schema.org",
+  "@graph": [
+    {"@type": "WebSite", "name": "Example Kitchen"},
+    {
+      "@type": "Recipe",
+      "name": "Fluffy Pancakes",
+      "description": "Weekend pancakes: light &amp; fluffy.",
+      "author": {"@type": "Person", "name": "Sam Cook"},
+      "recipeYield": ["4", "4 servings"],
+      "prepTime": "PT10M",
+      "cookTime": "PT1H5M",
+      "recipeCuisine": "American",
+      "recipeCategory": ["Breakfast"],
+      "keywords": "pancakes, brunch",
+      "image": [{"@type": "ImageObject", "url": "https://example.com/pancakes.jpg"}],
+      "recipeIngredient": [
+        "2 1/2 cups all-purpose flour, sifted",
+        "2 eggs",
+        "300ml milk (whole)",
+        "1 tablespoon sugar",
+        "salt"
+      ],
+      "recipeInstructions": [
+        {"@type": "HowToSection", "name": "Batter", "itemListElement": [
+          {"@type": "HowToStep", "text": "Whisk the flour, milk and eggs together."},
+          {"@type": "HowToStep", "text": "Rest the batter for 10-15 minutes."}
+        ]},
+        {"@type": "HowToStep", "text": "Fry in a pan for 2 minutes per side. Email me@example.com -- enjoy!"}
+      ]
+    }
+  ]
+}
+</script>
+</head><body></body></html>`
+
+func TestImportSchemaOrgJSONLD(t *testing.T) {
+	text, err := ImportSchemaOrg([]byte(schemaOrgPage), "https://example.com/pancakes")
+	if err != nil {
+		t.Fatalf("ImportSchemaOrg failed: %v", err)
+	}
+
+	for _, want := range []string{
+		"title: Fluffy Pancakes",
+		"author: Sam Cook",
+		"servings: 4",
+		"prep_time: 10 minutes",
+		"cook_time: 1 hour 5 minutes",
+		"cuisine: American",
+		"category: Breakfast",
+		"  - https://example.com/pancakes.jpg",
+		"source: https://example.com/pancakes",
+		"Gather @sugar{1%tbsp} and @salt{}.",
+		"= Batter =",
+		"Whisk the @all-purpose flour{2.5%cup}(sifted), @milk{300%ml}(whole) and @eggs{2} together.",
+		"Rest the batter for ~{10-15%minutes}.",
+		"Fry in a pan for ~{2%minutes} per side. Email me@ example.com – enjoy!",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("imported recipe is missing %q:\n%s", want, text)
+		}
+	}
+
+	recipe, err := ParseSchemaOrg([]byte(schemaOrgPage), "")
+	if err != nil {
+		t.Fatalf("ParseSchemaOrg failed: %v", err)
+	}
+	if recipe.Title != "Fluffy Pancakes" || recipe.Description != "Weekend pancakes: light & fluffy." || recipe.Servings != 4 {
+		t.Errorf("unexpected metadata: title %q, description %q, servings %v", recipe.Title, recipe.Description, recipe.Servings)
+	}
+	if got := strings.Join(recipe.Tags, ","); got != "pancakes,brunch" {
+		t.Errorf("expected tags pancakes,brunch, got %q", got)
+	}
+
+	ingredients := recipe.GetIngredients().Ingredients
+	if len(ingredients) != 5 {
+		t.Fatalf("expected 5 ingredients, got %d", len(ingredients))
+	}
+	flour := ingredients[2]
+	if flour.Name != "all-purpose flour" || flour.Quantity.Min() != 2.5 || flour.Unit != "cup" || flour.Annotation != "sifted" {
+		t.Errorf("unexpected flour: %+v", flour)
+	}
+}
+
+func TestImportSchemaOrgMicrodata(t *testing.T) {
+	page := `<div itemscope itemtype="http://schema.org/Recipe">
+  <h1 itemprop="name">Garlic Bread</h1>
+  <meta itemprop="totalTime" content="PT20M">
+  <span itemprop="recipeYield">Serves 6</span>
+  <ul>
+    <li itemprop="recipeIngredient">1 baguette</li>
+    <li itemprop="recipeIngredient">3 cloves garlic, crushed</li>
+    <li itemprop="recipeIngredient">50g butter</li>
+  </ul>
+  <div itemprop="recipeInstructions"><p>Mix the butter and garlic.</p><p>Spread on the baguette and bake for 8 minutes.</p></div>
+</div>`
+
+	text, err := ImportSchemaOrg([]byte(page), "")
+	if err != nil {
+		t.Fatalf("ImportSchemaOrg failed: %v", err)
+	}
+	for _, want := range []string{
+		"title: Garlic Bread",
+		"total_time: 20 minutes",
+		"servings: 6",
+		"yield: Serves 6",
+		"Mix the @butter{50%g} and @garlic{3%cloves}(crushed).",
+		"Spread on the @baguette{1} and bake for ~{8%minutes}.",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("imported recipe is missing %q:\n%s", want, text)
+		}
+	}
+}
+
+func TestImportSchemaOrgNoRecipe(t *testing.T) {
+	for _, input := range []string{
+		`<html><body><p>No recipe here</p></body></html>`,
+		`{"@type": "Article", "name": "News"}`,
+	} {
+		if _, err := ImportSchemaOrg([]byte(input), ""); err == nil {
+			t.Errorf("expected an error for %q", input)
+		}
+	}
+}
+
+func TestParseIngredientLine(t *testing.T) {
+	tests := []struct {
+		line string
+		want string
+	}{
+		{"2 cups flour", "@flour{2%cup}"},
+		{"1 ½ tsp baking powder", "@baking powder{1.5%tsp}"},
+		{"200g dark chocolate, chopped", "@dark chocolate{200%g}(chopped)"},
+		{"1 to 2 fl oz lime juice", "@lime juice{1-2%fl oz}"},
+		{"1 pinch of salt", "@salt{1%pinch}"},
+		{"3 large eggs (room temperature)", "@large eggs{3}(room temperature)"},
+		{"Salt and pepper to taste", "@Salt and pepper to taste{}"},
+		{"1 T olive oil", "@olive oil{1%tbsp}"},
+		{"1 t vanilla", "@vanilla{1%tsp}"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			ingredient := parseIngredientLine(tt.line)
+			if ingredient == nil {
+				t.Fatal("expected an ingredient")
+			}
+			if got := ingredient.cooklang(); got != tt.want {
+				t.Errorf("parseIngredientLine(%q) = %s, want %s", tt.line, got, tt.want)
+			}
+		})
+	}
+	if parseIngredientLine("  ") != nil {
+		t.Error("expected nil for an empty line")
+	}
+}
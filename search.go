@@ -0,0 +1,609 @@
+package cooklang
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// searchIndexVersion is bumped whenever the persisted index format changes;
+// indexes with another version are rebuilt from scratch.
+const searchIndexVersion = 2
+
+// fuzzyThreshold is the minimum similarity for a fuzzy ingredient match.
+const fuzzyThreshold = 0.75
+
+// IndexedRecipe is the searchable summary of a recipe kept in a SearchIndex.
+type IndexedRecipe struct {
+	Path        string        `json:"path"` // Path relative to the index root, using forward slashes
+	Title       string        `json:"title"`
+	Cuisine     string        `json:"cuisine,omitempty"`
+	Description string        `json:"description,omitempty"`
+	Tags        []string      `json:"tags,omitempty"`
+	Ingredients []string      `json:"ingredients,omitempty"`
+	TotalTime   time.Duration `json:"total_time,omitempty"` // Zero when the recipe declares no parseable time
+	Difficulty  Difficulty    `json:"difficulty,omitempty"` // DifficultyUnknown when the recipe declares no known level
+	ModTime     time.Time     `json:"mod_time,omitempty"`   // Source file modification time, used to refresh persisted indexes
+	Size        int64         `json:"size,omitempty"`       // Source file size, used to refresh persisted indexes
+}
+
+// SearchIndex holds searchable summaries of a recipe collection. It can be built from
+// a Library, or loaded from and saved to disk so that large collections start quickly:
+// LoadSearchIndex only re-parses files that changed since the index was saved.
+//
+// Example:
+//
+//	index, err := cooklang.LoadSearchIndex("recipes", "recipes/.cook-index.json")
+//	if err != nil {
+//	    log.Printf("some recipes could not be indexed: %v", err)
+//	}
+//	_ = index.Save("recipes/.cook-index.json")
+//
+//	for _, result := range index.Query().Ingredient("gin").Tag("cocktail").Results() {
+//	    fmt.Printf("%.1f %s\n", result.Score, result.Recipe.Title)
+//	}
+type SearchIndex struct {
+	Version int              `json:"version"`
+	Recipes []*IndexedRecipe `json:"recipes"`
+
+	entries map[string]*LibraryEntry // Set when built from a Library
+}
+
+// SearchResult is a recipe matched by a Query, with its relevance score.
+type SearchResult struct {
+	Recipe  *IndexedRecipe `json:"recipe"`
+	Entry   *LibraryEntry  `json:"-"`       // The full recipe, when the index was built from a Library
+	Score   float64        `json:"score"`   // Higher is more relevant
+	Matches []string       `json:"matches"` // Human-readable reasons, e.g. "ingredient: gin"
+}
+
+// Index builds a search index over the library's recipes.
+func (l *Library) Index() *SearchIndex {
+	index := &SearchIndex{Version: searchIndexVersion, entries: make(map[string]*LibraryEntry)}
+	for _, entry := range l.Entries {
+		indexed := newIndexedRecipe(entry.Path, entry.Recipe)
+		indexed.Title = entry.Name()
+		index.Recipes = append(index.Recipes, indexed)
+		index.entries[entry.Path] = entry
+	}
+	return index
+}
+
+// Query starts a query over the library's recipes.
+//
+// Example:
+//
+//	results := library.Query().Ingredient("gin").MaxTotalTime(30 * time.Minute).Tag("cocktail").Results()
+func (l *Library) Query() *Query {
+	return l.Index().Query()
+}
+
+// newIndexedRecipe summarizes a parsed recipe for searching.
+func newIndexedRecipe(path string, recipe *Recipe) *IndexedRecipe {
+	indexed := &IndexedRecipe{
+		Path:        path,
+		Title:       recipe.Title,
+		Cuisine:     recipe.Cuisine,
+		Description: recipe.Description,
+		Tags:        append([]string(nil), recipe.Tags...),
+		TotalTime:   recipeTotalTime(recipe),
+		Difficulty:  recipe.DifficultyLevel(),
+	}
+	if indexed.Title == "" {
+		indexed.Title = TitleFromFilename(path)
+	}
+	seen := make(map[string]bool)
+	for _, ingredient := range recipe.GetIngredients().Ingredients {
+		if key := libraryKey(ingredient.Name); !seen[key] {
+			seen[key] = true
+			indexed.Ingredients = append(indexed.Ingredients, ingredient.Name)
+		}
+	}
+	return indexed
+}
+
+// recipeTotalTime returns the recipe's total time (see Recipe.Times), falling back to
+// prep_time or cook_time when only one of them is set.
+func recipeTotalTime(recipe *Recipe) time.Duration {
+	times := recipe.Times()
+	if times.Total > 0 {
+		return times.Total
+	}
+	return times.Prep + times.Cook
+}
+
+var humanDurationPattern = regexp.MustCompile(`(\d+(?:\.\d+)?)\s*(hours?|hrs?|minutes?|mins?|seconds?|secs?|h|m|s)`)
+
+// parseHumanDuration parses durations such as "1 hour 30 minutes", "1h30m", "90 min"
+// or a plain number of minutes.
+func parseHumanDuration(s string) (time.Duration, bool) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "" {
+		return 0, false
+	}
+	if minutes, err := strconv.ParseFloat(s, 64); err == nil {
+		return time.Duration(minutes * float64(time.Minute)), minutes > 0
+	}
+
+	var total time.Duration
+	matches := humanDurationPattern.FindAllStringSubmatch(s, -1)
+	for _, match := range matches {
+		value, err := strconv.ParseFloat(match[1], 64)
+		if err != nil {
+			continue
+		}
+		unit := time.Second
+		switch match[2][0] {
+		case 'h':
+			unit = time.Hour
+		case 'm':
+			unit = time.Minute
+		}
+		total += time.Duration(value * float64(unit))
+	}
+	return total, total > 0
+}
+
+// BuildSearchIndex parses every .cook file under dir into a new search index.
+//
+// Parameters:
+//   - dir: The directory to scan
+//
+// Returns:
+//   - *SearchIndex: The index, containing every recipe that parsed successfully
+//   - error: An error joining all walk and parse failures, or nil
+func BuildSearchIndex(dir string) (*SearchIndex, error) {
+	return refreshSearchIndex(dir, nil)
+}
+
+// LoadSearchIndex loads a search index previously written with Save and brings it up
+// to date with dir: files whose size and modification time are unchanged are taken
+// from the saved index, new or changed files are parsed, and deleted files are dropped.
+// A missing, unreadable or outdated index file results in a full rebuild.
+//
+// Parameters:
+//   - dir: The recipe directory the index covers
+//   - indexFile: The saved index file
+//
+// Returns:
+//   - *SearchIndex: The up-to-date index
+//   - error: An error joining all walk and parse failures, or nil
+func LoadSearchIndex(dir, indexFile string) (*SearchIndex, error) {
+	var cached *SearchIndex
+	if data, err := os.ReadFile(indexFile); err == nil {
+		var saved SearchIndex
+		if json.Unmarshal(data, &saved) == nil && saved.Version == searchIndexVersion {
+			cached = &saved
+		}
+	}
+	return refreshSearchIndex(dir, cached)
+}
+
+// refreshSearchIndex walks dir, reusing unchanged recipes from cached.
+func refreshSearchIndex(dir string, cached *SearchIndex) (*SearchIndex, error) {
+	previous := make(map[string]*IndexedRecipe)
+	if cached != nil {
+		for _, recipe := range cached.Recipes {
+			previous[recipe.Path] = recipe
+		}
+	}
+
+	index := &SearchIndex{Version: searchIndexVersion}
+	errs := walkCookFiles(dir, func(path, rel string) error {
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		if old, ok := previous[rel]; ok && old.Size == info.Size() && old.ModTime.Equal(info.ModTime()) {
+			index.Recipes = append(index.Recipes, old)
+			return nil
+		}
+
+		recipe, err := ParseFile(path)
+		if err != nil {
+			return err
+		}
+		indexed := newIndexedRecipe(rel, recipe)
+		indexed.ModTime = info.ModTime()
+		indexed.Size = info.Size()
+		index.Recipes = append(index.Recipes, indexed)
+		return nil
+	})
+
+	sort.Slice(index.Recipes, func(i, j int) bool {
+		return index.Recipes[i].Path < index.Recipes[j].Path
+	})
+	return index, errors.Join(errs...)
+}
+
+// Save writes the index to a JSON file, for use with LoadSearchIndex.
+func (idx *SearchIndex) Save(path string) error {
+	data, err := json.Marshal(idx)
+	if err != nil {
+		return fmt.Errorf("failed to encode search index: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write search index: %w", err)
+	}
+	return nil
+}
+
+// Query starts a query over the index.
+func (idx *SearchIndex) Query() *Query {
+	return &Query{index: idx}
+}
+
+// Query is a builder for structured recipe searches. Filters (Tag, Cuisine,
+// Ingredient, ExcludeIngredient, MaxTotalTime, Difficulty) must all hold for a recipe to match;
+// free-text terms must each appear somewhere in the recipe. Matching recipes are
+// ranked by how well they match, with title matches weighing most.
+//
+// Ingredient names are matched fuzzily, so "tomato" finds "tomatoes" and
+// "gin" finds "london dry gin".
+//
+// Example:
+//
+//	results := library.Query().
+//	    Ingredient("gin").
+//	    MaxTotalTime(30 * time.Minute).
+//	    Tag("cocktail").
+//	    Results()
+type Query struct {
+	index        *SearchIndex
+	terms        []string
+	title        string
+	tags         []string
+	cuisine      string
+	ingredients  []string
+	excluded     []string
+	maxTotalTime time.Duration
+	difficulties []Difficulty
+	limit        int
+}
+
+// Text adds free-text terms; each whitespace-separated word must match the title,
+// a tag, the cuisine, an ingredient or the description.
+func (q *Query) Text(text string) *Query {
+	for _, term := range strings.Fields(text) {
+		q.terms = append(q.terms, libraryKey(term))
+	}
+	return q
+}
+
+// Title requires the title to contain the given text.
+func (q *Query) Title(title string) *Query {
+	q.title = libraryKey(title)
+	return q
+}
+
+// Tag requires the recipe to have the tag. Call it repeatedly to require several tags.
+func (q *Query) Tag(tag string) *Query {
+	q.tags = append(q.tags, libraryKey(tag))
+	return q
+}
+
+// Cuisine requires the recipe to be of the given cuisine.
+func (q *Query) Cuisine(cuisine string) *Query {
+	q.cuisine = libraryKey(cuisine)
+	return q
+}
+
+// Ingredient requires the recipe to use an ingredient matching name (fuzzily).
+// Call it repeatedly to require several ingredients.
+func (q *Query) Ingredient(name string) *Query {
+	q.ingredients = append(q.ingredients, libraryKey(name))
+	return q
+}
+
+// ExcludeIngredient rejects recipes using an ingredient matching name (fuzzily).
+func (q *Query) ExcludeIngredient(name string) *Query {
+	q.excluded = append(q.excluded, libraryKey(name))
+	return q
+}
+
+// MaxTotalTime requires the recipe's total time to be known and at most d.
+func (q *Query) MaxTotalTime(d time.Duration) *Query {
+	q.maxTotalTime = d
+	return q
+}
+
+// Difficulty requires the recipe's difficulty to be one of the given levels.
+func (q *Query) Difficulty(levels ...Difficulty) *Query {
+	q.difficulties = append(q.difficulties, levels...)
+	return q
+}
+
+// Limit caps the number of results; zero means no limit.
+func (q *Query) Limit(n int) *Query {
+	q.limit = n
+	return q
+}
+
+// Results runs the query and returns matching recipes, most relevant first.
+// Recipes with equal scores are ordered by title.
+func (q *Query) Results() []SearchResult {
+	var results []SearchResult
+	for _, recipe := range q.index.Recipes {
+		result, ok := q.match(recipe)
+		if !ok {
+			continue
+		}
+		result.Entry = q.index.entries[recipe.Path]
+		results = append(results, result)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Recipe.Title < results[j].Recipe.Title
+	})
+	if q.limit > 0 && len(results) > q.limit {
+		results = results[:q.limit]
+	}
+	return results
+}
+
+// match scores a single recipe against the query.
+func (q *Query) match(recipe *IndexedRecipe) (SearchResult, bool) {
+	result := SearchResult{Recipe: recipe, Matches: []string{}}
+	title := libraryKey(recipe.Title)
+
+	if q.title != "" {
+		if !strings.Contains(title, q.title) {
+			return result, false
+		}
+		result.add(5, "title: "+recipe.Title)
+	}
+	for _, tag := range q.tags {
+		if !containsKey(recipe.Tags, tag) {
+			return result, false
+		}
+		result.add(3, "tag: "+tag)
+	}
+	if q.cuisine != "" {
+		if libraryKey(recipe.Cuisine) != q.cuisine {
+			return result, false
+		}
+		result.add(2, "cuisine: "+recipe.Cuisine)
+	}
+	for _, wanted := range q.ingredients {
+		name, similarity := bestIngredientMatch(recipe.Ingredients, wanted)
+		if similarity < fuzzyThreshold {
+			return result, false
+		}
+		result.add(4*similarity, "ingredient: "+name)
+	}
+	for _, unwanted := range q.excluded {
+		if _, similarity := bestIngredientMatch(recipe.Ingredients, unwanted); similarity >= fuzzyThreshold {
+			return result, false
+		}
+	}
+	if q.maxTotalTime > 0 {
+		if recipe.TotalTime <= 0 || recipe.TotalTime > q.maxTotalTime {
+			return result, false
+		}
+		result.add(1, "time: "+recipe.TotalTime.String())
+	}
+	if len(q.difficulties) > 0 {
+		if !slices.Contains(q.difficulties, recipe.Difficulty) {
+			return result, false
+		}
+		result.add(1, "difficulty: "+string(recipe.Difficulty))
+	}
+
+	for _, term := range q.terms {
+		score, reason := scoreTerm(recipe, title, term)
+		if score == 0 {
+			return result, false
+		}
+		result.add(score, reason)
+	}
+	return result, true
+}
+
+// add records a match and its contribution to the score.
+func (r *SearchResult) add(score float64, reason string) {
+	r.Score += score
+	r.Matches = append(r.Matches, reason)
+}
+
+// scoreTerm scores a free-text term against the best-matching field of a recipe.
+func scoreTerm(recipe *IndexedRecipe, title, term string) (float64, string) {
+	switch {
+	case title == term:
+		return 10, "title: " + recipe.Title
+	case strings.Contains(title, term):
+		return 5, "title: " + recipe.Title
+	case containsKey(recipe.Tags, term):
+		return 3, "tag: " + term
+	}
+	if name, similarity := bestIngredientMatch(recipe.Ingredients, term); similarity >= fuzzyThreshold {
+		return 2 * similarity, "ingredient: " + name
+	}
+	if strings.Contains(libraryKey(recipe.Cuisine), term) {
+		return 2, "cuisine: " + recipe.Cuisine
+	}
+	if strings.Contains(libraryKey(recipe.Description), term) {
+		return 1, "description"
+	}
+	return 0, ""
+}
+
+// containsKey reports whether values contains key, case-insensitively.
+func containsKey(values []string, key string) bool {
+	for _, value := range values {
+		if libraryKey(value) == key {
+			return true
+		}
+	}
+	return false
+}
+
+// bestIngredientMatch returns the ingredient most similar to wanted and its similarity.
+func bestIngredientMatch(ingredients []string, wanted string) (string, float64) {
+	var best string
+	var bestScore float64
+	for _, ingredient := range ingredients {
+		if score := ingredientSimilarity(libraryKey(ingredient), wanted); score > bestScore {
+			best, bestScore = ingredient, score
+		}
+	}
+	return best, bestScore
+}
+
+// ingredientSimilarity scores how well a query matches an ingredient name, from 0 to 1.
+// Exact matches score 1, a query naming one of the ingredient's words scores 0.9,
+// and otherwise the edit-distance similarity of the closest word or the whole name is used.
+func ingredientSimilarity(name, query string) float64 {
+	if name == query {
+		return 1
+	}
+	words := strings.Fields(name)
+	for _, word := range words {
+		if word == query {
+			return 0.9
+		}
+	}
+	best := similarity(name, query)
+	for _, word := range words {
+		if s := similarity(word, query); s > best {
+			best = s
+		}
+	}
+	return best
+}
+
+// similarity returns 1 minus the normalized Levenshtein distance between a and b.
+func similarity(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	longest := len(ra)
+	if len(rb) > longest {
+		longest = len(rb)
+	}
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(levenshtein(ra, rb))/float64(longest)
+}
+
+// levenshtein returns the edit distance between a and b.
+func levenshtein(a, b []rune) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+// ParseQuery parses a search string into a query over the index. Plain words are
+// free-text terms; the following qualifiers are supported (values containing spaces
+// can be quoted, e.g. ingredient:"lime juice"):
+//
+//	tag:cocktail        recipe has the tag
+//	cuisine:italian     recipe is of the cuisine
+//	ingredient:gin      recipe uses the ingredient (also i:gin)
+//	-ingredient:olives  recipe does not use the ingredient (also -i:olives)
+//	title:negroni       title contains the text
+//	time:30m            total time is at most 30 minutes (also time<30m)
+//	difficulty:easy     difficulty is the level, see ParseDifficulty (also difficulty:easy,medium)
+//
+// Parameters:
+//   - query: The search string
+//
+// Returns:
+//   - *Query: The parsed query, ready to run with Results
+//   - error: An error for an unknown qualifier, invalid time or unknown difficulty
+//
+// Example:
+//
+//	query, err := index.ParseQuery(`tag:cocktail ingredient:gin time<10m bitter`)
+func (idx *SearchIndex) ParseQuery(query string) (*Query, error) {
+	q := idx.Query()
+	for _, token := range splitQuery(query) {
+		key, value, ok := strings.Cut(token, ":")
+		if !ok {
+			if strings.HasPrefix(token, "time<") {
+				key, value, ok = "time", strings.TrimPrefix(token, "time<"), true
+			} else {
+				q.Text(token)
+				continue
+			}
+		}
+		value = strings.Trim(value, `"`)
+
+		switch strings.ToLower(key) {
+		case "tag":
+			q.Tag(value)
+		case "cuisine":
+			q.Cuisine(value)
+		case "ingredient", "i":
+			q.Ingredient(value)
+		case "-ingredient", "-i":
+			q.ExcludeIngredient(value)
+		case "title":
+			q.Title(value)
+		case "time":
+			d, ok := parseHumanDuration(value)
+			if !ok {
+				return nil, fmt.Errorf("invalid time %q in query", value)
+			}
+			q.MaxTotalTime(d)
+		case "difficulty":
+			for _, name := range strings.Split(value, ",") {
+				level, err := ParseDifficulty(name)
+				if err != nil || level == DifficultyUnknown {
+					return nil, fmt.Errorf("invalid difficulty %q in query (use easy, medium or hard)", name)
+				}
+				q.Difficulty(level)
+			}
+		default:
+			return nil, fmt.Errorf("unknown search qualifier %q", key)
+		}
+	}
+	return q, nil
+}
+
+// splitQuery splits a query on whitespace, keeping double-quoted sections together.
+func splitQuery(query string) []string {
+	var tokens []string
+	var current strings.Builder
+	inQuotes := false
+	for _, r := range query {
+		switch {
+		case r == '"':
+			inQuotes = !inQuotes
+			current.WriteRune(r)
+		case (r == ' ' || r == '\t') && !inQuotes:
+			if current.Len() > 0 {
+				tokens = append(tokens, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if current.Len() > 0 {
+		tokens = append(tokens, current.String())
+	}
+	return tokens
+}
@@ -0,0 +1,215 @@
+package cooklang
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseHumanDuration(t *testing.T) {
+	tests := []struct {
+		input string
+		want  time.Duration
+		ok    bool
+	}{
+		{"5 minutes", 5 * time.Minute, true},
+		{"1 hour 30 minutes", 90 * time.Minute, true},
+		{"1h30m", 90 * time.Minute, true},
+		{"1.5 hours", 90 * time.Minute, true},
+		{"45 sec", 45 * time.Second, true},
+		{"20", 20 * time.Minute, true},
+		{"overnight", 0, false},
+		{"", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseHumanDuration(tt.input)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("parseHumanDuration(%q) = %v, %v; want %v, %v", tt.input, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestIngredientSimilarity(t *testing.T) {
+	tests := []struct {
+		name, query string
+		match       bool
+	}{
+		{"gin", "gin", true},
+		{"london dry gin", "gin", true},
+		{"tomatoes", "tomato", true},
+		{"campari", "campary", true},
+		{"rum", "gin", false},
+		{"vermouth", "vodka", false},
+	}
+	for _, tt := range tests {
+		score := ingredientSimilarity(tt.name, tt.query)
+		if (score >= fuzzyThreshold) != tt.match {
+			t.Errorf("ingredientSimilarity(%q, %q) = %.2f, want match=%v", tt.name, tt.query, score, tt.match)
+		}
+	}
+}
+
+// newSearchLibrary builds a small library of drinks for query tests.
+func newSearchLibrary(t *testing.T) *Library {
+	t.Helper()
+	recipes := map[string]string{
+		"negroni.cook":  "---\ntitle: Negroni\ntags: cocktail, bitter\ncuisine: Italian\ntime: 5 minutes\ndifficulty: easy\n---\nStir @gin{30%ml}, @vermouth{30%ml} and @Campari{30%ml}.",
+		"martini.cook":  "---\ntitle: Dry Martini\ntags: cocktail\ntotal_time: 3 min\ndifficulty: Advanced\ndescription: Very cold and very dry\n---\nStir @london dry gin{60%ml} with @vermouth{10%ml}. Add @olives{1}.",
+		"punch.cook":    "---\ntitle: Gin Punch\ntags: cocktail, party\ntotal_time: 1 hour\ndifficulty: leicht\n---\nMix @gin{700%ml} with @lemons{4} and @tomatoes{}.",
+		"daiquiri.cook": "---\ntitle: Daiquiri\ntags: cocktail\ntotal_time: 5 min\n---\nShake @rum{60%ml} and @lime juice{25%ml}.",
+	}
+	library := NewLibrary()
+	for path, content := range recipes {
+		recipe, err := ParseString(content)
+		if err != nil {
+			t.Fatal(err)
+		}
+		library.Add(path, recipe)
+	}
+	return library
+}
+
+func resultTitles(results []SearchResult) string {
+	titles := make([]string, 0, len(results))
+	for _, result := range results {
+		titles = append(titles, result.Recipe.Title)
+	}
+	return strings.Join(titles, ",")
+}
+
+func TestQueryBuilder(t *testing.T) {
+	library := newSearchLibrary(t)
+
+	tests := []struct {
+		name  string
+		query *Query
+		want  string
+	}{
+		{"ingredient", library.Query().Ingredient("gin"), "Gin Punch,Negroni,Dry Martini"},
+		{"ingredient and time", library.Query().Ingredient("gin").MaxTotalTime(30 * time.Minute).Tag("cocktail"), "Negroni,Dry Martini"},
+		{"fuzzy ingredient", library.Query().Ingredient("tomato"), "Gin Punch"},
+		{"exclude", library.Query().Ingredient("gin").ExcludeIngredient("olive"), "Gin Punch,Negroni"},
+		{"cuisine", library.Query().Cuisine("italian"), "Negroni"},
+		{"title ranks first", library.Query().Text("gin"), "Gin Punch,Negroni,Dry Martini"},
+		{"text in description", library.Query().Text("cold"), "Dry Martini"},
+		{"all terms must match", library.Query().Text("gin rum"), ""},
+		{"limit", library.Query().Tag("cocktail").Limit(2), "Daiquiri,Dry Martini"},
+		{"difficulty", library.Query().Difficulty(DifficultyEasy), "Gin Punch,Negroni"},
+		{"difficulties", library.Query().Difficulty(DifficultyMedium, DifficultyHard), "Dry Martini"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := resultTitles(tt.query.Results()); got != tt.want {
+				t.Errorf("Results() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	results := library.Query().Ingredient("campary").Results()
+	if len(results) != 1 || results[0].Entry == nil || results[0].Entry.Path != "negroni.cook" {
+		t.Fatalf("expected Negroni entry, got %+v", results)
+	}
+	if results[0].Matches[0] != "ingredient: Campari" {
+		t.Errorf("unexpected match reasons: %v", results[0].Matches)
+	}
+}
+
+func TestParseQuery(t *testing.T) {
+	index := newSearchLibrary(t).Index()
+
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{`tag:cocktail i:gin time<10m`, "Negroni,Dry Martini"},
+		{`ingredient:"lime juice"`, "Daiquiri"},
+		{`tag:bitter negroni`, "Negroni"},
+		{`-i:gin tag:cocktail`, "Daiquiri"},
+		{`title:martini`, "Dry Martini"},
+		{`difficulty:easy i:gin`, "Gin Punch,Negroni"},
+		{`difficulty:simple,hard title:martini`, "Dry Martini"},
+	}
+	for _, tt := range tests {
+		q, err := index.ParseQuery(tt.query)
+		if err != nil {
+			t.Fatalf("ParseQuery(%q) failed: %v", tt.query, err)
+		}
+		if got := resultTitles(q.Results()); got != tt.want {
+			t.Errorf("ParseQuery(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+
+	if _, err := index.ParseQuery("colour:red"); err == nil {
+		t.Error("expected error for unknown qualifier")
+	}
+	if _, err := index.ParseQuery("time:soon"); err == nil {
+		t.Error("expected error for invalid time")
+	}
+	if _, err := index.ParseQuery("difficulty:tricky"); err == nil {
+		t.Error("expected error for unknown difficulty")
+	}
+}
+
+func TestSearchIndexPersistence(t *testing.T) {
+	dir := t.TempDir()
+	indexFile := filepath.Join(t.TempDir(), "index.json")
+	if err := writeFile(filepath.Join(dir, "a.cook"), "---\ntitle: Alpha\n---\nUse @gin{}."); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeFile(filepath.Join(dir, "b.cook"), "---\ntitle: Beta\n---\nUse @rum{}."); err != nil {
+		t.Fatal(err)
+	}
+
+	index, err := BuildSearchIndex(dir)
+	if err != nil {
+		t.Fatalf("BuildSearchIndex failed: %v", err)
+	}
+	if err := index.Save(indexFile); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	// Change b.cook, delete a.cook and add c.cook
+	if err := writeFile(filepath.Join(dir, "b.cook"), "---\ntitle: Beta 2\n---\nUse @vodka{}."); err != nil {
+		t.Fatal(err)
+	}
+	future := time.Now().Add(time.Hour)
+	if err := os.Chtimes(filepath.Join(dir, "b.cook"), future, future); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(dir, "a.cook")); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeFile(filepath.Join(dir, "c.cook"), "---\ntitle: Gamma\n---\nUse @gin{}."); err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded, err := LoadSearchIndex(dir, indexFile)
+	if err != nil {
+		t.Fatalf("LoadSearchIndex failed: %v", err)
+	}
+	titles := make([]string, 0, len(reloaded.Recipes))
+	for _, recipe := range reloaded.Recipes {
+		titles = append(titles, recipe.Title)
+	}
+	if got := strings.Join(titles, ","); got != "Beta 2,Gamma" {
+		t.Errorf("reloaded titles = %q, want %q", got, "Beta 2,Gamma")
+	}
+
+	// Unchanged files are served from the saved index without re-parsing
+	reloaded.Recipes[1].Title = "Cached Gamma"
+	if err := reloaded.Save(indexFile); err != nil {
+		t.Fatal(err)
+	}
+	cached, err := LoadSearchIndex(dir, indexFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cached.Recipes[1].Title != "Cached Gamma" {
+		t.Errorf("expected unchanged recipe to come from the saved index, got %q", cached.Recipes[1].Title)
+	}
+	if got := resultTitles(cached.Query().Ingredient("gin").Results()); got != "Cached Gamma" {
+		t.Errorf("query over reloaded index = %q", got)
+	}
+}
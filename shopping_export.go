@@ -0,0 +1,136 @@
+package cooklang
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ShoppingListExporter sends shopping list items somewhere: a file, a spreadsheet,
+// or a task manager.
+//
+// Example:
+//
+//	list, _ := cooklang.CreateShoppingList(recipe1, recipe2)
+//	var exporter cooklang.ShoppingListExporter = &cooklang.MarkdownExporter{Writer: os.Stdout}
+//	if err := exporter.ExportItems(list.Items()); err != nil {
+//	    log.Fatal(err)
+//	}
+type ShoppingListExporter interface {
+	ExportItems(items []ShoppingListItem) error
+}
+
+// MarkdownExporter writes items as a Markdown checklist ("- [ ] flour (500 g)"),
+// which most notes and task apps understand.
+type MarkdownExporter struct {
+	Writer     io.Writer
+	Title      string         // Optional heading written before the list
+	Quantities *FormatOptions // Writes amounts with FormatQuantity, e.g. as "1½ cups"; nil uses decimals
+}
+
+// ExportItems writes the checklist.
+func (e *MarkdownExporter) ExportItems(items []ShoppingListItem) error {
+	var b strings.Builder
+	if e.Title != "" {
+		fmt.Fprintf(&b, "# %s\n\n", e.Title)
+	}
+	for _, item := range items {
+		if e.Quantities != nil && item.Quantity > 0 {
+			fmt.Fprintf(&b, "- [ ] %s (%s)\n", item.Name, item.FormatAmount(*e.Quantities))
+			continue
+		}
+		fmt.Fprintf(&b, "- [ ] %s\n", item)
+	}
+	_, err := io.WriteString(e.Writer, b.String())
+	return err
+}
+
+// CSVExporter writes items as CSV with the columns name, quantity, unit, for
+// spreadsheets. Ranges are written as "1-2"; unspecified quantities are left empty.
+type CSVExporter struct {
+	Writer io.Writer
+}
+
+// ExportItems writes the CSV, including a header row.
+func (e *CSVExporter) ExportItems(items []ShoppingListItem) error {
+	w := csv.NewWriter(e.Writer)
+	if err := w.Write([]string{"name", "quantity", "unit"}); err != nil {
+		return err
+	}
+	for _, item := range items {
+		quantity := ""
+		if item.Quantity > 0 {
+			quantity = strconv.FormatFloat(float64(item.Quantity), 'f', -1, 32)
+			if item.QuantityMax > item.Quantity {
+				quantity += "-" + strconv.FormatFloat(float64(item.QuantityMax), 'f', -1, 32)
+			}
+		}
+		if err := w.Write([]string{item.Name, quantity, item.Unit}); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// WebhookExporter POSTs items as JSON to a URL, for automation services and task
+// managers with incoming webhooks. The body is {"items": [...]}, where each item has
+// the ShoppingListItem fields plus "text", the item as a single line ("flour (500 g)").
+type WebhookExporter struct {
+	URL     string
+	Headers map[string]string // Extra request headers, e.g. "Authorization"
+	Client  *http.Client      // Defaults to a client with a 30 second timeout
+}
+
+// webhookItem is a shopping list item in a webhook payload.
+type webhookItem struct {
+	ShoppingListItem
+	Text string `json:"text"`
+}
+
+// ExportItems posts the items and fails if the server does not answer with a 2xx status.
+func (e *WebhookExporter) ExportItems(items []ShoppingListItem) error {
+	if e.URL == "" {
+		return fmt.Errorf("webhook URL is required")
+	}
+	payload := struct {
+		Items []webhookItem `json:"items"`
+	}{Items: make([]webhookItem, len(items))}
+	for i, item := range items {
+		payload.Items[i] = webhookItem{ShoppingListItem: item, Text: item.String()}
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, e.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("invalid webhook URL: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range e.Headers {
+		req.Header.Set(key, value)
+	}
+
+	client := e.Client
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("webhook returned %s: %s", resp.Status, strings.TrimSpace(string(message)))
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package cooklang
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func exportTestList(t *testing.T) *ShoppingList {
+	t.Helper()
+	recipe, err := ParseString("Mix @flour{500%g}, @Eggs{2-3}, @salt{} and @milk{~1.5%l}.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	list, err := CreateShoppingList(recipe)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return list
+}
+
+func TestMarkdownExporter(t *testing.T) {
+	var b strings.Builder
+	exporter := &MarkdownExporter{Writer: &b, Title: "Shopping List"}
+	if err := exporter.ExportItems(exportTestList(t).Items()); err != nil {
+		t.Fatal(err)
+	}
+	want := "# Shopping List\n\n- [ ] Eggs (2-3)\n- [ ] flour (500 g)\n- [ ] milk (≈1.5 l)\n- [ ] salt\n"
+	if b.String() != want {
+		t.Errorf("unexpected Markdown:\n%s\nwant:\n%s", b.String(), want)
+	}
+
+	b.Reset()
+	exporter = &MarkdownExporter{Writer: &b, Quantities: &FormatOptions{UnicodeFractions: true}}
+	if err := exporter.ExportItems(exportTestList(t).Items()); err != nil {
+		t.Fatal(err)
+	}
+	if want := "- [ ] Eggs (2-3)\n- [ ] flour (500 g)\n- [ ] milk (≈1½ l)\n- [ ] salt\n"; b.String() != want {
+		t.Errorf("unexpected Markdown with fractions:\n%s\nwant:\n%s", b.String(), want)
+	}
+}
+
+func TestCSVExporter(t *testing.T) {
+	var b strings.Builder
+	if err := (&CSVExporter{Writer: &b}).ExportItems(exportTestList(t).Items()); err != nil {
+		t.Fatal(err)
+	}
+	want := "name,quantity,unit\nEggs,2-3,\nflour,500,g\nmilk,1.5,l\nsalt,,\n"
+	if b.String() != want {
+		t.Errorf("unexpected CSV:\n%s\nwant:\n%s", b.String(), want)
+	}
+}
+
+func TestWebhookExporter(t *testing.T) {
+	var received struct {
+		Items []struct {
+			Name     string  `json:"name"`
+			Quantity float32 `json:"quantity"`
+			Unit     string  `json:"unit"`
+			Text     string  `json:"text"`
+		} `json:"items"`
+	}
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		auth = r.Header.Get("Authorization")
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &received); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	exporter := &WebhookExporter{URL: server.URL, Headers: map[string]string{"Authorization": "Bearer secret"}}
+	if err := exporter.ExportItems(exportTestList(t).Items()); err != nil {
+		t.Fatalf("ExportItems failed: %v", err)
+	}
+	if auth != "Bearer secret" {
+		t.Errorf("expected the Authorization header, got %q", auth)
+	}
+	if len(received.Items) != 4 {
+		t.Fatalf("expected 4 items, got %+v", received)
+	}
+	flour := received.Items[1]
+	if flour.Name != "flour" || flour.Quantity != 500 || flour.Unit != "g" || flour.Text != "flour (500 g)" {
+		t.Errorf("unexpected item: %+v", flour)
+	}
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid token", http.StatusUnauthorized)
+	}))
+	defer failing.Close()
+	err := (&WebhookExporter{URL: failing.URL}).ExportItems(nil)
+	if err == nil || !strings.Contains(err.Error(), "401") || !strings.Contains(err.Error(), "invalid token") {
+		t.Errorf("expected a 401 error with the response message, got %v", err)
+	}
+	if err := (&WebhookExporter{}).ExportItems(nil); err == nil {
+		t.Error("expected an error without a URL")
+	}
+}
@@ -0,0 +1,134 @@
+package cooklang
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+// ShoppingListFileOptions controls CreateShoppingListFromFiles.
+type ShoppingListFileOptions struct {
+	Workers    int           // Files parsed at once (default: the number of CPUs)
+	Parse      []ParseOption // Options for parsing the recipes
+	Servings   float64       // Servings to scale each recipe to; 0 takes the recipes as written
+	TargetUnit string        // Unit to convert compatible ingredients to; "" picks one per category
+}
+
+// FileError is the failure of one recipe file of CreateShoppingListFromFiles.
+type FileError struct {
+	Path string // The recipe file
+	Err  error  // Why it could not be read or parsed
+}
+
+func (e *FileError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Path, e.Err)
+}
+
+func (e *FileError) Unwrap() error {
+	return e.Err
+}
+
+// FileErrors lists the recipe files that failed, in the order they were given.
+type FileErrors []*FileError
+
+func (e FileErrors) Error() string {
+	if len(e) == 1 {
+		return e[0].Error()
+	}
+	return fmt.Sprintf("%d recipe files failed: %v", len(e), errors.Join(e.Unwrap()...))
+}
+
+// Unwrap returns the failures, for errors.Is and errors.As.
+func (e FileErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+	return errs
+}
+
+// CreateShoppingListFromFiles creates a consolidated shopping list from recipe files,
+// parsing them concurrently with a pool of workers, e.g. for a month of meal prep
+// from hundreds of recipes. The list is the same as CreateShoppingList (or
+// CreateShoppingListForServings and CreateShoppingListWithUnit, with opts.Servings and
+// opts.TargetUnit) makes from the recipes in the order the paths are given, however
+// many workers parse them. Files that fail do not stop the others: the list is made
+// from the recipes that parsed, and the error is a FileErrors.
+//
+// Parameters:
+//   - paths: The recipe files
+//   - opts: The number of workers, parse options, servings and target unit
+//
+// Returns:
+//   - *ShoppingList: The shopping list of every recipe that parsed
+//   - error: A FileErrors listing the files that failed, or nil
+//
+// Example:
+//
+//	paths, _ := filepath.Glob("meal-prep/*.cook")
+//	list, err := cooklang.CreateShoppingListFromFiles(paths, cooklang.ShoppingListFileOptions{Servings: 4})
+//	var failed cooklang.FileErrors
+//	if errors.As(err, &failed) {
+//	    for _, f := range failed {
+//	        log.Printf("skipped %s: %v", f.Path, f.Err)
+//	    }
+//	}
+//	for _, item := range list.Items() {
+//	    fmt.Println(item.Name)
+//	}
+func CreateShoppingListFromFiles(paths []string, opts ShoppingListFileOptions) (*ShoppingList, error) {
+	if opts.Workers <= 0 {
+		opts.Workers = runtime.NumCPU()
+	}
+
+	// Each worker writes only its own files' slots, so the results keep path order
+	recipes := make([]*Recipe, len(paths))
+	errs := make([]error, len(paths))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range min(opts.Workers, max(len(paths), 1)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				recipes[i], errs[i] = ParseFile(paths[i], opts.Parse...)
+			}
+		}()
+	}
+	for i := range paths {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	parsed := make([]*Recipe, 0, len(paths))
+	var failed FileErrors
+	for i, recipe := range recipes {
+		if errs[i] != nil {
+			failed = append(failed, &FileError{Path: paths[i], Err: errs[i]})
+			continue
+		}
+		parsed = append(parsed, recipe)
+	}
+
+	var list *ShoppingList
+	var err error
+	switch {
+	case opts.Servings > 0 && opts.TargetUnit != "":
+		list, err = CreateShoppingListForServingsWithUnit(opts.Servings, opts.TargetUnit, parsed...)
+	case opts.Servings > 0:
+		list, err = CreateShoppingListForServings(opts.Servings, parsed...)
+	case opts.TargetUnit != "":
+		list, err = CreateShoppingListWithUnit(opts.TargetUnit, parsed...)
+	default:
+		list, err = CreateShoppingList(parsed...)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(failed) > 0 {
+		return list, failed
+	}
+	return list, nil
+}
@@ -0,0 +1,60 @@
+package cooklang
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestCreateShoppingListFromFiles(t *testing.T) {
+	dir := t.TempDir()
+	var paths []string
+	var recipes []*Recipe
+	for i := range 40 {
+		path := filepath.Join(dir, fmt.Sprintf("recipe-%02d.cook", i))
+		content := fmt.Sprintf("---\nservings: 2\n---\nMix @flour{%d%%g}, @eggs{%d} and @spice-%d{1%%tsp}.\n", 100+i, 1+i%3, i%7)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		recipe, err := ParseFile(path)
+		if err != nil {
+			t.Fatalf("ParseFile failed: %v", err)
+		}
+		paths = append(paths, path)
+		recipes = append(recipes, recipe)
+	}
+	missing := filepath.Join(dir, "missing.cook")
+	withMissing := append(append(append([]string{}, paths[:20]...), missing), paths[20:]...)
+
+	serial, _ := CreateShoppingList(recipes...)
+	servings, _ := CreateShoppingListForServings(6, recipes...)
+	for _, workers := range []int{1, 8, 0} {
+		t.Run(fmt.Sprintf("%d workers", workers), func(t *testing.T) {
+			list, err := CreateShoppingListFromFiles(withMissing, ShoppingListFileOptions{Workers: workers})
+			var failed FileErrors
+			if !errors.As(err, &failed) || len(failed) != 1 || failed[0].Path != missing || !errors.Is(err, fs.ErrNotExist) {
+				t.Fatalf("expected one error for %s, got %v", missing, err)
+			}
+			if !reflect.DeepEqual(list.Items(), serial.Items()) || !reflect.DeepEqual(list.Recipes, serial.Recipes) {
+				t.Errorf("list differs from CreateShoppingList:\n%v\nwant\n%v", list.Items(), serial.Items())
+			}
+
+			list, err = CreateShoppingListFromFiles(paths, ShoppingListFileOptions{Workers: workers, Servings: 6})
+			if err != nil {
+				t.Fatalf("CreateShoppingListFromFiles failed: %v", err)
+			}
+			if !reflect.DeepEqual(list.Items(), servings.Items()) {
+				t.Errorf("list differs from CreateShoppingListForServings:\n%v\nwant\n%v", list.Items(), servings.Items())
+			}
+		})
+	}
+
+	list, err := CreateShoppingListFromFiles(nil, ShoppingListFileOptions{})
+	if err != nil || len(list.Ingredients.Ingredients) != 0 {
+		t.Errorf("CreateShoppingListFromFiles(nil) = %v, %v; want an empty list", list, err)
+	}
+}
@@ -0,0 +1,155 @@
+package cooklang
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// shoppingListDocument is the JSON document of RenderJSON.
+type shoppingListDocument struct {
+	Recipes []string           `json:"recipes"`
+	Items   []shoppingListJSON `json:"items"`
+}
+
+// shoppingListJSON is a shopping list item in the JSON document of RenderJSON.
+type shoppingListJSON struct {
+	Name     string                    `json:"name"`
+	Quantity *shoppingListJSONQuantity `json:"quantity"` // null for "some"
+	Unit     string                    `json:"unit,omitempty"`
+	Amount   string                    `json:"amount"` // Quantity and unit as text, e.g. "1-2 tsp" or "some"
+	Category string                    `json:"category"`
+	Notes    []string                  `json:"notes,omitempty"`
+	Recipes  []string                  `json:"recipes"`
+}
+
+// shoppingListJSONQuantity is the structured quantity of a shopping list item.
+type shoppingListJSONQuantity struct {
+	Value       float64 `json:"value"`         // The amount, or the lower bound of a range
+	Max         float64 `json:"max,omitempty"` // The upper bound of a range
+	Approximate bool    `json:"approximate,omitempty"`
+}
+
+// RenderCSV renders the shopping list as CSV, sorted by name, with a header row and
+// the columns name, quantity, unit, category (the aisle, see SortedItems), notes
+// and recipes. Ranges are written as "1-2" and unspecified quantities are left
+// empty; several notes or recipes are separated by "; ".
+//
+// Returns:
+//   - string: The CSV
+//
+// Example:
+//
+//	list, _ := cooklang.CreateShoppingList(monday, tuesday)
+//	os.WriteFile("shopping.csv", []byte(list.RenderCSV()), 0o644)
+func (sl *ShoppingList) RenderCSV() string {
+	var b strings.Builder
+	w := csv.NewWriter(&b)
+	_ = w.Write([]string{"name", "quantity", "unit", "category", "notes", "recipes"})
+	for _, item := range sl.Items() {
+		quantity := ""
+		if item.Quantity > 0 {
+			quantity = formatItemFloat(item.Quantity)
+			if item.QuantityMax > item.Quantity {
+				quantity += "-" + formatItemFloat(item.QuantityMax)
+			}
+		}
+		_ = w.Write([]string{
+			item.Name, quantity, item.Unit, item.Aisle,
+			strings.Join(item.Notes, "; "), strings.Join(item.SourceRecipes, "; "),
+		})
+	}
+	w.Flush() // Writing to a strings.Builder does not fail
+	return b.String()
+}
+
+// RenderJSON renders the shopping list as a JSON document with the titles of its
+// recipes and its items, sorted by name. Each item has its name, a structured
+// quantity ({"value", "max", "approximate"}, or null for "some"), its unit, its
+// amount as text, its category (the aisle, see SortedItems), notes and the recipes
+// that use it:
+//
+//	{"recipes": ["Pancakes"], "items": [{"name": "flour", "quantity": {"value": 250},
+//	 "unit": "g", "amount": "250 g", "category": "Pantry", "recipes": ["Pancakes"]}]}
+//
+// Returns:
+//   - string: The JSON document, indented
+//   - error: An error if the list cannot be encoded, e.g. for a NaN quantity
+func (sl *ShoppingList) RenderJSON() (string, error) {
+	doc := shoppingListDocument{Recipes: sl.Recipes, Items: []shoppingListJSON{}}
+	if doc.Recipes == nil {
+		doc.Recipes = []string{}
+	}
+	for _, item := range sl.Items() {
+		entry := shoppingListJSON{
+			Name:     item.Name,
+			Unit:     item.Unit,
+			Amount:   item.Amount(),
+			Category: item.Aisle,
+			Notes:    item.Notes,
+			Recipes:  item.SourceRecipes,
+		}
+		if entry.Recipes == nil {
+			entry.Recipes = []string{}
+		}
+		if item.Quantity > 0 {
+			entry.Quantity = &shoppingListJSONQuantity{Value: itemFloat(item.Quantity), Approximate: item.Approximate}
+			if item.QuantityMax > item.Quantity {
+				entry.Quantity.Max = itemFloat(item.QuantityMax)
+			}
+		}
+		doc.Items = append(doc.Items, entry)
+	}
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data) + "\n", nil
+}
+
+// RenderCooklang renders the shopping list in the Cooklang shopping list format: a
+// "[category]" section per aisle, in the order of SortedItems(OrderByAisle), listing
+// "name: amount" lines, or just the name for unspecified quantities. The sections
+// are written like those of an aisle.conf file (see AisleConfig).
+//
+// Returns:
+//   - string: The shopping list
+//
+// Example:
+//
+//	list, _ := cooklang.CreateShoppingList(recipe)
+//	fmt.Print(list.RenderCooklang())
+//	// [Pantry]
+//	// flour: 250 g
+func (sl *ShoppingList) RenderCooklang() string {
+	var b strings.Builder
+	aisle := ""
+	for i, item := range sl.SortedItems(OrderByAisle) {
+		if i == 0 || item.Aisle != aisle {
+			if i > 0 {
+				b.WriteString("\n")
+			}
+			aisle = item.Aisle
+			b.WriteString("[" + aisle + "]\n")
+		}
+		b.WriteString(item.Name)
+		if item.Quantity > 0 {
+			b.WriteString(": " + item.Amount())
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// itemFloat returns a shopping list quantity as the float64 it was written as, so
+// 0.1 is not encoded as 0.10000000149011612.
+func itemFloat(quantity float32) float64 {
+	value, _ := strconv.ParseFloat(formatItemFloat(quantity), 64)
+	return value
+}
+
+// formatItemFloat formats a shopping list quantity with as many digits as it needs.
+func formatItemFloat(quantity float32) string {
+	return strconv.FormatFloat(float64(quantity), 'f', -1, 32)
+}
@@ -0,0 +1,104 @@
+package cooklang
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func formatsTestList(t *testing.T) *ShoppingList {
+	t.Helper()
+	pancakes, err := ParseString("---\ntitle: Pancakes\n---\nMix @flour{250%g}, @eggs{2-3}, @salt{} and @milk{~0.1%l}(cold).")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bread, err := ParseString("---\ntitle: Bread\n---\nKnead @flour{500%g} with @water{300%ml}.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	list, err := CreateShoppingList(pancakes, bread)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return list
+}
+
+func TestShoppingListRenderCSV(t *testing.T) {
+	want := "name,quantity,unit,category,notes,recipes\n" +
+		"eggs,2-3,,Other,,Pancakes\n" +
+		"flour,750,g,Pantry,,Pancakes; Bread\n" +
+		"milk,0.1,l,Dairy & Eggs,cold,Pancakes\n" +
+		"salt,,,Spices & Seasonings,,Pancakes\n" +
+		"water,300,ml,Other,,Bread\n"
+	if got := formatsTestList(t).RenderCSV(); got != want {
+		t.Errorf("unexpected CSV:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestShoppingListRenderJSON(t *testing.T) {
+	data, err := formatsTestList(t).RenderJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Recipes []string `json:"recipes"`
+		Items   []struct {
+			Name     string `json:"name"`
+			Quantity *struct {
+				Value       float64 `json:"value"`
+				Max         float64 `json:"max"`
+				Approximate bool    `json:"approximate"`
+			} `json:"quantity"`
+			Unit     string   `json:"unit"`
+			Amount   string   `json:"amount"`
+			Category string   `json:"category"`
+			Notes    []string `json:"notes"`
+			Recipes  []string `json:"recipes"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal([]byte(data), &doc); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, data)
+	}
+	if strings.Join(doc.Recipes, ",") != "Pancakes,Bread" || len(doc.Items) != 5 {
+		t.Fatalf("unexpected document:\n%s", data)
+	}
+
+	eggs, flour, milk, salt := doc.Items[0], doc.Items[1], doc.Items[2], doc.Items[3]
+	if eggs.Quantity == nil || eggs.Quantity.Value != 2 || eggs.Quantity.Max != 3 || eggs.Amount != "2-3" {
+		t.Errorf("unexpected range item: %+v", eggs)
+	}
+	if flour.Quantity == nil || flour.Quantity.Value != 750 || flour.Unit != "g" || flour.Category != "Pantry" ||
+		strings.Join(flour.Recipes, ",") != "Pancakes,Bread" {
+		t.Errorf("unexpected flour item: %+v", flour)
+	}
+	if milk.Quantity == nil || milk.Quantity.Value != 0.1 || !milk.Quantity.Approximate || strings.Join(milk.Notes, ",") != "cold" {
+		t.Errorf("unexpected milk item: %+v", milk)
+	}
+	if salt.Quantity != nil || salt.Amount != "some" || salt.Category != "Spices & Seasonings" {
+		t.Errorf("unexpected salt item: %+v", salt)
+	}
+	if !strings.Contains(data, `"value": 0.1,`) {
+		t.Errorf("expected quantities as written, got:\n%s", data)
+	}
+
+	empty, err := (&ShoppingList{}).RenderJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if empty != "{\n  \"recipes\": [],\n  \"items\": []\n}\n" {
+		t.Errorf("unexpected empty document: %s", empty)
+	}
+}
+
+func TestShoppingListRenderCooklang(t *testing.T) {
+	want := "[Dairy & Eggs]\nmilk: ≈0.1 l\n\n" +
+		"[Pantry]\nflour: 750 g\n\n" +
+		"[Spices & Seasonings]\nsalt\n\n" +
+		"[Other]\neggs: 2-3\nwater: 300 ml\n"
+	if got := formatsTestList(t).RenderCooklang(); got != want {
+		t.Errorf("unexpected shopping list:\n%s\nwant:\n%s", got, want)
+	}
+	if got := (&ShoppingList{}).RenderCooklang(); got != "" {
+		t.Errorf("expected nothing for an empty list, got %q", got)
+	}
+}
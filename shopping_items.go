@@ -0,0 +1,312 @@
+package cooklang
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ShoppingListItem is one line of a shopping list.
+type ShoppingListItem struct {
+	Name          string   `json:"name"`
+	Quantity      float32  `json:"quantity,omitempty"`     // Amount to buy (0 if unspecified, i.e. "some"); lower bound for ranges
+	QuantityMax   float32  `json:"quantity_max,omitempty"` // Upper bound for ranges (0 if not a range)
+	Unit          string   `json:"unit,omitempty"`
+	Approximate   bool     `json:"approximate,omitempty"`    // Amount is an estimate
+	Notes         []string `json:"notes,omitempty"`          // Ingredient annotations from the recipes (e.g., "finely chopped")
+	SourceRecipes []string `json:"source_recipes,omitempty"` // Titles of the recipes that use the ingredient, in list order
+	Aisle         string   `json:"aisle,omitempty"`          // Store section, see AisleConfig.Aisle
+}
+
+// Amount formats the item's quantity and unit, rounded to two decimals
+// (e.g., "500 g", "1-2 tsp", "0.25 fl oz", "some").
+func (item ShoppingListItem) Amount() string {
+	return item.amount(formatItemQuantity)
+}
+
+// FormatAmount formats the item's quantity and unit like Amount, writing the
+// quantity with FormatQuantity.
+//
+// Parameters:
+//   - opts: How to write the quantity, e.g. with Unicode fractions
+//
+// Returns:
+//   - string: The amount, e.g. "1½ cups" or "some"
+//
+// Example:
+//
+//	for _, item := range list.Items() {
+//	    fmt.Printf("%s: %s\n", item.Name, item.FormatAmount(cooklang.FormatOptions{UnicodeFractions: true}))
+//	}
+func (item ShoppingListItem) FormatAmount(opts FormatOptions) string {
+	return item.amount(func(quantity float32) string {
+		return FormatQuantity(float64(quantity), opts)
+	})
+}
+
+// amount formats the item's quantity with format, and its unit.
+func (item ShoppingListItem) amount(format func(float32) string) string {
+	if item.Quantity <= 0 {
+		return strings.TrimSpace("some " + item.Unit)
+	}
+	amount := format(item.Quantity)
+	if item.QuantityMax > item.Quantity {
+		amount += "-" + format(item.QuantityMax)
+	}
+	if item.Unit != "" {
+		amount += " " + item.Unit
+	}
+	if item.Approximate {
+		amount = ApproximatePrefix + amount
+	}
+	return amount
+}
+
+// String returns the item as "name (amount)", or just the name if no amount is given.
+func (item ShoppingListItem) String() string {
+	if item.Quantity <= 0 {
+		return item.Name
+	}
+	return item.Name + " (" + item.Amount() + ")"
+}
+
+// formatItemQuantity formats a quantity with at most two decimals.
+func formatItemQuantity(quantity float32) string {
+	return strconv.FormatFloat(math.Round(float64(quantity)*100)/100, 'f', -1, 64)
+}
+
+// ShoppingListOrder selects how SortedItems orders a shopping list.
+type ShoppingListOrder int
+
+const (
+	// OrderAlphabetical sorts items by name, ignoring case.
+	OrderAlphabetical ShoppingListOrder = iota
+	// OrderByRecipe groups items by the first recipe that uses them, in the order the
+	// recipes were added, and sorts each group by name.
+	OrderByRecipe
+	// OrderByAisle groups items by aisle, in the order of the list's AisleConfig and
+	// then ShoppingAisles, and sorts each group by name.
+	OrderByAisle
+)
+
+// String returns the order's name as accepted by ParseShoppingListOrder.
+func (o ShoppingListOrder) String() string {
+	switch o {
+	case OrderByRecipe:
+		return "recipe"
+	case OrderByAisle:
+		return "aisle"
+	default:
+		return "alphabetical"
+	}
+}
+
+// ParseShoppingListOrder parses "alphabetical" (or "name"), "recipe" or "aisle".
+//
+// Parameters:
+//   - s: The order name (case-insensitive)
+//
+// Returns:
+//   - ShoppingListOrder: The order
+//   - error: An error if the name is unknown
+func ParseShoppingListOrder(s string) (ShoppingListOrder, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "alphabetical", "name", "":
+		return OrderAlphabetical, nil
+	case "recipe":
+		return OrderByRecipe, nil
+	case "aisle":
+		return OrderByAisle, nil
+	}
+	return OrderAlphabetical, fmt.Errorf("unknown shopping list order %q (use alphabetical, recipe, or aisle)", s)
+}
+
+// ShoppingAisles lists the aisles IngredientAisle assigns, in store walking order.
+var ShoppingAisles = []string{"Produce", "Dairy & Eggs", "Meat & Seafood", "Pantry", "Spices & Seasonings", "Other"}
+
+// aisleKeywords maps name fragments to aisles. The first match wins, so "pepper"
+// is produce rather than a spice.
+var aisleKeywords = []struct {
+	aisle    string
+	keywords []string
+}{
+	{"Produce", []string{"lettuce", "tomato", "onion", "garlic", "carrot", "potato", "cucumber", "pepper", "spinach", "kale"}},
+	{"Dairy & Eggs", []string{"milk", "cream", "cheese", "butter", "yogurt", "parmesan"}},
+	{"Meat & Seafood", []string{"chicken", "beef", "pork", "fish", "lamb", "turkey", "bacon", "sausage"}},
+	{"Spices & Seasonings", []string{"salt", "pepper", "cinnamon", "cumin", "paprika", "oregano", "basil", "thyme", "vanilla"}},
+	{"Pantry", []string{"flour", "sugar", "rice", "pasta", "bread", "oil", "vinegar", "sauce"}},
+}
+
+// IngredientAisle guesses the store aisle of an ingredient from common words in its
+// name, returning "Other" when nothing matches.
+//
+// Parameters:
+//   - name: The ingredient name
+//
+// Returns:
+//   - string: One of ShoppingAisles
+//
+// Example:
+//
+//	cooklang.IngredientAisle("cherry tomatoes") // "Produce"
+//	cooklang.IngredientAisle("Campari")         // "Other"
+func IngredientAisle(name string) string {
+	name = strings.ToLower(name)
+	for _, group := range aisleKeywords {
+		for _, keyword := range group.keywords {
+			if strings.Contains(name, keyword) {
+				return group.aisle
+			}
+		}
+	}
+	return "Other"
+}
+
+// shoppingSource records where a shopping list ingredient comes from.
+type shoppingSource struct {
+	recipes []string // Recipe titles, in list order
+	notes   []string // Distinct annotations
+}
+
+// shoppingSources collects, per ingredient name, the recipes using it and their annotations.
+func shoppingSources(recipes []*Recipe) map[string]*shoppingSource {
+	sources := make(map[string]*shoppingSource)
+	for _, recipe := range recipes {
+		for _, ingredient := range recipe.GetIngredients().Ingredients {
+			source := sources[ingredient.Name]
+			if source == nil {
+				source = &shoppingSource{}
+				sources[ingredient.Name] = source
+			}
+			if recipe.Title != "" && !slices.Contains(source.recipes, recipe.Title) {
+				source.recipes = append(source.recipes, recipe.Title)
+			}
+			if ingredient.Annotation != "" && !slices.Contains(source.notes, ingredient.Annotation) {
+				source.notes = append(source.notes, ingredient.Annotation)
+			}
+		}
+	}
+	return sources
+}
+
+// Items returns the shopping list as items sorted alphabetically by name.
+// It is shorthand for SortedItems(OrderAlphabetical).
+//
+// Returns:
+//   - []ShoppingListItem: The items, in a stable order
+//
+// Example:
+//
+//	list, _ := cooklang.CreateShoppingList(recipe1, recipe2)
+//	for _, item := range list.Items() {
+//	    fmt.Printf("- %s\n", item) // - flour (500 g)
+//	}
+func (sl *ShoppingList) Items() []ShoppingListItem {
+	return sl.SortedItems(OrderAlphabetical)
+}
+
+// SortedItems returns the shopping list as items in the given order. Unlike ToMap,
+// the result is the same on every call: ties are broken by name, unit and quantity.
+// Repeated unquantified ("some") entries for the same name and unit are listed once.
+// Lists made with the CreateShoppingList functions also fill in each item's notes
+// and source recipes.
+//
+// Parameters:
+//   - order: OrderAlphabetical, OrderByRecipe or OrderByAisle
+//
+// Returns:
+//   - []ShoppingListItem: The items (an empty slice for an empty list)
+//
+// Example:
+//
+//	list, _ := cooklang.CreateShoppingList(monday, tuesday)
+//	aisle := ""
+//	for _, item := range list.SortedItems(cooklang.OrderByAisle) {
+//	    if item.Aisle != aisle {
+//	        aisle = item.Aisle
+//	        fmt.Printf("%s:\n", aisle)
+//	    }
+//	    fmt.Printf("  %s\n", item)
+//	}
+func (sl *ShoppingList) SortedItems(order ShoppingListOrder) []ShoppingListItem {
+	items := []ShoppingListItem{}
+	if sl.Ingredients == nil {
+		return items
+	}
+	// Consolidation keeps each "some" mention; one line per name and unit is enough
+	unquantified := make(map[[2]string]bool)
+	for _, ingredient := range sl.Ingredients.Ingredients {
+		if !ingredient.Quantity.HasAmount() {
+			key := [2]string{ingredient.Name, ingredient.Unit}
+			if unquantified[key] {
+				continue
+			}
+			unquantified[key] = true
+		}
+		item := ShoppingListItem{
+			Name:        ingredient.Name,
+			Unit:        ingredient.Unit,
+			Approximate: ingredient.Approximate,
+			Aisle:       sl.Aisles.Aisle(ingredient.Name),
+		}
+		if ingredient.Quantity.HasAmount() {
+			item.Quantity = float32(ingredient.Quantity.Min())
+			if ingredient.Quantity.IsRange() {
+				item.QuantityMax = float32(ingredient.Quantity.Max())
+			}
+		}
+		if source := sl.sources[ingredient.Name]; source != nil {
+			item.Notes = slices.Clone(source.notes)
+			item.SourceRecipes = slices.Clone(source.recipes)
+		}
+		items = append(items, item)
+	}
+
+	// rank places items in groups; items with the same rank are sorted by name
+	rank := func(item ShoppingListItem) int { return 0 }
+	switch order {
+	case OrderByRecipe:
+		rank = func(item ShoppingListItem) int {
+			if len(item.SourceRecipes) == 0 {
+				return len(sl.Recipes)
+			}
+			if i := slices.Index(sl.Recipes, item.SourceRecipes[0]); i >= 0 {
+				return i
+			}
+			return len(sl.Recipes)
+		}
+	case OrderByAisle:
+		// Configured aisles come first, then the ones IngredientAisle guesses
+		var aisles []string
+		if sl.Aisles != nil {
+			aisles = append(aisles, sl.Aisles.Aisles...)
+		}
+		aisles = append(aisles, ShoppingAisles...)
+		rank = func(item ShoppingListItem) int {
+			if i := slices.Index(aisles, item.Aisle); i >= 0 {
+				return i
+			}
+			return len(aisles)
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := items[i], items[j]
+		if ra, rb := rank(a), rank(b); ra != rb {
+			return ra < rb
+		}
+		if la, lb := strings.ToLower(a.Name), strings.ToLower(b.Name); la != lb {
+			return la < lb
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		if a.Unit != b.Unit {
+			return a.Unit < b.Unit
+		}
+		return a.Quantity < b.Quantity
+	})
+	return items
+}
@@ -0,0 +1,110 @@
+package cooklang
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestShoppingListItems(t *testing.T) {
+	items := exportTestList(t).Items()
+	var names []string
+	for _, item := range items {
+		names = append(names, item.String())
+	}
+	want := "Eggs (2-3), flour (500 g), milk (≈1.5 l), salt"
+	if got := strings.Join(names, ", "); got != want {
+		t.Errorf("Items() = %q, want %q", got, want)
+	}
+	if items[3].Quantity != 0 || items[3].Amount() != "some" {
+		t.Errorf("expected salt without a quantity, got %+v (%q)", items[3], items[3].Amount())
+	}
+	if got := (&ShoppingList{}).Items(); got == nil || len(got) != 0 {
+		t.Errorf("expected an empty slice for an empty list, got %v", got)
+	}
+}
+
+func TestShoppingListItemFormatAmount(t *testing.T) {
+	unicode := FormatOptions{UnicodeFractions: true}
+	var amounts []string
+	for _, item := range exportTestList(t).Items() {
+		amounts = append(amounts, item.FormatAmount(unicode))
+	}
+	want := "2-3, 500 g, ≈1½ l, some"
+	if got := strings.Join(amounts, ", "); got != want {
+		t.Errorf("FormatAmount() = %q, want %q", got, want)
+	}
+}
+
+func TestShoppingListSortedItems(t *testing.T) {
+	pasta, err := ParseString("---\ntitle: Pasta\n---\nBoil @pasta{200%g} with @salt{}. Add @tomato{2}(chopped) and @parmesan{30%g}.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	salad, err := ParseString("---\ntitle: Salad\n---\nToss @lettuce{1} with @tomato{1}(sliced), @olive oil{2%tbsp} and @salt{}.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	list, err := CreateShoppingList(pasta, salad)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names := func(items []ShoppingListItem) string {
+		var result []string
+		for _, item := range items {
+			result = append(result, item.Name)
+		}
+		return strings.Join(result, ", ")
+	}
+	tests := []struct {
+		order ShoppingListOrder
+		want  string
+	}{
+		{OrderAlphabetical, "lettuce, olive oil, parmesan, pasta, salt, tomato"},
+		{OrderByRecipe, "parmesan, pasta, salt, tomato, lettuce, olive oil"},
+		{OrderByAisle, "lettuce, tomato, parmesan, olive oil, pasta, salt"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.order.String(), func(t *testing.T) {
+			for i := 0; i < 5; i++ {
+				if got := names(list.SortedItems(tt.order)); got != tt.want {
+					t.Fatalf("SortedItems(%v) = %q, want %q", tt.order, got, tt.want)
+				}
+			}
+		})
+	}
+
+	for _, item := range list.Scale(2).Items() {
+		if item.Name != "tomato" {
+			continue
+		}
+		if item.Quantity != 6 || item.Aisle != "Produce" {
+			t.Errorf("unexpected tomato item: %+v", item)
+		}
+		if strings.Join(item.Notes, ",") != "chopped,sliced" || strings.Join(item.SourceRecipes, ",") != "Pasta,Salad" {
+			t.Errorf("expected notes and source recipes to survive scaling, got %+v", item)
+		}
+	}
+}
+
+func TestParseShoppingListOrder(t *testing.T) {
+	tests := map[string]ShoppingListOrder{"": OrderAlphabetical, "name": OrderAlphabetical, "Recipe": OrderByRecipe, "aisle": OrderByAisle}
+	for input, want := range tests {
+		if got, err := ParseShoppingListOrder(input); err != nil || got != want {
+			t.Errorf("ParseShoppingListOrder(%q) = %v, %v; want %v", input, got, err, want)
+		}
+	}
+	if _, err := ParseShoppingListOrder("price"); err == nil {
+		t.Error("expected an error for an unknown order")
+	}
+}
+
+func TestIngredientAisle(t *testing.T) {
+	tests := map[string]string{"Cherry Tomatoes": "Produce", "whole milk": "Dairy & Eggs", "chicken thighs": "Meat & Seafood",
+		"black pepper": "Produce", "sea salt": "Spices & Seasonings", "olive oil": "Pantry", "Campari": "Other"}
+	for name, want := range tests {
+		if got := IngredientAisle(name); got != want {
+			t.Errorf("IngredientAisle(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
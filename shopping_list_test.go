@@ -1,7 +1,9 @@
 package cooklang
 
 import (
+	"fmt"
 	"math"
+	"strings"
 	"testing"
 )
 
@@ -100,7 +102,7 @@ Add @flour{200%g} and @butter{50%g}.`
 		flour := findIngredient(ingredients, "flour")
 		if flour == nil {
 			t.Error("flour not found in shopping list")
-		} else if !floatClose(float64(flour.Quantity), 1200, 0.1) {
+		} else if !floatClose(flour.Quantity.Min(), 1200, 0.1) {
 			t.Errorf("flour quantity = %v, want 1200", flour.Quantity)
 		}
 
@@ -108,7 +110,7 @@ Add @flour{200%g} and @butter{50%g}.`
 		sugar := findIngredient(ingredients, "sugar")
 		if sugar == nil {
 			t.Error("sugar not found in shopping list")
-		} else if !floatClose(float64(sugar.Quantity), 200, 0.1) {
+		} else if !floatClose(sugar.Quantity.Min(), 200, 0.1) {
 			t.Errorf("sugar quantity = %v, want 200", sugar.Quantity)
 		}
 
@@ -116,7 +118,7 @@ Add @flour{200%g} and @butter{50%g}.`
 		butter := findIngredient(ingredients, "butter")
 		if butter == nil {
 			t.Error("butter not found in shopping list")
-		} else if !floatClose(float64(butter.Quantity), 50, 0.1) {
+		} else if !floatClose(butter.Quantity.Min(), 50, 0.1) {
 			t.Errorf("butter quantity = %v, want 50", butter.Quantity)
 		}
 
@@ -124,7 +126,7 @@ Add @flour{200%g} and @butter{50%g}.`
 		salt := findIngredient(ingredients, "salt")
 		if salt == nil {
 			t.Error("salt not found in shopping list")
-		} else if !floatClose(float64(salt.Quantity), 20, 0.1) {
+		} else if !floatClose(salt.Quantity.Min(), 20, 0.1) {
 			t.Errorf("salt quantity = %v, want 20", salt.Quantity)
 		}
 	})
@@ -143,7 +145,7 @@ Add @flour{200%g} and @butter{50%g}.`
 		flour := findIngredient(ingredients, "flour")
 		if flour == nil {
 			t.Error("flour not found in shopping list")
-		} else if !floatClose(float64(flour.Quantity), 300, 0.1) {
+		} else if !floatClose(flour.Quantity.Min(), 300, 0.1) {
 			t.Errorf("flour quantity = %v, want 300", flour.Quantity)
 		}
 	})
@@ -186,7 +188,7 @@ Add @flour{500%g} and @butter{100%g}.`
 			if flour.Unit != "kg" {
 				t.Errorf("flour unit = %v, want kg", flour.Unit)
 			}
-			if !floatClose(float64(flour.Quantity), 1, 0.01) {
+			if !floatClose(flour.Quantity.Min(), 1, 0.01) {
 				t.Errorf("flour quantity = %v, want 1", flour.Quantity)
 			}
 		}
@@ -199,7 +201,7 @@ Add @flour{500%g} and @butter{100%g}.`
 			if butter.Unit != "kg" {
 				t.Errorf("butter unit = %v, want kg", butter.Unit)
 			}
-			if !floatClose(float64(butter.Quantity), 0.2, 0.01) {
+			if !floatClose(butter.Quantity.Min(), 0.2, 0.01) {
 				t.Errorf("butter quantity = %v, want 0.2", butter.Quantity)
 			}
 		}
@@ -230,7 +232,7 @@ Add @flour{500%g} and @salt{=10%g}.`
 		flour := findIngredient(ingredients, "flour")
 		if flour == nil {
 			t.Error("flour not found")
-		} else if !floatClose(float64(flour.Quantity), 1000, 0.1) {
+		} else if !floatClose(flour.Quantity.Min(), 1000, 0.1) {
 			t.Errorf("flour quantity = %v, want 1000", flour.Quantity)
 		}
 
@@ -238,7 +240,7 @@ Add @flour{500%g} and @salt{=10%g}.`
 		salt := findIngredient(ingredients, "salt")
 		if salt == nil {
 			t.Error("salt not found")
-		} else if !floatClose(float64(salt.Quantity), 10, 0.1) {
+		} else if !floatClose(salt.Quantity.Min(), 10, 0.1) {
 			t.Errorf("salt quantity = %v, want 10 (fixed)", salt.Quantity)
 		}
 	})
@@ -256,7 +258,7 @@ Add @flour{500%g} and @salt{=10%g}.`
 		flour := findIngredient(ingredients, "flour")
 		if flour == nil {
 			t.Error("flour not found")
-		} else if !floatClose(float64(flour.Quantity), 250, 0.1) {
+		} else if !floatClose(flour.Quantity.Min(), 250, 0.1) {
 			t.Errorf("flour quantity = %v, want 250", flour.Quantity)
 		}
 	})
@@ -276,3 +278,52 @@ func findIngredient(ingredients []*Ingredient, name string) *Ingredient {
 func floatClose(a, b, tolerance float64) bool {
 	return math.Abs(a-b) <= tolerance
 }
+
+func TestCreateShoppingListWithPlan(t *testing.T) {
+	pasta, err := ParseString("---\ntitle: Pasta\nservings: 4\n---\nBoil @pasta{400%g} with @salt{10%g}.\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dessert, err := ParseString("---\ntitle: Dessert\nservings: 2\n---\nWhisk @eggs{2} with @sugar{50%g} and @salt{1%g}.\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bread, err := ParseString("---\ntitle: Bread\n---\nKnead @flour{500%g} with @salt{10%g}.\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	list, err := CreateShoppingListWithPlan(map[*Recipe]PlanAmount{
+		pasta:   {Servings: 2},
+		dessert: {Servings: 6},
+		bread:   {Factor: 1.5},
+	})
+	if err != nil {
+		t.Fatalf("CreateShoppingListWithPlan failed: %v", err)
+	}
+
+	got := list.ToMap()
+	for name, want := range map[string]string{"pasta": "200 g", "eggs": "6", "sugar": "150 g", "flour": "750 g", "salt": "23 g"} {
+		if got[name] != want {
+			t.Errorf("%s = %q, want %q", name, got[name], want)
+		}
+	}
+	if strings.Join(list.Recipes, ",") != "Bread,Dessert,Pasta" {
+		t.Errorf("recipes = %v, want them in title order", list.Recipes)
+	}
+
+	var scalings []string
+	for _, scaling := range list.Report.Scaling {
+		scalings = append(scalings, fmt.Sprintf("%s %g→%g x%g", scaling.Recipe, scaling.Servings, scaling.TargetServings, scaling.Factor))
+	}
+	if got := strings.Join(scalings, ", "); got != "Bread 1→1.5 x1.5, Dessert 2→6 x3, Pasta 4→2 x0.5" {
+		t.Errorf("scaling = %s", got)
+	}
+
+	if _, err := CreateShoppingListWithPlan(map[*Recipe]PlanAmount{pasta: {Servings: 2, Factor: 2}}); err == nil {
+		t.Error("expected an error for servings and a factor")
+	}
+	if _, err := CreateShoppingListWithPlan(map[*Recipe]PlanAmount{pasta: {Servings: -1}}); err == nil {
+		t.Error("expected an error for negative servings")
+	}
+}
@@ -0,0 +1,105 @@
+package cooklang
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// PlanAmount says how much of a recipe a shopping plan needs: a number of servings,
+// or a factor to scale the recipe by. The zero value takes the recipe as written.
+type PlanAmount struct {
+	Servings float64 // Servings to make; the recipe is scaled from its own servings
+	Factor   float64 // Factor to scale the recipe by, when Servings is 0
+}
+
+// RecipeScaling records how CreateShoppingListWithPlan scaled a recipe.
+type RecipeScaling struct {
+	Recipe         string  `json:"recipe"`          // Recipe title
+	Servings       float64 `json:"servings"`        // Servings the recipe makes as written
+	TargetServings float64 `json:"target_servings"` // Servings planned
+	Factor         float64 `json:"factor"`          // Factor the ingredients were scaled by
+}
+
+// CreateShoppingListWithPlan creates a shopping list from recipes that each need a
+// different amount, such as 2 servings of pasta and 6 of dessert. Each recipe is
+// scaled to its planned servings or by its factor, then all ingredients are combined
+// and consolidated. Recipes are combined in title order, and ShoppingList.Report
+// records how each was scaled.
+//
+// Parameters:
+//   - plan: The servings or scaling factor of each recipe
+//
+// Returns:
+//   - *ShoppingList: Consolidated shopping list with all ingredients scaled
+//   - error: An error if an amount is negative or sets both Servings and Factor
+//
+// Example:
+//
+//	pasta, _ := cooklang.ParseFile("pasta.cook")     // servings: 4
+//	dessert, _ := cooklang.ParseFile("dessert.cook") // servings: 2
+//	list, _ := cooklang.CreateShoppingListWithPlan(map[*cooklang.Recipe]cooklang.PlanAmount{
+//	    pasta:   {Servings: 2}, // scaled 0.5x
+//	    dessert: {Servings: 6}, // scaled 3x
+//	})
+//	for _, scaling := range list.Report.Scaling {
+//	    fmt.Printf("%s: %g servings (x%g)\n", scaling.Recipe, scaling.TargetServings, scaling.Factor)
+//	}
+func CreateShoppingListWithPlan(plan map[*Recipe]PlanAmount) (*ShoppingList, error) {
+	recipes := make([]*Recipe, 0, len(plan))
+	for recipe := range plan {
+		recipes = append(recipes, recipe)
+	}
+	slices.SortStableFunc(recipes, func(a, b *Recipe) int {
+		return strings.Compare(a.Title, b.Title)
+	})
+
+	allIngredients := []*Ingredient{}
+	recipeNames := []string{}
+	scalings := make([]RecipeScaling, 0, len(recipes))
+	for _, recipe := range recipes {
+		amount := plan[recipe]
+		switch {
+		case amount.Servings < 0 || amount.Factor < 0:
+			return nil, fmt.Errorf("plan for %q: servings and factor cannot be negative", recipe.Title)
+		case amount.Servings > 0 && amount.Factor > 0:
+			return nil, fmt.Errorf("plan for %q: give servings or a factor, not both", recipe.Title)
+		}
+
+		servings := float64(recipe.Servings)
+		if servings <= 0 {
+			servings = 1 // Assume 1 serving if not specified
+		}
+		scaling := RecipeScaling{Recipe: recipe.Title, Servings: servings, TargetServings: servings, Factor: 1}
+		switch {
+		case amount.Servings > 0:
+			scaling.TargetServings, scaling.Factor = amount.Servings, amount.Servings/servings
+		case amount.Factor > 0:
+			scaling.TargetServings, scaling.Factor = servings*amount.Factor, amount.Factor
+		}
+		scalings = append(scalings, scaling)
+
+		scaled := recipe
+		if scaling.Factor != 1 {
+			scaled = recipe.Scale(scaling.Factor)
+		}
+		allIngredients = append(allIngredients, scaled.GetIngredients().Ingredients...)
+		if recipe.Title != "" {
+			recipeNames = append(recipeNames, recipe.Title)
+		}
+	}
+
+	combinedList := &IngredientList{Ingredients: allIngredients}
+	consolidated, report, err := combinedList.ConsolidateByNameWithReport("")
+	if err != nil {
+		return nil, err
+	}
+	report.Scaling = scalings
+
+	return &ShoppingList{
+		Ingredients: consolidated,
+		Report:      report,
+		Recipes:     recipeNames,
+		sources:     shoppingSources(recipes),
+	}, nil
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/hilli/cooklang/parser"
 	spec_test "github.com/hilli/cooklang/spec"
+	"github.com/hilli/cooklang/token"
 )
 
 func Test_Spec(t *testing.T) {
@@ -44,7 +45,12 @@ func Test_Spec(t *testing.T) {
 						t.SkipNow() // skip the rest of the test if steps don't match
 					}
 					for is, specstep := range spec.Result.Steps {
-						recipeComponent := recipe.Steps[is].Components
+						// The spec does not cover source positions
+						recipeComponent := make([]parser.Component, len(recipe.Steps[is].Components))
+						for ic, component := range recipe.Steps[is].Components {
+							component.Position = token.SourcePosition{}
+							recipeComponent[ic] = component
+						}
 						if !reflect.DeepEqual(recipeComponent, specstep) {
 							t.Errorf("step %d mismatch:\nWant: %#v\nGot : %#v", is, specstep, recipeComponent)
 						}
@@ -0,0 +1,138 @@
+package cooklang
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// RecipeStats counts the parts of a recipe.
+type RecipeStats struct {
+	Ingredients       int           `json:"ingredients"`        // Ingredient uses, counting an ingredient used in two steps twice
+	UniqueIngredients int           `json:"unique_ingredients"` // Different ingredients, by case-insensitive name
+	Cookware          int           `json:"cookware"`           // Different cookware, by case-insensitive name
+	Steps             int           `json:"steps"`              // Cooking steps, without sections, notes and comments
+	Sections          int           `json:"sections"`
+	Timers            int           `json:"timers"`
+	TimerTime         time.Duration `json:"timer_time"`  // Sum of all timers, see TotalTimerTime
+	ActiveTime        time.Duration `json:"active_time"` // Estimated time the steps keep the cook busy, see TotalActiveTime
+	Words             int           `json:"words"`       // Words of the steps, counting ingredient and cookware names and timers
+}
+
+// Stats counts the ingredients, steps, timers and words of the recipe.
+//
+// Returns:
+//   - RecipeStats: The counts
+//
+// Example:
+//
+//	stats := recipe.Stats()
+//	fmt.Printf("%d ingredients, %d steps, %s of timers\n", stats.UniqueIngredients, stats.Steps, stats.TimerTime)
+func (r *Recipe) Stats() RecipeStats {
+	stats := RecipeStats{
+		TimerTime:  r.TotalTimerTime(),
+		ActiveTime: r.TotalActiveTime(),
+		Words:      len(strings.Fields(stepPlainText(r))),
+	}
+	ingredients, cookware := map[string]bool{}, map[string]bool{}
+	for step := range r.Steps() {
+		cooking := false // Whether the step has more than a section header
+		for component := range step.Components() {
+			switch c := component.(type) {
+			case *Section:
+				stats.Sections++
+			case *Ingredient:
+				stats.Ingredients++
+				ingredients[libraryKey(c.Name)] = true
+				cooking = true
+			case *Cookware:
+				cookware[libraryKey(c.Name)] = true
+				cooking = true
+			case *Timer:
+				stats.Timers++
+				cooking = true
+			case *Temperature, *RecipeReference:
+				cooking = true
+			case *Instruction:
+				cooking = cooking || strings.TrimSpace(c.Text) != ""
+			}
+		}
+		if cooking {
+			stats.Steps++
+		}
+	}
+	stats.UniqueIngredients = len(ingredients)
+	stats.Cookware = len(cookware)
+	return stats
+}
+
+// StatCount is how many recipes of a library use a name, e.g. an ingredient or tag.
+type StatCount struct {
+	Name  string `json:"name"`  // Lowercased name
+	Count int    `json:"count"` // Recipes using it
+}
+
+// LibraryStats summarizes a recipe collection.
+type LibraryStats struct {
+	Recipes            int           `json:"recipes"`
+	AverageIngredients float64       `json:"average_ingredients"` // Average of UniqueIngredients
+	AverageSteps       float64       `json:"average_steps"`
+	AverageActiveTime  time.Duration `json:"average_active_time"`
+	Ingredients        []StatCount   `json:"ingredients"` // Most used first
+	Cuisines           []StatCount   `json:"cuisines"`    // Most used first
+	Tags               []StatCount   `json:"tags"`        // Most used first
+}
+
+// Stats summarizes the library: average recipe size, and how many recipes use each
+// ingredient, cuisine and tag, most used first (then by name).
+//
+// Returns:
+//   - LibraryStats: The summary
+//
+// Example:
+//
+//	stats := library.Stats()
+//	for _, ingredient := range stats.Ingredients[:min(5, len(stats.Ingredients))] {
+//	    fmt.Printf("%s: %d recipes\n", ingredient.Name, ingredient.Count)
+//	}
+func (l *Library) Stats() LibraryStats {
+	stats := LibraryStats{
+		Recipes:     l.Len(),
+		Ingredients: statCounts(l.byIngredient),
+		Cuisines:    statCounts(l.byCuisine),
+		Tags:        statCounts(l.byTag),
+	}
+	if stats.Recipes == 0 {
+		return stats
+	}
+	var ingredients, steps int
+	var active time.Duration
+	for _, entry := range l.Entries {
+		if entry.Recipe == nil {
+			continue
+		}
+		recipe := entry.Recipe.Stats()
+		ingredients += recipe.UniqueIngredients
+		steps += recipe.Steps
+		active += recipe.ActiveTime
+	}
+	stats.AverageIngredients = float64(ingredients) / float64(stats.Recipes)
+	stats.AverageSteps = float64(steps) / float64(stats.Recipes)
+	stats.AverageActiveTime = active / time.Duration(stats.Recipes)
+	return stats
+}
+
+// statCounts counts the entries of each key of an index, most used first.
+func statCounts(index map[string][]*LibraryEntry) []StatCount {
+	counts := make([]StatCount, 0, len(index))
+	for key, entries := range index {
+		counts = append(counts, StatCount{Name: key, Count: len(entries)})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Name < counts[j].Name
+	})
+	return counts
+}
@@ -0,0 +1,60 @@
+package cooklang
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRecipeStats(t *testing.T) {
+	recipe, err := ParseString(`== Dough ==
+Mix @flour{500%g}, @water{300%ml} and @salt{1%tsp} in a #bowl{}.
+
+Knead for ~{10%minutes}, then rest ~{1%hour} in the #Bowl{}.
+
+== Topping ==
+Spread @tomatoes{400%g} and @Salt{}. -- to taste
+
+> Use a very hot oven.`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats := recipe.Stats()
+	expected := RecipeStats{
+		Ingredients:       5,
+		UniqueIngredients: 4,
+		Cookware:          1,
+		Steps:             3,
+		Sections:          2,
+		Timers:            2,
+		TimerTime:         70 * time.Minute,
+		ActiveTime:        time.Hour,
+		Words:             23,
+	}
+	if stats != expected {
+		t.Errorf("Stats() = %+v\nwant %+v", stats, expected)
+	}
+}
+
+func TestLibraryStats(t *testing.T) {
+	library, err := newTestLibrary(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats := library.Stats()
+	if stats.Recipes != 4 || stats.AverageIngredients != 2 || stats.AverageSteps != 1 {
+		t.Errorf("unexpected totals: %+v", stats)
+	}
+	if first := stats.Ingredients[0]; first != (StatCount{Name: "tomatoes", Count: 2}) {
+		t.Errorf("most used ingredient = %+v, want tomatoes in 2 recipes", first)
+	}
+	if len(stats.Cuisines) != 2 || stats.Cuisines[0] != (StatCount{Name: "italian", Count: 2}) {
+		t.Errorf("cuisines = %+v", stats.Cuisines)
+	}
+	if len(stats.Tags) != 3 || stats.Tags[0].Name != "dinner" || stats.Tags[1].Name != "italian" {
+		t.Errorf("tags = %+v, want dinner and italian (2 each) before vegetarian", stats.Tags)
+	}
+
+	if empty := NewLibrary().Stats(); empty.Recipes != 0 || len(empty.Ingredients) != 0 {
+		t.Errorf("unexpected stats of an empty library: %+v", empty)
+	}
+}
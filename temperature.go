@@ -0,0 +1,364 @@
+package cooklang
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// TemperatureScale is the scale of a Temperature: Celsius or Fahrenheit.
+type TemperatureScale string
+
+const (
+	Celsius    TemperatureScale = "C"
+	Fahrenheit TemperatureScale = "F"
+)
+
+// Temperature represents a temperature mentioned in a step, such as "180°C" or
+// "350-375°F". Temperatures are recognized in the step text when a recipe is parsed,
+// so they can be converted (see ConvertTemperatures) and highlighted by renderers.
+//
+// Example Cooklang text: Bake at 180°C, Heat the oil to 175 °C, Roast at 400°F
+type Temperature struct {
+	Value         float64          `json:"value"`                    // Temperature (lower bound for ranges)
+	ValueMax      float64          `json:"value_max,omitempty"`      // Upper bound for ranges such as 180-200°C (0 if not a range)
+	Scale         TemperatureScale `json:"scale"`                    // Celsius or Fahrenheit
+	Text          string           `json:"text,omitempty"`           // Text as written in the recipe (empty after conversion)
+	Position      SourcePosition   `json:"position,omitzero"`        // Where the component is in the recipe source
+	NextComponent StepComponent    `json:"next_component,omitempty"` // Next component in the step
+	CooklangRenderable
+}
+
+func (Temperature) isStepComponent() {}
+
+// SetNext sets the next component in the step's linked list.
+// This implements the StepComponent interface for recipe step traversal.
+func (t *Temperature) SetNext(next StepComponent) {
+	t.NextComponent = next
+}
+
+// GetNext returns the next component in the step's linked list.
+// This implements the StepComponent interface for recipe step traversal.
+func (t *Temperature) GetNext() StepComponent {
+	return t.NextComponent
+}
+
+// Render returns the temperature as written in the recipe, so a parsed recipe
+// renders back unchanged. Converted temperatures render as RenderDisplay.
+func (t Temperature) Render() string {
+	if t.Text != "" {
+		return t.Text
+	}
+	return t.RenderDisplay()
+}
+
+// RenderDisplay returns the temperature for display.
+// Examples: "180°C", "350-375°F"
+func (t Temperature) RenderDisplay() string {
+	result := formatTemperatureValue(t.Value)
+	if t.ValueMax > t.Value {
+		result += "-" + formatTemperatureValue(t.ValueMax)
+	}
+	return result + "°" + string(t.Scale)
+}
+
+// RenderBothScales returns the temperature for display in its own scale followed by
+// the other one, converted as ConvertTo does.
+// Examples: "180°C / 355°F", "350-375°F / 175-190°C"
+func (t Temperature) RenderBothScales() string {
+	other := Fahrenheit
+	if t.Scale == Fahrenheit {
+		other = Celsius
+	}
+	return t.RenderDisplay() + " / " + t.ConvertTo(other).RenderDisplay()
+}
+
+// Suspicious says what looks wrong with a temperature, or returns "" if it is
+// plausible for cooking: hotter than a home oven gets (300°C, 575°F), colder than a
+// freezer (-40°), or, for an oven, below 200°F, which is more likely meant as °C.
+//
+// Parameters:
+//   - oven: Whether the temperature is an oven setting, such as oven_temp metadata
+//
+// Returns:
+//   - string: Why the temperature looks wrong, e.g. "350°C is hotter than a home oven; did you mean 350°F?"
+func (t Temperature) Suspicious(oven bool) string {
+	hottest := t.Value
+	if t.ValueMax > hottest {
+		hottest = t.ValueMax
+	}
+	switch {
+	case t.Value < -40:
+		return fmt.Sprintf("%s is colder than a freezer", t.RenderDisplay())
+	case t.Scale == Celsius && hottest > 300 && hottest <= 575:
+		return fmt.Sprintf("%s is hotter than a home oven; did you mean %s°F?", t.RenderDisplay(), formatTemperatureValue(hottest))
+	case t.Scale == Celsius && hottest > 300, t.Scale == Fahrenheit && hottest > 575:
+		return fmt.Sprintf("%s is hotter than a home oven", t.RenderDisplay())
+	case oven && t.Scale == Fahrenheit && hottest < 200:
+		return fmt.Sprintf("%s is cooler than an oven is set; did you mean %s°C?", t.RenderDisplay(), formatTemperatureValue(hottest))
+	}
+	return ""
+}
+
+// ConvertTo returns the temperature in the given scale. Converted values are rounded
+// the way recipes write them: oven temperatures (100 degrees and up) to the nearest 5,
+// lower ones to the nearest degree.
+//
+// Parameters:
+//   - scale: Celsius or Fahrenheit
+//
+// Returns:
+//   - *Temperature: An unlinked copy in the target scale (the same values if already in it)
+//
+// Example:
+//
+//	t := cooklang.Temperature{Value: 180, Scale: cooklang.Celsius}
+//	fmt.Println(t.ConvertTo(cooklang.Fahrenheit).RenderDisplay()) // 355°F
+func (t Temperature) ConvertTo(scale TemperatureScale) *Temperature {
+	converted := t
+	converted.NextComponent = nil
+	if t.Scale == scale || (scale != Celsius && scale != Fahrenheit) {
+		return &converted
+	}
+	convert := CelsiusToFahrenheit
+	if scale == Celsius {
+		convert = FahrenheitToCelsius
+	}
+	converted.Scale = scale
+	converted.Text = ""
+	converted.Value = roundTemperature(convert(t.Value))
+	if t.ValueMax > t.Value {
+		converted.ValueMax = roundTemperature(convert(t.ValueMax))
+	}
+	return &converted
+}
+
+// CelsiusToFahrenheit converts degrees Celsius to degrees Fahrenheit.
+func CelsiusToFahrenheit(celsius float64) float64 {
+	return celsius*9/5 + 32
+}
+
+// FahrenheitToCelsius converts degrees Fahrenheit to degrees Celsius.
+func FahrenheitToCelsius(fahrenheit float64) float64 {
+	return (fahrenheit - 32) * 5 / 9
+}
+
+// TemperatureScaleForSystem returns the temperature scale used with a unit system:
+// Fahrenheit for US units, Celsius otherwise.
+func TemperatureScaleForSystem(system UnitSystem) TemperatureScale {
+	if system == UnitSystemUS {
+		return Fahrenheit
+	}
+	return Celsius
+}
+
+// ParseTemperatureScale parses a temperature scale name: "C", "celsius", "F" or
+// "fahrenheit" (case-insensitive, optionally with a degree sign).
+func ParseTemperatureScale(name string) (TemperatureScale, error) {
+	switch strings.ToLower(strings.TrimPrefix(strings.TrimSpace(name), "°")) {
+	case "c", "celsius":
+		return Celsius, nil
+	case "f", "fahrenheit":
+		return Fahrenheit, nil
+	default:
+		return "", fmt.Errorf("unknown temperature scale: %s (use celsius or fahrenheit)", name)
+	}
+}
+
+// temperaturePattern matches temperatures written with a degree sign, such as
+// "180°C", "175 °C", "350-375°F" or "-18°C". The scale letter must end the word, so
+// "20°Celsius" is not matched.
+var temperaturePattern = regexp.MustCompile(`(-?\d+(?:[.,]\d+)?)(?:\s*[-–]\s*(\d+(?:[.,]\d+)?))?\s*°\s*([CcFf])\b`)
+
+// ParseTemperature parses a temperature such as "180°C", "350 °F", "180-200°C" or,
+// without the degree sign, "180C" and "350 F".
+//
+// Parameters:
+//   - s: The temperature text
+//
+// Returns:
+//   - *Temperature: The temperature, with Text set to s
+//   - error: An error if s is not a temperature
+//
+// Example:
+//
+//	t, _ := cooklang.ParseTemperature("350°F")
+//	fmt.Println(t.ConvertTo(cooklang.Celsius).RenderDisplay()) // 175°C
+func ParseTemperature(s string) (*Temperature, error) {
+	text := strings.TrimSpace(s)
+	normalized := text
+	if !strings.Contains(text, "°") {
+		// Insert the degree sign before a trailing scale letter ("180C", "350 F")
+		if i := strings.LastIndexAny(text, "CcFf"); i > 0 && i == len(text)-1 {
+			normalized = text[:i] + "°" + text[i:]
+		}
+	}
+	m := temperaturePattern.FindStringSubmatch(normalized)
+	if m == nil || m[0] != normalized {
+		return nil, fmt.Errorf("invalid temperature %q", s)
+	}
+	t := newTemperature(m)
+	t.Text = text
+	return t, nil
+}
+
+// newTemperature builds a temperature from a temperaturePattern match.
+func newTemperature(m []string) *Temperature {
+	t := &Temperature{Scale: TemperatureScale(strings.ToUpper(m[3]))}
+	t.Value, _ = strconv.ParseFloat(strings.Replace(m[1], ",", ".", 1), 64)
+	if m[2] != "" {
+		t.ValueMax, _ = strconv.ParseFloat(strings.Replace(m[2], ",", ".", 1), 64)
+	}
+	return t
+}
+
+// splitTemperatures splits instruction text around the temperatures it mentions,
+// returning the text as instructions and temperatures in order.
+func splitTemperatures(text string) []StepComponent {
+	matches := temperaturePattern.FindAllStringSubmatchIndex(text, -1)
+	if matches == nil {
+		return []StepComponent{&Instruction{Text: text}}
+	}
+	var components []StepComponent
+	last := 0
+	for _, loc := range matches {
+		if loc[0] > last {
+			components = append(components, &Instruction{Text: text[last:loc[0]]})
+		}
+		m := make([]string, 4)
+		for i := range m {
+			if loc[2*i] >= 0 {
+				m[i] = text[loc[2*i]:loc[2*i+1]]
+			}
+		}
+		t := newTemperature(m)
+		t.Text = m[0]
+		components = append(components, t)
+		last = loc[1]
+	}
+	if last < len(text) {
+		components = append(components, &Instruction{Text: text[last:]})
+	}
+	return components
+}
+
+// roundTemperature rounds a converted temperature: to the nearest 5 from 100
+// degrees up, otherwise to the nearest degree.
+func roundTemperature(value float64) float64 {
+	if math.Abs(value) >= 100 {
+		return math.Round(value/5) * 5
+	}
+	return math.Round(value)
+}
+
+// formatTemperatureValue formats a temperature without trailing zeros.
+func formatTemperatureValue(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// TemperatureMetadataKeys are the metadata keys that hold a temperature, such as
+// "oven_temp: 180°C". ConvertTemperaturesTo converts them with the step temperatures.
+var TemperatureMetadataKeys = []string{"oven_temp", "oven_temperature", "temperature"}
+
+// IsTemperatureMetadataKey reports whether a metadata key is one of
+// TemperatureMetadataKeys (case-insensitive).
+func IsTemperatureMetadataKey(key string) bool {
+	return slices.Contains(TemperatureMetadataKeys, strings.ToLower(strings.TrimSpace(key)))
+}
+
+// OvenTemperature returns the oven temperature from the first of the
+// TemperatureMetadataKeys the recipe sets, such as "oven_temp: 180°C".
+//
+// Returns:
+//   - *Temperature: The temperature
+//   - bool: False if no key is set or its value is not a temperature with a scale
+//
+// Example:
+//
+//	recipe, _ := cooklang.ParseString("---\noven_temp: 350F\n---\nBake the @bread{}.")
+//	if t, ok := recipe.OvenTemperature(); ok {
+//	    fmt.Println(t.RenderBothScales()) // 350°F / 175°C
+//	}
+func (r *Recipe) OvenTemperature() (*Temperature, bool) {
+	for _, key := range TemperatureMetadataKeys {
+		if value, ok := r.Metadata[key]; ok {
+			t, err := ParseTemperature(value)
+			return t, err == nil
+		}
+	}
+	return nil, false
+}
+
+// GetTemperatures returns all temperatures mentioned in the recipe's steps, in order.
+//
+// Returns:
+//   - []*Temperature: The temperatures (nil if there are none)
+//
+// Example:
+//
+//	recipe, _ := cooklang.ParseString("Bake at 180°C for ~{25%minutes}.")
+//	for _, t := range recipe.GetTemperatures() {
+//	    fmt.Println(t.RenderDisplay()) // 180°C
+//	}
+func (r *Recipe) GetTemperatures() []*Temperature {
+	var temperatures []*Temperature
+	for step := r.FirstStep; step != nil; step = step.NextStep {
+		for component := step.FirstComponent; component != nil; component = component.GetNext() {
+			if t, ok := component.(*Temperature); ok {
+				temperatures = append(temperatures, t)
+			}
+		}
+	}
+	return temperatures
+}
+
+// ConvertTemperatures returns a copy of the recipe with every temperature converted
+// to the scale of the unit system: Fahrenheit for UnitSystemUS, Celsius for metric
+// and imperial. Ingredients are left as they are; see ConvertToSystem.
+//
+// Parameters:
+//   - system: The target unit system
+//
+// Returns:
+//   - *Recipe: A new recipe with converted temperatures
+//
+// Example:
+//
+//	recipe, _ := cooklang.ParseString("Bake at 350°F until golden.")
+//	metric := recipe.ConvertTemperatures(cooklang.UnitSystemMetric)
+//	fmt.Println(metric.Render()) // Bake at 175°C until golden.
+func (r *Recipe) ConvertTemperatures(system UnitSystem) *Recipe {
+	return r.ConvertTemperaturesTo(TemperatureScaleForSystem(system))
+}
+
+// ConvertTemperaturesTo returns a copy of the recipe with every temperature
+// converted to the given scale, including the TemperatureMetadataKeys metadata.
+//
+// Parameters:
+//   - scale: Celsius or Fahrenheit
+//
+// Returns:
+//   - *Recipe: A new recipe with converted temperatures
+func (r *Recipe) ConvertTemperaturesTo(scale TemperatureScale) *Recipe {
+	converted := r.Clone()
+	for key, value := range converted.Metadata {
+		if !IsTemperatureMetadataKey(key) {
+			continue
+		}
+		if t, err := ParseTemperature(value); err == nil && t.Scale != scale {
+			converted.Metadata[key] = t.ConvertTo(scale).RenderDisplay()
+		}
+	}
+	for step := converted.FirstStep; step != nil; step = step.NextStep {
+		for component := step.FirstComponent; component != nil; component = component.GetNext() {
+			if t, ok := component.(*Temperature); ok {
+				result := t.ConvertTo(scale)
+				t.Value, t.ValueMax, t.Scale, t.Text = result.Value, result.ValueMax, result.Scale, result.Text
+			}
+		}
+	}
+	return converted
+}
@@ -0,0 +1,210 @@
+package cooklang
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseStringTemperatures(t *testing.T) {
+	recipe, err := ParseString("Preheat the #oven{} to 180°C and bake until golden.\n\nFry at 350-375 °F. Chill to -18°C.")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	temperatures := recipe.GetTemperatures()
+	expected := []Temperature{
+		{Value: 180, Scale: Celsius, Text: "180°C"},
+		{Value: 350, ValueMax: 375, Scale: Fahrenheit, Text: "350-375 °F"},
+		{Value: -18, Scale: Celsius, Text: "-18°C"},
+	}
+	if len(temperatures) != len(expected) {
+		t.Fatalf("expected %d temperatures, got %d", len(expected), len(temperatures))
+	}
+	for i, want := range expected {
+		got := temperatures[i]
+		if got.Value != want.Value || got.ValueMax != want.ValueMax || got.Scale != want.Scale || got.Text != want.Text {
+			t.Errorf("temperature %d: expected %+v, got %+v", i, want, *got)
+		}
+	}
+
+	// The text around a temperature stays in the step
+	first := recipe.FirstStep.FirstComponent
+	var text strings.Builder
+	for c := first; c != nil; c = c.GetNext() {
+		text.WriteString(c.Render())
+	}
+	if text.String() != "Preheat the #oven{} to 180°C and bake until golden." {
+		t.Errorf("step does not render back unchanged: %q", text.String())
+	}
+}
+
+func TestParseStringIgnoresDegreesWithoutScale(t *testing.T) {
+	recipe, err := ParseString("Cut at a 45° angle. Set the dial to 20°Celsius.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if temperatures := recipe.GetTemperatures(); len(temperatures) != 0 {
+		t.Errorf("expected no temperatures, got %d", len(temperatures))
+	}
+}
+
+func TestTemperatureConvertTo(t *testing.T) {
+	tests := []struct {
+		temperature Temperature
+		scale       TemperatureScale
+		expected    string
+	}{
+		{Temperature{Value: 180, Scale: Celsius}, Fahrenheit, "355°F"},
+		{Temperature{Value: 350, Scale: Fahrenheit}, Celsius, "175°C"},
+		{Temperature{Value: 425, Scale: Fahrenheit}, Celsius, "220°C"},
+		{Temperature{Value: 63, Scale: Celsius}, Fahrenheit, "145°F"},
+		{Temperature{Value: 4, Scale: Celsius}, Fahrenheit, "39°F"},
+		{Temperature{Value: 200, ValueMax: 220, Scale: Celsius}, Fahrenheit, "390-430°F"},
+		{Temperature{Value: 180, Scale: Celsius, Text: "180 °C"}, Celsius, "180°C"},
+	}
+	for _, tt := range tests {
+		if got := tt.temperature.ConvertTo(tt.scale).RenderDisplay(); got != tt.expected {
+			t.Errorf("%s to %s: expected %q, got %q", tt.temperature.RenderDisplay(), tt.scale, tt.expected, got)
+		}
+	}
+
+	same := Temperature{Value: 180, Scale: Celsius, Text: "180 °C"}
+	if got := same.ConvertTo(Celsius).Render(); got != "180 °C" {
+		t.Errorf("converting to the same scale should keep the text, got %q", got)
+	}
+}
+
+func TestParseTemperature(t *testing.T) {
+	tests := []struct {
+		input    string
+		value    float64
+		valueMax float64
+		scale    TemperatureScale
+	}{
+		{"180°C", 180, 0, Celsius},
+		{"350 °F", 350, 0, Fahrenheit},
+		{"180-200°C", 180, 200, Celsius},
+		{"180C", 180, 0, Celsius},
+		{"350 f", 350, 0, Fahrenheit},
+		{"37,5°C", 37.5, 0, Celsius},
+	}
+	for _, tt := range tests {
+		got, err := ParseTemperature(tt.input)
+		if err != nil {
+			t.Errorf("ParseTemperature(%q) failed: %v", tt.input, err)
+			continue
+		}
+		if got.Value != tt.value || got.ValueMax != tt.valueMax || got.Scale != tt.scale {
+			t.Errorf("ParseTemperature(%q) = %+v", tt.input, *got)
+		}
+	}
+
+	for _, input := range []string{"", "hot", "180", "180°K", "at 180°C"} {
+		if _, err := ParseTemperature(input); err == nil {
+			t.Errorf("ParseTemperature(%q) should fail", input)
+		}
+	}
+}
+
+func TestRecipeConvertTemperatures(t *testing.T) {
+	recipe, err := ParseString("Bake the @flour{2%cups} at 350°F until golden.")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	metric := recipe.ConvertTemperatures(UnitSystemMetric)
+	if got := metric.GetTemperatures()[0].RenderDisplay(); got != "175°C" {
+		t.Errorf("expected 175°C, got %q", got)
+	}
+	if got := recipe.GetTemperatures()[0].RenderDisplay(); got != "350°F" {
+		t.Errorf("the original recipe should be unchanged, got %q", got)
+	}
+	if ing := metric.GetIngredients().Ingredients[0]; ing.Unit != "cups" {
+		t.Errorf("ConvertTemperatures should leave ingredients alone, got unit %q", ing.Unit)
+	}
+
+	// Converting units converts temperatures as well
+	converted := recipe.ConvertToSystem(UnitSystemMetric)
+	if got := converted.GetTemperatures()[0].RenderDisplay(); got != "175°C" {
+		t.Errorf("expected ConvertToSystem to convert temperatures, got %q", got)
+	}
+	if got := recipe.ConvertTemperatures(UnitSystemUS).GetTemperatures()[0].Render(); got != "350°F" {
+		t.Errorf("expected temperatures already in Fahrenheit to be kept, got %q", got)
+	}
+}
+
+func TestParseTemperatureScale(t *testing.T) {
+	for input, want := range map[string]TemperatureScale{"C": Celsius, "celsius": Celsius, "°F": Fahrenheit, "Fahrenheit": Fahrenheit} {
+		if got, err := ParseTemperatureScale(input); err != nil || got != want {
+			t.Errorf("ParseTemperatureScale(%q) = %q, %v", input, got, err)
+		}
+	}
+	if _, err := ParseTemperatureScale("kelvin"); err == nil {
+		t.Error("expected an error for kelvin")
+	}
+}
+
+func TestTemperatureRenderBothScales(t *testing.T) {
+	tests := []struct {
+		temperature Temperature
+		want        string
+	}{
+		{Temperature{Value: 180, Scale: Celsius}, "180°C / 355°F"},
+		{Temperature{Value: 350, ValueMax: 375, Scale: Fahrenheit}, "350-375°F / 175-190°C"},
+	}
+	for _, tt := range tests {
+		if got := tt.temperature.RenderBothScales(); got != tt.want {
+			t.Errorf("RenderBothScales() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestTemperatureSuspicious(t *testing.T) {
+	tests := []struct {
+		temperature Temperature
+		oven        bool
+		want        string
+	}{
+		{Temperature{Value: 180, Scale: Celsius}, true, ""},
+		{Temperature{Value: 400, Scale: Fahrenheit}, true, ""},
+		{Temperature{Value: 75, Scale: Celsius}, false, ""},
+		{Temperature{Value: 350, Scale: Celsius}, true, "did you mean 350°F?"},
+		{Temperature{Value: 900, Scale: Fahrenheit}, true, "hotter than a home oven"},
+		{Temperature{Value: 180, Scale: Fahrenheit}, true, "did you mean 180°C?"},
+		{Temperature{Value: 180, Scale: Fahrenheit}, false, ""}, // Holding or sous vide
+		{Temperature{Value: -60, Scale: Celsius}, false, "colder than a freezer"},
+	}
+	for _, tt := range tests {
+		got := tt.temperature.Suspicious(tt.oven)
+		if (tt.want == "") != (got == "") || !strings.Contains(got, tt.want) {
+			t.Errorf("%s.Suspicious(%v) = %q, want %q", tt.temperature.RenderDisplay(), tt.oven, got, tt.want)
+		}
+	}
+}
+
+func TestRecipeOvenTemperature(t *testing.T) {
+	recipe, err := ParseString("---\noven_temp: 350F\n---\nBake the @bread{} at 350°F.\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	oven, ok := recipe.OvenTemperature()
+	if !ok || oven.RenderDisplay() != "350°F" {
+		t.Fatalf("OvenTemperature() = %v, %v, want 350°F", oven, ok)
+	}
+
+	metric := recipe.ConvertTemperatures(UnitSystemMetric)
+	if got := metric.Metadata["oven_temp"]; got != "175°C" {
+		t.Errorf("expected oven_temp converted to 175°C, got %q", got)
+	}
+	if got := recipe.Metadata["oven_temp"]; got != "350F" {
+		t.Errorf("the original metadata should be unchanged, got %q", got)
+	}
+
+	recipe, err = ParseString("---\noven_temp: hot\n---\nBake the @bread{}.\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := recipe.OvenTemperature(); ok {
+		t.Error("expected no oven temperature for \"hot\"")
+	}
+}
@@ -0,0 +1,160 @@
+package cooklang
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// timerUnits maps timer unit names to their length. Units are matched case-insensitively.
+var timerUnits = map[string]time.Duration{
+	"s": time.Second, "sec": time.Second, "secs": time.Second, "second": time.Second, "seconds": time.Second,
+	"m": time.Minute, "min": time.Minute, "mins": time.Minute, "minute": time.Minute, "minutes": time.Minute,
+	"h": time.Hour, "hr": time.Hour, "hrs": time.Hour, "hour": time.Hour, "hours": time.Hour,
+	"d": 24 * time.Hour, "day": 24 * time.Hour, "days": 24 * time.Hour,
+}
+
+// ParsedDuration returns the timer's duration as a time.Duration. For a range such as
+// ~{1-2%hours} it returns the lower bound; use DurationRange for both bounds.
+//
+// The amount may be a number, fraction, Unicode fraction or range ("90", "1/2", "1½",
+// "10-15"). A timer without a unit is read as minutes; a unit may also be written in
+// the duration itself ("90 min", "1 hour 30 minutes").
+//
+// Returns:
+//   - time.Duration: The (lower bound of the) duration
+//   - error: An error if the duration or unit is not recognized (e.g., "overnight")
+//
+// Example:
+//
+//	timer := cooklang.Timer{Duration: "1-2", Unit: "hours"}
+//	d, _ := timer.ParsedDuration() // 1h0m0s
+func (t Timer) ParsedDuration() (time.Duration, error) {
+	low, _, err := t.DurationRange()
+	return low, err
+}
+
+// DurationRange returns the lower and upper bounds of the timer's duration.
+// Both bounds are equal for timers that are not ranges.
+//
+// Returns:
+//   - time.Duration: The lower bound
+//   - time.Duration: The upper bound
+//   - error: An error if the duration or unit is not recognized
+//
+// Example:
+//
+//	timer := cooklang.Timer{Duration: "10-15", Unit: "min"}
+//	low, high, _ := timer.DurationRange() // 10m0s, 15m0s
+func (t Timer) DurationRange() (time.Duration, time.Duration, error) {
+	amount, unit := strings.TrimSpace(t.Duration), strings.TrimSpace(t.Unit)
+	if unit == "" {
+		amount, unit = splitDurationUnit(amount)
+	}
+
+	if per, ok := timerUnits[strings.ToLower(unit)]; ok || unit == "" {
+		if unit == "" {
+			per = time.Minute
+		}
+		if q, err := ParseQuantity(amount); err == nil && q.Min() > 0 {
+			return time.Duration(q.Min() * float64(per)), time.Duration(q.Max() * float64(per)), nil
+		}
+	}
+
+	// Compound durations written out in full, e.g. "1 hour 30 minutes"
+	if t.Unit == "" {
+		if d, ok := parseHumanDuration(t.Duration); ok {
+			return d, d, nil
+		}
+	}
+	return 0, 0, fmt.Errorf("cannot parse timer duration %q", t.RenderDisplay())
+}
+
+// splitDurationUnit splits a trailing unit word off a duration such as "90 min".
+func splitDurationUnit(s string) (string, string) {
+	end := strings.LastIndexFunc(s, func(r rune) bool { return !unicode.IsLetter(r) })
+	if end < 0 || end == len(s)-1 {
+		return s, ""
+	}
+	return strings.TrimSpace(s[:end+1]), s[end+1:]
+}
+
+// scaleDuration scales a numeric timer duration string, including ranges such as
+// "10-15", returning it unchanged if it cannot be parsed (e.g., "overnight").
+func scaleDuration(duration string, factor float64) string {
+	q, err := ParseQuantity(duration)
+	if err != nil || q.Min() <= 0 {
+		return duration
+	}
+	scaled := q.Scale(factor)
+	if scaled.IsRange() {
+		return strconv.FormatFloat(scaled.Min(), 'f', -1, 64) + "-" + strconv.FormatFloat(scaled.Max(), 'f', -1, 64)
+	}
+	return strconv.FormatFloat(scaled.Min(), 'f', -1, 64)
+}
+
+// GetTimers returns all timers in the recipe, in step order.
+//
+// Returns:
+//   - []*Timer: The timers
+func (r *Recipe) GetTimers() []*Timer {
+	var timers []*Timer
+	for step := r.FirstStep; step != nil; step = step.NextStep {
+		for component := step.FirstComponent; component != nil; component = component.GetNext() {
+			if timer, ok := component.(*Timer); ok {
+				timers = append(timers, timer)
+			}
+		}
+	}
+	return timers
+}
+
+// TotalTimerTime returns the sum of all timer durations in the recipe. Ranges count
+// with their lower bound, and timers that cannot be parsed (e.g., "overnight") are skipped.
+//
+// Returns:
+//   - time.Duration: The total of all timers
+//
+// Example:
+//
+//	recipe, _ := cooklang.ParseString("Boil for ~{10%minutes}, then rest ~{1/2%hour}.")
+//	fmt.Println(recipe.TotalTimerTime()) // 40m0s
+func (r *Recipe) TotalTimerTime() time.Duration {
+	var total time.Duration
+	for _, timer := range r.GetTimers() {
+		if d, err := timer.ParsedDuration(); err == nil {
+			total += d
+		}
+	}
+	return total
+}
+
+// TotalActiveTime returns the time the recipe's timers keep the cook busy when steps are
+// followed in order. Timers within the same step are assumed to run at the same time, so
+// each step contributes its longest timer. Ranges count with their lower bound, and timers
+// that cannot be parsed are skipped.
+//
+// Returns:
+//   - time.Duration: The sum over all steps of the longest timer in each step
+//
+// Example:
+//
+//	recipe, _ := cooklang.ParseString("Boil ~pasta{10%min} while frying ~sauce{5%min}.\n\nRest ~{2%min}.")
+//	fmt.Println(recipe.TotalActiveTime()) // 12m0s (TotalTimerTime is 17m0s)
+func (r *Recipe) TotalActiveTime() time.Duration {
+	var total time.Duration
+	for step := r.FirstStep; step != nil; step = step.NextStep {
+		var longest time.Duration
+		for component := step.FirstComponent; component != nil; component = component.GetNext() {
+			if timer, ok := component.(*Timer); ok {
+				if d, err := timer.ParsedDuration(); err == nil && d > longest {
+					longest = d
+				}
+			}
+		}
+		total += longest
+	}
+	return total
+}
@@ -2,6 +2,7 @@ package cooklang
 
 import (
 	"testing"
+	"time"
 )
 
 func TestTimerRenderDisplay(t *testing.T) {
@@ -66,6 +67,46 @@ func TestTimerRenderDisplay(t *testing.T) {
 	}
 }
 
+func TestTimerRenderRoundTrip(t *testing.T) {
+	tests := []struct {
+		source   string
+		expected string // Render output
+		display  string // String output
+	}{
+		{"~{10%minutes}", "~{10%minutes}", "10 minutes"},
+		{"~boil{15%min}", "~boil{15%min}", "15 min"},
+		{"~roast time{4%hours}", "~roast time{4%hours}", "4 hours"},
+		{"~rest{1-2%hours}", "~rest{1-2%hours}", "1-2 hours"},
+		{"~{5}", "~{5}", "5"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			recipe, err := ParseString("Wait "+tt.source+".", WithExtendedMode())
+			if err != nil {
+				t.Fatalf("failed to parse: %v", err)
+			}
+			timers := recipe.GetTimers()
+			if len(timers) != 1 {
+				t.Fatalf("expected 1 timer, got %d", len(timers))
+			}
+			if got := timers[0].Render(); got != tt.expected {
+				t.Errorf("Render() = %q, want %q", got, tt.expected)
+			}
+			if got := timers[0].String(); got != tt.display {
+				t.Errorf("String() = %q, want %q", got, tt.display)
+			}
+
+			again, err := ParseString("Wait "+timers[0].Render()+".", WithExtendedMode())
+			if err != nil {
+				t.Fatalf("failed to parse rendered timer: %v", err)
+			}
+			if rendered := again.GetTimers()[0]; rendered.Name != timers[0].Name || rendered.Duration != timers[0].Duration || rendered.Unit != timers[0].Unit {
+				t.Errorf("round trip changed the timer: %+v, want %+v", rendered, timers[0])
+			}
+		})
+	}
+}
+
 func TestTimerParsingWithUnit(t *testing.T) {
 	tests := []struct {
 		name             string
@@ -205,3 +246,64 @@ func containsHelper(s, substr string) bool {
 	}
 	return false
 }
+
+func TestTimerParsedDuration(t *testing.T) {
+	tests := []struct {
+		name     string
+		timer    Timer
+		low      time.Duration
+		high     time.Duration
+		hasError bool
+	}{
+		{"minutes", Timer{Duration: "10", Unit: "minutes"}, 10 * time.Minute, 10 * time.Minute, false},
+		{"abbreviated unit", Timer{Duration: "90", Unit: "min"}, 90 * time.Minute, 90 * time.Minute, false},
+		{"range", Timer{Duration: "1-2", Unit: "hours"}, time.Hour, 2 * time.Hour, false},
+		{"fraction", Timer{Duration: "1/2", Unit: "hour"}, 30 * time.Minute, 30 * time.Minute, false},
+		{"unicode fraction", Timer{Duration: "1½", Unit: "h"}, 90 * time.Minute, 90 * time.Minute, false},
+		{"seconds", Timer{Duration: "45", Unit: "Seconds"}, 45 * time.Second, 45 * time.Second, false},
+		{"days", Timer{Duration: "2", Unit: "days"}, 48 * time.Hour, 48 * time.Hour, false},
+		{"no unit means minutes", Timer{Duration: "5"}, 5 * time.Minute, 5 * time.Minute, false},
+		{"unit in duration", Timer{Duration: "90 min"}, 90 * time.Minute, 90 * time.Minute, false},
+		{"range with unit in duration", Timer{Duration: "1-2 hours"}, time.Hour, 2 * time.Hour, false},
+		{"compound duration", Timer{Duration: "1 hour 30 minutes"}, 90 * time.Minute, 90 * time.Minute, false},
+		{"not a duration", Timer{Duration: "overnight"}, 0, 0, true},
+		{"unknown unit", Timer{Duration: "3", Unit: "fortnights"}, 0, 0, true},
+		{"name only", Timer{Name: "rest"}, 0, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			low, high, err := tt.timer.DurationRange()
+			if (err != nil) != tt.hasError {
+				t.Fatalf("DurationRange() error = %v, hasError %v", err, tt.hasError)
+			}
+			if low != tt.low || high != tt.high {
+				t.Errorf("DurationRange() = %v, %v, want %v, %v", low, high, tt.low, tt.high)
+			}
+			if d, _ := tt.timer.ParsedDuration(); d != tt.low {
+				t.Errorf("ParsedDuration() = %v, want %v", d, tt.low)
+			}
+		})
+	}
+}
+
+func TestRecipeTimerTotals(t *testing.T) {
+	recipe, err := ParseString(`Boil ~pasta{10%minutes} while frying ~sauce{5%min}.
+
+Rest for ~{1/2%hour}, or ~{overnight}.
+
+Serve.`)
+	if err != nil {
+		t.Fatalf("Failed to parse recipe: %v", err)
+	}
+
+	if got := len(recipe.GetTimers()); got != 4 {
+		t.Errorf("GetTimers() returned %d timers, want 4", got)
+	}
+	if got := recipe.TotalTimerTime(); got != 45*time.Minute {
+		t.Errorf("TotalTimerTime() = %v, want 45m", got)
+	}
+	if got := recipe.TotalActiveTime(); got != 40*time.Minute {
+		t.Errorf("TotalActiveTime() = %v, want 40m", got)
+	}
+}
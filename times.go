@@ -0,0 +1,41 @@
+package cooklang
+
+import "time"
+
+// Times are a recipe's prep, cook and total time, read from the prep_time,
+// cook_time and total_time metadata as durations. Times that are missing or cannot
+// be read are zero.
+type Times struct {
+	Prep  time.Duration `json:"prep,omitempty"`  // From prep_time
+	Cook  time.Duration `json:"cook,omitempty"`  // From cook_time
+	Total time.Duration `json:"total,omitempty"` // From total_time or time, or Prep plus Cook
+	// TotalComputed is set when the recipe has no total time of its own and Total is
+	// Prep plus Cook.
+	TotalComputed bool `json:"total_computed,omitempty"`
+}
+
+// Times returns the recipe's prep, cook and total time as durations. Without a
+// total_time (or time) of its own, the total time of a recipe with both prep_time
+// and cook_time is their sum.
+//
+// Returns:
+//   - Times: The times; zero where the metadata is missing or not a duration
+//
+// Example:
+//
+//	recipe, _ := cooklang.ParseString("---\nprep_time: 15 minutes\ncook_time: 1 hour\n---\nBake @bread{}.")
+//	times := recipe.Times()
+//	fmt.Println(times.Total, times.TotalComputed) // 1h15m0s true
+func (r *Recipe) Times() Times {
+	var times Times
+	times.Prep, _ = parseHumanDuration(r.PrepTime)
+	times.Cook, _ = r.Metadata.GetDuration("cook_time")
+	if total, ok := parseHumanDuration(r.TotalTime); ok {
+		times.Total = total
+	} else if total, err := r.Metadata.GetDuration("time"); err == nil {
+		times.Total = total
+	} else if times.Prep > 0 && times.Cook > 0 {
+		times.Total, times.TotalComputed = times.Prep+times.Cook, true
+	}
+	return times
+}
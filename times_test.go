@@ -0,0 +1,31 @@
+package cooklang
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRecipeTimes(t *testing.T) {
+	tests := []struct {
+		name     string
+		metadata string
+		expected Times
+	}{
+		{"computed total", "prep_time: 15 minutes\ncook_time: 1 hour", Times{Prep: 15 * time.Minute, Cook: time.Hour, Total: 75 * time.Minute, TotalComputed: true}},
+		{"own total", "prep_time: 15 minutes\ncook_time: 1 hour\ntotal_time: 1h30m", Times{Prep: 15 * time.Minute, Cook: time.Hour, Total: 90 * time.Minute}},
+		{"time key", "time: 40 min", Times{Total: 40 * time.Minute}},
+		{"prep only", "prep_time: 10 minutes", Times{Prep: 10 * time.Minute}},
+		{"not a duration", "prep_time: a while\ncook_time: 20 minutes", Times{Cook: 20 * time.Minute}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recipe, err := ParseString("---\n" + tt.metadata + "\n---\nBake the @bread{}.")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := recipe.Times(); got != tt.expected {
+				t.Errorf("Times() = %+v, want %+v", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package cooklang
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// TitleFromFilename derives a recipe title from a file name by dropping the directory
+// and extension and turning underscores (and hyphens, in names without spaces) into
+// spaces. Capitalization is kept as it is.
+//
+// Parameters:
+//   - filename: Path to the recipe file
+//
+// Returns:
+//   - string: The title, or "" if the file name has no usable characters
+//
+// Example:
+//
+//	cooklang.TitleFromFilename("recipes/Gin_and_Tonic.cook") // "Gin and Tonic"
+//	cooklang.TitleFromFilename("recipes/banana-bread.cook")  // "banana bread"
+func TitleFromFilename(filename string) string {
+	name := filepath.Base(filename)
+	name = strings.TrimSuffix(name, filepath.Ext(name))
+	name = strings.ReplaceAll(name, "_", " ")
+	if !strings.Contains(name, " ") {
+		name = strings.ReplaceAll(name, "-", " ")
+	}
+	name = strings.Join(strings.Fields(name), " ")
+	if name == "." {
+		return ""
+	}
+	return name
+}
+
+// FilenameFromTitle returns a file name for a recipe with the given title, the
+// reverse of TitleFromFilename: "Fluffy Pancakes" becomes "Fluffy Pancakes.cook".
+// Characters that are not allowed in file names are removed.
+//
+// Parameters:
+//   - title: The recipe title
+//
+// Returns:
+//   - string: The file name, or "" if the title has no usable characters
+//
+// Example:
+//
+//	cooklang.FilenameFromTitle("Mac/Cheese: Deluxe") // "MacCheese Deluxe.cook"
+func FilenameFromTitle(title string) string {
+	name := strings.Map(func(c rune) rune {
+		if strings.ContainsRune(`/\:*?"<>|`, c) {
+			return -1
+		}
+		return c
+	}, title)
+	name = strings.Join(strings.Fields(name), " ")
+	if name == "" || name == "." || name == ".." {
+		return ""
+	}
+	return name + ".cook"
+}
+
+// InferTitle gives a recipe without title metadata a title, so listings and exports
+// never show a blank name. The title is taken from the file name, or, if fromSection is
+// true, from a section header that opens the recipe before any instructions
+// (e.g., "= Grandma's Lasagna ="). Without a usable file name, the opening section
+// header is used either way. TitleInferred is set when a title is inferred.
+//
+// ParseFile calls InferTitle(filename, false) automatically; recipes parsed from strings
+// or bytes keep an empty title unless InferTitle is called.
+//
+// Parameters:
+//   - filename: Path the recipe was loaded from (may be empty)
+//   - fromSection: Prefer an opening section header over the file name
+//
+// Returns:
+//   - bool: true if a title was inferred
+//
+// Example:
+//
+//	recipe, _ := cooklang.ParseString("= Weeknight Curry =\n\nFry the @onion{1}.")
+//	recipe.InferTitle("curry.cook", true)
+//	fmt.Println(recipe.Title, recipe.TitleInferred) // Weeknight Curry true
+func (r *Recipe) InferTitle(filename string, fromSection bool) bool {
+	if r.Title != "" && !r.TitleInferred {
+		return false
+	}
+
+	title := ""
+	section := r.openingSection()
+	if fromSection {
+		title = section
+	}
+	if title == "" && filename != "" {
+		title = TitleFromFilename(filename)
+	}
+	if title == "" {
+		title = section
+	}
+	if title == "" {
+		return false
+	}
+	r.Title = title
+	r.TitleInferred = true
+	return true
+}
+
+// openingSection returns the name of a section header that comes before any
+// instructions, or "" if the recipe does not start with one.
+func (r *Recipe) openingSection() string {
+	for step := r.FirstStep; step != nil; step = step.NextStep {
+		for component := step.FirstComponent; component != nil; component = component.GetNext() {
+			switch comp := component.(type) {
+			case *Section:
+				return strings.TrimSpace(comp.Name)
+			case *Comment, *Note:
+				continue
+			default:
+				return ""
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,94 @@
+package cooklang
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestTitleFromFilename(t *testing.T) {
+	tests := []struct {
+		filename string
+		expected string
+	}{
+		{"recipes/Gin_and_Tonic.cook", "Gin and Tonic"},
+		{"banana-bread.cook", "banana bread"},
+		{"sauces/Sauce - Hollandaise.cook", "Sauce - Hollandaise"},
+		{"Chili  con  carne.cook", "Chili con carne"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := TitleFromFilename(tt.filename); got != tt.expected {
+			t.Errorf("TitleFromFilename(%q) = %q, want %q", tt.filename, got, tt.expected)
+		}
+	}
+}
+
+func TestFilenameFromTitle(t *testing.T) {
+	tests := []struct {
+		title    string
+		expected string
+	}{
+		{"Fluffy Pancakes", "Fluffy Pancakes.cook"},
+		{"Mac/Cheese: Deluxe", "MacCheese Deluxe.cook"},
+		{"  Chili  con  carne ", "Chili con carne.cook"},
+		{"..", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := FilenameFromTitle(tt.title); got != tt.expected {
+			t.Errorf("FilenameFromTitle(%q) = %q, want %q", tt.title, got, tt.expected)
+		}
+	}
+}
+
+func TestInferTitle(t *testing.T) {
+	tests := []struct {
+		name        string
+		recipe      string
+		filename    string
+		fromSection bool
+		expected    string
+		inferred    bool
+	}{
+		{"from filename", "Fry the @onion{1}.", "weeknight_curry.cook", false, "weeknight curry", true},
+		{"metadata wins", "---\ntitle: Curry\n---\nFry the @onion{1}.", "weeknight_curry.cook", true, "Curry", false},
+		{"opening section", "-- A comment first\n\n= Weeknight Curry =\n\nFry the @onion{1}.", "curry.cook", true, "Weeknight Curry", true},
+		{"section not preferred", "= Weeknight Curry =\n\nFry the @onion{1}.", "curry.cook", false, "curry", true},
+		{"section without filename", "= Weeknight Curry =\n\nFry the @onion{1}.", "", false, "Weeknight Curry", true},
+		{"section after instructions", "Fry the @onion{1}.\n\n= Serving =\n\nServe.", "", true, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recipe, err := ParseString(tt.recipe)
+			if err != nil {
+				t.Fatalf("Failed to parse recipe: %v", err)
+			}
+			if got := recipe.InferTitle(tt.filename, tt.fromSection); got != tt.inferred {
+				t.Errorf("InferTitle() = %v, want %v", got, tt.inferred)
+			}
+			if recipe.Title != tt.expected || recipe.TitleInferred != tt.inferred {
+				t.Errorf("Title = %q (inferred %v), want %q (inferred %v)", recipe.Title, recipe.TitleInferred, tt.expected, tt.inferred)
+			}
+			if _, ok := recipe.Metadata["title"]; ok != (tt.name == "metadata wins") {
+				t.Errorf("Inferred title must not be written to metadata: %v", recipe.Metadata)
+			}
+		})
+	}
+}
+
+func TestParseFileInfersTitle(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "Gin_and_Tonic.cook")
+	if err := writeFile(path, "Pour @gin{50%ml} over @ice{}."); err != nil {
+		t.Fatal(err)
+	}
+	recipe, err := ParseFile(path)
+	if err != nil {
+		t.Fatalf("ParseFile error: %v", err)
+	}
+	if recipe.Title != "Gin and Tonic" || !recipe.TitleInferred {
+		t.Errorf("Title = %q (inferred %v), want inferred \"Gin and Tonic\"", recipe.Title, recipe.TitleInferred)
+	}
+	if scaled := recipe.Scale(2); !scaled.TitleInferred {
+		t.Error("Scale lost TitleInferred")
+	}
+}
@@ -1,10 +1,37 @@
 package token
 
+import "strconv"
+
 type TokenType string
 
 type Token struct {
 	Type    TokenType
 	Literal string
+	Pos     int // Byte offset of the first character of the token in the input
+	End     int // Byte offset just past the last character of the token
+}
+
+// SourcePosition is the location of a piece of recipe source text: the line and
+// column where it starts, and the byte range it covers. Lines and columns are
+// 1-based; columns count bytes, as in go/token. The zero value means "unknown".
+type SourcePosition struct {
+	Line      int `json:"line"`       // Line of the first character (1-based)
+	Column    int `json:"column"`     // Byte column of the first character (1-based)
+	Offset    int `json:"offset"`     // Byte offset of the first character
+	EndOffset int `json:"end_offset"` // Byte offset just past the last character
+}
+
+// IsValid reports whether the position is known.
+func (p SourcePosition) IsValid() bool {
+	return p.Line > 0
+}
+
+// String formats the position as "line:column", or "-" if it is unknown.
+func (p SourcePosition) String() string {
+	if !p.IsValid() {
+		return "-"
+	}
+	return strconv.Itoa(p.Line) + ":" + strconv.Itoa(p.Column)
 }
 
 const (
@@ -17,6 +44,7 @@ const (
 	COMMENT        = "-- "
 	BLOCK_COMMENT  = "[- -]"
 	NOTE           = ">"
+	METADATA       = ">>"
 	SECTION        = "="
 	SECTION_HEADER = "SECTION_HEADER"
 	LINE_BREAK     = "LINE_BREAK"
@@ -26,11 +54,13 @@ const (
 	IDENT = "IDENT"
 	INT   = "INT"
 
-	COOKTIME            = "~"
-	COOKWARE            = "#"
-	INGREDIENT          = "@"
-	OPTIONAL_INGREDIENT = "@?"
-	RECIPE_REFERENCE    = "@./"
+	COOKTIME             = "~"
+	COOKWARE             = "#"
+	INGREDIENT           = "@"
+	OPTIONAL_INGREDIENT  = "@?"
+	REFERENCE_INGREDIENT = "@&"
+	RECIPE_REFERENCE     = "@./"
+	EXTENSION            = "EXTENSION" // A custom component, see parser.RegisterExtension
 
 	// Delimiters
 	COMMA     = ","
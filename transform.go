@@ -0,0 +1,351 @@
+package cooklang
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Transform is a single named step in a recipe transformation pipeline.
+// Transforms never modify their input; each one returns a new recipe.
+type Transform struct {
+	Name  string // Transform name (e.g., "scale", "units")
+	Value string // Raw argument from the pipeline spec (e.g., "2", "metric")
+	apply func(*Recipe) (*Recipe, error)
+}
+
+// Apply runs the transform against a recipe and returns the transformed copy.
+func (t Transform) Apply(recipe *Recipe) (*Recipe, error) {
+	if t.apply == nil {
+		return nil, fmt.Errorf("transform %q has no implementation", t.Name)
+	}
+	return t.apply(recipe)
+}
+
+// String returns the transform in pipeline spec form (e.g., "scale=2").
+func (t Transform) String() string {
+	if t.Value == "" {
+		return t.Name
+	}
+	return t.Name + "=" + t.Value
+}
+
+// TransformPipeline is an ordered list of transforms applied one after another.
+type TransformPipeline []Transform
+
+// ParseTransformPipeline parses a comma-separated pipeline specification into
+// an ordered TransformPipeline. Each entry has the form name or name=value.
+//
+// Supported transforms:
+//   - scale=F: scale all ingredient quantities by factor F
+//   - servings=N: scale the recipe to N servings
+//   - units=SYSTEM: convert ingredients and temperatures to metric, imperial, or us units
+//   - substitute=FROM:TO: replace ingredient FROM with TO
+//   - diet=NAME (or just NAME): apply a dietary substitution set from
+//     DietarySubstitutions, e.g. "vegan" or "dairy-free"
+//   - convection: adjust oven temperatures and baking times for a convection oven
+//     (see Recipe.AdjustForConvection)
+//   - altitude=M: apply high-altitude baking corrections for M meters (see
+//     Recipe.AdjustForAltitude)
+//
+// Parameters:
+//   - spec: The pipeline specification (e.g., "scale=2,units=metric")
+//
+// Returns:
+//   - TransformPipeline: The parsed pipeline (empty for an empty spec)
+//   - error: An error for unknown transforms or invalid arguments
+//
+// Example:
+//
+//	pipeline, err := cooklang.ParseTransformPipeline("servings=4,units=metric,vegan")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	transformed, err := pipeline.Apply(recipe)
+func ParseTransformPipeline(spec string) (TransformPipeline, error) {
+	pipeline := TransformPipeline{}
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, value, _ := strings.Cut(entry, "=")
+		t, err := NewTransform(strings.TrimSpace(name), strings.TrimSpace(value))
+		if err != nil {
+			return nil, err
+		}
+		pipeline = append(pipeline, t)
+	}
+	return pipeline, nil
+}
+
+// NewTransform creates a single transform by name, validating its argument.
+//
+// Parameters:
+//   - name: The transform name (scale, servings, units, substitute, diet, convection,
+//     altitude, or a diet name)
+//   - value: The transform argument
+//
+// Returns:
+//   - Transform: The configured transform
+//   - error: An error for unknown transforms or invalid arguments
+func NewTransform(name, value string) (Transform, error) {
+	t := Transform{Name: strings.ToLower(name), Value: value}
+
+	switch t.Name {
+	case "scale":
+		factor, err := strconv.ParseFloat(value, 64)
+		if err != nil || factor <= 0 {
+			return t, fmt.Errorf("invalid scale factor %q: must be a positive number", value)
+		}
+		t.apply = func(r *Recipe) (*Recipe, error) {
+			return r.Scale(factor), nil
+		}
+	case "servings":
+		servings, err := strconv.ParseFloat(value, 64)
+		if err != nil || servings <= 0 {
+			return t, fmt.Errorf("invalid servings %q: must be a positive number", value)
+		}
+		t.apply = func(r *Recipe) (*Recipe, error) {
+			return r.ScaleToServings(servings), nil
+		}
+	case "units":
+		system, err := ParseUnitSystem(value)
+		if err != nil {
+			return t, err
+		}
+		t.apply = func(r *Recipe) (*Recipe, error) {
+			return r.ConvertToSystem(system), nil
+		}
+	case "substitute":
+		from, to, ok := strings.Cut(value, ":")
+		from, to = strings.TrimSpace(from), strings.TrimSpace(to)
+		if !ok || from == "" || to == "" {
+			return t, fmt.Errorf("invalid substitution %q: use substitute=from:to", value)
+		}
+		t.apply = func(r *Recipe) (*Recipe, error) {
+			return r.Substitute(from, to), nil
+		}
+	case "diet":
+		substitutions, ok := DietarySubstitutions[strings.ToLower(value)]
+		if !ok {
+			return t, fmt.Errorf("unknown diet %q (use %s)", value, strings.Join(dietNames(), ", "))
+		}
+		t.apply = func(r *Recipe) (*Recipe, error) {
+			return r.SubstituteAll(substitutions), nil
+		}
+	case "convection":
+		if value != "" {
+			return t, fmt.Errorf("convection takes no argument, got %q", value)
+		}
+		t.apply = func(r *Recipe) (*Recipe, error) {
+			adjusted, _ := r.AdjustForConvection()
+			return adjusted, nil
+		}
+	case "altitude":
+		meters, err := strconv.ParseFloat(strings.TrimSuffix(value, "m"), 64)
+		if err != nil || meters < 0 {
+			return t, fmt.Errorf("invalid altitude %q: must be a number of meters", value)
+		}
+		t.apply = func(r *Recipe) (*Recipe, error) {
+			adjusted, _ := r.AdjustForAltitude(meters)
+			return adjusted, nil
+		}
+	default:
+		substitutions, ok := DietarySubstitutions[t.Name]
+		if !ok || value != "" {
+			return t, fmt.Errorf("unknown transform %q", name)
+		}
+		t.apply = func(r *Recipe) (*Recipe, error) {
+			return r.SubstituteAll(substitutions), nil
+		}
+	}
+
+	return t, nil
+}
+
+// DietarySubstitutions are the ingredient substitution sets used by the diet
+// transform, keyed by diet name. Ingredient names are matched case-insensitively.
+// Ingredients without a listed substitute are kept, so a transformed recipe is not
+// guaranteed to suit the diet. Add entries to support more diets or ingredients.
+var DietarySubstitutions = map[string]map[string]string{
+	"vegan": {
+		"butter":        "vegan butter",
+		"milk":          "oat milk",
+		"whole milk":    "oat milk",
+		"cream":         "oat cream",
+		"heavy cream":   "coconut cream",
+		"yogurt":        "soy yogurt",
+		"greek yogurt":  "soy yogurt",
+		"honey":         "maple syrup",
+		"egg":           "flax egg",
+		"eggs":          "flax eggs",
+		"cheese":        "vegan cheese",
+		"parmesan":      "nutritional yeast",
+		"gelatin":       "agar agar",
+		"chicken stock": "vegetable stock",
+		"beef stock":    "vegetable stock",
+		"fish sauce":    "soy sauce",
+	},
+	"vegetarian": {
+		"chicken stock": "vegetable stock",
+		"beef stock":    "vegetable stock",
+		"fish sauce":    "soy sauce",
+		"gelatin":       "agar agar",
+		"anchovies":     "capers",
+	},
+	"dairy-free": {
+		"butter":      "vegan butter",
+		"milk":        "oat milk",
+		"whole milk":  "oat milk",
+		"cream":       "oat cream",
+		"heavy cream": "coconut cream",
+		"yogurt":      "soy yogurt",
+		"cheese":      "vegan cheese",
+		"parmesan":    "nutritional yeast",
+	},
+	"gluten-free": {
+		"flour":             "gluten-free flour",
+		"all-purpose flour": "gluten-free flour",
+		"wheat flour":       "gluten-free flour",
+		"soy sauce":         "tamari",
+		"breadcrumbs":       "gluten-free breadcrumbs",
+		"pasta":             "gluten-free pasta",
+	},
+}
+
+// dietNames returns the names of the DietarySubstitutions sets, sorted.
+func dietNames() []string {
+	names := make([]string, 0, len(DietarySubstitutions))
+	for name := range DietarySubstitutions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Apply runs every transform in order, feeding each result into the next.
+// The input recipe is never modified. An empty pipeline returns the input unchanged.
+func (tp TransformPipeline) Apply(recipe *Recipe) (*Recipe, error) {
+	current := recipe
+	for _, t := range tp {
+		next, err := t.Apply(current)
+		if err != nil {
+			return nil, fmt.Errorf("transform %s failed: %w", t, err)
+		}
+		current = next
+	}
+	return current, nil
+}
+
+// String returns the pipeline in spec form (e.g., "scale=2,units=metric").
+func (tp TransformPipeline) String() string {
+	parts := make([]string, len(tp))
+	for i, t := range tp {
+		parts[i] = t.String()
+	}
+	return strings.Join(parts, ",")
+}
+
+// ParseUnitSystem converts a unit system name (metric, imperial, us) to a UnitSystem.
+// Matching is case-insensitive.
+func ParseUnitSystem(name string) (UnitSystem, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "metric":
+		return UnitSystemMetric, nil
+	case "imperial":
+		return UnitSystemImperial, nil
+	case "us":
+		return UnitSystemUS, nil
+	default:
+		return "", fmt.Errorf("unknown unit system: %s (use metric, imperial, or us)", name)
+	}
+}
+
+// ConvertToSystem returns a copy of the recipe with every ingredient converted
+// to the target unit system. Ingredients that cannot be converted are left as-is.
+// Temperatures are converted too, to Fahrenheit for UnitSystemUS and to Celsius
+// otherwise (see ConvertTemperatures).
+//
+// Example:
+//
+//	recipe, _ := cooklang.ParseFile("pancakes.cook")
+//	metric := recipe.ConvertToSystem(cooklang.UnitSystemMetric)
+func (r *Recipe) ConvertToSystem(system UnitSystem) *Recipe {
+	return r.ConvertToSystemWithProfile(system, ProfilePrecise)
+}
+
+// ConvertToSystemWithProfile converts the recipe like ConvertToSystem, rounding amounts
+// and picking units with a conversion profile. ProfileAuto uses ProfileForRecipe, so
+// cocktails get dashes, barspoons and friendly fractions.
+//
+// Example:
+//
+//	negroni, _ := cooklang.ParseFile("Negroni.cook")
+//	us := negroni.ConvertToSystemWithProfile(cooklang.UnitSystemUS, cooklang.ProfileAuto)
+func (r *Recipe) ConvertToSystemWithProfile(system UnitSystem, profile ConversionProfile) *Recipe {
+	if profile == ProfileAuto {
+		profile = ProfileForRecipe(r)
+	}
+	converted := r.ConvertTemperatures(system)
+	for step := converted.FirstStep; step != nil; step = step.NextStep {
+		for component := step.FirstComponent; component != nil; component = component.GetNext() {
+			if ing, ok := component.(*Ingredient); ok {
+				result := ing.ConvertToSystemWithProfile(system, profile)
+				if ing.OriginalQuantity.HasAmount() && ing.Quantity.HasAmount() && result.Unit != ing.Unit {
+					// Keep the original quantity in the same unit as the quantity
+					ing.OriginalQuantity = ing.OriginalQuantity.Scale(result.Quantity.Min() / ing.Quantity.Min())
+				}
+				ing.Quantity = result.Quantity // Both bounds of a range
+				ing.Approximate = result.Approximate
+				ing.Unit = result.Unit
+				ing.TypedUnit = result.TypedUnit
+			}
+		}
+	}
+	return converted
+}
+
+// Substitute returns a copy of the recipe with every ingredient named from
+// (case-insensitive) renamed to to. Quantities and units are kept.
+//
+// Example:
+//
+//	dairyFree := recipe.Substitute("butter", "margarine")
+func (r *Recipe) Substitute(from, to string) *Recipe {
+	substituted := r.Clone()
+	for step := substituted.FirstStep; step != nil; step = step.NextStep {
+		for component := step.FirstComponent; component != nil; component = component.GetNext() {
+			if ing, ok := component.(*Ingredient); ok && strings.EqualFold(ing.Name, from) {
+				ing.Name = to
+			}
+		}
+	}
+	return substituted
+}
+
+// SubstituteAll returns a copy of the recipe with every ingredient whose name is a
+// key of substitutions (case-insensitive) renamed to its value. Quantities and
+// units are kept.
+//
+// Example:
+//
+//	vegan := recipe.SubstituteAll(cooklang.DietarySubstitutions["vegan"])
+func (r *Recipe) SubstituteAll(substitutions map[string]string) *Recipe {
+	lower := make(map[string]string, len(substitutions))
+	for from, to := range substitutions {
+		lower[strings.ToLower(from)] = to
+	}
+	substituted := r.Clone()
+	for step := substituted.FirstStep; step != nil; step = step.NextStep {
+		for component := step.FirstComponent; component != nil; component = component.GetNext() {
+			if ing, ok := component.(*Ingredient); ok {
+				if to, ok := lower[strings.ToLower(ing.Name)]; ok {
+					ing.Name = to
+				}
+			}
+		}
+	}
+	return substituted
+}
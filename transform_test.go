@@ -0,0 +1,160 @@
+package cooklang
+
+import (
+	"testing"
+)
+
+func TestParseTransformPipeline(t *testing.T) {
+	pipeline, err := ParseTransformPipeline("scale=2, units=metric,substitute=butter:margarine")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(pipeline) != 3 {
+		t.Fatalf("expected 3 transforms, got %d", len(pipeline))
+	}
+	if got := pipeline.String(); got != "scale=2,units=metric,substitute=butter:margarine" {
+		t.Errorf("unexpected pipeline string: %q", got)
+	}
+
+	empty, err := ParseTransformPipeline("")
+	if err != nil {
+		t.Fatalf("unexpected error for empty spec: %v", err)
+	}
+	if len(empty) != 0 {
+		t.Errorf("expected empty pipeline, got %d transforms", len(empty))
+	}
+}
+
+func TestParseTransformPipelineErrors(t *testing.T) {
+	tests := []string{
+		"scale=abc",
+		"scale=-1",
+		"servings=0",
+		"units=klingon",
+		"substitute=butter",
+		"explode",
+		"diet=keto",
+		"vegan=yes",
+		"convection=yes",
+		"altitude=high",
+	}
+	for _, spec := range tests {
+		t.Run(spec, func(t *testing.T) {
+			if _, err := ParseTransformPipeline(spec); err == nil {
+				t.Errorf("expected error for %q", spec)
+			}
+		})
+	}
+}
+
+func TestTransformPipelineApply(t *testing.T) {
+	recipe, err := ParseString(`---
+servings: 2
+---
+Melt @butter{50%g} and add @sugar{1%kg}.`)
+	if err != nil {
+		t.Fatalf("failed to parse recipe: %v", err)
+	}
+
+	pipeline, err := ParseTransformPipeline("servings=4,substitute=butter:margarine,units=metric")
+	if err != nil {
+		t.Fatalf("failed to parse pipeline: %v", err)
+	}
+
+	result, err := pipeline.Apply(recipe)
+	if err != nil {
+		t.Fatalf("failed to apply pipeline: %v", err)
+	}
+
+	if result.Servings != 4 {
+		t.Errorf("expected 4 servings, got %v", result.Servings)
+	}
+
+	ingredients := result.GetIngredients().Ingredients
+	if len(ingredients) != 2 {
+		t.Fatalf("expected 2 ingredients, got %d", len(ingredients))
+	}
+	if ingredients[0].Name != "margarine" || ingredients[0].Quantity.Min() != 100 || ingredients[0].Unit != "g" {
+		t.Errorf("unexpected first ingredient: %+v", ingredients[0])
+	}
+	if ingredients[1].Quantity.Min() != 2 || ingredients[1].Unit != "kg" {
+		t.Errorf("unexpected second ingredient: %v %s", ingredients[1].Quantity, ingredients[1].Unit)
+	}
+
+	// The original recipe must be untouched
+	original := recipe.GetIngredients().Ingredients
+	if original[0].Name != "butter" || original[0].Quantity.Min() != 50 {
+		t.Errorf("original recipe was modified: %+v", original[0])
+	}
+}
+
+func TestRecipeConvertToSystemRange(t *testing.T) {
+	recipe, err := ParseString("Add @milk{~1-2%cup}.")
+	if err != nil {
+		t.Fatalf("failed to parse recipe: %v", err)
+	}
+
+	milk := recipe.ConvertToSystem(UnitSystemMetric).GetIngredients().Ingredients[0]
+	if milk.Unit != "ml" {
+		t.Fatalf("expected ml, got %q", milk.Unit)
+	}
+	if !milk.Quantity.IsRange() || !floatClose(milk.Quantity.Min(), 236.588, 0.01) || !floatClose(milk.Quantity.Max(), 473.176, 0.01) {
+		t.Errorf("converted range = %v, want ~236.6-473.2", milk.Quantity)
+	}
+	if !milk.Approximate {
+		t.Error("converted range should stay approximate")
+	}
+}
+
+func TestDietTransform(t *testing.T) {
+	recipe, err := ParseString("Whisk @Milk{200%ml} with @eggs{2} and @sugar{50%g}.")
+	if err != nil {
+		t.Fatalf("failed to parse recipe: %v", err)
+	}
+
+	for _, spec := range []string{"vegan", "diet=vegan", "diet=Vegan"} {
+		pipeline, err := ParseTransformPipeline(spec)
+		if err != nil {
+			t.Fatalf("ParseTransformPipeline(%q): %v", spec, err)
+		}
+		result, err := pipeline.Apply(recipe)
+		if err != nil {
+			t.Fatalf("%s: failed to apply pipeline: %v", spec, err)
+		}
+		ingredients := result.GetIngredients().Ingredients
+		if ingredients[0].Name != "oat milk" || ingredients[0].Quantity.Min() != 200 || ingredients[0].Unit != "ml" {
+			t.Errorf("%s: unexpected first ingredient: %+v", spec, ingredients[0])
+		}
+		if ingredients[1].Name != "flax eggs" {
+			t.Errorf("%s: expected flax eggs, got %q", spec, ingredients[1].Name)
+		}
+		if ingredients[2].Name != "sugar" {
+			t.Errorf("%s: sugar should be kept, got %q", spec, ingredients[2].Name)
+		}
+	}
+
+	if recipe.GetIngredients().Ingredients[0].Name != "Milk" {
+		t.Error("original recipe was modified")
+	}
+}
+
+func TestParseUnitSystem(t *testing.T) {
+	tests := map[string]UnitSystem{
+		"metric":   UnitSystemMetric,
+		"Imperial": UnitSystemImperial,
+		"US":       UnitSystemUS,
+	}
+	for input, expected := range tests {
+		got, err := ParseUnitSystem(input)
+		if err != nil {
+			t.Errorf("ParseUnitSystem(%q) returned error: %v", input, err)
+		}
+		if got != expected {
+			t.Errorf("ParseUnitSystem(%q) = %q, want %q", input, got, expected)
+		}
+	}
+
+	if _, err := ParseUnitSystem("nautical"); err == nil {
+		t.Error("expected error for unknown unit system")
+	}
+}
@@ -1,6 +1,7 @@
 package cooklang
 
 import (
+	"math"
 	"testing"
 )
 
@@ -31,8 +32,8 @@ func TestIngredientUnitConversion(t *testing.T) {
 	if flour.Name != "flour" {
 		t.Errorf("Expected ingredient name 'flour', got '%s'", flour.Name)
 	}
-	if flour.Quantity != 500 {
-		t.Errorf("Expected quantity 500, got %f", flour.Quantity)
+	if flour.Quantity.Min() != 500 {
+		t.Errorf("Expected quantity 500, got %f", flour.Quantity.Min())
 	}
 	if flour.Unit != "g" {
 		t.Errorf("Expected unit 'g', got '%s'", flour.Unit)
@@ -61,9 +62,9 @@ func TestIngredientUnitConversion(t *testing.T) {
 		}
 
 		// Should be 0.5 kg
-		expectedQuantity := float32(0.5)
-		if abs(converted.Quantity-expectedQuantity) > 0.001 {
-			t.Errorf("Expected converted quantity %f, got %f", expectedQuantity, converted.Quantity)
+		expectedQuantity := 0.5
+		if math.Abs(converted.Quantity.Min()-expectedQuantity) > 0.001 {
+			t.Errorf("Expected converted quantity %f, got %v", expectedQuantity, converted.Quantity)
 		}
 	})
 
@@ -145,10 +146,36 @@ func TestIngredientWithCustomUnits(t *testing.T) {
 	}
 }
 
-// Helper function for floating point comparison
-func abs(x float32) float32 {
-	if x < 0 {
-		return -x
+func TestIsKnownUnit(t *testing.T) {
+	for _, unit := range []string{"g", "kg", "ml", "cup", "cups", "tablespoons", "tsp", "dash", "fl oz"} {
+		if !IsKnownUnit(unit) {
+			t.Errorf("expected %q to be known", unit)
+		}
+	}
+	// Parsing registers "pinch" with go-units, but it still cannot be converted
+	if _, err := ParseString("Add @salt{1%pinch}."); err != nil {
+		t.Fatal(err)
+	}
+	for _, unit := range []string{"", "pinch", "cloves", "handful"} {
+		if IsKnownUnit(unit) {
+			t.Errorf("expected %q to be unknown", unit)
+		}
+	}
+}
+
+func TestIngredientConversionKeepsAnnotation(t *testing.T) {
+	ingredient := NewIngredient("milk", 1, "l")
+	ingredient.Annotation = "cold"
+	ingredient.Optional = true
+	ingredient.Fixed = true
+
+	converted, err := ingredient.ConvertTo("ml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, result := range []*Ingredient{converted, ingredient.ConvertToSystem(UnitSystemUS)} {
+		if result.Annotation != "cold" || !result.Optional || !result.Fixed {
+			t.Errorf("expected the annotation, optional and fixed flags to be kept, got %+v", result)
+		}
 	}
-	return x
 }
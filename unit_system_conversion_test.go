@@ -38,7 +38,7 @@ func TestNewIngredient(t *testing.T) {
 					t.Logf("Warning: No conversion happened for %s %v %s -> %s (might be expected for some units)",
 						tc.name, tc.quantity, tc.unit, tc.targetSystem)
 				} else {
-					t.Logf("%s: %.1f %s -> %.2f %s", tc.name, tc.quantity, tc.unit, converted.Quantity, converted.Unit)
+					t.Logf("%s: %.1f %s -> %.2f %s", tc.name, tc.quantity, tc.unit, converted.Quantity.Min(), converted.Unit)
 				}
 			}
 		})
@@ -52,7 +52,7 @@ func TestNewIngredientVsManualCreation(t *testing.T) {
 	// Create ingredient manually (without TypedUnit)
 	manualIng := &Ingredient{
 		Name:     "vodka",
-		Quantity: 50,
+		Quantity: NewQuantity(50, 1),
 		Unit:     "ml",
 	}
 
@@ -64,12 +64,12 @@ func TestNewIngredientVsManualCreation(t *testing.T) {
 	constructedConverted := constructedIng.ConvertToSystem(UnitSystemUS)
 
 	t.Logf("Manual creation: %.1f %s -> %.2f %s",
-		manualIng.Quantity, manualIng.Unit,
-		manualConverted.Quantity, manualConverted.Unit)
+		manualIng.Quantity.Min(), manualIng.Unit,
+		manualConverted.Quantity.Min(), manualConverted.Unit)
 
 	t.Logf("NewIngredient: %.1f %s -> %.2f %s",
-		constructedIng.Quantity, constructedIng.Unit,
-		constructedConverted.Quantity, constructedConverted.Unit)
+		constructedIng.Quantity.Min(), constructedIng.Unit,
+		constructedConverted.Quantity.Min(), constructedConverted.Unit)
 
 	// The manually created ingredient should NOT convert (TypedUnit is nil)
 	if manualConverted.Unit != manualIng.Unit {
@@ -116,18 +116,18 @@ Add @butter{125%g} and @vanilla extract{5%ml}.
 	// Test individual ingredient conversion
 	ingredients := recipe.GetIngredients()
 	for _, ingredient := range ingredients.Ingredients {
-		if ingredient.Unit != "" && ingredient.Quantity > 0 {
+		if ingredient.Unit != "" && ingredient.Quantity.Min() > 0 {
 			// Test conversion to US units
 			usIngredient := ingredient.ConvertToSystem(UnitSystemUS)
 			t.Logf("Original: %s %.1f%s -> US: %s %.1f%s",
-				ingredient.Name, ingredient.Quantity, ingredient.Unit,
-				usIngredient.Name, usIngredient.Quantity, usIngredient.Unit)
+				ingredient.Name, ingredient.Quantity.Min(), ingredient.Unit,
+				usIngredient.Name, usIngredient.Quantity.Min(), usIngredient.Unit)
 
 			// Test conversion to Imperial units
 			imperialIngredient := ingredient.ConvertToSystem(UnitSystemImperial)
 			t.Logf("Original: %s %.1f%s -> Imperial: %s %.1f%s",
-				ingredient.Name, ingredient.Quantity, ingredient.Unit,
-				imperialIngredient.Name, imperialIngredient.Quantity, imperialIngredient.Unit)
+				ingredient.Name, ingredient.Quantity.Min(), ingredient.Unit,
+				imperialIngredient.Name, imperialIngredient.Quantity.Min(), imperialIngredient.Unit)
 		}
 	}
 }
@@ -137,9 +137,9 @@ func TestIngredientListConversion(t *testing.T) {
 	ingredients := NewIngredientList()
 
 	// Add some metric ingredients
-	flour := &Ingredient{Name: "flour", Quantity: 1000, Unit: "g", TypedUnit: CreateTypedUnit("g")}
-	milk := &Ingredient{Name: "milk", Quantity: 500, Unit: "ml", TypedUnit: CreateTypedUnit("ml")}
-	sugar := &Ingredient{Name: "sugar", Quantity: 200, Unit: "g", TypedUnit: CreateTypedUnit("g")}
+	flour := &Ingredient{Name: "flour", Quantity: NewQuantity(1000, 1), Unit: "g", TypedUnit: CreateTypedUnit("g")}
+	milk := &Ingredient{Name: "milk", Quantity: NewQuantity(500, 1), Unit: "ml", TypedUnit: CreateTypedUnit("ml")}
+	sugar := &Ingredient{Name: "sugar", Quantity: NewQuantity(200, 1), Unit: "g", TypedUnit: CreateTypedUnit("g")}
 
 	ingredients.Add(flour)
 	ingredients.Add(milk)
@@ -150,12 +150,12 @@ func TestIngredientListConversion(t *testing.T) {
 
 	t.Log("Original ingredients (metric):")
 	for _, ing := range ingredients.Ingredients {
-		t.Logf("  %s: %.1f %s", ing.Name, ing.Quantity, ing.Unit)
+		t.Logf("  %s: %.1f %s", ing.Name, ing.Quantity.Min(), ing.Unit)
 	}
 
 	t.Log("Converted to US system:")
 	for _, ing := range usIngredients.Ingredients {
-		t.Logf("  %s: %.1f %s", ing.Name, ing.Quantity, ing.Unit)
+		t.Logf("  %s: %.1f %s", ing.Name, ing.Quantity.Min(), ing.Unit)
 	}
 
 	// Convert to Imperial system
@@ -163,7 +163,7 @@ func TestIngredientListConversion(t *testing.T) {
 
 	t.Log("Converted to Imperial system:")
 	for _, ing := range imperialIngredients.Ingredients {
-		t.Logf("  %s: %.1f %s", ing.Name, ing.Quantity, ing.Unit)
+		t.Logf("  %s: %.1f %s", ing.Name, ing.Quantity.Min(), ing.Unit)
 	}
 }
 
@@ -171,7 +171,7 @@ func TestSmartUnitSelection(t *testing.T) {
 	// Test that large volumes get converted to appropriate units
 	testCases := []struct {
 		name     string
-		quantity float32
+		quantity float64
 		unit     string
 		system   UnitSystem
 		expected string
@@ -182,13 +182,19 @@ func TestSmartUnitSelection(t *testing.T) {
 		{"Tiny volume to US teaspoons", 5, "ml", UnitSystemUS, "tsp"},
 		{"Large mass to kg", 2000, "g", UnitSystemMetric, "kg"},
 		{"Small mass stays as g", 500, "g", UnitSystemMetric, "g"},
+		{"Very large volume to US gallons", 20000, "ml", UnitSystemUS, "gallon"},
+		{"Sub-gram mass to mg", 0.25, "g", UnitSystemMetric, "mg"},
+		{"Sub-milligram mass to µg", 50, "mcg", UnitSystemMetric, "µg"},
+		{"Catering mass to tonne", 1500, "kg", UnitSystemMetric, "tonne"},
+		{"Catering volume stays in litres", 250, "l", UnitSystemMetric, "l"},
+		{"Tiny volume stays in ml", 0.2, "ml", UnitSystemMetric, "ml"},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			ingredient := &Ingredient{
 				Name:      "test",
-				Quantity:  tc.quantity,
+				Quantity:  QuantityFromFloat(tc.quantity),
 				Unit:      tc.unit,
 				TypedUnit: CreateTypedUnit(tc.unit),
 			}
@@ -197,10 +203,41 @@ func TestSmartUnitSelection(t *testing.T) {
 
 			if converted.Unit != tc.expected {
 				t.Errorf("Expected unit %s, got %s (quantity: %.1f)",
-					tc.expected, converted.Unit, converted.Quantity)
+					tc.expected, converted.Unit, converted.Quantity.Min())
 			}
 
-			t.Logf("%.1f %s -> %.2f %s", tc.quantity, tc.unit, converted.Quantity, converted.Unit)
+			t.Logf("%.1f %s -> %.2f %s", tc.quantity, tc.unit, converted.Quantity.Min(), converted.Unit)
+		})
+	}
+}
+
+func TestSIPrefixConversions(t *testing.T) {
+	testCases := []struct {
+		name     string
+		quantity float32
+		from     string
+		to       string
+		expected float64
+	}{
+		{"micrograms to milligrams", 500, "µg", "mg", 0.5},
+		{"Greek mu micrograms to grams", 2000000, "μg", "g", 2},
+		{"mcg to ug", 25, "mcg", "ug", 25},
+		{"milligrams to grams", 1500, "mg", "g", 1.5},
+		{"tonne to kilograms", 0.5, "tonne", "kg", 500},
+		{"centilitres to millilitres", 4, "cl", "ml", 40},
+		{"decilitres to litres", 15, "dl", "l", 1.5},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ingredient := NewIngredient("test", tc.quantity, tc.from)
+			converted, err := ingredient.ConvertTo(tc.to)
+			if err != nil {
+				t.Fatalf("ConvertTo(%s) error: %v", tc.to, err)
+			}
+			if !floatClose(converted.Quantity.Min(), tc.expected, tc.expected*0.001) {
+				t.Errorf("%v %s = %v %s, want %v", tc.quantity, tc.from, converted.Quantity, tc.to, tc.expected)
+			}
 		})
 	}
 }
@@ -210,8 +247,8 @@ func TestConversionWithConsolidation(t *testing.T) {
 	ingredients := NewIngredientList()
 
 	// Add the same ingredient in different units
-	flour1 := &Ingredient{Name: "flour", Quantity: 500, Unit: "g", TypedUnit: CreateTypedUnit("g")}
-	flour2 := &Ingredient{Name: "flour", Quantity: 250, Unit: "g", TypedUnit: CreateTypedUnit("g")}
+	flour1 := &Ingredient{Name: "flour", Quantity: NewQuantity(500, 1), Unit: "g", TypedUnit: CreateTypedUnit("g")}
+	flour2 := &Ingredient{Name: "flour", Quantity: NewQuantity(250, 1), Unit: "g", TypedUnit: CreateTypedUnit("g")}
 
 	ingredients.Add(flour1)
 	ingredients.Add(flour2)
@@ -227,7 +264,7 @@ func TestConversionWithConsolidation(t *testing.T) {
 	}
 
 	flour := consolidated.Ingredients[0]
-	t.Logf("Consolidated flour: %.2f %s", flour.Quantity, flour.Unit)
+	t.Logf("Consolidated flour: %.2f %s", flour.Quantity.Min(), flour.Unit)
 
 	// Should be approximately 1.65 lbs or similar US weight unit
 	if flour.Name != "flour" {
@@ -0,0 +1,96 @@
+package cooklang
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Yield is what a recipe makes, from the "yield" metadata key, for recipes that do
+// not map to servings: "24 cookies", "2 loaves", or "500%ml" in Cooklang's
+// quantity%unit format.
+//
+// Example:
+//
+//	recipe, _ := cooklang.ParseString("---\nyield: 24 cookies\n---\nMix @flour{250%g}.")
+//	fmt.Println(recipe.Yield) // 24 cookies
+type Yield struct {
+	Quantity float64 `json:"quantity"`       // How many units the recipe makes
+	Unit     string  `json:"unit,omitempty"` // What it makes (e.g., "cookies", "loaves", "ml"); empty for a plain count
+}
+
+// IsZero reports whether the yield is unset.
+func (y Yield) IsZero() bool {
+	return y.Quantity <= 0
+}
+
+// String returns the yield as text, e.g. "24 cookies", or "" if it is unset.
+func (y Yield) String() string {
+	if y.IsZero() {
+		return ""
+	}
+	return strings.TrimSpace(formatYieldQuantity(y.Quantity) + " " + y.Unit)
+}
+
+// yield returns the recipe's yield, parsing the "yield" metadata of recipes that
+// were built without ToCooklangRecipe.
+func (r *Recipe) yield() Yield {
+	if !r.Yield.IsZero() {
+		return r.Yield
+	}
+	quantity, unit := ParseYield(r.Metadata["yield"])
+	return Yield{Quantity: quantity, Unit: unit}
+}
+
+// ScaleToYield creates a new recipe scaled to make the target quantity of its
+// yield, such as 48 cookies from a recipe that makes 24. The unit must match the
+// recipe's yield unit, ignoring case and a plural "s" or "es".
+//
+// Parameters:
+//   - target: The desired quantity (e.g., 48)
+//   - unit: The yield unit (e.g., "cookies")
+//
+// Returns:
+//   - *Recipe: A new recipe scaled to the target yield
+//   - error: An error if the recipe has no yield or it is in another unit
+//
+// Example:
+//
+//	recipe, _ := cooklang.ParseFile("cookies.cook") // yield: 24 cookies
+//	party, err := recipe.ScaleToYield(48, "cookies") // Double the recipe
+func (r *Recipe) ScaleToYield(target float64, unit string) (*Recipe, error) {
+	factor, err := ScaleByYield(r, target, unit)
+	if err != nil {
+		return nil, err
+	}
+	return r.Scale(factor), nil
+}
+
+// scaleYield multiplies the yield of a scaled recipe by factor, and rewrites the
+// "yield" metadata in the format it was written in.
+func (r *Recipe) scaleYield(original Yield, factor float64) {
+	if original.IsZero() {
+		return
+	}
+	r.Yield = Yield{Quantity: original.Quantity * factor, Unit: original.Unit}
+	if r.Metadata == nil {
+		return
+	}
+	if strings.Contains(r.Metadata["yield"], "%") {
+		r.Metadata["yield"] = formatYieldQuantity(r.Yield.Quantity) + "%" + r.Yield.Unit
+	} else {
+		r.Metadata["yield"] = r.Yield.String()
+	}
+}
+
+// sameYieldUnit reports whether two yield units are the same, ignoring case and a
+// plural "s" or "es" ("cookie" and "Cookies").
+func sameYieldUnit(a, b string) bool {
+	a, b = strings.ToLower(strings.TrimSpace(a)), strings.ToLower(strings.TrimSpace(b))
+	return a == b || a+"s" == b || b+"s" == a || a+"es" == b || b+"es" == a
+}
+
+// formatYieldQuantity writes a yield quantity with at most two decimals.
+func formatYieldQuantity(quantity float64) string {
+	return strconv.FormatFloat(math.Round(quantity*100)/100, 'f', -1, 64)
+}
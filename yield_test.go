@@ -0,0 +1,66 @@
+package cooklang
+
+import "testing"
+
+func TestRecipeYield(t *testing.T) {
+	recipe, err := ParseString("---\nyield: 24 cookies\n---\nMix @butter{100%g} and @flour{250%g}.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if recipe.Yield != (Yield{Quantity: 24, Unit: "cookies"}) {
+		t.Errorf("Yield = %+v, want 24 cookies", recipe.Yield)
+	}
+	if got := recipe.Yield.String(); got != "24 cookies" {
+		t.Errorf("Yield.String() = %q, want %q", got, "24 cookies")
+	}
+
+	noYield, err := ParseString("Mix @flour{250%g}.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !noYield.Yield.IsZero() {
+		t.Errorf("expected no yield, got %+v", noYield.Yield)
+	}
+}
+
+func TestScaleToYield(t *testing.T) {
+	recipe, err := ParseString("---\nyield: 24 cookies\n---\nMix @butter{100%g} and @flour{250%g}.")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scaled, err := recipe.ScaleToYield(48, "Cookie")
+	if err != nil {
+		t.Fatalf("ScaleToYield: %v", err)
+	}
+	if got := scaled.GetIngredients().Ingredients[1].Quantity.Float(); got != 500 {
+		t.Errorf("flour = %v, want 500", got)
+	}
+	if scaled.Yield.Quantity != 48 || scaled.Metadata["yield"] != "48 cookies" {
+		t.Errorf("scaled yield = %+v (metadata %q), want 48 cookies", scaled.Yield, scaled.Metadata["yield"])
+	}
+	if recipe.Yield.Quantity != 24 || recipe.Metadata["yield"] != "24 cookies" {
+		t.Errorf("original yield changed to %+v (metadata %q)", recipe.Yield, recipe.Metadata["yield"])
+	}
+
+	if _, err := recipe.ScaleToYield(2, "loaves"); err == nil {
+		t.Error("expected an error for another yield unit")
+	}
+	noYield, err := ParseString("Mix @flour{250%g}.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := noYield.ScaleToYield(48, "cookies"); err == nil {
+		t.Error("expected an error for a recipe without yield")
+	}
+}
+
+func TestScaleKeepsYieldFormat(t *testing.T) {
+	recipe, err := ParseString("---\nyield: 2%loaves\n---\nMix @flour{1%kg}.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := recipe.Scale(1.5).Metadata["yield"]; got != "3%loaves" {
+		t.Errorf("yield metadata = %q, want %q", got, "3%loaves")
+	}
+}